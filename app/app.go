@@ -7,101 +7,304 @@ package app
 import (
 	"context"
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
 
+	abci "github.com/tendermint/abci/types"
+
 	"github.com/confio/weave"
 	"github.com/confio/weave/app"
 	"github.com/confio/weave/orm"
-	"github.com/confio/weave/store/iavl"
 	"github.com/confio/weave/x"
 	"github.com/confio/weave/x/sigs"
 	"github.com/confio/weave/x/utils"
 
+	"github.com/iov-one/bcp-demo/query"
+	"github.com/iov-one/bcp-demo/storage"
+	"github.com/iov-one/bcp-demo/tracing"
+	"github.com/iov-one/bcp-demo/views"
+	"github.com/iov-one/bcp-demo/x/crash"
+	"github.com/iov-one/bcp-demo/x/deposit"
 	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/evidence"
+	"github.com/iov-one/bcp-demo/x/features"
+	"github.com/iov-one/bcp-demo/x/feepool"
+	"github.com/iov-one/bcp-demo/x/grant"
 	"github.com/iov-one/bcp-demo/x/hashlock"
+	"github.com/iov-one/bcp-demo/x/keys"
+	"github.com/iov-one/bcp-demo/x/ledger"
+	"github.com/iov-one/bcp-demo/x/limits"
+	"github.com/iov-one/bcp-demo/x/modaccount"
 	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/outbox"
+	"github.com/iov-one/bcp-demo/x/priority"
+	"github.com/iov-one/bcp-demo/x/scheduler"
+	"github.com/iov-one/bcp-demo/x/session"
+	"github.com/iov-one/bcp-demo/x/travelrule"
+	"github.com/iov-one/bcp-demo/x/txindex"
 )
 
 // Authenticator returns the typical authentication,
-// just using public key signatures
+// using public key signatures, preimages, the
+// permissions granted to them, session keys and the
+// owners of scheduled jobs
 func Authenticator() x.Authenticator {
-	return x.ChainAuth(sigs.Authenticate{}, hashlock.Authenticate{})
+	return x.ChainAuth(Signers(), hashlock.Authenticate{},
+		grant.Authenticate{}, session.Authenticate{}, scheduler.Authenticate{})
+}
+
+// Signers authenticates the ed25519 signatures of x/sigs
+// and the secp256k1 and multisig ones of x/keys
+func Signers() x.Authenticator {
+	return x.ChainAuth(sigs.Authenticate{}, keys.Authenticate{})
 }
 
 // Chain returns a chain of decorators, to handle authentication,
-// fees, logging, and recovery
-func Chain(minFee x.Coin, authFn x.Authenticator) app.Decorators {
-	return app.ChainDecorators(
+// fees, logging, and recovery. minPrice is the lowest fee per
+// byte this node accepts in its mempool. Panics are reported
+// to crashes, which may be nil. With a tracer (may be nil) every
+// decorator runs in a span of its own.
+func Chain(minFee x.Coin, minPrice int64, authFn x.Authenticator,
+	crashes crash.Reporter, tracer *tracing.Tracer) app.Decorators {
+
+	return app.ChainDecorators(tracing.Decorators(tracer,
 		utils.NewLogging(),
-		utils.NewRecovery(),
+		crash.NewDecorator(crashes),
+		// record the result of every tx, above all savepoints
+		txindex.NewDecorator(),
 		utils.NewKeyTagger(),
+		// reject oversized txs before checking signatures
+		limits.NewDecorator(),
+		// order the mempool by fee per byte
+		priority.NewDecorator(minPrice),
 		// on CheckTx, bad tx don't affect state
 		utils.NewSavepoint().OnCheck(),
 		sigs.NewDecorator(),
-		namecoin.NewFeeDecorator(authFn, minFee),
+		keys.NewDecorator(),
+		// list the tx in the history of all signers and parties
+		txindex.NewHistoryDecorator(authFn, Parties),
+		// fees in other tokens are converted by the pool
+		feepool.NewDecorator(authFn, minFee,
+			modaccount.Address(modaccount.FeeCollector)),
+		// the fee is paid even if the message fails below
+		ledger.NewFeeDecorator(authFn),
 		// cannot pay for fee with hashlock...
 		hashlock.NewDecorator(),
+		// signers may act for those who granted them this message
+		grant.NewDecorator(authFn),
 		// on DeliverTx, bad tx will increment nonce and take fee
 		// even if the message fails
 		utils.NewSavepoint().OnDeliver(),
-	)
+		// keep the escrow events, the tagged payments and what
+		// moves in and out of accounts for the webhooks, see Relay
+		outbox.NewDecorator("escrow.", deposit.TagPrefix, ledger.TagPrefix,
+			scheduler.TagPrefix),
+		// large transfers carry the hash of a travel rule envelope
+		travelrule.NewDecorator(Transfers),
+		// session keys act for their account, and fail the
+		// tx if they spend more than allowed
+		session.NewDecorator(Signers(), namecoin.NewController()),
+		// coins only leave module accounts through their module
+		modaccount.NewDecorator(),
+	)...)
 }
 
-// Router returns a default router, only dispatching to the
-// cash.SendMsg
-func Router(authFn x.Authenticator, issuer weave.Address) app.Router {
-	r := app.NewRouter()
-	namecoin.RegisterRoutes(r, authFn, issuer)
-	// we use the namecoin wallet handler
-	// TODO: move to cash upon refactor
-	escrow.RegisterRoutes(r, authFn, namecoin.NewController())
-	return r
+// Router returns a default router, dispatching to the
+// handlers of all modules
+func Router(authFn x.Authenticator) app.Router {
+	return Modules(authFn).Router()
+}
+
+// Ticker returns what runs at the start of every block:
+// closing the trade orders that timed out, releasing the
+// dead man's switch escrows that missed their heartbeat and
+// running the scheduled jobs, like returning expired escrows
+func Ticker() weave.Ticker {
+	return Modules(Authenticator()).Ticker()
+}
+
+// Initializer returns the initializers of all modules
+// that read state from the genesis file
+func Initializer() weave.Initializer {
+	return Modules(Authenticator()).Initializer()
 }
 
 // QueryRouter returns a default query router,
-// allowing access to "/wallets", "/auth", "/", and "/escrows"
+// allowing access to "/wallets", "/auth", "/", "/escrows",
+// "/escrows/rich", "/sales", "/prices", "/roles", "/grants", "/sessions",
+// "/keys", "/txs", "/txs/account", "/health/errors", "/features",
+// "/orders", "/feepool", "/evidence", "/confidential/...", "/faucet",
+// "/offers", "/outbox", "/travelrule", "/jobs" and "/version"
 func QueryRouter() weave.QueryRouter {
-	r := weave.NewQueryRouter()
+	r := Modules(Authenticator()).QueryRouter()
 	r.RegisterAll(
-		escrow.RegisterQuery,
-		namecoin.RegisterQuery,
-		sigs.RegisterQuery,
 		orm.RegisterQuery,
+		RegisterPagedQuery,
+		RegisterRichQuery,
+		RegisterVersionQuery,
 	)
 	return r
 }
 
+// RegisterPagedQuery adds "/escrows/page", "/wallets/page",
+// "/tokens/page", "/sales/page" and "/outbox/page" to read the
+// big buckets in chunks, see package query. Escrows and wallets
+// are counted, so their pages can include the total.
+//
+// It also pages the escrows of one party, with the address as
+// data of "/escrows/sender/page", "/escrows/recipient/page" and
+// "/escrows/arbiter/page", the wallet of a name on
+// "/wallets/name/page" and the events of an escrow, with its id
+// as data of "/escrows/history/page".
+func RegisterPagedQuery(qr weave.QueryRouter) {
+	escrows := escrow.NewBucket()
+	wallets := namecoin.NewWalletBucket()
+	query.RegisterCounted(qr, "/escrows", escrows.Bucket, 0)
+	query.RegisterCounted(qr, "/wallets", wallets.Bucket, 0)
+	query.Register(qr, "/tokens", namecoin.NewTokenBucket().Bucket, 0)
+	query.Register(qr, "/sales", namecoin.NewSaleBucket().Bucket, 0)
+	query.Register(qr, "/outbox", outbox.NewBucket().Bucket, 0)
+
+	for _, name := range []string{escrow.IndexSender, escrow.IndexRecipient, escrow.IndexArbiter} {
+		query.RegisterIndex(qr, "/escrows/"+name, escrows.Bucket,
+			escrows.Index(name), escrowIDLength, 0)
+	}
+	query.RegisterIndex(qr, "/wallets/"+namecoin.IndexName, wallets.Bucket,
+		wallets.NameIndex(), weave.AddressLength, 0)
+	qr.Register(escrow.QueryHistory+query.PagePath,
+		query.NewPrefixQuery(escrow.NewHistoryBucket().Bucket, escrowIDLength, 0))
+}
+
+// escrowIDLength is the size of the ids of the escrow sequence
+const escrowIDLength = 8
+
 // Stack wires up a standard router with a standard decorator
-// chain. This can be passed into BaseApp.
-func Stack(minFee x.Coin, issuer weave.Address) weave.Handler {
+// chain. This can be passed into BaseApp. Messages of paths
+// disabled in x/features are rejected before the router.
+func Stack(minFee x.Coin, minPrice int64) weave.Handler {
+	return ReportingStack(minFee, minPrice, nil, nil)
+}
+
+// ReportingStack is the Stack, reporting every panic
+// in a tx to crashes and tracing the txs with tracer,
+// both may be nil
+func ReportingStack(minFee x.Coin, minPrice int64, crashes crash.Reporter,
+	tracer *tracing.Tracer) weave.Handler {
+
 	authFn := Authenticator()
-	return Chain(minFee, authFn).
-		WithHandler(Router(authFn, issuer))
+	return Chain(minFee, minPrice, authFn, crashes, tracer).
+		WithHandler(tracing.Handler(tracer, features.NewRouter(Router(authFn))))
+}
+
+// App is the abci application, along with the store
+// it owns, so the database can be closed on shutdown,
+// the views it refreshes on every commit, the relay
+// of the events, the count of panics in txs and the
+// exporter of the traces (all but the store may be nil)
+type App struct {
+	app.BaseApp
+	kv       *storage.CommitStore
+	views    *views.Set
+	relay    *outbox.Relay
+	evidence evidence.Recorder
+	panics   *crash.Counter
+	traces   io.Closer
+}
+
+var _ io.Closer = App{}
+
+// Close stops the relay, flushes and releases the
+// database, waiting for a running Commit to finish,
+// then sends the traces not exported yet
+func (a App) Close() error {
+	if a.relay != nil {
+		a.relay.Close()
+	}
+	err := a.kv.Close()
+	if a.traces != nil {
+		a.traces.Close()
+	}
+	return err
+}
+
+// BeginBlock runs the Ticker, then records the validators
+// tendermint reports for misbehavior. Like the Ticker, it
+// panics if it cannot write the state.
+func (a App) BeginBlock(req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	res := a.BaseApp.BeginBlock(req)
+	ctx := weave.WithLogInfo(a.BlockContext(), "call", "begin_block")
+	err := a.evidence.Record(ctx, a.DeliverStore(), req.ByzantineValidators)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// Commit saves the block, refreshes the views and hands the
+// new events to the relay. A view that fails to build is logged
+// and keeps serving the last block.
+func (a App) Commit() abci.ResponseCommit {
+	res := a.BaseApp.Commit()
+	a.refreshViews()
+	if a.relay != nil {
+		a.relay.Commit(a.kv.Adapter())
+	}
+	return res
+}
+
+// WriteMetrics writes the metrics of the app in the text
+// format of prometheus
+func (a App) WriteMetrics(w io.Writer) error {
+	if a.panics == nil {
+		return nil
+	}
+	return a.panics.WriteMetrics(w)
+}
+
+func (a App) refreshViews() {
+	if a.views == nil {
+		return
+	}
+	err := a.views.Refresh(a.kv.Adapter())
+	if err != nil {
+		a.Logger().Error("Cannot refresh views", "err", err)
+	}
 }
 
 // Application constructs a basic ABCI application with
 // the given arguments. If you are not sure what to use
-// for the Handler, just use Stack().
-func Application(name string, h weave.Handler,
-	tx weave.TxDecoder, dbPath string) (app.BaseApp, error) {
+// for the Handler, just use Stack(). The views (may be nil)
+// are served along with the QueryRouter and built from the
+// last committed state.
+func Application(name string, h weave.Handler, tx weave.TxDecoder,
+	backend, dbPath string, set *views.Set) (App, error) {
 
 	ctx := context.Background()
-	kv, err := CommitKVStore(dbPath)
+	kv, err := CommitKVStore(backend, dbPath)
 	if err != nil {
-		return app.BaseApp{}, err
+		return App{}, err
+	}
+	qr := QueryRouter()
+	if set != nil {
+		set.RegisterQuery(qr)
 	}
-	store := app.NewStoreApp(name, kv, QueryRouter(), ctx)
-	base := app.NewBaseApp(store, tx, h, nil)
-	return base, nil
+	store := app.NewStoreApp(name, kv, qr, ctx)
+	base := app.NewBaseApp(store, tx, h, Ticker())
+	// there is no staking yet, so misbehavior is only recorded
+	res := App{BaseApp: base, kv: kv, views: set,
+		evidence: evidence.NewRecorder(nil)}
+	res.refreshViews()
+	return res, nil
 }
 
 // CommitKVStore returns an initialized KVStore that persists
-// the data to the named path.
-func CommitKVStore(dbPath string) (weave.CommitKVStore, error) {
+// the data to the named path, using the given db backend
+// (empty for the default).
+func CommitKVStore(backend, dbPath string) (*storage.CommitStore, error) {
 	// memory backed case, just for testing
 	if dbPath == "" {
-		return iavl.MockCommitStore(), nil
+		return storage.MockCommitStore(), nil
 	}
 
 	// Expand the path fully
@@ -116,5 +319,5 @@ func CommitKVStore(dbPath string) (weave.CommitKVStore, error) {
 	// Split the database name into it's components (dir, name)
 	dir := filepath.Dir(path)
 	name := filepath.Base(path)
-	return iavl.NewCommitStore(dir, name), nil
+	return storage.NewCommitStore(backend, dir, name)
 }
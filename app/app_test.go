@@ -5,13 +5,18 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/iov-one/bcp-demo/x/anymsg"
+	"github.com/iov-one/bcp-demo/x/deposit"
+	"github.com/iov-one/bcp-demo/x/limits"
 	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/txindex"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	abci "github.com/tendermint/abci/types"
 	"github.com/tendermint/tmlibs/log"
 
+	"github.com/confio/weave"
 	"github.com/confio/weave/app"
 	"github.com/confio/weave/crypto"
 	"github.com/confio/weave/x"
@@ -24,7 +29,7 @@ func TestApp(t *testing.T) {
 	chainID := "test-net-22"
 	abciApp, err := GenerateApp("", log.NewNopLogger())
 	require.NoError(t, err)
-	myApp := abciApp.(app.BaseApp)
+	myApp := abciApp.(App)
 
 	// let's set up a genesis file with some cash
 	pk := crypto.GenPrivKeyEd25519()
@@ -114,26 +119,42 @@ func TestApp(t *testing.T) {
 	dres := myApp.DeliverTx(txBytes)
 	require.Equal(t, uint32(0), dres.Code, dres.Log)
 
-	// ensure 3 keys with proper values
-	if assert.Equal(t, 3, len(dres.Tags), "%#v", dres.Tags) {
-		// three keys we expect, in order
-		keys := make([][]byte, 3)
-		vals := [][]byte{[]byte("s"), []byte("s"), []byte("s")}
+	// ensure the memo tags the payment, and the signer, both
+	// wallets, the tx history of both and the outbox event of
+	// the payment (with their sequences and counts) are written,
+	// in order
+	if assert.Equal(t, 12, len(dres.Tags), "%#v", dres.Tags) {
+		hexCount := []byte("5F6E2E")
+		hexSeq := []byte("5F732E")
+		hexHist := []byte("61636374783A")
+		hexOutbox := []byte("6F7574626F783A")
 		hexWllt := []byte("776C6C743A")
 		hexSigs := []byte("736967733A")
-		keys[0] = append(hexSigs, []byte(addr.String())...)
-		keys[1] = append(hexWllt, []byte(addr.String())...)
-		keys[2] = append(hexWllt, []byte(addr2.String())...)
+		wallets := [][]byte{
+			append(hexWllt, []byte(addr.String())...),
+			append(hexWllt, []byte(addr2.String())...),
+		}
 		if bytes.Compare(addr2, addr) < 0 {
-			keys[1], keys[2] = keys[2], keys[1]
+			wallets[0], wallets[1] = wallets[1], wallets[0]
 		}
 		// make sure the DeliverResult matches expections
-		assert.Equal(t, keys[0], dres.Tags[0].Key)
-		assert.Equal(t, keys[1], dres.Tags[1].Key)
-		assert.Equal(t, keys[2], dres.Tags[2].Key)
-		assert.Equal(t, vals[0], dres.Tags[0].Value)
-		assert.Equal(t, vals[1], dres.Tags[1].Value)
-		assert.Equal(t, vals[2], dres.Tags[2].Value)
+		assert.Equal(t, deposit.Tag(addr2, msg.Memo), dres.Tags[0])
+		// the history of both and the new wallet are counted
+		for _, tag := range dres.Tags[1:4] {
+			assert.True(t, bytes.HasPrefix(tag.Key, hexCount))
+		}
+		stored := dres.Tags[4:]
+		assert.True(t, bytes.HasPrefix(stored[0].Key, hexSeq))
+		assert.True(t, bytes.HasPrefix(stored[1].Key, hexSeq))
+		assert.True(t, bytes.HasPrefix(stored[2].Key, hexHist))
+		assert.True(t, bytes.HasPrefix(stored[3].Key, hexHist))
+		assert.True(t, bytes.HasPrefix(stored[4].Key, hexOutbox))
+		assert.Equal(t, append(hexSigs, []byte(addr.String())...), stored[5].Key)
+		assert.Equal(t, wallets[0], stored[6].Key)
+		assert.Equal(t, wallets[1], stored[7].Key)
+		for _, tag := range stored {
+			assert.Equal(t, []byte("s"), tag.Value)
+		}
 	}
 
 	// make sure commit is proper
@@ -228,4 +249,78 @@ func TestApp(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, int32(3), toke.SigFigs)
 	assert.Equal(t, "Frankie", toke.Name)
+
+	// the send was indexed by its hash
+	txquery := abci.RequestQuery{
+		Path: "/txs",
+		Data: txindex.Hash(txBytes),
+	}
+	txres := myApp.Query(txquery)
+	require.Equal(t, uint32(0), txres.Code, "%#v", txres)
+	var result txindex.TxResult
+	err = app.UnmarshalOneResult(txres.Value, &result)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Height)
+	assert.Equal(t, uint32(0), result.Code)
+	assert.Equal(t, msg.Path(), result.Path)
+	assert.Equal(t, len(dres.Tags), len(result.Tags))
+	// with the gas of its check and the message
+	assert.Equal(t, chres.GasWanted, result.GasAllocated)
+	assert.Contains(t, result.Msg, `"memo":"`+msg.Memo+`"`)
+
+	// and is in the history of the recipient
+	hquery := abci.RequestQuery{
+		Path: "/txs/account",
+		Data: addr2,
+	}
+	hres := myApp.Query(hquery)
+	require.Equal(t, uint32(0), hres.Code, "%#v", hres)
+	var entry txindex.AccountTx
+	err = app.UnmarshalOneResult(hres.Value, &entry)
+	require.NoError(t, err)
+	assert.Equal(t, txindex.Hash(txBytes), entry.Hash)
+	assert.Equal(t, int64(2), entry.Height)
+}
+
+func TestAnyMsg(t *testing.T) {
+	msg := &cash.SendMsg{
+		Src:    weave.NewAddress([]byte("alice")),
+		Dest:   weave.NewAddress([]byte("bob")),
+		Amount: &x.Coin{Whole: 20, Ticker: "ETH"},
+	}
+	any, err := anymsg.Pack(msg)
+	require.NoError(t, err)
+	bz, err := (&Tx{Sum: &Tx_AnyMsg{any}}).Marshal()
+	require.NoError(t, err)
+
+	tx, err := TxDecoder(bz)
+	require.NoError(t, err)
+	got, err := tx.GetMsg()
+	require.NoError(t, err)
+	assert.Equal(t, msg, got)
+	assert.Equal(t, msg.Path(), got.Path())
+
+	any.TypeUrl = "/cash.FeeInfo"
+	_, err = (&Tx{Sum: &Tx_AnyMsg{any}}).GetMsg()
+	assert.True(t, anymsg.IsUnknownTypeErr(err), "%+v", err)
+}
+
+func TestTxDecoderLimits(t *testing.T) {
+	msg := &cash.SendMsg{
+		Src:    weave.NewAddress([]byte("alice")),
+		Dest:   weave.NewAddress([]byte("bob")),
+		Amount: &x.Coin{Whole: 20, Ticker: "ETH"},
+	}
+	bz, err := (&Tx{Sum: &Tx_SendMsg{msg}}).Marshal()
+	require.NoError(t, err)
+	_, err = TxDecoder(bz)
+	require.NoError(t, err)
+
+	// field 99 is unknown, it is dropped on decode
+	padded := append(append([]byte{}, bz...), 0x98, 0x06, 0x01)
+	_, err = TxDecoder(padded)
+	assert.True(t, limits.IsLimitExceededErr(err), "%+v", err)
+
+	_, err = TxDecoder(make([]byte, limits.MaxTxBytes+1))
+	assert.True(t, limits.IsLimitExceededErr(err), "%+v", err)
 }
@@ -0,0 +1,209 @@
+package app
+
+import (
+	"sort"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/sigs"
+
+	"github.com/iov-one/bcp-demo/budget"
+	"github.com/iov-one/bcp-demo/module"
+	"github.com/iov-one/bcp-demo/x/chainaddr"
+	"github.com/iov-one/bcp-demo/x/confidential"
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/evidence"
+	"github.com/iov-one/bcp-demo/x/faucet"
+	"github.com/iov-one/bcp-demo/x/features"
+	"github.com/iov-one/bcp-demo/x/feepool"
+	"github.com/iov-one/bcp-demo/x/grant"
+	"github.com/iov-one/bcp-demo/x/hashlock"
+	"github.com/iov-one/bcp-demo/x/keys"
+	"github.com/iov-one/bcp-demo/x/limits"
+	"github.com/iov-one/bcp-demo/x/modaccount"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/oracle"
+	"github.com/iov-one/bcp-demo/x/outbox"
+	"github.com/iov-one/bcp-demo/x/ownership"
+	"github.com/iov-one/bcp-demo/x/rbac"
+	"github.com/iov-one/bcp-demo/x/scheduler"
+	"github.com/iov-one/bcp-demo/x/session"
+	"github.com/iov-one/bcp-demo/x/trade"
+	"github.com/iov-one/bcp-demo/x/travelrule"
+	"github.com/iov-one/bcp-demo/x/txindex"
+)
+
+// Builder assembles the parts of an app from its modules.
+// The genesis state is read and the tickers run in the order
+// the modules were added.
+type Builder struct {
+	modules []module.Module
+}
+
+// New returns a Builder without any modules
+func New() *Builder {
+	return &Builder{}
+}
+
+// WithModule adds a module. It panics if a module of the same
+// name was added before.
+func (b *Builder) WithModule(m module.Module) *Builder {
+	for _, prior := range b.modules {
+		if prior.Name() == m.Name() {
+			panic("app: duplicate module " + m.Name())
+		}
+	}
+	b.modules = append(b.modules, m)
+	return b
+}
+
+// Modules returns the Builder of the bcp-demo app, its modules
+// authenticating the signers with authFn
+func Modules(authFn x.Authenticator) *Builder {
+	roles := rbac.NewAuthenticator(authFn)
+	// we use the namecoin wallet handler
+	// TODO: move to cash upon refactor
+	control := namecoin.NewController()
+	return New().
+		WithModule(namecoin.Module{Auth: roles}).
+		// orders time out before the escrows are released
+		WithModule(trade.Module{Auth: authFn, Control: control}).
+		WithModule(escrow.Module{Auth: authFn, Control: control}).
+		// escrows time out after the switches were released
+		WithModule(scheduler.Module{Auth: roles}).
+		WithModule(oracle.Module{Auth: roles}).
+		WithModule(rbac.Module{Auth: roles}).
+		WithModule(limits.Module{}).
+		WithModule(features.Module{Auth: roles}).
+		WithModule(feepool.Module{Auth: roles}).
+		WithModule(faucet.Module{Control: control}).
+		WithModule(grant.Module{Auth: authFn}).
+		WithModule(session.Module{Auth: authFn}).
+		WithModule(confidential.Module{Auth: authFn, Control: control}).
+		WithModule(ownership.Module{}).
+		WithModule(keys.Module{}).
+		WithModule(txindex.Module{}).
+		WithModule(evidence.Module{}).
+		WithModule(hashlock.Module{}).
+		WithModule(modaccount.Module{}).
+		WithModule(chainaddr.Module{}).
+		WithModule(outbox.Module{}).
+		WithModule(travelrule.Module{}).
+		WithModule(sigsModule{})
+}
+
+// Router returns a router dispatching to the handlers of
+// all modules
+func (b *Builder) Router() app.Router {
+	r := app.NewRouter()
+	for _, m := range b.modules {
+		if router, ok := m.(module.Router); ok {
+			router.RegisterRoutes(r)
+		}
+	}
+	return r
+}
+
+// QueryRouter returns a query router serving the queries
+// of all modules
+func (b *Builder) QueryRouter() weave.QueryRouter {
+	r := weave.NewQueryRouter()
+	for _, m := range b.modules {
+		if querier, ok := m.(module.Querier); ok {
+			querier.RegisterQuery(r)
+		}
+	}
+	return r
+}
+
+// Initializer returns the initializers of all modules
+// that read state from the genesis file
+func (b *Builder) Initializer() weave.Initializer {
+	var inits []weave.Initializer
+	for _, m := range b.modules {
+		if genesis, ok := m.(module.Genesis); ok {
+			inits = append(inits, genesis.Initializer())
+		}
+	}
+	return app.ChainInitializers(inits...)
+}
+
+// Ticker returns the tickers of all modules, run in order.
+// Dispatching modules route their messages with the Router,
+// behind the switches of x/features as the messages of txs.
+func (b *Builder) Ticker() weave.Ticker {
+	var res tickers
+	for _, m := range b.modules {
+		switch m := m.(type) {
+		case module.Ticking:
+			res = append(res, m.Ticker())
+		case module.Dispatching:
+			res = append(res, m.Dispatcher(features.NewRouter(b.Router())))
+		}
+	}
+	return res
+}
+
+// Schemas returns the schema versions of all modules,
+// sorted by name
+func (b *Builder) Schemas() []*ModuleVersion {
+	res := make([]*ModuleVersion, len(b.modules))
+	for i, m := range b.modules {
+		res[i] = &ModuleVersion{Name: m.Name(), Version: m.Version()}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// TickGas is the budget of gas all tickers share at the start
+// of a block, the work they leave carries over to the next.
+// Every ticker has an equal share of it reserved, see Tick.
+const TickGas int64 = 20000
+
+// tickers runs every Ticker in order, the validator
+// changes of all of them add up
+type tickers []weave.Ticker
+
+var _ weave.Ticker = tickers{}
+
+// Tick fulfils weave.Ticker, the tickers share TickGas. Each
+// ticker keeps a share of it for every ticker after, so it can
+// use its own share and what the earlier ones left.
+func (t tickers) Tick(ctx weave.Context, db weave.KVStore) (weave.TickResult, error) {
+	var res weave.TickResult
+	if len(t) == 0 {
+		return res, nil
+	}
+	ctx = budget.With(ctx, TickGas)
+	share := TickGas / int64(len(t))
+	for i, ticker := range t {
+		later := int64(len(t) - i - 1)
+		r, err := ticker.Tick(budget.Keep(ctx, later*share), db)
+		if err != nil {
+			return res, err
+		}
+		res.Diff = append(res.Diff, r.Diff...)
+	}
+	return res, nil
+}
+
+// sigsModule serves the nonces of the ed25519 signers of weave
+type sigsModule struct{}
+
+var _ module.Querier = sigsModule{}
+
+// Name fulfils module.Module
+func (sigsModule) Name() string {
+	return "sigs"
+}
+
+// Version fulfils module.Module
+func (sigsModule) Version() uint32 {
+	return 1
+}
+
+// RegisterQuery fulfils module.Querier
+func (sigsModule) RegisterQuery(qr weave.QueryRouter) {
+	sigs.RegisterQuery(qr)
+}
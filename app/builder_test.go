@@ -0,0 +1,119 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+
+	"github.com/iov-one/bcp-demo/budget"
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/faucet"
+	"github.com/iov-one/bcp-demo/x/features"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/outbox"
+	"github.com/iov-one/bcp-demo/x/scheduler"
+	"github.com/iov-one/bcp-demo/x/trade"
+)
+
+func TestBuilder(t *testing.T) {
+	authFn := Authenticator()
+	control := namecoin.NewController()
+	b := New().
+		WithModule(escrow.Module{Auth: authFn, Control: control}).
+		WithModule(faucet.Module{Control: control})
+
+	// only the messages and queries of the modules are served
+	r := b.Router()
+	assert.NotNil(t, r.Handler(escrow.CreateEscrowMsg{}.Path()))
+	assert.NotNil(t, r.Handler(faucet.TapMsg{}.Path()))
+	_, err := r.Handler(trade.CreateOrderMsg{}.Path()).Check(nil, nil, nil)
+	assert.Error(t, err)
+	qr := b.QueryRouter()
+	assert.NotNil(t, qr.Handler("/escrows"))
+	assert.Nil(t, qr.Handler("/orders"))
+
+	assert.Len(t, b.Ticker(), 1)
+	assert.Equal(t, []*ModuleVersion{{Name: "escrow", Version: 21},
+		{Name: "faucet", Version: 1}}, b.Schemas())
+
+	assert.Panics(t, func() { b.WithModule(escrow.Module{}) })
+}
+
+// TestModules makes sure every module of the app is wired up
+func TestModules(t *testing.T) {
+	b := Modules(Authenticator())
+	assert.Equal(t, Schemas, b.Schemas())
+	assert.Equal(t, uint32(21), NewVersionInfo().SchemaVersion("escrow"))
+	assert.Equal(t, uint32(1), NewVersionInfo().SchemaVersion("sigs"))
+
+	// orders time out before the escrows are released, and
+	// those expire after
+	ticks := b.Ticker().(tickers)
+	require.Len(t, ticks, 4)
+	assert.IsType(t, trade.Ticker{}, ticks[0])
+	assert.IsType(t, escrow.Ticker{}, ticks[1])
+	assert.IsType(t, scheduler.Ticker{}, ticks[2])
+	assert.IsType(t, outbox.Ticker{}, ticks[3])
+}
+
+// dispatching keeps the handler it is given for its messages
+type dispatching struct {
+	handler *weave.Handler
+}
+
+func (dispatching) Name() string    { return "dispatching" }
+func (dispatching) Version() uint32 { return 1 }
+
+func (d dispatching) Dispatcher(h weave.Handler) weave.Ticker {
+	*d.handler = h
+	return nil
+}
+
+// TestDispatcher routes the messages of dispatching modules
+// behind the switches of x/features
+func TestDispatcher(t *testing.T) {
+	var h weave.Handler
+	New().WithModule(dispatching{handler: &h}).Ticker()
+	assert.IsType(t, features.Router{}, h)
+}
+
+// greedy takes gas of the budget as long as there is some
+type greedy struct {
+	gas   int64
+	taken *int
+}
+
+func (g greedy) Tick(ctx weave.Context, db weave.KVStore) (weave.TickResult, error) {
+	for budget.Take(ctx, g.gas) {
+		*g.taken++
+	}
+	return weave.TickResult{}, nil
+}
+
+// TestTickGas makes the tickers of a block share TickGas,
+// every block anew, with a share reserved for each
+func TestTickGas(t *testing.T) {
+	share := TickGas / 2
+	var first, second int
+	ticks := tickers{greedy{gas: 3000, taken: &first}, greedy{gas: 1000, taken: &second}}
+	for i := 1; i <= 2; i++ {
+		_, err := ticks.Tick(context.Background(), store.MemStore())
+		require.NoError(t, err)
+		// the second takes its share and what the first left
+		assert.Equal(t, i*int(share/3000), first)
+		assert.Equal(t, i*int((TickGas-share/3000*3000)/1000), second)
+	}
+
+	// however much the first could take
+	first, second = 0, 0
+	ticks = tickers{greedy{gas: 1, taken: &first}, greedy{gas: 1000, taken: &second}}
+	_, err := ticks.Tick(context.Background(), store.MemStore())
+	require.NoError(t, err)
+	assert.Equal(t, int(share), first)
+	assert.Equal(t, int(share/1000), second)
+}
@@ -0,0 +1,18 @@
+package app
+
+import (
+	"github.com/iov-one/bcp-demo/x/chainaddr"
+)
+
+// init registers the address formats of the other chains that
+// messages may refer to, by CAIP-2 chain id. Addresses of any
+// other chain are rejected until it is added here.
+func init() {
+	// Ethereum mainnet
+	chainaddr.Register("eip155:1", chainaddr.Hex(20))
+	// Bitcoin mainnet, pay to public key hash and to script hash
+	chainaddr.Register("bip122:000000000019d6689c085ae165831e93",
+		chainaddr.Base58Check(20, 0x00, 0x05))
+	// Cosmos Hub
+	chainaddr.Register("cosmos:cosmoshub-4", chainaddr.Bech32("cosmos", 20))
+}
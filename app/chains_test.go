@@ -0,0 +1,27 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iov-one/bcp-demo/x/chainaddr"
+)
+
+func TestForeignChains(t *testing.T) {
+	valid := []chainaddr.ForeignAddress{
+		{ChainId: "eip155:1", Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{ChainId: "bip122:000000000019d6689c085ae165831e93", Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa"},
+		{ChainId: "cosmos:cosmoshub-4", Address: "cosmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xu"},
+	}
+	for _, addr := range valid {
+		assert.NoError(t, addr.Validate(), addr.ChainId)
+	}
+
+	// an ethereum address on bitcoin
+	addr := chainaddr.ForeignAddress{ChainId: "bip122:000000000019d6689c085ae165831e93",
+		Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"}
+	assert.True(t, chainaddr.IsInvalidAddressErr(addr.Validate()))
+	addr.ChainId = "eip155:3"
+	assert.True(t, chainaddr.IsUnknownChainErr(addr.Validate()))
+}
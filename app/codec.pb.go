@@ -9,16 +9,33 @@
 
 	It has these top-level messages:
 		Tx
+		RichEscrow
+		Party
+		VersionInfo
+		ModuleVersion
 */
 package app
 
 import proto "github.com/gogo/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import x "github.com/confio/weave/x"
 import cash "github.com/confio/weave/x/cash"
 import sigs "github.com/confio/weave/x/sigs"
 import namecoin "github.com/iov-one/bcp-demo/x/namecoin"
 import escrow "github.com/iov-one/bcp-demo/x/escrow"
+import oracle "github.com/iov-one/bcp-demo/x/oracle"
+import rbac "github.com/iov-one/bcp-demo/x/rbac"
+import grant "github.com/iov-one/bcp-demo/x/grant"
+import session "github.com/iov-one/bcp-demo/x/session"
+import keys "github.com/iov-one/bcp-demo/x/keys"
+import anymsg "github.com/iov-one/bcp-demo/x/anymsg"
+import features "github.com/iov-one/bcp-demo/x/features"
+import trade "github.com/iov-one/bcp-demo/x/trade"
+import feepool "github.com/iov-one/bcp-demo/x/feepool"
+import confidential "github.com/iov-one/bcp-demo/x/confidential"
+import faucet "github.com/iov-one/bcp-demo/x/faucet"
+import scheduler "github.com/iov-one/bcp-demo/x/scheduler"
 
 import io "io"
 
@@ -45,6 +62,51 @@ type Tx struct {
 	//	*Tx_ReleaseEscrowMsg
 	//	*Tx_ReturnEscrowMsg
 	//	*Tx_UpdateEscrowMsg
+	//	*Tx_SetPriceMsg
+	//	*Tx_AssignRoleMsg
+	//	*Tx_RevokeRoleMsg
+	//	*Tx_CreateGrantMsg
+	//	*Tx_RevokeGrantMsg
+	//	*Tx_CreateSessionMsg
+	//	*Tx_RevokeSessionMsg
+	//	*Tx_SellNameMsg
+	//	*Tx_BuyNameMsg
+	//	*Tx_CancelNameSaleMsg
+	//	*Tx_UpdateMetadataMsg
+	//	*Tx_BidArbitrationMsg
+	//	*Tx_AssignArbiterMsg
+	//	*Tx_CreateEscrowMsgV2
+	//	*Tx_AnyMsg
+	//	*Tx_UpdateObserversMsg
+	//	*Tx_SetFeatureMsg
+	//	*Tx_NetEscrowsMsg
+	//	*Tx_SetArbiterPolicyMsg
+	//	*Tx_CreateOrderMsg
+	//	*Tx_FillOrderMsg
+	//	*Tx_CancelOrderMsg
+	//	*Tx_SetConversionMsg
+	//	*Tx_RevealMemoMsg
+	//	*Tx_ShieldMsg
+	//	*Tx_UnshieldMsg
+	//	*Tx_SetViewKeyMsg
+	//	*Tx_CreateConfidentialEscrowMsg
+	//	*Tx_ReleaseConfidentialEscrowMsg
+	//	*Tx_ReturnConfidentialEscrowMsg
+	//	*Tx_GrantViewMsg
+	//	*Tx_TapMsg
+	//	*Tx_CreateFromTemplateMsg
+	//	*Tx_SetTemplateMsg
+	//	*Tx_PingEscrowMsg
+	//	*Tx_SendEscrowMsg
+	//	*Tx_QuarantineEscrowMsg
+	//	*Tx_RestoreEscrowMsg
+	//	*Tx_ForceSettleEscrowMsg
+	//	*Tx_ClawbackEscrowMsg
+	//	*Tx_AttestMilestoneMsg
+	//	*Tx_OfferEscrowPartyMsg
+	//	*Tx_AcceptEscrowPartyMsg
+	//	*Tx_DisputeEscrowMsg
+	//	*Tx_RedriveJobMsg
 	Sum isTx_Sum `protobuf_oneof:"sum"`
 	// fee info, autogenerates GetFees()
 	Fees *cash.FeeInfo `protobuf:"bytes,20,opt,name=fees" json:"fees,omitempty"`
@@ -52,6 +114,12 @@ type Tx struct {
 	Signatures []*sigs.StdSignature `protobuf:"bytes,21,rep,name=signatures" json:"signatures,omitempty"`
 	// preimage for hashlock, autogenerates GetPreimage
 	Preimage []byte `protobuf:"bytes,22,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	// secp256k1 and multisig signatures, autogenerates GetKeySignatures()
+	KeySignatures []*keys.StdSignature `protobuf:"bytes,23,rep,name=key_signatures,json=keySignatures" json:"key_signatures,omitempty"`
+	// sha256 of the sealed travel rule envelope of a large transfer,
+	// autogenerates GetTravelRuleHash(). New fields of the tx take
+	// numbers from 100, the messages above take those below.
+	TravelRuleHash []byte `protobuf:"bytes,100,opt,name=travel_rule_hash,json=travelRuleHash,proto3" json:"travel_rule_hash,omitempty"`
 }
 
 func (m *Tx) Reset()                    { *m = Tx{} }
@@ -86,14 +154,194 @@ type Tx_ReturnEscrowMsg struct {
 type Tx_UpdateEscrowMsg struct {
 	UpdateEscrowMsg *escrow.UpdateEscrowPartiesMsg `protobuf:"bytes,7,opt,name=update_escrow_msg,json=updateEscrowMsg,oneof"`
 }
+type Tx_SetPriceMsg struct {
+	SetPriceMsg *oracle.SetPriceMsg `protobuf:"bytes,8,opt,name=set_price_msg,json=setPriceMsg,oneof"`
+}
+type Tx_AssignRoleMsg struct {
+	AssignRoleMsg *rbac.AssignRoleMsg `protobuf:"bytes,9,opt,name=assign_role_msg,json=assignRoleMsg,oneof"`
+}
+type Tx_RevokeRoleMsg struct {
+	RevokeRoleMsg *rbac.RevokeRoleMsg `protobuf:"bytes,10,opt,name=revoke_role_msg,json=revokeRoleMsg,oneof"`
+}
+type Tx_CreateGrantMsg struct {
+	CreateGrantMsg *grant.CreateGrantMsg `protobuf:"bytes,11,opt,name=create_grant_msg,json=createGrantMsg,oneof"`
+}
+type Tx_RevokeGrantMsg struct {
+	RevokeGrantMsg *grant.RevokeGrantMsg `protobuf:"bytes,12,opt,name=revoke_grant_msg,json=revokeGrantMsg,oneof"`
+}
+type Tx_CreateSessionMsg struct {
+	CreateSessionMsg *session.CreateSessionMsg `protobuf:"bytes,13,opt,name=create_session_msg,json=createSessionMsg,oneof"`
+}
+type Tx_RevokeSessionMsg struct {
+	RevokeSessionMsg *session.RevokeSessionMsg `protobuf:"bytes,14,opt,name=revoke_session_msg,json=revokeSessionMsg,oneof"`
+}
+type Tx_SellNameMsg struct {
+	SellNameMsg *namecoin.SellNameMsg `protobuf:"bytes,15,opt,name=sell_name_msg,json=sellNameMsg,oneof"`
+}
+type Tx_BuyNameMsg struct {
+	BuyNameMsg *namecoin.BuyNameMsg `protobuf:"bytes,16,opt,name=buy_name_msg,json=buyNameMsg,oneof"`
+}
+type Tx_CancelNameSaleMsg struct {
+	CancelNameSaleMsg *namecoin.CancelNameSaleMsg `protobuf:"bytes,17,opt,name=cancel_name_sale_msg,json=cancelNameSaleMsg,oneof"`
+}
+type Tx_UpdateMetadataMsg struct {
+	UpdateMetadataMsg *namecoin.UpdateWalletMetadataMsg `protobuf:"bytes,18,opt,name=update_metadata_msg,json=updateMetadataMsg,oneof"`
+}
+type Tx_BidArbitrationMsg struct {
+	BidArbitrationMsg *escrow.BidArbitrationMsg `protobuf:"bytes,19,opt,name=bid_arbitration_msg,json=bidArbitrationMsg,oneof"`
+}
+type Tx_AssignArbiterMsg struct {
+	AssignArbiterMsg *escrow.AssignArbiterMsg `protobuf:"bytes,24,opt,name=assign_arbiter_msg,json=assignArbiterMsg,oneof"`
+}
+type Tx_CreateEscrowMsgV2 struct {
+	CreateEscrowMsgV2 *escrow.CreateEscrowMsgV2 `protobuf:"bytes,25,opt,name=create_escrow_msg_v2,json=createEscrowMsgV2,oneof"`
+}
+type Tx_AnyMsg struct {
+	AnyMsg *anymsg.Any `protobuf:"bytes,26,opt,name=any_msg,json=anyMsg,oneof"`
+}
+type Tx_UpdateObserversMsg struct {
+	UpdateObserversMsg *escrow.UpdateEscrowObserversMsg `protobuf:"bytes,27,opt,name=update_observers_msg,json=updateObserversMsg,oneof"`
+}
+type Tx_SetFeatureMsg struct {
+	SetFeatureMsg *features.SetFeatureMsg `protobuf:"bytes,28,opt,name=set_feature_msg,json=setFeatureMsg,oneof"`
+}
+type Tx_NetEscrowsMsg struct {
+	NetEscrowsMsg *escrow.NetEscrowsMsg `protobuf:"bytes,29,opt,name=net_escrows_msg,json=netEscrowsMsg,oneof"`
+}
+type Tx_SetArbiterPolicyMsg struct {
+	SetArbiterPolicyMsg *escrow.SetArbiterPolicyMsg `protobuf:"bytes,30,opt,name=set_arbiter_policy_msg,json=setArbiterPolicyMsg,oneof"`
+}
+type Tx_CreateOrderMsg struct {
+	CreateOrderMsg *trade.CreateOrderMsg `protobuf:"bytes,31,opt,name=create_order_msg,json=createOrderMsg,oneof"`
+}
+type Tx_FillOrderMsg struct {
+	FillOrderMsg *trade.FillOrderMsg `protobuf:"bytes,32,opt,name=fill_order_msg,json=fillOrderMsg,oneof"`
+}
+type Tx_CancelOrderMsg struct {
+	CancelOrderMsg *trade.CancelOrderMsg `protobuf:"bytes,33,opt,name=cancel_order_msg,json=cancelOrderMsg,oneof"`
+}
+type Tx_SetConversionMsg struct {
+	SetConversionMsg *feepool.SetConversionMsg `protobuf:"bytes,34,opt,name=set_conversion_msg,json=setConversionMsg,oneof"`
+}
+type Tx_RevealMemoMsg struct {
+	RevealMemoMsg *escrow.RevealMemoMsg `protobuf:"bytes,35,opt,name=reveal_memo_msg,json=revealMemoMsg,oneof"`
+}
+type Tx_ShieldMsg struct {
+	ShieldMsg *confidential.ShieldMsg `protobuf:"bytes,36,opt,name=shield_msg,json=shieldMsg,oneof"`
+}
+type Tx_UnshieldMsg struct {
+	UnshieldMsg *confidential.UnshieldMsg `protobuf:"bytes,37,opt,name=unshield_msg,json=unshieldMsg,oneof"`
+}
+type Tx_SetViewKeyMsg struct {
+	SetViewKeyMsg *confidential.SetViewKeyMsg `protobuf:"bytes,38,opt,name=set_view_key_msg,json=setViewKeyMsg,oneof"`
+}
+type Tx_CreateConfidentialEscrowMsg struct {
+	CreateConfidentialEscrowMsg *confidential.CreateEscrowMsg `protobuf:"bytes,39,opt,name=create_confidential_escrow_msg,json=createConfidentialEscrowMsg,oneof"`
+}
+type Tx_ReleaseConfidentialEscrowMsg struct {
+	ReleaseConfidentialEscrowMsg *confidential.ReleaseEscrowMsg `protobuf:"bytes,40,opt,name=release_confidential_escrow_msg,json=releaseConfidentialEscrowMsg,oneof"`
+}
+type Tx_ReturnConfidentialEscrowMsg struct {
+	ReturnConfidentialEscrowMsg *confidential.ReturnEscrowMsg `protobuf:"bytes,41,opt,name=return_confidential_escrow_msg,json=returnConfidentialEscrowMsg,oneof"`
+}
+type Tx_GrantViewMsg struct {
+	GrantViewMsg *confidential.GrantViewMsg `protobuf:"bytes,42,opt,name=grant_view_msg,json=grantViewMsg,oneof"`
+}
+type Tx_TapMsg struct {
+	TapMsg *faucet.TapMsg `protobuf:"bytes,43,opt,name=tap_msg,json=tapMsg,oneof"`
+}
+type Tx_CreateFromTemplateMsg struct {
+	CreateFromTemplateMsg *escrow.CreateFromTemplateMsg `protobuf:"bytes,44,opt,name=create_from_template_msg,json=createFromTemplateMsg,oneof"`
+}
+type Tx_SetTemplateMsg struct {
+	SetTemplateMsg *escrow.SetTemplateMsg `protobuf:"bytes,45,opt,name=set_template_msg,json=setTemplateMsg,oneof"`
+}
+type Tx_PingEscrowMsg struct {
+	PingEscrowMsg *escrow.PingEscrowMsg `protobuf:"bytes,46,opt,name=ping_escrow_msg,json=pingEscrowMsg,oneof"`
+}
+type Tx_SendEscrowMsg struct {
+	SendEscrowMsg *escrow.SendEscrowMsg `protobuf:"bytes,47,opt,name=send_escrow_msg,json=sendEscrowMsg,oneof"`
+}
+type Tx_QuarantineEscrowMsg struct {
+	QuarantineEscrowMsg *escrow.QuarantineEscrowMsg `protobuf:"bytes,48,opt,name=quarantine_escrow_msg,json=quarantineEscrowMsg,oneof"`
+}
+type Tx_RestoreEscrowMsg struct {
+	RestoreEscrowMsg *escrow.RestoreEscrowMsg `protobuf:"bytes,49,opt,name=restore_escrow_msg,json=restoreEscrowMsg,oneof"`
+}
+type Tx_ForceSettleEscrowMsg struct {
+	ForceSettleEscrowMsg *escrow.ForceSettleEscrowMsg `protobuf:"bytes,50,opt,name=force_settle_escrow_msg,json=forceSettleEscrowMsg,oneof"`
+}
+type Tx_ClawbackEscrowMsg struct {
+	ClawbackEscrowMsg *escrow.ClawbackEscrowMsg `protobuf:"bytes,51,opt,name=clawback_escrow_msg,json=clawbackEscrowMsg,oneof"`
+}
+type Tx_AttestMilestoneMsg struct {
+	AttestMilestoneMsg *escrow.AttestMilestoneMsg `protobuf:"bytes,52,opt,name=attest_milestone_msg,json=attestMilestoneMsg,oneof"`
+}
+type Tx_OfferEscrowPartyMsg struct {
+	OfferEscrowPartyMsg *escrow.OfferEscrowPartyMsg `protobuf:"bytes,53,opt,name=offer_escrow_party_msg,json=offerEscrowPartyMsg,oneof"`
+}
+type Tx_AcceptEscrowPartyMsg struct {
+	AcceptEscrowPartyMsg *escrow.AcceptEscrowPartyMsg `protobuf:"bytes,54,opt,name=accept_escrow_party_msg,json=acceptEscrowPartyMsg,oneof"`
+}
+type Tx_DisputeEscrowMsg struct {
+	DisputeEscrowMsg *escrow.DisputeEscrowMsg `protobuf:"bytes,55,opt,name=dispute_escrow_msg,json=disputeEscrowMsg,oneof"`
+}
+type Tx_RedriveJobMsg struct {
+	RedriveJobMsg *scheduler.RedriveJobMsg `protobuf:"bytes,56,opt,name=redrive_job_msg,json=redriveJobMsg,oneof"`
+}
 
-func (*Tx_SendMsg) isTx_Sum()          {}
-func (*Tx_NewTokenMsg) isTx_Sum()      {}
-func (*Tx_SetNameMsg) isTx_Sum()       {}
-func (*Tx_CreateEscrowMsg) isTx_Sum()  {}
-func (*Tx_ReleaseEscrowMsg) isTx_Sum() {}
-func (*Tx_ReturnEscrowMsg) isTx_Sum()  {}
-func (*Tx_UpdateEscrowMsg) isTx_Sum()  {}
+func (*Tx_SendMsg) isTx_Sum()                      {}
+func (*Tx_NewTokenMsg) isTx_Sum()                  {}
+func (*Tx_SetNameMsg) isTx_Sum()                   {}
+func (*Tx_CreateEscrowMsg) isTx_Sum()              {}
+func (*Tx_ReleaseEscrowMsg) isTx_Sum()             {}
+func (*Tx_ReturnEscrowMsg) isTx_Sum()              {}
+func (*Tx_UpdateEscrowMsg) isTx_Sum()              {}
+func (*Tx_SetPriceMsg) isTx_Sum()                  {}
+func (*Tx_AssignRoleMsg) isTx_Sum()                {}
+func (*Tx_RevokeRoleMsg) isTx_Sum()                {}
+func (*Tx_CreateGrantMsg) isTx_Sum()               {}
+func (*Tx_RevokeGrantMsg) isTx_Sum()               {}
+func (*Tx_CreateSessionMsg) isTx_Sum()             {}
+func (*Tx_RevokeSessionMsg) isTx_Sum()             {}
+func (*Tx_SellNameMsg) isTx_Sum()                  {}
+func (*Tx_BuyNameMsg) isTx_Sum()                   {}
+func (*Tx_CancelNameSaleMsg) isTx_Sum()            {}
+func (*Tx_UpdateMetadataMsg) isTx_Sum()            {}
+func (*Tx_BidArbitrationMsg) isTx_Sum()            {}
+func (*Tx_AssignArbiterMsg) isTx_Sum()             {}
+func (*Tx_CreateEscrowMsgV2) isTx_Sum()            {}
+func (*Tx_AnyMsg) isTx_Sum()                       {}
+func (*Tx_UpdateObserversMsg) isTx_Sum()           {}
+func (*Tx_SetFeatureMsg) isTx_Sum()                {}
+func (*Tx_NetEscrowsMsg) isTx_Sum()                {}
+func (*Tx_SetArbiterPolicyMsg) isTx_Sum()          {}
+func (*Tx_CreateOrderMsg) isTx_Sum()               {}
+func (*Tx_FillOrderMsg) isTx_Sum()                 {}
+func (*Tx_CancelOrderMsg) isTx_Sum()               {}
+func (*Tx_SetConversionMsg) isTx_Sum()             {}
+func (*Tx_RevealMemoMsg) isTx_Sum()                {}
+func (*Tx_ShieldMsg) isTx_Sum()                    {}
+func (*Tx_UnshieldMsg) isTx_Sum()                  {}
+func (*Tx_SetViewKeyMsg) isTx_Sum()                {}
+func (*Tx_CreateConfidentialEscrowMsg) isTx_Sum()  {}
+func (*Tx_ReleaseConfidentialEscrowMsg) isTx_Sum() {}
+func (*Tx_ReturnConfidentialEscrowMsg) isTx_Sum()  {}
+func (*Tx_GrantViewMsg) isTx_Sum()                 {}
+func (*Tx_TapMsg) isTx_Sum()                       {}
+func (*Tx_CreateFromTemplateMsg) isTx_Sum()        {}
+func (*Tx_SetTemplateMsg) isTx_Sum()               {}
+func (*Tx_PingEscrowMsg) isTx_Sum()                {}
+func (*Tx_SendEscrowMsg) isTx_Sum()                {}
+func (*Tx_QuarantineEscrowMsg) isTx_Sum()          {}
+func (*Tx_RestoreEscrowMsg) isTx_Sum()             {}
+func (*Tx_ForceSettleEscrowMsg) isTx_Sum()         {}
+func (*Tx_ClawbackEscrowMsg) isTx_Sum()            {}
+func (*Tx_AttestMilestoneMsg) isTx_Sum()           {}
+func (*Tx_OfferEscrowPartyMsg) isTx_Sum()          {}
+func (*Tx_AcceptEscrowPartyMsg) isTx_Sum()         {}
+func (*Tx_DisputeEscrowMsg) isTx_Sum()             {}
+func (*Tx_RedriveJobMsg) isTx_Sum()                {}
 
 func (m *Tx) GetSum() isTx_Sum {
 	if m != nil {
@@ -151,6 +399,321 @@ func (m *Tx) GetUpdateEscrowMsg() *escrow.UpdateEscrowPartiesMsg {
 	return nil
 }
 
+func (m *Tx) GetSetPriceMsg() *oracle.SetPriceMsg {
+	if x, ok := m.GetSum().(*Tx_SetPriceMsg); ok {
+		return x.SetPriceMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetAssignRoleMsg() *rbac.AssignRoleMsg {
+	if x, ok := m.GetSum().(*Tx_AssignRoleMsg); ok {
+		return x.AssignRoleMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetRevokeRoleMsg() *rbac.RevokeRoleMsg {
+	if x, ok := m.GetSum().(*Tx_RevokeRoleMsg); ok {
+		return x.RevokeRoleMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetCreateGrantMsg() *grant.CreateGrantMsg {
+	if x, ok := m.GetSum().(*Tx_CreateGrantMsg); ok {
+		return x.CreateGrantMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetRevokeGrantMsg() *grant.RevokeGrantMsg {
+	if x, ok := m.GetSum().(*Tx_RevokeGrantMsg); ok {
+		return x.RevokeGrantMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetCreateSessionMsg() *session.CreateSessionMsg {
+	if x, ok := m.GetSum().(*Tx_CreateSessionMsg); ok {
+		return x.CreateSessionMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetRevokeSessionMsg() *session.RevokeSessionMsg {
+	if x, ok := m.GetSum().(*Tx_RevokeSessionMsg); ok {
+		return x.RevokeSessionMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetSellNameMsg() *namecoin.SellNameMsg {
+	if x, ok := m.GetSum().(*Tx_SellNameMsg); ok {
+		return x.SellNameMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetBuyNameMsg() *namecoin.BuyNameMsg {
+	if x, ok := m.GetSum().(*Tx_BuyNameMsg); ok {
+		return x.BuyNameMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetCancelNameSaleMsg() *namecoin.CancelNameSaleMsg {
+	if x, ok := m.GetSum().(*Tx_CancelNameSaleMsg); ok {
+		return x.CancelNameSaleMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetUpdateMetadataMsg() *namecoin.UpdateWalletMetadataMsg {
+	if x, ok := m.GetSum().(*Tx_UpdateMetadataMsg); ok {
+		return x.UpdateMetadataMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetBidArbitrationMsg() *escrow.BidArbitrationMsg {
+	if x, ok := m.GetSum().(*Tx_BidArbitrationMsg); ok {
+		return x.BidArbitrationMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetAssignArbiterMsg() *escrow.AssignArbiterMsg {
+	if x, ok := m.GetSum().(*Tx_AssignArbiterMsg); ok {
+		return x.AssignArbiterMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetCreateEscrowMsgV2() *escrow.CreateEscrowMsgV2 {
+	if x, ok := m.GetSum().(*Tx_CreateEscrowMsgV2); ok {
+		return x.CreateEscrowMsgV2
+	}
+	return nil
+}
+
+func (m *Tx) GetAnyMsg() *anymsg.Any {
+	if x, ok := m.GetSum().(*Tx_AnyMsg); ok {
+		return x.AnyMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetUpdateObserversMsg() *escrow.UpdateEscrowObserversMsg {
+	if x, ok := m.GetSum().(*Tx_UpdateObserversMsg); ok {
+		return x.UpdateObserversMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetSetFeatureMsg() *features.SetFeatureMsg {
+	if x, ok := m.GetSum().(*Tx_SetFeatureMsg); ok {
+		return x.SetFeatureMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetNetEscrowsMsg() *escrow.NetEscrowsMsg {
+	if x, ok := m.GetSum().(*Tx_NetEscrowsMsg); ok {
+		return x.NetEscrowsMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetSetArbiterPolicyMsg() *escrow.SetArbiterPolicyMsg {
+	if x, ok := m.GetSum().(*Tx_SetArbiterPolicyMsg); ok {
+		return x.SetArbiterPolicyMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetCreateOrderMsg() *trade.CreateOrderMsg {
+	if x, ok := m.GetSum().(*Tx_CreateOrderMsg); ok {
+		return x.CreateOrderMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetFillOrderMsg() *trade.FillOrderMsg {
+	if x, ok := m.GetSum().(*Tx_FillOrderMsg); ok {
+		return x.FillOrderMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetCancelOrderMsg() *trade.CancelOrderMsg {
+	if x, ok := m.GetSum().(*Tx_CancelOrderMsg); ok {
+		return x.CancelOrderMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetSetConversionMsg() *feepool.SetConversionMsg {
+	if x, ok := m.GetSum().(*Tx_SetConversionMsg); ok {
+		return x.SetConversionMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetRevealMemoMsg() *escrow.RevealMemoMsg {
+	if x, ok := m.GetSum().(*Tx_RevealMemoMsg); ok {
+		return x.RevealMemoMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetShieldMsg() *confidential.ShieldMsg {
+	if x, ok := m.GetSum().(*Tx_ShieldMsg); ok {
+		return x.ShieldMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetUnshieldMsg() *confidential.UnshieldMsg {
+	if x, ok := m.GetSum().(*Tx_UnshieldMsg); ok {
+		return x.UnshieldMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetSetViewKeyMsg() *confidential.SetViewKeyMsg {
+	if x, ok := m.GetSum().(*Tx_SetViewKeyMsg); ok {
+		return x.SetViewKeyMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetCreateConfidentialEscrowMsg() *confidential.CreateEscrowMsg {
+	if x, ok := m.GetSum().(*Tx_CreateConfidentialEscrowMsg); ok {
+		return x.CreateConfidentialEscrowMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetReleaseConfidentialEscrowMsg() *confidential.ReleaseEscrowMsg {
+	if x, ok := m.GetSum().(*Tx_ReleaseConfidentialEscrowMsg); ok {
+		return x.ReleaseConfidentialEscrowMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetReturnConfidentialEscrowMsg() *confidential.ReturnEscrowMsg {
+	if x, ok := m.GetSum().(*Tx_ReturnConfidentialEscrowMsg); ok {
+		return x.ReturnConfidentialEscrowMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetGrantViewMsg() *confidential.GrantViewMsg {
+	if x, ok := m.GetSum().(*Tx_GrantViewMsg); ok {
+		return x.GrantViewMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetTapMsg() *faucet.TapMsg {
+	if x, ok := m.GetSum().(*Tx_TapMsg); ok {
+		return x.TapMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetCreateFromTemplateMsg() *escrow.CreateFromTemplateMsg {
+	if x, ok := m.GetSum().(*Tx_CreateFromTemplateMsg); ok {
+		return x.CreateFromTemplateMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetSetTemplateMsg() *escrow.SetTemplateMsg {
+	if x, ok := m.GetSum().(*Tx_SetTemplateMsg); ok {
+		return x.SetTemplateMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetPingEscrowMsg() *escrow.PingEscrowMsg {
+	if x, ok := m.GetSum().(*Tx_PingEscrowMsg); ok {
+		return x.PingEscrowMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetSendEscrowMsg() *escrow.SendEscrowMsg {
+	if x, ok := m.GetSum().(*Tx_SendEscrowMsg); ok {
+		return x.SendEscrowMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetQuarantineEscrowMsg() *escrow.QuarantineEscrowMsg {
+	if x, ok := m.GetSum().(*Tx_QuarantineEscrowMsg); ok {
+		return x.QuarantineEscrowMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetRestoreEscrowMsg() *escrow.RestoreEscrowMsg {
+	if x, ok := m.GetSum().(*Tx_RestoreEscrowMsg); ok {
+		return x.RestoreEscrowMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetForceSettleEscrowMsg() *escrow.ForceSettleEscrowMsg {
+	if x, ok := m.GetSum().(*Tx_ForceSettleEscrowMsg); ok {
+		return x.ForceSettleEscrowMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetClawbackEscrowMsg() *escrow.ClawbackEscrowMsg {
+	if x, ok := m.GetSum().(*Tx_ClawbackEscrowMsg); ok {
+		return x.ClawbackEscrowMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetAttestMilestoneMsg() *escrow.AttestMilestoneMsg {
+	if x, ok := m.GetSum().(*Tx_AttestMilestoneMsg); ok {
+		return x.AttestMilestoneMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetOfferEscrowPartyMsg() *escrow.OfferEscrowPartyMsg {
+	if x, ok := m.GetSum().(*Tx_OfferEscrowPartyMsg); ok {
+		return x.OfferEscrowPartyMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetAcceptEscrowPartyMsg() *escrow.AcceptEscrowPartyMsg {
+	if x, ok := m.GetSum().(*Tx_AcceptEscrowPartyMsg); ok {
+		return x.AcceptEscrowPartyMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetDisputeEscrowMsg() *escrow.DisputeEscrowMsg {
+	if x, ok := m.GetSum().(*Tx_DisputeEscrowMsg); ok {
+		return x.DisputeEscrowMsg
+	}
+	return nil
+}
+
+func (m *Tx) GetRedriveJobMsg() *scheduler.RedriveJobMsg {
+	if x, ok := m.GetSum().(*Tx_RedriveJobMsg); ok {
+		return x.RedriveJobMsg
+	}
+	return nil
+}
+
 func (m *Tx) GetFees() *cash.FeeInfo {
 	if m != nil {
 		return m.Fees
@@ -172,6 +735,20 @@ func (m *Tx) GetPreimage() []byte {
 	return nil
 }
 
+func (m *Tx) GetKeySignatures() []*keys.StdSignature {
+	if m != nil {
+		return m.KeySignatures
+	}
+	return nil
+}
+
+func (m *Tx) GetTravelRuleHash() []byte {
+	if m != nil {
+		return m.TravelRuleHash
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*Tx) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _Tx_OneofMarshaler, _Tx_OneofUnmarshaler, _Tx_OneofSizer, []interface{}{
@@ -182,6 +759,51 @@ func (*Tx) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, fun
 		(*Tx_ReleaseEscrowMsg)(nil),
 		(*Tx_ReturnEscrowMsg)(nil),
 		(*Tx_UpdateEscrowMsg)(nil),
+		(*Tx_SetPriceMsg)(nil),
+		(*Tx_AssignRoleMsg)(nil),
+		(*Tx_RevokeRoleMsg)(nil),
+		(*Tx_CreateGrantMsg)(nil),
+		(*Tx_RevokeGrantMsg)(nil),
+		(*Tx_CreateSessionMsg)(nil),
+		(*Tx_RevokeSessionMsg)(nil),
+		(*Tx_SellNameMsg)(nil),
+		(*Tx_BuyNameMsg)(nil),
+		(*Tx_CancelNameSaleMsg)(nil),
+		(*Tx_UpdateMetadataMsg)(nil),
+		(*Tx_BidArbitrationMsg)(nil),
+		(*Tx_AssignArbiterMsg)(nil),
+		(*Tx_CreateEscrowMsgV2)(nil),
+		(*Tx_AnyMsg)(nil),
+		(*Tx_UpdateObserversMsg)(nil),
+		(*Tx_SetFeatureMsg)(nil),
+		(*Tx_NetEscrowsMsg)(nil),
+		(*Tx_SetArbiterPolicyMsg)(nil),
+		(*Tx_CreateOrderMsg)(nil),
+		(*Tx_FillOrderMsg)(nil),
+		(*Tx_CancelOrderMsg)(nil),
+		(*Tx_SetConversionMsg)(nil),
+		(*Tx_RevealMemoMsg)(nil),
+		(*Tx_ShieldMsg)(nil),
+		(*Tx_UnshieldMsg)(nil),
+		(*Tx_SetViewKeyMsg)(nil),
+		(*Tx_CreateConfidentialEscrowMsg)(nil),
+		(*Tx_ReleaseConfidentialEscrowMsg)(nil),
+		(*Tx_ReturnConfidentialEscrowMsg)(nil),
+		(*Tx_GrantViewMsg)(nil),
+		(*Tx_TapMsg)(nil),
+		(*Tx_CreateFromTemplateMsg)(nil),
+		(*Tx_SetTemplateMsg)(nil),
+		(*Tx_PingEscrowMsg)(nil),
+		(*Tx_SendEscrowMsg)(nil),
+		(*Tx_QuarantineEscrowMsg)(nil),
+		(*Tx_RestoreEscrowMsg)(nil),
+		(*Tx_ForceSettleEscrowMsg)(nil),
+		(*Tx_ClawbackEscrowMsg)(nil),
+		(*Tx_AttestMilestoneMsg)(nil),
+		(*Tx_OfferEscrowPartyMsg)(nil),
+		(*Tx_AcceptEscrowPartyMsg)(nil),
+		(*Tx_DisputeEscrowMsg)(nil),
+		(*Tx_RedriveJobMsg)(nil),
 	}
 }
 
@@ -224,27 +846,252 @@ func _Tx_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.UpdateEscrowMsg); err != nil {
 			return err
 		}
-	case nil:
-	default:
-		return fmt.Errorf("Tx.Sum has unexpected type %T", x)
-	}
-	return nil
-}
-
-func _Tx_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
-	m := msg.(*Tx)
-	switch tag {
-	case 1: // sum.send_msg
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
+	case *Tx_SetPriceMsg:
+		_ = b.EncodeVarint(8<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SetPriceMsg); err != nil {
+			return err
 		}
-		msg := new(cash.SendMsg)
-		err := b.DecodeMessage(msg)
-		m.Sum = &Tx_SendMsg{msg}
-		return true, err
-	case 2: // sum.new_token_msg
-		if wire != proto.WireBytes {
-			return true, proto.ErrInternalBadWireType
+	case *Tx_AssignRoleMsg:
+		_ = b.EncodeVarint(9<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.AssignRoleMsg); err != nil {
+			return err
+		}
+	case *Tx_RevokeRoleMsg:
+		_ = b.EncodeVarint(10<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.RevokeRoleMsg); err != nil {
+			return err
+		}
+	case *Tx_CreateGrantMsg:
+		_ = b.EncodeVarint(11<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CreateGrantMsg); err != nil {
+			return err
+		}
+	case *Tx_RevokeGrantMsg:
+		_ = b.EncodeVarint(12<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.RevokeGrantMsg); err != nil {
+			return err
+		}
+	case *Tx_CreateSessionMsg:
+		_ = b.EncodeVarint(13<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CreateSessionMsg); err != nil {
+			return err
+		}
+	case *Tx_RevokeSessionMsg:
+		_ = b.EncodeVarint(14<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.RevokeSessionMsg); err != nil {
+			return err
+		}
+	case *Tx_SellNameMsg:
+		_ = b.EncodeVarint(15<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SellNameMsg); err != nil {
+			return err
+		}
+	case *Tx_BuyNameMsg:
+		_ = b.EncodeVarint(16<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.BuyNameMsg); err != nil {
+			return err
+		}
+	case *Tx_CancelNameSaleMsg:
+		_ = b.EncodeVarint(17<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CancelNameSaleMsg); err != nil {
+			return err
+		}
+	case *Tx_UpdateMetadataMsg:
+		_ = b.EncodeVarint(18<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.UpdateMetadataMsg); err != nil {
+			return err
+		}
+	case *Tx_BidArbitrationMsg:
+		_ = b.EncodeVarint(19<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.BidArbitrationMsg); err != nil {
+			return err
+		}
+	case *Tx_AssignArbiterMsg:
+		_ = b.EncodeVarint(24<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.AssignArbiterMsg); err != nil {
+			return err
+		}
+	case *Tx_CreateEscrowMsgV2:
+		_ = b.EncodeVarint(25<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CreateEscrowMsgV2); err != nil {
+			return err
+		}
+	case *Tx_AnyMsg:
+		_ = b.EncodeVarint(26<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.AnyMsg); err != nil {
+			return err
+		}
+	case *Tx_UpdateObserversMsg:
+		_ = b.EncodeVarint(27<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.UpdateObserversMsg); err != nil {
+			return err
+		}
+	case *Tx_SetFeatureMsg:
+		_ = b.EncodeVarint(28<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SetFeatureMsg); err != nil {
+			return err
+		}
+	case *Tx_NetEscrowsMsg:
+		_ = b.EncodeVarint(29<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.NetEscrowsMsg); err != nil {
+			return err
+		}
+	case *Tx_SetArbiterPolicyMsg:
+		_ = b.EncodeVarint(30<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SetArbiterPolicyMsg); err != nil {
+			return err
+		}
+	case *Tx_CreateOrderMsg:
+		_ = b.EncodeVarint(31<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CreateOrderMsg); err != nil {
+			return err
+		}
+	case *Tx_FillOrderMsg:
+		_ = b.EncodeVarint(32<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.FillOrderMsg); err != nil {
+			return err
+		}
+	case *Tx_CancelOrderMsg:
+		_ = b.EncodeVarint(33<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CancelOrderMsg); err != nil {
+			return err
+		}
+	case *Tx_SetConversionMsg:
+		_ = b.EncodeVarint(34<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SetConversionMsg); err != nil {
+			return err
+		}
+	case *Tx_RevealMemoMsg:
+		_ = b.EncodeVarint(35<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.RevealMemoMsg); err != nil {
+			return err
+		}
+	case *Tx_ShieldMsg:
+		_ = b.EncodeVarint(36<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ShieldMsg); err != nil {
+			return err
+		}
+	case *Tx_UnshieldMsg:
+		_ = b.EncodeVarint(37<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.UnshieldMsg); err != nil {
+			return err
+		}
+	case *Tx_SetViewKeyMsg:
+		_ = b.EncodeVarint(38<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SetViewKeyMsg); err != nil {
+			return err
+		}
+	case *Tx_CreateConfidentialEscrowMsg:
+		_ = b.EncodeVarint(39<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CreateConfidentialEscrowMsg); err != nil {
+			return err
+		}
+	case *Tx_ReleaseConfidentialEscrowMsg:
+		_ = b.EncodeVarint(40<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ReleaseConfidentialEscrowMsg); err != nil {
+			return err
+		}
+	case *Tx_ReturnConfidentialEscrowMsg:
+		_ = b.EncodeVarint(41<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ReturnConfidentialEscrowMsg); err != nil {
+			return err
+		}
+	case *Tx_GrantViewMsg:
+		_ = b.EncodeVarint(42<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.GrantViewMsg); err != nil {
+			return err
+		}
+	case *Tx_TapMsg:
+		_ = b.EncodeVarint(43<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.TapMsg); err != nil {
+			return err
+		}
+	case *Tx_CreateFromTemplateMsg:
+		_ = b.EncodeVarint(44<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CreateFromTemplateMsg); err != nil {
+			return err
+		}
+	case *Tx_SetTemplateMsg:
+		_ = b.EncodeVarint(45<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SetTemplateMsg); err != nil {
+			return err
+		}
+	case *Tx_PingEscrowMsg:
+		_ = b.EncodeVarint(46<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.PingEscrowMsg); err != nil {
+			return err
+		}
+	case *Tx_SendEscrowMsg:
+		_ = b.EncodeVarint(47<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SendEscrowMsg); err != nil {
+			return err
+		}
+	case *Tx_QuarantineEscrowMsg:
+		_ = b.EncodeVarint(48<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.QuarantineEscrowMsg); err != nil {
+			return err
+		}
+	case *Tx_RestoreEscrowMsg:
+		_ = b.EncodeVarint(49<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.RestoreEscrowMsg); err != nil {
+			return err
+		}
+	case *Tx_ForceSettleEscrowMsg:
+		_ = b.EncodeVarint(50<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ForceSettleEscrowMsg); err != nil {
+			return err
+		}
+	case *Tx_ClawbackEscrowMsg:
+		_ = b.EncodeVarint(51<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ClawbackEscrowMsg); err != nil {
+			return err
+		}
+	case *Tx_AttestMilestoneMsg:
+		_ = b.EncodeVarint(52<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.AttestMilestoneMsg); err != nil {
+			return err
+		}
+	case *Tx_OfferEscrowPartyMsg:
+		_ = b.EncodeVarint(53<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.OfferEscrowPartyMsg); err != nil {
+			return err
+		}
+	case *Tx_AcceptEscrowPartyMsg:
+		_ = b.EncodeVarint(54<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.AcceptEscrowPartyMsg); err != nil {
+			return err
+		}
+	case *Tx_DisputeEscrowMsg:
+		_ = b.EncodeVarint(55<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.DisputeEscrowMsg); err != nil {
+			return err
+		}
+	case *Tx_RedriveJobMsg:
+		_ = b.EncodeVarint(56<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.RedriveJobMsg); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("Tx.Sum has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _Tx_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*Tx)
+	switch tag {
+	case 1: // sum.send_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(cash.SendMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_SendMsg{msg}
+		return true, err
+	case 2: // sum.new_token_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
 		}
 		msg := new(namecoin.NewTokenMsg)
 		err := b.DecodeMessage(msg)
@@ -290,327 +1137,4576 @@ func _Tx_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bo
 		err := b.DecodeMessage(msg)
 		m.Sum = &Tx_UpdateEscrowMsg{msg}
 		return true, err
-	default:
-		return false, nil
-	}
-}
-
-func _Tx_OneofSizer(msg proto.Message) (n int) {
-	m := msg.(*Tx)
-	// sum
-	switch x := m.Sum.(type) {
-	case *Tx_SendMsg:
-		s := proto.Size(x.SendMsg)
-		n += proto.SizeVarint(1<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case *Tx_NewTokenMsg:
-		s := proto.Size(x.NewTokenMsg)
-		n += proto.SizeVarint(2<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case *Tx_SetNameMsg:
-		s := proto.Size(x.SetNameMsg)
-		n += proto.SizeVarint(3<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case *Tx_CreateEscrowMsg:
-		s := proto.Size(x.CreateEscrowMsg)
-		n += proto.SizeVarint(4<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case *Tx_ReleaseEscrowMsg:
-		s := proto.Size(x.ReleaseEscrowMsg)
-		n += proto.SizeVarint(5<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case *Tx_ReturnEscrowMsg:
-		s := proto.Size(x.ReturnEscrowMsg)
-		n += proto.SizeVarint(6<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case *Tx_UpdateEscrowMsg:
-		s := proto.Size(x.UpdateEscrowMsg)
-		n += proto.SizeVarint(7<<3 | proto.WireBytes)
-		n += proto.SizeVarint(uint64(s))
-		n += s
-	case nil:
-	default:
-		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
-	}
-	return n
-}
-
-func init() {
-	proto.RegisterType((*Tx)(nil), "app.Tx")
-}
-func (m *Tx) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
-}
-
-func (m *Tx) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Sum != nil {
-		nn1, err := m.Sum.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
-		}
-		i += nn1
-	}
-	if m.Fees != nil {
-		dAtA[i] = 0xa2
-		i++
-		dAtA[i] = 0x1
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(m.Fees.Size()))
-		n2, err := m.Fees.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	case 8: // sum.set_price_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
 		}
-		i += n2
-	}
-	if len(m.Signatures) > 0 {
-		for _, msg := range m.Signatures {
-			dAtA[i] = 0xaa
-			i++
-			dAtA[i] = 0x1
-			i++
-			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
+		msg := new(oracle.SetPriceMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_SetPriceMsg{msg}
+		return true, err
+	case 9: // sum.assign_role_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
 		}
-	}
-	if len(m.Preimage) > 0 {
-		dAtA[i] = 0xb2
-		i++
-		dAtA[i] = 0x1
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(len(m.Preimage)))
-		i += copy(dAtA[i:], m.Preimage)
-	}
-	return i, nil
-}
-
-func (m *Tx_SendMsg) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
-	if m.SendMsg != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(m.SendMsg.Size()))
-		n3, err := m.SendMsg.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		msg := new(rbac.AssignRoleMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_AssignRoleMsg{msg}
+		return true, err
+	case 10: // sum.revoke_role_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
 		}
-		i += n3
-	}
-	return i, nil
-}
-func (m *Tx_NewTokenMsg) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
-	if m.NewTokenMsg != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(m.NewTokenMsg.Size()))
-		n4, err := m.NewTokenMsg.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		msg := new(rbac.RevokeRoleMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_RevokeRoleMsg{msg}
+		return true, err
+	case 11: // sum.create_grant_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
 		}
-		i += n4
-	}
-	return i, nil
-}
-func (m *Tx_SetNameMsg) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
-	if m.SetNameMsg != nil {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(m.SetNameMsg.Size()))
-		n5, err := m.SetNameMsg.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		msg := new(grant.CreateGrantMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_CreateGrantMsg{msg}
+		return true, err
+	case 12: // sum.revoke_grant_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
 		}
-		i += n5
-	}
-	return i, nil
-}
-func (m *Tx_CreateEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
-	if m.CreateEscrowMsg != nil {
-		dAtA[i] = 0x22
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(m.CreateEscrowMsg.Size()))
-		n6, err := m.CreateEscrowMsg.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		msg := new(grant.RevokeGrantMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_RevokeGrantMsg{msg}
+		return true, err
+	case 13: // sum.create_session_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
 		}
-		i += n6
-	}
-	return i, nil
-}
-func (m *Tx_ReleaseEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
-	if m.ReleaseEscrowMsg != nil {
-		dAtA[i] = 0x2a
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(m.ReleaseEscrowMsg.Size()))
-		n7, err := m.ReleaseEscrowMsg.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		msg := new(session.CreateSessionMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_CreateSessionMsg{msg}
+		return true, err
+	case 14: // sum.revoke_session_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
 		}
-		i += n7
-	}
-	return i, nil
-}
-func (m *Tx_ReturnEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
-	if m.ReturnEscrowMsg != nil {
-		dAtA[i] = 0x32
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(m.ReturnEscrowMsg.Size()))
-		n8, err := m.ReturnEscrowMsg.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		msg := new(session.RevokeSessionMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_RevokeSessionMsg{msg}
+		return true, err
+	case 15: // sum.sell_name_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
 		}
-		i += n8
-	}
-	return i, nil
-}
-func (m *Tx_UpdateEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
-	i := 0
-	if m.UpdateEscrowMsg != nil {
-		dAtA[i] = 0x3a
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(m.UpdateEscrowMsg.Size()))
-		n9, err := m.UpdateEscrowMsg.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+		msg := new(namecoin.SellNameMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_SellNameMsg{msg}
+		return true, err
+	case 16: // sum.buy_name_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
 		}
-		i += n9
-	}
-	return i, nil
-}
-func encodeVarintCodec(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
-	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
-}
-func (m *Tx) Size() (n int) {
-	var l int
-	_ = l
-	if m.Sum != nil {
-		n += m.Sum.Size()
-	}
-	if m.Fees != nil {
-		l = m.Fees.Size()
-		n += 2 + l + sovCodec(uint64(l))
-	}
-	if len(m.Signatures) > 0 {
-		for _, e := range m.Signatures {
-			l = e.Size()
-			n += 2 + l + sovCodec(uint64(l))
+		msg := new(namecoin.BuyNameMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_BuyNameMsg{msg}
+		return true, err
+	case 17: // sum.cancel_name_sale_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(namecoin.CancelNameSaleMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_CancelNameSaleMsg{msg}
+		return true, err
+	case 18: // sum.update_metadata_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(namecoin.UpdateWalletMetadataMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_UpdateMetadataMsg{msg}
+		return true, err
+	case 19: // sum.bid_arbitration_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.BidArbitrationMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_BidArbitrationMsg{msg}
+		return true, err
+	case 24: // sum.assign_arbiter_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.AssignArbiterMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_AssignArbiterMsg{msg}
+		return true, err
+	case 25: // sum.create_escrow_msg_v2
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.CreateEscrowMsgV2)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_CreateEscrowMsgV2{msg}
+		return true, err
+	case 26: // sum.any_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(anymsg.Any)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_AnyMsg{msg}
+		return true, err
+	case 27: // sum.update_observers_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.UpdateEscrowObserversMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_UpdateObserversMsg{msg}
+		return true, err
+	case 28: // sum.set_feature_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(features.SetFeatureMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_SetFeatureMsg{msg}
+		return true, err
+	case 29: // sum.net_escrows_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.NetEscrowsMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_NetEscrowsMsg{msg}
+		return true, err
+	case 30: // sum.set_arbiter_policy_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.SetArbiterPolicyMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_SetArbiterPolicyMsg{msg}
+		return true, err
+	case 31: // sum.create_order_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(trade.CreateOrderMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_CreateOrderMsg{msg}
+		return true, err
+	case 32: // sum.fill_order_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(trade.FillOrderMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_FillOrderMsg{msg}
+		return true, err
+	case 33: // sum.cancel_order_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(trade.CancelOrderMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_CancelOrderMsg{msg}
+		return true, err
+	case 34: // sum.set_conversion_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(feepool.SetConversionMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_SetConversionMsg{msg}
+		return true, err
+	case 35: // sum.reveal_memo_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.RevealMemoMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_RevealMemoMsg{msg}
+		return true, err
+	case 36: // sum.shield_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(confidential.ShieldMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_ShieldMsg{msg}
+		return true, err
+	case 37: // sum.unshield_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(confidential.UnshieldMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_UnshieldMsg{msg}
+		return true, err
+	case 38: // sum.set_view_key_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(confidential.SetViewKeyMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_SetViewKeyMsg{msg}
+		return true, err
+	case 39: // sum.create_confidential_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(confidential.CreateEscrowMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_CreateConfidentialEscrowMsg{msg}
+		return true, err
+	case 40: // sum.release_confidential_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(confidential.ReleaseEscrowMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_ReleaseConfidentialEscrowMsg{msg}
+		return true, err
+	case 41: // sum.return_confidential_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(confidential.ReturnEscrowMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_ReturnConfidentialEscrowMsg{msg}
+		return true, err
+	case 42: // sum.grant_view_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(confidential.GrantViewMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_GrantViewMsg{msg}
+		return true, err
+	case 43: // sum.tap_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(faucet.TapMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_TapMsg{msg}
+		return true, err
+	case 44: // sum.create_from_template_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.CreateFromTemplateMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_CreateFromTemplateMsg{msg}
+		return true, err
+	case 45: // sum.set_template_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.SetTemplateMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_SetTemplateMsg{msg}
+		return true, err
+	case 46: // sum.ping_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.PingEscrowMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_PingEscrowMsg{msg}
+		return true, err
+	case 47: // sum.send_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.SendEscrowMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_SendEscrowMsg{msg}
+		return true, err
+	case 48: // sum.quarantine_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.QuarantineEscrowMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_QuarantineEscrowMsg{msg}
+		return true, err
+	case 49: // sum.restore_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.RestoreEscrowMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_RestoreEscrowMsg{msg}
+		return true, err
+	case 50: // sum.force_settle_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.ForceSettleEscrowMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_ForceSettleEscrowMsg{msg}
+		return true, err
+	case 51: // sum.clawback_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.ClawbackEscrowMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_ClawbackEscrowMsg{msg}
+		return true, err
+	case 52: // sum.attest_milestone_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.AttestMilestoneMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_AttestMilestoneMsg{msg}
+		return true, err
+	case 53: // sum.offer_escrow_party_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.OfferEscrowPartyMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_OfferEscrowPartyMsg{msg}
+		return true, err
+	case 54: // sum.accept_escrow_party_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.AcceptEscrowPartyMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_AcceptEscrowPartyMsg{msg}
+		return true, err
+	case 55: // sum.dispute_escrow_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(escrow.DisputeEscrowMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_DisputeEscrowMsg{msg}
+		return true, err
+	case 56: // sum.redrive_job_msg
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(scheduler.RedriveJobMsg)
+		err := b.DecodeMessage(msg)
+		m.Sum = &Tx_RedriveJobMsg{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _Tx_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*Tx)
+	// sum
+	switch x := m.Sum.(type) {
+	case *Tx_SendMsg:
+		s := proto.Size(x.SendMsg)
+		n += proto.SizeVarint(1<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_NewTokenMsg:
+		s := proto.Size(x.NewTokenMsg)
+		n += proto.SizeVarint(2<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_SetNameMsg:
+		s := proto.Size(x.SetNameMsg)
+		n += proto.SizeVarint(3<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_CreateEscrowMsg:
+		s := proto.Size(x.CreateEscrowMsg)
+		n += proto.SizeVarint(4<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_ReleaseEscrowMsg:
+		s := proto.Size(x.ReleaseEscrowMsg)
+		n += proto.SizeVarint(5<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_ReturnEscrowMsg:
+		s := proto.Size(x.ReturnEscrowMsg)
+		n += proto.SizeVarint(6<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_UpdateEscrowMsg:
+		s := proto.Size(x.UpdateEscrowMsg)
+		n += proto.SizeVarint(7<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_SetPriceMsg:
+		s := proto.Size(x.SetPriceMsg)
+		n += proto.SizeVarint(8<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_AssignRoleMsg:
+		s := proto.Size(x.AssignRoleMsg)
+		n += proto.SizeVarint(9<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_RevokeRoleMsg:
+		s := proto.Size(x.RevokeRoleMsg)
+		n += proto.SizeVarint(10<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_CreateGrantMsg:
+		s := proto.Size(x.CreateGrantMsg)
+		n += proto.SizeVarint(11<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_RevokeGrantMsg:
+		s := proto.Size(x.RevokeGrantMsg)
+		n += proto.SizeVarint(12<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_CreateSessionMsg:
+		s := proto.Size(x.CreateSessionMsg)
+		n += proto.SizeVarint(13<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_RevokeSessionMsg:
+		s := proto.Size(x.RevokeSessionMsg)
+		n += proto.SizeVarint(14<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_SellNameMsg:
+		s := proto.Size(x.SellNameMsg)
+		n += proto.SizeVarint(15<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_BuyNameMsg:
+		s := proto.Size(x.BuyNameMsg)
+		n += proto.SizeVarint(16<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_CancelNameSaleMsg:
+		s := proto.Size(x.CancelNameSaleMsg)
+		n += proto.SizeVarint(17<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_UpdateMetadataMsg:
+		s := proto.Size(x.UpdateMetadataMsg)
+		n += proto.SizeVarint(18<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_BidArbitrationMsg:
+		s := proto.Size(x.BidArbitrationMsg)
+		n += proto.SizeVarint(19<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_AssignArbiterMsg:
+		s := proto.Size(x.AssignArbiterMsg)
+		n += proto.SizeVarint(24<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_CreateEscrowMsgV2:
+		s := proto.Size(x.CreateEscrowMsgV2)
+		n += proto.SizeVarint(25<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_AnyMsg:
+		s := proto.Size(x.AnyMsg)
+		n += proto.SizeVarint(26<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_UpdateObserversMsg:
+		s := proto.Size(x.UpdateObserversMsg)
+		n += proto.SizeVarint(27<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_SetFeatureMsg:
+		s := proto.Size(x.SetFeatureMsg)
+		n += proto.SizeVarint(28<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_NetEscrowsMsg:
+		s := proto.Size(x.NetEscrowsMsg)
+		n += proto.SizeVarint(29<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_SetArbiterPolicyMsg:
+		s := proto.Size(x.SetArbiterPolicyMsg)
+		n += proto.SizeVarint(30<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_CreateOrderMsg:
+		s := proto.Size(x.CreateOrderMsg)
+		n += proto.SizeVarint(31<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_FillOrderMsg:
+		s := proto.Size(x.FillOrderMsg)
+		n += proto.SizeVarint(32<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_CancelOrderMsg:
+		s := proto.Size(x.CancelOrderMsg)
+		n += proto.SizeVarint(33<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_SetConversionMsg:
+		s := proto.Size(x.SetConversionMsg)
+		n += proto.SizeVarint(34<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_RevealMemoMsg:
+		s := proto.Size(x.RevealMemoMsg)
+		n += proto.SizeVarint(35<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_ShieldMsg:
+		s := proto.Size(x.ShieldMsg)
+		n += proto.SizeVarint(36<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_UnshieldMsg:
+		s := proto.Size(x.UnshieldMsg)
+		n += proto.SizeVarint(37<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_SetViewKeyMsg:
+		s := proto.Size(x.SetViewKeyMsg)
+		n += proto.SizeVarint(38<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_CreateConfidentialEscrowMsg:
+		s := proto.Size(x.CreateConfidentialEscrowMsg)
+		n += proto.SizeVarint(39<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_ReleaseConfidentialEscrowMsg:
+		s := proto.Size(x.ReleaseConfidentialEscrowMsg)
+		n += proto.SizeVarint(40<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_ReturnConfidentialEscrowMsg:
+		s := proto.Size(x.ReturnConfidentialEscrowMsg)
+		n += proto.SizeVarint(41<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_GrantViewMsg:
+		s := proto.Size(x.GrantViewMsg)
+		n += proto.SizeVarint(42<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_TapMsg:
+		s := proto.Size(x.TapMsg)
+		n += proto.SizeVarint(43<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_CreateFromTemplateMsg:
+		s := proto.Size(x.CreateFromTemplateMsg)
+		n += proto.SizeVarint(44<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_SetTemplateMsg:
+		s := proto.Size(x.SetTemplateMsg)
+		n += proto.SizeVarint(45<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_PingEscrowMsg:
+		s := proto.Size(x.PingEscrowMsg)
+		n += proto.SizeVarint(46<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_SendEscrowMsg:
+		s := proto.Size(x.SendEscrowMsg)
+		n += proto.SizeVarint(47<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_QuarantineEscrowMsg:
+		s := proto.Size(x.QuarantineEscrowMsg)
+		n += proto.SizeVarint(48<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_RestoreEscrowMsg:
+		s := proto.Size(x.RestoreEscrowMsg)
+		n += proto.SizeVarint(49<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_ForceSettleEscrowMsg:
+		s := proto.Size(x.ForceSettleEscrowMsg)
+		n += proto.SizeVarint(50<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_ClawbackEscrowMsg:
+		s := proto.Size(x.ClawbackEscrowMsg)
+		n += proto.SizeVarint(51<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_AttestMilestoneMsg:
+		s := proto.Size(x.AttestMilestoneMsg)
+		n += proto.SizeVarint(52<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_OfferEscrowPartyMsg:
+		s := proto.Size(x.OfferEscrowPartyMsg)
+		n += proto.SizeVarint(53<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_AcceptEscrowPartyMsg:
+		s := proto.Size(x.AcceptEscrowPartyMsg)
+		n += proto.SizeVarint(54<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_DisputeEscrowMsg:
+		s := proto.Size(x.DisputeEscrowMsg)
+		n += proto.SizeVarint(55<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Tx_RedriveJobMsg:
+		s := proto.Size(x.RedriveJobMsg)
+		n += proto.SizeVarint(56<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+// RichEscrow is an escrow with all its parties resolved
+// to their wallets, as returned by the "/escrows/rich" query
+type RichEscrow struct {
+	Escrow *escrow.Escrow `protobuf:"bytes,1,opt,name=escrow" json:"escrow,omitempty"`
+	// balance of the escrow account
+	Balance   []*x.Coin `protobuf:"bytes,2,rep,name=balance" json:"balance,omitempty"`
+	Sender    *Party    `protobuf:"bytes,3,opt,name=sender" json:"sender,omitempty"`
+	Arbiter   *Party    `protobuf:"bytes,4,opt,name=arbiter" json:"arbiter,omitempty"`
+	Recipient *Party    `protobuf:"bytes,5,opt,name=recipient" json:"recipient,omitempty"`
+}
+
+func (m *RichEscrow) Reset()                    { *m = RichEscrow{} }
+func (m *RichEscrow) String() string            { return proto.CompactTextString(m) }
+func (*RichEscrow) ProtoMessage()               {}
+func (*RichEscrow) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{1} }
+
+func (m *RichEscrow) GetEscrow() *escrow.Escrow {
+	if m != nil {
+		return m.Escrow
+	}
+	return nil
+}
+
+func (m *RichEscrow) GetBalance() []*x.Coin {
+	if m != nil {
+		return m.Balance
+	}
+	return nil
+}
+
+func (m *RichEscrow) GetSender() *Party {
+	if m != nil {
+		return m.Sender
+	}
+	return nil
+}
+
+func (m *RichEscrow) GetArbiter() *Party {
+	if m != nil {
+		return m.Arbiter
+	}
+	return nil
+}
+
+func (m *RichEscrow) GetRecipient() *Party {
+	if m != nil {
+		return m.Recipient
+	}
+	return nil
+}
+
+// Party is one participant of an escrow, with the name and
+// current balance of the wallet the permission belongs to
+type Party struct {
+	Address []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// empty if no name is registered
+	Name  string    `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Coins []*x.Coin `protobuf:"bytes,3,rep,name=coins" json:"coins,omitempty"`
+	// set by the owner, eg. to prefill a default arbiter
+	Metadata *namecoin.WalletMetadata `protobuf:"bytes,4,opt,name=metadata" json:"metadata,omitempty"`
+}
+
+func (m *Party) Reset()                    { *m = Party{} }
+func (m *Party) String() string            { return proto.CompactTextString(m) }
+func (*Party) ProtoMessage()               {}
+func (*Party) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{2} }
+
+func (m *Party) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *Party) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Party) GetCoins() []*x.Coin {
+	if m != nil {
+		return m.Coins
+	}
+	return nil
+}
+
+func (m *Party) GetMetadata() *namecoin.WalletMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// VersionInfo describes the running app, as returned by the
+// "/version" query and in the data of ABCI Info
+type VersionInfo struct {
+	// semantic version, eg. "0.3.0"
+	Version string `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	// exact build, from git describe
+	Build string `protobuf:"bytes,2,opt,name=build,proto3" json:"build,omitempty"`
+	// schema version of every module, sorted by name
+	Modules []*ModuleVersion `protobuf:"bytes,3,rep,name=modules" json:"modules,omitempty"`
+}
+
+func (m *VersionInfo) Reset()                    { *m = VersionInfo{} }
+func (m *VersionInfo) String() string            { return proto.CompactTextString(m) }
+func (*VersionInfo) ProtoMessage()               {}
+func (*VersionInfo) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{3} }
+
+func (m *VersionInfo) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *VersionInfo) GetBuild() string {
+	if m != nil {
+		return m.Build
+	}
+	return ""
+}
+
+func (m *VersionInfo) GetModules() []*ModuleVersion {
+	if m != nil {
+		return m.Modules
+	}
+	return nil
+}
+
+// ModuleVersion is the version of the state and message
+// format of one module
+type ModuleVersion struct {
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version uint32 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+}
+
+func (m *ModuleVersion) Reset()                    { *m = ModuleVersion{} }
+func (m *ModuleVersion) String() string            { return proto.CompactTextString(m) }
+func (*ModuleVersion) ProtoMessage()               {}
+func (*ModuleVersion) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{4} }
+
+func (m *ModuleVersion) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ModuleVersion) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*Tx)(nil), "app.Tx")
+	proto.RegisterType((*RichEscrow)(nil), "app.RichEscrow")
+	proto.RegisterType((*Party)(nil), "app.Party")
+	proto.RegisterType((*VersionInfo)(nil), "app.VersionInfo")
+	proto.RegisterType((*ModuleVersion)(nil), "app.ModuleVersion")
+}
+func (m *Tx) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Tx) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Sum != nil {
+		nn1, err := m.Sum.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += nn1
+	}
+	if m.Fees != nil {
+		dAtA[i] = 0xa2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Fees.Size()))
+		n2, err := m.Fees.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if len(m.Signatures) > 0 {
+		for _, msg := range m.Signatures {
+			dAtA[i] = 0xaa
+			i++
+			dAtA[i] = 0x1
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Preimage) > 0 {
+		dAtA[i] = 0xb2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Preimage)))
+		i += copy(dAtA[i:], m.Preimage)
+	}
+	if len(m.KeySignatures) > 0 {
+		for _, msg := range m.KeySignatures {
+			dAtA[i] = 0xba
+			i++
+			dAtA[i] = 0x1
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.TravelRuleHash) > 0 {
+		dAtA[i] = 0xa2
+		i++
+		dAtA[i] = 0x6
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.TravelRuleHash)))
+		i += copy(dAtA[i:], m.TravelRuleHash)
+	}
+	return i, nil
+}
+
+func (m *Tx_SendMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.SendMsg != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.SendMsg.Size()))
+		n3, err := m.SendMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	return i, nil
+}
+func (m *Tx_NewTokenMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.NewTokenMsg != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.NewTokenMsg.Size()))
+		n4, err := m.NewTokenMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	return i, nil
+}
+func (m *Tx_SetNameMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.SetNameMsg != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.SetNameMsg.Size()))
+		n5, err := m.SetNameMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	return i, nil
+}
+func (m *Tx_CreateEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.CreateEscrowMsg != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.CreateEscrowMsg.Size()))
+		n6, err := m.CreateEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n6
+	}
+	return i, nil
+}
+func (m *Tx_ReleaseEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.ReleaseEscrowMsg != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.ReleaseEscrowMsg.Size()))
+		n7, err := m.ReleaseEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n7
+	}
+	return i, nil
+}
+func (m *Tx_ReturnEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.ReturnEscrowMsg != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.ReturnEscrowMsg.Size()))
+		n8, err := m.ReturnEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
+	return i, nil
+}
+func (m *Tx_UpdateEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.UpdateEscrowMsg != nil {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.UpdateEscrowMsg.Size()))
+		n9, err := m.UpdateEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n9
+	}
+	return i, nil
+}
+func (m *Tx_SetPriceMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.SetPriceMsg != nil {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.SetPriceMsg.Size()))
+		n10, err := m.SetPriceMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
+	}
+	return i, nil
+}
+func (m *Tx_AssignRoleMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.AssignRoleMsg != nil {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.AssignRoleMsg.Size()))
+		n11, err := m.AssignRoleMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
+	}
+	return i, nil
+}
+func (m *Tx_RevokeRoleMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.RevokeRoleMsg != nil {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.RevokeRoleMsg.Size()))
+		n12, err := m.RevokeRoleMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n12
+	}
+	return i, nil
+}
+func (m *Tx_CreateGrantMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.CreateGrantMsg != nil {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.CreateGrantMsg.Size()))
+		n13, err := m.CreateGrantMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n13
+	}
+	return i, nil
+}
+func (m *Tx_RevokeGrantMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.RevokeGrantMsg != nil {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.RevokeGrantMsg.Size()))
+		n14, err := m.RevokeGrantMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n14
+	}
+	return i, nil
+}
+func (m *Tx_CreateSessionMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.CreateSessionMsg != nil {
+		dAtA[i] = 0x6a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.CreateSessionMsg.Size()))
+		n15, err := m.CreateSessionMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n15
+	}
+	return i, nil
+}
+func (m *Tx_RevokeSessionMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.RevokeSessionMsg != nil {
+		dAtA[i] = 0x72
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.RevokeSessionMsg.Size()))
+		n16, err := m.RevokeSessionMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n16
+	}
+	return i, nil
+}
+func (m *Tx_SellNameMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.SellNameMsg != nil {
+		dAtA[i] = 0x7a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.SellNameMsg.Size()))
+		n17, err := m.SellNameMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n17
+	}
+	return i, nil
+}
+func (m *Tx_BuyNameMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.BuyNameMsg != nil {
+		dAtA[i] = 0x82
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.BuyNameMsg.Size()))
+		n18, err := m.BuyNameMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n18
+	}
+	return i, nil
+}
+func (m *Tx_CancelNameSaleMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.CancelNameSaleMsg != nil {
+		dAtA[i] = 0x8a
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.CancelNameSaleMsg.Size()))
+		n19, err := m.CancelNameSaleMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n19
+	}
+	return i, nil
+}
+func (m *Tx_UpdateMetadataMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.UpdateMetadataMsg != nil {
+		dAtA[i] = 0x92
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.UpdateMetadataMsg.Size()))
+		n20, err := m.UpdateMetadataMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n20
+	}
+	return i, nil
+}
+func (m *Tx_BidArbitrationMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.BidArbitrationMsg != nil {
+		dAtA[i] = 0x9a
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.BidArbitrationMsg.Size()))
+		n21, err := m.BidArbitrationMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n21
+	}
+	return i, nil
+}
+func (m *Tx_AssignArbiterMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.AssignArbiterMsg != nil {
+		dAtA[i] = 0xc2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.AssignArbiterMsg.Size()))
+		n22, err := m.AssignArbiterMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n22
+	}
+	return i, nil
+}
+func (m *Tx_CreateEscrowMsgV2) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.CreateEscrowMsgV2 != nil {
+		dAtA[i] = 0xca
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.CreateEscrowMsgV2.Size()))
+		n23, err := m.CreateEscrowMsgV2.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n23
+	}
+	return i, nil
+}
+func (m *Tx_AnyMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.AnyMsg != nil {
+		dAtA[i] = 0xd2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.AnyMsg.Size()))
+		n24, err := m.AnyMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n24
+	}
+	return i, nil
+}
+func (m *Tx_UpdateObserversMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.UpdateObserversMsg != nil {
+		dAtA[i] = 0xda
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.UpdateObserversMsg.Size()))
+		n25, err := m.UpdateObserversMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n25
+	}
+	return i, nil
+}
+func (m *Tx_SetFeatureMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.SetFeatureMsg != nil {
+		dAtA[i] = 0xe2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.SetFeatureMsg.Size()))
+		n26, err := m.SetFeatureMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n26
+	}
+	return i, nil
+}
+func (m *Tx_NetEscrowsMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.NetEscrowsMsg != nil {
+		dAtA[i] = 0xea
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.NetEscrowsMsg.Size()))
+		n27, err := m.NetEscrowsMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n27
+	}
+	return i, nil
+}
+func (m *Tx_SetArbiterPolicyMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.SetArbiterPolicyMsg != nil {
+		dAtA[i] = 0xf2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.SetArbiterPolicyMsg.Size()))
+		n28, err := m.SetArbiterPolicyMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n28
+	}
+	return i, nil
+}
+func (m *Tx_CreateOrderMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.CreateOrderMsg != nil {
+		dAtA[i] = 0xfa
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.CreateOrderMsg.Size()))
+		n29, err := m.CreateOrderMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n29
+	}
+	return i, nil
+}
+func (m *Tx_FillOrderMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.FillOrderMsg != nil {
+		dAtA[i] = 0x82
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.FillOrderMsg.Size()))
+		n30, err := m.FillOrderMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n30
+	}
+	return i, nil
+}
+func (m *Tx_CancelOrderMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.CancelOrderMsg != nil {
+		dAtA[i] = 0x8a
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.CancelOrderMsg.Size()))
+		n31, err := m.CancelOrderMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n31
+	}
+	return i, nil
+}
+func (m *Tx_SetConversionMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.SetConversionMsg != nil {
+		dAtA[i] = 0x92
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.SetConversionMsg.Size()))
+		n32, err := m.SetConversionMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n32
+	}
+	return i, nil
+}
+func (m *Tx_RevealMemoMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.RevealMemoMsg != nil {
+		dAtA[i] = 0x9a
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.RevealMemoMsg.Size()))
+		n33, err := m.RevealMemoMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n33
+	}
+	return i, nil
+}
+func (m *Tx_ShieldMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.ShieldMsg != nil {
+		dAtA[i] = 0xa2
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.ShieldMsg.Size()))
+		n34, err := m.ShieldMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n34
+	}
+	return i, nil
+}
+func (m *Tx_UnshieldMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.UnshieldMsg != nil {
+		dAtA[i] = 0xaa
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.UnshieldMsg.Size()))
+		n35, err := m.UnshieldMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n35
+	}
+	return i, nil
+}
+func (m *Tx_SetViewKeyMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.SetViewKeyMsg != nil {
+		dAtA[i] = 0xb2
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.SetViewKeyMsg.Size()))
+		n36, err := m.SetViewKeyMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n36
+	}
+	return i, nil
+}
+func (m *Tx_CreateConfidentialEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.CreateConfidentialEscrowMsg != nil {
+		dAtA[i] = 0xba
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.CreateConfidentialEscrowMsg.Size()))
+		n37, err := m.CreateConfidentialEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n37
+	}
+	return i, nil
+}
+func (m *Tx_ReleaseConfidentialEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.ReleaseConfidentialEscrowMsg != nil {
+		dAtA[i] = 0xc2
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.ReleaseConfidentialEscrowMsg.Size()))
+		n38, err := m.ReleaseConfidentialEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n38
+	}
+	return i, nil
+}
+func (m *Tx_ReturnConfidentialEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.ReturnConfidentialEscrowMsg != nil {
+		dAtA[i] = 0xca
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.ReturnConfidentialEscrowMsg.Size()))
+		n39, err := m.ReturnConfidentialEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n39
+	}
+	return i, nil
+}
+func (m *Tx_GrantViewMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.GrantViewMsg != nil {
+		dAtA[i] = 0xd2
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.GrantViewMsg.Size()))
+		n40, err := m.GrantViewMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n40
+	}
+	return i, nil
+}
+func (m *Tx_TapMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.TapMsg != nil {
+		dAtA[i] = 0xda
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.TapMsg.Size()))
+		n41, err := m.TapMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n41
+	}
+	return i, nil
+}
+func (m *Tx_CreateFromTemplateMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.CreateFromTemplateMsg != nil {
+		dAtA[i] = 0xe2
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.CreateFromTemplateMsg.Size()))
+		n42, err := m.CreateFromTemplateMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n42
+	}
+	return i, nil
+}
+func (m *Tx_SetTemplateMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.SetTemplateMsg != nil {
+		dAtA[i] = 0xea
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.SetTemplateMsg.Size()))
+		n43, err := m.SetTemplateMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n43
+	}
+	return i, nil
+}
+func (m *Tx_PingEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.PingEscrowMsg != nil {
+		dAtA[i] = 0xf2
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.PingEscrowMsg.Size()))
+		n44, err := m.PingEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n44
+	}
+	return i, nil
+}
+func (m *Tx_SendEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.SendEscrowMsg != nil {
+		dAtA[i] = 0xfa
+		i++
+		dAtA[i] = 0x2
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.SendEscrowMsg.Size()))
+		n45, err := m.SendEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n45
+	}
+	return i, nil
+}
+func (m *Tx_QuarantineEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.QuarantineEscrowMsg != nil {
+		dAtA[i] = 0x82
+		i++
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.QuarantineEscrowMsg.Size()))
+		n46, err := m.QuarantineEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n46
+	}
+	return i, nil
+}
+func (m *Tx_RestoreEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.RestoreEscrowMsg != nil {
+		dAtA[i] = 0x8a
+		i++
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.RestoreEscrowMsg.Size()))
+		n47, err := m.RestoreEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n47
+	}
+	return i, nil
+}
+func (m *Tx_ForceSettleEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.ForceSettleEscrowMsg != nil {
+		dAtA[i] = 0x92
+		i++
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.ForceSettleEscrowMsg.Size()))
+		n48, err := m.ForceSettleEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n48
+	}
+	return i, nil
+}
+func (m *Tx_ClawbackEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.ClawbackEscrowMsg != nil {
+		dAtA[i] = 0x9a
+		i++
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.ClawbackEscrowMsg.Size()))
+		n49, err := m.ClawbackEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n49
+	}
+	return i, nil
+}
+func (m *Tx_AttestMilestoneMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.AttestMilestoneMsg != nil {
+		dAtA[i] = 0xa2
+		i++
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.AttestMilestoneMsg.Size()))
+		n50, err := m.AttestMilestoneMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n50
+	}
+	return i, nil
+}
+func (m *Tx_OfferEscrowPartyMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.OfferEscrowPartyMsg != nil {
+		dAtA[i] = 0xaa
+		i++
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.OfferEscrowPartyMsg.Size()))
+		n51, err := m.OfferEscrowPartyMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n51
+	}
+	return i, nil
+}
+func (m *Tx_AcceptEscrowPartyMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.AcceptEscrowPartyMsg != nil {
+		dAtA[i] = 0xb2
+		i++
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.AcceptEscrowPartyMsg.Size()))
+		n52, err := m.AcceptEscrowPartyMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n52
+	}
+	return i, nil
+}
+func (m *Tx_DisputeEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.DisputeEscrowMsg != nil {
+		dAtA[i] = 0xba
+		i++
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.DisputeEscrowMsg.Size()))
+		n53, err := m.DisputeEscrowMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n53
+	}
+	return i, nil
+}
+func (m *Tx_RedriveJobMsg) MarshalTo(dAtA []byte) (int, error) {
+	i := 0
+	if m.RedriveJobMsg != nil {
+		dAtA[i] = 0xc2
+		i++
+		dAtA[i] = 0x3
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.RedriveJobMsg.Size()))
+		n54, err := m.RedriveJobMsg.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n54
+	}
+	return i, nil
+}
+func (m *RichEscrow) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RichEscrow) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Escrow != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Escrow.Size()))
+		n55, err := m.Escrow.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n55
+	}
+	if len(m.Balance) > 0 {
+		for _, msg := range m.Balance {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Sender != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Sender.Size()))
+		n56, err := m.Sender.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n56
+	}
+	if m.Arbiter != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Arbiter.Size()))
+		n57, err := m.Arbiter.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n57
+	}
+	if m.Recipient != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Recipient.Size()))
+		n58, err := m.Recipient.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n58
+	}
+	return i, nil
+}
+
+func (m *Party) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Party) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Address) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Address)))
+		i += copy(dAtA[i:], m.Address)
+	}
+	if len(m.Name) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
+	}
+	if len(m.Coins) > 0 {
+		for _, msg := range m.Coins {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Metadata != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Metadata.Size()))
+		n59, err := m.Metadata.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n59
+	}
+	return i, nil
+}
+
+func (m *VersionInfo) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *VersionInfo) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Version) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Version)))
+		i += copy(dAtA[i:], m.Version)
+	}
+	if len(m.Build) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Build)))
+		i += copy(dAtA[i:], m.Build)
+	}
+	if len(m.Modules) > 0 {
+		for _, msg := range m.Modules {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *ModuleVersion) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ModuleVersion) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Name) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
+	}
+	if m.Version != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Version))
+	}
+	return i, nil
+}
+
+func encodeVarintCodec(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *Tx) Size() (n int) {
+	var l int
+	_ = l
+	if m.Sum != nil {
+		n += m.Sum.Size()
+	}
+	if m.Fees != nil {
+		l = m.Fees.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	if len(m.Signatures) > 0 {
+		for _, e := range m.Signatures {
+			l = e.Size()
+			n += 2 + l + sovCodec(uint64(l))
+		}
+	}
+	l = len(m.Preimage)
+	if l > 0 {
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	if len(m.KeySignatures) > 0 {
+		for _, e := range m.KeySignatures {
+			l = e.Size()
+			n += 2 + l + sovCodec(uint64(l))
+		}
+	}
+	l = len(m.TravelRuleHash)
+	if l > 0 {
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *Tx_SendMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.SendMsg != nil {
+		l = m.SendMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_NewTokenMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.NewTokenMsg != nil {
+		l = m.NewTokenMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_SetNameMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.SetNameMsg != nil {
+		l = m.SetNameMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_CreateEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.CreateEscrowMsg != nil {
+		l = m.CreateEscrowMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_ReleaseEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.ReleaseEscrowMsg != nil {
+		l = m.ReleaseEscrowMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_ReturnEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.ReturnEscrowMsg != nil {
+		l = m.ReturnEscrowMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_UpdateEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.UpdateEscrowMsg != nil {
+		l = m.UpdateEscrowMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_SetPriceMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.SetPriceMsg != nil {
+		l = m.SetPriceMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_AssignRoleMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.AssignRoleMsg != nil {
+		l = m.AssignRoleMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_RevokeRoleMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.RevokeRoleMsg != nil {
+		l = m.RevokeRoleMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_CreateGrantMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.CreateGrantMsg != nil {
+		l = m.CreateGrantMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_RevokeGrantMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.RevokeGrantMsg != nil {
+		l = m.RevokeGrantMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_CreateSessionMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.CreateSessionMsg != nil {
+		l = m.CreateSessionMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_RevokeSessionMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.RevokeSessionMsg != nil {
+		l = m.RevokeSessionMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_SellNameMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.SellNameMsg != nil {
+		l = m.SellNameMsg.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_BuyNameMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.BuyNameMsg != nil {
+		l = m.BuyNameMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_CancelNameSaleMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.CancelNameSaleMsg != nil {
+		l = m.CancelNameSaleMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_UpdateMetadataMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.UpdateMetadataMsg != nil {
+		l = m.UpdateMetadataMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_BidArbitrationMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.BidArbitrationMsg != nil {
+		l = m.BidArbitrationMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_AssignArbiterMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.AssignArbiterMsg != nil {
+		l = m.AssignArbiterMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_CreateEscrowMsgV2) Size() (n int) {
+	var l int
+	_ = l
+	if m.CreateEscrowMsgV2 != nil {
+		l = m.CreateEscrowMsgV2.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_AnyMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.AnyMsg != nil {
+		l = m.AnyMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_UpdateObserversMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.UpdateObserversMsg != nil {
+		l = m.UpdateObserversMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_SetFeatureMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.SetFeatureMsg != nil {
+		l = m.SetFeatureMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_NetEscrowsMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.NetEscrowsMsg != nil {
+		l = m.NetEscrowsMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_SetArbiterPolicyMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.SetArbiterPolicyMsg != nil {
+		l = m.SetArbiterPolicyMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_CreateOrderMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.CreateOrderMsg != nil {
+		l = m.CreateOrderMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_FillOrderMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.FillOrderMsg != nil {
+		l = m.FillOrderMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_CancelOrderMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.CancelOrderMsg != nil {
+		l = m.CancelOrderMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_SetConversionMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.SetConversionMsg != nil {
+		l = m.SetConversionMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_RevealMemoMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.RevealMemoMsg != nil {
+		l = m.RevealMemoMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_ShieldMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.ShieldMsg != nil {
+		l = m.ShieldMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_UnshieldMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.UnshieldMsg != nil {
+		l = m.UnshieldMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_SetViewKeyMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.SetViewKeyMsg != nil {
+		l = m.SetViewKeyMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_CreateConfidentialEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.CreateConfidentialEscrowMsg != nil {
+		l = m.CreateConfidentialEscrowMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_ReleaseConfidentialEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.ReleaseConfidentialEscrowMsg != nil {
+		l = m.ReleaseConfidentialEscrowMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_ReturnConfidentialEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.ReturnConfidentialEscrowMsg != nil {
+		l = m.ReturnConfidentialEscrowMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_GrantViewMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.GrantViewMsg != nil {
+		l = m.GrantViewMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_TapMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.TapMsg != nil {
+		l = m.TapMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_CreateFromTemplateMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.CreateFromTemplateMsg != nil {
+		l = m.CreateFromTemplateMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_SetTemplateMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.SetTemplateMsg != nil {
+		l = m.SetTemplateMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_PingEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.PingEscrowMsg != nil {
+		l = m.PingEscrowMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_SendEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.SendEscrowMsg != nil {
+		l = m.SendEscrowMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_QuarantineEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.QuarantineEscrowMsg != nil {
+		l = m.QuarantineEscrowMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_RestoreEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.RestoreEscrowMsg != nil {
+		l = m.RestoreEscrowMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_ForceSettleEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.ForceSettleEscrowMsg != nil {
+		l = m.ForceSettleEscrowMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_ClawbackEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.ClawbackEscrowMsg != nil {
+		l = m.ClawbackEscrowMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_AttestMilestoneMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.AttestMilestoneMsg != nil {
+		l = m.AttestMilestoneMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_OfferEscrowPartyMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.OfferEscrowPartyMsg != nil {
+		l = m.OfferEscrowPartyMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_AcceptEscrowPartyMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.AcceptEscrowPartyMsg != nil {
+		l = m.AcceptEscrowPartyMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_DisputeEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.DisputeEscrowMsg != nil {
+		l = m.DisputeEscrowMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *Tx_RedriveJobMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.RedriveJobMsg != nil {
+		l = m.RedriveJobMsg.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+func (m *RichEscrow) Size() (n int) {
+	var l int
+	_ = l
+	if m.Escrow != nil {
+		l = m.Escrow.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Balance) > 0 {
+		for _, e := range m.Balance {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	if m.Sender != nil {
+		l = m.Sender.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Arbiter != nil {
+		l = m.Arbiter.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Recipient != nil {
+		l = m.Recipient.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *Party) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Address)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Coins) > 0 {
+		for _, e := range m.Coins {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	if m.Metadata != nil {
+		l = m.Metadata.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *VersionInfo) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Version)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Build)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Modules) > 0 {
+		for _, e := range m.Modules {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ModuleVersion) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Version != 0 {
+		n += 1 + sovCodec(uint64(m.Version))
+	}
+	return n
+}
+
+func sovCodec(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozCodec(x uint64) (n int) {
+	return sovCodec(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Tx) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Tx: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Tx: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SendMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &cash.SendMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_SendMsg{v}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NewTokenMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &namecoin.NewTokenMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_NewTokenMsg{v}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SetNameMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &namecoin.SetWalletNameMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_SetNameMsg{v}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.CreateEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_CreateEscrowMsg{v}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReleaseEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.ReleaseEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_ReleaseEscrowMsg{v}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReturnEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.ReturnEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_ReturnEscrowMsg{v}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UpdateEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.UpdateEscrowPartiesMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_UpdateEscrowMsg{v}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SetPriceMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &oracle.SetPriceMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_SetPriceMsg{v}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AssignRoleMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &rbac.AssignRoleMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_AssignRoleMsg{v}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RevokeRoleMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &rbac.RevokeRoleMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_RevokeRoleMsg{v}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateGrantMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &grant.CreateGrantMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_CreateGrantMsg{v}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RevokeGrantMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &grant.RevokeGrantMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_RevokeGrantMsg{v}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateSessionMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &session.CreateSessionMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_CreateSessionMsg{v}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RevokeSessionMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &session.RevokeSessionMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_RevokeSessionMsg{v}
+			iNdEx = postIndex
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SellNameMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &namecoin.SellNameMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_SellNameMsg{v}
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BuyNameMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &namecoin.BuyNameMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_BuyNameMsg{v}
+			iNdEx = postIndex
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CancelNameSaleMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &namecoin.CancelNameSaleMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_CancelNameSaleMsg{v}
+			iNdEx = postIndex
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UpdateMetadataMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &namecoin.UpdateWalletMetadataMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_UpdateMetadataMsg{v}
+			iNdEx = postIndex
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BidArbitrationMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.BidArbitrationMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_BidArbitrationMsg{v}
+			iNdEx = postIndex
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fees", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Fees == nil {
+				m.Fees = &cash.FeeInfo{}
+			}
+			if err := m.Fees.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 21:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signatures", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signatures = append(m.Signatures, &sigs.StdSignature{})
+			if err := m.Signatures[len(m.Signatures)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 22:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Preimage", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Preimage = append(m.Preimage[:0], dAtA[iNdEx:postIndex]...)
+			if m.Preimage == nil {
+				m.Preimage = []byte{}
+			}
+			iNdEx = postIndex
+		case 23:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeySignatures", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.KeySignatures = append(m.KeySignatures, &keys.StdSignature{})
+			if err := m.KeySignatures[len(m.KeySignatures)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 24:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AssignArbiterMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.AssignArbiterMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_AssignArbiterMsg{v}
+			iNdEx = postIndex
+		case 25:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateEscrowMsgV2", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.CreateEscrowMsgV2{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_CreateEscrowMsgV2{v}
+			iNdEx = postIndex
+		case 26:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AnyMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &anymsg.Any{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_AnyMsg{v}
+			iNdEx = postIndex
+		case 27:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UpdateObserversMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.UpdateEscrowObserversMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_UpdateObserversMsg{v}
+			iNdEx = postIndex
+		case 28:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SetFeatureMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &features.SetFeatureMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_SetFeatureMsg{v}
+			iNdEx = postIndex
+		case 29:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NetEscrowsMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.NetEscrowsMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_NetEscrowsMsg{v}
+			iNdEx = postIndex
+		case 30:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SetArbiterPolicyMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.SetArbiterPolicyMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_SetArbiterPolicyMsg{v}
+			iNdEx = postIndex
+		case 31:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateOrderMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &trade.CreateOrderMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_CreateOrderMsg{v}
+			iNdEx = postIndex
+		case 32:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FillOrderMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &trade.FillOrderMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_FillOrderMsg{v}
+			iNdEx = postIndex
+		case 33:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CancelOrderMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &trade.CancelOrderMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_CancelOrderMsg{v}
+			iNdEx = postIndex
+		case 34:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SetConversionMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &feepool.SetConversionMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_SetConversionMsg{v}
+			iNdEx = postIndex
+		case 35:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RevealMemoMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.RevealMemoMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_RevealMemoMsg{v}
+			iNdEx = postIndex
+		case 36:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ShieldMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &confidential.ShieldMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_ShieldMsg{v}
+			iNdEx = postIndex
+		case 37:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UnshieldMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &confidential.UnshieldMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_UnshieldMsg{v}
+			iNdEx = postIndex
+		case 38:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SetViewKeyMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &confidential.SetViewKeyMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_SetViewKeyMsg{v}
+			iNdEx = postIndex
+		case 39:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateConfidentialEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &confidential.CreateEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_CreateConfidentialEscrowMsg{v}
+			iNdEx = postIndex
+		case 40:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReleaseConfidentialEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &confidential.ReleaseEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_ReleaseConfidentialEscrowMsg{v}
+			iNdEx = postIndex
+		case 41:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReturnConfidentialEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &confidential.ReturnEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_ReturnConfidentialEscrowMsg{v}
+			iNdEx = postIndex
+		case 42:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GrantViewMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &confidential.GrantViewMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_GrantViewMsg{v}
+			iNdEx = postIndex
+		case 43:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TapMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &faucet.TapMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_TapMsg{v}
+			iNdEx = postIndex
+		case 44:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CreateFromTemplateMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.CreateFromTemplateMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_CreateFromTemplateMsg{v}
+			iNdEx = postIndex
+		case 45:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SetTemplateMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.SetTemplateMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_SetTemplateMsg{v}
+			iNdEx = postIndex
+		case 46:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PingEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.PingEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_PingEscrowMsg{v}
+			iNdEx = postIndex
+		case 47:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SendEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.SendEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_SendEscrowMsg{v}
+			iNdEx = postIndex
+		case 48:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QuarantineEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.QuarantineEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_QuarantineEscrowMsg{v}
+			iNdEx = postIndex
+		case 49:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RestoreEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.RestoreEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_RestoreEscrowMsg{v}
+			iNdEx = postIndex
+		case 50:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ForceSettleEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.ForceSettleEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_ForceSettleEscrowMsg{v}
+			iNdEx = postIndex
+		case 51:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ClawbackEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.ClawbackEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_ClawbackEscrowMsg{v}
+			iNdEx = postIndex
+		case 52:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AttestMilestoneMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.AttestMilestoneMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_AttestMilestoneMsg{v}
+			iNdEx = postIndex
+		case 53:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OfferEscrowPartyMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.OfferEscrowPartyMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_OfferEscrowPartyMsg{v}
+			iNdEx = postIndex
+		case 54:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AcceptEscrowPartyMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.AcceptEscrowPartyMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_AcceptEscrowPartyMsg{v}
+			iNdEx = postIndex
+		case 55:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DisputeEscrowMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &escrow.DisputeEscrowMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_DisputeEscrowMsg{v}
+			iNdEx = postIndex
+		case 56:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RedriveJobMsg", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			v := &scheduler.RedriveJobMsg{}
+			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			m.Sum = &Tx_RedriveJobMsg{v}
+			iNdEx = postIndex
+		case 100:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TravelRuleHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TravelRuleHash = append(m.TravelRuleHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.TravelRuleHash == nil {
+				m.TravelRuleHash = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RichEscrow) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RichEscrow: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RichEscrow: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Escrow", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Escrow == nil {
+				m.Escrow = &escrow.Escrow{}
+			}
+			if err := m.Escrow.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Balance", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Balance = append(m.Balance, &x.Coin{})
+			if err := m.Balance[len(m.Balance)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Sender == nil {
+				m.Sender = &Party{}
+			}
+			if err := m.Sender.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Arbiter", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Arbiter == nil {
+				m.Arbiter = &Party{}
+			}
+			if err := m.Arbiter.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Recipient", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Recipient == nil {
+				m.Recipient = &Party{}
+			}
+			if err := m.Recipient.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
 	}
-	l = len(m.Preimage)
-	if l > 0 {
-		n += 2 + l + sovCodec(uint64(l))
-	}
-	return n
-}
-
-func (m *Tx_SendMsg) Size() (n int) {
-	var l int
-	_ = l
-	if m.SendMsg != nil {
-		l = m.SendMsg.Size()
-		n += 1 + l + sovCodec(uint64(l))
-	}
-	return n
-}
-func (m *Tx_NewTokenMsg) Size() (n int) {
-	var l int
-	_ = l
-	if m.NewTokenMsg != nil {
-		l = m.NewTokenMsg.Size()
-		n += 1 + l + sovCodec(uint64(l))
-	}
-	return n
-}
-func (m *Tx_SetNameMsg) Size() (n int) {
-	var l int
-	_ = l
-	if m.SetNameMsg != nil {
-		l = m.SetNameMsg.Size()
-		n += 1 + l + sovCodec(uint64(l))
-	}
-	return n
-}
-func (m *Tx_CreateEscrowMsg) Size() (n int) {
-	var l int
-	_ = l
-	if m.CreateEscrowMsg != nil {
-		l = m.CreateEscrowMsg.Size()
-		n += 1 + l + sovCodec(uint64(l))
-	}
-	return n
-}
-func (m *Tx_ReleaseEscrowMsg) Size() (n int) {
-	var l int
-	_ = l
-	if m.ReleaseEscrowMsg != nil {
-		l = m.ReleaseEscrowMsg.Size()
-		n += 1 + l + sovCodec(uint64(l))
-	}
-	return n
-}
-func (m *Tx_ReturnEscrowMsg) Size() (n int) {
-	var l int
-	_ = l
-	if m.ReturnEscrowMsg != nil {
-		l = m.ReturnEscrowMsg.Size()
-		n += 1 + l + sovCodec(uint64(l))
-	}
-	return n
-}
-func (m *Tx_UpdateEscrowMsg) Size() (n int) {
-	var l int
-	_ = l
-	if m.UpdateEscrowMsg != nil {
-		l = m.UpdateEscrowMsg.Size()
-		n += 1 + l + sovCodec(uint64(l))
-	}
-	return n
-}
 
-func sovCodec(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-func sozCodec(x uint64) (n int) {
-	return sovCodec(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *Tx) Unmarshal(dAtA []byte) error {
+func (m *Party) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -633,17 +5729,17 @@ func (m *Tx) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Tx: wiretype end group for non-group")
+			return fmt.Errorf("proto: Party: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Tx: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Party: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SendMsg", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -653,29 +5749,28 @@ func (m *Tx) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthCodec
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &cash.SendMsg{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Address = append(m.Address[:0], dAtA[iNdEx:postIndex]...)
+			if m.Address == nil {
+				m.Address = []byte{}
 			}
-			m.Sum = &Tx_SendMsg{v}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field NewTokenMsg", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -685,27 +5780,24 @@ func (m *Tx) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthCodec
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &namecoin.NewTokenMsg{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			m.Sum = &Tx_NewTokenMsg{v}
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SetNameMsg", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Coins", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -729,15 +5821,14 @@ func (m *Tx) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &namecoin.SetWalletNameMsg{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Coins = append(m.Coins, &x.Coin{})
+			if err := m.Coins[len(m.Coins)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
-			m.Sum = &Tx_SetNameMsg{v}
 			iNdEx = postIndex
 		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field CreateEscrowMsg", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -761,17 +5852,68 @@ func (m *Tx) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &escrow.CreateEscrowMsg{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Metadata == nil {
+				m.Metadata = &namecoin.WalletMetadata{}
+			}
+			if err := m.Metadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
-			m.Sum = &Tx_CreateEscrowMsg{v}
 			iNdEx = postIndex
-		case 5:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *VersionInfo) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: VersionInfo: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: VersionInfo: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ReleaseEscrowMsg", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -781,29 +5923,26 @@ func (m *Tx) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthCodec
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &escrow.ReleaseEscrowMsg{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			m.Sum = &Tx_ReleaseEscrowMsg{v}
+			m.Version = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 6:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ReturnEscrowMsg", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Build", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -813,27 +5952,24 @@ func (m *Tx) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthCodec
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &escrow.ReturnEscrowMsg{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			m.Sum = &Tx_ReturnEscrowMsg{v}
+			m.Build = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 7:
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field UpdateEscrowMsg", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Modules", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -857,50 +5993,66 @@ func (m *Tx) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			v := &escrow.UpdateEscrowPartiesMsg{}
-			if err := v.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Modules = append(m.Modules, &ModuleVersion{})
+			if err := m.Modules[len(m.Modules)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
-			m.Sum = &Tx_UpdateEscrowMsg{v}
 			iNdEx = postIndex
-		case 20:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Fees", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowCodec
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			if msglen < 0 {
+			if skippy < 0 {
 				return ErrInvalidLengthCodec
 			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Fees == nil {
-				m.Fees = &cash.FeeInfo{}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ModuleVersion) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
 			}
-			if err := m.Fees.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
 			}
-			iNdEx = postIndex
-		case 21:
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ModuleVersion: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ModuleVersion: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Signatures", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -910,28 +6062,26 @@ func (m *Tx) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthCodec
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Signatures = append(m.Signatures, &sigs.StdSignature{})
-			if err := m.Signatures[len(m.Signatures)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 22:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Preimage", wireType)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Version", wireType)
 			}
-			var byteLen int
+			m.Version = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -941,23 +6091,11 @@ func (m *Tx) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				m.Version |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthCodec
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Preimage = append(m.Preimage[:0], dAtA[iNdEx:postIndex]...)
-			if m.Preimage == nil {
-				m.Preimage = []byte{}
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipCodec(dAtA[iNdEx:])
@@ -1087,33 +6225,122 @@ var (
 func init() { proto.RegisterFile("app/codec.proto", fileDescriptorCodec) }
 
 var fileDescriptorCodec = []byte{
-	// 443 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0xcf, 0x6a, 0xdb, 0x40,
-	0x10, 0x87, 0xa3, 0xd8, 0xf9, 0xc3, 0x3a, 0x21, 0xc9, 0xd2, 0xb4, 0xc2, 0x07, 0xe1, 0xf6, 0x64,
-	0x02, 0x59, 0x15, 0xf7, 0x58, 0xe8, 0x21, 0x25, 0x25, 0x85, 0x36, 0x14, 0x39, 0xa5, 0x47, 0xb3,
-	0x5e, 0x8d, 0x15, 0x51, 0x6b, 0x77, 0xd9, 0x59, 0xc5, 0x7e, 0x8c, 0x3e, 0x56, 0x2f, 0x85, 0x3e,
-	0x42, 0x71, 0x5f, 0xa4, 0x68, 0xb7, 0x4a, 0x56, 0x39, 0xe4, 0xa6, 0x99, 0xf9, 0x7d, 0x1f, 0xb3,
-	0x68, 0xc8, 0x11, 0xd7, 0x3a, 0x15, 0x2a, 0x07, 0xc1, 0xb4, 0x51, 0x56, 0xd1, 0x1e, 0xd7, 0x7a,
-	0x78, 0x56, 0x94, 0xf6, 0xb6, 0x9e, 0x33, 0xa1, 0xaa, 0x54, 0x28, 0xb9, 0x28, 0x55, 0xba, 0x02,
-	0x7e, 0x07, 0xe9, 0x3a, 0x15, 0x1c, 0x6f, 0x43, 0xe0, 0xa9, 0x2c, 0x96, 0x05, 0x76, 0xb2, 0x93,
-	0x20, 0x5b, 0xaa, 0xbb, 0x73, 0x25, 0x21, 0x9d, 0x0b, 0x7d, 0x9e, 0x43, 0xa5, 0xd2, 0x75, 0x2a,
-	0x79, 0x05, 0x42, 0x95, 0xb2, 0xc3, 0xbc, 0x7e, 0x9a, 0x01, 0x14, 0x46, 0xad, 0x42, 0xe2, 0xd5,
-	0xaf, 0x3e, 0xd9, 0xbe, 0x59, 0xd3, 0x33, 0xb2, 0x8f, 0x20, 0xf3, 0x59, 0x85, 0x45, 0x1c, 0x8d,
-	0xa2, 0xf1, 0x60, 0x72, 0xc8, 0x9a, 0xed, 0xd9, 0x14, 0x64, 0xfe, 0x19, 0x8b, 0xab, 0xad, 0x6c,
-	0x0f, 0xfd, 0x27, 0x7d, 0x4b, 0x0e, 0x25, 0xac, 0x66, 0x56, 0x7d, 0x07, 0xe9, 0x80, 0x6d, 0x07,
-	0x9c, 0xb2, 0x76, 0x25, 0x76, 0x0d, 0xab, 0x9b, 0x66, 0xea, 0xc1, 0x81, 0x7c, 0x28, 0xe9, 0x3b,
-	0x72, 0x80, 0x60, 0x67, 0x4d, 0xd4, 0xb1, 0x3d, 0xc7, 0x0e, 0x1f, 0xd8, 0x29, 0xd8, 0x6f, 0x7c,
-	0xb9, 0x04, 0x7b, 0xcd, 0x2b, 0xf0, 0x02, 0x82, 0xf7, 0x15, 0xbd, 0x24, 0x27, 0xc2, 0x00, 0xb7,
-	0x30, 0xf3, 0x8f, 0x71, 0x92, 0xbe, 0x93, 0xbc, 0x60, 0xbe, 0xc5, 0xde, 0xbb, 0xc0, 0xa5, 0x2b,
-	0xbc, 0xe1, 0x48, 0x74, 0x5b, 0xf4, 0x8a, 0x50, 0x03, 0x4b, 0xe0, 0xd8, 0xf1, 0xec, 0x38, 0x4f,
-	0xdc, 0x7a, 0x32, 0x9f, 0x08, 0x45, 0xc7, 0xe6, 0x51, 0xaf, 0x59, 0xc8, 0x80, 0xad, 0x8d, 0x0c,
-	0x45, 0xbb, 0xdd, 0x85, 0x32, 0x17, 0xe8, 0x2c, 0x64, 0xba, 0x2d, 0xfa, 0x89, 0x9c, 0xd4, 0x3a,
-	0x7f, 0xf4, 0xae, 0x3d, 0xa7, 0x49, 0x5a, 0xcd, 0x57, 0x17, 0xf0, 0xcc, 0x17, 0x6e, 0x6c, 0x09,
-	0xf8, 0xdf, 0x56, 0x07, 0x93, 0xc6, 0xf6, 0x92, 0xf4, 0x17, 0x00, 0x18, 0x3f, 0x0b, 0x7f, 0xe5,
-	0x07, 0x80, 0x8f, 0x72, 0xa1, 0x32, 0x37, 0xa2, 0x13, 0x42, 0xb0, 0x2c, 0x24, 0xb7, 0xb5, 0x01,
-	0x8c, 0x4f, 0x47, 0xbd, 0xf1, 0x60, 0x42, 0x59, 0x73, 0x85, 0x6c, 0x6a, 0xf3, 0x69, 0x3b, 0xca,
-	0x82, 0x14, 0x1d, 0x92, 0x7d, 0x6d, 0xa0, 0xac, 0x78, 0x01, 0xf1, 0xf3, 0x51, 0x34, 0x3e, 0xc8,
-	0xee, 0xeb, 0x8b, 0x1d, 0xd2, 0xc3, 0xba, 0xba, 0x38, 0xfe, 0xb9, 0x49, 0xa2, 0xdf, 0x9b, 0x24,
-	0xfa, 0xb3, 0x49, 0xa2, 0x1f, 0x7f, 0x93, 0xad, 0xf9, 0xae, 0x3b, 0xb4, 0x37, 0xff, 0x02, 0x00,
-	0x00, 0xff, 0xff, 0x40, 0x1d, 0xfd, 0x47, 0x3e, 0x03, 0x00, 0x00,
+	// 1857 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x58, 0xe1, 0x72, 0x1b, 0xb7,
+	0x11, 0x36, 0x2d, 0x4b, 0xb4, 0x20, 0x91, 0x92, 0x20, 0xd9, 0x66, 0x64, 0x9b, 0x91, 0xd5, 0xc4,
+	0x55, 0xdc, 0xf8, 0x98, 0xc8, 0x69, 0xea, 0x4c, 0x26, 0xed, 0x48, 0x6a, 0x54, 0xa7, 0x89, 0x6c,
+	0xe7, 0x28, 0xbb, 0xfd, 0xc7, 0x01, 0xef, 0x96, 0xd4, 0x55, 0xc7, 0xc3, 0x05, 0x00, 0x29, 0xf3,
+	0x15, 0xfa, 0xab, 0x8f, 0xd5, 0x99, 0xfe, 0xe9, 0x23, 0x74, 0xdc, 0xe7, 0xe8, 0x4c, 0x07, 0xc0,
+	0x1e, 0x0f, 0x38, 0x2a, 0x6a, 0xf5, 0x8f, 0xf8, 0xb0, 0xdf, 0x87, 0xc5, 0x2e, 0xb0, 0xd8, 0x23,
+	0x59, 0x63, 0x79, 0xde, 0x89, 0x78, 0x0c, 0x51, 0x90, 0x0b, 0xae, 0x38, 0x5d, 0x60, 0x79, 0xbe,
+	0xfd, 0xf1, 0x30, 0x51, 0x67, 0xe3, 0x7e, 0x10, 0xf1, 0x51, 0x27, 0xe2, 0xd9, 0x20, 0xe1, 0x9d,
+	0x0b, 0x60, 0x13, 0xe8, 0xbc, 0x73, 0x6d, 0xb7, 0x9f, 0x5c, 0x61, 0xc6, 0xe4, 0xd9, 0xff, 0x6b,
+	0x2b, 0x93, 0xa1, 0xf4, 0x6c, 0xf7, 0x1d, 0xdb, 0x84, 0x4f, 0x9e, 0xf2, 0x0c, 0x3a, 0xfd, 0x28,
+	0x7f, 0x1a, 0xc3, 0x88, 0x77, 0xde, 0x75, 0x32, 0x36, 0x82, 0x88, 0x27, 0x99, 0xc7, 0xf9, 0xec,
+	0x6a, 0x0e, 0xc8, 0x48, 0xf0, 0x8b, 0xeb, 0x30, 0xb8, 0x60, 0x51, 0x0a, 0x1e, 0x23, 0xb8, 0x9a,
+	0x21, 0xfa, 0x2c, 0xf2, 0xec, 0x3b, 0x57, 0xdb, 0x0f, 0x05, 0xcb, 0x94, 0x47, 0xf8, 0xfc, 0x6a,
+	0x82, 0x04, 0x29, 0x13, 0x9e, 0x5d, 0xc7, 0xa7, 0x73, 0x98, 0xca, 0xeb, 0xec, 0x9a, 0x65, 0xd3,
+	0x91, 0x1c, 0x5e, 0x27, 0x1b, 0x03, 0x60, 0x6a, 0x2c, 0x40, 0x5e, 0x67, 0xe7, 0x4a, 0xb0, 0x18,
+	0xae, 0xb3, 0xf3, 0x01, 0x40, 0xce, 0x79, 0xea, 0x51, 0xbe, 0xbc, 0x9a, 0x62, 0x0e, 0x59, 0x0c,
+	0x99, 0x4a, 0x58, 0x7a, 0x9d, 0x08, 0x0c, 0xd8, 0x38, 0x02, 0x3f, 0x2d, 0xcf, 0xfe, 0x47, 0x5a,
+	0xa2, 0x33, 0x88, 0xc7, 0x29, 0x08, 0x97, 0xb4, 0xfb, 0x9f, 0x36, 0xb9, 0x79, 0xfa, 0x8e, 0x3e,
+	0x21, 0xb7, 0x25, 0x64, 0x71, 0x6f, 0x24, 0x87, 0xad, 0xda, 0x4e, 0x6d, 0x6f, 0x65, 0xbf, 0x11,
+	0xe8, 0xcb, 0x11, 0x74, 0x21, 0x8b, 0x4f, 0xe4, 0xf0, 0xc5, 0x8d, 0xb0, 0x2e, 0xed, 0x4f, 0xfa,
+	0x35, 0x69, 0x64, 0x70, 0xd1, 0x53, 0xfc, 0x1c, 0x32, 0x43, 0xb8, 0x69, 0x08, 0x77, 0x82, 0xe2,
+	0xc4, 0x07, 0x2f, 0xe1, 0xe2, 0x54, 0xcf, 0x5a, 0xe2, 0x4a, 0x56, 0x0e, 0xe9, 0x6f, 0xc9, 0xaa,
+	0x04, 0xd5, 0xd3, 0xa6, 0x86, 0xbb, 0x60, 0xb8, 0xdb, 0x25, 0xb7, 0x0b, 0xea, 0x4f, 0x2c, 0x4d,
+	0x41, 0xbd, 0x64, 0x23, 0xb0, 0x02, 0x44, 0xce, 0x46, 0xf4, 0x5b, 0xb2, 0x11, 0x09, 0x60, 0x0a,
+	0x7a, 0xf6, 0xae, 0x18, 0x91, 0x5b, 0x46, 0xe4, 0x5e, 0x60, 0xa1, 0xe0, 0xc8, 0x18, 0x7c, 0x6b,
+	0x06, 0x56, 0x61, 0x2d, 0xf2, 0x21, 0xfa, 0x82, 0x50, 0x01, 0x29, 0x30, 0xe9, 0xe9, 0x2c, 0x1a,
+	0x9d, 0x56, 0xa1, 0x13, 0x5a, 0x0b, 0x57, 0x68, 0x5d, 0x54, 0x30, 0xed, 0x90, 0x00, 0x35, 0x16,
+	0x99, 0x2b, 0xb4, 0xe4, 0x3b, 0x14, 0x1a, 0x03, 0xcf, 0x21, 0xe1, 0x43, 0xf4, 0x07, 0xb2, 0x31,
+	0xce, 0xe3, 0xca, 0xbe, 0xea, 0x46, 0xa6, 0x5d, 0xc8, 0xbc, 0x31, 0x06, 0x96, 0xf3, 0x9a, 0x09,
+	0x95, 0x80, 0x44, 0xb5, 0xb1, 0x33, 0xa3, 0xd5, 0xbe, 0x22, 0x0d, 0x1d, 0xe5, 0x5c, 0x24, 0x91,
+	0x0d, 0xf3, 0x6d, 0xa3, 0xb4, 0x19, 0xd8, 0x72, 0xa1, 0x83, 0xfc, 0x5a, 0xcf, 0x61, 0x82, 0x64,
+	0x39, 0xa4, 0xdf, 0x90, 0x35, 0x26, 0x65, 0x32, 0xcc, 0x7a, 0x82, 0xa7, 0x96, 0xbc, 0x8c, 0x64,
+	0x5d, 0x39, 0x82, 0x03, 0x33, 0x19, 0xf2, 0x14, 0xc9, 0x0d, 0xe6, 0x02, 0x9a, 0x2e, 0x60, 0xc2,
+	0xcf, 0xa1, 0xa4, 0x13, 0x97, 0x1e, 0x9a, 0x49, 0x87, 0x2e, 0x5c, 0x80, 0x1e, 0x90, 0x75, 0x4c,
+	0xaf, 0x29, 0x3b, 0x86, 0xbf, 0x82, 0xc7, 0xcb, 0x20, 0x98, 0xdc, 0x3f, 0xe8, 0xdf, 0x56, 0xa1,
+	0x19, 0x79, 0x88, 0x96, 0x40, 0x0f, 0x4a, 0x89, 0x55, 0x4f, 0xc2, 0xfa, 0xe0, 0x4a, 0x08, 0x0f,
+	0xa1, 0xdf, 0x11, 0x8a, 0x5e, 0x60, 0x2d, 0x33, 0x22, 0x0d, 0x23, 0xf2, 0x41, 0x80, 0x18, 0x7a,
+	0xd2, 0xb5, 0x23, 0x3c, 0x1e, 0x51, 0x05, 0xd3, 0x52, 0xe8, 0x8d, 0x2b, 0xd5, 0xac, 0x48, 0x59,
+	0x8f, 0x7c, 0x29, 0x51, 0xc1, 0xf4, 0xbd, 0x93, 0x90, 0xa6, 0xe5, 0xdd, 0x59, 0xab, 0xde, 0xbb,
+	0x2e, 0xa4, 0x69, 0x79, 0x6d, 0x56, 0x64, 0x39, 0xa4, 0xcf, 0xc9, 0x6a, 0x7f, 0x3c, 0x2d, 0xb9,
+	0xeb, 0x86, 0xbb, 0x55, 0x72, 0x0f, 0xc7, 0x53, 0xe7, 0xc6, 0xf5, 0x67, 0x23, 0xfa, 0x92, 0x6c,
+	0x45, 0x2c, 0x8b, 0x00, 0x17, 0x96, 0x0c, 0xd3, 0xba, 0x61, 0x14, 0xee, 0x97, 0x0a, 0x47, 0xc6,
+	0x4a, 0xd3, 0xba, 0xac, 0x48, 0xef, 0x46, 0x54, 0x05, 0x69, 0x97, 0x6c, 0xe2, 0x49, 0x1f, 0x81,
+	0x62, 0x31, 0x53, 0xcc, 0xc8, 0x51, 0x23, 0xf7, 0xa8, 0x94, 0xb3, 0xa7, 0xdd, 0xd6, 0x82, 0x13,
+	0xb4, 0x44, 0x51, 0xcb, 0x77, 0x40, 0xfa, 0x3d, 0xd9, 0xec, 0x27, 0x71, 0x8f, 0x89, 0x7e, 0xa2,
+	0x04, 0x53, 0x45, 0x9c, 0x37, 0x31, 0xce, 0x78, 0x81, 0x0e, 0x93, 0xf8, 0xa0, 0xb4, 0x40, 0xb1,
+	0x7e, 0x15, 0xd4, 0xc5, 0x01, 0xaf, 0x80, 0xd1, 0x03, 0x61, 0xb4, 0x5a, 0x7e, 0x71, 0xb0, 0xf7,
+	0xe0, 0xc0, 0x1a, 0x60, 0xca, 0x58, 0x05, 0xa3, 0x3f, 0x90, 0xad, 0xb9, 0x6a, 0xd5, 0x9b, 0xec,
+	0xb7, 0x3e, 0xf0, 0xfd, 0xaa, 0x14, 0xac, 0xb7, 0xfb, 0x26, 0x72, 0x55, 0x90, 0x3e, 0x26, 0x75,
+	0x96, 0x4d, 0x8d, 0x33, 0xdb, 0x46, 0x60, 0x25, 0xb0, 0x0f, 0x61, 0x70, 0x90, 0x4d, 0x5f, 0xdc,
+	0x08, 0x97, 0x58, 0x36, 0xd5, 0xab, 0x9e, 0x92, 0x2d, 0x8c, 0x30, 0xef, 0x4b, 0x10, 0x13, 0x10,
+	0xd2, 0x90, 0xee, 0x1b, 0xd2, 0xce, 0x65, 0xe5, 0xe4, 0x55, 0x61, 0x68, 0x77, 0x42, 0x2d, 0xdf,
+	0x45, 0xe9, 0x01, 0x59, 0xd3, 0x35, 0x05, 0x1f, 0x52, 0x23, 0xf8, 0x00, 0xcb, 0x1c, 0x62, 0x52,
+	0xd7, 0x95, 0x63, 0xfb, 0x1b, 0x6f, 0xb7, 0x74, 0x01, 0xfa, 0x3b, 0xb2, 0x96, 0x81, 0xc2, 0x58,
+	0x58, 0x9f, 0x1e, 0xe2, 0x19, 0x46, 0x9f, 0x5e, 0x82, 0xb2, 0x0e, 0xa1, 0x23, 0x8d, 0xcc, 0x05,
+	0x68, 0x48, 0xee, 0x6a, 0x1f, 0x8a, 0xb4, 0xe4, 0x3c, 0x4d, 0x22, 0x1b, 0x90, 0x36, 0x9e, 0x46,
+	0xd4, 0xe9, 0x82, 0xc2, 0x34, 0xbc, 0x36, 0x36, 0x56, 0x6d, 0x53, 0xce, 0xc3, 0x4e, 0xc9, 0xe1,
+	0x22, 0xc6, 0x5c, 0x7f, 0x88, 0x5e, 0x99, 0x0e, 0x00, 0xd3, 0xf3, 0x4a, 0xcf, 0x7a, 0x25, 0xa7,
+	0x40, 0xe8, 0xd7, 0xa4, 0x39, 0x48, 0xd2, 0xd4, 0x11, 0xd8, 0xc1, 0x9a, 0x67, 0x05, 0x8e, 0x93,
+	0x34, 0x75, 0xe8, 0xab, 0x03, 0x67, 0x6c, 0xd6, 0xb7, 0xf7, 0xab, 0xa4, 0x3f, 0xf2, 0xd7, 0x37,
+	0xd3, 0xde, 0xfa, 0x1e, 0xa2, 0x8b, 0x8c, 0x0e, 0x4b, 0xc4, 0x33, 0x9d, 0xac, 0xe2, 0xf0, 0xef,
+	0xe2, 0x21, 0xc3, 0xae, 0x44, 0xc7, 0xe4, 0x68, 0x66, 0x81, 0x27, 0x56, 0x56, 0x30, 0x9d, 0x22,
+	0x01, 0x13, 0x60, 0x69, 0x6f, 0x04, 0x23, 0x6e, 0x74, 0x7e, 0xe1, 0xa7, 0x28, 0x34, 0xd3, 0x27,
+	0x30, 0xe2, 0x65, 0x05, 0x2f, 0x01, 0xfa, 0x9c, 0x10, 0x79, 0x96, 0x40, 0x6a, 0x7b, 0x89, 0x8f,
+	0xf0, 0x84, 0xb8, 0x6d, 0x4e, 0xd0, 0x35, 0xf3, 0x96, 0xbd, 0x2c, 0x8b, 0x81, 0x6e, 0x0d, 0xc6,
+	0x99, 0xc3, 0xfd, 0x18, 0xfd, 0xf7, 0xb8, 0x6f, 0x32, 0xe9, 0xb0, 0x57, 0xc6, 0xe5, 0x90, 0x1e,
+	0x13, 0xbd, 0x9d, 0xde, 0x24, 0x81, 0x8b, 0xde, 0x39, 0xd8, 0x63, 0xf1, 0x18, 0x8f, 0x85, 0xbf,
+	0x3e, 0xa8, 0xb7, 0x09, 0x5c, 0x7c, 0x0f, 0xd3, 0xf2, 0x94, 0x96, 0x00, 0x8d, 0x49, 0x1b, 0x0f,
+	0x84, 0xcb, 0x72, 0xdf, 0xe5, 0x5f, 0x1a, 0xd5, 0x87, 0xbe, 0xea, 0x7c, 0xd7, 0x71, 0xdf, 0xca,
+	0x1c, 0x39, 0x56, 0xb3, 0x69, 0x3a, 0x24, 0x1f, 0x16, 0x1d, 0xc8, 0xcf, 0x2d, 0xb3, 0x87, 0xcf,
+	0xbf, 0xb7, 0xcc, 0x25, 0x4d, 0xc9, 0x03, 0x14, 0xba, 0x7c, 0xa1, 0x98, 0xb4, 0xb1, 0x41, 0xf9,
+	0xb9, 0x75, 0x3e, 0xb9, 0x6c, 0x3b, 0xf3, 0x3d, 0xcb, 0x7d, 0x2b, 0x73, 0xf9, 0x2a, 0x87, 0xa4,
+	0x69, 0x9f, 0x5b, 0x13, 0x7e, 0xad, 0xfa, 0x04, 0x3b, 0x3b, 0x4f, 0xd5, 0x3c, 0xb1, 0x3a, 0xd6,
+	0x78, 0x13, 0x86, 0xce, 0x98, 0x7e, 0x42, 0xea, 0x8a, 0xe5, 0x86, 0xfc, 0x2b, 0x43, 0x6e, 0x06,
+	0xb6, 0xcd, 0x0d, 0x4e, 0x59, 0x6e, 0x09, 0x4b, 0xca, 0xfc, 0xa2, 0x7f, 0x26, 0x2d, 0xcc, 0xd1,
+	0x40, 0xf0, 0x51, 0x4f, 0xc1, 0x28, 0x4f, 0xf5, 0x48, 0x73, 0x3f, 0xc5, 0xed, 0x78, 0xc5, 0xf5,
+	0x58, 0xf0, 0xd1, 0x29, 0x5a, 0x59, 0xa9, 0x3b, 0xd1, 0x65, 0x13, 0xf4, 0xd0, 0x9e, 0x22, 0x4f,
+	0xf1, 0xa9, 0x51, 0xbc, 0xeb, 0x14, 0x17, 0x5f, 0xaa, 0x29, 0x3d, 0x44, 0x5f, 0xa2, 0x3c, 0xc9,
+	0x86, 0x6e, 0x8c, 0x03, 0xff, 0x12, 0xbd, 0x4e, 0xb2, 0xa1, 0x1b, 0xdb, 0x46, 0xee, 0x02, 0x5a,
+	0xc0, 0xb4, 0xe3, 0x8e, 0x40, 0xc7, 0x17, 0xd0, 0x7d, 0xb9, 0x27, 0x20, 0x5d, 0x80, 0xfe, 0x48,
+	0xee, 0xfc, 0x34, 0x66, 0x3a, 0xb8, 0x49, 0xe6, 0xb5, 0x94, 0x9f, 0xf9, 0x75, 0xf2, 0xc7, 0x99,
+	0x91, 0x2b, 0xb6, 0xf9, 0xd3, 0x3c, 0x6c, 0x5b, 0x66, 0xa9, 0xb8, 0xf0, 0xf4, 0x3e, 0xaf, 0xb6,
+	0xcc, 0xc6, 0xa2, 0xd2, 0x32, 0xfb, 0x18, 0x7d, 0x43, 0xee, 0x0d, 0xb8, 0x88, 0xa0, 0x27, 0x41,
+	0xa9, 0xd4, 0x93, 0xdb, 0x37, 0x72, 0x0f, 0x0a, 0xb9, 0x63, 0x6d, 0xd6, 0x35, 0x56, 0xae, 0xe4,
+	0xd6, 0xe0, 0x12, 0x5c, 0xf7, 0x00, 0x51, 0xca, 0x2e, 0xfa, 0x2c, 0x3a, 0x77, 0x25, 0x9f, 0x55,
+	0xde, 0x5a, 0x34, 0x71, 0xf5, 0x36, 0xa2, 0x2a, 0xa8, 0xbb, 0x1e, 0xa6, 0x14, 0x48, 0xd5, 0x1b,
+	0x25, 0xa9, 0xde, 0x40, 0x66, 0x8f, 0xc2, 0x17, 0x78, 0xaa, 0x8b, 0x2e, 0xc0, 0xd8, 0x9c, 0x14,
+	0x26, 0xf8, 0x7a, 0xb2, 0x39, 0x54, 0xbf, 0x5c, 0x7c, 0x30, 0x00, 0x51, 0x78, 0x96, 0x33, 0xa1,
+	0x6c, 0x89, 0xfa, 0xb5, 0x9f, 0x91, 0x57, 0xda, 0xaa, 0xec, 0xf1, 0x8b, 0x97, 0x8b, 0xcf, 0xc3,
+	0x3a, 0x8e, 0x2c, 0x8a, 0x20, 0x57, 0xf3, 0xa2, 0x5f, 0xfa, 0x71, 0x3c, 0x30, 0x66, 0x73, 0xaa,
+	0x5b, 0xec, 0x12, 0x5c, 0x27, 0x3a, 0x4e, 0x64, 0x3e, 0xf6, 0xbf, 0x45, 0x7e, 0xe3, 0x27, 0xfa,
+	0xf7, 0xd6, 0xc2, 0x4b, 0x74, 0x5c, 0xc1, 0xe8, 0xa1, 0x7e, 0x4c, 0x62, 0x91, 0x4c, 0xa0, 0xf7,
+	0x17, 0xde, 0x37, 0x32, 0xcf, 0x51, 0x66, 0xf6, 0x35, 0x1a, 0x84, 0xd6, 0xe2, 0x8f, 0xbc, 0x3f,
+	0x7b, 0x4f, 0x1c, 0x80, 0x3e, 0x22, 0xb7, 0x06, 0x00, 0xb2, 0xb5, 0xe5, 0x7e, 0x95, 0x1e, 0x03,
+	0x7c, 0x97, 0x0d, 0x78, 0x68, 0xa6, 0xe8, 0x3e, 0x21, 0xba, 0xef, 0xb2, 0x3d, 0x48, 0xeb, 0xce,
+	0xce, 0xc2, 0xde, 0xca, 0x3e, 0x0d, 0x64, 0x32, 0x94, 0x41, 0x57, 0xc5, 0xdd, 0x62, 0x2a, 0x74,
+	0xac, 0xe8, 0x36, 0xb9, 0x9d, 0x0b, 0x48, 0x46, 0x6c, 0x08, 0xad, 0xbb, 0x3b, 0xb5, 0xbd, 0xd5,
+	0x70, 0x36, 0xa6, 0x5f, 0x91, 0xa6, 0x7e, 0x3f, 0x1c, 0xcd, 0x7b, 0xa8, 0xa9, 0xff, 0xa7, 0xf0,
+	0x35, 0x1b, 0xe7, 0x30, 0xed, 0x96, 0xb2, 0x7b, 0x64, 0x5d, 0x09, 0x36, 0x81, 0xb4, 0x27, 0xc6,
+	0x29, 0xf4, 0xce, 0x98, 0x3c, 0x6b, 0xc5, 0x46, 0xbe, 0x69, 0xf1, 0x70, 0x9c, 0xc2, 0x0b, 0x26,
+	0xcf, 0x0e, 0x17, 0xc9, 0x82, 0x1c, 0x8f, 0x76, 0xff, 0x51, 0x23, 0x24, 0x4c, 0xa2, 0x33, 0x1b,
+	0x34, 0xfa, 0x98, 0x2c, 0xd9, 0x00, 0xe3, 0x57, 0x78, 0xb3, 0x88, 0xb7, 0x9d, 0x0f, 0x71, 0x96,
+	0x3e, 0x22, 0xf5, 0x3e, 0x4b, 0x75, 0x17, 0xd0, 0xba, 0x69, 0x7c, 0xab, 0x07, 0xef, 0x82, 0x23,
+	0x9e, 0x64, 0x61, 0x81, 0xd3, 0x5d, 0xb2, 0xa4, 0x6b, 0x02, 0x08, 0xfc, 0xc6, 0x26, 0x01, 0xcb,
+	0xf3, 0xc0, 0x64, 0x39, 0xc4, 0x19, 0xfa, 0x11, 0xa9, 0x63, 0x2f, 0xd5, 0xba, 0x35, 0x67, 0x54,
+	0x4c, 0xd1, 0x3d, 0xb2, 0x2c, 0x20, 0x4a, 0xf2, 0x04, 0x32, 0xd5, 0x5a, 0x9c, 0xb3, 0x2b, 0x27,
+	0x77, 0xff, 0x5a, 0x23, 0x8b, 0x06, 0xa4, 0x2d, 0x52, 0x67, 0x71, 0x2c, 0x40, 0x4a, 0xb3, 0x93,
+	0xd5, 0xb0, 0x18, 0x52, 0x4a, 0x6e, 0xe9, 0x1e, 0xdf, 0xfc, 0x6b, 0xb0, 0x1c, 0x9a, 0xdf, 0xf4,
+	0x21, 0x59, 0xd4, 0x3d, 0xbf, 0x6c, 0x2d, 0xf8, 0x9b, 0xb1, 0x28, 0xfd, 0x82, 0xdc, 0x2e, 0xbe,
+	0x15, 0xd0, 0xcf, 0x56, 0xf9, 0x9d, 0xe0, 0x7f, 0x21, 0x84, 0x33, 0xcb, 0xdd, 0x73, 0xb2, 0xf2,
+	0xd6, 0x36, 0x36, 0xfa, 0xac, 0x68, 0x8f, 0xb0, 0xcf, 0x31, 0x1e, 0x2d, 0x87, 0xc5, 0x90, 0x6e,
+	0x91, 0xc5, 0xfe, 0x38, 0x49, 0x63, 0x74, 0xc9, 0x0e, 0xe8, 0xa7, 0xa4, 0x3e, 0xe2, 0xfa, 0x88,
+	0x16, 0x5e, 0x51, 0xb3, 0xe7, 0x13, 0x83, 0xa1, 0x70, 0x58, 0x98, 0xec, 0x7e, 0x43, 0x1a, 0xde,
+	0xcc, 0x6c, 0x9b, 0x35, 0x67, 0x9b, 0x8e, 0x0b, 0x7a, 0xa9, 0xc6, 0xcc, 0x85, 0xc3, 0xf5, 0xbf,
+	0xbf, 0x6f, 0xd7, 0xfe, 0xf9, 0xbe, 0x5d, 0xfb, 0xd7, 0xfb, 0x76, 0xed, 0x6f, 0xff, 0x6e, 0xdf,
+	0xe8, 0x2f, 0x99, 0xff, 0x67, 0x9e, 0xfd, 0x77, 0x00, 0x89, 0xce, 0x10, 0xa0, 0xfb, 0x14, 0x00,
+	0x00,
 }
@@ -0,0 +1,255 @@
+package app
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/crypto"
+
+	"github.com/iov-one/bcp-demo/node"
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// EscrowDocument is an escrow exported from a chain and signed
+// by whoever exported it, so it can be passed around (eg. attached
+// to a support ticket) and imported into the genesis of a dev chain.
+//
+// The signature covers the protobuf encoding of the export followed
+// by the height as 8 byte big endian. PubKey and Signature are the
+// hex encoded ed25519 key and signature.
+type EscrowDocument struct {
+	Height    int64                `json:"height"`
+	Export    *escrow.EscrowExport `json:"export"`
+	PubKey    string               `json:"pub_key"`
+	Signature string               `json:"signature"`
+}
+
+func escrowSignBytes(export *escrow.EscrowExport, height int64) ([]byte, error) {
+	bz, err := export.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	var h [8]byte
+	binary.BigEndian.PutUint64(h[:], uint64(height))
+	return append(bz, h[:]...), nil
+}
+
+// SignEscrowExport wraps the export of the given height into a
+// document signed by key
+func SignEscrowExport(export *escrow.EscrowExport, height int64,
+	key *crypto.PrivateKey) (*EscrowDocument, error) {
+
+	bz, err := escrowSignBytes(export, height)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := key.Sign(bz)
+	if err != nil {
+		return nil, err
+	}
+	return &EscrowDocument{
+		Height:    height,
+		Export:    export,
+		PubKey:    hex.EncodeToString(key.PublicKey().GetEd25519()),
+		Signature: hex.EncodeToString(sig.GetEd25519()),
+	}, nil
+}
+
+// Verify checks the signature and the export, and returns the
+// address of the signer
+func (d *EscrowDocument) Verify() (weave.Address, error) {
+	if d.Export == nil {
+		return nil, errors.New("document has no export")
+	}
+	pub, err := hex.DecodeString(d.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pub_key: %v", err)
+	}
+	sig, err := hex.DecodeString(d.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %v", err)
+	}
+	bz, err := escrowSignBytes(d.Export, d.Height)
+	if err != nil {
+		return nil, err
+	}
+	pubKey := &crypto.PublicKey{Pub: &crypto.PublicKey_Ed25519{Ed25519: pub}}
+	signature := &crypto.Signature{Sig: &crypto.Signature_Ed25519{Ed25519: sig}}
+	if !pubKey.Verify(bz, signature) {
+		return nil, errors.New("invalid signature")
+	}
+	err = d.Export.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return pubKey.Address(), nil
+}
+
+// updateAppState lets fn change the app_state of a genesis
+// file, keeping everything else
+func updateAppState(genesis []byte, fn func(weave.Options) error) ([]byte, error) {
+	var gen map[string]json.RawMessage
+	err := json.Unmarshal(genesis, &gen)
+	if err != nil {
+		return nil, err
+	}
+	opts := make(weave.Options)
+	if state := gen["app_state"]; len(state) > 0 && string(state) != "null" {
+		err = json.Unmarshal(state, &opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = fn(opts)
+	if err != nil {
+		return nil, err
+	}
+	gen["app_state"], err = json.MarshalIndent(opts, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(gen, "", "  ")
+}
+
+// updateGenesisFile applies fn to the app_state of the
+// genesis file in home
+func updateGenesisFile(home string, fn func(weave.Options) error) error {
+	genFile := filepath.Join(home, "config", "genesis.json")
+	info, err := os.Stat(genFile)
+	if err != nil {
+		return err
+	}
+	genesis, err := ioutil.ReadFile(genFile)
+	if err != nil {
+		return err
+	}
+	genesis, err = updateAppState(genesis, fn)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(genFile, genesis, info.Mode())
+}
+
+// ExportEscrowCmd writes the signed document of one escrow to
+// stdout, reading the database in home. The node must be stopped.
+//
+// Run it as `bov export-escrow -key words.txt esc1...`, with the
+// encoded id of the escrow (or its sequence, eg. 17), where
+// words.txt holds the mnemonic of the signing key, see
+// KeyFromMnemonic.
+func ExportEscrowCmd(w io.Writer, home string, args []string) error {
+	flags := flag.NewFlagSet("export-escrow", flag.ExitOnError)
+	keyFile := flags.String("key", "", "file with the mnemonic of the signing key")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *keyFile == "" || flags.NArg() != 1 {
+		return errors.New("usage: export-escrow -key FILE ID")
+	}
+	id, err := parseEscrowID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	words, err := ioutil.ReadFile(*keyFile)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := node.LoadConfig(filepath.Join(home, node.ConfigFile))
+	if err != nil {
+		return err
+	}
+	kv, err := CommitKVStore(cfg.DBBackend, filepath.Join(home, "bov.db"))
+	if err != nil {
+		return err
+	}
+	defer kv.Close()
+
+	export, err := escrow.Export(kv.Adapter(), escrow.NewBucket(),
+		namecoin.NewController(), id)
+	if err != nil {
+		return err
+	}
+	doc, err := SignEscrowExport(export, kv.LatestVersion().Version,
+		KeyFromMnemonic(string(words)))
+	if err != nil {
+		return err
+	}
+	bz, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(bz))
+	return err
+}
+
+// parseEscrowID reads an escrow id as written by escrow.EncodeID,
+// or the plain sequence of the escrow
+func parseEscrowID(arg string) ([]byte, error) {
+	if seq, err := strconv.ParseUint(arg, 10, 64); err == nil {
+		return escrow.SeqCondition(seq).ID(), nil
+	}
+	id, err := escrow.ParseID(arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid escrow id: %s", arg)
+	}
+	return id, nil
+}
+
+// ImportEscrowCmd verifies a document written by ExportEscrowCmd and
+// adds its escrow to the genesis file in home, keeping all other
+// genesis values. Pass -signer to only accept documents signed by
+// that (hex) address.
+//
+// This only changes genesis, reset the chain to apply it. The escrow
+// gets the next id of the new chain, the coins it holds are issued
+// on InitChain.
+func ImportEscrowCmd(home string, args []string) error {
+	flags := flag.NewFlagSet("import-escrow", flag.ExitOnError)
+	signer := flags.String("signer", "", "hex address the document must be signed by")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: import-escrow [-signer ADDRESS] FILE")
+	}
+	bz, err := ioutil.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	var doc EscrowDocument
+	err = json.Unmarshal(bz, &doc)
+	if err != nil {
+		return err
+	}
+	addr, err := doc.Verify()
+	if err != nil {
+		return err
+	}
+	if *signer != "" {
+		want, err := hex.DecodeString(*signer)
+		if err != nil {
+			return fmt.Errorf("invalid signer: %v", err)
+		}
+		if !addr.Equals(want) {
+			return fmt.Errorf("document signed by %s, not %s", addr, *signer)
+		}
+	}
+
+	return updateGenesisFile(home, func(opts weave.Options) error {
+		return escrow.AppendGenesis(opts, doc.Export.Escrow)
+	})
+}
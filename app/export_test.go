@@ -0,0 +1,118 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestExportImportEscrow(t *testing.T) {
+	home, err := ioutil.TempDir("", "bov-export")
+	require.NoError(t, err)
+	defer os.RemoveAll(home)
+	require.NoError(t, os.MkdirAll(filepath.Join(home, "config"), 0755))
+
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	esc := &escrow.Escrow{Sender: a, Arbiter: a, Recipient: b, Timeout: 100,
+		Amount: x.Coins{&x.Coin{Whole: 5, Ticker: "FOO"}}, Memo: "ticket 42"}
+
+	// a chain with one escrow, funded as the handler would
+	kv, err := CommitKVStore("", filepath.Join(home, "bov.db"))
+	require.NoError(t, err)
+	db := kv.Adapter()
+	obj, err := escrow.NewBucket().Create(db, esc)
+	require.NoError(t, err)
+	require.NoError(t, namecoin.NewController().IssueCoins(db,
+		escrow.Account.Address(obj.Key()), *esc.Amount[0]))
+	kv.Commit()
+	require.NoError(t, kv.Close())
+
+	keyFile := filepath.Join(home, "words.txt")
+	require.NoError(t, ioutil.WriteFile(keyFile, []byte("support desk words\n"), 0600))
+	signer := KeyFromMnemonic("support desk words").PublicKey().Address()
+
+	var out bytes.Buffer
+	err = ExportEscrowCmd(&out, home, []string{"-key", keyFile, "1"})
+	require.NoError(t, err)
+	var doc EscrowDocument
+	require.NoError(t, json.Unmarshal(out.Bytes(), &doc))
+	assert.Equal(t, int64(1), doc.Height)
+	assert.Equal(t, obj.Key(), doc.Export.Id)
+	assert.Equal(t, esc.Memo, doc.Export.Escrow.Memo)
+	addr, err := doc.Verify()
+	require.NoError(t, err)
+	assert.Equal(t, signer, addr)
+
+	// the encoded id works as well
+	var encoded bytes.Buffer
+	err = ExportEscrowCmd(&encoded, home, []string{"-key", keyFile, escrow.EncodeID(obj.Key())})
+	require.NoError(t, err)
+	assert.Equal(t, out.String(), encoded.String())
+	err = ExportEscrowCmd(&encoded, home, []string{"-key", keyFile, "esc1typo"})
+	assert.Error(t, err)
+
+	err = ExportEscrowCmd(&out, home, []string{"-key", keyFile, "2"})
+	assert.True(t, escrow.IsNoSuchEscrowErr(err), "%+v", err)
+
+	// any change breaks the signature
+	var forged EscrowDocument
+	require.NoError(t, json.Unmarshal(out.Bytes(), &forged))
+	forged.Export.Escrow.Amount = x.Coins{&x.Coin{Whole: 500, Ticker: "FOO"}}
+	_, err = forged.Verify()
+	assert.Error(t, err)
+	// and a signed document must hold what the escrow does
+	bad, err := SignEscrowExport(forged.Export, 1, KeyFromMnemonic("support desk words"))
+	require.NoError(t, err)
+	_, err = bad.Verify()
+	assert.Error(t, err)
+
+	// import keeps the rest of genesis
+	genFile := filepath.Join(home, "config", "genesis.json")
+	genesis := `{"chain_id": "dev", "app_state": {"wallets": []}}`
+	require.NoError(t, ioutil.WriteFile(genFile, []byte(genesis), 0644))
+	docFile := filepath.Join(home, "escrow.json")
+	require.NoError(t, ioutil.WriteFile(docFile, out.Bytes(), 0644))
+
+	err = ImportEscrowCmd(home, []string{"-signer", "0000000000000000000000000000000000000000", docFile})
+	assert.Error(t, err)
+	err = ImportEscrowCmd(home, []string{"-signer", signer.String(), docFile})
+	require.NoError(t, err)
+	err = ImportEscrowCmd(home, []string{docFile})
+	require.NoError(t, err)
+
+	bz, err := ioutil.ReadFile(genFile)
+	require.NoError(t, err)
+	var gen struct {
+		ChainID  string        `json:"chain_id"`
+		AppState weave.Options `json:"app_state"`
+	}
+	require.NoError(t, json.Unmarshal(bz, &gen))
+	assert.Equal(t, "dev", gen.ChainID)
+	assert.Equal(t, "[]", string(gen.AppState["wallets"]))
+
+	// both copies are created and funded on init
+	fresh := store.MemStore()
+	require.NoError(t, Initializer().FromGenesis(gen.AppState, fresh))
+	for _, seq := range []uint64{1, 2} {
+		id := escrow.SeqCondition(seq)
+		export, err := escrow.Export(fresh, escrow.NewBucket(), namecoin.NewController(), id)
+		require.NoError(t, err)
+		assert.NoError(t, export.Validate())
+		assert.Equal(t, esc.Memo, export.Escrow.Memo)
+	}
+}
@@ -0,0 +1,214 @@
+package app
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/ed25519"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/crypto"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/ordered"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// GenesisEntry describes one account to be funded in genesis.
+// Either Mnemonic or Address must be set. If a mnemonic is
+// given, the key is derived deterministically from it, so the
+// same input always produces the same genesis file.
+type GenesisEntry struct {
+	Mnemonic string        `json:"mnemonic,omitempty"`
+	Address  weave.Address `json:"address,omitempty"`
+	Name     string        `json:"name,omitempty"`
+	Coins    []*x.Coin     `json:"coins"`
+}
+
+// GetAddress returns the address to fund, deriving it
+// from the mnemonic if needed
+func (g GenesisEntry) GetAddress() (weave.Address, error) {
+	if g.Mnemonic != "" {
+		if g.Address != nil {
+			return nil, fmt.Errorf("both mnemonic and address set for %q", g.Name)
+		}
+		return KeyFromMnemonic(g.Mnemonic).PublicKey().Address(), nil
+	}
+	if len(g.Address) != weave.AddressLength {
+		return nil, fmt.Errorf("invalid address %X", []byte(g.Address))
+	}
+	return g.Address, nil
+}
+
+// KeyFromMnemonic derives an ed25519 private key from a list
+// of words. Words are normalized (lowercase, single spaces)
+// and hashed into the seed.
+//
+// This is not BIP39/BIP44, but it is stable and good enough
+// for reproducible demo environments.
+func KeyFromMnemonic(mnemonic string) *crypto.PrivateKey {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	seed := sha256.Sum256([]byte(strings.Join(words, " ")))
+	_, priv, err := ed25519.GenerateKey(bytes.NewReader(seed[:]))
+	if err != nil {
+		panic(err)
+	}
+	return &crypto.PrivateKey{
+		Priv: &crypto.PrivateKey_Ed25519{
+			Ed25519: priv,
+		},
+	}
+}
+
+// ReadGenesisEntries parses accounts from json or csv.
+//
+// json is a list of GenesisEntry. csv has one coin per line:
+//
+//	<mnemonic or hex address>,<name>,<amount>,<ticker>
+//
+// where amount may have up to 9 decimals ("12.5"). Lines with
+// the same key are merged into one account, an empty line or
+// one starting with # is ignored.
+func ReadGenesisEntries(r io.Reader, format string) ([]GenesisEntry, error) {
+	switch format {
+	case "json":
+		var entries []GenesisEntry
+		err := json.NewDecoder(r).Decode(&entries)
+		return entries, err
+	case "csv":
+		return readGenesisCSV(r)
+	default:
+		return nil, fmt.Errorf("unknown format: %s", format)
+	}
+}
+
+func readGenesisCSV(r io.Reader) ([]GenesisEntry, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []GenesisEntry
+	known := make(map[string]int)
+	for _, rec := range records {
+		key, name, amount, ticker := rec[0], rec[1], rec[2], rec[3]
+		coin, err := namecoin.ParseAmount(amount, ticker)
+		if err != nil {
+			return nil, err
+		}
+
+		idx, ok := known[key]
+		if !ok {
+			entry := GenesisEntry{Name: name}
+			if addr, err := hex.DecodeString(key); err == nil && len(addr) == weave.AddressLength {
+				entry.Address = addr
+			} else {
+				entry.Mnemonic = key
+			}
+			idx = len(entries)
+			known[key] = idx
+			entries = append(entries, entry)
+		}
+		if name != "" && entries[idx].Name != name {
+			if entries[idx].Name != "" {
+				return nil, fmt.Errorf("conflicting names %q and %q", entries[idx].Name, name)
+			}
+			entries[idx].Name = name
+		}
+		entries[idx].Coins = append(entries[idx].Coins, coin)
+	}
+	return entries, nil
+}
+
+// BuildAccountGenesis creates the namecoin wallets and tokens
+// for the given accounts. One token with default settings
+// is registered for every ticker in use, sorted by ticker.
+//
+// Signature accounts (x/sigs) are created on the first tx,
+// so they need no entry in genesis.
+func BuildAccountGenesis(entries []GenesisEntry) (weave.Options, error) {
+	wallets := make([]namecoin.GenesisAccount, len(entries))
+	var seen ordered.Set
+	for i, entry := range entries {
+		addr, err := entry.GetAddress()
+		if err != nil {
+			return nil, err
+		}
+		coins, err := x.CombineCoins(asCoinList(entry.Coins)...)
+		if err != nil {
+			return nil, err
+		}
+		wallet, err := namecoin.WalletWith(addr, entry.Name, coins...)
+		if err != nil {
+			return nil, err
+		}
+		wallets[i] = namecoin.GenesisAccount{
+			Address: addr,
+			Wallet:  namecoin.AsWallet(wallet),
+		}
+		for _, c := range coins {
+			seen.Add(c.Ticker)
+		}
+	}
+
+	tickers := seen.Values()
+	tokens := make([]namecoin.GenesisToken, len(tickers))
+	for i, t := range tickers {
+		tokens[i] = namecoin.GenesisToken{
+			Ticker:  t,
+			Name:    fmt.Sprintf("Token %s", t),
+			SigFigs: 6,
+		}
+	}
+
+	return namecoin.BuildGenesis(wallets, tokens)
+}
+
+// GenAccountOptions reads the accounts file given as first
+// argument and produces the app_state for the genesis file.
+// The format is taken from the file extension (.csv or .json).
+//
+// It can be used as server.GenOptions
+func GenAccountOptions(args []string) (json.RawMessage, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("missing accounts file")
+	}
+	file := args[0]
+	format := strings.TrimPrefix(filepath.Ext(file), ".")
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries, err := ReadGenesisEntries(f, format)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := BuildAccountGenesis(entries)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(opts, "", "  ")
+}
+
+func asCoinList(coins []*x.Coin) []x.Coin {
+	res := make([]x.Coin, len(coins))
+	for i, c := range coins {
+		res[i] = *c
+	}
+	return res
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave/store"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestKeyFromMnemonic(t *testing.T) {
+	a := KeyFromMnemonic("cat dog fish")
+	b := KeyFromMnemonic("  Cat DOG\tfish ")
+	c := KeyFromMnemonic("cat dog fisher")
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a.PublicKey().Address(), c.PublicKey().Address())
+
+	// make sure we have a usable signing key
+	msg := []byte("foobar")
+	sig, err := a.Sign(msg)
+	require.NoError(t, err)
+	assert.True(t, a.PublicKey().Verify(msg, sig))
+}
+
+func TestReadGenesisEntries(t *testing.T) {
+	addr := KeyFromMnemonic("one two three").PublicKey().Address()
+	bob := KeyFromMnemonic("four five six").PublicKey().Address()
+
+	csvIn := fmt.Sprintf(`# demo accounts
+one two three,alice,100,IOV
+one two three,,2.5,ETH
+%s,bobby,7,IOV
+`, bob.String())
+	jsonIn := fmt.Sprintf(`[
+  {"mnemonic": "one two three", "name": "alice",
+   "coins": [{"whole": 100, "ticker": "IOV"}, {"whole": 2, "fractional": 500000000, "ticker": "ETH"}]},
+  {"address": "%s", "name": "bobby", "coins": [{"whole": 7, "ticker": "IOV"}]}
+]`, bob.String())
+
+	cases := []struct {
+		input   string
+		format  string
+		isError bool
+	}{
+		0: {csvIn, "csv", false},
+		1: {jsonIn, "json", false},
+		2: {csvIn, "yaml", true},
+		// wrong number of fields
+		3: {"one two,alice,100\n", "csv", true},
+		// name conflict
+		4: {"one,alice,100,IOV\none,bobby,1,IOV\n", "csv", true},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			entries, err := ReadGenesisEntries(strings.NewReader(tc.input), tc.format)
+			if tc.isError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 2, len(entries))
+
+			opts, err := BuildAccountGenesis(entries)
+			require.NoError(t, err)
+
+			// same input gives same output
+			again, err := BuildAccountGenesis(entries)
+			require.NoError(t, err)
+			assert.Equal(t, opts, again)
+
+			db := store.MemStore()
+			err = Initializer().FromGenesis(opts, db)
+			require.NoError(t, err)
+
+			bucket := namecoin.NewWalletBucket()
+			obj, err := bucket.GetByName(db, "alice")
+			require.NoError(t, err)
+			require.NotNil(t, obj)
+			assert.EqualValues(t, addr, obj.Key())
+			assert.Equal(t, 2, len(namecoin.AsWallet(obj).Coins))
+
+			obj, err = bucket.GetByName(db, "bobby")
+			require.NoError(t, err)
+			require.NotNil(t, obj)
+			assert.EqualValues(t, bob, obj.Key())
+
+			var tokens []namecoin.GenesisToken
+			err = json.Unmarshal(opts["tokens"], &tokens)
+			require.NoError(t, err)
+			require.Equal(t, 2, len(tokens))
+			assert.Equal(t, "ETH", tokens[0].Ticker)
+			assert.Equal(t, "IOV", tokens[1].Ticker)
+		})
+	}
+}
@@ -6,13 +6,18 @@ import (
 	"fmt"
 	"path/filepath"
 
-	"github.com/iov-one/bcp-demo/x/namecoin"
 	abci "github.com/tendermint/abci/types"
 	"github.com/tendermint/tmlibs/log"
 
 	"github.com/confio/weave"
 	"github.com/confio/weave/crypto"
 	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/node"
+	"github.com/iov-one/bcp-demo/tracing"
+	"github.com/iov-one/bcp-demo/x/crash"
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/outbox"
 )
 
 // GenInitOptions will produce some basic options for one rich
@@ -39,6 +44,12 @@ func GenInitOptions(args []string) (json.RawMessage, error) {
 		fmt.Println(phrase)
 	}
 
+	// ship the escrow presets, admins maintain them later
+	templates, err := json.MarshalIndent(escrow.DefaultTemplates(), "    ", "  ")
+	if err != nil {
+		return nil, err
+	}
+
 	opts := fmt.Sprintf(`{
     "wallets": [
       {
@@ -58,26 +69,81 @@ func GenInitOptions(args []string) (json.RawMessage, error) {
         "name": "Main token of this chain",
         "sig_figs": 6
       }
-    ]
-  }`, addr, ticker, ticker)
+    ],
+    "roles": [
+      {
+        "address": "%s",
+        "roles": ["admin", "issuer"]
+      }
+    ],
+    "tx_limits": {
+      "max_tx_bytes": 65536,
+      "max_memo_length": 128,
+      "max_coins": 8,
+      "max_batch_msgs": 16
+    },
+    "escrow_templates": %s
+  }`, addr, ticker, ticker, addr, templates)
 	return []byte(opts), nil
 }
 
 // GenerateApp is used to create a stub for server/start.go command
 func GenerateApp(home string, logger log.Logger) (abci.Application, error) {
-	// db goes in a subdir, but "" -> "" for memdb
+	// db goes in a subdir, but "" -> "" keeps it in memory for tests
 	var dbPath string
+	cfg := node.DefaultConfig()
 	if home != "" {
 		dbPath = filepath.Join(home, "bov.db")
+		var err error
+		cfg, err = node.LoadConfig(filepath.Join(home, node.ConfigFile))
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// TODO: anyone can make a token????
-	stack := Stack(x.Coin{}, nil)
-	app, err := Application("mycoin", stack, TxDecoder, dbPath)
+	// count the panics for the metrics, and keep the
+	// reports next to the db
+	counter := crash.NewCounter()
+	crashes := crash.Reporters{counter}
+	if home != "" {
+		crashes = append(crashes, crash.NewFileReporter(filepath.Join(home, node.CrashFile),
+			func(err error) { logger.Error("Cannot write crash report", "err", err) }))
+	}
+
+	var tracer *tracing.Tracer
+	var exporter *tracing.OTLPExporter
+	if cfg.Tracing.Endpoint != "" {
+		exporter = tracing.NewOTLPExporter(cfg.Tracing.Endpoint, "bov", logger)
+		tracer = tracing.NewTracer(exporter, cfg.Tracing.Every)
+	}
+
+	stack := ReportingStack(x.Coin{}, cfg.MinGasPrice, crashes, tracer)
+	app, err := Application("mycoin", stack, TxDecoder, cfg.DBBackend, dbPath,
+		Views(cfg.Views))
 	if err != nil {
+		if exporter != nil {
+			exporter.Close()
+		}
 		return nil, err
 	}
-	app.WithInit(namecoin.Initializer{})
+	app.panics = counter
+	if exporter != nil {
+		app.traces = exporter
+	}
+	if cfg.Outbox.Webhook != "" && home != "" {
+		app.relay, err = outbox.NewRelay(outbox.NewWebhook(cfg.Outbox.Webhook),
+			filepath.Join(home, node.OutboxFile), logger.With("module", "outbox"))
+		if err != nil {
+			app.Close()
+			return nil, err
+		}
+		// push what was left before the restart
+		app.relay.Commit(app.kv.Adapter())
+	}
+	if cfg.Diagnostics && home != "" {
+		app.kv.EnableDiagnostics(filepath.Join(home, node.DiagnosticsFile))
+	}
+	app.WithInit(Initializer())
 
 	// guess the location of the genesis file
 	genesisPath := filepath.Join(home, "config", "genesis.json")
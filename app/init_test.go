@@ -34,6 +34,7 @@ func TestGenInitOptions(t *testing.T) {
 				ca = ca[:len(ca)-1]
 			}
 			assert.Contains(t, string(val), ca)
+			assert.Contains(t, string(val), `"otc-trade"`)
 		})
 	}
 }
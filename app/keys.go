@@ -0,0 +1,138 @@
+package app
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/iov-one/bcp-demo/x/keys"
+)
+
+// KeysCmd manages secp256k1 keys of x/keys, stored as the hex
+// of the 32 byte secret so Ethereum keys can be imported as is.
+//
+//	bov keys generate FILE       writes a new key to FILE
+//	bov keys import HEX FILE     writes an existing secret to FILE
+//	bov keys show FILE           prints the public key and address
+//	bov keys sign -key FILE -chain-id ID -seq N -out SIGNED TX
+//	                             adds a signature to the tx in TX
+func KeysCmd(w io.Writer, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: keys generate|import|show|sign ...")
+	}
+	cmd, rest := args[0], args[1:]
+	switch cmd {
+	case "generate":
+		if len(rest) != 1 {
+			return errors.New("usage: keys generate FILE")
+		}
+		key, err := keys.GenPrivKeySecp256k1()
+		if err != nil {
+			return err
+		}
+		return writeKey(w, rest[0], key)
+	case "import":
+		if len(rest) != 2 {
+			return errors.New("usage: keys import HEX FILE")
+		}
+		secret, err := hex.DecodeString(strings.TrimPrefix(rest[0], "0x"))
+		if err != nil {
+			return fmt.Errorf("invalid secret: %v", err)
+		}
+		key, err := keys.NewPrivateKey(secret)
+		if err != nil {
+			return err
+		}
+		return writeKey(w, rest[1], key)
+	case "show":
+		if len(rest) != 1 {
+			return errors.New("usage: keys show FILE")
+		}
+		key, err := readKey(rest[0])
+		if err != nil {
+			return err
+		}
+		return showKey(w, key)
+	case "sign":
+		return signTxCmd(rest)
+	}
+	return fmt.Errorf("unknown keys command: %s", cmd)
+}
+
+// writeKey stores key in a new file, it never overwrites a key
+func writeKey(w io.Writer, file string, key *keys.PrivateKey) error {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(f, hex.EncodeToString(key.Bytes()))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return showKey(w, key)
+}
+
+func readKey(file string) (*keys.PrivateKey, error) {
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := hex.DecodeString(strings.TrimSpace(string(bz)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid key file %s: %v", file, err)
+	}
+	return keys.NewPrivateKey(secret)
+}
+
+func showKey(w io.Writer, key *keys.PrivateKey) error {
+	pub := key.PublicKey()
+	_, err := fmt.Fprintf(w, "pubkey:  %X\naddress: %s\n", pub.Secp256K1, pub.Address())
+	return err
+}
+
+// signTxCmd reads a binary tx, adds the signature of the key
+// and writes it to the out file
+func signTxCmd(args []string) error {
+	flags := flag.NewFlagSet("keys sign", flag.ExitOnError)
+	keyFile := flags.String("key", "", "file with the signing key")
+	chainID := flags.String("chain-id", "", "chain the tx is for")
+	seq := flags.Int64("seq", 0, "sequence of the signer")
+	out := flags.String("out", "", "file to write the signed tx to")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *keyFile == "" || *chainID == "" || *out == "" || flags.NArg() != 1 {
+		return errors.New("usage: keys sign -key FILE -chain-id ID [-seq N] -out FILE TX")
+	}
+	key, err := readKey(*keyFile)
+	if err != nil {
+		return err
+	}
+	bz, err := ioutil.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	var tx Tx
+	if err := tx.Unmarshal(bz); err != nil {
+		return fmt.Errorf("invalid tx: %v", err)
+	}
+	sig, err := keys.SignTx(key, &tx, *chainID, *seq)
+	if err != nil {
+		return err
+	}
+	tx.KeySignatures = append(tx.KeySignatures, sig)
+	bz, err = tx.Marshal()
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(*out, bz, 0644)
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/keys"
+)
+
+func TestKeysCmd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bov-keys")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// the key of the web3.js documentation
+	secret := "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	keyFile := filepath.Join(dir, "eth.key")
+	var out bytes.Buffer
+	require.NoError(t, KeysCmd(&out, []string{"import", secret, keyFile}))
+	var shown bytes.Buffer
+	require.NoError(t, KeysCmd(&shown, []string{"show", keyFile}))
+	assert.Equal(t, out.String(), shown.String())
+
+	key, err := readKey(keyFile)
+	require.NoError(t, err)
+	assert.Contains(t, shown.String(), key.PublicKey().Address().String())
+
+	// never overwrite a key
+	err = KeysCmd(&out, []string{"generate", keyFile})
+	assert.Error(t, err)
+	other := filepath.Join(dir, "new.key")
+	require.NoError(t, KeysCmd(&out, []string{"generate", other}))
+	err = KeysCmd(&out, []string{"import", "abcd", filepath.Join(dir, "short.key")})
+	assert.Error(t, err)
+
+	// sign a tx and verify it as the decorator does
+	var helpers x.TestHelpers
+	_, rcpt := helpers.MakeKey()
+	tx := &Tx{Sum: &Tx_SendMsg{&cash.SendMsg{
+		Src:    key.PublicKey().Address(),
+		Dest:   rcpt.Address(),
+		Amount: &x.Coin{Whole: 1, Ticker: "IOV"},
+	}}}
+	bz, err := tx.Marshal()
+	require.NoError(t, err)
+	txFile := filepath.Join(dir, "tx.bin")
+	require.NoError(t, ioutil.WriteFile(txFile, bz, 0644))
+	signed := filepath.Join(dir, "signed.bin")
+	err = KeysCmd(&out, []string{"sign", "-key", keyFile, "-chain-id", "test-chain",
+		"-seq", "3", "-out", signed, txFile})
+	require.NoError(t, err)
+
+	bz, err = ioutil.ReadFile(signed)
+	require.NoError(t, err)
+	var got Tx
+	require.NoError(t, got.Unmarshal(bz))
+	require.Len(t, got.KeySignatures, 1)
+	sig := got.KeySignatures[0]
+	assert.Equal(t, int64(3), sig.Sequence)
+	signBytes, err := got.GetSignBytes()
+	require.NoError(t, err)
+	assert.True(t, sig.Pubkey.Verify(keys.BuildSignBytes(signBytes, "test-chain", 3), sig.Signature))
+
+	err = KeysCmd(&out, []string{"rotate", keyFile})
+	assert.True(t, strings.Contains(err.Error(), "unknown"))
+}
@@ -0,0 +1,15 @@
+package app
+
+import (
+	"github.com/confio/weave/x/cash"
+	"github.com/confio/weave/x/sigs"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+)
+
+// init registers the buckets of the weave modules in the app,
+// the modules of bcp-demo register their own
+func init() {
+	keyspace.Buckets("cash", cash.BucketName)
+	keyspace.Buckets("sigs", sigs.BucketName)
+}
@@ -0,0 +1,25 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+)
+
+// TestKeyspace makes sure every key the app writes has an owner
+func TestKeyspace(t *testing.T) {
+	cases := map[string]string{
+		"esc:\x00\x00\x00\x00\x00\x00\x00\x01": "escrow",
+		"offers:escrow/1/sender":               "ownership",
+		"cash:addr":                            "cash",
+		"sigs:addr":                            "sigs",
+		"txs:hash":                             "txindex",
+		"_i.esc_sender:addr":                   keyspace.Weave,
+		"_wv:chainID":                          keyspace.Weave,
+	}
+	for key, module := range cases {
+		assert.Equal(t, module, keyspace.Owner([]byte(key)), key)
+	}
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/ioutil"
+
+	"github.com/confio/weave"
+
+	"github.com/iov-one/bcp-demo/x/escrow"
+)
+
+// MigrateEscrowsCmd re-creates the escrows of a prior chain in the
+// genesis file in home. The input is a json list of EscrowExport,
+// as returned by "/escrows/export" for every escrow. The height
+// offset is added to all timeouts, usually minus the last height of
+// the prior chain.
+//
+// The escrows get new ids, "/escrows/alias" finds them by the id
+// they had before. Open bids are not migrated.
+//
+// Run it as `bov migrate-escrows -height-offset -120000 escrows.json`
+func MigrateEscrowsCmd(home string, args []string) error {
+	flags := flag.NewFlagSet("migrate-escrows", flag.ExitOnError)
+	offset := flags.Int64("height-offset", 0, "added to the timeout of every escrow")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: migrate-escrows [-height-offset N] FILE")
+	}
+	bz, err := ioutil.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	var exports []*escrow.EscrowExport
+	err = json.Unmarshal(bz, &exports)
+	if err != nil {
+		return err
+	}
+	migrated, err := escrow.Migrate(exports, *offset)
+	if err != nil {
+		return err
+	}
+	return updateGenesisFile(home, func(opts weave.Options) error {
+		return escrow.AppendMigratedGenesis(opts, migrated...)
+	})
+}
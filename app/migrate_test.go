@@ -0,0 +1,72 @@
+package app
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/escrow"
+)
+
+func TestMigrateEscrowsCmd(t *testing.T) {
+	home, err := ioutil.TempDir("", "bov-migrate")
+	require.NoError(t, err)
+	defer os.RemoveAll(home)
+	require.NoError(t, os.MkdirAll(filepath.Join(home, "config"), 0755))
+
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	amount := x.Coins{&x.Coin{Whole: 5, Ticker: "FOO"}}
+	exports := []*escrow.EscrowExport{{
+		Id:      escrow.SeqCondition(42),
+		Escrow:  &escrow.Escrow{Sender: a, Arbiter: a, Recipient: b, Timeout: 1500, Amount: amount},
+		Balance: amount,
+	}}
+	bz, err := json.Marshal(exports)
+	require.NoError(t, err)
+	exportFile := filepath.Join(home, "escrows.json")
+	require.NoError(t, ioutil.WriteFile(exportFile, bz, 0644))
+
+	genFile := filepath.Join(home, "config", "genesis.json")
+	genesis := `{"chain_id": "next", "app_state": {"wallets": []}}`
+	require.NoError(t, ioutil.WriteFile(genFile, []byte(genesis), 0644))
+
+	// expired on the new chain, genesis is left as is
+	err = MigrateEscrowsCmd(home, []string{"-height-offset", "-1500", exportFile})
+	assert.Error(t, err)
+	bz, err = ioutil.ReadFile(genFile)
+	require.NoError(t, err)
+	assert.Equal(t, genesis, string(bz))
+
+	err = MigrateEscrowsCmd(home, []string{"-height-offset", "-1000", exportFile})
+	require.NoError(t, err)
+
+	bz, err = ioutil.ReadFile(genFile)
+	require.NoError(t, err)
+	var gen struct {
+		ChainID  string        `json:"chain_id"`
+		AppState weave.Options `json:"app_state"`
+	}
+	require.NoError(t, json.Unmarshal(bz, &gen))
+	assert.Equal(t, "next", gen.ChainID)
+	assert.Equal(t, "[]", string(gen.AppState["wallets"]))
+
+	db := store.MemStore()
+	require.NoError(t, Initializer().FromGenesis(gen.AppState, db))
+	id, err := escrow.NewAliasBucket().Resolve(db, escrow.SeqCondition(42))
+	require.NoError(t, err)
+	obj, err := escrow.NewBucket().Get(db, id)
+	require.NoError(t, err)
+	require.NotNil(t, obj)
+	assert.Equal(t, int64(500), escrow.AsEscrow(obj).Timeout)
+}
@@ -0,0 +1,152 @@
+package app
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/confidential"
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/faucet"
+	"github.com/iov-one/bcp-demo/x/trade"
+)
+
+// escrowMsg is any message acting on an existing escrow
+type escrowMsg interface {
+	GetEscrowId() []byte
+}
+
+// Parties returns the addresses a tx concerns besides its
+// signers: the recipient of a payment, all parties and
+// observers of an escrow, the maker of a filled order, the
+// parties of a confidential escrow and who taps the faucet. It is
+// the txindex.PartiesFunc of this app.
+func Parties(db weave.ReadOnlyKVStore, tx weave.Tx) ([]weave.Address, error) {
+	msg, err := tx.GetMsg()
+	if err != nil {
+		// the tx fails below, with no one to record
+		return nil, nil
+	}
+
+	var addrs []weave.Address
+	switch m := msg.(type) {
+	case *cash.SendMsg:
+		addrs = append(addrs, m.Src, m.Dest)
+	case *escrow.CreateEscrowMsg:
+		addrs = append(addrs, permAddresses(m.Sender, m.Arbiter, m.Recipient,
+			m.Clawback, m.Attester, m.BackupArbiter)...)
+		addrs = append(addrs, asAddresses(m.Observers)...)
+		addrs = append(addrs, asAddresses(escrow.ShareAddresses(m.Shares))...)
+	case *escrow.CreateEscrowMsgV2:
+		addrs = append(addrs, permAddresses(m.Sender, m.Arbiter, m.Recipient,
+			m.GetOptions().GetClawback(), m.GetOptions().GetAttester(),
+			m.GetOptions().GetBackupArbiter())...)
+		addrs = append(addrs, asAddresses(m.GetOptions().GetObservers())...)
+		addrs = append(addrs, asAddresses(escrow.ShareAddresses(m.GetOptions().GetShares()))...)
+	case *escrow.CreateFromTemplateMsg:
+		addrs = append(addrs, permAddresses(m.Sender, m.Arbiter, m.Recipient)...)
+	case *escrow.SendEscrowMsg:
+		addrs = append(addrs, m.Src)
+		addrs = append(addrs, permAddresses(m.Dest, m.Escrow.GetArbiter())...)
+	case *escrow.ReleaseEscrowMsg:
+		// the escrow a chained release pays into
+		if c := m.Chain; c != nil && len(c.EscrowId) > 0 {
+			parties, err := escrowParties(db, c.EscrowId)
+			if err != nil {
+				return nil, err
+			}
+			addrs = append(addrs, parties...)
+		} else if c != nil {
+			addrs = append(addrs, permAddresses(c.Arbiter, c.Recipient)...)
+		}
+	case *escrow.UpdateEscrowPartiesMsg:
+		// the new parties, the old ones are added below
+		addrs = append(addrs, permAddresses(m.Sender, m.Arbiter, m.Recipient)...)
+	case *escrow.UpdateEscrowObserversMsg:
+		// the new observers, the old ones are added below
+		addrs = append(addrs, asAddresses(m.Add)...)
+	case *escrow.NetEscrowsMsg:
+		for _, id := range m.EscrowIds {
+			parties, err := escrowParties(db, id)
+			if err != nil {
+				return nil, err
+			}
+			addrs = append(addrs, parties...)
+		}
+	case *trade.FillOrderMsg:
+		obj, err := trade.NewBucket().Get(db, m.OrderId)
+		if err != nil {
+			return nil, err
+		}
+		if order := trade.AsOrder(obj); order != nil {
+			addrs = append(addrs, order.Maker)
+		}
+	case *faucet.TapMsg:
+		addrs = append(addrs, m.Recipient)
+	case *confidential.CreateEscrowMsg:
+		addrs = append(addrs, m.Arbiter, m.Recipient)
+	case *confidential.ReleaseEscrowMsg, *confidential.ReturnEscrowMsg,
+		*confidential.GrantViewMsg:
+		// not an id in the escrow bucket
+		return confidentialParties(db, m.(escrowMsg).GetEscrowId())
+	}
+
+	if em, ok := msg.(escrowMsg); ok {
+		parties, err := escrowParties(db, em.GetEscrowId())
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, parties...)
+	}
+	return addrs, nil
+}
+
+// escrowParties returns the parties and observers of the
+// escrow, none if it does not exist
+func escrowParties(db weave.ReadOnlyKVStore, id []byte) ([]weave.Address, error) {
+	obj, err := escrow.NewBucket().Get(db, id)
+	if err != nil {
+		return nil, err
+	}
+	esc := escrow.AsEscrow(obj)
+	if esc == nil {
+		return nil, nil
+	}
+	addrs := permAddresses(esc.Sender, esc.Arbiter, esc.Recipient,
+		esc.Clawback, esc.Attester, esc.BackupArbiter)
+	addrs = append(addrs, asAddresses(esc.Observers)...)
+	return append(addrs, asAddresses(escrow.ShareAddresses(esc.Shares))...), nil
+}
+
+// confidentialParties returns the parties of the confidential
+// escrow, none if it does not exist
+func confidentialParties(db weave.ReadOnlyKVStore, id []byte) ([]weave.Address, error) {
+	obj, err := confidential.NewEscrowBucket().Get(db, id)
+	if err != nil {
+		return nil, err
+	}
+	esc := confidential.AsEscrow(obj)
+	if esc == nil {
+		return nil, nil
+	}
+	return []weave.Address{esc.Sender, esc.Arbiter, esc.Recipient}, nil
+}
+
+// permAddresses returns the addresses of all permissions
+// that are set
+func permAddresses(perms ...[]byte) []weave.Address {
+	var addrs []weave.Address
+	for _, perm := range perms {
+		if len(perm) != 0 {
+			addrs = append(addrs, weave.Permission(perm).Address())
+		}
+	}
+	return addrs
+}
+
+func asAddresses(list [][]byte) []weave.Address {
+	addrs := make([]weave.Address, len(list))
+	for i, addr := range list {
+		addrs[i] = addr
+	}
+	return addrs
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iov-one/bcp-demo/x/confidential"
+	"github.com/iov-one/bcp-demo/x/escrow"
+)
+
+func TestPartiesObservers(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	o := weave.NewAddress([]byte("observer"))
+	p := weave.NewAddress([]byte("new observer"))
+
+	db := store.MemStore()
+	esc := &escrow.Escrow{Sender: a, Arbiter: a, Recipient: b, Timeout: 100,
+		Amount: x.Coins{&x.Coin{Whole: 1, Ticker: "FOO"}}, Observers: [][]byte{o}}
+	obj, err := escrow.NewBucket().Create(db, esc)
+	require.NoError(t, err)
+
+	create := escrow.NewCreateMsg(a, b, a, esc.Amount, 100, "")
+	create.Observers = [][]byte{o}
+	addrs, err := Parties(db, helpers.MockTx(create))
+	require.NoError(t, err)
+	assert.Contains(t, addrs, o)
+
+	// the old observers learn about the change, and the new ones
+	update := &escrow.UpdateEscrowObserversMsg{EscrowId: obj.Key(),
+		Add: [][]byte{p}, Remove: [][]byte{o}}
+	addrs, err = Parties(db, helpers.MockTx(update))
+	require.NoError(t, err)
+	assert.Contains(t, addrs, o)
+	assert.Contains(t, addrs, p)
+
+	addrs, err = Parties(db, helpers.MockTx(&escrow.ReleaseEscrowMsg{EscrowId: obj.Key()}))
+	require.NoError(t, err)
+	assert.Equal(t, []weave.Address{a.Address(), a.Address(), b.Address(), o}, addrs)
+
+	// a chained release reaches the parties of the next escrow
+	_, c := helpers.MakeKey()
+	chained := &escrow.ReleaseEscrowMsg{EscrowId: obj.Key(),
+		Chain: &escrow.ChainEscrow{Arbiter: a, Recipient: c, Timeout: 200}}
+	addrs, err = Parties(db, helpers.MockTx(chained))
+	require.NoError(t, err)
+	assert.Contains(t, addrs, c.Address())
+}
+
+func TestPartiesConfidential(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	_, c := helpers.MakeKey()
+
+	// both escrows get the first id
+	db := store.MemStore()
+	_, err := escrow.NewBucket().Create(db, &escrow.Escrow{Sender: a, Arbiter: a,
+		Recipient: a, Timeout: 100, Amount: x.Coins{&x.Coin{Whole: 1, Ticker: "FOO"}}})
+	require.NoError(t, err)
+	o, err := confidential.NewOpening(5)
+	require.NoError(t, err)
+	obj, err := confidential.NewEscrowBucket().Create(db, &confidential.Escrow{
+		Sender: a.Address(), Arbiter: b.Address(), Recipient: c.Address(),
+		Ticker: "FOO", Timeout: 100, Commitment: confidential.Commit(o)})
+	require.NoError(t, err)
+
+	release := &confidential.ReleaseEscrowMsg{EscrowId: obj.Key()}
+	addrs, err := Parties(db, helpers.MockTx(release))
+	require.NoError(t, err)
+	assert.Equal(t, []weave.Address{a.Address(), b.Address(), c.Address()}, addrs)
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave/errors"
+)
+
+// QueryProof looks up one db key with a proof, see App.Query
+const QueryProof = "/proof"
+
+// Query answers QueryProof with the value of the db key given
+// as data (eg. the key of a wallet with its bucket prefix) and
+// an iavl proof against the app hash of the version in Height,
+// the last committed one unless Height is set. This app hash is
+// in the header of the next block, see package verify.
+// All other paths go to the QueryRouter.
+func (a App) Query(req abci.RequestQuery) abci.ResponseQuery {
+	if req.Path != QueryProof {
+		return a.BaseApp.Query(req)
+	}
+
+	var res abci.ResponseQuery
+	if len(req.Data) == 0 {
+		res.Code = errors.CodeUnknownRequest
+		res.Log = "Missing key"
+		return res
+	}
+	version := req.Height
+	if version == 0 {
+		version = a.kv.LatestVersion().Version
+	}
+	value, proof, err := a.kv.GetWithProof(req.Data, version)
+	if err != nil {
+		res.Code = errors.CodeInternalErr
+		res.Log = fmt.Sprintf("No proof at height %d: %v", version, err)
+		return res
+	}
+	res.Key = req.Data
+	res.Value = value
+	res.Proof = proof.Bytes()
+	res.Height = version
+	return res
+}
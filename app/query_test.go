@@ -0,0 +1,192 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/abci/types"
+	"github.com/tendermint/tmlibs/log"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+
+	bov "github.com/iov-one/bcp-demo"
+	"github.com/iov-one/bcp-demo/query"
+	"github.com/iov-one/bcp-demo/x/escrow"
+)
+
+func TestRichEscrowQuery(t *testing.T) {
+	sender := KeyFromMnemonic("rich sender").PublicKey()
+	recipient := KeyFromMnemonic("rich recipient").PublicKey()
+	arbiter := KeyFromMnemonic("rich arbiter").PublicKey()
+	coin := x.NewCoin(50, 0, "IOV")
+
+	opts, err := BuildAccountGenesis([]GenesisEntry{
+		{Address: sender.Address(), Name: "sender", Coins: []*x.Coin{&coin}},
+		{Address: recipient.Address(), Name: "recipient", Coins: []*x.Coin{&coin}},
+	})
+	require.NoError(t, err)
+	escOpts, err := escrow.BuildGenesis([]*escrow.Escrow{{
+		Sender:    sender.Permission(),
+		Recipient: recipient.Permission(),
+		Arbiter:   arbiter.Permission(),
+		Amount:    []*x.Coin{&coin},
+		Timeout:   100,
+	}})
+	require.NoError(t, err)
+	for k, v := range escOpts {
+		opts[k] = v
+	}
+	db := store.MemStore()
+	require.NoError(t, Initializer().FromGenesis(opts, db))
+
+	// genesis escrows get the first sequence value
+	id := []byte{0, 0, 0, 0, 0, 0, 0, 1}
+	qr := weave.NewQueryRouter()
+	RegisterRichQuery(qr)
+	h := qr.Handler(QueryRichEscrow)
+	require.NotNil(t, h)
+
+	cases := []struct {
+		mod     string
+		data    []byte
+		isError bool
+		found   bool
+	}{
+		0: {"", id, false, true},
+		1: {"", []byte{0, 0, 0, 0, 0, 0, 0, 7}, false, false},
+		2: {"prefix", id, true, false},
+	}
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			models, err := h.Query(db, tc.mod, tc.data)
+			if tc.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if !tc.found {
+				assert.Empty(t, models)
+				return
+			}
+			require.Equal(t, 1, len(models))
+			assert.Equal(t, escrow.NewBucket().DBKey(id), models[0].Key)
+
+			var rich RichEscrow
+			require.NoError(t, rich.Unmarshal(models[0].Value))
+			assert.Equal(t, int64(100), rich.Escrow.Timeout)
+			assert.Equal(t, []*x.Coin{&coin}, rich.Balance)
+			assert.Equal(t, "sender", rich.Sender.Name)
+			assert.Equal(t, "recipient", rich.Recipient.Name)
+			assert.Equal(t, []*x.Coin{&coin}, rich.Recipient.Coins)
+			// no wallet, just the address
+			assert.EqualValues(t, arbiter.Address(), rich.Arbiter.Address)
+			assert.Equal(t, "", rich.Arbiter.Name)
+			assert.Empty(t, rich.Arbiter.Coins)
+		})
+	}
+}
+
+func TestPagedQuery(t *testing.T) {
+	sender := KeyFromMnemonic("paged sender").PublicKey()
+	recipient := KeyFromMnemonic("paged recipient").PublicKey()
+	coin := x.NewCoin(50, 0, "IOV")
+
+	opts, err := BuildAccountGenesis([]GenesisEntry{
+		{Address: sender.Address(), Name: "sender", Coins: []*x.Coin{&coin}},
+		{Address: recipient.Address(), Name: "recipient", Coins: []*x.Coin{&coin}},
+	})
+	require.NoError(t, err)
+	escOpts, err := escrow.BuildGenesis([]*escrow.Escrow{{
+		Sender:    sender.Permission(),
+		Recipient: recipient.Permission(),
+		Arbiter:   recipient.Permission(),
+		Amount:    []*x.Coin{&coin},
+		Timeout:   100,
+	}})
+	require.NoError(t, err)
+	for k, v := range escOpts {
+		opts[k] = v
+	}
+	db := store.MemStore()
+	require.NoError(t, Initializer().FromGenesis(opts, db))
+
+	id := []byte{0, 0, 0, 0, 0, 0, 0, 1}
+	escrowKey := escrow.NewBucket().DBKey(id)
+	qr := weave.NewQueryRouter()
+	RegisterPagedQuery(qr)
+
+	cases := []struct {
+		path    string
+		data    []byte
+		isError bool
+		keys    int
+		total   int64
+	}{
+		0: {"/escrows/page?include_total=true", nil, false, 1, 1},
+		// the escrow holds the coins in a wallet of its own
+		1: {"/wallets/page?include_total=true", nil, false, 3, 3},
+		2: {"/tokens/page?include_total=true", nil, true, 0, 0},
+		3: {"/escrows/sender/page?include_total=true", sender.Permission(), false, 1, 1},
+		4: {"/escrows/recipient/page", recipient.Permission(), false, 1, -1},
+		5: {"/escrows/arbiter/page", sender.Permission(), false, 0, -1},
+		6: {"/wallets/name/page?include_total=true", []byte("recipient"), false, 1, 1},
+		7: {"/escrows/history/page?include_total=true", id, false, 0, 0},
+	}
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			path, mod := tc.path, ""
+			if i := strings.Index(path, "?"); i >= 0 {
+				path, mod = path[:i], path[i+1:]
+			}
+			h := qr.Handler(path)
+			require.NotNil(t, h)
+			page, err := h.Query(db, mod, tc.data)
+			if tc.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			page, total, ok := query.SplitTotal(page)
+			assert.Equal(t, tc.total >= 0, ok)
+			if ok {
+				assert.Equal(t, tc.total, total)
+			}
+			require.Equal(t, tc.keys, len(page))
+			if strings.HasPrefix(path, "/escrows/") && len(page) > 0 {
+				// the escrow itself, under the index key
+				assert.Equal(t, db.Get(escrowKey), page[0].Value)
+			}
+		})
+	}
+}
+
+func TestVersionQuery(t *testing.T) {
+	qr := QueryRouter()
+	h := qr.Handler(QueryVersion)
+	require.NotNil(t, h)
+
+	res, err := h.Query(store.MemStore(), "", nil)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	var info VersionInfo
+	require.NoError(t, info.Unmarshal(res[0].Value))
+	assert.Equal(t, bov.SemVer(), info.Version)
+	assert.Equal(t, uint32(21), info.SchemaVersion("escrow"))
+	assert.Equal(t, uint32(0), info.SchemaVersion("unknown"))
+
+	// the same is reported by abci info
+	abciApp, err := GenerateApp("", log.NewNopLogger())
+	require.NoError(t, err)
+	ires := abciApp.Info(abci.RequestInfo{})
+	assert.Equal(t, bov.SemVer(), ires.Version)
+	var fromInfo VersionInfo
+	require.NoError(t, json.Unmarshal([]byte(ires.Data), &fromInfo))
+	assert.Equal(t, info, fromInfo)
+}
@@ -0,0 +1,220 @@
+package app
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/crypto"
+
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// addressTarget is where an old address moves to. perm is only
+// known if the map gives the new public key.
+type addressTarget struct {
+	addr weave.Address
+	perm weave.Permission
+}
+
+// AddressMap moves everything an address holds in genesis to a
+// new one, eg. to rotate keys when migrating to a new chain.
+// It is keyed by the old address.
+type AddressMap map[string]addressTarget
+
+// ReadAddressMap parses an address map from csv, with one
+// address per line:
+//
+//	<old hex address>,<new hex address or ed25519 public key>
+//
+// Escrow parties are permissions, so an address that is a party
+// of an escrow must be mapped to the new public key. An empty
+// line or one starting with # is ignored.
+func ReadAddressMap(r io.Reader) (AddressMap, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(AddressMap, len(records))
+	targets := make(map[string]bool, len(records))
+	for _, rec := range records {
+		old, err := hex.DecodeString(rec[0])
+		if err != nil || len(old) != weave.AddressLength {
+			return nil, fmt.Errorf("invalid address %q", rec[0])
+		}
+		bz, err := hex.DecodeString(rec[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid target %q", rec[1])
+		}
+		var target addressTarget
+		switch len(bz) {
+		case weave.AddressLength:
+			target.addr = bz
+		case 32:
+			pub := &crypto.PublicKey{Pub: &crypto.PublicKey_Ed25519{Ed25519: bz}}
+			target.perm = pub.Permission()
+			target.addr = pub.Address()
+		default:
+			return nil, fmt.Errorf("invalid target %q", rec[1])
+		}
+
+		if _, ok := res[string(old)]; ok {
+			return nil, fmt.Errorf("%X mapped twice", old)
+		}
+		if target.addr.Equals(old) {
+			return nil, fmt.Errorf("%X mapped to itself", old)
+		}
+		if targets[string(target.addr)] {
+			return nil, fmt.Errorf("two addresses mapped to %s", target.addr)
+		}
+		targets[string(target.addr)] = true
+		res[string(old)] = target
+	}
+	return res, nil
+}
+
+// RewriteReport lists what RewriteAddresses did
+type RewriteReport struct {
+	// Rewritten counts the values that were changed
+	Rewritten int
+	// Unmapped lists for every address that is not in the map
+	// where it was found
+	Unmapped map[string][]string
+	// Unused lists the addresses of the map that were not found
+	Unused []string
+}
+
+// Write prints the report, sorted by address
+func (r *RewriteReport) Write(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "rewritten: %d\n", r.Rewritten)
+	if err != nil {
+		return err
+	}
+	addrs := make([]string, 0, len(r.Unmapped))
+	for addr := range r.Unmapped {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	for _, addr := range addrs {
+		_, err := fmt.Fprintf(w, "unmapped: %s in %s\n", addr,
+			strings.Join(r.Unmapped[addr], ", "))
+		if err != nil {
+			return err
+		}
+	}
+	for _, addr := range r.Unused {
+		_, err := fmt.Fprintf(w, "unused: %s\n", addr)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RewriteAddresses applies the map to the wallets and escrows in
+// opts. Wallet names move along with the wallets. It fails if an
+// escrow party is mapped to an address only, or two wallets would
+// end up at the same address.
+func RewriteAddresses(opts weave.Options, m AddressMap) (*RewriteReport, error) {
+	report := &RewriteReport{Unmapped: make(map[string][]string)}
+	used := make(map[string]bool, len(m))
+
+	addr := func(where string, a weave.Address) (weave.Address, error) {
+		target, ok := m[string(a)]
+		if !ok {
+			report.Unmapped[a.String()] = append(report.Unmapped[a.String()], where)
+			return a, nil
+		}
+		used[string(a)] = true
+		report.Rewritten++
+		return target.addr, nil
+	}
+	perm := func(where string, p weave.Permission) (weave.Permission, error) {
+		a := p.Address()
+		target, ok := m[string(a)]
+		if ok && target.perm == nil {
+			return nil, fmt.Errorf("%s: %s must be mapped to a public key", where, a)
+		}
+		_, err := addr(where, a)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return p, nil
+		}
+		return target.perm, nil
+	}
+
+	err := namecoin.RewriteGenesis(opts, addr)
+	if err != nil {
+		return nil, err
+	}
+	err = escrow.RewriteGenesis(opts, perm, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	for old := range m {
+		if !used[old] {
+			report.Unused = append(report.Unused, weave.Address(old).String())
+		}
+	}
+	sort.Strings(report.Unused)
+	return report, nil
+}
+
+// RewriteAddressesCmd applies an address map to the genesis file in
+// home and writes the report to w. Run it after the escrows and
+// wallets of the prior chain are in genesis, as
+// `bov rewrite-addresses keys.csv`, see ReadAddressMap.
+//
+// With -strict, it fails if an address is not mapped and leaves
+// genesis as is.
+func RewriteAddressesCmd(w io.Writer, home string, args []string) error {
+	flags := flag.NewFlagSet("rewrite-addresses", flag.ExitOnError)
+	strict := flags.Bool("strict", false, "fail if an address is not mapped")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: rewrite-addresses [-strict] FILE")
+	}
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	m, err := ReadAddressMap(f)
+	if err != nil {
+		return err
+	}
+
+	return updateGenesisFile(home, func(opts weave.Options) error {
+		report, err := RewriteAddresses(opts, m)
+		if err != nil {
+			return err
+		}
+		err = report.Write(w)
+		if err != nil {
+			return err
+		}
+		if *strict && len(report.Unmapped) > 0 {
+			return fmt.Errorf("%d addresses not mapped", len(report.Unmapped))
+		}
+		return nil
+	})
+}
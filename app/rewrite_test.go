@@ -0,0 +1,127 @@
+package app
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestReadAddressMap(t *testing.T) {
+	var helpers x.TestHelpers
+	newKey, _ := helpers.MakeKey()
+	pub := hex.EncodeToString(newKey.PublicKey().GetEd25519())
+	old := strings.Repeat("12", 20)
+	other := strings.Repeat("34", 20)
+
+	m, err := ReadAddressMap(strings.NewReader(fmt.Sprintf(
+		"# rotated keys\n%s,%s\n%s,%s\n", old, pub, other, strings.Repeat("56", 20))))
+	require.NoError(t, err)
+	require.Len(t, m, 2)
+	target := m[string(mustHex(t, old))]
+	assert.Equal(t, newKey.PublicKey().Address(), target.addr)
+	assert.Equal(t, newKey.PublicKey().Permission(), target.perm)
+	assert.Nil(t, m[string(mustHex(t, other))].perm)
+
+	cases := map[string]string{
+		"bad old":     fmt.Sprintf("1234,%s\n", pub),
+		"bad new":     fmt.Sprintf("%s,1234\n", old),
+		"to itself":   fmt.Sprintf("%s,%s\n", old, old),
+		"twice":       fmt.Sprintf("%s,%s\n%s,%s\n", old, pub, old, other),
+		"same target": fmt.Sprintf("%s,%s\n%s,%s\n", old, pub, other, pub),
+		"columns":     fmt.Sprintf("%s\n", old),
+	}
+	for name, input := range cases {
+		_, err := ReadAddressMap(strings.NewReader(input))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestRewriteAddresses(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	_, c := helpers.MakeKey()
+	newA, _ := helpers.MakeKey()
+	newC := weave.NewAddress([]byte("new c"))
+
+	coins := x.Coins{&x.Coin{Whole: 10, Ticker: "FOO"}}
+	wallets := []namecoin.GenesisAccount{
+		{Address: a.Address(), Wallet: &namecoin.Wallet{Name: "alice", Coins: coins}},
+		{Address: b.Address(), Wallet: &namecoin.Wallet{Coins: coins}},
+	}
+	opts, err := namecoin.BuildGenesis(wallets, nil)
+	require.NoError(t, err)
+	esc := &escrow.Escrow{Sender: a, Arbiter: a, Recipient: b, Timeout: 100,
+		Amount: coins, Observers: [][]byte{c.Address()}}
+	require.NoError(t, escrow.AppendGenesis(opts, esc))
+	migrated, err := escrow.Migrate([]*escrow.EscrowExport{{
+		Id: escrow.SeqCondition(9), Escrow: esc, Balance: coins,
+	}}, 0)
+	require.NoError(t, err)
+	require.NoError(t, escrow.AppendMigratedGenesis(opts, migrated...))
+
+	unused := weave.NewAddress([]byte("unused"))
+	m, err := ReadAddressMap(strings.NewReader(fmt.Sprintf("%s,%X\n%s,%s\n%s,%s\n",
+		a.Address(), newA.PublicKey().GetEd25519(),
+		c.Address(), newC,
+		unused, weave.NewAddress([]byte("other")))))
+	require.NoError(t, err)
+
+	report, err := RewriteAddresses(opts, m)
+	require.NoError(t, err)
+	// wallet, two escrows with sender, arbiter and observer each
+	assert.Equal(t, 7, report.Rewritten)
+	assert.Equal(t, []string{"wallet " + b.Address().String(),
+		"escrow #1 recipient", "escrow 0000000000000009 recipient"},
+		report.Unmapped[b.Address().String()])
+	assert.Len(t, report.Unmapped, 1)
+	assert.Equal(t, []string{unused.String()}, report.Unused)
+
+	var out bytes.Buffer
+	require.NoError(t, report.Write(&out))
+	assert.Contains(t, out.String(), "rewritten: 7\n")
+	assert.Contains(t, out.String(), "unused: "+unused.String())
+
+	// the name moved along with the wallet, the escrows pay the new key
+	db := store.MemStore()
+	require.NoError(t, Initializer().FromGenesis(opts, db))
+	wallet, err := namecoin.NewWalletBucket().GetOrCreate(db, newA.PublicKey().Address())
+	require.NoError(t, err)
+	assert.Equal(t, "alice", namecoin.AsWallet(wallet).Name)
+	obj, err := namecoin.NewWalletBucket().Get(db, a.Address())
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+	for _, id := range []uint64{1, 2} {
+		obj, err := escrow.NewBucket().Get(db, escrow.SeqCondition(id))
+		require.NoError(t, err)
+		got := escrow.AsEscrow(obj)
+		assert.Equal(t, []byte(newA.PublicKey().Permission()), got.Sender)
+		assert.Equal(t, []byte(newA.PublicKey().Permission()), got.Arbiter)
+		assert.Equal(t, []byte(b), got.Recipient)
+		assert.Equal(t, [][]byte{newC}, got.Observers)
+	}
+
+	// an escrow party needs the new key
+	m, err = ReadAddressMap(strings.NewReader(fmt.Sprintf("%s,%s\n", b.Address(), newC)))
+	require.NoError(t, err)
+	_, err = RewriteAddresses(opts, m)
+	assert.Error(t, err)
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	bz, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return bz
+}
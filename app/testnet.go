@@ -0,0 +1,360 @@
+package app
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tendermint/tmlibs/log"
+	"golang.org/x/crypto/ripemd160"
+
+	"github.com/confio/weave/crypto"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/node"
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/rbac"
+)
+
+const (
+	testnetPower   = 10
+	testnetCoins   = 123456789
+	testnetEscrow  = 1000
+	testnetTimeout = 1000
+)
+
+// testnetNode holds all keys and addresses generated for
+// one validator or follower of the testnet
+type testnetNode struct {
+	name     string
+	follower bool
+	home     string
+	valKey   *crypto.PrivateKey
+	nodeKey  *crypto.PrivateKey
+	account  *crypto.PrivateKey
+	p2pPort  int
+	rpcPort  int
+	appPort  int
+}
+
+// TestnetCmd generates the home directories for a local testnet
+// of n validators. Every home contains the tendermint keys, a config
+// with all other nodes as persistent peers and one shared genesis
+// file with a funded wallet and a sample escrow per validator.
+//
+// With -f, it also generates follower homes that peer with all
+// validators, with a validator key that is not in genesis and
+// follower mode on in the bov config, see node.StartCmd. Point
+// query heavy clients at their rpc port.
+//
+// Run it as `bov testnet -v 4 -f 1 -o ./testnet`
+func TestnetCmd(logger log.Logger, args []string) error {
+	flags := flag.NewFlagSet("testnet", flag.ExitOnError)
+	num := flags.Int("v", 4, "number of validators to generate")
+	numFollowers := flags.Int("f", 0, "number of followers to generate")
+	outDir := flags.String("o", "testnet", "directory to store the node homes under")
+	chainID := flags.String("chain-id", "", "chain id (default: random test-chain-XXXXXX)")
+	ticker := flags.String("ticker", "IOV", "ticker of the token issued in genesis")
+	host := flags.String("host", "127.0.0.1", "host all nodes listen on")
+	basePort := flags.Int("base-port", 46656, "p2p port of node0, node i uses base-port+10*i")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	if *num < 1 {
+		return fmt.Errorf("need at least one validator, got %d", *num)
+	}
+	if *numFollowers < 0 {
+		return fmt.Errorf("negative number of followers %d", *numFollowers)
+	}
+	if *chainID == "" {
+		*chainID = randomChainID()
+	}
+
+	nodes := make([]*testnetNode, *num)
+	for i := range nodes {
+		port := *basePort + 10*i
+		nodes[i] = &testnetNode{
+			name:    fmt.Sprintf("node%d", i),
+			home:    filepath.Join(*outDir, fmt.Sprintf("node%d", i)),
+			valKey:  crypto.GenPrivKeyEd25519(),
+			nodeKey: crypto.GenPrivKeyEd25519(),
+			account: crypto.GenPrivKeyEd25519(),
+			p2pPort: port,
+			rpcPort: port + 1,
+			appPort: port + 2,
+		}
+	}
+
+	genesis, err := testnetGenesis(*chainID, *ticker, nodes)
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		err = node.write(genesis, *host, nodes)
+		if err != nil {
+			return err
+		}
+		logger.Info("Generated validator home", "node", node.name,
+			"home", node.home, "id", node.id())
+	}
+
+	for i := 0; i < *numFollowers; i++ {
+		port := *basePort + 10*(*num+i)
+		name := fmt.Sprintf("follower%d", i)
+		follower := &testnetNode{
+			name:     name,
+			follower: true,
+			home:     filepath.Join(*outDir, name),
+			valKey:   crypto.GenPrivKeyEd25519(),
+			nodeKey:  crypto.GenPrivKeyEd25519(),
+			p2pPort:  port,
+			rpcPort:  port + 1,
+			appPort:  port + 2,
+		}
+		err = follower.write(genesis, *host, nodes)
+		if err != nil {
+			return err
+		}
+		logger.Info("Generated follower home", "node", follower.name,
+			"home", follower.home, "id", follower.id())
+	}
+	return nil
+}
+
+// testnetGenesis builds a genesis file with all validators, a wallet
+// for each validator account and a sample escrow between them
+func testnetGenesis(chainID, ticker string, nodes []*testnetNode) ([]byte, error) {
+	n := len(nodes)
+	wallets := make([]namecoin.GenesisAccount, n)
+	escrows := make([]*escrow.Escrow, n)
+	validators := make([]tmValidator, n)
+	for i, node := range nodes {
+		wallets[i] = namecoin.GenesisAccount{
+			Address: node.account.PublicKey().Address(),
+			Wallet: &namecoin.Wallet{
+				Name:  node.name,
+				Coins: []*x.Coin{{Whole: testnetCoins, Ticker: ticker}},
+			},
+		}
+		escrows[i] = &escrow.Escrow{
+			Sender:    nodes[i].account.PublicKey().Permission(),
+			Recipient: nodes[(i+1)%n].account.PublicKey().Permission(),
+			Arbiter:   nodes[(i+2)%n].account.PublicKey().Permission(),
+			Amount:    []*x.Coin{{Whole: testnetEscrow, Ticker: ticker}},
+			Timeout:   testnetTimeout,
+			Memo:      fmt.Sprintf("testnet escrow %d", i),
+		}
+		validators[i] = tmValidator{
+			PubKey: newTmKey(node.valKey.PublicKey().GetEd25519()),
+			Power:  testnetPower,
+			Name:   node.name,
+		}
+	}
+
+	tokens := []namecoin.GenesisToken{{
+		Ticker:  ticker,
+		Name:    "Main token of this chain",
+		SigFigs: 6,
+	}}
+	opts, err := namecoin.BuildGenesis(wallets, tokens)
+	if err != nil {
+		return nil, err
+	}
+	escOpts, err := escrow.BuildGenesis(escrows)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range escOpts {
+		opts[k] = v
+	}
+	tmplOpts, err := escrow.BuildTemplatesGenesis(escrow.DefaultTemplates())
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range tmplOpts {
+		opts[k] = v
+	}
+	// the first validator administers the roles
+	roleOpts, err := rbac.BuildGenesis([]rbac.GenesisRoles{{
+		Address: nodes[0].account.PublicKey().Address(),
+		Roles:   []string{rbac.RoleAdmin, rbac.RoleIssuer},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range roleOpts {
+		opts[k] = v
+	}
+	appState, err := json.MarshalIndent(opts, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	doc := tmGenesis{
+		GenesisTime: time.Now().UTC(),
+		ChainID:     chainID,
+		Validators:  validators,
+		AppHash:     "",
+		AppState:    appState,
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// write creates the config dir of one node with all files
+// tendermint and bov need to start
+func (t *testnetNode) write(genesis []byte, host string, nodes []*testnetNode) error {
+	configDir := filepath.Join(t.home, "config")
+	err := os.MkdirAll(configDir, 0755)
+	if err != nil {
+		return err
+	}
+
+	var peers []string
+	for _, peer := range nodes {
+		if peer != t {
+			peers = append(peers, fmt.Sprintf("%s@%s:%d", peer.id(), host, peer.p2pPort))
+		}
+	}
+	config := fmt.Sprintf(testnetConfig, host, t.appPort, t.name,
+		host, t.rpcPort, host, t.p2pPort, strings.Join(peers, ","))
+
+	valPub := t.valKey.PublicKey().GetEd25519()
+	privVal := tmPrivValidator{
+		Address: strings.ToUpper(hex.EncodeToString(tmAddress(valPub))),
+		PubKey:  newTmKey(valPub),
+		PrivKey: newTmKey(t.valKey.GetEd25519()),
+	}
+	nodeKey := tmNodeKey{
+		PrivKey: newTmKey(t.nodeKey.GetEd25519()),
+	}
+
+	type file struct {
+		name string
+		data interface{}
+	}
+	files := []file{
+		{"priv_validator.json", privVal},
+		{"node_key.json", nodeKey},
+	}
+	if t.follower {
+		cfg := node.DefaultConfig()
+		cfg.Follower = true
+		files = append(files, file{filepath.Base(node.ConfigFile), cfg})
+	} else {
+		files = append(files, file{"account.json", output{
+			Pubkey: t.account.PublicKey(),
+			Secret: t.account,
+		}})
+	}
+	for _, f := range files {
+		bz, err := json.MarshalIndent(f.data, "", "  ")
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(filepath.Join(configDir, f.name), bz, 0600)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = ioutil.WriteFile(filepath.Join(configDir, "genesis.json"), genesis, 0644)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(configDir, "config.toml"), []byte(config), 0644)
+}
+
+// id returns the p2p node id, as used in persistent_peers
+func (t *testnetNode) id() string {
+	return hex.EncodeToString(tmAddress(t.nodeKey.PublicKey().GetEd25519()))
+}
+
+// tmAddress calculates the address tendermint uses for an
+// ed25519 public key (ripemd160 of the go-wire encoded key)
+func tmAddress(pubKey []byte) []byte {
+	hasher := ripemd160.New()
+	// go-wire prefix: type byte for ed25519 and byte slice length
+	hasher.Write([]byte{0x01, 0x01, byte(len(pubKey))})
+	hasher.Write(pubKey)
+	return hasher.Sum(nil)
+}
+
+func randomChainID() string {
+	bz := make([]byte, 3)
+	_, err := rand.Read(bz)
+	if err != nil {
+		panic(err)
+	}
+	return "test-chain-" + hex.EncodeToString(bz)
+}
+
+// tmKey is the json representation of a tendermint key
+type tmKey struct {
+	Type string `json:"type"`
+	Data string `json:"data"`
+}
+
+func newTmKey(bz []byte) tmKey {
+	return tmKey{Type: "ed25519", Data: strings.ToUpper(hex.EncodeToString(bz))}
+}
+
+type tmValidator struct {
+	PubKey tmKey  `json:"pub_key"`
+	Power  int64  `json:"power"`
+	Name   string `json:"name"`
+}
+
+type tmGenesis struct {
+	GenesisTime time.Time       `json:"genesis_time"`
+	ChainID     string          `json:"chain_id"`
+	Validators  []tmValidator   `json:"validators"`
+	AppHash     string          `json:"app_hash"`
+	AppState    json.RawMessage `json:"app_state"`
+}
+
+type tmPrivValidator struct {
+	Address       string          `json:"address"`
+	PubKey        tmKey           `json:"pub_key"`
+	LastHeight    int64           `json:"last_height"`
+	LastRound     int             `json:"last_round"`
+	LastStep      int8            `json:"last_step"`
+	LastSignature json.RawMessage `json:"last_signature"`
+	PrivKey       tmKey           `json:"priv_key"`
+}
+
+type tmNodeKey struct {
+	PrivKey tmKey `json:"priv_key"`
+}
+
+// testnetConfig only sets the values that differ between nodes,
+// everything else uses the tendermint defaults
+const testnetConfig = `# This is a TOML config file.
+# For more information, see https://github.com/toml-lang/toml
+
+proxy_app = "tcp://%s:%d"
+moniker = "%s"
+fast_sync = true
+db_backend = "leveldb"
+log_level = "main:info,state:info,*:error"
+
+[rpc]
+laddr = "tcp://%s:%d"
+
+[p2p]
+laddr = "tcp://%s:%d"
+persistent_peers = "%s"
+addr_book_strict = false
+
+[tx_index]
+indexer = "kv"
+`
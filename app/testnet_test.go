@@ -0,0 +1,100 @@
+package app
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tmlibs/log"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/commands/server"
+	"github.com/confio/weave/store"
+
+	"github.com/iov-one/bcp-demo/node"
+)
+
+func TestTmAddress(t *testing.T) {
+	// taken from cmd/bov/testdata/priv_validator.json
+	pub, err := hex.DecodeString("C92B4BF37A4A84E763511E2D3E838C0D4DCC5C34C9D9B068822A71F519C40C93")
+	require.NoError(t, err)
+	addr := strings.ToUpper(hex.EncodeToString(tmAddress(pub)))
+	assert.Equal(t, "E5D0E5E1F272481F1DA6F05B793F731C7135A8B6", addr)
+}
+
+func TestTestnetCmd(t *testing.T) {
+	outDir, err := ioutil.TempDir("", "bov-testnet")
+	require.NoError(t, err)
+	defer os.RemoveAll(outDir)
+
+	n := 3
+	args := []string{"-v", fmt.Sprintf("%d", n), "-o", outDir,
+		"-chain-id", "my-testnet", "-ticker", "ETH", "-f", "1"}
+	err = TestnetCmd(log.NewNopLogger(), args)
+	require.NoError(t, err)
+
+	var genesis []byte
+	for i := 0; i < n; i++ {
+		configDir := filepath.Join(outDir, fmt.Sprintf("node%d", i), "config")
+		for _, f := range []string{"priv_validator.json", "node_key.json",
+			"account.json", "config.toml"} {
+			_, err := os.Stat(filepath.Join(configDir, f))
+			assert.NoError(t, err, f)
+		}
+
+		// all nodes share the same genesis file
+		bz, err := ioutil.ReadFile(filepath.Join(configDir, "genesis.json"))
+		require.NoError(t, err)
+		if genesis == nil {
+			genesis = bz
+		}
+		assert.Equal(t, genesis, bz)
+
+		// every node peers with all others
+		config, err := ioutil.ReadFile(filepath.Join(configDir, "config.toml"))
+		require.NoError(t, err)
+		assert.Contains(t, string(config), fmt.Sprintf(`moniker = "node%d"`, i))
+		assert.Equal(t, n-1, strings.Count(string(config), "@127.0.0.1:"))
+	}
+
+	// the follower peers with all validators, but is none of them
+	followerDir := filepath.Join(outDir, "follower0", "config")
+	bz, err := ioutil.ReadFile(filepath.Join(followerDir, "genesis.json"))
+	require.NoError(t, err)
+	assert.Equal(t, genesis, bz)
+	config, err := ioutil.ReadFile(filepath.Join(followerDir, "config.toml"))
+	require.NoError(t, err)
+	assert.Equal(t, n, strings.Count(string(config), "@127.0.0.1:"))
+	cfg, err := node.LoadConfig(filepath.Join(outDir, "follower0", node.ConfigFile))
+	require.NoError(t, err)
+	assert.True(t, cfg.Follower)
+	_, err = os.Stat(filepath.Join(followerDir, "account.json"))
+	assert.True(t, os.IsNotExist(err))
+
+	var doc server.GenesisDoc
+	err = json.Unmarshal(genesis, &doc)
+	require.NoError(t, err)
+	assert.EqualValues(t, `"my-testnet"`, doc["chain_id"])
+
+	var vals []tmValidator
+	err = json.Unmarshal(doc["validators"], &vals)
+	require.NoError(t, err)
+	assert.Equal(t, n, len(vals))
+
+	// the app state must be accepted by the app initializers
+	var opts weave.Options
+	err = json.Unmarshal(doc[server.AppStateKey], &opts)
+	require.NoError(t, err)
+	for _, key := range []string{"wallets", "tokens", "escrow", "escrow_templates", "roles"} {
+		assert.Contains(t, opts, key)
+	}
+	err = Initializer().FromGenesis(opts, store.MemStore())
+	require.NoError(t, err)
+}
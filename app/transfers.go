@@ -0,0 +1,73 @@
+package app
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/escrow"
+)
+
+// Transfers returns the coins a message moves to someone else:
+// the amount of a send, and what an escrow settlement pays out.
+// A release without an amount and a netting count all that the
+// escrows hold. It is the travelrule.AmountFunc of this app.
+func Transfers(db weave.ReadOnlyKVStore, msg weave.Msg) (x.Coins, error) {
+	switch m := msg.(type) {
+	case *cash.SendMsg:
+		if m.Amount == nil {
+			return nil, nil
+		}
+		return x.Coins{m.Amount}, nil
+	case *escrow.ReleaseEscrowMsg:
+		if len(m.Amount) > 0 {
+			return m.Amount, nil
+		}
+		return escrowAmount(db, m.EscrowId)
+	case *escrow.ForceSettleEscrowMsg:
+		return m.Release, nil
+	case *escrow.AttestMilestoneMsg:
+		esc, err := loadEscrow(db, m.EscrowId)
+		if esc == nil || err != nil {
+			return nil, err
+		}
+		if m.Milestone < 0 || int(m.Milestone) >= len(esc.Milestones) {
+			// the message fails below
+			return nil, nil
+		}
+		return esc.Milestones[m.Milestone].Amount, nil
+	case *escrow.NetEscrowsMsg:
+		var total x.Coins
+		for _, id := range m.EscrowIds {
+			amount, err := escrowAmount(db, id)
+			if err != nil {
+				return nil, err
+			}
+			total, err = total.Combine(amount)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return total, nil
+	}
+	return nil, nil
+}
+
+// escrowAmount returns what the escrow holds, none if it
+// does not exist
+func escrowAmount(db weave.ReadOnlyKVStore, id []byte) (x.Coins, error) {
+	esc, err := loadEscrow(db, id)
+	if esc == nil || err != nil {
+		return nil, err
+	}
+	return esc.Amount, nil
+}
+
+// loadEscrow returns the escrow, nil if it does not exist
+func loadEscrow(db weave.ReadOnlyKVStore, id []byte) (*escrow.Escrow, error) {
+	obj, err := escrow.NewBucket().Get(db, id)
+	if err != nil {
+		return nil, err
+	}
+	return escrow.AsEscrow(obj), nil
+}
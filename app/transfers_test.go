@@ -0,0 +1,59 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iov-one/bcp-demo/x/escrow"
+)
+
+func TestTransfers(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	coin := func(n int64) *x.Coin {
+		return &x.Coin{Whole: n, Ticker: "FOO"}
+	}
+
+	db := store.MemStore()
+	bucket := escrow.NewBucket()
+	esc := &escrow.Escrow{Sender: a, Arbiter: a, Recipient: b, Timeout: 100,
+		Amount: x.Coins{coin(10)}, Milestones: []*escrow.Milestone{
+			{Name: "design", Amount: x.Coins{coin(4)}},
+			{Name: "build", Amount: x.Coins{coin(6)}},
+		}}
+	first, err := bucket.Create(db, esc)
+	require.NoError(t, err)
+	second, err := bucket.Create(db, &escrow.Escrow{Sender: a, Arbiter: a, Recipient: b,
+		Timeout: 100, Amount: x.Coins{coin(5)}})
+	require.NoError(t, err)
+	missing := []byte("missing")
+
+	cases := []struct {
+		msg      weave.Msg
+		expected x.Coins
+	}{
+		{&cash.SendMsg{Amount: coin(3)}, x.Coins{coin(3)}},
+		{&escrow.ReleaseEscrowMsg{EscrowId: first.Key(), Amount: x.Coins{coin(2)}}, x.Coins{coin(2)}},
+		// a release without amount pays all
+		{&escrow.ReleaseEscrowMsg{EscrowId: first.Key()}, x.Coins{coin(10)}},
+		{&escrow.ReleaseEscrowMsg{EscrowId: missing}, nil},
+		{&escrow.ForceSettleEscrowMsg{EscrowId: first.Key(), Release: x.Coins{coin(7)}}, x.Coins{coin(7)}},
+		{&escrow.AttestMilestoneMsg{EscrowId: first.Key(), Milestone: 0}, x.Coins{coin(4)}},
+		{&escrow.AttestMilestoneMsg{EscrowId: first.Key(), Milestone: 2}, nil},
+		{&escrow.NetEscrowsMsg{EscrowIds: [][]byte{first.Key(), second.Key()}}, x.Coins{coin(15)}},
+		// the others transfer nothing
+		{&escrow.ReturnEscrowMsg{EscrowId: first.Key()}, nil},
+	}
+	for i, tc := range cases {
+		amount, err := Transfers(db, tc.msg)
+		require.NoError(t, err, "case %d", i)
+		assert.Equal(t, tc.expected, amount, "case %d", i)
+	}
+}
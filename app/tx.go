@@ -6,9 +6,82 @@ import (
 	"github.com/confio/weave/x/cash"
 	"github.com/confio/weave/x/sigs"
 
+	"github.com/iov-one/bcp-demo/x/anymsg"
+	"github.com/iov-one/bcp-demo/x/confidential"
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/faucet"
+	"github.com/iov-one/bcp-demo/x/features"
+	"github.com/iov-one/bcp-demo/x/feepool"
+	"github.com/iov-one/bcp-demo/x/grant"
 	"github.com/iov-one/bcp-demo/x/hashlock"
+	"github.com/iov-one/bcp-demo/x/keys"
+	"github.com/iov-one/bcp-demo/x/limits"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/oracle"
+	"github.com/iov-one/bcp-demo/x/rbac"
+	"github.com/iov-one/bcp-demo/x/scheduler"
+	"github.com/iov-one/bcp-demo/x/session"
+	"github.com/iov-one/bcp-demo/x/trade"
 )
 
+// all messages of the app can also be sent in an anymsg.Any.
+// New messages only need to be added here.
+func init() {
+	anymsg.Register(
+		&cash.SendMsg{},
+		&namecoin.NewTokenMsg{},
+		&namecoin.SetWalletNameMsg{},
+		&namecoin.SellNameMsg{},
+		&namecoin.BuyNameMsg{},
+		&namecoin.CancelNameSaleMsg{},
+		&namecoin.UpdateWalletMetadataMsg{},
+		&escrow.CreateEscrowMsg{},
+		&escrow.CreateEscrowMsgV2{},
+		&escrow.ReleaseEscrowMsg{},
+		&escrow.ReturnEscrowMsg{},
+		&escrow.UpdateEscrowPartiesMsg{},
+		&escrow.BidArbitrationMsg{},
+		&escrow.AssignArbiterMsg{},
+		&escrow.UpdateEscrowObserversMsg{},
+		&escrow.RevealMemoMsg{},
+		&escrow.NetEscrowsMsg{},
+		&escrow.SetArbiterPolicyMsg{},
+		&oracle.SetPriceMsg{},
+		&rbac.AssignRoleMsg{},
+		&rbac.RevokeRoleMsg{},
+		&grant.CreateGrantMsg{},
+		&grant.RevokeGrantMsg{},
+		&session.CreateSessionMsg{},
+		&session.RevokeSessionMsg{},
+		&features.SetFeatureMsg{},
+		&trade.CreateOrderMsg{},
+		&trade.FillOrderMsg{},
+		&trade.CancelOrderMsg{},
+		&feepool.SetConversionMsg{},
+		&confidential.ShieldMsg{},
+		&confidential.UnshieldMsg{},
+		&confidential.SetViewKeyMsg{},
+		&confidential.CreateEscrowMsg{},
+		&confidential.ReleaseEscrowMsg{},
+		&confidential.ReturnEscrowMsg{},
+		&confidential.GrantViewMsg{},
+		&faucet.TapMsg{},
+		&escrow.CreateFromTemplateMsg{},
+		&escrow.SetTemplateMsg{},
+		&escrow.PingEscrowMsg{},
+		&escrow.SendEscrowMsg{},
+		&escrow.QuarantineEscrowMsg{},
+		&escrow.RestoreEscrowMsg{},
+		&escrow.ForceSettleEscrowMsg{},
+		&escrow.ClawbackEscrowMsg{},
+		&escrow.AttestMilestoneMsg{},
+		&escrow.OfferEscrowPartyMsg{},
+		&escrow.AcceptEscrowPartyMsg{},
+		&escrow.DisputeEscrowMsg{},
+		&scheduler.RedriveJobMsg{},
+	)
+}
+
 //-------------------------------
 // copied from weave/app verbatim
 //
@@ -16,11 +89,17 @@ import (
 
 // TxDecoder creates a Tx and unmarshals bytes into it
 func TxDecoder(bz []byte) (weave.Tx, error) {
+	if err := limits.CheckEncoded(bz, nil); err != nil {
+		return nil, err
+	}
 	tx := new(Tx)
 	err := tx.Unmarshal(bz)
 	if err != nil {
 		return nil, err
 	}
+	if err := limits.CheckEncoded(bz, tx); err != nil {
+		return nil, err
+	}
 	return tx, nil
 }
 
@@ -29,6 +108,7 @@ var _ weave.Tx = (*Tx)(nil)
 var _ cash.FeeTx = (*Tx)(nil)
 var _ sigs.SignedTx = (*Tx)(nil)
 var _ hashlock.HashKeyTx = (*Tx)(nil)
+var _ keys.SignedTx = (*Tx)(nil)
 
 // GetMsg switches over all types defined in the protobuf file
 func (tx *Tx) GetMsg() (weave.Msg, error) {
@@ -53,6 +133,96 @@ func (tx *Tx) GetMsg() (weave.Msg, error) {
 		return t.ReturnEscrowMsg, nil
 	case *Tx_UpdateEscrowMsg:
 		return t.UpdateEscrowMsg, nil
+	case *Tx_SetPriceMsg:
+		return t.SetPriceMsg, nil
+	case *Tx_AssignRoleMsg:
+		return t.AssignRoleMsg, nil
+	case *Tx_RevokeRoleMsg:
+		return t.RevokeRoleMsg, nil
+	case *Tx_CreateGrantMsg:
+		return t.CreateGrantMsg, nil
+	case *Tx_RevokeGrantMsg:
+		return t.RevokeGrantMsg, nil
+	case *Tx_CreateSessionMsg:
+		return t.CreateSessionMsg, nil
+	case *Tx_RevokeSessionMsg:
+		return t.RevokeSessionMsg, nil
+	case *Tx_SellNameMsg:
+		return t.SellNameMsg, nil
+	case *Tx_BuyNameMsg:
+		return t.BuyNameMsg, nil
+	case *Tx_CancelNameSaleMsg:
+		return t.CancelNameSaleMsg, nil
+	case *Tx_UpdateMetadataMsg:
+		return t.UpdateMetadataMsg, nil
+	case *Tx_BidArbitrationMsg:
+		return t.BidArbitrationMsg, nil
+	case *Tx_AssignArbiterMsg:
+		return t.AssignArbiterMsg, nil
+	case *Tx_CreateEscrowMsgV2:
+		return t.CreateEscrowMsgV2, nil
+	case *Tx_AnyMsg:
+		return t.AnyMsg.Unpack()
+	case *Tx_UpdateObserversMsg:
+		return t.UpdateObserversMsg, nil
+	case *Tx_SetFeatureMsg:
+		return t.SetFeatureMsg, nil
+	case *Tx_NetEscrowsMsg:
+		return t.NetEscrowsMsg, nil
+	case *Tx_SetArbiterPolicyMsg:
+		return t.SetArbiterPolicyMsg, nil
+	case *Tx_CreateOrderMsg:
+		return t.CreateOrderMsg, nil
+	case *Tx_FillOrderMsg:
+		return t.FillOrderMsg, nil
+	case *Tx_CancelOrderMsg:
+		return t.CancelOrderMsg, nil
+	case *Tx_SetConversionMsg:
+		return t.SetConversionMsg, nil
+	case *Tx_RevealMemoMsg:
+		return t.RevealMemoMsg, nil
+	case *Tx_ShieldMsg:
+		return t.ShieldMsg, nil
+	case *Tx_UnshieldMsg:
+		return t.UnshieldMsg, nil
+	case *Tx_SetViewKeyMsg:
+		return t.SetViewKeyMsg, nil
+	case *Tx_CreateConfidentialEscrowMsg:
+		return t.CreateConfidentialEscrowMsg, nil
+	case *Tx_ReleaseConfidentialEscrowMsg:
+		return t.ReleaseConfidentialEscrowMsg, nil
+	case *Tx_ReturnConfidentialEscrowMsg:
+		return t.ReturnConfidentialEscrowMsg, nil
+	case *Tx_GrantViewMsg:
+		return t.GrantViewMsg, nil
+	case *Tx_TapMsg:
+		return t.TapMsg, nil
+	case *Tx_CreateFromTemplateMsg:
+		return t.CreateFromTemplateMsg, nil
+	case *Tx_SetTemplateMsg:
+		return t.SetTemplateMsg, nil
+	case *Tx_PingEscrowMsg:
+		return t.PingEscrowMsg, nil
+	case *Tx_SendEscrowMsg:
+		return t.SendEscrowMsg, nil
+	case *Tx_QuarantineEscrowMsg:
+		return t.QuarantineEscrowMsg, nil
+	case *Tx_RestoreEscrowMsg:
+		return t.RestoreEscrowMsg, nil
+	case *Tx_ForceSettleEscrowMsg:
+		return t.ForceSettleEscrowMsg, nil
+	case *Tx_ClawbackEscrowMsg:
+		return t.ClawbackEscrowMsg, nil
+	case *Tx_AttestMilestoneMsg:
+		return t.AttestMilestoneMsg, nil
+	case *Tx_OfferEscrowPartyMsg:
+		return t.OfferEscrowPartyMsg, nil
+	case *Tx_AcceptEscrowPartyMsg:
+		return t.AcceptEscrowPartyMsg, nil
+	case *Tx_DisputeEscrowMsg:
+		return t.DisputeEscrowMsg, nil
+	case *Tx_RedriveJobMsg:
+		return t.RedriveJobMsg, nil
 	}
 
 	// we must have covered it above
@@ -64,12 +234,12 @@ func (tx *Tx) GetMsg() (weave.Msg, error) {
 func (tx *Tx) GetSignBytes() ([]byte, error) {
 	// temporarily unset the signatures, as the sign bytes
 	// should only come from the data itself, not previous signatures
-	sigs := tx.Signatures
-	tx.Signatures = nil
+	sigs, keySigs := tx.Signatures, tx.KeySignatures
+	tx.Signatures, tx.KeySignatures = nil, nil
 
 	bz, err := tx.Marshal()
 
 	// reset the signatures after calculating the bytes
-	tx.Signatures = sigs
+	tx.Signatures, tx.KeySignatures = sigs, keySigs
 	return bz, err
 }
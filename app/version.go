@@ -0,0 +1,71 @@
+package app
+
+import (
+	"encoding/json"
+
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave"
+
+	bov "github.com/iov-one/bcp-demo"
+)
+
+// QueryVersion is the path of the VersionQuery
+const QueryVersion = "/version"
+
+// Schemas is the version of the state and message format of
+// every module, see module.Module
+var Schemas = Modules(Authenticator()).Schemas()
+
+// NewVersionInfo describes this build of the app
+func NewVersionInfo() *VersionInfo {
+	return &VersionInfo{
+		Version: bov.SemVer(),
+		Build:   bov.Version,
+		Modules: Schemas,
+	}
+}
+
+// SchemaVersion returns the schema version of the module,
+// 0 if the app doesn't have it
+func (v *VersionInfo) SchemaVersion(module string) uint32 {
+	for _, m := range v.GetModules() {
+		if m.Name == module {
+			return m.Version
+		}
+	}
+	return 0
+}
+
+// VersionQuery returns the VersionInfo, whatever the data
+type VersionQuery struct{}
+
+var _ weave.QueryHandler = VersionQuery{}
+
+// RegisterVersionQuery adds the VersionQuery to the router
+func RegisterVersionQuery(qr weave.QueryRouter) {
+	qr.Register(QueryVersion, VersionQuery{})
+}
+
+// Query implements weave.QueryHandler
+func (VersionQuery) Query(db weave.ReadOnlyKVStore, mod string,
+	data []byte) ([]weave.Model, error) {
+
+	bz, err := NewVersionInfo().Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return []weave.Model{{Key: []byte("version"), Value: bz}}, nil
+}
+
+// Info adds the semantic version to the ABCI Info, and the
+// VersionInfo as json to its data
+func (a App) Info(req abci.RequestInfo) abci.ResponseInfo {
+	res := a.BaseApp.Info(req)
+	res.Version = bov.SemVer()
+	bz, err := json.Marshal(NewVersionInfo())
+	if err == nil {
+		res.Data = string(bz)
+	}
+	return res
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"github.com/confio/weave"
+
+	"github.com/iov-one/bcp-demo/node"
+	"github.com/iov-one/bcp-demo/views"
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+const (
+	// ViewEscrowsByRecipient lists the open escrows for the
+	// recipient address given as data
+	ViewEscrowsByRecipient = "/views/escrows/recipient"
+	// ViewBalances returns the wallet of the address given as
+	// data, if the node is configured to keep it
+	ViewBalances = "/views/balances"
+)
+
+// Views returns the views enabled in cfg, nil if there are none
+func Views(cfg node.ViewsConfig) *views.Set {
+	if !cfg.EscrowsByRecipient && len(cfg.Balances) == 0 {
+		return nil
+	}
+	set := views.NewSet()
+	if cfg.EscrowsByRecipient {
+		set.Add(ViewEscrowsByRecipient, views.NewView(EscrowsByRecipient))
+	}
+	if len(cfg.Balances) > 0 {
+		set.Add(ViewBalances, views.NewView(Balances(cfg.Balances)))
+	}
+	return set
+}
+
+// EscrowsByRecipient indexes all open escrows by the address of
+// their recipient, in the order of their ids
+func EscrowsByRecipient(db weave.ReadOnlyKVStore) (views.Index, error) {
+	index := views.Index{}
+	err := views.Scan(db, escrow.NewBucket().Bucket, func(m weave.Model) error {
+		var esc escrow.Escrow
+		err := esc.Unmarshal(m.Value)
+		if err != nil {
+			return err
+		}
+		rcpt := string(weave.Permission(esc.Recipient).Address())
+		index[rcpt] = append(index[rcpt], m)
+		return nil
+	})
+	return index, err
+}
+
+// Balances indexes the wallets of the given addresses. An
+// address without a wallet has no entry.
+func Balances(addrs []weave.Address) views.Builder {
+	bucket := namecoin.NewWalletBucket()
+	return func(db weave.ReadOnlyKVStore) (views.Index, error) {
+		index := make(views.Index, len(addrs))
+		for _, addr := range addrs {
+			key := bucket.DBKey(addr)
+			if value := db.Get(key); value != nil {
+				index[string(addr)] = []weave.Model{{Key: key, Value: value}}
+			}
+		}
+		return index, nil
+	}
+}
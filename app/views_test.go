@@ -0,0 +1,88 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/node"
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestViews(t *testing.T) {
+	assert.Nil(t, Views(node.ViewsConfig{}))
+
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	_, c := helpers.MakeKey()
+	coins := x.Coins{&x.Coin{Whole: 10, Ticker: "FOO"}}
+
+	opts, err := namecoin.BuildGenesis([]namecoin.GenesisAccount{
+		{Address: a.Address(), Wallet: &namecoin.Wallet{Name: "alice", Coins: coins}},
+	}, []namecoin.GenesisToken{{Ticker: "FOO", Name: "Foo", SigFigs: 6}})
+	require.NoError(t, err)
+	require.NoError(t, escrow.AppendGenesis(opts,
+		&escrow.Escrow{Sender: a, Arbiter: a, Recipient: b, Timeout: 100, Amount: coins},
+		&escrow.Escrow{Sender: a, Arbiter: a, Recipient: c, Timeout: 100, Amount: coins},
+		&escrow.Escrow{Sender: c, Arbiter: a, Recipient: b, Timeout: 100, Amount: coins},
+	))
+	state, err := json.Marshal(opts)
+	require.NoError(t, err)
+	genesis, err := json.Marshal(map[string]json.RawMessage{
+		"chain_id":  json.RawMessage(`"test-views"`),
+		"app_state": state,
+	})
+	require.NoError(t, err)
+
+	set := Views(node.ViewsConfig{
+		EscrowsByRecipient: true,
+		Balances:           []weave.Address{a.Address(), b.Address()},
+	})
+	require.NotNil(t, set)
+	myApp, err := Application("views", Stack(x.Coin{}, 0), TxDecoder, "", "", set)
+	require.NoError(t, err)
+	myApp.WithInit(Initializer())
+
+	query := func(path string, data []byte) [][]byte {
+		res := myApp.Query(abci.RequestQuery{Path: path, Data: data})
+		require.Equal(t, uint32(0), res.Code, res.Log)
+		var keys app.ResultSet
+		require.NoError(t, keys.Unmarshal(res.Key))
+		return keys.Results
+	}
+
+	// nothing until the first commit
+	assert.Empty(t, query(ViewBalances, a.Address()))
+
+	myApp.InitChainWithGenesis(abci.RequestInitChain{}, genesis)
+	myApp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 1}})
+	myApp.EndBlock(abci.RequestEndBlock{})
+	myApp.Commit()
+
+	bucket := escrow.NewBucket()
+	assert.Equal(t, [][]byte{bucket.DBKey(escrow.SeqCondition(1)), bucket.DBKey(escrow.SeqCondition(3))},
+		query(ViewEscrowsByRecipient, b.Address()))
+	assert.Equal(t, [][]byte{bucket.DBKey(escrow.SeqCondition(2))},
+		query(ViewEscrowsByRecipient, c.Address()))
+	assert.Empty(t, query(ViewEscrowsByRecipient, a.Address()))
+
+	// the view returns the same wallet as the store
+	assert.Equal(t, [][]byte{namecoin.NewWalletBucket().DBKey(a.Address())},
+		query(ViewBalances, a.Address()))
+	assert.Equal(t, query("/wallets", a.Address()), query(ViewBalances, a.Address()))
+	assert.Empty(t, query(ViewBalances, b.Address()))
+	// c is not configured
+	assert.Empty(t, query(ViewBalances, c.Address()))
+
+	res := myApp.Query(abci.RequestQuery{Path: ViewBalances + "?prefix", Data: a.Address()})
+	assert.NotEqual(t, uint32(0), res.Code)
+}
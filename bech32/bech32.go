@@ -0,0 +1,163 @@
+/*
+Package bech32 encodes bytes as BIP-173 strings, eg. escrow ids as
+"esc1qqqqqqqqqqqqzjwh4xz". The human readable prefix names what
+is encoded, the checksum catches any typo of up to four characters,
+and the result is lowercase only, so it is easy to read out and
+copy between tools.
+*/
+package bech32
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	// maxLength is the longest string Decode accepts
+	maxLength = 90
+	// checksumLength is the number of characters of the checksum
+	checksumLength = 6
+)
+
+var generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+// Encode returns the bech32 string of data with the prefix hrp,
+// which must be lowercase
+func Encode(hrp string, data []byte) string {
+	values := toBase32(data)
+	values = append(values, checksum(hrp, values)...)
+
+	var b strings.Builder
+	b.Grow(len(hrp) + 1 + len(values))
+	b.WriteString(hrp)
+	b.WriteByte('1')
+	for _, v := range values {
+		b.WriteByte(charset[v])
+	}
+	return b.String()
+}
+
+// Decode returns the prefix and data of a bech32 string. It must
+// be all lowercase or all uppercase, the prefix is returned in
+// lowercase.
+func Decode(s string) (string, []byte, error) {
+	if len(s) > maxLength {
+		return "", nil, fmt.Errorf("bech32: longer than %d characters", maxLength)
+	}
+	var hasLower, hasUpper bool
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c < 33 || c > 126:
+			return "", nil, fmt.Errorf("bech32: invalid character %q", c)
+		case c >= 'a' && c <= 'z':
+			hasLower = true
+		case c >= 'A' && c <= 'Z':
+			hasUpper = true
+		}
+	}
+	if hasLower && hasUpper {
+		return "", nil, fmt.Errorf("bech32: mixed case")
+	}
+	lower := strings.ToLower(s)
+	sep := strings.LastIndexByte(lower, '1')
+	if sep < 1 || sep+checksumLength+1 > len(lower) {
+		return "", nil, fmt.Errorf("bech32: no prefix or checksum")
+	}
+	hrp := lower[:sep]
+
+	values := make([]byte, 0, len(lower)-sep-1)
+	for i := sep + 1; i < len(lower); i++ {
+		v := strings.IndexByte(charset, lower[i])
+		if v < 0 {
+			return "", nil, fmt.Errorf("bech32: invalid character %q", lower[i])
+		}
+		values = append(values, byte(v))
+	}
+	if polymod(append(expandPrefix(hrp), values...)) != 1 {
+		return "", nil, fmt.Errorf("bech32: invalid checksum")
+	}
+	data, err := fromBase32(values[:len(values)-checksumLength])
+	if err != nil {
+		return "", nil, err
+	}
+	return hrp, data, nil
+}
+
+func polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i, g := range generator {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= g
+			}
+		}
+	}
+	return chk
+}
+
+// expandPrefix spreads the prefix over 5 bit values for the checksum
+func expandPrefix(hrp string) []byte {
+	res := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		res = append(res, hrp[i]>>5)
+	}
+	res = append(res, 0)
+	for i := 0; i < len(hrp); i++ {
+		res = append(res, hrp[i]&31)
+	}
+	return res
+}
+
+func checksum(hrp string, values []byte) []byte {
+	in := append(expandPrefix(hrp), values...)
+	in = append(in, make([]byte, checksumLength)...)
+	mod := polymod(in) ^ 1
+	res := make([]byte, checksumLength)
+	for i := range res {
+		res[i] = byte(mod>>uint(5*(5-i))) & 31
+	}
+	return res
+}
+
+// toBase32 splits bytes into 5 bit values, padding the last one
+func toBase32(data []byte) []byte {
+	res := make([]byte, 0, (len(data)*8+4)/5)
+	var acc uint32
+	var bits uint
+	for _, b := range data {
+		acc = acc<<8 | uint32(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			res = append(res, byte(acc>>bits)&31)
+		}
+	}
+	if bits > 0 {
+		res = append(res, byte(acc<<(5-bits))&31)
+	}
+	return res
+}
+
+// fromBase32 joins 5 bit values to bytes. The padding must be
+// less than a byte and all zero, so every string has one meaning.
+func fromBase32(values []byte) ([]byte, error) {
+	res := make([]byte, 0, len(values)*5/8)
+	var acc uint32
+	var bits uint
+	for _, v := range values {
+		acc = acc<<5 | uint32(v)
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			res = append(res, byte(acc>>bits))
+		}
+	}
+	if bits >= 5 || acc&(1<<bits-1) != 0 {
+		return nil, fmt.Errorf("bech32: invalid padding")
+	}
+	return res, nil
+}
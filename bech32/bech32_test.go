@@ -0,0 +1,67 @@
+package bech32
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	// the vectors of BIP-173
+	valid := []string{
+		"A12UEL5L",
+		"a12uel5l",
+		"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs",
+		"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+		"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w",
+		"?1ezyfcl",
+	}
+	for _, s := range valid {
+		hrp, data, err := Decode(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, strings.ToLower(s), Encode(hrp, data))
+	}
+
+	invalid := []string{
+		"\x201nwldj5",
+		"\x7f1axkwrx",
+		"an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx",
+		"pzry9x0s0muk",
+		"1pzry9x0s0muk",
+		"x1b4n0q5v",
+		"li1dgmt3",
+		"de1lg7wt\xff",
+		"A1G7SGD8",
+		"10a06t8",
+		"1qzzfhee",
+		"aBcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw",
+	}
+	for _, s := range invalid {
+		_, _, err := Decode(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	cases := [][]byte{
+		nil,
+		{0},
+		{0, 0, 0, 0, 0, 0, 0, 1},
+		{0xff, 0xfe, 0xfd, 0xfc, 0xfb},
+	}
+	for _, data := range cases {
+		s := Encode("esc", data)
+		hrp, got, err := Decode(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, "esc", hrp)
+		assert.Equal(t, len(data), len(got))
+		assert.Equal(t, string(data), string(got))
+	}
+	assert.Equal(t, "esc1qqqqqqqqqqqqzjwh4xz", Encode("esc", []byte{0, 0, 0, 0, 0, 0, 0, 1}))
+
+	// a typo is caught by the checksum
+	_, _, err := Decode("esc1qqqqqqqqqqqqzjwh4xy")
+	assert.Error(t, err)
+}
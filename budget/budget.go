@@ -0,0 +1,86 @@
+/*
+Package budget bounds the work the tickers do at the start of a
+block, so a mass expiry can't stall the chain for seconds.
+
+The app gives every block a budget of gas with With, shared by all
+tickers in the order they run. A ticker takes the gas of each unit
+of work, eg. an escrow it releases, with Take before it starts on
+it, and stops once Take fails. What is left stays due, so the
+ticker carries on with it in the next block.
+
+An earlier ticker could use up the whole budget, every block. To
+keep it from starving the later ones, the app runs each ticker with
+Keep, holding back a share of the budget for every ticker after it.
+A ticker gets its own share and whatever the ones before it left.
+
+Without a budget in the context, eg. in tests calling a ticker
+directly, Take always succeeds.
+*/
+package budget
+
+import (
+	"context"
+
+	"github.com/confio/weave"
+)
+
+type contextKey int // local to the budget package
+
+const (
+	contextKeyMeter contextKey = iota
+)
+
+// meter holds the gas left in the block, the tickers share it
+type meter struct {
+	left int64
+}
+
+// share is what one ticker may take of the meter, all but the
+// gas kept for the tickers after it
+type share struct {
+	meter *meter
+	keep  int64
+}
+
+// With returns a context giving the tickers of the block gas
+func With(ctx weave.Context, gas int64) weave.Context {
+	return context.WithValue(ctx, contextKeyMeter, &share{meter: &meter{left: gas}})
+}
+
+// Keep returns a context that cannot take the last gas of the
+// budget, but leaves it to the tickers after. Without a budget
+// it returns ctx.
+func Keep(ctx weave.Context, gas int64) weave.Context {
+	s, _ := ctx.Value(contextKeyMeter).(*share)
+	if s == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, contextKeyMeter, &share{meter: s.meter, keep: gas})
+}
+
+// Take uses gas of the budget of the block. It returns false, and
+// takes nothing, if less is left.
+func Take(ctx weave.Context, gas int64) bool {
+	s, _ := ctx.Value(contextKeyMeter).(*share)
+	if s == nil {
+		return true
+	}
+	if gas > s.meter.left-s.keep {
+		return false
+	}
+	s.meter.left -= gas
+	return true
+}
+
+// Left returns the gas left in the block that ctx may take, and
+// false if there is no budget
+func Left(ctx weave.Context) (int64, bool) {
+	s, _ := ctx.Value(contextKeyMeter).(*share)
+	if s == nil {
+		return 0, false
+	}
+	if s.meter.left < s.keep {
+		return 0, true
+	}
+	return s.meter.left - s.keep, true
+}
@@ -0,0 +1,45 @@
+package budget
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudget(t *testing.T) {
+	// no limit without a budget
+	ctx := context.Background()
+	assert.True(t, Take(ctx, 1<<40))
+	_, ok := Left(ctx)
+	assert.False(t, ok)
+
+	ctx = With(ctx, 100)
+	assert.True(t, Take(ctx, 60))
+	// too much takes nothing
+	assert.False(t, Take(ctx, 50))
+	left, ok := Left(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, int64(40), left)
+	assert.True(t, Take(ctx, 40))
+	assert.False(t, Take(ctx, 1))
+	assert.True(t, Take(ctx, 0))
+
+	// every block starts anew
+	ctx = With(ctx, 10)
+	assert.True(t, Take(ctx, 10))
+
+	// what is kept is left for later
+	ctx = With(context.Background(), 100)
+	first := Keep(ctx, 70)
+	assert.False(t, Take(first, 31))
+	assert.True(t, Take(first, 20))
+	left, _ = Left(first)
+	assert.Equal(t, int64(10), left)
+	second := Keep(ctx, 0)
+	assert.True(t, Take(second, 80))
+	left, _ = Left(first)
+	assert.Equal(t, int64(0), left)
+	assert.False(t, Take(first, 1))
+	assert.Equal(t, context.Background(), Keep(context.Background(), 10))
+}
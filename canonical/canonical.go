@@ -0,0 +1,196 @@
+/*
+Package canonical encodes JSON the same way on every platform, so
+a document signed by a third party (eg. a hardware wallet, or a
+receipt of a settlement signed by a gateway) has exactly one byte
+representation, that anyone can rebuild from the values.
+
+The canonical form of a JSON document is:
+
+  - no whitespace outside of strings
+  - the members of every object sorted by the UTF-8 bytes of
+    their keys, and no key twice in the same object
+  - numbers as integers in decimal, without sign for zero and
+    without leading zeros, fractions or exponents. Amounts are
+    whole and fractional integers everywhere in this app.
+  - strings with only '"', '\' and the control characters
+    escaped, as \b, \f, \n, \r, \t or else \u00XX in lower case,
+    and all other characters as UTF-8
+
+testdata/vectors.json lists inputs with their canonical form (or
+none if they must be rejected), other implementations should
+produce the same bytes for all of them.
+*/
+package canonical
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Marshal returns the canonical form of the JSON encoding/json
+// produces for v
+func Marshal(v interface{}) ([]byte, error) {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return Canonicalize(bz)
+}
+
+// Canonicalize returns the canonical form of a JSON document.
+// It fails if the document is invalid (also as UTF-8), has a non
+// integer number, or an object with the same key twice.
+func Canonicalize(doc []byte) ([]byte, error) {
+	if !utf8.Valid(doc) {
+		return nil, fmt.Errorf("invalid UTF-8")
+	}
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	var buf bytes.Buffer
+	err := encodeValue(dec, &buf)
+	if err != nil {
+		return nil, err
+	}
+	// nothing may follow the document
+	_, err = dec.Token()
+	if err != io.EOF {
+		return nil, fmt.Errorf("data after the document")
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeValue(dec *json.Decoder, buf *bytes.Buffer) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			return encodeObject(dec, buf)
+		}
+		return encodeArray(dec, buf)
+	case string:
+		encodeString(t, buf)
+	case json.Number:
+		return encodeNumber(t, buf)
+	case bool:
+		fmt.Fprint(buf, t)
+	case nil:
+		buf.WriteString("null")
+	}
+	return nil
+}
+
+type member struct {
+	key   string
+	value []byte
+}
+
+func encodeObject(dec *json.Decoder, buf *bytes.Buffer) error {
+	var members []member
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		var value bytes.Buffer
+		err = encodeValue(dec, &value)
+		if err != nil {
+			return err
+		}
+		members = append(members, member{key, value.Bytes()})
+	}
+	// consume the closing brace
+	_, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	// Go strings compare by their UTF-8 bytes
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].key < members[j].key
+	})
+	buf.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			if members[i-1].key == m.key {
+				return fmt.Errorf("duplicate key %q", m.key)
+			}
+			buf.WriteByte(',')
+		}
+		encodeString(m.key, buf)
+		buf.WriteByte(':')
+		buf.Write(m.value)
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func encodeArray(dec *json.Decoder, buf *bytes.Buffer) error {
+	buf.WriteByte('[')
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		err := encodeValue(dec, buf)
+		if err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	// consume the closing bracket
+	_, err := dec.Token()
+	return err
+}
+
+// encodeNumber writes integers without leading zeros
+// and "-0" as "0", the decoder already checked the syntax
+func encodeNumber(n json.Number, buf *bytes.Buffer) error {
+	s := n.String()
+	if strings.ContainsAny(s, ".eE") {
+		return fmt.Errorf("not an integer: %s", s)
+	}
+	if s == "-0" {
+		s = "0"
+	}
+	buf.WriteString(s)
+	return nil
+}
+
+const hex = "0123456789abcdef"
+
+func encodeString(s string, buf *bytes.Buffer) {
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c == '\b':
+			buf.WriteString(`\b`)
+		case c == '\f':
+			buf.WriteString(`\f`)
+		case c == '\n':
+			buf.WriteString(`\n`)
+		case c == '\r':
+			buf.WriteString(`\r`)
+		case c == '\t':
+			buf.WriteString(`\t`)
+		case c < 0x20:
+			buf.WriteString(`\u00`)
+			buf.WriteByte(hex[c>>4])
+			buf.WriteByte(hex[c&0xF])
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('"')
+}
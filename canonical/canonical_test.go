@@ -0,0 +1,62 @@
+package canonical
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave/x"
+)
+
+// vector is one entry of testdata/vectors.json, a nil
+// output means the input must be rejected
+type vector struct {
+	Comment string  `json:"comment"`
+	Input   string  `json:"input"`
+	Output  *string `json:"output"`
+}
+
+func TestVectors(t *testing.T) {
+	bz, err := ioutil.ReadFile("testdata/vectors.json")
+	require.NoError(t, err)
+	var vectors []vector
+	require.NoError(t, json.Unmarshal(bz, &vectors))
+	require.NotEmpty(t, vectors)
+
+	for _, v := range vectors {
+		res, err := Canonicalize([]byte(v.Input))
+		if v.Output == nil {
+			assert.Error(t, err, v.Comment)
+			continue
+		}
+		require.NoError(t, err, v.Comment)
+		assert.Equal(t, *v.Output, string(res), v.Comment)
+		// the canonical form is its own canonical form
+		again, err := Canonicalize(res)
+		require.NoError(t, err, v.Comment)
+		assert.Equal(t, res, again, v.Comment)
+	}
+
+	_, err = Canonicalize([]byte("\"\xff\""))
+	assert.Error(t, err)
+}
+
+func TestMarshal(t *testing.T) {
+	coin := x.NewCoin(12, 500, "IOV")
+	doc := map[string]interface{}{
+		"memo":   "<pay> & \"thanks\"",
+		"amount": &coin,
+		"seq":    int64(-0),
+		"to":     []byte{0xca, 0xfe},
+	}
+	bz, err := Marshal(doc)
+	require.NoError(t, err)
+	assert.Equal(t, `{"amount":{"fractional":500,"ticker":"IOV","whole":12},`+
+		`"memo":"<pay> & \"thanks\"","seq":0,"to":"yv4="}`, string(bz))
+
+	_, err = Marshal(map[string]float64{"rate": 1.5})
+	assert.Error(t, err)
+}
@@ -0,0 +1,243 @@
+/*
+Package chaos injects failures into a handler, to test that the
+savepoint and recovery decorators roll back everything a failed
+tx wrote, wherever it failed.
+
+It is only meant for tests, never add it to the app.
+
+An Injector counts the coin moves and store writes of a tx, and
+fails the selected one. Run a tx once with no fault to learn the
+counts, then once per position to cover all orderings:
+
+	inj := &chaos.Injector{}
+	ctrl := inj.Controller(namecoin.NewController())
+	h := app.ChainDecorators(utils.NewRecovery(),
+		utils.NewSavepoint().OnDeliver(), inj.Decorator()).
+		WithHandler(router)
+*/
+package chaos
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// ErrInjected is returned, or panicked with, by all faults
+var ErrInjected = errors.New("chaos: injected failure")
+
+// Mode is how a fault fails
+type Mode int
+
+const (
+	// Off never fails
+	Off Mode = iota
+	// Fail returns ErrInjected
+	Fail
+	// Panic panics with ErrInjected
+	Panic
+)
+
+// Fault selects the At-th call (starting at 1) to fail
+type Fault struct {
+	Mode Mode
+	At   int
+}
+
+// RandomFault fails one of the first n calls, either way
+func RandomFault(r *rand.Rand, n int) Fault {
+	return Fault{Mode: Mode(1 + r.Intn(2)), At: 1 + r.Intn(n)}
+}
+
+// Injector holds the faults of one test and counts the calls
+// they are matched against. Call Reset before every tx.
+//
+// Moves fails after the coins of the selected MoveCoins or
+// MoveCoinsBatch call moved, so the tx fails half way through a
+// settlement. Writes fails on the selected Set or Delete, before
+// it is applied. A store cannot return an error, so Fail panics
+// there as well.
+//
+// With Delay, writes only reach the store when the handler
+// returns, whether it failed or not. A failed tx then leaves all
+// its writes behind, so only a savepoint above can undo them.
+type Injector struct {
+	Moves  Fault
+	Writes Fault
+	Delay  bool
+
+	moves  int
+	writes int
+}
+
+// Reset starts counting from zero
+func (i *Injector) Reset() {
+	i.moves, i.writes = 0, 0
+}
+
+// Counts returns the moves and writes since the last Reset
+func (i *Injector) Counts() (moves, writes int) {
+	return i.moves, i.writes
+}
+
+func (i *Injector) move() error {
+	i.moves++
+	if i.Moves.At != i.moves {
+		return nil
+	}
+	switch i.Moves.Mode {
+	case Fail:
+		return ErrInjected
+	case Panic:
+		panic(ErrInjected)
+	}
+	return nil
+}
+
+func (i *Injector) write() {
+	i.writes++
+	if i.Writes.At == i.writes && i.Writes.Mode != Off {
+		panic(ErrInjected)
+	}
+}
+
+//---- controller
+
+// Controller wraps ctrl, so its moves fail as set in Moves
+func (i *Injector) Controller(ctrl namecoin.Controller) namecoin.Controller {
+	return controller{Controller: ctrl, inj: i}
+}
+
+type controller struct {
+	namecoin.Controller
+	inj *Injector
+}
+
+var _ namecoin.Controller = controller{}
+
+func (c controller) WithContext(ctx weave.Context) namecoin.Controller {
+	return controller{Controller: c.Controller.WithContext(ctx), inj: c.inj}
+}
+
+func (c controller) MoveCoins(db weave.KVStore, src, dest weave.Address,
+	amount x.Coin) error {
+
+	if err := c.Controller.MoveCoins(db, src, dest, amount); err != nil {
+		return err
+	}
+	return c.inj.move()
+}
+
+func (c controller) MoveCoinsBatch(db weave.KVStore, transfers []namecoin.Transfer) error {
+	if err := c.Controller.MoveCoinsBatch(db, transfers); err != nil {
+		return err
+	}
+	return c.inj.move()
+}
+
+//---- store
+
+// Decorator wraps the store of the handlers below, so their
+// writes fail as set in Writes and are delayed with Delay
+func (i *Injector) Decorator() weave.Decorator {
+	return decorator{inj: i}
+}
+
+type decorator struct {
+	inj *Injector
+}
+
+var _ weave.Decorator = decorator{}
+
+// Check passes a wrapped store to next
+func (d decorator) Check(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	db, flush := d.wrap(db)
+	defer flush()
+	return next.Check(ctx, db, tx)
+}
+
+// Deliver passes a wrapped store to next
+func (d decorator) Deliver(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	db, flush := d.wrap(db)
+	defer flush()
+	return next.Deliver(ctx, db, tx)
+}
+
+// wrap returns the store to pass on, and a func to call when
+// the handler returned
+func (d decorator) wrap(db weave.KVStore) (weave.KVStore, func()) {
+	cacheable, ok := db.(weave.CacheableKVStore)
+	if !ok {
+		return chaosStore{KVStore: db, inj: d.inj}, func() {}
+	}
+	if !d.inj.Delay {
+		return cacheStore{CacheableKVStore: cacheable, inj: d.inj}, func() {}
+	}
+	cache := cacheable.CacheWrap()
+	return cacheStore{CacheableKVStore: cache, inj: d.inj}, cache.Write
+}
+
+// chaosStore counts the writes to a store
+type chaosStore struct {
+	weave.KVStore
+	inj *Injector
+}
+
+func (s chaosStore) Set(key, value []byte) {
+	s.inj.write()
+	s.KVStore.Set(key, value)
+}
+
+func (s chaosStore) Delete(key []byte) {
+	s.inj.write()
+	s.KVStore.Delete(key)
+}
+
+// cacheStore counts the writes to a store that can be cache
+// wrapped, and to all its cache wraps, so handlers see the
+// same kind of store as without the Injector
+type cacheStore struct {
+	weave.CacheableKVStore
+	inj *Injector
+}
+
+func (s cacheStore) Set(key, value []byte) {
+	s.inj.write()
+	s.CacheableKVStore.Set(key, value)
+}
+
+func (s cacheStore) Delete(key []byte) {
+	s.inj.write()
+	s.CacheableKVStore.Delete(key)
+}
+
+func (s cacheStore) CacheWrap() weave.KVCacheWrap {
+	return cacheWrap{KVCacheWrap: s.CacheableKVStore.CacheWrap(), inj: s.inj}
+}
+
+type cacheWrap struct {
+	weave.KVCacheWrap
+	inj *Injector
+}
+
+func (c cacheWrap) Set(key, value []byte) {
+	c.inj.write()
+	c.KVCacheWrap.Set(key, value)
+}
+
+func (c cacheWrap) Delete(key []byte) {
+	c.inj.write()
+	c.KVCacheWrap.Delete(key)
+}
+
+func (c cacheWrap) CacheWrap() weave.KVCacheWrap {
+	return cacheWrap{KVCacheWrap: c.KVCacheWrap.CacheWrap(), inj: c.inj}
+}
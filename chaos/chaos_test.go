@@ -0,0 +1,90 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// writer sets n keys, one through a cache wrap
+type writer int
+
+func (w writer) Check(ctx weave.Context, db weave.KVStore, tx weave.Tx) (weave.CheckResult, error) {
+	return weave.CheckResult{}, nil
+}
+
+func (w writer) Deliver(ctx weave.Context, db weave.KVStore, tx weave.Tx) (weave.DeliverResult, error) {
+	for i := 0; i < int(w)-1; i++ {
+		db.Set([]byte{byte(i)}, []byte("foo"))
+	}
+	cache := db.(weave.CacheableKVStore).CacheWrap()
+	cache.Set([]byte("cached"), []byte("bar"))
+	cache.Write()
+	return weave.DeliverResult{}, nil
+}
+
+func TestInjectorWrites(t *testing.T) {
+	var helpers x.TestHelpers
+	tx := helpers.MockTx(nil)
+	ctx := context.Background()
+
+	inj := &Injector{}
+	d := inj.Decorator()
+	db := store.MemStore()
+	_, err := d.Deliver(ctx, db, tx, writer(3))
+	require.NoError(t, err)
+	_, writes := inj.Counts()
+	assert.Equal(t, 3, writes)
+
+	for _, at := range []int{1, 3} {
+		inj.Reset()
+		inj.Writes = Fault{Mode: Fail, At: at}
+		assert.Panics(t, func() { d.Deliver(ctx, store.MemStore(), tx, writer(3)) })
+	}
+
+	// delayed writes are applied even if the handler panics
+	inj.Reset()
+	inj.Writes = Fault{Mode: Panic, At: 2}
+	inj.Delay = true
+	db = store.MemStore()
+	assert.Panics(t, func() { d.Deliver(ctx, db, tx, writer(3)) })
+	assert.Equal(t, []byte("foo"), db.Get([]byte{0}))
+	assert.Nil(t, db.Get([]byte{1}))
+}
+
+func TestInjectorMoves(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	db := store.MemStore()
+	coin := x.NewCoin(1, 0, "FOO")
+
+	inj := &Injector{Moves: Fault{Mode: Fail, At: 2}}
+	ctrl := inj.Controller(namecoin.NewController())
+	require.NoError(t, ctrl.IssueCoins(db, a.Address(), x.NewCoin(10, 0, "FOO")))
+
+	assert.NoError(t, ctrl.MoveCoins(db, a.Address(), b.Address(), coin))
+	// the coins move, then it fails
+	err := ctrl.MoveCoinsBatch(db, namecoin.NewTransfers(a.Address(), b.Address(), x.Coins{&coin}))
+	assert.Equal(t, ErrInjected, err)
+	balance, err := ctrl.Balance(db, b.Address())
+	require.NoError(t, err)
+	assert.Equal(t, x.Coins{&x.Coin{Whole: 2, Ticker: "FOO"}}, balance)
+	moves, _ := inj.Counts()
+	assert.Equal(t, 2, moves)
+
+	inj.Moves.Mode = Panic
+	inj.Reset()
+	ctrl.MoveCoins(db, a.Address(), b.Address(), coin)
+	assert.PanicsWithValue(t, ErrInjected, func() {
+		ctrl.MoveCoins(db, a.Address(), b.Address(), coin)
+	})
+}
@@ -13,6 +13,10 @@ import (
 
 	bov "github.com/iov-one/bcp-demo"
 	"github.com/iov-one/bcp-demo/app"
+	"github.com/iov-one/bcp-demo/demo"
+	"github.com/iov-one/bcp-demo/gateway"
+	"github.com/iov-one/bcp-demo/node"
+	"github.com/iov-one/bcp-demo/scenario"
 )
 
 var (
@@ -31,10 +35,22 @@ func helpMessage() {
 	fmt.Println("bov")
 	fmt.Println("        Blockchain of Value node")
 	fmt.Println("")
-	fmt.Println("help    Print this message")
-	fmt.Println("init    Initialize app options in genesis file")
-	fmt.Println("start   Run the abci server")
-	fmt.Println("version Print the app version")
+	fmt.Println("help          Print this message")
+	fmt.Println("init          Initialize app options in genesis file")
+	fmt.Println("genesis       Set genesis accounts from a csv or json file")
+	fmt.Println("start         Run the abci server")
+	fmt.Println("testnet       Generate validator homes for a local testnet")
+	fmt.Println("export-escrow Print one escrow as a signed json document")
+	fmt.Println("import-escrow Add an exported escrow to the genesis file")
+	fmt.Println("migrate-escrows")
+	fmt.Println("              Add the escrows of a prior chain to the genesis file")
+	fmt.Println("rewrite-addresses")
+	fmt.Println("              Move wallets and escrow parties in genesis to new addresses")
+	fmt.Println("keys          Generate, import and sign with secp256k1 keys")
+	fmt.Println("gateway       Serve the queries of a node to rest clients with api keys")
+	fmt.Println("scenario      Run scenario files on a node in memory")
+	fmt.Println("demo          Play an over the counter trade on a node in memory")
+	fmt.Println("version       Print the app version")
 	fmt.Println(`
   -home string
         directory to store files under (default "$HOME/.bov")`)
@@ -60,8 +76,28 @@ func main() {
 		helpMessage()
 	case "init":
 		err = server.InitCmd(app.GenInitOptions, logger, *varHome, rest)
+	case "genesis":
+		err = server.InitCmd(app.GenAccountOptions, logger, *varHome, rest)
 	case "start":
-		err = server.StartCmd(app.GenerateApp, logger, *varHome, rest)
+		err = node.StartCmd(app.GenerateApp, logger, *varHome, rest)
+	case "testnet":
+		err = app.TestnetCmd(logger, rest)
+	case "export-escrow":
+		err = app.ExportEscrowCmd(os.Stdout, *varHome, rest)
+	case "import-escrow":
+		err = app.ImportEscrowCmd(*varHome, rest)
+	case "migrate-escrows":
+		err = app.MigrateEscrowsCmd(*varHome, rest)
+	case "rewrite-addresses":
+		err = app.RewriteAddressesCmd(os.Stdout, *varHome, rest)
+	case "keys":
+		err = app.KeysCmd(os.Stdout, rest)
+	case "gateway":
+		err = gateway.RunCmd(logger, *varHome, rest)
+	case "scenario":
+		err = scenario.RunCmd(os.Stdout, rest)
+	case "demo":
+		err = demo.OTCTrade(os.Stdout)
 	case "testgen":
 		err = commands.TestGenCmd(app.Examples(), rest)
 	case "version":
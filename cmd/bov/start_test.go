@@ -16,6 +16,7 @@ import (
 	"github.com/confio/weave/commands/server"
 
 	"github.com/iov-one/bcp-demo/app"
+	"github.com/iov-one/bcp-demo/node"
 )
 
 func TestStartStandAlone(t *testing.T) {
@@ -34,7 +35,7 @@ func TestStartStandAlone(t *testing.T) {
 	// set up app and start up
 	args := []string{"-bind", "localhost:11122"}
 	runStart := func() error {
-		return server.StartCmd(app.GenerateApp, logger, home, args)
+		return node.StartCmd(app.GenerateApp, logger, home, args)
 	}
 	timeout := time.Duration(2) * time.Second
 	err = runOrTimeout(runStart, timeout)
@@ -65,7 +66,7 @@ func TestStartWithTendermint(t *testing.T) {
 	// set up app and start up
 	args := []string{"-bind", "localhost:46658"}
 	runStart := func() error {
-		return server.StartCmd(app.GenerateApp, logger, home, args)
+		return node.StartCmd(app.GenerateApp, logger, home, args)
 	}
 	timeout := time.Duration(runTime+1) * time.Second
 	err = runOrTimeout(runStart, timeout)
@@ -0,0 +1,417 @@
+/*
+detcheck is a vet style check that flags ranges over maps in
+the Deliver path of the handlers and decorators. Go randomizes
+the order of a map range, so writing to the store or the tags
+from such a loop lets nodes compute different app hashes.
+
+	detcheck ./x/... ./app
+
+The check only looks at the syntax of one package at a time. It
+starts at every func or method named Deliver (see -funcs), follows
+the calls to funcs of the same package by name and reports every
+range over a value it knows to be a map: a var, param or struct
+field declared with a map type, or a make or literal of a map.
+Calls into other packages are not followed, so keep the helpers
+they rely on covered by their own package.
+
+Iterate over ordered.Keys or an ordered.Set instead. If the order
+really does not matter (eg. only summing up), mark the loop with
+a "detcheck:ok" comment on the line of the range or just above.
+
+It exits with 1 if anything was found.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// okComment silences a finding on the same or the next line
+const okComment = "detcheck:ok"
+
+// Finding is a map range reached from an entry func
+type Finding struct {
+	Pos token.Position
+	// Expr is the ranged map, as written
+	Expr string
+	// Path is the chain of calls from the entry
+	Path []string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: range over map %s in %s", f.Pos, f.Expr, strings.Join(f.Path, " -> "))
+}
+
+func main() {
+	funcs := flag.String("funcs", "Deliver", "comma separated names of the entry funcs")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		args = []string{"./..."}
+	}
+	found, err := run(os.Stdout, args, strings.Split(*funcs, ","))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %+v\n", err)
+		os.Exit(2)
+	}
+	if found {
+		os.Exit(1)
+	}
+}
+
+func run(w io.Writer, args, entries []string) (bool, error) {
+	dirs, err := expand(args)
+	if err != nil {
+		return false, err
+	}
+	var found bool
+	for _, dir := range dirs {
+		findings, err := CheckDir(dir, entries)
+		if err != nil {
+			return false, err
+		}
+		for _, f := range findings {
+			fmt.Fprintln(w, f)
+			found = true
+		}
+	}
+	return found, nil
+}
+
+// expand resolves the "dir/..." patterns to all dirs below,
+// skipping vendor, testdata and hidden dirs
+func expand(args []string) ([]string, error) {
+	var dirs []string
+	for _, arg := range args {
+		if !strings.HasSuffix(arg, "/...") {
+			dirs = append(dirs, arg)
+			continue
+		}
+		root := strings.TrimSuffix(arg, "/...")
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil || !info.IsDir() {
+				return err
+			}
+			name := info.Name()
+			if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			dirs = append(dirs, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dirs, nil
+}
+
+// CheckDir parses the non test files of each package in dir
+// and returns the findings, sorted by position
+func CheckDir(dir string, entries []string) ([]Finding, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Finding
+	for _, pkg := range pkgs {
+		res = append(res, newChecker(fset, pkg).check(entries)...)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i].Pos, res[j].Pos
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Line < b.Line
+	})
+	return res, nil
+}
+
+type checker struct {
+	fset *token.FileSet
+	// funcs are all funcs and methods by name
+	funcs map[string][]*ast.FuncDecl
+	// mapTypes are the names of types declared as maps
+	mapTypes map[string]bool
+	// mapVars are the package level vars and the struct fields
+	// with a map type
+	mapVars map[string]bool
+	// imports are the names of the imported packages
+	imports map[string]bool
+	// ok are the lines marked with okComment, by file
+	ok map[string]map[int]bool
+}
+
+func newChecker(fset *token.FileSet, pkg *ast.Package) *checker {
+	c := &checker{
+		fset:     fset,
+		funcs:    make(map[string][]*ast.FuncDecl),
+		mapTypes: make(map[string]bool),
+		mapVars:  make(map[string]bool),
+		imports:  make(map[string]bool),
+		ok:       make(map[string]map[int]bool),
+	}
+
+	// the types first, as the vars and fields refer to them
+	for _, file := range pkg.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			if spec, ok := n.(*ast.TypeSpec); ok {
+				if _, ok := spec.Type.(*ast.MapType); ok {
+					c.mapTypes[spec.Name.Name] = true
+				}
+			}
+			return true
+		})
+	}
+
+	for name, file := range pkg.Files {
+		for _, imp := range file.Imports {
+			path := strings.Trim(imp.Path.Value, `"`)
+			c.imports[path[strings.LastIndex(path, "/")+1:]] = true
+			if imp.Name != nil {
+				c.imports[imp.Name.Name] = true
+			}
+		}
+		for _, group := range file.Comments {
+			for _, comment := range group.List {
+				if strings.Contains(comment.Text, okComment) {
+					if c.ok[name] == nil {
+						c.ok[name] = make(map[int]bool)
+					}
+					c.ok[name][fset.Position(comment.Pos()).Line] = true
+				}
+			}
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				c.funcs[decl.Name.Name] = append(c.funcs[decl.Name.Name], decl)
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.ValueSpec); ok {
+						c.addValueSpec(c.mapVars, spec)
+					}
+				}
+			}
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			if st, ok := n.(*ast.StructType); ok {
+				c.addFields(c.mapVars, st.Fields)
+			}
+			return true
+		})
+	}
+	return c
+}
+
+// isMapType returns true for a map type or the name of one
+func (c *checker) isMapType(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.MapType:
+		return true
+	case *ast.Ident:
+		return c.mapTypes[expr.Name]
+	case *ast.ParenExpr:
+		return c.isMapType(expr.X)
+	}
+	return false
+}
+
+// isMapValue returns true if expr creates a new map
+func (c *checker) isMapValue(expr ast.Expr) bool {
+	switch expr := expr.(type) {
+	case *ast.CompositeLit:
+		return c.isMapType(expr.Type)
+	case *ast.CallExpr:
+		if fn, ok := expr.Fun.(*ast.Ident); ok && fn.Name == "make" && len(expr.Args) > 0 {
+			return c.isMapType(expr.Args[0])
+		}
+		// a conversion, like Balances(m)
+		return len(expr.Args) == 1 && c.isMapType(expr.Fun)
+	}
+	return false
+}
+
+func (c *checker) addFields(vars map[string]bool, fields *ast.FieldList) {
+	if fields == nil {
+		return
+	}
+	for _, field := range fields.List {
+		if c.isMapType(field.Type) {
+			for _, name := range field.Names {
+				vars[name.Name] = true
+			}
+		}
+	}
+}
+
+func (c *checker) addValueSpec(vars map[string]bool, spec *ast.ValueSpec) {
+	for i, name := range spec.Names {
+		if c.isMapType(spec.Type) || (i < len(spec.Values) && c.isMapValue(spec.Values[i])) {
+			vars[name.Name] = true
+		}
+	}
+}
+
+// isMap returns true if expr is known to be a map in the
+// scope of a func with the locals
+func (c *checker) isMap(expr ast.Expr, locals map[string]bool) bool {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if known, ok := locals[expr.Name]; ok {
+			return known
+		}
+		return c.mapVars[expr.Name]
+	case *ast.SelectorExpr:
+		if pkg, ok := expr.X.(*ast.Ident); ok && c.imports[pkg.Name] {
+			return false
+		}
+		return c.mapVars[expr.Sel.Name]
+	case *ast.ParenExpr:
+		return c.isMap(expr.X, locals)
+	}
+	return c.isMapValue(expr)
+}
+
+// locals returns the params and variables of fn, true if
+// they are maps. Names are not scoped, the last one wins.
+func (c *checker) locals(fn *ast.FuncDecl) map[string]bool {
+	locals := make(map[string]bool)
+	add := func(fields *ast.FieldList) {
+		if fields == nil {
+			return
+		}
+		for _, field := range fields.List {
+			for _, name := range field.Names {
+				locals[name.Name] = c.isMapType(field.Type)
+			}
+		}
+	}
+	add(fn.Recv)
+	add(fn.Type.Params)
+	add(fn.Type.Results)
+	ast.Inspect(fn, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			add(n.Type.Params)
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				locals[name.Name] = c.isMapType(n.Type) ||
+					(i < len(n.Values) && c.isMapValue(n.Values[i]))
+			}
+		case *ast.AssignStmt:
+			if n.Tok != token.DEFINE {
+				return true
+			}
+			for i, lhs := range n.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok {
+					locals[id.Name] = len(n.Lhs) == len(n.Rhs) && c.isMap(n.Rhs[i], locals)
+				}
+			}
+		}
+		return true
+	})
+	return locals
+}
+
+// name returns Type.Method or the func name
+func name(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if id, ok := typ.(*ast.Ident); ok {
+		return id.Name + "." + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+func (c *checker) check(entries []string) []Finding {
+	var res []Finding
+	seen := make(map[*ast.FuncDecl]bool)
+
+	var visit func(fn *ast.FuncDecl, path []string)
+	visit = func(fn *ast.FuncDecl, path []string) {
+		if seen[fn] || fn.Body == nil {
+			return
+		}
+		seen[fn] = true
+		path = append(path[:len(path):len(path)], name(fn))
+		locals := c.locals(fn)
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.RangeStmt:
+				if c.isMap(n.X, locals) && !c.marked(n.Pos()) {
+					res = append(res, Finding{
+						Pos:  c.fset.Position(n.Pos()),
+						Expr: c.source(n.X),
+						Path: path,
+					})
+				}
+			case *ast.CallExpr:
+				for _, callee := range c.callees(n) {
+					visit(callee, path)
+				}
+			}
+			return true
+		})
+	}
+
+	for _, entry := range entries {
+		for _, fn := range c.funcs[strings.TrimSpace(entry)] {
+			visit(fn, nil)
+		}
+	}
+	return res
+}
+
+// callees returns the funcs of this package a call may go to
+func (c *checker) callees(call *ast.CallExpr) []*ast.FuncDecl {
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		return c.funcs[fn.Name]
+	case *ast.SelectorExpr:
+		if pkg, ok := fn.X.(*ast.Ident); ok && c.imports[pkg.Name] {
+			return nil
+		}
+		return c.funcs[fn.Sel.Name]
+	}
+	return nil
+}
+
+// marked returns true if the line or the one above has okComment
+func (c *checker) marked(pos token.Pos) bool {
+	p := c.fset.Position(pos)
+	lines := c.ok[p.Filename]
+	return lines[p.Line] || lines[p.Line-1]
+}
+
+func (c *checker) source(expr ast.Expr) string {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr.Name
+	case *ast.SelectorExpr:
+		return c.source(expr.X) + "." + expr.Sel.Name
+	case *ast.ParenExpr:
+		return c.source(expr.X)
+	}
+	return "literal"
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSource = `package demo
+
+import "sort"
+
+type Balances map[string]int64
+
+type Handler struct {
+	byName map[string]int64
+	names  []string
+}
+
+var registry = map[string]bool{}
+
+func (h Handler) Deliver(db Store, tx Tx) error {
+	for _, n := range h.names {
+		_ = n
+	}
+	for k := range h.byName {
+		_ = k
+	}
+	return h.apply(db)
+}
+
+func (h *Handler) apply(db Store) error {
+	seen := make(map[string]bool)
+	var tally Balances
+	for k := range seen {
+		_ = k
+	}
+	// detcheck:ok only summing up
+	for _, v := range tally {
+		_ = v
+	}
+	keys := sort.StringSlice(nil)
+	for _, k := range keys {
+		_ = k
+	}
+	return helper(Balances{})
+}
+
+func helper(b Balances) error {
+	for k := range b {
+		_ = k
+	}
+	for k := range registry {
+		_ = k
+	}
+	return nil
+}
+
+// Check is not on the Deliver path
+func (h Handler) Check() {
+	for k := range h.byName {
+		_ = k
+	}
+}
+`
+
+const testFile = `package demo
+
+func Deliver() {
+	for k := range map[string]int{"a": 1} { // detcheck:ok
+		_ = k
+	}
+}
+
+func ignored(m map[string]int) {
+	for k := range m {
+		_ = k
+	}
+}
+`
+
+func TestCheckDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "detcheck")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "demo.go")
+	require.NoError(t, ioutil.WriteFile(file, []byte(testSource), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "other.go"), []byte(testFile), 0644))
+	// tests are skipped
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "demo_test.go"),
+		[]byte("package demo\n\nfunc Deliver(m map[int]int) {\n\tfor range m {}\n}\n"), 0644))
+
+	findings, err := CheckDir(dir, []string{"Deliver"})
+	require.NoError(t, err)
+
+	type short struct {
+		Line int
+		Expr string
+		Path string
+	}
+	var got []short
+	for _, f := range findings {
+		assert.Equal(t, file, f.Pos.Filename)
+		got = append(got, short{f.Pos.Line, f.Expr, f.String()[len(f.Pos.String()):]})
+	}
+	assert.Equal(t, []short{
+		{18, "h.byName", ": range over map h.byName in Handler.Deliver"},
+		{27, "seen", ": range over map seen in Handler.Deliver -> Handler.apply"},
+		{42, "b", ": range over map b in Handler.Deliver -> Handler.apply -> helper"},
+		{45, "registry", ": range over map registry in Handler.Deliver -> Handler.apply -> helper"},
+	}, got)
+
+	// other entries
+	findings, err = CheckDir(dir, []string{"Check", "ignored"})
+	require.NoError(t, err)
+	assert.Len(t, findings, 2)
+}
+
+func TestRun(t *testing.T) {
+	// the tree must stay clean
+	var out bytes.Buffer
+	found, err := run(&out, []string{"../../x/...", "../../app"}, []string{"Deliver"})
+	require.NoError(t, err)
+	assert.False(t, found, out.String())
+}
@@ -0,0 +1,309 @@
+/*
+msggen writes the boilerplate of a new message, as we have it in
+x/escrow: the path constant, Path and a Validate skeleton, a handler
+with the Check/Deliver/validate split and the testgen example that
+lets clients dump the message with "bov testgen".
+
+	msggen -proto x/escrow/codec.proto -msg BidArbitrationMsg \
+		-path escrow/bid -o x/escrow/bid.go
+
+Without -path the route is derived from the package and the
+message name, eg. "escrow/bid_arbitration". Without -o the code
+is printed. The output is a starting point, fill in the TODOs.
+*/
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// Field is one field of the message
+type Field struct {
+	Name   string
+	GoName string
+	Type   string
+}
+
+// Message is the parsed message definition
+type Message struct {
+	Package string
+	Name    string
+	Path    string
+	Fields  []Field
+}
+
+var (
+	rePackage = regexp.MustCompile(`^package\s+(\w+)\s*;`)
+	reMessage = regexp.MustCompile(`^message\s+(\w+)\s*\{`)
+	reField   = regexp.MustCompile(`^(repeated\s+)?([\w.]+)\s+(\w+)\s*=\s*\d+\s*;`)
+)
+
+func main() {
+	protoFile := flag.String("proto", "", "the .proto file with the message")
+	msgName := flag.String("msg", "", "name of the message")
+	path := flag.String("path", "", "route of the message (default derived from the name)")
+	out := flag.String("o", "", "file to write (default stdout)")
+	flag.Parse()
+
+	if err := run(*protoFile, *msgName, *path, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %+v\n", err)
+		flag.Usage()
+		os.Exit(1)
+	}
+}
+
+func run(protoFile, msgName, path, out string) error {
+	if protoFile == "" || msgName == "" {
+		return fmt.Errorf("-proto and -msg are required")
+	}
+	f, err := os.Open(protoFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	msg, err := Parse(f, msgName)
+	if err != nil {
+		return err
+	}
+	if path != "" {
+		msg.Path = path
+	}
+	code, err := Generate(msg)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(code)
+		return err
+	}
+	if _, err := os.Stat(out); err == nil {
+		return fmt.Errorf("%s exists", out)
+	}
+	return ioutil.WriteFile(out, code, 0644)
+}
+
+// Parse finds the message in a proto file. It understands the
+// subset of proto3 we use: one package, no nested messages.
+func Parse(r io.Reader, name string) (*Message, error) {
+	var pkg string
+	var msg *Message
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if m := rePackage.FindStringSubmatch(line); m != nil {
+			pkg = m[1]
+			continue
+		}
+		if msg == nil {
+			if m := reMessage.FindStringSubmatch(line); m != nil && m[1] == name {
+				msg = &Message{Package: pkg, Name: name}
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "}") {
+			msg.Path = DefaultPath(pkg, name)
+			return msg, nil
+		}
+		if m := reField.FindStringSubmatch(line); m != nil {
+			msg.Fields = append(msg.Fields, Field{
+				Name:   m[3],
+				GoName: CamelCase(m[3]),
+				Type:   goType(m[2], m[1] != ""),
+			})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("message %s not found", name)
+}
+
+// DefaultPath returns the package and the snake case name
+// without the Msg suffix, eg. "escrow/bid_arbitration"
+func DefaultPath(pkg, name string) string {
+	return pkg + "/" + snakeCase(strings.TrimSuffix(name, "Msg"))
+}
+
+func snakeCase(name string) string {
+	var buf bytes.Buffer
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				buf.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// CamelCase converts a field name as protoc-gen-go does,
+// "escrow_id" becomes "EscrowId"
+func CamelCase(name string) string {
+	var buf bytes.Buffer
+	up := true
+	for _, r := range name {
+		if r == '_' {
+			up = true
+			continue
+		}
+		if up {
+			r = unicode.ToUpper(r)
+			up = false
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+var scalars = map[string]string{
+	"string": "string",
+	"bytes":  "[]byte",
+	"bool":   "bool",
+	"int64":  "int64",
+	"int32":  "int32",
+	"uint64": "uint64",
+	"uint32": "uint32",
+}
+
+func goType(typ string, repeated bool) string {
+	t, ok := scalars[typ]
+	if !ok {
+		t = "*" + typ
+	}
+	if repeated {
+		return "[]" + t
+	}
+	return t
+}
+
+// Generate returns the formatted go code for msg
+func Generate(msg *Message) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, msg); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+var tmpl = template.Must(template.New("msg").Funcs(template.FuncMap{
+	"lower": func(s string) string {
+		return strings.ToLower(s[:1]) + s[1:]
+	},
+	"snake": snakeCase,
+	"trim": func(s string) string {
+		return strings.TrimSuffix(s, "Msg")
+	},
+}).Parse(`package {{.Package}}
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x"
+)
+
+// TODO: move the path next to the others in msg.go
+// and register the handler in RegisterRoutes:
+//
+//	r.Handle(path{{.Name}}, {{trim .Name}}Handler{auth, bucket})
+//
+// Add the message to the Tx of the app (app/codec.proto and
+// app/tx.go) and an example for testgen to app/examples.go:
+//
+//	{"{{snake .Name}}", &{{.Package}}.{{.Name}}{}},
+const (
+	path{{.Name}} = "{{.Path}}"
+
+	{{lower (trim .Name)}}Cost int64 = 0
+)
+
+var _ weave.Msg = (*{{.Name}})(nil)
+
+// Path fulfills weave.Msg interface to allow routing
+func ({{.Name}}) Path() string {
+	return path{{.Name}}
+}
+
+// Validate makes sure that this is sensible
+func (m *{{.Name}}) Validate() error {
+	// TODO: check the fields
+{{- range .Fields}}
+	// {{.GoName}} {{.Type}}
+{{- end}}
+	return nil
+}
+
+// {{trim .Name}}Handler TODO
+type {{trim .Name}}Handler struct {
+	auth   x.Authenticator
+	bucket Bucket
+}
+
+var _ weave.Handler = {{trim .Name}}Handler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h {{trim .Name}}Handler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += {{lower (trim .Name)}}Cost
+	return res, nil
+}
+
+// Deliver TODO
+func (h {{trim .Name}}Handler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// TODO: apply the message
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h {{trim .Name}}Handler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*{{.Name}}, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*{{.Name}})
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	// TODO: load the state and check the signers
+	return msg, nil
+}
+`))
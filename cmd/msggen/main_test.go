@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testProto = `syntax = "proto3";
+
+package escrow;
+
+import "github.com/confio/weave/x/codec.proto";
+
+// BidArbitrationMsg offers to arbitrate an escrow
+message BidArbitrationMsg {
+    bytes escrow_id = 1;
+    // fee is the part of the bounty the arbiter asks for
+    x.Coin fee = 2;
+    repeated string notes = 3;
+}
+
+message Other {
+    int64 height = 1;
+}
+`
+
+func TestParse(t *testing.T) {
+	msg, err := Parse(strings.NewReader(testProto), "BidArbitrationMsg")
+	require.NoError(t, err)
+	assert.Equal(t, "escrow", msg.Package)
+	assert.Equal(t, "escrow/bid_arbitration", msg.Path)
+	assert.Equal(t, []Field{
+		{Name: "escrow_id", GoName: "EscrowId", Type: "[]byte"},
+		{Name: "fee", GoName: "Fee", Type: "*x.Coin"},
+		{Name: "notes", GoName: "Notes", Type: "[]string"},
+	}, msg.Fields)
+
+	_, err = Parse(strings.NewReader(testProto), "Missing")
+	assert.Error(t, err)
+}
+
+func TestGenerate(t *testing.T) {
+	msg, err := Parse(strings.NewReader(testProto), "BidArbitrationMsg")
+	require.NoError(t, err)
+	msg.Path = "escrow/bid"
+
+	// the output must be valid go
+	code, err := Generate(msg)
+	require.NoError(t, err)
+	src := string(code)
+	assert.Contains(t, src, `pathBidArbitrationMsg = "escrow/bid"`)
+	assert.Contains(t, src, "func (m *BidArbitrationMsg) Validate() error {")
+	assert.Contains(t, src, "// Fee *x.Coin")
+	assert.Contains(t, src, "type BidArbitrationHandler struct {")
+	assert.Contains(t, src, "res.GasAllocated += bidArbitrationCost")
+	assert.Contains(t, src, `{"bid_arbitration_msg", &escrow.BidArbitrationMsg{}}`)
+}
@@ -0,0 +1,240 @@
+/*
+Package conformance generates the vectors that client libraries in
+other languages (eg. JS or Rust) check their encoding against: the
+keys derived from fixed mnemonics, the bytes to sign and the signed
+bytes of txs, the addresses of escrows, and the app hash after each
+block of a fixed script run from genesis.
+
+Everything is derived from constants, so Generate always returns
+the same vectors for a given version of the app. They are published
+in testdata/vectors.json, all bytes hex encoded. A change of the tx
+or state format changes them; regenerate the file with
+`go test ./conformance -update` and ship it with the release.
+*/
+package conformance
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave/crypto"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+	"github.com/confio/weave/x/sigs"
+
+	"github.com/iov-one/bcp-demo/app"
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/txindex"
+)
+
+// ChainID is the chain the txs are signed for
+const ChainID = "conformance"
+
+// Mnemonics are the keys of alice, bob and carol
+var Mnemonics = []string{
+	"alice alice alice alice alice alice alice alice alice alice alice alice",
+	"bob bob bob bob bob bob bob bob bob bob bob bob",
+	"carol carol carol carol carol carol carol carol carol carol carol carol",
+}
+
+// Vectors are all values an implementation must reproduce
+type Vectors struct {
+	ChainID string         `json:"chain_id"`
+	Keys    []KeyVector    `json:"keys"`
+	Escrows []EscrowVector `json:"escrows"`
+	Blocks  []BlockVector  `json:"blocks"`
+}
+
+// KeyVector is the ed25519 key derived from a mnemonic,
+// see app.KeyFromMnemonic
+type KeyVector struct {
+	Mnemonic string `json:"mnemonic"`
+	PubKey   string `json:"pub_key"`
+	Address  string `json:"address"`
+}
+
+// EscrowVector is the address holding the coins of the
+// escrow with the sequence
+type EscrowVector struct {
+	Sequence   uint64 `json:"sequence"`
+	Permission string `json:"permission"`
+	Address    string `json:"address"`
+}
+
+// TxVector is one signed tx. SignBytes is what the signer
+// signs, Tx the encoded tx sent to tendermint, and Hash the
+// hash it is found by with the "/txs" query.
+type TxVector struct {
+	Comment   string `json:"comment"`
+	Signer    int    `json:"signer"`
+	Sequence  int64  `json:"sequence"`
+	SignBytes string `json:"sign_bytes"`
+	Tx        string `json:"tx"`
+	Hash      string `json:"hash"`
+}
+
+// BlockVector is the app hash after delivering the txs at
+// the height, starting from the genesis of Genesis
+type BlockVector struct {
+	Height  int64      `json:"height"`
+	Txs     []TxVector `json:"txs"`
+	AppHash string     `json:"app_hash"`
+}
+
+// step is one tx of the script
+type step struct {
+	comment string
+	signer  int
+	seq     int64
+	tx      *app.Tx
+}
+
+// Genesis returns the genesis file the script starts from:
+// every key holds 1000 IOV
+func Genesis(keys []*crypto.PrivateKey) ([]byte, error) {
+	var accts []namecoin.GenesisAccount
+	for _, key := range keys {
+		accts = append(accts, namecoin.GenesisAccount{
+			Address: key.PublicKey().Address(),
+			Wallet: &namecoin.Wallet{
+				Coins: x.Coins{&x.Coin{Whole: 1000, Ticker: "IOV"}},
+			},
+		})
+	}
+	opts, err := namecoin.BuildGenesis(accts,
+		[]namecoin.GenesisToken{{Ticker: "IOV", Name: "IOV", SigFigs: 9}})
+	if err != nil {
+		return nil, err
+	}
+	state, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]json.RawMessage{
+		"chain_id":  json.RawMessage(fmt.Sprintf("%q", ChainID)),
+		"app_state": state,
+	})
+}
+
+// script returns the txs of every block: alice pays bob, then
+// escrows coins for bob with carol as arbiter, who releases them
+func script(keys []*crypto.PrivateKey) [][]step {
+	alice := keys[0].PublicKey()
+	bob := keys[1].PublicKey()
+	carol := keys[2].PublicKey()
+	iov := func(whole int64) *x.Coin {
+		return &x.Coin{Whole: whole, Ticker: "IOV"}
+	}
+	return [][]step{
+		// empty block after genesis
+		{},
+		{{"send", 0, 0, &app.Tx{Sum: &app.Tx_SendMsg{SendMsg: &cash.SendMsg{
+			Src:    alice.Address(),
+			Dest:   bob.Address(),
+			Amount: iov(100),
+			Memo:   "conformance",
+		}}}}},
+		{{"create escrow", 0, 1, &app.Tx{Sum: &app.Tx_CreateEscrowMsg{CreateEscrowMsg: &escrow.CreateEscrowMsg{
+			Sender:    alice.Permission(),
+			Arbiter:   carol.Permission(),
+			Recipient: bob.Permission(),
+			Amount:    x.Coins{iov(50)},
+			Timeout:   100,
+		}}}}},
+		{{"release escrow", 2, 0, &app.Tx{Sum: &app.Tx_ReleaseEscrowMsg{ReleaseEscrowMsg: &escrow.ReleaseEscrowMsg{
+			EscrowId: escrow.SeqCondition(1),
+			Amount:   x.Coins{iov(20)},
+		}}}}},
+	}
+}
+
+// Generate runs the script on a new app in memory
+// and returns the vectors
+func Generate() (*Vectors, error) {
+	res := &Vectors{ChainID: ChainID}
+	var keys []*crypto.PrivateKey
+	for _, m := range Mnemonics {
+		key := app.KeyFromMnemonic(m)
+		keys = append(keys, key)
+		res.Keys = append(res.Keys, KeyVector{
+			Mnemonic: m,
+			PubKey:   hex.EncodeToString(key.PublicKey().GetEd25519()),
+			Address:  hex.EncodeToString(key.PublicKey().Address()),
+		})
+	}
+	for seq := uint64(1); seq <= 3; seq++ {
+		cond := escrow.SeqCondition(seq)
+		res.Escrows = append(res.Escrows, EscrowVector{
+			Sequence:   seq,
+			Permission: hex.EncodeToString(cond.Permission()),
+			Address:    hex.EncodeToString(cond.Address()),
+		})
+	}
+
+	genesis, err := Genesis(keys)
+	if err != nil {
+		return nil, err
+	}
+	myApp, err := app.Application(ChainID, app.Stack(x.Coin{}, 0),
+		app.TxDecoder, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer myApp.Close()
+	myApp.WithInit(app.Initializer())
+	myApp.InitChainWithGenesis(abci.RequestInitChain{}, genesis)
+
+	for i, block := range script(keys) {
+		height := int64(i + 1)
+		myApp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: height}})
+		bv := BlockVector{Height: height, Txs: []TxVector{}}
+		for _, s := range block {
+			tv, bz, err := sign(keys[s.signer], s)
+			if err != nil {
+				return nil, err
+			}
+			dres := myApp.DeliverTx(bz)
+			if dres.Code != 0 {
+				return nil, fmt.Errorf("%s failed: %s", s.comment, dres.Log)
+			}
+			bv.Txs = append(bv.Txs, tv)
+		}
+		myApp.EndBlock(abci.RequestEndBlock{Height: height})
+		cres := myApp.Commit()
+		bv.AppHash = hex.EncodeToString(cres.Data)
+		res.Blocks = append(res.Blocks, bv)
+	}
+	return res, nil
+}
+
+// sign adds the signature of key to the tx of the step
+// and returns its vector and bytes
+func sign(key *crypto.PrivateKey, s step) (TxVector, []byte, error) {
+	var tv TxVector
+	signBytes, err := sigs.BuildSignBytesTx(s.tx, ChainID, s.seq)
+	if err != nil {
+		return tv, nil, err
+	}
+	sig, err := sigs.SignTx(key, s.tx, ChainID, s.seq)
+	if err != nil {
+		return tv, nil, err
+	}
+	s.tx.Signatures = []*sigs.StdSignature{sig}
+	bz, err := s.tx.Marshal()
+	if err != nil {
+		return tv, nil, err
+	}
+	tv = TxVector{
+		Comment:   s.comment,
+		Signer:    s.signer,
+		Sequence:  s.seq,
+		SignBytes: hex.EncodeToString(signBytes),
+		Tx:        hex.EncodeToString(bz),
+		Hash:      hex.EncodeToString(txindex.Hash(bz)),
+	}
+	return tv, bz, nil
+}
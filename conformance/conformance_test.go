@@ -0,0 +1,38 @@
+package conformance
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const vectorsFile = "testdata/vectors.json"
+
+var update = flag.Bool("update", false, "rewrite "+vectorsFile)
+
+// TestVectors fails if the app no longer produces the
+// published vectors
+func TestVectors(t *testing.T) {
+	vectors, err := Generate()
+	require.NoError(t, err)
+	bz, err := json.MarshalIndent(vectors, "", "  ")
+	require.NoError(t, err)
+	bz = append(bz, '\n')
+
+	// the script does not depend on anything but the code
+	again, err := Generate()
+	require.NoError(t, err)
+	assert.Equal(t, vectors, again)
+
+	if *update {
+		require.NoError(t, ioutil.WriteFile(vectorsFile, bz, 0644))
+	}
+	published, err := ioutil.ReadFile(vectorsFile)
+	require.NoError(t, err)
+	assert.Equal(t, string(published), string(bz),
+		"vectors changed, run go test ./conformance -update if that is intended")
+}
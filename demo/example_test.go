@@ -0,0 +1,27 @@
+package demo_test
+
+import (
+	"os"
+
+	"github.com/iov-one/bcp-demo/demo"
+)
+
+func ExampleOTCTrade() {
+	err := demo.OTCTrade(os.Stdout)
+	if err != nil {
+		panic(err)
+	}
+	// Output:
+	// The desk funds Alice with 5000 IOV
+	// Alice escrows them for Bob, Carol arbitrates
+	//   Alice has nothing
+	//   Bob has nothing
+	//   the escrow has 5000 IOV
+	// Bob sends the bitcoin, Alice does not release
+	// Bob cannot release it himself
+	// Bob disputes, revealing the terms to Carol
+	// Carol finds the bitcoin and releases the escrow to Bob
+	//   Alice has nothing
+	//   Bob has 5000 IOV
+	//   the escrow has nothing
+}
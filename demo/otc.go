@@ -0,0 +1,124 @@
+/*
+Package demo plays the stories the wallet demo tells, with signed
+txs on an app in memory. They double as documentation of the
+messages a wallet sends: run them with `bov demo`, or read the
+examples of this package.
+*/
+package demo
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/app"
+	"github.com/iov-one/bcp-demo/scenario"
+	"github.com/iov-one/bcp-demo/x/escrow"
+)
+
+// ChainID is the chain the demos sign their txs for
+const ChainID = "demo-chain"
+
+// OTCTrade plays an over the counter trade on a new node:
+//
+//  1. the desk funds Alice with 5000 IOV
+//  2. Alice buys a bitcoin from Bob. She escrows the IOV for him
+//     with Carol as arbiter, only the hash of the terms is public.
+//  3. Bob sends the bitcoin, but Alice does not release. Bob
+//     cannot release to himself.
+//  4. Bob disputes, revealing the terms to the arbiter
+//  5. Carol finds the bitcoin on its chain and releases to Bob
+//
+// Every step is written to out.
+func OTCTrade(out io.Writer) error {
+	genesis, err := (&scenario.Scenario{
+		Accounts: map[string][]string{"desk": {"100000 IOV"}},
+	}).GenesisFile(ChainID)
+	if err != nil {
+		return err
+	}
+	node, err := scenario.NewLocal(ChainID, genesis)
+	if err != nil {
+		return err
+	}
+	defer node.Close()
+
+	desk := scenario.NewWallet(node, "desk")
+	alice := scenario.NewWallet(node, "alice")
+	bob := scenario.NewWallet(node, "bob")
+	carol := scenario.NewWallet(node, "carol")
+	price := x.NewCoin(5000, 0, "IOV")
+	terms := "1 BTC to bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"
+	salt := []byte("alice and bob only")
+
+	fmt.Fprintln(out, "The desk funds Alice with 5000 IOV")
+	_, err = desk.Deliver(&app.Tx{Sum: &app.Tx_SendMsg{SendMsg: &cash.SendMsg{
+		Src: desk.Address(), Dest: alice.Address(), Amount: &price}}})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "Alice escrows them for Bob, Carol arbitrates")
+	id, err := alice.Deliver(&app.Tx{Sum: &app.Tx_CreateEscrowMsgV2{
+		CreateEscrowMsgV2: &escrow.CreateEscrowMsgV2{
+			Sender:    alice.Permission(),
+			Recipient: bob.Permission(),
+			Arbiter:   carol.Permission(),
+			Amount:    x.Coins{&price},
+			TimeoutIn: 100,
+			Options:   &escrow.EscrowOptions{MemoHash: escrow.MemoHash(salt, terms)},
+		}}})
+	if err != nil {
+		return err
+	}
+	accts := []account{{"Alice", alice.Address()}, {"Bob", bob.Address()},
+		{"the escrow", escrow.NewCondition(id).Address()}}
+	err = balances(out, node, accts...)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "Bob sends the bitcoin, Alice does not release")
+	release := &app.Tx{Sum: &app.Tx_ReleaseEscrowMsg{
+		ReleaseEscrowMsg: &escrow.ReleaseEscrowMsg{EscrowId: id}}}
+	_, err = bob.Deliver(release)
+	if err == nil {
+		return fmt.Errorf("the recipient released the escrow")
+	}
+	fmt.Fprintln(out, "Bob cannot release it himself")
+
+	fmt.Fprintln(out, "Bob disputes, revealing the terms to Carol")
+	_, err = bob.Deliver(&app.Tx{Sum: &app.Tx_RevealMemoMsg{
+		RevealMemoMsg: &escrow.RevealMemoMsg{EscrowId: id, Memo: terms, Salt: salt}}})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, "Carol finds the bitcoin and releases the escrow to Bob")
+	_, err = carol.Deliver(release)
+	if err != nil {
+		return err
+	}
+	return balances(out, node, accts...)
+}
+
+// account is an address with a name to print
+type account struct {
+	name string
+	addr weave.Address
+}
+
+// balances writes the coins of the accounts
+func balances(out io.Writer, node scenario.Node, accts ...account) error {
+	for _, acct := range accts {
+		coins, err := scenario.Balance(node, acct.addr)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "  %s has %s\n", acct.name, scenario.FormatCoins(coins))
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package gateway
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	abci "github.com/tendermint/abci/types"
+)
+
+const (
+	metricCacheHits   = "gateway_query_cache_hits_total"
+	metricCacheMisses = "gateway_query_cache_misses_total"
+)
+
+// Querier answers abci queries, like the app of a node or
+// a client of its rpc
+type Querier interface {
+	Query(req abci.RequestQuery) abci.ResponseQuery
+}
+
+// QueryCache answers the queries of the latest height from
+// memory. The answers of a height never change, so they are
+// kept until a block is committed after it. Queries of an
+// explicit height or with a proof always go to the node.
+type QueryCache struct {
+	node Querier
+
+	mtx     sync.Mutex
+	height  int64
+	entries map[cacheKey]abci.ResponseQuery
+	hits    int64
+	misses  int64
+}
+
+// cacheKey is a query at a height
+type cacheKey struct {
+	path   string
+	data   string
+	height int64
+}
+
+var _ Querier = (*QueryCache)(nil)
+
+// NewQueryCache returns an empty cache in front of node
+func NewQueryCache(node Querier) *QueryCache {
+	return &QueryCache{
+		node:    node,
+		entries: make(map[cacheKey]abci.ResponseQuery),
+	}
+}
+
+// Query fulfils Querier
+func (c *QueryCache) Query(req abci.RequestQuery) abci.ResponseQuery {
+	if req.Height != 0 || req.Prove {
+		return c.node.Query(req)
+	}
+	c.mtx.Lock()
+	key := cacheKey{path: req.Path, data: string(req.Data), height: c.height}
+	res, ok := c.entries[key]
+	if ok {
+		c.hits++
+	} else {
+		c.misses++
+	}
+	c.mtx.Unlock()
+	if ok {
+		return res
+	}
+
+	// not locked, so slow queries do not hold up the others
+	res = c.node.Query(req)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	// the node may have committed before the cache was told
+	c.advance(res.Height)
+	// a block committed meanwhile makes res stale
+	if res.Height == c.height {
+		key.height = res.Height
+		c.entries[key] = res
+	}
+	return res
+}
+
+// Commit drops the answers of the heights before the height of
+// the block committed last, as reported by the queries after it
+func (c *QueryCache) Commit(height int64) {
+	c.mtx.Lock()
+	c.advance(height)
+	c.mtx.Unlock()
+}
+
+// advance moves the cache to a later height, it must be
+// called with the lock held
+func (c *QueryCache) advance(height int64) {
+	if height > c.height {
+		c.height = height
+		c.entries = make(map[cacheKey]abci.ResponseQuery)
+	}
+}
+
+// Height is the latest height the cache knows of
+func (c *QueryCache) Height() int64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.height
+}
+
+// WriteMetrics writes the hits and misses in the text format
+// of prometheus
+func (c *QueryCache) WriteMetrics(w io.Writer) error {
+	c.mtx.Lock()
+	hits, misses := c.hits, c.misses
+	c.mtx.Unlock()
+
+	_, err := fmt.Fprintf(w, "# HELP %s Queries answered from the cache.\n"+
+		"# TYPE %s counter\n%s %d\n"+
+		"# HELP %s Queries sent to the node.\n"+
+		"# TYPE %s counter\n%s %d\n",
+		metricCacheHits, metricCacheHits, metricCacheHits, hits,
+		metricCacheMisses, metricCacheMisses, metricCacheMisses, misses)
+	return err
+}
@@ -0,0 +1,79 @@
+package gateway
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+)
+
+// countingNode answers every query with its path and data
+// at its height, and counts the queries
+type countingNode struct {
+	height  int64
+	queries int
+}
+
+func (n *countingNode) Query(req abci.RequestQuery) abci.ResponseQuery {
+	n.queries++
+	return abci.ResponseQuery{
+		Key:    []byte(req.Path),
+		Value:  req.Data,
+		Height: n.height,
+	}
+}
+
+func TestQueryCache(t *testing.T) {
+	node := &countingNode{height: 5}
+	cache := NewQueryCache(node)
+	escrow := abci.RequestQuery{Path: "/escrows", Data: []byte{1}}
+
+	res := cache.Query(escrow)
+	assert.Equal(t, []byte{1}, res.Value)
+	assert.Equal(t, int64(5), cache.Height())
+	cache.Query(escrow)
+	assert.Equal(t, 1, node.queries)
+
+	// other data, other entry
+	cache.Query(abci.RequestQuery{Path: "/escrows", Data: []byte{2}})
+	assert.Equal(t, 2, node.queries)
+
+	// explicit heights and proofs pass through
+	cache.Query(abci.RequestQuery{Path: "/escrows", Data: []byte{1}, Height: 5})
+	cache.Query(abci.RequestQuery{Path: "/escrows", Data: []byte{1}, Prove: true})
+	assert.Equal(t, 4, node.queries)
+
+	// a commit drops the entries
+	node.height = 6
+	cache.Commit(6)
+	res = cache.Query(escrow)
+	assert.Equal(t, int64(6), res.Height)
+	cache.Query(escrow)
+	assert.Equal(t, 5, node.queries)
+
+	// older heights do not go back
+	cache.Commit(4)
+	assert.Equal(t, int64(6), cache.Height())
+	cache.Query(escrow)
+	assert.Equal(t, 5, node.queries)
+
+	// the node committed before the cache was told
+	node.height = 7
+	cache.Query(abci.RequestQuery{Path: "/escrows", Data: []byte{3}})
+	assert.Equal(t, int64(7), cache.Height())
+	res = cache.Query(escrow)
+	assert.Equal(t, int64(7), res.Height)
+	assert.Equal(t, 7, node.queries)
+
+	var metrics bytes.Buffer
+	require.NoError(t, cache.WriteMetrics(&metrics))
+	assert.Equal(t, `# HELP gateway_query_cache_hits_total Queries answered from the cache.
+# TYPE gateway_query_cache_hits_total counter
+gateway_query_cache_hits_total 3
+# HELP gateway_query_cache_misses_total Queries sent to the node.
+# TYPE gateway_query_cache_misses_total counter
+gateway_query_cache_misses_total 5
+`, metrics.String())
+}
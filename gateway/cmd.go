@@ -0,0 +1,256 @@
+package gateway
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	abcicli "github.com/tendermint/abci/client"
+	abci "github.com/tendermint/abci/types"
+	"github.com/tendermint/tmlibs/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/confio/weave/errors"
+)
+
+// ConfigFile is where RunCmd reads its settings, relative to home
+const ConfigFile = "config/gateway.json"
+
+const (
+	defaultNode = "tcp://localhost:46658"
+	// defaultPoll is how often the height of the node is read
+	// if the config sets no poll interval
+	defaultPoll = time.Second
+)
+
+// Config holds the settings of the gateway process.
+//
+// Node is the abci address of the node to query, as it was given
+// to "bov start -bind". ChainID is the chain view tokens must be
+// issued for. The node is asked for its height every Poll
+// seconds, 0 for every second, to drop the cached queries once a
+// block is committed.
+//
+// GRPCAddr, if set, serves the Query service of a StreamServer
+// there, with the tls certificate files of the server if it has
+// them.
+type Config struct {
+	Node     string       `json:"node"`
+	ChainID  string       `json:"chain_id"`
+	Poll     int64        `json:"poll"`
+	Server   ServerConfig `json:"server"`
+	GRPCAddr string       `json:"grpc_addr"`
+	Keys     []KeyConfig  `json:"keys"`
+}
+
+// KeyConfig is an api key with its Limit. Period is in seconds.
+type KeyConfig struct {
+	Key    string  `json:"key"`
+	Name   string  `json:"name"`
+	Rate   float64 `json:"rate"`
+	Burst  int     `json:"burst"`
+	Quota  int64   `json:"quota"`
+	Period int64   `json:"period"`
+}
+
+// Limit is what the key may do
+func (k KeyConfig) Limit() Limit {
+	return Limit{
+		Name:   k.Name,
+		Rate:   k.Rate,
+		Burst:  k.Burst,
+		Quota:  k.Quota,
+		Period: time.Duration(k.Period) * time.Second,
+	}
+}
+
+// Validate makes sure the gateway can be set up
+func (c Config) Validate() error {
+	if c.ChainID == "" {
+		return fmt.Errorf("missing chain id")
+	}
+	if c.Poll < 0 {
+		return fmt.Errorf("negative poll interval")
+	}
+	for i, k := range c.Keys {
+		if k.Key == "" || k.Name == "" {
+			return fmt.Errorf("key %d needs a key and a name", i)
+		}
+		if k.Rate < 0 || k.Burst < 0 || k.Quota < 0 || k.Period < 0 {
+			return fmt.Errorf("negative limit of key %s", k.Name)
+		}
+		if k.Quota > 0 && k.Period == 0 {
+			return fmt.Errorf("quota of key %s needs a period", k.Name)
+		}
+	}
+	if c.GRPCAddr != "" && len(c.Server.TLS.ACMEHosts) != 0 {
+		return fmt.Errorf("grpc takes tls from files only")
+	}
+	return c.Server.Validate()
+}
+
+// LoadConfig reads the config file
+func LoadConfig(path string) (Config, error) {
+	cfg := Config{Node: defaultNode}
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	err = json.Unmarshal(bz, &cfg)
+	if err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// NewHandler serves the endpoints of the gateway, all of them
+// behind the returned Limiter of the keys of cfg, so prometheus
+// scrapes the metrics with a key of its own:
+//
+//	/query      a QueryEndpoint asking the returned QueryCache
+//	/envelopes  an EnvelopeEndpoint keeping the envelopes in memory
+//	/metrics    the metrics of the limiter and the cache
+//
+// The faucet needs a captcha and a key to send with, a gateway
+// offering it builds its own handler.
+func NewHandler(cfg Config, node Querier) (*Limiter, *QueryCache) {
+	store := NewMemStore()
+	for _, k := range cfg.Keys {
+		store.Set(k.Key, k.Limit())
+	}
+	cache := NewQueryCache(node)
+	mux := http.NewServeMux()
+	mux.Handle("/query", NewQueryEndpoint(cache, cfg.ChainID, NodeParties(cache)))
+	mux.Handle("/envelopes", NewEnvelopeEndpoint(NewMemEnvelopes()))
+	limiter := NewLimiter(mux, store)
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := limiter.WriteMetrics(w); err == nil {
+			cache.WriteMetrics(w)
+		}
+	})
+	return limiter, cache
+}
+
+// RunCmd serves the gateway of a node until it receives SIGINT
+// or SIGTERM, or loses the node. It reads ConfigFile in home, or
+// the file of the -config flag.
+func RunCmd(logger log.Logger, home string, args []string) error {
+	flags := flag.NewFlagSet("gateway", flag.ExitOnError)
+	file := flags.String("config", filepath.Join(home, ConfigFile), "settings file")
+	err := flags.Parse(args)
+	if err != nil {
+		return err
+	}
+	cfg, err := LoadConfig(*file)
+	if err != nil {
+		return err
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	cli := abcicli.NewSocketClient(cfg.Node, true)
+	cli.SetLogger(logger.With("module", "abci-client"))
+	err = cli.Start()
+	if err != nil {
+		return fmt.Errorf("cannot connect to node %s: %v", cfg.Node, err)
+	}
+	defer cli.Stop()
+
+	h, cache := NewHandler(cfg, nodeClient{cli})
+	svr, err := NewServer(cfg.Server, h, logger.With("module", "gateway"))
+	if err != nil {
+		return err
+	}
+	err = svr.Start()
+	if err != nil {
+		return err
+	}
+	defer svr.Close()
+	logger.Info("Starting gateway", "addr", svr.Addr(), "node", cfg.Node,
+		"chain_id", cfg.ChainID, "keys", len(cfg.Keys))
+
+	if cfg.GRPCAddr != "" {
+		stop, err := serveGRPC(cfg, h, nodeClient{cli}, logger.With("module", "grpc"))
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+
+	poll := defaultPoll
+	if cfg.Poll > 0 {
+		poll = time.Duration(cfg.Poll) * time.Second
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for {
+		select {
+		case sig := <-sigs:
+			logger.Info("Stopping gateway", "signal", sig)
+			return nil
+		case <-ticker.C:
+			info, err := cli.InfoSync(abci.RequestInfo{})
+			if err != nil {
+				return fmt.Errorf("lost node %s: %v", cfg.Node, err)
+			}
+			cache.Commit(info.LastBlockHeight)
+		}
+	}
+}
+
+// serveGRPC serves a StreamServer of node on cfg.GRPCAddr, with
+// the keys of limiter, until stop is called
+func serveGRPC(cfg Config, limiter *Limiter, node Querier,
+	logger log.Logger) (stop func(), err error) {
+
+	opts := []grpc.ServerOption{grpc.StreamInterceptor(limiter.StreamInterceptor)}
+	if t := cfg.Server.TLS; t.CertFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	ln, err := net.Listen("tcp", cfg.GRPCAddr)
+	if err != nil {
+		return nil, err
+	}
+	svr := grpc.NewServer(opts...)
+	RegisterQueryServer(svr, NewStreamServer(node))
+	go func() {
+		// Serve only returns nil once stopped
+		if err := svr.Serve(ln); err != nil {
+			logger.Error("Grpc stopped", "err", err)
+		}
+	}()
+	logger.Info("Starting grpc", "addr", ln.Addr())
+	return svr.Stop, nil
+}
+
+// nodeClient queries the node over its abci socket
+type nodeClient struct {
+	cli abcicli.Client
+}
+
+var _ Querier = nodeClient{}
+
+// Query fulfils Querier, a lost node answers an internal error
+func (n nodeClient) Query(req abci.RequestQuery) abci.ResponseQuery {
+	res, err := n.cli.QuerySync(req)
+	if err != nil || res == nil {
+		return abci.ResponseQuery{Code: errors.CodeInternalErr, Log: fmt.Sprintf("node: %v", err)}
+	}
+	return *res
+}
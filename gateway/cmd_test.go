@@ -0,0 +1,87 @@
+package gateway
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iov-one/bcp-demo/x/travelrule"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gateway")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	load := func(json string) (Config, error) {
+		file := filepath.Join(dir, "gateway.json")
+		require.NoError(t, ioutil.WriteFile(file, []byte(json), 0644))
+		return LoadConfig(file)
+	}
+
+	cfg, err := load(`{"chain_id": "test-chain", "server": {"addr": ":8080"},
+		"keys": [{"key": "secret", "name": "wallet", "rate": 1, "burst": 5,
+			"quota": 100, "period": 3600}]}`)
+	require.NoError(t, err)
+	assert.Equal(t, defaultNode, cfg.Node)
+	assert.Equal(t, ":8080", cfg.Server.Addr)
+	require.Len(t, cfg.Keys, 1)
+	assert.Equal(t, Limit{Name: "wallet", Rate: 1, Burst: 5, Quota: 100, Period: time.Hour},
+		cfg.Keys[0].Limit())
+
+	_, err = load(`{"server": {}}`)
+	assert.Error(t, err)
+	_, err = load(`{"chain_id": "test-chain", "keys": [{"key": "secret"}]}`)
+	assert.Error(t, err)
+	_, err = load(`{"chain_id": "test-chain", "keys": [{"key": "secret", "name": "w", "quota": 1}]}`)
+	assert.Error(t, err)
+	_, err = load(`{"chain_id": "test-chain", "server": {"tls": {"cert_file": "c.pem"}}}`)
+	assert.Error(t, err)
+	_, err = load(`{"chain_id": "test-chain", "grpc_addr": ":9090",
+		"server": {"tls": {"acme_hosts": ["demo.example.com"], "acme_cache": "certs"}}}`)
+	assert.Error(t, err)
+	_, err = LoadConfig(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestNewHandler(t *testing.T) {
+	node := &countingNode{height: 5}
+	cfg := Config{
+		ChainID: "test-chain",
+		Keys:    []KeyConfig{{Key: "secret", Name: "wallet"}},
+	}
+	h, cache := NewHandler(cfg, node)
+	get := func(target, key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", target, nil)
+		if key != "" {
+			r.Header.Set(KeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	// every endpoint needs a key
+	assert.Equal(t, http.StatusUnauthorized, get("/query?path=/escrows&data=01", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, get("/metrics", "other").Code)
+
+	// queries go through the cache
+	assert.Equal(t, http.StatusOK, get("/query?path=/escrows&data=01", "secret").Code)
+	assert.Equal(t, http.StatusOK, get("/query?path=/escrows&data=01", "secret").Code)
+	assert.Equal(t, 1, node.queries)
+	assert.Equal(t, int64(5), cache.Height())
+	unknown := hex.EncodeToString(travelrule.Hash([]byte("other")))
+	assert.Equal(t, http.StatusNotFound, get("/envelopes?hash="+unknown, "secret").Code)
+
+	w := get("/metrics", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `gateway_requests_total{key="wallet",status="ok"} 4`)
+	assert.Contains(t, w.Body.String(), "gateway_query_cache_hits_total 1")
+}
@@ -0,0 +1,137 @@
+package gateway
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// MaxEnvelopeSize bounds the body of an envelope, the sealed
+// payload of a transfer is far smaller
+const MaxEnvelopeSize = 64 << 10
+
+// EnvelopeStore keeps the sealed travel rule envelopes by their
+// hash. Like a Store, hosts sharing their envelopes implement it
+// on a shared db.
+type EnvelopeStore interface {
+	// Put keeps the envelope under the hash
+	Put(hash, envelope []byte) error
+	// Get returns the envelope of the hash, nil if unknown
+	Get(hash []byte) ([]byte, error)
+}
+
+// MemEnvelopes keeps the envelopes in memory of this host, they
+// are gone after a restart
+type MemEnvelopes struct {
+	mtx       sync.Mutex
+	envelopes map[string][]byte
+}
+
+var _ EnvelopeStore = (*MemEnvelopes)(nil)
+
+// NewMemEnvelopes returns a store without any envelopes
+func NewMemEnvelopes() *MemEnvelopes {
+	return &MemEnvelopes{envelopes: make(map[string][]byte)}
+}
+
+// Put fulfils EnvelopeStore
+func (s *MemEnvelopes) Put(hash, envelope []byte) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.envelopes[string(hash)] = envelope
+	return nil
+}
+
+// Get fulfils EnvelopeStore
+func (s *MemEnvelopes) Get(hash []byte) ([]byte, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.envelopes[string(hash)], nil
+}
+
+// EnvelopeEndpoint is the http.Handler relaying the travel rule
+// envelopes between VASPs. The originating VASP POSTs the sealed
+// envelope and sets the hash it gets back as travel_rule_hash of
+// its tx. The beneficiary VASP finds the hash in the "travelrule"
+// tag of the tx and GETs the envelope with the hex hash as form
+// value "hash". Only the holder of the key it is sealed to can
+// open it, so the endpoint needs no more than an api key.
+type EnvelopeEndpoint struct {
+	store EnvelopeStore
+}
+
+var _ http.Handler = EnvelopeEndpoint{}
+
+// envelopeResult is the body answering a POST
+type envelopeResult struct {
+	Hash string `json:"hash"`
+}
+
+// NewEnvelopeEndpoint returns an EnvelopeEndpoint keeping the
+// envelopes in store
+func NewEnvelopeEndpoint(store EnvelopeStore) EnvelopeEndpoint {
+	return EnvelopeEndpoint{store: store}
+}
+
+// ServeHTTP answers 201 with the hex hash of a stored envelope
+// and 404 for the hash of none
+func (e EnvelopeEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		e.put(w, r)
+	case http.MethodGet:
+		e.get(w, r)
+	default:
+		http.Error(w, "GET or POST only", http.StatusMethodNotAllowed)
+	}
+}
+
+func (e EnvelopeEndpoint) put(w http.ResponseWriter, r *http.Request) {
+	envelope, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, MaxEnvelopeSize))
+	if err != nil {
+		http.Error(w, "envelope: "+err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if len(envelope) == 0 {
+		http.Error(w, "empty envelope", http.StatusBadRequest)
+		return
+	}
+	// the same as travelrule.Hash
+	hash := sha256.Sum256(envelope)
+	if err := e.store.Put(hash[:], envelope); err != nil {
+		http.Error(w, "envelopes: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	body, err := json.Marshal(envelopeResult{Hash: hex.EncodeToString(hash[:])})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	w.Write(body)
+}
+
+func (e EnvelopeEndpoint) get(w http.ResponseWriter, r *http.Request) {
+	hash, err := hex.DecodeString(r.FormValue("hash"))
+	if err != nil || len(hash) != sha256.Size {
+		http.Error(w, "invalid hash", http.StatusBadRequest)
+		return
+	}
+	envelope, err := e.store.Get(hash)
+	switch {
+	case err != nil:
+		http.Error(w, "envelopes: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	case envelope == nil:
+		http.NotFound(w, r)
+		return
+	}
+	// the envelope of a hash never changes
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(envelope)
+}
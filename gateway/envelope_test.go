@@ -0,0 +1,55 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iov-one/bcp-demo/x/travelrule"
+)
+
+func TestEnvelopeEndpoint(t *testing.T) {
+	endpoint := NewEnvelopeEndpoint(NewMemEnvelopes())
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		endpoint.ServeHTTP(w, r)
+		return w
+	}
+
+	envelope := []byte("sealed payload")
+	w := serve(httptest.NewRequest("POST", "/envelopes", bytes.NewReader(envelope)))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var res envelopeResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	// the hash is the one the tx carries
+	assert.Equal(t, hex.EncodeToString(travelrule.Hash(envelope)), res.Hash)
+
+	w = serve(httptest.NewRequest("GET", "/envelopes?hash="+res.Hash, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, envelope, w.Body.Bytes())
+
+	// unknown and invalid hashes
+	unknown := hex.EncodeToString(travelrule.Hash([]byte("other")))
+	w = serve(httptest.NewRequest("GET", "/envelopes?hash="+unknown, nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	w = serve(httptest.NewRequest("GET", "/envelopes?hash=0102", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = serve(httptest.NewRequest("GET", "/envelopes?hash=zz", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// empty and oversized envelopes
+	w = serve(httptest.NewRequest("POST", "/envelopes", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	big := make([]byte, MaxEnvelopeSize+1)
+	w = serve(httptest.NewRequest("POST", "/envelopes", bytes.NewReader(big)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+	w = serve(httptest.NewRequest("DELETE", "/envelopes", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
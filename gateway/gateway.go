@@ -0,0 +1,169 @@
+/*
+Package gateway guards the public http endpoints of the demo chain,
+like the faucet, with api keys. Every key has a Limit on its rate
+and a quota; a Limiter wrapping the endpoint refuses the requests
+beyond them and counts the requests of every key for prometheus.
+
+The keys and their usage live in a Store. A MemStore is enough for
+a single host; hosts sharing their keys implement Store on a shared
+db, with Counter doing the counting.
+
+A QueryEndpoint serves the abci queries to rest clients, with
+ETags so polling clients only get a result once it changes, and
+the history of an account only to a view token of its owner. A
+QueryCache in front of the node answers the queries of the latest
+height from memory until the next commit.
+
+An EnvelopeEndpoint relays the sealed travel rule envelopes of
+large transfers between VASPs, by the hash their txs carry.
+
+A StreamServer streams big buckets over grpc, in chunks of one
+page query each, with a cursor to resume from.
+
+"bov gateway" (see RunCmd) serves the queries and envelopes of a
+node as set in a Config, with the keys listed there. A gateway
+offering more, like the faucet, wraps the endpoints itself:
+
+	store := gateway.NewMemStore()
+	key, err := store.Issue(gateway.Limit{Name: "wallet", Rate: 1, Burst: 5})
+	http.Handle("/faucet", gateway.NewLimiter(faucet.NewEndpoint(captcha, send), store))
+	cache := gateway.NewQueryCache(node)
+	query := gateway.NewQueryEndpoint(cache, chainID, gateway.NodeParties(cache))
+	http.Handle("/query", gateway.NewLimiter(query, store))
+	http.Handle("/envelopes", gateway.NewLimiter(gateway.NewEnvelopeEndpoint(gateway.NewMemEnvelopes()), store))
+	// and on every new block: cache.Commit(height)
+
+A Server serves them with the settings of a ServerConfig: tls from
+certificate files or let's encrypt, CORS for the pages of wallets,
+and a log of every request naming the client behind a reverse proxy.
+*/
+package gateway
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// KeyHeader carries the api key of a request
+const KeyHeader = "X-Api-Key"
+
+const metricRequests = "gateway_requests_total"
+
+// Limiter is the http.Handler passing the requests with a key
+// within its limit on to the next handler
+type Limiter struct {
+	next  http.Handler
+	store Store
+	now   func() time.Time
+
+	mtx    sync.Mutex
+	counts map[request]int64
+}
+
+// request is a label set of the metrics
+type request struct {
+	name   string
+	status string
+}
+
+var _ http.Handler = (*Limiter)(nil)
+
+// NewLimiter returns a Limiter of the keys in store in
+// front of next
+func NewLimiter(next http.Handler, store Store) *Limiter {
+	return &Limiter{
+		next:   next,
+		store:  store,
+		now:    time.Now,
+		counts: make(map[request]int64),
+	}
+}
+
+// ServeHTTP answers 401 without a valid key and 429 beyond its
+// limit, telling the client when to retry. The remaining quota
+// is in the header X-Quota-Remaining.
+func (l *Limiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get(KeyHeader)
+	if key == "" {
+		l.count("", StatusUnknownKey)
+		http.Error(w, "missing "+KeyHeader, http.StatusUnauthorized)
+		return
+	}
+	usage, limit, err := l.store.Take(key, l.now())
+	switch {
+	case err == ErrUnknownKey:
+		// unknown keys are counted together, so clients
+		// cannot add labels to the metrics
+		l.count("", StatusUnknownKey)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	case err != nil:
+		l.count(limit.Name, StatusError)
+		http.Error(w, "api keys: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	l.count(limit.Name, usage.Status)
+
+	if usage.Remaining >= 0 {
+		w.Header().Set("X-Quota-Remaining", strconv.FormatInt(usage.Remaining, 10))
+	}
+	if usage.Status != StatusOK {
+		w.Header().Set("Retry-After", strconv.FormatInt(retrySeconds(usage.RetryAfter), 10))
+		http.Error(w, usage.Status, http.StatusTooManyRequests)
+		return
+	}
+	l.next.ServeHTTP(w, r)
+}
+
+// retrySeconds rounds d up to whole seconds, as Retry-After
+// takes no fractions
+func retrySeconds(d time.Duration) int64 {
+	return int64((d + time.Second - 1) / time.Second)
+}
+
+// count counts a request for the metrics
+func (l *Limiter) count(name, status string) {
+	l.mtx.Lock()
+	l.counts[request{name: name, status: status}]++
+	l.mtx.Unlock()
+}
+
+// WriteMetrics writes the requests by key and status in the
+// text format of prometheus
+func (l *Limiter) WriteMetrics(w io.Writer) error {
+	l.mtx.Lock()
+	reqs := make([]request, 0, len(l.counts))
+	for req := range l.counts {
+		reqs = append(reqs, req)
+	}
+	sort.Slice(reqs, func(i, j int) bool {
+		if reqs[i].name != reqs[j].name {
+			return reqs[i].name < reqs[j].name
+		}
+		return reqs[i].status < reqs[j].status
+	})
+	counts := make([]int64, len(reqs))
+	for i, req := range reqs {
+		counts[i] = l.counts[req]
+	}
+	l.mtx.Unlock()
+
+	_, err := fmt.Fprintf(w, "# HELP %s Requests to the gateway, by key and status.\n"+
+		"# TYPE %s counter\n", metricRequests, metricRequests)
+	if err != nil {
+		return err
+	}
+	for i, req := range reqs {
+		_, err := fmt.Fprintf(w, "%s{key=%q,status=%q} %d\n",
+			metricRequests, req.name, req.status, counts[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
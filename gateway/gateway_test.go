@@ -0,0 +1,87 @@
+package gateway
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	store := NewMemStore()
+	store.Set("secret", Limit{Name: "wallet", Rate: 1, Burst: 2, Quota: 3, Period: time.Hour})
+	var served int
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { served++ })
+	lim := NewLimiter(ok, store)
+	clock := time.Unix(1000, 0)
+	lim.now = func() time.Time { return clock }
+
+	call := func(key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/faucet", nil)
+		if key != "" {
+			r.Header.Set(KeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		lim.ServeHTTP(w, r)
+		return w
+	}
+
+	w := call("")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = call("guess")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = call("secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("X-Quota-Remaining"))
+	w = call("secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// the burst is used up
+	w = call("secret")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	// the quota is used up
+	clock = clock.Add(10 * time.Second)
+	w = call("secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = call("secret")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-Quota-Remaining"))
+	assert.Equal(t, "3590", w.Header().Get("Retry-After"))
+	assert.Equal(t, 3, served)
+
+	var metrics bytes.Buffer
+	require.NoError(t, lim.WriteMetrics(&metrics))
+	assert.Equal(t, `# HELP gateway_requests_total Requests to the gateway, by key and status.
+# TYPE gateway_requests_total counter
+gateway_requests_total{key="",status="unknown_key"} 2
+gateway_requests_total{key="wallet",status="limited"} 1
+gateway_requests_total{key="wallet",status="ok"} 3
+gateway_requests_total{key="wallet",status="over_quota"} 1
+`, metrics.String())
+}
+
+// brokenStore fails like a db that is down
+type brokenStore struct{}
+
+func (brokenStore) Take(string, time.Time) (Usage, Limit, error) {
+	return Usage{}, Limit{}, errors.New("db is down")
+}
+
+func TestLimiterStoreFails(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("served without checking the key")
+	})
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(KeyHeader, "secret")
+	w := httptest.NewRecorder()
+	NewLimiter(ok, brokenStore{}).ServeHTTP(w, r)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,170 @@
+package gateway
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+
+	"github.com/iov-one/bcp-demo/viewtoken"
+	"github.com/iov-one/bcp-demo/x/escrow"
+)
+
+// QueryEndpoint is the http.Handler of the queries for rest
+// clients. It takes a GET with the query path as form value
+// "path" and the hex data as "data", and answers the keys and
+// values of the result as json, with the height in the header
+// X-Height.
+//
+// The ETag is a hash of the body, so it stays the same for as
+// long as the result does, whatever the height. A client sending
+// it back in If-None-Match gets 304 until the result changes, eg.
+// when polling an escrow. Put a QueryCache in front of the node
+// to also spare it the queries.
+//
+// The paths of viewtoken.Paths are only about one account, so
+// they need a view token of the owner in the header
+// viewtoken.ViewTokenHeader that authorizes the query.
+type QueryEndpoint struct {
+	node    Querier
+	chainID string
+	parties viewtoken.EscrowParties
+	now     func() time.Time
+}
+
+var _ http.Handler = QueryEndpoint{}
+
+// queryResult is the body of a QueryEndpoint
+type queryResult struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// NewQueryEndpoint returns a QueryEndpoint asking node, that
+// accepts view tokens for chainID. parties tells the parties of
+// an escrow for the queries of its history, see NodeParties.
+func NewQueryEndpoint(node Querier, chainID string,
+	parties viewtoken.EscrowParties) QueryEndpoint {
+
+	return QueryEndpoint{
+		node:    node,
+		chainID: chainID,
+		parties: parties,
+		now:     time.Now,
+	}
+}
+
+// ServeHTTP answers 400 if the query fails, 401 without a valid
+// view token for a path that needs one and 403 if the token does
+// not authorize the query
+func (e QueryEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "GET only", http.StatusMethodNotAllowed)
+		return
+	}
+	data, err := hex.DecodeString(r.FormValue("data"))
+	if err != nil {
+		http.Error(w, "data: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	path := r.FormValue("path")
+	if status, err := e.authorize(r, path, data); err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+	res := e.node.Query(abci.RequestQuery{Path: path, Data: data})
+	if res.Code != 0 {
+		http.Error(w, res.Log, http.StatusBadRequest)
+		return
+	}
+	body, err := json.Marshal(queryResult{Key: res.Key, Value: res.Value})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	hash := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(hash[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("X-Height", strconv.FormatInt(res.Height, 10))
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// authorize checks the view token of the request if the path
+// needs one, and returns the status to answer if not authorized
+func (e QueryEndpoint) authorize(r *http.Request, path string, data []byte) (int, error) {
+	// split the mod as the app does
+	mod := weave.KeyQueryMod
+	if i := strings.Index(path, "?"); i >= 0 {
+		path, mod = path[:i], path[i+1:]
+	}
+	if !contains(viewtoken.Paths, path) {
+		return http.StatusOK, nil
+	}
+	raw := r.Header.Get(viewtoken.ViewTokenHeader)
+	if raw == "" {
+		return http.StatusUnauthorized, fmt.Errorf("missing %s", viewtoken.ViewTokenHeader)
+	}
+	token, err := viewtoken.Parse(raw, e.chainID, e.now())
+	if err != nil {
+		return http.StatusUnauthorized, fmt.Errorf("view token: %v", err)
+	}
+	err = token.Authorize(path, mod, data, e.parties)
+	if err != nil {
+		return http.StatusForbidden, err
+	}
+	return http.StatusOK, nil
+}
+
+// NodeParties returns the sender, arbiter and recipient of an
+// escrow as node answers it on "/escrows". Escrows that are closed
+// have none, so their history needs the token of a gateway keeping
+// the parties itself.
+func NodeParties(node Querier) viewtoken.EscrowParties {
+	return func(id []byte) ([]weave.Address, error) {
+		res := node.Query(abci.RequestQuery{Path: "/escrows", Data: id})
+		if res.Code != 0 {
+			return nil, fmt.Errorf("escrow: %s", res.Log)
+		}
+		var values app.ResultSet
+		err := values.Unmarshal(res.Value)
+		if err != nil || len(values.Results) == 0 {
+			return nil, err
+		}
+		obj, err := escrow.NewBucket().Parse(id, values.Results[0])
+		if err != nil {
+			return nil, err
+		}
+		esc := escrow.AsEscrow(obj)
+		var addrs []weave.Address
+		for _, perm := range [][]byte{esc.Sender, esc.Arbiter, esc.Recipient} {
+			if len(perm) != 0 {
+				addrs = append(addrs, weave.Permission(perm).Address())
+			}
+		}
+		return addrs, nil
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
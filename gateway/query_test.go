@@ -0,0 +1,135 @@
+package gateway
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/crypto"
+
+	"github.com/iov-one/bcp-demo/viewtoken"
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/txindex"
+)
+
+// failingNode knows no query path
+type failingNode struct{}
+
+func (failingNode) Query(req abci.RequestQuery) abci.ResponseQuery {
+	return abci.ResponseQuery{Code: 1, Log: "Unexpected Query path: " + req.Path}
+}
+
+func TestQueryEndpoint(t *testing.T) {
+	node := &countingNode{height: 5}
+	endpoint := NewQueryEndpoint(node, "test-chain", nil)
+	get := func(target, etag string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", target, nil)
+		if etag != "" {
+			r.Header.Set("If-None-Match", etag)
+		}
+		w := httptest.NewRecorder()
+		endpoint.ServeHTTP(w, r)
+		return w
+	}
+
+	w := get("/query?path=/escrows&data=0102", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"key": "L2VzY3Jvd3M=", "value": "AQI="}`, w.Body.String())
+	assert.Equal(t, "5", w.Header().Get("X-Height"))
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	// the same result at a later height is not modified
+	node.height = 6
+	w = get("/query?path=/escrows&data=0102", etag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, "6", w.Header().Get("X-Height"))
+
+	// another result is
+	w = get("/query?path=/escrows&data=0103", etag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+
+	w = get("/query?path=/escrows&data=zz", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = httptest.NewRecorder()
+	endpoint.ServeHTTP(w, httptest.NewRequest("POST", "/query", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	w = httptest.NewRecorder()
+	NewQueryEndpoint(failingNode{}, "test-chain", nil).ServeHTTP(w, httptest.NewRequest("GET", "/query?path=/nope", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Unexpected Query path: /nope")
+}
+
+// escrowNode answers "/escrows" with its escrows
+type escrowNode map[string]*escrow.Escrow
+
+func (n escrowNode) Query(req abci.RequestQuery) abci.ResponseQuery {
+	var values app.ResultSet
+	if esc, ok := n[string(req.Data)]; ok && req.Path == "/escrows" {
+		bz, _ := esc.Marshal()
+		values.Results = append(values.Results, bz)
+	}
+	bz, _ := values.Marshal()
+	return abci.ResponseQuery{Key: []byte(req.Path), Value: bz}
+}
+
+func TestQueryEndpointViewToken(t *testing.T) {
+	owner := crypto.GenPrivKeyEd25519()
+	addr := owner.PublicKey().Permission().Address()
+	other := weave.NewAddress([]byte("other"))
+	node := escrowNode{
+		"\x01": {Sender: owner.PublicKey().Permission(), Recipient: other},
+		"\x02": {Sender: crypto.GenPrivKeyEd25519().PublicKey().Permission(), Recipient: other},
+	}
+	endpoint := NewQueryEndpoint(node, "test-chain", NodeParties(node))
+	endpoint.now = func() time.Time { return time.Unix(1000, 0) }
+
+	grant := viewtoken.Grant{
+		ChainID: "test-chain",
+		Viewer:  "accountant",
+		Paths:   []string{txindex.QueryAccount, escrow.QueryHistory},
+		Expires: 2000,
+	}
+	token, err := viewtoken.Issue(owner, grant)
+	require.NoError(t, err)
+	grant.ChainID = "other-chain"
+	elsewhere, err := viewtoken.Issue(owner, grant)
+	require.NoError(t, err)
+
+	get := func(path string, data []byte, token string) int {
+		r := httptest.NewRequest("GET", "/query?path="+path+"&data="+hex.EncodeToString(data), nil)
+		if token != "" {
+			r.Header.Set(viewtoken.ViewTokenHeader, token)
+		}
+		w := httptest.NewRecorder()
+		endpoint.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// other paths need no token
+	assert.Equal(t, http.StatusOK, get("/escrows", []byte{1}, ""))
+	// those of an account do
+	assert.Equal(t, http.StatusUnauthorized, get(txindex.QueryAccount, addr, ""))
+	assert.Equal(t, http.StatusUnauthorized, get(txindex.QueryAccount, addr, "junk"))
+	assert.Equal(t, http.StatusUnauthorized, get(txindex.QueryAccount, addr, elsewhere))
+	// and only for the owner
+	assert.Equal(t, http.StatusOK, get(txindex.QueryAccount, addr, token))
+	assert.Equal(t, http.StatusForbidden, get(txindex.QueryAccount, other, token))
+	assert.Equal(t, http.StatusOK, get(escrow.QueryHistory, []byte{1}, token))
+	assert.Equal(t, http.StatusForbidden, get(escrow.QueryHistory, []byte{2}, token))
+	assert.Equal(t, http.StatusForbidden, get(escrow.QueryHistory, []byte{3}, token))
+	assert.Equal(t, http.StatusForbidden, get(escrow.QueryHistory+"?prefix", []byte{1}, token))
+	// paths not granted
+	assert.Equal(t, http.StatusForbidden, get("/escrows/sender", addr, token))
+}
@@ -0,0 +1,323 @@
+package gateway
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tendermint/tmlibs/log"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// ServerConfig sets the http server of the gateway. It serves
+// plain http, as behind a reverse proxy terminating tls, unless
+// TLS is set.
+//
+// The clients are logged by the address they connect from. Behind
+// a proxy that is the proxy, so list its addresses or networks
+// in TrustedProxies to log the client in its X-Forwarded-For
+// header instead.
+type ServerConfig struct {
+	Addr           string     `json:"addr"`
+	TLS            TLSConfig  `json:"tls"`
+	CORS           CORSConfig `json:"cors"`
+	TrustedProxies []string   `json:"trusted_proxies"`
+}
+
+// TLSConfig serves https, with the certificate in CertFile and
+// KeyFile or with certificates of ACMEHosts from let's encrypt.
+// ACME keeps the certificates in ACMECache, a directory, and
+// answers its challenges on ACMEAddr, ":80" by default, where
+// it also redirects to https.
+type TLSConfig struct {
+	CertFile  string   `json:"cert_file"`
+	KeyFile   string   `json:"key_file"`
+	ACMEHosts []string `json:"acme_hosts"`
+	ACMECache string   `json:"acme_cache"`
+	ACMEAddr  string   `json:"acme_addr"`
+}
+
+// CORSConfig lets the pages of Origins call the gateway from a
+// browser, "*" for any page. MaxAge is the seconds a browser may
+// remember the answer to a preflight request.
+type CORSConfig struct {
+	Origins []string `json:"origins"`
+	MaxAge  int64    `json:"max_age"`
+}
+
+const defaultACMEAddr = ":80"
+
+// the headers of requests and answers the gateway uses,
+// a browser hides all others from the page
+var (
+	corsAllowHeaders  = strings.Join([]string{KeyHeader, "If-None-Match", "Content-Type"}, ", ")
+	corsExposeHeaders = strings.Join([]string{"ETag", "X-Height", "X-Quota-Remaining", "Retry-After"}, ", ")
+)
+
+// Validate makes sure the server can be set up
+func (c ServerConfig) Validate() error {
+	t := c.TLS
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("tls needs both a cert and a key file")
+	}
+	if t.CertFile != "" && len(t.ACMEHosts) != 0 {
+		return fmt.Errorf("tls from files and from acme at once")
+	}
+	if len(t.ACMEHosts) != 0 && t.ACMECache == "" {
+		return fmt.Errorf("acme needs a cache directory")
+	}
+	for _, origin := range c.CORS.Origins {
+		if origin != "*" && !strings.HasPrefix(origin, "http://") &&
+			!strings.HasPrefix(origin, "https://") {
+			return fmt.Errorf("invalid cors origin %q", origin)
+		}
+	}
+	if c.CORS.MaxAge < 0 {
+		return fmt.Errorf("negative cors max age")
+	}
+	_, err := parseNets(c.TrustedProxies)
+	return err
+}
+
+// parseNets reads addresses and networks in CIDR notation
+func parseNets(addrs []string) ([]*net.IPNet, error) {
+	res := make([]*net.IPNet, 0, len(addrs))
+	for _, addr := range addrs {
+		if !strings.Contains(addr, "/") {
+			ip := net.ParseIP(addr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid proxy address %q", addr)
+			}
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			addr = fmt.Sprintf("%s/%d", addr, bits)
+		}
+		_, ipnet, err := net.ParseCIDR(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy address %q", addr)
+		}
+		res = append(res, ipnet)
+	}
+	return res, nil
+}
+
+// Server serves the gateway with the settings of a ServerConfig
+type Server struct {
+	svr       *http.Server
+	challenge *http.Server
+	addr      string
+	acmeAddr  string
+	logger    log.Logger
+	listener  net.Listener
+}
+
+// NewServer serves h as set by cfg, logging every request and
+// why the server stopped. It reads the tls certificate, if any,
+// but does not listen until Start.
+func NewServer(cfg ServerConfig, h http.Handler, logger log.Logger) (*Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	proxies, _ := parseNets(cfg.TrustedProxies)
+	s := &Server{
+		svr: &http.Server{
+			Addr:    cfg.Addr,
+			Handler: logRequests(withCORS(h, cfg.CORS), proxies, logger),
+		},
+		addr:   cfg.Addr,
+		logger: logger,
+	}
+
+	t := cfg.TLS
+	switch {
+	case t.CertFile != "":
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		s.svr.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	case len(t.ACMEHosts) != 0:
+		m := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(t.ACMEHosts...),
+			Cache:      autocert.DirCache(t.ACMECache),
+		}
+		s.svr.TLSConfig = &tls.Config{GetCertificate: m.GetCertificate}
+		s.challenge = &http.Server{Handler: m.HTTPHandler(nil)}
+		s.acmeAddr = t.ACMEAddr
+		if s.acmeAddr == "" {
+			s.acmeAddr = defaultACMEAddr
+		}
+	}
+	return s, nil
+}
+
+// Start listens and serves in the background, until the
+// server is closed
+func (s *Server) Start() error {
+	if s.challenge != nil {
+		ln, err := net.Listen("tcp", s.acmeAddr)
+		if err != nil {
+			return err
+		}
+		go s.serve(s.challenge, ln, false, "ACME challenges stopped")
+	}
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		if s.challenge != nil {
+			s.challenge.Close()
+		}
+		return err
+	}
+	s.listener = ln
+	go s.serve(s.svr, ln, s.svr.TLSConfig != nil, "Gateway stopped")
+	return nil
+}
+
+// serve logs why svr stopped, unless it was closed
+func (s *Server) serve(svr *http.Server, ln net.Listener, withTLS bool, msg string) {
+	var err error
+	if withTLS {
+		err = svr.ServeTLS(ln, "", "")
+	} else {
+		err = svr.Serve(ln)
+	}
+	if err != http.ErrServerClosed {
+		s.logger.Error(msg, "err", err)
+	}
+}
+
+// Addr is where the server listens once started, eg. to
+// find the port picked for ":0"
+func (s *Server) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Close stops serving at once
+func (s *Server) Close() error {
+	if s.challenge != nil {
+		s.challenge.Close()
+	}
+	return s.svr.Close()
+}
+
+// withCORS answers the preflight requests of browsers itself,
+// as they carry no api key, and lets the pages of the origins
+// read the answers of all others
+func withCORS(next http.Handler, cfg CORSConfig) http.Handler {
+	if len(cfg.Origins) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		allowed := corsAllowed(cfg.Origins, origin)
+		w.Header().Add("Vary", "Origin")
+		if allowed {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Expose-Headers", corsExposeHeaders)
+		}
+		if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !allowed {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST")
+		w.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders)
+		if cfg.MaxAge > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.FormatInt(cfg.MaxAge, 10))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// corsAllowed is true if origin is one of origins
+func corsAllowed(origins []string, origin string) bool {
+	if origin == "" {
+		return false
+	}
+	for _, o := range origins {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// statusWriter remembers the status of the answer
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests logs every request with its client
+func logRequests(next http.Handler, proxies []*net.IPNet, logger log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		logger.Info("Request", "method", r.Method, "path", r.URL.Path,
+			"status", sw.status, "client", clientIP(r, proxies),
+			"took", time.Since(start))
+	})
+}
+
+// clientIP is the address the request comes from. If that is a
+// trusted proxy, it is the last address in X-Forwarded-For not
+// of a trusted proxy, as every proxy appends the address its
+// request came from.
+func clientIP(r *http.Request, proxies []*net.IPNet) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !trusted(proxies, host) {
+		return host
+	}
+	var hops []string
+	for _, header := range r.Header["X-Forwarded-For"] {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		host = hop
+		if !trusted(proxies, hop) {
+			break
+		}
+	}
+	return host
+}
+
+// trusted is true if addr is in one of the proxy networks
+func trusted(proxies []*net.IPNet, addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, ipnet := range proxies {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
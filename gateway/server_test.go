@@ -0,0 +1,229 @@
+package gateway
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tmlibs/log"
+)
+
+func TestServerConfigValidate(t *testing.T) {
+	cases := map[string]struct {
+		cfg     ServerConfig
+		wantErr bool
+	}{
+		"plain": {cfg: ServerConfig{Addr: ":8080"}},
+		"tls files": {
+			cfg: ServerConfig{TLS: TLSConfig{CertFile: "c.pem", KeyFile: "k.pem"}},
+		},
+		"cert without key": {
+			cfg:     ServerConfig{TLS: TLSConfig{CertFile: "c.pem"}},
+			wantErr: true,
+		},
+		"acme": {
+			cfg: ServerConfig{TLS: TLSConfig{ACMEHosts: []string{"demo.iov.one"}, ACMECache: "certs"}},
+		},
+		"acme without cache": {
+			cfg:     ServerConfig{TLS: TLSConfig{ACMEHosts: []string{"demo.iov.one"}}},
+			wantErr: true,
+		},
+		"files and acme": {
+			cfg: ServerConfig{TLS: TLSConfig{CertFile: "c.pem", KeyFile: "k.pem",
+				ACMEHosts: []string{"demo.iov.one"}, ACMECache: "certs"}},
+			wantErr: true,
+		},
+		"origins": {
+			cfg: ServerConfig{CORS: CORSConfig{Origins: []string{"*", "https://wallet.iov.one"}}},
+		},
+		"origin without scheme": {
+			cfg:     ServerConfig{CORS: CORSConfig{Origins: []string{"wallet.iov.one"}}},
+			wantErr: true,
+		},
+		"negative max age": {
+			cfg:     ServerConfig{CORS: CORSConfig{MaxAge: -1}},
+			wantErr: true,
+		},
+		"proxies": {
+			cfg: ServerConfig{TrustedProxies: []string{"10.0.0.0/8", "127.0.0.1", "::1"}},
+		},
+		"invalid proxy": {
+			cfg:     ServerConfig{TrustedProxies: []string{"proxy.local"}},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCORS(t *testing.T) {
+	var served int
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { served++ })
+	h := withCORS(ok, CORSConfig{Origins: []string{"https://wallet.iov.one"}, MaxAge: 600})
+	call := func(method, origin string, preflight bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/query", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		if preflight {
+			r.Header.Set("Access-Control-Request-Method", "GET")
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := call("OPTIONS", "https://wallet.iov.one", true)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://wallet.iov.one", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), KeyHeader)
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	assert.Equal(t, 0, served)
+
+	w = call("OPTIONS", "https://evil.example", true)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = call("GET", "https://wallet.iov.one", false)
+	assert.Equal(t, "https://wallet.iov.one", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "ETag")
+
+	// the browser hides the answer from other pages
+	w = call("GET", "https://evil.example", false)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	w = call("GET", "", false)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, 3, served)
+}
+
+func TestClientIP(t *testing.T) {
+	proxies, err := parseNets([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	cases := map[string]struct {
+		remote    string
+		forwarded []string
+		want      string
+	}{
+		"direct": {
+			remote: "1.2.3.4:5000",
+			want:   "1.2.3.4",
+		},
+		"untrusted forwarded for": {
+			remote:    "1.2.3.4:5000",
+			forwarded: []string{"5.6.7.8"},
+			want:      "1.2.3.4",
+		},
+		"behind a proxy": {
+			remote:    "10.0.0.1:5000",
+			forwarded: []string{"5.6.7.8"},
+			want:      "5.6.7.8",
+		},
+		"client lies": {
+			remote:    "10.0.0.1:5000",
+			forwarded: []string{"6.6.6.6, 5.6.7.8"},
+			want:      "5.6.7.8",
+		},
+		"behind two proxies": {
+			remote:    "10.0.0.1:5000",
+			forwarded: []string{"5.6.7.8, 192.168.1.1", "10.0.0.2"},
+			want:      "5.6.7.8",
+		},
+		"proxy without header": {
+			remote: "10.0.0.1:5000",
+			want:   "10.0.0.1",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tc.remote
+			for _, f := range tc.forwarded {
+				r.Header.Add("X-Forwarded-For", f)
+			}
+			assert.Equal(t, tc.want, clientIP(r, proxies))
+		})
+	}
+}
+
+func TestServerTLS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gateway")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	certFile, keyFile := writeCert(t, dir)
+
+	cfg := ServerConfig{
+		Addr: "127.0.0.1:0",
+		TLS:  TLSConfig{CertFile: certFile, KeyFile: keyFile},
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello"))
+	})
+	svr, err := NewServer(cfg, ok, log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, svr.Start())
+	defer svr.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+	res, err := client.Get("https://" + svr.Addr().String())
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(body))
+	assert.NotNil(t, res.TLS)
+
+	_, err = NewServer(ServerConfig{TLS: TLSConfig{CertFile: keyFile, KeyFile: certFile}},
+		ok, log.NewNopLogger())
+	assert.Error(t, err)
+}
+
+// writeCert writes a self signed certificate of 127.0.0.1
+// and its key into dir
+func writeCert(t *testing.T, dir string) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, ioutil.WriteFile(certFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644))
+	require.NoError(t, ioutil.WriteFile(keyFile,
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+	return certFile, keyFile
+}
@@ -0,0 +1,170 @@
+package gateway
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ErrUnknownKey is returned by a Store for keys never issued
+// or revoked since
+var ErrUnknownKey = errors.New("unknown api key")
+
+// The status of a request, as counted in the metrics
+const (
+	StatusOK         = "ok"
+	StatusLimited    = "limited"
+	StatusOverQuota  = "over_quota"
+	StatusUnknownKey = "unknown_key"
+	StatusError      = "error"
+)
+
+// Limit is what the holder of an api key may do. Rate is the
+// requests per second on average, with bursts of up to Burst
+// requests, 0 for no rate limit. Quota caps the requests of
+// every Period, 0 for no quota.
+type Limit struct {
+	// Name stands for the key in the metrics, so the key
+	// itself is never exposed
+	Name   string
+	Rate   float64
+	Burst  int
+	Quota  int64
+	Period time.Duration
+}
+
+// Usage is what was decided for a request
+type Usage struct {
+	// Status is StatusOK, StatusLimited or StatusOverQuota
+	Status string
+	// Remaining is what is left of the quota of the period,
+	// -1 without a quota
+	Remaining int64
+	// RetryAfter is when a refused request may pass
+	RetryAfter time.Duration
+}
+
+// Store keeps the api keys and what they used. The gateway may
+// run on many hosts sharing a Store, eg. in redis, or each host
+// keeps its own in a MemStore.
+type Store interface {
+	// Take counts a request of the key at now, it returns
+	// ErrUnknownKey if the key is not issued
+	Take(key string, now time.Time) (Usage, Limit, error)
+}
+
+// Counter is the usage of one key. A Store persists it as it
+// likes and calls Take for every request, so all stores limit
+// alike.
+type Counter struct {
+	Tokens float64
+	Last   time.Time
+	Start  time.Time
+	Used   int64
+}
+
+// Take counts a request at now against the limit. The token
+// bucket refills at the rate since the last request, the quota
+// starts over once the period is up.
+func (c *Counter) Take(l Limit, now time.Time) Usage {
+	if c.Last.IsZero() {
+		c.Tokens = float64(l.Burst)
+		c.Last = now
+		c.Start = now
+	}
+	if elapsed := now.Sub(c.Last); elapsed > 0 {
+		c.Tokens = math.Min(float64(l.Burst), c.Tokens+elapsed.Seconds()*l.Rate)
+		c.Last = now
+	}
+	if l.Quota > 0 && now.Sub(c.Start) >= l.Period {
+		c.Start = now
+		c.Used = 0
+	}
+
+	res := Usage{Status: StatusOK, Remaining: -1}
+	switch {
+	case l.Quota > 0 && c.Used >= l.Quota:
+		res.Status = StatusOverQuota
+		res.RetryAfter = c.Start.Add(l.Period).Sub(now)
+	case l.Rate > 0 && c.Tokens < 1:
+		res.Status = StatusLimited
+		res.RetryAfter = time.Duration((1 - c.Tokens) / l.Rate * float64(time.Second))
+	default:
+		c.Tokens--
+		c.Used++
+	}
+	if l.Quota > 0 {
+		res.Remaining = l.Quota - c.Used
+	}
+	return res
+}
+
+// MemStore keeps the keys in memory of this host, they are gone
+// after a restart
+type MemStore struct {
+	mtx    sync.Mutex
+	limits map[string]Limit
+	counts map[string]*Counter
+}
+
+var _ Store = (*MemStore)(nil)
+
+// NewMemStore returns a store without any keys
+func NewMemStore() *MemStore {
+	return &MemStore{
+		limits: make(map[string]Limit),
+		counts: make(map[string]*Counter),
+	}
+}
+
+// Issue creates a random key with the limit
+func (s *MemStore) Issue(l Limit) (string, error) {
+	key, err := NewKey()
+	if err != nil {
+		return "", err
+	}
+	s.Set(key, l)
+	return key, nil
+}
+
+// Set sets the limit of the key, issuing it if needed. The
+// usage of an issued key is kept.
+func (s *MemStore) Set(key string, l Limit) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.limits[key] = l
+	if s.counts[key] == nil {
+		s.counts[key] = &Counter{}
+	}
+}
+
+// Revoke removes the key, its requests fail from now on
+func (s *MemStore) Revoke(key string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.limits, key)
+	delete(s.counts, key)
+}
+
+// Take fulfils Store
+func (s *MemStore) Take(key string, now time.Time) (Usage, Limit, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	l, ok := s.limits[key]
+	if !ok {
+		return Usage{}, Limit{}, ErrUnknownKey
+	}
+	return s.counts[key].Take(l, now), l, nil
+}
+
+// NewKey returns a random api key
+func NewKey() (string, error) {
+	bz := make([]byte, 16)
+	if _, err := rand.Read(bz); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bz), nil
+}
@@ -0,0 +1,103 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounter(t *testing.T) {
+	start := time.Unix(1000, 0)
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+
+	cases := map[string]struct {
+		limit  Limit
+		times  []time.Time
+		want   []string
+		remain int64
+		retry  time.Duration
+	}{
+		"burst then limited": {
+			limit: Limit{Rate: 2, Burst: 2},
+			times: []time.Time{at(0), at(0), at(0)},
+			want:  []string{StatusOK, StatusOK, StatusLimited},
+			// no quota
+			remain: -1,
+			retry:  500 * time.Millisecond,
+		},
+		"refilled at the rate": {
+			limit:  Limit{Rate: 2, Burst: 1},
+			times:  []time.Time{at(0), at(250), at(500)},
+			want:   []string{StatusOK, StatusLimited, StatusOK},
+			remain: -1,
+		},
+		"no rate limit": {
+			limit:  Limit{Quota: 3, Period: time.Hour},
+			times:  []time.Time{at(0), at(0), at(0)},
+			want:   []string{StatusOK, StatusOK, StatusOK},
+			remain: 0,
+		},
+		"over quota until the period is up": {
+			limit:  Limit{Rate: 10, Burst: 10, Quota: 2, Period: time.Minute},
+			times:  []time.Time{at(0), at(1000), at(2000)},
+			want:   []string{StatusOK, StatusOK, StatusOverQuota},
+			remain: 0,
+			retry:  58 * time.Second,
+		},
+		"quota starts over": {
+			limit:  Limit{Rate: 10, Burst: 10, Quota: 2, Period: time.Minute},
+			times:  []time.Time{at(0), at(1000), at(60000)},
+			want:   []string{StatusOK, StatusOK, StatusOK},
+			remain: 1,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, len(tc.times), len(tc.want))
+			var c Counter
+			var last Usage
+			for i, now := range tc.times {
+				last = c.Take(tc.limit, now)
+				assert.Equal(t, tc.want[i], last.Status, "request %d", i)
+			}
+			assert.Equal(t, tc.remain, last.Remaining)
+			assert.Equal(t, tc.retry, last.RetryAfter)
+		})
+	}
+}
+
+func TestMemStore(t *testing.T) {
+	s := NewMemStore()
+	now := time.Unix(1000, 0)
+	limit := Limit{Name: "wallet", Rate: 1, Burst: 1}
+
+	key, err := s.Issue(limit)
+	require.NoError(t, err)
+	assert.Len(t, key, 32)
+	other, err := s.Issue(limit)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+
+	usage, got, err := s.Take(key, now)
+	require.NoError(t, err)
+	assert.Equal(t, StatusOK, usage.Status)
+	assert.Equal(t, limit, got)
+	usage, _, err = s.Take(key, now)
+	require.NoError(t, err)
+	assert.Equal(t, StatusLimited, usage.Status)
+
+	// a new limit keeps the usage
+	s.Set(key, Limit{Name: "wallet", Rate: 1, Burst: 5})
+	usage, _, err = s.Take(key, now)
+	require.NoError(t, err)
+	assert.Equal(t, StatusLimited, usage.Status)
+
+	s.Revoke(key)
+	_, _, err = s.Take(key, now)
+	assert.Equal(t, ErrUnknownKey, err)
+	_, _, err = s.Take("nonsense", now)
+	assert.Equal(t, ErrUnknownKey, err)
+}
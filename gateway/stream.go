@@ -0,0 +1,123 @@
+package gateway
+
+import (
+	"strings"
+
+	abci "github.com/tendermint/abci/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/confio/weave/app"
+
+	"github.com/iov-one/bcp-demo/query"
+	"github.com/iov-one/bcp-demo/viewtoken"
+)
+
+// StreamServer is the QueryServer of the gateway. It streams a
+// bucket by repeating its paged query (see package query) with
+// the key of the last item as cursor, one chunk per page, so no
+// abci response grows with the bucket. A client resumes a broken
+// stream with the cursor of the last chunk it received.
+//
+// Every page is read at the height of the node at the time, so
+// the items written while streaming may or may not show up. The
+// paths of viewtoken.Paths need a view token, which only
+// QueryEndpoint takes.
+type StreamServer struct {
+	node Querier
+}
+
+var _ QueryServer = StreamServer{}
+
+// NewStreamServer returns a StreamServer asking node
+func NewStreamServer(node Querier) StreamServer {
+	return StreamServer{node: node}
+}
+
+// Stream implements QueryServer. A failed page query ends the
+// stream with InvalidArgument, as QueryEndpoint answers 400.
+func (s StreamServer) Stream(req *StreamRequest, stream Query_StreamServer) error {
+	if req.Path == "" || req.Limit < 0 {
+		return status.Errorf(codes.InvalidArgument, "stream needs a path and no negative limit")
+	}
+	if contains(viewtoken.Paths, req.Path) {
+		return status.Errorf(codes.PermissionDenied, "%s needs a view token", req.Path)
+	}
+	path := query.PageRequest{Limit: int(req.Limit), Reverse: req.Reverse}.Path(req.Path)
+	cursor := req.Cursor
+	for {
+		res := s.node.Query(abci.RequestQuery{Path: path, Data: cursor})
+		if res.Code != 0 {
+			return status.Errorf(codes.InvalidArgument, "%s", res.Log)
+		}
+		items, err := resultItems(res)
+		if err != nil {
+			return status.Errorf(codes.Internal, "page: %v", err)
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		cursor = items[len(items)-1].Key
+		err = stream.Send(&Chunk{Items: items, Cursor: cursor, Height: res.Height})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// resultItems pairs the keys and values of a query result
+func resultItems(res abci.ResponseQuery) ([]*Item, error) {
+	var keys, values app.ResultSet
+	err := keys.Unmarshal(res.Key)
+	if err != nil {
+		return nil, err
+	}
+	err = values.Unmarshal(res.Value)
+	if err != nil {
+		return nil, err
+	}
+	models, err := app.JoinResults(&keys, &values)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*Item, len(models))
+	for i, m := range models {
+		items[i] = &Item{Key: m.Key, Value: m.Value}
+	}
+	return items, nil
+}
+
+// StreamInterceptor is the grpc.StreamServerInterceptor of the
+// keys of the Limiter, taking the key from the metadata KeyHeader.
+// It counts every stream as one request, with the same metrics
+// as ServeHTTP.
+func (l *Limiter) StreamInterceptor(srv interface{}, ss grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+	var key string
+	md, _ := metadata.FromIncomingContext(ss.Context())
+	if vals := md[strings.ToLower(KeyHeader)]; len(vals) > 0 {
+		key = vals[0]
+	}
+	if key == "" {
+		l.count("", StatusUnknownKey)
+		return status.Errorf(codes.Unauthenticated, "missing %s", KeyHeader)
+	}
+	usage, limit, err := l.store.Take(key, l.now())
+	switch {
+	case err == ErrUnknownKey:
+		l.count("", StatusUnknownKey)
+		return status.Errorf(codes.Unauthenticated, "%v", err)
+	case err != nil:
+		l.count(limit.Name, StatusError)
+		return status.Errorf(codes.Unavailable, "api keys: %v", err)
+	}
+	l.count(limit.Name, usage.Status)
+	if usage.Status != StatusOK {
+		return status.Errorf(codes.ResourceExhausted, "%s, retry in %ds",
+			usage.Status, retrySeconds(usage.RetryAfter))
+	}
+	return handler(srv, ss)
+}
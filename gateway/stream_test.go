@@ -0,0 +1,141 @@
+package gateway
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/store"
+
+	"github.com/iov-one/bcp-demo/query"
+)
+
+// pageNode answers the paged queries of a bucket as the app
+// does, and counts the queries
+type pageNode struct {
+	db      weave.KVStore
+	qr      weave.QueryRouter
+	queries int
+}
+
+func (n *pageNode) Query(req abci.RequestQuery) abci.ResponseQuery {
+	n.queries++
+	path, mod := req.Path, ""
+	if i := strings.Index(path, "?"); i >= 0 {
+		path, mod = path[:i], path[i+1:]
+	}
+	h := n.qr.Handler(path)
+	if h == nil {
+		return abci.ResponseQuery{Code: 1, Log: "unknown path " + path}
+	}
+	models, err := h.Query(n.db, mod, req.Data)
+	if err != nil {
+		return abci.ResponseQuery{Code: 1, Log: err.Error()}
+	}
+	res := abci.ResponseQuery{Height: 7}
+	res.Key, _ = app.ResultsFromKeys(models).Marshal()
+	res.Value, _ = app.ResultsFromValues(models).Marshal()
+	return res
+}
+
+// chunkStream collects the chunks sent
+type chunkStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	chunks []*Chunk
+}
+
+func (s *chunkStream) Send(c *Chunk) error {
+	s.chunks = append(s.chunks, c)
+	return nil
+}
+
+func (s *chunkStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamServer(t *testing.T) {
+	bucket := orm.NewBucket("items", orm.NewSimpleObj(nil, new(orm.Counter)))
+	node := &pageNode{db: store.MemStore(), qr: weave.NewQueryRouter()}
+	for i := 0; i < 25; i++ {
+		key := []byte(fmt.Sprintf("key-%02d", i))
+		require.NoError(t, bucket.Save(node.db, orm.NewSimpleObj(key, &orm.Counter{Count: int64(i)})))
+	}
+	query.Register(node.qr, "/items", bucket, 10)
+	svr := NewStreamServer(node)
+
+	stream := &chunkStream{ctx: context.Background()}
+	require.NoError(t, svr.Stream(&StreamRequest{Path: "/items"}, stream))
+	require.Len(t, stream.chunks, 3)
+	assert.Len(t, stream.chunks[2].Items, 5)
+	assert.Equal(t, bucket.DBKey([]byte("key-24")), stream.chunks[2].Cursor)
+	assert.Equal(t, int64(7), stream.chunks[0].Height)
+	// the last query finds the end
+	assert.Equal(t, 4, node.queries)
+
+	// resume after the first chunk, in smaller chunks
+	stream = &chunkStream{ctx: context.Background()}
+	req := &StreamRequest{Path: "/items", Cursor: bucket.DBKey([]byte("key-09")), Limit: 5}
+	require.NoError(t, svr.Stream(req, stream))
+	require.Len(t, stream.chunks, 3)
+	assert.Equal(t, bucket.DBKey([]byte("key-10")), stream.chunks[0].Items[0].Key)
+
+	// backwards, the limit is capped by the query
+	stream = &chunkStream{ctx: context.Background()}
+	require.NoError(t, svr.Stream(&StreamRequest{Path: "/items", Reverse: true, Limit: 20}, stream))
+	require.Len(t, stream.chunks, 3)
+	assert.Equal(t, bucket.DBKey([]byte("key-24")), stream.chunks[0].Items[0].Key)
+
+	cases := []struct {
+		req  *StreamRequest
+		code codes.Code
+	}{
+		0: {&StreamRequest{}, codes.InvalidArgument},
+		1: {&StreamRequest{Path: "/items", Limit: -1}, codes.InvalidArgument},
+		2: {&StreamRequest{Path: "/escrows/sender"}, codes.PermissionDenied},
+		3: {&StreamRequest{Path: "/other"}, codes.InvalidArgument},
+		4: {&StreamRequest{Path: "/items", Cursor: []byte("other")}, codes.InvalidArgument},
+	}
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			err := svr.Stream(tc.req, &chunkStream{ctx: context.Background()})
+			assert.Equal(t, tc.code, grpc.Code(err))
+		})
+	}
+}
+
+func TestStreamInterceptor(t *testing.T) {
+	store := NewMemStore()
+	store.Set("secret", Limit{Name: "wallet", Quota: 1, Period: time.Hour})
+	limiter := NewLimiter(nil, store)
+	called := 0
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		called++
+		return nil
+	}
+	call := func(key string) error {
+		ctx := context.Background()
+		if key != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(KeyHeader, key))
+		}
+		return limiter.StreamInterceptor(nil, &chunkStream{ctx: ctx}, nil, handler)
+	}
+
+	assert.Equal(t, codes.Unauthenticated, grpc.Code(call("")))
+	assert.Equal(t, codes.Unauthenticated, grpc.Code(call("other")))
+	assert.NoError(t, call("secret"))
+	assert.Equal(t, codes.ResourceExhausted, grpc.Code(call("secret")))
+	assert.Equal(t, 1, called)
+}
@@ -0,0 +1,155 @@
+/*
+Package indexer keeps the books of the accounts of the chain, off
+chain, from the event stream of the outbox, and serves monthly
+statements of them for bookkeeping.
+
+A Receiver is the webhook of the outbox relay of a node. It keeps
+the ledger events (see package x/ledger) in a Book: what escrows
+paid to an address, what it put into new escrows and the fees it
+paid. A ReportEndpoint serves the statement of an address for a
+month, by ticker, as json or csv.
+
+The tree has no indexer of its own yet, the service running it
+wraps them:
+
+	book := indexer.NewMemBook()
+	http.Handle("/events", indexer.NewReceiver(book, logger))
+	http.Handle("/reports", gateway.NewLimiter(indexer.NewReportEndpoint(book), store))
+
+and the nodes push to it with "outbox": {"webhook": "http://.../events"}.
+*/
+package indexer
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/confio/weave"
+	"github.com/tendermint/tmlibs/log"
+
+	"github.com/iov-one/bcp-demo/x/ledger"
+	"github.com/iov-one/bcp-demo/x/outbox"
+)
+
+// Entry is a ledger event of an account
+type Entry struct {
+	ledger.Entry
+	Seq    int64
+	Height int64
+	Time   time.Time
+	// TxHash is hex encoded
+	TxHash string
+}
+
+// Book keeps the entries of the accounts. The service may run on
+// many hosts sharing a Book on a shared db, or keep a MemBook.
+type Book interface {
+	// Record keeps the entry, unless it has seen its sequence
+	// before. The outbox pushes at least once and in order.
+	Record(e Entry) error
+	// Entries returns the entries of addr in [from, to),
+	// oldest first
+	Entries(addr weave.Address, from, to time.Time) ([]Entry, error)
+}
+
+// MemBook keeps the entries in memory of this host. After a
+// restart it needs the events again, from a new cursor of the
+// relay.
+type MemBook struct {
+	mtx      sync.Mutex
+	last     int64
+	accounts map[string][]Entry
+}
+
+var _ Book = (*MemBook)(nil)
+
+// NewMemBook returns an empty book
+func NewMemBook() *MemBook {
+	return &MemBook{accounts: make(map[string][]Entry)}
+}
+
+// Record fulfils Book
+func (b *MemBook) Record(e Entry) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if e.Seq <= b.last {
+		return nil
+	}
+	b.last = e.Seq
+	key := string(e.Address)
+	b.accounts[key] = append(b.accounts[key], e)
+	return nil
+}
+
+// Entries fulfils Book
+func (b *MemBook) Entries(addr weave.Address, from, to time.Time) ([]Entry, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	all := b.accounts[string(addr)]
+	// entries are in order of the chain, so of time
+	i := sort.Search(len(all), func(i int) bool { return !all[i].Time.Before(from) })
+	j := sort.Search(len(all), func(i int) bool { return !all[i].Time.Before(to) })
+	return append([]Entry{}, all[i:j]...), nil
+}
+
+// Receiver is the http.Handler taking the pushes of the outbox
+// relay, as {"events": [...]}. It records the ledger events in
+// its book and skips the others.
+type Receiver struct {
+	book   Book
+	logger log.Logger
+}
+
+var _ http.Handler = Receiver{}
+
+// receiverBody is what the relay pushes
+type receiverBody struct {
+	Events []outbox.Delivery `json:"events"`
+}
+
+// NewReceiver records the events in book, logging the ones it
+// can't read
+func NewReceiver(book Book, logger log.Logger) Receiver {
+	return Receiver{book: book, logger: logger}
+}
+
+// ServeHTTP answers 204 once all events are recorded. A failing
+// book answers 503, so the relay pushes them again.
+func (r Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, "POST only", http.StatusMethodNotAllowed)
+		return
+	}
+	var body receiverBody
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		http.Error(w, "events: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	for _, d := range body.Events {
+		if !strings.HasPrefix(d.Key, ledger.TagPrefix) {
+			continue
+		}
+		e, err := ledger.Parse(d.Key, []byte(d.Value))
+		if err != nil {
+			// pushing it again won't help
+			r.logger.Error("Cannot read ledger event", "seq", d.Seq, "err", err)
+			continue
+		}
+		err = r.book.Record(Entry{
+			Entry:  e,
+			Seq:    d.Seq,
+			Height: d.Height,
+			Time:   time.Unix(d.Time, 0).UTC(),
+			TxHash: d.TxHash,
+		})
+		if err != nil {
+			http.Error(w, "book: "+err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,81 @@
+package indexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tmlibs/log"
+
+	"github.com/iov-one/bcp-demo/x/ledger"
+	"github.com/iov-one/bcp-demo/x/outbox"
+)
+
+func TestReceiver(t *testing.T) {
+	alice := weave.NewAddress([]byte("alice"))
+	bob := weave.NewAddress([]byte("bob"))
+	iov := x.NewCoin(12, 500000000, "IOV")
+	fee := x.NewCoin(0, 50000, "IOV")
+	july := time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	book := NewMemBook()
+	receiver := NewReceiver(book, log.NewNopLogger())
+	push := func(events ...outbox.Delivery) *httptest.ResponseRecorder {
+		body, err := json.Marshal(receiverBody{Events: events})
+		require.NoError(t, err)
+		w := httptest.NewRecorder()
+		receiver.ServeHTTP(w, httptest.NewRequest("POST", "/events", bytes.NewReader(body)))
+		return w
+	}
+	delivery := func(seq int64, tag ledger.Entry, at time.Time) outbox.Delivery {
+		kv := ledger.Tag(tag.Kind, tag.Address, tag.Amount)
+		return outbox.Delivery{Seq: seq, Height: seq + 10, Key: string(kv.Key),
+			Value: string(kv.Value), TxHash: "abcd", Time: at.Unix()}
+	}
+
+	in := ledger.Entry{Kind: ledger.KindIn, Address: alice, Amount: iov}
+	out := ledger.Entry{Kind: ledger.KindOut, Address: bob, Amount: iov}
+	paid := ledger.Entry{Kind: ledger.KindFee, Address: alice, Amount: fee}
+	w := push(
+		delivery(1, in, july.Add(time.Hour)),
+		delivery(2, out, july.Add(time.Hour)),
+		// not a ledger event
+		outbox.Delivery{Seq: 3, Key: "escrow.00", Value: "release"},
+		// a broken one
+		outbox.Delivery{Seq: 4, Key: "ledger.in." + alice.String(), Value: "lots"},
+		delivery(5, paid, july.AddDate(0, 1, 0)),
+	)
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	// the relay pushes some again
+	w = push(delivery(1, in, july.Add(time.Hour)), delivery(5, paid, july.AddDate(0, 1, 0)))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	entries, err := book.Entries(alice, july, july.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{{Entry: in, Seq: 1, Height: 11, Time: july.Add(time.Hour), TxHash: "abcd"}}, entries)
+	entries, err = book.Entries(alice, july, july.AddDate(0, 2, 0))
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+	entries, err = book.Entries(bob, july, july.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+	entries, err = book.Entries(bob, july.AddDate(0, 1, 0), july.AddDate(0, 2, 0))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// bad requests
+	w = httptest.NewRecorder()
+	receiver.ServeHTTP(w, httptest.NewRequest("POST", "/events", strings.NewReader("{")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = httptest.NewRecorder()
+	receiver.ServeHTTP(w, httptest.NewRequest("GET", "/events", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
@@ -0,0 +1,191 @@
+package indexer
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/ledger"
+)
+
+// MonthFormat is the layout of the month of a statement
+const MonthFormat = "2006-01"
+
+// Statement is what an account received, put into escrows and
+// paid in fees in a month, in json
+type Statement struct {
+	Address string           `json:"address"`
+	Month   string           `json:"month"`
+	Totals  []Total          `json:"totals"`
+	Entries []StatementEntry `json:"entries"`
+}
+
+// Total sums the entries of one ticker. The amounts are decimal
+// with all fractional digits, as strings to keep them exact.
+type Total struct {
+	Ticker   string `json:"ticker"`
+	Incoming string `json:"incoming"`
+	Outgoing string `json:"outgoing"`
+	Fees     string `json:"fees"`
+}
+
+// StatementEntry is one entry of a statement
+type StatementEntry struct {
+	Time   time.Time `json:"time"`
+	Height int64     `json:"height"`
+	TxHash string    `json:"tx_hash"`
+	Kind   string    `json:"kind"`
+	Ticker string    `json:"ticker"`
+	Amount string    `json:"amount"`
+}
+
+// csvHeader is the first row of a statement in csv, the columns
+// of a StatementEntry
+var csvHeader = []string{"time", "height", "tx_hash", "kind", "ticker", "amount"}
+
+// ReportEndpoint is the http.Handler serving statements. It
+// takes the hex "address" and the "month" as YYYY-MM, in UTC, and
+// answers json, or csv for "format=csv". The csv has just the
+// entries, a spreadsheet sums them.
+type ReportEndpoint struct {
+	book Book
+}
+
+var _ http.Handler = ReportEndpoint{}
+
+// NewReportEndpoint serves the statements of the entries in book
+func NewReportEndpoint(book Book) ReportEndpoint {
+	return ReportEndpoint{book: book}
+}
+
+// ServeHTTP answers the statement of the month, empty if the
+// book has no entries of the address in it
+func (e ReportEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "GET only", http.StatusMethodNotAllowed)
+		return
+	}
+	addr, err := hex.DecodeString(r.FormValue("address"))
+	if err != nil || len(addr) != weave.AddressLength {
+		http.Error(w, "invalid address", http.StatusBadRequest)
+		return
+	}
+	month := r.FormValue("month")
+	from, err := time.Parse(MonthFormat, month)
+	if err != nil {
+		http.Error(w, "invalid month", http.StatusBadRequest)
+		return
+	}
+	format := r.FormValue("format")
+	if format != "" && format != "json" && format != "csv" {
+		http.Error(w, "format is json or csv", http.StatusBadRequest)
+		return
+	}
+
+	entries, err := e.book.Entries(addr, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		http.Error(w, "book: "+err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	stmt, err := NewStatement(addr, month, entries)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf("attachment; filename=%q", stmt.Address+"-"+month+".csv"))
+		writeCSV(w, stmt)
+		return
+	}
+	body, err := json.Marshal(stmt)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// NewStatement sums the entries of addr by ticker
+func NewStatement(addr weave.Address, month string, entries []Entry) (Statement, error) {
+	stmt := Statement{
+		Address: addr.String(),
+		Month:   month,
+		Totals:  []Total{},
+		Entries: make([]StatementEntry, 0, len(entries)),
+	}
+	// incoming, outgoing and fees of every ticker
+	sums := make(map[string]*[3]x.Coin)
+	for _, en := range entries {
+		ticker := en.Amount.Ticker
+		sum, ok := sums[ticker]
+		if !ok {
+			zero := x.Coin{Ticker: ticker}
+			sum = &[3]x.Coin{zero, zero, zero}
+			sums[ticker] = sum
+		}
+		i := 0
+		switch en.Kind {
+		case ledger.KindOut:
+			i = 1
+		case ledger.KindFee:
+			i = 2
+		}
+		var err error
+		if sum[i], err = sum[i].Add(en.Amount); err != nil {
+			return stmt, err
+		}
+		stmt.Entries = append(stmt.Entries, StatementEntry{
+			Time:   en.Time,
+			Height: en.Height,
+			TxHash: en.TxHash,
+			Kind:   en.Kind,
+			Ticker: ticker,
+			Amount: decimal(en.Amount),
+		})
+	}
+	for ticker, sum := range sums {
+		stmt.Totals = append(stmt.Totals, Total{
+			Ticker:   ticker,
+			Incoming: decimal(sum[0]),
+			Outgoing: decimal(sum[1]),
+			Fees:     decimal(sum[2]),
+		})
+	}
+	sort.Slice(stmt.Totals, func(i, j int) bool {
+		return stmt.Totals[i].Ticker < stmt.Totals[j].Ticker
+	})
+	return stmt, nil
+}
+
+// writeCSV writes the entries of stmt under csvHeader
+func writeCSV(w http.ResponseWriter, stmt Statement) {
+	cw := csv.NewWriter(w)
+	cw.Write(csvHeader)
+	for _, en := range stmt.Entries {
+		cw.Write([]string{
+			en.Time.Format(time.RFC3339),
+			strconv.FormatInt(en.Height, 10),
+			en.TxHash,
+			en.Kind,
+			en.Ticker,
+			en.Amount,
+		})
+	}
+	cw.Flush()
+}
+
+// decimal is the amount of c without its ticker
+func decimal(c x.Coin) string {
+	return fmt.Sprintf("%d.%09d", c.Whole, c.Fractional)
+}
@@ -0,0 +1,86 @@
+package indexer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iov-one/bcp-demo/x/ledger"
+)
+
+func TestReportEndpoint(t *testing.T) {
+	alice := weave.NewAddress([]byte("alice"))
+	july := time.Date(2018, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	book := NewMemBook()
+	records := []struct {
+		kind   string
+		amount x.Coin
+		at     time.Time
+	}{
+		{ledger.KindIn, x.NewCoin(12, 500000000, "IOV"), july.Add(time.Hour)},
+		{ledger.KindIn, x.NewCoin(1, 0, "ETH"), july.Add(2 * time.Hour)},
+		{ledger.KindOut, x.NewCoin(5, 0, "IOV"), july.Add(3 * time.Hour)},
+		{ledger.KindFee, x.NewCoin(0, 50000, "IOV"), july.Add(3 * time.Hour)},
+		{ledger.KindIn, x.NewCoin(7, 0, "IOV"), july.AddDate(0, 1, 0)},
+	}
+	for i, r := range records {
+		e := Entry{
+			Entry: ledger.Entry{Kind: r.kind, Address: alice, Amount: r.amount},
+			Seq:   int64(i + 1), Height: int64(i + 100), Time: r.at, TxHash: "abcd",
+		}
+		require.NoError(t, book.Record(e))
+	}
+
+	endpoint := NewReportEndpoint(book)
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		endpoint.ServeHTTP(w, httptest.NewRequest("GET", "/reports?"+query, nil))
+		return w
+	}
+
+	w := get("address=" + alice.String() + "&month=2018-07")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var stmt Statement
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stmt))
+	assert.Equal(t, alice.String(), stmt.Address)
+	assert.Equal(t, []Total{
+		{Ticker: "ETH", Incoming: "1.000000000", Outgoing: "0.000000000", Fees: "0.000000000"},
+		{Ticker: "IOV", Incoming: "12.500000000", Outgoing: "5.000000000", Fees: "0.000050000"},
+	}, stmt.Totals)
+	require.Len(t, stmt.Entries, 4)
+	assert.Equal(t, StatementEntry{Time: july.Add(time.Hour), Height: 100, TxHash: "abcd",
+		Kind: ledger.KindIn, Ticker: "IOV", Amount: "12.500000000"}, stmt.Entries[0])
+
+	w = get("address=" + alice.String() + "&month=2018-07&format=csv")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Equal(t, "time,height,tx_hash,kind,ticker,amount\n"+
+		"2018-07-01T01:00:00Z,100,abcd,in,IOV,12.500000000\n"+
+		"2018-07-01T02:00:00Z,101,abcd,in,ETH,1.000000000\n"+
+		"2018-07-01T03:00:00Z,102,abcd,out,IOV,5.000000000\n"+
+		"2018-07-01T03:00:00Z,103,abcd,fee,IOV,0.000050000\n", w.Body.String())
+
+	// a month without entries
+	w = get("address=" + alice.String() + "&month=2018-06")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stmt))
+	assert.Empty(t, stmt.Totals)
+	assert.Empty(t, stmt.Entries)
+
+	bad := []string{
+		"address=0102&month=2018-07",
+		"address=" + alice.String() + "&month=july",
+		"address=" + alice.String() + "&month=2018-07&format=xls",
+	}
+	for i, q := range bad {
+		assert.Equal(t, http.StatusBadRequest, get(q).Code, "case %d", i)
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Package keyspace records which module owns which prefix of the
+store, so a new module can never write into the keys of another.
+
+All modules share one key value store. An orm bucket named "esc"
+keeps its objects under "esc:", and two modules that happen to
+pick the same name would silently read and overwrite each other's
+objects. Every module registers its buckets in an init function
+with Buckets, or other raw prefixes with Register. A prefix that
+equals, extends or is extended by one registered before panics
+right when the binary starts, naming both owners.
+
+The prefixes of weave itself, for indexes, sequences and the
+chain id, are registered here.
+*/
+package keyspace
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Weave is the owner of the prefixes weave uses internally
+const Weave = "weave"
+
+// Entry is a prefix of the store and the module owning it
+type Entry struct {
+	Module string
+	Prefix string
+}
+
+var entries []Entry
+
+func init() {
+	// "_i.<bucket>_<index>:", "_s.<bucket>:<name>" and the
+	// chain id of weave/app
+	Register(Weave, "_i.", "_s.", "_wv:")
+}
+
+// Buckets registers the orm buckets of a module by name.
+// Their keys are the name followed by ':'.
+func Buckets(module string, names ...string) {
+	for _, name := range names {
+		if name == "" || strings.ContainsAny(name, ":.") {
+			panic(fmt.Sprintf("keyspace: invalid bucket name %q of %s", name, module))
+		}
+		Register(module, name+":")
+	}
+}
+
+// Register reserves raw key prefixes for a module. It panics if
+// a prefix overlaps with one registered before, as the modules
+// would share keys.
+func Register(module string, prefixes ...string) {
+	for _, prefix := range prefixes {
+		if prefix == "" {
+			panic("keyspace: empty prefix of " + module)
+		}
+		for _, e := range entries {
+			if strings.HasPrefix(prefix, e.Prefix) || strings.HasPrefix(e.Prefix, prefix) {
+				panic(fmt.Sprintf("keyspace: prefix %q of %s overlaps %q of %s",
+					prefix, module, e.Prefix, e.Module))
+			}
+		}
+		entries = append(entries, Entry{Module: module, Prefix: prefix})
+	}
+}
+
+// Owner returns the module that owns the key, "" if the key is
+// under no registered prefix
+func Owner(key []byte) string {
+	for _, e := range entries {
+		if strings.HasPrefix(string(key), e.Prefix) {
+			return e.Module
+		}
+	}
+	return ""
+}
+
+// All returns every registered prefix, sorted
+func All() []Entry {
+	res := make([]Entry, len(entries))
+	copy(res, entries)
+	sort.Slice(res, func(i, j int) bool { return res[i].Prefix < res[j].Prefix })
+	return res
+}
@@ -0,0 +1,54 @@
+package keyspace
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// reset restores the registry after a test adds to it
+func reset() func() {
+	saved := entries
+	entries = append([]Entry(nil), entries...)
+	return func() { entries = saved }
+}
+
+func TestRegister(t *testing.T) {
+	defer reset()()
+
+	Buckets("escrow", "esc", "escparams")
+	Register("auction", "auc/")
+	assert.Equal(t, "escrow", Owner([]byte("esc:1234")))
+	assert.Equal(t, "escrow", Owner([]byte("escparams:")))
+	assert.Equal(t, "auction", Owner([]byte("auc/lot")))
+	assert.Equal(t, Weave, Owner([]byte("_s.esc:id")))
+	assert.Equal(t, "", Owner([]byte("esc")))
+	assert.Equal(t, "", Owner([]byte("gov:1")))
+
+	all := All()
+	assert.Equal(t, len(entries), len(all))
+	for i := 1; i < len(all); i++ {
+		assert.True(t, all[i-1].Prefix < all[i].Prefix)
+	}
+}
+
+func TestRegisterPanics(t *testing.T) {
+	defer reset()()
+	Buckets("escrow", "esc")
+
+	cases := map[string]func(){
+		"same bucket":   func() { Buckets("gov", "esc") },
+		"extends":       func() { Register("gov", "esc:proposals") },
+		"extended":      func() { Register("gov", "es") },
+		"weave":         func() { Register("gov", "_i.gov") },
+		"empty prefix":  func() { Register("gov", "") },
+		"empty bucket":  func() { Buckets("gov", "") },
+		"colon in name": func() { Buckets("gov", "esc:x") },
+		"dot in name":   func() { Buckets("gov", "_i.gov") },
+	}
+	for name, register := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Panics(t, register)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+/*
+Package module describes what a module adds to the app, so the
+app can be assembled from a list of them rather than by hand.
+
+Every module has a name and a schema version. It adds its
+messages, queries, genesis state and work at the start of every
+block by also implementing Router, Querier, Genesis or Ticking,
+whichever apply. A module running messages of the others at the
+start of a block implements Dispatching instead of Ticking. The modules export a Module type, that takes
+what the module needs from the app, like the authenticator or
+the coin controller, as fields.
+*/
+package module
+
+import (
+	"github.com/confio/weave"
+)
+
+// Module is a part of the app
+type Module interface {
+	// Name identifies the module in the schema versions
+	Name() string
+	// Version is the schema version of the state and messages
+	// of the module. Bump it with every change a client may
+	// notice, so clients can detect what a node supports.
+	Version() uint32
+}
+
+// Router is a module handling messages
+type Router interface {
+	RegisterRoutes(r weave.Registry)
+}
+
+// Querier is a module serving queries
+type Querier interface {
+	RegisterQuery(qr weave.QueryRouter)
+}
+
+// Genesis is a module reading its state from the genesis file
+type Genesis interface {
+	Initializer() weave.Initializer
+}
+
+// Ticking is a module running at the start of every block
+type Ticking interface {
+	Ticker() weave.Ticker
+}
+
+// Dispatching is a module running messages at the start of every
+// block, through the handlers of all modules
+type Dispatching interface {
+	Dispatcher(h weave.Handler) weave.Ticker
+}
@@ -0,0 +1,155 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+
+	"github.com/confio/weave"
+
+	"github.com/iov-one/bcp-demo/storage"
+)
+
+// ConfigFile is where the node settings are stored, relative to home
+const ConfigFile = "config/bov.json"
+
+// DiagnosticsFile is where the state of the last commit is written
+// with Diagnostics on, relative to home
+const DiagnosticsFile = "bov.diag.json"
+
+// CrashFile is where a report of every panic in a tx is
+// appended, relative to home
+const CrashFile = "bov.crash.json"
+
+// OutboxFile is where the relay keeps the sequence of the last
+// event it pushed, relative to home
+const OutboxFile = "bov.outbox.json"
+
+// Config holds the settings of the node process.
+//
+// LogLevel, HaltHeight, ReadOnly and Health, the address of the
+// probes and metrics included, can be changed without a restart,
+// by sending SIGHUP, see Maintenance for the second and third.
+// DBBackend, MinGasPrice and Diagnostics are only read when the
+// app is created. MinGasPrice is the lowest fee, in fractional
+// units per byte of the tx, accepted into the mempool.
+//
+// Diagnostics writes the changed keys and a hash of every bucket
+// to DiagnosticsFile on each commit, to debug an app hash mismatch.
+// It reads the whole state on every block, so turn it on only to
+// replay the blocks up to a fork.
+//
+// Follower runs the node as a follower, see StartCmd. It is only
+// read at start, as are the Views to keep in memory, Tracing and
+// the Outbox relay.
+//
+// Everything that affects consensus (genesis, app state)
+// is not part of this config.
+type Config struct {
+	LogLevel    string        `json:"log_level"`
+	DBBackend   string        `json:"db_backend"`
+	MinGasPrice int64         `json:"min_gas_price"`
+	HaltHeight  int64         `json:"halt_height"`
+	ReadOnly    bool          `json:"read_only"`
+	Diagnostics bool          `json:"diagnostics"`
+	Follower    bool          `json:"follower"`
+	Views       ViewsConfig   `json:"views"`
+	Health      HealthConfig  `json:"health"`
+	Tracing     TracingConfig `json:"tracing"`
+	Outbox      OutboxConfig  `json:"outbox"`
+}
+
+// OutboxConfig pushes the escrow events to Webhook, see package
+// x/outbox. Webhook is empty to turn it off.
+type OutboxConfig struct {
+	Webhook string `json:"webhook"`
+}
+
+// TracingConfig sends traces of the txs to an OpenTelemetry
+// collector at Endpoint, eg. "http://localhost:4318", see package
+// tracing. One in Every txs is traced, 0 or 1 for all of them.
+// Endpoint is empty to turn it off.
+type TracingConfig struct {
+	Endpoint string `json:"endpoint"`
+	Every    int64  `json:"every"`
+}
+
+// ViewsConfig selects the materialized views of hot queries the
+// node keeps in memory, see package views. All are off by default.
+type ViewsConfig struct {
+	// EscrowsByRecipient serves "/views/escrows/recipient"
+	EscrowsByRecipient bool `json:"escrows_by_recipient"`
+	// Balances serves "/views/balances" for these addresses
+	Balances []weave.Address `json:"balances"`
+}
+
+// DefaultConfig is used if no config file is present
+func DefaultConfig() Config {
+	return Config{
+		LogLevel:  "info",
+		DBBackend: storage.DefaultBackend,
+	}
+}
+
+// Validate makes sure all settings can be applied
+func (c Config) Validate() error {
+	_, err := parseLevel(c.LogLevel)
+	if err != nil {
+		return err
+	}
+	if !storage.HasBackend(c.DBBackend) {
+		return fmt.Errorf("unknown db backend %q, available: %v",
+			c.DBBackend, storage.Backends())
+	}
+	if c.MinGasPrice < 0 {
+		return fmt.Errorf("negative min gas price %d", c.MinGasPrice)
+	}
+	if c.HaltHeight < 0 {
+		return fmt.Errorf("negative halt height %d", c.HaltHeight)
+	}
+	if c.Health.MaxCommitAge < 0 || c.Health.MaxSyncLag < 0 {
+		return fmt.Errorf("negative health limit")
+	}
+	if c.Tracing.Every < 0 {
+		return fmt.Errorf("negative tracing interval %d", c.Tracing.Every)
+	}
+	if c.Tracing.Endpoint != "" && !isHTTP(c.Tracing.Endpoint) {
+		return fmt.Errorf("invalid tracing endpoint %q", c.Tracing.Endpoint)
+	}
+	if c.Outbox.Webhook != "" && !isHTTP(c.Outbox.Webhook) {
+		return fmt.Errorf("invalid outbox webhook %q", c.Outbox.Webhook)
+	}
+	for _, addr := range c.Views.Balances {
+		if err := addr.Validate(); err != nil {
+			return fmt.Errorf("invalid balance view address %s", addr)
+		}
+	}
+	return nil
+}
+
+// isHTTP is true for http and https urls
+func isHTTP(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
+
+// LoadConfig reads the config from the given file.
+// Missing values keep their defaults, a missing file
+// returns the DefaultConfig.
+func LoadConfig(path string) (Config, error) {
+	cfg := DefaultConfig()
+	bz, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	err = json.Unmarshal(bz, &cfg)
+	if err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
@@ -0,0 +1,81 @@
+package node
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bov-config")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cases := []struct {
+		content  string
+		isError  bool
+		expected Config
+	}{
+		// no file
+		0: {"", false, DefaultConfig()},
+		1: {`{"log_level": "debug"}`, false, Config{LogLevel: "debug", DBBackend: "goleveldb"}},
+		// defaults are kept
+		2: {`{}`, false, DefaultConfig()},
+		3: {`{"log_level": "loud"}`, true, Config{}},
+		4: {`log_level = "debug"`, true, Config{}},
+		5: {`{"db_backend": "memdb"}`, true, Config{}},
+		6: {`{"db_backend": "rocksdb"}`, true, Config{}},
+		7: {`{"min_gas_price": 50}`, false, Config{LogLevel: "info", DBBackend: "goleveldb", MinGasPrice: 50}},
+		8: {`{"min_gas_price": -1}`, true, Config{}},
+		9: {`{"halt_height": 100, "read_only": true}`, false,
+			Config{LogLevel: "info", DBBackend: "goleveldb", HaltHeight: 100, ReadOnly: true}},
+		10: {`{"halt_height": -5}`, true, Config{}},
+		11: {`{"follower": true}`, false, Config{LogLevel: "info", DBBackend: "goleveldb", Follower: true}},
+		12: {`{"views": {"escrows_by_recipient": true, "balances": ["0102030405060708090A0B0C0D0E0F1011121314"]}}`, false,
+			Config{LogLevel: "info", DBBackend: "goleveldb", Views: ViewsConfig{
+				EscrowsByRecipient: true,
+				Balances:           []weave.Address{{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}},
+			}}},
+		13: {`{"views": {"balances": ["0102"]}}`, true, Config{}},
+		14: {`{"health": {"addr": "localhost:46660", "max_sync_lag": 120}}`, false,
+			Config{LogLevel: "info", DBBackend: "goleveldb",
+				Health: HealthConfig{Addr: "localhost:46660", MaxSyncLag: 120}}},
+		15: {`{"health": {"max_commit_age": -1}}`, true, Config{}},
+		16: {`{"tracing": {"endpoint": "http://localhost:4318", "every": 10}}`, false,
+			Config{LogLevel: "info", DBBackend: "goleveldb",
+				Tracing: TracingConfig{Endpoint: "http://localhost:4318", Every: 10}}},
+		17: {`{"tracing": {"endpoint": "localhost:4318"}}`, true, Config{}},
+		18: {`{"tracing": {"every": -1}}`, true, Config{}},
+		19: {`{"outbox": {"webhook": "https://hooks.example.com/escrows"}}`, false,
+			Config{LogLevel: "info", DBBackend: "goleveldb",
+				Outbox: OutboxConfig{Webhook: "https://hooks.example.com/escrows"}}},
+		20: {`{"outbox": {"webhook": "hooks.example.com"}}`, true, Config{}},
+		21: {`{"health": {"no_metrics": true}}`, false,
+			Config{LogLevel: "info", DBBackend: "goleveldb", Health: HealthConfig{NoMetrics: true}}},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			path := filepath.Join(dir, fmt.Sprintf("case-%d.json", i))
+			if tc.content != "" {
+				err := ioutil.WriteFile(path, []byte(tc.content), 0644)
+				require.NoError(t, err)
+			}
+
+			cfg, err := LoadConfig(path)
+			if tc.isError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, cfg)
+		})
+	}
+}
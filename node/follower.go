@@ -0,0 +1,61 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	privValidatorFile = "config/priv_validator.json"
+	genesisFile       = "config/genesis.json"
+)
+
+// tmPubKey is the part of a tendermint key we compare
+type tmPubKey struct {
+	Data string `json:"data"`
+}
+
+// checkFollower fails if the validator key in home is one of the
+// genesis validators. Without a key file tendermint generates a
+// new one, which is never a validator.
+func checkFollower(home string) error {
+	bz, err := ioutil.ReadFile(filepath.Join(home, privValidatorFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var privVal struct {
+		PubKey tmPubKey `json:"pub_key"`
+	}
+	err = json.Unmarshal(bz, &privVal)
+	if err != nil {
+		return err
+	}
+
+	bz, err = ioutil.ReadFile(filepath.Join(home, genesisFile))
+	if err != nil {
+		return err
+	}
+	var genesis struct {
+		Validators []struct {
+			PubKey tmPubKey `json:"pub_key"`
+			Name   string   `json:"name"`
+		} `json:"validators"`
+	}
+	err = json.Unmarshal(bz, &genesis)
+	if err != nil {
+		return err
+	}
+	for _, val := range genesis.Validators {
+		if strings.EqualFold(val.PubKey.Data, privVal.PubKey.Data) {
+			return fmt.Errorf("follower has the key of validator %q", val.Name)
+		}
+	}
+	return nil
+}
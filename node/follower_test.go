@@ -0,0 +1,33 @@
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckFollower(t *testing.T) {
+	home, err := ioutil.TempDir("", "bov-follower")
+	require.NoError(t, err)
+	defer os.RemoveAll(home)
+	require.NoError(t, os.MkdirAll(filepath.Join(home, "config"), 0755))
+
+	genesis := `{"validators": [{"pub_key": {"type": "ed25519", "data": "C92B4BF3"}, "name": "node0"}]}`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(home, genesisFile), []byte(genesis), 0644))
+
+	// tendermint generates a new key
+	assert.NoError(t, checkFollower(home))
+
+	privVal := filepath.Join(home, privValidatorFile)
+	require.NoError(t, ioutil.WriteFile(privVal,
+		[]byte(`{"pub_key": {"type": "ed25519", "data": "1234ABCD"}}`), 0600))
+	assert.NoError(t, checkFollower(home))
+
+	require.NoError(t, ioutil.WriteFile(privVal,
+		[]byte(`{"pub_key": {"type": "ed25519", "data": "c92b4bf3"}}`), 0600))
+	assert.Error(t, checkFollower(home))
+}
@@ -0,0 +1,221 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	abci "github.com/tendermint/abci/types"
+)
+
+const (
+	// defaultMaxCommitAge is used if the config sets none
+	defaultMaxCommitAge = 60 * time.Second
+	// defaultMaxSyncLag is used if the config sets none
+	defaultMaxSyncLag = 60 * time.Second
+
+	// probeKey is read from the raw key store "/" every weave
+	// app serves, missing or not it proves the db answers
+	probeKey = "_health"
+)
+
+// MetricsWriter writes metrics in the text format of
+// prometheus, as the app does
+type MetricsWriter interface {
+	WriteMetrics(w io.Writer) error
+}
+
+// HealthConfig sets the http server of the probes, see Health.
+// Addr is empty to turn it off. The limits are in seconds,
+// 0 keeps the defaults of a minute. NoMetrics stops serving
+// "/metrics" on it. All of it can be changed on SIGHUP.
+type HealthConfig struct {
+	Addr         string `json:"addr"`
+	MaxCommitAge int64  `json:"max_commit_age"`
+	MaxSyncLag   int64  `json:"max_sync_lag"`
+	NoMetrics    bool   `json:"no_metrics"`
+}
+
+// Health wraps the app to serve the liveness and readiness
+// probes of the node, eg. for kubernetes.
+//
+// "/healthz" fails once the db stops answering. "/readyz" also
+// fails until a block is committed, if the last commit is older
+// than MaxCommitAge, or if the time of the last block lags behind
+// the clock by more than MaxSyncLag, as on a node catching up.
+//
+// The db is read after every commit and once on creation, so the
+// probes never access the app outside the abci connections.
+type Health struct {
+	abci.Application
+
+	now     func() time.Time
+	metrics MetricsWriter
+
+	mtx          sync.Mutex
+	maxCommitAge time.Duration
+	maxSyncLag   time.Duration
+	noMetrics    bool
+	dbErr        error
+	pending      int64
+	commits      int64
+	blockTime    time.Time
+	lastCommit   time.Time
+}
+
+var _ http.Handler = (*Health)(nil)
+
+// NewHealth wraps app with the limits of the config
+func NewHealth(app abci.Application, cfg HealthConfig) *Health {
+	h := &Health{
+		Application: app,
+		now:         time.Now,
+	}
+	h.Configure(cfg)
+	h.dbErr = probe(app)
+	return h
+}
+
+// Configure applies the limits and metrics setting of cfg,
+// the address is up to the server
+func (h *Health) Configure(cfg HealthConfig) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.maxCommitAge = seconds(cfg.MaxCommitAge, defaultMaxCommitAge)
+	h.maxSyncLag = seconds(cfg.MaxSyncLag, defaultMaxSyncLag)
+	h.noMetrics = cfg.NoMetrics
+}
+
+// WithMetrics also serves the metrics under "/metrics"
+func (h *Health) WithMetrics(m MetricsWriter) *Health {
+	h.metrics = m
+	return h
+}
+
+// seconds returns n seconds, or def if n is not set
+func seconds(n int64, def time.Duration) time.Duration {
+	if n <= 0 {
+		return def
+	}
+	return time.Duration(n) * time.Second
+}
+
+// probe reads a key to make sure the db answers
+func probe(app abci.Application) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("db: %v", r)
+		}
+	}()
+	res := app.Query(abci.RequestQuery{Path: "/", Data: []byte(probeKey)})
+	if res.Code != 0 {
+		return fmt.Errorf("db: %s", res.Log)
+	}
+	return nil
+}
+
+// BeginBlock remembers the time of the block
+func (h *Health) BeginBlock(req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	h.mtx.Lock()
+	h.pending = req.Header.Time
+	h.mtx.Unlock()
+	return h.Application.BeginBlock(req)
+}
+
+// Commit records when the block was committed and
+// whether the db still answers
+func (h *Health) Commit() abci.ResponseCommit {
+	res := h.Application.Commit()
+	dbErr := probe(h.Application)
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.dbErr = dbErr
+	h.blockTime = time.Unix(h.pending, 0)
+	h.lastCommit = h.now()
+	h.commits++
+	return res
+}
+
+// healthReport is the body of both probes
+type healthReport struct {
+	OK bool `json:"ok"`
+	// Error is why the probe failed
+	Error string `json:"error,omitempty"`
+	// Commits counts the blocks committed since start
+	Commits int64 `json:"commits"`
+	// CommitAge and SyncLag are in seconds
+	CommitAge int64 `json:"commit_age"`
+	SyncLag   int64 `json:"sync_lag"`
+}
+
+// live reports whether the db answers
+func (h *Health) live() healthReport {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	rep := healthReport{OK: h.dbErr == nil, Commits: h.commits}
+	if h.dbErr != nil {
+		rep.Error = h.dbErr.Error()
+	}
+	if h.commits > 0 {
+		now := h.now()
+		rep.CommitAge = int64(now.Sub(h.lastCommit) / time.Second)
+		rep.SyncLag = int64(now.Sub(h.blockTime) / time.Second)
+	}
+	return rep
+}
+
+// ready reports whether the node is live and in sync
+func (h *Health) ready() healthReport {
+	rep := h.live()
+	if !rep.OK {
+		return rep
+	}
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	switch now := h.now(); {
+	case h.commits == 0:
+		rep.Error = "no block committed yet"
+	case now.Sub(h.lastCommit) > h.maxCommitAge:
+		rep.Error = fmt.Sprintf("last commit %ds ago", rep.CommitAge)
+	case now.Sub(h.blockTime) > h.maxSyncLag:
+		rep.Error = fmt.Sprintf("last block %ds behind", rep.SyncLag)
+	}
+	rep.OK = rep.Error == ""
+	return rep
+}
+
+// ServeHTTP answers "/healthz" and "/readyz" with the report
+// as json, and 503 if the probe fails, and "/metrics" if set
+// and not turned off
+func (h *Health) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var rep healthReport
+	switch r.URL.Path {
+	case "/healthz":
+		rep = h.live()
+	case "/readyz":
+		rep = h.ready()
+	case "/metrics":
+		h.mtx.Lock()
+		off := h.noMetrics
+		h.mtx.Unlock()
+		if h.metrics == nil || off {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		h.metrics.WriteMetrics(w)
+		return
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if !rep.OK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(rep)
+}
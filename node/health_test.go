@@ -0,0 +1,112 @@
+package node
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+)
+
+func TestHealthProbes(t *testing.T) {
+	app := &dbApp{BaseApplication: abci.NewBaseApplication()}
+	health := NewHealth(app, HealthConfig{MaxCommitAge: 10, MaxSyncLag: 30})
+	clock := time.Unix(1000, 0)
+	health.now = func() time.Time { return clock }
+
+	probe := func(path string) (int, healthReport) {
+		w := httptest.NewRecorder()
+		health.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		var rep healthReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &rep))
+		return w.Code, rep
+	}
+	commit := func(blockTime int64) {
+		health.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Time: blockTime}})
+		health.Commit()
+	}
+
+	// live, but not ready before the first block
+	code, _ := probe("/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	code, rep := probe("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "no block committed yet", rep.Error)
+
+	// catching up on old blocks
+	commit(900)
+	code, rep = probe("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, int64(100), rep.SyncLag)
+
+	commit(995)
+	code, rep = probe("/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int64(2), rep.Commits)
+
+	// no commit for too long
+	clock = clock.Add(11 * time.Second)
+	code, rep = probe("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, int64(11), rep.CommitAge)
+	code, _ = probe("/healthz")
+	assert.Equal(t, http.StatusOK, code)
+
+	// the db is gone
+	app.broken = true
+	commit(1011)
+	code, rep = probe("/healthz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Contains(t, rep.Error, "db")
+
+	w := httptest.NewRecorder()
+	health.ServeHTTP(w, httptest.NewRequest("GET", "/other", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// dbApp panics on queries once its db is broken
+type dbApp struct {
+	*abci.BaseApplication
+	broken bool
+}
+
+func (a *dbApp) Query(req abci.RequestQuery) abci.ResponseQuery {
+	if a.broken {
+		panic("leveldb: closed")
+	}
+	return a.BaseApplication.Query(req)
+}
+
+func TestHealthMetrics(t *testing.T) {
+	health := NewHealth(abci.NewBaseApplication(), HealthConfig{})
+	w := httptest.NewRecorder()
+	health.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	health.WithMetrics(metricsFunc(func(w io.Writer) error {
+		_, err := io.WriteString(w, "bov_tx_panics_total{path=\"cash/send\"} 1\n")
+		return err
+	}))
+	w = httptest.NewRecorder()
+	health.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "bov_tx_panics_total{path=\"cash/send\"} 1\n", w.Body.String())
+
+	// until turned off on reload
+	health.Configure(HealthConfig{NoMetrics: true})
+	w = httptest.NewRecorder()
+	health.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+// metricsFunc writes fixed metrics
+type metricsFunc func(w io.Writer) error
+
+func (f metricsFunc) WriteMetrics(w io.Writer) error {
+	return f(w)
+}
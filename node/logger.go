@@ -0,0 +1,94 @@
+package node
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/tendermint/tmlibs/log"
+)
+
+const (
+	levelDebug int32 = iota
+	levelInfo
+	levelError
+	levelNone
+)
+
+// LevelLogger filters log output by level, like log.NewFilter,
+// but the level can be changed while the node is running.
+// All loggers derived via With share the same level.
+type LevelLogger struct {
+	next  log.Logger
+	level *int32
+}
+
+var _ log.Logger = LevelLogger{}
+
+// NewLevelLogger wraps next, allowing only messages of the
+// given level and above (debug, info, error, none)
+func NewLevelLogger(next log.Logger, level string) (LevelLogger, error) {
+	l := LevelLogger{next: next, level: new(int32)}
+	err := l.SetLevel(level)
+	return l, err
+}
+
+// SetLevel changes the level of this and all derived loggers
+func (l LevelLogger) SetLevel(level string) error {
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	atomic.StoreInt32(l.level, lvl)
+	return nil
+}
+
+// Level returns the name of the current level
+func (l LevelLogger) Level() string {
+	return levelNames[atomic.LoadInt32(l.level)]
+}
+
+// Debug logs if level is debug
+func (l LevelLogger) Debug(msg string, keyvals ...interface{}) {
+	if l.allowed(levelDebug) {
+		l.next.Debug(msg, keyvals...)
+	}
+}
+
+// Info logs if level is info or debug
+func (l LevelLogger) Info(msg string, keyvals ...interface{}) {
+	if l.allowed(levelInfo) {
+		l.next.Info(msg, keyvals...)
+	}
+}
+
+// Error logs unless level is none
+func (l LevelLogger) Error(msg string, keyvals ...interface{}) {
+	if l.allowed(levelError) {
+		l.next.Error(msg, keyvals...)
+	}
+}
+
+// With returns a logger with some context, sharing the level
+func (l LevelLogger) With(keyvals ...interface{}) log.Logger {
+	return LevelLogger{next: l.next.With(keyvals...), level: l.level}
+}
+
+func (l LevelLogger) allowed(lvl int32) bool {
+	return lvl >= atomic.LoadInt32(l.level)
+}
+
+var levelNames = map[int32]string{
+	levelDebug: "debug",
+	levelInfo:  "info",
+	levelError: "error",
+	levelNone:  "none",
+}
+
+func parseLevel(level string) (int32, error) {
+	for lvl, name := range levelNames {
+		if name == level {
+			return lvl, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level: %q", level)
+}
@@ -0,0 +1,53 @@
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tmlibs/log"
+)
+
+func TestLevelLogger(t *testing.T) {
+	cases := []struct {
+		level   string
+		isError bool
+		debug   bool
+		info    bool
+		err     bool
+	}{
+		0: {"debug", false, true, true, true},
+		1: {"info", false, false, true, true},
+		2: {"error", false, false, false, true},
+		3: {"none", false, false, false, false},
+		4: {"trace", true, false, false, false},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := NewLevelLogger(log.NewTMLogger(&buf), "info")
+			require.NoError(t, err)
+			// derived before the level is changed
+			child := logger.With("module", "child")
+
+			err = logger.SetLevel(tc.level)
+			if tc.isError {
+				require.Error(t, err)
+				assert.Equal(t, "info", logger.Level())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.level, logger.Level())
+
+			child.Debug("dbg")
+			assert.Equal(t, tc.debug, bytes.Contains(buf.Bytes(), []byte("dbg")))
+			child.Info("inf")
+			assert.Equal(t, tc.info, bytes.Contains(buf.Bytes(), []byte("inf")))
+			child.Error("err")
+			assert.Equal(t, tc.err, bytes.Contains(buf.Bytes(), []byte("err")))
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package node
+
+import (
+	"fmt"
+	"sync"
+
+	abci "github.com/tendermint/abci/types"
+)
+
+// bov takes 1000-1300
+// node takes 1120-1130
+const (
+	// CodeReadOnly is returned by CheckTx in read-only mode
+	CodeReadOnly = 1120
+	// CodeFollower is returned by CheckTx on a follower
+	CodeFollower = 1121
+)
+
+// Maintenance wraps the app for coordinated upgrades and
+// incident response.
+//
+// With a halt height, the node stops after committing that
+// block. In read-only mode CheckTx rejects all txs, so none
+// enter the mempool, but blocks are still executed and
+// queries served. A follower rejects all txs the same way, but
+// that can only change on restart.
+type Maintenance struct {
+	abci.Application
+
+	mtx        sync.Mutex
+	haltHeight int64
+	readOnly   bool
+	follower   bool
+	applied    bool
+	height     int64
+	halted     chan int64
+}
+
+// NewMaintenance wraps app, with all settings off
+func NewMaintenance(app abci.Application) *Maintenance {
+	return &Maintenance{
+		Application: app,
+		halted:      make(chan int64, 1),
+	}
+}
+
+// Apply sets halt height, read-only and follower mode from the
+// config. It fails if the app already committed the halt height,
+// or follower mode changes after the first call.
+func (m *Maintenance) Apply(cfg Config) error {
+	if cfg.HaltHeight > 0 {
+		last := m.Application.Info(abci.RequestInfo{}).LastBlockHeight
+		if last >= cfg.HaltHeight {
+			return fmt.Errorf("halt height %d already committed, at %d",
+				cfg.HaltHeight, last)
+		}
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.applied && m.follower != cfg.Follower {
+		return fmt.Errorf("follower mode only changes on restart")
+	}
+	m.haltHeight = cfg.HaltHeight
+	m.readOnly = cfg.ReadOnly
+	m.follower = cfg.Follower
+	m.applied = true
+	return nil
+}
+
+// Halted receives the height once the halt height
+// is committed
+func (m *Maintenance) Halted() <-chan int64 {
+	return m.halted
+}
+
+// CheckTx rejects all txs on a follower or in read-only mode
+func (m *Maintenance) CheckTx(tx []byte) abci.ResponseCheckTx {
+	m.mtx.Lock()
+	readOnly, follower := m.readOnly, m.follower
+	m.mtx.Unlock()
+	if follower {
+		return abci.ResponseCheckTx{
+			Code: CodeFollower,
+			Log:  "Node is a follower",
+		}
+	}
+	if readOnly {
+		return abci.ResponseCheckTx{
+			Code: CodeReadOnly,
+			Log:  "Node is read-only",
+		}
+	}
+	return m.Application.CheckTx(tx)
+}
+
+// BeginBlock remembers the height of the block
+func (m *Maintenance) BeginBlock(req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	m.mtx.Lock()
+	m.height = req.Header.Height
+	m.mtx.Unlock()
+	return m.Application.BeginBlock(req)
+}
+
+// Commit signals Halted after the halt height is committed
+func (m *Maintenance) Commit() abci.ResponseCommit {
+	res := m.Application.Commit()
+
+	m.mtx.Lock()
+	halt := m.haltHeight > 0 && m.height >= m.haltHeight
+	height := m.height
+	m.mtx.Unlock()
+	if halt {
+		select {
+		case m.halted <- height:
+		default:
+		}
+	}
+	return res
+}
@@ -0,0 +1,86 @@
+package node
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+)
+
+func TestMaintenanceReadOnly(t *testing.T) {
+	maint := NewMaintenance(abci.NewBaseApplication())
+	assert.Equal(t, uint32(0), maint.CheckTx([]byte("foo")).Code)
+
+	require.NoError(t, maint.Apply(Config{ReadOnly: true}))
+	assert.Equal(t, uint32(CodeReadOnly), maint.CheckTx([]byte("foo")).Code)
+	// blocks are still executed
+	assert.Equal(t, uint32(0), maint.DeliverTx([]byte("foo")).Code)
+
+	require.NoError(t, maint.Apply(Config{}))
+	assert.Equal(t, uint32(0), maint.CheckTx([]byte("foo")).Code)
+}
+
+func TestMaintenanceFollower(t *testing.T) {
+	maint := NewMaintenance(abci.NewBaseApplication())
+	require.NoError(t, maint.Apply(Config{Follower: true}))
+	assert.Equal(t, uint32(CodeFollower), maint.CheckTx([]byte("foo")).Code)
+	assert.Equal(t, uint32(0), maint.DeliverTx([]byte("foo")).Code)
+
+	// other settings still reload, follower mode does not
+	require.NoError(t, maint.Apply(Config{Follower: true, ReadOnly: true}))
+	assert.Error(t, maint.Apply(Config{}))
+	assert.Equal(t, uint32(CodeFollower), maint.CheckTx([]byte("foo")).Code)
+
+	// and a validator cannot become one
+	maint = NewMaintenance(abci.NewBaseApplication())
+	require.NoError(t, maint.Apply(Config{}))
+	assert.Error(t, maint.Apply(Config{Follower: true}))
+	assert.Equal(t, uint32(0), maint.CheckTx([]byte("foo")).Code)
+}
+
+func TestMaintenanceHalt(t *testing.T) {
+	app := &heightApp{BaseApplication: abci.NewBaseApplication()}
+	maint := NewMaintenance(app)
+	require.NoError(t, maint.Apply(Config{HaltHeight: 3}))
+
+	for h := int64(1); h <= 3; h++ {
+		select {
+		case <-maint.Halted():
+			t.Fatalf("halted before %d", h)
+		default:
+		}
+		maint.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: h}})
+		maint.Commit()
+	}
+	select {
+	case height := <-maint.Halted():
+		assert.Equal(t, int64(3), height)
+	default:
+		t.Fatal("not halted")
+	}
+
+	// cannot restart with a committed halt height
+	assert.Error(t, maint.Apply(Config{HaltHeight: 3}))
+	assert.NoError(t, maint.Apply(Config{HaltHeight: 4}))
+}
+
+// heightApp reports the last committed height in Info
+type heightApp struct {
+	*abci.BaseApplication
+	height, committed int64
+}
+
+func (h *heightApp) BeginBlock(req abci.RequestBeginBlock) abci.ResponseBeginBlock {
+	h.height = req.Header.Height
+	return abci.ResponseBeginBlock{}
+}
+
+func (h *heightApp) Commit() abci.ResponseCommit {
+	h.committed = h.height
+	return abci.ResponseCommit{}
+}
+
+func (h *heightApp) Info(req abci.RequestInfo) abci.ResponseInfo {
+	return abci.ResponseInfo{LastBlockHeight: h.committed}
+}
@@ -0,0 +1,291 @@
+/*
+Package node runs the bov abci server and manages the
+node process: signals, runtime config and shutdown.
+*/
+package node
+
+import (
+	"flag"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/tendermint/abci/server"
+	abci "github.com/tendermint/abci/types"
+	cmn "github.com/tendermint/tmlibs/common"
+	"github.com/tendermint/tmlibs/log"
+
+	weaveserver "github.com/confio/weave/commands/server"
+)
+
+const (
+	flagBind       = "bind"
+	flagConfig     = "config"
+	flagFollower   = "follower"
+	flagHaltHeight = "halt-height"
+	flagHealth     = "health"
+	flagReadOnly   = "read-only"
+)
+
+// startOptions are the flags of the start command
+type startOptions struct {
+	addr       string
+	config     string
+	haltHeight int64
+	readOnly   bool
+	follower   bool
+	health     string
+}
+
+// apply overrides the config with the flags that are set
+func (o startOptions) apply(cfg Config) Config {
+	if o.haltHeight > 0 {
+		cfg.HaltHeight = o.haltHeight
+	}
+	if o.readOnly {
+		cfg.ReadOnly = true
+	}
+	if o.follower {
+		cfg.Follower = true
+	}
+	if o.health != "" {
+		cfg.Health.Addr = o.health
+	}
+	return cfg
+}
+
+// loadConfig reads the config file and applies the flags
+func (o startOptions) loadConfig() (Config, error) {
+	cfg, err := LoadConfig(o.config)
+	if err != nil {
+		return cfg, err
+	}
+	return o.apply(cfg), nil
+}
+
+func parseStart(home string, args []string) (startOptions, error) {
+	// parse flags and return the result
+	var opts startOptions
+	startFlags := flag.NewFlagSet("start", flag.ExitOnError)
+	startFlags.StringVar(&opts.addr, flagBind, "tcp://localhost:46658", "address server listens on")
+	startFlags.StringVar(&opts.config, flagConfig, filepath.Join(home, ConfigFile),
+		"settings file, reloaded on SIGHUP")
+	startFlags.Int64Var(&opts.haltHeight, flagHaltHeight, 0,
+		"stop after committing this block, overrides the settings file")
+	startFlags.BoolVar(&opts.readOnly, flagReadOnly, false,
+		"reject all new txs but serve queries, overrides the settings file")
+	startFlags.BoolVar(&opts.follower, flagFollower, false,
+		"only replay blocks and serve queries, overrides the settings file")
+	startFlags.StringVar(&opts.health, flagHealth, "",
+		"address to serve /healthz and /readyz on, eg. localhost:46660, overrides the settings file")
+	err := startFlags.Parse(args)
+	return opts, err
+}
+
+// StartCmd initializes the application, and runs the abci
+// server until it receives SIGINT or SIGTERM.
+//
+// On SIGHUP the config file is read again and applied.
+// A broken config file is logged and the old settings kept.
+//
+// Once the halt height is committed, the server shuts
+// down as on SIGTERM.
+//
+// A follower replays the blocks it gets from its peers (usually
+// sentries) and serves queries, but rejects all txs and never takes
+// part in consensus. It refuses to start if the validator key in
+// home is one of the genesis validators, so it can take the query
+// load off the validators without risking a double sign.
+//
+// With a health address, it serves the probes of Health over http
+// until it shuts down. On SIGHUP the server moves to the new
+// address, and the limits and metrics settings apply at once.
+func StartCmd(gen weaveserver.AppGenerator, logger log.Logger, home string, args []string) error {
+	opts, err := parseStart(home, args)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := opts.loadConfig()
+	if err != nil {
+		return err
+	}
+	levels, err := NewLevelLogger(logger, cfg.LogLevel)
+	if err != nil {
+		return err
+	}
+	logger = levels
+	if cfg.Follower {
+		err = checkFollower(home)
+		if err != nil {
+			return err
+		}
+	}
+
+	// listen before we start anything, so no signal is lost
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	// Generate the app in the proper dir
+	app, err := gen(home, logger)
+	if err != nil {
+		return err
+	}
+	maint := NewMaintenance(app)
+	err = maint.Apply(cfg)
+	if err != nil {
+		if closer, ok := app.(io.Closer); ok {
+			closer.Close()
+		}
+		return err
+	}
+
+	health := NewHealth(maint, cfg.Health)
+	if m, ok := app.(MetricsWriter); ok {
+		health.WithMetrics(m)
+	}
+
+	logger.Info("Starting ABCI app", "bind", opts.addr, "halt_height", cfg.HaltHeight,
+		"read_only", cfg.ReadOnly, "follower", cfg.Follower, "health", cfg.Health.Addr)
+
+	probes := &healthServer{health: health, logger: logger}
+	err = probes.Listen(cfg.Health.Addr)
+	if err != nil {
+		return errors.Errorf("Error creating health listener: %v\n", err)
+	}
+	defer probes.Close()
+
+	svr, err := server.NewServer(opts.addr, "socket", health)
+	if err != nil {
+		return errors.Errorf("Error creating listener: %v\n", err)
+	}
+	svr.SetLogger(logger.With("module", "abci-server"))
+	err = svr.Start()
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case sig := <-sigs:
+			if sig == syscall.SIGHUP {
+				reload(levels, maint, probes, opts)
+				continue
+			}
+			logger.Info("Stopping ABCI app", "signal", sig)
+		case height := <-maint.Halted():
+			logger.Info("Stopping ABCI app", "halt_height", height)
+		}
+		return shutdown(svr, app)
+	}
+}
+
+// shutdown closes all listeners, so no new requests come
+// in, and then releases the app. The app is expected to
+// wait for a running Commit before it closes the db.
+func shutdown(svr cmn.Service, app abci.Application) error {
+	err := svr.Stop()
+	if closer, ok := app.(io.Closer); ok {
+		cerr := closer.Close()
+		if err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// reload applies all runtime settings from the config file,
+// the flags still override it. The health server moves if
+// its address changed.
+func reload(levels LevelLogger, maint *Maintenance, probes *healthServer, opts startOptions) {
+	cfg, err := opts.loadConfig()
+	if err == nil {
+		err = maint.Apply(cfg)
+	}
+	if err != nil {
+		levels.Error("Cannot reload config", "file", opts.config, "err", err)
+		return
+	}
+	// cannot fail, LoadConfig validates
+	levels.SetLevel(cfg.LogLevel)
+	probes.health.Configure(cfg.Health)
+	err = probes.Listen(cfg.Health.Addr)
+	if err != nil {
+		levels.Error("Cannot move health server", "addr", cfg.Health.Addr, "err", err)
+	}
+	levels.Info("Reloaded config", "file", opts.config, "log_level", cfg.LogLevel,
+		"halt_height", cfg.HaltHeight, "read_only", cfg.ReadOnly, "health", probes.Addr())
+}
+
+// healthServer serves the probes of health over http on
+// one address at a time
+type healthServer struct {
+	health *Health
+	logger log.Logger
+
+	addr string
+	svr  io.Closer
+}
+
+// Addr is where the probes are served, empty if nowhere
+func (s *healthServer) Addr() string {
+	return s.addr
+}
+
+// Listen serves the probes on addr instead, nowhere if it is
+// empty. If addr can't be listened on, the probes stay where
+// they were.
+func (s *healthServer) Listen(addr string) error {
+	if s.svr != nil && addr == s.addr {
+		return nil
+	}
+	// free the port first, it may only be the host that changed
+	s.Close()
+	svr, err := serveHealth(addr, s.health, s.logger)
+	if err != nil {
+		if s.addr != "" {
+			s.svr, _ = serveHealth(s.addr, s.health, s.logger)
+		}
+		return err
+	}
+	s.svr, s.addr = svr, addr
+	return nil
+}
+
+// Close stops serving the probes
+func (s *healthServer) Close() error {
+	if s.svr == nil {
+		return nil
+	}
+	err := s.svr.Close()
+	s.svr = nil
+	return err
+}
+
+// serveHealth serves the probes on addr in the background,
+// it serves nothing if addr is empty. The server stops when
+// it is closed.
+func serveHealth(addr string, health *Health, logger log.Logger) (io.Closer, error) {
+	svr := &http.Server{Addr: addr, Handler: health}
+	if addr == "" {
+		return svr, nil
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		err := svr.Serve(ln)
+		if err != http.ErrServerClosed {
+			logger.Error("Health server stopped", "err", err)
+		}
+	}()
+	return svr, nil
+}
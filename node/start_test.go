@@ -0,0 +1,108 @@
+package node
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+	"github.com/tendermint/tmlibs/log"
+)
+
+func TestStartReload(t *testing.T) {
+	home, err := ioutil.TempDir("", "bov-start")
+	require.NoError(t, err)
+	defer os.RemoveAll(home)
+	configFile := filepath.Join(home, "bov.json")
+
+	started := make(chan LevelLogger, 1)
+	myApp := &closingApp{BaseApplication: abci.NewBaseApplication()}
+	gen := func(home string, logger log.Logger) (abci.Application, error) {
+		started <- logger.(LevelLogger)
+		return myApp, nil
+	}
+
+	done := make(chan error, 1)
+	args := []string{"-bind", "localhost:11133", "-config", configFile}
+	go func() {
+		done <- StartCmd(gen, log.NewNopLogger(), home, args)
+	}()
+
+	var logger LevelLogger
+	select {
+	case logger = <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("app not started")
+	}
+	assert.Equal(t, "info", logger.Level())
+
+	// reload with a new level
+	err = ioutil.WriteFile(configFile, []byte(`{"log_level": "error"}`), 0644)
+	require.NoError(t, err)
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	waitFor(t, func() bool { return logger.Level() == "error" })
+
+	// the probes move to the new address
+	err = ioutil.WriteFile(configFile, []byte(`{"log_level": "error", "health": {"addr": "localhost:11134"}}`), 0644)
+	require.NoError(t, err)
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	waitFor(t, func() bool { return serves("localhost:11134") })
+	err = ioutil.WriteFile(configFile, []byte(`{"log_level": "error", "health": {"addr": "localhost:11135"}}`), 0644)
+	require.NoError(t, err)
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	waitFor(t, func() bool { return serves("localhost:11135") })
+	assert.False(t, serves("localhost:11134"))
+
+	// broken config keeps the old settings
+	err = ioutil.WriteFile(configFile, []byte(`{"log_level": "foo"}`), 0644)
+	require.NoError(t, err)
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, "error", logger.Level())
+
+	// and shut down cleanly
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGTERM))
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("server not stopped")
+	}
+	assert.True(t, myApp.closed)
+}
+
+type closingApp struct {
+	*abci.BaseApplication
+	closed bool
+}
+
+func (c *closingApp) Close() error {
+	c.closed = true
+	return nil
+}
+
+// serves is true if the health probes answer on addr
+func serves(addr string) bool {
+	res, err := http.Get("http://" + addr + "/healthz")
+	if err != nil {
+		return false
+	}
+	res.Body.Close()
+	return true
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	for i := 0; i < 100; i++ {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("condition not met")
+}
@@ -0,0 +1,87 @@
+/*
+Package ordered has helpers to walk collections in a fixed order.
+
+Everything a Deliver writes to the store or returns in the tags
+must not depend on the random order of a map range, or the nodes
+compute different app hashes and halt. Collect into a map if you
+need to, but iterate over Keys or a Set.
+
+cmd/detcheck flags map ranges in Deliver paths.
+*/
+package ordered
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Keys returns the keys of a map with string keys, sorted.
+// It panics if m is not such a map.
+func Keys(m interface{}) []string {
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		panic(fmt.Sprintf("ordered.Keys of %T", m))
+	}
+	res := make([]string, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		res = append(res, k.String())
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Set is a set of strings, that returns its values sorted.
+// The zero value is an empty set.
+type Set struct {
+	values []string
+}
+
+// NewSet returns a set with the given values
+func NewSet(values ...string) *Set {
+	s := new(Set)
+	for _, v := range values {
+		s.Add(v)
+	}
+	return s
+}
+
+// Add inserts v, it returns false if v was in the set already
+func (s *Set) Add(v string) bool {
+	i := sort.SearchStrings(s.values, v)
+	if i < len(s.values) && s.values[i] == v {
+		return false
+	}
+	s.values = append(s.values, "")
+	copy(s.values[i+1:], s.values[i:])
+	s.values[i] = v
+	return true
+}
+
+// Has returns true if v is in the set
+func (s *Set) Has(v string) bool {
+	i := sort.SearchStrings(s.values, v)
+	return i < len(s.values) && s.values[i] == v
+}
+
+// Len returns the number of values in the set
+func (s *Set) Len() int {
+	return len(s.values)
+}
+
+// Values returns a sorted copy of the values
+func (s *Set) Values() []string {
+	res := make([]string, len(s.values))
+	copy(res, s.values)
+	return res
+}
+
+// SortBytes sorts byte slices, like addresses or db keys,
+// in place and returns them
+func SortBytes(list [][]byte) [][]byte {
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i], list[j]) < 0
+	})
+	return list
+}
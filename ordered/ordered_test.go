@@ -0,0 +1,48 @@
+package ordered
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeys(t *testing.T) {
+	m := map[string]int{"foo": 1, "bar": 2, "baz": 3, "": 4}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, []string{"", "bar", "baz", "foo"}, Keys(m))
+	}
+
+	type ticker string
+	assert.Equal(t, []string{"BAR", "FOO"}, Keys(map[ticker]bool{"FOO": true, "BAR": true}))
+	assert.Equal(t, []string{}, Keys(map[string]int(nil)))
+
+	assert.Panics(t, func() { Keys(map[int]int{1: 1}) })
+	assert.Panics(t, func() { Keys([]string{"foo"}) })
+}
+
+func TestSet(t *testing.T) {
+	var s Set
+	assert.Equal(t, 0, s.Len())
+	assert.False(t, s.Has("foo"))
+
+	assert.True(t, s.Add("foo"))
+	assert.True(t, s.Add("bar"))
+	assert.False(t, s.Add("foo"))
+	assert.True(t, s.Add("baz"))
+	assert.True(t, s.Has("foo"))
+	assert.False(t, s.Has("qux"))
+	assert.Equal(t, 3, s.Len())
+
+	values := s.Values()
+	assert.Equal(t, []string{"bar", "baz", "foo"}, values)
+	// values is a copy
+	values[0] = "zzz"
+	assert.True(t, s.Has("bar"))
+
+	assert.Equal(t, []string{"a", "b"}, NewSet("b", "a", "b").Values())
+}
+
+func TestSortBytes(t *testing.T) {
+	list := [][]byte{[]byte("foo"), {1, 2}, []byte("bar"), {1}}
+	assert.Equal(t, [][]byte{{1}, {1, 2}, []byte("bar"), []byte("foo")}, SortBytes(list))
+}
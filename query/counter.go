@@ -0,0 +1,71 @@
+package query
+
+import (
+	"encoding/binary"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+)
+
+// counterPrefix is followed by the prefix that is counted
+const counterPrefix = "_n."
+
+func init() {
+	keyspace.Register("query", counterPrefix)
+}
+
+// Counter is the number of keys under a prefix, kept in the store
+// next to them so a page can include the total without reading
+// all keys. Whoever writes under the prefix must keep it up to
+// date, with Save and Delete or by calling Add.
+type Counter struct {
+	key []byte
+}
+
+// NewCounter returns the counter of the keys under prefix
+func NewCounter(prefix []byte) Counter {
+	return Counter{key: append([]byte(counterPrefix), prefix...)}
+}
+
+// Count returns the current count, 0 if none was stored
+func (c Counter) Count(db weave.ReadOnlyKVStore) int64 {
+	bz := db.Get(c.key)
+	if len(bz) != 8 {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(bz))
+}
+
+// Add changes the count by n, which may be negative
+func (c Counter) Add(db weave.KVStore, n int64) {
+	count := c.Count(db) + n
+	if count <= 0 {
+		db.Delete(c.key)
+		return
+	}
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(count))
+	db.Set(c.key, bz)
+}
+
+// Save writes obj to the bucket, and counts it if it is new
+func Save(db weave.KVStore, bucket orm.Bucket, obj orm.Object) error {
+	exists := db.Has(bucket.DBKey(obj.Key()))
+	err := bucket.Save(db, obj)
+	if err == nil && !exists {
+		NewCounter(bucket.DBKey(nil)).Add(db, 1)
+	}
+	return err
+}
+
+// Delete removes the key from the bucket and its count
+func Delete(db weave.KVStore, bucket orm.Bucket, key []byte) error {
+	exists := db.Has(bucket.DBKey(key))
+	err := bucket.Delete(db, key)
+	if err == nil && exists {
+		NewCounter(bucket.DBKey(nil)).Add(db, -1)
+	}
+	return err
+}
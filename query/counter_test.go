@@ -0,0 +1,41 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/store"
+)
+
+func TestCounter(t *testing.T) {
+	bucket := orm.NewBucket("items", orm.NewSimpleObj(nil, new(orm.Counter)))
+	counter := NewCounter(bucket.DBKey(nil))
+	db := store.MemStore()
+	assert.Equal(t, int64(0), counter.Count(db))
+
+	save := func(key string, n int64) {
+		require.NoError(t, Save(db, bucket, orm.NewSimpleObj([]byte(key), &orm.Counter{Count: n})))
+	}
+	save("a", 1)
+	save("b", 2)
+	assert.Equal(t, int64(2), counter.Count(db))
+	// an update is not counted again
+	save("a", 5)
+	assert.Equal(t, int64(2), counter.Count(db))
+
+	// neither is a missing key
+	require.NoError(t, Delete(db, bucket, []byte("c")))
+	assert.Equal(t, int64(2), counter.Count(db))
+	require.NoError(t, Delete(db, bucket, []byte("a")))
+	require.NoError(t, Delete(db, bucket, []byte("b")))
+	assert.Equal(t, int64(0), counter.Count(db))
+	// nothing is left behind
+	assert.False(t, db.Has(counter.key))
+
+	counter.Add(db, 3)
+	counter.Add(db, -1)
+	assert.Equal(t, int64(2), counter.Count(db))
+}
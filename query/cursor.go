@@ -0,0 +1,167 @@
+/*
+Package query adds queries over large buckets, that return
+the results in chunks rather than all at once.
+
+A client reads a bucket by repeating the query, passing the
+key of the last result as cursor, until it gets an empty
+page. Each response stays small, no matter how big the
+bucket gets.
+
+All paged queries take the same PageRequest, so every client
+paginates identically: the limit, order and whether to count
+the bucket go in the path, the cursor in the data. The total
+is read from a Counter, so only buckets that keep one offer it.
+*/
+package query
+
+import (
+	"bytes"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/store"
+)
+
+// DefaultPageSize is used when registering a bucket with limit 0
+const DefaultPageSize = 100
+
+// PagePath is appended to the bucket path to register the query
+const PagePath = "/page"
+
+// CursorQuery returns one page of a bucket, in key order,
+// as described by a PageRequest. The limit of the request is
+// capped to the limit of the query.
+//
+// The cursor is the full db key (as returned in the results)
+// of the last item of the previous page. An empty cursor
+// starts at the beginning, or at the end if reversed.
+type CursorQuery struct {
+	prefix  []byte
+	limit   int
+	counted bool
+}
+
+var _ weave.QueryHandler = CursorQuery{}
+
+// NewCursorQuery pages over all items in the bucket
+func NewCursorQuery(bucket orm.Bucket, limit int) CursorQuery {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	return CursorQuery{prefix: bucket.DBKey(nil), limit: limit}
+}
+
+// Counted returns the query with the total, for a bucket
+// only written with Save and Delete of this package
+func (q CursorQuery) Counted() CursorQuery {
+	q.counted = true
+	return q
+}
+
+// Register adds a CursorQuery for the bucket under
+// path + "/page", eg. "/tokens/page"
+func Register(qr weave.QueryRouter, path string, bucket orm.Bucket, limit int) {
+	qr.Register(path+PagePath, NewCursorQuery(bucket, limit))
+}
+
+// RegisterCounted is Register for a bucket that keeps a
+// Counter, so the page can include the total
+func RegisterCounted(qr weave.QueryRouter, path string, bucket orm.Bucket, limit int) {
+	qr.Register(path+PagePath, NewCursorQuery(bucket, limit).Counted())
+}
+
+// Query implements weave.QueryHandler
+func (q CursorQuery) Query(db weave.ReadOnlyKVStore, mod string,
+	data []byte) ([]weave.Model, error) {
+
+	req, err := ParsePageRequest(mod, data)
+	if err != nil {
+		return nil, err
+	}
+	if req.IncludeTotal && !q.counted {
+		return nil, ErrInvalidPageRequest(paramTotal)
+	}
+	res, err := Page(db, q.prefix, req, q.limit)
+	if err != nil || !req.IncludeTotal {
+		return res, err
+	}
+	return WithTotal(res, NewCounter(q.prefix).Count(db)), nil
+}
+
+// Page returns the page of all keys with prefix that req
+// describes, with at most max items. The total is left to
+// the caller, see WithTotal.
+func Page(db weave.ReadOnlyKVStore, prefix []byte, req PageRequest,
+	max int) ([]weave.Model, error) {
+
+	cursor := req.Cursor
+	if len(cursor) > 0 && !bytes.HasPrefix(cursor, prefix) {
+		return nil, ErrInvalidCursor(cursor)
+	}
+	limit := max
+	if req.Limit > 0 && req.Limit < limit {
+		limit = req.Limit
+	}
+
+	var itr store.Iterator
+	switch {
+	case req.Reverse && len(cursor) > 0:
+		// the end is exclusive, so this starts before the cursor
+		itr = db.ReverseIterator(prefix, cursor)
+	case req.Reverse:
+		itr = db.ReverseIterator(prefix, prefixEnd(prefix))
+	case len(cursor) > 0:
+		// the first key after the cursor
+		start := append(append([]byte{}, cursor...), 0)
+		itr = db.Iterator(start, prefixEnd(prefix))
+	default:
+		itr = db.Iterator(prefix, prefixEnd(prefix))
+	}
+	defer itr.Close()
+
+	var res []weave.Model
+	for ; itr.Valid() && len(res) < limit; itr.Next() {
+		res = append(res, weave.Model{Key: itr.Key(), Value: itr.Value()})
+	}
+	return res, nil
+}
+
+// Pager fetches one page for the given cursor, eg. by calling
+// abci Query with the cursor as data
+type Pager func(cursor []byte) ([]weave.Model, error)
+
+// Stream calls fetch until all pages are read, passing every
+// result to cb. It stops on the first error of fetch or cb.
+func Stream(fetch Pager, cb func(weave.Model) error) error {
+	var cursor []byte
+	for {
+		page, err := fetch(cursor)
+		if err != nil {
+			return err
+		}
+		page, _, _ = SplitTotal(page)
+		if len(page) == 0 {
+			return nil
+		}
+		for _, m := range page {
+			err = cb(m)
+			if err != nil {
+				return err
+			}
+		}
+		cursor = page[len(page)-1].Key
+	}
+}
+
+// prefixEnd returns the first key after all keys with prefix
+// (nil if there is none)
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for l := len(end) - 1; l >= 0; l-- {
+		end[l]++
+		if end[l] != 0 {
+			return end[:l+1]
+		}
+	}
+	return nil
+}
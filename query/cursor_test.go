@@ -0,0 +1,165 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/store"
+)
+
+func TestPrefixEnd(t *testing.T) {
+	cases := []struct {
+		prefix, end []byte
+	}{
+		0: {[]byte("esc:"), []byte("esc;")},
+		1: {[]byte{1, 0xff}, []byte{2}},
+		2: {[]byte{0xff, 0xff}, nil},
+	}
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			assert.Equal(t, tc.end, prefixEnd(tc.prefix))
+		})
+	}
+}
+
+func TestCursorQuery(t *testing.T) {
+	bucket := orm.NewBucket("items", orm.NewSimpleObj(nil, new(orm.Counter)))
+	other := orm.NewBucket("itemsx", orm.NewSimpleObj(nil, new(orm.Counter)))
+
+	db := store.MemStore()
+	total := 25
+	for i := 0; i < total; i++ {
+		key := []byte(fmt.Sprintf("key-%02d", i))
+		require.NoError(t, bucket.Save(db, orm.NewSimpleObj(key, &orm.Counter{Count: int64(i)})))
+		// must never show up in the results
+		require.NoError(t, other.Save(db, orm.NewSimpleObj(key, &orm.Counter{Count: 1})))
+	}
+
+	qr := weave.NewQueryRouter()
+	Register(qr, "/items", bucket, 10)
+	h := qr.Handler("/items/page")
+	require.NotNil(t, h)
+
+	fetch := func(cursor []byte) ([]weave.Model, error) {
+		return h.Query(db, "", cursor)
+	}
+
+	// first page
+	page, err := fetch(nil)
+	require.NoError(t, err)
+	require.Equal(t, 10, len(page))
+	assert.Equal(t, bucket.DBKey([]byte("key-00")), page[0].Key)
+
+	// read everything in chunks
+	var keys [][]byte
+	pages := 0
+	counting := func(cursor []byte) ([]weave.Model, error) {
+		pages++
+		return fetch(cursor)
+	}
+	err = Stream(counting, func(m weave.Model) error {
+		keys = append(keys, m.Key)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, total, len(keys))
+	// 3 full pages and one empty
+	assert.Equal(t, 4, pages)
+	for i, key := range keys {
+		assert.Equal(t, bucket.DBKey([]byte(fmt.Sprintf("key-%02d", i))), key)
+	}
+
+	// stop on callback error
+	stop := fmt.Errorf("stop")
+	err = Stream(fetch, func(m weave.Model) error { return stop })
+	assert.Equal(t, stop, err)
+
+	// bad input
+	_, err = fetch([]byte("foo"))
+	assert.True(t, IsInvalidCursorErr(err))
+	_, err = h.Query(db, "prefix", nil)
+	assert.True(t, IsInvalidPageRequestErr(err))
+	// the bucket is not counted
+	_, err = h.Query(db, "include_total=true", nil)
+	assert.True(t, IsInvalidPageRequestErr(err))
+}
+
+func TestPageRequest(t *testing.T) {
+	bucket := orm.NewBucket("items", orm.NewSimpleObj(nil, new(orm.Counter)))
+
+	db := store.MemStore()
+	total := 25
+	for i := 0; i < total; i++ {
+		key := []byte(fmt.Sprintf("key-%02d", i))
+		require.NoError(t, Save(db, bucket, orm.NewSimpleObj(key, &orm.Counter{Count: int64(i)})))
+	}
+	key := func(i int) []byte {
+		return bucket.DBKey([]byte(fmt.Sprintf("key-%02d", i)))
+	}
+
+	qr := weave.NewQueryRouter()
+	RegisterCounted(qr, "/items", bucket, 10)
+	h := qr.Handler("/items/page")
+	require.NotNil(t, h)
+
+	cases := []struct {
+		req   PageRequest
+		path  string
+		keys  [][]byte
+		total int64
+	}{
+		// limit is capped by the query
+		0: {PageRequest{Limit: 3}, "/items/page?limit=3",
+			[][]byte{key(0), key(1), key(2)}, -1},
+		1: {PageRequest{Limit: 50, Cursor: key(19)}, "/items/page?limit=50",
+			[][]byte{key(20), key(21), key(22), key(23), key(24)}, -1},
+		2: {PageRequest{Limit: 2, Reverse: true}, "/items/page?limit=2&reverse=true",
+			[][]byte{key(24), key(23)}, -1},
+		3: {PageRequest{Limit: 2, Reverse: true, Cursor: key(1)},
+			"/items/page?limit=2&reverse=true", [][]byte{key(0)}, -1},
+		4: {PageRequest{Limit: 1, IncludeTotal: true},
+			"/items/page?include_total=true&limit=1", [][]byte{key(0)}, 25},
+		5: {PageRequest{IncludeTotal: true, Cursor: key(24)},
+			"/items/page?include_total=true", nil, 25},
+	}
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			path := tc.req.Path("/items")
+			assert.Equal(t, tc.path, path)
+
+			mod := ""
+			if i := strings.Index(path, "?"); i >= 0 {
+				mod = path[i+1:]
+			}
+			req, err := ParsePageRequest(mod, tc.req.Cursor)
+			require.NoError(t, err)
+			assert.Equal(t, tc.req, req)
+
+			page, err := h.Query(db, mod, tc.req.Cursor)
+			require.NoError(t, err)
+			page, n, ok := SplitTotal(page)
+			assert.Equal(t, tc.total >= 0, ok)
+			if ok {
+				assert.Equal(t, tc.total, n)
+			}
+			var keys [][]byte
+			for _, m := range page {
+				keys = append(keys, m.Key)
+			}
+			assert.Equal(t, tc.keys, keys)
+		})
+	}
+
+	// bad input
+	for _, mod := range []string{"limit=-1", "limit=a", "reverse=maybe",
+		"limit=1&limit=2", "order=asc"} {
+		_, err := h.Query(db, mod, nil)
+		assert.True(t, IsInvalidPageRequestErr(err), mod)
+	}
+}
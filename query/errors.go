@@ -0,0 +1,35 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// query takes 1030-1040
+const (
+	CodeInvalidCursor      = 1030
+	CodeInvalidPageRequest = 1031
+)
+
+var (
+	errInvalidCursor      = fmt.Errorf("Invalid cursor")
+	errInvalidPageRequest = fmt.Errorf("Invalid page request")
+)
+
+func ErrInvalidCursor(cursor []byte) error {
+	msg := fmt.Sprintf("%X", cursor)
+	return errors.WithLog(msg, errInvalidCursor, CodeInvalidCursor)
+}
+func IsInvalidCursorErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidCursor)
+}
+
+func ErrInvalidPageRequest(param string) error {
+	return errors.WithLog(param, errInvalidPageRequest, CodeInvalidPageRequest)
+}
+func IsInvalidPageRequestErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidPageRequest)
+}
@@ -0,0 +1,94 @@
+package query
+
+import (
+	"bytes"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+)
+
+// IndexQuery pages over the objects one value of an index
+// refers to, eg. the escrows of one sender. The data of the
+// first page is the value. Every result is the object, keyed by
+// the index key of the value followed by the primary key, which
+// is the cursor of the following page.
+//
+// The primary keys must all be of the same size. They are stored
+// sorted with the value, so the total is read along with them.
+type IndexQuery struct {
+	bucket orm.Bucket
+	index  orm.Index
+	size   int
+	limit  int
+}
+
+var _ weave.QueryHandler = IndexQuery{}
+
+// NewIndexQuery pages over the index of the bucket, whose keys
+// are size bytes, at most limit (or DefaultPageSize) at once
+func NewIndexQuery(bucket orm.Bucket, index orm.Index, size, limit int) IndexQuery {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	return IndexQuery{bucket: bucket, index: index, size: size, limit: limit}
+}
+
+// RegisterIndex adds an IndexQuery under path + "/page",
+// eg. "/escrows/sender/page"
+func RegisterIndex(qr weave.QueryRouter, path string, bucket orm.Bucket,
+	index orm.Index, size, limit int) {
+
+	qr.Register(path+PagePath, NewIndexQuery(bucket, index, size, limit))
+}
+
+// Query implements weave.QueryHandler
+func (q IndexQuery) Query(db weave.ReadOnlyKVStore, mod string,
+	data []byte) ([]weave.Model, error) {
+
+	req, err := ParsePageRequest(mod, data)
+	if err != nil {
+		return nil, err
+	}
+	value, cursor := data, []byte(nil)
+	if start := q.index.IndexKey(nil); bytes.HasPrefix(data, start) {
+		if len(data) <= len(start)+q.size {
+			return nil, ErrInvalidCursor(data)
+		}
+		value = data[len(start) : len(data)-q.size]
+		cursor = data[len(data)-q.size:]
+	}
+	if len(value) == 0 {
+		return nil, ErrInvalidCursor(data)
+	}
+	refs, err := q.index.GetAt(db, value)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := q.limit
+	if req.Limit > 0 && req.Limit < limit {
+		limit = req.Limit
+	}
+	var res []weave.Model
+	for i := range refs {
+		ref := refs[i]
+		if req.Reverse {
+			ref = refs[len(refs)-1-i]
+		}
+		if len(res) == limit {
+			break
+		}
+		if cursor != nil {
+			cmp := bytes.Compare(ref, cursor)
+			if cmp == 0 || (cmp < 0) != req.Reverse {
+				continue
+			}
+		}
+		key := append(q.index.IndexKey(value), ref...)
+		res = append(res, weave.Model{Key: key, Value: db.Get(q.bucket.DBKey(ref))})
+	}
+	if req.IncludeTotal {
+		res = WithTotal(res, int64(len(refs)))
+	}
+	return res, nil
+}
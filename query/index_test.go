@@ -0,0 +1,91 @@
+package query
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/store"
+)
+
+// byCount indexes a counter by its count modulo 2
+func byCount(obj orm.Object) ([]byte, error) {
+	c := obj.Value().(*orm.Counter)
+	return []byte{byte(c.Count % 2)}, nil
+}
+
+func TestIndexQuery(t *testing.T) {
+	bucket := orm.NewBucket("items", orm.NewSimpleObj(nil, new(orm.Counter))).
+		WithIndex("parity", byCount, false)
+	index := orm.NewIndex("items_parity", byCount, false, bucket.DBKey)
+
+	db := store.MemStore()
+	ref := func(i int) []byte {
+		bz := make([]byte, 8)
+		binary.BigEndian.PutUint64(bz, uint64(i))
+		return bz
+	}
+	for i := 0; i < 9; i++ {
+		require.NoError(t, bucket.Save(db, orm.NewSimpleObj(ref(i), &orm.Counter{Count: int64(i)})))
+	}
+	even := []byte{0}
+	key := func(i int) []byte {
+		return append(index.IndexKey(even), ref(i)...)
+	}
+
+	qr := weave.NewQueryRouter()
+	RegisterIndex(qr, "/items/parity", bucket, index, 8, 2)
+	h := qr.Handler("/items/parity/page")
+	require.NotNil(t, h)
+
+	cases := []struct {
+		path  string
+		data  []byte
+		keys  [][]byte
+		total int64
+	}{
+		0: {"/items/parity/page", even, [][]byte{key(0), key(2)}, -1},
+		1: {"/items/parity/page", key(2), [][]byte{key(4), key(6)}, -1},
+		2: {"/items/parity/page?limit=5", key(6), [][]byte{key(8)}, -1},
+		3: {"/items/parity/page?reverse=true", even, [][]byte{key(8), key(6)}, -1},
+		4: {"/items/parity/page?reverse=true", key(2), [][]byte{key(0)}, -1},
+		5: {"/items/parity/page?include_total=true&limit=1", even, [][]byte{key(0)}, 5},
+		6: {"/items/parity/page?include_total=true", []byte{7}, nil, 0},
+	}
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			mod := ""
+			if i := strings.Index(tc.path, "?"); i >= 0 {
+				mod = tc.path[i+1:]
+			}
+			page, err := h.Query(db, mod, tc.data)
+			require.NoError(t, err)
+			page, n, ok := SplitTotal(page)
+			assert.Equal(t, tc.total >= 0, ok)
+			if ok {
+				assert.Equal(t, tc.total, n)
+			}
+			var keys [][]byte
+			for _, m := range page {
+				keys = append(keys, m.Key)
+				// the value is the object
+				assert.Equal(t, db.Get(bucket.DBKey(m.Key[len(m.Key)-8:])), m.Value)
+			}
+			assert.Equal(t, tc.keys, keys)
+		})
+	}
+
+	// bad input
+	_, err := h.Query(db, "", nil)
+	assert.True(t, IsInvalidCursorErr(err))
+	_, err = h.Query(db, "", index.IndexKey(even))
+	assert.True(t, IsInvalidCursorErr(err))
+	_, err = h.Query(db, "limit=a", even)
+	assert.True(t, IsInvalidPageRequestErr(err))
+}
@@ -0,0 +1,109 @@
+package query
+
+import (
+	"encoding/binary"
+	"net/url"
+	"strconv"
+
+	"github.com/confio/weave"
+)
+
+// TotalKey is the key of the extra model appended to a page
+// when the total is requested. Its value is the number of
+// items in the bucket, as 8 byte big endian.
+const TotalKey = "_total"
+
+const (
+	paramLimit   = "limit"
+	paramReverse = "reverse"
+	paramTotal   = "include_total"
+)
+
+// PageRequest is the envelope shared by all paged queries.
+//
+// Limit, Reverse and IncludeTotal are passed in the path, as
+// "/escrows/page?limit=10&reverse=true&include_total=true",
+// the cursor is the query data.
+type PageRequest struct {
+	// Limit is the most items to return, 0 for the default
+	Limit int
+	// Cursor is the db key of the last item of the previous page
+	Cursor []byte
+	// Reverse returns the items in descending key order
+	Reverse bool
+	// IncludeTotal appends the size of the bucket, see TotalKey.
+	// Only queries that keep a Counter accept it.
+	IncludeTotal bool
+}
+
+// ParsePageRequest reads the request from the modifier and
+// data of a query
+func ParsePageRequest(mod string, data []byte) (PageRequest, error) {
+	req := PageRequest{Cursor: data}
+	params, err := url.ParseQuery(mod)
+	if err != nil {
+		return req, ErrInvalidPageRequest(mod)
+	}
+	for name, vals := range params {
+		if len(vals) != 1 {
+			return req, ErrInvalidPageRequest(name)
+		}
+		switch name {
+		case paramLimit:
+			req.Limit, err = strconv.Atoi(vals[0])
+			if err == nil && req.Limit < 0 {
+				err = ErrInvalidPageRequest(name)
+			}
+		case paramReverse:
+			req.Reverse, err = strconv.ParseBool(vals[0])
+		case paramTotal:
+			req.IncludeTotal, err = strconv.ParseBool(vals[0])
+		default:
+			err = ErrInvalidPageRequest(name)
+		}
+		if err != nil {
+			return req, ErrInvalidPageRequest(name)
+		}
+	}
+	return req, nil
+}
+
+// Path returns the path to query this page of the bucket
+// registered under path
+func (r PageRequest) Path(path string) string {
+	params := url.Values{}
+	if r.Limit > 0 {
+		params.Set(paramLimit, strconv.Itoa(r.Limit))
+	}
+	if r.Reverse {
+		params.Set(paramReverse, "true")
+	}
+	if r.IncludeTotal {
+		params.Set(paramTotal, "true")
+	}
+	path += PagePath
+	if len(params) == 0 {
+		return path
+	}
+	return path + "?" + params.Encode()
+}
+
+// SplitTotal removes the total from a page, if it was included
+func SplitTotal(page []weave.Model) ([]weave.Model, int64, bool) {
+	if len(page) == 0 {
+		return page, 0, false
+	}
+	last := page[len(page)-1]
+	if string(last.Key) != TotalKey || len(last.Value) != 8 {
+		return page, 0, false
+	}
+	total := int64(binary.BigEndian.Uint64(last.Value))
+	return page[:len(page)-1], total, true
+}
+
+// WithTotal appends the total to a page, see TotalKey
+func WithTotal(page []weave.Model, total int64) []weave.Model {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(total))
+	return append(page, weave.Model{Key: []byte(TotalKey), Value: bz})
+}
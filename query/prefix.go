@@ -0,0 +1,58 @@
+package query
+
+import (
+	"bytes"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+)
+
+// PrefixQuery pages over the keys of a bucket that start with
+// a key prefix of fixed size, eg. the events of one escrow. The
+// data of the first page is that prefix, the following pages
+// take the db key of the last result as cursor.
+//
+// The writer counts the keys of every prefix with a Counter
+// of the db key of the prefix, for the total.
+type PrefixQuery struct {
+	bucket orm.Bucket
+	size   int
+	limit  int
+}
+
+var _ weave.QueryHandler = PrefixQuery{}
+
+// NewPrefixQuery pages over the keys with a prefix of size
+// bytes, at most limit (or DefaultPageSize) at once
+func NewPrefixQuery(bucket orm.Bucket, size, limit int) PrefixQuery {
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	return PrefixQuery{bucket: bucket, size: size, limit: limit}
+}
+
+// Query implements weave.QueryHandler
+func (q PrefixQuery) Query(db weave.ReadOnlyKVStore, mod string,
+	data []byte) ([]weave.Model, error) {
+
+	req, err := ParsePageRequest(mod, data)
+	if err != nil {
+		return nil, err
+	}
+	start := q.bucket.DBKey(nil)
+	var prefix []byte
+	switch {
+	case len(data) == q.size:
+		prefix = q.bucket.DBKey(data)
+		req.Cursor = nil
+	case len(data) > len(start)+q.size && bytes.HasPrefix(data, start):
+		prefix = data[:len(start)+q.size]
+	default:
+		return nil, ErrInvalidCursor(data)
+	}
+	res, err := Page(db, prefix, req, q.limit)
+	if err != nil || !req.IncludeTotal {
+		return res, err
+	}
+	return WithTotal(res, NewCounter(prefix).Count(db)), nil
+}
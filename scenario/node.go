@@ -0,0 +1,100 @@
+package scenario
+
+import (
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/app"
+)
+
+// Node is what a scenario runs against. Local runs the app in
+// memory, a client of a running node can implement it as well.
+type Node interface {
+	ChainID() string
+	// Height is the height of the last committed block
+	Height() int64
+	// Deliver runs the tx in a block of its own
+	Deliver(tx []byte) abci.ResponseDeliverTx
+	// Advance commits n empty blocks
+	Advance(n int64)
+	Query(path string, data []byte) abci.ResponseQuery
+}
+
+// Local is a node in memory, started from genesis
+type Local struct {
+	app     app.App
+	chainID string
+	height  int64
+	hash    []byte
+}
+
+var _ Node = (*Local)(nil)
+
+// NewLocal initializes the app with the genesis file on a
+// store in memory, without fees, and commits the first block
+func NewLocal(chainID string, genesis []byte) (*Local, error) {
+	myApp, err := app.Application(chainID, app.Stack(x.Coin{}, 0),
+		app.TxDecoder, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	myApp.WithInit(app.Initializer())
+	myApp.InitChainWithGenesis(abci.RequestInitChain{}, genesis)
+	// queries only see the genesis state once it is committed
+	l := &Local{app: myApp, chainID: chainID}
+	l.Advance(1)
+	return l, nil
+}
+
+// Close releases the store
+func (l *Local) Close() error {
+	return l.app.Close()
+}
+
+// ChainID fulfils Node
+func (l *Local) ChainID() string {
+	return l.chainID
+}
+
+// Height fulfils Node
+func (l *Local) Height() int64 {
+	return l.height
+}
+
+// Deliver fulfils Node
+func (l *Local) Deliver(tx []byte) abci.ResponseDeliverTx {
+	l.begin()
+	res := l.app.DeliverTx(tx)
+	l.commit()
+	return res
+}
+
+// Advance fulfils Node
+func (l *Local) Advance(n int64) {
+	for i := int64(0); i < n; i++ {
+		l.begin()
+		l.commit()
+	}
+}
+
+// AppHash is the hash of the last committed state, the same
+// on every run of a scenario
+func (l *Local) AppHash() []byte {
+	return l.hash
+}
+
+// Query fulfils Node
+func (l *Local) Query(path string, data []byte) abci.ResponseQuery {
+	return l.app.Query(abci.RequestQuery{Path: path, Data: data})
+}
+
+func (l *Local) begin() {
+	l.height++
+	l.app.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: l.height}})
+}
+
+func (l *Local) commit() {
+	l.app.EndBlock(abci.RequestEndBlock{Height: l.height})
+	l.hash = l.app.Commit().Data
+}
@@ -0,0 +1,298 @@
+package scenario
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/crypto"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/app"
+	"github.com/iov-one/bcp-demo/ordered"
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// DefaultChainID is the chain scenarios run on, unless set
+const DefaultChainID = "scenario"
+
+// Key returns the key of the account with the name
+func Key(name string) *crypto.PrivateKey {
+	return app.KeyFromMnemonic("scenario " + name)
+}
+
+// GenesisFile returns the genesis file of the scenario: its
+// accounts with their balances, a token for every ticker and the
+// other options as they are
+func (s *Scenario) GenesisFile(chainID string) ([]byte, error) {
+	var accts []namecoin.GenesisAccount
+	tickers := ordered.NewSet()
+	for _, name := range ordered.Keys(s.Accounts) {
+		coins, err := ParseCoins(s.Accounts[name])
+		if err != nil {
+			return nil, fmt.Errorf("account %s: %s", name, err)
+		}
+		if len(coins) == 0 {
+			// nothing to store, the key is enough
+			continue
+		}
+		for _, c := range coins {
+			tickers.Add(c.Ticker)
+		}
+		accts = append(accts, namecoin.GenesisAccount{
+			Address: Key(name).PublicKey().Address(),
+			Wallet:  &namecoin.Wallet{Coins: coins},
+		})
+	}
+	var tokens []namecoin.GenesisToken
+	for _, ticker := range tickers.Values() {
+		tokens = append(tokens, namecoin.GenesisToken{Ticker: ticker, Name: ticker, SigFigs: 9})
+	}
+	opts, err := namecoin.BuildGenesis(accts, tokens)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range ordered.Keys(s.Genesis) {
+		if _, ok := opts[key]; ok {
+			return nil, fmt.Errorf("genesis option %q is set by the accounts", key)
+		}
+		opts[key], err = json.Marshal(s.Genesis[key])
+		if err != nil {
+			return nil, fmt.Errorf("genesis option %q: %s", key, err)
+		}
+	}
+	state, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]json.RawMessage{
+		"chain_id":  json.RawMessage(fmt.Sprintf("%q", chainID)),
+		"app_state": state,
+	})
+}
+
+// Runner plays the steps of a scenario on a node
+type Runner struct {
+	node    Node
+	out     io.Writer
+	escrows map[string][]byte
+}
+
+// NewRunner returns a Runner that logs every step to out
+func NewRunner(node Node, out io.Writer) *Runner {
+	return &Runner{node: node, out: out, escrows: make(map[string][]byte)}
+}
+
+// Run plays all steps in order, it stops at the first step
+// that does not go as expected
+func (r *Runner) Run(s *Scenario) error {
+	for i, step := range s.Steps {
+		err := r.step(step)
+		if err != nil {
+			return fmt.Errorf("step %d (%s): %s", i+1, step.Kind(), err)
+		}
+		result := "ok"
+		if step.Fails {
+			result = "failed as expected"
+		}
+		fmt.Fprintf(r.out, "%d: %s %s, height %d\n", i+1, step.Kind(), result, r.node.Height())
+	}
+	return nil
+}
+
+func (r *Runner) step(step Step) error {
+	switch {
+	case step.Advance != 0:
+		r.node.Advance(step.Advance)
+		return nil
+	case step.ExpectBalances != nil:
+		return r.expectBalances(step.ExpectBalances)
+	}
+
+	signer, tx, err := r.buildTx(step)
+	if err != nil {
+		return err
+	}
+	data, err := r.deliver(signer, tx)
+	if step.Fails {
+		if err == nil {
+			return fmt.Errorf("expected to fail")
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if step.Create != nil {
+		r.escrows[step.Create.Name] = data
+	}
+	return nil
+}
+
+// buildTx returns the name of the signer and the tx of the step
+func (r *Runner) buildTx(step Step) (string, *app.Tx, error) {
+	switch {
+	case step.Send != nil:
+		s := step.Send
+		amount, err := ParseCoin(s.Amount)
+		if err != nil {
+			return "", nil, err
+		}
+		msg := &cash.SendMsg{
+			Src:    Key(s.From).PublicKey().Address(),
+			Dest:   Key(s.To).PublicKey().Address(),
+			Amount: amount,
+			Memo:   s.Memo,
+		}
+		return s.From, &app.Tx{Sum: &app.Tx_SendMsg{SendMsg: msg}}, nil
+	case step.Create != nil:
+		c := step.Create
+		if c.Name == "" {
+			return "", nil, fmt.Errorf("escrow without a name")
+		}
+		if _, ok := r.escrows[c.Name]; ok {
+			return "", nil, fmt.Errorf("escrow %s exists", c.Name)
+		}
+		amount, err := ParseCoin(c.Amount)
+		if err != nil {
+			return "", nil, err
+		}
+		// delivered at the next height
+		msg := escrow.NewCreateMsg(
+			Key(c.Sender).PublicKey().Permission(),
+			Key(c.Recipient).PublicKey().Permission(),
+			Key(c.Arbiter).PublicKey().Permission(),
+			x.Coins{amount}, r.node.Height()+1+c.TimeoutIn, c.Memo)
+		return c.Sender, &app.Tx{Sum: &app.Tx_CreateEscrowMsg{CreateEscrowMsg: msg}}, nil
+	case step.Release != nil:
+		id, amount, err := r.settle(step.Release)
+		if err != nil {
+			return "", nil, err
+		}
+		msg := &escrow.ReleaseEscrowMsg{EscrowId: id, Amount: amount}
+		return step.Release.By, &app.Tx{Sum: &app.Tx_ReleaseEscrowMsg{ReleaseEscrowMsg: msg}}, nil
+	case step.Return != nil:
+		id, amount, err := r.settle(step.Return)
+		if err != nil {
+			return "", nil, err
+		}
+		if amount != nil {
+			return "", nil, fmt.Errorf("escrows are returned in full")
+		}
+		msg := &escrow.ReturnEscrowMsg{EscrowId: id}
+		return step.Return.By, &app.Tx{Sum: &app.Tx_ReturnEscrowMsg{ReturnEscrowMsg: msg}}, nil
+	}
+	return "", nil, fmt.Errorf("no tx")
+}
+
+// settle returns the id of the escrow and the amount, if any
+func (r *Runner) settle(s *Settle) ([]byte, x.Coins, error) {
+	id, ok := r.escrows[s.Escrow]
+	if !ok {
+		return nil, nil, fmt.Errorf("no escrow %s", s.Escrow)
+	}
+	if s.Amount == "" {
+		return id, nil, nil
+	}
+	amount, err := ParseCoin(s.Amount)
+	if err != nil {
+		return nil, nil, err
+	}
+	return id, x.Coins{amount}, nil
+}
+
+// deliver signs the tx as the named account and returns the
+// data of the result
+func (r *Runner) deliver(signer string, tx *app.Tx) ([]byte, error) {
+	return NewWallet(r.node, signer).Deliver(tx)
+}
+
+func (r *Runner) expectBalances(expected map[string][]string) error {
+	var failed []string
+	for _, name := range ordered.Keys(expected) {
+		want, err := ParseCoins(expected[name])
+		if err != nil {
+			return fmt.Errorf("%s: %s", name, err)
+		}
+		got, err := Balance(r.node, r.address(name))
+		if err != nil {
+			return err
+		}
+		if !got.Equals(want) {
+			failed = append(failed, fmt.Sprintf("%s has %s, not %s",
+				name, FormatCoins(got), FormatCoins(want)))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// address returns the address of the escrow or account with
+// the name
+func (r *Runner) address(name string) weave.Address {
+	if id, ok := r.escrows[name]; ok {
+		return escrow.NewCondition(id).Address()
+	}
+	return Key(name).PublicKey().Address()
+}
+
+// FormatCoins lists the coins as in scenario files
+func FormatCoins(coins x.Coins) string {
+	if len(coins) == 0 {
+		return "nothing"
+	}
+	list := make([]string, len(coins))
+	for i, c := range coins {
+		list[i] = namecoin.FormatAmount(*c) + " " + c.Ticker
+	}
+	return strings.Join(list, ", ")
+}
+
+// RunCmd runs the scenario files given as arguments, each on a
+// new node in memory
+func RunCmd(out io.Writer, args []string) error {
+	flags := flag.NewFlagSet("scenario", flag.ExitOnError)
+	chainID := flags.String("chain-id", DefaultChainID, "chain to sign the txs for")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		return fmt.Errorf("usage: scenario [-chain-id id] file.yaml...")
+	}
+	for _, path := range flags.Args() {
+		s, err := Load(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s: %s\n", path, s.Name)
+		err = RunLocal(s, *chainID, out)
+		if err != nil {
+			return fmt.Errorf("%s: %s", path, err)
+		}
+	}
+	return nil
+}
+
+// RunLocal plays the scenario on a new node in memory
+func RunLocal(s *Scenario, chainID string, out io.Writer) error {
+	genesis, err := s.GenesisFile(chainID)
+	if err != nil {
+		return err
+	}
+	node, err := NewLocal(chainID, genesis)
+	if err != nil {
+		return err
+	}
+	defer node.Close()
+	err = NewRunner(node, out).Run(s)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "app hash %X\n", node.AppHash())
+	return nil
+}
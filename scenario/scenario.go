@@ -0,0 +1,187 @@
+/*
+Package scenario runs scripted sequences of txs against a node, for
+acceptance tests and demos that must play out the same every time.
+
+A scenario is a YAML file: the accounts with their balances at
+genesis, any other genesis options, and the steps to run in order.
+
+	name: release half
+	accounts:
+	  alice: [100 IOV]
+	  bob: []
+	  carol: []
+	steps:
+	  - send: {from: alice, to: bob, amount: 10 IOV}
+	  - create_escrow: {name: deal, sender: alice, arbiter: carol,
+	      recipient: bob, amount: 50 IOV, timeout_in: 10}
+	  - advance: 3
+	  - release_escrow: {escrow: deal, by: carol, amount: 20 IOV}
+	  - expect_balances: {alice: [40 IOV], bob: [30 IOV], deal: [30 IOV]}
+	  - advance: 10
+	  - return_escrow: {escrow: deal, by: bob}
+	  - expect_balances: {alice: [70 IOV]}
+
+The key of every account is derived from its name, so a scenario
+signs the same txs on every run. Every tx step runs in a block of
+its own and must succeed, unless it sets fails: true. An escrow is
+referred to by its name, also as the account holding its coins.
+*/
+package scenario
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// Scenario is the content of a scenario file
+type Scenario struct {
+	Name string `yaml:"name"`
+	// Accounts are the balances at genesis by account name
+	Accounts map[string][]string `yaml:"accounts"`
+	// Genesis holds other app_state options, eg. "features"
+	Genesis map[string]interface{} `yaml:"genesis"`
+	Steps   []Step                 `yaml:"steps"`
+}
+
+// Step is one action or check, only one of its fields is set
+// besides Fails
+type Step struct {
+	Send    *Send   `yaml:"send"`
+	Create  *Create `yaml:"create_escrow"`
+	Release *Settle `yaml:"release_escrow"`
+	Return  *Settle `yaml:"return_escrow"`
+	// Advance commits that many empty blocks
+	Advance int64 `yaml:"advance"`
+	// ExpectBalances are the balances of accounts or escrows,
+	// nothing for an empty one
+	ExpectBalances map[string][]string `yaml:"expect_balances"`
+	// Fails expects the tx of the step to fail
+	Fails bool `yaml:"fails"`
+}
+
+// Send pays from one account to another
+type Send struct {
+	From   string `yaml:"from"`
+	To     string `yaml:"to"`
+	Amount string `yaml:"amount"`
+	Memo   string `yaml:"memo"`
+}
+
+// Create makes an escrow, signed by the sender
+type Create struct {
+	// Name is how later steps refer to the escrow
+	Name      string `yaml:"name"`
+	Sender    string `yaml:"sender"`
+	Arbiter   string `yaml:"arbiter"`
+	Recipient string `yaml:"recipient"`
+	Amount    string `yaml:"amount"`
+	// TimeoutIn is the number of blocks from now it expires in
+	TimeoutIn int64  `yaml:"timeout_in"`
+	Memo      string `yaml:"memo"`
+}
+
+// Settle releases or returns an escrow, all of it unless an
+// amount is set
+type Settle struct {
+	Escrow string `yaml:"escrow"`
+	By     string `yaml:"by"`
+	Amount string `yaml:"amount"`
+}
+
+// Load reads and parses a scenario file
+func Load(path string) (*Scenario, error) {
+	bz, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(bz)
+}
+
+// Parse reads a scenario from YAML, rejecting unknown fields
+// and steps that do not set exactly one action
+func Parse(bz []byte) (*Scenario, error) {
+	var s Scenario
+	dec := yaml.NewDecoder(strings.NewReader(string(bz)))
+	dec.KnownFields(true)
+	err := dec.Decode(&s)
+	if err != nil {
+		return nil, err
+	}
+	for i, step := range s.Steps {
+		if err := step.validate(); err != nil {
+			return nil, fmt.Errorf("step %d: %s", i+1, err)
+		}
+	}
+	return &s, nil
+}
+
+// Kind names the action of the step
+func (s Step) Kind() string {
+	var kinds []string
+	if s.Send != nil {
+		kinds = append(kinds, "send")
+	}
+	if s.Create != nil {
+		kinds = append(kinds, "create_escrow")
+	}
+	if s.Release != nil {
+		kinds = append(kinds, "release_escrow")
+	}
+	if s.Return != nil {
+		kinds = append(kinds, "return_escrow")
+	}
+	if s.Advance != 0 {
+		kinds = append(kinds, "advance")
+	}
+	if s.ExpectBalances != nil {
+		kinds = append(kinds, "expect_balances")
+	}
+	return strings.Join(kinds, ",")
+}
+
+func (s Step) validate() error {
+	kind := s.Kind()
+	switch {
+	case kind == "":
+		return fmt.Errorf("no action")
+	case strings.Contains(kind, ","):
+		return fmt.Errorf("more than one action: %s", kind)
+	case s.Advance < 0:
+		return fmt.Errorf("cannot advance %d blocks", s.Advance)
+	case s.Fails && (s.Advance != 0 || s.ExpectBalances != nil):
+		return fmt.Errorf("only txs can fail")
+	}
+	return nil
+}
+
+// ParseCoin reads an amount like "12.5 IOV"
+func ParseCoin(s string) (*x.Coin, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("amount %q is not like \"12.5 IOV\"", s)
+	}
+	return namecoin.ParseAmount(fields[0], fields[1])
+}
+
+// ParseCoins reads a list of amounts, as x.Coins normalizes them
+func ParseCoins(list []string) (x.Coins, error) {
+	var res x.Coins
+	for _, s := range list {
+		c, err := ParseCoin(s)
+		if err != nil {
+			return nil, err
+		}
+		res, err = res.Add(*c)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,85 @@
+package scenario
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScenarios(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.yaml")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, path := range files {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			s, err := Load(path)
+			require.NoError(t, err)
+			var out bytes.Buffer
+			err = RunLocal(s, DefaultChainID, &out)
+			assert.NoError(t, err, out.String())
+		})
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	s, err := Load("testdata/release.yaml")
+	require.NoError(t, err)
+	genesis, err := s.GenesisFile(DefaultChainID)
+	require.NoError(t, err)
+
+	// the same txs lead to the same state
+	var hashes [2][]byte
+	for i := range hashes {
+		node, err := NewLocal(DefaultChainID, genesis)
+		require.NoError(t, err)
+		require.NoError(t, NewRunner(node, &bytes.Buffer{}).Run(s))
+		hashes[i] = node.AppHash()
+		node.Close()
+	}
+	assert.Equal(t, hashes[0], hashes[1])
+}
+
+func TestFailures(t *testing.T) {
+	cases := map[string]struct {
+		yaml string
+		err  string
+	}{
+		"unknown field": {`steps: [{advance: 1, wait: 2}]`, "field wait not found"},
+		"two actions":   {`steps: [{advance: 1, expect_balances: {}}]`, "more than one action"},
+		"no action":     {`steps: [{fails: true}]`, "no action"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.yaml))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+
+	// a balance that does not match names the account
+	s, err := Parse([]byte(`
+accounts: {alice: [3 IOV]}
+steps:
+  - expect_balances: {alice: [2 IOV], bob: []}
+  - advance: 1
+`))
+	require.NoError(t, err)
+	err = RunLocal(s, DefaultChainID, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Equal(t, "step 1 (expect_balances): alice has 3 IOV, not 2 IOV", err.Error())
+
+	// so does a tx that was meant to fail
+	s, err = Parse([]byte(`
+accounts: {alice: [3 IOV]}
+steps:
+  - send: {from: alice, to: bob, amount: 1 IOV}
+    fails: true
+`))
+	require.NoError(t, err)
+	err = RunLocal(s, DefaultChainID, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected to fail")
+}
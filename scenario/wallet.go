@@ -0,0 +1,78 @@
+package scenario
+
+import (
+	"fmt"
+
+	"github.com/confio/weave"
+	weaveapp "github.com/confio/weave/app"
+	"github.com/confio/weave/crypto"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/sigs"
+
+	"github.com/iov-one/bcp-demo/app"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// Wallet signs txs with the key of the named account and sends
+// them to the node, as a client would
+type Wallet struct {
+	Name string
+	key  *crypto.PrivateKey
+	node Node
+}
+
+// NewWallet returns the wallet of the account on the node,
+// with the key derived from its name
+func NewWallet(node Node, name string) *Wallet {
+	return &Wallet{Name: name, key: Key(name), node: node}
+}
+
+// Address is where the wallet holds its coins
+func (w *Wallet) Address() weave.Address {
+	return w.key.PublicKey().Address()
+}
+
+// Permission is what the wallet signs as
+func (w *Wallet) Permission() weave.Permission {
+	return w.key.PublicKey().Permission()
+}
+
+// Deliver signs the tx with the next sequence of the account
+// and returns the data of the result
+func (w *Wallet) Deliver(tx *app.Tx) ([]byte, error) {
+	var user sigs.UserData
+	err := query(w.node, "/auth", w.Address(), &user)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := sigs.SignTx(w.key, tx, w.node.ChainID(), user.Sequence)
+	if err != nil {
+		return nil, err
+	}
+	tx.Signatures = []*sigs.StdSignature{sig}
+	bz, err := tx.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	res := w.node.Deliver(bz)
+	if res.Code != 0 {
+		return nil, fmt.Errorf("code %d: %s", res.Code, res.Log)
+	}
+	return res.Data, nil
+}
+
+// Balance returns the coins held by the address on the node
+func Balance(node Node, addr weave.Address) (x.Coins, error) {
+	var wallet namecoin.Wallet
+	err := query(node, "/wallets", addr, &wallet)
+	return x.Coins(wallet.Coins), err
+}
+
+// query decodes the one result, if any, into o
+func query(node Node, path string, data []byte, o weave.Persistent) error {
+	res := node.Query(path, data)
+	if res.Code != 0 {
+		return fmt.Errorf("query %s: %s", path, res.Log)
+	}
+	return weaveapp.UnmarshalOneResult(res.Value, o)
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+
+	dbm "github.com/tendermint/tmlibs/db"
+)
+
+// DefaultBackend is used if no backend is configured.
+// It is pure go and needs no cgo.
+const DefaultBackend = "goleveldb"
+
+// Backend opens (or creates) the named db in dir
+type Backend func(name, dir string) (dbm.DB, error)
+
+// backends are all persistent, an in-memory db would lose the
+// state on restart, tests use MockCommitStore instead
+var backends = map[string]Backend{
+	"goleveldb": func(name, dir string) (dbm.DB, error) {
+		return dbm.NewGoLevelDB(name, dir)
+	},
+}
+
+// RegisterBackend adds a new db backend, that can be selected
+// in the config. Backends that need extra libraries register
+// themselves in a file with a build tag, so the default build
+// stays pure go and small: cleveldb (-tags gcc), rocksdb
+// (-tags rocksdb), badgerdb (-tags badgerdb) and boltdb
+// (-tags boltdb).
+func RegisterBackend(name string, backend Backend) {
+	backends[name] = backend
+}
+
+// Backends returns the names of all registered backends, sorted
+func Backends() []string {
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// HasBackend returns true if we can open a db with this backend
+func HasBackend(name string) bool {
+	_, ok := backends[name]
+	return ok
+}
+
+// OpenDB opens the named database in dir with the given backend.
+// An empty backend means DefaultBackend.
+func OpenDB(backend, dir, name string) (dbm.DB, error) {
+	if backend == "" {
+		backend = DefaultBackend
+	}
+	open, ok := backends[backend]
+	if !ok {
+		return nil, fmt.Errorf("unknown db backend %q, available: %v", backend, Backends())
+	}
+	return open(name, dir)
+}
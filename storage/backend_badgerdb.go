@@ -0,0 +1,221 @@
+//go:build badgerdb
+// +build badgerdb
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dgraph-io/badger"
+	dbm "github.com/tendermint/tmlibs/db"
+)
+
+// badger is pure go but adds a dependency, build with -tags badgerdb
+func init() {
+	RegisterBackend("badgerdb", func(name, dir string) (dbm.DB, error) {
+		return newBadgerDB(name, dir)
+	})
+}
+
+// badgerDB keeps the db in a badger dir. Like goleveldb it
+// panics on errors, badger rejects empty keys.
+type badgerDB struct {
+	db *badger.DB
+}
+
+var _ dbm.DB = (*badgerDB)(nil)
+
+func newBadgerDB(name, dir string) (*badgerDB, error) {
+	path := filepath.Join(dir, name+".db")
+	err := os.MkdirAll(path, 0755)
+	if err != nil {
+		return nil, err
+	}
+	opts := badger.DefaultOptions
+	opts.Dir = path
+	opts.ValueDir = path
+	opts.SyncWrites = true
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &badgerDB{db: db}, nil
+}
+
+// Get fulfils dbm.DB
+func (b *badgerDB) Get(key []byte) []byte {
+	var res []byte
+	err := b.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(key)
+		if err == badger.ErrKeyNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		v, err := item.Value()
+		if err != nil {
+			return err
+		}
+		res = cp(v)
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// Has fulfils dbm.DB
+func (b *badgerDB) Has(key []byte) bool {
+	return b.Get(key) != nil
+}
+
+// Set fulfils dbm.DB, every write is synced
+func (b *badgerDB) Set(key, value []byte) {
+	err := b.db.Update(func(txn *badger.Txn) error {
+		return txn.Set(key, value)
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// SetSync fulfils dbm.DB
+func (b *badgerDB) SetSync(key, value []byte) {
+	b.Set(key, value)
+}
+
+// Delete fulfils dbm.DB
+func (b *badgerDB) Delete(key []byte) {
+	err := b.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete(key)
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DeleteSync fulfils dbm.DB
+func (b *badgerDB) DeleteSync(key []byte) {
+	b.Delete(key)
+}
+
+// Iterator fulfils dbm.DB
+func (b *badgerDB) Iterator(start, end []byte) dbm.Iterator {
+	return newScanIterator(b.scan, start, end)
+}
+
+// ReverseIterator fulfils dbm.DB, like goleveldb nothing
+// iterates in reverse
+func (b *badgerDB) ReverseIterator(start, end []byte) dbm.Iterator {
+	panic("not implemented yet")
+}
+
+// scan is the scanFunc of the db
+func (b *badgerDB) scan(start, end []byte, limit int) (keys, values [][]byte) {
+	err := b.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+		for it.Seek(start); it.Valid() && len(keys) < limit; it.Next() {
+			item := it.Item()
+			if end != nil && bytes.Compare(item.Key(), end) >= 0 {
+				break
+			}
+			v, err := item.Value()
+			if err != nil {
+				return err
+			}
+			keys = append(keys, item.KeyCopy(nil))
+			values = append(values, cp(v))
+		}
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return keys, values
+}
+
+// Close fulfils dbm.DB
+func (b *badgerDB) Close() {
+	b.db.Close()
+}
+
+// NewBatch fulfils dbm.DB
+func (b *badgerDB) NewBatch() dbm.Batch {
+	return &badgerBatch{db: b.db}
+}
+
+// Print fulfils dbm.DB
+func (b *badgerDB) Print() {
+	itr := b.Iterator(nil, nil)
+	defer itr.Close()
+	for ; itr.Valid(); itr.Next() {
+		fmt.Printf("[%X]:\t[%X]\n", itr.Key(), itr.Value())
+	}
+}
+
+// Stats fulfils dbm.DB
+func (b *badgerDB) Stats() map[string]string {
+	lsm, vlog := b.db.Size()
+	return map[string]string{
+		"badger.lsm_size":  fmt.Sprint(lsm),
+		"badger.vlog_size": fmt.Sprint(vlog),
+	}
+}
+
+// badgerBatch collects the writes until Write
+type badgerBatch struct {
+	db  *badger.DB
+	ops []badgerOp
+}
+
+type badgerOp struct {
+	key, value []byte
+	delete     bool
+}
+
+// Set fulfils dbm.Batch
+func (b *badgerBatch) Set(key, value []byte) {
+	b.ops = append(b.ops, badgerOp{key: key, value: value})
+}
+
+// Delete fulfils dbm.Batch
+func (b *badgerBatch) Delete(key []byte) {
+	b.ops = append(b.ops, badgerOp{key: key, delete: true})
+}
+
+// Write fulfils dbm.Batch. A batch too big for one badger txn
+// is split, a crash in between leaves a partial commit that
+// Recover repairs on the next start.
+func (b *badgerBatch) Write() {
+	txn := b.db.NewTransaction(true)
+	defer func() { txn.Discard() }()
+	for _, op := range b.ops {
+		err := op.apply(txn)
+		if err == badger.ErrTxnTooBig {
+			if err = txn.Commit(nil); err != nil {
+				panic(err)
+			}
+			txn = b.db.NewTransaction(true)
+			err = op.apply(txn)
+		}
+		if err != nil {
+			panic(err)
+		}
+	}
+	if err := txn.Commit(nil); err != nil {
+		panic(err)
+	}
+}
+
+func (op badgerOp) apply(txn *badger.Txn) error {
+	if op.delete {
+		return txn.Delete(op.key)
+	}
+	return txn.Set(op.key, op.value)
+}
@@ -0,0 +1,201 @@
+//go:build boltdb
+// +build boltdb
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "github.com/coreos/bbolt"
+	dbm "github.com/tendermint/tmlibs/db"
+)
+
+// boltdb is pure go but adds a dependency, build with -tags boltdb
+func init() {
+	RegisterBackend("boltdb", func(name, dir string) (dbm.DB, error) {
+		return newBoltDB(name, dir)
+	})
+}
+
+// boltBucket holds all keys of the db
+var boltBucket = []byte("bov")
+
+// boltDB keeps the db in one bolt file. Like goleveldb it panics
+// on errors, bolt rejects empty keys.
+type boltDB struct {
+	db *bolt.DB
+}
+
+var _ dbm.DB = (*boltDB)(nil)
+
+func newBoltDB(name, dir string) (*boltDB, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	// another process holding the file fails the open
+	opts := &bolt.Options{Timeout: time.Second}
+	db, err := bolt.Open(filepath.Join(dir, name+".db"), 0600, opts)
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltDB{db: db}, nil
+}
+
+// Get fulfils dbm.DB
+func (b *boltDB) Get(key []byte) []byte {
+	var res []byte
+	err := b.db.View(func(tx *bolt.Tx) error {
+		// only valid during the tx
+		if v := tx.Bucket(boltBucket).Get(key); v != nil {
+			res = cp(v)
+		}
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// Has fulfils dbm.DB
+func (b *boltDB) Has(key []byte) bool {
+	return b.Get(key) != nil
+}
+
+// Set fulfils dbm.DB, every write is synced
+func (b *boltDB) Set(key, value []byte) {
+	b.write(func(bucket *bolt.Bucket) error {
+		return bucket.Put(key, value)
+	})
+}
+
+// SetSync fulfils dbm.DB
+func (b *boltDB) SetSync(key, value []byte) {
+	b.Set(key, value)
+}
+
+// Delete fulfils dbm.DB
+func (b *boltDB) Delete(key []byte) {
+	b.write(func(bucket *bolt.Bucket) error {
+		return bucket.Delete(key)
+	})
+}
+
+// DeleteSync fulfils dbm.DB
+func (b *boltDB) DeleteSync(key []byte) {
+	b.Delete(key)
+}
+
+// write runs fn in a tx and panics if it fails
+func (b *boltDB) write(fn func(*bolt.Bucket) error) {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		return fn(tx.Bucket(boltBucket))
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Iterator fulfils dbm.DB
+func (b *boltDB) Iterator(start, end []byte) dbm.Iterator {
+	return newScanIterator(b.scan, start, end)
+}
+
+// ReverseIterator fulfils dbm.DB, like goleveldb nothing
+// iterates in reverse
+func (b *boltDB) ReverseIterator(start, end []byte) dbm.Iterator {
+	panic("not implemented yet")
+}
+
+// scan is the scanFunc of the db
+func (b *boltDB) scan(start, end []byte, limit int) (keys, values [][]byte) {
+	err := b.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltBucket).Cursor()
+		for k, v := c.Seek(start); k != nil && len(keys) < limit; k, v = c.Next() {
+			if end != nil && bytes.Compare(k, end) >= 0 {
+				break
+			}
+			keys = append(keys, cp(k))
+			values = append(values, cp(v))
+		}
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	return keys, values
+}
+
+// Close fulfils dbm.DB
+func (b *boltDB) Close() {
+	b.db.Close()
+}
+
+// NewBatch fulfils dbm.DB, the batch is written in one tx
+func (b *boltDB) NewBatch() dbm.Batch {
+	return &boltBatch{db: b}
+}
+
+// Print fulfils dbm.DB
+func (b *boltDB) Print() {
+	itr := b.Iterator(nil, nil)
+	defer itr.Close()
+	for ; itr.Valid(); itr.Next() {
+		fmt.Printf("[%X]:\t[%X]\n", itr.Key(), itr.Value())
+	}
+}
+
+// Stats fulfils dbm.DB
+func (b *boltDB) Stats() map[string]string {
+	stats := b.db.Stats()
+	return map[string]string{
+		"bolt.tx_count":      fmt.Sprint(stats.TxN),
+		"bolt.open_tx_count": fmt.Sprint(stats.OpenTxN),
+		"bolt.free_pages":    fmt.Sprint(stats.FreePageN),
+	}
+}
+
+// boltBatch collects the writes until Write
+type boltBatch struct {
+	db  *boltDB
+	ops []func(*bolt.Bucket) error
+}
+
+// Set fulfils dbm.Batch
+func (b *boltBatch) Set(key, value []byte) {
+	b.ops = append(b.ops, func(bucket *bolt.Bucket) error {
+		return bucket.Put(key, value)
+	})
+}
+
+// Delete fulfils dbm.Batch
+func (b *boltBatch) Delete(key []byte) {
+	b.ops = append(b.ops, func(bucket *bolt.Bucket) error {
+		return bucket.Delete(key)
+	})
+}
+
+// Write fulfils dbm.Batch, all writes apply or none
+func (b *boltBatch) Write() {
+	b.db.write(func(bucket *bolt.Bucket) error {
+		for _, op := range b.ops {
+			if err := op(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,15 @@
+//go:build gcc
+// +build gcc
+
+package storage
+
+import (
+	dbm "github.com/tendermint/tmlibs/db"
+)
+
+// cleveldb needs the leveldb c library, build with -tags gcc
+func init() {
+	RegisterBackend("cleveldb", func(name, dir string) (dbm.DB, error) {
+		return dbm.NewCLevelDB(name, dir)
+	})
+}
@@ -0,0 +1,170 @@
+//go:build rocksdb
+// +build rocksdb
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+
+	"github.com/tecbot/gorocksdb"
+	dbm "github.com/tendermint/tmlibs/db"
+)
+
+// rocksdb needs the rocksdb c library, build with -tags rocksdb
+func init() {
+	RegisterBackend("rocksdb", func(name, dir string) (dbm.DB, error) {
+		return newRocksDB(name, dir)
+	})
+}
+
+// rocksDB keeps the db in a rocksdb dir. Like goleveldb it
+// panics on errors.
+type rocksDB struct {
+	db     *gorocksdb.DB
+	ro     *gorocksdb.ReadOptions
+	wo     *gorocksdb.WriteOptions
+	woSync *gorocksdb.WriteOptions
+}
+
+var _ dbm.DB = (*rocksDB)(nil)
+
+func newRocksDB(name, dir string) (*rocksDB, error) {
+	opts := gorocksdb.NewDefaultOptions()
+	opts.SetCreateIfMissing(true)
+	db, err := gorocksdb.OpenDb(opts, filepath.Join(dir, name+".db"))
+	if err != nil {
+		return nil, err
+	}
+	woSync := gorocksdb.NewDefaultWriteOptions()
+	woSync.SetSync(true)
+	return &rocksDB{
+		db:     db,
+		ro:     gorocksdb.NewDefaultReadOptions(),
+		wo:     gorocksdb.NewDefaultWriteOptions(),
+		woSync: woSync,
+	}, nil
+}
+
+// Get fulfils dbm.DB
+func (r *rocksDB) Get(key []byte) []byte {
+	res, err := r.db.GetBytes(r.ro, key)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// Has fulfils dbm.DB
+func (r *rocksDB) Has(key []byte) bool {
+	return r.Get(key) != nil
+}
+
+// Set fulfils dbm.DB
+func (r *rocksDB) Set(key, value []byte) {
+	r.check(r.db.Put(r.wo, key, value))
+}
+
+// SetSync fulfils dbm.DB
+func (r *rocksDB) SetSync(key, value []byte) {
+	r.check(r.db.Put(r.woSync, key, value))
+}
+
+// Delete fulfils dbm.DB
+func (r *rocksDB) Delete(key []byte) {
+	r.check(r.db.Delete(r.wo, key))
+}
+
+// DeleteSync fulfils dbm.DB
+func (r *rocksDB) DeleteSync(key []byte) {
+	r.check(r.db.Delete(r.woSync, key))
+}
+
+func (r *rocksDB) check(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Iterator fulfils dbm.DB
+func (r *rocksDB) Iterator(start, end []byte) dbm.Iterator {
+	return newScanIterator(r.scan, start, end)
+}
+
+// ReverseIterator fulfils dbm.DB, like goleveldb nothing
+// iterates in reverse
+func (r *rocksDB) ReverseIterator(start, end []byte) dbm.Iterator {
+	panic("not implemented yet")
+}
+
+// scan is the scanFunc of the db
+func (r *rocksDB) scan(start, end []byte, limit int) (keys, values [][]byte) {
+	it := r.db.NewIterator(r.ro)
+	defer it.Close()
+	for it.Seek(start); it.Valid() && len(keys) < limit; it.Next() {
+		k := it.Key()
+		key := cp(k.Data())
+		k.Free()
+		if end != nil && bytes.Compare(key, end) >= 0 {
+			break
+		}
+		v := it.Value()
+		keys = append(keys, key)
+		values = append(values, cp(v.Data()))
+		v.Free()
+	}
+	r.check(it.Err())
+	return keys, values
+}
+
+// Close fulfils dbm.DB
+func (r *rocksDB) Close() {
+	r.db.Close()
+	r.ro.Destroy()
+	r.wo.Destroy()
+	r.woSync.Destroy()
+}
+
+// NewBatch fulfils dbm.DB
+func (r *rocksDB) NewBatch() dbm.Batch {
+	return &rocksBatch{db: r, batch: gorocksdb.NewWriteBatch()}
+}
+
+// Print fulfils dbm.DB
+func (r *rocksDB) Print() {
+	itr := r.Iterator(nil, nil)
+	defer itr.Close()
+	for ; itr.Valid(); itr.Next() {
+		fmt.Printf("[%X]:\t[%X]\n", itr.Key(), itr.Value())
+	}
+}
+
+// Stats fulfils dbm.DB
+func (r *rocksDB) Stats() map[string]string {
+	return map[string]string{
+		"rocksdb.stats": r.db.GetProperty("rocksdb.stats"),
+	}
+}
+
+// rocksBatch is written atomically
+type rocksBatch struct {
+	db    *rocksDB
+	batch *gorocksdb.WriteBatch
+}
+
+// Set fulfils dbm.Batch
+func (b *rocksBatch) Set(key, value []byte) {
+	b.batch.Put(key, value)
+}
+
+// Delete fulfils dbm.Batch
+func (b *rocksBatch) Delete(key []byte) {
+	b.batch.Delete(key)
+}
+
+// Write fulfils dbm.Batch, the batch can't be used after
+func (b *rocksBatch) Write() {
+	defer b.batch.Destroy()
+	b.db.check(b.db.db.Write(b.db.wo, b.batch))
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave/store"
+)
+
+// TestBackendConformance runs the same operations against
+// every registered backend and expects the same app hash
+func TestBackendConformance(t *testing.T) {
+	var expected []store.CommitID
+
+	for _, backend := range Backends() {
+		t.Run(backend, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "bov-backend-")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			commit, err := NewCommitStore(backend, dir, "conform")
+			require.NoError(t, err)
+
+			var ids []store.CommitID
+			for i := 0; i < 3; i++ {
+				kv := commit.Adapter()
+				for j := 0; j < 10; j++ {
+					kv.Set([]byte(fmt.Sprintf("%d-%d", i, j)), []byte("value"))
+				}
+				kv.Delete([]byte(fmt.Sprintf("%d-%d", i, 5)))
+				ids = append(ids, commit.Commit())
+			}
+
+			// iterate over a range
+			itr := commit.Adapter().Iterator([]byte("1-"), []byte("2-"))
+			count := 0
+			for ; itr.Valid(); itr.Next() {
+				count++
+			}
+			itr.Close()
+			assert.Equal(t, 9, count)
+
+			if expected == nil {
+				expected = ids
+			}
+			assert.Equal(t, expected, ids)
+			require.NoError(t, commit.Close())
+
+			// and load the same state
+			reopened, err := NewCommitStore(backend, dir, "conform")
+			require.NoError(t, err)
+			defer reopened.Close()
+			assert.Equal(t, ids[len(ids)-1], reopened.LatestVersion())
+			assert.Equal(t, []byte("value"), reopened.Get([]byte("2-9")))
+		})
+	}
+}
+
+func TestOpenDB(t *testing.T) {
+	assert.True(t, HasBackend(DefaultBackend))
+	assert.False(t, HasBackend("rocksdb"))
+
+	_, err := OpenDB("rocksdb", "", "foo")
+	assert.Error(t, err)
+
+	// a node never runs in memory
+	assert.False(t, HasBackend("memdb"))
+
+	dir, err := ioutil.TempDir("", "bov-backend-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	db, err := OpenDB("", dir, "foo")
+	require.NoError(t, err)
+	db.Close()
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"github.com/confio/weave/store"
+
+	"github.com/iov-one/bcp-demo/ordered"
+)
+
+// sortedBatch collects the writes of a block and applies them to
+// the tree sorted by key.
+//
+// The shape of an iavl tree, and so the app hash, depends on the
+// order of the inserts, not only on the resulting keys. The weave
+// orm updates the indexes of a bucket in map order, so replaying
+// the ops as they came would give every node a different hash.
+// Only the last write of a key is applied.
+type sortedBatch struct {
+	out store.SetDeleter
+	ops map[string]batchOp
+}
+
+type batchOp struct {
+	value   []byte
+	deleted bool
+}
+
+var _ store.Batch = (*sortedBatch)(nil)
+
+func newSortedBatch(out store.SetDeleter) *sortedBatch {
+	return &sortedBatch{out: out, ops: make(map[string]batchOp)}
+}
+
+// Set adds a set operation to the batch
+func (b *sortedBatch) Set(key, value []byte) {
+	b.ops[string(key)] = batchOp{value: value}
+}
+
+// Delete adds a delete operation to the batch
+func (b *sortedBatch) Delete(key []byte) {
+	b.ops[string(key)] = batchOp{deleted: true}
+}
+
+// Write applies all ops in key order and resets the batch
+func (b *sortedBatch) Write() {
+	for _, k := range ordered.Keys(b.ops) {
+		op := b.ops[k]
+		if op.deleted {
+			b.out.Delete([]byte(k))
+		} else {
+			b.out.Set([]byte(k), op.value)
+		}
+	}
+	b.ops = make(map[string]batchOp)
+}
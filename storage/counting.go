@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+)
+
+// Counts is the number of operations done on a store
+type Counts struct {
+	Gets      int
+	Has       int
+	Sets      int
+	Deletes   int
+	Iterators int
+}
+
+// Reads is the total number of read operations
+func (c Counts) Reads() int {
+	return c.Gets + c.Has + c.Iterators
+}
+
+// Writes is the total number of write operations
+func (c Counts) Writes() int {
+	return c.Sets + c.Deletes
+}
+
+// Total is the sum of all operations
+func (c Counts) Total() int {
+	return c.Reads() + c.Writes()
+}
+
+// CountingStore wraps a KVStore and counts all operations done
+// on it. It is meant for tests, to assert how much work a handler
+// does, and is not safe for concurrent use.
+//
+// Writes done through a Batch are counted when they are added
+// to the batch.
+type CountingStore struct {
+	kv     weave.KVStore
+	counts *Counts
+}
+
+var _ weave.KVStore = CountingStore{}
+
+// NewCountingStore wraps kv, starting with all counts at zero
+func NewCountingStore(kv weave.KVStore) CountingStore {
+	return CountingStore{kv: kv, counts: new(Counts)}
+}
+
+// MockCountingStore is a CountingStore over a new memory store
+func MockCountingStore() CountingStore {
+	return NewCountingStore(MockCommitStore().Adapter())
+}
+
+// Counts returns all operations since creation or the last Reset
+func (c CountingStore) Counts() Counts {
+	return *c.counts
+}
+
+// Reset sets all counts to zero
+func (c CountingStore) Reset() {
+	*c.counts = Counts{}
+}
+
+// Count resets the counts, runs fn on the store and returns
+// the operations fn caused
+func (c CountingStore) Count(fn func(weave.KVStore) error) (Counts, error) {
+	c.Reset()
+	err := fn(c)
+	return c.Counts(), err
+}
+
+// Get counts and forwards the call
+func (c CountingStore) Get(key []byte) []byte {
+	c.counts.Gets++
+	return c.kv.Get(key)
+}
+
+// Has counts and forwards the call
+func (c CountingStore) Has(key []byte) bool {
+	c.counts.Has++
+	return c.kv.Has(key)
+}
+
+// Set counts and forwards the call
+func (c CountingStore) Set(key, value []byte) {
+	c.counts.Sets++
+	c.kv.Set(key, value)
+}
+
+// Delete counts and forwards the call
+func (c CountingStore) Delete(key []byte) {
+	c.counts.Deletes++
+	c.kv.Delete(key)
+}
+
+// Iterator counts and forwards the call
+func (c CountingStore) Iterator(start, end []byte) store.Iterator {
+	c.counts.Iterators++
+	return c.kv.Iterator(start, end)
+}
+
+// ReverseIterator counts and forwards the call
+func (c CountingStore) ReverseIterator(start, end []byte) store.Iterator {
+	c.counts.Iterators++
+	return c.kv.ReverseIterator(start, end)
+}
+
+// NewBatch returns a batch that counts all writes on this store
+func (c CountingStore) NewBatch() store.Batch {
+	return store.NewNonAtomicBatch(c)
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+)
+
+func TestCountingStore(t *testing.T) {
+	cases := []struct {
+		ops      func(kv weave.KVStore)
+		expected Counts
+	}{
+		0: {func(kv weave.KVStore) {}, Counts{}},
+		1: {
+			func(kv weave.KVStore) {
+				kv.Set([]byte("a"), []byte("1"))
+				kv.Get([]byte("a"))
+				kv.Get([]byte("b"))
+				kv.Has([]byte("a"))
+			},
+			Counts{Gets: 2, Has: 1, Sets: 1},
+		},
+		2: {
+			func(kv weave.KVStore) {
+				kv.Delete([]byte("a"))
+				kv.Iterator(nil, nil).Close()
+				kv.ReverseIterator(nil, nil).Close()
+			},
+			Counts{Deletes: 1, Iterators: 2},
+		},
+		// batch writes are counted
+		3: {
+			func(kv weave.KVStore) {
+				b := kv.NewBatch()
+				b.Set([]byte("a"), []byte("1"))
+				b.Set([]byte("b"), []byte("2"))
+				b.Delete([]byte("a"))
+				b.Write()
+				kv.Get([]byte("b"))
+			},
+			Counts{Gets: 1, Sets: 2, Deletes: 1},
+		},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			kv := MockCountingStore()
+			// some noise that should be reset
+			kv.Set([]byte("noise"), []byte("foo"))
+
+			counts, err := kv.Count(func(db weave.KVStore) error {
+				tc.ops(db)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, counts)
+			assert.Equal(t, tc.expected, kv.Counts())
+			assert.Equal(t, tc.expected.Reads()+tc.expected.Writes(), counts.Total())
+		})
+	}
+}
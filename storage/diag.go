@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/iov-one/bcp-demo/ordered"
+)
+
+// Write is one key set or deleted in a block
+type Write struct {
+	Key     string `json:"key"`
+	Value   string `json:"value,omitempty"`
+	Deleted bool   `json:"deleted,omitempty"`
+}
+
+// BucketHash is the hash over all keys and values of one bucket
+type BucketHash struct {
+	Bucket string `json:"bucket"`
+	Keys   int    `json:"keys"`
+	Hash   string `json:"hash"`
+}
+
+// Diagnostics describes a committed version, so the state of two
+// nodes that disagree on the app hash can be compared.
+//
+// Writes are the last write of every key changed in the version,
+// Buckets hash the whole state per bucket, that is the key prefix
+// up to the first ':' ("" for keys without one). Keys and values
+// are hex encoded and everything is sorted, so the files of two
+// nodes can be diffed line by line.
+type Diagnostics struct {
+	Version int64        `json:"version"`
+	Hash    string       `json:"hash"`
+	Writes  []Write      `json:"writes"`
+	Buckets []BucketHash `json:"buckets"`
+}
+
+// writeSet records the writes of the adapter since the last
+// commit. It does nothing until enabled.
+type writeSet struct {
+	writes map[string]Write
+}
+
+func (w *writeSet) enabled() bool {
+	return w.writes != nil
+}
+
+func (w *writeSet) set(key, value []byte) {
+	if w.enabled() {
+		k := hex.EncodeToString(key)
+		w.writes[k] = Write{Key: k, Value: hex.EncodeToString(value)}
+	}
+}
+
+func (w *writeSet) delete(key []byte) {
+	if w.enabled() {
+		k := hex.EncodeToString(key)
+		w.writes[k] = Write{Key: k, Deleted: true}
+	}
+}
+
+// flush returns the writes sorted by key and starts over
+func (w *writeSet) flush() []Write {
+	res := make([]Write, 0, len(w.writes))
+	for _, k := range ordered.Keys(w.writes) {
+		res = append(res, w.writes[k])
+	}
+	w.writes = make(map[string]Write)
+	return res
+}
+
+// EnableDiagnostics writes the Diagnostics of every version to
+// path on Commit, replacing the file of the version before. When
+// tendermint halts on an app hash mismatch, the file describes the
+// last block this node committed, compare it with the one of a
+// healthy node.
+//
+// Hashing the buckets reads the whole state on every Commit, this
+// is meant to debug a fork, not for normal operation.
+func (s *CommitStore) EnableDiagnostics(path string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.diagPath = path
+	s.writes.writes = make(map[string]Write)
+}
+
+// Diagnose returns the Diagnostics of the latest version, without
+// the writes. The buckets are hashed from the working tree, so
+// call it between blocks.
+func (s *CommitStore) Diagnose() Diagnostics {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.diagnose(nil)
+}
+
+func (s *CommitStore) diagnose(writes []Write) Diagnostics {
+	if writes == nil {
+		writes = []Write{}
+	}
+	return Diagnostics{
+		Version: int64(s.tree.Version()),
+		Hash:    hex.EncodeToString(s.tree.Hash()),
+		Writes:  writes,
+		Buckets: s.bucketHashes(),
+	}
+}
+
+// bucketHashes iterates over the working tree in key order, so
+// the keys of each bucket are hashed in order
+func (s *CommitStore) bucketHashes() []BucketHash {
+	buckets := make(map[string]*BucketHash)
+	hashes := make(map[string]hash.Hash)
+	s.tree.Tree().IterateRange(nil, nil, true, func(key, value []byte) bool {
+		name := bucketOf(key)
+		if buckets[name] == nil {
+			buckets[name] = &BucketHash{Bucket: name}
+			hashes[name] = sha256.New()
+		}
+		buckets[name].Keys++
+		writeLen(hashes[name], key)
+		writeLen(hashes[name], value)
+		return false
+	})
+
+	res := make([]BucketHash, 0, len(buckets))
+	for _, name := range ordered.Keys(buckets) {
+		b := buckets[name]
+		b.Hash = hex.EncodeToString(hashes[name].Sum(nil))
+		res = append(res, *b)
+	}
+	return res
+}
+
+// bucketOf returns the orm bucket name of a key
+func bucketOf(key []byte) string {
+	if i := bytes.IndexByte(key, ':'); i >= 0 {
+		return string(key[:i])
+	}
+	return ""
+}
+
+// writeLen prefixes bz with its length, so the hash of a
+// bucket is unambiguous
+func writeLen(w io.Writer, bz []byte) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(len(bz)))
+	w.Write(buf[:n])
+	w.Write(bz)
+}
+
+// writeDiagnostics replaces the file at path, it is written to
+// a temp file first, so a crash never leaves half a file
+func writeDiagnostics(path string, diag Diagnostics) error {
+	bz, err := json.MarshalIndent(diag, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(bz)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
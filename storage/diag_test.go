@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readDiagnostics(t *testing.T, path string) Diagnostics {
+	bz, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	var diag Diagnostics
+	require.NoError(t, json.Unmarshal(bz, &diag))
+	return diag
+}
+
+func TestDiagnostics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bov-diag-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "diag.json")
+
+	commit := MockCommitStore()
+	// writes before are not recorded
+	commit.Adapter().Set([]byte("cash:before"), []byte("1"))
+	commit.Commit()
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	commit.EnableDiagnostics(path)
+	kv := commit.Adapter()
+	kv.Set([]byte("esc:2"), []byte("foo"))
+	kv.Set([]byte("cash:a"), []byte("bar"))
+	kv.Set([]byte("plain"), []byte("baz"))
+	kv.Set([]byte("esc:1"), []byte("first"))
+	kv.Set([]byte("esc:1"), []byte("second"))
+	id := commit.Commit()
+
+	enc := func(s string) string { return hex.EncodeToString([]byte(s)) }
+	diag := readDiagnostics(t, path)
+	assert.Equal(t, id.Version, diag.Version)
+	assert.Equal(t, hex.EncodeToString(id.Hash), diag.Hash)
+	assert.Equal(t, []Write{
+		{Key: enc("cash:a"), Value: enc("bar")},
+		{Key: enc("esc:1"), Value: enc("second")},
+		{Key: enc("esc:2"), Value: enc("foo")},
+		{Key: enc("plain"), Value: enc("baz")},
+	}, diag.Writes)
+	require.Len(t, diag.Buckets, 3)
+	assert.Equal(t, "", diag.Buckets[0].Bucket)
+	assert.Equal(t, 1, diag.Buckets[0].Keys)
+	assert.Equal(t, "cash", diag.Buckets[1].Bucket)
+	assert.Equal(t, 2, diag.Buckets[1].Keys)
+	assert.Equal(t, "esc", diag.Buckets[2].Bucket)
+	assert.Equal(t, 2, diag.Buckets[2].Keys)
+	assert.Equal(t, diag.Buckets, commit.Diagnose().Buckets)
+
+	// only the writes of the last block are listed
+	commit.Adapter().Delete([]byte("esc:2"))
+	commit.Commit()
+	next := readDiagnostics(t, path)
+	assert.Equal(t, []Write{{Key: enc("esc:2"), Deleted: true}}, next.Writes)
+	assert.Equal(t, diag.Buckets[:2], next.Buckets[:2])
+	assert.Equal(t, 1, next.Buckets[2].Keys)
+	assert.NotEqual(t, diag.Buckets[2].Hash, next.Buckets[2].Hash)
+
+	// the same state has the same hashes, whatever the history
+	other := MockCommitStore()
+	okv := other.Adapter()
+	okv.Set([]byte("esc:1"), []byte("second"))
+	okv.Set([]byte("plain"), []byte("baz"))
+	okv.Set([]byte("cash:a"), []byte("bar"))
+	okv.Set([]byte("cash:before"), []byte("1"))
+	other.Commit()
+	assert.Equal(t, next.Buckets, other.Diagnose().Buckets)
+	assert.Empty(t, other.Diagnose().Writes)
+}
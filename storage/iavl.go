@@ -0,0 +1,247 @@
+/*
+Package storage contains the persistent store of the bov app.
+
+It is a copy of the weave iavl CommitStore, that can be closed
+cleanly on shutdown and repairs a partially written version
+when it is opened again after a crash.
+*/
+package storage
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/tendermint/iavl"
+	dbm "github.com/tendermint/tmlibs/db"
+
+	"github.com/confio/weave/store"
+)
+
+// TODO: make these configurable?
+const (
+	DefaultCacheSize int   = 10000
+	DefaultHistory   int64 = 20
+)
+
+// ErrClosed is returned when using a store after Close
+var ErrClosed = errors.New("store is closed")
+
+// CommitStore manages a iavl committed state
+type CommitStore struct {
+	db         dbm.DB
+	tree       *iavl.VersionedTree
+	numHistory int64
+
+	// mtx guards commits against closing the db
+	mtx    sync.Mutex
+	closed bool
+
+	// writes of the current block, if diagnostics are enabled
+	writes   writeSet
+	diagPath string
+}
+
+var _ store.CommitKVStore = (*CommitStore)(nil)
+
+// NewCommitStore creates a new store with disk backing,
+// using the given db backend (see Backends).
+// Any version that was not completely written is dropped
+// before loading the latest version.
+func NewCommitStore(backend, path, name string) (*CommitStore, error) {
+	// Create the underlying datastore which will
+	// persist the Merkle tree inner & leaf nodes.
+	db, err := OpenDB(backend, path, name)
+	if err != nil {
+		return nil, err
+	}
+	return NewCommitStoreFromDB(db)
+}
+
+// NewCommitStoreFromDB creates a store on top of an open db,
+// repairing it if needed
+func NewCommitStoreFromDB(db dbm.DB) (*CommitStore, error) {
+	_, err := Recover(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	tree := iavl.NewVersionedTree(db, DefaultCacheSize)
+	commit := &CommitStore{db: db, tree: tree, numHistory: DefaultHistory}
+	err = commit.LoadLatestVersion()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return commit, nil
+}
+
+// MockCommitStore creates a new in-memory store for testing
+func MockCommitStore() *CommitStore {
+	var db dbm.DB = dbm.NewMemDB()
+	tree := iavl.NewVersionedTree(db, DefaultCacheSize)
+	return &CommitStore{db: db, tree: tree, numHistory: DefaultHistory}
+}
+
+// Get returns the value at last committed state
+// returns nil iff key doesn't exist. Panics on nil key.
+func (s *CommitStore) Get(key []byte) []byte {
+	version := int64(s.tree.Version())
+	_, val := s.tree.GetVersioned(key, version)
+	return val
+}
+
+// GetWithProof returns the value of key at the committed version,
+// or the latest one if version is 0, along with a proof against
+// the hash of that version. The proof shows the key is absent if
+// the value is nil.
+func (s *CommitStore) GetWithProof(key []byte, version int64) ([]byte, iavl.KeyProof, error) {
+	if version == 0 {
+		version = int64(s.tree.Version())
+	}
+	return s.tree.GetVersionedWithProof(key, version)
+}
+
+// Commit the next version to disk, and returns info.
+//
+// Close waits for a running Commit to finish, a Commit
+// after Close panics, as we cannot guarantee the data
+// is saved.
+func (s *CommitStore) Commit() store.CommitID {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.closed {
+		panic(ErrClosed)
+	}
+
+	hash, version, err := s.tree.SaveVersion()
+	if err != nil {
+		panic(err)
+	}
+	if s.writes.enabled() {
+		// just a debug aid, a broken disk shows up elsewhere
+		_ = writeDiagnostics(s.diagPath, s.diagnose(s.writes.flush()))
+	}
+
+	// Potentially release an old version of history
+	if s.numHistory > 0 && (s.numHistory < version) {
+		toRelease := version - s.numHistory
+		s.tree.DeleteVersion(toRelease)
+	}
+
+	return store.CommitID{
+		Version: int64(version),
+		Hash:    hash,
+	}
+}
+
+// Close flushes all data to disk and releases the db.
+// It is safe to call multiple times.
+func (s *CommitStore) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	s.db.Close()
+	return nil
+}
+
+// LoadLatestVersion loads the latest persisted version.
+// If there was a crash during the last commit, it is guaranteed
+// to return a stable state, even if older.
+func (s *CommitStore) LoadLatestVersion() error {
+	_, err := s.tree.Load()
+	return err
+}
+
+// LatestVersion returns info on the latest version saved to disk
+func (s *CommitStore) LatestVersion() store.CommitID {
+	return store.CommitID{
+		Version: int64(s.tree.Version()),
+		Hash:    s.tree.Hash(),
+	}
+}
+
+// Adapter returns a wrapped version of the tree.
+//
+// Data written here is stored in the tip of the version tree,
+// and will be written to disk on Commit. There is no way
+// to rollback writes here, without throwing away the CommitStore
+// and re-loading from disk.
+func (s *CommitStore) Adapter() store.CacheableKVStore {
+	var kv store.KVStore = adapter{tree: s.tree.Tree(), writes: &s.writes}
+	return store.BTreeCacheable{KVStore: kv}
+}
+
+// CacheWrap wraps the Adapter with a cache, so it may be written
+// or discarded as needed.
+func (s *CommitStore) CacheWrap() store.KVCacheWrap {
+	return s.Adapter().CacheWrap()
+}
+
+// adapter converts the working iavl.Tree to match these interfaces
+type adapter struct {
+	tree   *iavl.Tree
+	writes *writeSet
+}
+
+var _ store.KVStore = adapter{}
+
+// Get returns nil iff key doesn't exist. Panics on nil key.
+func (a adapter) Get(key []byte) []byte {
+	_, val := a.tree.Get(key)
+	return val
+}
+
+// Has checks if a key exists. Panics on nil key.
+func (a adapter) Has(key []byte) bool {
+	return a.tree.Has(key)
+}
+
+// Set adds a new value
+func (a adapter) Set(key, value []byte) {
+	a.writes.set(key, value)
+	a.tree.Set(key, value)
+}
+
+// Delete removes from the tree
+func (a adapter) Delete(key []byte) {
+	a.writes.delete(key)
+	a.tree.Remove(key)
+}
+
+// NewBatch returns a batch that can write multiple ops atomically,
+// sorted by key so the app hash doesn't depend on their order
+func (a adapter) NewBatch() store.Batch {
+	return newSortedBatch(a)
+}
+
+// Iterator over a domain of keys in ascending order. End is exclusive.
+// Start must be less than end, or the Iterator is invalid.
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+func (a adapter) Iterator(start, end []byte) store.Iterator {
+	var res []store.Model
+	add := func(key []byte, value []byte) bool {
+		m := store.Model{Key: key, Value: value}
+		res = append(res, m)
+		return false
+	}
+	a.tree.IterateRange(start, end, true, add)
+	return store.NewSliceIterator(res)
+}
+
+// ReverseIterator over a domain of keys in descending order. End is exclusive.
+// Start must be greater than end, or the Iterator is invalid.
+// CONTRACT: No writes may happen within a domain while an iterator exists over it.
+func (a adapter) ReverseIterator(start, end []byte) store.Iterator {
+	var res []store.Model
+	add := func(key []byte, value []byte) bool {
+		m := store.Model{Key: key, Value: value}
+		res = append(res, m)
+		return false
+	}
+	a.tree.IterateRange(start, end, false, add)
+	return store.NewSliceIterator(res)
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeCommitStore(t *testing.T) (string, *CommitStore, func()) {
+	tmpDir, err := ioutil.TempDir("", "bov-storage-")
+	require.NoError(t, err)
+	cleanup := func() { os.RemoveAll(tmpDir) }
+	commit, err := NewCommitStore("", tmpDir, "base")
+	require.NoError(t, err)
+	return tmpDir, commit, cleanup
+}
+
+func TestCommitAndReopen(t *testing.T) {
+	dir, commit, cleanup := makeCommitStore(t)
+	defer cleanup()
+
+	k, v := []byte("french"), []byte("fry")
+	k2, v2 := []byte("ice"), []byte("cream")
+
+	adapter := commit.Adapter()
+	adapter.Set(k, v)
+	id := commit.Commit()
+	assert.EqualValues(t, 1, id.Version)
+	assert.Equal(t, v, commit.Get(k))
+
+	// uncommitted writes are lost on close
+	adapter.Set(k2, v2)
+	require.NoError(t, commit.Close())
+	// closing twice is fine
+	require.NoError(t, commit.Close())
+	assert.Panics(t, func() { commit.Commit() })
+
+	reopened, err := NewCommitStore("", dir, "base")
+	require.NoError(t, err)
+	defer reopened.Close()
+	assert.Equal(t, id, reopened.LatestVersion())
+	assert.Equal(t, v, reopened.Get(k))
+	assert.Nil(t, reopened.Get(k2))
+}
+
+func TestMockCommitStore(t *testing.T) {
+	commit := MockCommitStore()
+	k, v := []byte("foo"), []byte("bar")
+
+	cache := commit.CacheWrap()
+	cache.Set(k, v)
+	assert.Nil(t, commit.Get(k))
+	cache.Write()
+	assert.Nil(t, commit.Get(k))
+
+	commit.Commit()
+	assert.Equal(t, v, commit.Get(k))
+	assert.NoError(t, commit.Close())
+}
+
+func TestCommitHashIgnoresWriteOrder(t *testing.T) {
+	keys := [][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d"), []byte("e")}
+
+	commit := func(order []int) []byte {
+		store := MockCommitStore()
+		cache := store.CacheWrap()
+		for _, i := range order {
+			cache.Set(keys[i], []byte{byte(i)})
+		}
+		cache.Delete(keys[2])
+		cache.Write()
+		return store.Commit().Hash
+	}
+
+	hash := commit([]int{0, 1, 2, 3, 4})
+	assert.Equal(t, hash, commit([]int{4, 3, 2, 1, 0}))
+	assert.Equal(t, hash, commit([]int{2, 0, 4, 1, 3}))
+}
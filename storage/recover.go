@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+
+	dbm "github.com/tendermint/tmlibs/db"
+)
+
+// these must match the key layout of tendermint/iavl
+const (
+	rootPrefix    = "r/"
+	rootPrefixFmt = "r/%d"
+	nodeKeyFmt    = "n/%x"
+)
+
+// Recover checks the latest versions stored in the db and
+// removes every version from the top whose root node was
+// never written, which happens if the process is killed
+// in the middle of a commit. It returns the number of
+// versions removed.
+//
+// After Recover, the tree can be loaded as normal and the
+// app replays the missing blocks from tendermint.
+func Recover(db dbm.DB) (int, error) {
+	roots := make(map[int64][]byte)
+	itr := dbm.IteratePrefix(db, []byte(rootPrefix))
+	for ; itr.Valid(); itr.Next() {
+		var version int64
+		_, err := fmt.Sscanf(string(itr.Key()), rootPrefixFmt, &version)
+		if err != nil {
+			itr.Close()
+			return 0, fmt.Errorf("invalid root key %q: %v", itr.Key(), err)
+		}
+		roots[version] = itr.Value()
+	}
+	itr.Close()
+
+	versions := make([]int64, 0, len(roots))
+	for v := range roots {
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+	dropped := 0
+	for _, v := range versions {
+		root := roots[v]
+		// empty tree has no root node
+		if len(root) == 0 || db.Has([]byte(fmt.Sprintf(nodeKeyFmt, root))) {
+			break
+		}
+		db.DeleteSync([]byte(fmt.Sprintf(rootPrefixFmt, v)))
+		dropped++
+	}
+	return dropped, nil
+}
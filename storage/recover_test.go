@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tmlibs/db"
+)
+
+// setupDB commits n versions to a memory db
+func setupDB(t *testing.T, n int) (dbm.DB, [][]byte) {
+	db := dbm.NewMemDB()
+	commit, err := NewCommitStoreFromDB(db)
+	require.NoError(t, err)
+
+	var hashes [][]byte
+	for i := 0; i < n; i++ {
+		commit.Adapter().Set([]byte(fmt.Sprintf("key-%d", i)), []byte("value"))
+		id := commit.Commit()
+		hashes = append(hashes, id.Hash)
+	}
+	return db, hashes
+}
+
+func TestRecover(t *testing.T) {
+	cases := []struct {
+		versions int
+		// remove the root nodes of these versions
+		broken  []int64
+		dropped int
+		latest  int64
+	}{
+		// empty db
+		0: {0, nil, 0, 0},
+		// nothing to fix
+		1: {3, nil, 0, 3},
+		// crash during last commit
+		2: {3, []int64{3}, 1, 2},
+		// only the top is dropped
+		3: {4, []int64{4, 3}, 2, 2},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db, hashes := setupDB(t, tc.versions)
+			for _, v := range tc.broken {
+				db.Delete([]byte(fmt.Sprintf(nodeKeyFmt, hashes[v-1])))
+			}
+
+			dropped, err := Recover(db)
+			require.NoError(t, err)
+			assert.Equal(t, tc.dropped, dropped)
+
+			// we can load it again
+			commit, err := NewCommitStoreFromDB(db)
+			require.NoError(t, err)
+			id := commit.LatestVersion()
+			assert.Equal(t, tc.latest, id.Version)
+			if tc.latest > 0 {
+				assert.Equal(t, hashes[tc.latest-1], id.Hash)
+			}
+
+			// and continue committing
+			commit.Adapter().Set([]byte("next"), []byte("block"))
+			next := commit.Commit()
+			assert.Equal(t, tc.latest+1, next.Version)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	dbm "github.com/tendermint/tmlibs/db"
+)
+
+// scanPage is how many pairs a scanIterator reads at once
+const scanPage = 100
+
+// scanFunc returns up to limit pairs in order, with keys from
+// start on and below end, unless it is nil. The slices must be
+// copies, they outlive the read.
+type scanFunc func(start, end []byte, limit int) (keys, values [][]byte)
+
+// scanIterator iterates forward over a db a page at a time. The
+// backends that build with a tag use it, so they hold no read
+// transaction while the caller writes to the db, which blocks
+// the writes of boltdb.
+type scanIterator struct {
+	scan   scanFunc
+	start  []byte
+	end    []byte
+	keys   [][]byte
+	values [][]byte
+	pos    int
+	// last is true once a page came back short
+	last bool
+}
+
+var _ dbm.Iterator = (*scanIterator)(nil)
+
+func newScanIterator(scan scanFunc, start, end []byte) *scanIterator {
+	itr := &scanIterator{scan: scan, start: start, end: end}
+	itr.read(start)
+	return itr
+}
+
+// read loads the page from the key on
+func (itr *scanIterator) read(from []byte) {
+	itr.keys, itr.values = itr.scan(from, itr.end, scanPage)
+	itr.pos = 0
+	itr.last = len(itr.keys) < scanPage
+}
+
+// Domain fulfils dbm.Iterator
+func (itr *scanIterator) Domain() ([]byte, []byte) {
+	return itr.start, itr.end
+}
+
+// Valid fulfils dbm.Iterator
+func (itr *scanIterator) Valid() bool {
+	return itr.pos < len(itr.keys)
+}
+
+// Next fulfils dbm.Iterator, it reads the next page once
+// this one is done
+func (itr *scanIterator) Next() {
+	itr.assertIsValid()
+	itr.pos++
+	if itr.pos < len(itr.keys) || itr.last {
+		return
+	}
+	// the smallest key after the last one
+	prev := itr.keys[len(itr.keys)-1]
+	from := make([]byte, len(prev)+1)
+	copy(from, prev)
+	itr.read(from)
+}
+
+// Key fulfils dbm.Iterator
+func (itr *scanIterator) Key() []byte {
+	itr.assertIsValid()
+	return itr.keys[itr.pos]
+}
+
+// Value fulfils dbm.Iterator
+func (itr *scanIterator) Value() []byte {
+	itr.assertIsValid()
+	return itr.values[itr.pos]
+}
+
+// Close fulfils dbm.Iterator
+func (itr *scanIterator) Close() {
+	itr.keys, itr.values = nil, nil
+}
+
+func (itr *scanIterator) assertIsValid() {
+	if !itr.Valid() {
+		panic("scanIterator is invalid")
+	}
+}
+
+// cp copies bz, never returning nil
+func cp(bz []byte) []byte {
+	res := make([]byte, len(bz))
+	copy(res, bz)
+	return res
+}
@@ -0,0 +1,51 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dbm "github.com/tendermint/tmlibs/db"
+)
+
+// TestScanIterator reads a range over several pages
+func TestScanIterator(t *testing.T) {
+	db := dbm.NewMemDB()
+	for i := 0; i < 3*scanPage; i++ {
+		db.Set([]byte(fmt.Sprintf("%04d", i)), []byte{byte(i)})
+	}
+	// the key after 0100 starts the next page
+	db.Set([]byte("0100\x00"), []byte("next"))
+	var reads int
+	scan := func(start, end []byte, limit int) (keys, values [][]byte) {
+		reads++
+		itr := db.Iterator(start, end)
+		defer itr.Close()
+		for ; itr.Valid() && len(keys) < limit; itr.Next() {
+			keys = append(keys, cp(itr.Key()))
+			values = append(values, cp(itr.Value()))
+		}
+		return keys, values
+	}
+
+	itr := newScanIterator(scan, []byte("0050"), []byte("0250"))
+	var keys []string
+	for ; itr.Valid(); itr.Next() {
+		keys = append(keys, string(itr.Key()))
+	}
+	itr.Close()
+	assert.Len(t, keys, 201)
+	assert.Equal(t, "0050", keys[0])
+	assert.Equal(t, "0100\x00", keys[51])
+	assert.Equal(t, "0249", keys[200])
+	assert.Equal(t, 3, reads)
+	assert.Panics(t, func() { itr.Next() })
+
+	// an empty range is invalid at once
+	itr = newScanIterator(scan, []byte("1"), nil)
+	assert.False(t, itr.Valid())
+	start, end := itr.Domain()
+	assert.True(t, bytes.Equal([]byte("1"), start))
+	assert.Nil(t, end)
+}
@@ -0,0 +1,55 @@
+package tracing_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/tracing"
+	"github.com/iov-one/bcp-demo/x/anymsg"
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/escrow/escrowtest"
+)
+
+// the app registers all messages, an escrow schedules its return
+func init() {
+	anymsg.Register(&escrow.ReturnEscrowMsg{})
+}
+
+type recorder struct {
+	traces [][]*tracing.Span
+}
+
+func (r *recorder) Export(spans []*tracing.Span) {
+	r.traces = append(r.traces, spans)
+}
+
+// the escrow handlers add their steps to the trace
+func TestEscrowSteps(t *testing.T) {
+	f := escrowtest.NewFixture(t)
+	rec := &recorder{}
+	tracer := tracing.NewTracer(rec, 0)
+
+	tx := x.TestHelpers{}.MockTx(escrowtest.StandardEscrow())
+	ctx := weave.WithHeight(context.Background(), 10)
+	ctx = x.TestHelpers{}.CtxAuth("escrowtest").SetPermissions(ctx, escrowtest.Alice)
+	_, err := tracer.Deliver(ctx, f.DB, tx, tracing.Handler(tracer, f.Router))
+	require.NoError(t, err)
+
+	require.Len(t, rec.traces, 1)
+	var names []string
+	for _, s := range rec.traces[0] {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{"DeliverTx", "escrow/create", "validate", "Save",
+		"MoveCoinsBatch", "MoveCoins"}, names)
+	spans := rec.traces[0]
+	assert.Equal(t, spans[1].SpanID, spans[2].Parent)
+	assert.Equal(t, spans[4].SpanID, spans[5].Parent)
+	assert.NotZero(t, spans[3].Writes)
+}
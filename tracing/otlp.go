@@ -0,0 +1,244 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tmlibs/log"
+)
+
+const (
+	// otlpPath is where collectors take traces over http
+	otlpPath = "/v1/traces"
+	// otlpQueue is how many traces wait to be sent, more
+	// are dropped rather than slowing down the node
+	otlpQueue = 1024
+	// otlpBatch is how many traces are sent at once
+	otlpBatch = 64
+	// otlpFlush is how long a trace waits for a batch
+	otlpFlush = 2 * time.Second
+
+	// span kind and status codes of OTLP
+	otlpKindInternal = 1
+	otlpStatusError  = 2
+)
+
+// OTLPExporter sends the traces to an OpenTelemetry collector
+// with OTLP over http, encoded as json. It sends them in batches
+// in the background, and drops them while the collector is slow
+// or gone. Close sends what is left.
+type OTLPExporter struct {
+	url     string
+	service string
+	client  *http.Client
+	logger  log.Logger
+
+	queue chan []*Span
+	done  chan struct{}
+	once  sync.Once
+}
+
+var _ Exporter = (*OTLPExporter)(nil)
+
+// NewOTLPExporter sends the traces of service to the collector
+// at endpoint, eg. "http://localhost:4318". Failures are logged.
+func NewOTLPExporter(endpoint, service string, logger log.Logger) *OTLPExporter {
+	e := &OTLPExporter{
+		url:     strings.TrimSuffix(endpoint, "/") + otlpPath,
+		service: service,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		logger:  logger,
+		queue:   make(chan []*Span, otlpQueue),
+		done:    make(chan struct{}),
+	}
+	go e.run()
+	return e
+}
+
+// Export queues the trace, it is dropped if the queue is full
+func (e *OTLPExporter) Export(spans []*Span) {
+	select {
+	case e.queue <- spans:
+	default:
+	}
+}
+
+// Close sends the queued traces and stops, no trace must be
+// exported after
+func (e *OTLPExporter) Close() error {
+	e.once.Do(func() { close(e.queue) })
+	<-e.done
+	return nil
+}
+
+// run sends the queue in batches, until it is closed
+func (e *OTLPExporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(otlpFlush)
+	defer ticker.Stop()
+
+	var batch [][]*Span
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := e.send(batch); err != nil {
+			e.logger.Error("Cannot export traces", "traces", len(batch), "err", err)
+		}
+		batch = nil
+	}
+	for {
+		select {
+		case spans, ok := <-e.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, spans)
+			if len(batch) >= otlpBatch {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// send posts the traces to the collector
+func (e *OTLPExporter) send(traces [][]*Span) error {
+	body, err := json.Marshal(e.request(traces))
+	if err != nil {
+		return err
+	}
+	res, err := e.client.Post(e.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("collector answered %s", res.Status)
+	}
+	return nil
+}
+
+// request is the ExportTraceServiceRequest of the traces
+func (e *OTLPExporter) request(traces [][]*Span) otlpRequest {
+	var spans []otlpSpan
+	for _, trace := range traces {
+		for _, s := range trace {
+			spans = append(spans, toOTLP(s))
+		}
+	}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpAttr{
+			attr("service.name", e.service),
+		}},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "github.com/iov-one/bcp-demo/tracing"},
+			Spans: spans,
+		}},
+	}}}
+}
+
+// toOTLP converts a span, the ids are hex in the json of OTLP
+func toOTLP(s *Span) otlpSpan {
+	res := otlpSpan{
+		TraceID: hex.EncodeToString(s.TraceID[:]),
+		SpanID:  hex.EncodeToString(s.SpanID[:]),
+		Name:    s.Name,
+		Kind:    otlpKindInternal,
+		Start:   strconv.FormatInt(s.Started.UnixNano(), 10),
+		End:     strconv.FormatInt(s.Ended.UnixNano(), 10),
+	}
+	if s.Parent != ([8]byte{}) {
+		res.ParentSpanID = hex.EncodeToString(s.Parent[:])
+	}
+	for _, a := range s.Attrs {
+		res.Attributes = append(res.Attributes, attr(a.Key, a.Value))
+	}
+	res.Attributes = append(res.Attributes,
+		attr("db.reads", s.Reads), attr("db.writes", s.Writes))
+	if s.Err != "" {
+		res.Status = otlpStatus{Code: otlpStatusError, Message: s.Err}
+	}
+	return res
+}
+
+// attr converts an attribute, values of other types than
+// string, int64 and bool are written as strings
+func attr(key string, value interface{}) otlpAttr {
+	res := otlpAttr{Key: key}
+	switch v := value.(type) {
+	case string:
+		res.Value.String = &v
+	case int64:
+		i := strconv.FormatInt(v, 10)
+		res.Value.Int = &i
+	case bool:
+		res.Value.Bool = &v
+	default:
+		str := fmt.Sprintf("%v", v)
+		res.Value.String = &str
+	}
+	return res
+}
+
+// the json of OTLP, only what the spans use
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttr `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID      string     `json:"traceId"`
+	SpanID       string     `json:"spanId"`
+	ParentSpanID string     `json:"parentSpanId,omitempty"`
+	Name         string     `json:"name"`
+	Kind         int        `json:"kind"`
+	Start        string     `json:"startTimeUnixNano"`
+	End          string     `json:"endTimeUnixNano"`
+	Attributes   []otlpAttr `json:"attributes,omitempty"`
+	Status       otlpStatus `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpAttr struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+// otlpValue is one of a string, an int64 as a string, or a bool
+type otlpValue struct {
+	String *string `json:"stringValue,omitempty"`
+	Int    *string `json:"intValue,omitempty"`
+	Bool   *bool   `json:"boolValue,omitempty"`
+}
@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tmlibs/log"
+)
+
+func TestOTLPExporter(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		bodies <- body
+	}))
+	defer collector.Close()
+
+	root := &Span{
+		TraceID: [16]byte{1, 2, 3},
+		SpanID:  [8]byte{4},
+		Name:    "DeliverTx",
+		Started: time.Unix(1, 0),
+		Ended:   time.Unix(2, 0),
+		Attrs:   []Attr{{"height", int64(7)}, {"msg", "cash/send"}},
+		Err:     "no funds",
+	}
+	child := &Span{
+		TraceID: root.TraceID,
+		SpanID:  [8]byte{5},
+		Parent:  root.SpanID,
+		Name:    "validate",
+		Started: time.Unix(1, 5),
+		Ended:   time.Unix(1, 10),
+		Reads:   3,
+	}
+
+	exp := NewOTLPExporter(collector.URL+"/", "bov", log.NewNopLogger())
+	exp.Export([]*Span{root, child})
+	require.NoError(t, exp.Close())
+
+	var body []byte
+	select {
+	case body = <-bodies:
+	default:
+		t.Fatal("nothing sent on close")
+	}
+	assert.JSONEq(t, `{"resourceSpans": [{
+		"resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "bov"}}]},
+		"scopeSpans": [{
+			"scope": {"name": "github.com/iov-one/bcp-demo/tracing"},
+			"spans": [{
+				"traceId": "01020300000000000000000000000000",
+				"spanId": "0400000000000000",
+				"name": "DeliverTx",
+				"kind": 1,
+				"startTimeUnixNano": "1000000000",
+				"endTimeUnixNano": "2000000000",
+				"attributes": [
+					{"key": "height", "value": {"intValue": "7"}},
+					{"key": "msg", "value": {"stringValue": "cash/send"}},
+					{"key": "db.reads", "value": {"intValue": "0"}},
+					{"key": "db.writes", "value": {"intValue": "0"}}
+				],
+				"status": {"code": 2, "message": "no funds"}
+			}, {
+				"traceId": "01020300000000000000000000000000",
+				"spanId": "0500000000000000",
+				"parentSpanId": "0400000000000000",
+				"name": "validate",
+				"kind": 1,
+				"startTimeUnixNano": "1000000005",
+				"endTimeUnixNano": "1000000010",
+				"attributes": [
+					{"key": "db.reads", "value": {"intValue": "3"}},
+					{"key": "db.writes", "value": {"intValue": "0"}}
+				],
+				"status": {}
+			}]
+		}]
+	}]}`, string(body))
+}
+
+func TestOTLPExporterDrops(t *testing.T) {
+	// a collector that is gone does not block the node
+	exp := NewOTLPExporter("http://127.0.0.1:1", "bov", log.NewNopLogger())
+	for i := 0; i < 2*otlpQueue; i++ {
+		exp.Export([]*Span{{Name: "DeliverTx"}})
+	}
+	require.NoError(t, exp.Close())
+}
@@ -0,0 +1,145 @@
+package tracing
+
+import (
+	"github.com/confio/weave"
+)
+
+// wrap returns db counting its access in the trace, a store
+// that can be cache wrapped stays one
+func wrap(db weave.KVStore, t *trace) weave.KVStore {
+	if cacheable, ok := db.(weave.CacheableKVStore); ok {
+		return cacheStore{CacheableKVStore: cacheable, trace: t}
+	}
+	return tracedStore{KVStore: db, trace: t}
+}
+
+// traceOf is the trace of the tx the store belongs to,
+// nil if it is not traced
+func traceOf(db weave.ReadOnlyKVStore) *trace {
+	switch s := db.(type) {
+	case tracedStore:
+		return s.trace
+	case cacheStore:
+		return s.trace
+	case cacheWrap:
+		return s.trace
+	}
+	return nil
+}
+
+// tracedStore counts the access to a store
+type tracedStore struct {
+	weave.KVStore
+	trace *trace
+}
+
+func (s tracedStore) Get(key []byte) []byte {
+	s.trace.read()
+	return s.KVStore.Get(key)
+}
+
+func (s tracedStore) Has(key []byte) bool {
+	s.trace.read()
+	return s.KVStore.Has(key)
+}
+
+func (s tracedStore) Iterator(start, end []byte) weave.Iterator {
+	s.trace.read()
+	return s.KVStore.Iterator(start, end)
+}
+
+func (s tracedStore) ReverseIterator(start, end []byte) weave.Iterator {
+	s.trace.read()
+	return s.KVStore.ReverseIterator(start, end)
+}
+
+func (s tracedStore) Set(key, value []byte) {
+	s.trace.write()
+	s.KVStore.Set(key, value)
+}
+
+func (s tracedStore) Delete(key []byte) {
+	s.trace.write()
+	s.KVStore.Delete(key)
+}
+
+// cacheStore counts the access to a store that can be cache
+// wrapped, and to all its cache wraps, so the savepoints work
+// as without tracing
+type cacheStore struct {
+	weave.CacheableKVStore
+	trace *trace
+}
+
+func (s cacheStore) Get(key []byte) []byte {
+	s.trace.read()
+	return s.CacheableKVStore.Get(key)
+}
+
+func (s cacheStore) Has(key []byte) bool {
+	s.trace.read()
+	return s.CacheableKVStore.Has(key)
+}
+
+func (s cacheStore) Iterator(start, end []byte) weave.Iterator {
+	s.trace.read()
+	return s.CacheableKVStore.Iterator(start, end)
+}
+
+func (s cacheStore) ReverseIterator(start, end []byte) weave.Iterator {
+	s.trace.read()
+	return s.CacheableKVStore.ReverseIterator(start, end)
+}
+
+func (s cacheStore) Set(key, value []byte) {
+	s.trace.write()
+	s.CacheableKVStore.Set(key, value)
+}
+
+func (s cacheStore) Delete(key []byte) {
+	s.trace.write()
+	s.CacheableKVStore.Delete(key)
+}
+
+func (s cacheStore) CacheWrap() weave.KVCacheWrap {
+	return cacheWrap{KVCacheWrap: s.CacheableKVStore.CacheWrap(), trace: s.trace}
+}
+
+type cacheWrap struct {
+	weave.KVCacheWrap
+	trace *trace
+}
+
+func (c cacheWrap) Get(key []byte) []byte {
+	c.trace.read()
+	return c.KVCacheWrap.Get(key)
+}
+
+func (c cacheWrap) Has(key []byte) bool {
+	c.trace.read()
+	return c.KVCacheWrap.Has(key)
+}
+
+func (c cacheWrap) Iterator(start, end []byte) weave.Iterator {
+	c.trace.read()
+	return c.KVCacheWrap.Iterator(start, end)
+}
+
+func (c cacheWrap) ReverseIterator(start, end []byte) weave.Iterator {
+	c.trace.read()
+	return c.KVCacheWrap.ReverseIterator(start, end)
+}
+
+func (c cacheWrap) Set(key, value []byte) {
+	c.trace.write()
+	c.KVCacheWrap.Set(key, value)
+}
+
+func (c cacheWrap) Delete(key []byte) {
+	c.trace.write()
+	c.KVCacheWrap.Delete(key)
+}
+
+func (c cacheWrap) CacheWrap() weave.KVCacheWrap {
+	return cacheWrap{KVCacheWrap: c.KVCacheWrap.CacheWrap(), trace: c.trace}
+}
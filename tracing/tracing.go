@@ -0,0 +1,329 @@
+/*
+Package tracing records where the time of a tx goes, as a trace of
+spans: one for the tx, one inside the other for every decorator of
+the chain, one for the handler, and any the handlers start for their
+steps, like validating the msg, moving coins or saving an escrow.
+Every span counts the reads and writes to the store while it is the
+innermost one.
+
+A Tracer at the top of the chain starts the trace of a tx and hands
+it to an Exporter once the tx is done, eg. an OTLPExporter sending
+it to an OpenTelemetry collector. The trace travels with the store,
+as every handler and controller gets that one, so code deep down
+starts a span with
+
+	defer tracing.Begin(db, "MoveCoins").End()
+
+which does nothing if the tx is not traced. The spans only observe,
+so tracing does not change the app hash.
+*/
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/confio/weave"
+)
+
+// Attr is an attribute of a span, its Value is a string,
+// an int64 or a bool
+type Attr struct {
+	Key   string
+	Value interface{}
+}
+
+// Span is a step of a tx. A nil Span is not traced, all its
+// methods do nothing.
+type Span struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	// Parent is zero for the span of the tx
+	Parent  [8]byte
+	Name    string
+	Started time.Time
+	Ended   time.Time
+	Attrs   []Attr
+	// Err is why the step failed, empty if it did not
+	Err string
+	// Reads and Writes count the store access while the span
+	// was the innermost one
+	Reads  int64
+	Writes int64
+
+	trace  *trace
+	parent *Span
+}
+
+// SetAttr adds an attribute
+func (s *Span) SetAttr(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.Attrs = append(s.Attrs, Attr{Key: key, Value: value})
+}
+
+// SetError records err, if any, as why the step failed
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.Err = err.Error()
+}
+
+// End ends the span, the spans it started must have ended
+// before. Ending the span of the tx exports the trace.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.trace.end(s)
+}
+
+// trace collects the spans of one tx, the innermost span is
+// current. A tx runs on one goroutine, so there is no lock.
+type trace struct {
+	tracer  *Tracer
+	id      [16]byte
+	base    uint64
+	spans   []*Span
+	current *Span
+}
+
+// start starts a span inside the current one
+func (t *trace) start(name string) *Span {
+	s := &Span{
+		TraceID: t.id,
+		Name:    name,
+		Started: t.tracer.now(),
+		trace:   t,
+		parent:  t.current,
+	}
+	binary.BigEndian.PutUint64(s.SpanID[:], t.base+uint64(len(t.spans)))
+	if t.current != nil {
+		s.Parent = t.current.SpanID
+	}
+	t.spans = append(t.spans, s)
+	t.current = s
+	return s
+}
+
+func (t *trace) end(s *Span) {
+	s.Ended = t.tracer.now()
+	t.current = s.parent
+	if s.parent != nil {
+		return
+	}
+	// the spans a panic left open end with the tx
+	for _, open := range t.spans {
+		if open.Ended.IsZero() {
+			open.Ended = s.Ended
+		}
+	}
+	t.tracer.exporter.Export(t.spans)
+}
+
+func (t *trace) read() {
+	if t.current != nil {
+		t.current.Reads++
+	}
+}
+
+func (t *trace) write() {
+	if t.current != nil {
+		t.current.Writes++
+	}
+}
+
+// Begin starts a span inside the innermost span of the tx
+// that db belongs to. It returns nil if the tx is not traced.
+func Begin(db weave.ReadOnlyKVStore, name string) *Span {
+	t := traceOf(db)
+	if t == nil {
+		return nil
+	}
+	return t.start(name)
+}
+
+// Exporter sends the traces where they are looked at
+type Exporter interface {
+	// Export gets the spans of a tx once it is done, the
+	// span of the tx first. It must not block the tx.
+	Export(spans []*Span)
+}
+
+// Tracer is the decorator starting the traces, place it first
+// in the chain to see all of the tx
+type Tracer struct {
+	exporter Exporter
+	every    int64
+	now      func() time.Time
+
+	mtx sync.Mutex
+	txs int64
+}
+
+var _ weave.Decorator = (*Tracer)(nil)
+
+// NewTracer traces one in every txs, 0 or 1 for all of them,
+// and exports the traces to exp
+func NewTracer(exp Exporter, every int64) *Tracer {
+	return &Tracer{exporter: exp, every: every, now: time.Now}
+}
+
+// Check traces the tx
+func (t *Tracer) Check(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	span, db := t.begin(ctx, db, tx, "CheckTx")
+	res, err := next.Check(ctx, db, tx)
+	span.SetError(err)
+	span.End()
+	return res, err
+}
+
+// Deliver traces the tx
+func (t *Tracer) Deliver(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	span, db := t.begin(ctx, db, tx, "DeliverTx")
+	res, err := next.Deliver(ctx, db, tx)
+	span.SetError(err)
+	span.End()
+	return res, err
+}
+
+// begin starts the trace of the tx, if it is sampled, and
+// returns the store to pass on
+func (t *Tracer) begin(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	name string) (*Span, weave.KVStore) {
+
+	if !t.sampled() {
+		return nil, db
+	}
+	var ids [24]byte
+	if _, err := rand.Read(ids[:]); err != nil {
+		return nil, db
+	}
+	tr := &trace{tracer: t, base: binary.BigEndian.Uint64(ids[16:])}
+	copy(tr.id[:], ids[:16])
+	span := tr.start(name)
+	height, _ := weave.GetHeight(ctx)
+	span.SetAttr("height", height)
+	if msg, err := tx.GetMsg(); err == nil && msg != nil {
+		span.SetAttr("msg", msg.Path())
+	}
+	return span, wrap(db, tr)
+}
+
+// sampled counts the tx, it is true for one in every
+func (t *Tracer) sampled() bool {
+	if t.every <= 1 {
+		return true
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.txs++
+	return t.txs%t.every == 1
+}
+
+// Decorators returns the chain with t first and every decorator
+// in a span of its own, named after its type. Without a tracer,
+// the chain is returned as it is.
+func Decorators(t *Tracer, chain ...weave.Decorator) []weave.Decorator {
+	if t == nil {
+		return chain
+	}
+	res := []weave.Decorator{t}
+	for _, d := range chain {
+		res = append(res, decorator{name: typeName(d), next: d})
+	}
+	return res
+}
+
+// typeName is the type of v without the package path,
+// eg. "sigs.Decorator"
+func typeName(v interface{}) string {
+	return strings.TrimPrefix(fmt.Sprintf("%T", v), "*")
+}
+
+// decorator runs a decorator in a span
+type decorator struct {
+	name string
+	next weave.Decorator
+}
+
+var _ weave.Decorator = decorator{}
+
+// Check runs the decorator in a span
+func (d decorator) Check(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	span := Begin(db, d.name)
+	res, err := d.next.Check(ctx, db, tx, next)
+	span.SetError(err)
+	span.End()
+	return res, err
+}
+
+// Deliver runs the decorator in a span
+func (d decorator) Deliver(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	span := Begin(db, d.name)
+	res, err := d.next.Deliver(ctx, db, tx, next)
+	span.SetError(err)
+	span.End()
+	return res, err
+}
+
+// Handler runs h in a span named after the path of the msg.
+// Without a tracer, h is returned as it is.
+func Handler(t *Tracer, h weave.Handler) weave.Handler {
+	if t == nil {
+		return h
+	}
+	return handler{next: h}
+}
+
+// handler runs a handler in a span
+type handler struct {
+	next weave.Handler
+}
+
+var _ weave.Handler = handler{}
+
+// Check runs the handler in a span
+func (h handler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	span := Begin(db, handlerName(tx))
+	res, err := h.next.Check(ctx, db, tx)
+	span.SetError(err)
+	span.End()
+	return res, err
+}
+
+// Deliver runs the handler in a span
+func (h handler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	span := Begin(db, handlerName(tx))
+	res, err := h.next.Deliver(ctx, db, tx)
+	span.SetError(err)
+	span.End()
+	return res, err
+}
+
+// handlerName is the path of the msg, "handler" if there is none
+func handlerName(tx weave.Tx) string {
+	msg, err := tx.GetMsg()
+	if err != nil || msg == nil {
+		return "handler"
+	}
+	return msg.Path()
+}
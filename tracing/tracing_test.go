@@ -0,0 +1,141 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+	"github.com/confio/weave/x/utils"
+)
+
+// recorder keeps the exported traces
+type recorder struct {
+	traces [][]*Span
+}
+
+func (r *recorder) Export(spans []*Span) {
+	r.traces = append(r.traces, spans)
+}
+
+// stepper reads a key in a validate step, then writes one
+// through a cache wrap, or fails or panics before
+type stepper struct {
+	err   error
+	panic bool
+}
+
+func (s stepper) Check(ctx weave.Context, db weave.KVStore, tx weave.Tx) (weave.CheckResult, error) {
+	return weave.CheckResult{}, nil
+}
+
+func (s stepper) Deliver(ctx weave.Context, db weave.KVStore, tx weave.Tx) (weave.DeliverResult, error) {
+	span := Begin(db, "validate")
+	db.Get([]byte("foo"))
+	span.End()
+	if s.panic {
+		panic("boom")
+	}
+	if s.err != nil {
+		return weave.DeliverResult{}, s.err
+	}
+	cache := db.(weave.CacheableKVStore).CacheWrap()
+	cache.Set([]byte("foo"), []byte("bar"))
+	cache.Write()
+	return weave.DeliverResult{}, nil
+}
+
+func TestTracer(t *testing.T) {
+	rec := &recorder{}
+	tracer := NewTracer(rec, 0)
+	clock := time.Unix(1000, 0)
+	tracer.now = func() time.Time {
+		clock = clock.Add(time.Millisecond)
+		return clock
+	}
+	send := &cash.SendMsg{}
+	tx := x.TestHelpers{}.MockTx(send)
+	ctx := weave.WithHeight(context.Background(), 7)
+
+	h := app.ChainDecorators(Decorators(tracer, utils.NewSavepoint().OnDeliver())...).
+		WithHandler(Handler(tracer, stepper{}))
+	db := store.MemStore()
+	_, err := h.Deliver(ctx, db, tx)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("bar"), db.Get([]byte("foo")))
+
+	require.Len(t, rec.traces, 1)
+	spans := rec.traces[0]
+	var names []string
+	for _, s := range spans {
+		names = append(names, s.Name)
+		assert.Equal(t, spans[0].TraceID, s.TraceID)
+		assert.True(t, s.Ended.After(s.Started), s.Name)
+	}
+	assert.Equal(t, []string{"DeliverTx", "utils.Savepoint", send.Path(), "validate"}, names)
+	for i := 1; i < len(spans); i++ {
+		assert.Equal(t, spans[i-1].SpanID, spans[i].Parent, spans[i].Name)
+		assert.NotEqual(t, spans[i-1].SpanID, spans[i].SpanID)
+	}
+	assert.Equal(t, [8]byte{}, spans[0].Parent)
+	assert.Equal(t, []Attr{{"height", int64(7)}, {"msg", send.Path()}}, spans[0].Attrs)
+	// the write is done by the handler, through the cache wrap
+	// of the savepoint, which writes it through when done
+	assert.Equal(t, int64(1), spans[2].Writes)
+	assert.Equal(t, int64(1), spans[3].Reads)
+	assert.Equal(t, int64(0), spans[3].Writes)
+
+	// failures and panics
+	h = app.ChainDecorators(Decorators(tracer, utils.NewRecovery())...).
+		WithHandler(Handler(tracer, stepper{err: errors.New("no funds")}))
+	_, err = h.Deliver(ctx, store.MemStore(), tx)
+	require.Error(t, err)
+	require.Len(t, rec.traces, 2)
+	assert.Equal(t, "no funds", rec.traces[1][0].Err)
+	assert.Equal(t, "no funds", rec.traces[1][2].Err)
+
+	h = app.ChainDecorators(Decorators(tracer, utils.NewRecovery())...).
+		WithHandler(Handler(tracer, stepper{panic: true}))
+	_, err = h.Deliver(ctx, store.MemStore(), tx)
+	require.Error(t, err)
+	require.Len(t, rec.traces, 3)
+	spans = rec.traces[2]
+	assert.NotEmpty(t, spans[0].Err)
+	// the handler never ended, it ends with the tx
+	assert.Equal(t, spans[0].Ended, spans[2].Ended)
+}
+
+func TestSampling(t *testing.T) {
+	rec := &recorder{}
+	tracer := NewTracer(rec, 3)
+	h := app.ChainDecorators(Decorators(tracer)...).WithHandler(Handler(tracer, stepper{}))
+	tx := x.TestHelpers{}.MockTx(&cash.SendMsg{})
+	for i := 0; i < 7; i++ {
+		_, err := h.Deliver(context.Background(), store.MemStore(), tx)
+		require.NoError(t, err)
+	}
+	// the 1st, 4th and 7th
+	assert.Len(t, rec.traces, 3)
+}
+
+func TestWithoutTracer(t *testing.T) {
+	d := utils.NewSavepoint()
+	assert.Equal(t, []weave.Decorator{d}, Decorators(nil, d))
+	h := stepper{}
+	assert.Equal(t, h, Handler(nil, h))
+
+	// not traced, nothing happens
+	span := Begin(store.MemStore(), "validate")
+	assert.Nil(t, span)
+	span.SetAttr("foo", "bar")
+	span.SetError(errors.New("foo"))
+	span.End()
+}
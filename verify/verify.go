@@ -0,0 +1,95 @@
+/*
+Package verify checks the answers of a node against a trusted
+header, so a light client (eg. a mobile wallet showing the state
+of its escrows) does not need to trust the node it queries.
+
+The app answers the "/proof" query with the value of one db key
+and an iavl proof. The state after block H is proven against the
+app hash in the header of block H+1, which the client must get
+from a source it trusts, eg. by checking the signatures of the
+validators on the commit. This package only needs the header
+and does not talk to a node.
+*/
+package verify
+
+import (
+	"bytes"
+	"fmt"
+
+	abci "github.com/tendermint/abci/types"
+	"github.com/tendermint/iavl"
+
+	"github.com/confio/weave"
+
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// Value returns the value of the db key in a "/proof" query
+// response, nil if the response proves it is absent. It fails
+// unless the proof matches the app hash of the header of the
+// block after the queried height.
+func Value(header abci.Header, key []byte, res abci.ResponseQuery) ([]byte, error) {
+	if res.Code != 0 {
+		return nil, fmt.Errorf("query failed: %s", res.Log)
+	}
+	if !bytes.Equal(key, res.Key) {
+		return nil, fmt.Errorf("proof for key %X, not %X", res.Key, key)
+	}
+	if res.Height+1 != header.Height {
+		return nil, fmt.Errorf("proof of height %d needs header %d, not %d",
+			res.Height, res.Height+1, header.Height)
+	}
+	proof, err := iavl.ReadKeyProof(res.Proof)
+	if err != nil {
+		return nil, err
+	}
+	err = proof.Verify(key, res.Value, header.AppHash)
+	if err != nil {
+		return nil, err
+	}
+	return res.Value, nil
+}
+
+// WalletKey is the db key to query for the wallet of addr
+func WalletKey(addr weave.Address) []byte {
+	return namecoin.NewWalletBucket().DBKey(addr)
+}
+
+// Wallet returns the proven wallet of addr, nil if it has none
+func Wallet(header abci.Header, addr weave.Address,
+	res abci.ResponseQuery) (*namecoin.Wallet, error) {
+
+	bucket := namecoin.NewWalletBucket()
+	value, err := Value(header, bucket.DBKey(addr), res)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	obj, err := bucket.Parse(addr, value)
+	if err != nil {
+		return nil, err
+	}
+	return namecoin.AsWallet(obj), nil
+}
+
+// EscrowKey is the db key to query for the escrow with the id
+func EscrowKey(id []byte) []byte {
+	return escrow.NewBucket().DBKey(id)
+}
+
+// Escrow returns the proven escrow with the id, nil if there is
+// none, eg. as it was released
+func Escrow(header abci.Header, id []byte,
+	res abci.ResponseQuery) (*escrow.Escrow, error) {
+
+	bucket := escrow.NewBucket()
+	value, err := Value(header, bucket.DBKey(id), res)
+	if err != nil || value == nil {
+		return nil, err
+	}
+	obj, err := bucket.Parse(id, value)
+	if err != nil {
+		return nil, err
+	}
+	return escrow.AsEscrow(obj), nil
+}
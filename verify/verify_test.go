@@ -0,0 +1,92 @@
+package verify
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/app"
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestVerify(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	coins := x.Coins{&x.Coin{Whole: 10, Ticker: "FOO"}}
+
+	opts, err := namecoin.BuildGenesis([]namecoin.GenesisAccount{
+		{Address: a.Address(), Wallet: &namecoin.Wallet{Name: "alice", Coins: coins}},
+	}, []namecoin.GenesisToken{{Ticker: "FOO", Name: "Foo", SigFigs: 6}})
+	require.NoError(t, err)
+	require.NoError(t, escrow.AppendGenesis(opts,
+		&escrow.Escrow{Sender: a, Arbiter: a, Recipient: b, Timeout: 100, Amount: coins},
+	))
+	state, err := json.Marshal(opts)
+	require.NoError(t, err)
+	genesis, err := json.Marshal(map[string]json.RawMessage{
+		"chain_id":  json.RawMessage(`"test-verify"`),
+		"app_state": state,
+	})
+	require.NoError(t, err)
+
+	myApp, err := app.Application("verify", app.Stack(x.Coin{}, 0), app.TxDecoder, "", "", nil)
+	require.NoError(t, err)
+	myApp.WithInit(app.Initializer())
+	myApp.InitChainWithGenesis(abci.RequestInitChain{}, genesis)
+	myApp.BeginBlock(abci.RequestBeginBlock{Header: abci.Header{Height: 1}})
+	myApp.EndBlock(abci.RequestEndBlock{})
+	commit := myApp.Commit()
+	// the state of block 1 is proven by the header of block 2
+	header := abci.Header{Height: 2, AppHash: commit.Data}
+
+	prove := func(key []byte) abci.ResponseQuery {
+		return myApp.Query(abci.RequestQuery{Path: app.QueryProof, Data: key})
+	}
+
+	wallet, err := Wallet(header, a.Address(), prove(WalletKey(a.Address())))
+	require.NoError(t, err)
+	require.NotNil(t, wallet)
+	assert.Equal(t, "alice", wallet.Name)
+	assert.True(t, coins.Equals(wallet.Coins))
+	// absence is proven as well
+	wallet, err = Wallet(header, b.Address(), prove(WalletKey(b.Address())))
+	require.NoError(t, err)
+	assert.Nil(t, wallet)
+
+	id := escrow.SeqCondition(1)
+	esc, err := Escrow(header, id, prove(EscrowKey(id)))
+	require.NoError(t, err)
+	require.NotNil(t, esc)
+	assert.EqualValues(t, b, esc.Recipient)
+	esc, err = Escrow(header, escrow.SeqCondition(2), prove(EscrowKey(escrow.SeqCondition(2))))
+	require.NoError(t, err)
+	assert.Nil(t, esc)
+
+	// a lying node is caught
+	res := prove(WalletKey(a.Address()))
+	res.Value = append([]byte(nil), res.Value...)
+	res.Value[len(res.Value)-1]++
+	_, err = Wallet(header, a.Address(), res)
+	assert.Error(t, err)
+	// so is a proof for another key
+	_, err = Wallet(header, b.Address(), prove(WalletKey(a.Address())))
+	assert.Error(t, err)
+	// or against another header
+	_, err = Wallet(abci.Header{Height: 3, AppHash: commit.Data}, a.Address(), prove(WalletKey(a.Address())))
+	assert.Error(t, err)
+	_, err = Wallet(abci.Header{Height: 2, AppHash: []byte("other")}, a.Address(), prove(WalletKey(a.Address())))
+	assert.Error(t, err)
+
+	// failed queries are not verified
+	res = myApp.Query(abci.RequestQuery{Path: app.QueryProof})
+	assert.NotEqual(t, uint32(0), res.Code)
+	_, err = Value(header, nil, res)
+	assert.Error(t, err)
+}
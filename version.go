@@ -1,5 +1,7 @@
 package bov
 
+import "fmt"
+
 // Maj is the major version number (updated on breaking release)
 const Maj = 0
 
@@ -11,3 +13,8 @@ const Fix = 0
 
 // Version should be set by build flags: `git describe --tags`
 var Version = "please set in makefile"
+
+// SemVer returns the semantic version, eg. "0.3.0"
+func SemVer() string {
+	return fmt.Sprintf("%d.%d.%d", Maj, Min, Fix)
+}
@@ -0,0 +1,25 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// views takes 1160-1170
+const (
+	CodeInvalidViewQuery = 1160
+)
+
+var (
+	errInvalidViewQuery = fmt.Errorf("Invalid view query")
+)
+
+func ErrInvalidViewQuery(mod string) error {
+	return errors.WithLog(mod, errInvalidViewQuery, CodeInvalidViewQuery)
+}
+func IsInvalidViewQueryErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidViewQuery)
+}
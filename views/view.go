@@ -0,0 +1,129 @@
+/*
+Package views keeps materialized views of hot queries in
+memory, eg. for dashboards that poll the same questions every
+few seconds.
+
+A view is rebuilt from the committed state after every block
+and then served from memory, without touching the store. It
+is never older than the last commit, but reads the whole
+state it covers on every block, so a node only enables the
+views its clients need.
+*/
+package views
+
+import (
+	"sync"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+
+	"github.com/iov-one/bcp-demo/query"
+)
+
+// Index is a built view: the models to return for every
+// query data
+type Index map[string][]weave.Model
+
+// Builder reads the committed state into a new Index
+type Builder func(db weave.ReadOnlyKVStore) (Index, error)
+
+// View serves the last Index of its Builder as a query.
+// The query data is the key of the Index, there are no
+// modifiers.
+type View struct {
+	build Builder
+
+	mtx   sync.RWMutex
+	index Index
+}
+
+var _ weave.QueryHandler = (*View)(nil)
+
+// NewView creates an empty view, it is filled on Refresh
+func NewView(build Builder) *View {
+	return &View{build: build, index: Index{}}
+}
+
+// Refresh rebuilds the view. On error the old one is kept.
+func (v *View) Refresh(db weave.ReadOnlyKVStore) error {
+	index, err := v.build(db)
+	if err != nil {
+		return err
+	}
+	v.mtx.Lock()
+	v.index = index
+	v.mtx.Unlock()
+	return nil
+}
+
+// Query implements weave.QueryHandler. It does not read
+// db, the results are as of the last Refresh.
+func (v *View) Query(db weave.ReadOnlyKVStore, mod string,
+	data []byte) ([]weave.Model, error) {
+
+	if mod != weave.KeyQueryMod {
+		return nil, ErrInvalidViewQuery(mod)
+	}
+	v.mtx.RLock()
+	models := v.index[string(data)]
+	v.mtx.RUnlock()
+	// the index is never changed, only replaced
+	return append([]weave.Model(nil), models...), nil
+}
+
+// Set holds the views of a node by query path
+type Set struct {
+	paths []string
+	views map[string]*View
+}
+
+// NewSet creates an empty set
+func NewSet() *Set {
+	return &Set{views: make(map[string]*View)}
+}
+
+// Add serves the view under path, eg. "/views/balances".
+// It panics if the path is taken.
+func (s *Set) Add(path string, v *View) {
+	if _, ok := s.views[path]; ok {
+		panic("view registered twice: " + path)
+	}
+	s.paths = append(s.paths, path)
+	s.views[path] = v
+}
+
+// Paths lists the paths of all views, in the order added
+func (s *Set) Paths() []string {
+	return s.paths
+}
+
+// RegisterQuery adds all views to the router
+func (s *Set) RegisterQuery(qr weave.QueryRouter) {
+	for _, path := range s.paths {
+		qr.Register(path, s.views[path])
+	}
+}
+
+// Refresh rebuilds all views from db, usually right after
+// a commit. It returns the first error, but refreshes the
+// other views anyway.
+func (s *Set) Refresh(db weave.ReadOnlyKVStore) error {
+	var first error
+	for _, path := range s.paths {
+		err := s.views[path].Refresh(db)
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Scan calls fn for every model of the bucket, in key order
+func Scan(db weave.ReadOnlyKVStore, bucket orm.Bucket, fn func(weave.Model) error) error {
+	prefix := bucket.DBKey(nil)
+	page := func(cursor []byte) ([]weave.Model, error) {
+		req := query.PageRequest{Cursor: cursor}
+		return query.Page(db, prefix, req, query.DefaultPageSize)
+	}
+	return query.Stream(page, fn)
+}
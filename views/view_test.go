@@ -0,0 +1,74 @@
+package views
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/store"
+)
+
+func TestView(t *testing.T) {
+	bucket := orm.NewBucket("foo", orm.NewSimpleObj(nil, new(orm.Counter)))
+	db := store.MemStore()
+
+	var fail bool
+	// index all keys of the bucket by their first byte
+	view := NewView(func(db weave.ReadOnlyKVStore) (Index, error) {
+		if fail {
+			return nil, errors.New("broken")
+		}
+		index := Index{}
+		err := Scan(db, bucket, func(m weave.Model) error {
+			first := string(m.Key[len("foo:")])
+			index[first] = append(index[first], m)
+			return nil
+		})
+		return index, err
+	})
+	set := NewSet()
+	set.Add("/views/foo", view)
+	assert.Panics(t, func() { set.Add("/views/foo", view) })
+	assert.Equal(t, []string{"/views/foo"}, set.Paths())
+
+	qr := weave.NewQueryRouter()
+	set.RegisterQuery(qr)
+	h := qr.Handler("/views/foo")
+	require.NotNil(t, h)
+
+	for _, key := range []string{"a1", "b1", "a2"} {
+		db.Set(bucket.DBKey([]byte(key)), []byte(key))
+	}
+	// nothing until refreshed
+	models, err := h.Query(db, weave.KeyQueryMod, []byte("a"))
+	require.NoError(t, err)
+	assert.Empty(t, models)
+
+	require.NoError(t, set.Refresh(db))
+	models, err = h.Query(nil, weave.KeyQueryMod, []byte("a"))
+	require.NoError(t, err)
+	require.Len(t, models, 2)
+	assert.Equal(t, bucket.DBKey([]byte("a1")), models[0].Key)
+	assert.Equal(t, []byte("a2"), models[1].Value)
+
+	// a failed refresh keeps the old view
+	db.Delete(bucket.DBKey([]byte("a1")))
+	fail = true
+	assert.Error(t, set.Refresh(db))
+	models, err = h.Query(nil, weave.KeyQueryMod, []byte("a"))
+	require.NoError(t, err)
+	assert.Len(t, models, 2)
+
+	fail = false
+	require.NoError(t, set.Refresh(db))
+	models, err = h.Query(nil, weave.KeyQueryMod, []byte("a"))
+	require.NoError(t, err)
+	assert.Len(t, models, 1)
+
+	_, err = h.Query(nil, weave.PrefixQueryMod, []byte("a"))
+	assert.True(t, IsInvalidViewQueryErr(err))
+}
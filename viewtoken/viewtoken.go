@@ -0,0 +1,227 @@
+/*
+Package viewtoken lets the owner of an account give a third party
+(eg. an accountant) read access to the escrow history of the account
+on a gateway, without sharing a key that can spend.
+
+The owner signs a Grant with the key of the account: who it is for,
+which query paths it covers and until when. The signed Token travels
+as a string, eg. in the ViewTokenHeader of a request to the gateway.
+The gateway parses it, checking the signature, chain and expiry, and
+before answering a query asks Authorize whether the query is about
+the owner, eg. the "/txs/account" of its address or the history of
+an escrow it is a party of.
+
+The node itself answers every query, so this only restricts what a
+gateway serves, it does not hide anything on chain.
+*/
+package viewtoken
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/crypto"
+
+	"github.com/iov-one/bcp-demo/canonical"
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/txindex"
+)
+
+// ViewTokenHeader is the HTTP header a gateway reads the token from
+const ViewTokenHeader = "X-View-Token"
+
+// signPrefix separates the sign bytes of a grant from those of a
+// tx or any other document signed with the same key
+const signPrefix = "bov/view-token:"
+
+// Paths are the query paths a grant can cover, all of them are
+// about the escrows and txs of one account
+var Paths = []string{
+	"/escrows/sender",
+	"/escrows/recipient",
+	"/escrows/arbiter",
+	escrow.QueryHistory,
+	escrow.QueryExport,
+	txindex.QueryAccount,
+}
+
+// Grant is what the owner signs
+type Grant struct {
+	// ChainID is the chain the grant is valid on
+	ChainID string `json:"chain_id"`
+	// Viewer names who it is for, for the logs of the gateway
+	Viewer string `json:"viewer"`
+	// Paths are the query paths the viewer may use
+	Paths []string `json:"paths"`
+	// Expires is the unix time in seconds it ends at
+	Expires int64 `json:"expires"`
+}
+
+// Validate makes sure the grant is complete and only covers
+// known paths
+func (g Grant) Validate() error {
+	if g.ChainID == "" {
+		return fmt.Errorf("missing chain id")
+	}
+	if g.Viewer == "" {
+		return fmt.Errorf("missing viewer")
+	}
+	if g.Expires <= 0 {
+		return fmt.Errorf("missing expiry")
+	}
+	if len(g.Paths) == 0 {
+		return fmt.Errorf("no paths")
+	}
+	for _, path := range g.Paths {
+		if !contains(Paths, path) {
+			return fmt.Errorf("path %q cannot be granted", path)
+		}
+	}
+	return nil
+}
+
+// SignBytes is what the owner signs, the canonical JSON of
+// the grant after a fixed prefix
+func (g Grant) SignBytes() ([]byte, error) {
+	bz, err := canonical.Marshal(g)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(signPrefix), bz...), nil
+}
+
+// Token is a grant signed by the owner
+type Token struct {
+	Grant
+	// PubKey is the ed25519 key of the owner
+	PubKey []byte `json:"pub_key"`
+	// Signature is the ed25519 signature of the SignBytes
+	Signature []byte `json:"signature"`
+}
+
+// Issue signs the grant with the key of the owner and returns the
+// token as a string to hand to the viewer
+func Issue(owner *crypto.PrivateKey, g Grant) (string, error) {
+	err := g.Validate()
+	if err != nil {
+		return "", err
+	}
+	msg, err := g.SignBytes()
+	if err != nil {
+		return "", err
+	}
+	sig, err := owner.Sign(msg)
+	if err != nil {
+		return "", err
+	}
+	token := Token{
+		Grant:     g,
+		PubKey:    owner.PublicKey().GetEd25519(),
+		Signature: sig.GetEd25519(),
+	}
+	bz, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bz), nil
+}
+
+// Parse decodes the token and checks it is signed by its owner,
+// for the chain and not expired at now
+func Parse(s, chainID string, now time.Time) (*Token, error) {
+	bz, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var token Token
+	err = json.Unmarshal(bz, &token)
+	if err != nil {
+		return nil, err
+	}
+	err = token.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if token.ChainID != chainID {
+		return nil, fmt.Errorf("token for chain %q, not %q", token.ChainID, chainID)
+	}
+	if now.Unix() >= token.Expires {
+		return nil, fmt.Errorf("token expired")
+	}
+
+	msg, err := token.SignBytes()
+	if err != nil {
+		return nil, err
+	}
+	sig := &crypto.Signature{Sig: &crypto.Signature_Ed25519{Ed25519: token.Signature}}
+	if len(token.PubKey) != 32 || !token.pubKey().Verify(msg, sig) {
+		return nil, fmt.Errorf("invalid signature")
+	}
+	return &token, nil
+}
+
+func (t *Token) pubKey() *crypto.PublicKey {
+	return &crypto.PublicKey{Pub: &crypto.PublicKey_Ed25519{Ed25519: t.PubKey}}
+}
+
+// Owner is the permission of the key that signed the token
+func (t *Token) Owner() weave.Permission {
+	return t.pubKey().Permission()
+}
+
+// EscrowParties returns the addresses of the parties of the escrow
+// with the id, nil if the gateway cannot tell. A gateway can read
+// them from a proven "/escrows" query (see verify.Escrow) while the
+// escrow is open, or keep them from its create tx.
+type EscrowParties func(id []byte) ([]weave.Address, error)
+
+// Authorize returns nil if the token allows the query, that is
+// the path is granted and the query only covers the owner
+func (t *Token) Authorize(path, mod string, data []byte, parties EscrowParties) error {
+	if !contains(t.Paths, path) {
+		return fmt.Errorf("path %q not granted", path)
+	}
+	owner := t.Owner()
+	switch path {
+	case txindex.QueryAccount:
+		// the address, or a cursor in its history
+		addr := owner.Address()
+		prefix := txindex.NewHistoryBucket().DBKey(addr)
+		if bytes.Equal(data, addr) || bytes.HasPrefix(data, prefix) {
+			return nil
+		}
+	case escrow.QueryHistory, escrow.QueryExport:
+		if mod != weave.KeyQueryMod {
+			break
+		}
+		addrs, err := parties(data)
+		if err != nil {
+			return err
+		}
+		for _, addr := range addrs {
+			if owner.Address().Equals(addr) {
+				return nil
+			}
+		}
+	default:
+		// the indexes of the escrows by party, a prefix query
+		// would cover other accounts
+		if mod == weave.KeyQueryMod && bytes.Equal(data, owner) {
+			return nil
+		}
+	}
+	return fmt.Errorf("query on %s not about the owner", path)
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
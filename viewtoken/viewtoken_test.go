@@ -0,0 +1,124 @@
+package viewtoken
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/crypto"
+
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/txindex"
+)
+
+func TestIssueAndParse(t *testing.T) {
+	owner := crypto.GenPrivKeyEd25519()
+	now := time.Unix(1000, 0)
+	grant := Grant{
+		ChainID: "test-chain",
+		Viewer:  "accountant",
+		Paths:   []string{escrow.QueryHistory},
+		Expires: 2000,
+	}
+	s, err := Issue(owner, grant)
+	require.NoError(t, err)
+
+	token, err := Parse(s, "test-chain", now)
+	require.NoError(t, err)
+	assert.Equal(t, grant, token.Grant)
+	assert.Equal(t, owner.PublicKey().Permission(), token.Owner())
+
+	_, err = Parse(s, "other-chain", now)
+	assert.Error(t, err)
+	_, err = Parse(s, "test-chain", time.Unix(2000, 0))
+	assert.Error(t, err)
+	_, err = Parse(s[:len(s)-2], "test-chain", now)
+	assert.Error(t, err)
+
+	// a grant changed after signing
+	token.Paths = Paths
+	other := crypto.GenPrivKeyEd25519()
+	msg, err := token.SignBytes()
+	require.NoError(t, err)
+	sig, err := other.Sign(msg)
+	require.NoError(t, err)
+	_, err = Parse(encode(t, token), "test-chain", now)
+	assert.Error(t, err)
+	// or signed by another key
+	token.Signature = sig.GetEd25519()
+	_, err = Parse(encode(t, token), "test-chain", now)
+	assert.Error(t, err)
+
+	bad := grant
+	bad.Paths = []string{"/wallets"}
+	_, err = Issue(owner, bad)
+	assert.Error(t, err)
+}
+
+func TestAuthorize(t *testing.T) {
+	owner := crypto.GenPrivKeyEd25519()
+	perm := owner.PublicKey().Permission()
+	addr := perm.Address()
+	other := weave.NewAddress([]byte("other"))
+
+	s, err := Issue(owner, Grant{ChainID: "test", Viewer: "v", Paths: Paths, Expires: 2000})
+	require.NoError(t, err)
+	token, err := Parse(s, "test", time.Unix(1000, 0))
+	require.NoError(t, err)
+
+	parties := func(id []byte) ([]weave.Address, error) {
+		if string(id) == "mine" {
+			return []weave.Address{other, addr}, nil
+		}
+		return []weave.Address{other}, nil
+	}
+	cursor := append(txindex.NewHistoryBucket().DBKey(addr), 0, 0, 0, 1)
+
+	cases := map[string]struct {
+		path, mod string
+		data      []byte
+		allowed   bool
+	}{
+		"own txs":        {txindex.QueryAccount, "", addr, true},
+		"own cursor":     {txindex.QueryAccount, "", cursor, true},
+		"other txs":      {txindex.QueryAccount, "", other, false},
+		"own history":    {escrow.QueryHistory, weave.KeyQueryMod, []byte("mine"), true},
+		"other history":  {escrow.QueryHistory, weave.KeyQueryMod, []byte("theirs"), false},
+		"own export":     {escrow.QueryExport, weave.KeyQueryMod, []byte("mine"), true},
+		"as sender":      {"/escrows/sender", weave.KeyQueryMod, perm, true},
+		"as arbiter":     {"/escrows/arbiter", weave.KeyQueryMod, perm, true},
+		"prefix of own":  {"/escrows/sender", weave.PrefixQueryMod, perm[:4], false},
+		"other sender":   {"/escrows/sender", weave.KeyQueryMod, other, false},
+		"not granted":    {"/wallets", "", addr, false},
+		"no index query": {"/escrows", weave.KeyQueryMod, perm, false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := token.Authorize(tc.path, tc.mod, tc.data, parties)
+			if tc.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+
+	// only the granted paths
+	s, err = Issue(owner, Grant{ChainID: "test", Viewer: "v",
+		Paths: []string{txindex.QueryAccount}, Expires: 2000})
+	require.NoError(t, err)
+	token, err = Parse(s, "test", time.Unix(1000, 0))
+	require.NoError(t, err)
+	assert.Error(t, token.Authorize(escrow.QueryHistory, weave.KeyQueryMod, []byte("mine"), parties))
+}
+
+func encode(t *testing.T, token *Token) string {
+	bz, err := json.Marshal(token)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(bz)
+}
@@ -0,0 +1,25 @@
+package anymsg
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// anymsg takes 1140-1150
+const (
+	CodeUnknownType = 1140
+)
+
+var (
+	errUnknownType = fmt.Errorf("Unknown message type")
+)
+
+func ErrUnknownType(url string) error {
+	return errors.WithLog(url, errUnknownType, CodeUnknownType)
+}
+func IsUnknownTypeErr(err error) bool {
+	return errors.HasErrorCode(err, CodeUnknownType)
+}
@@ -0,0 +1,75 @@
+/*
+Package anymsg lets a tx carry any registered message in an Any,
+instead of a field of its own in the oneof of the app Tx.
+
+Messages are registered once with Register, which derives their
+type URL from the protobuf name. Unpack looks the type up and
+decodes the value, and the message is then routed by its own
+Path method as usual. The Any is only another encoding of the
+messages of the app, it does not change their routes.
+*/
+package anymsg
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/confio/weave"
+	"github.com/gogo/protobuf/proto"
+)
+
+// Msg is a weave.Msg generated by protobuf
+type Msg interface {
+	weave.Msg
+	proto.Message
+}
+
+var registry = map[string]reflect.Type{}
+
+// Register makes the messages known to Unpack.
+// It panics if a type is registered twice.
+func Register(msgs ...Msg) {
+	for _, msg := range msgs {
+		url := TypeURL(msg)
+		if _, ok := registry[url]; ok {
+			panic("anymsg: duplicate type " + url)
+		}
+		registry[url] = reflect.TypeOf(msg).Elem()
+	}
+}
+
+// TypeURL returns "/" followed by the protobuf name of msg
+func TypeURL(msg proto.Message) string {
+	return "/" + proto.MessageName(msg)
+}
+
+// Pack wraps a registered message in an Any
+func Pack(msg Msg) (*Any, error) {
+	url := TypeURL(msg)
+	if _, ok := registry[url]; !ok {
+		return nil, ErrUnknownType(url)
+	}
+	bz, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &Any{TypeUrl: url, Value: bz}, nil
+}
+
+// Unpack decodes the message held in the Any. Like other
+// protobuf libraries, it only looks at the type URL after the
+// last "/", so "type.googleapis.com/escrow.CreateEscrowMsg"
+// works as well.
+func (a *Any) Unpack() (weave.Msg, error) {
+	name := a.TypeUrl[strings.LastIndex(a.TypeUrl, "/")+1:]
+	typ, ok := registry["/"+name]
+	if !ok {
+		return nil, ErrUnknownType(a.TypeUrl)
+	}
+	msg := reflect.New(typ).Interface().(Msg)
+	err := proto.Unmarshal(a.Value, msg)
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
@@ -0,0 +1,48 @@
+package anymsg
+
+import (
+	"testing"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	msg := &cash.SendMsg{
+		Src:    weave.NewAddress([]byte("alice")),
+		Dest:   weave.NewAddress([]byte("bob")),
+		Amount: &x.Coin{Whole: 10, Ticker: "IOV"},
+		Memo:   "lunch",
+	}
+
+	// only registered types are packed
+	_, err := Pack(msg)
+	assert.True(t, IsUnknownTypeErr(err), "%+v", err)
+
+	Register(msg)
+	assert.Panics(t, func() { Register(&cash.SendMsg{}) })
+
+	any, err := Pack(msg)
+	require.NoError(t, err)
+	assert.Equal(t, "/cash.SendMsg", any.TypeUrl)
+	got, err := any.Unpack()
+	require.NoError(t, err)
+	assert.Equal(t, msg, got)
+
+	// the round trip goes through the wire format
+	bz, err := any.Marshal()
+	require.NoError(t, err)
+	var other Any
+	require.NoError(t, other.Unmarshal(bz))
+	other.TypeUrl = "type.googleapis.com/cash.SendMsg"
+	got, err = other.Unpack()
+	require.NoError(t, err)
+	assert.Equal(t, msg, got)
+
+	other.TypeUrl = "/cash.FeeInfo"
+	_, err = other.Unpack()
+	assert.True(t, IsUnknownTypeErr(err), "%+v", err)
+}
@@ -0,0 +1,34 @@
+package chainaddr
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// chainaddr takes 1230-1240
+const (
+	CodeUnknownChain   = 1230
+	CodeInvalidAddress = 1231
+)
+
+var (
+	errUnknownChain   = fmt.Errorf("No address format for chain")
+	errInvalidAddress = fmt.Errorf("Invalid foreign address")
+)
+
+func ErrUnknownChain(chainID string) error {
+	return errors.WithLog(chainID, errUnknownChain, CodeUnknownChain)
+}
+func IsUnknownChainErr(err error) bool {
+	return errors.HasErrorCode(err, CodeUnknownChain)
+}
+
+func ErrInvalidAddress(reason string) error {
+	return errors.WithLog(reason, errInvalidAddress, CodeInvalidAddress)
+}
+func IsInvalidAddressErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidAddress)
+}
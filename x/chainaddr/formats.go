@@ -0,0 +1,97 @@
+package chainaddr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"strings"
+
+	"github.com/iov-one/bcp-demo/bech32"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Hex accepts "0x" followed by size bytes in hex, like Ethereum
+// addresses. The mixed case checksum of EIP-55 is not verified.
+func Hex(size int) Validator {
+	return func(addr string) error {
+		if !strings.HasPrefix(addr, "0x") {
+			return ErrInvalidAddress("no 0x prefix")
+		}
+		bz, err := hex.DecodeString(addr[2:])
+		if err != nil {
+			return ErrInvalidAddress("not hex")
+		}
+		if len(bz) != size {
+			return ErrInvalidAddress("length")
+		}
+		return nil
+	}
+}
+
+// Bech32 accepts bech32 strings with the prefix holding size
+// bytes, like Cosmos addresses
+func Bech32(prefix string, size int) Validator {
+	return func(addr string) error {
+		hrp, bz, err := bech32.Decode(addr)
+		if err != nil {
+			return ErrInvalidAddress(err.Error())
+		}
+		if hrp != prefix {
+			return ErrInvalidAddress("prefix " + hrp)
+		}
+		if len(bz) != size {
+			return ErrInvalidAddress("length")
+		}
+		return nil
+	}
+}
+
+// Base58Check accepts base58 strings of a version byte, size
+// bytes and a checksum of four, like Bitcoin addresses. The
+// version must be one of versions.
+func Base58Check(size int, versions ...byte) Validator {
+	return func(addr string) error {
+		bz, ok := decodeBase58(addr)
+		if !ok {
+			return ErrInvalidAddress("not base58")
+		}
+		if len(bz) != 1+size+4 {
+			return ErrInvalidAddress("length")
+		}
+		payload, check := bz[:1+size], bz[1+size:]
+		first := sha256.Sum256(payload)
+		second := sha256.Sum256(first[:])
+		if !bytes.Equal(second[:4], check) {
+			return ErrInvalidAddress("checksum")
+		}
+		if bytes.IndexByte(versions, payload[0]) < 0 {
+			return ErrInvalidAddress("version")
+		}
+		return nil
+	}
+}
+
+// decodeBase58 returns the bytes of s, every leading "1" is a
+// zero byte
+func decodeBase58(s string) ([]byte, bool) {
+	if s == "" {
+		return nil, false
+	}
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(base58Alphabet, s[i])
+		if d < 0 {
+			return nil, false
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(d)))
+	}
+	zeros := 0
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), n.Bytes()...), true
+}
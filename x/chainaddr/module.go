@@ -0,0 +1,21 @@
+package chainaddr
+
+import (
+	"github.com/iov-one/bcp-demo/module"
+)
+
+// Module declares the schema of the addresses of other
+// chains, the app registers their formats
+type Module struct{}
+
+var _ module.Module = Module{}
+
+// Name fulfils module.Module
+func (Module) Name() string {
+	return "chainaddr"
+}
+
+// Version fulfils module.Module
+func (Module) Version() uint32 {
+	return 1
+}
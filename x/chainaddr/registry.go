@@ -0,0 +1,54 @@
+/*
+Package chainaddr validates addresses of other chains, so a message
+that refers to one, eg. the target of a username or the subject of
+an oracle report, is rejected when it is submitted rather than
+failing later where the address is used.
+
+Each chain has a CAIP-2 id, eg. "eip155:1" for Ethereum, and a
+Validator for the text form of its addresses. Chains are registered
+once with Register, usually by the app on start, and a message
+carries a ForeignAddress whose Validate looks the format up.
+Hex, Bech32 and Base58Check cover most chains.
+*/
+package chainaddr
+
+import (
+	"regexp"
+)
+
+// Validator returns an error if addr is not a well formed
+// address of a chain
+type Validator func(addr string) error
+
+// chainID is a CAIP-2 chain id, "<namespace>:<reference>"
+var chainID = regexp.MustCompile(`^[-a-z0-9]{3,8}:[-_a-zA-Z0-9]{1,32}$`)
+
+var registry = map[string]Validator{}
+
+// Register sets the address format of a chain. It panics if
+// the chain id is malformed or registered twice.
+func Register(id string, v Validator) {
+	if !chainID.MatchString(id) {
+		panic("chainaddr: invalid chain id " + id)
+	}
+	if _, ok := registry[id]; ok {
+		panic("chainaddr: duplicate chain " + id)
+	}
+	registry[id] = v
+}
+
+// Validate returns an error unless addr is an address of the
+// registered chain
+func Validate(id, addr string) error {
+	v, ok := registry[id]
+	if !ok {
+		return ErrUnknownChain(id)
+	}
+	return v(addr)
+}
+
+// Validate ensures the address is of a registered chain and in
+// its format
+func (a *ForeignAddress) Validate() error {
+	return Validate(a.ChainId, a.Address)
+}
@@ -0,0 +1,64 @@
+package chainaddr
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/iov-one/bcp-demo/bech32"
+)
+
+func TestFormats(t *testing.T) {
+	eth := Hex(20)
+	btc := Base58Check(20, 0x00, 0x05)
+	atom := Bech32("cosmos", 20)
+	twenty := bytes.Repeat([]byte{7}, 20)
+
+	cases := map[string]struct {
+		v     Validator
+		addr  string
+		valid bool
+	}{
+		"eth":           {eth, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true},
+		"eth lowercase": {eth, "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", true},
+		"eth short":     {eth, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", false},
+		"eth no 0x":     {eth, "5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", false},
+		"eth not hex":   {eth, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeg", false},
+		"btc p2pkh":     {btc, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", true},
+		"btc p2sh":      {btc, "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", true},
+		"btc checksum":  {btc, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", false},
+		"btc character": {btc, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfN0", false},
+		"btc empty":     {btc, "", false},
+		"btc version":   {Base58Check(20, 0x6f), "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", false},
+		"atom":          {atom, bech32.Encode("cosmos", twenty), true},
+		"atom prefix":   {atom, bech32.Encode("osmo", twenty), false},
+		"atom length":   {atom, bech32.Encode("cosmos", twenty[:19]), false},
+		"atom typo":     {atom, "cosmos1qypqxpq9qcrsszg2pvxq6rs0zqg3yyc5lzv7xv", false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.v(tc.addr)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, IsInvalidAddressErr(err), "%+v", err)
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	Register("test:hex", Hex(4))
+	assert.Panics(t, func() { Register("test:hex", Hex(20)) })
+	assert.Panics(t, func() { Register("no chain", Hex(20)) })
+
+	assert.NoError(t, Validate("test:hex", "0x01020304"))
+	assert.True(t, IsInvalidAddressErr(Validate("test:hex", "0x0102")))
+	assert.True(t, IsUnknownChainErr(Validate("test:other", "0x01020304")))
+
+	addr := &ForeignAddress{ChainId: "test:hex", Address: "0xdeadbeef"}
+	assert.NoError(t, addr.Validate())
+	addr.ChainId = ""
+	assert.True(t, IsUnknownChainErr(addr.Validate()))
+}
@@ -0,0 +1,65 @@
+package confidential
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// confidential takes 1200-1210
+const (
+	CodeInvalidCommitment = 1200
+	CodeInvalidProof      = 1201
+	CodeInvalidDisclosure = 1202
+	CodeNoEscrow          = 1203
+	CodeInvalidEscrow     = 1204
+)
+
+var (
+	errInvalidCommitment = fmt.Errorf("Invalid commitment")
+	errInvalidProof      = fmt.Errorf("Invalid range proof")
+	errInvalidViewKey    = fmt.Errorf("Invalid view key")
+	errInvalidDisclosure = fmt.Errorf("Invalid disclosure")
+	errNoSuchEscrow      = fmt.Errorf("No confidential escrow with this ID")
+	errInvalidEscrow     = fmt.Errorf("Invalid confidential escrow")
+)
+
+func ErrInvalidCommitment(reason string) error {
+	return errors.WithLog(reason, errInvalidCommitment, CodeInvalidCommitment)
+}
+func IsInvalidCommitmentErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidCommitment)
+}
+
+func ErrInvalidProof(reason string) error {
+	return errors.WithLog(reason, errInvalidProof, CodeInvalidProof)
+}
+func IsInvalidProofErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidProof)
+}
+
+func ErrInvalidViewKey() error {
+	return errors.WithCode(errInvalidViewKey, CodeInvalidDisclosure)
+}
+func ErrInvalidDisclosure(reason string) error {
+	return errors.WithLog(reason, errInvalidDisclosure, CodeInvalidDisclosure)
+}
+func IsInvalidDisclosureErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidDisclosure)
+}
+
+func ErrNoSuchEscrow(id []byte) error {
+	return errors.WithLog(fmt.Sprintf("%X", id), errNoSuchEscrow, CodeNoEscrow)
+}
+func IsNoSuchEscrowErr(err error) bool {
+	return errors.HasErrorCode(err, CodeNoEscrow)
+}
+
+func ErrInvalidEscrow(reason string) error {
+	return errors.WithLog(reason, errInvalidEscrow, CodeInvalidEscrow)
+}
+func IsInvalidEscrowErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidEscrow)
+}
@@ -0,0 +1,628 @@
+package confidential
+
+import (
+	"math/big"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+const (
+	// verifying a range proof takes a few hundred curve
+	// multiplications
+	proofCost int64 = 500
+
+	shieldCost  int64 = 50
+	viewKeyCost int64 = 10
+	createCost  int64 = 300
+	releaseCost int64 = 0
+	returnCost  int64 = 0
+	grantCost   int64 = 50
+)
+
+// RegisterRoutes will instantiate and register
+// all handlers in this package
+func RegisterRoutes(r weave.Registry, auth x.Authenticator,
+	control namecoin.Controller) {
+
+	balances := NewBalanceBucket()
+	keys := NewViewKeyBucket()
+	escrows := NewEscrowBucket()
+	r.Handle(pathShieldMsg, ShieldHandler{auth, balances, control})
+	r.Handle(pathUnshieldMsg, UnshieldHandler{auth, balances, control})
+	r.Handle(pathSetViewKeyMsg, SetViewKeyHandler{auth, keys})
+	r.Handle(pathCreateEscrowMsg, CreateEscrowHandler{auth, balances, keys, escrows})
+	r.Handle(pathReleaseEscrowMsg, ReleaseEscrowHandler{auth, balances, escrows})
+	r.Handle(pathReturnEscrowMsg, ReturnEscrowHandler{auth, balances, escrows})
+	r.Handle(pathGrantViewMsg, GrantViewHandler{auth, keys, escrows})
+}
+
+// RegisterQuery will register the balances as
+// "/confidential/balances", the view keys as
+// "/confidential/viewkeys" and the escrows as
+// "/confidential/escrows"
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBalanceBucket().Register("confidential/balances", qr)
+	NewViewKeyBucket().Register("confidential/viewkeys", qr)
+	NewEscrowBucket().Register("confidential/escrows", qr)
+}
+
+// mainSigner returns the address of the main signer, or
+// fails if there is none
+func mainSigner(ctx weave.Context, auth x.Authenticator) (weave.Address, error) {
+	signer := x.MainSigner(ctx, auth)
+	if signer == nil {
+		return nil, errors.ErrUnauthorized()
+	}
+	return signer.Address(), nil
+}
+
+//---- shield
+
+// ShieldHandler moves coins into the shielded balance
+// of the signer
+type ShieldHandler struct {
+	auth     x.Authenticator
+	balances BalanceBucket
+	cash     namecoin.Controller
+}
+
+var _ weave.Handler = ShieldHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h ShieldHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += shieldCost
+	return res, nil
+}
+
+// Deliver moves the coins to the pool and adds a commitment
+// to them without blinding to the balance
+func (h ShieldHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, owner, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	err = h.cash.WithContext(ctx).MoveCoins(db, owner, Pool, *msg.Amount)
+	if err != nil {
+		return res, err
+	}
+	units, _ := Units(*msg.Amount)
+	c := commit(units, new(big.Int))
+	return res, h.balances.add(db, owner, msg.Amount.Ticker, c)
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h ShieldHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*ShieldMsg, weave.Address, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*ShieldMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+	owner, err := mainSigner(ctx, h.auth)
+	return msg, owner, err
+}
+
+//---- unshield
+
+// UnshieldHandler pays coins from the shielded balance
+// of the signer
+type UnshieldHandler struct {
+	auth     x.Authenticator
+	balances BalanceBucket
+	cash     namecoin.Controller
+}
+
+var _ weave.Handler = UnshieldHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h UnshieldHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += shieldCost + proofCost
+	return res, nil
+}
+
+// Deliver takes the amount from the balance and pays it
+// from the pool
+func (h UnshieldHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, owner, paid, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	err = h.balances.add(db, owner, msg.Amount.Ticker, paid.neg())
+	if err != nil {
+		return res, err
+	}
+	return res, h.cash.WithContext(ctx).MoveCoins(db, Pool, owner, *msg.Amount)
+}
+
+// validate does all common pre-processing between Check and
+// Deliver, and returns the commitment to the amount paid
+func (h UnshieldHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*UnshieldMsg, weave.Address, point, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, point{}, err
+	}
+	msg, ok := rmsg.(*UnshieldMsg)
+	if !ok {
+		return nil, nil, point{}, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, point{}, err
+	}
+	owner, err := mainSigner(ctx, h.auth)
+	if err != nil {
+		return nil, nil, point{}, err
+	}
+
+	balance, err := h.balances.commitment(db, owner, msg.Amount.Ticker)
+	if err != nil {
+		return nil, nil, point{}, err
+	}
+	units, _ := Units(*msg.Amount)
+	paid := commit(units, new(big.Int))
+	err = VerifyRange(balance.sub(paid).Bytes(), msg.Proof)
+	if err != nil {
+		return nil, nil, point{}, err
+	}
+	return msg, owner, paid, nil
+}
+
+//---- view keys
+
+// SetViewKeyHandler stores the view key of the signer
+type SetViewKeyHandler struct {
+	auth x.Authenticator
+	keys ViewKeyBucket
+}
+
+var _ weave.Handler = SetViewKeyHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h SetViewKeyHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += viewKeyCost
+	return res, nil
+}
+
+// Deliver replaces the view key of the signer
+func (h SetViewKeyHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, owner, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	return res, h.keys.Save(db, orm.NewSimpleObj(owner, &ViewKey{Key: msg.Key}))
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h SetViewKeyHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*SetViewKeyMsg, weave.Address, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*SetViewKeyMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+	owner, err := mainSigner(ctx, h.auth)
+	return msg, owner, err
+}
+
+//---- create
+
+// CreateEscrowHandler moves a commitment from the balance
+// of the sender to a new escrow
+type CreateEscrowHandler struct {
+	auth     x.Authenticator
+	balances BalanceBucket
+	keys     ViewKeyBucket
+	escrows  EscrowBucket
+}
+
+var _ weave.Handler = CreateEscrowHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h CreateEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += createCost + 2*proofCost
+	return res, nil
+}
+
+// Deliver stores the escrow and returns its id as data
+func (h CreateEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, sender, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	c, _ := decodePoint(msg.Commitment)
+	err = h.balances.add(db, sender, msg.Ticker, c.neg())
+	if err != nil {
+		return res, err
+	}
+	obj, err := h.escrows.Create(db, &Escrow{
+		Sender:      sender,
+		Arbiter:     msg.Arbiter,
+		Recipient:   msg.Recipient,
+		Ticker:      msg.Ticker,
+		Timeout:     msg.Timeout,
+		Commitment:  msg.Commitment,
+		Disclosures: msg.Disclosures,
+	})
+	if err != nil {
+		return res, err
+	}
+	res.Data = obj.Key()
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and
+// Deliver, including both range proofs
+func (h CreateEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*CreateEscrowMsg, weave.Address, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*CreateEscrowMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+	sender, err := mainSigner(ctx, h.auth)
+	if err != nil {
+		return nil, nil, err
+	}
+	height, _ := weave.GetHeight(ctx)
+	if msg.Timeout <= height {
+		return nil, nil, ErrInvalidEscrow("already expired")
+	}
+	for _, d := range msg.Disclosures {
+		if err := h.checkViewer(db, d); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err = VerifyRange(msg.Commitment, msg.Proof)
+	if err != nil {
+		return nil, nil, err
+	}
+	balance, err := h.balances.commitment(db, sender, msg.Ticker)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, _ := decodePoint(msg.Commitment)
+	err = VerifyRange(balance.sub(c).Bytes(), msg.ChangeProof)
+	if err != nil {
+		return nil, nil, err
+	}
+	return msg, sender, nil
+}
+
+// checkViewer makes sure the viewer has a view key
+func (h CreateEscrowHandler) checkViewer(db weave.ReadOnlyKVStore, d *Disclosure) error {
+	return checkViewer(h.keys, db, d)
+}
+
+func checkViewer(keys ViewKeyBucket, db weave.ReadOnlyKVStore, d *Disclosure) error {
+	ok, err := keys.Has(db, d.Viewer)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidDisclosure("viewer has no view key")
+	}
+	return nil
+}
+
+//---- release
+
+// ReleaseEscrowHandler adds the escrow to the balance
+// of the recipient
+type ReleaseEscrowHandler struct {
+	auth     x.Authenticator
+	balances BalanceBucket
+	escrows  EscrowBucket
+}
+
+var _ weave.Handler = ReleaseEscrowHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h ReleaseEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += releaseCost
+	return res, nil
+}
+
+// Deliver pays the recipient and deletes the escrow
+func (h ReleaseEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	return res, settle(db, h.balances, h.escrows, obj, AsEscrow(obj).Recipient)
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h ReleaseEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (orm.Object, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*ReleaseEscrowMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	obj, escrow, err := loadEscrow(db, h.escrows, msg.EscrowId)
+	if err != nil {
+		return nil, err
+	}
+	if !h.auth.HasAddress(ctx, escrow.Arbiter) {
+		return nil, errors.ErrUnauthorized()
+	}
+	height, _ := weave.GetHeight(ctx)
+	if escrow.Timeout <= height {
+		return nil, ErrInvalidEscrow("expired")
+	}
+	return obj, nil
+}
+
+//---- return
+
+// ReturnEscrowHandler adds the escrow back to the balance
+// of the sender
+type ReturnEscrowHandler struct {
+	auth     x.Authenticator
+	balances BalanceBucket
+	escrows  EscrowBucket
+}
+
+var _ weave.Handler = ReturnEscrowHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h ReturnEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += returnCost
+	return res, nil
+}
+
+// Deliver refunds the sender and deletes the escrow
+func (h ReturnEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	return res, settle(db, h.balances, h.escrows, obj, AsEscrow(obj).Sender)
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h ReturnEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (orm.Object, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*ReturnEscrowMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	obj, escrow, err := loadEscrow(db, h.escrows, msg.EscrowId)
+	if err != nil {
+		return nil, err
+	}
+	// the recipient may refund before the timeout
+	height, _ := weave.GetHeight(ctx)
+	if escrow.Timeout > height && !h.auth.HasAddress(ctx, escrow.Recipient) {
+		return nil, errors.ErrUnauthorized()
+	}
+	return obj, nil
+}
+
+//---- grant
+
+// GrantViewHandler adds a disclosure to an escrow
+type GrantViewHandler struct {
+	auth    x.Authenticator
+	keys    ViewKeyBucket
+	escrows EscrowBucket
+}
+
+var _ weave.Handler = GrantViewHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h GrantViewHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += grantCost
+	return res, nil
+}
+
+// Deliver adds the disclosure to the escrow
+func (h GrantViewHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	escrow := AsEscrow(obj)
+	escrow.Disclosures = append(escrow.Disclosures, msg.Disclosure)
+	return res, h.escrows.Save(db, obj)
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h GrantViewHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*GrantViewMsg, orm.Object, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*GrantViewMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+	obj, escrow, err := loadEscrow(db, h.escrows, msg.EscrowId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !h.auth.HasAddress(ctx, escrow.Sender) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	err = checkViewer(h.keys, db, msg.Disclosure)
+	if err != nil {
+		return nil, nil, err
+	}
+	// at most one per viewer, and not too many
+	list := append(append([]*Disclosure(nil), escrow.Disclosures...), msg.Disclosure)
+	err = validateDisclosures(list)
+	if err != nil {
+		return nil, nil, err
+	}
+	return msg, obj, nil
+}
+
+//---- helpers
+
+// loadEscrow returns the escrow with the id, or fails if
+// there is none
+func loadEscrow(db weave.ReadOnlyKVStore, bucket EscrowBucket,
+	id []byte) (orm.Object, *Escrow, error) {
+
+	obj, err := bucket.Get(db, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	escrow := AsEscrow(obj)
+	if escrow == nil {
+		return nil, nil, ErrNoSuchEscrow(id)
+	}
+	return obj, escrow, nil
+}
+
+// settle adds the commitment of the escrow to the balance
+// of addr and deletes the escrow
+func settle(db weave.KVStore, balances BalanceBucket, escrows EscrowBucket,
+	obj orm.Object, addr weave.Address) error {
+
+	escrow := AsEscrow(obj)
+	c, err := decodePoint(escrow.Commitment)
+	if err != nil {
+		return err
+	}
+	err = balances.add(db, addr, escrow.Ticker, c)
+	if err != nil {
+		return err
+	}
+	return escrows.Delete(db, obj.Key())
+}
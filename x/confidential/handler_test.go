@@ -0,0 +1,182 @@
+package confidential
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/features"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestConfidentialEscrow(t *testing.T) {
+	var helpers x.TestHelpers
+	_, sender := helpers.MakeKey()
+	_, arbiter := helpers.MakeKey()
+	_, recipient := helpers.MakeKey()
+	_, auditor := helpers.MakeKey()
+
+	auth := helpers.CtxAuth("auth")
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, auth, control)
+
+	db := store.MemStore()
+	wallet, err := cash.WalletWith(sender.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, wallet))
+
+	deliver := func(height int64, signer weave.Permission, msg weave.Msg) ([]byte, error) {
+		ctx := auth.SetPermissions(weave.WithHeight(context.Background(), height), signer)
+		tx := helpers.MockTx(msg)
+		_, err := r.Check(ctx, db, tx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := r.Deliver(ctx, db, tx)
+		return res.Data, err
+	}
+	balance := func(addr weave.Address) x.Coins {
+		coins, err := control.Balance(db, addr)
+		require.NoError(t, err)
+		return coins
+	}
+	foo := func(whole int64) *x.Coin {
+		c := x.NewCoin(whole, 0, "FOO")
+		return &c
+	}
+	prove := func(o *Opening) []byte {
+		proof, err := ProveRange(o)
+		require.NoError(t, err)
+		return proof
+	}
+
+	// shielding moves the coins to the pool
+	_, err = deliver(1, sender, &ShieldMsg{Amount: foo(40)})
+	require.NoError(t, err)
+	assert.Equal(t, x.Coins{foo(60)}, balance(sender.Address()))
+	assert.Equal(t, x.Coins{foo(40)}, balance(Pool))
+	held := &Opening{Amount: 40 * fracUnit}
+
+	// viewers need a key
+	pub, priv, err := GenerateViewKey()
+	require.NoError(t, err)
+	escrowed, err := NewOpening(30 * fracUnit)
+	require.NoError(t, err)
+	sealed, err := Seal(pub[:], escrowed)
+	require.NoError(t, err)
+	create := &CreateEscrowMsg{
+		Arbiter:     arbiter.Address(),
+		Recipient:   recipient.Address(),
+		Ticker:      "FOO",
+		Timeout:     20,
+		Commitment:  Commit(escrowed),
+		Proof:       prove(escrowed),
+		ChangeProof: prove(held.Subtract(escrowed)),
+		Disclosures: []*Disclosure{{Viewer: recipient.Address(), Sealed: sealed}},
+	}
+	_, err = deliver(2, sender, create)
+	assert.True(t, IsInvalidDisclosureErr(err), "%+v", err)
+	_, err = deliver(2, recipient, &SetViewKeyMsg{Key: pub[:]})
+	require.NoError(t, err)
+
+	// the change must not be negative
+	tooMuch, err := NewOpening(41 * fracUnit)
+	require.NoError(t, err)
+	bad := *create
+	bad.Commitment = Commit(tooMuch)
+	bad.Proof = prove(tooMuch)
+	bad.ChangeProof = prove(held.Subtract(tooMuch))
+	_, err = deliver(2, sender, &bad)
+	assert.True(t, IsInvalidProofErr(err), "%+v", err)
+	// nor can the proof be for another commitment
+	bad = *create
+	bad.Proof = prove(tooMuch)
+	_, err = deliver(2, sender, &bad)
+	assert.True(t, IsInvalidProofErr(err), "%+v", err)
+
+	id, err := deliver(2, sender, create)
+	require.NoError(t, err)
+	obj, err := NewEscrowBucket().Get(db, id)
+	require.NoError(t, err)
+	opened, err := Unseal(priv, AsEscrow(obj).Disclosures[0].Sealed)
+	require.NoError(t, err)
+	assert.Equal(t, escrowed, opened)
+
+	// only the sender grants access, to a viewer with a key
+	apub, _, err := GenerateViewKey()
+	require.NoError(t, err)
+	sealed, err = Seal(apub[:], escrowed)
+	require.NoError(t, err)
+	grant := &GrantViewMsg{EscrowId: id, Disclosure: &Disclosure{Viewer: auditor.Address(), Sealed: sealed}}
+	_, err = deliver(3, sender, grant)
+	assert.True(t, IsInvalidDisclosureErr(err), "%+v", err)
+	_, err = deliver(3, auditor, &SetViewKeyMsg{Key: apub[:]})
+	require.NoError(t, err)
+	_, err = deliver(3, recipient, grant)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = deliver(3, sender, grant)
+	require.NoError(t, err)
+	_, err = deliver(3, sender, grant)
+	assert.True(t, IsInvalidDisclosureErr(err), "%+v", err)
+
+	// only the arbiter releases, before the timeout
+	_, err = deliver(4, sender, &ReleaseEscrowMsg{EscrowId: id})
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = deliver(20, arbiter, &ReleaseEscrowMsg{EscrowId: id})
+	assert.True(t, IsInvalidEscrowErr(err), "%+v", err)
+	_, err = deliver(4, arbiter, &ReleaseEscrowMsg{EscrowId: id})
+	require.NoError(t, err)
+	_, err = deliver(4, arbiter, &ReleaseEscrowMsg{EscrowId: id})
+	assert.True(t, IsNoSuchEscrowErr(err), "%+v", err)
+
+	// the recipient unshields with the disclosed opening
+	paid := &Opening{Amount: 30 * fracUnit}
+	unshield := &UnshieldMsg{Amount: foo(30), Proof: prove(opened.Subtract(paid))}
+	_, err = deliver(5, recipient, &UnshieldMsg{Amount: foo(31), Proof: unshield.Proof})
+	assert.True(t, IsInvalidProofErr(err), "%+v", err)
+	_, err = deliver(5, recipient, unshield)
+	require.NoError(t, err)
+	assert.Equal(t, x.Coins{foo(30)}, balance(recipient.Address()))
+	assert.Equal(t, x.Coins{foo(10)}, balance(Pool))
+
+	// returns go back to the sender, only the recipient
+	// may return early
+	held = held.Subtract(escrowed)
+	escrowed, err = NewOpening(10 * fracUnit)
+	require.NoError(t, err)
+	create.Commitment = Commit(escrowed)
+	create.Proof = prove(escrowed)
+	create.ChangeProof = prove(held.Subtract(escrowed))
+	create.Disclosures = nil
+	id, err = deliver(6, sender, create)
+	require.NoError(t, err)
+	_, err = deliver(7, sender, &ReturnEscrowMsg{EscrowId: id})
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = deliver(20, sender, &ReturnEscrowMsg{EscrowId: id})
+	require.NoError(t, err)
+
+	// and the sender unshields the rest
+	held = held.Subtract(escrowed).Add(escrowed)
+	unshield = &UnshieldMsg{Amount: foo(10), Proof: prove(held.Subtract(&Opening{Amount: 10 * fracUnit}))}
+	_, err = deliver(21, sender, unshield)
+	require.NoError(t, err)
+	assert.Equal(t, x.Coins{foo(70)}, balance(sender.Address()))
+	assert.Equal(t, x.Coins(nil), balance(Pool))
+}
+
+func TestPathsAreExperimental(t *testing.T) {
+	for _, path := range Paths {
+		assert.True(t, features.IsExperimental(path), path)
+	}
+}
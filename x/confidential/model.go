@@ -0,0 +1,276 @@
+/*
+Package confidential is an experimental mode of escrows that hides
+their amounts. It is research grade: the proofs are large and slow,
+and it has not been audited. All its paths are experimental, see
+x/features, so a chain must enable them explicitly.
+
+Coins are first shielded: they move into the shielded pool, a module
+account, and are added to the shielded balance of their owner. A
+balance is a Pedersen commitment amount * G + blinding * H, that
+hides the amount. A confidential escrow moves a commitment from the
+balance of the sender to the escrow, with range proofs that neither
+the escrowed amount nor the balance left is negative. Release and
+return add the commitment to a balance again, so the amount never
+shows on chain until it is unshielded.
+
+Only the owner of a balance can compute its opening, the sum of the
+openings it received. The sender discloses the opening of an escrow
+to the recipient, the arbiter and auditors by sealing it to their
+view keys, and can grant more viewers access later.
+*/
+package confidential
+
+import (
+	"math/big"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+	"github.com/iov-one/bcp-demo/x/modaccount"
+)
+
+const (
+	// BalanceBucketName is where we store the shielded balances
+	BalanceBucketName = "cbal"
+	// ViewKeyBucketName is where we store the view keys
+	ViewKeyBucketName = "viewkey"
+	// EscrowBucketName is where we store the escrows
+	EscrowBucketName = "cesc"
+	// SequenceName is an auto-increment ID counter for escrows
+	SequenceName = "id"
+
+	// fracUnit is the number of fractional units in one whole coin
+	fracUnit = 1000000000
+
+	maxDisclosures = 8
+)
+
+func init() {
+	keyspace.Buckets("confidential",
+		BalanceBucketName, ViewKeyBucketName, EscrowBucketName)
+}
+
+// Pool is the address holding all shielded coins
+var Pool = modaccount.Address(modaccount.ShieldedPool)
+
+// Units returns the amount of the coin in fractional units,
+// as committed to. It fails if the coin is not positive or
+// too large for a range proof.
+func Units(c x.Coin) (uint64, error) {
+	if !c.IsPositive() {
+		return 0, ErrInvalidCommitment("amount must be positive")
+	}
+	n := new(big.Int).Mul(big.NewInt(c.Whole), big.NewInt(fracUnit))
+	n.Add(n, big.NewInt(c.Fractional))
+	if n.BitLen() > rangeBits {
+		return 0, ErrInvalidCommitment("amount too large")
+	}
+	return n.Uint64(), nil
+}
+
+//--- Balance
+
+var _ orm.CloneableData = (*Balance)(nil)
+
+// Validate ensures the commitment is a point
+func (b *Balance) Validate() error {
+	_, err := decodePoint(b.Commitment)
+	return err
+}
+
+// Copy makes a new balance with the same commitment
+func (b *Balance) Copy() orm.CloneableData {
+	return &Balance{Commitment: b.Commitment}
+}
+
+// BalanceBucket stores the shielded balances by address and ticker
+type BalanceBucket struct {
+	orm.Bucket
+}
+
+// NewBalanceBucket initializes a BalanceBucket with default name
+func NewBalanceBucket() BalanceBucket {
+	return BalanceBucket{
+		Bucket: orm.NewBucket(BalanceBucketName,
+			orm.NewSimpleObj(nil, new(Balance))),
+	}
+}
+
+// BalanceKey is the key of the balance of addr in ticker
+func BalanceKey(addr weave.Address, ticker string) []byte {
+	return append(append([]byte(nil), addr...), ticker...)
+}
+
+// commitment returns the balance as a point, the
+// identity if there is none
+func (b BalanceBucket) commitment(db weave.ReadOnlyKVStore,
+	addr weave.Address, ticker string) (point, error) {
+
+	obj, err := b.Get(db, BalanceKey(addr, ticker))
+	if err != nil {
+		return point{}, err
+	}
+	if obj == nil || obj.Value() == nil {
+		return identity(), nil
+	}
+	return decodePoint(obj.Value().(*Balance).Commitment)
+}
+
+// add adds the commitment to the balance
+func (b BalanceBucket) add(db weave.KVStore, addr weave.Address,
+	ticker string, c point) error {
+
+	bal, err := b.commitment(db, addr, ticker)
+	if err != nil {
+		return err
+	}
+	obj := orm.NewSimpleObj(BalanceKey(addr, ticker),
+		&Balance{Commitment: bal.add(c).Bytes()})
+	return b.Save(db, obj)
+}
+
+//--- ViewKey
+
+var _ orm.CloneableData = (*ViewKey)(nil)
+
+// Validate ensures the key has the right size
+func (k *ViewKey) Validate() error {
+	if len(k.Key) != ViewKeySize {
+		return ErrInvalidViewKey()
+	}
+	return nil
+}
+
+// Copy makes a new key with the same value
+func (k *ViewKey) Copy() orm.CloneableData {
+	return &ViewKey{Key: k.Key}
+}
+
+// ViewKeyBucket stores the view keys by address
+type ViewKeyBucket struct {
+	orm.Bucket
+}
+
+// NewViewKeyBucket initializes a ViewKeyBucket with default name
+func NewViewKeyBucket() ViewKeyBucket {
+	return ViewKeyBucket{
+		Bucket: orm.NewBucket(ViewKeyBucketName,
+			orm.NewSimpleObj(nil, new(ViewKey))),
+	}
+}
+
+// Has returns true if the address has a view key
+func (b ViewKeyBucket) Has(db weave.ReadOnlyKVStore, addr []byte) (bool, error) {
+	obj, err := b.Get(db, addr)
+	return obj != nil && obj.Value() != nil, err
+}
+
+//--- Escrow
+
+var _ orm.CloneableData = (*Escrow)(nil)
+
+// Validate ensures the escrow is valid
+func (e *Escrow) Validate() error {
+	for _, addr := range [][]byte{e.Sender, e.Arbiter, e.Recipient} {
+		if err := weave.Address(addr).Validate(); err != nil {
+			return err
+		}
+	}
+	if !x.IsCC(e.Ticker) {
+		return x.ErrInvalidCurrency(e.Ticker)
+	}
+	if e.Timeout <= 0 {
+		return ErrInvalidEscrow("timeout")
+	}
+	if len(e.Commitment) == 0 {
+		return ErrInvalidCommitment("missing")
+	}
+	if _, err := decodePoint(e.Commitment); err != nil {
+		return err
+	}
+	return validateDisclosures(e.Disclosures)
+}
+
+// Copy makes a new escrow with the same values
+func (e *Escrow) Copy() orm.CloneableData {
+	return &Escrow{
+		Sender:      e.Sender,
+		Arbiter:     e.Arbiter,
+		Recipient:   e.Recipient,
+		Ticker:      e.Ticker,
+		Timeout:     e.Timeout,
+		Commitment:  e.Commitment,
+		Disclosures: append([]*Disclosure(nil), e.Disclosures...),
+	}
+}
+
+// AsEscrow safely extracts an Escrow value from the object
+func AsEscrow(obj orm.Object) *Escrow {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Escrow)
+}
+
+// EscrowBucket is a type-safe wrapper around orm.Bucket
+type EscrowBucket struct {
+	orm.Bucket
+	idSeq orm.Sequence
+}
+
+// NewEscrowBucket initializes an EscrowBucket with default name
+func NewEscrowBucket() EscrowBucket {
+	bucket := orm.NewBucket(EscrowBucketName,
+		orm.NewSimpleObj(nil, new(Escrow)))
+	return EscrowBucket{
+		Bucket: bucket,
+		idSeq:  bucket.Sequence(SequenceName),
+	}
+}
+
+// Create will calculate the next sequence number and then
+// store the escrow there
+func (b EscrowBucket) Create(db weave.KVStore, escrow *Escrow) (orm.Object, error) {
+	key := b.idSeq.NextVal(db)
+	obj := orm.NewSimpleObj(key, escrow)
+	err := b.Save(db, obj)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// validateDisclosures makes sure there are not too many,
+// and at most one per viewer
+func validateDisclosures(list []*Disclosure) error {
+	if len(list) > maxDisclosures {
+		return ErrInvalidDisclosure("too many")
+	}
+	for i, d := range list {
+		if err := d.Validate(); err != nil {
+			return err
+		}
+		for _, other := range list[:i] {
+			if weave.Address(other.Viewer).Equals(d.Viewer) {
+				return ErrInvalidDisclosure("duplicate viewer")
+			}
+		}
+	}
+	return nil
+}
+
+// Validate makes sure there is a viewer and something sealed
+func (d *Disclosure) Validate() error {
+	if d == nil {
+		return ErrInvalidDisclosure("missing")
+	}
+	if err := weave.Address(d.Viewer).Validate(); err != nil {
+		return err
+	}
+	if len(d.Sealed) == 0 {
+		return ErrInvalidDisclosure("empty")
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package confidential
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/module"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// Module adds shielded balances and escrows. Auth authenticates
+// the owners, Control moves the coins they shield.
+type Module struct {
+	Auth    x.Authenticator
+	Control namecoin.Controller
+}
+
+var (
+	_ module.Module  = Module{}
+	_ module.Router  = Module{}
+	_ module.Querier = Module{}
+)
+
+// Name fulfils module.Module
+func (Module) Name() string {
+	return "confidential"
+}
+
+// Version fulfils module.Module
+func (Module) Version() uint32 {
+	return 1
+}
+
+// RegisterRoutes fulfils module.Router
+func (m Module) RegisterRoutes(r weave.Registry) {
+	RegisterRoutes(r, m.Auth, m.Control)
+}
+
+// RegisterQuery fulfils module.Querier
+func (Module) RegisterQuery(qr weave.QueryRouter) {
+	RegisterQuery(qr)
+}
@@ -0,0 +1,160 @@
+package confidential
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/features"
+)
+
+const (
+	pathShieldMsg        = "confidential/shield"
+	pathUnshieldMsg      = "confidential/unshield"
+	pathSetViewKeyMsg    = "confidential/view_key"
+	pathCreateEscrowMsg  = "confidential/create"
+	pathReleaseEscrowMsg = "confidential/release"
+	pathReturnEscrowMsg  = "confidential/return"
+	pathGrantViewMsg     = "confidential/grant"
+)
+
+// Paths are all paths of the module, off until enabled
+var Paths = []string{
+	pathShieldMsg,
+	pathUnshieldMsg,
+	pathSetViewKeyMsg,
+	pathCreateEscrowMsg,
+	pathReleaseEscrowMsg,
+	pathReturnEscrowMsg,
+	pathGrantViewMsg,
+}
+
+func init() {
+	features.RegisterExperimental(Paths...)
+}
+
+var _ weave.Msg = (*ShieldMsg)(nil)
+var _ weave.Msg = (*UnshieldMsg)(nil)
+var _ weave.Msg = (*SetViewKeyMsg)(nil)
+var _ weave.Msg = (*CreateEscrowMsg)(nil)
+var _ weave.Msg = (*ReleaseEscrowMsg)(nil)
+var _ weave.Msg = (*ReturnEscrowMsg)(nil)
+var _ weave.Msg = (*GrantViewMsg)(nil)
+
+// Path fulfills weave.Msg interface to allow routing
+func (ShieldMsg) Path() string {
+	return pathShieldMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (UnshieldMsg) Path() string {
+	return pathUnshieldMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (SetViewKeyMsg) Path() string {
+	return pathSetViewKeyMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (CreateEscrowMsg) Path() string {
+	return pathCreateEscrowMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (ReleaseEscrowMsg) Path() string {
+	return pathReleaseEscrowMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (ReturnEscrowMsg) Path() string {
+	return pathReturnEscrowMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (GrantViewMsg) Path() string {
+	return pathGrantViewMsg
+}
+
+// Validate makes sure the amount can be committed to
+func (m *ShieldMsg) Validate() error {
+	return validateAmount(m.Amount)
+}
+
+// Validate makes sure the amount can be committed to and
+// there is a proof, it is verified by the handler
+func (m *UnshieldMsg) Validate() error {
+	if err := validateAmount(m.Amount); err != nil {
+		return err
+	}
+	if len(m.Proof) != RangeProofSize {
+		return ErrInvalidProof("size")
+	}
+	return nil
+}
+
+// Validate makes sure the key has the right size
+func (m *SetViewKeyMsg) Validate() error {
+	return (&ViewKey{Key: m.Key}).Validate()
+}
+
+// Validate makes sure the terms are sensible and the proofs
+// have the right size, they are verified by the handler
+func (m *CreateEscrowMsg) Validate() error {
+	if err := weave.Address(m.Arbiter).Validate(); err != nil {
+		return err
+	}
+	if err := weave.Address(m.Recipient).Validate(); err != nil {
+		return err
+	}
+	if !x.IsCC(m.Ticker) {
+		return x.ErrInvalidCurrency(m.Ticker)
+	}
+	if m.Timeout <= 0 {
+		return ErrInvalidEscrow("timeout")
+	}
+	if len(m.Commitment) == 0 {
+		return ErrInvalidCommitment("missing")
+	}
+	if _, err := decodePoint(m.Commitment); err != nil {
+		return err
+	}
+	if len(m.Proof) != RangeProofSize || len(m.ChangeProof) != RangeProofSize {
+		return ErrInvalidProof("size")
+	}
+	return validateDisclosures(m.Disclosures)
+}
+
+// Validate only checks the id
+func (m *ReleaseEscrowMsg) Validate() error {
+	return validateEscrowID(m.EscrowId)
+}
+
+// Validate only checks the id
+func (m *ReturnEscrowMsg) Validate() error {
+	return validateEscrowID(m.EscrowId)
+}
+
+// Validate makes sure there is a disclosure
+func (m *GrantViewMsg) Validate() error {
+	if err := validateEscrowID(m.EscrowId); err != nil {
+		return err
+	}
+	return m.Disclosure.Validate()
+}
+
+func validateAmount(amount *x.Coin) error {
+	if amount == nil {
+		return ErrInvalidCommitment("missing amount")
+	}
+	if _, err := Units(*amount); err != nil {
+		return err
+	}
+	return amount.Validate()
+}
+
+func validateEscrowID(id []byte) error {
+	if len(id) != 8 {
+		return ErrInvalidEscrow("id")
+	}
+	return nil
+}
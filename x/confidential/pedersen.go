@@ -0,0 +1,321 @@
+package confidential
+
+import (
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"math/big"
+)
+
+// The commitments live on P-256, the curve of the standard
+// library. H is derived from a hash, so nobody knows its
+// discrete log to G and a commitment can only be opened to
+// the amount it was made for.
+var (
+	curve = elliptic.P256()
+	order = curve.Params().N
+
+	g = point{curve.Params().Gx, curve.Params().Gy}
+	h = hashToPoint([]byte("bov/confidential/H"))
+)
+
+const (
+	// rangeBits is the size of the amounts a range proof covers
+	rangeBits = 64
+	// pointSize is the length of an uncompressed point
+	pointSize = 65
+	// scalarSize is the length of a scalar mod the order
+	scalarSize = 32
+	// bitProofSize is a commitment to one bit with its proof
+	bitProofSize = pointSize + 4*scalarSize
+	// RangeProofSize is the length of every range proof
+	RangeProofSize = rangeBits * bitProofSize
+)
+
+// point is a point of the curve, (0, 0) is the identity
+type point struct {
+	x, y *big.Int
+}
+
+func identity() point {
+	return point{new(big.Int), new(big.Int)}
+}
+
+func (p point) isIdentity() bool {
+	return p.x.Sign() == 0 && p.y.Sign() == 0
+}
+
+// Bytes is the uncompressed encoding, empty for the identity
+func (p point) Bytes() []byte {
+	if p.isIdentity() {
+		return nil
+	}
+	return elliptic.Marshal(curve, p.x, p.y)
+}
+
+func (p point) equals(q point) bool {
+	return p.x.Cmp(q.x) == 0 && p.y.Cmp(q.y) == 0
+}
+
+func (p point) add(q point) point {
+	x, y := curve.Add(p.x, p.y, q.x, q.y)
+	return point{x, y}
+}
+
+func (p point) neg() point {
+	if p.isIdentity() {
+		return p
+	}
+	return point{p.x, new(big.Int).Sub(curve.Params().P, p.y)}
+}
+
+func (p point) sub(q point) point {
+	return p.add(q.neg())
+}
+
+func (p point) mul(k *big.Int) point {
+	k = new(big.Int).Mod(k, order)
+	if k.Sign() == 0 || p.isIdentity() {
+		return identity()
+	}
+	x, y := curve.ScalarMult(p.x, p.y, k.Bytes())
+	return point{x, y}
+}
+
+// decodePoint parses a point as encoded by Bytes
+func decodePoint(bz []byte) (point, error) {
+	if len(bz) == 0 {
+		return identity(), nil
+	}
+	if len(bz) != pointSize {
+		return point{}, ErrInvalidCommitment("size")
+	}
+	x, y := elliptic.Unmarshal(curve, bz)
+	if x == nil {
+		return point{}, ErrInvalidCommitment("not on curve")
+	}
+	return point{x, y}, nil
+}
+
+// hashToPoint tries sha256(tag || counter) as x until it
+// is on the curve
+func hashToPoint(tag []byte) point {
+	params := curve.Params()
+	three := big.NewInt(3)
+	for i := uint32(0); ; i++ {
+		ctr := make([]byte, 4)
+		binary.BigEndian.PutUint32(ctr, i)
+		sum := sha256.Sum256(append(append([]byte(nil), tag...), ctr...))
+		x := new(big.Int).SetBytes(sum[:])
+		x.Mod(x, params.P)
+		// y^2 = x^3 - 3x + b
+		rhs := new(big.Int).Exp(x, three, params.P)
+		rhs.Sub(rhs, new(big.Int).Mul(three, x))
+		rhs.Add(rhs, params.B)
+		rhs.Mod(rhs, params.P)
+		if y := new(big.Int).ModSqrt(rhs, params.P); y != nil {
+			return point{x, y}
+		}
+	}
+}
+
+// commit returns amount * G + blinding * H
+func commit(amount uint64, blinding *big.Int) point {
+	v := new(big.Int).SetUint64(amount)
+	return g.mul(v).add(h.mul(blinding))
+}
+
+// Commit returns the commitment to the opening, as stored
+func Commit(o *Opening) []byte {
+	return commit(o.Amount, new(big.Int).SetBytes(o.Blinding)).Bytes()
+}
+
+// scalarBytes pads a scalar to scalarSize
+func scalarBytes(k *big.Int) []byte {
+	bz := make([]byte, scalarSize)
+	b := k.Bytes()
+	copy(bz[scalarSize-len(b):], b)
+	return bz
+}
+
+// randomScalar returns a uniform non-zero scalar
+func randomScalar(r io.Reader) (*big.Int, error) {
+	buf := make([]byte, scalarSize+8)
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		k := new(big.Int).SetBytes(buf)
+		k.Mod(k, order)
+		if k.Sign() != 0 {
+			return k, nil
+		}
+	}
+}
+
+// NewOpening returns an opening of the amount with a random
+// blinding factor
+func NewOpening(amount uint64) (*Opening, error) {
+	r, err := randomScalar(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Opening{Amount: amount, Blinding: scalarBytes(r)}, nil
+}
+
+// Subtract returns the opening of the difference of the
+// commitments of o and other, eg. a balance less an escrow
+func (o *Opening) Subtract(other *Opening) *Opening {
+	r := new(big.Int).SetBytes(o.Blinding)
+	r.Sub(r, new(big.Int).SetBytes(other.Blinding))
+	r.Mod(r, order)
+	return &Opening{Amount: o.Amount - other.Amount, Blinding: scalarBytes(r)}
+}
+
+// Add returns the opening of the sum of the commitments
+func (o *Opening) Add(other *Opening) *Opening {
+	r := new(big.Int).SetBytes(o.Blinding)
+	r.Add(r, new(big.Int).SetBytes(other.Blinding))
+	r.Mod(r, order)
+	return &Opening{Amount: o.Amount + other.Amount, Blinding: scalarBytes(r)}
+}
+
+//--- range proofs
+
+// ProveRange shows the commitment of the opening hides an
+// amount in [0, 2^64) without revealing it.
+//
+// The amount is split in bits, each committed to as
+// bit * 2^i * G + r_i * H, with the r_i summing up to the
+// blinding. For every bit, a ring signature over H proves
+// that either the commitment or the commitment less 2^i * G
+// is a multiple of H, so the bit is 0 or 1. This takes
+// RangeProofSize bytes, research grade rather than compact.
+func ProveRange(o *Opening) ([]byte, error) {
+	blinding := new(big.Int).SetBytes(o.Blinding)
+	c := commit(o.Amount, blinding)
+	proof := make([]byte, 0, RangeProofSize)
+	sum := new(big.Int)
+	for i := uint(0); i < rangeBits; i++ {
+		var ri *big.Int
+		if i == rangeBits-1 {
+			ri = new(big.Int).Sub(blinding, sum)
+			ri.Mod(ri, order)
+		} else {
+			var err error
+			ri, err = randomScalar(rand.Reader)
+			if err != nil {
+				return nil, err
+			}
+			sum.Add(sum, ri)
+		}
+		bit := int(o.Amount>>i) & 1
+		bz, err := proveBit(c, i, bit, ri)
+		if err != nil {
+			return nil, err
+		}
+		proof = append(proof, bz...)
+	}
+	return proof, nil
+}
+
+// proveBit proves the commitment to bit * 2^i with blinding r
+func proveBit(c point, i uint, bit int, r *big.Int) ([]byte, error) {
+	pow := g.mul(new(big.Int).Lsh(big.NewInt(1), i))
+	ci := h.mul(r)
+	if bit == 1 {
+		ci = ci.add(pow)
+	}
+	// P_0 = C_i and P_1 = C_i - 2^i * G, we know r for P_bit
+	keys := [2]point{ci, ci.sub(pow)}
+	var e, s [2]*big.Int
+	var nonces [2]point
+
+	other := 1 - bit
+	var err error
+	if e[other], err = randomScalar(rand.Reader); err != nil {
+		return nil, err
+	}
+	if s[other], err = randomScalar(rand.Reader); err != nil {
+		return nil, err
+	}
+	nonces[other] = h.mul(s[other]).sub(keys[other].mul(e[other]))
+	t, err := randomScalar(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	nonces[bit] = h.mul(t)
+
+	challenge := bitChallenge(c, i, ci, nonces)
+	e[bit] = new(big.Int).Sub(challenge, e[other])
+	e[bit].Mod(e[bit], order)
+	s[bit] = new(big.Int).Mul(e[bit], r)
+	s[bit].Add(s[bit], t)
+	s[bit].Mod(s[bit], order)
+
+	bz := elliptic.Marshal(curve, ci.x, ci.y)
+	for _, k := range []*big.Int{e[0], e[1], s[0], s[1]} {
+		bz = append(bz, scalarBytes(k)...)
+	}
+	return bz, nil
+}
+
+// VerifyRange checks a proof of ProveRange for the commitment
+func VerifyRange(commitment, proof []byte) error {
+	c, err := decodePoint(commitment)
+	if err != nil {
+		return err
+	}
+	if len(proof) != RangeProofSize {
+		return ErrInvalidProof("size")
+	}
+	total := identity()
+	for i := uint(0); i < rangeBits; i++ {
+		bz := proof[int(i)*bitProofSize : int(i+1)*bitProofSize]
+		ci, err := decodePoint(bz[:pointSize])
+		if err != nil || ci.isIdentity() {
+			return ErrInvalidProof("bit commitment")
+		}
+		var k [4]*big.Int
+		for j := range k {
+			off := pointSize + j*scalarSize
+			k[j] = new(big.Int).SetBytes(bz[off : off+scalarSize])
+			if k[j].Cmp(order) >= 0 {
+				return ErrInvalidProof("scalar")
+			}
+		}
+		e, s := [2]*big.Int{k[0], k[1]}, [2]*big.Int{k[2], k[3]}
+
+		pow := g.mul(new(big.Int).Lsh(big.NewInt(1), i))
+		keys := [2]point{ci, ci.sub(pow)}
+		var nonces [2]point
+		for j := range keys {
+			nonces[j] = h.mul(s[j]).sub(keys[j].mul(e[j]))
+		}
+		sum := new(big.Int).Add(e[0], e[1])
+		sum.Mod(sum, order)
+		if sum.Cmp(bitChallenge(c, i, ci, nonces)) != 0 {
+			return ErrInvalidProof("bit")
+		}
+		total = total.add(ci)
+	}
+	if !total.equals(c) {
+		return ErrInvalidProof("sum")
+	}
+	return nil
+}
+
+// bitChallenge hashes everything the proof of bit i commits to
+func bitChallenge(c point, i uint, ci point, nonces [2]point) *big.Int {
+	hash := sha256.New()
+	hash.Write([]byte("bov/confidential/range"))
+	hash.Write(c.Bytes())
+	hash.Write([]byte{byte(i)})
+	hash.Write(ci.Bytes())
+	hash.Write(nonces[0].Bytes())
+	hash.Write(nonces[1].Bytes())
+	e := new(big.Int).SetBytes(hash.Sum(nil))
+	return e.Mod(e, order)
+}
@@ -0,0 +1,97 @@
+package confidential
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeProof(t *testing.T) {
+	cases := map[string]uint64{
+		"zero": 0,
+		"one":  1,
+		"some": 123456789,
+		"max":  ^uint64(0),
+	}
+	for name, amount := range cases {
+		t.Run(name, func(t *testing.T) {
+			o, err := NewOpening(amount)
+			require.NoError(t, err)
+			proof, err := ProveRange(o)
+			require.NoError(t, err)
+			assert.Len(t, proof, RangeProofSize)
+			assert.NoError(t, VerifyRange(Commit(o), proof))
+		})
+	}
+}
+
+func TestRangeProofRejects(t *testing.T) {
+	o, err := NewOpening(77)
+	require.NoError(t, err)
+	proof, err := ProveRange(o)
+	require.NoError(t, err)
+
+	// a proof for another commitment
+	other, err := NewOpening(77)
+	require.NoError(t, err)
+	err = VerifyRange(Commit(other), proof)
+	assert.True(t, IsInvalidProofErr(err), "%+v", err)
+
+	// any flipped byte
+	for _, i := range []int{0, 64, 65, 200, RangeProofSize - 1} {
+		bad := append([]byte(nil), proof...)
+		bad[i] ^= 1
+		err = VerifyRange(Commit(o), bad)
+		assert.Error(t, err, "byte %d", i)
+	}
+
+	// a negative amount wraps around 2^64 and cannot be
+	// split in bits with a blinding that sums up
+	small, err := NewOpening(10)
+	require.NoError(t, err)
+	large, err := NewOpening(11)
+	require.NoError(t, err)
+	diff := small.Subtract(large)
+	csmall, _ := decodePoint(Commit(small))
+	clarge, _ := decodePoint(Commit(large))
+	proof, err = ProveRange(diff)
+	require.NoError(t, err)
+	err = VerifyRange(csmall.sub(clarge).Bytes(), proof)
+	assert.True(t, IsInvalidProofErr(err), "%+v", err)
+}
+
+func TestOpeningArithmetic(t *testing.T) {
+	a, err := NewOpening(50)
+	require.NoError(t, err)
+	b, err := NewOpening(20)
+	require.NoError(t, err)
+	ca, _ := decodePoint(Commit(a))
+	cb, _ := decodePoint(Commit(b))
+
+	assert.Equal(t, ca.add(cb).Bytes(), Commit(a.Add(b)))
+	assert.Equal(t, ca.sub(cb).Bytes(), Commit(a.Subtract(b)))
+	assert.Equal(t, uint64(30), a.Subtract(b).Amount)
+}
+
+func TestSeal(t *testing.T) {
+	pub, priv, err := GenerateViewKey()
+	require.NoError(t, err)
+	_, other, err := GenerateViewKey()
+	require.NoError(t, err)
+	o, err := NewOpening(42)
+	require.NoError(t, err)
+
+	sealed, err := Seal(pub[:], o)
+	require.NoError(t, err)
+	opened, err := Unseal(priv, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, o, opened)
+
+	_, err = Unseal(other, sealed)
+	assert.True(t, IsInvalidDisclosureErr(err), "%+v", err)
+	_, err = Unseal(priv, sealed[:10])
+	assert.True(t, IsInvalidDisclosureErr(err), "%+v", err)
+	_, err = Seal(pub[:5], o)
+	assert.Error(t, err)
+}
@@ -0,0 +1,64 @@
+package confidential
+
+import (
+	"crypto/rand"
+	"io"
+
+	"golang.org/x/crypto/nacl/box"
+)
+
+const (
+	// ViewKeySize is the length of a curve25519 view key
+	ViewKeySize = 32
+	nonceSize   = 24
+)
+
+// GenerateViewKey returns a new key pair to receive disclosures,
+// the public key goes into a SetViewKeyMsg
+func GenerateViewKey() (public, private *[ViewKeySize]byte, err error) {
+	return box.GenerateKey(rand.Reader)
+}
+
+// Seal encrypts the opening to the view key with a nacl box
+// from a new key, the result is
+// ephemeral public key || nonce || box
+func Seal(viewKey []byte, o *Opening) ([]byte, error) {
+	var peer [ViewKeySize]byte
+	if len(viewKey) != ViewKeySize {
+		return nil, ErrInvalidViewKey()
+	}
+	copy(peer[:], viewKey)
+
+	pub, priv, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [nonceSize]byte
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return nil, err
+	}
+	msg, err := o.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	out := append(pub[:], nonce[:]...)
+	return box.Seal(out, msg, &nonce, &peer, priv), nil
+}
+
+// Unseal decrypts a disclosure with the private view key
+func Unseal(private *[ViewKeySize]byte, sealed []byte) (*Opening, error) {
+	if len(sealed) < ViewKeySize+nonceSize+box.Overhead {
+		return nil, ErrInvalidDisclosure("size")
+	}
+	var peer [ViewKeySize]byte
+	var nonce [nonceSize]byte
+	copy(peer[:], sealed)
+	copy(nonce[:], sealed[ViewKeySize:])
+	msg, ok := box.Open(nil, sealed[ViewKeySize+nonceSize:], &nonce, &peer, private)
+	if !ok {
+		return nil, ErrInvalidDisclosure("cannot open")
+	}
+	var o Opening
+	err := o.Unmarshal(msg)
+	return &o, err
+}
@@ -0,0 +1,170 @@
+/*
+Package crash replaces the recovery decorator of weave. Like it,
+it turns a panic in a tx into an error, but it also hands a Report
+of the panic (message path, tx hash, signer and stack) to a Reporter,
+eg. to append it to a crash file and count it for metrics.
+
+Classes convert known panics to typed errors, which clients can
+handle like any other error, instead of an internal error.
+*/
+package crash
+
+import (
+	"encoding/hex"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x/sigs"
+
+	"github.com/iov-one/bcp-demo/x/keys"
+	"github.com/iov-one/bcp-demo/x/txindex"
+)
+
+// Report describes one panic in a tx
+type Report struct {
+	Height int64 `json:"height"`
+	// Deliver is false for a panic in CheckTx
+	Deliver bool   `json:"deliver"`
+	Path    string `json:"path"`
+	// TxHash is the hash tendermint shows, as hex
+	TxHash string        `json:"tx_hash,omitempty"`
+	Signer weave.Address `json:"signer,omitempty"`
+	Panic  string        `json:"panic"`
+	// Error is what the tx returned instead
+	Error string `json:"error"`
+	Stack string `json:"stack"`
+}
+
+// Reporter records the reports of panics. It must not panic
+// itself, and errors are its own to log.
+type Reporter interface {
+	Report(Report)
+}
+
+// Class returns a typed error for the panics it knows,
+// nil for all others
+type Class func(p interface{}) error
+
+// Matching is the class of panics whose message starts with
+// prefix, they all return err
+func Matching(prefix string, err error) Class {
+	return func(p interface{}) error {
+		if strings.HasPrefix(fmt.Sprintf("%v", p), prefix) {
+			return err
+		}
+		return nil
+	}
+}
+
+// Decorator turns panics into errors and reports them
+type Decorator struct {
+	report  Reporter
+	classes []Class
+}
+
+var _ weave.Decorator = Decorator{}
+
+// NewDecorator reports panics to report, which may be nil
+func NewDecorator(report Reporter) Decorator {
+	return Decorator{report: report}
+}
+
+// WithClasses converts panics of any of the classes to the error
+// it returns, the first one that matches wins. Others are handled
+// as by the weave recovery: errors keep their code, anything else
+// becomes an internal error.
+func (d Decorator) WithClasses(classes ...Class) Decorator {
+	d.classes = append(append([]Class{}, d.classes...), classes...)
+	return d
+}
+
+// Check turns panics into errors
+func (d Decorator) Check(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Checker) (res weave.CheckResult, err error) {
+
+	defer d.recover(ctx, tx, false, &err)
+	return next.Check(ctx, store, tx)
+}
+
+// Deliver turns panics into errors
+func (d Decorator) Deliver(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (res weave.DeliverResult, err error) {
+
+	defer d.recover(ctx, tx, true, &err)
+	return next.Deliver(ctx, store, tx)
+}
+
+// recover sets err if the tx panicked, and reports it
+func (d Decorator) recover(ctx weave.Context, tx weave.Tx, deliver bool, err *error) {
+	p := recover()
+	if p == nil {
+		return
+	}
+	*err = d.convert(p)
+	if d.report == nil {
+		return
+	}
+	height, _ := weave.GetHeight(ctx)
+	d.report.Report(Report{
+		Height:  height,
+		Deliver: deliver,
+		Path:    msgPath(tx),
+		TxHash:  txHash(tx),
+		Signer:  signer(tx),
+		Panic:   fmt.Sprintf("%v", p),
+		Error:   (*err).Error(),
+		Stack:   string(debug.Stack()),
+	})
+}
+
+// convert returns the error of the first class matching p
+func (d Decorator) convert(p interface{}) error {
+	for _, class := range d.classes {
+		if err := class(p); err != nil {
+			return err
+		}
+	}
+	return errors.NormalizePanic(p)
+}
+
+// msgPath is the path of the message, empty if the tx has none
+func msgPath(tx weave.Tx) string {
+	msg, err := tx.GetMsg()
+	if err != nil {
+		return ""
+	}
+	return msg.Path()
+}
+
+// txHash is the hash of the tx as hex, empty if it can't
+// be encoded
+func txHash(tx weave.Tx) string {
+	mtx, ok := tx.(txindex.MarshaledTx)
+	if !ok {
+		return ""
+	}
+	bz, err := mtx.Marshal()
+	if err != nil {
+		return ""
+	}
+	return hex.EncodeToString(txindex.Hash(bz))
+}
+
+// signer is the address of the first signature, nil if the tx
+// is not signed. The signatures are not verified yet.
+func signer(tx weave.Tx) weave.Address {
+	if stx, ok := tx.(sigs.SignedTx); ok {
+		if list := stx.GetSignatures(); len(list) > 0 && list[0].PubKey != nil {
+			return list[0].PubKey.Address()
+		}
+	}
+	if ktx, ok := tx.(keys.SignedTx); ok {
+		if list := ktx.GetKeySignatures(); len(list) > 0 && list[0].Pubkey != nil {
+			return list[0].Pubkey.Address()
+		}
+	}
+	return nil
+}
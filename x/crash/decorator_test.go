@@ -0,0 +1,135 @@
+package crash
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/txindex"
+)
+
+func TestDecoratorReports(t *testing.T) {
+	var helpers x.TestHelpers
+	dir, err := ioutil.TempDir("", "bov-crash")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "crash.json")
+
+	counter := NewCounter()
+	d := NewDecorator(Reporters{counter, NewFileReporter(path, nil)})
+	tx := marshaledTx{helpers.MockTx(&cash.SendMsg{Memo: "hi"}), []byte("tx")}
+	ctx := weave.WithHeight(context.Background(), 12)
+	db := store.MemStore()
+
+	// panics become internal errors
+	stack := helpers.Wrap(d, panicHandler{"boom"})
+	_, err = stack.Deliver(ctx, db, tx)
+	require.Error(t, err)
+	assert.True(t, errors.IsInternalErr(err), "%+v", err)
+	_, err = stack.Check(ctx, db, tx)
+	require.Error(t, err)
+
+	// no reports without a panic
+	_, err = helpers.Wrap(d, helpers.CountingHandler()).Deliver(ctx, db, tx)
+	require.NoError(t, err)
+
+	bz, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(bz)), "\n")
+	require.Len(t, lines, 2)
+	var rep Report
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &rep))
+	assert.Equal(t, int64(12), rep.Height)
+	assert.True(t, rep.Deliver)
+	assert.Equal(t, "cash/send", rep.Path)
+	assert.Equal(t, fmt.Sprintf("%x", txindex.Hash([]byte("tx"))), rep.TxHash)
+	assert.Equal(t, "boom", rep.Panic)
+	assert.Contains(t, rep.Stack, "panicHandler")
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &rep))
+	assert.False(t, rep.Deliver)
+
+	var out bytes.Buffer
+	require.NoError(t, counter.WriteMetrics(&out))
+	assert.Contains(t, out.String(), "bov_tx_panics_total{path=\"cash/send\"} 2\n")
+}
+
+func TestDecoratorClasses(t *testing.T) {
+	var helpers x.TestHelpers
+	ctx := context.Background()
+	db := store.MemStore()
+	tx := helpers.MockTx(&cash.SendMsg{})
+	coded := errors.ErrUnauthorized()
+	notFound := fmt.Errorf("not found")
+
+	plain := NewDecorator(nil)
+	typed := plain.WithClasses(Matching("missing", notFound), func(p interface{}) error {
+		if p == "gone" {
+			return notFound
+		}
+		return nil
+	})
+
+	cases := map[string]struct {
+		d     Decorator
+		panic interface{}
+		check func(error) bool
+	}{
+		// errors keep their code, as in the weave recovery
+		"plain coded":   {plain, coded, errors.IsUnauthorizedErr},
+		"plain missing": {plain, "missing key", errors.IsInternalErr},
+		"typed missing": {typed, "missing key", func(err error) bool { return err == notFound }},
+		"typed custom":  {typed, "gone", func(err error) bool { return err == notFound }},
+		"typed unknown": {typed, "other", errors.IsInternalErr},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := helpers.Wrap(tc.d, panicHandler{tc.panic}).Deliver(ctx, db, tx)
+			require.Error(t, err)
+			assert.True(t, tc.check(err), "%+v", err)
+		})
+	}
+}
+
+//---------------- helpers --------
+
+// panicHandler panics with p on Check and Deliver
+type panicHandler struct {
+	p interface{}
+}
+
+var _ weave.Handler = panicHandler{}
+
+func (h panicHandler) Check(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	panic(h.p)
+}
+
+func (h panicHandler) Deliver(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	panic(h.p)
+}
+
+// marshaledTx returns fixed bytes as its encoding
+type marshaledTx struct {
+	weave.Tx
+	bz []byte
+}
+
+func (m marshaledTx) Marshal() ([]byte, error) {
+	return m.bz, nil
+}
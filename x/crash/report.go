@@ -0,0 +1,116 @@
+package crash
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// metricPanics is the name Counter exports its counts under
+const metricPanics = "bov_tx_panics_total"
+
+// FileReporter appends every report as a line of json to a file
+type FileReporter struct {
+	path string
+	// log gets the reports that can't be written
+	log func(err error)
+	mtx sync.Mutex
+}
+
+var _ Reporter = (*FileReporter)(nil)
+
+// NewFileReporter appends to the file at path, creating it
+// if needed. Write errors go to log.
+func NewFileReporter(path string, log func(err error)) *FileReporter {
+	return &FileReporter{path: path, log: log}
+}
+
+// Report appends the report to the file
+func (f *FileReporter) Report(r Report) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	err := f.write(r)
+	if err != nil && f.log != nil {
+		f.log(err)
+	}
+}
+
+func (f *FileReporter) write(r Report) error {
+	bz, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(bz, '\n'))
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Counter counts the panics by message path, in memory,
+// for the metrics of the node
+type Counter struct {
+	mtx    sync.Mutex
+	counts map[string]int64
+}
+
+var _ Reporter = (*Counter)(nil)
+
+// NewCounter starts with no panics
+func NewCounter() *Counter {
+	return &Counter{counts: make(map[string]int64)}
+}
+
+// Report counts the panic
+func (c *Counter) Report(r Report) {
+	c.mtx.Lock()
+	c.counts[r.Path]++
+	c.mtx.Unlock()
+}
+
+// WriteMetrics writes the counts in the text format of prometheus
+func (c *Counter) WriteMetrics(w io.Writer) error {
+	c.mtx.Lock()
+	paths := make([]string, 0, len(c.counts))
+	for path := range c.counts {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	counts := make([]int64, len(paths))
+	for i, path := range paths {
+		counts[i] = c.counts[path]
+	}
+	c.mtx.Unlock()
+
+	_, err := fmt.Fprintf(w, "# HELP %s Txs that panicked, by message path.\n"+
+		"# TYPE %s counter\n", metricPanics, metricPanics)
+	if err != nil {
+		return err
+	}
+	for i, path := range paths {
+		_, err := fmt.Fprintf(w, "%s{path=%q} %d\n", metricPanics, path, counts[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reporters hands every report to all of them in order
+type Reporters []Reporter
+
+var _ Reporter = Reporters{}
+
+// Report fulfils Reporter
+func (rs Reporters) Report(r Report) {
+	for _, rep := range rs {
+		rep.Report(r)
+	}
+}
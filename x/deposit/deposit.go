@@ -0,0 +1,84 @@
+/*
+Package deposit tags payments with a destination tag, so an exchange
+can take the deposits of all its users on one address and credit
+each of them by the tag, like the destination tags of ripple.
+
+The payer sets the tag as the memo of a SendMsg, or as the dest_tag
+of an escrow, which applies to every payment of the escrow to its
+recipient. A payment with a tag adds the tag "deposit.<ADDRESS>",
+the hex address of the payee, with the destination tag as value to
+the result of the tx. The tx history of the payee records it too.
+*/
+package deposit
+
+import (
+	"bytes"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x/cash"
+	"github.com/tendermint/tmlibs/common"
+)
+
+const (
+	// TagPrefix starts the key of every deposit tag
+	TagPrefix = "deposit."
+	// MaxTagLength is the longest destination tag, as long
+	// as the memo of a SendMsg
+	MaxTagLength = 128
+)
+
+// Tag is the tag of a payment to dest with the destination tag
+func Tag(dest weave.Address, destTag string) common.KVPair {
+	return common.KVPair{
+		Key:   []byte(TagPrefix + dest.String()),
+		Value: []byte(destTag),
+	}
+}
+
+// Find returns the destination tag of the payment to dest in
+// tags, empty if there is none
+func Find(tags []common.KVPair, dest weave.Address) string {
+	key := []byte(TagPrefix + dest.String())
+	for _, tag := range tags {
+		if bytes.Equal(tag.Key, key) {
+			return string(tag.Value)
+		}
+	}
+	return ""
+}
+
+// SendHandler adds the memo of every SendMsg delivered by the
+// handler it wraps as destination tag of the payment
+type SendHandler struct {
+	weave.Handler
+}
+
+var _ weave.Handler = SendHandler{}
+
+// NewSendHandler wraps a handler of SendMsg
+func NewSendHandler(h weave.Handler) SendHandler {
+	return SendHandler{Handler: h}
+}
+
+// Deliver delivers the msg and tags the payment
+func (h SendHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	res, err := h.Handler.Deliver(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return res, err
+	}
+	msg, ok := rmsg.(*cash.SendMsg)
+	if !ok {
+		return res, errors.ErrUnknownTxType(rmsg)
+	}
+	if msg.Memo != "" {
+		res.Tags = append(res.Tags, Tag(msg.Dest, msg.Memo))
+	}
+	return res, nil
+}
@@ -0,0 +1,67 @@
+package deposit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tmlibs/common"
+
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+func TestFind(t *testing.T) {
+	var helpers x.TestHelpers
+	_, alice := helpers.MakeKey()
+	_, bob := helpers.MakeKey()
+
+	tags := []common.KVPair{
+		{Key: []byte("escrow.release"), Value: []byte("0001")},
+		Tag(bob.Address(), "104882"),
+	}
+	assert.Equal(t, "deposit."+bob.Address().String(), string(tags[1].Key))
+	assert.Equal(t, "104882", Find(tags, bob.Address()))
+	assert.Equal(t, "", Find(tags, alice.Address()))
+	assert.Equal(t, "", Find(nil, bob.Address()))
+}
+
+func TestSendHandler(t *testing.T) {
+	var helpers x.TestHelpers
+	_, alice := helpers.MakeKey()
+	_, bob := helpers.MakeKey()
+
+	coin := x.NewCoin(10, 0, "FOO")
+	cases := []struct {
+		memo   string
+		amount x.Coin
+		tags   []common.KVPair
+		fails  bool
+	}{
+		0: {"104882", coin, []common.KVPair{Tag(bob.Address(), "104882")}, false},
+		1: {"", coin, nil, false},
+		// failed payments are not tagged
+		2: {"104882", x.NewCoin(1000, 0, "FOO"), nil, true},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			control := cash.NewController(cash.NewBucket())
+			require.NoError(t, control.IssueCoins(db, alice.Address(), coin))
+
+			auth := helpers.CtxAuth("auth")
+			ctx := auth.SetPermissions(context.Background(), alice)
+			h := NewSendHandler(cash.NewSendHandler(auth, control))
+			msg := &cash.SendMsg{Src: alice.Address(), Dest: bob.Address(),
+				Amount: &tc.amount, Memo: tc.memo}
+
+			res, err := h.Deliver(ctx, db, helpers.MockTx(msg))
+			assert.Equal(t, tc.fails, err != nil, "%+v", err)
+			assert.Equal(t, tc.tags, res.Tags)
+		})
+	}
+}
@@ -0,0 +1,415 @@
+package escrow
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/tracing"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/rbac"
+)
+
+const (
+	// BucketNameBids is where we store the arbitration bids
+	BucketNameBids = "escbid"
+	// QueryBids is the path to list the bids of an escrow
+	QueryBids = "/escrows/bids"
+
+	bidCost    int64 = 50
+	assignCost int64 = 50
+)
+
+var _ orm.CloneableData = (*Bid)(nil)
+
+// Validate ensures the bid is complete
+func (b *Bid) Validate() error {
+	if err := weave.Permission(b.Arbiter).Validate(); err != nil {
+		return err
+	}
+	if b.Fee == nil || b.Fee.Negative().IsPositive() {
+		return ErrInvalidBid("fee")
+	}
+	return b.Fee.Validate()
+}
+
+// Copy makes a new bid with the same values
+func (b *Bid) Copy() orm.CloneableData {
+	return &Bid{
+		Arbiter: b.Arbiter,
+		Fee:     b.Fee,
+		Height:  b.Height,
+	}
+}
+
+// AsBid safely extracts a Bid value from the object
+func AsBid(obj orm.Object) *Bid {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Bid)
+}
+
+// BidBucket holds the open bids for all escrows. Keys are the
+// escrow id followed by the address of the bidder, so a prefix
+// scan on the id returns all bids of one escrow.
+type BidBucket struct {
+	orm.Bucket
+}
+
+// NewBidBucket initializes a BidBucket with default name
+func NewBidBucket() BidBucket {
+	return BidBucket{
+		Bucket: orm.NewBucket(BucketNameBids,
+			orm.NewSimpleObj(nil, new(Bid))),
+	}
+}
+
+func bidKey(id []byte, bidder weave.Address) []byte {
+	return append(append([]byte{}, id...), bidder...)
+}
+
+// Bids returns all bids for the escrow
+func (b BidBucket) Bids(db weave.ReadOnlyKVStore, id []byte) ([]orm.Object, error) {
+	models, err := b.Query(db, weave.PrefixQueryMod, id)
+	if err != nil {
+		return nil, err
+	}
+	// the models have the full db key, with the bucket prefix
+	prefix := len(b.DBKey(nil))
+	res := make([]orm.Object, len(models))
+	for i, m := range models {
+		res[i], err = b.Parse(m.Key[prefix:], m.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Lowest returns the bid with the lowest fee, the earliest
+// of them on a tie. It returns nil if there are no bids.
+func (b BidBucket) Lowest(db weave.ReadOnlyKVStore, id []byte) (orm.Object, error) {
+	bids, err := b.Bids(db, id)
+	if err != nil {
+		return nil, err
+	}
+	var best orm.Object
+	for _, obj := range bids {
+		if best == nil {
+			best = obj
+			continue
+		}
+		bid, low := AsBid(obj), AsBid(best)
+		cmp := bid.Fee.Compare(*low.Fee)
+		if cmp < 0 || (cmp == 0 && bid.Height < low.Height) {
+			best = obj
+		}
+	}
+	return best, nil
+}
+
+// DeleteAll removes all bids for the escrow
+func (b BidBucket) DeleteAll(db weave.KVStore, id []byte) error {
+	bids, err := b.Bids(db, id)
+	if err != nil {
+		return err
+	}
+	for _, obj := range bids {
+		err = b.Delete(db, obj.Key())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteBids drops the bids of a closed escrow. Only escrows
+// still waiting for an arbiter have bids, so the others are
+// not scanned.
+func deleteBids(db weave.KVStore, bids BidBucket, obj orm.Object) error {
+	escrow := AsEscrow(obj)
+	if escrow.Arbiter != nil || escrow.Bounty == nil {
+		return nil
+	}
+	return bids.DeleteAll(db, obj.Key())
+}
+
+// BidsQuery returns all bids for one escrow, with the escrow
+// id as data and no modifier
+type BidsQuery struct {
+	bucket BidBucket
+}
+
+var _ weave.QueryHandler = BidsQuery{}
+
+// Query implements weave.QueryHandler
+func (q BidsQuery) Query(db weave.ReadOnlyKVStore, mod string,
+	data []byte) ([]weave.Model, error) {
+
+	if mod != weave.KeyQueryMod || len(data) == 0 {
+		return nil, ErrInvalidQuery(mod)
+	}
+	return q.bucket.Query(db, weave.PrefixQueryMod, data)
+}
+
+//---- bid
+
+// BidArbitrationHandler stores the bids of registered arbiters
+type BidArbitrationHandler struct {
+	auth   x.Authenticator
+	bucket Store
+	bids   BidBucket
+	roles  rbac.Bucket
+}
+
+var _ weave.Handler = BidArbitrationHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h BidArbitrationHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += bidCost
+	return res, nil
+}
+
+// Deliver stores the bid, replacing any previous one of
+// the same arbiter
+func (h BidArbitrationHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, bidder, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	height, _ := weave.GetHeight(ctx)
+	bid := &Bid{
+		Arbiter: bidder,
+		Fee:     msg.Fee,
+		Height:  height,
+	}
+	key := bidKey(msg.EscrowId, bidder.Address())
+	err = h.bids.Save(db, orm.NewSimpleObj(key, bid))
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h BidArbitrationHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*BidArbitrationMsg, weave.Permission, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*BidArbitrationMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := openForBids(ctx, db, h.bucket, msg.EscrowId)
+	if err != nil {
+		return nil, nil, err
+	}
+	escrow := AsEscrow(obj)
+
+	// only registered arbiters may bid
+	bidder := x.MainSigner(ctx, h.auth)
+	if bidder == nil {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	ok, err = h.roles.HasRole(db, bidder.Address(), rbac.RoleArbiter)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	// nobody may judge their own escrow
+	addr := bidder.Address()
+	if addr.Equals(weave.Permission(escrow.Sender).Address()) ||
+		addr.Equals(weave.Permission(escrow.Recipient).Address()) {
+		return nil, nil, ErrInvalidBid("party of the escrow")
+	}
+
+	// the fee is paid from the bounty
+	if msg.Fee.Ticker != escrow.Bounty.Ticker ||
+		msg.Fee.Compare(*escrow.Bounty) > 0 {
+		return nil, nil, ErrInvalidBid("fee exceeds bounty")
+	}
+	return msg, bidder, nil
+}
+
+//---- assign
+
+// AssignArbiterHandler lets the sender accept a bid
+type AssignArbiterHandler struct {
+	auth    x.Authenticator
+	bucket  Store
+	bids    BidBucket
+	history HistoryBucket
+	cash    namecoin.Controller
+}
+
+var _ weave.Handler = AssignArbiterHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h AssignArbiterHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += assignCost
+	return res, nil
+}
+
+// Deliver makes the bidder the arbiter, refunds the part of
+// the bounty it didn't ask for and drops all other bids
+func (h AssignArbiterHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	obj, bid, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	escrow := AsEscrow(obj)
+
+	rest, err := escrow.Bounty.Add(bid.Fee.Negative())
+	if err != nil {
+		return res, err
+	}
+	if rest.IsPositive() {
+		src := NewCondition(obj.Key()).Address()
+		dest := weave.Permission(escrow.Sender).Address()
+		err = h.cash.WithContext(ctx).MoveCoins(db, src, dest, rest)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	escrow.Arbiter = bid.Arbiter
+	escrow.Bounty = bid.Fee
+	var fee x.Coins
+	if bid.Fee.IsPositive() {
+		fee = x.Coins{bid.Fee}
+	} else {
+		escrow.Bounty = nil
+	}
+	err = h.bucket.Save(db, obj)
+	if err != nil {
+		return res, err
+	}
+	err = h.bids.DeleteAll(db, obj.Key())
+	if err != nil {
+		return res, err
+	}
+	err = h.history.Append(ctx, db, h.auth, obj.Key(), EventAssign, fee)
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h AssignArbiterHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (orm.Object, *Bid, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*AssignArbiterMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := openForBids(ctx, db, h.bucket, msg.EscrowId)
+	if err != nil {
+		return nil, nil, err
+	}
+	// only the sender chooses
+	escrow := AsEscrow(obj)
+	sender := weave.Permission(escrow.Sender).Address()
+	if !h.auth.HasAddress(ctx, sender) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+
+	var bid orm.Object
+	if msg.Bidder != nil {
+		bid, err = h.bids.Get(db, bidKey(msg.EscrowId, msg.Bidder))
+	} else {
+		bid, err = h.bids.Lowest(db, msg.EscrowId)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if bid == nil {
+		return nil, nil, ErrNoBids(msg.EscrowId)
+	}
+	return obj, AsBid(bid), nil
+}
+
+// openForBids loads an unexpired escrow that is waiting
+// for an arbiter
+func openForBids(ctx weave.Context, db weave.KVStore, bucket Store,
+	id []byte) (orm.Object, error) {
+
+	obj, err := bucket.Get(db, id)
+	if err != nil {
+		return nil, err
+	}
+	escrow := AsEscrow(obj)
+	if escrow == nil {
+		return nil, ErrNoSuchEscrow(id)
+	}
+	if err := checkOpen(id, escrow); err != nil {
+		return nil, err
+	}
+	if escrow.Arbiter != nil || escrow.Bounty == nil {
+		return nil, ErrArbiterAssigned()
+	}
+	height, _ := weave.GetHeight(ctx)
+	if height > escrow.Timeout {
+		return nil, ErrEscrowExpired(escrow.Timeout)
+	}
+	return obj, nil
+}
+
+// bountyTransfers pays the bounty held by the escrow to the
+// arbiter if released, or back to the sender otherwise.
+// It returns nil if the escrow has no bounty.
+func bountyTransfers(obj orm.Object, released bool) []namecoin.Transfer {
+	escrow := AsEscrow(obj)
+	bounty := escrow.Bounty
+	if bounty == nil || !bounty.IsPositive() {
+		return nil
+	}
+	dest := weave.Permission(escrow.Sender).Address()
+	if released && escrow.Arbiter != nil {
+		dest = weave.Permission(escrow.Arbiter).Address()
+	}
+	src := NewCondition(obj.Key()).Address()
+	return []namecoin.Transfer{{Src: src, Dest: dest, Amount: *bounty}}
+}
@@ -0,0 +1,170 @@
+package escrow
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// maxChainDepth limits the escrows listed in funded_by,
+// so chained escrows stay small
+const maxChainDepth = 16
+
+// Validate makes sure the chain either tops up an escrow
+// or sets a new one
+func (c *ChainEscrow) Validate() error {
+	if len(c.EscrowId) == 0 {
+		if c.Recipient == nil {
+			return ErrMissingRecipient()
+		}
+		return nil
+	}
+	if c.Arbiter != nil || c.Recipient != nil || c.Timeout != 0 || c.Memo != "" {
+		return ErrInvalidChain("top up with new terms")
+	}
+	return validateEscrowID(c.EscrowId)
+}
+
+// createMsg returns the message creating the new escrow,
+// sent by the recipient of the released one
+func (c *ChainEscrow) createMsg(sender weave.Permission, amount x.Coins) *CreateEscrowMsg {
+	return NewCreateMsg(sender, c.Recipient, c.Arbiter, amount, c.Timeout, c.Memo)
+}
+
+// chainFunding returns the funded_by of an escrow once obj
+// paid into it. It fails if the escrow funded obj already,
+// as the coins would go round in a cycle.
+func chainFunding(obj orm.Object, id []byte, fundedBy [][]byte) ([][]byte, error) {
+	parent := AsEscrow(obj)
+	ancestors := append([][]byte{obj.Key()}, parent.FundedBy...)
+	if indexOf(ancestors, id) >= 0 {
+		return nil, ErrChainCycle(id)
+	}
+	res := append([][]byte{}, fundedBy...)
+	for _, a := range ancestors {
+		if indexOf(res, a) < 0 {
+			res = append(res, a)
+		}
+	}
+	if len(res) > maxChainDepth {
+		return nil, ErrInvalidChain("too long")
+	}
+	return res, nil
+}
+
+// checkChain verifies the chain of the release, as far as it
+// can be before the recipient is paid
+func (h ReleaseEscrowHandler) checkChain(ctx weave.Context, db weave.KVStore,
+	obj orm.Object, msg *ReleaseEscrowMsg) error {
+
+	escrow := AsEscrow(obj)
+	if err := msg.Chain.Validate(); err != nil {
+		return err
+	}
+	// the price sets what is paid only on release
+	if escrow.Target != nil {
+		return ErrInvalidChain("priced escrow")
+	}
+	// the coins are not paid to the recipient alone
+	if len(escrow.Shares) > 0 {
+		return ErrInvalidChain("split escrow")
+	}
+	// the coins are the recipient's to put elsewhere
+	rcpt := weave.Permission(escrow.Recipient)
+	if !h.auth.HasAddress(ctx, rcpt.Address()) {
+		return errors.ErrUnauthorized()
+	}
+
+	amount := x.Coins(msg.Amount)
+	if len(amount) == 0 {
+		amount = escrow.Amount
+	}
+	height, _ := weave.GetHeight(ctx)
+	if len(msg.Chain.EscrowId) == 0 {
+		cmsg := msg.Chain.createMsg(rcpt, amount)
+		if err := cmsg.Validate(); err != nil {
+			return err
+		}
+		if cmsg.Timeout <= height {
+			return ErrInvalidTimeout(cmsg.Timeout)
+		}
+		_, err := chainFunding(obj, nil, nil)
+		return err
+	}
+
+	next, err := h.bucket.Get(db, msg.Chain.EscrowId)
+	if err != nil {
+		return err
+	}
+	topUp := AsEscrow(next)
+	switch {
+	case topUp == nil:
+		return ErrNoSuchEscrow(msg.Chain.EscrowId)
+	case topUp.Quarantine != nil:
+		return ErrQuarantined(msg.Chain.EscrowId)
+	case !rcpt.Address().Equals(weave.Permission(topUp.Sender).Address()):
+		return ErrInvalidChain("not sent by the recipient")
+	case topUp.Target != nil:
+		return ErrInvalidChain("priced escrow")
+	case len(topUp.Milestones) > 0:
+		return ErrInvalidChain("milestone escrow")
+	case topUp.Timeout < height:
+		return ErrEscrowExpired(topUp.Timeout)
+	}
+	_, err = chainFunding(obj, next.Key(), topUp.FundedBy)
+	return err
+}
+
+// chain moves what the recipient was paid into the escrow of
+// the chain, it returns its id, the event recorded for it and
+// the transfers that funded it
+func (h ReleaseEscrowHandler) chain(ctx weave.Context, db weave.KVStore,
+	obj orm.Object, paid x.Coins, c *ChainEscrow) ([]byte, string, []namecoin.Transfer, error) {
+
+	rcpt := weave.Permission(AsEscrow(obj).Recipient)
+	if len(c.EscrowId) == 0 {
+		cmsg := c.createMsg(rcpt, paid)
+		// the recipient holds the coins by now
+		err := h.create.check(ctx, db, cmsg)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		next, transfers, err := h.create.create(ctx, db, cmsg)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		escrow := AsEscrow(next)
+		escrow.FundedBy, err = chainFunding(obj, next.Key(), nil)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		return next.Key(), EventCreate, transfers, h.bucket.Save(db, next)
+	}
+
+	next, err := h.bucket.Get(db, c.EscrowId)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	escrow := AsEscrow(next)
+	escrow.FundedBy, err = chainFunding(obj, next.Key(), escrow.FundedBy)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	escrow.Amount, err = addCoins(escrow.Amount, paid)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	transfers := namecoin.NewTransfers(rcpt.Address(), NewCondition(next.Key()).Address(), paid)
+	err = h.cash.WithContext(ctx).MoveCoinsBatch(db, transfers)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	err = h.history.Append(ctx, db, h.auth, next.Key(), EventFund, paid)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return next.Key(), EventFund, transfers, h.bucket.Save(db, next)
+}
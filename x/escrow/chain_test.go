@@ -0,0 +1,157 @@
+package escrow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/ledger"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// TestChainedRelease pays the release of one escrow into
+// another, down the tiers of a supply chain
+func TestChainedRelease(t *testing.T) {
+	var helpers x.TestHelpers
+	_, buyer := helpers.MakeKey()
+	_, maker := helpers.MakeKey()
+	_, supplier := helpers.MakeKey()
+	_, arbiter := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), control, NewBucket())
+
+	db := store.MemStore()
+	for _, perm := range []weave.Permission{buyer, maker} {
+		wallet, err := cash.WalletWith(perm.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+		require.NoError(t, err)
+		require.NoError(t, bank.Save(db, wallet))
+	}
+	deliver := func(msg weave.Msg, perms ...weave.Permission) (weave.DeliverResult, error) {
+		ctx := weave.WithHeight(context.Background(), 10)
+		ctx = authenticator().SetPermissions(ctx, perms...)
+		tx := helpers.MockTx(msg)
+		_, err := r.Check(ctx, db, tx)
+		if err != nil {
+			return weave.DeliverResult{}, err
+		}
+		return r.Deliver(ctx, db, tx)
+	}
+	create := func(from, to weave.Permission, amount int64) []byte {
+		msg := NewCreateMsg(from, to, arbiter,
+			mustCombineCoins(x.NewCoin(amount, 0, "FOO")), 1000, "")
+		res, err := deliver(msg, from)
+		require.NoError(t, err)
+		return res.Data
+	}
+	get := func(id []byte) *Escrow {
+		obj, err := NewBucket().Get(db, id)
+		require.NoError(t, err)
+		return AsEscrow(obj)
+	}
+	balance := func(addr weave.Address) x.Coins {
+		coins, err := control.Balance(db, addr)
+		require.NoError(t, err)
+		return coins
+	}
+
+	order := create(buyer, maker, 50)
+	chain := &ChainEscrow{Arbiter: arbiter, Recipient: supplier, Timeout: 500, Memo: "parts"}
+
+	// the maker must agree to pass the payment on
+	release := &ReleaseEscrowMsg{
+		EscrowId: order,
+		Amount:   mustCombineCoins(x.NewCoin(20, 0, "FOO")),
+		Chain:    chain,
+	}
+	_, err := deliver(release, arbiter)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+
+	res, err := deliver(release, arbiter, maker)
+	require.NoError(t, err)
+	parts := get(res.Data)
+	require.NotNil(t, parts)
+	assert.Equal(t, maker, weave.Permission(parts.Sender))
+	assert.Equal(t, supplier, weave.Permission(parts.Recipient))
+	assert.Equal(t, [][]byte{order}, parts.FundedBy)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(20, 0, "FOO")), balance(NewCondition(res.Data).Address()))
+	assert.Equal(t, mustCombineCoins(x.NewCoin(100, 0, "FOO")), balance(maker.Address()))
+	// the maker is paid and funds the new escrow at once
+	require.Len(t, res.Tags, 4)
+	amount := x.NewCoin(20, 0, "FOO")
+	assert.Equal(t, ledger.Tag(ledger.KindIn, maker.Address(), amount), res.Tags[0])
+	assert.Equal(t, EventTag(EventRelease, order), res.Tags[1])
+	assert.Equal(t, EventTag(EventCreate, res.Data), res.Tags[2])
+	assert.Equal(t, ledger.Tag(ledger.KindOut, maker.Address(), amount), res.Tags[3])
+
+	// the rest tops up the same escrow
+	res, err = deliver(&ReleaseEscrowMsg{EscrowId: order,
+		Chain: &ChainEscrow{EscrowId: res.Data}}, arbiter, maker)
+	require.NoError(t, err)
+	parts = get(res.Data)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(50, 0, "FOO")), x.Coins(parts.Amount))
+	assert.Equal(t, [][]byte{order}, parts.FundedBy)
+	assert.Nil(t, get(order))
+	assert.Equal(t, EventTag(EventFund, res.Data), res.Tags[2])
+	history, err := NewHistoryBucket().History(db, res.Data)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, EventFund, history[1].Event)
+
+	// only escrows of the recipient can be topped up
+	other := create(buyer, supplier, 10)
+	second := create(buyer, maker, 10)
+	_, err = deliver(&ReleaseEscrowMsg{EscrowId: second,
+		Chain: &ChainEscrow{EscrowId: other}}, arbiter, maker)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+
+	// no cycles: back is funded by there, so there can't be
+	// topped up by back
+	there := create(maker, buyer, 10)
+	res, err = deliver(&ReleaseEscrowMsg{EscrowId: there,
+		Amount: mustCombineCoins(x.NewCoin(5, 0, "FOO")),
+		Chain:  &ChainEscrow{Arbiter: arbiter, Recipient: maker, Timeout: 500}}, arbiter, buyer)
+	require.NoError(t, err)
+	back := res.Data
+	_, err = deliver(&ReleaseEscrowMsg{EscrowId: back,
+		Chain: &ChainEscrow{EscrowId: there}}, arbiter, maker)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+	assert.NotNil(t, get(back))
+}
+
+func TestChainEscrowValidate(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	id := []byte("12345678")
+
+	cases := map[string]struct {
+		chain *ChainEscrow
+		valid bool
+	}{
+		"new":            {&ChainEscrow{Recipient: a, Timeout: 5}, true},
+		"top up":         {&ChainEscrow{EscrowId: id}, true},
+		"no recipient":   {&ChainEscrow{Timeout: 5}, false},
+		"top up + terms": {&ChainEscrow{EscrowId: id, Memo: "hi"}, false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.chain.Validate()
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package escrow
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/utils"
+
+	"github.com/iov-one/bcp-demo/chaos"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// dump returns all keys and values in db, to compare states
+func dump(db weave.KVStore) [][2]string {
+	var res [][2]string
+	itr := db.Iterator(nil, nil)
+	defer itr.Close()
+	for ; itr.Valid(); itr.Next() {
+		res = append(res, [2]string{string(itr.Key()), string(itr.Value())})
+	}
+	return res
+}
+
+// TestSettlementRollback fails every coin move and every write
+// of each way to settle an escrow, and makes sure the savepoint
+// and recovery decorators leave nothing of it behind
+func TestSettlementRollback(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	amount := mustCombineCoins(x.NewCoin(10, 0, "FOO"), x.NewCoin(3, 0, "BAR"))
+	bounty := x.NewCoin(1, 0, "FOO")
+
+	inj := &chaos.Injector{}
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), inj.Controller(namecoin.NewController()), NewBucket())
+	h := app.ChainDecorators(utils.NewRecovery(), utils.NewSavepoint().OnDeliver(),
+		inj.Decorator()).WithHandler(r)
+	ctx := func(height int64, signer weave.Permission) weave.Context {
+		ctx := weave.WithHeight(context.Background(), height)
+		return authenticator().SetPermissions(ctx, signer)
+	}
+
+	// setup returns a store with one open escrow and its id
+	setup := func() (weave.KVStore, []byte) {
+		db := store.MemStore()
+		ctrl := namecoin.NewController()
+		for _, c := range append(amount, &bounty) {
+			require.NoError(t, ctrl.IssueCoins(db, a.Address(), *c))
+		}
+		msg := NewCreateMsg(a, b, a, amount, 1000, "")
+		msg.Bounty = &bounty
+		res, err := r.Deliver(ctx(500, a), db, helpers.MockTx(msg))
+		require.NoError(t, err)
+		return db, res.Data
+	}
+
+	cases := []struct {
+		name   string
+		height int64
+		signer weave.Permission
+		msg    func(id []byte) weave.Msg
+	}{
+		{"release", 600, a, func(id []byte) weave.Msg {
+			return &ReleaseEscrowMsg{EscrowId: id}
+		}},
+		{"partial release", 600, a, func(id []byte) weave.Msg {
+			return &ReleaseEscrowMsg{EscrowId: id, Amount: mustCombineCoins(x.NewCoin(4, 0, "FOO"))}
+		}},
+		{"refund", 600, b, func(id []byte) weave.Msg {
+			return &ReturnEscrowMsg{EscrowId: id}
+		}},
+		{"return", 1001, b, func(id []byte) weave.Msg {
+			return &ReturnEscrowMsg{EscrowId: id}
+		}},
+	}
+
+	for _, tc := range cases {
+		// a clean run tells how many calls there are to fail
+		db, id := setup()
+		*inj = chaos.Injector{}
+		tx := helpers.MockTx(tc.msg(id))
+		_, err := h.Deliver(ctx(tc.height, tc.signer), db, tx)
+		require.NoError(t, err, tc.name)
+		moves, writes := inj.Counts()
+		require.True(t, moves > 0 && writes > 0, tc.name)
+
+		var faults []chaos.Injector
+		for _, delay := range []bool{false, true} {
+			for _, mode := range []chaos.Mode{chaos.Fail, chaos.Panic} {
+				for i := 1; i <= moves; i++ {
+					faults = append(faults, chaos.Injector{Moves: chaos.Fault{Mode: mode, At: i}, Delay: delay})
+				}
+				for i := 1; i <= writes; i++ {
+					faults = append(faults, chaos.Injector{Writes: chaos.Fault{Mode: mode, At: i}, Delay: delay})
+				}
+			}
+		}
+		// and some combinations
+		rnd := rand.New(rand.NewSource(42))
+		for i := 0; i < 20; i++ {
+			faults = append(faults, chaos.Injector{
+				Moves:  chaos.RandomFault(rnd, moves),
+				Writes: chaos.RandomFault(rnd, writes),
+				Delay:  rnd.Intn(2) == 0,
+			})
+		}
+
+		for _, fault := range faults {
+			name := fmt.Sprintf("%s %+v", tc.name, fault)
+			db, id := setup()
+			before := dump(db)
+			*inj = fault
+			_, err := h.Deliver(ctx(tc.height, tc.signer), db, helpers.MockTx(tc.msg(id)))
+			require.Error(t, err, name)
+			assert.Equal(t, before, dump(db), name)
+
+			// and the escrow can still be settled
+			*inj = chaos.Injector{}
+			_, err = h.Deliver(ctx(tc.height, tc.signer), db, helpers.MockTx(tc.msg(id)))
+			require.NoError(t, err, name)
+		}
+	}
+}
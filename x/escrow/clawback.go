@@ -0,0 +1,118 @@
+package escrow
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/tracing"
+	"github.com/iov-one/bcp-demo/x/ledger"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// ClawbackEscrowHandler lets the compliance authority of an
+// escrow return it to the sender
+type ClawbackEscrowHandler struct {
+	auth    x.Authenticator
+	bucket  Store
+	history HistoryBucket
+	bids    BidBucket
+	cash    namecoin.Controller
+}
+
+var _ weave.Handler = ClawbackEscrowHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h ClawbackEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += returnEscrowCost
+	return res, nil
+}
+
+// Deliver returns all the escrow holds to the sender, the
+// deposit and bounty included, and closes it
+func (h ClawbackEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	escrow := AsEscrow(obj)
+
+	src := NewCondition(obj.Key()).Address()
+	sender := weave.Permission(escrow.Sender).Address()
+	transfers := namecoin.NewTransfers(src, sender, escrow.Amount)
+	transfers = append(transfers, depositTransfers(obj, sender)...)
+	transfers = append(transfers, bountyTransfers(obj, false)...)
+	err = h.cash.WithContext(ctx).MoveCoinsBatch(db, transfers)
+	if err != nil {
+		return res, err
+	}
+
+	err = deleteBids(db, h.bids, obj)
+	if err != nil {
+		return res, err
+	}
+	err = h.bucket.Delete(db, obj.Key())
+	if err != nil {
+		return res, err
+	}
+	err = h.history.AppendNote(ctx, db, h.auth, obj.Key(), EventClawback,
+		escrow.Amount, msg.Reason)
+	if err != nil {
+		return res, err
+	}
+	res.Tags = append(res.Tags, EventTag(EventClawback, obj.Key()))
+	res.Tags = append(res.Tags, ledger.TransferTags(ledger.KindIn, transfers)...)
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h ClawbackEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*ClawbackEscrowMsg, orm.Object, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*ClawbackEscrowMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := h.bucket.Get(db, msg.EscrowId)
+	if err != nil {
+		return nil, nil, err
+	}
+	escrow := AsEscrow(obj)
+	if escrow == nil {
+		return nil, nil, ErrNoSuchEscrow(msg.EscrowId)
+	}
+	if err := checkOpen(obj.Key(), escrow); err != nil {
+		return nil, nil, err
+	}
+
+	// only escrows created with an authority can be clawed back,
+	// and only by it, even after the timeout
+	if escrow.Clawback == nil ||
+		!h.auth.HasAddress(ctx, weave.Permission(escrow.Clawback).Address()) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	return msg, obj, nil
+}
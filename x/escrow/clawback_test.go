@@ -0,0 +1,91 @@
+package escrow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// TestClawback lets the compliance authority of an issuer return
+// a partly released escrow, but not one without an authority
+func TestClawback(t *testing.T) {
+	var helpers x.TestHelpers
+	_, sender := helpers.MakeKey()
+	_, arbiter := helpers.MakeKey()
+	_, rcpt := helpers.MakeKey()
+	_, compliance := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), control, NewBucket())
+
+	db := store.MemStore()
+	wallet, err := cash.WalletWith(sender.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, wallet))
+	deliver := func(height int64, msg weave.Msg, perm weave.Permission) ([]byte, error) {
+		ctx := weave.WithHeight(context.Background(), height)
+		ctx = authenticator().SetPermissions(ctx, perm)
+		tx := helpers.MockTx(msg)
+		_, err := r.Check(ctx, db, tx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := r.Deliver(ctx, db, tx)
+		return res.Data, err
+	}
+	balance := func(addr weave.Address) x.Coins {
+		coins, err := control.Balance(db, addr)
+		require.NoError(t, err)
+		return coins
+	}
+
+	plain, err := deliver(10, NewCreateMsg(sender, rcpt, arbiter,
+		mustCombineCoins(x.NewCoin(10, 0, "FOO")), 100, "plain"), sender)
+	require.NoError(t, err)
+	msg := NewCreateMsg(sender, rcpt, arbiter,
+		mustCombineCoins(x.NewCoin(20, 0, "FOO")), 100, "issued")
+	msg.Clawback = compliance
+	id, err := deliver(10, msg, sender)
+	require.NoError(t, err)
+	_, err = deliver(20, &ReleaseEscrowMsg{EscrowId: id,
+		Amount: mustCombineCoins(x.NewCoin(5, 0, "FOO"))}, arbiter)
+	require.NoError(t, err)
+
+	// only the authority of the escrow may claw it back
+	_, err = deliver(30, &ClawbackEscrowMsg{EscrowId: plain, Reason: "sanctions"}, compliance)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = deliver(30, &ClawbackEscrowMsg{EscrowId: id, Reason: "sanctions"}, arbiter)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = deliver(30, &ClawbackEscrowMsg{EscrowId: id}, compliance)
+	assert.Error(t, err)
+
+	// even after the timeout
+	_, err = deliver(200, &ClawbackEscrowMsg{EscrowId: id, Reason: "sanctions"}, compliance)
+	require.NoError(t, err)
+	obj, err := NewBucket().Get(db, id)
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(85, 0, "FOO")), balance(sender.Address()))
+	assert.Equal(t, mustCombineCoins(x.NewCoin(5, 0, "FOO")), balance(rcpt.Address()))
+
+	entries, err := NewHistoryBucket().History(db, id)
+	require.NoError(t, err)
+	last := entries[len(entries)-1]
+	assert.Equal(t, EventClawback, last.Event)
+	assert.Equal(t, compliance.Address(), weave.Address(last.Actor))
+	assert.Equal(t, "sanctions", last.Note)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(15, 0, "FOO")), x.Coins(last.Amount))
+}
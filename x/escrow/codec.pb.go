@@ -9,10 +9,46 @@
 
 	It has these top-level messages:
 		Escrow
+		Milestone
+		Dispute
+		Quarantine
+		Share
 		CreateEscrowMsg
+		CreateEscrowMsgV2
+		EscrowOptions
 		ReleaseEscrowMsg
+		ChainEscrow
 		ReturnEscrowMsg
 		UpdateEscrowPartiesMsg
+		UpdateEscrowObserversMsg
+		RevealMemoMsg
+		Bid
+		BidArbitrationMsg
+		AssignArbiterMsg
+		Params
+		Locked
+		NetEscrowsMsg
+		ArbiterPolicy
+		SetArbiterPolicyMsg
+		HistoryEntry
+		EscrowExport
+		Alias
+		EscrowTemplate
+		CreateFromTemplateMsg
+		SetTemplateMsg
+		Heartbeat
+		Escalation
+		PingEscrowMsg
+		SendEscrowMsg
+		EscrowInstructions
+		QuarantineEscrowMsg
+		RestoreEscrowMsg
+		ForceSettleEscrowMsg
+		ClawbackEscrowMsg
+		AttestMilestoneMsg
+		OfferEscrowPartyMsg
+		AcceptEscrowPartyMsg
+		DisputeEscrowMsg
 */
 package escrow
 
@@ -52,6 +88,74 @@ type Escrow struct {
 	Timeout int64 `protobuf:"varint,5,opt,name=timeout,proto3" json:"timeout,omitempty"`
 	// max length 128 character
 	Memo string `protobuf:"bytes,6,opt,name=memo,proto3" json:"memo,omitempty"`
+	// sender_can_release lets the sender release the coins
+	// to the recipient, as well as the arbiter
+	SenderCanRelease bool `protobuf:"varint,7,opt,name=sender_can_release,json=senderCanRelease,proto3" json:"sender_can_release,omitempty"`
+	// target, if set, is the value to pay the recipient in a
+	// reference currency (eg. 100 USD). The coins released are
+	// computed from the oracle price at release time, the rest
+	// goes back to the sender. Amount must be a single coin.
+	Target *x.Coin `protobuf:"bytes,8,opt,name=target" json:"target,omitempty"`
+	// min_price and max_price optionally bound the price of one
+	// escrowed coin in the target currency accepted on release
+	MinPrice *x.Coin `protobuf:"bytes,9,opt,name=min_price,json=minPrice" json:"min_price,omitempty"`
+	MaxPrice *x.Coin `protobuf:"bytes,10,opt,name=max_price,json=maxPrice" json:"max_price,omitempty"`
+	// deposit is taken from the sender on create, on top of the
+	// amount. It is refunded when the escrow is settled, and goes
+	// to the fee collector if the expiry job returns the escrow.
+	Deposit *x.Coin `protobuf:"bytes,11,opt,name=deposit" json:"deposit,omitempty"`
+	// bounty is held for arbitration. Without an arbiter it is the
+	// most bids may ask, once one is assigned it is the fee of the
+	// winning bid. It goes to the arbiter when the escrow is released
+	// and back to the sender when it is returned.
+	Bounty *x.Coin `protobuf:"bytes,12,opt,name=bounty" json:"bounty,omitempty"`
+	// observers are addresses that follow the escrow, eg. an
+	// accountant. They are listed in the history of its txs,
+	// but cannot act on it.
+	Observers [][]byte `protobuf:"bytes,13,rep,name=observers" json:"observers,omitempty"`
+	// memo_hash, if set, is the sha256 of a salt followed by the
+	// memo, which is kept off chain until it is revealed
+	MemoHash []byte `protobuf:"bytes,14,opt,name=memo_hash,json=memoHash,proto3" json:"memo_hash,omitempty"`
+	// heartbeat_window, if set, makes a dead man's switch: unless
+	// the sender pings the escrow at least every that many blocks,
+	// it is released to the recipient
+	HeartbeatWindow int64 `protobuf:"varint,15,opt,name=heartbeat_window,json=heartbeatWindow,proto3" json:"heartbeat_window,omitempty"`
+	// funded_by are the ids of the escrows whose releases funded
+	// this one, directly or through others, see ChainEscrow
+	FundedBy [][]byte `protobuf:"bytes,16,rep,name=funded_by,json=fundedBy" json:"funded_by,omitempty"`
+	// shares, if set, split every release between their addresses
+	// rather than paying it all to the recipient
+	Shares []*Share `protobuf:"bytes,17,rep,name=shares" json:"shares,omitempty"`
+	// quarantine, if set, freezes the escrow until an admin
+	// restores or settles it, see QuarantineEscrowMsg
+	Quarantine *Quarantine `protobuf:"bytes,18,opt,name=quarantine" json:"quarantine,omitempty"`
+	// clawback, if set, is a weave.Permission of a compliance
+	// authority that may return the escrow to the sender at any
+	// time before it is released, see ClawbackEscrowMsg
+	Clawback []byte `protobuf:"bytes,19,opt,name=clawback,proto3" json:"clawback,omitempty"`
+	// milestones, if set, split the amount into tranches that
+	// are released one by one as they are attested, see
+	// AttestMilestoneMsg. The amount is what the pending ones
+	// add up to.
+	Milestones []*Milestone `protobuf:"bytes,20,rep,name=milestones" json:"milestones,omitempty"`
+	// attester is a weave.Permission, eg. an oracle, that may
+	// attest milestones besides the arbiter
+	Attester []byte `protobuf:"bytes,21,opt,name=attester,proto3" json:"attester,omitempty"`
+	// dest_tag, if set, is the destination tag of every payment
+	// to the recipient, eg. the account of a user at an exchange,
+	// see package x/deposit
+	DestTag string `protobuf:"bytes,22,opt,name=dest_tag,json=destTag,proto3" json:"dest_tag,omitempty"`
+	// backup_arbiter, if set, is a weave.Permission, eg. the
+	// multisig of a governance panel, that becomes the arbiter of
+	// a dispute the arbiter leaves open for sla_blocks
+	BackupArbiter []byte `protobuf:"bytes,23,opt,name=backup_arbiter,json=backupArbiter,proto3" json:"backup_arbiter,omitempty"`
+	SlaBlocks     int64  `protobuf:"varint,24,opt,name=sla_blocks,json=slaBlocks,proto3" json:"sla_blocks,omitempty"`
+	// dispute, if set, is the dispute the arbiter has to act on,
+	// see DisputeEscrowMsg
+	Dispute *Dispute `protobuf:"bytes,25,opt,name=dispute" json:"dispute,omitempty"`
+	// policy_released is the total released so far with the
+	// policy of the arbiter, which max_amount applies to
+	PolicyReleased []*x.Coin `protobuf:"bytes,26,rep,name=policy_released,json=policyReleased" json:"policy_released,omitempty"`
 }
 
 func (m *Escrow) Reset()                    { *m = Escrow{} }
@@ -101,6 +205,270 @@ func (m *Escrow) GetMemo() string {
 	return ""
 }
 
+func (m *Escrow) GetSenderCanRelease() bool {
+	if m != nil {
+		return m.SenderCanRelease
+	}
+	return false
+}
+
+func (m *Escrow) GetTarget() *x.Coin {
+	if m != nil {
+		return m.Target
+	}
+	return nil
+}
+
+func (m *Escrow) GetMinPrice() *x.Coin {
+	if m != nil {
+		return m.MinPrice
+	}
+	return nil
+}
+
+func (m *Escrow) GetMaxPrice() *x.Coin {
+	if m != nil {
+		return m.MaxPrice
+	}
+	return nil
+}
+
+func (m *Escrow) GetDeposit() *x.Coin {
+	if m != nil {
+		return m.Deposit
+	}
+	return nil
+}
+
+func (m *Escrow) GetBounty() *x.Coin {
+	if m != nil {
+		return m.Bounty
+	}
+	return nil
+}
+
+func (m *Escrow) GetObservers() [][]byte {
+	if m != nil {
+		return m.Observers
+	}
+	return nil
+}
+
+func (m *Escrow) GetMemoHash() []byte {
+	if m != nil {
+		return m.MemoHash
+	}
+	return nil
+}
+
+func (m *Escrow) GetHeartbeatWindow() int64 {
+	if m != nil {
+		return m.HeartbeatWindow
+	}
+	return 0
+}
+
+func (m *Escrow) GetFundedBy() [][]byte {
+	if m != nil {
+		return m.FundedBy
+	}
+	return nil
+}
+
+func (m *Escrow) GetShares() []*Share {
+	if m != nil {
+		return m.Shares
+	}
+	return nil
+}
+
+func (m *Escrow) GetQuarantine() *Quarantine {
+	if m != nil {
+		return m.Quarantine
+	}
+	return nil
+}
+
+func (m *Escrow) GetClawback() []byte {
+	if m != nil {
+		return m.Clawback
+	}
+	return nil
+}
+
+func (m *Escrow) GetMilestones() []*Milestone {
+	if m != nil {
+		return m.Milestones
+	}
+	return nil
+}
+
+func (m *Escrow) GetAttester() []byte {
+	if m != nil {
+		return m.Attester
+	}
+	return nil
+}
+
+func (m *Escrow) GetDestTag() string {
+	if m != nil {
+		return m.DestTag
+	}
+	return ""
+}
+
+func (m *Escrow) GetBackupArbiter() []byte {
+	if m != nil {
+		return m.BackupArbiter
+	}
+	return nil
+}
+
+func (m *Escrow) GetSlaBlocks() int64 {
+	if m != nil {
+		return m.SlaBlocks
+	}
+	return 0
+}
+
+func (m *Escrow) GetDispute() *Dispute {
+	if m != nil {
+		return m.Dispute
+	}
+	return nil
+}
+
+func (m *Escrow) GetPolicyReleased() []*x.Coin {
+	if m != nil {
+		return m.PolicyReleased
+	}
+	return nil
+}
+
+// Milestone is one tranche of a milestone escrow
+type Milestone struct {
+	// name describes the work, eg. "prototype"
+	Name   string    `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Amount []*x.Coin `protobuf:"bytes,2,rep,name=amount" json:"amount,omitempty"`
+	// attested is the height it was attested and paid at,
+	// 0 while it is pending
+	Attested int64 `protobuf:"varint,3,opt,name=attested,proto3" json:"attested,omitempty"`
+}
+
+func (m *Milestone) Reset()                    { *m = Milestone{} }
+func (m *Milestone) String() string            { return proto.CompactTextString(m) }
+func (*Milestone) ProtoMessage()               {}
+func (*Milestone) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{1} }
+
+func (m *Milestone) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Milestone) GetAmount() []*x.Coin {
+	if m != nil {
+		return m.Amount
+	}
+	return nil
+}
+
+func (m *Milestone) GetAttested() int64 {
+	if m != nil {
+		return m.Attested
+	}
+	return 0
+}
+
+// Dispute records why and when a party disputed an escrow. It
+// is settled by the next release or attested milestone.
+type Dispute struct {
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	Height int64  `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	// escalated is the height the backup arbiter took over
+	// the dispute, 0 if not yet
+	Escalated int64 `protobuf:"varint,3,opt,name=escalated,proto3" json:"escalated,omitempty"`
+}
+
+func (m *Dispute) Reset()                    { *m = Dispute{} }
+func (m *Dispute) String() string            { return proto.CompactTextString(m) }
+func (*Dispute) ProtoMessage()               {}
+func (*Dispute) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{2} }
+
+func (m *Dispute) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *Dispute) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *Dispute) GetEscalated() int64 {
+	if m != nil {
+		return m.Escalated
+	}
+	return 0
+}
+
+// Quarantine records why and when an admin froze an escrow
+type Quarantine struct {
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	Height int64  `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (m *Quarantine) Reset()                    { *m = Quarantine{} }
+func (m *Quarantine) String() string            { return proto.CompactTextString(m) }
+func (*Quarantine) ProtoMessage()               {}
+func (*Quarantine) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{3} }
+
+func (m *Quarantine) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *Quarantine) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+// Share is the part of every release paid to an address, in
+// basis points (1/100 of a percent). The shares of an escrow
+// add up to 10000.
+type Share struct {
+	Address []byte `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Bps     int32  `protobuf:"varint,2,opt,name=bps,proto3" json:"bps,omitempty"`
+}
+
+func (m *Share) Reset()                    { *m = Share{} }
+func (m *Share) String() string            { return proto.CompactTextString(m) }
+func (*Share) ProtoMessage()               {}
+func (*Share) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{4} }
+
+func (m *Share) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *Share) GetBps() int32 {
+	if m != nil {
+		return m.Bps
+	}
+	return 0
+}
+
 // CreateEscrowMsg is a request to create an Escrow with some tokens.
 // If sender is not defined, it defaults to the first signer
 // The rest must be defined
@@ -115,12 +483,50 @@ type CreateEscrowMsg struct {
 	Timeout int64 `protobuf:"varint,5,opt,name=timeout,proto3" json:"timeout,omitempty"`
 	// max length 128 character
 	Memo string `protobuf:"bytes,6,opt,name=memo,proto3" json:"memo,omitempty"`
+	// sender_can_release lets the sender release the coins
+	// to the recipient, as well as the arbiter
+	SenderCanRelease bool `protobuf:"varint,7,opt,name=sender_can_release,json=senderCanRelease,proto3" json:"sender_can_release,omitempty"`
+	// target, if set, is the value to pay the recipient in a
+	// reference currency (eg. 100 USD). The coins released are
+	// computed from the oracle price at release time, the rest
+	// goes back to the sender. Amount must be a single coin.
+	Target *x.Coin `protobuf:"bytes,8,opt,name=target" json:"target,omitempty"`
+	// min_price and max_price optionally bound the price of one
+	// escrowed coin in the target currency accepted on release
+	MinPrice *x.Coin `protobuf:"bytes,9,opt,name=min_price,json=minPrice" json:"min_price,omitempty"`
+	MaxPrice *x.Coin `protobuf:"bytes,10,opt,name=max_price,json=maxPrice" json:"max_price,omitempty"`
+	// bounty, if set, is paid by the sender for arbitration.
+	// The arbiter may then be left empty, to be assigned from
+	// the bids of registered arbiters.
+	Bounty *x.Coin `protobuf:"bytes,11,opt,name=bounty" json:"bounty,omitempty"`
+	// observers follow the escrow without any power over it
+	Observers [][]byte `protobuf:"bytes,12,rep,name=observers" json:"observers,omitempty"`
+	// memo_hash replaces the memo with a commitment to it,
+	// see RevealMemoMsg
+	MemoHash []byte `protobuf:"bytes,13,opt,name=memo_hash,json=memoHash,proto3" json:"memo_hash,omitempty"`
+	// heartbeat_window makes a dead man's switch, see PingEscrowMsg
+	HeartbeatWindow int64 `protobuf:"varint,14,opt,name=heartbeat_window,json=heartbeatWindow,proto3" json:"heartbeat_window,omitempty"`
+	// shares split the releases between several payees
+	Shares []*Share `protobuf:"bytes,15,rep,name=shares" json:"shares,omitempty"`
+	// clawback lets a compliance authority return the escrow
+	Clawback []byte `protobuf:"bytes,16,opt,name=clawback,proto3" json:"clawback,omitempty"`
+	// milestones split the amount into tranches released by
+	// attestation, optionally also by the attester
+	Milestones []*Milestone `protobuf:"bytes,17,rep,name=milestones" json:"milestones,omitempty"`
+	Attester   []byte       `protobuf:"bytes,18,opt,name=attester,proto3" json:"attester,omitempty"`
+	// dest_tag tags the payments to the recipient, max length
+	// 128 character
+	DestTag string `protobuf:"bytes,19,opt,name=dest_tag,json=destTag,proto3" json:"dest_tag,omitempty"`
+	// backup_arbiter takes over disputes the arbiter leaves
+	// open for sla_blocks
+	BackupArbiter []byte `protobuf:"bytes,20,opt,name=backup_arbiter,json=backupArbiter,proto3" json:"backup_arbiter,omitempty"`
+	SlaBlocks     int64  `protobuf:"varint,21,opt,name=sla_blocks,json=slaBlocks,proto3" json:"sla_blocks,omitempty"`
 }
 
 func (m *CreateEscrowMsg) Reset()                    { *m = CreateEscrowMsg{} }
 func (m *CreateEscrowMsg) String() string            { return proto.CompactTextString(m) }
 func (*CreateEscrowMsg) ProtoMessage()               {}
-func (*CreateEscrowMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{1} }
+func (*CreateEscrowMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{5} }
 
 func (m *CreateEscrowMsg) GetSender() []byte {
 	if m != nil {
@@ -164,457 +570,10325 @@ func (m *CreateEscrowMsg) GetMemo() string {
 	return ""
 }
 
-// ReleaseEscrowMsg releases the content to the recipient.
-// Must be authorized by sender or arbiter.
-// If amount not provided, defaults to entire escrow,
-// May be a subset of the current balance.
-type ReleaseEscrowMsg struct {
-	EscrowId []byte    `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
-	Amount   []*x.Coin `protobuf:"bytes,2,rep,name=amount" json:"amount,omitempty"`
+func (m *CreateEscrowMsg) GetSenderCanRelease() bool {
+	if m != nil {
+		return m.SenderCanRelease
+	}
+	return false
 }
 
-func (m *ReleaseEscrowMsg) Reset()                    { *m = ReleaseEscrowMsg{} }
-func (m *ReleaseEscrowMsg) String() string            { return proto.CompactTextString(m) }
-func (*ReleaseEscrowMsg) ProtoMessage()               {}
-func (*ReleaseEscrowMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{2} }
-
-func (m *ReleaseEscrowMsg) GetEscrowId() []byte {
+func (m *CreateEscrowMsg) GetTarget() *x.Coin {
 	if m != nil {
-		return m.EscrowId
+		return m.Target
 	}
 	return nil
 }
 
-func (m *ReleaseEscrowMsg) GetAmount() []*x.Coin {
+func (m *CreateEscrowMsg) GetMinPrice() *x.Coin {
 	if m != nil {
-		return m.Amount
+		return m.MinPrice
 	}
 	return nil
 }
 
-// ReturnEscrowMsg returns the content to the sender.
-// Must be authorized by the sender or an expired timeout
-type ReturnEscrowMsg struct {
-	EscrowId []byte `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+func (m *CreateEscrowMsg) GetMaxPrice() *x.Coin {
+	if m != nil {
+		return m.MaxPrice
+	}
+	return nil
 }
 
-func (m *ReturnEscrowMsg) Reset()                    { *m = ReturnEscrowMsg{} }
-func (m *ReturnEscrowMsg) String() string            { return proto.CompactTextString(m) }
-func (*ReturnEscrowMsg) ProtoMessage()               {}
-func (*ReturnEscrowMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{3} }
+func (m *CreateEscrowMsg) GetBounty() *x.Coin {
+	if m != nil {
+		return m.Bounty
+	}
+	return nil
+}
 
-func (m *ReturnEscrowMsg) GetEscrowId() []byte {
+func (m *CreateEscrowMsg) GetObservers() [][]byte {
 	if m != nil {
-		return m.EscrowId
+		return m.Observers
 	}
 	return nil
 }
 
-// UpdateEscrowPartiesMsg changes any of the parties of the escrow:
-// sender, arbiter, recipient. This must be authorized by the current
-// holder of that position (eg. only sender can update sender).
-//
-// Represents delegating responsibility
-type UpdateEscrowPartiesMsg struct {
-	EscrowId  []byte `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
-	Sender    []byte `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
-	Arbiter   []byte `protobuf:"bytes,3,opt,name=arbiter,proto3" json:"arbiter,omitempty"`
-	Recipient []byte `protobuf:"bytes,4,opt,name=recipient,proto3" json:"recipient,omitempty"`
+func (m *CreateEscrowMsg) GetMemoHash() []byte {
+	if m != nil {
+		return m.MemoHash
+	}
+	return nil
 }
 
-func (m *UpdateEscrowPartiesMsg) Reset()                    { *m = UpdateEscrowPartiesMsg{} }
-func (m *UpdateEscrowPartiesMsg) String() string            { return proto.CompactTextString(m) }
-func (*UpdateEscrowPartiesMsg) ProtoMessage()               {}
-func (*UpdateEscrowPartiesMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{4} }
+func (m *CreateEscrowMsg) GetHeartbeatWindow() int64 {
+	if m != nil {
+		return m.HeartbeatWindow
+	}
+	return 0
+}
 
-func (m *UpdateEscrowPartiesMsg) GetEscrowId() []byte {
+func (m *CreateEscrowMsg) GetShares() []*Share {
 	if m != nil {
-		return m.EscrowId
+		return m.Shares
 	}
 	return nil
 }
 
-func (m *UpdateEscrowPartiesMsg) GetSender() []byte {
+func (m *CreateEscrowMsg) GetClawback() []byte {
 	if m != nil {
-		return m.Sender
+		return m.Clawback
 	}
 	return nil
 }
 
-func (m *UpdateEscrowPartiesMsg) GetArbiter() []byte {
+func (m *CreateEscrowMsg) GetMilestones() []*Milestone {
 	if m != nil {
-		return m.Arbiter
+		return m.Milestones
 	}
 	return nil
 }
 
-func (m *UpdateEscrowPartiesMsg) GetRecipient() []byte {
+func (m *CreateEscrowMsg) GetAttester() []byte {
 	if m != nil {
-		return m.Recipient
+		return m.Attester
 	}
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*Escrow)(nil), "escrow.Escrow")
-	proto.RegisterType((*CreateEscrowMsg)(nil), "escrow.CreateEscrowMsg")
-	proto.RegisterType((*ReleaseEscrowMsg)(nil), "escrow.ReleaseEscrowMsg")
-	proto.RegisterType((*ReturnEscrowMsg)(nil), "escrow.ReturnEscrowMsg")
-	proto.RegisterType((*UpdateEscrowPartiesMsg)(nil), "escrow.UpdateEscrowPartiesMsg")
-}
-func (m *Escrow) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *CreateEscrowMsg) GetDestTag() string {
+	if m != nil {
+		return m.DestTag
 	}
-	return dAtA[:n], nil
+	return ""
 }
 
-func (m *Escrow) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Sender) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(len(m.Sender)))
-		i += copy(dAtA[i:], m.Sender)
+func (m *CreateEscrowMsg) GetBackupArbiter() []byte {
+	if m != nil {
+		return m.BackupArbiter
 	}
-	if len(m.Arbiter) > 0 {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(len(m.Arbiter)))
-		i += copy(dAtA[i:], m.Arbiter)
+	return nil
+}
+
+func (m *CreateEscrowMsg) GetSlaBlocks() int64 {
+	if m != nil {
+		return m.SlaBlocks
 	}
-	if len(m.Recipient) > 0 {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(len(m.Recipient)))
-		i += copy(dAtA[i:], m.Recipient)
+	return 0
+}
+
+// CreateEscrowMsgV2 is the second version of CreateEscrowMsg.
+// It is routed to the same handler, which adapts it to the
+// first version. The optional settings are grouped in options,
+// so new features only extend EscrowOptions. Nodes skip fields
+// they don't know, so old clients keep working.
+type CreateEscrowMsgV2 struct {
+	// Sender, Arbiter, Recipient are all weave.Permission
+	Sender    []byte    `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
+	Arbiter   []byte    `protobuf:"bytes,2,opt,name=arbiter,proto3" json:"arbiter,omitempty"`
+	Recipient []byte    `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Amount    []*x.Coin `protobuf:"bytes,4,rep,name=amount" json:"amount,omitempty"`
+	// exactly one of timeout, an absolute height, and
+	// timeout_in, a number of blocks after delivery, is set
+	Timeout   int64 `protobuf:"varint,5,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	TimeoutIn int64 `protobuf:"varint,6,opt,name=timeout_in,json=timeoutIn,proto3" json:"timeout_in,omitempty"`
+	// max length 128 character
+	Memo    string         `protobuf:"bytes,7,opt,name=memo,proto3" json:"memo,omitempty"`
+	Options *EscrowOptions `protobuf:"bytes,8,opt,name=options" json:"options,omitempty"`
+}
+
+func (m *CreateEscrowMsgV2) Reset()                    { *m = CreateEscrowMsgV2{} }
+func (m *CreateEscrowMsgV2) String() string            { return proto.CompactTextString(m) }
+func (*CreateEscrowMsgV2) ProtoMessage()               {}
+func (*CreateEscrowMsgV2) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{6} }
+
+func (m *CreateEscrowMsgV2) GetSender() []byte {
+	if m != nil {
+		return m.Sender
 	}
-	if len(m.Amount) > 0 {
-		for _, msg := range m.Amount {
-			dAtA[i] = 0x22
-			i++
-			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
-		}
+	return nil
+}
+
+func (m *CreateEscrowMsgV2) GetArbiter() []byte {
+	if m != nil {
+		return m.Arbiter
 	}
-	if m.Timeout != 0 {
-		dAtA[i] = 0x28
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(m.Timeout))
+	return nil
+}
+
+func (m *CreateEscrowMsgV2) GetRecipient() []byte {
+	if m != nil {
+		return m.Recipient
 	}
-	if len(m.Memo) > 0 {
-		dAtA[i] = 0x32
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(len(m.Memo)))
-		i += copy(dAtA[i:], m.Memo)
+	return nil
+}
+
+func (m *CreateEscrowMsgV2) GetAmount() []*x.Coin {
+	if m != nil {
+		return m.Amount
 	}
-	return i, nil
+	return nil
 }
 
-func (m *CreateEscrowMsg) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *CreateEscrowMsgV2) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
 	}
-	return dAtA[:n], nil
+	return 0
 }
 
-func (m *CreateEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Sender) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(len(m.Sender)))
-		i += copy(dAtA[i:], m.Sender)
+func (m *CreateEscrowMsgV2) GetTimeoutIn() int64 {
+	if m != nil {
+		return m.TimeoutIn
 	}
-	if len(m.Arbiter) > 0 {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(len(m.Arbiter)))
-		i += copy(dAtA[i:], m.Arbiter)
+	return 0
+}
+
+func (m *CreateEscrowMsgV2) GetMemo() string {
+	if m != nil {
+		return m.Memo
 	}
-	if len(m.Recipient) > 0 {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(len(m.Recipient)))
-		i += copy(dAtA[i:], m.Recipient)
+	return ""
+}
+
+func (m *CreateEscrowMsgV2) GetOptions() *EscrowOptions {
+	if m != nil {
+		return m.Options
 	}
-	if len(m.Amount) > 0 {
-		for _, msg := range m.Amount {
-			dAtA[i] = 0x22
-			i++
-			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
-		}
+	return nil
+}
+
+// EscrowOptions are the optional settings of an escrow,
+// as described in CreateEscrowMsg
+type EscrowOptions struct {
+	SenderCanRelease bool         `protobuf:"varint,1,opt,name=sender_can_release,json=senderCanRelease,proto3" json:"sender_can_release,omitempty"`
+	Target           *x.Coin      `protobuf:"bytes,2,opt,name=target" json:"target,omitempty"`
+	MinPrice         *x.Coin      `protobuf:"bytes,3,opt,name=min_price,json=minPrice" json:"min_price,omitempty"`
+	MaxPrice         *x.Coin      `protobuf:"bytes,4,opt,name=max_price,json=maxPrice" json:"max_price,omitempty"`
+	Bounty           *x.Coin      `protobuf:"bytes,5,opt,name=bounty" json:"bounty,omitempty"`
+	Observers        [][]byte     `protobuf:"bytes,6,rep,name=observers" json:"observers,omitempty"`
+	MemoHash         []byte       `protobuf:"bytes,7,opt,name=memo_hash,json=memoHash,proto3" json:"memo_hash,omitempty"`
+	HeartbeatWindow  int64        `protobuf:"varint,8,opt,name=heartbeat_window,json=heartbeatWindow,proto3" json:"heartbeat_window,omitempty"`
+	Shares           []*Share     `protobuf:"bytes,9,rep,name=shares" json:"shares,omitempty"`
+	Clawback         []byte       `protobuf:"bytes,10,opt,name=clawback,proto3" json:"clawback,omitempty"`
+	Milestones       []*Milestone `protobuf:"bytes,11,rep,name=milestones" json:"milestones,omitempty"`
+	Attester         []byte       `protobuf:"bytes,12,opt,name=attester,proto3" json:"attester,omitempty"`
+	DestTag          string       `protobuf:"bytes,13,opt,name=dest_tag,json=destTag,proto3" json:"dest_tag,omitempty"`
+	BackupArbiter    []byte       `protobuf:"bytes,14,opt,name=backup_arbiter,json=backupArbiter,proto3" json:"backup_arbiter,omitempty"`
+	SlaBlocks        int64        `protobuf:"varint,15,opt,name=sla_blocks,json=slaBlocks,proto3" json:"sla_blocks,omitempty"`
+}
+
+func (m *EscrowOptions) Reset()                    { *m = EscrowOptions{} }
+func (m *EscrowOptions) String() string            { return proto.CompactTextString(m) }
+func (*EscrowOptions) ProtoMessage()               {}
+func (*EscrowOptions) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{7} }
+
+func (m *EscrowOptions) GetSenderCanRelease() bool {
+	if m != nil {
+		return m.SenderCanRelease
 	}
-	if m.Timeout != 0 {
-		dAtA[i] = 0x28
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(m.Timeout))
+	return false
+}
+
+func (m *EscrowOptions) GetTarget() *x.Coin {
+	if m != nil {
+		return m.Target
 	}
-	if len(m.Memo) > 0 {
-		dAtA[i] = 0x32
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(len(m.Memo)))
-		i += copy(dAtA[i:], m.Memo)
+	return nil
+}
+
+func (m *EscrowOptions) GetMinPrice() *x.Coin {
+	if m != nil {
+		return m.MinPrice
 	}
-	return i, nil
+	return nil
 }
 
-func (m *ReleaseEscrowMsg) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *EscrowOptions) GetMaxPrice() *x.Coin {
+	if m != nil {
+		return m.MaxPrice
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *ReleaseEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.EscrowId) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
-		i += copy(dAtA[i:], m.EscrowId)
+func (m *EscrowOptions) GetBounty() *x.Coin {
+	if m != nil {
+		return m.Bounty
 	}
-	if len(m.Amount) > 0 {
-		for _, msg := range m.Amount {
-			dAtA[i] = 0x12
-			i++
-			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
-		}
+	return nil
+}
+
+func (m *EscrowOptions) GetObservers() [][]byte {
+	if m != nil {
+		return m.Observers
 	}
-	return i, nil
+	return nil
 }
 
-func (m *ReturnEscrowMsg) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *EscrowOptions) GetMemoHash() []byte {
+	if m != nil {
+		return m.MemoHash
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *ReturnEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.EscrowId) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
-		i += copy(dAtA[i:], m.EscrowId)
+func (m *EscrowOptions) GetHeartbeatWindow() int64 {
+	if m != nil {
+		return m.HeartbeatWindow
 	}
-	return i, nil
+	return 0
 }
 
-func (m *UpdateEscrowPartiesMsg) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (m *EscrowOptions) GetShares() []*Share {
+	if m != nil {
+		return m.Shares
 	}
-	return dAtA[:n], nil
+	return nil
 }
 
-func (m *UpdateEscrowPartiesMsg) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.EscrowId) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
-		i += copy(dAtA[i:], m.EscrowId)
+func (m *EscrowOptions) GetClawback() []byte {
+	if m != nil {
+		return m.Clawback
 	}
-	if len(m.Sender) > 0 {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(len(m.Sender)))
-		i += copy(dAtA[i:], m.Sender)
+	return nil
+}
+
+func (m *EscrowOptions) GetMilestones() []*Milestone {
+	if m != nil {
+		return m.Milestones
 	}
-	if len(m.Arbiter) > 0 {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(len(m.Arbiter)))
-		i += copy(dAtA[i:], m.Arbiter)
+	return nil
+}
+
+func (m *EscrowOptions) GetAttester() []byte {
+	if m != nil {
+		return m.Attester
 	}
-	if len(m.Recipient) > 0 {
-		dAtA[i] = 0x22
-		i++
-		i = encodeVarintCodec(dAtA, i, uint64(len(m.Recipient)))
-		i += copy(dAtA[i:], m.Recipient)
+	return nil
+}
+
+func (m *EscrowOptions) GetDestTag() string {
+	if m != nil {
+		return m.DestTag
 	}
-	return i, nil
+	return ""
 }
 
-func encodeVarintCodec(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *EscrowOptions) GetBackupArbiter() []byte {
+	if m != nil {
+		return m.BackupArbiter
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return nil
 }
-func (m *Escrow) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.Sender)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
+
+func (m *EscrowOptions) GetSlaBlocks() int64 {
+	if m != nil {
+		return m.SlaBlocks
 	}
-	l = len(m.Arbiter)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
+	return 0
+}
+
+// ReleaseEscrowMsg releases the content to the recipient.
+// Must be authorized by the arbiter, or the sender if the
+// escrow allows it.
+// If amount not provided, defaults to entire escrow,
+// May be a subset of the current balance.
+type ReleaseEscrowMsg struct {
+	EscrowId []byte    `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+	Amount   []*x.Coin `protobuf:"bytes,2,rep,name=amount" json:"amount,omitempty"`
+	// policy asks for the release under the standing policy
+	// of the arbiter, rather than signed by the arbiter
+	Policy bool `protobuf:"varint,3,opt,name=policy,proto3" json:"policy,omitempty"`
+	// chain, if set, puts what the recipient is paid into
+	// another escrow in the same tx
+	Chain *ChainEscrow `protobuf:"bytes,4,opt,name=chain" json:"chain,omitempty"`
+}
+
+func (m *ReleaseEscrowMsg) Reset()                    { *m = ReleaseEscrowMsg{} }
+func (m *ReleaseEscrowMsg) String() string            { return proto.CompactTextString(m) }
+func (*ReleaseEscrowMsg) ProtoMessage()               {}
+func (*ReleaseEscrowMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{8} }
+
+func (m *ReleaseEscrowMsg) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
 	}
-	l = len(m.Recipient)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
+	return nil
+}
+
+func (m *ReleaseEscrowMsg) GetAmount() []*x.Coin {
+	if m != nil {
+		return m.Amount
 	}
-	if len(m.Amount) > 0 {
-		for _, e := range m.Amount {
-			l = e.Size()
-			n += 1 + l + sovCodec(uint64(l))
-		}
+	return nil
+}
+
+func (m *ReleaseEscrowMsg) GetPolicy() bool {
+	if m != nil {
+		return m.Policy
 	}
-	if m.Timeout != 0 {
-		n += 1 + sovCodec(uint64(m.Timeout))
+	return false
+}
+
+func (m *ReleaseEscrowMsg) GetChain() *ChainEscrow {
+	if m != nil {
+		return m.Chain
 	}
-	l = len(m.Memo)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
+	return nil
+}
+
+// ChainEscrow directs a release into another escrow sent by the
+// recipient, who must sign the release as well. It either tops up
+// the open escrow with escrow_id, or creates a new one with the
+// other fields as in CreateEscrowMsg.
+type ChainEscrow struct {
+	EscrowId  []byte `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+	Arbiter   []byte `protobuf:"bytes,2,opt,name=arbiter,proto3" json:"arbiter,omitempty"`
+	Recipient []byte `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Timeout   int64  `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Memo      string `protobuf:"bytes,5,opt,name=memo,proto3" json:"memo,omitempty"`
+}
+
+func (m *ChainEscrow) Reset()                    { *m = ChainEscrow{} }
+func (m *ChainEscrow) String() string            { return proto.CompactTextString(m) }
+func (*ChainEscrow) ProtoMessage()               {}
+func (*ChainEscrow) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{9} }
+
+func (m *ChainEscrow) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
 	}
-	return n
+	return nil
 }
 
-func (m *CreateEscrowMsg) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.Sender)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
+func (m *ChainEscrow) GetArbiter() []byte {
+	if m != nil {
+		return m.Arbiter
 	}
-	l = len(m.Arbiter)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
+	return nil
+}
+
+func (m *ChainEscrow) GetRecipient() []byte {
+	if m != nil {
+		return m.Recipient
 	}
-	l = len(m.Recipient)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
+	return nil
+}
+
+func (m *ChainEscrow) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
 	}
-	if len(m.Amount) > 0 {
-		for _, e := range m.Amount {
-			l = e.Size()
-			n += 1 + l + sovCodec(uint64(l))
-		}
+	return 0
+}
+
+func (m *ChainEscrow) GetMemo() string {
+	if m != nil {
+		return m.Memo
 	}
-	if m.Timeout != 0 {
-		n += 1 + sovCodec(uint64(m.Timeout))
+	return ""
+}
+
+// ReturnEscrowMsg returns the content to the sender.
+// Anyone can return it after the timeout, before that it
+// must be authorized by the recipient (a refund).
+type ReturnEscrowMsg struct {
+	EscrowId []byte `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+}
+
+func (m *ReturnEscrowMsg) Reset()                    { *m = ReturnEscrowMsg{} }
+func (m *ReturnEscrowMsg) String() string            { return proto.CompactTextString(m) }
+func (*ReturnEscrowMsg) ProtoMessage()               {}
+func (*ReturnEscrowMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{10} }
+
+func (m *ReturnEscrowMsg) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
 	}
-	l = len(m.Memo)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
+	return nil
+}
+
+// UpdateEscrowPartiesMsg proposes new parties for the escrow:
+// sender, arbiter, recipient. This must be authorized by the current
+// holder of that position (eg. only sender can update sender).
+// Each is offered as with OfferEscrowPartyMsg, and only changes
+// once the new party accepts it within the offer window.
+//
+// Represents delegating responsibility
+type UpdateEscrowPartiesMsg struct {
+	EscrowId  []byte `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+	Sender    []byte `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
+	Arbiter   []byte `protobuf:"bytes,3,opt,name=arbiter,proto3" json:"arbiter,omitempty"`
+	Recipient []byte `protobuf:"bytes,4,opt,name=recipient,proto3" json:"recipient,omitempty"`
+}
+
+func (m *UpdateEscrowPartiesMsg) Reset()                    { *m = UpdateEscrowPartiesMsg{} }
+func (m *UpdateEscrowPartiesMsg) String() string            { return proto.CompactTextString(m) }
+func (*UpdateEscrowPartiesMsg) ProtoMessage()               {}
+func (*UpdateEscrowPartiesMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{11} }
+
+func (m *UpdateEscrowPartiesMsg) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
 	}
-	return n
+	return nil
 }
 
-func (m *ReleaseEscrowMsg) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.EscrowId)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
+func (m *UpdateEscrowPartiesMsg) GetSender() []byte {
+	if m != nil {
+		return m.Sender
 	}
-	if len(m.Amount) > 0 {
-		for _, e := range m.Amount {
-			l = e.Size()
-			n += 1 + l + sovCodec(uint64(l))
+	return nil
+}
+
+func (m *UpdateEscrowPartiesMsg) GetArbiter() []byte {
+	if m != nil {
+		return m.Arbiter
+	}
+	return nil
+}
+
+func (m *UpdateEscrowPartiesMsg) GetRecipient() []byte {
+	if m != nil {
+		return m.Recipient
+	}
+	return nil
+}
+
+// UpdateEscrowObserversMsg adds and removes observers of an
+// escrow. Only the sender can change them.
+type UpdateEscrowObserversMsg struct {
+	EscrowId []byte   `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+	Add      [][]byte `protobuf:"bytes,2,rep,name=add" json:"add,omitempty"`
+	Remove   [][]byte `protobuf:"bytes,3,rep,name=remove" json:"remove,omitempty"`
+}
+
+func (m *UpdateEscrowObserversMsg) Reset()                    { *m = UpdateEscrowObserversMsg{} }
+func (m *UpdateEscrowObserversMsg) String() string            { return proto.CompactTextString(m) }
+func (*UpdateEscrowObserversMsg) ProtoMessage()               {}
+func (*UpdateEscrowObserversMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{12} }
+
+func (m *UpdateEscrowObserversMsg) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
+	}
+	return nil
+}
+
+func (m *UpdateEscrowObserversMsg) GetAdd() [][]byte {
+	if m != nil {
+		return m.Add
+	}
+	return nil
+}
+
+func (m *UpdateEscrowObserversMsg) GetRemove() [][]byte {
+	if m != nil {
+		return m.Remove
+	}
+	return nil
+}
+
+// RevealMemoMsg publishes the memo of an escrow created with
+// only its hash, eg. for a dispute. It must be signed by one of
+// the parties, and sha256(salt || memo) must be the memo_hash.
+type RevealMemoMsg struct {
+	EscrowId []byte `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+	Memo     string `protobuf:"bytes,2,opt,name=memo,proto3" json:"memo,omitempty"`
+	Salt     []byte `protobuf:"bytes,3,opt,name=salt,proto3" json:"salt,omitempty"`
+}
+
+func (m *RevealMemoMsg) Reset()                    { *m = RevealMemoMsg{} }
+func (m *RevealMemoMsg) String() string            { return proto.CompactTextString(m) }
+func (*RevealMemoMsg) ProtoMessage()               {}
+func (*RevealMemoMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{13} }
+
+func (m *RevealMemoMsg) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
+	}
+	return nil
+}
+
+func (m *RevealMemoMsg) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
+func (m *RevealMemoMsg) GetSalt() []byte {
+	if m != nil {
+		return m.Salt
+	}
+	return nil
+}
+
+// Bid is the offer of an arbiter to take the arbitration of
+// an escrow. Bids are stored under the escrow id and the
+// address of the arbiter.
+type Bid struct {
+	// arbiter is the weave.Permission of the bidder
+	Arbiter []byte `protobuf:"bytes,1,opt,name=arbiter,proto3" json:"arbiter,omitempty"`
+	// fee is the part of the bounty the arbiter asks for
+	Fee *x.Coin `protobuf:"bytes,2,opt,name=fee" json:"fee,omitempty"`
+	// height the bid was made at, the earliest wins a tie
+	Height int64 `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (m *Bid) Reset()                    { *m = Bid{} }
+func (m *Bid) String() string            { return proto.CompactTextString(m) }
+func (*Bid) ProtoMessage()               {}
+func (*Bid) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{14} }
+
+func (m *Bid) GetArbiter() []byte {
+	if m != nil {
+		return m.Arbiter
+	}
+	return nil
+}
+
+func (m *Bid) GetFee() *x.Coin {
+	if m != nil {
+		return m.Fee
+	}
+	return nil
+}
+
+func (m *Bid) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+// BidArbitrationMsg offers to arbitrate an escrow without an
+// arbiter for a fee. The main signer must hold the arbiter role.
+// A new bid replaces the previous one of the same signer.
+type BidArbitrationMsg struct {
+	EscrowId []byte  `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+	Fee      *x.Coin `protobuf:"bytes,2,opt,name=fee" json:"fee,omitempty"`
+}
+
+func (m *BidArbitrationMsg) Reset()                    { *m = BidArbitrationMsg{} }
+func (m *BidArbitrationMsg) String() string            { return proto.CompactTextString(m) }
+func (*BidArbitrationMsg) ProtoMessage()               {}
+func (*BidArbitrationMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{15} }
+
+func (m *BidArbitrationMsg) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
+	}
+	return nil
+}
+
+func (m *BidArbitrationMsg) GetFee() *x.Coin {
+	if m != nil {
+		return m.Fee
+	}
+	return nil
+}
+
+// AssignArbiterMsg makes a bidder the arbiter of an escrow.
+// Must be authorized by the sender. Without a bidder, the
+// lowest bid is accepted.
+type AssignArbiterMsg struct {
+	EscrowId []byte `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+	// bidder is the address of the accepted bid
+	Bidder []byte `protobuf:"bytes,2,opt,name=bidder,proto3" json:"bidder,omitempty"`
+}
+
+func (m *AssignArbiterMsg) Reset()                    { *m = AssignArbiterMsg{} }
+func (m *AssignArbiterMsg) String() string            { return proto.CompactTextString(m) }
+func (*AssignArbiterMsg) ProtoMessage()               {}
+func (*AssignArbiterMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{16} }
+
+func (m *AssignArbiterMsg) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
+	}
+	return nil
+}
+
+func (m *AssignArbiterMsg) GetBidder() []byte {
+	if m != nil {
+		return m.Bidder
+	}
+	return nil
+}
+
+// Params are the chain wide settings of the escrow module,
+// set in genesis
+type Params struct {
+	// dust_threshold holds an amount per ticker. If a partial
+	// release leaves less than that of every remaining ticker,
+	// the rest is swept and the escrow closed.
+	DustThreshold []*x.Coin `protobuf:"bytes,1,rep,name=dust_threshold,json=dustThreshold" json:"dust_threshold,omitempty"`
+	// dust_to_sender sweeps the dust back to the sender,
+	// rather than to the recipient
+	DustToSender bool `protobuf:"varint,2,opt,name=dust_to_sender,json=dustToSender,proto3" json:"dust_to_sender,omitempty"`
+	// max_per_sender limits the open escrows of one sender,
+	// 0 means no limit
+	MaxPerSender int64 `protobuf:"varint,3,opt,name=max_per_sender,json=maxPerSender,proto3" json:"max_per_sender,omitempty"`
+	// max_locked limits the total value held in escrows for
+	// each ticker. Tickers not listed have no limit.
+	MaxLocked []*x.Coin `protobuf:"bytes,4,rep,name=max_locked,json=maxLocked" json:"max_locked,omitempty"`
+	// gas_per_byte is charged on create for every byte of
+	// memo and coins the escrow stores, 0 means free
+	GasPerByte int64 `protobuf:"varint,5,opt,name=gas_per_byte,json=gasPerByte,proto3" json:"gas_per_byte,omitempty"`
+	// deposit_per_block is the refundable deposit for every block
+	// until the timeout of a new escrow, not set means no deposit
+	DepositPerBlock *x.Coin `protobuf:"bytes,6,opt,name=deposit_per_block,json=depositPerBlock" json:"deposit_per_block,omitempty"`
+	// offer_window is the number of blocks a new party has to
+	// accept an offer, 0 means the default of 1000
+	OfferWindow int64 `protobuf:"varint,7,opt,name=offer_window,json=offerWindow,proto3" json:"offer_window,omitempty"`
+}
+
+func (m *Params) Reset()                    { *m = Params{} }
+func (m *Params) String() string            { return proto.CompactTextString(m) }
+func (*Params) ProtoMessage()               {}
+func (*Params) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{17} }
+
+func (m *Params) GetDustThreshold() []*x.Coin {
+	if m != nil {
+		return m.DustThreshold
+	}
+	return nil
+}
+
+func (m *Params) GetDustToSender() bool {
+	if m != nil {
+		return m.DustToSender
+	}
+	return false
+}
+
+func (m *Params) GetMaxPerSender() int64 {
+	if m != nil {
+		return m.MaxPerSender
+	}
+	return 0
+}
+
+func (m *Params) GetMaxLocked() []*x.Coin {
+	if m != nil {
+		return m.MaxLocked
+	}
+	return nil
+}
+
+func (m *Params) GetGasPerByte() int64 {
+	if m != nil {
+		return m.GasPerByte
+	}
+	return 0
+}
+
+func (m *Params) GetDepositPerBlock() *x.Coin {
+	if m != nil {
+		return m.DepositPerBlock
+	}
+	return nil
+}
+
+func (m *Params) GetOfferWindow() int64 {
+	if m != nil {
+		return m.OfferWindow
+	}
+	return 0
+}
+
+// Locked is the total value currently held in all escrows
+type Locked struct {
+	Amount []*x.Coin `protobuf:"bytes,1,rep,name=amount" json:"amount,omitempty"`
+}
+
+func (m *Locked) Reset()                    { *m = Locked{} }
+func (m *Locked) String() string            { return proto.CompactTextString(m) }
+func (*Locked) ProtoMessage()               {}
+func (*Locked) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{18} }
+
+func (m *Locked) GetAmount() []*x.Coin {
+	if m != nil {
+		return m.Amount
+	}
+	return nil
+}
+
+// NetEscrowsMsg settles escrows between two parties at once.
+// Both must sign, and every escrow must be sent by one of them
+// to the other. Of every ticker, only the difference between
+// what the two sides owe each other is paid, the rest goes back
+// to the senders.
+type NetEscrowsMsg struct {
+	EscrowIds [][]byte `protobuf:"bytes,1,rep,name=escrow_ids,json=escrowIds" json:"escrow_ids,omitempty"`
+}
+
+func (m *NetEscrowsMsg) Reset()                    { *m = NetEscrowsMsg{} }
+func (m *NetEscrowsMsg) String() string            { return proto.CompactTextString(m) }
+func (*NetEscrowsMsg) ProtoMessage()               {}
+func (*NetEscrowsMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{19} }
+
+func (m *NetEscrowsMsg) GetEscrowIds() [][]byte {
+	if m != nil {
+		return m.EscrowIds
+	}
+	return nil
+}
+
+// ArbiterPolicy approves releases in advance for an arbiter.
+// It is stored under the address of the arbiter.
+type ArbiterPolicy struct {
+	// max_amount is the most released of every ticker from one
+	// escrow in total, releases of any other ticker are not approved
+	MaxAmount []*x.Coin `protobuf:"bytes,1,rep,name=max_amount,json=maxAmount" json:"max_amount,omitempty"`
+	// recipients are the addresses releases may be paid to
+	Recipients [][]byte `protobuf:"bytes,2,rep,name=recipients" json:"recipients,omitempty"`
+}
+
+func (m *ArbiterPolicy) Reset()                    { *m = ArbiterPolicy{} }
+func (m *ArbiterPolicy) String() string            { return proto.CompactTextString(m) }
+func (*ArbiterPolicy) ProtoMessage()               {}
+func (*ArbiterPolicy) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{20} }
+
+func (m *ArbiterPolicy) GetMaxAmount() []*x.Coin {
+	if m != nil {
+		return m.MaxAmount
+	}
+	return nil
+}
+
+func (m *ArbiterPolicy) GetRecipients() [][]byte {
+	if m != nil {
+		return m.Recipients
+	}
+	return nil
+}
+
+// SetArbiterPolicyMsg sets the policy of the main signer,
+// or removes it if empty
+type SetArbiterPolicyMsg struct {
+	Policy *ArbiterPolicy `protobuf:"bytes,1,opt,name=policy" json:"policy,omitempty"`
+}
+
+func (m *SetArbiterPolicyMsg) Reset()                    { *m = SetArbiterPolicyMsg{} }
+func (m *SetArbiterPolicyMsg) String() string            { return proto.CompactTextString(m) }
+func (*SetArbiterPolicyMsg) ProtoMessage()               {}
+func (*SetArbiterPolicyMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{21} }
+
+func (m *SetArbiterPolicyMsg) GetPolicy() *ArbiterPolicy {
+	if m != nil {
+		return m.Policy
+	}
+	return nil
+}
+
+// HistoryEntry is one step in the lifecycle of an escrow.
+// Entries are stored under the escrow id and a sequence,
+// and are kept after the escrow is closed.
+type HistoryEntry struct {
+	// event is one of the escrow events, eg. "create"
+	Event string `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	// height of the block it happened in
+	Height int64 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	// actor is the address of the main signer, if any
+	Actor []byte `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	// amount is the value moved by this event, if any
+	Amount []*x.Coin `protobuf:"bytes,4,rep,name=amount" json:"amount,omitempty"`
+	// note is the reason an admin gave for the event, if any
+	Note string `protobuf:"bytes,5,opt,name=note,proto3" json:"note,omitempty"`
+}
+
+func (m *HistoryEntry) Reset()                    { *m = HistoryEntry{} }
+func (m *HistoryEntry) String() string            { return proto.CompactTextString(m) }
+func (*HistoryEntry) ProtoMessage()               {}
+func (*HistoryEntry) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{22} }
+
+func (m *HistoryEntry) GetEvent() string {
+	if m != nil {
+		return m.Event
+	}
+	return ""
+}
+
+func (m *HistoryEntry) GetHeight() int64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *HistoryEntry) GetActor() []byte {
+	if m != nil {
+		return m.Actor
+	}
+	return nil
+}
+
+func (m *HistoryEntry) GetAmount() []*x.Coin {
+	if m != nil {
+		return m.Amount
+	}
+	return nil
+}
+
+func (m *HistoryEntry) GetNote() string {
+	if m != nil {
+		return m.Note
+	}
+	return ""
+}
+
+// EscrowExport is a copy of one escrow along with the coins
+// held by its account, to recreate it on another chain.
+type EscrowExport struct {
+	// id of the escrow on the chain it was exported from
+	Id     []byte  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Escrow *Escrow `protobuf:"bytes,2,opt,name=escrow" json:"escrow,omitempty"`
+	// balance of the escrow account at export
+	Balance []*x.Coin `protobuf:"bytes,3,rep,name=balance" json:"balance,omitempty"`
+}
+
+func (m *EscrowExport) Reset()                    { *m = EscrowExport{} }
+func (m *EscrowExport) String() string            { return proto.CompactTextString(m) }
+func (*EscrowExport) ProtoMessage()               {}
+func (*EscrowExport) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{23} }
+
+func (m *EscrowExport) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *EscrowExport) GetEscrow() *Escrow {
+	if m != nil {
+		return m.Escrow
+	}
+	return nil
+}
+
+func (m *EscrowExport) GetBalance() []*x.Coin {
+	if m != nil {
+		return m.Balance
+	}
+	return nil
+}
+
+// Alias points from the id an escrow had on a prior chain
+// to its id on this one, after a migration
+type Alias struct {
+	Id []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (m *Alias) Reset()                    { *m = Alias{} }
+func (m *Alias) String() string            { return proto.CompactTextString(m) }
+func (*Alias) ProtoMessage()               {}
+func (*Alias) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{24} }
+
+func (m *Alias) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+// EscrowTemplate holds standard terms of an escrow, so clients
+// can create one by picking the template rather than setting
+// every option. Templates are stored under their id, set in
+// genesis and maintained by admins.
+type EscrowTemplate struct {
+	// description is shown to users choosing a template
+	Description string `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	// timeout_in is the number of blocks from create until
+	// the escrow times out
+	TimeoutIn        int64 `protobuf:"varint,2,opt,name=timeout_in,json=timeoutIn,proto3" json:"timeout_in,omitempty"`
+	SenderCanRelease bool  `protobuf:"varint,3,opt,name=sender_can_release,json=senderCanRelease,proto3" json:"sender_can_release,omitempty"`
+	// bounty, if set, is paid by the sender for arbitration,
+	// as in CreateEscrowMsg
+	Bounty *x.Coin `protobuf:"bytes,4,opt,name=bounty" json:"bounty,omitempty"`
+	// max_amount, if set, limits the escrowed amount of every
+	// ticker listed, other tickers are not allowed
+	MaxAmount []*x.Coin `protobuf:"bytes,5,rep,name=max_amount,json=maxAmount" json:"max_amount,omitempty"`
+}
+
+func (m *EscrowTemplate) Reset()                    { *m = EscrowTemplate{} }
+func (m *EscrowTemplate) String() string            { return proto.CompactTextString(m) }
+func (*EscrowTemplate) ProtoMessage()               {}
+func (*EscrowTemplate) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{25} }
+
+func (m *EscrowTemplate) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *EscrowTemplate) GetTimeoutIn() int64 {
+	if m != nil {
+		return m.TimeoutIn
+	}
+	return 0
+}
+
+func (m *EscrowTemplate) GetSenderCanRelease() bool {
+	if m != nil {
+		return m.SenderCanRelease
+	}
+	return false
+}
+
+func (m *EscrowTemplate) GetBounty() *x.Coin {
+	if m != nil {
+		return m.Bounty
+	}
+	return nil
+}
+
+func (m *EscrowTemplate) GetMaxAmount() []*x.Coin {
+	if m != nil {
+		return m.MaxAmount
+	}
+	return nil
+}
+
+// CreateFromTemplateMsg creates an escrow with the terms of a
+// template. It is routed to the create handler, which adapts
+// it to a CreateEscrowMsg.
+type CreateFromTemplateMsg struct {
+	TemplateId string `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	// Sender, Arbiter, Recipient are all weave.Permission,
+	// sender defaults to the first signer
+	Sender    []byte    `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
+	Arbiter   []byte    `protobuf:"bytes,3,opt,name=arbiter,proto3" json:"arbiter,omitempty"`
+	Recipient []byte    `protobuf:"bytes,4,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Amount    []*x.Coin `protobuf:"bytes,5,rep,name=amount" json:"amount,omitempty"`
+	// max length 128 character
+	Memo string `protobuf:"bytes,6,opt,name=memo,proto3" json:"memo,omitempty"`
+}
+
+func (m *CreateFromTemplateMsg) Reset()                    { *m = CreateFromTemplateMsg{} }
+func (m *CreateFromTemplateMsg) String() string            { return proto.CompactTextString(m) }
+func (*CreateFromTemplateMsg) ProtoMessage()               {}
+func (*CreateFromTemplateMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{26} }
+
+func (m *CreateFromTemplateMsg) GetTemplateId() string {
+	if m != nil {
+		return m.TemplateId
+	}
+	return ""
+}
+
+func (m *CreateFromTemplateMsg) GetSender() []byte {
+	if m != nil {
+		return m.Sender
+	}
+	return nil
+}
+
+func (m *CreateFromTemplateMsg) GetArbiter() []byte {
+	if m != nil {
+		return m.Arbiter
+	}
+	return nil
+}
+
+func (m *CreateFromTemplateMsg) GetRecipient() []byte {
+	if m != nil {
+		return m.Recipient
+	}
+	return nil
+}
+
+func (m *CreateFromTemplateMsg) GetAmount() []*x.Coin {
+	if m != nil {
+		return m.Amount
+	}
+	return nil
+}
+
+func (m *CreateFromTemplateMsg) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
+// SetTemplateMsg adds or replaces a template, or removes
+// it if empty. Must be signed by an admin.
+type SetTemplateMsg struct {
+	TemplateId string          `protobuf:"bytes,1,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	Template   *EscrowTemplate `protobuf:"bytes,2,opt,name=template" json:"template,omitempty"`
+}
+
+func (m *SetTemplateMsg) Reset()                    { *m = SetTemplateMsg{} }
+func (m *SetTemplateMsg) String() string            { return proto.CompactTextString(m) }
+func (*SetTemplateMsg) ProtoMessage()               {}
+func (*SetTemplateMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{27} }
+
+func (m *SetTemplateMsg) GetTemplateId() string {
+	if m != nil {
+		return m.TemplateId
+	}
+	return ""
+}
+
+func (m *SetTemplateMsg) GetTemplate() *EscrowTemplate {
+	if m != nil {
+		return m.Template
+	}
+	return nil
+}
+
+// Heartbeat is the height a dead man's switch escrow is released
+// at, unless the sender pings it before. It is stored under the
+// escrow id.
+type Heartbeat struct {
+	Due int64 `protobuf:"varint,1,opt,name=due,proto3" json:"due,omitempty"`
+}
+
+func (m *Heartbeat) Reset()                    { *m = Heartbeat{} }
+func (m *Heartbeat) String() string            { return proto.CompactTextString(m) }
+func (*Heartbeat) ProtoMessage()               {}
+func (*Heartbeat) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{28} }
+
+func (m *Heartbeat) GetDue() int64 {
+	if m != nil {
+		return m.Due
+	}
+	return 0
+}
+
+// Escalation is the height the dispute of an escrow goes to its
+// backup arbiter at, unless the arbiter acts before. It is stored
+// under the escrow id.
+type Escalation struct {
+	Due int64 `protobuf:"varint,1,opt,name=due,proto3" json:"due,omitempty"`
+}
+
+func (m *Escalation) Reset()                    { *m = Escalation{} }
+func (m *Escalation) String() string            { return proto.CompactTextString(m) }
+func (*Escalation) ProtoMessage()               {}
+func (*Escalation) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{29} }
+
+func (m *Escalation) GetDue() int64 {
+	if m != nil {
+		return m.Due
+	}
+	return 0
+}
+
+// PingEscrowMsg proves the sender of a dead man's switch escrow
+// is still around, and moves the release to heartbeat_window
+// blocks from now. Must be signed by the sender.
+type PingEscrowMsg struct {
+	EscrowId []byte `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+}
+
+func (m *PingEscrowMsg) Reset()                    { *m = PingEscrowMsg{} }
+func (m *PingEscrowMsg) String() string            { return proto.CompactTextString(m) }
+func (*PingEscrowMsg) ProtoMessage()               {}
+func (*PingEscrowMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{30} }
+
+func (m *PingEscrowMsg) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
+	}
+	return nil
+}
+
+// SendEscrowMsg is a transfer that lands in a new escrow instead
+// of the wallet of the recipient, eg. for an exchange paying out a
+// withdrawal the arbiter can still hold back. Like a SendMsg it
+// only needs the signature of src.
+type SendEscrowMsg struct {
+	// src is the address paying, it must sign
+	Src []byte `protobuf:"bytes,1,opt,name=src,proto3" json:"src,omitempty"`
+	// dest is the weave.Permission the escrow is released to
+	Dest   []byte  `protobuf:"bytes,2,opt,name=dest,proto3" json:"dest,omitempty"`
+	Amount *x.Coin `protobuf:"bytes,3,opt,name=amount" json:"amount,omitempty"`
+	// max length 128 character
+	Memo   string              `protobuf:"bytes,4,opt,name=memo,proto3" json:"memo,omitempty"`
+	Escrow *EscrowInstructions `protobuf:"bytes,5,opt,name=escrow" json:"escrow,omitempty"`
+}
+
+func (m *SendEscrowMsg) Reset()                    { *m = SendEscrowMsg{} }
+func (m *SendEscrowMsg) String() string            { return proto.CompactTextString(m) }
+func (*SendEscrowMsg) ProtoMessage()               {}
+func (*SendEscrowMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{31} }
+
+func (m *SendEscrowMsg) GetSrc() []byte {
+	if m != nil {
+		return m.Src
+	}
+	return nil
+}
+
+func (m *SendEscrowMsg) GetDest() []byte {
+	if m != nil {
+		return m.Dest
+	}
+	return nil
+}
+
+func (m *SendEscrowMsg) GetAmount() *x.Coin {
+	if m != nil {
+		return m.Amount
+	}
+	return nil
+}
+
+func (m *SendEscrowMsg) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
+func (m *SendEscrowMsg) GetEscrow() *EscrowInstructions {
+	if m != nil {
+		return m.Escrow
+	}
+	return nil
+}
+
+// EscrowInstructions are the terms a SendEscrowMsg holds
+// the coins under
+type EscrowInstructions struct {
+	// arbiter is a weave.Permission
+	Arbiter []byte `protobuf:"bytes,1,opt,name=arbiter,proto3" json:"arbiter,omitempty"`
+	Timeout int64  `protobuf:"varint,2,opt,name=timeout,proto3" json:"timeout,omitempty"`
+}
+
+func (m *EscrowInstructions) Reset()                    { *m = EscrowInstructions{} }
+func (m *EscrowInstructions) String() string            { return proto.CompactTextString(m) }
+func (*EscrowInstructions) ProtoMessage()               {}
+func (*EscrowInstructions) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{32} }
+
+func (m *EscrowInstructions) GetArbiter() []byte {
+	if m != nil {
+		return m.Arbiter
+	}
+	return nil
+}
+
+func (m *EscrowInstructions) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+// QuarantineEscrowMsg freezes a suspicious escrow, so no one can
+// release, return, update or bid on it until it is restored or
+// settled. Must be signed by an admin.
+type QuarantineEscrowMsg struct {
+	EscrowId []byte `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+	// reason is kept in the escrow and its history,
+	// max length 128 character
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (m *QuarantineEscrowMsg) Reset()                    { *m = QuarantineEscrowMsg{} }
+func (m *QuarantineEscrowMsg) String() string            { return proto.CompactTextString(m) }
+func (*QuarantineEscrowMsg) ProtoMessage()               {}
+func (*QuarantineEscrowMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{33} }
+
+func (m *QuarantineEscrowMsg) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
+	}
+	return nil
+}
+
+func (m *QuarantineEscrowMsg) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+// RestoreEscrowMsg lifts the quarantine of an escrow. The timeout
+// is pushed back by the blocks it was frozen for. Must be signed
+// by an admin.
+type RestoreEscrowMsg struct {
+	EscrowId []byte `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+}
+
+func (m *RestoreEscrowMsg) Reset()                    { *m = RestoreEscrowMsg{} }
+func (m *RestoreEscrowMsg) String() string            { return proto.CompactTextString(m) }
+func (*RestoreEscrowMsg) ProtoMessage()               {}
+func (*RestoreEscrowMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{34} }
+
+func (m *RestoreEscrowMsg) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
+	}
+	return nil
+}
+
+// ForceSettleEscrowMsg closes a quarantined escrow. Release is paid
+// as a release to the recipient would be, everything else goes
+// back to the sender. Must be signed by an admin.
+type ForceSettleEscrowMsg struct {
+	EscrowId []byte    `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+	Release  []*x.Coin `protobuf:"bytes,2,rep,name=release" json:"release,omitempty"`
+}
+
+func (m *ForceSettleEscrowMsg) Reset()                    { *m = ForceSettleEscrowMsg{} }
+func (m *ForceSettleEscrowMsg) String() string            { return proto.CompactTextString(m) }
+func (*ForceSettleEscrowMsg) ProtoMessage()               {}
+func (*ForceSettleEscrowMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{35} }
+
+func (m *ForceSettleEscrowMsg) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
+	}
+	return nil
+}
+
+func (m *ForceSettleEscrowMsg) GetRelease() []*x.Coin {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+// ClawbackEscrowMsg returns an escrow created with a clawback
+// authority to the sender, along with its deposit and bounty.
+// Must be signed by the authority.
+type ClawbackEscrowMsg struct {
+	EscrowId []byte `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+	// reason is recorded in the history, max length 128 character
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (m *ClawbackEscrowMsg) Reset()                    { *m = ClawbackEscrowMsg{} }
+func (m *ClawbackEscrowMsg) String() string            { return proto.CompactTextString(m) }
+func (*ClawbackEscrowMsg) ProtoMessage()               {}
+func (*ClawbackEscrowMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{36} }
+
+func (m *ClawbackEscrowMsg) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
+	}
+	return nil
+}
+
+func (m *ClawbackEscrowMsg) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+// AttestMilestoneMsg confirms a milestone of an escrow is done,
+// and releases its tranche to the recipient. Must be signed by the
+// arbiter or the attester, before the timeout.
+type AttestMilestoneMsg struct {
+	EscrowId []byte `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+	// milestone is the index in the milestones of the escrow
+	Milestone int32 `protobuf:"varint,2,opt,name=milestone,proto3" json:"milestone,omitempty"`
+}
+
+func (m *AttestMilestoneMsg) Reset()                    { *m = AttestMilestoneMsg{} }
+func (m *AttestMilestoneMsg) String() string            { return proto.CompactTextString(m) }
+func (*AttestMilestoneMsg) ProtoMessage()               {}
+func (*AttestMilestoneMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{37} }
+
+func (m *AttestMilestoneMsg) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
+	}
+	return nil
+}
+
+func (m *AttestMilestoneMsg) GetMilestone() int32 {
+	if m != nil {
+		return m.Milestone
+	}
+	return 0
+}
+
+// OfferEscrowPartyMsg offers a party of the escrow, one of
+// "sender", "arbiter" and "recipient", to another permission.
+// Must be signed by the current holder. It only changes once the
+// new holder accepts with AcceptEscrowPartyMsg within the offer
+// window, an offer to no one withdraws it.
+type OfferEscrowPartyMsg struct {
+	EscrowId []byte `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+	Role     string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	To       []byte `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+}
+
+func (m *OfferEscrowPartyMsg) Reset()                    { *m = OfferEscrowPartyMsg{} }
+func (m *OfferEscrowPartyMsg) String() string            { return proto.CompactTextString(m) }
+func (*OfferEscrowPartyMsg) ProtoMessage()               {}
+func (*OfferEscrowPartyMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{38} }
+
+func (m *OfferEscrowPartyMsg) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
+	}
+	return nil
+}
+
+func (m *OfferEscrowPartyMsg) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *OfferEscrowPartyMsg) GetTo() []byte {
+	if m != nil {
+		return m.To
+	}
+	return nil
+}
+
+// AcceptEscrowPartyMsg takes the party offered to the signer
+type AcceptEscrowPartyMsg struct {
+	EscrowId []byte `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+	Role     string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+}
+
+func (m *AcceptEscrowPartyMsg) Reset()                    { *m = AcceptEscrowPartyMsg{} }
+func (m *AcceptEscrowPartyMsg) String() string            { return proto.CompactTextString(m) }
+func (*AcceptEscrowPartyMsg) ProtoMessage()               {}
+func (*AcceptEscrowPartyMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{39} }
+
+func (m *AcceptEscrowPartyMsg) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
+	}
+	return nil
+}
+
+func (m *AcceptEscrowPartyMsg) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+// DisputeEscrowMsg asks the arbiter to settle an escrow. Must be
+// signed by the sender or the recipient, before the timeout. If
+// the escrow has a backup arbiter and the arbiter neither releases
+// nor attests within sla_blocks, the backup arbiter takes over.
+type DisputeEscrowMsg struct {
+	EscrowId []byte `protobuf:"bytes,1,opt,name=escrow_id,json=escrowId,proto3" json:"escrow_id,omitempty"`
+	// reason is kept in the escrow and its history,
+	// max length 128 character
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (m *DisputeEscrowMsg) Reset()                    { *m = DisputeEscrowMsg{} }
+func (m *DisputeEscrowMsg) String() string            { return proto.CompactTextString(m) }
+func (*DisputeEscrowMsg) ProtoMessage()               {}
+func (*DisputeEscrowMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{40} }
+
+func (m *DisputeEscrowMsg) GetEscrowId() []byte {
+	if m != nil {
+		return m.EscrowId
+	}
+	return nil
+}
+
+func (m *DisputeEscrowMsg) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*Escrow)(nil), "escrow.Escrow")
+	proto.RegisterType((*Milestone)(nil), "escrow.Milestone")
+	proto.RegisterType((*Dispute)(nil), "escrow.Dispute")
+	proto.RegisterType((*Quarantine)(nil), "escrow.Quarantine")
+	proto.RegisterType((*Share)(nil), "escrow.Share")
+	proto.RegisterType((*CreateEscrowMsg)(nil), "escrow.CreateEscrowMsg")
+	proto.RegisterType((*CreateEscrowMsgV2)(nil), "escrow.CreateEscrowMsgV2")
+	proto.RegisterType((*EscrowOptions)(nil), "escrow.EscrowOptions")
+	proto.RegisterType((*ReleaseEscrowMsg)(nil), "escrow.ReleaseEscrowMsg")
+	proto.RegisterType((*ChainEscrow)(nil), "escrow.ChainEscrow")
+	proto.RegisterType((*ReturnEscrowMsg)(nil), "escrow.ReturnEscrowMsg")
+	proto.RegisterType((*UpdateEscrowPartiesMsg)(nil), "escrow.UpdateEscrowPartiesMsg")
+	proto.RegisterType((*UpdateEscrowObserversMsg)(nil), "escrow.UpdateEscrowObserversMsg")
+	proto.RegisterType((*RevealMemoMsg)(nil), "escrow.RevealMemoMsg")
+	proto.RegisterType((*Bid)(nil), "escrow.Bid")
+	proto.RegisterType((*BidArbitrationMsg)(nil), "escrow.BidArbitrationMsg")
+	proto.RegisterType((*AssignArbiterMsg)(nil), "escrow.AssignArbiterMsg")
+	proto.RegisterType((*Params)(nil), "escrow.Params")
+	proto.RegisterType((*Locked)(nil), "escrow.Locked")
+	proto.RegisterType((*NetEscrowsMsg)(nil), "escrow.NetEscrowsMsg")
+	proto.RegisterType((*ArbiterPolicy)(nil), "escrow.ArbiterPolicy")
+	proto.RegisterType((*SetArbiterPolicyMsg)(nil), "escrow.SetArbiterPolicyMsg")
+	proto.RegisterType((*HistoryEntry)(nil), "escrow.HistoryEntry")
+	proto.RegisterType((*EscrowExport)(nil), "escrow.EscrowExport")
+	proto.RegisterType((*Alias)(nil), "escrow.Alias")
+	proto.RegisterType((*EscrowTemplate)(nil), "escrow.EscrowTemplate")
+	proto.RegisterType((*CreateFromTemplateMsg)(nil), "escrow.CreateFromTemplateMsg")
+	proto.RegisterType((*SetTemplateMsg)(nil), "escrow.SetTemplateMsg")
+	proto.RegisterType((*Heartbeat)(nil), "escrow.Heartbeat")
+	proto.RegisterType((*Escalation)(nil), "escrow.Escalation")
+	proto.RegisterType((*PingEscrowMsg)(nil), "escrow.PingEscrowMsg")
+	proto.RegisterType((*SendEscrowMsg)(nil), "escrow.SendEscrowMsg")
+	proto.RegisterType((*EscrowInstructions)(nil), "escrow.EscrowInstructions")
+	proto.RegisterType((*QuarantineEscrowMsg)(nil), "escrow.QuarantineEscrowMsg")
+	proto.RegisterType((*RestoreEscrowMsg)(nil), "escrow.RestoreEscrowMsg")
+	proto.RegisterType((*ForceSettleEscrowMsg)(nil), "escrow.ForceSettleEscrowMsg")
+	proto.RegisterType((*ClawbackEscrowMsg)(nil), "escrow.ClawbackEscrowMsg")
+	proto.RegisterType((*AttestMilestoneMsg)(nil), "escrow.AttestMilestoneMsg")
+	proto.RegisterType((*OfferEscrowPartyMsg)(nil), "escrow.OfferEscrowPartyMsg")
+	proto.RegisterType((*AcceptEscrowPartyMsg)(nil), "escrow.AcceptEscrowPartyMsg")
+	proto.RegisterType((*DisputeEscrowMsg)(nil), "escrow.DisputeEscrowMsg")
+}
+func (m *Escrow) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Escrow) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Sender) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Sender)))
+		i += copy(dAtA[i:], m.Sender)
+	}
+	if len(m.Arbiter) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Arbiter)))
+		i += copy(dAtA[i:], m.Arbiter)
+	}
+	if len(m.Recipient) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Recipient)))
+		i += copy(dAtA[i:], m.Recipient)
+	}
+	if len(m.Amount) > 0 {
+		for _, msg := range m.Amount {
+			dAtA[i] = 0x22
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Timeout != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Timeout))
+	}
+	if len(m.Memo) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Memo)))
+		i += copy(dAtA[i:], m.Memo)
+	}
+	if m.SenderCanRelease {
+		dAtA[i] = 0x38
+		i++
+		if m.SenderCanRelease {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Target != nil {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Target.Size()))
+		n1, err := m.Target.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if m.MinPrice != nil {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.MinPrice.Size()))
+		n2, err := m.MinPrice.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if m.MaxPrice != nil {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.MaxPrice.Size()))
+		n3, err := m.MaxPrice.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	if m.Deposit != nil {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Deposit.Size()))
+		n4, err := m.Deposit.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if m.Bounty != nil {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Bounty.Size()))
+		n5, err := m.Bounty.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	if len(m.Observers) > 0 {
+		for _, b := range m.Observers {
+			dAtA[i] = 0x6a
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	if len(m.MemoHash) > 0 {
+		dAtA[i] = 0x72
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.MemoHash)))
+		i += copy(dAtA[i:], m.MemoHash)
+	}
+	if m.HeartbeatWindow != 0 {
+		dAtA[i] = 0x78
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.HeartbeatWindow))
+	}
+	if len(m.FundedBy) > 0 {
+		for _, b := range m.FundedBy {
+			dAtA[i] = 0x82
+			i++
+			dAtA[i] = 0x1
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	if len(m.Shares) > 0 {
+		for _, msg := range m.Shares {
+			dAtA[i] = 0x8a
+			i++
+			dAtA[i] = 0x1
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Quarantine != nil {
+		dAtA[i] = 0x92
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Quarantine.Size()))
+		n6, err := m.Quarantine.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n6
+	}
+	if len(m.Clawback) > 0 {
+		dAtA[i] = 0x9a
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Clawback)))
+		i += copy(dAtA[i:], m.Clawback)
+	}
+	if len(m.Milestones) > 0 {
+		for _, msg := range m.Milestones {
+			dAtA[i] = 0xa2
+			i++
+			dAtA[i] = 0x1
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Attester) > 0 {
+		dAtA[i] = 0xaa
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Attester)))
+		i += copy(dAtA[i:], m.Attester)
+	}
+	if len(m.DestTag) > 0 {
+		dAtA[i] = 0xb2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.DestTag)))
+		i += copy(dAtA[i:], m.DestTag)
+	}
+	if len(m.BackupArbiter) > 0 {
+		dAtA[i] = 0xba
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.BackupArbiter)))
+		i += copy(dAtA[i:], m.BackupArbiter)
+	}
+	if m.SlaBlocks != 0 {
+		dAtA[i] = 0xc0
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.SlaBlocks))
+	}
+	if m.Dispute != nil {
+		dAtA[i] = 0xca
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Dispute.Size()))
+		n7, err := m.Dispute.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n7
+	}
+	if len(m.PolicyReleased) > 0 {
+		for _, msg := range m.PolicyReleased {
+			dAtA[i] = 0xd2
+			i++
+			dAtA[i] = 0x1
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *Milestone) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Milestone) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Name) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
+	}
+	if len(m.Amount) > 0 {
+		for _, msg := range m.Amount {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Attested != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Attested))
+	}
+	return i, nil
+}
+
+func (m *Dispute) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Dispute) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Reason) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Reason)))
+		i += copy(dAtA[i:], m.Reason)
+	}
+	if m.Height != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Height))
+	}
+	if m.Escalated != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Escalated))
+	}
+	return i, nil
+}
+
+func (m *Quarantine) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Quarantine) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Reason) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Reason)))
+		i += copy(dAtA[i:], m.Reason)
+	}
+	if m.Height != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Height))
+	}
+	return i, nil
+}
+
+func (m *Share) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Share) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Address) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Address)))
+		i += copy(dAtA[i:], m.Address)
+	}
+	if m.Bps != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Bps))
+	}
+	return i, nil
+}
+
+func (m *CreateEscrowMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CreateEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Sender) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Sender)))
+		i += copy(dAtA[i:], m.Sender)
+	}
+	if len(m.Arbiter) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Arbiter)))
+		i += copy(dAtA[i:], m.Arbiter)
+	}
+	if len(m.Recipient) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Recipient)))
+		i += copy(dAtA[i:], m.Recipient)
+	}
+	if len(m.Amount) > 0 {
+		for _, msg := range m.Amount {
+			dAtA[i] = 0x22
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Timeout != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Timeout))
+	}
+	if len(m.Memo) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Memo)))
+		i += copy(dAtA[i:], m.Memo)
+	}
+	if m.SenderCanRelease {
+		dAtA[i] = 0x38
+		i++
+		if m.SenderCanRelease {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Target != nil {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Target.Size()))
+		n8, err := m.Target.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
+	if m.MinPrice != nil {
+		dAtA[i] = 0x4a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.MinPrice.Size()))
+		n9, err := m.MinPrice.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n9
+	}
+	if m.MaxPrice != nil {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.MaxPrice.Size()))
+		n10, err := m.MaxPrice.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
+	}
+	if m.Bounty != nil {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Bounty.Size()))
+		n11, err := m.Bounty.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
+	}
+	if len(m.Observers) > 0 {
+		for _, b := range m.Observers {
+			dAtA[i] = 0x62
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	if len(m.MemoHash) > 0 {
+		dAtA[i] = 0x6a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.MemoHash)))
+		i += copy(dAtA[i:], m.MemoHash)
+	}
+	if m.HeartbeatWindow != 0 {
+		dAtA[i] = 0x70
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.HeartbeatWindow))
+	}
+	if len(m.Shares) > 0 {
+		for _, msg := range m.Shares {
+			dAtA[i] = 0x7a
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Clawback) > 0 {
+		dAtA[i] = 0x82
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Clawback)))
+		i += copy(dAtA[i:], m.Clawback)
+	}
+	if len(m.Milestones) > 0 {
+		for _, msg := range m.Milestones {
+			dAtA[i] = 0x8a
+			i++
+			dAtA[i] = 0x1
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Attester) > 0 {
+		dAtA[i] = 0x92
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Attester)))
+		i += copy(dAtA[i:], m.Attester)
+	}
+	if len(m.DestTag) > 0 {
+		dAtA[i] = 0x9a
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.DestTag)))
+		i += copy(dAtA[i:], m.DestTag)
+	}
+	if len(m.BackupArbiter) > 0 {
+		dAtA[i] = 0xa2
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.BackupArbiter)))
+		i += copy(dAtA[i:], m.BackupArbiter)
+	}
+	if m.SlaBlocks != 0 {
+		dAtA[i] = 0xa8
+		i++
+		dAtA[i] = 0x1
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.SlaBlocks))
+	}
+	return i, nil
+}
+
+func (m *CreateEscrowMsgV2) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CreateEscrowMsgV2) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Sender) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Sender)))
+		i += copy(dAtA[i:], m.Sender)
+	}
+	if len(m.Arbiter) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Arbiter)))
+		i += copy(dAtA[i:], m.Arbiter)
+	}
+	if len(m.Recipient) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Recipient)))
+		i += copy(dAtA[i:], m.Recipient)
+	}
+	if len(m.Amount) > 0 {
+		for _, msg := range m.Amount {
+			dAtA[i] = 0x22
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Timeout != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Timeout))
+	}
+	if m.TimeoutIn != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.TimeoutIn))
+	}
+	if len(m.Memo) > 0 {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Memo)))
+		i += copy(dAtA[i:], m.Memo)
+	}
+	if m.Options != nil {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Options.Size()))
+		n12, err := m.Options.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n12
+	}
+	return i, nil
+}
+
+func (m *EscrowOptions) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *EscrowOptions) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.SenderCanRelease {
+		dAtA[i] = 0x8
+		i++
+		if m.SenderCanRelease {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Target != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Target.Size()))
+		n13, err := m.Target.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n13
+	}
+	if m.MinPrice != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.MinPrice.Size()))
+		n14, err := m.MinPrice.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n14
+	}
+	if m.MaxPrice != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.MaxPrice.Size()))
+		n15, err := m.MaxPrice.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n15
+	}
+	if m.Bounty != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Bounty.Size()))
+		n16, err := m.Bounty.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n16
+	}
+	if len(m.Observers) > 0 {
+		for _, b := range m.Observers {
+			dAtA[i] = 0x32
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	if len(m.MemoHash) > 0 {
+		dAtA[i] = 0x3a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.MemoHash)))
+		i += copy(dAtA[i:], m.MemoHash)
+	}
+	if m.HeartbeatWindow != 0 {
+		dAtA[i] = 0x40
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.HeartbeatWindow))
+	}
+	if len(m.Shares) > 0 {
+		for _, msg := range m.Shares {
+			dAtA[i] = 0x4a
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Clawback) > 0 {
+		dAtA[i] = 0x52
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Clawback)))
+		i += copy(dAtA[i:], m.Clawback)
+	}
+	if len(m.Milestones) > 0 {
+		for _, msg := range m.Milestones {
+			dAtA[i] = 0x5a
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Attester) > 0 {
+		dAtA[i] = 0x62
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Attester)))
+		i += copy(dAtA[i:], m.Attester)
+	}
+	if len(m.DestTag) > 0 {
+		dAtA[i] = 0x6a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.DestTag)))
+		i += copy(dAtA[i:], m.DestTag)
+	}
+	if len(m.BackupArbiter) > 0 {
+		dAtA[i] = 0x72
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.BackupArbiter)))
+		i += copy(dAtA[i:], m.BackupArbiter)
+	}
+	if m.SlaBlocks != 0 {
+		dAtA[i] = 0x78
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.SlaBlocks))
+	}
+	return i, nil
+}
+
+func (m *ReleaseEscrowMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReleaseEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	if len(m.Amount) > 0 {
+		for _, msg := range m.Amount {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Policy {
+		dAtA[i] = 0x18
+		i++
+		if m.Policy {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Chain != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Chain.Size()))
+		n17, err := m.Chain.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n17
+	}
+	return i, nil
+}
+
+func (m *ChainEscrow) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ChainEscrow) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	if len(m.Arbiter) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Arbiter)))
+		i += copy(dAtA[i:], m.Arbiter)
+	}
+	if len(m.Recipient) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Recipient)))
+		i += copy(dAtA[i:], m.Recipient)
+	}
+	if m.Timeout != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Timeout))
+	}
+	if len(m.Memo) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Memo)))
+		i += copy(dAtA[i:], m.Memo)
+	}
+	return i, nil
+}
+
+func (m *ReturnEscrowMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReturnEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	return i, nil
+}
+
+func (m *UpdateEscrowPartiesMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpdateEscrowPartiesMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	if len(m.Sender) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Sender)))
+		i += copy(dAtA[i:], m.Sender)
+	}
+	if len(m.Arbiter) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Arbiter)))
+		i += copy(dAtA[i:], m.Arbiter)
+	}
+	if len(m.Recipient) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Recipient)))
+		i += copy(dAtA[i:], m.Recipient)
+	}
+	return i, nil
+}
+
+func (m *UpdateEscrowObserversMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *UpdateEscrowObserversMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	if len(m.Add) > 0 {
+		for _, b := range m.Add {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	if len(m.Remove) > 0 {
+		for _, b := range m.Remove {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	return i, nil
+}
+
+func (m *RevealMemoMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RevealMemoMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	if len(m.Memo) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Memo)))
+		i += copy(dAtA[i:], m.Memo)
+	}
+	if len(m.Salt) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Salt)))
+		i += copy(dAtA[i:], m.Salt)
+	}
+	return i, nil
+}
+
+func (m *Bid) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Bid) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Arbiter) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Arbiter)))
+		i += copy(dAtA[i:], m.Arbiter)
+	}
+	if m.Fee != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Fee.Size()))
+		n18, err := m.Fee.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n18
+	}
+	if m.Height != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Height))
+	}
+	return i, nil
+}
+
+func (m *BidArbitrationMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BidArbitrationMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	if m.Fee != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Fee.Size()))
+		n19, err := m.Fee.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n19
+	}
+	return i, nil
+}
+
+func (m *AssignArbiterMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AssignArbiterMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	if len(m.Bidder) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Bidder)))
+		i += copy(dAtA[i:], m.Bidder)
+	}
+	return i, nil
+}
+
+func (m *Params) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Params) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.DustThreshold) > 0 {
+		for _, msg := range m.DustThreshold {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.DustToSender {
+		dAtA[i] = 0x10
+		i++
+		if m.DustToSender {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.MaxPerSender != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.MaxPerSender))
+	}
+	if len(m.MaxLocked) > 0 {
+		for _, msg := range m.MaxLocked {
+			dAtA[i] = 0x22
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.GasPerByte != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.GasPerByte))
+	}
+	if m.DepositPerBlock != nil {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.DepositPerBlock.Size()))
+		n20, err := m.DepositPerBlock.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n20
+	}
+	if m.OfferWindow != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.OfferWindow))
+	}
+	return i, nil
+}
+
+func (m *Locked) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Locked) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Amount) > 0 {
+		for _, msg := range m.Amount {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *NetEscrowsMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *NetEscrowsMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowIds) > 0 {
+		for _, b := range m.EscrowIds {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	return i, nil
+}
+
+func (m *ArbiterPolicy) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ArbiterPolicy) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.MaxAmount) > 0 {
+		for _, msg := range m.MaxAmount {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Recipients) > 0 {
+		for _, b := range m.Recipients {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	return i, nil
+}
+
+func (m *SetArbiterPolicyMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SetArbiterPolicyMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Policy != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Policy.Size()))
+		n21, err := m.Policy.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n21
+	}
+	return i, nil
+}
+
+func (m *HistoryEntry) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *HistoryEntry) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Event) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Event)))
+		i += copy(dAtA[i:], m.Event)
+	}
+	if m.Height != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Height))
+	}
+	if len(m.Actor) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Actor)))
+		i += copy(dAtA[i:], m.Actor)
+	}
+	if len(m.Amount) > 0 {
+		for _, msg := range m.Amount {
+			dAtA[i] = 0x22
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Note) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Note)))
+		i += copy(dAtA[i:], m.Note)
+	}
+	return i, nil
+}
+
+func (m *EscrowExport) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *EscrowExport) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if m.Escrow != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Escrow.Size()))
+		n22, err := m.Escrow.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n22
+	}
+	if len(m.Balance) > 0 {
+		for _, msg := range m.Balance {
+			dAtA[i] = 0x1a
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *Alias) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Alias) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	return i, nil
+}
+
+func (m *EscrowTemplate) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *EscrowTemplate) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Description) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Description)))
+		i += copy(dAtA[i:], m.Description)
+	}
+	if m.TimeoutIn != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.TimeoutIn))
+	}
+	if m.SenderCanRelease {
+		dAtA[i] = 0x18
+		i++
+		if m.SenderCanRelease {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Bounty != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Bounty.Size()))
+		n23, err := m.Bounty.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n23
+	}
+	if len(m.MaxAmount) > 0 {
+		for _, msg := range m.MaxAmount {
+			dAtA[i] = 0x2a
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *CreateFromTemplateMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CreateFromTemplateMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.TemplateId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.TemplateId)))
+		i += copy(dAtA[i:], m.TemplateId)
+	}
+	if len(m.Sender) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Sender)))
+		i += copy(dAtA[i:], m.Sender)
+	}
+	if len(m.Arbiter) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Arbiter)))
+		i += copy(dAtA[i:], m.Arbiter)
+	}
+	if len(m.Recipient) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Recipient)))
+		i += copy(dAtA[i:], m.Recipient)
+	}
+	if len(m.Amount) > 0 {
+		for _, msg := range m.Amount {
+			dAtA[i] = 0x2a
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if len(m.Memo) > 0 {
+		dAtA[i] = 0x32
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Memo)))
+		i += copy(dAtA[i:], m.Memo)
+	}
+	return i, nil
+}
+
+func (m *SetTemplateMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SetTemplateMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.TemplateId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.TemplateId)))
+		i += copy(dAtA[i:], m.TemplateId)
+	}
+	if m.Template != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Template.Size()))
+		n24, err := m.Template.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n24
+	}
+	return i, nil
+}
+
+func (m *Heartbeat) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Heartbeat) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Due != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Due))
+	}
+	return i, nil
+}
+
+func (m *Escalation) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Escalation) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Due != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Due))
+	}
+	return i, nil
+}
+
+func (m *PingEscrowMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *PingEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	return i, nil
+}
+
+func (m *SendEscrowMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SendEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Src) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Src)))
+		i += copy(dAtA[i:], m.Src)
+	}
+	if len(m.Dest) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Dest)))
+		i += copy(dAtA[i:], m.Dest)
+	}
+	if m.Amount != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Amount.Size()))
+		n25, err := m.Amount.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n25
+	}
+	if len(m.Memo) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Memo)))
+		i += copy(dAtA[i:], m.Memo)
+	}
+	if m.Escrow != nil {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Escrow.Size()))
+		n26, err := m.Escrow.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n26
+	}
+	return i, nil
+}
+
+func (m *EscrowInstructions) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *EscrowInstructions) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Arbiter) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Arbiter)))
+		i += copy(dAtA[i:], m.Arbiter)
+	}
+	if m.Timeout != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Timeout))
+	}
+	return i, nil
+}
+
+func (m *QuarantineEscrowMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QuarantineEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	if len(m.Reason) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Reason)))
+		i += copy(dAtA[i:], m.Reason)
+	}
+	return i, nil
+}
+
+func (m *RestoreEscrowMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RestoreEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	return i, nil
+}
+
+func (m *ForceSettleEscrowMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ForceSettleEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	if len(m.Release) > 0 {
+		for _, msg := range m.Release {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintCodec(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *ClawbackEscrowMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ClawbackEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	if len(m.Reason) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Reason)))
+		i += copy(dAtA[i:], m.Reason)
+	}
+	return i, nil
+}
+
+func (m *AttestMilestoneMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AttestMilestoneMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	if m.Milestone != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Milestone))
+	}
+	return i, nil
+}
+
+func (m *OfferEscrowPartyMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *OfferEscrowPartyMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	if len(m.Role) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Role)))
+		i += copy(dAtA[i:], m.Role)
+	}
+	if len(m.To) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.To)))
+		i += copy(dAtA[i:], m.To)
+	}
+	return i, nil
+}
+
+func (m *AcceptEscrowPartyMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AcceptEscrowPartyMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	if len(m.Role) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Role)))
+		i += copy(dAtA[i:], m.Role)
+	}
+	return i, nil
+}
+
+func (m *DisputeEscrowMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *DisputeEscrowMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.EscrowId) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.EscrowId)))
+		i += copy(dAtA[i:], m.EscrowId)
+	}
+	if len(m.Reason) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Reason)))
+		i += copy(dAtA[i:], m.Reason)
+	}
+	return i, nil
+}
+
+func encodeVarintCodec(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *Escrow) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Sender)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Arbiter)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Recipient)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Amount) > 0 {
+		for _, e := range m.Amount {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	if m.Timeout != 0 {
+		n += 1 + sovCodec(uint64(m.Timeout))
+	}
+	l = len(m.Memo)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.SenderCanRelease {
+		n += 2
+	}
+	if m.Target != nil {
+		l = m.Target.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.MinPrice != nil {
+		l = m.MinPrice.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.MaxPrice != nil {
+		l = m.MaxPrice.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Deposit != nil {
+		l = m.Deposit.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Bounty != nil {
+		l = m.Bounty.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Observers) > 0 {
+		for _, b := range m.Observers {
+			l = len(b)
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	l = len(m.MemoHash)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.HeartbeatWindow != 0 {
+		n += 1 + sovCodec(uint64(m.HeartbeatWindow))
+	}
+	if len(m.FundedBy) > 0 {
+		for _, b := range m.FundedBy {
+			l = len(b)
+			n += 2 + l + sovCodec(uint64(l))
+		}
+	}
+	if len(m.Shares) > 0 {
+		for _, e := range m.Shares {
+			l = e.Size()
+			n += 2 + l + sovCodec(uint64(l))
+		}
+	}
+	if m.Quarantine != nil {
+		l = m.Quarantine.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Clawback)
+	if l > 0 {
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	if len(m.Milestones) > 0 {
+		for _, e := range m.Milestones {
+			l = e.Size()
+			n += 2 + l + sovCodec(uint64(l))
+		}
+	}
+	l = len(m.Attester)
+	if l > 0 {
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	l = len(m.DestTag)
+	if l > 0 {
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	l = len(m.BackupArbiter)
+	if l > 0 {
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	if m.SlaBlocks != 0 {
+		n += 2 + sovCodec(uint64(m.SlaBlocks))
+	}
+	if m.Dispute != nil {
+		l = m.Dispute.Size()
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	if len(m.PolicyReleased) > 0 {
+		for _, e := range m.PolicyReleased {
+			l = e.Size()
+			n += 2 + l + sovCodec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Milestone) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Amount) > 0 {
+		for _, e := range m.Amount {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	if m.Attested != 0 {
+		n += 1 + sovCodec(uint64(m.Attested))
+	}
+	return n
+}
+
+func (m *Dispute) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Reason)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Height != 0 {
+		n += 1 + sovCodec(uint64(m.Height))
+	}
+	if m.Escalated != 0 {
+		n += 1 + sovCodec(uint64(m.Escalated))
+	}
+	return n
+}
+
+func (m *Quarantine) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Reason)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Height != 0 {
+		n += 1 + sovCodec(uint64(m.Height))
+	}
+	return n
+}
+
+func (m *Share) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Address)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Bps != 0 {
+		n += 1 + sovCodec(uint64(m.Bps))
+	}
+	return n
+}
+
+func (m *CreateEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Sender)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Arbiter)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Recipient)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Amount) > 0 {
+		for _, e := range m.Amount {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	if m.Timeout != 0 {
+		n += 1 + sovCodec(uint64(m.Timeout))
+	}
+	l = len(m.Memo)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.SenderCanRelease {
+		n += 2
+	}
+	if m.Target != nil {
+		l = m.Target.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.MinPrice != nil {
+		l = m.MinPrice.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.MaxPrice != nil {
+		l = m.MaxPrice.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Bounty != nil {
+		l = m.Bounty.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Observers) > 0 {
+		for _, b := range m.Observers {
+			l = len(b)
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	l = len(m.MemoHash)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.HeartbeatWindow != 0 {
+		n += 1 + sovCodec(uint64(m.HeartbeatWindow))
+	}
+	if len(m.Shares) > 0 {
+		for _, e := range m.Shares {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	l = len(m.Clawback)
+	if l > 0 {
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	if len(m.Milestones) > 0 {
+		for _, e := range m.Milestones {
+			l = e.Size()
+			n += 2 + l + sovCodec(uint64(l))
+		}
+	}
+	l = len(m.Attester)
+	if l > 0 {
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	l = len(m.DestTag)
+	if l > 0 {
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	l = len(m.BackupArbiter)
+	if l > 0 {
+		n += 2 + l + sovCodec(uint64(l))
+	}
+	if m.SlaBlocks != 0 {
+		n += 2 + sovCodec(uint64(m.SlaBlocks))
+	}
+	return n
+}
+
+func (m *CreateEscrowMsgV2) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Sender)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Arbiter)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Recipient)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Amount) > 0 {
+		for _, e := range m.Amount {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	if m.Timeout != 0 {
+		n += 1 + sovCodec(uint64(m.Timeout))
+	}
+	if m.TimeoutIn != 0 {
+		n += 1 + sovCodec(uint64(m.TimeoutIn))
+	}
+	l = len(m.Memo)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Options != nil {
+		l = m.Options.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *EscrowOptions) Size() (n int) {
+	var l int
+	_ = l
+	if m.SenderCanRelease {
+		n += 2
+	}
+	if m.Target != nil {
+		l = m.Target.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.MinPrice != nil {
+		l = m.MinPrice.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.MaxPrice != nil {
+		l = m.MaxPrice.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Bounty != nil {
+		l = m.Bounty.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Observers) > 0 {
+		for _, b := range m.Observers {
+			l = len(b)
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	l = len(m.MemoHash)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.HeartbeatWindow != 0 {
+		n += 1 + sovCodec(uint64(m.HeartbeatWindow))
+	}
+	if len(m.Shares) > 0 {
+		for _, e := range m.Shares {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	l = len(m.Clawback)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Milestones) > 0 {
+		for _, e := range m.Milestones {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	l = len(m.Attester)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.DestTag)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.BackupArbiter)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.SlaBlocks != 0 {
+		n += 1 + sovCodec(uint64(m.SlaBlocks))
+	}
+	return n
+}
+
+func (m *ReleaseEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Amount) > 0 {
+		for _, e := range m.Amount {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	if m.Policy {
+		n += 2
+	}
+	if m.Chain != nil {
+		l = m.Chain.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *ChainEscrow) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Arbiter)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Recipient)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Timeout != 0 {
+		n += 1 + sovCodec(uint64(m.Timeout))
+	}
+	l = len(m.Memo)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *ReturnEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *UpdateEscrowPartiesMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Sender)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Arbiter)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Recipient)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *UpdateEscrowObserversMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Add) > 0 {
+		for _, b := range m.Add {
+			l = len(b)
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	if len(m.Remove) > 0 {
+		for _, b := range m.Remove {
+			l = len(b)
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *RevealMemoMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Memo)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Salt)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *Bid) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Arbiter)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Fee != nil {
+		l = m.Fee.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Height != 0 {
+		n += 1 + sovCodec(uint64(m.Height))
+	}
+	return n
+}
+
+func (m *BidArbitrationMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Fee != nil {
+		l = m.Fee.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *AssignArbiterMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Bidder)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *Params) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.DustThreshold) > 0 {
+		for _, e := range m.DustThreshold {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	if m.DustToSender {
+		n += 2
+	}
+	if m.MaxPerSender != 0 {
+		n += 1 + sovCodec(uint64(m.MaxPerSender))
+	}
+	if len(m.MaxLocked) > 0 {
+		for _, e := range m.MaxLocked {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	if m.GasPerByte != 0 {
+		n += 1 + sovCodec(uint64(m.GasPerByte))
+	}
+	if m.DepositPerBlock != nil {
+		l = m.DepositPerBlock.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.OfferWindow != 0 {
+		n += 1 + sovCodec(uint64(m.OfferWindow))
+	}
+	return n
+}
+
+func (m *Locked) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Amount) > 0 {
+		for _, e := range m.Amount {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *NetEscrowsMsg) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.EscrowIds) > 0 {
+		for _, b := range m.EscrowIds {
+			l = len(b)
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ArbiterPolicy) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.MaxAmount) > 0 {
+		for _, e := range m.MaxAmount {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	if len(m.Recipients) > 0 {
+		for _, b := range m.Recipients {
+			l = len(b)
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SetArbiterPolicyMsg) Size() (n int) {
+	var l int
+	_ = l
+	if m.Policy != nil {
+		l = m.Policy.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *HistoryEntry) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Event)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Height != 0 {
+		n += 1 + sovCodec(uint64(m.Height))
+	}
+	l = len(m.Actor)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Amount) > 0 {
+		for _, e := range m.Amount {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	l = len(m.Note)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *EscrowExport) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Escrow != nil {
+		l = m.Escrow.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Balance) > 0 {
+		for _, e := range m.Balance {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Alias) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *EscrowTemplate) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Description)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.TimeoutIn != 0 {
+		n += 1 + sovCodec(uint64(m.TimeoutIn))
+	}
+	if m.SenderCanRelease {
+		n += 2
+	}
+	if m.Bounty != nil {
+		l = m.Bounty.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.MaxAmount) > 0 {
+		for _, e := range m.MaxAmount {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *CreateFromTemplateMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.TemplateId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Sender)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Arbiter)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Recipient)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Amount) > 0 {
+		for _, e := range m.Amount {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	l = len(m.Memo)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *SetTemplateMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.TemplateId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Template != nil {
+		l = m.Template.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *Heartbeat) Size() (n int) {
+	var l int
+	_ = l
+	if m.Due != 0 {
+		n += 1 + sovCodec(uint64(m.Due))
+	}
+	return n
+}
+
+func (m *Escalation) Size() (n int) {
+	var l int
+	_ = l
+	if m.Due != 0 {
+		n += 1 + sovCodec(uint64(m.Due))
+	}
+	return n
+}
+
+func (m *PingEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *SendEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Src)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Dest)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Amount != nil {
+		l = m.Amount.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Memo)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Escrow != nil {
+		l = m.Escrow.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *EscrowInstructions) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Arbiter)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Timeout != 0 {
+		n += 1 + sovCodec(uint64(m.Timeout))
+	}
+	return n
+}
+
+func (m *QuarantineEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Reason)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *RestoreEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *ForceSettleEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if len(m.Release) > 0 {
+		for _, e := range m.Release {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ClawbackEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Reason)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *AttestMilestoneMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Milestone != 0 {
+		n += 1 + sovCodec(uint64(m.Milestone))
+	}
+	return n
+}
+
+func (m *OfferEscrowPartyMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Role)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.To)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *AcceptEscrowPartyMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Role)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *DisputeEscrowMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.EscrowId)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Reason)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func sovCodec(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozCodec(x uint64) (n int) {
+	return sovCodec(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Escrow) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Escrow: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Escrow: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sender = append(m.Sender[:0], dAtA[iNdEx:postIndex]...)
+			if m.Sender == nil {
+				m.Sender = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Arbiter", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Arbiter = append(m.Arbiter[:0], dAtA[iNdEx:postIndex]...)
+			if m.Arbiter == nil {
+				m.Arbiter = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Recipient", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Recipient = append(m.Recipient[:0], dAtA[iNdEx:postIndex]...)
+			if m.Recipient == nil {
+				m.Recipient = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Amount", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Amount = append(m.Amount, &x.Coin{})
+			if err := m.Amount[len(m.Amount)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
+			}
+			m.Timeout = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timeout |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Memo", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Memo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SenderCanRelease", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.SenderCanRelease = bool(v != 0)
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Target", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Target == nil {
+				m.Target = &x.Coin{}
+			}
+			if err := m.Target.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinPrice", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MinPrice == nil {
+				m.MinPrice = &x.Coin{}
+			}
+			if err := m.MinPrice.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxPrice", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MaxPrice == nil {
+				m.MaxPrice = &x.Coin{}
+			}
+			if err := m.MaxPrice.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Deposit", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Deposit == nil {
+				m.Deposit = &x.Coin{}
+			}
+			if err := m.Deposit.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bounty", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Bounty == nil {
+				m.Bounty = &x.Coin{}
+			}
+			if err := m.Bounty.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Observers", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Observers = append(m.Observers, make([]byte, postIndex-iNdEx))
+			copy(m.Observers[len(m.Observers)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MemoHash = append(m.MemoHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.MemoHash == nil {
+				m.MemoHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 15:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeartbeatWindow", wireType)
+			}
+			m.HeartbeatWindow = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HeartbeatWindow |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FundedBy", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FundedBy = append(m.FundedBy, make([]byte, postIndex-iNdEx))
+			copy(m.FundedBy[len(m.FundedBy)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Shares", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Shares = append(m.Shares, &Share{})
+			if err := m.Shares[len(m.Shares)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Quarantine", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Quarantine == nil {
+				m.Quarantine = &Quarantine{}
+			}
+			if err := m.Quarantine.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Clawback", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Clawback = append(m.Clawback[:0], dAtA[iNdEx:postIndex]...)
+			if m.Clawback == nil {
+				m.Clawback = []byte{}
+			}
+			iNdEx = postIndex
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Milestones", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Milestones = append(m.Milestones, &Milestone{})
+			if err := m.Milestones[len(m.Milestones)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 21:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attester", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attester = append(m.Attester[:0], dAtA[iNdEx:postIndex]...)
+			if m.Attester == nil {
+				m.Attester = []byte{}
+			}
+			iNdEx = postIndex
+		case 22:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DestTag", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DestTag = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 23:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BackupArbiter", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BackupArbiter = append(m.BackupArbiter[:0], dAtA[iNdEx:postIndex]...)
+			if m.BackupArbiter == nil {
+				m.BackupArbiter = []byte{}
+			}
+			iNdEx = postIndex
+		case 24:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SlaBlocks", wireType)
+			}
+			m.SlaBlocks = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SlaBlocks |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 25:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Dispute", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Dispute == nil {
+				m.Dispute = &Dispute{}
+			}
+			if err := m.Dispute.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 26:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PolicyReleased", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PolicyReleased = append(m.PolicyReleased, &x.Coin{})
+			if err := m.PolicyReleased[len(m.PolicyReleased)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Milestone) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Milestone: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Milestone: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Amount", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Amount = append(m.Amount, &x.Coin{})
+			if err := m.Amount[len(m.Amount)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attested", wireType)
+			}
+			m.Attested = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Attested |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Dispute) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Dispute: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Dispute: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Reason = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Escalated", wireType)
+			}
+			m.Escalated = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Escalated |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Quarantine) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Quarantine: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Quarantine: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Reason = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Share) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Share: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Share: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Address = append(m.Address[:0], dAtA[iNdEx:postIndex]...)
+			if m.Address == nil {
+				m.Address = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bps", wireType)
+			}
+			m.Bps = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Bps |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CreateEscrowMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CreateEscrowMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CreateEscrowMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sender = append(m.Sender[:0], dAtA[iNdEx:postIndex]...)
+			if m.Sender == nil {
+				m.Sender = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Arbiter", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Arbiter = append(m.Arbiter[:0], dAtA[iNdEx:postIndex]...)
+			if m.Arbiter == nil {
+				m.Arbiter = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Recipient", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Recipient = append(m.Recipient[:0], dAtA[iNdEx:postIndex]...)
+			if m.Recipient == nil {
+				m.Recipient = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Amount", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Amount = append(m.Amount, &x.Coin{})
+			if err := m.Amount[len(m.Amount)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
+			}
+			m.Timeout = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timeout |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Memo", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Memo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SenderCanRelease", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.SenderCanRelease = bool(v != 0)
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Target", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Target == nil {
+				m.Target = &x.Coin{}
+			}
+			if err := m.Target.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinPrice", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MinPrice == nil {
+				m.MinPrice = &x.Coin{}
+			}
+			if err := m.MinPrice.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxPrice", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MaxPrice == nil {
+				m.MaxPrice = &x.Coin{}
+			}
+			if err := m.MaxPrice.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bounty", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Bounty == nil {
+				m.Bounty = &x.Coin{}
+			}
+			if err := m.Bounty.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Observers", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Observers = append(m.Observers, make([]byte, postIndex-iNdEx))
+			copy(m.Observers[len(m.Observers)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MemoHash = append(m.MemoHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.MemoHash == nil {
+				m.MemoHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeartbeatWindow", wireType)
+			}
+			m.HeartbeatWindow = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HeartbeatWindow |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Shares", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Shares = append(m.Shares, &Share{})
+			if err := m.Shares[len(m.Shares)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Clawback", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Clawback = append(m.Clawback[:0], dAtA[iNdEx:postIndex]...)
+			if m.Clawback == nil {
+				m.Clawback = []byte{}
+			}
+			iNdEx = postIndex
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Milestones", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Milestones = append(m.Milestones, &Milestone{})
+			if err := m.Milestones[len(m.Milestones)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attester", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attester = append(m.Attester[:0], dAtA[iNdEx:postIndex]...)
+			if m.Attester == nil {
+				m.Attester = []byte{}
+			}
+			iNdEx = postIndex
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DestTag", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DestTag = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BackupArbiter", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BackupArbiter = append(m.BackupArbiter[:0], dAtA[iNdEx:postIndex]...)
+			if m.BackupArbiter == nil {
+				m.BackupArbiter = []byte{}
+			}
+			iNdEx = postIndex
+		case 21:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SlaBlocks", wireType)
+			}
+			m.SlaBlocks = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SlaBlocks |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CreateEscrowMsgV2) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CreateEscrowMsgV2: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CreateEscrowMsgV2: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sender = append(m.Sender[:0], dAtA[iNdEx:postIndex]...)
+			if m.Sender == nil {
+				m.Sender = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Arbiter", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Arbiter = append(m.Arbiter[:0], dAtA[iNdEx:postIndex]...)
+			if m.Arbiter == nil {
+				m.Arbiter = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Recipient", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Recipient = append(m.Recipient[:0], dAtA[iNdEx:postIndex]...)
+			if m.Recipient == nil {
+				m.Recipient = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Amount", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Amount = append(m.Amount, &x.Coin{})
+			if err := m.Amount[len(m.Amount)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
+			}
+			m.Timeout = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timeout |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeoutIn", wireType)
+			}
+			m.TimeoutIn = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimeoutIn |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Memo", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Memo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Options", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Options == nil {
+				m.Options = &EscrowOptions{}
+			}
+			if err := m.Options.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *EscrowOptions) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: EscrowOptions: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: EscrowOptions: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SenderCanRelease", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.SenderCanRelease = bool(v != 0)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Target", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Target == nil {
+				m.Target = &x.Coin{}
+			}
+			if err := m.Target.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinPrice", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MinPrice == nil {
+				m.MinPrice = &x.Coin{}
+			}
+			if err := m.MinPrice.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxPrice", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.MaxPrice == nil {
+				m.MaxPrice = &x.Coin{}
+			}
+			if err := m.MaxPrice.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bounty", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Bounty == nil {
+				m.Bounty = &x.Coin{}
+			}
+			if err := m.Bounty.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Observers", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Observers = append(m.Observers, make([]byte, postIndex-iNdEx))
+			copy(m.Observers[len(m.Observers)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MemoHash = append(m.MemoHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.MemoHash == nil {
+				m.MemoHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HeartbeatWindow", wireType)
+			}
+			m.HeartbeatWindow = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HeartbeatWindow |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Shares", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Shares = append(m.Shares, &Share{})
+			if err := m.Shares[len(m.Shares)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Clawback", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Clawback = append(m.Clawback[:0], dAtA[iNdEx:postIndex]...)
+			if m.Clawback == nil {
+				m.Clawback = []byte{}
+			}
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Milestones", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Milestones = append(m.Milestones, &Milestone{})
+			if err := m.Milestones[len(m.Milestones)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Attester", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Attester = append(m.Attester[:0], dAtA[iNdEx:postIndex]...)
+			if m.Attester == nil {
+				m.Attester = []byte{}
+			}
+			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DestTag", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DestTag = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BackupArbiter", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BackupArbiter = append(m.BackupArbiter[:0], dAtA[iNdEx:postIndex]...)
+			if m.BackupArbiter == nil {
+				m.BackupArbiter = []byte{}
+			}
+			iNdEx = postIndex
+		case 15:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SlaBlocks", wireType)
+			}
+			m.SlaBlocks = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SlaBlocks |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReleaseEscrowMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReleaseEscrowMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReleaseEscrowMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EscrowId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EscrowId = append(m.EscrowId[:0], dAtA[iNdEx:postIndex]...)
+			if m.EscrowId == nil {
+				m.EscrowId = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Amount", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Amount = append(m.Amount, &x.Coin{})
+			if err := m.Amount[len(m.Amount)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Policy", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Policy = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Chain", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Chain == nil {
+				m.Chain = &ChainEscrow{}
+			}
+			if err := m.Chain.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ChainEscrow) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ChainEscrow: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ChainEscrow: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EscrowId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EscrowId = append(m.EscrowId[:0], dAtA[iNdEx:postIndex]...)
+			if m.EscrowId == nil {
+				m.EscrowId = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Arbiter", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Arbiter = append(m.Arbiter[:0], dAtA[iNdEx:postIndex]...)
+			if m.Arbiter == nil {
+				m.Arbiter = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Recipient", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Recipient = append(m.Recipient[:0], dAtA[iNdEx:postIndex]...)
+			if m.Recipient == nil {
+				m.Recipient = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
+			}
+			m.Timeout = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timeout |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Memo", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Memo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReturnEscrowMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReturnEscrowMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReturnEscrowMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EscrowId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EscrowId = append(m.EscrowId[:0], dAtA[iNdEx:postIndex]...)
+			if m.EscrowId == nil {
+				m.EscrowId = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UpdateEscrowPartiesMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UpdateEscrowPartiesMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UpdateEscrowPartiesMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EscrowId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EscrowId = append(m.EscrowId[:0], dAtA[iNdEx:postIndex]...)
+			if m.EscrowId == nil {
+				m.EscrowId = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sender = append(m.Sender[:0], dAtA[iNdEx:postIndex]...)
+			if m.Sender == nil {
+				m.Sender = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Arbiter", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Arbiter = append(m.Arbiter[:0], dAtA[iNdEx:postIndex]...)
+			if m.Arbiter == nil {
+				m.Arbiter = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Recipient", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Recipient = append(m.Recipient[:0], dAtA[iNdEx:postIndex]...)
+			if m.Recipient == nil {
+				m.Recipient = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *UpdateEscrowObserversMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: UpdateEscrowObserversMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: UpdateEscrowObserversMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EscrowId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EscrowId = append(m.EscrowId[:0], dAtA[iNdEx:postIndex]...)
+			if m.EscrowId == nil {
+				m.EscrowId = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Add", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Add = append(m.Add, make([]byte, postIndex-iNdEx))
+			copy(m.Add[len(m.Add)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Remove", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Remove = append(m.Remove, make([]byte, postIndex-iNdEx))
+			copy(m.Remove[len(m.Remove)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RevealMemoMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RevealMemoMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RevealMemoMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EscrowId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EscrowId = append(m.EscrowId[:0], dAtA[iNdEx:postIndex]...)
+			if m.EscrowId == nil {
+				m.EscrowId = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Memo", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Memo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Salt", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Salt = append(m.Salt[:0], dAtA[iNdEx:postIndex]...)
+			if m.Salt == nil {
+				m.Salt = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Bid) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Bid: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Bid: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Arbiter", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Arbiter = append(m.Arbiter[:0], dAtA[iNdEx:postIndex]...)
+			if m.Arbiter == nil {
+				m.Arbiter = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fee", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Fee == nil {
+				m.Fee = &x.Coin{}
+			}
+			if err := m.Fee.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BidArbitrationMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BidArbitrationMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BidArbitrationMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EscrowId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EscrowId = append(m.EscrowId[:0], dAtA[iNdEx:postIndex]...)
+			if m.EscrowId == nil {
+				m.EscrowId = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Fee", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Fee == nil {
+				m.Fee = &x.Coin{}
+			}
+			if err := m.Fee.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AssignArbiterMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AssignArbiterMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AssignArbiterMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EscrowId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EscrowId = append(m.EscrowId[:0], dAtA[iNdEx:postIndex]...)
+			if m.EscrowId == nil {
+				m.EscrowId = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bidder", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Bidder = append(m.Bidder[:0], dAtA[iNdEx:postIndex]...)
+			if m.Bidder == nil {
+				m.Bidder = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Params) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Params: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Params: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DustThreshold", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DustThreshold = append(m.DustThreshold, &x.Coin{})
+			if err := m.DustThreshold[len(m.DustThreshold)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DustToSender", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.DustToSender = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxPerSender", wireType)
+			}
+			m.MaxPerSender = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxPerSender |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxLocked", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MaxLocked = append(m.MaxLocked, &x.Coin{})
+			if err := m.MaxLocked[len(m.MaxLocked)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GasPerByte", wireType)
+			}
+			m.GasPerByte = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.GasPerByte |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DepositPerBlock", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.DepositPerBlock == nil {
+				m.DepositPerBlock = &x.Coin{}
+			}
+			if err := m.DepositPerBlock.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OfferWindow", wireType)
+			}
+			m.OfferWindow = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.OfferWindow |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Locked) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Locked: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Locked: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Amount", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Amount = append(m.Amount, &x.Coin{})
+			if err := m.Amount[len(m.Amount)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NetEscrowsMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NetEscrowsMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NetEscrowsMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EscrowIds", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EscrowIds = append(m.EscrowIds, make([]byte, postIndex-iNdEx))
+			copy(m.EscrowIds[len(m.EscrowIds)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ArbiterPolicy) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ArbiterPolicy: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ArbiterPolicy: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxAmount", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MaxAmount = append(m.MaxAmount, &x.Coin{})
+			if err := m.MaxAmount[len(m.MaxAmount)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Recipients", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Recipients = append(m.Recipients, make([]byte, postIndex-iNdEx))
+			copy(m.Recipients[len(m.Recipients)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SetArbiterPolicyMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SetArbiterPolicyMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SetArbiterPolicyMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Policy", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Policy == nil {
+				m.Policy = &ArbiterPolicy{}
+			}
+			if err := m.Policy.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HistoryEntry) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HistoryEntry: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HistoryEntry: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Event", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Event = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Actor", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Actor = append(m.Actor[:0], dAtA[iNdEx:postIndex]...)
+			if m.Actor == nil {
+				m.Actor = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Amount", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Amount = append(m.Amount, &x.Coin{})
+			if err := m.Amount[len(m.Amount)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Note", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Note = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *EscrowExport) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: EscrowExport: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: EscrowExport: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
+			if m.Id == nil {
+				m.Id = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Escrow", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Escrow == nil {
+				m.Escrow = &Escrow{}
+			}
+			if err := m.Escrow.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Balance", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Balance = append(m.Balance, &x.Coin{})
+			if err := m.Balance[len(m.Balance)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Alias) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Alias: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Alias: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
+			if m.Id == nil {
+				m.Id = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *EscrowTemplate) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: EscrowTemplate: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: EscrowTemplate: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Description", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Description = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TimeoutIn", wireType)
+			}
+			m.TimeoutIn = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.TimeoutIn |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SenderCanRelease", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.SenderCanRelease = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bounty", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Bounty == nil {
+				m.Bounty = &x.Coin{}
+			}
+			if err := m.Bounty.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxAmount", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MaxAmount = append(m.MaxAmount, &x.Coin{})
+			if err := m.MaxAmount[len(m.MaxAmount)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CreateFromTemplateMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CreateFromTemplateMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CreateFromTemplateMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TemplateId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TemplateId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sender = append(m.Sender[:0], dAtA[iNdEx:postIndex]...)
+			if m.Sender == nil {
+				m.Sender = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Arbiter", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Arbiter = append(m.Arbiter[:0], dAtA[iNdEx:postIndex]...)
+			if m.Arbiter == nil {
+				m.Arbiter = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Recipient", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Recipient = append(m.Recipient[:0], dAtA[iNdEx:postIndex]...)
+			if m.Recipient == nil {
+				m.Recipient = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Amount", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Amount = append(m.Amount, &x.Coin{})
+			if err := m.Amount[len(m.Amount)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Memo", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Memo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SetTemplateMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SetTemplateMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SetTemplateMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TemplateId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TemplateId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Template", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Template == nil {
+				m.Template = &EscrowTemplate{}
+			}
+			if err := m.Template.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Heartbeat) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Heartbeat: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Heartbeat: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Due", wireType)
+			}
+			m.Due = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Due |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Escalation) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Escalation: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Escalation: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Due", wireType)
+			}
+			m.Due = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Due |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *PingEscrowMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: PingEscrowMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PingEscrowMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EscrowId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EscrowId = append(m.EscrowId[:0], dAtA[iNdEx:postIndex]...)
+			if m.EscrowId == nil {
+				m.EscrowId = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SendEscrowMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SendEscrowMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SendEscrowMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Src", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Src = append(m.Src[:0], dAtA[iNdEx:postIndex]...)
+			if m.Src == nil {
+				m.Src = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Dest", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Dest = append(m.Dest[:0], dAtA[iNdEx:postIndex]...)
+			if m.Dest == nil {
+				m.Dest = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Amount", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Amount == nil {
+				m.Amount = &x.Coin{}
+			}
+			if err := m.Amount.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Memo", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Memo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Escrow", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Escrow == nil {
+				m.Escrow = &EscrowInstructions{}
+			}
+			if err := m.Escrow.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
 	}
-	return n
-}
-
-func (m *ReturnEscrowMsg) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.EscrowId)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
-	}
-	return n
-}
-
-func (m *UpdateEscrowPartiesMsg) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.EscrowId)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
-	}
-	l = len(m.Sender)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
-	}
-	l = len(m.Arbiter)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
-	}
-	l = len(m.Recipient)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
-	}
-	return n
-}
 
-func sovCodec(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
-		}
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	return n
-}
-func sozCodec(x uint64) (n int) {
-	return sovCodec(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return nil
 }
-func (m *Escrow) Unmarshal(dAtA []byte) error {
+func (m *EscrowInstructions) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -637,15 +10911,15 @@ func (m *Escrow) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Escrow: wiretype end group for non-group")
+			return fmt.Errorf("proto: EscrowInstructions: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Escrow: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: EscrowInstructions: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Arbiter", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -669,16 +10943,16 @@ func (m *Escrow) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Sender = append(m.Sender[:0], dAtA[iNdEx:postIndex]...)
-			if m.Sender == nil {
-				m.Sender = []byte{}
+			m.Arbiter = append(m.Arbiter[:0], dAtA[iNdEx:postIndex]...)
+			if m.Arbiter == nil {
+				m.Arbiter = []byte{}
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Arbiter", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
 			}
-			var byteLen int
+			m.Timeout = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -688,26 +10962,64 @@ func (m *Escrow) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				m.Timeout |= (int64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
 				return ErrInvalidLengthCodec
 			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Arbiter = append(m.Arbiter[:0], dAtA[iNdEx:postIndex]...)
-			if m.Arbiter == nil {
-				m.Arbiter = []byte{}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QuarantineEscrowMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
 			}
-			iNdEx = postIndex
-		case 3:
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QuarantineEscrowMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QuarantineEscrowMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Recipient", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field EscrowId", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -731,64 +11043,14 @@ func (m *Escrow) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Recipient = append(m.Recipient[:0], dAtA[iNdEx:postIndex]...)
-			if m.Recipient == nil {
-				m.Recipient = []byte{}
-			}
-			iNdEx = postIndex
-		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Amount", wireType)
-			}
-			var msglen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowCodec
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			if msglen < 0 {
-				return ErrInvalidLengthCodec
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Amount = append(m.Amount, &x.Coin{})
-			if err := m.Amount[len(m.Amount)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.EscrowId = append(m.EscrowId[:0], dAtA[iNdEx:postIndex]...)
+			if m.EscrowId == nil {
+				m.EscrowId = []byte{}
 			}
 			iNdEx = postIndex
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
-			}
-			m.Timeout = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowCodec
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Timeout |= (int64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 6:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Memo", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -813,7 +11075,7 @@ func (m *Escrow) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Memo = string(dAtA[iNdEx:postIndex])
+			m.Reason = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -836,7 +11098,7 @@ func (m *Escrow) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *CreateEscrowMsg) Unmarshal(dAtA []byte) error {
+func (m *RestoreEscrowMsg) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -859,15 +11121,15 @@ func (m *CreateEscrowMsg) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: CreateEscrowMsg: wiretype end group for non-group")
+			return fmt.Errorf("proto: RestoreEscrowMsg: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: CreateEscrowMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: RestoreEscrowMsg: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field EscrowId", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -891,45 +11153,64 @@ func (m *CreateEscrowMsg) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Sender = append(m.Sender[:0], dAtA[iNdEx:postIndex]...)
-			if m.Sender == nil {
-				m.Sender = []byte{}
+			m.EscrowId = append(m.EscrowId[:0], dAtA[iNdEx:postIndex]...)
+			if m.EscrowId == nil {
+				m.EscrowId = []byte{}
 			}
 			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Arbiter", wireType)
-			}
-			var byteLen int
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowCodec
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			if byteLen < 0 {
+			if skippy < 0 {
 				return ErrInvalidLengthCodec
 			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
+			if (iNdEx + skippy) > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Arbiter = append(m.Arbiter[:0], dAtA[iNdEx:postIndex]...)
-			if m.Arbiter == nil {
-				m.Arbiter = []byte{}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ForceSettleEscrowMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
 			}
-			iNdEx = postIndex
-		case 3:
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ForceSettleEscrowMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ForceSettleEscrowMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Recipient", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field EscrowId", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -953,14 +11234,14 @@ func (m *CreateEscrowMsg) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Recipient = append(m.Recipient[:0], dAtA[iNdEx:postIndex]...)
-			if m.Recipient == nil {
-				m.Recipient = []byte{}
+			m.EscrowId = append(m.EscrowId[:0], dAtA[iNdEx:postIndex]...)
+			if m.EscrowId == nil {
+				m.EscrowId = []byte{}
 			}
 			iNdEx = postIndex
-		case 4:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Amount", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Release", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -984,16 +11265,66 @@ func (m *CreateEscrowMsg) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Amount = append(m.Amount, &x.Coin{})
-			if err := m.Amount[len(m.Amount)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Release = append(m.Release, &x.Coin{})
+			if err := m.Release[len(m.Release)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
 			}
-			m.Timeout = 0
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ClawbackEscrowMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ClawbackEscrowMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ClawbackEscrowMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EscrowId", wireType)
+			}
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -1003,14 +11334,26 @@ func (m *CreateEscrowMsg) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Timeout |= (int64(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 6:
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.EscrowId = append(m.EscrowId[:0], dAtA[iNdEx:postIndex]...)
+			if m.EscrowId == nil {
+				m.EscrowId = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Memo", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -1035,7 +11378,7 @@ func (m *CreateEscrowMsg) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Memo = string(dAtA[iNdEx:postIndex])
+			m.Reason = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1058,7 +11401,7 @@ func (m *CreateEscrowMsg) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ReleaseEscrowMsg) Unmarshal(dAtA []byte) error {
+func (m *AttestMilestoneMsg) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1081,10 +11424,10 @@ func (m *ReleaseEscrowMsg) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ReleaseEscrowMsg: wiretype end group for non-group")
+			return fmt.Errorf("proto: AttestMilestoneMsg: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ReleaseEscrowMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: AttestMilestoneMsg: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -1119,10 +11462,10 @@ func (m *ReleaseEscrowMsg) Unmarshal(dAtA []byte) error {
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Amount", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Milestone", wireType)
 			}
-			var msglen int
+			m.Milestone = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -1132,23 +11475,11 @@ func (m *ReleaseEscrowMsg) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				m.Milestone |= (int32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
-				return ErrInvalidLengthCodec
-			}
-			postIndex := iNdEx + msglen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Amount = append(m.Amount, &x.Coin{})
-			if err := m.Amount[len(m.Amount)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipCodec(dAtA[iNdEx:])
@@ -1170,7 +11501,7 @@ func (m *ReleaseEscrowMsg) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *ReturnEscrowMsg) Unmarshal(dAtA []byte) error {
+func (m *OfferEscrowPartyMsg) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1193,10 +11524,10 @@ func (m *ReturnEscrowMsg) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ReturnEscrowMsg: wiretype end group for non-group")
+			return fmt.Errorf("proto: OfferEscrowPartyMsg: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ReturnEscrowMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: OfferEscrowPartyMsg: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -1230,6 +11561,66 @@ func (m *ReturnEscrowMsg) Unmarshal(dAtA []byte) error {
 				m.EscrowId = []byte{}
 			}
 			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Role", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Role = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field To", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.To = append(m.To[:0], dAtA[iNdEx:postIndex]...)
+			if m.To == nil {
+				m.To = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipCodec(dAtA[iNdEx:])
@@ -1251,7 +11642,7 @@ func (m *ReturnEscrowMsg) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *UpdateEscrowPartiesMsg) Unmarshal(dAtA []byte) error {
+func (m *AcceptEscrowPartyMsg) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1274,10 +11665,10 @@ func (m *UpdateEscrowPartiesMsg) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: UpdateEscrowPartiesMsg: wiretype end group for non-group")
+			return fmt.Errorf("proto: AcceptEscrowPartyMsg: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: UpdateEscrowPartiesMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: AcceptEscrowPartyMsg: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -1313,9 +11704,9 @@ func (m *UpdateEscrowPartiesMsg) Unmarshal(dAtA []byte) error {
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Role", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -1325,26 +11716,74 @@ func (m *UpdateEscrowPartiesMsg) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthCodec
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Sender = append(m.Sender[:0], dAtA[iNdEx:postIndex]...)
-			if m.Sender == nil {
-				m.Sender = []byte{}
-			}
+			m.Role = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 3:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DisputeEscrowMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DisputeEscrowMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DisputeEscrowMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Arbiter", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field EscrowId", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -1368,16 +11807,16 @@ func (m *UpdateEscrowPartiesMsg) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Arbiter = append(m.Arbiter[:0], dAtA[iNdEx:postIndex]...)
-			if m.Arbiter == nil {
-				m.Arbiter = []byte{}
+			m.EscrowId = append(m.EscrowId[:0], dAtA[iNdEx:postIndex]...)
+			if m.EscrowId == nil {
+				m.EscrowId = []byte{}
 			}
 			iNdEx = postIndex
-		case 4:
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Recipient", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Reason", wireType)
 			}
-			var byteLen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -1387,22 +11826,20 @@ func (m *UpdateEscrowPartiesMsg) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				stringLen |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthCodec
 			}
-			postIndex := iNdEx + byteLen
+			postIndex := iNdEx + intStringLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Recipient = append(m.Recipient[:0], dAtA[iNdEx:postIndex]...)
-			if m.Recipient == nil {
-				m.Recipient = []byte{}
-			}
+			m.Reason = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -1533,26 +11970,117 @@ var (
 func init() { proto.RegisterFile("x/escrow/codec.proto", fileDescriptorCodec) }
 
 var fileDescriptorCodec = []byte{
-	// 323 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x92, 0xcf, 0x4a, 0xf3, 0x40,
-	0x14, 0xc5, 0xbf, 0x69, 0xfa, 0xa5, 0xf6, 0x2a, 0xb4, 0x0c, 0x52, 0x06, 0x95, 0x18, 0x02, 0x42,
-	0x56, 0x09, 0xe8, 0x1b, 0x58, 0x5c, 0xb8, 0x10, 0xca, 0x80, 0x6b, 0x99, 0x4e, 0xae, 0x75, 0xc0,
-	0xcc, 0x94, 0xc9, 0xc4, 0xf6, 0x05, 0xdc, 0xfb, 0x16, 0x6e, 0x7d, 0x0c, 0x97, 0x3e, 0x82, 0xd4,
-	0x17, 0x11, 0x93, 0xd6, 0x06, 0xf1, 0xdf, 0xd2, 0xdd, 0x9c, 0x73, 0x67, 0xee, 0x3d, 0xfc, 0xe6,
-	0xc2, 0xf6, 0x3c, 0xc5, 0x42, 0x5a, 0x33, 0x4b, 0xa5, 0xc9, 0x50, 0x26, 0x53, 0x6b, 0x9c, 0xa1,
-	0x7e, 0xed, 0xed, 0x1c, 0x4c, 0x94, 0xbb, 0x2a, 0xc7, 0x89, 0x34, 0x79, 0x2a, 0x8d, 0xbe, 0x54,
-	0x26, 0x9d, 0xa1, 0xb8, 0xc1, 0x74, 0xde, 0xbc, 0x1e, 0xdd, 0x13, 0xf0, 0x4f, 0xaa, 0x17, 0x74,
-	0x00, 0x7e, 0x81, 0x3a, 0x43, 0xcb, 0x48, 0x48, 0xe2, 0x2d, 0xbe, 0x54, 0x94, 0x41, 0x47, 0xd8,
-	0xb1, 0x72, 0x68, 0x59, 0xab, 0x2a, 0xac, 0x24, 0xdd, 0x83, 0xae, 0x45, 0xa9, 0xa6, 0x0a, 0xb5,
-	0x63, 0x5e, 0x55, 0x5b, 0x1b, 0x74, 0x1f, 0x7c, 0x91, 0x9b, 0x52, 0x3b, 0xd6, 0x0e, 0xbd, 0x78,
-	0xf3, 0xb0, 0x93, 0xcc, 0x93, 0xa1, 0x51, 0x9a, 0x2f, 0xed, 0xb7, 0xc6, 0x4e, 0xe5, 0x68, 0x4a,
-	0xc7, 0xfe, 0x87, 0x24, 0xf6, 0xf8, 0x4a, 0x52, 0x0a, 0xed, 0x1c, 0x73, 0xc3, 0xfc, 0x90, 0xc4,
-	0x5d, 0x5e, 0x9d, 0xa3, 0x07, 0x02, 0xbd, 0xa1, 0x45, 0xe1, 0xb0, 0xce, 0x7b, 0x56, 0x4c, 0xfe,
-	0x7a, 0xe4, 0x11, 0xf4, 0x39, 0x5e, 0xa3, 0x28, 0x1a, 0x91, 0x77, 0xa1, 0x5b, 0xff, 0xd0, 0x85,
-	0xca, 0x96, 0xa9, 0x37, 0x6a, 0xe3, 0x34, 0x6b, 0xcc, 0x6f, 0x7d, 0x3a, 0x3f, 0x4a, 0xa0, 0xc7,
-	0xd1, 0x95, 0x56, 0xff, 0xae, 0x61, 0x74, 0x4b, 0x60, 0x70, 0x3e, 0xcd, 0xde, 0xa1, 0x8d, 0x84,
-	0x75, 0x0a, 0x8b, 0x1f, 0x83, 0xac, 0xc1, 0xb6, 0xbe, 0x02, 0xeb, 0x7d, 0x03, 0xb6, 0xfd, 0x01,
-	0xec, 0x71, 0xff, 0x71, 0x11, 0x90, 0xa7, 0x45, 0x40, 0x9e, 0x17, 0x01, 0xb9, 0x7b, 0x09, 0xfe,
-	0x8d, 0xfd, 0x6a, 0xff, 0x8e, 0x5e, 0x03, 0x00, 0x00, 0xff, 0xff, 0x6f, 0x51, 0x9d, 0xde, 0xc6,
-	0x02, 0x00, 0x00,
+	// 1779 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x58, 0xcd, 0x6f, 0x1b, 0xb9,
+	0x15, 0xef, 0x68, 0xf4, 0xf9, 0xac, 0x2f, 0xd3, 0x8e, 0x77, 0x92, 0xdd, 0x78, 0x15, 0x62, 0xb3,
+	0x70, 0x80, 0xad, 0xdc, 0x3a, 0xd7, 0x5e, 0x6c, 0x6f, 0x76, 0x9d, 0xb6, 0x69, 0xdc, 0x71, 0x9a,
+	0x5c, 0x0a, 0x08, 0xd4, 0x0c, 0x2d, 0x0d, 0x56, 0x1a, 0xaa, 0x24, 0x65, 0x5b, 0xd7, 0x02, 0x45,
+	0x4f, 0x05, 0x16, 0xe8, 0xbd, 0xa7, 0xa2, 0xff, 0x45, 0x8f, 0x05, 0xf6, 0xd8, 0x3f, 0xa1, 0x48,
+	0xff, 0x91, 0x82, 0x5f, 0xd2, 0x8c, 0xd6, 0x96, 0xe4, 0x4d, 0x0f, 0x3d, 0xf4, 0xc6, 0xf7, 0x31,
+	0x8f, 0xe4, 0xe3, 0xfb, 0xfd, 0xf8, 0x38, 0xb0, 0x7b, 0x73, 0x48, 0x45, 0xc4, 0xd9, 0xf5, 0x61,
+	0xc4, 0x62, 0x1a, 0x75, 0x27, 0x9c, 0x49, 0x86, 0xca, 0x46, 0xf7, 0xe8, 0xe9, 0x20, 0x91, 0xc3,
+	0x69, 0xbf, 0x1b, 0xb1, 0xf1, 0x61, 0xc4, 0xd2, 0xcb, 0x84, 0x1d, 0x5e, 0x53, 0x72, 0x45, 0x0f,
+	0x6f, 0xb2, 0xee, 0xf8, 0x4f, 0x15, 0x28, 0xbf, 0xd0, 0x5f, 0xa0, 0x3d, 0x28, 0x0b, 0x9a, 0xc6,
+	0x94, 0x07, 0x5e, 0xc7, 0x3b, 0xa8, 0x87, 0x56, 0x42, 0x01, 0x54, 0x08, 0xef, 0x27, 0x92, 0xf2,
+	0xa0, 0xa0, 0x0d, 0x4e, 0x44, 0x9f, 0x40, 0x8d, 0xd3, 0x28, 0x99, 0x24, 0x34, 0x95, 0x81, 0xaf,
+	0x6d, 0x0b, 0x05, 0xfa, 0x14, 0xca, 0x64, 0xcc, 0xa6, 0xa9, 0x0c, 0x8a, 0x1d, 0xff, 0x60, 0xeb,
+	0xa8, 0xd2, 0xbd, 0xe9, 0x9e, 0xb2, 0x24, 0x0d, 0xad, 0x5a, 0x05, 0x96, 0xc9, 0x98, 0xb2, 0xa9,
+	0x0c, 0x4a, 0x1d, 0xef, 0xc0, 0x0f, 0x9d, 0x88, 0x10, 0x14, 0xc7, 0x74, 0xcc, 0x82, 0x72, 0xc7,
+	0x3b, 0xa8, 0x85, 0x7a, 0x8c, 0xbe, 0x00, 0x64, 0x16, 0xd4, 0x8b, 0x48, 0xda, 0xe3, 0x74, 0x44,
+	0x89, 0xa0, 0x41, 0xa5, 0xe3, 0x1d, 0x54, 0xc3, 0xb6, 0xb1, 0x9c, 0x92, 0x34, 0x34, 0x7a, 0x35,
+	0xb9, 0x24, 0x7c, 0x40, 0x65, 0x50, 0xed, 0x78, 0xb9, 0xc9, 0x8d, 0x1a, 0x7d, 0x06, 0xb5, 0x71,
+	0x92, 0xf6, 0x26, 0x3c, 0x89, 0x68, 0x50, 0xcb, 0xfb, 0x54, 0xc7, 0x49, 0x7a, 0xae, 0x0c, 0xda,
+	0x8b, 0xdc, 0x58, 0x2f, 0x58, 0xf6, 0x22, 0x37, 0xc6, 0xeb, 0x09, 0x54, 0x62, 0x3a, 0x61, 0x22,
+	0x91, 0xc1, 0x56, 0xde, 0xc7, 0xe9, 0xd5, 0x7a, 0xfa, 0x6a, 0xd3, 0xb3, 0xa0, 0xbe, 0xb4, 0x1e,
+	0xa3, 0x56, 0xb9, 0x64, 0x7d, 0x41, 0xf9, 0x15, 0xe5, 0x22, 0x68, 0x74, 0x7c, 0x95, 0xcb, 0xb9,
+	0x02, 0x7d, 0x0c, 0x35, 0x95, 0x84, 0xde, 0x90, 0x88, 0x61, 0xd0, 0xd4, 0x99, 0xae, 0x2a, 0xc5,
+	0x19, 0x11, 0x43, 0xf4, 0x0c, 0xda, 0x43, 0x4a, 0xb8, 0xec, 0x53, 0x22, 0x7b, 0xd7, 0x49, 0x1a,
+	0xb3, 0xeb, 0xa0, 0xa5, 0x13, 0xda, 0x9a, 0xeb, 0xdf, 0x69, 0xb5, 0x8a, 0x73, 0x39, 0x4d, 0x63,
+	0x1a, 0xf7, 0xfa, 0xb3, 0xa0, 0xad, 0x67, 0xa9, 0x1a, 0xc5, 0xc9, 0x0c, 0x3d, 0x85, 0xb2, 0x18,
+	0x12, 0x4e, 0x45, 0xb0, 0xad, 0x0f, 0xac, 0xd1, 0x35, 0xb5, 0xd4, 0xbd, 0x50, 0xda, 0xd0, 0x1a,
+	0xd1, 0x11, 0xc0, 0xef, 0xa6, 0x84, 0x93, 0x54, 0x26, 0x29, 0x0d, 0x90, 0xde, 0x0e, 0x72, 0xae,
+	0xbf, 0x9e, 0x5b, 0xc2, 0x8c, 0x17, 0x7a, 0x04, 0xd5, 0x68, 0x44, 0xae, 0xfb, 0x24, 0xfa, 0x26,
+	0xd8, 0x31, 0xcb, 0x77, 0x32, 0xfa, 0x29, 0xc0, 0x38, 0x19, 0x51, 0x21, 0x59, 0x4a, 0x45, 0xb0,
+	0xab, 0xa7, 0xde, 0x76, 0xf1, 0x5e, 0x39, 0x4b, 0x98, 0x71, 0x52, 0xe1, 0x88, 0x94, 0x54, 0xa8,
+	0x9a, 0x7c, 0x60, 0xc2, 0x39, 0x19, 0x3d, 0x84, 0x6a, 0x4c, 0x85, 0xec, 0x49, 0x32, 0x08, 0xf6,
+	0x74, 0xfd, 0x54, 0x94, 0xfc, 0x86, 0x0c, 0xd0, 0x53, 0x68, 0xaa, 0x19, 0xa7, 0x93, 0x9e, 0x2b,
+	0xe8, 0x8f, 0xf4, 0xc7, 0x0d, 0xa3, 0x3d, 0x36, 0x4a, 0xf4, 0x18, 0x40, 0x8c, 0x48, 0xaf, 0x3f,
+	0x62, 0xd1, 0x37, 0x22, 0x08, 0x74, 0x26, 0x6b, 0x62, 0x44, 0x4e, 0xb4, 0x02, 0x3d, 0x83, 0x4a,
+	0x9c, 0x88, 0xc9, 0x54, 0xd2, 0xe0, 0xa1, 0xde, 0x7c, 0xcb, 0x2d, 0xf6, 0x4b, 0xa3, 0x0e, 0x9d,
+	0x1d, 0xfd, 0x04, 0x5a, 0x13, 0x36, 0x4a, 0xa2, 0x99, 0xab, 0xd7, 0x38, 0x78, 0x94, 0xc7, 0x42,
+	0xd3, 0xd8, 0x6d, 0xd9, 0xc6, 0xf8, 0xb7, 0x50, 0x9b, 0x6f, 0x59, 0xc1, 0x20, 0x25, 0x63, 0xaa,
+	0xf1, 0x58, 0x0b, 0xf5, 0x38, 0x83, 0xaa, 0xc2, 0xed, 0xa8, 0x5a, 0xe4, 0x26, 0xd6, 0x98, 0xf4,
+	0xe7, 0xb9, 0x89, 0xf1, 0x3b, 0xa8, 0xd8, 0x35, 0x2a, 0xb4, 0x73, 0x4a, 0x04, 0x4b, 0x6d, 0x74,
+	0x2b, 0x29, 0xfd, 0x90, 0x26, 0x83, 0xa1, 0xd4, 0x60, 0xf7, 0x43, 0x2b, 0xa9, 0xfa, 0xa4, 0x22,
+	0x22, 0x23, 0xb2, 0x88, 0xbb, 0x50, 0xe0, 0x9f, 0x01, 0x2c, 0x4e, 0xfe, 0xbe, 0xb1, 0xf1, 0x73,
+	0x28, 0xe9, 0x12, 0xd3, 0x54, 0x13, 0xc7, 0x9c, 0x0a, 0x61, 0x39, 0xc8, 0x89, 0xa8, 0x0d, 0x7e,
+	0x7f, 0x22, 0xf4, 0x77, 0xa5, 0x50, 0x0d, 0xf1, 0x3f, 0x4a, 0xd0, 0x3a, 0xe5, 0x94, 0x48, 0x6a,
+	0xf8, 0xeb, 0x95, 0x18, 0xfc, 0x9f, 0xc2, 0x7e, 0x30, 0x85, 0x2d, 0xf8, 0x69, 0x6b, 0x03, 0x7e,
+	0xaa, 0xaf, 0xe4, 0xa7, 0xc6, 0x06, 0xfc, 0xd4, 0xbc, 0x9d, 0x9f, 0x16, 0x14, 0xd4, 0x5a, 0x45,
+	0x41, 0x59, 0x3a, 0x69, 0xaf, 0xa4, 0x93, 0xed, 0xfb, 0xd2, 0x09, 0x5a, 0x41, 0x27, 0x3b, 0xeb,
+	0xe8, 0x64, 0x77, 0x3d, 0x9d, 0x3c, 0x58, 0xa2, 0x13, 0xfc, 0xfb, 0x02, 0x6c, 0x2f, 0xd5, 0xf1,
+	0xdb, 0xa3, 0xff, 0xa5, 0x4a, 0x7e, 0x0c, 0x60, 0x87, 0xbd, 0x24, 0xd5, 0xf5, 0xec, 0x87, 0x35,
+	0xab, 0x79, 0x99, 0xce, 0x0b, 0xbd, 0x92, 0x29, 0xf4, 0x43, 0xa8, 0xb0, 0x89, 0x4c, 0x58, 0x2a,
+	0x6c, 0xed, 0x3e, 0x70, 0x07, 0x60, 0xf6, 0xf8, 0xda, 0x18, 0x43, 0xe7, 0x85, 0xff, 0x5a, 0x84,
+	0x46, 0xce, 0x74, 0x07, 0x56, 0xbc, 0xb5, 0x58, 0x29, 0x6c, 0x80, 0x15, 0x7f, 0x23, 0xac, 0x14,
+	0xd7, 0x63, 0xa5, 0xb4, 0x01, 0x56, 0xca, 0x2b, 0xb1, 0x52, 0xd9, 0x00, 0x2b, 0xd5, 0x75, 0x58,
+	0xa9, 0x6d, 0x8a, 0x15, 0x58, 0x89, 0x95, 0xad, 0xfb, 0x62, 0xa5, 0xbe, 0x02, 0x2b, 0x8d, 0x75,
+	0x58, 0x69, 0xae, 0xc7, 0x4a, 0x6b, 0x19, 0x2b, 0x7f, 0xf6, 0xa0, 0x6d, 0x8f, 0x7c, 0x41, 0xfa,
+	0x1f, 0xeb, 0x9b, 0x89, 0xb3, 0xeb, 0x5e, 0x12, 0x5b, 0xb4, 0x54, 0x8d, 0xe2, 0x65, 0xbc, 0xfe,
+	0xba, 0xdc, 0x83, 0xb2, 0xb9, 0x82, 0x75, 0x55, 0x54, 0x43, 0x2b, 0xa1, 0x67, 0x50, 0x8a, 0x86,
+	0x24, 0x49, 0x6d, 0x19, 0xec, 0xb8, 0xac, 0x9c, 0x2a, 0xa5, 0x99, 0x3c, 0x34, 0x1e, 0xf8, 0x5b,
+	0x0f, 0xb6, 0x32, 0xea, 0xd5, 0x0b, 0xfa, 0xa1, 0x00, 0xce, 0xe0, 0xb3, 0x78, 0xfb, 0x4d, 0x53,
+	0x5a, 0x00, 0x10, 0x77, 0xa1, 0x15, 0x52, 0x39, 0xe5, 0xe9, 0x66, 0x69, 0xc2, 0x7f, 0xf0, 0x60,
+	0xef, 0x37, 0x93, 0x78, 0x4e, 0x42, 0xe7, 0x84, 0xcb, 0x84, 0x8a, 0xb5, 0xe9, 0x5d, 0xd0, 0x54,
+	0xe1, 0x2e, 0x9a, 0xf2, 0x57, 0xec, 0xb2, 0xb8, 0xb4, 0x4b, 0x4c, 0x20, 0xc8, 0x2e, 0xe3, 0xb5,
+	0x03, 0xcd, 0xda, 0x85, 0xb4, 0xc1, 0x27, 0x71, 0xac, 0x0f, 0xb9, 0x1e, 0xaa, 0xa1, 0x69, 0x42,
+	0xc6, 0xec, 0x4a, 0xc1, 0x5d, 0x29, 0xad, 0x84, 0xdf, 0x40, 0x23, 0xa4, 0x57, 0x94, 0x8c, 0x5e,
+	0xd1, 0x31, 0x5b, 0x1b, 0xd7, 0x25, 0xb7, 0x90, 0x61, 0x37, 0x04, 0x45, 0x41, 0x46, 0xee, 0x8c,
+	0xf4, 0x18, 0x87, 0xe0, 0x9f, 0x24, 0xb9, 0xd3, 0xf5, 0xf2, 0xfb, 0x7e, 0x08, 0xfe, 0x25, 0xa5,
+	0xcb, 0xf4, 0xa4, 0x74, 0x99, 0xb6, 0xc8, 0xcf, 0xb5, 0x45, 0xbf, 0x80, 0xed, 0x93, 0x24, 0xd6,
+	0xd0, 0xe0, 0x44, 0xb1, 0xe2, 0xda, 0xd5, 0xde, 0x3d, 0x09, 0xfe, 0x1a, 0xda, 0xc7, 0x42, 0x24,
+	0x83, 0xd4, 0x42, 0x6d, 0x93, 0xa3, 0xed, 0x27, 0x71, 0xe6, 0x68, 0x8d, 0x84, 0xff, 0x56, 0x80,
+	0xf2, 0x39, 0xe1, 0x64, 0x2c, 0x50, 0x17, 0x9a, 0xf1, 0x54, 0xe1, 0x7d, 0xc8, 0xa9, 0x18, 0xb2,
+	0x91, 0x0a, 0x92, 0x03, 0x59, 0x43, 0x99, 0xdf, 0x38, 0x2b, 0xfa, 0xcc, 0xf9, 0xb3, 0x5e, 0xa6,
+	0x6a, 0xaa, 0x61, 0x5d, 0xbb, 0xb1, 0x0b, 0xad, 0x53, 0x5e, 0x9a, 0x84, 0x29, 0x77, 0x5e, 0x26,
+	0x2d, 0x75, 0x45, 0xc0, 0x94, 0x5b, 0xaf, 0xcf, 0x01, 0x94, 0x97, 0xe2, 0x05, 0x1a, 0x2f, 0x5f,
+	0x6a, 0x8a, 0xc5, 0x7f, 0xa9, 0x2d, 0xa8, 0x03, 0xf5, 0x01, 0x11, 0x3a, 0x5a, 0x7f, 0x26, 0xa9,
+	0xbd, 0xdc, 0x60, 0x40, 0xc4, 0x39, 0xe5, 0x27, 0x33, 0x49, 0xd1, 0x73, 0xd8, 0xb6, 0xaf, 0x34,
+	0xe3, 0xa5, 0x42, 0xea, 0x6b, 0x2e, 0x13, 0xb0, 0x65, 0x3d, 0xd4, 0x37, 0xca, 0x8e, 0x9e, 0x40,
+	0x9d, 0x5d, 0x5e, 0x52, 0xee, 0x38, 0xba, 0xa2, 0xc3, 0x6e, 0x69, 0x9d, 0xe1, 0x67, 0xfc, 0x0c,
+	0xca, 0x76, 0x0d, 0x0b, 0x12, 0xf2, 0x6e, 0x25, 0x21, 0xdc, 0x85, 0xc6, 0xaf, 0xa8, 0x34, 0x35,
+	0xaf, 0x6b, 0xfd, 0x31, 0xc0, 0xfc, 0x64, 0x84, 0xfe, 0xaa, 0x1e, 0xd6, 0xdc, 0xd1, 0x08, 0xfc,
+	0x0e, 0x1a, 0xf6, 0x18, 0xcf, 0x0d, 0x5b, 0xd9, 0x6c, 0xdc, 0x3e, 0x8b, 0xca, 0xc6, 0xb1, 0xb6,
+	0xa0, 0x7d, 0x80, 0x39, 0xd8, 0x84, 0x45, 0x4b, 0x46, 0x83, 0xbf, 0x84, 0x9d, 0x0b, 0x2a, 0x73,
+	0xb1, 0xd5, 0x72, 0x7e, 0x3c, 0x27, 0x49, 0x2f, 0x7f, 0x9d, 0xe7, 0x3c, 0x1d, 0x77, 0xe2, 0x3f,
+	0x7a, 0x50, 0x3f, 0x4b, 0x84, 0x64, 0x7c, 0xf6, 0x22, 0x95, 0x7c, 0x86, 0x76, 0xa1, 0x44, 0xaf,
+	0xa8, 0x5e, 0x99, 0x82, 0x91, 0x11, 0xee, 0x7c, 0x6a, 0xec, 0x42, 0x89, 0x44, 0x92, 0x39, 0xea,
+	0x30, 0xc2, 0xfa, 0x0e, 0x46, 0xbd, 0x96, 0x98, 0x3d, 0x61, 0xf5, 0x5a, 0x62, 0x92, 0xe2, 0x04,
+	0xea, 0x26, 0xab, 0x2f, 0x6e, 0x26, 0x8c, 0x4b, 0xd4, 0x84, 0xc2, 0xbc, 0xd4, 0x0b, 0x49, 0x8c,
+	0x3e, 0x07, 0xfb, 0xbf, 0xc4, 0x62, 0xa6, 0x99, 0xef, 0x53, 0x42, 0x6b, 0x55, 0x2f, 0xfc, 0x3e,
+	0x19, 0x91, 0x34, 0x32, 0x6c, 0x92, 0x7d, 0xe1, 0x5b, 0x3d, 0xfe, 0x08, 0x4a, 0xc7, 0xa3, 0x84,
+	0x88, 0xe5, 0x39, 0xf0, 0x77, 0x1e, 0x34, 0x4d, 0xb8, 0x37, 0x74, 0x3c, 0x51, 0xef, 0x25, 0xd4,
+	0x81, 0xad, 0x58, 0x45, 0x4e, 0x74, 0xb3, 0x63, 0xb3, 0x92, 0x55, 0x2d, 0x35, 0x5d, 0x85, 0xe5,
+	0xa6, 0xeb, 0xf6, 0xee, 0xc8, 0xbf, 0xbb, 0x3b, 0xb2, 0x0d, 0x4b, 0xf1, 0xf6, 0x86, 0x25, 0x5f,
+	0x3e, 0xa5, 0xbb, 0xca, 0x07, 0xff, 0xdd, 0x83, 0x07, 0xa6, 0x57, 0xfd, 0x8a, 0xb3, 0xb1, 0xdb,
+	0x8e, 0xaa, 0x90, 0x4f, 0x61, 0x4b, 0x5a, 0xd1, 0x91, 0x49, 0x2d, 0x04, 0xa7, 0xfa, 0xef, 0xdf,
+	0x14, 0x99, 0x72, 0x28, 0xdd, 0x59, 0x0e, 0xcb, 0x0f, 0x30, 0x4c, 0xa1, 0x79, 0x41, 0xe5, 0xbd,
+	0xd6, 0x7d, 0x04, 0x55, 0x27, 0xd9, 0x1a, 0xd9, 0xcb, 0xd7, 0x88, 0x8b, 0x16, 0xce, 0xfd, 0xf0,
+	0x63, 0xa8, 0x9d, 0xb9, 0x66, 0x4d, 0xdd, 0x4c, 0xf1, 0xd4, 0x74, 0xae, 0x7e, 0xa8, 0x86, 0x78,
+	0x1f, 0xe0, 0x85, 0x79, 0x39, 0xab, 0x93, 0xfe, 0xbe, 0xfd, 0x0b, 0x68, 0x9c, 0x27, 0xe9, 0x60,
+	0xc3, 0xab, 0xfb, 0x2f, 0x1e, 0x34, 0x14, 0x27, 0x2e, 0xdc, 0xdb, 0xe0, 0x0b, 0x1e, 0x59, 0x47,
+	0x35, 0x54, 0xb9, 0x50, 0x8d, 0x98, 0x4d, 0xbd, 0x1e, 0x67, 0x12, 0xb8, 0xd4, 0x0e, 0x2f, 0x27,
+	0xb0, 0x98, 0xb9, 0xfa, 0x8e, 0xe6, 0x78, 0x31, 0xad, 0xef, 0xa3, 0x7c, 0x2e, 0x5e, 0xa6, 0x42,
+	0xf2, 0x69, 0x64, 0x9a, 0x7b, 0xeb, 0x89, 0xcf, 0x00, 0x7d, 0xdf, 0xba, 0xe2, 0xa6, 0xcc, 0x74,
+	0x3a, 0x85, 0x5c, 0xa7, 0x83, 0x7f, 0x0e, 0x3b, 0x8b, 0xbf, 0x0c, 0x1b, 0x36, 0x80, 0x8b, 0x7f,
+	0x11, 0x85, 0xec, 0xbf, 0x08, 0x7c, 0xa8, 0x3a, 0x49, 0x45, 0x51, 0x1b, 0x06, 0xc2, 0x6f, 0x61,
+	0xf7, 0x2b, 0xc6, 0x23, 0x7a, 0x41, 0xa5, 0x1c, 0x6d, 0x3a, 0xfb, 0x13, 0xa8, 0x38, 0x70, 0x2e,
+	0xf5, 0x9f, 0x4e, 0x8f, 0xcf, 0x60, 0xfb, 0xd4, 0xf6, 0xe3, 0x1f, 0xb8, 0xa5, 0xd7, 0x80, 0x8e,
+	0x75, 0x2b, 0x3e, 0xef, 0xdc, 0xd7, 0x86, 0xfa, 0x44, 0x3d, 0x8b, 0xac, 0xb3, 0xfd, 0xb9, 0xb2,
+	0x50, 0xe0, 0xb7, 0xb0, 0xf3, 0x5a, 0x5d, 0x68, 0x8b, 0x9e, 0x70, 0xb6, 0x49, 0xc3, 0xc4, 0xd9,
+	0x88, 0xba, 0x86, 0x49, 0x8d, 0x15, 0x23, 0x4a, 0x66, 0xe1, 0x5d, 0x90, 0x0c, 0x7f, 0x0d, 0xbb,
+	0xc7, 0x51, 0x44, 0x27, 0xf2, 0x03, 0x03, 0xab, 0xa6, 0xc6, 0xfe, 0xcf, 0xfa, 0xb0, 0xd4, 0x9d,
+	0xb4, 0xbf, 0x7b, 0xbf, 0xef, 0xfd, 0xf3, 0xfd, 0xbe, 0xf7, 0xaf, 0xf7, 0xfb, 0xde, 0xb7, 0xff,
+	0xde, 0xff, 0x51, 0xbf, 0xac, 0xff, 0x8f, 0x3f, 0xff, 0xcf, 0x00, 0x34, 0x22, 0x00, 0xc1, 0x66,
+	0x17, 0x00, 0x00,
 }
@@ -0,0 +1,69 @@
+package escrow
+
+import (
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+// The escrow math works on sets of coins of several tickers.
+// x.Coins only adds and subtracts one coin at a time, and lets
+// the balance of a ticker go negative.
+
+// addCoins returns a new set with all coins of a and b
+func addCoins(a, b x.Coins) (x.Coins, error) {
+	res := a.Clone()
+	for _, c := range b {
+		var err error
+		res, err = res.Add(*c)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// subtractCoins returns a new set with have minus all of sub.
+// It fails if any ticker would go negative.
+func subtractCoins(have, sub x.Coins) (x.Coins, error) {
+	res := have.Clone()
+	for _, c := range sub {
+		var err error
+		res, err = res.Subtract(*c)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if hasNegative(res) {
+		return nil, cash.ErrInsufficientFunds()
+	}
+	return nonZero(res), nil
+}
+
+// containsCoins returns true if have holds at least want
+// of every ticker
+func containsCoins(have, want x.Coins) bool {
+	_, err := subtractCoins(have, want)
+	return err == nil
+}
+
+// hasNegative returns true if any coin is below zero
+func hasNegative(coins x.Coins) bool {
+	for _, c := range coins {
+		if c.Negative().IsPositive() {
+			return true
+		}
+	}
+	return false
+}
+
+// nonZero drops all zero coins, so an emptied ticker is gone
+// from the set rather than kept as zero
+func nonZero(coins x.Coins) x.Coins {
+	var res x.Coins
+	for _, c := range coins {
+		if c.IsPositive() || c.Negative().IsPositive() {
+			res = append(res, c)
+		}
+	}
+	return res
+}
@@ -0,0 +1,123 @@
+package escrow
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// coinSet is a random set of positive coins of up to
+// three tickers, for testing/quick
+type coinSet x.Coins
+
+// Generate implements quick.Generator
+func (coinSet) Generate(r *rand.Rand, size int) reflect.Value {
+	var coins x.Coins
+	for _, ticker := range []string{"BAR", "ETH", "FOO"} {
+		if r.Intn(3) == 0 {
+			continue
+		}
+		c := x.NewCoin(r.Int63n(1000), r.Int63n(1000000000), ticker)
+		if c.IsPositive() {
+			coins = append(coins, &c)
+		}
+	}
+	return reflect.ValueOf(coinSet(coins))
+}
+
+func sameCoins(a, b x.Coins) bool {
+	a, b = nonZero(a), nonZero(b)
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func TestCoinProperties(t *testing.T) {
+	props := map[string]interface{}{
+		// what is added can be taken out again
+		"add-subtract": func(a, b coinSet) bool {
+			sum, err := addCoins(x.Coins(a), x.Coins(b))
+			if err != nil {
+				return false
+			}
+			rest, err := subtractCoins(sum, x.Coins(b))
+			return err == nil && sameCoins(rest, x.Coins(a))
+		},
+		"commutative": func(a, b coinSet) bool {
+			ab, err := addCoins(x.Coins(a), x.Coins(b))
+			if err != nil {
+				return false
+			}
+			ba, err := addCoins(x.Coins(b), x.Coins(a))
+			return err == nil && sameCoins(ab, ba)
+		},
+		"contains-parts": func(a, b coinSet) bool {
+			sum, err := addCoins(x.Coins(a), x.Coins(b))
+			return err == nil && containsCoins(sum, x.Coins(a)) &&
+				containsCoins(sum, x.Coins(b))
+		},
+		// releasing an escrow in two parts empties it, with
+		// nothing negative or zero left on the way
+		"partial-release": func(a, b coinSet) bool {
+			escrow, err := addCoins(x.Coins(a), x.Coins(b))
+			if err != nil {
+				return false
+			}
+			rest, err := subtractCoins(escrow, x.Coins(a))
+			if err != nil || hasNegative(rest) || len(nonZero(rest)) != len(rest) {
+				return false
+			}
+			rest, err = subtractCoins(rest, x.Coins(b))
+			return err == nil && len(rest) == 0
+		},
+		// asking for more than there is never succeeds
+		"overdraw": func(a, b coinSet) bool {
+			if len(b) == 0 {
+				return true
+			}
+			more, err := addCoins(x.Coins(a), x.Coins(b))
+			if err != nil {
+				return false
+			}
+			_, err = subtractCoins(x.Coins(a), more)
+			return cash.IsInsufficientFundsErr(err) && !containsCoins(x.Coins(a), more)
+		},
+		// the inputs are never modified
+		"immutable": func(a, b coinSet) bool {
+			before := x.Coins(a).Clone()
+			_, _ = addCoins(x.Coins(a), x.Coins(b))
+			_, _ = subtractCoins(x.Coins(a), x.Coins(b))
+			return sameCoins(before, x.Coins(a))
+		},
+	}
+
+	for name, prop := range props {
+		t.Run(name, func(t *testing.T) {
+			err := quick.Check(prop, &quick.Config{MaxCount: 500})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCoinHelpers(t *testing.T) {
+	foo := x.NewCoin(5, 0, "FOO")
+	bar := x.NewCoin(0, 500, "BAR")
+	coins := mustCombineCoins(foo, bar)
+
+	assert.False(t, hasNegative(coins))
+	assert.True(t, hasNegative(x.Coins{&foo, &x.Coin{Whole: -1, Ticker: "BAR"}}))
+	assert.Equal(t, x.Coins{&foo}, nonZero(x.Coins{&x.Coin{Ticker: "BAR"}, &foo}))
+
+	rest, err := subtractCoins(coins, x.Coins{&bar})
+	require.NoError(t, err)
+	assert.Equal(t, x.Coins{&foo}, rest)
+	assert.True(t, containsCoins(coins, nil))
+	assert.False(t, containsCoins(x.Coins{&foo}, coins))
+}
@@ -0,0 +1,103 @@
+package escrow
+
+import (
+	"encoding/binary"
+
+	"github.com/confio/weave"
+
+	"github.com/iov-one/bcp-demo/bech32"
+)
+
+const (
+	// IDPrefix is the human readable part of an encoded escrow id
+	IDPrefix = "esc"
+
+	// conditionExt and conditionType are the fixed prefix of every
+	// escrow condition, "escrow/seq/". Changing them changes all
+	// escrow addresses, so a new encoding needs a new type.
+	conditionExt  = "escrow"
+	conditionType = "seq"
+)
+
+// Condition is the permission that controls the coins of one
+// escrow. It is never granted by any authenticator, so only
+// the escrow handlers can move the coins.
+//
+// It serializes as "escrow/seq/" followed by the 8 byte big
+// endian escrow id, and the address is the first 20 bytes of
+// the sha256 hash of that. See README.md for the spec and test
+// vectors, so clients can derive the address themselves.
+type Condition []byte
+
+// NewCondition returns the condition for the escrow with this id
+func NewCondition(id []byte) Condition {
+	return Condition(id)
+}
+
+// SeqCondition returns the condition for the n-th escrow
+func SeqCondition(seq uint64) Condition {
+	id := make([]byte, 8)
+	binary.BigEndian.PutUint64(id, seq)
+	return NewCondition(id)
+}
+
+// EncodeID returns the string form of an escrow id, eg.
+// "esc1qqqqqqqqqqqqzjwh4xz" for the first escrow. Tags, the CLI
+// and clients show ids like this, as the checksum catches typos.
+func EncodeID(id []byte) string {
+	return bech32.Encode(IDPrefix, id)
+}
+
+// ParseID reads an escrow id written by EncodeID
+func ParseID(s string) ([]byte, error) {
+	hrp, id, err := bech32.Decode(s)
+	if err != nil || hrp != IDPrefix {
+		return nil, ErrMalformedEscrowID(s)
+	}
+	if err := validateEscrowID(id); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// ParseCondition reads an escrow condition from a permission,
+// returning an error if it is none
+func ParseCondition(perm weave.Permission) (Condition, error) {
+	ext, typ, id, err := perm.Parse()
+	if err != nil {
+		return nil, err
+	}
+	if ext != conditionExt || typ != conditionType {
+		return nil, ErrInvalidPermission(perm)
+	}
+	if err := validateEscrowID(id); err != nil {
+		return nil, err
+	}
+	return NewCondition(id), nil
+}
+
+// ID returns the id of the escrow
+func (c Condition) ID() []byte {
+	return []byte(c)
+}
+
+// Permission returns the serialized condition
+func (c Condition) Permission() weave.Permission {
+	return weave.NewPermission(conditionExt, conditionType, c)
+}
+
+// Address returns the address holding the escrowed coins
+func (c Condition) Address() weave.Address {
+	return c.Permission().Address()
+}
+
+// String returns the human readable form, eg.
+// "escrow/seq/0000000000000001"
+func (c Condition) String() string {
+	return c.Permission().String()
+}
+
+// Validate returns an error if this is not a valid escrow id
+func (c Condition) Validate() error {
+	return validateEscrowID(c)
+}
@@ -0,0 +1,94 @@
+package escrow
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+
+	"github.com/iov-one/bcp-demo/bech32"
+)
+
+// TestConditionVectors checks the test vectors in README.md
+func TestConditionVectors(t *testing.T) {
+	cases := []struct {
+		seq       uint64
+		encoded   string
+		condition string
+		address   string
+	}{
+		0: {1, "esc1qqqqqqqqqqqqzjwh4xz", "657363726F772F7365712F0000000000000001", "F3C0C76DEB86274D8BB166FB91D840FFD8EC46C4"},
+		1: {2, "esc1qqqqqqqqqqqqywp5s64", "657363726F772F7365712F0000000000000002", "661DEE3E3D2B48422DAB878B3B5B0B7EA298EE93"},
+		2: {256, "esc1qqqqqqqqqqqsq87sgp5", "657363726F772F7365712F0000000000000100", "EF93DC53BCC7065E2DD6ACBCEDEB2F7702DE0E7F"},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			c := SeqCondition(tc.seq)
+			assert.Equal(t, tc.encoded, EncodeID(c.ID()))
+			assert.Equal(t, tc.condition, strings.ToUpper(hex.EncodeToString(c.Permission())))
+			assert.Equal(t, tc.address, strings.ToUpper(hex.EncodeToString(c.Address())))
+			// same as used for the escrow accounts
+			assert.Equal(t, Account.Address(c.ID()), c.Address())
+			assert.Equal(t, Permission(c.ID()), c.Permission())
+		})
+	}
+}
+
+func TestParseCondition(t *testing.T) {
+	id := []byte{0, 0, 0, 0, 0, 0, 0, 7}
+
+	cases := []struct {
+		perm    weave.Permission
+		isError bool
+	}{
+		0: {SeqCondition(7).Permission(), false},
+		1: {weave.NewPermission("escrow", "seq", id[:4]), true},
+		2: {weave.NewPermission("escrow", "hash", id), true},
+		3: {weave.NewPermission("hash", "seq", id), true},
+		4: {weave.Permission("foobar"), true},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			c, err := ParseCondition(tc.perm)
+			if tc.isError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, id, c.ID())
+			assert.NoError(t, c.Validate())
+			assert.Equal(t, "escrow/seq/0000000000000007", c.String())
+		})
+	}
+}
+
+func TestParseID(t *testing.T) {
+	id, err := ParseID("esc1qqqqqqqqqqqqzjwh4xz")
+	require.NoError(t, err)
+	assert.Equal(t, SeqCondition(1).ID(), id)
+	id, err = ParseID("ESC1QQQQQQQQQQQQZJWH4XZ")
+	require.NoError(t, err)
+	assert.Equal(t, SeqCondition(1).ID(), id)
+
+	invalid := []string{
+		"",
+		"0000000000000001",
+		// a typo
+		"esc1qqqqqqqqqqqqzjwh4xy",
+		// another prefix
+		bech32.Encode("foo", SeqCondition(1).ID()),
+		// not 8 bytes
+		EncodeID([]byte{1, 2, 3}),
+	}
+	for _, s := range invalid {
+		_, err := ParseID(s)
+		assert.True(t, IsInvalidMetadataErr(err), "%s: %+v", s, err)
+	}
+}
@@ -3,11 +3,13 @@ package escrow
 import (
 	"fmt"
 
+	"github.com/confio/weave"
 	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x"
 )
 
 // ABCI Response Codes
-// bov takes 1000-1100
+// bov takes 1000-1300
 // escrow takes 1010-1020
 const (
 	CodeNoEscrow          = 1010
@@ -15,6 +17,12 @@ const (
 	CodeInvalidPermission = 1012
 	CodeInvalidMetadata   = 1013
 	CodeInvalidHeight     = 1014
+	CodeInvalidQuery      = 1015
+	CodeLimitExceeded     = 1016
+	CodeInvalidPrice      = 1017
+	CodeInvalidParams     = 1018
+	CodeInvalidBid        = 1019
+	CodePolicyDenied      = 1020
 
 	// CodeInvalidIndex  = 1001
 	// CodeInvalidWallet = 1002
@@ -26,15 +34,49 @@ var (
 	errMissingRecipient      = fmt.Errorf("Missing Recipient")
 	errMissingAllPermissions = fmt.Errorf("Missing All Permissions")
 
-	errInvalidMemo     = fmt.Errorf("Memo field too long")
-	errInvalidTimeout  = fmt.Errorf("Invalid Timeout")
-	errInvalidEscrowID = fmt.Errorf("Invalid Escrow ID")
+	errInvalidMemo      = fmt.Errorf("Memo field too long")
+	errInvalidDestTag   = fmt.Errorf("Destination tag too long")
+	errInvalidTimeout   = fmt.Errorf("Invalid Timeout")
+	errInvalidEscrowID  = fmt.Errorf("Invalid Escrow ID")
+	errInvalidEvent     = fmt.Errorf("Invalid history event")
+	errInvalidObservers = fmt.Errorf("Invalid observers")
+	errInvalidPolicy    = fmt.Errorf("Invalid arbiter policy")
+	errInvalidReveal    = fmt.Errorf("Invalid memo reveal")
+	errInvalidTemplate  = fmt.Errorf("Invalid escrow template")
+	errInvalidHeartbeat = fmt.Errorf("Invalid heartbeat")
+	errInvalidChain     = fmt.Errorf("Invalid escrow chain")
+	errChainCycle       = fmt.Errorf("Escrow chain would be a cycle")
+	errInvalidShares    = fmt.Errorf("Invalid payout shares")
+	errInvalidReason    = fmt.Errorf("Invalid reason")
+	errQuarantined      = fmt.Errorf("Escrow is quarantined")
+	errNotQuarantined   = fmt.Errorf("Escrow is not quarantined")
+	errInvalidMilestone = fmt.Errorf("Invalid milestone")
+	errInvalidDispute   = fmt.Errorf("Invalid dispute")
+	errInvalidRole      = fmt.Errorf("Not a party of an escrow")
+	errUnsupportedParty = fmt.Errorf("Permission type cannot be a party")
+	errEscrowParty      = fmt.Errorf("Escrow cannot be its own party")
 
 	errNoSuchEscrow = fmt.Errorf("No Escrow with this ID")
 
 	errEscrowExpired    = fmt.Errorf("Escrow already expired")
 	errEscrowNotExpired = fmt.Errorf("Escrow not yet expired")
 
+	errInvalidQuery = fmt.Errorf("Invalid query")
+
+	errTooManyEscrows = fmt.Errorf("Too many open escrows for sender")
+	errLockedLimit    = fmt.Errorf("Total value locked limit exceeded")
+
+	errInvalidTarget    = fmt.Errorf("Invalid target value")
+	errPriceOutOfBounds = fmt.Errorf("Price out of bounds")
+
+	errInvalidGasRate = fmt.Errorf("Gas rate must not be negative")
+
+	errInvalidBid      = fmt.Errorf("Invalid bid")
+	errNoBids          = fmt.Errorf("No bids for escrow")
+	errArbiterAssigned = fmt.Errorf("Escrow already has an arbiter")
+
+	errPolicyDenied = fmt.Errorf("Release not approved by policy")
+
 	// errInvalidIndex      = fmt.Errorf("Cannot calculate index")
 	// errInvalidWalletName = fmt.Errorf("Invalid name for a wallet")
 	// errChangeWalletName  = fmt.Errorf("Wallet already has a name")
@@ -64,9 +106,22 @@ func IsInvalidPermissionErr(err error) bool {
 	return errors.IsUnrecognizedPermissionErr(err)
 }
 
+func ErrUnsupportedParty(perm weave.Permission) error {
+	return errors.WithLog(perm.String(), errUnsupportedParty, CodeInvalidPermission)
+}
+func ErrEscrowParty(id []byte) error {
+	return errors.WithLog(fmt.Sprintf("%X", id), errEscrowParty, CodeInvalidPermission)
+}
+func IsInvalidPartyErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidPermission)
+}
+
 func ErrInvalidMemo(memo string) error {
 	return errors.WithLog(memo, errInvalidMemo, CodeInvalidMetadata)
 }
+func ErrInvalidDestTag(tag string) error {
+	return errors.WithLog(tag, errInvalidDestTag, CodeInvalidMetadata)
+}
 func ErrInvalidTimeout(timeout int64) error {
 	msg := fmt.Sprintf("%d", timeout)
 	return errors.WithLog(msg, errInvalidTimeout, CodeInvalidMetadata)
@@ -78,6 +133,54 @@ func ErrInvalidEscrowID(id []byte) error {
 	}
 	return errors.WithLog(msg, errInvalidEscrowID, CodeInvalidMetadata)
 }
+func ErrMalformedEscrowID(s string) error {
+	return errors.WithLog(s, errInvalidEscrowID, CodeInvalidMetadata)
+}
+func ErrInvalidEvent(event string) error {
+	return errors.WithLog(event, errInvalidEvent, CodeInvalidMetadata)
+}
+func ErrInvalidObservers(reason string) error {
+	return errors.WithLog(reason, errInvalidObservers, CodeInvalidMetadata)
+}
+func ErrInvalidPolicy(reason string) error {
+	return errors.WithLog(reason, errInvalidPolicy, CodeInvalidMetadata)
+}
+func ErrInvalidReveal(reason string) error {
+	return errors.WithLog(reason, errInvalidReveal, CodeInvalidMetadata)
+}
+func ErrInvalidTemplate(reason string) error {
+	return errors.WithLog(reason, errInvalidTemplate, CodeInvalidMetadata)
+}
+func ErrInvalidHeartbeat(reason string) error {
+	return errors.WithLog(reason, errInvalidHeartbeat, CodeInvalidMetadata)
+}
+func ErrInvalidChain(reason string) error {
+	return errors.WithLog(reason, errInvalidChain, CodeInvalidMetadata)
+}
+func ErrChainCycle(id []byte) error {
+	return errors.WithLog(fmt.Sprintf("%X", id), errChainCycle, CodeInvalidMetadata)
+}
+func ErrInvalidShares(reason string) error {
+	return errors.WithLog(reason, errInvalidShares, CodeInvalidMetadata)
+}
+func ErrInvalidReason(reason string) error {
+	return errors.WithLog(reason, errInvalidReason, CodeInvalidMetadata)
+}
+func ErrQuarantined(id []byte) error {
+	return errors.WithLog(fmt.Sprintf("%X", id), errQuarantined, CodeInvalidMetadata)
+}
+func ErrNotQuarantined(id []byte) error {
+	return errors.WithLog(fmt.Sprintf("%X", id), errNotQuarantined, CodeInvalidMetadata)
+}
+func ErrInvalidDispute(reason string) error {
+	return errors.WithLog(reason, errInvalidDispute, CodeInvalidMetadata)
+}
+func ErrInvalidRole(role string) error {
+	return errors.WithLog(role, errInvalidRole, CodeInvalidMetadata)
+}
+func ErrInvalidMilestone(reason string) error {
+	return errors.WithLog(reason, errInvalidMilestone, CodeInvalidMetadata)
+}
 func IsInvalidMetadataErr(err error) bool {
 	return errors.HasErrorCode(err, CodeInvalidMetadata)
 }
@@ -98,3 +201,60 @@ func ErrEscrowNotExpired(timeout int64) error {
 	msg := fmt.Sprintf("%d", timeout)
 	return errors.WithLog(msg, errEscrowNotExpired, CodeInvalidHeight)
 }
+
+func ErrInvalidQuery(query string) error {
+	return errors.WithLog(query, errInvalidQuery, CodeInvalidQuery)
+}
+func IsInvalidQueryErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidQuery)
+}
+
+func ErrTooManyEscrows(count int) error {
+	msg := fmt.Sprintf("%d", count)
+	return errors.WithLog(msg, errTooManyEscrows, CodeLimitExceeded)
+}
+func ErrLockedLimit(ticker string) error {
+	return errors.WithLog(ticker, errLockedLimit, CodeLimitExceeded)
+}
+func IsLimitExceededErr(err error) bool {
+	return errors.HasErrorCode(err, CodeLimitExceeded)
+}
+
+func ErrInvalidTarget(reason string) error {
+	return errors.WithLog(reason, errInvalidTarget, CodeInvalidPrice)
+}
+func ErrPriceOutOfBounds(rate x.Coin) error {
+	msg := fmt.Sprintf("%d.%09d %s", rate.Whole, rate.Fractional, rate.Ticker)
+	return errors.WithLog(msg, errPriceOutOfBounds, CodeInvalidPrice)
+}
+func IsInvalidPriceErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidPrice)
+}
+
+func ErrInvalidGasRate(rate int64) error {
+	msg := fmt.Sprintf("%d", rate)
+	return errors.WithLog(msg, errInvalidGasRate, CodeInvalidParams)
+}
+func IsInvalidParamsErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidParams)
+}
+
+func ErrInvalidBid(reason string) error {
+	return errors.WithLog(reason, errInvalidBid, CodeInvalidBid)
+}
+func ErrNoBids(id []byte) error {
+	return errors.WithLog(fmt.Sprintf("%X", id), errNoBids, CodeInvalidBid)
+}
+func ErrArbiterAssigned() error {
+	return errors.WithCode(errArbiterAssigned, CodeInvalidBid)
+}
+func IsInvalidBidErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidBid)
+}
+
+func ErrPolicyDenied(reason string) error {
+	return errors.WithLog(reason, errPolicyDenied, CodePolicyDenied)
+}
+func IsPolicyDeniedErr(err error) bool {
+	return errors.HasErrorCode(err, CodePolicyDenied)
+}
@@ -0,0 +1,127 @@
+/*
+Package escrowtest helps testing code that uses escrows: a Fixture
+is a store with funded accounts and the escrow handlers, with
+assertions on the balances and events of the txs, and Golden
+compares the results of txs with files in testdata.
+
+The keys of the accounts are fixed, so the results are the same on
+every run and can be checked in. Run the tests with -update to
+rewrite the golden files after an intended change.
+*/
+package escrowtest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// Alice, Bob and Carol are the funded accounts of a Fixture, the
+// sender, recipient and arbiter of the StandardEscrow
+var (
+	Alice = weave.NewPermission("sigs", "ed25519", []byte("alice"))
+	Bob   = weave.NewPermission("sigs", "ed25519", []byte("bob"))
+	Carol = weave.NewPermission("sigs", "ed25519", []byte("carol"))
+)
+
+// Funds are the coins every account starts with
+var Funds = x.NewCoin(100, 0, "FOO")
+
+// Fixture is a store with the escrow handlers, moving
+// coins with Control
+type Fixture struct {
+	DB      weave.KVStore
+	Router  app.Router
+	Control namecoin.Controller
+	auth    x.CtxAuther
+}
+
+// NewFixture funds Alice, Bob and Carol in a new store
+func NewFixture(t testing.TB) *Fixture {
+	f := &Fixture{
+		DB:      store.MemStore(),
+		Router:  app.NewRouter(),
+		Control: namecoin.NewController(),
+		auth:    x.TestHelpers{}.CtxAuth("escrowtest"),
+	}
+	escrow.RegisterRoutes(f.Router, f.auth, f.Control, escrow.NewBucket())
+	for _, perm := range []weave.Permission{Alice, Bob, Carol} {
+		require.NoError(t, f.Control.IssueCoins(f.DB, perm.Address(), Funds))
+	}
+	return f
+}
+
+// StandardEscrow returns a message creating an escrow of 10 FOO
+// from Alice to Bob, arbitrated by Carol, until height 1000
+func StandardEscrow() *escrow.CreateEscrowMsg {
+	amount := x.NewCoin(10, 0, "FOO")
+	return escrow.NewCreateMsg(Alice, Bob, Carol, x.Coins{&amount}, 1000, "")
+}
+
+// Deliver checks and delivers the msg at the height, signed
+// by the signers
+func (f *Fixture) Deliver(height int64, msg weave.Msg,
+	signers ...weave.Permission) (weave.DeliverResult, error) {
+
+	ctx := weave.WithHeight(context.Background(), height)
+	ctx = f.auth.SetPermissions(ctx, signers...)
+	tx := x.TestHelpers{}.MockTx(msg)
+	_, err := f.Router.Check(ctx, f.DB, tx)
+	if err != nil {
+		return weave.DeliverResult{}, err
+	}
+	return f.Router.Deliver(ctx, f.DB, tx)
+}
+
+// Create delivers the StandardEscrow at height 10 and
+// returns its id
+func (f *Fixture) Create(t testing.TB) []byte {
+	res, err := f.Deliver(10, StandardEscrow(), Alice)
+	require.NoError(t, err)
+	return res.Data
+}
+
+// Balances are the coins of accounts by address
+type Balances map[string]x.Coins
+
+// Balances returns the coins of the accounts now
+func (f *Fixture) Balances(t testing.TB, addrs ...weave.Address) Balances {
+	res := make(Balances, len(addrs))
+	for _, addr := range addrs {
+		coins, err := f.Control.Balance(f.DB, addr)
+		require.NoError(t, err)
+		res[addr.String()] = coins
+	}
+	return res
+}
+
+// AssertDelta fails the test unless the coins of the account
+// changed by exactly delta since before, negative if it paid
+func (f *Fixture) AssertDelta(t testing.TB, before Balances, addr weave.Address,
+	delta ...x.Coin) {
+
+	want := before[addr.String()].Clone()
+	for _, c := range delta {
+		var err error
+		want, err = want.Add(c)
+		require.NoError(t, err)
+	}
+	got := f.Balances(t, addr)[addr.String()]
+	assert.True(t, want.Equals(got), "balance of %s is %v, want %v", addr, got, want)
+}
+
+// AssertEvent fails the test unless res has the tag of the
+// event of the escrow id
+func AssertEvent(t testing.TB, res weave.DeliverResult, event string, id []byte) {
+	assert.Contains(t, res.Tags, escrow.EventTag(event, id))
+}
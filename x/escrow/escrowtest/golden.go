@@ -0,0 +1,44 @@
+package escrowtest
+
+import (
+	"encoding/hex"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// Golden fails the test unless the bytes of the abci result of
+// every DeliverResult are the ones in testdata/<name>.golden, one
+// hex encoded result per line. Clients decode these bytes, so any
+// change of them may break a client. With -update the file is
+// written instead.
+func Golden(t testing.TB, name string, results ...weave.DeliverResult) {
+	var lines []byte
+	for _, res := range results {
+		abci := res.ToABCI()
+		bz, err := proto.Marshal(&abci)
+		require.NoError(t, err)
+		lines = append(lines, hex.EncodeToString(bz)...)
+		lines = append(lines, '\n')
+	}
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, lines, 0644))
+	}
+	golden, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), string(lines),
+		"results changed, run the test with -update if that is intended")
+}
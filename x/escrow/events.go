@@ -0,0 +1,75 @@
+package escrow
+
+import (
+	"github.com/tendermint/tmlibs/common"
+)
+
+// Events are recorded in the HistoryBucket. Returns, refunds,
+// clawbacks, disputes, chained releases and the actions of admins
+// are also added as tags to the DeliverResult, with
+// Key="escrow.<event>", Value=EncodeID(<escrow id>), so clients
+// can subscribe to them.
+// A chained release adds a "release" tag and a "create" or "fund"
+// tag for the escrow it pays into.
+const (
+	eventPrefix = "escrow."
+
+	// EventCreate is recorded when an escrow is created
+	EventCreate = "create"
+	// EventRelease is recorded for every (partial) release
+	EventRelease = "release"
+	// EventUpdate is recorded when the parties change, with
+	// the role as note if an offer was accepted
+	EventUpdate = "update"
+	// EventOffer is recorded when a party offers its role,
+	// named in the note, to someone else
+	EventOffer = "offer"
+	// EventAssign is recorded when an arbiter is assigned
+	// from the bids, with the fee of the bid
+	EventAssign = "assign"
+	// EventObservers is recorded when the observers change
+	EventObservers = "observers"
+	// EventReveal is recorded when a hashed memo is revealed
+	EventReveal = "reveal"
+	// EventPing is recorded when the sender of a dead man's
+	// switch pings it
+	EventPing = "ping"
+	// EventFund is recorded when a chained release tops up
+	// an escrow
+	EventFund = "fund"
+	// EventQuarantine is recorded when an admin freezes an
+	// escrow, with the reason as note
+	EventQuarantine = "quarantine"
+	// EventRestore is recorded when an admin lifts the
+	// quarantine of an escrow
+	EventRestore = "restore"
+	// EventSettle is recorded when an admin closes a
+	// quarantined escrow, with what the recipient is paid
+	EventSettle = "settle"
+	// EventClawback is recorded when the compliance authority
+	// returns an escrow, with the reason as note
+	EventClawback = "clawback"
+	// EventMilestone is recorded when a milestone is attested,
+	// with its tranche and its name as note
+	EventMilestone = "milestone"
+	// EventDispute is recorded when the sender or the recipient
+	// disputes an escrow, with the reason as note
+	EventDispute = "dispute"
+	// EventEscalate is recorded when a dispute goes to the
+	// backup arbiter, as the arbiter missed its sla
+	EventEscalate = "escalate"
+
+	// EventReturn is emitted when an expired escrow is returned
+	EventReturn = "return"
+	// EventRefund is emitted when the recipient returns an
+	// escrow before the timeout
+	EventRefund = "refund"
+)
+
+// EventTag is the tag of the event of the escrow id
+func EventTag(event string, id []byte) common.KVPair {
+	return common.KVPair{
+		Key:   []byte(eventPrefix + event),
+		Value: []byte(EncodeID(id)),
+	}
+}
@@ -0,0 +1,167 @@
+package escrow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/anymsg"
+	"github.com/iov-one/bcp-demo/x/modaccount"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/rbac"
+	"github.com/iov-one/bcp-demo/x/scheduler"
+)
+
+// the app registers all messages, the scheduler needs the return
+func init() {
+	anymsg.Register(&ReturnEscrowMsg{})
+}
+
+// TestExpiry returns the escrows nobody settled in the first
+// block after their timeout
+func TestExpiry(t *testing.T) {
+	var helpers x.TestHelpers
+	_, sender := helpers.MakeKey()
+	_, arbiter := helpers.MakeKey()
+	_, rcpt := helpers.MakeKey()
+	_, admin := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), control, NewBucket())
+	jobs := scheduler.NewTicker(r)
+
+	db := store.MemStore()
+	wallet, err := cash.WalletWith(sender.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, wallet))
+	require.NoError(t, rbac.NewBucket().Assign(db, admin.Address(), rbac.RoleAdmin))
+	deliver := func(height int64, msg weave.Msg, perm weave.Permission) []byte {
+		ctx := weave.WithHeight(context.Background(), height)
+		ctx = authenticator().SetPermissions(ctx, perm)
+		res, err := r.Deliver(ctx, db, helpers.MockTx(msg))
+		require.NoError(t, err)
+		return res.Data
+	}
+	tick := func(height int64) {
+		_, err := jobs.Tick(weave.WithHeight(context.Background(), height), db)
+		require.NoError(t, err)
+	}
+	load := func(id []byte) *Escrow {
+		obj, err := NewBucket().Get(db, id)
+		require.NoError(t, err)
+		return AsEscrow(obj)
+	}
+	balance := func(addr weave.Address) x.Coins {
+		coins, err := control.Balance(db, addr)
+		require.NoError(t, err)
+		return coins
+	}
+	create := func(timeout int64) []byte {
+		return deliver(10, NewCreateMsg(sender, rcpt, arbiter,
+			mustCombineCoins(x.NewCoin(20, 0, "FOO")), timeout, "goods"), sender)
+	}
+
+	expiring := create(100)
+	released := create(100)
+	frozen := create(100)
+	pending, err := scheduler.NewBucket().Due(db, 101, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+	assert.Equal(t, "escrow", scheduler.AsJob(pending[0]).Module)
+
+	deliver(50, &ReleaseEscrowMsg{EscrowId: released}, arbiter)
+	deliver(50, &QuarantineEscrowMsg{EscrowId: frozen, Reason: "stolen key"}, admin)
+
+	// nothing before the timeout
+	tick(100)
+	assert.NotNil(t, load(expiring))
+	tick(101)
+	assert.Nil(t, load(expiring))
+	assert.Equal(t, mustCombineCoins(x.NewCoin(60, 0, "FOO")), balance(sender.Address()))
+	history, err := NewHistoryBucket().History(db, expiring)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, EventReturn, history[1].Event)
+	assert.Nil(t, history[1].Actor)
+
+	// the return of a quarantined escrow is dropped, it expires
+	// again once restored
+	assert.NotNil(t, load(frozen))
+	pending, err = scheduler.NewBucket().Due(db, 1000, 10)
+	require.NoError(t, err)
+	assert.Len(t, pending, 0)
+	deliver(150, &RestoreEscrowMsg{EscrowId: frozen}, admin)
+	assert.Equal(t, int64(200), load(frozen).Timeout)
+	tick(200)
+	assert.NotNil(t, load(frozen))
+	tick(201)
+	assert.Nil(t, load(frozen))
+	assert.Equal(t, mustCombineCoins(x.NewCoin(80, 0, "FOO")), balance(sender.Address()))
+	assert.Equal(t, mustCombineCoins(x.NewCoin(20, 0, "FOO")), balance(rcpt.Address()))
+}
+
+// TestExpiryDeposit forfeits the deposit when the job returns
+// the escrow, but not when a party does after the timeout
+func TestExpiryDeposit(t *testing.T) {
+	var helpers x.TestHelpers
+	_, sender := helpers.MakeKey()
+	_, rcpt := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	auth := x.ChainAuth(authenticator(), scheduler.Authenticate{})
+	RegisterRoutes(r, auth, control, NewBucket())
+	jobs := scheduler.NewTicker(r)
+
+	db := store.MemStore()
+	params := &Params{DepositPerBlock: &x.Coin{Fractional: 1000000, Ticker: "FOO"}}
+	require.NoError(t, NewParamsBucket().Store(db, params))
+	wallet, err := cash.WalletWith(sender.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, wallet))
+	deliver := func(height int64, msg weave.Msg, perm weave.Permission) []byte {
+		ctx := weave.WithHeight(context.Background(), height)
+		ctx = authenticator().SetPermissions(ctx, perm)
+		res, err := r.Deliver(ctx, db, helpers.MockTx(msg))
+		require.NoError(t, err)
+		return res.Data
+	}
+	balance := func(addr weave.Address) x.Coins {
+		coins, err := control.Balance(db, addr)
+		require.NoError(t, err)
+		return coins
+	}
+	fees := modaccount.Address(modaccount.FeeCollector)
+
+	// 100 blocks at 0.001 FOO
+	create := func() []byte {
+		return deliver(0, NewCreateMsg(sender, rcpt, rcpt,
+			mustCombineCoins(x.NewCoin(20, 0, "FOO")), 100, "goods"), sender)
+	}
+	returned := create()
+	expiring := create()
+	assert.Equal(t, mustCombineCoins(x.NewCoin(59, 800000000, "FOO")), balance(sender.Address()))
+
+	// the sender was faster than the job
+	deliver(101, &ReturnEscrowMsg{EscrowId: returned}, sender)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(79, 900000000, "FOO")), balance(sender.Address()))
+
+	_, err = jobs.Tick(weave.WithHeight(context.Background(), 101), db)
+	require.NoError(t, err)
+	obj, err := NewBucket().Get(db, expiring)
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(99, 900000000, "FOO")), balance(sender.Address()))
+	assert.Equal(t, mustCombineCoins(x.NewCoin(0, 100000000, "FOO")), balance(fees))
+}
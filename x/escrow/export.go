@@ -0,0 +1,132 @@
+package escrow
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+const (
+	// QueryExport is the path of the ExportQuery
+	QueryExport = "/escrows/export"
+)
+
+var _ orm.CloneableData = (*EscrowExport)(nil)
+
+// Validate ensures the escrow is valid and its account holds
+// exactly the amount, deposit and bounty, as the escrow is
+// funded with those on import.
+func (e *EscrowExport) Validate() error {
+	if len(e.Id) != 8 {
+		return ErrInvalidEscrowID(e.Id)
+	}
+	if e.Escrow == nil {
+		return ErrNoSuchEscrow(e.Id)
+	}
+	if err := e.Escrow.Validate(); err != nil {
+		return err
+	}
+	want, err := heldCoins(e.Escrow)
+	if err != nil {
+		return err
+	}
+	if !x.Coins(e.Balance).Equals(want) {
+		return cash.ErrInvalidAmount("balance does not match escrow")
+	}
+	return nil
+}
+
+// Copy makes a new export with the same values
+func (e *EscrowExport) Copy() orm.CloneableData {
+	var esc *Escrow
+	if e.Escrow != nil {
+		esc = e.Escrow.Copy().(*Escrow)
+	}
+	return &EscrowExport{
+		Id:      append([]byte(nil), e.Id...),
+		Escrow:  esc,
+		Balance: x.Coins(e.Balance).Clone(),
+	}
+}
+
+// heldCoins returns all coins the account of an escrow should
+// hold: the amount, along with the deposit and bounty if set
+func heldCoins(esc *Escrow) (x.Coins, error) {
+	held, err := addCoins(nil, esc.Amount)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range []*x.Coin{esc.Deposit, esc.Bounty} {
+		if c != nil {
+			held, err = held.Add(*c)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return held, nil
+}
+
+// Export copies the escrow with the given id along with the
+// balance of its account. The balance is recorded as is, check
+// the result with Validate before importing it elsewhere.
+func Export(db weave.ReadOnlyKVStore, bucket Bucket, ctrl namecoin.Controller,
+	id []byte) (*EscrowExport, error) {
+
+	obj, err := bucket.Get(db, id)
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil || obj.Value() == nil {
+		return nil, ErrNoSuchEscrow(id)
+	}
+	balance, err := ctrl.Balance(db, Account.Address(id))
+	if err != nil {
+		return nil, err
+	}
+	return &EscrowExport{
+		Id:      obj.Key(),
+		Escrow:  AsEscrow(obj),
+		Balance: balance,
+	}, nil
+}
+
+// ExportQuery returns the EscrowExport of one escrow, with the
+// escrow id as data and no modifier. The result is empty if
+// there is no such escrow.
+type ExportQuery struct {
+	bucket Bucket
+	cash   namecoin.Controller
+}
+
+var _ weave.QueryHandler = ExportQuery{}
+
+// NewExportQuery creates a query handler reading the escrows
+// from bucket and their balance through ctrl
+func NewExportQuery(bucket Bucket, ctrl namecoin.Controller) ExportQuery {
+	return ExportQuery{bucket: bucket, cash: ctrl}
+}
+
+// Query implements weave.QueryHandler
+func (q ExportQuery) Query(db weave.ReadOnlyKVStore, mod string,
+	data []byte) ([]weave.Model, error) {
+
+	if mod != weave.KeyQueryMod || len(data) == 0 {
+		return nil, ErrInvalidQuery(mod)
+	}
+	export, err := Export(db, q.bucket, q.cash, data)
+	if IsNoSuchEscrowErr(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	bz, err := export.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return []weave.Model{{Key: q.bucket.DBKey(data), Value: bz}}, nil
+}
@@ -0,0 +1,66 @@
+package escrow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestExport(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	amount := mustCombineCoins(x.NewCoin(10, 0, "FOO"))
+	deposit := x.NewCoin(1, 0, "IOV")
+
+	db := store.MemStore()
+	ctrl := namecoin.NewController()
+	obj, err := NewBucket().Create(db, &Escrow{Sender: a, Recipient: b, Arbiter: a,
+		Amount: amount, Timeout: 100, Deposit: &deposit})
+	require.NoError(t, err)
+	dest := Account.Address(obj.Key())
+	require.NoError(t, ctrl.IssueCoins(db, dest, x.NewCoin(10, 0, "FOO")))
+
+	// only the amount arrived
+	export, err := Export(db, NewBucket(), ctrl, obj.Key())
+	require.NoError(t, err)
+	assert.Equal(t, obj.Key(), export.Id)
+	assert.EqualValues(t, amount, export.Balance)
+	err = export.Validate()
+	assert.True(t, cash.IsInvalidAmountErr(err), "%+v", err)
+
+	require.NoError(t, ctrl.IssueCoins(db, dest, deposit))
+	export, err = Export(db, NewBucket(), ctrl, obj.Key())
+	require.NoError(t, err)
+	assert.NoError(t, export.Validate())
+	assert.Equal(t, export, export.Copy())
+
+	_, err = Export(db, NewBucket(), ctrl, SeqCondition(17))
+	assert.True(t, IsNoSuchEscrowErr(err), "%+v", err)
+
+	// the same through the query
+	qr := weave.NewQueryRouter()
+	RegisterQuery(qr)
+	h := qr.Handler(QueryExport)
+	require.NotNil(t, h)
+	models, err := h.Query(db, weave.KeyQueryMod, obj.Key())
+	require.NoError(t, err)
+	require.Len(t, models, 1)
+	var res EscrowExport
+	require.NoError(t, res.Unmarshal(models[0].Value))
+	assert.Equal(t, export, &res)
+
+	models, err = h.Query(db, weave.KeyQueryMod, SeqCondition(17))
+	require.NoError(t, err)
+	assert.Empty(t, models)
+	_, err = h.Query(db, weave.PrefixQueryMod, obj.Key())
+	assert.True(t, IsInvalidQueryErr(err), "%+v", err)
+}
@@ -0,0 +1,119 @@
+package escrow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// The fuzz targets decode arbitrary bytes into a message and
+// run it through Check and Deliver, against a store with one
+// open escrow. Errors are fine, panics are not. Run them with
+//
+//	go test ./x/escrow -run XXX -fuzz FuzzReleaseEscrowMsg
+
+// fuzzMsg is a message the fuzzer can fill
+type fuzzMsg interface {
+	weave.Msg
+	Reset()
+	Validate() error
+}
+
+// fuzzSeeds are the encoded messages, the fuzzer starts from
+func fuzzSeeds(f *testing.F, msgs ...weave.Msg) {
+	for _, msg := range msgs {
+		bz, err := msg.Marshal()
+		require.NoError(f, err)
+		f.Add(bz)
+	}
+}
+
+// runFuzz delivers msg decoded from bz, signed by the sender
+// of the escrow, and fails on panic
+func runFuzz(t *testing.T, msg fuzzMsg, bz []byte) {
+	msg.Reset()
+	if err := msg.Unmarshal(bz); err != nil {
+		return
+	}
+	_ = msg.Validate()
+
+	var helpers x.TestHelpers
+	db, sender := fuzzStore(t)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), namecoin.NewController(), NewBucket())
+	ctx := weave.WithHeight(context.Background(), 500)
+	ctx = authenticator().SetPermissions(ctx, sender)
+
+	tx := helpers.MockTx(msg)
+	if _, err := r.Check(ctx, db, tx); err != nil {
+		return
+	}
+	_, _ = r.Deliver(ctx, db, tx)
+}
+
+// fuzzID is the id of the escrow in the fuzz store
+var fuzzID = []byte{0, 0, 0, 0, 0, 0, 0, 1}
+
+// fuzzStore funds the sender and opens an escrow
+func fuzzStore(t *testing.T) (weave.KVStore, weave.Permission) {
+	sender := weave.NewPermission("sigs", "ed25519", []byte("sender"))
+	recipient := weave.NewPermission("sigs", "ed25519", []byte("recipient"))
+
+	db := store.MemStore()
+	ctrl := namecoin.NewController()
+	require.NoError(t, ctrl.IssueCoins(db, sender.Address(), x.NewCoin(100, 0, "FOO")))
+	require.NoError(t, ctrl.IssueCoins(db, sender.Address(), x.NewCoin(100, 0, "BAR")))
+
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), ctrl, NewBucket())
+	ctx := authenticator().SetPermissions(weave.WithHeight(context.Background(), 400), sender)
+	msg := NewCreateMsg(sender, recipient, sender,
+		mustCombineCoins(x.NewCoin(10, 0, "FOO"), x.NewCoin(5, 0, "BAR")), 1000, "")
+	var helpers x.TestHelpers
+	res, err := r.Deliver(ctx, db, helpers.MockTx(msg))
+	require.NoError(t, err)
+	require.Equal(t, fuzzID, []byte(res.Data))
+	return db, sender
+}
+
+func FuzzCreateEscrowMsg(f *testing.F) {
+	sender := weave.NewPermission("sigs", "ed25519", []byte("sender"))
+	arbiter := weave.NewPermission("sigs", "ed25519", []byte("arbiter"))
+	target := x.NewCoin(100, 0, "USD")
+	fuzzSeeds(f,
+		NewCreateMsg(sender, arbiter, arbiter, mustCombineCoins(x.NewCoin(5, 0, "FOO")), 1000, "memo"),
+		&CreateEscrowMsg{Recipient: arbiter, Amount: mustCombineCoins(x.NewCoin(5, 0, "FOO")),
+			Timeout: 1000, Target: &target, Bounty: &target},
+	)
+	f.Fuzz(func(t *testing.T, bz []byte) {
+		runFuzz(t, new(CreateEscrowMsg), bz)
+	})
+}
+
+func FuzzCreateEscrowMsgV2(f *testing.F) {
+	arbiter := weave.NewPermission("sigs", "ed25519", []byte("arbiter"))
+	fuzzSeeds(f, &CreateEscrowMsgV2{Arbiter: arbiter, Recipient: arbiter,
+		Amount: mustCombineCoins(x.NewCoin(5, 0, "FOO")), TimeoutIn: 20,
+		Options: &EscrowOptions{SenderCanRelease: true}})
+	f.Fuzz(func(t *testing.T, bz []byte) {
+		runFuzz(t, new(CreateEscrowMsgV2), bz)
+	})
+}
+
+func FuzzReleaseEscrowMsg(f *testing.F) {
+	fuzzSeeds(f,
+		&ReleaseEscrowMsg{EscrowId: fuzzID},
+		&ReleaseEscrowMsg{EscrowId: fuzzID, Amount: mustCombineCoins(x.NewCoin(3, 0, "BAR"))},
+		&ReleaseEscrowMsg{EscrowId: fuzzID, Amount: mustCombineCoins(x.NewCoin(20, 0, "FOO"))},
+	)
+	f.Fuzz(func(t *testing.T, bz []byte) {
+		runFuzz(t, new(ReleaseEscrowMsg), bz)
+	})
+}
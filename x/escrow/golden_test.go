@@ -0,0 +1,44 @@
+package escrow_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/escrow/escrowtest"
+)
+
+// TestGoldenResults locks in the results clients decode: a
+// partial release and a refund, then a return after the timeout
+func TestGoldenResults(t *testing.T) {
+	f := escrowtest.NewFixture(t)
+	alice, bob := escrowtest.Alice.Address(), escrowtest.Bob.Address()
+
+	create, err := f.Deliver(10, escrowtest.StandardEscrow(), escrowtest.Alice)
+	require.NoError(t, err)
+	id := create.Data
+	before := f.Balances(t, alice, bob)
+	release, err := f.Deliver(20, &escrow.ReleaseEscrowMsg{EscrowId: id,
+		Amount: x.Coins{&x.Coin{Whole: 4, Ticker: "FOO"}}}, escrowtest.Carol)
+	require.NoError(t, err)
+	f.AssertDelta(t, before, alice)
+	f.AssertDelta(t, before, bob, x.NewCoin(4, 0, "FOO"))
+	refund, err := f.Deliver(30, &escrow.ReturnEscrowMsg{EscrowId: id}, escrowtest.Bob)
+	require.NoError(t, err)
+	escrowtest.AssertEvent(t, refund, escrow.EventRefund, id)
+	f.AssertDelta(t, before, alice, x.NewCoin(6, 0, "FOO"))
+
+	second, err := f.Deliver(40, escrowtest.StandardEscrow(), escrowtest.Alice)
+	require.NoError(t, err)
+	before = f.Balances(t, alice)
+	expired, err := f.Deliver(1001, &escrow.ReturnEscrowMsg{EscrowId: second.Data},
+		escrowtest.Carol)
+	require.NoError(t, err)
+	escrowtest.AssertEvent(t, expired, escrow.EventReturn, second.Data)
+	f.AssertDelta(t, before, alice, x.NewCoin(10, 0, "FOO"))
+
+	escrowtest.Golden(t, "results", create, release, refund, second, expired)
+}
@@ -1,11 +1,22 @@
 package escrow
 
 import (
+	"bytes"
+
 	"github.com/confio/weave"
 	"github.com/confio/weave/errors"
 	"github.com/confio/weave/orm"
 	"github.com/confio/weave/x"
 	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/tracing"
+	"github.com/iov-one/bcp-demo/x/ledger"
+	"github.com/iov-one/bcp-demo/x/modaccount"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/oracle"
+	"github.com/iov-one/bcp-demo/x/ownership"
+	"github.com/iov-one/bcp-demo/x/rbac"
+	"github.com/iov-one/bcp-demo/x/scheduler"
 )
 
 const (
@@ -14,32 +25,93 @@ const (
 	returnEscrowCost  int64 = 0
 	releaseEscrowCost int64 = 0
 	updateEscrowCost  int64 = 50
+
+	// gas of the return the scheduler runs on expiry
+	expireEscrowGas int64 = 50
 )
 
 // RegisterRoutes will instantiate and register
-// all handlers in this package
+// all handlers in this package. They move coins with the
+// control and keep the escrows in the bucket, usually
+// NewBucket().
 func RegisterRoutes(r weave.Registry, auth x.Authenticator,
-	control cash.Controller) {
-
-	bucket := NewBucket()
-	r.Handle(pathCreateEscrowMsg, CreateEscrowHandler{auth, bucket, control})
-	r.Handle(pathReleaseEscrowMsg, ReleaseEscrowHandler{auth, bucket, control})
-	r.Handle(pathReturnEscrowMsg, ReturnEscrowHandler{auth, bucket, control})
-	r.Handle(pathUpdateEscrowPartiesMsg, UpdateEscrowHandler{auth, bucket})
+	control namecoin.Controller, bucket Store) {
+
+	params := NewParamsBucket()
+	locked := NewLockedBucket()
+	history := NewHistoryBucket()
+	bids := NewBidBucket()
+	policies := NewPolicyBucket()
+	templates := NewTemplateBucket()
+	heartbeats := NewHeartbeatBucket()
+	create := CreateEscrowHandler{auth, bucket, params, locked,
+		history, templates, heartbeats, scheduler.NewBucket(), modaccount.NewBucket(), control}
+	r.Handle(pathCreateEscrowMsg, create)
+	r.Handle(pathSendEscrowMsg, SendEscrowHandler{auth, create})
+	r.Handle(pathReleaseEscrowMsg, ReleaseEscrowHandler{auth, bucket, params,
+		history, bids, policies, oracle.NewPriceBucket(), control, create})
+	r.Handle(pathReturnEscrowMsg, ReturnEscrowHandler{auth, bucket, history,
+		bids, control})
+	offers := ownership.NewBucket()
+	accounts := modaccount.NewBucket()
+	r.Handle(pathUpdateEscrowPartiesMsg, UpdateEscrowHandler{auth, bucket, params,
+		offers, accounts, history})
+	r.Handle(pathOfferEscrowPartyMsg, OfferEscrowPartyHandler{auth, bucket, params,
+		offers, accounts, history})
+	r.Handle(pathAcceptEscrowPartyMsg, AcceptEscrowPartyHandler{auth, bucket, offers, history})
+	r.Handle(pathUpdateObserversMsg, UpdateObserversHandler{auth, bucket, history})
+	r.Handle(pathRevealMemoMsg, RevealMemoHandler{auth, bucket, history})
+	r.Handle(pathPingEscrowMsg, PingEscrowHandler{auth, bucket, heartbeats, history})
+	r.Handle(pathDisputeEscrowMsg, DisputeEscrowHandler{auth, bucket,
+		NewEscalationBucket(), history})
+	r.Handle(pathBidArbitrationMsg, BidArbitrationHandler{auth, bucket, bids, rbac.NewBucket()})
+	r.Handle(pathAssignArbiterMsg, AssignArbiterHandler{auth, bucket, bids, history, control})
+	r.Handle(pathNetEscrowsMsg, NetEscrowsHandler{auth, bucket, history, bids, control})
+	r.Handle(pathClawbackEscrowMsg, ClawbackEscrowHandler{auth, bucket, history,
+		bids, control})
+	r.Handle(pathAttestMilestoneMsg, AttestMilestoneHandler{auth, bucket, history,
+		bids, control})
+	r.Handle(pathSetArbiterPolicyMsg, SetArbiterPolicyHandler{auth, policies})
+	admins := rbac.NewAuthenticator(auth)
+	r.Handle(pathSetTemplateMsg, SetTemplateHandler{admins, templates})
+	r.Handle(pathQuarantineEscrowMsg, QuarantineEscrowHandler{admins, bucket, history})
+	r.Handle(pathRestoreEscrowMsg, RestoreEscrowHandler{admins, bucket, heartbeats,
+		NewEscalationBucket(), scheduler.NewBucket(), history})
+	r.Handle(pathForceSettleEscrowMsg, ForceSettleEscrowHandler{admins, bucket, history,
+		bids, control})
 }
 
-// RegisterQuery will register this bucket as "/wallets"
+// RegisterQuery will register this bucket as "/escrows",
+// along with "/escrows/expiring", "/escrows/history",
+// "/escrows/bids", "/escrows/export", "/escrows/alias",
+// "/escrows/policies" and "/escrows/templates"
 func RegisterQuery(qr weave.QueryRouter) {
-	NewBucket().Register("escrows", qr)
+	bucket := NewBucket()
+	bucket.Register("escrows", qr)
+	qr.Register(QueryExpiring, NewExpiringQuery(bucket))
+	qr.Register(QueryID, IDQuery{bucket})
+	qr.Register(QueryHistory, NewHistoryQuery(NewHistoryBucket()))
+	qr.Register(QueryBids, BidsQuery{NewBidBucket()})
+	qr.Register(QueryExport, NewExportQuery(bucket, namecoin.NewController()))
+	qr.Register(QueryAlias, AliasQuery{NewAliasBucket(), bucket})
+	NewPolicyBucket().Register("escrows/policies", qr)
+	NewTemplateBucket().Register("escrows/templates", qr)
 }
 
 //---- create
 
 // CreateEscrowHandler will set a name for objects in this bucket
 type CreateEscrowHandler struct {
-	auth   x.Authenticator
-	bucket Bucket
-	cash   cash.Controller
+	auth       x.Authenticator
+	bucket     Store
+	params     ParamsBucket
+	locked     LockedBucket
+	history    HistoryBucket
+	templates  TemplateBucket
+	heartbeats HeartbeatBucket
+	jobs       scheduler.Bucket
+	accounts   modaccount.Bucket
+	cash       namecoin.Controller
 }
 
 var _ weave.Handler = CreateEscrowHandler{}
@@ -49,13 +121,17 @@ var _ weave.Handler = CreateEscrowHandler{}
 func (h CreateEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
 	tx weave.Tx) (weave.CheckResult, error) {
 	var res weave.CheckResult
-	_, err := h.validate(ctx, db, tx)
+	msg, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	params, err := h.params.Load(db)
 	if err != nil {
 		return res, err
 	}
 
-	// return cost
-	res.GasAllocated += createEscrowCost
+	// return cost, large escrows pay for the state they use
+	res.GasAllocated += createEscrowCost + params.StorageGas(msg)
 	return res, nil
 }
 
@@ -68,87 +144,233 @@ func (h CreateEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
 	if err != nil {
 		return res, err
 	}
+	obj, transfers, err := h.create(ctx, db, msg)
+	if err != nil {
+		return res, err
+	}
+
+	// return id of escrow to use in future calls
+	res.Data = obj.Key()
+	res.Tags = append(res.Tags, ledger.TransferTags(ledger.KindOut, transfers)...)
+	return res, nil
+}
+
+// create stores the escrow of a checked message and moves the
+// coins from the sender to its account, it returns the escrow
+// and the transfers that funded it
+func (h CreateEscrowHandler) create(ctx weave.Context, db weave.KVStore,
+	msg *CreateEscrowMsg) (orm.Object, []namecoin.Transfer, error) {
 
 	// apply a default for sender
-	sender := weave.Permission(msg.Sender)
-	if sender == nil {
-		sender = x.MainSigner(ctx, h.auth)
+	sender := h.sender(ctx, msg)
+	deposit, err := h.deposit(ctx, db, msg)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// create an escrow object
-	escrow := &Escrow{
-		Sender:    sender,
-		Arbiter:   msg.Arbiter,
-		Recipient: msg.Recipient,
-		Amount:    msg.Amount,
-		Timeout:   msg.Timeout,
-		Memo:      msg.Memo,
-	}
+	escrow := msg.escrow(sender)
+	escrow.Deposit = deposit
 	obj, err := h.bucket.Create(db, escrow)
 	if err != nil {
-		return res, err
+		return nil, nil, err
 	}
-
-	// move the money to this object
-	dest := Permission(obj.Key()).Address()
-	sendAddr := sender.Address()
-	for _, c := range escrow.Amount {
-		err := h.cash.MoveCoins(db, sendAddr, dest, *c)
+	if escrow.HeartbeatWindow > 0 {
+		height, _ := weave.GetHeight(ctx)
+		err = h.heartbeats.Beat(db, obj.Key(), height, escrow.HeartbeatWindow)
 		if err != nil {
-			// this will rollback the half-finished tx
-			return res, err
+			return nil, nil, err
 		}
 	}
+	err = scheduleExpiry(db, h.jobs, obj)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// return id of escrow to use in future calls
-	res.Data = obj.Key()
-	return res, err
+	// move the money to the account of this object
+	dest, err := h.accounts.Open(db, Account, obj.Key())
+	if err != nil {
+		return nil, nil, err
+	}
+	// the deposit and bounty are held next to the amount
+	transfers := namecoin.NewTransfers(sender.Address(), dest, escrow.Amount)
+	if deposit != nil {
+		transfers = append(transfers,
+			namecoin.Transfer{Src: sender.Address(), Dest: dest, Amount: *deposit})
+	}
+	if msg.Bounty != nil {
+		transfers = append(transfers,
+			namecoin.Transfer{Src: sender.Address(), Dest: dest, Amount: *msg.Bounty})
+	}
+	err = h.cash.WithContext(ctx).MoveCoinsBatch(db, transfers)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = h.history.Append(ctx, db, h.auth, obj.Key(), EventCreate, escrow.Amount)
+	if err != nil {
+		return nil, nil, err
+	}
+	return obj, transfers, nil
 }
 
 // validate does all common pre-processing between Check and Deliver
 func (h CreateEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
 	tx weave.Tx) (*CreateEscrowMsg, error) {
 
+	defer tracing.Begin(db, "validate").End()
 	rmsg, err := tx.GetMsg()
 	if err != nil {
 		return nil, err
 	}
-	msg, ok := rmsg.(*CreateEscrowMsg)
-	if !ok {
-		return nil, errors.ErrUnknownTxType(rmsg)
+	msg, err := createMsg(ctx, db, h.templates, rmsg)
+	if err != nil {
+		return nil, err
 	}
+	return msg, h.check(ctx, db, msg)
+}
 
-	err = msg.Validate()
+// check verifies the message can create an escrow now
+func (h CreateEscrowHandler) check(ctx weave.Context, db weave.KVStore,
+	msg *CreateEscrowMsg) error {
+
+	err := msg.Validate()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	// verify that timeout is in the future
 	height, _ := weave.GetHeight(ctx)
 	if msg.Timeout <= height {
-		return nil, ErrInvalidTimeout(msg.Timeout)
+		return ErrInvalidTimeout(msg.Timeout)
 	}
 
 	// sender must authorize this (if not set, defaults to MainSigner)
 	if msg.Sender != nil {
 		sender := weave.Permission(msg.Sender).Address()
 		if !h.auth.HasAddress(ctx, sender) {
-			return nil, errors.ErrUnauthorized()
+			return errors.ErrUnauthorized()
+		}
+	}
+
+	err = h.checkFunds(ctx, db, msg)
+	if err != nil {
+		return err
+	}
+	return h.checkLimits(ctx, db, msg)
+}
+
+// sender returns the sender of the escrow, which defaults
+// to the main signer
+func (h CreateEscrowHandler) sender(ctx weave.Context, msg *CreateEscrowMsg) weave.Permission {
+	sender := weave.Permission(msg.Sender)
+	if sender == nil {
+		sender = x.MainSigner(ctx, h.auth)
+	}
+	return sender
+}
+
+// deposit returns the deposit for the lifetime of the escrow,
+// nil if the params require none
+func (h CreateEscrowHandler) deposit(ctx weave.Context, db weave.KVStore,
+	msg *CreateEscrowMsg) (*x.Coin, error) {
+
+	params, err := h.params.Load(db)
+	if err != nil {
+		return nil, err
+	}
+	height, _ := weave.GetHeight(ctx)
+	return params.Deposit(msg.Timeout - height)
+}
+
+// checkFunds makes sure the sender can pay the full amount,
+// deposit and bounty, so Check fails without trying to move
+// any coins
+func (h CreateEscrowHandler) checkFunds(ctx weave.Context, db weave.KVStore,
+	msg *CreateEscrowMsg) error {
+
+	sender := h.sender(ctx, msg)
+	if sender == nil {
+		return errors.ErrUnauthorized()
+	}
+	spendable, err := h.cash.Spendable(db, sender.Address())
+	if err != nil {
+		return err
+	}
+	due := x.Coins(msg.Amount).Clone()
+	deposit, err := h.deposit(ctx, db, msg)
+	if err != nil {
+		return err
+	}
+	if deposit != nil {
+		due, err = due.Add(*deposit)
+		if err != nil {
+			return err
+		}
+	}
+	if msg.Bounty != nil {
+		due, err = due.Add(*msg.Bounty)
+		if err != nil {
+			return err
+		}
+	}
+	for _, c := range due {
+		if !spendable.Contains(*c) {
+			return cash.ErrInsufficientFunds()
 		}
 	}
+	return nil
+}
+
+// checkLimits enforces the caps set in the Params
+func (h CreateEscrowHandler) checkLimits(ctx weave.Context, db weave.KVStore,
+	msg *CreateEscrowMsg) error {
+
+	params, err := h.params.Load(db)
+	if err != nil {
+		return err
+	}
 
-	// TODO: check balance? or just error on deliver?
+	sender := h.sender(ctx, msg)
+	if params.GetMaxPerSender() > 0 && sender != nil {
+		count, err := h.bucket.CountBySender(db, sender)
+		if err != nil {
+			return err
+		}
+		if err := params.CheckOpen(count); err != nil {
+			return err
+		}
+	}
 
-	return msg, nil
+	if len(params.GetMaxLocked()) > 0 {
+		total, err := h.locked.Load(db)
+		if err != nil {
+			return err
+		}
+		for _, c := range msg.Amount {
+			total, err = total.Add(*c)
+			if err != nil {
+				return err
+			}
+		}
+		return params.CheckLocked(total)
+	}
+	return nil
 }
 
 //---- release
 
 // ReleaseEscrowHandler will set a name for objects in this bucket
 type ReleaseEscrowHandler struct {
-	auth   x.Authenticator
-	bucket Bucket
-	cash   cash.Controller
+	auth     x.Authenticator
+	bucket   Store
+	params   ParamsBucket
+	history  HistoryBucket
+	bids     BidBucket
+	policies PolicyBucket
+	prices   oracle.PriceBucket
+	cash     namecoin.Controller
+	// create makes the escrows releases are chained into
+	create CreateEscrowHandler
 }
 
 var _ weave.Handler = ReleaseEscrowHandler{}
@@ -178,33 +400,58 @@ func (h ReleaseEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
 		return res, err
 	}
 	escrow := AsEscrow(obj)
+	if escrow.Target != nil {
+		return h.settle(ctx, db, obj)
+	}
 
 	// use amount in message, or
 	request := x.Coins(msg.Amount)
 	available := x.Coins(escrow.Amount)
 	if len(request) == 0 {
-		request = available
-
-		// TODO: add functionality to compare two sets
-		// } else if !available.Contains(request) {
-		// 	// ensure there is enough to pay
-		// 	return res, cash.ErrInsufficientFunds()
+		request = available.Clone()
+	}
+	// any dust swept to the recipient is not chained
+	paid := request
+
+	// move the money from escrow to recipient, as long as
+	// there is enough of every ticker
+	sender := NewCondition(obj.Key()).Address()
+	transfers := payTransfers(escrow, sender, request)
+	available, err = subtractCoins(available, request)
+	if err != nil {
+		return res, err
 	}
 
-	// move the money from escrow to recipient
-	sender := Permission(obj.Key()).Address()
-	dest := weave.Permission(escrow.Recipient).Address()
-	for _, c := range request {
-		err := h.cash.MoveCoins(db, sender, dest, *c)
-		if err != nil {
-			// this will rollback the half-finished tx
-			return res, err
-		}
-		// remove coin from remaining balance
-		available, err = available.Subtract(*c)
-		if err != nil {
-			return res, err
+	// sweep the rest if it is too small to ever be released
+	params, err := h.params.Load(db)
+	if err != nil {
+		return res, err
+	}
+	if params.IsDust(available) {
+		if params.DustToSender {
+			transfers = append(transfers, namecoin.NewTransfers(sender,
+				weave.Permission(escrow.Sender).Address(), available)...)
+		} else {
+			transfers = append(transfers, payTransfers(escrow, sender, available)...)
 		}
+		// count the dust as released
+		request = append(request.Clone(), available...)
+		available = nil
+	}
+	// closing the escrow refunds the deposit and pays the arbiter
+	if !available.IsPositive() {
+		transfers = append(transfers,
+			depositTransfers(obj, weave.Permission(escrow.Sender).Address())...)
+		transfers = append(transfers, bountyTransfers(obj, true)...)
+	}
+	err = h.cash.WithContext(ctx).MoveCoinsBatch(db, transfers)
+	if err != nil {
+		return res, err
+	}
+
+	err = h.history.Append(ctx, db, h.auth, obj.Key(), EventRelease, request)
+	if err != nil {
+		return res, err
 	}
 
 	// if there is something left, just update the balance...
@@ -213,20 +460,115 @@ func (h ReleaseEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
 		res.Data = obj.Key()
 		// this updates the object, as we have a pointer
 		escrow.Amount = available
+		// a release settles any dispute
+		escrow.Dispute = nil
+		// and one approved by policy counts towards its limit
+		if msg.Policy {
+			escrow.PolicyReleased, err = x.Coins(escrow.PolicyReleased).Combine(paid)
+			if err != nil {
+				return res, err
+			}
+		}
 		err = h.bucket.Save(db, obj)
 	} else {
 		// otherwise we finished the escrow and can delete it
+		err = deleteBids(db, h.bids, obj)
+		if err != nil {
+			return res, err
+		}
 		err = h.bucket.Delete(db, obj.Key())
 	}
+	if err != nil {
+		return res, err
+	}
 
-	// returns error if Save/Delete failed
-	return res, err
+	res.Tags = append(res.Tags, ledger.TransferTags(ledger.KindIn, transfers)...)
+	// chained releases return the escrow they fund
+	if msg.Chain != nil {
+		id, event, funding, err := h.chain(ctx, db, obj, paid, msg.Chain)
+		if err != nil {
+			return res, err
+		}
+		res.Data = id
+		res.Tags = append(res.Tags, EventTag(EventRelease, obj.Key()), EventTag(event, id))
+		res.Tags = append(res.Tags, ledger.TransferTags(ledger.KindOut, funding)...)
+	} else {
+		// what is chained never reaches the recipient
+		res.Tags = append(res.Tags, destTags(escrow, transfers)...)
+	}
+	return res, nil
+}
+
+// settle pays out an escrow with a target value. The recipient
+// gets coins worth the target at the current price, the rest is
+// returned to the sender and the escrow is closed.
+func (h ReleaseEscrowHandler) settle(ctx weave.Context, db weave.KVStore,
+	obj orm.Object) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	escrow := AsEscrow(obj)
+	held := *escrow.Amount[0]
+
+	price, err := h.prices.Price(db, held.Ticker, escrow.Target.Ticker)
+	if err != nil {
+		return res, err
+	}
+	err = checkBounds(*price.Rate, escrow.MinPrice, escrow.MaxPrice)
+	if err != nil {
+		return res, err
+	}
+
+	src := NewCondition(obj.Key()).Address()
+	due := coinsFor(*escrow.Target, *price.Rate, held)
+	rest, err := held.Add(due.Negative())
+	if err != nil {
+		return res, err
+	}
+	var transfers []namecoin.Transfer
+	if due.IsPositive() {
+		transfers = append(transfers, payTransfers(escrow, src, x.Coins{&due})...)
+	}
+	if rest.IsPositive() {
+		transfers = append(transfers, namecoin.Transfer{
+			Src: src, Dest: weave.Permission(escrow.Sender).Address(), Amount: rest})
+	}
+	transfers = append(transfers,
+		depositTransfers(obj, weave.Permission(escrow.Sender).Address())...)
+	transfers = append(transfers, bountyTransfers(obj, true)...)
+	err = h.cash.WithContext(ctx).MoveCoinsBatch(db, transfers)
+	if err != nil {
+		return res, err
+	}
+
+	// only the part paid to the recipient counts as released
+	var released x.Coins
+	if due.IsPositive() {
+		released = x.Coins{&due}
+	}
+	err = h.history.Append(ctx, db, h.auth, obj.Key(), EventRelease, released)
+	if err != nil {
+		return res, err
+	}
+	if rest.IsPositive() {
+		err = h.history.Append(ctx, db, h.auth, obj.Key(), EventReturn, x.Coins{&rest})
+		if err != nil {
+			return res, err
+		}
+	}
+	err = deleteBids(db, h.bids, obj)
+	if err != nil {
+		return res, err
+	}
+	res.Tags = append(res.Tags, ledger.TransferTags(ledger.KindIn, transfers)...)
+	res.Tags = append(res.Tags, destTags(escrow, transfers)...)
+	return res, h.bucket.Delete(db, obj.Key())
 }
 
 // validate does all common pre-processing between Check and Deliver
 func (h ReleaseEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
 	tx weave.Tx) (*ReleaseEscrowMsg, orm.Object, error) {
 
+	defer tracing.Begin(db, "validate").End()
 	rmsg, err := tx.GetMsg()
 	if err != nil {
 		return nil, nil, err
@@ -250,10 +592,21 @@ func (h ReleaseEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
 	if escrow == nil {
 		return nil, nil, ErrNoSuchEscrow(msg.EscrowId)
 	}
+	if err := checkOpen(obj.Key(), escrow); err != nil {
+		return nil, nil, err
+	}
 
-	// arbiter must authorize this
+	// arbiter must authorize this, or the sender if allowed,
+	// or the policy of the arbiter if asked for
 	arbiter := weave.Permission(escrow.Arbiter).Address()
-	if !h.auth.HasAddress(ctx, arbiter) {
+	sender := weave.Permission(escrow.Sender).Address()
+	if msg.Policy {
+		err = approvedByPolicy(ctx, db, h.auth, h.policies, escrow, msg.Amount)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if !(escrow.Arbiter != nil && h.auth.HasAddress(ctx, arbiter)) &&
+		!(escrow.SenderCanRelease && h.auth.HasAddress(ctx, sender)) {
 		return nil, nil, errors.ErrUnauthorized()
 	}
 
@@ -263,6 +616,22 @@ func (h ReleaseEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
 		return nil, nil, ErrEscrowExpired(escrow.Timeout)
 	}
 
+	// the oracle decides the amount of priced escrows
+	if escrow.Target != nil && len(msg.Amount) > 0 {
+		return nil, nil, ErrInvalidTarget("amount set by price")
+	}
+	// and the attestations the tranches of milestone escrows
+	if len(escrow.Milestones) > 0 {
+		return nil, nil, ErrInvalidMilestone("released by attestation")
+	}
+
+	if msg.Chain != nil {
+		err = h.checkChain(ctx, db, obj, msg)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	return msg, obj, nil
 }
 
@@ -270,9 +639,11 @@ func (h ReleaseEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
 
 // ReturnEscrowHandler will set a name for objects in this bucket
 type ReturnEscrowHandler struct {
-	auth   x.Authenticator
-	bucket Bucket
-	cash   cash.Controller
+	auth    x.Authenticator
+	bucket  Store
+	history HistoryBucket
+	bids    BidBucket
+	cash    namecoin.Controller
 }
 
 var _ weave.Handler = ReturnEscrowHandler{}
@@ -282,7 +653,7 @@ var _ weave.Handler = ReturnEscrowHandler{}
 func (h ReturnEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
 	tx weave.Tx) (weave.CheckResult, error) {
 	var res weave.CheckResult
-	_, err := h.validate(ctx, db, tx)
+	_, _, err := h.validate(ctx, db, tx)
 	if err != nil {
 		return res, err
 	}
@@ -297,73 +668,137 @@ func (h ReturnEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
 func (h ReturnEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
 	tx weave.Tx) (weave.DeliverResult, error) {
 	var res weave.DeliverResult
-	obj, err := h.validate(ctx, db, tx)
+	obj, refund, err := h.validate(ctx, db, tx)
 	if err != nil {
 		return res, err
 	}
 	escrow := AsEscrow(obj)
 
-	// move the money from escrow to recipient
-	sender := Permission(obj.Key()).Address()
+	// move the money from escrow to sender
+	sender := NewCondition(obj.Key()).Address()
 	dest := weave.Permission(escrow.Sender).Address()
-	for _, c := range escrow.Amount {
-		err := h.cash.MoveCoins(db, sender, dest, *c)
-		if err != nil {
-			// this will rollback the half-finished tx
-			return res, err
-		}
+	transfers := namecoin.NewTransfers(sender, dest, escrow.Amount)
+	// the deposit is forfeit if nobody settled it in time, and
+	// the expiry job closes it
+	if !refund && h.auth.HasAddress(ctx, sender) {
+		dest = modaccount.Address(modaccount.FeeCollector)
+	}
+	transfers = append(transfers, depositTransfers(obj, dest)...)
+	// the arbiter is only paid for a release
+	transfers = append(transfers, bountyTransfers(obj, false)...)
+	err = h.cash.WithContext(ctx).MoveCoinsBatch(db, transfers)
+	if err != nil {
+		return res, err
 	}
 
 	// now remove the finished escrow
+	err = deleteBids(db, h.bids, obj)
+	if err != nil {
+		return res, err
+	}
 	err = h.bucket.Delete(db, obj.Key())
+	if err != nil {
+		return res, err
+	}
 
-	// returns error if Delete failed
-	return res, err
+	event := EventReturn
+	if refund {
+		event = EventRefund
+	}
+	err = h.history.Append(ctx, db, h.auth, obj.Key(), event, escrow.Amount)
+	if err != nil {
+		return res, err
+	}
+	res.Tags = append(res.Tags, EventTag(event, obj.Key()))
+	res.Tags = append(res.Tags, ledger.TransferTags(ledger.KindIn, transfers)...)
+	return res, nil
 }
 
-// validate does all common pre-processing between Check and Deliver
+// validate does all common pre-processing between Check and Deliver.
+// It returns true if this is a refund by the recipient before the
+// timeout, rather than a return after it.
 func (h ReturnEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
-	tx weave.Tx) (orm.Object, error) {
+	tx weave.Tx) (orm.Object, bool, error) {
 
+	defer tracing.Begin(db, "validate").End()
 	rmsg, err := tx.GetMsg()
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	msg, ok := rmsg.(*ReturnEscrowMsg)
 	if !ok {
-		return nil, errors.ErrUnknownTxType(rmsg)
+		return nil, false, errors.ErrUnknownTxType(rmsg)
 	}
 
 	err = msg.Validate()
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	// load escrow
 	obj, err := h.bucket.Get(db, msg.EscrowId)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	escrow := AsEscrow(obj)
 	if escrow == nil {
-		return nil, ErrNoSuchEscrow(msg.EscrowId)
+		return nil, false, ErrNoSuchEscrow(msg.EscrowId)
+	}
+	if err := checkOpen(obj.Key(), escrow); err != nil {
+		return nil, false, err
 	}
 
-	// timeout must have expired
+	// anyone can return it after the timeout
 	height, _ := weave.GetHeight(ctx)
-	if height <= escrow.Timeout {
-		return nil, ErrEscrowNotExpired(escrow.Timeout)
+	if height > escrow.Timeout {
+		return obj, false, nil
+	}
+
+	// before that, only the recipient can give it back
+	rcpt := weave.Permission(escrow.Recipient).Address()
+	if !h.auth.HasAddress(ctx, rcpt) {
+		return nil, false, ErrEscrowNotExpired(escrow.Timeout)
+	}
+	return obj, true, nil
+}
+
+// depositTransfers pays the deposit held by the escrow
+// to dest, nil if it has none
+func depositTransfers(obj orm.Object, dest weave.Address) []namecoin.Transfer {
+	deposit := AsEscrow(obj).Deposit
+	if deposit == nil || !deposit.IsPositive() {
+		return nil
 	}
+	src := NewCondition(obj.Key()).Address()
+	return []namecoin.Transfer{{Src: src, Dest: dest, Amount: *deposit}}
+}
 
-	return obj, nil
+// scheduleExpiry returns the escrow to the sender in the first
+// block after it timed out. The return fails, and is dropped, if
+// the escrow is closed or quarantined by then.
+// The job runs as the escrow itself, so the return can tell it
+// from one sent by a party and forfeit the deposit.
+func scheduleExpiry(db weave.KVStore, jobs scheduler.Bucket, obj orm.Object) error {
+	job, err := scheduler.NewJob("escrow", AsEscrow(obj).Timeout+1,
+		&ReturnEscrowMsg{EscrowId: obj.Key()}, expireEscrowGas)
+	if err != nil {
+		return err
+	}
+	job.Owner = NewCondition(obj.Key()).Permission()
+	_, err = jobs.Schedule(db, job)
+	return err
 }
 
 //---- update
 
-// UpdateEscrowHandler will set a name for objects in this bucket
+// UpdateEscrowHandler offers parties of an escrow to others
 type UpdateEscrowHandler struct {
-	auth   x.Authenticator
-	bucket Bucket
+	auth     x.Authenticator
+	bucket   Store
+	params   ParamsBucket
+	offers   ownership.Bucket
+	accounts modaccount.Bucket
+	history  HistoryBucket
 }
 
 var _ weave.Handler = UpdateEscrowHandler{}
@@ -383,8 +818,8 @@ func (h UpdateEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
 	return res, nil
 }
 
-// Deliver moves the tokens from sender to receiver if
-// all preconditions are met
+// Deliver offers every party in the message to its new holder,
+// the escrow is unchanged until they accept
 func (h UpdateEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
 	tx weave.Tx) (weave.DeliverResult, error) {
 	var res weave.DeliverResult
@@ -394,33 +829,134 @@ func (h UpdateEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
 	}
 	escrow := AsEscrow(obj)
 
-	// update the escrow with message values
-	if msg.Sender != nil {
-		escrow.Sender = msg.Sender
+	params, err := h.params.Load(db)
+	if err != nil {
+		return res, err
+	}
+	height, _ := weave.GetHeight(ctx)
+	expires := params.OfferExpires(height)
+	for _, c := range partyChanges(msg) {
+		holder := *party(escrow, c.role)
+		tag, err := h.offers.Offer(ctx, db, offerKey(obj.Key(), c.role),
+			holder, c.to, expires)
+		if err != nil {
+			return res, err
+		}
+		err = h.history.AppendNote(ctx, db, h.auth, obj.Key(), EventOffer, nil, c.role)
+		if err != nil {
+			return res, err
+		}
+		res.Tags = append(res.Tags, tag)
+	}
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h UpdateEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*UpdateEscrowPartiesMsg, orm.Object, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
 	}
-	if msg.Recipient != nil {
-		escrow.Recipient = msg.Recipient
+	msg, ok := rmsg.(*UpdateEscrowPartiesMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
 	}
-	if msg.Arbiter != nil {
-		escrow.Arbiter = msg.Arbiter
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// save the updated escrow
-	err = h.bucket.Save(db, obj)
+	// timeout must not have expired
+	obj, err := loadUnexpired(ctx, db, h.bucket, msg.EscrowId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// we must hold the parties we want to change, and the new
+	// ones must be able to sign
+	escrow := AsEscrow(obj)
+	for _, c := range partyChanges(msg) {
+		err := ownership.Authorize(ctx, h.auth, *party(escrow, c.role))
+		if err != nil {
+			return nil, nil, err
+		}
+		err = checkNewParty(db, h.accounts, obj.Key(), c.to)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return msg, obj, nil
+}
+
+//---- observers
+
+// UpdateObserversHandler lets the sender add and remove
+// the observers of an escrow
+type UpdateObserversHandler struct {
+	auth    x.Authenticator
+	bucket  Store
+	history HistoryBucket
+}
+
+var _ weave.Handler = UpdateObserversHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h UpdateObserversHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += updateEscrowCost
+	return res, nil
+}
 
-	// returns error if Save failed
+// Deliver removes and then adds the observers. Saving the
+// escrow fails if that leaves too many.
+func (h UpdateObserversHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	escrow := AsEscrow(obj)
+
+	var observers [][]byte
+	for _, addr := range escrow.Observers {
+		if indexOf(msg.Remove, addr) < 0 {
+			observers = append(observers, addr)
+		}
+	}
+	escrow.Observers = append(observers, msg.Add...)
+
+	err = h.bucket.Save(db, obj)
+	if err != nil {
+		return res, err
+	}
+	err = h.history.Append(ctx, db, h.auth, obj.Key(), EventObservers, nil)
 	return res, err
 }
 
 // validate does all common pre-processing between Check and Deliver
-func (h UpdateEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
-	tx weave.Tx) (*UpdateEscrowPartiesMsg, orm.Object, error) {
+func (h UpdateObserversHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*UpdateEscrowObserversMsg, orm.Object, error) {
 
+	defer tracing.Begin(db, "validate").End()
 	rmsg, err := tx.GetMsg()
 	if err != nil {
 		return nil, nil, err
 	}
-	msg, ok := rmsg.(*UpdateEscrowPartiesMsg)
+	msg, ok := rmsg.(*UpdateEscrowObserversMsg)
 	if !ok {
 		return nil, nil, errors.ErrUnknownTxType(rmsg)
 	}
@@ -430,7 +966,6 @@ func (h UpdateEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
 		return nil, nil, err
 	}
 
-	// load escrow
 	obj, err := h.bucket.Get(db, msg.EscrowId)
 	if err != nil {
 		return nil, nil, err
@@ -439,32 +974,122 @@ func (h UpdateEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
 	if escrow == nil {
 		return nil, nil, ErrNoSuchEscrow(msg.EscrowId)
 	}
+	if err := checkOpen(obj.Key(), escrow); err != nil {
+		return nil, nil, err
+	}
 
-	// timeout must not have expired
-	height, _ := weave.GetHeight(ctx)
-	if height > escrow.Timeout {
-		return nil, nil, ErrEscrowExpired(escrow.Timeout)
+	sender := weave.Permission(escrow.Sender).Address()
+	if !h.auth.HasAddress(ctx, sender) {
+		return nil, nil, errors.ErrUnauthorized()
 	}
 
-	// we must have the permission for the items we want to change
-	if msg.Sender != nil {
-		sender := weave.Permission(escrow.Sender).Address()
-		if !h.auth.HasAddress(ctx, sender) {
-			return nil, nil, errors.ErrUnauthorized()
+	for _, addr := range msg.Remove {
+		if indexOf(escrow.Observers, addr) < 0 {
+			return nil, nil, ErrInvalidObservers("not an observer")
 		}
 	}
-	if msg.Recipient != nil {
-		rcpt := weave.Permission(escrow.Recipient).Address()
-		if !h.auth.HasAddress(ctx, rcpt) {
-			return nil, nil, errors.ErrUnauthorized()
+	for _, addr := range msg.Add {
+		if indexOf(escrow.Observers, addr) >= 0 && indexOf(msg.Remove, addr) < 0 {
+			return nil, nil, ErrInvalidObservers("duplicate")
 		}
 	}
-	if msg.Arbiter != nil {
-		arbiter := weave.Permission(escrow.Arbiter).Address()
-		if !h.auth.HasAddress(ctx, arbiter) {
-			return nil, nil, errors.ErrUnauthorized()
+	return msg, obj, nil
+}
+
+//---- reveal
+
+// RevealMemoHandler publishes the memo of an escrow that
+// was created with only its hash
+type RevealMemoHandler struct {
+	auth    x.Authenticator
+	bucket  Store
+	history HistoryBucket
+}
+
+var _ weave.Handler = RevealMemoHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h RevealMemoHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += updateEscrowCost
+	return res, nil
+}
+
+// Deliver stores the memo next to its hash
+func (h RevealMemoHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	AsEscrow(obj).Memo = msg.Memo
+
+	err = h.bucket.Save(db, obj)
+	if err != nil {
+		return res, err
+	}
+	err = h.history.Append(ctx, db, h.auth, obj.Key(), EventReveal, nil)
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h RevealMemoHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*RevealMemoMsg, orm.Object, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*RevealMemoMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := h.bucket.Get(db, msg.EscrowId)
+	if err != nil {
+		return nil, nil, err
+	}
+	escrow := AsEscrow(obj)
+	if escrow == nil {
+		return nil, nil, ErrNoSuchEscrow(msg.EscrowId)
+	}
+	if err := checkOpen(obj.Key(), escrow); err != nil {
+		return nil, nil, err
+	}
+
+	// any party may reveal it, eg. to the arbiter of a dispute
+	var signed bool
+	for _, p := range [][]byte{escrow.Sender, escrow.Arbiter, escrow.Recipient} {
+		if p != nil && h.auth.HasAddress(ctx, weave.Permission(p).Address()) {
+			signed = true
 		}
 	}
+	if !signed {
+		return nil, nil, errors.ErrUnauthorized()
+	}
 
+	switch {
+	case len(escrow.MemoHash) == 0:
+		return nil, nil, ErrInvalidReveal("no memo hash")
+	case escrow.Memo != "":
+		return nil, nil, ErrInvalidReveal("already revealed")
+	case !bytes.Equal(MemoHash(msg.Salt, msg.Memo), escrow.MemoHash):
+		return nil, nil, ErrInvalidReveal("hash mismatch")
+	}
 	return msg, obj, nil
 }
@@ -8,11 +8,17 @@ import (
 
 	"github.com/confio/weave"
 	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
 	"github.com/confio/weave/orm"
 	"github.com/confio/weave/store"
 	"github.com/confio/weave/x"
 	"github.com/confio/weave/x/cash"
+	"github.com/iov-one/bcp-demo/storage"
 	"github.com/iov-one/bcp-demo/x/hashlock"
+	"github.com/iov-one/bcp-demo/x/modaccount"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/oracle"
+	"github.com/iov-one/bcp-demo/x/rbac"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -478,8 +484,8 @@ func TestHandler(t *testing.T) {
 			true,
 			nil,
 		},
-		// we update the arbiter and sender, and then make sure
-		// the new actors are used
+		// we update the arbiter, and once accepted make sure
+		// the new actor is used
 		11: {
 			a.Address(),
 			all,
@@ -496,6 +502,14 @@ func TestHandler(t *testing.T) {
 					Arbiter:  d,
 				},
 				height: 200,
+			}, {
+				perms: []weave.Permission{d},
+				// d takes over
+				msg: &AcceptEscrowPartyMsg{
+					EscrowId: id(1),
+					Role:     RoleArbiter,
+				},
+				height: 300,
 			}},
 			action{
 				// new arbiter can resolve
@@ -527,7 +541,7 @@ func TestHandler(t *testing.T) {
 				},
 			},
 		},
-		// after update, original arbiter cannot resolve
+		// after the update is accepted, original arbiter cannot resolve
 		12: {
 			a.Address(),
 			all,
@@ -544,6 +558,14 @@ func TestHandler(t *testing.T) {
 					Arbiter:  d,
 				},
 				height: 200,
+			}, {
+				perms: []weave.Permission{d},
+				// d takes over
+				msg: &AcceptEscrowPartyMsg{
+					EscrowId: id(1),
+					Role:     RoleArbiter,
+				},
+				height: 300,
 			}},
 			action{
 				// original arbiter can no longer resolve
@@ -649,14 +671,100 @@ func TestHandler(t *testing.T) {
 				},
 			},
 		},
+		// sender cannot release by default
+		16: {
+			a.Address(),
+			all,
+			[]action{{
+				perms:  []weave.Permission{a},
+				msg:    NewCreateMsg(a, b, c, all, 12345, ""),
+				height: 1000,
+			}},
+			action{
+				perms: []weave.Permission{a},
+				msg: &ReleaseEscrowMsg{
+					EscrowId: id(1),
+				},
+				height: 2000,
+			},
+			true,
+			nil,
+		},
+		// sender can release if the escrow allows it
+		17: {
+			a.Address(),
+			all,
+			[]action{{
+				perms:  []weave.Permission{a},
+				msg:    senderRelease(NewCreateMsg(a, b, c, all, 12345, "")),
+				height: 1000,
+			}},
+			action{
+				perms: []weave.Permission{a},
+				msg: &ReleaseEscrowMsg{
+					EscrowId: id(1),
+					Amount:   some,
+				},
+				height: 2000,
+			},
+			false,
+			[]query{
+				// verify escrow balance is updated, flag is kept
+				{
+					"/escrows", "", id(1), false,
+					[]orm.Object{
+						withSenderRelease(NewEscrow(id(1), a, b, c, remain, 12345, "")),
+					},
+					NewBucket().Bucket,
+				},
+				// recipient has cash
+				{"/wallets", "", b.Address(), false,
+					[]orm.Object{
+						mo(cash.WalletWith(b.Address(), some...)),
+					},
+					cash.NewBucket().Bucket,
+				},
+			},
+		},
+		// recipient can refund before timeout
+		18: {
+			a.Address(),
+			all,
+			[]action{{
+				perms:  []weave.Permission{a},
+				msg:    NewCreateMsg(a, b, c, all, 1234, ""),
+				height: 1000,
+			}},
+			action{
+				perms: []weave.Permission{b},
+				msg: &ReturnEscrowMsg{
+					EscrowId: id(1),
+				},
+				height: 1233,
+			},
+			false,
+			[]query{
+				// verify escrow is deleted
+				{
+					"/escrows", "", id(1), false, nil, orm.Bucket{},
+				},
+				// sender has the cash back
+				{"/wallets", "", a.Address(), false,
+					[]orm.Object{
+						mo(cash.WalletWith(a.Address(), all...)),
+					},
+					cash.NewBucket().Bucket,
+				},
+			},
+		},
 	}
 
 	bank := cash.NewBucket()
-	ctrl := cash.NewController(bank)
+	ctrl := namecoin.NewWalletController(bank)
 	auth := authenticator()
 	// create handler objects and query objects
 	h := app.NewRouter()
-	RegisterRoutes(h, auth, ctrl)
+	RegisterRoutes(h, auth, ctrl, NewBucket())
 	qr := weave.NewQueryRouter()
 	cash.RegisterQuery(qr)
 	RegisterQuery(qr)
@@ -779,7 +887,7 @@ func TestAtomicSwap(t *testing.T) {
 	}
 
 	bank := cash.NewBucket()
-	ctrl := cash.NewController(bank)
+	ctrl := namecoin.NewWalletController(bank)
 
 	setBalance := func(t *testing.T, db weave.KVStore, addr weave.Address, coins x.Coins) {
 		acct, err := cash.WalletWith(addr, coins...)
@@ -801,7 +909,7 @@ func TestAtomicSwap(t *testing.T) {
 	// route the escrow commands, and wrap with the hashlock
 	// middleware
 	r := app.NewRouter()
-	RegisterRoutes(r, auth, ctrl)
+	RegisterRoutes(r, auth, ctrl, NewBucket())
 	h := helpers.Wrap(hashlock.NewDecorator(), r)
 
 	timeout := int64(1000)
@@ -865,6 +973,631 @@ func TestAtomicSwap(t *testing.T) {
 	}
 }
 
+// TestReturnEvents checks refunds and returns are tagged
+func TestReturnEvents(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), namecoin.NewWalletController(bank), NewBucket())
+
+	cases := []struct {
+		perm   weave.Permission
+		height int64
+		tag    string
+	}{
+		0: {b, 500, "escrow.refund"},
+		1: {a, 1500, "escrow.return"},
+		2: {nil, 1500, "escrow.return"},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			acct, err := cash.WalletWith(a.Address(), &x.Coin{Whole: 10, Ticker: "FOO"})
+			require.NoError(t, err)
+			require.NoError(t, bank.Save(db, acct))
+
+			ctx := authenticator().SetPermissions(weave.WithHeight(context.Background(), 100), a)
+			msg := NewCreateMsg(a, b, a, mustCombineCoins(x.NewCoin(10, 0, "FOO")), 1000, "")
+			res, err := r.Deliver(ctx, db, helpers.MockTx(msg))
+			require.NoError(t, err)
+
+			ctx = weave.WithHeight(context.Background(), tc.height)
+			if tc.perm != nil {
+				ctx = authenticator().SetPermissions(ctx, tc.perm)
+			}
+			ret := &ReturnEscrowMsg{EscrowId: res.Data}
+			dres, err := r.Deliver(ctx, db, helpers.MockTx(ret))
+			require.NoError(t, err)
+			// and the coins returned to the sender
+			require.Equal(t, 2, len(dres.Tags))
+			assert.Equal(t, tc.tag, string(dres.Tags[0].Key))
+			assert.Equal(t, "esc1qqqqqqqqqqqqzjwh4xz", string(dres.Tags[0].Value))
+		})
+	}
+}
+
+func senderRelease(msg *CreateEscrowMsg) *CreateEscrowMsg {
+	msg.SenderCanRelease = true
+	return msg
+}
+
+func withSenderRelease(obj orm.Object) orm.Object {
+	AsEscrow(obj).SenderCanRelease = true
+	return obj
+}
+
+// TestReleaseOps makes sure releasing an escrow does
+// a constant amount of work per coin
+func TestReleaseOps(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), namecoin.NewWalletController(bank), NewBucket())
+	ctx := weave.WithHeight(context.Background(), 500)
+
+	tickers := []string{"AAA", "BBB", "CCC", "DDD"}
+	release := func(t *testing.T, n int) storage.Counts {
+		db := storage.MockCountingStore()
+
+		var coins x.Coins
+		for _, ticker := range tickers[:n] {
+			coins = append(coins, &x.Coin{Whole: 100, Ticker: ticker})
+		}
+		acct, err := cash.WalletWith(a.Address(), coins.Clone()...)
+		require.NoError(t, err)
+		require.NoError(t, bank.Save(db, acct))
+
+		msg := NewCreateMsg(a, b, a, coins, 1000, "")
+		res, err := r.Deliver(authenticator().SetPermissions(ctx, a), db, helpers.MockTx(msg))
+		require.NoError(t, err)
+
+		rel := &ReleaseEscrowMsg{EscrowId: res.Data}
+		counts, err := db.Count(func(kv weave.KVStore) error {
+			_, err := r.Deliver(authenticator().SetPermissions(ctx, a), kv, helpers.MockTx(rel))
+			return err
+		})
+		require.NoError(t, err)
+		return counts
+	}
+
+	base := release(t, 1)
+	step := release(t, 2).Total() - base.Total()
+	assert.True(t, step > 0)
+	for n := 3; n <= len(tickers); n++ {
+		counts := release(t, n)
+		assert.Equal(t, base.Total()+(n-1)*step, counts.Total(), "%d coins", n)
+		// no scans over the store
+		assert.Equal(t, 0, counts.Iterators)
+	}
+}
+
+// TestReleaseMulti releases an escrow of two tickers in parts
+func TestReleaseMulti(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), namecoin.NewWalletController(bank), NewBucket())
+	ctx := authenticator().SetPermissions(weave.WithHeight(context.Background(), 500), a)
+
+	db := store.MemStore()
+	acct, err := cash.WalletWith(a.Address(), &x.Coin{Whole: 10, Ticker: "FOO"},
+		&x.Coin{Whole: 5, Ticker: "BAR"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, acct))
+	coins := mustCombineCoins(x.NewCoin(10, 0, "FOO"), x.NewCoin(5, 0, "BAR"))
+	res, err := r.Deliver(ctx, db, helpers.MockTx(NewCreateMsg(a, b, a, coins, 1000, "")))
+	require.NoError(t, err)
+	id := res.Data
+	release := func(amount ...x.Coin) error {
+		msg := &ReleaseEscrowMsg{EscrowId: id, Amount: mustCombineCoins(amount...)}
+		_, err := r.Deliver(ctx, db, helpers.MockTx(msg))
+		return err
+	}
+
+	require.NoError(t, release(x.NewCoin(4, 0, "FOO")))
+	obj, err := NewBucket().Get(db, id)
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(6, 0, "FOO"), x.NewCoin(5, 0, "BAR")),
+		x.Coins(AsEscrow(obj).Amount))
+
+	// more than is left of one ticker
+	err = release(x.NewCoin(1, 0, "FOO"), x.NewCoin(6, 0, "BAR"))
+	assert.True(t, cash.IsInsufficientFundsErr(err), "%+v", err)
+
+	require.NoError(t, release(x.NewCoin(6, 0, "FOO"), x.NewCoin(5, 0, "BAR")))
+	obj, err = NewBucket().Get(db, id)
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+}
+
+// TestPartiesKeepAddress makes sure an escrow settles with the
+// addresses it was created with, even if their names moved
+func TestPartiesKeepAddress(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	_, c := helpers.MakeKey()
+
+	ctrl := namecoin.NewController()
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), ctrl, NewBucket())
+	ctx := authenticator().SetPermissions(weave.WithHeight(context.Background(), 500), a)
+
+	db := store.MemStore()
+	wallets := namecoin.NewWalletBucket()
+	require.NoError(t, ctrl.IssueCoins(db, a.Address(), x.NewCoin(10, 0, "FOO")))
+	named, err := namecoin.WalletWith(b.Address(), "bobby")
+	require.NoError(t, err)
+	require.NoError(t, wallets.Save(db, named))
+
+	coins := mustCombineCoins(x.NewCoin(10, 0, "FOO"))
+	res, err := r.Deliver(ctx, db, helpers.MockTx(NewCreateMsg(a, b, a, coins, 1000, "")))
+	require.NoError(t, err)
+
+	// the name goes to c, as a name sale does
+	namecoin.AsWallet(named).Name = ""
+	require.NoError(t, wallets.Save(db, named))
+	other, err := namecoin.WalletWith(c.Address(), "bobby")
+	require.NoError(t, err)
+	require.NoError(t, wallets.Save(db, other))
+
+	_, err = r.Deliver(ctx, db, helpers.MockTx(&ReleaseEscrowMsg{EscrowId: res.Data}))
+	require.NoError(t, err)
+	balance, err := ctrl.Balance(db, b.Address())
+	require.NoError(t, err)
+	assert.Equal(t, coins, balance)
+	balance, err = ctrl.Balance(db, c.Address())
+	require.NoError(t, err)
+	assert.True(t, balance.IsEmpty())
+}
+
+// TestReleaseDust makes sure a partial release leaves no
+// dust behind, if a threshold is configured
+func TestReleaseDust(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), namecoin.NewWalletController(bank), NewBucket())
+	ctx := authenticator().SetPermissions(weave.WithHeight(context.Background(), 500), a)
+
+	all := x.NewCoin(10, 0, "FOO")
+	threshold := mustCombineCoins(x.NewCoin(0, 1000, "FOO"))
+	cases := []struct {
+		params  *Params
+		release x.Coin
+		closed  bool
+		// final balances of sender and recipient
+		aFinal, bFinal x.Coins
+	}{
+		// no policy, dust stays
+		0: {nil, x.NewCoin(9, 999999500, "FOO"), false,
+			nil, mustCombineCoins(x.NewCoin(9, 999999500, "FOO"))},
+		// swept to the recipient
+		1: {&Params{DustThreshold: threshold}, x.NewCoin(9, 999999500, "FOO"), true,
+			nil, mustCombineCoins(all)},
+		// swept to the sender
+		2: {&Params{DustThreshold: threshold, DustToSender: true}, x.NewCoin(9, 999999500, "FOO"), true,
+			mustCombineCoins(x.NewCoin(0, 500, "FOO")), mustCombineCoins(x.NewCoin(9, 999999500, "FOO"))},
+		// above threshold, nothing happens
+		3: {&Params{DustThreshold: threshold}, x.NewCoin(9, 0, "FOO"), false,
+			nil, mustCombineCoins(x.NewCoin(9, 0, "FOO"))},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			if tc.params != nil {
+				require.NoError(t, NewParamsBucket().Store(db, tc.params))
+			}
+			acct, err := cash.WalletWith(a.Address(), &all)
+			require.NoError(t, err)
+			require.NoError(t, bank.Save(db, acct))
+
+			msg := NewCreateMsg(a, b, a, mustCombineCoins(all), 1000, "")
+			res, err := r.Deliver(ctx, db, helpers.MockTx(msg))
+			require.NoError(t, err)
+			id := res.Data
+
+			rel := &ReleaseEscrowMsg{EscrowId: id, Amount: []*x.Coin{&tc.release}}
+			res, err = r.Deliver(ctx, db, helpers.MockTx(rel))
+			require.NoError(t, err)
+
+			obj, err := NewBucket().Get(db, id)
+			require.NoError(t, err)
+			assert.Equal(t, tc.closed, obj == nil)
+			assert.Equal(t, tc.closed, res.Data == nil)
+
+			for _, bal := range []struct {
+				addr     weave.Address
+				expected x.Coins
+			}{
+				{a.Address(), tc.aFinal},
+				{b.Address(), tc.bFinal},
+			} {
+				wallet, err := bank.Get(db, bal.addr)
+				require.NoError(t, err)
+				assert.Equal(t, bal.expected, cash.AsCoins(wallet))
+			}
+		})
+	}
+}
+
+// TestEscrowLimits checks the caps are enforced on create
+// and the total value locked follows all escrow actions
+func TestEscrowLimits(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	_, c := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), namecoin.NewWalletController(bank), NewBucket())
+	ctx := weave.WithHeight(context.Background(), 500)
+	as := func(perm weave.Permission) weave.Context {
+		return authenticator().SetPermissions(ctx, perm)
+	}
+
+	db := store.MemStore()
+	params := &Params{
+		MaxPerSender: 2,
+		MaxLocked:    mustCombineCoins(x.NewCoin(25, 0, "FOO")),
+	}
+	require.NoError(t, NewParamsBucket().Store(db, params))
+	for _, perm := range []weave.Permission{a, b} {
+		acct, err := cash.WalletWith(perm.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+		require.NoError(t, err)
+		require.NoError(t, bank.Save(db, acct))
+	}
+	locked := func() x.Coins {
+		total, err := NewLockedBucket().Load(db)
+		require.NoError(t, err)
+		return total
+	}
+	create := func(sender weave.Permission, amount int64) ([]byte, error) {
+		msg := NewCreateMsg(sender, c, c, mustCombineCoins(x.NewCoin(amount, 0, "FOO")), 1000, "")
+		tx := helpers.MockTx(msg)
+		_, err := r.Check(as(sender), db, tx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := r.Deliver(as(sender), db, tx)
+		return res.Data, err
+	}
+
+	first, err := create(a, 5)
+	require.NoError(t, err)
+	_, err = create(a, 5)
+	require.NoError(t, err)
+	// a has two open escrows
+	_, err = create(a, 5)
+	assert.True(t, IsLimitExceededErr(err), "%+v", err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(10, 0, "FOO")), locked())
+
+	// b is below its own cap, but above the total
+	_, err = create(b, 16)
+	assert.True(t, IsLimitExceededErr(err), "%+v", err)
+	_, err = create(b, 15)
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(25, 0, "FOO")), locked())
+
+	// releasing frees both caps
+	rel := &ReleaseEscrowMsg{EscrowId: first, Amount: mustCombineCoins(x.NewCoin(2, 0, "FOO"))}
+	_, err = r.Deliver(as(c), db, helpers.MockTx(rel))
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(23, 0, "FOO")), locked())
+	rel = &ReleaseEscrowMsg{EscrowId: first}
+	_, err = r.Deliver(as(c), db, helpers.MockTx(rel))
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(20, 0, "FOO")), locked())
+	_, err = create(a, 5)
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(25, 0, "FOO")), locked())
+
+	// returning expired escrows as well
+	late := weave.WithHeight(context.Background(), 2000)
+	count, err := NewBucket().CountBySender(db, a)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	for id := int64(1); id <= 4; id++ {
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, uint64(id))
+		obj, err := NewBucket().Get(db, key)
+		require.NoError(t, err)
+		if obj == nil {
+			continue
+		}
+		ret := &ReturnEscrowMsg{EscrowId: key}
+		_, err = r.Deliver(late, db, helpers.MockTx(ret))
+		require.NoError(t, err)
+	}
+	assert.Empty(t, locked())
+}
+
+// TestCreateStorageGas checks large escrows allocate gas
+// for the memo and coins they store
+func TestCreateStorageGas(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), namecoin.NewWalletController(bank), NewBucket())
+	ctx := authenticator().SetPermissions(weave.WithHeight(context.Background(), 500), a)
+
+	db := store.MemStore()
+	acct, err := cash.WalletWith(a.Address(), &x.Coin{Whole: 100, Ticker: "FOO"},
+		&x.Coin{Whole: 100, Ticker: "BAR"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, acct))
+
+	small := NewCreateMsg(a, b, b, mustCombineCoins(x.NewCoin(5, 0, "FOO")), 1000, "")
+	large := NewCreateMsg(a, b, b, mustCombineCoins(x.NewCoin(5, 0, "FOO"),
+		x.NewCoin(5, 0, "BAR")), 1000, "pay for the pizza")
+	gas := func(msg *CreateEscrowMsg) int64 {
+		res, err := r.Check(ctx, db, helpers.MockTx(msg))
+		require.NoError(t, err)
+		return res.GasAllocated
+	}
+
+	// free by default
+	assert.Equal(t, createEscrowCost, gas(small))
+	assert.Equal(t, createEscrowCost, gas(large))
+
+	params := &Params{GasPerByte: 3}
+	require.NoError(t, NewParamsBucket().Store(db, params))
+	assert.Equal(t, createEscrowCost+params.StorageGas(small), gas(small))
+	assert.Equal(t, createEscrowCost+params.StorageGas(large), gas(large))
+	assert.True(t, gas(large) > gas(small))
+}
+
+// TestCreateEscrowV2 creates escrows with the second version
+// of the message through the same handler
+func TestCreateEscrowV2(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), namecoin.NewWalletController(bank), NewBucket())
+	ctx := authenticator().SetPermissions(weave.WithHeight(context.Background(), 500), a)
+
+	db := store.MemStore()
+	acct, err := cash.WalletWith(a.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, acct))
+
+	msg := &CreateEscrowMsgV2{
+		Arbiter:   b,
+		Recipient: b,
+		Amount:    mustCombineCoins(x.NewCoin(5, 0, "FOO")),
+		TimeoutIn: 50,
+		Options:   &EscrowOptions{SenderCanRelease: true},
+	}
+	tx := helpers.MockTx(msg)
+	_, err = r.Check(ctx, db, tx)
+	require.NoError(t, err)
+	res, err := r.Deliver(ctx, db, tx)
+	require.NoError(t, err)
+
+	obj, err := NewBucket().Get(db, res.Data)
+	require.NoError(t, err)
+	escrow := AsEscrow(obj)
+	require.NotNil(t, escrow)
+	assert.Equal(t, int64(550), escrow.Timeout)
+	assert.Equal(t, a, weave.Permission(escrow.Sender))
+	assert.True(t, escrow.SenderCanRelease)
+
+	// both timeouts are rejected
+	msg.Timeout = 600
+	_, err = r.Check(ctx, db, helpers.MockTx(msg))
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+}
+
+// TestEscrowDeposit checks the deposit is refunded when the
+// escrow is settled, and forfeit when the expiry job returns it
+func TestEscrowDeposit(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), control, NewBucket())
+	at := func(height int64, perm weave.Permission) weave.Context {
+		ctx := weave.WithHeight(context.Background(), height)
+		return authenticator().SetPermissions(ctx, perm)
+	}
+
+	db := store.MemStore()
+	params := &Params{DepositPerBlock: &x.Coin{Fractional: 1000000, Ticker: "FOO"}}
+	require.NoError(t, NewParamsBucket().Store(db, params))
+	acct, err := cash.WalletWith(a.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, acct))
+	balance := func(addr weave.Address) x.Coins {
+		coins, err := control.Balance(db, addr)
+		require.NoError(t, err)
+		return coins
+	}
+	create := func() []byte {
+		// 500 blocks at 0.001 FOO
+		msg := NewCreateMsg(a, b, b, mustCombineCoins(x.NewCoin(5, 0, "FOO")), 1000, "")
+		tx := helpers.MockTx(msg)
+		_, err := r.Check(at(500, a), db, tx)
+		require.NoError(t, err)
+		res, err := r.Deliver(at(500, a), db, tx)
+		require.NoError(t, err)
+		obj, err := NewBucket().Get(db, res.Data)
+		require.NoError(t, err)
+		assert.Equal(t, x.NewCoin(0, 500000000, "FOO"), *AsEscrow(obj).Deposit)
+		return res.Data
+	}
+
+	// settled escrows refund the deposit
+	id := create()
+	assert.Equal(t, mustCombineCoins(x.NewCoin(94, 500000000, "FOO")), balance(a.Address()))
+	_, err = r.Deliver(at(600, b), db, helpers.MockTx(&ReleaseEscrowMsg{EscrowId: id}))
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(95, 0, "FOO")), balance(a.Address()))
+	assert.Equal(t, mustCombineCoins(x.NewCoin(5, 0, "FOO")), balance(b.Address()))
+
+	// a refund by the recipient as well
+	id = create()
+	_, err = r.Deliver(at(600, b), db, helpers.MockTx(&ReturnEscrowMsg{EscrowId: id}))
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(95, 0, "FOO")), balance(a.Address()))
+
+	// and a return by a party after the timeout
+	id = create()
+	_, err = r.Deliver(at(1001, a), db, helpers.MockTx(&ReturnEscrowMsg{EscrowId: id}))
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(95, 0, "FOO")), balance(a.Address()))
+
+	// but ones closed by the expiry job lose it to the fee collector
+	id = create()
+	_, err = r.Deliver(at(1001, NewCondition(id).Permission()), db, helpers.MockTx(&ReturnEscrowMsg{EscrowId: id}))
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(94, 500000000, "FOO")), balance(a.Address()))
+	fees := modaccount.Address(modaccount.FeeCollector)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(0, 500000000, "FOO")), balance(fees))
+
+	// the deposit must be affordable as well
+	acct, err = cash.WalletWith(a.Address(), &x.Coin{Whole: 5, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, acct))
+	msg := NewCreateMsg(a, b, b, mustCombineCoins(x.NewCoin(5, 0, "FOO")), 1000, "")
+	_, err = r.Check(at(500, a), db, helpers.MockTx(msg))
+	assert.Error(t, err)
+}
+
+// TestArbitrationBids assigns the arbiter of an escrow
+// from the bids of registered arbiters
+func TestArbitrationBids(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	_, c := helpers.MakeKey()
+	_, d := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), control, NewBucket())
+	at := func(height int64, perm weave.Permission) weave.Context {
+		ctx := weave.WithHeight(context.Background(), height)
+		return authenticator().SetPermissions(ctx, perm)
+	}
+
+	db := store.MemStore()
+	acct, err := cash.WalletWith(a.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, acct))
+	// b is an arbiter, but also the recipient
+	for _, perm := range []weave.Permission{b, c, d} {
+		require.NoError(t, rbac.NewBucket().Assign(db, perm.Address(), rbac.RoleArbiter))
+	}
+	balance := func(addr weave.Address) x.Coins {
+		coins, err := control.Balance(db, addr)
+		require.NoError(t, err)
+		return coins
+	}
+	create := func() []byte {
+		msg := NewCreateMsg(a, b, nil, mustCombineCoins(x.NewCoin(5, 0, "FOO")), 1000, "")
+		bounty := x.NewCoin(10, 0, "FOO")
+		msg.Bounty = &bounty
+		res, err := r.Deliver(at(500, a), db, helpers.MockTx(msg))
+		require.NoError(t, err)
+		return res.Data
+	}
+	bid := func(height int64, perm weave.Permission, id []byte, fee int64) error {
+		coin := x.NewCoin(fee, 0, "FOO")
+		tx := helpers.MockTx(&BidArbitrationMsg{EscrowId: id, Fee: &coin})
+		_, err := r.Check(at(height, perm), db, tx)
+		if err != nil {
+			return err
+		}
+		_, err = r.Deliver(at(height, perm), db, tx)
+		return err
+	}
+
+	id := create()
+	assert.Equal(t, mustCombineCoins(x.NewCoin(85, 0, "FOO")), balance(a.Address()))
+	// nobody can release before an arbiter is assigned
+	_, err = r.Check(at(501, b), db, helpers.MockTx(&ReleaseEscrowMsg{EscrowId: id}))
+	assert.True(t, errors.IsUnauthorizedErr(err))
+
+	// only registered arbiters that are no party may bid, up to the bounty
+	assert.True(t, errors.IsUnauthorizedErr(bid(501, a, id, 5)))
+	assert.True(t, IsInvalidBidErr(bid(501, b, id, 5)))
+	assert.True(t, IsInvalidBidErr(bid(501, c, id, 11)))
+	require.NoError(t, bid(501, c, id, 6))
+	require.NoError(t, bid(502, d, id, 7))
+	// d lowers the bid, c matches it later
+	require.NoError(t, bid(503, d, id, 3))
+	require.NoError(t, bid(504, c, id, 3))
+	bids, err := NewBidBucket().Bids(db, id)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(bids))
+
+	// only the sender assigns, the earliest lowest bid wins
+	assign := helpers.MockTx(&AssignArbiterMsg{EscrowId: id})
+	_, err = r.Check(at(505, b), db, assign)
+	assert.True(t, errors.IsUnauthorizedErr(err))
+	_, err = r.Deliver(at(505, a), db, assign)
+	require.NoError(t, err)
+	obj, err := NewBucket().Get(db, id)
+	require.NoError(t, err)
+	assert.Equal(t, d.Address(), weave.Permission(AsEscrow(obj).Arbiter).Address())
+	fee := x.NewCoin(3, 0, "FOO")
+	assert.Equal(t, &fee, AsEscrow(obj).Bounty)
+	// the rest of the bounty is refunded and the other bids dropped
+	assert.Equal(t, mustCombineCoins(x.NewCoin(92, 0, "FOO")), balance(a.Address()))
+	bids, err = NewBidBucket().Bids(db, id)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(bids))
+	assert.True(t, IsInvalidBidErr(bid(506, c, id, 1)))
+
+	// the arbiter is paid on release
+	_, err = r.Deliver(at(507, d), db, helpers.MockTx(&ReleaseEscrowMsg{EscrowId: id}))
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(5, 0, "FOO")), balance(b.Address()))
+	assert.Equal(t, mustCombineCoins(x.NewCoin(3, 0, "FOO")), balance(d.Address()))
+
+	// the sender can pick any bid, but needs one
+	id = create()
+	pick := helpers.MockTx(&AssignArbiterMsg{EscrowId: id, Bidder: c.Address()})
+	_, err = r.Check(at(510, a), db, pick)
+	assert.True(t, IsInvalidBidErr(err))
+	require.NoError(t, bid(511, c, id, 8))
+	require.NoError(t, bid(511, d, id, 2))
+	_, err = r.Deliver(at(512, a), db, pick)
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(79, 0, "FOO")), balance(a.Address()))
+
+	// and gets the bounty back on a refund
+	_, err = r.Deliver(at(513, b), db, helpers.MockTx(&ReturnEscrowMsg{EscrowId: id}))
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(92, 0, "FOO")), balance(a.Address()))
+	assert.Equal(t, 0, len(balance(c.Address())))
+}
+
 // --- cut and paste from hashlock/decorator_test.go :(
 
 // PreimageTx fulfills the HashKeyTx interface to satisfy the decorator
@@ -879,3 +1612,266 @@ var _ weave.Tx = PreimageTx{}
 func (p PreimageTx) GetPreimage() []byte {
 	return p.Preimage
 }
+
+// TestReleasePriced settles an escrow with a target value
+// at the price reported by the oracle
+func TestReleasePriced(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), namecoin.NewWalletController(bank), NewBucket())
+	ctx := authenticator().SetPermissions(weave.WithHeight(context.Background(), 500), a)
+
+	all := x.NewCoin(200, 0, "IOV")
+	target := x.NewCoin(50, 0, "USD")
+	min := x.NewCoin(0, 200000000, "USD")
+	max := x.NewCoin(1, 0, "USD")
+	cases := []struct {
+		rate *x.Coin
+		// withdraw is set on the release msg
+		withdraw x.Coins
+		isError  bool
+		// final balances of sender and recipient
+		aFinal, bFinal x.Coins
+	}{
+		// 50 USD at 0.5 is 100 IOV, the rest goes back
+		0: {&x.Coin{Fractional: 500000000, Ticker: "USD"}, nil, false,
+			mustCombineCoins(x.NewCoin(100, 0, "IOV")), mustCombineCoins(x.NewCoin(100, 0, "IOV"))},
+		// at the lower bound all is paid out
+		1: {&min, nil, false,
+			nil, mustCombineCoins(all)},
+		// no price
+		2: {nil, nil, true, nil, nil},
+		// below min
+		3: {&x.Coin{Fractional: 100000000, Ticker: "USD"}, nil, true, nil, nil},
+		// above max
+		4: {&x.Coin{Whole: 2, Ticker: "USD"}, nil, true, nil, nil},
+		// no partial release
+		5: {&max, mustCombineCoins(x.NewCoin(1, 0, "IOV")), true, nil, nil},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			if tc.rate != nil {
+				require.NoError(t, oracle.NewPriceBucket().Set(db, "IOV", *tc.rate, 400))
+			}
+			acct, err := cash.WalletWith(a.Address(), &all)
+			require.NoError(t, err)
+			require.NoError(t, bank.Save(db, acct))
+
+			msg := NewCreateMsg(a, b, a, mustCombineCoins(all), 1000, "")
+			msg.Target, msg.MinPrice, msg.MaxPrice = &target, &min, &max
+			res, err := r.Deliver(ctx, db, helpers.MockTx(msg))
+			require.NoError(t, err)
+			id := res.Data
+
+			rel := &ReleaseEscrowMsg{EscrowId: id, Amount: tc.withdraw}
+			_, err = r.Deliver(ctx, db, helpers.MockTx(rel))
+			obj, _ := NewBucket().Get(db, id)
+			if tc.isError {
+				require.Error(t, err)
+				assert.NotNil(t, obj)
+				return
+			}
+			require.NoError(t, err)
+			assert.Nil(t, obj)
+
+			for _, bal := range []struct {
+				addr     weave.Address
+				expected x.Coins
+			}{
+				{a.Address(), tc.aFinal},
+				{b.Address(), tc.bFinal},
+			} {
+				wallet, err := bank.Get(db, bal.addr)
+				require.NoError(t, err)
+				assert.Equal(t, bal.expected, cash.AsCoins(wallet))
+			}
+
+			locked, err := NewLockedBucket().Load(db)
+			require.NoError(t, err)
+			assert.Empty(t, locked)
+		})
+	}
+}
+
+// TestCreateSolvency makes sure Check rejects escrows the
+// sender can not pay, counting coins on hold as unavailable
+func TestCreateSolvency(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	ctrl := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), ctrl, NewBucket())
+	ctx := authenticator().SetPermissions(weave.WithHeight(context.Background(), 500), a)
+
+	cases := []struct {
+		held    int64
+		amount  int64
+		isError bool
+	}{
+		0: {0, 10, false},
+		1: {0, 11, true},
+		2: {4, 6, false},
+		3: {4, 7, true},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			require.NoError(t, ctrl.IssueCoins(db, a.Address(), x.NewCoin(10, 0, "FOO")))
+			if tc.held > 0 {
+				require.NoError(t, ctrl.Hold(db, a.Address(), x.NewCoin(tc.held, 0, "FOO")))
+			}
+
+			msg := NewCreateMsg(a, b, b, mustCombineCoins(x.NewCoin(tc.amount, 0, "FOO")), 1000, "")
+			_, err := r.Check(ctx, db, helpers.MockTx(msg))
+			if tc.isError {
+				assert.True(t, cash.IsInsufficientFundsErr(err), "%+v", err)
+				return
+			}
+			require.NoError(t, err)
+			_, err = r.Deliver(ctx, db, helpers.MockTx(msg))
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRevealMemo(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	_, c := helpers.MakeKey()
+	_, o := helpers.MakeKey()
+
+	ctrl := namecoin.NewController()
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), ctrl, NewBucket())
+	db := store.MemStore()
+	require.NoError(t, ctrl.IssueCoins(db, a.Address(), x.NewCoin(10, 0, "FOO")))
+
+	deliver := func(msg weave.Msg, perms ...weave.Permission) ([]byte, error) {
+		ctx := weave.WithHeight(context.Background(), 500)
+		ctx = authenticator().SetPermissions(ctx, perms...)
+		res, err := r.Deliver(ctx, db, helpers.MockTx(msg))
+		return res.Data, err
+	}
+	memo := func(id []byte) string {
+		obj, err := NewBucket().Get(db, id)
+		require.NoError(t, err)
+		return AsEscrow(obj).Memo
+	}
+
+	salt := []byte("a salt of 16+ bytes")
+	create := NewCreateMsg(a, b, c, mustCombineCoins(x.NewCoin(5, 0, "FOO")), 1000, "")
+	create.MemoHash = MemoHash(salt, "delivery by friday")
+	id, err := deliver(create, a)
+	require.NoError(t, err)
+	assert.Equal(t, "", memo(id))
+
+	// an escrow without a hash has nothing to reveal
+	plain, err := deliver(NewCreateMsg(a, b, c, mustCombineCoins(x.NewCoin(5, 0, "FOO")), 1000, ""), a)
+	require.NoError(t, err)
+	_, err = deliver(&RevealMemoMsg{EscrowId: plain, Memo: "delivery by friday", Salt: salt}, a)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+
+	// only parties can reveal, and only the committed memo
+	reveal := &RevealMemoMsg{EscrowId: id, Memo: "delivery by friday", Salt: salt}
+	_, err = deliver(reveal, o)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = deliver(&RevealMemoMsg{EscrowId: id, Memo: "delivery by monday", Salt: salt}, c)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+	_, err = deliver(&RevealMemoMsg{EscrowId: id, Memo: "delivery by friday",
+		Salt: []byte("another salt of 16+")}, c)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+
+	_, err = deliver(reveal, c)
+	require.NoError(t, err)
+	assert.Equal(t, "delivery by friday", memo(id))
+	_, err = deliver(reveal, b)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+
+	history, err := NewHistoryBucket().History(db, id)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, EventReveal, history[1].Event)
+	assert.Equal(t, c.Address(), weave.Address(history[1].Actor))
+}
+
+func TestObservers(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	_, c := helpers.MakeKey()
+	_, o := helpers.MakeKey()
+	_, p := helpers.MakeKey()
+
+	ctrl := namecoin.NewController()
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), ctrl, NewBucket())
+	db := store.MemStore()
+	require.NoError(t, ctrl.IssueCoins(db, a.Address(), x.NewCoin(10, 0, "FOO")))
+
+	deliver := func(msg weave.Msg, perms ...weave.Permission) ([]byte, error) {
+		ctx := weave.WithHeight(context.Background(), 500)
+		ctx = authenticator().SetPermissions(ctx, perms...)
+		res, err := r.Deliver(ctx, db, helpers.MockTx(msg))
+		return res.Data, err
+	}
+	observers := func(id []byte) [][]byte {
+		obj, err := NewBucket().Get(db, id)
+		require.NoError(t, err)
+		return AsEscrow(obj).Observers
+	}
+
+	create := NewCreateMsg(a, b, c, mustCombineCoins(x.NewCoin(10, 0, "FOO")), 1000, "")
+	create.Observers = [][]byte{o.Address()}
+	id, err := deliver(create, a)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{o.Address()}, observers(id))
+
+	// observers have no power over the escrow
+	_, err = deliver(&ReleaseEscrowMsg{EscrowId: id}, o)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = deliver(&UpdateEscrowObserversMsg{EscrowId: id, Add: [][]byte{p.Address()}}, o)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	// neither have the other parties on them
+	_, err = deliver(&UpdateEscrowObserversMsg{EscrowId: id, Add: [][]byte{p.Address()}}, b)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+
+	// only existing observers can be removed, new ones added
+	_, err = deliver(&UpdateEscrowObserversMsg{EscrowId: id, Remove: [][]byte{p.Address()}}, a)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+	_, err = deliver(&UpdateEscrowObserversMsg{EscrowId: id, Add: [][]byte{o.Address()}}, a)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+
+	_, err = deliver(&UpdateEscrowObserversMsg{EscrowId: id,
+		Add: [][]byte{p.Address()}, Remove: [][]byte{o.Address()}}, a)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{p.Address()}, observers(id))
+
+	// the escrow holds no more than maxObservers
+	more := make([][]byte, maxObservers)
+	for i := range more {
+		more[i] = weave.NewAddress([]byte{byte(i)})
+	}
+	_, err = deliver(&UpdateEscrowObserversMsg{EscrowId: id, Add: more}, a)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+
+	_, err = deliver(&UpdateEscrowObserversMsg{EscrowId: id, Remove: [][]byte{p.Address()}}, a)
+	require.NoError(t, err)
+	assert.Empty(t, observers(id))
+
+	history, err := NewHistoryBucket().History(db, id)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, EventObservers, history[1].Event)
+	assert.Equal(t, EventObservers, history[2].Event)
+}
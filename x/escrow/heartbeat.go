@@ -0,0 +1,350 @@
+package escrow
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/budget"
+	"github.com/iov-one/bcp-demo/tracing"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+const (
+	// BucketNameHeartbeats is where we store when dead man's
+	// switch escrows are due
+	BucketNameHeartbeats = "eschb"
+	// IndexDue is the index of heartbeats by due height
+	IndexDue = "due"
+
+	pingEscrowCost int64 = 50
+
+	// maxReleasePerBlock limits the escrows the Ticker releases
+	// at the start of one block, the rest follow in the next
+	maxReleasePerBlock = 100
+	// the Ticker takes these of the budget of the block for
+	// every escrow it releases or escalates
+	releaseTickCost  int64 = 100
+	escalateTickCost int64 = 50
+)
+
+var _ orm.CloneableData = (*Heartbeat)(nil)
+
+// Validate ensures the heartbeat is due at some height
+func (h *Heartbeat) Validate() error {
+	if h.Due <= 0 {
+		return ErrInvalidHeartbeat("due")
+	}
+	return nil
+}
+
+// Copy makes a new heartbeat with the same value
+func (h *Heartbeat) Copy() orm.CloneableData {
+	return &Heartbeat{Due: h.Due}
+}
+
+// AsHeartbeat safely extracts a Heartbeat value from the object
+func AsHeartbeat(obj orm.Object) *Heartbeat {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Heartbeat)
+}
+
+// validateHeartbeat checks the window of a dead man's switch.
+// The amount of a priced escrow is only known on release, so
+// they can't have one.
+func validateHeartbeat(e *Escrow) error {
+	switch {
+	case e.HeartbeatWindow < 0:
+		return ErrInvalidHeartbeat("window")
+	case e.HeartbeatWindow > 0 && e.Target != nil:
+		return ErrInvalidHeartbeat("priced escrow")
+	}
+	return nil
+}
+
+// HeartbeatBucket holds the heartbeats of dead man's switch
+// escrows, keyed by the escrow id. A heartbeat may outlive its
+// escrow until it is due, the Ticker removes it then.
+type HeartbeatBucket struct {
+	orm.Bucket
+	due orm.Index
+}
+
+// NewHeartbeatBucket initializes a HeartbeatBucket with default name
+func NewHeartbeatBucket() HeartbeatBucket {
+	bucket := orm.NewBucket(BucketNameHeartbeats,
+		orm.NewSimpleObj(nil, new(Heartbeat))).
+		WithIndex(IndexDue, idxDue, false)
+	return HeartbeatBucket{
+		Bucket: bucket,
+		// must match the name orm.Bucket.WithIndex uses
+		due: orm.NewIndex(BucketNameHeartbeats+"_"+IndexDue, idxDue, false, nil),
+	}
+}
+
+func idxDue(obj orm.Object) ([]byte, error) {
+	hb := AsHeartbeat(obj)
+	if hb == nil {
+		return nil, errors.ErrInternal("Can only take index of Heartbeat")
+	}
+	return timeoutKey(hb.Due), nil
+}
+
+// Beat sets the escrow with the id due window blocks
+// after height
+func (b HeartbeatBucket) Beat(db weave.KVStore, id []byte, height, window int64) error {
+	return b.Save(db, orm.NewSimpleObj(id, &Heartbeat{Due: height + window}))
+}
+
+// DueBefore returns up to limit heartbeats due before the
+// given height, the ones due first come first
+func (b HeartbeatBucket) DueBefore(db weave.ReadOnlyKVStore, height int64,
+	limit int) ([]orm.Object, error) {
+	return dueBefore(db, b.Bucket, b.due, height, limit)
+}
+
+// dueBefore returns up to limit objects of the bucket whose due
+// index is before the given height, the ones due first come first
+func dueBefore(db weave.ReadOnlyKVStore, bucket orm.Bucket, due orm.Index,
+	height int64, limit int) ([]orm.Object, error) {
+
+	if height <= 0 {
+		return nil, nil
+	}
+	start := due.IndexKey(nil)
+	end := due.IndexKey(timeoutKey(height))
+	itr := db.Iterator(start, end)
+	defer itr.Close()
+
+	var res []orm.Object
+	for ; itr.Valid() && len(res) < limit; itr.Next() {
+		var refs orm.MultiRef
+		err := refs.Unmarshal(itr.Value())
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range refs.GetRefs() {
+			obj, err := bucket.Get(db, ref)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, obj)
+			if len(res) == limit {
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+//---- ping
+
+// PingEscrowHandler moves the release of a dead man's switch
+type PingEscrowHandler struct {
+	auth       x.Authenticator
+	bucket     Store
+	heartbeats HeartbeatBucket
+	history    HistoryBucket
+}
+
+var _ weave.Handler = PingEscrowHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h PingEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += pingEscrowCost
+	return res, nil
+}
+
+// Deliver sets the escrow due a full window from now
+func (h PingEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	height, _ := weave.GetHeight(ctx)
+	err = h.heartbeats.Beat(db, obj.Key(), height, AsEscrow(obj).HeartbeatWindow)
+	if err != nil {
+		return res, err
+	}
+	return res, h.history.Append(ctx, db, h.auth, obj.Key(), EventPing, nil)
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h PingEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (orm.Object, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*PingEscrowMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := h.bucket.Get(db, msg.EscrowId)
+	if err != nil {
+		return nil, err
+	}
+	escrow := AsEscrow(obj)
+	if escrow == nil {
+		return nil, ErrNoSuchEscrow(msg.EscrowId)
+	}
+	if err := checkOpen(obj.Key(), escrow); err != nil {
+		return nil, err
+	}
+	if escrow.HeartbeatWindow == 0 {
+		return nil, ErrInvalidHeartbeat("not a dead man's switch")
+	}
+
+	sender := weave.Permission(escrow.Sender).Address()
+	if !h.auth.HasAddress(ctx, sender) {
+		return nil, errors.ErrUnauthorized()
+	}
+
+	// once expired it can only be returned
+	height, _ := weave.GetHeight(ctx)
+	if escrow.Timeout < height {
+		return nil, ErrEscrowExpired(escrow.Timeout)
+	}
+	return obj, nil
+}
+
+//---- release on missed heartbeat
+
+// Ticker releases the dead man's switch escrows whose sender
+// missed the heartbeat, in full to the recipient, and hands the
+// disputes the arbiter left open past the sla to the backup arbiter
+type Ticker struct {
+	bucket      Bucket
+	heartbeats  HeartbeatBucket
+	escalations EscalationBucket
+	history     HistoryBucket
+	bids        BidBucket
+	cash        namecoin.Controller
+}
+
+var _ weave.Ticker = Ticker{}
+
+// NewTicker creates a Ticker moving coins with control
+func NewTicker(control namecoin.Controller) Ticker {
+	return Ticker{
+		bucket:      NewBucket(),
+		heartbeats:  NewHeartbeatBucket(),
+		escalations: NewEscalationBucket(),
+		history:     NewHistoryBucket(),
+		bids:        NewBidBucket(),
+		cash:        control,
+	}
+}
+
+// Tick releases up to maxReleasePerBlock escrows, the ones due
+// first come first. Escrows that are closed, expired or
+// quarantined are skipped, only their heartbeat is removed.
+// Then it escalates as many disputes, skipping the same and
+// the settled ones. It stops early once the budget of the
+// block is used up, what is left is due in the next block.
+func (t Ticker) Tick(ctx weave.Context, db weave.KVStore) (weave.TickResult, error) {
+	var res weave.TickResult
+	height, _ := weave.GetHeight(ctx)
+	due, err := t.heartbeats.DueBefore(db, height, maxReleasePerBlock)
+	if err != nil {
+		return res, err
+	}
+	for _, hb := range due {
+		// the rest is released in the next block
+		if !budget.Take(ctx, releaseTickCost) {
+			break
+		}
+		err := t.heartbeats.Delete(db, hb.Key())
+		if err != nil {
+			return res, err
+		}
+		obj, err := t.bucket.Get(db, hb.Key())
+		if err != nil {
+			return res, err
+		}
+		escrow := AsEscrow(obj)
+		if escrow == nil || escrow.Timeout < height || escrow.Quarantine != nil {
+			continue
+		}
+		err = t.release(ctx, db, obj)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	due, err = t.escalations.DueBefore(db, height, maxReleasePerBlock)
+	if err != nil {
+		return res, err
+	}
+	for _, e := range due {
+		if !budget.Take(ctx, escalateTickCost) {
+			break
+		}
+		err := t.escalations.Delete(db, e.Key())
+		if err != nil {
+			return res, err
+		}
+		obj, err := t.bucket.Get(db, e.Key())
+		if err != nil {
+			return res, err
+		}
+		escrow := AsEscrow(obj)
+		if escrow == nil || escrow.Timeout < height || escrow.Quarantine != nil ||
+			escrow.Dispute == nil || escrow.BackupArbiter == nil {
+			continue
+		}
+		err = t.escalate(ctx, db, obj)
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// release pays all the escrow holds to the recipient and
+// closes it, as a full release by the arbiter would
+func (t Ticker) release(ctx weave.Context, db weave.KVStore, obj orm.Object) error {
+	escrow := AsEscrow(obj)
+	src := NewCondition(obj.Key()).Address()
+	transfers := payTransfers(escrow, src, escrow.Amount)
+	transfers = append(transfers,
+		depositTransfers(obj, weave.Permission(escrow.Sender).Address())...)
+	transfers = append(transfers, bountyTransfers(obj, true)...)
+	err := t.cash.WithContext(ctx).MoveCoinsBatch(db, transfers)
+	if err != nil {
+		return err
+	}
+	// no one signs for the ticker
+	err = t.history.Append(ctx, db, x.ChainAuth(), obj.Key(), EventRelease, escrow.Amount)
+	if err != nil {
+		return err
+	}
+	err = deleteBids(db, t.bids, obj)
+	if err != nil {
+		return err
+	}
+	return t.bucket.Delete(db, obj.Key())
+}
@@ -0,0 +1,119 @@
+package escrow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// TestDeadManSwitch releases an escrow to the recipient once
+// the sender stops pinging it
+func TestDeadManSwitch(t *testing.T) {
+	var helpers x.TestHelpers
+	_, sender := helpers.MakeKey()
+	_, arbiter := helpers.MakeKey()
+	_, rcpt := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), control, NewBucket())
+	ticker := NewTicker(control)
+
+	db := store.MemStore()
+	wallet, err := cash.WalletWith(sender.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, wallet))
+	deliver := func(height int64, msg weave.Msg, perm weave.Permission) ([]byte, error) {
+		ctx := weave.WithHeight(context.Background(), height)
+		ctx = authenticator().SetPermissions(ctx, perm)
+		tx := helpers.MockTx(msg)
+		_, err := r.Check(ctx, db, tx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := r.Deliver(ctx, db, tx)
+		return res.Data, err
+	}
+	tick := func(height int64) {
+		_, err := ticker.Tick(weave.WithHeight(context.Background(), height), db)
+		require.NoError(t, err)
+	}
+	balance := func(addr weave.Address) x.Coins {
+		coins, err := control.Balance(db, addr)
+		require.NoError(t, err)
+		return coins
+	}
+	create := func(window int64, amount int64) []byte {
+		msg := NewCreateMsg(sender, rcpt, arbiter,
+			mustCombineCoins(x.NewCoin(amount, 0, "FOO")), 1000, "will")
+		msg.HeartbeatWindow = window
+		id, err := deliver(10, msg, sender)
+		require.NoError(t, err)
+		return id
+	}
+
+	will := create(100, 30)
+	plain := create(0, 10)
+
+	// only the sender pings, and only a dead man's switch
+	_, err = deliver(50, &PingEscrowMsg{EscrowId: will}, rcpt)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = deliver(50, &PingEscrowMsg{EscrowId: plain}, sender)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+
+	// due at 110, moved to 150 by the ping
+	_, err = deliver(50, &PingEscrowMsg{EscrowId: will}, sender)
+	require.NoError(t, err)
+	tick(120)
+	assert.Nil(t, balance(rcpt.Address()))
+
+	tick(151)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(30, 0, "FOO")), balance(rcpt.Address()))
+	obj, err := NewBucket().Get(db, will)
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+	history, err := NewHistoryBucket().History(db, will)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, EventPing, history[1].Event)
+	assert.Equal(t, EventRelease, history[2].Event)
+	assert.Nil(t, history[2].Actor)
+
+	// a closed escrow just drops its heartbeat
+	early := create(100, 5)
+	_, err = deliver(20, &ReleaseEscrowMsg{EscrowId: early}, arbiter)
+	require.NoError(t, err)
+	tick(200)
+	due, err := NewHeartbeatBucket().DueBefore(db, 1000, 10)
+	require.NoError(t, err)
+	assert.Len(t, due, 0)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(35, 0, "FOO")), balance(rcpt.Address()))
+}
+
+func TestHeartbeatTerms(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	plus := mustCombineCoins(x.NewCoin(5, 0, "FOO"))
+
+	msg := NewCreateMsg(a, b, b, plus, 100, "")
+	msg.HeartbeatWindow = -1
+	assert.True(t, IsInvalidMetadataErr(msg.Validate()))
+
+	msg.HeartbeatWindow = 10
+	assert.NoError(t, msg.Validate())
+	msg.Target = &x.Coin{Whole: 50, Ticker: "USD"}
+	assert.True(t, IsInvalidMetadataErr(msg.Validate()))
+}
@@ -0,0 +1,137 @@
+package escrow
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	paged "github.com/iov-one/bcp-demo/query"
+)
+
+const (
+	// BucketNameHistory is where we store the escrow history
+	BucketNameHistory = "eschist"
+	// QueryHistory is the path of the HistoryQuery
+	QueryHistory = "/escrows/history"
+
+	historySeq = "seq"
+)
+
+var _ orm.CloneableData = (*HistoryEntry)(nil)
+
+// Validate ensures the entry is complete
+func (h *HistoryEntry) Validate() error {
+	if h.Event == "" {
+		return ErrInvalidEvent(h.Event)
+	}
+	return x.Coins(h.Amount).Validate()
+}
+
+// Copy makes a new entry with the same values
+func (h *HistoryEntry) Copy() orm.CloneableData {
+	return &HistoryEntry{
+		Event:  h.Event,
+		Height: h.Height,
+		Actor:  h.Actor,
+		Amount: x.Coins(h.Amount).Clone(),
+		Note:   h.Note,
+	}
+}
+
+// HistoryBucket is an append-only log of all escrow events.
+// Keys are the escrow id followed by a sequence, so a prefix
+// scan on the id returns the events of one escrow in order.
+type HistoryBucket struct {
+	orm.Bucket
+	seq orm.Sequence
+}
+
+// NewHistoryBucket initializes a HistoryBucket with default name
+func NewHistoryBucket() HistoryBucket {
+	bucket := orm.NewBucket(BucketNameHistory,
+		orm.NewSimpleObj(nil, new(HistoryEntry)))
+	return HistoryBucket{
+		Bucket: bucket,
+		seq:    bucket.Sequence(historySeq),
+	}
+}
+
+// Append records an event of the escrow with the given id.
+// The amount is normalized, so it may hold a ticker twice.
+func (b HistoryBucket) Append(ctx weave.Context, db weave.KVStore, auth x.Authenticator,
+	id []byte, event string, amount x.Coins) error {
+	return b.AppendNote(ctx, db, auth, id, event, amount, "")
+}
+
+// AppendNote records an event along with a note, eg. the
+// reason an admin gave for it
+func (b HistoryBucket) AppendNote(ctx weave.Context, db weave.KVStore, auth x.Authenticator,
+	id []byte, event string, amount x.Coins, note string) error {
+
+	var total x.Coins
+	for _, c := range amount {
+		var err error
+		total, err = total.Add(*c)
+		if err != nil {
+			return err
+		}
+	}
+	height, _ := weave.GetHeight(ctx)
+	entry := &HistoryEntry{
+		Event:  event,
+		Height: height,
+		Amount: total,
+		Note:   note,
+	}
+	if signer := x.MainSigner(ctx, auth); signer != nil {
+		entry.Actor = signer.Address()
+	}
+	key := append(append([]byte{}, id...), b.seq.NextVal(db)...)
+	err := paged.Save(db, b.Bucket, orm.NewSimpleObj(key, entry))
+	if err != nil {
+		return err
+	}
+	// the events of each escrow are counted for the paged query
+	paged.NewCounter(b.DBKey(id)).Add(db, 1)
+	return nil
+}
+
+// History returns all events of the escrow, oldest first
+func (b HistoryBucket) History(db weave.ReadOnlyKVStore, id []byte) ([]*HistoryEntry, error) {
+	models, err := b.Query(db, weave.PrefixQueryMod, id)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*HistoryEntry, len(models))
+	for i, m := range models {
+		obj, err := b.Parse(m.Key, m.Value)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = obj.Value().(*HistoryEntry)
+	}
+	return res, nil
+}
+
+// HistoryQuery returns all events of one escrow, with the escrow
+// id as data and no modifier
+type HistoryQuery struct {
+	bucket HistoryBucket
+}
+
+var _ weave.QueryHandler = HistoryQuery{}
+
+// NewHistoryQuery creates a query handler for the given bucket
+func NewHistoryQuery(bucket HistoryBucket) HistoryQuery {
+	return HistoryQuery{bucket: bucket}
+}
+
+// Query implements weave.QueryHandler
+func (q HistoryQuery) Query(db weave.ReadOnlyKVStore, mod string,
+	data []byte) ([]weave.Model, error) {
+
+	if mod != weave.KeyQueryMod || len(data) == 0 {
+		return nil, ErrInvalidQuery(mod)
+	}
+	return q.bucket.Query(db, weave.PrefixQueryMod, data)
+}
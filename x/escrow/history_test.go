@@ -0,0 +1,91 @@
+package escrow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestHistoryQuery(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	_, c := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), namecoin.NewWalletController(bank), NewBucket())
+	qr := weave.NewQueryRouter()
+	RegisterQuery(qr)
+
+	db := store.MemStore()
+	acct, err := cash.WalletWith(a.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, acct))
+
+	deliver := func(height int64, msg weave.Msg, perms ...weave.Permission) []byte {
+		ctx := weave.WithHeight(context.Background(), height)
+		ctx = authenticator().SetPermissions(ctx, perms...)
+		res, err := r.Deliver(ctx, db, helpers.MockTx(msg))
+		require.NoError(t, err)
+		return res.Data
+	}
+
+	// a second escrow, to make sure we only get the events of one
+	other := deliver(5, NewCreateMsg(a, b, a, mustCombineCoins(x.NewCoin(10, 0, "FOO")), 1000, ""), a)
+	id := deliver(10, NewCreateMsg(a, b, a, mustCombineCoins(x.NewCoin(50, 0, "FOO")), 1000, ""), a)
+	deliver(20, &ReleaseEscrowMsg{EscrowId: id,
+		Amount: mustCombineCoins(x.NewCoin(20, 0, "FOO"))}, a)
+	deliver(30, &UpdateEscrowPartiesMsg{EscrowId: id, Arbiter: c}, a)
+	deliver(35, &AcceptEscrowPartyMsg{EscrowId: id, Role: RoleArbiter}, c)
+	deliver(40, &ReturnEscrowMsg{EscrowId: id}, b)
+
+	h := qr.Handler(QueryHistory)
+	require.NotNil(t, h)
+	models, err := h.Query(db, "", id)
+	require.NoError(t, err)
+
+	expected := []HistoryEntry{
+		{Event: EventCreate, Height: 10, Actor: a.Address(),
+			Amount: mustCombineCoins(x.NewCoin(50, 0, "FOO"))},
+		{Event: EventRelease, Height: 20, Actor: a.Address(),
+			Amount: mustCombineCoins(x.NewCoin(20, 0, "FOO"))},
+		{Event: EventOffer, Height: 30, Actor: a.Address()},
+		{Event: EventUpdate, Height: 35, Actor: c.Address()},
+		{Event: EventRefund, Height: 40, Actor: b.Address(),
+			Amount: mustCombineCoins(x.NewCoin(30, 0, "FOO"))},
+	}
+	bucket := NewHistoryBucket()
+	require.Equal(t, len(expected), len(models))
+	for i, ex := range expected {
+		obj, err := bucket.Parse(nil, models[i].Value)
+		require.NoError(t, err)
+		got := obj.Value().(*HistoryEntry)
+		assert.Equal(t, ex.Event, got.Event, "%d", i)
+		assert.Equal(t, ex.Height, got.Height, "%d", i)
+		assert.Equal(t, []byte(ex.Actor), got.Actor, "%d", i)
+		assert.Equal(t, ex.Amount, got.Amount, "%d", i)
+	}
+
+	// the other escrow has its own history
+	entries, err := bucket.History(db, other)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(entries))
+	assert.Equal(t, EventCreate, entries[0].Event)
+
+	// bad queries
+	_, err = h.Query(db, "", nil)
+	assert.True(t, IsInvalidQueryErr(err))
+	_, err = h.Query(db, weave.PrefixQueryMod, id)
+	assert.True(t, IsInvalidQueryErr(err))
+}
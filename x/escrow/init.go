@@ -0,0 +1,205 @@
+package escrow
+
+import (
+	"encoding/json"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+
+	"github.com/iov-one/bcp-demo/ordered"
+	"github.com/iov-one/bcp-demo/x/modaccount"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/scheduler"
+)
+
+const (
+	optEscrow    = "escrow"
+	optParams    = "escrow_params"
+	optTemplates = "escrow_templates"
+)
+
+// Initializer fulfils the InitStater interface to load data from
+// the genesis file
+type Initializer struct {
+	// Minter is used to fund the escrow accounts, its MoveHooks
+	// count the coins in the total value locked
+	Minter namecoin.Controller
+}
+
+var _ weave.Initializer = Initializer{}
+
+// NewInitializer creates an Initializer that issues the escrowed
+// coins using the given controller
+func NewInitializer(minter namecoin.Controller) Initializer {
+	return Initializer{Minter: minter}
+}
+
+// FromGenesis will parse initial escrows from genesis,
+// save them to the database and issue the escrowed amount,
+// deposit and bounty to the escrow address. Migrated escrows
+// are created the same way, and can be found by their legacy
+// id in the AliasBucket. It also stores the module params
+// and the templates.
+func (i Initializer) FromGenesis(opts weave.Options, db weave.KVStore) error {
+	escrows := []*Escrow{}
+	err := opts.ReadOptions(optEscrow, &escrows)
+	if err != nil {
+		return err
+	}
+	var params *Params
+	err = opts.ReadOptions(optParams, &params)
+	if err != nil {
+		return err
+	}
+	if params != nil {
+		err = NewParamsBucket().Store(db, params)
+		if err != nil {
+			return err
+		}
+	}
+	err = loadTemplates(opts, db)
+	if err != nil {
+		return err
+	}
+
+	for _, esc := range escrows {
+		_, err := i.create(db, esc)
+		if err != nil {
+			return err
+		}
+	}
+
+	var migrated []MigratedEscrow
+	err = opts.ReadOptions(optMigrated, &migrated)
+	if err != nil {
+		return err
+	}
+	aliases := NewAliasBucket()
+	for _, m := range migrated {
+		if m.Escrow == nil {
+			return ErrNoSuchEscrow(m.LegacyID)
+		}
+		obj, err := i.create(db, m.Escrow)
+		if err != nil {
+			return err
+		}
+		err = aliases.Add(db, m.LegacyID, obj.Key())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTemplates stores the templates from genesis by id
+func loadTemplates(opts weave.Options, db weave.KVStore) error {
+	var templates map[string]*EscrowTemplate
+	err := opts.ReadOptions(optTemplates, &templates)
+	if err != nil {
+		return err
+	}
+	bucket := NewTemplateBucket()
+	for _, id := range ordered.Keys(templates) {
+		t := templates[id]
+		if !isTemplateID(id) || t == nil {
+			return ErrInvalidTemplate(id)
+		}
+		if err := t.Validate(); err != nil {
+			return err
+		}
+		err := bucket.Save(db, orm.NewSimpleObj([]byte(id), t))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// create stores the escrow under the next id and issues the
+// amount, deposit and bounty to its account
+func (i Initializer) create(db weave.KVStore, esc *Escrow) (orm.Object, error) {
+	obj, err := NewBucket().Create(db, esc)
+	if err != nil {
+		return nil, err
+	}
+	// dead man's switches and disputes start counting at genesis,
+	// the escrows expire at their timeout as usual
+	if esc.HeartbeatWindow > 0 {
+		err = NewHeartbeatBucket().Beat(db, obj.Key(), 0, esc.HeartbeatWindow)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if esc.Dispute != nil && esc.SlaBlocks > 0 {
+		err = NewEscalationBucket().Schedule(db, obj.Key(), esc.SlaBlocks)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = scheduleExpiry(db, scheduler.NewBucket(), obj)
+	if err != nil {
+		return nil, err
+	}
+	dest, err := modaccount.NewBucket().Open(db, Account, obj.Key())
+	if err != nil {
+		return nil, err
+	}
+	held, err := heldCoins(esc)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range held {
+		err := i.Minter.IssueCoins(db, dest, *c)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return obj, nil
+}
+
+// BuildGenesis will create Options with the given escrows
+func BuildGenesis(escrows []*Escrow) (weave.Options, error) {
+	opts := make(weave.Options, 1)
+	if len(escrows) > 0 {
+		bz, err := json.MarshalIndent(escrows, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		opts[optEscrow] = bz
+	}
+	return opts, nil
+}
+
+// BuildParamsGenesis will create Options with the given params
+func BuildParamsGenesis(params *Params) (weave.Options, error) {
+	bz, err := json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return weave.Options{optParams: bz}, nil
+}
+
+// BuildTemplatesGenesis will create Options with the given
+// templates by id, eg. DefaultTemplates
+func BuildTemplatesGenesis(templates map[string]*EscrowTemplate) (weave.Options, error) {
+	bz, err := json.MarshalIndent(templates, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return weave.Options{optTemplates: bz}, nil
+}
+
+// AppendGenesis adds escrows to the ones already in opts
+func AppendGenesis(opts weave.Options, escrows ...*Escrow) error {
+	var all []*Escrow
+	err := opts.ReadOptions(optEscrow, &all)
+	if err != nil {
+		return err
+	}
+	bz, err := json.MarshalIndent(append(all, escrows...), "", "  ")
+	if err != nil {
+		return err
+	}
+	opts[optEscrow] = bz
+	return nil
+}
@@ -0,0 +1,120 @@
+package escrow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/modaccount"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestInitState(t *testing.T) {
+	var helpers x.TestHelpers
+
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	_, c := helpers.MakeKey()
+
+	all := mustCombineCoins(x.NewCoin(100, 0, "FOO"))
+	some := mustCombineCoins(x.NewCoin(32, 500, "BAR"))
+
+	esc1 := &Escrow{Sender: a, Recipient: b, Arbiter: c, Amount: all, Timeout: 500}
+	esc2 := &Escrow{Sender: b, Recipient: c, Arbiter: a, Amount: some, Timeout: 12, Memo: "hi"}
+	opts, err := BuildGenesis([]*Escrow{esc1, esc2})
+	require.NoError(t, err)
+
+	// missing timeout
+	bad := &Escrow{Sender: a, Recipient: b, Arbiter: c, Amount: all}
+	optsBad, err := BuildGenesis([]*Escrow{bad})
+	require.NoError(t, err)
+
+	params := &Params{DustThreshold: mustCombineCoins(x.NewCoin(0, 10, "FOO"))}
+	paramOpts, err := BuildParamsGenesis(params)
+	require.NoError(t, err)
+
+	id := func(i int64) []byte {
+		bz := make([]byte, 8)
+		binary.BigEndian.PutUint64(bz, uint64(i))
+		return bz
+	}
+
+	cases := []struct {
+		opts    weave.Options
+		isError bool
+		escrows []*Escrow
+	}{
+		// nothing set, no error
+		0: {weave.Options{}, false, nil},
+		// not a list
+		1: {weave.Options{optEscrow: []byte(`{"timeout": 5}`)}, true, nil},
+		// hand-built, should pass
+		2: {opts, false, []*Escrow{esc1, esc2}},
+		// invalid escrow
+		3: {optsBad, true, nil},
+		// params are stored
+		4: {paramOpts, false, nil},
+		// invalid params
+		5: {weave.Options{optParams: []byte(`{"dust_threshold": [{"whole": -3, "ticker": "FOO"}]}`)}, true, nil},
+	}
+
+	bank := cash.NewBucket()
+	init := NewInitializer(namecoin.NewWalletController(bank))
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			kv := store.MemStore()
+			err := init.FromGenesis(tc.opts, kv)
+			if tc.isError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			stored, err := NewParamsBucket().Load(kv)
+			require.NoError(t, err)
+			if _, ok := tc.opts[optParams]; ok {
+				assert.Equal(t, params, stored)
+			} else {
+				assert.Equal(t, new(Params), stored)
+			}
+
+			var total x.Coins
+			for _, esc := range tc.escrows {
+				total, err = total.Combine(esc.Amount)
+				require.NoError(t, err)
+			}
+			locked, err := NewLockedBucket().Load(kv)
+			require.NoError(t, err)
+			assert.Equal(t, total, locked)
+
+			bucket := NewBucket()
+			for j, expected := range tc.escrows {
+				key := id(int64(j + 1))
+				obj, err := bucket.Get(kv, key)
+				require.NoError(t, err)
+				if assert.NotNil(t, obj) {
+					assert.EqualValues(t, expected, AsEscrow(obj))
+				}
+
+				// the escrow address is a module account
+				module, err := modaccount.NewBucket().Module(kv, Permission(key).Address())
+				require.NoError(t, err)
+				assert.Equal(t, "escrow", module)
+
+				// make sure the escrow is funded
+				wallet, err := bank.Get(kv, Permission(key).Address())
+				require.NoError(t, err)
+				assert.Equal(t, x.Coins(expected.Amount), cash.AsCoins(wallet))
+			}
+		})
+	}
+}
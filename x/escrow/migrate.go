@@ -0,0 +1,233 @@
+package escrow
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+)
+
+const (
+	// BucketNameAlias is where we store the ids of migrated escrows
+	BucketNameAlias = "escalias"
+	// QueryAlias is the path of the AliasQuery
+	QueryAlias = "/escrows/alias"
+
+	optMigrated = "escrow_migrated"
+)
+
+var _ orm.CloneableData = (*Alias)(nil)
+
+// Validate ensures the alias points to an escrow id
+func (a *Alias) Validate() error {
+	if len(a.Id) != 8 {
+		return ErrInvalidEscrowID(a.Id)
+	}
+	return nil
+}
+
+// Copy makes a new alias with the same values
+func (a *Alias) Copy() orm.CloneableData {
+	return &Alias{Id: a.Id}
+}
+
+// AliasBucket maps the ids escrows had on a prior chain
+// to their ids on this one
+type AliasBucket struct {
+	orm.Bucket
+}
+
+// NewAliasBucket initializes an AliasBucket with default name
+func NewAliasBucket() AliasBucket {
+	return AliasBucket{
+		Bucket: orm.NewBucket(BucketNameAlias,
+			orm.NewSimpleObj(nil, new(Alias))),
+	}
+}
+
+// Add records the id of a migrated escrow. Every legacy
+// id can only be added once.
+func (b AliasBucket) Add(db weave.KVStore, legacy, id []byte) error {
+	obj, err := b.Get(db, legacy)
+	if err != nil {
+		return err
+	}
+	if obj != nil {
+		return ErrInvalidEscrowID(legacy)
+	}
+	return b.Save(db, orm.NewSimpleObj(legacy, &Alias{Id: id}))
+}
+
+// Resolve returns the id of the escrow that had the legacy
+// id on the prior chain, nil if there is none
+func (b AliasBucket) Resolve(db weave.ReadOnlyKVStore, legacy []byte) ([]byte, error) {
+	obj, err := b.Get(db, legacy)
+	if err != nil || obj == nil || obj.Value() == nil {
+		return nil, err
+	}
+	return obj.Value().(*Alias).Id, nil
+}
+
+// AliasQuery returns the escrow with the legacy id given as
+// data and no modifier. The result is empty if there is none,
+// or the escrow was closed since.
+type AliasQuery struct {
+	aliases AliasBucket
+	bucket  Bucket
+}
+
+var _ weave.QueryHandler = AliasQuery{}
+
+// Query implements weave.QueryHandler
+func (q AliasQuery) Query(db weave.ReadOnlyKVStore, mod string,
+	data []byte) ([]weave.Model, error) {
+
+	if mod != weave.KeyQueryMod || len(data) == 0 {
+		return nil, ErrInvalidQuery(mod)
+	}
+	id, err := q.aliases.Resolve(db, data)
+	if err != nil || id == nil {
+		return nil, err
+	}
+	return q.bucket.Query(db, weave.KeyQueryMod, id)
+}
+
+// MigratedEscrow is an escrow of a prior chain along with
+// the id it had there, as listed in genesis
+type MigratedEscrow struct {
+	LegacyID []byte  `json:"legacy_id"`
+	Escrow   *Escrow `json:"escrow"`
+}
+
+// Migrate prepares the escrows exported from a prior chain for
+// the genesis of a new one. offset is added to every timeout,
+// usually minus the height of the prior chain at export.
+//
+// The exports are checked like an import, all escrows must be
+// valid and their balance match. It fails if an escrow would
+// time out before the new chain starts or an id is listed twice.
+func Migrate(exports []*EscrowExport, offset int64) ([]MigratedEscrow, error) {
+	seen := make(map[string]bool, len(exports))
+	res := make([]MigratedEscrow, len(exports))
+	for i, export := range exports {
+		if export == nil {
+			return nil, fmt.Errorf("entry %d is empty", i)
+		}
+		err := export.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("escrow %X: %v", export.Id, err)
+		}
+		if seen[string(export.Id)] {
+			return nil, fmt.Errorf("escrow %X listed twice", export.Id)
+		}
+		seen[string(export.Id)] = true
+
+		esc := export.Escrow.Copy().(*Escrow)
+		esc.Timeout += offset
+		if esc.Timeout <= 0 {
+			return nil, fmt.Errorf("escrow %X times out before the new chain starts (%d)",
+				export.Id, esc.Timeout)
+		}
+		res[i] = MigratedEscrow{LegacyID: export.Id, Escrow: esc}
+	}
+	return res, nil
+}
+
+// AppendMigratedGenesis adds migrated escrows to the ones
+// already in opts
+func AppendMigratedGenesis(opts weave.Options, escrows ...MigratedEscrow) error {
+	var all []MigratedEscrow
+	err := opts.ReadOptions(optMigrated, &all)
+	if err != nil {
+		return err
+	}
+	bz, err := json.MarshalIndent(append(all, escrows...), "", "  ")
+	if err != nil {
+		return err
+	}
+	opts[optMigrated] = bz
+	return nil
+}
+
+// RewriteGenesis replaces the parties of every escrow in opts,
+// plain and migrated, with the permissions returned by perm, and
+// the observers with the addresses returned by addr. Both also get
+// a description of the value for reporting. An escrow without an
+// arbiter keeps none.
+func RewriteGenesis(opts weave.Options,
+	perm func(where string, p weave.Permission) (weave.Permission, error),
+	addr func(where string, a weave.Address) (weave.Address, error)) error {
+
+	rewrite := func(name string, esc *Escrow) error {
+		parties := []struct {
+			role string
+			p    *[]byte
+		}{
+			{"sender", &esc.Sender},
+			{"arbiter", &esc.Arbiter},
+			{"recipient", &esc.Recipient},
+			{"clawback", &esc.Clawback},
+			{"attester", &esc.Attester},
+			{"backup arbiter", &esc.BackupArbiter},
+		}
+		for _, party := range parties {
+			if len(*party.p) == 0 {
+				continue
+			}
+			p, err := perm(fmt.Sprintf("%s %s", name, party.role), *party.p)
+			if err != nil {
+				return err
+			}
+			*party.p = p
+		}
+		for i, o := range esc.Observers {
+			a, err := addr(fmt.Sprintf("%s observer", name), o)
+			if err != nil {
+				return err
+			}
+			esc.Observers[i] = a
+		}
+		return esc.Validate()
+	}
+
+	var escrows []*Escrow
+	err := opts.ReadOptions(optEscrow, &escrows)
+	if err != nil {
+		return err
+	}
+	for i, esc := range escrows {
+		if err := rewrite(fmt.Sprintf("escrow #%d", i+1), esc); err != nil {
+			return err
+		}
+	}
+	var migrated []MigratedEscrow
+	err = opts.ReadOptions(optMigrated, &migrated)
+	if err != nil {
+		return err
+	}
+	for _, m := range migrated {
+		if m.Escrow == nil {
+			return ErrNoSuchEscrow(m.LegacyID)
+		}
+		if err := rewrite(fmt.Sprintf("escrow %X", m.LegacyID), m.Escrow); err != nil {
+			return err
+		}
+	}
+
+	if escrows != nil {
+		bz, err := json.MarshalIndent(escrows, "", "  ")
+		if err != nil {
+			return err
+		}
+		opts[optEscrow] = bz
+	}
+	if migrated != nil {
+		bz, err := json.MarshalIndent(migrated, "", "  ")
+		if err != nil {
+			return err
+		}
+		opts[optMigrated] = bz
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package escrow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestMigrate(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	amount := mustCombineCoins(x.NewCoin(10, 0, "FOO"))
+	bounty := x.NewCoin(1, 0, "FOO")
+
+	export := func(seq uint64, timeout int64) *EscrowExport {
+		esc := &Escrow{Sender: a, Recipient: b, Arbiter: a,
+			Amount: amount, Timeout: timeout, Bounty: &bounty}
+		held, err := heldCoins(esc)
+		require.NoError(t, err)
+		return &EscrowExport{Id: SeqCondition(seq), Escrow: esc, Balance: held}
+	}
+
+	exports := []*EscrowExport{export(7, 5000), export(3, 2100)}
+	migrated, err := Migrate(exports, -2000)
+	require.NoError(t, err)
+	require.Len(t, migrated, 2)
+	assert.Equal(t, exports[0].Id, migrated[0].LegacyID)
+	assert.Equal(t, int64(3000), migrated[0].Escrow.Timeout)
+	assert.Equal(t, int64(100), migrated[1].Escrow.Timeout)
+	// the exports are unchanged
+	assert.Equal(t, int64(5000), exports[0].Escrow.Timeout)
+
+	// expired
+	_, err = Migrate(exports, -2100)
+	assert.Error(t, err)
+	// listed twice
+	_, err = Migrate([]*EscrowExport{export(7, 5000), export(7, 6000)}, 0)
+	assert.Error(t, err)
+	// balance does not match
+	bad := export(8, 5000)
+	bad.Balance = amount
+	_, err = Migrate([]*EscrowExport{bad}, 0)
+	assert.Error(t, err)
+	_, err = Migrate([]*EscrowExport{nil}, 0)
+	assert.Error(t, err)
+
+	// genesis has one plain escrow, then the migrated ones
+	opts, err := BuildGenesis([]*Escrow{exports[0].Escrow})
+	require.NoError(t, err)
+	require.NoError(t, AppendMigratedGenesis(opts, migrated[0]))
+	require.NoError(t, AppendMigratedGenesis(opts, migrated[1]))
+
+	db := store.MemStore()
+	ctrl := namecoin.NewController()
+	require.NoError(t, NewInitializer(ctrl).FromGenesis(opts, db))
+
+	aliases := NewAliasBucket()
+	for i, legacy := range []uint64{7, 3} {
+		id, err := aliases.Resolve(db, SeqCondition(legacy))
+		require.NoError(t, err)
+		assert.Equal(t, []byte(SeqCondition(uint64(i+2))), id)
+		imported, err := Export(db, NewBucket(), ctrl, id)
+		require.NoError(t, err)
+		assert.NoError(t, imported.Validate())
+		assert.Equal(t, migrated[i].Escrow, imported.Escrow)
+	}
+	id, err := aliases.Resolve(db, SeqCondition(1))
+	require.NoError(t, err)
+	assert.Nil(t, id)
+
+	// a legacy id can only be used once
+	err = NewInitializer(ctrl).FromGenesis(opts, db)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+
+	// the query returns the escrow under its new id
+	qr := weave.NewQueryRouter()
+	RegisterQuery(qr)
+	h := qr.Handler(QueryAlias)
+	require.NotNil(t, h)
+	models, err := h.Query(db, weave.KeyQueryMod, SeqCondition(3))
+	require.NoError(t, err)
+	require.Len(t, models, 1)
+	assert.Equal(t, NewBucket().DBKey(SeqCondition(3)), models[0].Key)
+	models, err = h.Query(db, weave.KeyQueryMod, SeqCondition(1))
+	require.NoError(t, err)
+	assert.Empty(t, models)
+	_, err = h.Query(db, weave.PrefixQueryMod, SeqCondition(3))
+	assert.True(t, IsInvalidQueryErr(err), "%+v", err)
+}
@@ -0,0 +1,207 @@
+package escrow
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/tracing"
+	"github.com/iov-one/bcp-demo/x/ledger"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+const (
+	// maxMilestones limits the tranches of one escrow
+	maxMilestones = 16
+	// maxMilestoneName is the longest name of a milestone
+	maxMilestoneName = 64
+)
+
+// validateMilestones checks the tranches of a milestone escrow.
+// The pending ones must add up to the amount it holds, so every
+// coin is paid by exactly one attestation. Priced escrows and
+// dead man's switches pay out in other ways, so they can't have
+// milestones.
+func validateMilestones(e *Escrow) error {
+	if len(e.Milestones) == 0 {
+		if e.Attester != nil {
+			return ErrInvalidMilestone("attester without milestones")
+		}
+		return nil
+	}
+	switch {
+	case len(e.Milestones) > maxMilestones:
+		return ErrInvalidMilestone("too many")
+	case e.Target != nil:
+		return ErrInvalidMilestone("priced escrow")
+	case e.HeartbeatWindow > 0:
+		return ErrInvalidMilestone("dead man's switch")
+	}
+
+	var pending x.Coins
+	for i, m := range e.Milestones {
+		if m.Name == "" || len(m.Name) > maxMilestoneName {
+			return ErrInvalidMilestone("name")
+		}
+		for _, prev := range e.Milestones[:i] {
+			if prev.Name == m.Name {
+				return ErrInvalidMilestone("duplicate name")
+			}
+		}
+		if err := validateAmount(m.Amount); err != nil {
+			return err
+		}
+		if m.Attested < 0 {
+			return ErrInvalidMilestone("attested")
+		}
+		if m.Attested == 0 {
+			var err error
+			pending, err = addCoins(pending, m.Amount)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if !pending.Equals(e.Amount) {
+		return ErrInvalidMilestone("not the amount")
+	}
+	return nil
+}
+
+// AttestMilestoneHandler releases the tranche of a milestone
+// once it is attested
+type AttestMilestoneHandler struct {
+	auth    x.Authenticator
+	bucket  Store
+	history HistoryBucket
+	bids    BidBucket
+	cash    namecoin.Controller
+}
+
+var _ weave.Handler = AttestMilestoneHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h AttestMilestoneHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += releaseEscrowCost
+	return res, nil
+}
+
+// Deliver pays the tranche as a release would, and closes the
+// escrow once the last milestone is attested
+func (h AttestMilestoneHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	escrow := AsEscrow(obj)
+	milestone := escrow.Milestones[msg.Milestone]
+
+	src := NewCondition(obj.Key()).Address()
+	transfers := payTransfers(escrow, src, milestone.Amount)
+	available, err := subtractCoins(escrow.Amount, milestone.Amount)
+	if err != nil {
+		return res, err
+	}
+	// closing the escrow refunds the deposit and pays the arbiter
+	if !available.IsPositive() {
+		transfers = append(transfers,
+			depositTransfers(obj, weave.Permission(escrow.Sender).Address())...)
+		transfers = append(transfers, bountyTransfers(obj, true)...)
+	}
+	err = h.cash.WithContext(ctx).MoveCoinsBatch(db, transfers)
+	if err != nil {
+		return res, err
+	}
+
+	err = h.history.AppendNote(ctx, db, h.auth, obj.Key(), EventMilestone,
+		milestone.Amount, milestone.Name)
+	if err != nil {
+		return res, err
+	}
+	res.Tags = append(res.Tags, ledger.TransferTags(ledger.KindIn, transfers)...)
+	res.Tags = append(res.Tags, destTags(escrow, transfers)...)
+
+	if available.IsPositive() {
+		res.Data = obj.Key()
+		height, _ := weave.GetHeight(ctx)
+		milestone.Attested = height
+		escrow.Amount = available
+		// an attestation settles any dispute, as a release does
+		escrow.Dispute = nil
+		return res, h.bucket.Save(db, obj)
+	}
+	err = deleteBids(db, h.bids, obj)
+	if err != nil {
+		return res, err
+	}
+	return res, h.bucket.Delete(db, obj.Key())
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h AttestMilestoneHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*AttestMilestoneMsg, orm.Object, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*AttestMilestoneMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := h.bucket.Get(db, msg.EscrowId)
+	if err != nil {
+		return nil, nil, err
+	}
+	escrow := AsEscrow(obj)
+	if escrow == nil {
+		return nil, nil, ErrNoSuchEscrow(msg.EscrowId)
+	}
+	if err := checkOpen(obj.Key(), escrow); err != nil {
+		return nil, nil, err
+	}
+
+	// the arbiter attests, or the attester if there is one
+	var signed bool
+	for _, p := range [][]byte{escrow.Arbiter, escrow.Attester} {
+		if p != nil && h.auth.HasAddress(ctx, weave.Permission(p).Address()) {
+			signed = true
+		}
+	}
+	if !signed {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+
+	// what is not attested by the timeout goes back to the sender
+	height, _ := weave.GetHeight(ctx)
+	if escrow.Timeout < height {
+		return nil, nil, ErrEscrowExpired(escrow.Timeout)
+	}
+
+	switch {
+	case int(msg.Milestone) >= len(escrow.Milestones):
+		return nil, nil, ErrInvalidMilestone("no such milestone")
+	case escrow.Milestones[msg.Milestone].Attested != 0:
+		return nil, nil, ErrInvalidMilestone("already attested")
+	}
+	return msg, obj, nil
+}
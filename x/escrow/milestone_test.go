@@ -0,0 +1,142 @@
+package escrow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// TestMilestones pays a grant in tranches as an oracle attests
+// them, and returns the rest after the timeout
+func TestMilestones(t *testing.T) {
+	var helpers x.TestHelpers
+	_, funder := helpers.MakeKey()
+	_, arbiter := helpers.MakeKey()
+	_, grantee := helpers.MakeKey()
+	_, oracle := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), control, NewBucket())
+
+	db := store.MemStore()
+	wallet, err := cash.WalletWith(funder.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, wallet))
+	deliver := func(height int64, msg weave.Msg, perm weave.Permission) ([]byte, error) {
+		ctx := weave.WithHeight(context.Background(), height)
+		ctx = authenticator().SetPermissions(ctx, perm)
+		tx := helpers.MockTx(msg)
+		_, err := r.Check(ctx, db, tx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := r.Deliver(ctx, db, tx)
+		return res.Data, err
+	}
+	balance := func(addr weave.Address) x.Coins {
+		coins, err := control.Balance(db, addr)
+		require.NoError(t, err)
+		return coins
+	}
+
+	msg := NewCreateMsg(funder, grantee, arbiter,
+		mustCombineCoins(x.NewCoin(30, 0, "FOO")), 1000, "research grant")
+	msg.Milestones = []*Milestone{
+		{Name: "prototype", Amount: mustCombineCoins(x.NewCoin(10, 0, "FOO"))},
+		{Name: "pilot", Amount: mustCombineCoins(x.NewCoin(5, 0, "FOO"))},
+		{Name: "report", Amount: mustCombineCoins(x.NewCoin(15, 0, "FOO"))},
+	}
+	msg.Attester = oracle
+	id, err := deliver(10, msg, funder)
+	require.NoError(t, err)
+
+	// the tranches are only released by attestation
+	_, err = deliver(20, &ReleaseEscrowMsg{EscrowId: id}, arbiter)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+	_, err = deliver(20, &AttestMilestoneMsg{EscrowId: id, Milestone: 1}, grantee)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = deliver(20, &AttestMilestoneMsg{EscrowId: id, Milestone: 3}, oracle)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+
+	_, err = deliver(20, &AttestMilestoneMsg{EscrowId: id, Milestone: 1}, oracle)
+	require.NoError(t, err)
+	_, err = deliver(30, &AttestMilestoneMsg{EscrowId: id, Milestone: 0}, arbiter)
+	require.NoError(t, err)
+	_, err = deliver(40, &AttestMilestoneMsg{EscrowId: id, Milestone: 0}, arbiter)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(15, 0, "FOO")), balance(grantee.Address()))
+
+	obj, err := NewBucket().Get(db, id)
+	require.NoError(t, err)
+	escrow := AsEscrow(obj)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(15, 0, "FOO")), x.Coins(escrow.Amount))
+	assert.Equal(t, int64(30), escrow.Milestones[0].Attested)
+	assert.Equal(t, int64(20), escrow.Milestones[1].Attested)
+	assert.Equal(t, int64(0), escrow.Milestones[2].Attested)
+
+	// the report is late, the funder gets its tranche back
+	_, err = deliver(1001, &AttestMilestoneMsg{EscrowId: id, Milestone: 2}, oracle)
+	assert.Error(t, err)
+	_, err = deliver(1001, &ReturnEscrowMsg{EscrowId: id}, funder)
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(85, 0, "FOO")), balance(funder.Address()))
+
+	entries, err := NewHistoryBucket().History(db, id)
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	assert.Equal(t, EventMilestone, entries[1].Event)
+	assert.Equal(t, "pilot", entries[1].Note)
+	assert.Equal(t, oracle.Address(), weave.Address(entries[1].Actor))
+}
+
+func TestValidateMilestones(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	ten := mustCombineCoins(x.NewCoin(10, 0, "FOO"))
+	five := mustCombineCoins(x.NewCoin(5, 0, "FOO"))
+
+	cases := map[string]struct {
+		escrow *Escrow
+		valid  bool
+	}{
+		"none": {&Escrow{Amount: ten}, true},
+		"split": {&Escrow{Amount: ten, Milestones: []*Milestone{
+			{Name: "a", Amount: five}, {Name: "b", Amount: five}}}, true},
+		"paid": {&Escrow{Amount: five, Milestones: []*Milestone{
+			{Name: "a", Amount: five, Attested: 3}, {Name: "b", Amount: five}}}, true},
+		"short": {&Escrow{Amount: ten, Milestones: []*Milestone{
+			{Name: "a", Amount: five}}}, false},
+		"duplicate": {&Escrow{Amount: ten, Milestones: []*Milestone{
+			{Name: "a", Amount: five}, {Name: "a", Amount: five}}}, false},
+		"no name": {&Escrow{Amount: ten, Milestones: []*Milestone{
+			{Amount: ten}}}, false},
+		"no amount": {&Escrow{Amount: ten, Milestones: []*Milestone{
+			{Name: "a", Amount: ten}, {Name: "b"}}}, false},
+		"attester only": {&Escrow{Amount: ten, Attester: a}, false},
+		"dead man's switch": {&Escrow{Amount: ten, HeartbeatWindow: 5, Milestones: []*Milestone{
+			{Name: "a", Amount: ten}}}, false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateMilestones(tc.escrow)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -1,11 +1,19 @@
 package escrow
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
 	"errors"
 
 	"github.com/confio/weave"
 	"github.com/confio/weave/orm"
 	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+	paged "github.com/iov-one/bcp-demo/query"
+	"github.com/iov-one/bcp-demo/tracing"
+	"github.com/iov-one/bcp-demo/x/deposit"
+	"github.com/iov-one/bcp-demo/x/modaccount"
 )
 
 const (
@@ -13,18 +21,54 @@ const (
 	BucketName = "esc"
 	// SequenceName is an auto-increment ID counter for escrows
 	SequenceName = "id"
+	// IndexSender is the index of escrows by sender
+	IndexSender = "sender"
+	// IndexRecipient is the index of escrows by recipient
+	IndexRecipient = "recipient"
+	// IndexArbiter is the index of escrows by arbiter
+	IndexArbiter = "arbiter"
+	// IndexTimeout is the index of escrows by timeout height
+	IndexTimeout = "timeout"
 )
 
+func init() {
+	keyspace.Buckets("escrow", BucketName, BucketNameParams, BucketNameLocked,
+		BucketNameTemplates, BucketNameHeartbeats, BucketNameBids,
+		BucketNamePolicies, BucketNameHistory, BucketNameAlias, BucketNameEscalations)
+}
+
 var _ orm.CloneableData = (*Escrow)(nil)
 
-// Validate ensures the escrow is valid
+// Validate ensures the escrow is valid. The orm calls it on
+// every Save, so no handler can store a broken escrow.
 func (e *Escrow) Validate() error {
 	if e.Sender == nil {
 		return ErrMissingSender()
 	}
-	// Copied from CreateEscrowMsg.Validate
-	// TODO: code reuse???
-	if e.Arbiter == nil {
+	if err := e.validateTerms(); err != nil {
+		return err
+	}
+	if e.Deposit != nil {
+		if err := validateAmount(x.Coins{e.Deposit}); err != nil {
+			return err
+		}
+	}
+	if e.Dispute != nil {
+		if err := e.Dispute.Validate(); err != nil {
+			return err
+		}
+	}
+	if e.Quarantine != nil {
+		return e.Quarantine.Validate()
+	}
+	return nil
+}
+
+// validateTerms checks all the escrow shares with the
+// CreateEscrowMsg, where the sender may be left to default
+func (e *Escrow) validateTerms() error {
+	// the arbiter of an escrow with a bounty is assigned later
+	if e.Arbiter == nil && e.Bounty == nil {
 		return ErrMissingArbiter()
 	}
 	if e.Recipient == nil {
@@ -36,10 +80,41 @@ func (e *Escrow) Validate() error {
 	if len(e.Memo) > maxMemoSize {
 		return ErrInvalidMemo(e.Memo)
 	}
+	if len(e.DestTag) > deposit.MaxTagLength {
+		return ErrInvalidDestTag(e.DestTag)
+	}
+	// the memo is set next to the hash once revealed
+	if len(e.MemoHash) > 0 && len(e.MemoHash) != sha256.Size {
+		return ErrInvalidReveal("memo hash size")
+	}
 	if err := validateAmount(e.Amount); err != nil {
 		return err
 	}
-	return validatePermissions(e.Arbiter, e.Sender, e.Recipient)
+	if err := validateTarget(e.Amount, e.Target, e.MinPrice, e.MaxPrice); err != nil {
+		return err
+	}
+	if e.Bounty != nil {
+		if err := validateAmount(x.Coins{e.Bounty}); err != nil {
+			return err
+		}
+	}
+	if err := validateObservers(e.Observers); err != nil {
+		return err
+	}
+	if err := validateHeartbeat(e); err != nil {
+		return err
+	}
+	if err := validateShares(e.Shares); err != nil {
+		return err
+	}
+	if err := validateMilestones(e); err != nil {
+		return err
+	}
+	if err := validateSLA(e); err != nil {
+		return err
+	}
+	return validatePermissions(e.Arbiter, e.Sender, e.Recipient, e.Clawback, e.Attester,
+		e.BackupArbiter)
 }
 
 // Copy makes a new set with the same coins
@@ -51,6 +126,27 @@ func (e *Escrow) Copy() orm.CloneableData {
 		Amount:    e.Amount,
 		Timeout:   e.Timeout,
 		Memo:      e.Memo,
+
+		SenderCanRelease: e.SenderCanRelease,
+		Target:           e.Target,
+		MinPrice:         e.MinPrice,
+		MaxPrice:         e.MaxPrice,
+		Deposit:          e.Deposit,
+		Bounty:           e.Bounty,
+		Observers:        e.Observers,
+		MemoHash:         e.MemoHash,
+		HeartbeatWindow:  e.HeartbeatWindow,
+		FundedBy:         e.FundedBy,
+		Shares:           e.Shares,
+		Quarantine:       e.Quarantine,
+		Clawback:         e.Clawback,
+		Milestones:       e.Milestones,
+		Attester:         e.Attester,
+		DestTag:          e.DestTag,
+		BackupArbiter:    e.BackupArbiter,
+		SlaBlocks:        e.SlaBlocks,
+		Dispute:          e.Dispute,
+		PolicyReleased:   e.PolicyReleased,
 	}
 }
 
@@ -76,10 +172,14 @@ func NewEscrow(id []byte, sender, rcpt, arb weave.Permission,
 	return orm.NewSimpleObj(id, esc)
 }
 
+// Account is the module account holding the coins of each
+// escrow, derived from the escrow id
+var Account = modaccount.NewDerived(conditionExt, conditionType)
+
 // Permission calculates the address of an escrow given
-// the key
+// the key, see Condition
 func Permission(key []byte) weave.Permission {
-	return weave.NewPermission("escrow", "seq", key)
+	return NewCondition(key).Permission()
 }
 
 // NewEscrow generates a new Escrow object
@@ -94,12 +194,30 @@ func Permission(key []byte) weave.Permission {
 
 //--- Bucket - handles escrows
 
+// Store is where the handlers keep the escrows. Bucket is the
+// one of the app, tests may pass a spy or another storage.
+type Store interface {
+	Get(db weave.ReadOnlyKVStore, key []byte) (orm.Object, error)
+	Save(db weave.KVStore, obj orm.Object) error
+	Delete(db weave.KVStore, key []byte) error
+	// Create saves the escrow under the next id
+	Create(db weave.KVStore, escrow *Escrow) (orm.Object, error)
+	// CountBySender returns the number of open escrows of the sender
+	CountBySender(db weave.ReadOnlyKVStore, sender weave.Permission) (int, error)
+}
+
 // Bucket is a type-safe wrapper around orm.Bucket
 type Bucket struct {
 	orm.Bucket
 	idSeq orm.Sequence
+	// sender and timeout mirror the indexes of the bucket,
+	// so we can count and scan them directly
+	sender  orm.Index
+	timeout orm.Index
 }
 
+var _ Store = Bucket{}
+
 // NewBucket initializes a Bucket with default name
 //
 // inherit Get and Save from orm.Bucket
@@ -107,15 +225,20 @@ type Bucket struct {
 func NewBucket() Bucket {
 	bucket := orm.NewBucket(BucketName,
 		orm.NewSimpleObj(nil, new(Escrow))).
-		WithIndex("sender", idxSender, false).
-		WithIndex("recipient", idxRecipient, false).
-		WithIndex("arbiter", idxArbiter, false)
+		WithIndex(IndexSender, idxSender, false).
+		WithIndex(IndexRecipient, idxRecipient, false).
+		WithIndex(IndexArbiter, idxArbiter, false).
+		WithIndex(IndexTimeout, idxTimeout, false)
 
 	return Bucket{
 		Bucket: bucket,
 		idSeq:  bucket.Sequence(SequenceName),
+		// must match the name orm.Bucket.WithIndex uses
+		sender: orm.NewIndex(BucketName+"_"+IndexSender,
+			idxSender, false, bucket.DBKey),
+		timeout: orm.NewIndex(BucketName+"_"+IndexTimeout,
+			idxTimeout, false, bucket.DBKey),
 	}
-	// TODO: add indexes
 }
 
 func getEscrow(obj orm.Object) (*Escrow, error) {
@@ -153,23 +276,99 @@ func idxArbiter(obj orm.Object) ([]byte, error) {
 	return esc.Arbiter, nil
 }
 
+func idxTimeout(obj orm.Object) ([]byte, error) {
+	esc, err := getEscrow(obj)
+	if err != nil {
+		return nil, err
+	}
+	return timeoutKey(esc.Timeout), nil
+}
+
+// timeoutKey encodes the height big endian, so the index
+// is sorted by height
+func timeoutKey(height int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(height))
+	return bz
+}
+
+// ExpiringBefore returns all escrows with a timeout lower than
+// the given height, the ones expiring first come first
+func (b Bucket) ExpiringBefore(db weave.ReadOnlyKVStore, height int64) ([]orm.Object, error) {
+	if height <= 0 {
+		return nil, nil
+	}
+	start := b.timeout.IndexKey(nil)
+	end := b.timeout.IndexKey(timeoutKey(height))
+	itr := db.Iterator(start, end)
+	defer itr.Close()
+
+	var res []orm.Object
+	for ; itr.Valid(); itr.Next() {
+		var refs orm.MultiRef
+		err := refs.Unmarshal(itr.Value())
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range refs.GetRefs() {
+			obj, err := b.Get(db, ref)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, obj)
+		}
+	}
+	return res, nil
+}
+
+// CountBySender returns the number of open escrows of the sender,
+// reading only the index
+func (b Bucket) CountBySender(db weave.ReadOnlyKVStore, sender weave.Permission) (int, error) {
+	refs, err := b.sender.GetAt(db, sender)
+	return len(refs), err
+}
+
 // Create will calculate the next sequence number and then
 // store the escrow there.
 // Saves the object and returns it (to inspect the ID)
 func (b Bucket) Create(db weave.KVStore, escrow *Escrow) (orm.Object, error) {
 	key := b.idSeq.NextVal(db)
 	obj := orm.NewSimpleObj(key, escrow)
-	err := b.Bucket.Save(db, obj)
+	err := b.Save(db, obj)
 	if err != nil {
 		return nil, err
 	}
 	return obj, nil
 }
 
-// Save enforces the proper type
+// Save enforces the proper type, the orm validates
+// the escrow before writing it. New escrows are counted
+// for the paged query.
 func (b Bucket) Save(db weave.KVStore, obj orm.Object) error {
+	defer tracing.Begin(db, "Save").End()
 	if _, ok := obj.Value().(*Escrow); !ok {
 		return orm.ErrInvalidObject(obj.Value())
 	}
-	return b.Bucket.Save(db, obj)
+	return paged.Save(db, b.Bucket, obj)
+}
+
+// Delete removes the escrow and its count
+func (b Bucket) Delete(db weave.KVStore, key []byte) error {
+	return paged.Delete(db, b.Bucket, key)
+}
+
+// Index returns the index of the bucket with the given name,
+// to page over it. It panics on an unknown name.
+func (b Bucket) Index(name string) orm.Index {
+	indexer, ok := map[string]orm.Indexer{
+		IndexSender:    idxSender,
+		IndexRecipient: idxRecipient,
+		IndexArbiter:   idxArbiter,
+		IndexTimeout:   idxTimeout,
+	}[name]
+	if !ok {
+		panic("unknown escrow index " + name)
+	}
+	// must match the name orm.Bucket.WithIndex uses
+	return orm.NewIndex(BucketName+"_"+name, indexer, false, b.Bucket.DBKey)
 }
@@ -0,0 +1,163 @@
+package escrow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/iov-one/bcp-demo/x/deposit"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestEscrowValidate(t *testing.T) {
+	a := weave.NewPermission("sigs", "ed25519", []byte("sender"))
+	b := weave.NewPermission("sigs", "ed25519", []byte("arbiter"))
+	c := weave.NewPermission("sigs", "ed25519", []byte("recipient"))
+	amount := mustCombineCoins(x.NewCoin(100, 0, "FOO"))
+	zero := x.NewCoin(0, 0, "IOV")
+	fee := x.NewCoin(1, 0, "IOV")
+
+	// valid returns a good escrow, changed by fn
+	valid := func(fn func(*Escrow)) *Escrow {
+		esc := &Escrow{
+			Sender:    a,
+			Arbiter:   b,
+			Recipient: c,
+			Amount:    amount,
+			Timeout:   100,
+			Memo:      "ok",
+		}
+		fn(esc)
+		return esc
+	}
+
+	cases := []struct {
+		escrow *Escrow
+		check  checkErr
+	}{
+		0: {valid(func(e *Escrow) {}), noErr},
+		1: {valid(func(e *Escrow) { e.Sender = nil }), IsMissingPermissionErr},
+		2: {valid(func(e *Escrow) { e.Recipient = nil }), IsMissingPermissionErr},
+		3: {valid(func(e *Escrow) { e.Arbiter = nil }), IsMissingPermissionErr},
+		// the arbiter of a bounty is assigned later
+		4:  {valid(func(e *Escrow) { e.Arbiter, e.Bounty = nil, &fee }), noErr},
+		5:  {valid(func(e *Escrow) { e.Timeout = 0 }), IsInvalidMetadataErr},
+		6:  {valid(func(e *Escrow) { e.Timeout = -5 }), IsInvalidMetadataErr},
+		7:  {valid(func(e *Escrow) { e.Memo = strings.Repeat("x", maxMemoSize+1) }), IsInvalidMetadataErr},
+		8:  {valid(func(e *Escrow) { e.Amount = nil }), cash.IsInvalidAmountErr},
+		9:  {valid(func(e *Escrow) { e.Amount = x.Coins{&zero} }), cash.IsInvalidAmountErr},
+		10: {valid(func(e *Escrow) { e.Deposit = &zero }), cash.IsInvalidAmountErr},
+		11: {valid(func(e *Escrow) { e.Deposit = &fee }), noErr},
+		12: {valid(func(e *Escrow) { e.Bounty = &zero }), cash.IsInvalidAmountErr},
+		13: {valid(func(e *Escrow) { e.Recipient = weave.Permission("foo") }), errors.IsUnrecognizedPermissionErr},
+		14: {valid(func(e *Escrow) { e.DestTag = "104882" }), noErr},
+		15: {valid(func(e *Escrow) { e.DestTag = strings.Repeat("x", deposit.MaxTagLength+1) }), IsInvalidMetadataErr},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			err := tc.escrow.Validate()
+			assert.True(t, tc.check(err), "%+v", err)
+		})
+	}
+}
+
+func TestBucketSaveValidates(t *testing.T) {
+	a := weave.NewPermission("sigs", "ed25519", []byte("sender"))
+	b := weave.NewPermission("sigs", "ed25519", []byte("recipient"))
+	amount := mustCombineCoins(x.NewCoin(100, 0, "FOO"))
+
+	db := store.MemStore()
+	bucket := NewBucket()
+	obj, err := bucket.Create(db, &Escrow{Sender: a, Arbiter: a, Recipient: b,
+		Amount: amount, Timeout: 100})
+	require.NoError(t, err)
+
+	// a handler that forgets a check cannot store the result
+	escrow := AsEscrow(obj)
+	escrow.Amount = nil
+	err = bucket.Save(db, obj)
+	assert.True(t, cash.IsInvalidAmountErr(err), "%+v", err)
+	escrow.Amount = amount
+	escrow.Recipient = nil
+	err = bucket.Save(db, obj)
+	assert.True(t, IsMissingPermissionErr(err), "%+v", err)
+
+	// the stored escrow is unchanged
+	loaded, err := bucket.Get(db, obj.Key())
+	require.NoError(t, err)
+	assert.EqualValues(t, b, AsEscrow(loaded).Recipient)
+	assert.EqualValues(t, amount, AsEscrow(loaded).Amount)
+
+	_, err = bucket.Create(db, &Escrow{Sender: a, Arbiter: a, Recipient: b,
+		Amount: amount, Timeout: 0})
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+	err = bucket.Save(db, orm.NewSimpleObj([]byte("foo"), new(Escrow)))
+	assert.Error(t, err)
+}
+
+// spyStore records the writes of the handlers, and fails them
+// all if broken
+type spyStore struct {
+	Store
+	writes []string
+	broken bool
+}
+
+func (s *spyStore) Create(db weave.KVStore, escrow *Escrow) (orm.Object, error) {
+	s.writes = append(s.writes, "create")
+	if s.broken {
+		return nil, errors.ErrInternal("broken")
+	}
+	return s.Store.Create(db, escrow)
+}
+
+func (s *spyStore) Delete(db weave.KVStore, key []byte) error {
+	s.writes = append(s.writes, "delete")
+	if s.broken {
+		return errors.ErrInternal("broken")
+	}
+	return s.Store.Delete(db, key)
+}
+
+func TestInjectedStore(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	amount := mustCombineCoins(x.NewCoin(10, 0, "FOO"))
+
+	spy := &spyStore{Store: NewBucket()}
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), namecoin.NewController(), spy)
+	db := store.MemStore()
+	require.NoError(t, namecoin.NewController().IssueCoins(db, a.Address(),
+		x.NewCoin(20, 0, "FOO")))
+	deliver := func(msg weave.Msg) ([]byte, error) {
+		ctx := weave.WithHeight(context.Background(), 10)
+		ctx = authenticator().SetPermissions(ctx, a)
+		res, err := r.Deliver(ctx, db, helpers.MockTx(msg))
+		return res.Data, err
+	}
+
+	id, err := deliver(NewCreateMsg(a, b, a, amount, 100, ""))
+	require.NoError(t, err)
+	_, err = deliver(&ReleaseEscrowMsg{EscrowId: id})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"create", "delete"}, spy.writes)
+
+	// the handlers fail with the store
+	spy.broken = true
+	_, err = deliver(NewCreateMsg(a, b, a, amount, 100, ""))
+	assert.True(t, errors.IsInternalErr(err), "%+v", err)
+}
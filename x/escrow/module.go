@@ -0,0 +1,60 @@
+package escrow
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/module"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// Module adds escrows. Auth authenticates the parties, Control
+// moves the coins they lock and release. The handlers keep the
+// escrows in Escrows, the Bucket if nil.
+type Module struct {
+	Auth    x.Authenticator
+	Control namecoin.Controller
+	Escrows Store
+}
+
+var (
+	_ module.Module  = Module{}
+	_ module.Router  = Module{}
+	_ module.Querier = Module{}
+	_ module.Genesis = Module{}
+	_ module.Ticking = Module{}
+)
+
+// Name fulfils module.Module
+func (Module) Name() string {
+	return "escrow"
+}
+
+// Version fulfils module.Module
+func (Module) Version() uint32 {
+	return 21
+}
+
+// RegisterRoutes fulfils module.Router
+func (m Module) RegisterRoutes(r weave.Registry) {
+	escrows := m.Escrows
+	if escrows == nil {
+		escrows = NewBucket()
+	}
+	RegisterRoutes(r, m.Auth, m.Control, escrows)
+}
+
+// RegisterQuery fulfils module.Querier
+func (Module) RegisterQuery(qr weave.QueryRouter) {
+	RegisterQuery(qr)
+}
+
+// Initializer fulfils module.Genesis
+func (m Module) Initializer() weave.Initializer {
+	return NewInitializer(m.Control)
+}
+
+// Ticker fulfils module.Ticking
+func (m Module) Ticker() weave.Ticker {
+	return NewTicker(m.Control)
+}
@@ -1,7 +1,11 @@
 package escrow
 
 import (
+	"bytes"
+	"crypto/sha256"
+
 	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
 	"github.com/confio/weave/x"
 	"github.com/confio/weave/x/cash"
 )
@@ -11,14 +15,56 @@ const (
 	pathReleaseEscrowMsg       = "escrow/release"
 	pathReturnEscrowMsg        = "escrow/return"
 	pathUpdateEscrowPartiesMsg = "escrow/update"
+	pathBidArbitrationMsg      = "escrow/bid"
+	pathAssignArbiterMsg       = "escrow/assign"
+	pathUpdateObserversMsg     = "escrow/observers"
+	pathNetEscrowsMsg          = "escrow/net"
+	pathSetArbiterPolicyMsg    = "escrow/policy"
+	pathRevealMemoMsg          = "escrow/reveal"
+	pathSetTemplateMsg         = "escrow/template"
+	pathPingEscrowMsg          = "escrow/ping"
+	pathSendEscrowMsg          = "escrow/send"
+	pathQuarantineEscrowMsg    = "escrow/quarantine"
+	pathRestoreEscrowMsg       = "escrow/restore"
+	pathForceSettleEscrowMsg   = "escrow/settle"
+	pathClawbackEscrowMsg      = "escrow/clawback"
+	pathAttestMilestoneMsg     = "escrow/attest"
+	pathOfferEscrowPartyMsg    = "escrow/offer"
+	pathAcceptEscrowPartyMsg   = "escrow/accept"
+	pathDisputeEscrowMsg       = "escrow/dispute"
 
-	maxMemoSize int = 128
+	maxMemoSize         int = 128
+	maxObservers        int = 8
+	maxNetEscrows       int = 32
+	maxPolicyRecipients int = 32
+	// salts must be long enough that short memos
+	// can't be found by trying all of them
+	minSaltSize int = 16
 )
 
 var _ weave.Msg = (*CreateEscrowMsg)(nil)
+var _ weave.Msg = (*CreateEscrowMsgV2)(nil)
+var _ weave.Msg = (*CreateFromTemplateMsg)(nil)
 var _ weave.Msg = (*ReleaseEscrowMsg)(nil)
 var _ weave.Msg = (*ReturnEscrowMsg)(nil)
 var _ weave.Msg = (*UpdateEscrowPartiesMsg)(nil)
+var _ weave.Msg = (*BidArbitrationMsg)(nil)
+var _ weave.Msg = (*AssignArbiterMsg)(nil)
+var _ weave.Msg = (*UpdateEscrowObserversMsg)(nil)
+var _ weave.Msg = (*NetEscrowsMsg)(nil)
+var _ weave.Msg = (*SetArbiterPolicyMsg)(nil)
+var _ weave.Msg = (*RevealMemoMsg)(nil)
+var _ weave.Msg = (*SetTemplateMsg)(nil)
+var _ weave.Msg = (*PingEscrowMsg)(nil)
+var _ weave.Msg = (*SendEscrowMsg)(nil)
+var _ weave.Msg = (*QuarantineEscrowMsg)(nil)
+var _ weave.Msg = (*RestoreEscrowMsg)(nil)
+var _ weave.Msg = (*ForceSettleEscrowMsg)(nil)
+var _ weave.Msg = (*ClawbackEscrowMsg)(nil)
+var _ weave.Msg = (*AttestMilestoneMsg)(nil)
+var _ weave.Msg = (*OfferEscrowPartyMsg)(nil)
+var _ weave.Msg = (*AcceptEscrowPartyMsg)(nil)
+var _ weave.Msg = (*DisputeEscrowMsg)(nil)
 
 //--------- Path routing --------
 
@@ -27,6 +73,18 @@ func (CreateEscrowMsg) Path() string {
 	return pathCreateEscrowMsg
 }
 
+// Path fulfills weave.Msg interface to allow routing.
+// All versions go to the same handler.
+func (CreateEscrowMsgV2) Path() string {
+	return pathCreateEscrowMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing.
+// It is created by the same handler as the other versions.
+func (CreateFromTemplateMsg) Path() string {
+	return pathCreateEscrowMsg
+}
+
 // Path fulfills weave.Msg interface to allow routing
 func (ReleaseEscrowMsg) Path() string {
 	return pathReleaseEscrowMsg
@@ -37,11 +95,96 @@ func (ReturnEscrowMsg) Path() string {
 	return pathReturnEscrowMsg
 }
 
+// Path fulfills weave.Msg interface to allow routing
+func (NetEscrowsMsg) Path() string {
+	return pathNetEscrowsMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (SetArbiterPolicyMsg) Path() string {
+	return pathSetArbiterPolicyMsg
+}
+
 // Path fulfills weave.Msg interface to allow routing
 func (UpdateEscrowPartiesMsg) Path() string {
 	return pathUpdateEscrowPartiesMsg
 }
 
+// Path fulfills weave.Msg interface to allow routing
+func (UpdateEscrowObserversMsg) Path() string {
+	return pathUpdateObserversMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (RevealMemoMsg) Path() string {
+	return pathRevealMemoMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (PingEscrowMsg) Path() string {
+	return pathPingEscrowMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (SendEscrowMsg) Path() string {
+	return pathSendEscrowMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (SetTemplateMsg) Path() string {
+	return pathSetTemplateMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (BidArbitrationMsg) Path() string {
+	return pathBidArbitrationMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (AssignArbiterMsg) Path() string {
+	return pathAssignArbiterMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (QuarantineEscrowMsg) Path() string {
+	return pathQuarantineEscrowMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (RestoreEscrowMsg) Path() string {
+	return pathRestoreEscrowMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (ForceSettleEscrowMsg) Path() string {
+	return pathForceSettleEscrowMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (ClawbackEscrowMsg) Path() string {
+	return pathClawbackEscrowMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (AttestMilestoneMsg) Path() string {
+	return pathAttestMilestoneMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (OfferEscrowPartyMsg) Path() string {
+	return pathOfferEscrowPartyMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (AcceptEscrowPartyMsg) Path() string {
+	return pathAcceptEscrowPartyMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (DisputeEscrowMsg) Path() string {
+	return pathDisputeEscrowMsg
+}
+
 //--------- Validation --------
 
 // NewCreateMsg is a helper to quickly build a create escrow message
@@ -59,22 +202,123 @@ func NewCreateMsg(send, rcpt, arb weave.Permission,
 
 // Validate makes sure that this is sensible
 func (m *CreateEscrowMsg) Validate() error {
-	if m.Arbiter == nil {
-		return ErrMissingArbiter()
+	// a clear memo would defeat the hash
+	if len(m.MemoHash) > 0 && m.Memo != "" {
+		return ErrInvalidMemo(m.Memo)
 	}
-	if m.Recipient == nil {
-		return ErrMissingRecipient()
+	for _, ms := range m.Milestones {
+		if ms.Attested != 0 {
+			return ErrInvalidMilestone("already attested")
+		}
+	}
+	return m.escrow(m.Sender).validateTerms()
+}
+
+// escrow returns the escrow this message creates, before the
+// deposit is set
+func (m *CreateEscrowMsg) escrow(sender weave.Permission) *Escrow {
+	return &Escrow{
+		Sender:    sender,
+		Arbiter:   m.Arbiter,
+		Recipient: m.Recipient,
+		Amount:    m.Amount,
+		Timeout:   m.Timeout,
+		Memo:      m.Memo,
+
+		SenderCanRelease: m.SenderCanRelease,
+		Target:           m.Target,
+		MinPrice:         m.MinPrice,
+		MaxPrice:         m.MaxPrice,
+		Bounty:           m.Bounty,
+		Observers:        m.Observers,
+		MemoHash:         m.MemoHash,
+		HeartbeatWindow:  m.HeartbeatWindow,
+		Shares:           m.Shares,
+		Clawback:         m.Clawback,
+		Milestones:       m.Milestones,
+		Attester:         m.Attester,
+		DestTag:          m.DestTag,
+		BackupArbiter:    m.BackupArbiter,
+		SlaBlocks:        m.SlaBlocks,
 	}
-	if m.Timeout <= 0 {
+}
+
+// Validate makes sure exactly one timeout is set, and
+// the rest as CreateEscrowMsg does
+func (m *CreateEscrowMsgV2) Validate() error {
+	if m.Timeout < 0 || m.TimeoutIn < 0 || (m.Timeout > 0) == (m.TimeoutIn > 0) {
 		return ErrInvalidTimeout(m.Timeout)
 	}
-	if len(m.Memo) > maxMemoSize {
-		return ErrInvalidMemo(m.Memo)
+	return m.CreateMsg(0).Validate()
+}
+
+// CreateMsg adapts the message to the CreateEscrowMsg the
+// handler works on. A relative timeout counts from height.
+func (m *CreateEscrowMsgV2) CreateMsg(height int64) *CreateEscrowMsg {
+	msg := NewCreateMsg(m.Sender, m.Recipient, m.Arbiter,
+		m.Amount, m.Timeout, m.Memo)
+	if m.TimeoutIn > 0 {
+		msg.Timeout = height + m.TimeoutIn
 	}
-	if err := validateAmount(m.Amount); err != nil {
-		return err
+	if opts := m.Options; opts != nil {
+		msg.SenderCanRelease = opts.SenderCanRelease
+		msg.Target = opts.Target
+		msg.MinPrice = opts.MinPrice
+		msg.MaxPrice = opts.MaxPrice
+		msg.Bounty = opts.Bounty
+		msg.Observers = opts.Observers
+		msg.MemoHash = opts.MemoHash
+		msg.HeartbeatWindow = opts.HeartbeatWindow
+		msg.Shares = opts.Shares
+		msg.Clawback = opts.Clawback
+		msg.Milestones = opts.Milestones
+		msg.Attester = opts.Attester
+		msg.DestTag = opts.DestTag
+		msg.BackupArbiter = opts.BackupArbiter
+		msg.SlaBlocks = opts.SlaBlocks
 	}
-	return validatePermissions(m.Arbiter, m.Sender, m.Recipient)
+	return msg
+}
+
+// Validate makes sure the template id is well formed. The
+// terms are checked once the template is applied.
+func (m *CreateFromTemplateMsg) Validate() error {
+	if !isTemplateID(m.TemplateId) {
+		return ErrInvalidTemplate("id")
+	}
+	return nil
+}
+
+// createMsg returns any version of the create message
+// as a CreateEscrowMsg, templates are read from db
+func createMsg(ctx weave.Context, db weave.ReadOnlyKVStore,
+	templates TemplateBucket, rmsg weave.Msg) (*CreateEscrowMsg, error) {
+
+	switch msg := rmsg.(type) {
+	case *CreateEscrowMsg:
+		return msg, nil
+	case *CreateEscrowMsgV2:
+		// catch invalid timeouts before they are adapted
+		if err := msg.Validate(); err != nil {
+			return nil, err
+		}
+		height, _ := weave.GetHeight(ctx)
+		return msg.CreateMsg(height), nil
+	case *CreateFromTemplateMsg:
+		if err := msg.Validate(); err != nil {
+			return nil, err
+		}
+		t, err := templates.Template(db, msg.TemplateId)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.Allows(msg.Amount); err != nil {
+			return nil, err
+		}
+		height, _ := weave.GetHeight(ctx)
+		return t.CreateMsg(msg, height), nil
+	}
+	return nil, errors.ErrUnknownTxType(rmsg)
 }
 
 // Validate makes sure that this is sensible
@@ -83,6 +327,11 @@ func (m *ReleaseEscrowMsg) Validate() error {
 	if err != nil {
 		return err
 	}
+	if m.Chain != nil {
+		if err := m.Chain.Validate(); err != nil {
+			return err
+		}
+	}
 	if m.Amount == nil {
 		return nil
 	}
@@ -94,6 +343,33 @@ func (m *ReturnEscrowMsg) Validate() error {
 	return validateEscrowID(m.EscrowId)
 }
 
+// Validate makes sure the escrow id is well formed
+func (m *PingEscrowMsg) Validate() error {
+	return validateEscrowID(m.EscrowId)
+}
+
+// Validate makes sure the transfer is a valid SendMsg
+// and the escrow has valid terms
+func (m *SendEscrowMsg) Validate() error {
+	if m.Amount == nil || !m.Amount.IsPositive() {
+		return cash.ErrInvalidAmount("Non-positive SendMsg")
+	}
+	if err := m.Amount.Validate(); err != nil {
+		return err
+	}
+	if err := weave.Address(m.Src).Validate(); err != nil {
+		return err
+	}
+	return m.CreateMsg(nil).Validate()
+}
+
+// CreateMsg returns the message creating the escrow of the
+// transfer, sent by the signer with the src address
+func (m *SendEscrowMsg) CreateMsg(sender weave.Permission) *CreateEscrowMsg {
+	return NewCreateMsg(sender, m.Dest, m.Escrow.GetArbiter(),
+		x.Coins{m.Amount}, m.Escrow.GetTimeout(), m.Memo)
+}
+
 // Validate makes sure any included items are valid permissions
 // and there is at least one change
 func (m *UpdateEscrowPartiesMsg) Validate() error {
@@ -106,7 +382,191 @@ func (m *UpdateEscrowPartiesMsg) Validate() error {
 		m.Recipient == nil {
 		return ErrMissingAllPermissions()
 	}
-	return validatePermissions(m.Arbiter, m.Sender, m.Recipient)
+	for _, p := range []weave.Permission{m.Arbiter, m.Sender, m.Recipient} {
+		if p == nil {
+			continue
+		}
+		if err := validateNewParty(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate makes sure the addresses are valid and there is
+// at least one change
+func (m *UpdateEscrowObserversMsg) Validate() error {
+	err := validateEscrowID(m.EscrowId)
+	if err != nil {
+		return err
+	}
+	if len(m.Add) == 0 && len(m.Remove) == 0 {
+		return ErrInvalidObservers("no change")
+	}
+	if err := validateObservers(m.Add); err != nil {
+		return err
+	}
+	return validateObservers(m.Remove)
+}
+
+// Validate makes sure the salt is long enough, the memo
+// is checked against the hash by the handler
+func (m *RevealMemoMsg) Validate() error {
+	err := validateEscrowID(m.EscrowId)
+	if err != nil {
+		return err
+	}
+	if m.Memo == "" || len(m.Memo) > maxMemoSize {
+		return ErrInvalidMemo(m.Memo)
+	}
+	if len(m.Salt) < minSaltSize {
+		return ErrInvalidReveal("salt too short")
+	}
+	return nil
+}
+
+// MemoHash is the memo_hash that hides the memo of an escrow
+// until it is revealed with the same salt
+func MemoHash(salt []byte, memo string) []byte {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(memo))
+	return h.Sum(nil)
+}
+
+// Validate makes sure the fee is a valid coin, zero means
+// arbitrating for free
+func (m *BidArbitrationMsg) Validate() error {
+	err := validateEscrowID(m.EscrowId)
+	if err != nil {
+		return err
+	}
+	if m.Fee == nil || m.Fee.Negative().IsPositive() {
+		return ErrInvalidBid("fee")
+	}
+	return m.Fee.Validate()
+}
+
+// Validate makes sure the bidder, if set, is an address
+func (m *AssignArbiterMsg) Validate() error {
+	err := validateEscrowID(m.EscrowId)
+	if err != nil {
+		return err
+	}
+	if m.Bidder != nil {
+		return weave.Address(m.Bidder).Validate()
+	}
+	return nil
+}
+
+// Validate makes sure there are between one and maxNetEscrows
+// distinct ids
+func (m *NetEscrowsMsg) Validate() error {
+	if len(m.EscrowIds) == 0 || len(m.EscrowIds) > maxNetEscrows {
+		return ErrInvalidEscrowID(nil)
+	}
+	for i, id := range m.EscrowIds {
+		if err := validateEscrowID(id); err != nil {
+			return err
+		}
+		for _, other := range m.EscrowIds[:i] {
+			if bytes.Equal(id, other) {
+				return ErrInvalidEscrowID(id)
+			}
+		}
+	}
+	return nil
+}
+
+// Validate makes sure the policy, if any, is valid
+func (m *SetArbiterPolicyMsg) Validate() error {
+	if m.Policy == nil {
+		return nil
+	}
+	return m.Policy.Validate()
+}
+
+// Validate makes sure the id is well formed and the template,
+// if any, is valid
+func (m *SetTemplateMsg) Validate() error {
+	if !isTemplateID(m.TemplateId) {
+		return ErrInvalidTemplate("id")
+	}
+	if m.Template == nil {
+		return nil
+	}
+	return m.Template.Validate()
+}
+
+// Validate makes sure the escrow is quarantined for a reason
+func (m *QuarantineEscrowMsg) Validate() error {
+	if err := validateEscrowID(m.EscrowId); err != nil {
+		return err
+	}
+	return validateReason(m.Reason)
+}
+
+// Validate makes sure that this is sensible
+func (m *RestoreEscrowMsg) Validate() error {
+	return validateEscrowID(m.EscrowId)
+}
+
+// Validate makes sure the release, if any, is positive
+func (m *ForceSettleEscrowMsg) Validate() error {
+	if err := validateEscrowID(m.EscrowId); err != nil {
+		return err
+	}
+	if len(m.Release) == 0 {
+		return nil
+	}
+	return validateAmount(m.Release)
+}
+
+// Validate makes sure the clawback is given a reason
+func (m *ClawbackEscrowMsg) Validate() error {
+	if err := validateEscrowID(m.EscrowId); err != nil {
+		return err
+	}
+	return validateReason(m.Reason)
+}
+
+// Validate makes sure that this is sensible
+func (m *AttestMilestoneMsg) Validate() error {
+	if m.Milestone < 0 {
+		return ErrInvalidMilestone("index")
+	}
+	return validateEscrowID(m.EscrowId)
+}
+
+// Validate makes sure the role is offered to a valid
+// permission, or to none to withdraw it
+func (m *OfferEscrowPartyMsg) Validate() error {
+	if err := validateEscrowID(m.EscrowId); err != nil {
+		return err
+	}
+	if err := validateRole(m.Role); err != nil {
+		return err
+	}
+	if m.To == nil {
+		return nil
+	}
+	return validateNewParty(m.To)
+}
+
+// Validate makes sure that this is sensible
+func (m *AcceptEscrowPartyMsg) Validate() error {
+	if err := validateEscrowID(m.EscrowId); err != nil {
+		return err
+	}
+	return validateRole(m.Role)
+}
+
+// Validate makes sure the dispute is given a reason
+func (m *DisputeEscrowMsg) Validate() error {
+	if err := validateEscrowID(m.EscrowId); err != nil {
+		return err
+	}
+	return validateReason(m.Reason)
 }
 
 // validatePermissions returns an error if any permission doesn't validate
@@ -122,6 +582,33 @@ func validatePermissions(perms ...weave.Permission) error {
 	return nil
 }
 
+// validateObservers makes sure there are not too many and
+// all are distinct addresses
+func validateObservers(addrs [][]byte) error {
+	if len(addrs) > maxObservers {
+		return ErrInvalidObservers("too many")
+	}
+	for i, addr := range addrs {
+		if err := weave.Address(addr).Validate(); err != nil {
+			return err
+		}
+		if indexOf(addrs[:i], addr) >= 0 {
+			return ErrInvalidObservers("duplicate")
+		}
+	}
+	return nil
+}
+
+// indexOf returns the position of addr in addrs, -1 if missing
+func indexOf(addrs [][]byte, addr []byte) int {
+	for i, a := range addrs {
+		if weave.Address(a).Equals(addr) {
+			return i
+		}
+	}
+	return -1
+}
+
 func validateAmount(amount x.Coins) error {
 	// we enforce this is positive
 	positive := amount.IsPositive()
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
 	"github.com/confio/weave/x"
 	"github.com/confio/weave/x/cash"
 	"github.com/stretchr/testify/assert"
@@ -140,6 +141,52 @@ func TestCreateEscrowMsg(t *testing.T) {
 	}
 }
 
+func TestCreateEscrowMsgV2(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	plus := mustCombineCoins(x.NewCoin(100, 0, "FOO"))
+	bounty := x.NewCoin(2, 0, "FOO")
+
+	cases := []struct {
+		msg   *CreateEscrowMsgV2
+		check checkErr
+		// expected timeout when created at height 100
+		timeout int64
+	}{
+		0: {new(CreateEscrowMsgV2), IsInvalidMetadataErr, 0},
+		1: {&CreateEscrowMsgV2{Arbiter: b, Recipient: a, Amount: plus, Timeout: 333},
+			noErr, 333},
+		2: {&CreateEscrowMsgV2{Arbiter: b, Recipient: a, Amount: plus, TimeoutIn: 20},
+			noErr, 120},
+		// only one timeout
+		3: {&CreateEscrowMsgV2{Arbiter: b, Recipient: a, Amount: plus, Timeout: 333,
+			TimeoutIn: 20}, IsInvalidMetadataErr, 0},
+		4: {&CreateEscrowMsgV2{Arbiter: b, Recipient: a, Amount: plus, Timeout: -3,
+			TimeoutIn: 20}, IsInvalidMetadataErr, 0},
+		// options are checked as in the first version
+		5: {&CreateEscrowMsgV2{Recipient: a, Amount: plus, TimeoutIn: 20},
+			IsMissingPermissionErr, 0},
+		6: {&CreateEscrowMsgV2{Recipient: a, Amount: plus, TimeoutIn: 20,
+			Options: &EscrowOptions{Bounty: &bounty}}, noErr, 120},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			assert.Equal(t, pathCreateEscrowMsg, tc.msg.Path())
+			err := tc.msg.Validate()
+			assert.True(t, tc.check(err), "%+v", err)
+			if err != nil {
+				return
+			}
+			msg := tc.msg.CreateMsg(100)
+			assert.Equal(t, tc.timeout, msg.Timeout)
+			assert.Equal(t, tc.msg.Amount, msg.Amount)
+			assert.Equal(t, tc.msg.Options.GetBounty(), msg.Bounty)
+		})
+	}
+}
+
 func TestReleaseEscrowMsg(t *testing.T) {
 	// valid: fixed 8 byte id
 	escrow := []byte{1, 2, 3, 4, 5, 6, 7, 8}
@@ -263,9 +310,12 @@ func TestUpdateEscrowMsg(t *testing.T) {
 	// good
 	_, a := helpers.MakeKey()
 	_, b := helpers.MakeKey()
-	c := weave.NewPermission("monkey", "gelato", []byte("berry"))
+	c := weave.NewPermission("hash", "sha256", []byte("berry"))
 	// invalid
 	d := weave.Permission("foobar")
+	// well formed, but nobody can sign for them
+	e := weave.NewPermission("monkey", "gelato", []byte("berry"))
+	f := NewCondition(escrow).Permission()
 
 	cases := []struct {
 		msg   *UpdateEscrowPartiesMsg
@@ -313,6 +363,22 @@ func TestUpdateEscrowMsg(t *testing.T) {
 			},
 			IsInvalidPermissionErr,
 		},
+		// only types that can sign
+		6: {
+			&UpdateEscrowPartiesMsg{
+				EscrowId: escrow,
+				Arbiter:  e,
+			},
+			IsInvalidPartyErr,
+		},
+		7: {
+			&UpdateEscrowPartiesMsg{
+				EscrowId:  escrow,
+				Sender:    a,
+				Recipient: f,
+			},
+			IsInvalidPartyErr,
+		},
 	}
 
 	for i, tc := range cases {
@@ -323,3 +389,77 @@ func TestUpdateEscrowMsg(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateObserversMsg(t *testing.T) {
+	escrow := []byte("12345678")
+	a := weave.NewAddress([]byte("a"))
+	b := weave.NewAddress([]byte("b"))
+	many := make([][]byte, maxObservers+1)
+	for i := range many {
+		many[i] = weave.NewAddress([]byte{byte(i)})
+	}
+
+	cases := []struct {
+		msg   *UpdateEscrowObserversMsg
+		check checkErr
+	}{
+		0: {new(UpdateEscrowObserversMsg), IsInvalidMetadataErr},
+		1: {&UpdateEscrowObserversMsg{EscrowId: escrow, Add: [][]byte{a, b}}, noErr},
+		2: {&UpdateEscrowObserversMsg{EscrowId: escrow, Remove: [][]byte{a}}, noErr},
+		// swapping one for another
+		3: {&UpdateEscrowObserversMsg{EscrowId: escrow, Add: [][]byte{a}, Remove: [][]byte{b}}, noErr},
+		// no change
+		4: {&UpdateEscrowObserversMsg{EscrowId: escrow}, IsInvalidMetadataErr},
+		5: {&UpdateEscrowObserversMsg{EscrowId: escrow, Add: [][]byte{a, b, a}}, IsInvalidMetadataErr},
+		6: {&UpdateEscrowObserversMsg{EscrowId: escrow, Add: many}, IsInvalidMetadataErr},
+		7: {&UpdateEscrowObserversMsg{EscrowId: escrow, Add: [][]byte{[]byte("short")}},
+			errors.IsUnrecognizedAddressErr},
+		8: {&UpdateEscrowObserversMsg{EscrowId: []byte("bad"), Add: [][]byte{a}}, IsInvalidMetadataErr},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			assert.Equal(t, pathUpdateObserversMsg, tc.msg.Path())
+			err := tc.msg.Validate()
+			assert.True(t, tc.check(err), "%+v", err)
+		})
+	}
+}
+
+func TestRevealMemoMsg(t *testing.T) {
+	escrow := []byte("12345678")
+	salt := []byte("0123456789abcdef")
+
+	cases := []struct {
+		msg   *RevealMemoMsg
+		check checkErr
+	}{
+		0: {new(RevealMemoMsg), IsInvalidMetadataErr},
+		1: {&RevealMemoMsg{EscrowId: escrow, Memo: "terms", Salt: salt}, noErr},
+		2: {&RevealMemoMsg{EscrowId: escrow, Salt: salt}, IsInvalidMetadataErr},
+		3: {&RevealMemoMsg{EscrowId: escrow, Memo: strings.Repeat("x", maxMemoSize+1),
+			Salt: salt}, IsInvalidMetadataErr},
+		4: {&RevealMemoMsg{EscrowId: escrow, Memo: "terms", Salt: salt[1:]}, IsInvalidMetadataErr},
+		5: {&RevealMemoMsg{EscrowId: []byte("bad"), Memo: "terms", Salt: salt}, IsInvalidMetadataErr},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			assert.Equal(t, pathRevealMemoMsg, tc.msg.Path())
+			err := tc.msg.Validate()
+			assert.True(t, tc.check(err), "%+v", err)
+		})
+	}
+
+	// a hash goes instead of the memo, not next to it
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	create := NewCreateMsg(a, a, a, mustCombineCoins(x.NewCoin(1, 0, "FOO")), 100, "")
+	create.MemoHash = MemoHash(salt, "terms")
+	assert.NoError(t, create.Validate())
+	create.MemoHash = create.MemoHash[1:]
+	assert.True(t, IsInvalidMetadataErr(create.Validate()))
+	create.MemoHash = MemoHash(salt, "terms")
+	create.Memo = "terms"
+	assert.True(t, IsInvalidMetadataErr(create.Validate()))
+}
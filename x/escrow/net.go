@@ -0,0 +1,242 @@
+package escrow
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/tracing"
+	"github.com/iov-one/bcp-demo/x/ledger"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+const netEscrowsCost int64 = 0
+
+// NetEscrowsHandler settles a batch of escrows between two
+// parties with only the net difference changing sides
+type NetEscrowsHandler struct {
+	auth    x.Authenticator
+	bucket  Store
+	history HistoryBucket
+	bids    BidBucket
+	cash    namecoin.Controller
+}
+
+var _ weave.Handler = NetEscrowsHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h NetEscrowsHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += netEscrowsCost
+	return res, nil
+}
+
+// Deliver closes all escrows. Of every ticker, the side whose
+// escrows hold more pays the difference to the other, from its
+// escrows in the order given. All other coins go back to the
+// sender of their escrow. Deposits are refunded and arbiters
+// paid as on a full release.
+func (h NetEscrowsHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	objs, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// what the escrows of each side hold, by sender
+	first := weave.Permission(AsEscrow(objs[0]).Sender).Address()
+	var held [2]x.Coins
+	for _, obj := range objs {
+		escrow := AsEscrow(obj)
+		side := netSide(first, escrow)
+		held[side], err = addCoins(held[side], escrow.Amount)
+		if err != nil {
+			return res, err
+		}
+	}
+	due, err := netDue(held[0], held[1])
+	if err != nil {
+		return res, err
+	}
+
+	var transfers []namecoin.Transfer
+	for _, obj := range objs {
+		escrow := AsEscrow(obj)
+		side := netSide(first, escrow)
+		var paid, rest x.Coins
+		paid, rest, due[side], err = takeCoins(escrow.Amount, due[side])
+		if err != nil {
+			return res, err
+		}
+
+		src := NewCondition(obj.Key()).Address()
+		sender := weave.Permission(escrow.Sender).Address()
+		transfers = append(transfers, namecoin.NewTransfers(src,
+			weave.Permission(escrow.Recipient).Address(), paid)...)
+		transfers = append(transfers, namecoin.NewTransfers(src, sender, rest)...)
+		transfers = append(transfers, depositTransfers(obj, sender)...)
+		transfers = append(transfers, bountyTransfers(obj, true)...)
+
+		err = h.history.Append(ctx, db, h.auth, obj.Key(), EventRelease, paid)
+		if err != nil {
+			return res, err
+		}
+		if rest.IsPositive() {
+			err = h.history.Append(ctx, db, h.auth, obj.Key(), EventReturn, rest)
+			if err != nil {
+				return res, err
+			}
+		}
+		err = deleteBids(db, h.bids, obj)
+		if err != nil {
+			return res, err
+		}
+		err = h.bucket.Delete(db, obj.Key())
+		if err != nil {
+			return res, err
+		}
+	}
+	res.Tags = append(res.Tags, ledger.TransferTags(ledger.KindIn, transfers)...)
+	return res, h.cash.WithContext(ctx).MoveCoinsBatch(db, transfers)
+}
+
+// validate does all common pre-processing between Check and Deliver.
+// It returns the escrows in the order of the message.
+func (h NetEscrowsHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) ([]orm.Object, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*NetEscrowsMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	height, _ := weave.GetHeight(ctx)
+	objs := make([]orm.Object, len(msg.EscrowIds))
+	var parties [2]weave.Address
+	for i, id := range msg.EscrowIds {
+		obj, err := h.bucket.Get(db, id)
+		if err != nil {
+			return nil, err
+		}
+		escrow := AsEscrow(obj)
+		if escrow == nil {
+			return nil, ErrNoSuchEscrow(id)
+		}
+		if err := checkOpen(id, escrow); err != nil {
+			return nil, err
+		}
+		if escrow.Timeout < height {
+			return nil, ErrEscrowExpired(escrow.Timeout)
+		}
+		if escrow.Target != nil {
+			return nil, ErrInvalidTarget("priced escrows cannot be netted")
+		}
+		if len(escrow.Shares) > 0 {
+			return nil, ErrInvalidShares("split escrows cannot be netted")
+		}
+		if len(escrow.Milestones) > 0 {
+			return nil, ErrInvalidMilestone("milestone escrows cannot be netted")
+		}
+
+		sender := weave.Permission(escrow.Sender).Address()
+		rcpt := weave.Permission(escrow.Recipient).Address()
+		if i == 0 {
+			parties = [2]weave.Address{sender, rcpt}
+		}
+		if !(sender.Equals(parties[0]) && rcpt.Equals(parties[1])) &&
+			!(sender.Equals(parties[1]) && rcpt.Equals(parties[0])) {
+			return nil, ErrInvalidPermission(escrow.Recipient)
+		}
+		objs[i] = obj
+	}
+
+	// both parties agree to settle
+	if !h.auth.HasAddress(ctx, parties[0]) || !h.auth.HasAddress(ctx, parties[1]) {
+		return nil, errors.ErrUnauthorized()
+	}
+	return objs, nil
+}
+
+// netSide is 0 for an escrow sent by first, 1 for the other way
+func netSide(first weave.Address, escrow *Escrow) int {
+	if weave.Permission(escrow.Sender).Address().Equals(first) {
+		return 0
+	}
+	return 1
+}
+
+// netDue returns of every ticker the difference between what
+// the two sides hold, as due from the side that holds more
+func netDue(a, b x.Coins) ([2]x.Coins, error) {
+	var due [2]x.Coins
+	diff := a.Clone()
+	for _, c := range b {
+		var err error
+		diff, err = diff.Subtract(*c)
+		if err != nil {
+			return due, err
+		}
+	}
+	for _, c := range diff {
+		if c.IsPositive() {
+			due[0] = append(due[0], c.Clone())
+		} else if c.Negative().IsPositive() {
+			neg := c.Negative()
+			due[1] = append(due[1], &neg)
+		}
+	}
+	return due, nil
+}
+
+// takeCoins splits have into the part that pays what is due,
+// up to all of have, and the rest. It returns what is still
+// due afterwards.
+func takeCoins(have, due x.Coins) (paid, rest, left x.Coins, err error) {
+	left = due.Clone()
+	for _, c := range have {
+		pay := x.Coin{Ticker: c.Ticker, Issuer: c.Issuer}
+		for _, d := range left {
+			if d.SameType(*c) {
+				pay = *d
+			}
+		}
+		if pay.IsGTE(*c) {
+			pay = *c
+		}
+		if pay.IsPositive() {
+			paid = append(paid, pay.Clone())
+			left, err = left.Subtract(pay)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		keep, err := c.Add(pay.Negative())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if keep.IsPositive() {
+			rest = append(rest, &keep)
+		}
+	}
+	return paid, rest, nonZero(left), nil
+}
@@ -0,0 +1,125 @@
+package escrow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestNetEscrows(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	_, c := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), control, NewBucket())
+	at := func(height int64, perms ...weave.Permission) weave.Context {
+		ctx := weave.WithHeight(context.Background(), height)
+		return authenticator().SetPermissions(ctx, perms...)
+	}
+
+	db := store.MemStore()
+	for _, acct := range []struct {
+		perm  weave.Permission
+		coins []*x.Coin
+	}{
+		{a, []*x.Coin{{Whole: 100, Ticker: "FOO"}, {Whole: 50, Ticker: "BAR"}}},
+		{b, []*x.Coin{{Whole: 100, Ticker: "FOO"}}},
+		{c, []*x.Coin{{Whole: 100, Ticker: "FOO"}}},
+	} {
+		wallet, err := cash.WalletWith(acct.perm.Address(), acct.coins...)
+		require.NoError(t, err)
+		require.NoError(t, bank.Save(db, wallet))
+	}
+	balance := func(perm weave.Permission) x.Coins {
+		coins, err := control.Balance(db, perm.Address())
+		require.NoError(t, err)
+		return coins
+	}
+	create := func(send, rcpt weave.Permission, amount ...x.Coin) []byte {
+		msg := NewCreateMsg(send, rcpt, c, mustCombineCoins(amount...), 1000, "")
+		res, err := r.Deliver(at(10, send), db, helpers.MockTx(msg))
+		require.NoError(t, err)
+		return res.Data
+	}
+	deliver := func(height int64, msg *NetEscrowsMsg, perms ...weave.Permission) error {
+		tx := helpers.MockTx(msg)
+		_, err := r.Check(at(height, perms...), db, tx)
+		if err != nil {
+			return err
+		}
+		_, err = r.Deliver(at(height, perms...), db, tx)
+		return err
+	}
+
+	e1 := create(a, b, x.NewCoin(30, 0, "FOO"))
+	e2 := create(a, b, x.NewCoin(10, 0, "FOO"), x.NewCoin(5, 0, "BAR"))
+	e3 := create(b, a, x.NewCoin(25, 0, "FOO"))
+	other := create(c, a, x.NewCoin(1, 0, "FOO"))
+	all := &NetEscrowsMsg{EscrowIds: [][]byte{e1, e2, e3}}
+
+	// both parties must sign
+	err := deliver(20, all, a)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	// all escrows must be between them
+	err = deliver(20, &NetEscrowsMsg{EscrowIds: [][]byte{e1, other}}, a, b, c)
+	assert.True(t, IsInvalidPermissionErr(err), "%+v", err)
+	err = deliver(20, &NetEscrowsMsg{EscrowIds: [][]byte{e1, e1}}, a, b)
+	assert.Error(t, err)
+	err = deliver(20, &NetEscrowsMsg{}, a, b)
+	assert.Error(t, err)
+	err = deliver(1001, all, a, b)
+	assert.True(t, errors.HasErrorCode(err, CodeInvalidHeight), "%+v", err)
+
+	// a owes 15 FOO and 5 BAR net, taken from e1 and e2
+	require.NoError(t, deliver(20, all, a, b))
+	assert.Equal(t, mustCombineCoins(x.NewCoin(85, 0, "FOO"), x.NewCoin(45, 0, "BAR")), balance(a))
+	assert.Equal(t, mustCombineCoins(x.NewCoin(115, 0, "FOO"), x.NewCoin(5, 0, "BAR")), balance(b))
+	for _, id := range [][]byte{e1, e2, e3} {
+		obj, err := NewBucket().Get(db, id)
+		require.NoError(t, err)
+		assert.Nil(t, obj)
+		assert.Empty(t, balance(Permission(id)))
+	}
+	locked, err := NewLockedBucket().Load(db)
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(1, 0, "FOO")), locked)
+
+	history, err := NewHistoryBucket().History(db, e1)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, EventRelease, history[1].Event)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(15, 0, "FOO")), x.Coins(history[1].Amount))
+	assert.Equal(t, EventReturn, history[2].Event)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(15, 0, "FOO")), x.Coins(history[2].Amount))
+}
+
+func TestTakeCoins(t *testing.T) {
+	have := mustCombineCoins(x.NewCoin(10, 0, "FOO"), x.NewCoin(3, 0, "BAR"))
+	due := mustCombineCoins(x.NewCoin(4, 500000000, "FOO"), x.NewCoin(7, 0, "BAR"))
+	paid, rest, left, err := takeCoins(have, due)
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(4, 500000000, "FOO"), x.NewCoin(3, 0, "BAR")), paid)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(5, 500000000, "FOO")), rest)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(4, 0, "BAR")), left)
+
+	paid, rest, left, err = takeCoins(have, nil)
+	require.NoError(t, err)
+	assert.Empty(t, paid)
+	assert.True(t, have.Equals(rest))
+	assert.Empty(t, left)
+}
@@ -0,0 +1,290 @@
+package escrow
+
+import (
+	"math/big"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/modaccount"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+const (
+	// BucketNameParams is where we store the module settings
+	BucketNameParams = "escparam"
+	// BucketNameLocked is where we store the total value locked
+	BucketNameLocked = "esclock"
+
+	paramsKey = "params"
+	lockedKey = "locked"
+
+	// defaultOfferWindow is the offer window if none is set
+	defaultOfferWindow int64 = 1000
+)
+
+var _ orm.CloneableData = (*Params)(nil)
+
+// Validate ensures the params are valid
+func (p *Params) Validate() error {
+	if p.MaxPerSender < 0 {
+		return ErrTooManyEscrows(int(p.MaxPerSender))
+	}
+	if p.GasPerByte < 0 {
+		return ErrInvalidGasRate(p.GasPerByte)
+	}
+	if p.OfferWindow < 0 {
+		return ErrInvalidTimeout(p.OfferWindow)
+	}
+	if p.DepositPerBlock != nil {
+		if err := validateAmount(x.Coins{p.DepositPerBlock}); err != nil {
+			return err
+		}
+	}
+	if len(p.DustThreshold) > 0 {
+		if err := validateAmount(p.DustThreshold); err != nil {
+			return err
+		}
+	}
+	if len(p.MaxLocked) > 0 {
+		return validateAmount(p.MaxLocked)
+	}
+	return nil
+}
+
+// Copy makes a new set with the same values
+func (p *Params) Copy() orm.CloneableData {
+	return &Params{
+		DustThreshold: x.Coins(p.DustThreshold).Clone(),
+		DustToSender:  p.DustToSender,
+		MaxPerSender:  p.MaxPerSender,
+		MaxLocked:     x.Coins(p.MaxLocked).Clone(),
+		GasPerByte:    p.GasPerByte,
+
+		DepositPerBlock: p.DepositPerBlock,
+		OfferWindow:     p.OfferWindow,
+	}
+}
+
+// CheckOpen returns an error if a sender with count open
+// escrows may not create another one
+func (p *Params) CheckOpen(count int) error {
+	if p.GetMaxPerSender() > 0 && int64(count) >= p.MaxPerSender {
+		return ErrTooManyEscrows(count)
+	}
+	return nil
+}
+
+// CheckLocked returns an error if the total value locked
+// exceeds the limit of any ticker
+func (p *Params) CheckLocked(locked x.Coins) error {
+	for _, limit := range p.GetMaxLocked() {
+		c := findTicker(locked, limit.Ticker)
+		if c != nil && c.Compare(*limit) > 0 {
+			return ErrLockedLimit(c.Ticker)
+		}
+	}
+	return nil
+}
+
+// OfferExpires is the last height an offer made at the given
+// height may be accepted at
+func (p *Params) OfferExpires(height int64) int64 {
+	window := p.GetOfferWindow()
+	if window == 0 {
+		window = defaultOfferWindow
+	}
+	return height + window
+}
+
+// StorageGas is the gas to store the memo (or its hash), the
+// destination tag and coins of a new escrow, at GasPerByte
+func (p *Params) StorageGas(msg *CreateEscrowMsg) int64 {
+	size := len(msg.Memo) + len(msg.MemoHash) + len(msg.DestTag)
+	for _, c := range msg.Amount {
+		size += c.Size()
+	}
+	return p.GetGasPerByte() * int64(size)
+}
+
+// Deposit is the deposit for an escrow that is open for the
+// given number of blocks, nil if none is required
+func (p *Params) Deposit(blocks int64) (*x.Coin, error) {
+	rate := p.GetDepositPerBlock()
+	if rate == nil || blocks <= 0 {
+		return nil, nil
+	}
+	n := new(big.Int).Mul(units(*rate), big.NewInt(blocks))
+	whole, frac := new(big.Int).QuoRem(n, big.NewInt(fracUnit), new(big.Int))
+	if !whole.IsInt64() {
+		return nil, ErrInvalidTimeout(blocks)
+	}
+	deposit := x.NewCoin(whole.Int64(), frac.Int64(), rate.Ticker)
+	return &deposit, deposit.Validate()
+}
+
+// IsDust returns true if every coin is below the threshold
+// of its ticker. Tickers without threshold are never dust.
+func (p *Params) IsDust(coins x.Coins) bool {
+	if len(coins) == 0 || len(p.GetDustThreshold()) == 0 {
+		return false
+	}
+	for _, c := range coins {
+		if !c.IsPositive() {
+			continue
+		}
+		limit := findTicker(p.DustThreshold, c.Ticker)
+		if limit == nil || c.IsGTE(*limit) {
+			return false
+		}
+	}
+	return true
+}
+
+func findTicker(coins []*x.Coin, ticker string) *x.Coin {
+	for _, c := range coins {
+		if c.Ticker == ticker {
+			return c
+		}
+	}
+	return nil
+}
+
+// ParamsBucket stores the single Params object of the module
+type ParamsBucket struct {
+	orm.Bucket
+}
+
+// NewParamsBucket initializes a ParamsBucket with default name
+func NewParamsBucket() ParamsBucket {
+	return ParamsBucket{
+		Bucket: orm.NewBucket(BucketNameParams,
+			orm.NewSimpleObj(nil, new(Params))),
+	}
+}
+
+// Load returns the stored params, or the defaults if
+// none were set in genesis
+func (b ParamsBucket) Load(db weave.ReadOnlyKVStore) (*Params, error) {
+	obj, err := b.Get(db, []byte(paramsKey))
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil || obj.Value() == nil {
+		return new(Params), nil
+	}
+	return obj.Value().(*Params), nil
+}
+
+// Store saves the params, replacing the old ones
+func (b ParamsBucket) Store(db weave.KVStore, params *Params) error {
+	return b.Save(db, orm.NewSimpleObj([]byte(paramsKey), params))
+}
+
+//--- Locked
+
+var _ orm.CloneableData = (*Locked)(nil)
+
+// Validate ensures the amount is well formed
+func (l *Locked) Validate() error {
+	return x.Coins(l.Amount).Validate()
+}
+
+// Copy makes a new set with the same coins
+func (l *Locked) Copy() orm.CloneableData {
+	return &Locked{Amount: x.Coins(l.Amount).Clone()}
+}
+
+func init() {
+	namecoin.RegisterMoveHook(trackLocked)
+}
+
+// LockedBucket keeps the total value held in all escrows,
+// updated by a namecoin.MoveHook whenever coins move in or out
+// of an escrow account
+type LockedBucket struct {
+	orm.Bucket
+}
+
+// NewLockedBucket initializes a LockedBucket with default name
+func NewLockedBucket() LockedBucket {
+	return LockedBucket{
+		Bucket: orm.NewBucket(BucketNameLocked,
+			orm.NewSimpleObj(nil, new(Locked))),
+	}
+}
+
+// Load returns the total value locked
+func (b LockedBucket) Load(db weave.ReadOnlyKVStore) (x.Coins, error) {
+	obj, err := b.Get(db, []byte(lockedKey))
+	if err != nil || obj == nil || obj.Value() == nil {
+		return nil, err
+	}
+	return x.Coins(obj.Value().(*Locked).Amount).Clone(), nil
+}
+
+// Add increases the total value locked and returns the new total
+func (b LockedBucket) Add(db weave.KVStore, coins x.Coins) (x.Coins, error) {
+	total, err := b.Load(db)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range coins {
+		total, err = total.Add(*c)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return total, b.store(db, total)
+}
+
+// Subtract reduces the total value locked
+func (b LockedBucket) Subtract(db weave.KVStore, coins x.Coins) error {
+	total, err := b.Load(db)
+	if err != nil {
+		return err
+	}
+	for _, c := range coins {
+		total, err = total.Subtract(*c)
+		if err != nil {
+			return err
+		}
+	}
+	return b.store(db, total)
+}
+
+func (b LockedBucket) store(db weave.KVStore, total x.Coins) error {
+	return b.Save(db, orm.NewSimpleObj([]byte(lockedKey), &Locked{Amount: total}))
+}
+
+// trackLocked adds the coins paid into an escrow account to the
+// total value locked, and subtracts those paid out of one
+func trackLocked(ctx weave.Context, db weave.KVStore, src, dest weave.Address,
+	amount x.Coin) error {
+
+	in, err := isEscrowAccount(db, dest)
+	if err != nil {
+		return err
+	}
+	var out bool
+	if src != nil {
+		out, err = isEscrowAccount(db, src)
+		if err != nil {
+			return err
+		}
+	}
+	coins := x.Coins{&amount}
+	switch {
+	case in && !out:
+		_, err = NewLockedBucket().Add(db, coins)
+	case out && !in:
+		err = NewLockedBucket().Subtract(db, coins)
+	}
+	return err
+}
+
+func isEscrowAccount(db weave.ReadOnlyKVStore, addr weave.Address) (bool, error) {
+	module, err := modaccount.NewBucket().Module(db, addr)
+	return module == Account.Module(), err
+}
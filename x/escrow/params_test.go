@@ -0,0 +1,190 @@
+package escrow
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/modaccount"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestParamsIsDust(t *testing.T) {
+	params := &Params{DustThreshold: mustCombineCoins(
+		x.NewCoin(0, 1000, "FOO"),
+		x.NewCoin(1, 0, "BAR"),
+	)}
+
+	cases := []struct {
+		params *Params
+		coins  x.Coins
+		dust   bool
+	}{
+		0: {params, mustCombineCoins(x.NewCoin(0, 999, "FOO")), true},
+		1: {params, mustCombineCoins(x.NewCoin(0, 1000, "FOO")), false},
+		2: {params, mustCombineCoins(x.NewCoin(0, 5, "FOO"), x.NewCoin(0, 5, "BAR")), true},
+		3: {params, mustCombineCoins(x.NewCoin(0, 5, "FOO"), x.NewCoin(2, 0, "BAR")), false},
+		// no threshold for this ticker
+		4: {params, mustCombineCoins(x.NewCoin(0, 1, "BAZ")), false},
+		5: {params, nil, false},
+		// no policy
+		6: {new(Params), mustCombineCoins(x.NewCoin(0, 1, "FOO")), false},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			assert.Equal(t, tc.dust, tc.params.IsDust(tc.coins))
+		})
+	}
+}
+
+func TestParamsBucket(t *testing.T) {
+	db := store.MemStore()
+	bucket := NewParamsBucket()
+
+	// defaults if nothing stored
+	params, err := bucket.Load(db)
+	require.NoError(t, err)
+	assert.Equal(t, new(Params), params)
+
+	set := &Params{
+		DustThreshold: mustCombineCoins(x.NewCoin(0, 1000, "FOO")),
+		DustToSender:  true,
+	}
+	require.NoError(t, bucket.Store(db, set))
+	params, err = bucket.Load(db)
+	require.NoError(t, err)
+	assert.Equal(t, set, params)
+
+	// thresholds must be positive
+	bad := &Params{DustThreshold: []*x.Coin{{Whole: -1, Ticker: "FOO"}}}
+	assert.Error(t, bucket.Store(db, bad))
+}
+
+func TestParamsLimits(t *testing.T) {
+	params := &Params{
+		MaxPerSender: 2,
+		MaxLocked:    mustCombineCoins(x.NewCoin(100, 0, "FOO")),
+	}
+	require.NoError(t, params.Validate())
+	assert.NoError(t, params.CheckOpen(1))
+	assert.True(t, IsLimitExceededErr(params.CheckOpen(2)))
+	// no limit
+	assert.NoError(t, new(Params).CheckOpen(1000))
+
+	cases := []struct {
+		locked  x.Coins
+		isError bool
+	}{
+		0: {nil, false},
+		1: {mustCombineCoins(x.NewCoin(100, 0, "FOO")), false},
+		2: {mustCombineCoins(x.NewCoin(100, 1, "FOO")), true},
+		// other tickers are not limited
+		3: {mustCombineCoins(x.NewCoin(5000, 0, "BAR"), x.NewCoin(1, 0, "FOO")), false},
+	}
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			err := params.CheckLocked(tc.locked)
+			if tc.isError {
+				assert.True(t, IsLimitExceededErr(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.Error(t, (&Params{MaxPerSender: -1}).Validate())
+}
+
+func TestParamsStorageGas(t *testing.T) {
+	coins := mustCombineCoins(x.NewCoin(1, 0, "FOO"), x.NewCoin(0, 5, "BAR"))
+	size := int64(coins[0].Size() + coins[1].Size())
+	msg := &CreateEscrowMsg{Memo: "hello", Amount: coins}
+
+	assert.Equal(t, int64(0), new(Params).StorageGas(msg))
+	assert.Equal(t, 2*(5+size), (&Params{GasPerByte: 2}).StorageGas(msg))
+	assert.Equal(t, int64(2), (&Params{GasPerByte: 2}).StorageGas(&CreateEscrowMsg{Memo: "a"}))
+
+	assert.True(t, IsInvalidParamsErr((&Params{GasPerByte: -1}).Validate()))
+}
+
+func TestParamsDeposit(t *testing.T) {
+	params := &Params{DepositPerBlock: &x.Coin{Fractional: 300000000, Ticker: "FOO"}}
+	require.NoError(t, params.Validate())
+
+	deposit, err := params.Deposit(10)
+	require.NoError(t, err)
+	assert.Equal(t, x.NewCoin(3, 0, "FOO"), *deposit)
+	deposit, err = params.Deposit(5)
+	require.NoError(t, err)
+	assert.Equal(t, x.NewCoin(1, 500000000, "FOO"), *deposit)
+
+	// no deposit
+	deposit, err = new(Params).Deposit(10)
+	require.NoError(t, err)
+	assert.Nil(t, deposit)
+
+	big := &Params{DepositPerBlock: &x.Coin{Whole: 1000000000000, Ticker: "FOO"}}
+	_, err = big.Deposit(1000000000000)
+	assert.Error(t, err)
+
+	assert.Error(t, (&Params{DepositPerBlock: &x.Coin{Ticker: "FOO"}}).Validate())
+}
+
+func TestLockedBucket(t *testing.T) {
+	db := store.MemStore()
+	bucket := NewLockedBucket()
+
+	locked, err := bucket.Load(db)
+	require.NoError(t, err)
+	assert.Empty(t, locked)
+
+	in := mustCombineCoins(x.NewCoin(10, 0, "FOO"), x.NewCoin(3, 0, "BAR"))
+	total, err := bucket.Add(db, in)
+	require.NoError(t, err)
+	assert.Equal(t, in, total)
+	total, err = bucket.Add(db, in)
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(20, 0, "FOO"), x.NewCoin(6, 0, "BAR")), total)
+
+	err = bucket.Subtract(db, mustCombineCoins(x.NewCoin(20, 0, "FOO"), x.NewCoin(1, 0, "BAR")))
+	require.NoError(t, err)
+	locked, err = bucket.Load(db)
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(5, 0, "BAR")), locked)
+
+	// the input is not modified
+	assert.Equal(t, mustCombineCoins(x.NewCoin(10, 0, "FOO"), x.NewCoin(3, 0, "BAR")), in)
+}
+
+func TestTrackLocked(t *testing.T) {
+	var helpers x.TestHelpers
+	_, user := helpers.MakeKey()
+
+	db := store.MemStore()
+	accounts := modaccount.NewBucket()
+	one, err := accounts.Open(db, Account, []byte{1})
+	require.NoError(t, err)
+	two, err := accounts.Open(db, Account, []byte{2})
+	require.NoError(t, err)
+
+	ctrl := namecoin.NewController()
+	foo := func(n int64) x.Coin { return x.NewCoin(n, 0, "FOO") }
+	require.NoError(t, ctrl.IssueCoins(db, user.Address(), foo(20)))
+	require.NoError(t, ctrl.IssueCoins(db, one, foo(5)))
+	require.NoError(t, ctrl.MoveCoins(db, user.Address(), two, foo(8)))
+	// between escrows and out of them
+	require.NoError(t, ctrl.MoveCoins(db, one, two, foo(2)))
+	require.NoError(t, ctrl.MoveCoins(db, two, user.Address(), foo(4)))
+	// other module accounts are not counted
+	require.NoError(t, ctrl.MoveCoins(db, user.Address(), modaccount.Address(modaccount.CommunityPool), foo(1)))
+
+	locked, err := NewLockedBucket().Load(db)
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(foo(9)), locked)
+}
@@ -0,0 +1,222 @@
+package escrow
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/tracing"
+)
+
+const (
+	// BucketNamePolicies is where we store the arbiter policies
+	BucketNamePolicies = "escpol"
+
+	setPolicyCost int64 = 50
+)
+
+var _ orm.CloneableData = (*ArbiterPolicy)(nil)
+
+// Validate ensures the policy approves something and lists
+// every ticker and recipient once
+func (p *ArbiterPolicy) Validate() error {
+	if len(p.MaxAmount) == 0 {
+		return ErrInvalidPolicy("no max amount")
+	}
+	for i, c := range p.MaxAmount {
+		if c == nil || !c.IsPositive() {
+			return ErrInvalidPolicy("max amount")
+		}
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		for _, other := range p.MaxAmount[:i] {
+			if c.SameType(*other) {
+				return ErrInvalidPolicy("duplicate ticker")
+			}
+		}
+	}
+	if len(p.Recipients) == 0 || len(p.Recipients) > maxPolicyRecipients {
+		return ErrInvalidPolicy("recipients")
+	}
+	for i, addr := range p.Recipients {
+		if err := weave.Address(addr).Validate(); err != nil {
+			return err
+		}
+		if indexOf(p.Recipients[:i], addr) >= 0 {
+			return ErrInvalidPolicy("duplicate recipient")
+		}
+	}
+	return nil
+}
+
+// Copy makes a new policy with the same values
+func (p *ArbiterPolicy) Copy() orm.CloneableData {
+	return &ArbiterPolicy{
+		MaxAmount:  x.Coins(p.MaxAmount).Clone(),
+		Recipients: p.Recipients,
+	}
+}
+
+// Approves returns an error unless the policy allows to release
+// amount to the recipient, counting all releases from one escrow
+func (p *ArbiterPolicy) Approves(recipient weave.Address, amount x.Coins) error {
+	if indexOf(p.Recipients, recipient) < 0 {
+		return ErrPolicyDenied("recipient")
+	}
+	for _, c := range amount {
+		var limit *x.Coin
+		for _, max := range p.MaxAmount {
+			if max.SameType(*c) {
+				limit = max
+			}
+		}
+		if limit == nil || !limit.IsGTE(*c) {
+			return ErrPolicyDenied(c.Ticker)
+		}
+	}
+	return nil
+}
+
+// AsArbiterPolicy safely extracts an ArbiterPolicy value from the object
+func AsArbiterPolicy(obj orm.Object) *ArbiterPolicy {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*ArbiterPolicy)
+}
+
+// PolicyBucket holds the policies of arbiters, keyed by
+// their address
+type PolicyBucket struct {
+	orm.Bucket
+}
+
+// NewPolicyBucket initializes a PolicyBucket with default name
+func NewPolicyBucket() PolicyBucket {
+	return PolicyBucket{
+		Bucket: orm.NewBucket(BucketNamePolicies,
+			orm.NewSimpleObj(nil, new(ArbiterPolicy))),
+	}
+}
+
+// Policy returns the policy of the arbiter, nil if it has none
+func (b PolicyBucket) Policy(db weave.ReadOnlyKVStore, arbiter weave.Address) (*ArbiterPolicy, error) {
+	obj, err := b.Get(db, arbiter)
+	if err != nil {
+		return nil, err
+	}
+	return AsArbiterPolicy(obj), nil
+}
+
+//---- set policy
+
+// SetArbiterPolicyHandler stores the policy of an arbiter
+type SetArbiterPolicyHandler struct {
+	auth     x.Authenticator
+	policies PolicyBucket
+}
+
+var _ weave.Handler = SetArbiterPolicyHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h SetArbiterPolicyHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += setPolicyCost
+	return res, nil
+}
+
+// Deliver replaces the policy of the main signer, or removes it
+// if the message has none
+func (h SetArbiterPolicyHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, arbiter, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	key := arbiter.Address()
+	if msg.Policy == nil {
+		return res, h.policies.Delete(db, key)
+	}
+	return res, h.policies.Save(db, orm.NewSimpleObj(key, msg.Policy))
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h SetArbiterPolicyHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*SetArbiterPolicyMsg, weave.Permission, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*SetArbiterPolicyMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	arbiter := x.MainSigner(ctx, h.auth)
+	if arbiter == nil {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	return msg, arbiter, nil
+}
+
+// approvedByPolicy checks a release asked for with the policy
+// flag. One of the parties must ask for it, and the policy of the
+// arbiter must approve paying the amount to the recipient, added
+// to what was released with the policy before.
+// Priced escrows are never approved, as their amount is only
+// known on release.
+func approvedByPolicy(ctx weave.Context, db weave.ReadOnlyKVStore, auth x.Authenticator,
+	policies PolicyBucket, escrow *Escrow, amount x.Coins) error {
+
+	sender := weave.Permission(escrow.Sender).Address()
+	rcpt := weave.Permission(escrow.Recipient).Address()
+	if !auth.HasAddress(ctx, sender) && !auth.HasAddress(ctx, rcpt) {
+		return errors.ErrUnauthorized()
+	}
+	if escrow.Arbiter == nil {
+		return ErrPolicyDenied("no arbiter")
+	}
+	if escrow.Target != nil {
+		return ErrPolicyDenied("priced escrow")
+	}
+	policy, err := policies.Policy(db, weave.Permission(escrow.Arbiter).Address())
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return ErrPolicyDenied("no policy")
+	}
+	if len(amount) == 0 {
+		amount = escrow.Amount
+	}
+	amount, err = x.Coins(escrow.PolicyReleased).Combine(amount)
+	if err != nil {
+		return err
+	}
+	// every payee of a split escrow must be approved
+	for _, addr := range ShareAddresses(escrow.Shares) {
+		if err := policy.Approves(addr, amount); err != nil {
+			return err
+		}
+	}
+	return policy.Approves(rcpt, amount)
+}
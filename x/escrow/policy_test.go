@@ -0,0 +1,130 @@
+package escrow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestArbiterPolicy(t *testing.T) {
+	var helpers x.TestHelpers
+	_, sender := helpers.MakeKey()
+	_, arbiter := helpers.MakeKey()
+	_, rcpt := helpers.MakeKey()
+	_, other := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), control, NewBucket())
+	at := func(height int64, perms ...weave.Permission) weave.Context {
+		ctx := weave.WithHeight(context.Background(), height)
+		return authenticator().SetPermissions(ctx, perms...)
+	}
+
+	db := store.MemStore()
+	wallet, err := cash.WalletWith(sender.Address(),
+		&x.Coin{Whole: 100, Ticker: "FOO"}, &x.Coin{Whole: 100, Ticker: "BAR"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, wallet))
+	deliver := func(msg weave.Msg, perms ...weave.Permission) ([]byte, error) {
+		tx := helpers.MockTx(msg)
+		_, err := r.Check(at(10, perms...), db, tx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := r.Deliver(at(10, perms...), db, tx)
+		return res.Data, err
+	}
+	create := func(to weave.Permission, amount ...x.Coin) []byte {
+		msg := NewCreateMsg(sender, to, arbiter, mustCombineCoins(amount...), 1000, "")
+		id, err := deliver(msg, sender)
+		require.NoError(t, err)
+		return id
+	}
+	release := func(id []byte, perm weave.Permission, amount ...x.Coin) error {
+		msg := &ReleaseEscrowMsg{EscrowId: id, Policy: true}
+		if len(amount) > 0 {
+			msg.Amount = mustCombineCoins(amount...)
+		}
+		_, err := deliver(msg, perm)
+		return err
+	}
+
+	small := create(rcpt, x.NewCoin(5, 0, "FOO"))
+	big := create(rcpt, x.NewCoin(50, 0, "FOO"))
+	mixed := create(rcpt, x.NewCoin(5, 0, "FOO"), x.NewCoin(1, 0, "BAR"))
+	elsewhere := create(other, x.NewCoin(5, 0, "FOO"))
+
+	// nothing is approved without a policy
+	err = release(small, rcpt)
+	assert.True(t, IsPolicyDeniedErr(err), "%+v", err)
+
+	policy := &ArbiterPolicy{
+		MaxAmount:  []*x.Coin{{Whole: 10, Ticker: "FOO"}},
+		Recipients: [][]byte{rcpt.Address()},
+	}
+	_, err = deliver(&SetArbiterPolicyMsg{Policy: &ArbiterPolicy{}}, arbiter)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+	_, err = deliver(&SetArbiterPolicyMsg{Policy: policy}, arbiter)
+	require.NoError(t, err)
+	stored, err := NewPolicyBucket().Policy(db, arbiter.Address())
+	require.NoError(t, err)
+	assert.Equal(t, policy, stored)
+
+	// only the parties may ask for it
+	err = release(small, other)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	// beyond the policy
+	err = release(big, rcpt)
+	assert.True(t, IsPolicyDeniedErr(err), "%+v", err)
+	err = release(mixed, rcpt)
+	assert.True(t, IsPolicyDeniedErr(err), "%+v", err)
+	err = release(elsewhere, sender)
+	assert.True(t, IsPolicyDeniedErr(err), "%+v", err)
+
+	// within it, in parts or in full
+	require.NoError(t, release(big, rcpt, x.NewCoin(10, 0, "FOO")))
+	require.NoError(t, release(mixed, rcpt, x.NewCoin(5, 0, "FOO")))
+	require.NoError(t, release(small, sender))
+	coins, err := control.Balance(db, rcpt.Address())
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(20, 0, "FOO")), coins)
+	obj, err := NewBucket().Get(db, small)
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+
+	// the limit counts all releases from an escrow
+	err = release(big, rcpt, x.NewCoin(1, 0, "FOO"))
+	assert.True(t, IsPolicyDeniedErr(err), "%+v", err)
+	parts := create(rcpt, x.NewCoin(20, 0, "FOO"))
+	require.NoError(t, release(parts, rcpt, x.NewCoin(6, 0, "FOO")))
+	err = release(parts, rcpt, x.NewCoin(5, 0, "FOO"))
+	assert.True(t, IsPolicyDeniedErr(err), "%+v", err)
+	require.NoError(t, release(parts, rcpt, x.NewCoin(4, 0, "FOO")))
+	obj, err = NewBucket().Get(db, parts)
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(10, 0, "FOO")),
+		x.Coins(AsEscrow(obj).PolicyReleased))
+
+	// without the flag the arbiter must still sign
+	_, err = deliver(&ReleaseEscrowMsg{EscrowId: big}, rcpt)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+
+	// removing the policy stops all approvals
+	_, err = deliver(&SetArbiterPolicyMsg{}, arbiter)
+	require.NoError(t, err)
+	err = release(big, rcpt, x.NewCoin(1, 0, "FOO"))
+	assert.True(t, IsPolicyDeniedErr(err), "%+v", err)
+}
@@ -0,0 +1,79 @@
+package escrow
+
+import (
+	"math/big"
+
+	"github.com/confio/weave/x"
+)
+
+// fracUnit is the number of fractional units in one whole coin
+const fracUnit = 1000000000
+
+// validateTarget checks the pricing fields of an escrow, all
+// of which are optional
+func validateTarget(amount x.Coins, target, min, max *x.Coin) error {
+	if target == nil {
+		if min != nil || max != nil {
+			return ErrInvalidTarget("price bounds need a target")
+		}
+		return nil
+	}
+	if !target.IsPositive() {
+		return ErrInvalidTarget("target must be positive")
+	}
+	if err := target.Validate(); err != nil {
+		return err
+	}
+	if len(amount) != 1 {
+		return ErrInvalidTarget("amount must be a single coin")
+	}
+	if amount[0].Ticker == target.Ticker {
+		return ErrInvalidTarget("amount already in target currency")
+	}
+	for _, bound := range []*x.Coin{min, max} {
+		if bound == nil {
+			continue
+		}
+		if bound.Ticker != target.Ticker || !bound.IsPositive() {
+			return ErrInvalidTarget("bounds must be positive, in target currency")
+		}
+		if err := bound.Validate(); err != nil {
+			return err
+		}
+	}
+	if min != nil && max != nil && min.Compare(*max) > 0 {
+		return ErrInvalidTarget("min price above max price")
+	}
+	return nil
+}
+
+// checkBounds returns an error if the rate is outside of the
+// bounds set on the escrow
+func checkBounds(rate x.Coin, min, max *x.Coin) error {
+	if min != nil && rate.Compare(*min) < 0 {
+		return ErrPriceOutOfBounds(rate)
+	}
+	if max != nil && rate.Compare(*max) > 0 {
+		return ErrPriceOutOfBounds(rate)
+	}
+	return nil
+}
+
+// coinsFor returns how much of available is worth target, given the
+// price of one available coin. It never returns more than available,
+// and rounds down to the smallest unit.
+func coinsFor(target, rate, available x.Coin) x.Coin {
+	due := new(big.Int).Mul(units(target), big.NewInt(fracUnit))
+	due.Quo(due, units(rate))
+	if due.Cmp(units(available)) >= 0 {
+		return available
+	}
+	n := due.Int64()
+	return x.NewCoin(n/fracUnit, n%fracUnit, available.Ticker)
+}
+
+// units returns the value of the coin in fractional units
+func units(c x.Coin) *big.Int {
+	n := new(big.Int).Mul(big.NewInt(c.Whole), big.NewInt(fracUnit))
+	return n.Add(n, big.NewInt(c.Fractional))
+}
@@ -0,0 +1,90 @@
+package escrow
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/confio/weave/x"
+)
+
+func TestValidateTarget(t *testing.T) {
+	iov := mustCombineCoins(x.NewCoin(100, 0, "IOV"))
+	usd := x.NewCoin(50, 0, "USD")
+	low := x.NewCoin(0, 400000000, "USD")
+	high := x.NewCoin(0, 600000000, "USD")
+
+	cases := []struct {
+		amount      x.Coins
+		target      *x.Coin
+		min, max    *x.Coin
+		expectedErr bool
+	}{
+		0: {iov, nil, nil, nil, false},
+		1: {iov, &usd, nil, nil, false},
+		2: {iov, &usd, &low, &high, false},
+		3: {iov, &usd, &low, nil, false},
+		// bounds without target
+		4: {iov, nil, &low, nil, true},
+		// target in the escrowed currency
+		5: {iov, &x.Coin{Whole: 10, Ticker: "IOV"}, nil, nil, true},
+		// more than one coin escrowed
+		6: {mustCombineCoins(x.NewCoin(1, 0, "IOV"), x.NewCoin(1, 0, "ETH")), &usd, nil, nil, true},
+		// bound in wrong currency
+		7: {iov, &usd, &x.Coin{Whole: 1, Ticker: "EUR"}, nil, true},
+		// min above max
+		8: {iov, &usd, &high, &low, true},
+		// non-positive target
+		9: {iov, &x.Coin{Ticker: "USD"}, nil, nil, true},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			err := validateTarget(tc.amount, tc.target, tc.min, tc.max)
+			if tc.expectedErr {
+				assert.True(t, IsInvalidPriceErr(err), "%+v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCoinsFor(t *testing.T) {
+	cases := []struct {
+		target, rate, available x.Coin
+		expected                x.Coin
+	}{
+		// 50 USD at 0.5 USD/IOV is 100 IOV
+		0: {x.NewCoin(50, 0, "USD"), x.NewCoin(0, 500000000, "USD"),
+			x.NewCoin(150, 0, "IOV"), x.NewCoin(100, 0, "IOV")},
+		// capped at what is available
+		1: {x.NewCoin(50, 0, "USD"), x.NewCoin(0, 250000000, "USD"),
+			x.NewCoin(150, 0, "IOV"), x.NewCoin(150, 0, "IOV")},
+		// rounds down to the smallest unit: 1 USD at 3 USD/ETH
+		2: {x.NewCoin(1, 0, "USD"), x.NewCoin(3, 0, "USD"),
+			x.NewCoin(1, 0, "ETH"), x.NewCoin(0, 333333333, "ETH")},
+		// large values do not overflow
+		3: {x.NewCoin(900000000000, 0, "USD"), x.NewCoin(0, 1, "USD"),
+			x.NewCoin(1, 0, "ETH"), x.NewCoin(1, 0, "ETH")},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			got := coinsFor(tc.target, tc.rate, tc.available)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestCheckBounds(t *testing.T) {
+	low := x.NewCoin(1, 0, "USD")
+	high := x.NewCoin(2, 0, "USD")
+
+	assert.NoError(t, checkBounds(x.NewCoin(1, 500000000, "USD"), &low, &high))
+	assert.NoError(t, checkBounds(low, &low, &high))
+	assert.NoError(t, checkBounds(x.NewCoin(100, 0, "USD"), &low, nil))
+	assert.True(t, IsInvalidPriceErr(checkBounds(x.NewCoin(0, 1, "USD"), &low, nil)))
+	assert.True(t, IsInvalidPriceErr(checkBounds(x.NewCoin(3, 0, "USD"), nil, &high)))
+}
@@ -0,0 +1,354 @@
+package escrow
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/tracing"
+	"github.com/iov-one/bcp-demo/x/ledger"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/rbac"
+	"github.com/iov-one/bcp-demo/x/scheduler"
+)
+
+const (
+	quarantineEscrowCost int64 = 50
+)
+
+// Validate ensures the quarantine has a reason and a height
+func (q *Quarantine) Validate() error {
+	if err := validateReason(q.Reason); err != nil {
+		return err
+	}
+	if q.Height <= 0 {
+		return ErrInvalidReason("height")
+	}
+	return nil
+}
+
+// validateReason makes sure an authority overruling the
+// parties explains why
+func validateReason(reason string) error {
+	if reason == "" || len(reason) > maxMemoSize {
+		return ErrInvalidReason(reason)
+	}
+	return nil
+}
+
+// checkOpen returns an error if the escrow is quarantined,
+// as then only an admin may act on it
+func checkOpen(id []byte, escrow *Escrow) error {
+	if escrow.Quarantine != nil {
+		return ErrQuarantined(id)
+	}
+	return nil
+}
+
+// loadForAdmin loads the escrow for an admin remediation. The
+// escrow must be quarantined or not, as the action expects.
+func loadForAdmin(ctx weave.Context, db weave.KVStore, auth rbac.Authenticator,
+	bucket Store, id []byte, quarantined bool) (orm.Object, error) {
+
+	err := auth.RequireRole(ctx, db, rbac.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := bucket.Get(db, id)
+	if err != nil {
+		return nil, err
+	}
+	escrow := AsEscrow(obj)
+	switch {
+	case escrow == nil:
+		return nil, ErrNoSuchEscrow(id)
+	case quarantined && escrow.Quarantine == nil:
+		return nil, ErrNotQuarantined(id)
+	case !quarantined && escrow.Quarantine != nil:
+		return nil, ErrQuarantined(id)
+	}
+	return obj, nil
+}
+
+//---- quarantine
+
+// QuarantineEscrowHandler lets admins freeze a suspicious escrow
+type QuarantineEscrowHandler struct {
+	auth    rbac.Authenticator
+	bucket  Store
+	history HistoryBucket
+}
+
+var _ weave.Handler = QuarantineEscrowHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h QuarantineEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += quarantineEscrowCost
+	return res, nil
+}
+
+// Deliver marks the escrow quarantined. The coins stay where
+// they are, it just can't be used anymore.
+func (h QuarantineEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	height, _ := weave.GetHeight(ctx)
+	AsEscrow(obj).Quarantine = &Quarantine{Reason: msg.Reason, Height: height}
+	err = h.bucket.Save(db, obj)
+	if err != nil {
+		return res, err
+	}
+	err = h.history.AppendNote(ctx, db, h.auth, obj.Key(), EventQuarantine, nil, msg.Reason)
+	if err != nil {
+		return res, err
+	}
+	res.Tags = append(res.Tags, EventTag(EventQuarantine, obj.Key()))
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h QuarantineEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*QuarantineEscrowMsg, orm.Object, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*QuarantineEscrowMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+	obj, err := loadForAdmin(ctx, db, h.auth, h.bucket, msg.EscrowId, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	return msg, obj, nil
+}
+
+//---- restore
+
+// RestoreEscrowHandler lets admins lift a quarantine
+type RestoreEscrowHandler struct {
+	auth        rbac.Authenticator
+	bucket      Store
+	heartbeats  HeartbeatBucket
+	escalations EscalationBucket
+	jobs        scheduler.Bucket
+	history     HistoryBucket
+}
+
+var _ weave.Handler = RestoreEscrowHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h RestoreEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += quarantineEscrowCost
+	return res, nil
+}
+
+// Deliver lifts the quarantine. The parties get back the blocks
+// it was frozen for, and a dead man's switch a full window, as
+// the Ticker dropped any heartbeat that fell due meanwhile. So
+// does the arbiter of a dispute, for the same reason, and the
+// escrow expires again at its new timeout.
+func (h RestoreEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	escrow := AsEscrow(obj)
+
+	height, _ := weave.GetHeight(ctx)
+	escrow.Timeout += height - escrow.Quarantine.Height
+	escrow.Quarantine = nil
+	err = h.bucket.Save(db, obj)
+	if err != nil {
+		return res, err
+	}
+	if escrow.HeartbeatWindow > 0 {
+		err = h.heartbeats.Beat(db, obj.Key(), height, escrow.HeartbeatWindow)
+		if err != nil {
+			return res, err
+		}
+	}
+	if escrow.Dispute != nil && escrow.SlaBlocks > 0 {
+		err = h.escalations.Schedule(db, obj.Key(), height+escrow.SlaBlocks)
+		if err != nil {
+			return res, err
+		}
+	}
+	err = scheduleExpiry(db, h.jobs, obj)
+	if err != nil {
+		return res, err
+	}
+	err = h.history.Append(ctx, db, h.auth, obj.Key(), EventRestore, nil)
+	if err != nil {
+		return res, err
+	}
+	res.Tags = append(res.Tags, EventTag(EventRestore, obj.Key()))
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h RestoreEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (orm.Object, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*RestoreEscrowMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return loadForAdmin(ctx, db, h.auth, h.bucket, msg.EscrowId, true)
+}
+
+//---- force settle
+
+// ForceSettleEscrowHandler lets admins close a quarantined escrow
+type ForceSettleEscrowHandler struct {
+	auth    rbac.Authenticator
+	bucket  Store
+	history HistoryBucket
+	bids    BidBucket
+	cash    namecoin.Controller
+}
+
+var _ weave.Handler = ForceSettleEscrowHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h ForceSettleEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += quarantineEscrowCost
+	return res, nil
+}
+
+// Deliver pays the release to the recipient, or the shares, and
+// returns the rest to the sender. The deposit and the bounty go
+// back to the sender too, as for a refund.
+func (h ForceSettleEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	escrow := AsEscrow(obj)
+
+	rest, err := subtractCoins(escrow.Amount, msg.Release)
+	if err != nil {
+		return res, err
+	}
+	src := NewCondition(obj.Key()).Address()
+	sender := weave.Permission(escrow.Sender).Address()
+	var transfers []namecoin.Transfer
+	if len(msg.Release) > 0 {
+		transfers = append(transfers, payTransfers(escrow, src, msg.Release)...)
+	}
+	transfers = append(transfers, namecoin.NewTransfers(src, sender, rest)...)
+	transfers = append(transfers, depositTransfers(obj, sender)...)
+	transfers = append(transfers, bountyTransfers(obj, false)...)
+	err = h.cash.WithContext(ctx).MoveCoinsBatch(db, transfers)
+	if err != nil {
+		return res, err
+	}
+
+	err = h.history.AppendNote(ctx, db, h.auth, obj.Key(), EventSettle,
+		msg.Release, escrow.Quarantine.Reason)
+	if err != nil {
+		return res, err
+	}
+	if rest.IsPositive() {
+		err = h.history.Append(ctx, db, h.auth, obj.Key(), EventReturn, rest)
+		if err != nil {
+			return res, err
+		}
+	}
+	err = deleteBids(db, h.bids, obj)
+	if err != nil {
+		return res, err
+	}
+	err = h.bucket.Delete(db, obj.Key())
+	if err != nil {
+		return res, err
+	}
+	res.Tags = append(res.Tags, EventTag(EventSettle, obj.Key()))
+	res.Tags = append(res.Tags, ledger.TransferTags(ledger.KindIn, transfers)...)
+	res.Tags = append(res.Tags, destTags(escrow, transfers)...)
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h ForceSettleEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*ForceSettleEscrowMsg, orm.Object, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*ForceSettleEscrowMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+	obj, err := loadForAdmin(ctx, db, h.auth, h.bucket, msg.EscrowId, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	// the release can't be more than the escrow holds
+	if !containsCoins(AsEscrow(obj).Amount, msg.Release) {
+		return nil, nil, cash.ErrInsufficientFunds()
+	}
+	return msg, obj, nil
+}
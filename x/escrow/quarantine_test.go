@@ -0,0 +1,219 @@
+package escrow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/rbac"
+)
+
+// TestQuarantine freezes an escrow, restores it with the lost
+// blocks, and then force-settles a second one
+func TestQuarantine(t *testing.T) {
+	var helpers x.TestHelpers
+	_, sender := helpers.MakeKey()
+	_, arbiter := helpers.MakeKey()
+	_, rcpt := helpers.MakeKey()
+	_, admin := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), control, NewBucket())
+
+	db := store.MemStore()
+	wallet, err := cash.WalletWith(sender.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, wallet))
+	require.NoError(t, rbac.NewBucket().Assign(db, admin.Address(), rbac.RoleAdmin))
+	deliver := func(height int64, msg weave.Msg, perm weave.Permission) ([]byte, error) {
+		ctx := weave.WithHeight(context.Background(), height)
+		ctx = authenticator().SetPermissions(ctx, perm)
+		tx := helpers.MockTx(msg)
+		_, err := r.Check(ctx, db, tx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := r.Deliver(ctx, db, tx)
+		return res.Data, err
+	}
+	balance := func(addr weave.Address) x.Coins {
+		coins, err := control.Balance(db, addr)
+		require.NoError(t, err)
+		return coins
+	}
+	load := func(id []byte) *Escrow {
+		obj, err := NewBucket().Get(db, id)
+		require.NoError(t, err)
+		return AsEscrow(obj)
+	}
+
+	create := NewCreateMsg(sender, rcpt, arbiter,
+		mustCombineCoins(x.NewCoin(10, 0, "FOO")), 100, "order")
+	id, err := deliver(10, create, sender)
+	require.NoError(t, err)
+
+	// only admins may quarantine, and must say why
+	quarantine := &QuarantineEscrowMsg{EscrowId: id, Reason: "stolen key reported"}
+	_, err = deliver(20, quarantine, sender)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = deliver(20, &QuarantineEscrowMsg{EscrowId: id}, admin)
+	assert.Error(t, err)
+	_, err = deliver(20, quarantine, admin)
+	require.NoError(t, err)
+	_, err = deliver(21, quarantine, admin)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+
+	// all other actions are frozen
+	frozen := []struct {
+		msg  weave.Msg
+		perm weave.Permission
+	}{
+		{&ReleaseEscrowMsg{EscrowId: id}, arbiter},
+		{&ReturnEscrowMsg{EscrowId: id}, rcpt},
+		{&UpdateEscrowPartiesMsg{EscrowId: id, Arbiter: rcpt}, arbiter},
+		{&ForceSettleEscrowMsg{EscrowId: id}, sender},
+	}
+	for _, f := range frozen {
+		_, err = deliver(30, f.msg, f.perm)
+		assert.Error(t, err, "%T", f.msg)
+	}
+	// not even after the timeout
+	_, err = deliver(200, &ReturnEscrowMsg{EscrowId: id}, sender)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+
+	// restore gives back the 40 blocks it was frozen for
+	_, err = deliver(60, &RestoreEscrowMsg{EscrowId: id}, sender)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = deliver(60, &RestoreEscrowMsg{EscrowId: id}, admin)
+	require.NoError(t, err)
+	escrow := load(id)
+	assert.Nil(t, escrow.Quarantine)
+	assert.Equal(t, int64(140), escrow.Timeout)
+	_, err = deliver(60, &RestoreEscrowMsg{EscrowId: id}, admin)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+	_, err = deliver(130, &ReleaseEscrowMsg{EscrowId: id,
+		Amount: mustCombineCoins(x.NewCoin(4, 0, "FOO"))}, arbiter)
+	require.NoError(t, err)
+
+	// settle pays the release and returns the rest
+	_, err = deliver(130, &QuarantineEscrowMsg{EscrowId: id, Reason: "dispute"}, admin)
+	require.NoError(t, err)
+	_, err = deliver(140, &ForceSettleEscrowMsg{EscrowId: id,
+		Release: mustCombineCoins(x.NewCoin(7, 0, "FOO"))}, admin)
+	assert.Error(t, err)
+	_, err = deliver(140, &ForceSettleEscrowMsg{EscrowId: id,
+		Release: mustCombineCoins(x.NewCoin(1, 0, "FOO"))}, admin)
+	require.NoError(t, err)
+	assert.Nil(t, load(id))
+	assert.Equal(t, mustCombineCoins(x.NewCoin(5, 0, "FOO")), balance(rcpt.Address()))
+	assert.Equal(t, mustCombineCoins(x.NewCoin(95, 0, "FOO")), balance(sender.Address()))
+
+	// the history shows who did what and why
+	entries, err := NewHistoryBucket().History(db, id)
+	require.NoError(t, err)
+	var events []string
+	for _, e := range entries {
+		events = append(events, e.Event)
+	}
+	assert.Equal(t, []string{EventCreate, EventQuarantine, EventRestore, EventRelease,
+		EventQuarantine, EventSettle, EventReturn}, events)
+	assert.Equal(t, "stolen key reported", entries[1].Note)
+	assert.Equal(t, admin.Address(), weave.Address(entries[1].Actor))
+	assert.Equal(t, "dispute", entries[5].Note)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(1, 0, "FOO")), x.Coins(entries[5].Amount))
+}
+
+// TestQuarantineHeartbeat makes sure a frozen dead man's switch
+// is not released, and gets a full window once restored
+func TestQuarantineHeartbeat(t *testing.T) {
+	var helpers x.TestHelpers
+	_, sender := helpers.MakeKey()
+	_, arbiter := helpers.MakeKey()
+	_, rcpt := helpers.MakeKey()
+	_, admin := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), control, NewBucket())
+
+	db := store.MemStore()
+	wallet, err := cash.WalletWith(sender.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, wallet))
+	require.NoError(t, rbac.NewBucket().Assign(db, admin.Address(), rbac.RoleAdmin))
+	deliver := func(height int64, msg weave.Msg, perm weave.Permission) error {
+		ctx := weave.WithHeight(context.Background(), height)
+		ctx = authenticator().SetPermissions(ctx, perm)
+		_, err := r.Deliver(ctx, db, helpers.MockTx(msg))
+		return err
+	}
+	tick := func(height int64) {
+		_, err := NewTicker(control).Tick(weave.WithHeight(context.Background(), height), db)
+		require.NoError(t, err)
+	}
+
+	create := NewCreateMsg(sender, rcpt, arbiter,
+		mustCombineCoins(x.NewCoin(10, 0, "FOO")), 1000, "will")
+	create.HeartbeatWindow = 50
+	ctx := weave.WithHeight(context.Background(), 10)
+	ctx = authenticator().SetPermissions(ctx, sender)
+	res, err := r.Deliver(ctx, db, helpers.MockTx(create))
+	require.NoError(t, err)
+	id := res.Data
+
+	require.NoError(t, deliver(20, &QuarantineEscrowMsg{EscrowId: id, Reason: "probate"}, admin))
+	assert.Error(t, deliver(30, &PingEscrowMsg{EscrowId: id}, sender))
+	tick(100)
+	obj, err := NewBucket().Get(db, id)
+	require.NoError(t, err)
+	require.NotNil(t, obj)
+
+	require.NoError(t, deliver(200, &RestoreEscrowMsg{EscrowId: id}, admin))
+	hb, err := NewHeartbeatBucket().Get(db, id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(250), AsHeartbeat(hb).Due)
+}
+
+func TestQuarantineValidate(t *testing.T) {
+	id := make([]byte, 8)
+	long := string(make([]byte, maxMemoSize+1))
+
+	cases := map[string]struct {
+		msg   weave.Msg
+		valid bool
+	}{
+		"quarantine": {&QuarantineEscrowMsg{EscrowId: id, Reason: "fraud"}, true},
+		"no reason":  {&QuarantineEscrowMsg{EscrowId: id}, false},
+		"long":       {&QuarantineEscrowMsg{EscrowId: id, Reason: long}, false},
+		"no id":      {&QuarantineEscrowMsg{Reason: "fraud"}, false},
+		"restore":    {&RestoreEscrowMsg{EscrowId: id}, true},
+		"settle":     {&ForceSettleEscrowMsg{EscrowId: id}, true},
+		"release": {&ForceSettleEscrowMsg{EscrowId: id,
+			Release: mustCombineCoins(x.NewCoin(1, 0, "FOO"))}, true},
+		"negative": {&ForceSettleEscrowMsg{EscrowId: id,
+			Release: mustCombineCoins(x.NewCoin(-1, 0, "FOO"))}, false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.msg.(interface{ Validate() error }).Validate()
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
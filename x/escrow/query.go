@@ -0,0 +1,101 @@
+package escrow
+
+import (
+	"encoding/binary"
+	"strconv"
+	"strings"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+)
+
+const (
+	// QueryExpiring is the path of the ExpiringQuery
+	QueryExpiring = "/escrows/expiring"
+	// QueryID is the path of the IDQuery
+	QueryID = "/escrows/id"
+
+	beforeMod = "before="
+)
+
+// ExpiringQuery returns all escrows that time out before a
+// given height, using the timeout index.
+//
+// The height is either passed in the path, as
+// "/escrows/expiring?before=H", or as 8 byte big endian data
+// with no modifier.
+type ExpiringQuery struct {
+	bucket Bucket
+}
+
+var _ weave.QueryHandler = ExpiringQuery{}
+
+// NewExpiringQuery creates a query handler for the given bucket
+func NewExpiringQuery(bucket Bucket) ExpiringQuery {
+	return ExpiringQuery{bucket: bucket}
+}
+
+// Query implements weave.QueryHandler
+func (q ExpiringQuery) Query(db weave.ReadOnlyKVStore, mod string,
+	data []byte) ([]weave.Model, error) {
+
+	height, err := parseBefore(mod, data)
+	if err != nil {
+		return nil, err
+	}
+	objs, err := q.bucket.ExpiringBefore(db, height)
+	if err != nil {
+		return nil, err
+	}
+	return toModels(q.bucket, objs)
+}
+
+func parseBefore(mod string, data []byte) (int64, error) {
+	switch {
+	case strings.HasPrefix(mod, beforeMod):
+		height, err := strconv.ParseInt(strings.TrimPrefix(mod, beforeMod), 10, 64)
+		if err != nil {
+			return 0, ErrInvalidQuery(mod)
+		}
+		return height, nil
+	case mod == weave.KeyQueryMod && len(data) == 8:
+		return int64(binary.BigEndian.Uint64(data)), nil
+	default:
+		return 0, ErrInvalidQuery(mod)
+	}
+}
+
+// IDQuery returns the escrow with the encoded id, as written by
+// EncodeID, given as data and no modifier
+type IDQuery struct {
+	bucket Bucket
+}
+
+var _ weave.QueryHandler = IDQuery{}
+
+// Query implements weave.QueryHandler
+func (q IDQuery) Query(db weave.ReadOnlyKVStore, mod string,
+	data []byte) ([]weave.Model, error) {
+
+	if mod != weave.KeyQueryMod {
+		return nil, ErrInvalidQuery(mod)
+	}
+	id, err := ParseID(string(data))
+	if err != nil {
+		return nil, err
+	}
+	return q.bucket.Query(db, weave.KeyQueryMod, id)
+}
+
+// toModels serializes the objects to return them from a query
+func toModels(bucket Bucket, objs []orm.Object) ([]weave.Model, error) {
+	res := make([]weave.Model, len(objs))
+	for i, obj := range objs {
+		bz, err := obj.Value().Marshal()
+		if err != nil {
+			return nil, err
+		}
+		res[i] = weave.Model{Key: bucket.DBKey(obj.Key()), Value: bz}
+	}
+	return res, nil
+}
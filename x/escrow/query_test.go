@@ -0,0 +1,104 @@
+package escrow
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+)
+
+func TestExpiringQuery(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	amount := mustCombineCoins(x.NewCoin(10, 0, "FOO"))
+
+	bucket := NewBucket()
+	db := store.MemStore()
+	var ids [][]byte
+	for _, timeout := range []int64{30, 10, 20, 20} {
+		esc := &Escrow{Sender: a, Recipient: b, Arbiter: a,
+			Amount: amount, Timeout: timeout}
+		obj, err := bucket.Create(db, esc)
+		require.NoError(t, err)
+		ids = append(ids, obj.Key())
+	}
+	// deleted escrows are removed from the index
+	require.NoError(t, bucket.Delete(db, ids[3]))
+
+	qr := weave.NewQueryRouter()
+	RegisterQuery(qr)
+	h := qr.Handler(QueryExpiring)
+	require.NotNil(t, h)
+
+	cases := []struct {
+		mod      string
+		data     []byte
+		isError  bool
+		expected [][]byte
+	}{
+		0: {"before=10", nil, false, nil},
+		1: {"before=11", nil, false, [][]byte{ids[1]}},
+		2: {"before=21", nil, false, [][]byte{ids[1], ids[2]}},
+		3: {"before=1000", nil, false, [][]byte{ids[1], ids[2], ids[0]}},
+		4: {"", timeoutKey(21), false, [][]byte{ids[1], ids[2]}},
+		5: {"before=-5", nil, false, nil},
+		6: {"before=soon", nil, true, nil},
+		7: {"", []byte{1, 2}, true, nil},
+		8: {"prefix", timeoutKey(21), true, nil},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			models, err := h.Query(db, tc.mod, tc.data)
+			if tc.isError {
+				require.Error(t, err)
+				assert.True(t, IsInvalidQueryErr(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, len(tc.expected), len(models))
+			for j, id := range tc.expected {
+				assert.Equal(t, bucket.DBKey(id), models[j].Key)
+				obj, err := bucket.Parse(nil, models[j].Value)
+				require.NoError(t, err)
+				assert.NotNil(t, AsEscrow(obj))
+			}
+		})
+	}
+}
+
+func TestIDQuery(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+
+	bucket := NewBucket()
+	db := store.MemStore()
+	obj, err := bucket.Create(db, &Escrow{Sender: a, Recipient: b, Arbiter: a,
+		Amount: mustCombineCoins(x.NewCoin(10, 0, "FOO")), Timeout: 30})
+	require.NoError(t, err)
+
+	qr := weave.NewQueryRouter()
+	RegisterQuery(qr)
+	h := qr.Handler(QueryID)
+	require.NotNil(t, h)
+
+	models, err := h.Query(db, "", []byte(EncodeID(obj.Key())))
+	require.NoError(t, err)
+	require.Len(t, models, 1)
+	assert.Equal(t, bucket.DBKey(obj.Key()), models[0].Key)
+
+	models, err = h.Query(db, "", []byte(EncodeID(SeqCondition(99).ID())))
+	require.NoError(t, err)
+	assert.Len(t, models, 0)
+	_, err = h.Query(db, "", obj.Key())
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+	_, err = h.Query(db, "prefix", []byte(EncodeID(obj.Key())))
+	assert.Error(t, err)
+}
@@ -0,0 +1,99 @@
+package escrow
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/tracing"
+	"github.com/iov-one/bcp-demo/x/ledger"
+)
+
+// SendEscrowHandler creates an escrow from a transfer. It is
+// the create handler, fed by the signer of the src address.
+type SendEscrowHandler struct {
+	auth   x.Authenticator
+	create CreateEscrowHandler
+}
+
+var _ weave.Handler = SendEscrowHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h SendEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	msg, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	params, err := h.create.params.Load(db)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost, as for any other escrow
+	res.GasAllocated += createEscrowCost + params.StorageGas(msg)
+	return res, nil
+}
+
+// Deliver moves the coins from src into a new escrow,
+// both or nothing
+func (h SendEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	obj, transfers, err := h.create.create(ctx, db, msg)
+	if err != nil {
+		return res, err
+	}
+
+	// return id of escrow to use in future calls
+	res.Data = obj.Key()
+	res.Tags = append(res.Tags, ledger.TransferTags(ledger.KindOut, transfers)...)
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver,
+// it returns the message creating the escrow
+func (h SendEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*CreateEscrowMsg, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*SendEscrowMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	// the escrow is sent by the signer paying, as src
+	// is only an address
+	sender := signerOf(ctx, h.auth, msg.Src)
+	if sender == nil {
+		return nil, errors.ErrUnauthorized()
+	}
+	cmsg := msg.CreateMsg(sender)
+	return cmsg, h.create.check(ctx, db, cmsg)
+}
+
+// signerOf returns the permission that signed for addr,
+// nil if there is none
+func signerOf(ctx weave.Context, auth x.Authenticator, addr weave.Address) weave.Permission {
+	for _, perm := range auth.GetPermissions(ctx) {
+		if perm.Address().Equals(addr) {
+			return perm
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package escrow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// TestSendEscrow pays out a withdrawal of an exchange into an
+// escrow, with the one signature of the exchange
+func TestSendEscrow(t *testing.T) {
+	var helpers x.TestHelpers
+	_, exchange := helpers.MakeKey()
+	_, user := helpers.MakeKey()
+	_, arbiter := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), control, NewBucket())
+
+	db := store.MemStore()
+	wallet, err := cash.WalletWith(exchange.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, wallet))
+	deliver := func(msg weave.Msg, perm weave.Permission) ([]byte, error) {
+		ctx := weave.WithHeight(context.Background(), 10)
+		ctx = authenticator().SetPermissions(ctx, perm)
+		tx := helpers.MockTx(msg)
+		_, err := r.Check(ctx, db, tx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := r.Deliver(ctx, db, tx)
+		return res.Data, err
+	}
+	balance := func(addr weave.Address) x.Coins {
+		coins, err := control.Balance(db, addr)
+		require.NoError(t, err)
+		return coins
+	}
+
+	amount := x.NewCoin(30, 0, "FOO")
+	msg := &SendEscrowMsg{
+		Src:    exchange.Address(),
+		Dest:   user,
+		Amount: &amount,
+		Memo:   "withdrawal 42",
+		Escrow: &EscrowInstructions{Arbiter: arbiter, Timeout: 500},
+	}
+
+	// only the owner of src can send
+	_, err = deliver(msg, user)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+
+	id, err := deliver(msg, exchange)
+	require.NoError(t, err)
+	obj, err := NewBucket().Get(db, id)
+	require.NoError(t, err)
+	escrow := AsEscrow(obj)
+	require.NotNil(t, escrow)
+	assert.Equal(t, exchange, weave.Permission(escrow.Sender))
+	assert.Equal(t, user, weave.Permission(escrow.Recipient))
+	assert.Equal(t, arbiter, weave.Permission(escrow.Arbiter))
+	assert.Equal(t, int64(500), escrow.Timeout)
+	assert.Equal(t, "withdrawal 42", escrow.Memo)
+	assert.Equal(t, mustCombineCoins(amount), balance(NewCondition(id).Address()))
+	assert.Equal(t, mustCombineCoins(x.NewCoin(70, 0, "FOO")), balance(exchange.Address()))
+
+	// nothing moves if the escrow can't be created
+	expired := *msg
+	expired.Escrow = &EscrowInstructions{Arbiter: arbiter, Timeout: 5}
+	_, err = deliver(&expired, exchange)
+	assert.Error(t, err)
+	tooMuch := *msg
+	tooMuch.Amount = &x.Coin{Whole: 80, Ticker: "FOO"}
+	_, err = deliver(&tooMuch, exchange)
+	assert.Error(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(70, 0, "FOO")), balance(exchange.Address()))
+}
+
+func TestSendEscrowValidate(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	amount := x.NewCoin(5, 0, "FOO")
+	terms := &EscrowInstructions{Arbiter: b, Timeout: 100}
+
+	cases := map[string]struct {
+		msg   *SendEscrowMsg
+		valid bool
+	}{
+		"valid":     {&SendEscrowMsg{Src: a.Address(), Dest: b, Amount: &amount, Escrow: terms}, true},
+		"no terms":  {&SendEscrowMsg{Src: a.Address(), Dest: b, Amount: &amount}, false},
+		"no amount": {&SendEscrowMsg{Src: a.Address(), Dest: b, Escrow: terms}, false},
+		"no src":    {&SendEscrowMsg{Dest: b, Amount: &amount, Escrow: terms}, false},
+		"no dest":   {&SendEscrowMsg{Src: a.Address(), Amount: &amount, Escrow: terms}, false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.msg.Validate()
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
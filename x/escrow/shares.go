@@ -0,0 +1,121 @@
+package escrow
+
+import (
+	"math/big"
+
+	"github.com/tendermint/tmlibs/common"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/deposit"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+const (
+	// totalBps is what the shares of an escrow add up to
+	totalBps  int32 = 10000
+	maxShares int   = 8
+)
+
+// validateShares makes sure every share pays a distinct
+// address and all of them add up to totalBps
+func validateShares(shares []*Share) error {
+	if len(shares) == 0 {
+		return nil
+	}
+	if len(shares) > maxShares {
+		return ErrInvalidShares("too many")
+	}
+	var sum int32
+	addrs := make([][]byte, 0, len(shares))
+	for _, s := range shares {
+		if s == nil || s.Bps <= 0 || s.Bps > totalBps {
+			return ErrInvalidShares("bps")
+		}
+		if err := weave.Address(s.Address).Validate(); err != nil {
+			return err
+		}
+		if indexOf(addrs, s.Address) >= 0 {
+			return ErrInvalidShares("duplicate address")
+		}
+		addrs = append(addrs, s.Address)
+		sum += s.Bps
+	}
+	if sum != totalBps {
+		return ErrInvalidShares("not 100%")
+	}
+	return nil
+}
+
+// ShareAddresses returns the addresses paid by the shares
+func ShareAddresses(shares []*Share) [][]byte {
+	res := make([][]byte, len(shares))
+	for i, s := range shares {
+		res[i] = s.Address
+	}
+	return res
+}
+
+// payTransfers pays amount from src to the recipient of the
+// escrow, or splits it between the shares if it has any
+func payTransfers(escrow *Escrow, src weave.Address, amount x.Coins) []namecoin.Transfer {
+	if len(escrow.Shares) == 0 {
+		return namecoin.NewTransfers(src, weave.Permission(escrow.Recipient).Address(), amount)
+	}
+	var res []namecoin.Transfer
+	for _, c := range amount {
+		for i, part := range splitCoin(*c, escrow.Shares) {
+			if part.IsPositive() {
+				res = append(res, namecoin.Transfer{
+					Src: src, Dest: escrow.Shares[i].Address, Amount: part})
+			}
+		}
+	}
+	return res
+}
+
+// destTags tags the payment to the recipient among the transfers
+// with the destination tag of the escrow, see package x/deposit
+func destTags(escrow *Escrow, transfers []namecoin.Transfer) []common.KVPair {
+	if escrow.DestTag == "" {
+		return nil
+	}
+	rcpt := weave.Permission(escrow.Recipient).Address()
+	for _, t := range transfers {
+		if t.Dest.Equals(rcpt) && t.Amount.IsPositive() {
+			return []common.KVPair{deposit.Tag(rcpt, escrow.DestTag)}
+		}
+	}
+	return nil
+}
+
+// splitCoin divides c between the shares. Every share gets its
+// part rounded down to the smallest unit, the first share gets
+// what is left over, so the parts always add up to c.
+func splitCoin(c x.Coin, shares []*Share) []x.Coin {
+	total := units(c)
+	rest := new(big.Int).Set(total)
+	res := make([]x.Coin, len(shares))
+	for i, s := range shares {
+		n := new(big.Int).Mul(total, big.NewInt(int64(s.Bps)))
+		n.Quo(n, big.NewInt(int64(totalBps)))
+		rest.Sub(rest, n)
+		res[i] = fromUnits(n, c)
+	}
+	first := new(big.Int).Add(units(res[0]), rest)
+	res[0] = fromUnits(first, c)
+	return res
+}
+
+// fromUnits is the coin of the same token as c worth n
+// fractional units
+func fromUnits(n *big.Int, c x.Coin) x.Coin {
+	whole, frac := new(big.Int).QuoRem(n, big.NewInt(fracUnit), new(big.Int))
+	return x.Coin{
+		Whole:      whole.Int64(),
+		Fractional: frac.Int64(),
+		Ticker:     c.Ticker,
+		Issuer:     c.Issuer,
+	}
+}
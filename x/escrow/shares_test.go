@@ -0,0 +1,201 @@
+package escrow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tmlibs/common"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/deposit"
+	"github.com/iov-one/bcp-demo/x/ledger"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// TestSplitRelease pays a marketplace sale to the seller and
+// the commission of the platform, and returns the rest to the
+// buyer alone
+func TestSplitRelease(t *testing.T) {
+	var helpers x.TestHelpers
+	_, buyer := helpers.MakeKey()
+	_, seller := helpers.MakeKey()
+	_, platform := helpers.MakeKey()
+	_, arbiter := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), control, NewBucket())
+
+	db := store.MemStore()
+	wallet, err := cash.WalletWith(buyer.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, wallet))
+	deliver := func(height int64, msg weave.Msg, perm weave.Permission) ([]byte, error) {
+		ctx := weave.WithHeight(context.Background(), height)
+		ctx = authenticator().SetPermissions(ctx, perm)
+		tx := helpers.MockTx(msg)
+		_, err := r.Check(ctx, db, tx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := r.Deliver(ctx, db, tx)
+		return res.Data, err
+	}
+	balance := func(addr weave.Address) x.Coins {
+		coins, err := control.Balance(db, addr)
+		require.NoError(t, err)
+		return coins
+	}
+
+	msg := NewCreateMsg(buyer, seller, arbiter,
+		mustCombineCoins(x.NewCoin(10, 0, "FOO")), 1000, "order")
+	msg.Shares = []*Share{
+		{Address: seller.Address(), Bps: 9750},
+		{Address: platform.Address(), Bps: 250},
+	}
+	id, err := deliver(10, msg, buyer)
+	require.NoError(t, err)
+
+	// the odd unit left over by rounding goes to the seller
+	_, err = deliver(20, &ReleaseEscrowMsg{EscrowId: id,
+		Amount: mustCombineCoins(x.NewCoin(0, 3, "FOO"))}, arbiter)
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(0, 3, "FOO")), balance(seller.Address()))
+	assert.Nil(t, balance(platform.Address()))
+
+	_, err = deliver(20, &ReleaseEscrowMsg{EscrowId: id,
+		Amount: mustCombineCoins(x.NewCoin(4, 0, "FOO"))}, arbiter)
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(3, 900000003, "FOO")), balance(seller.Address()))
+	assert.Equal(t, mustCombineCoins(x.NewCoin(0, 100000000, "FOO")), balance(platform.Address()))
+
+	// the rest goes back to the buyer
+	_, err = deliver(2000, &ReturnEscrowMsg{EscrowId: id}, buyer)
+	require.NoError(t, err)
+	assert.Equal(t, mustCombineCoins(x.NewCoin(95, 999999997, "FOO")), balance(buyer.Address()))
+	assert.Equal(t, mustCombineCoins(x.NewCoin(0, 100000000, "FOO")), balance(platform.Address()))
+}
+
+// TestDestTags tags the payments to an exchange with the
+// account of the seller there
+func TestDestTags(t *testing.T) {
+	var helpers x.TestHelpers
+	_, buyer := helpers.MakeKey()
+	_, exchange := helpers.MakeKey()
+	_, platform := helpers.MakeKey()
+	_, arbiter := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), control, NewBucket())
+
+	db := store.MemStore()
+	wallet, err := cash.WalletWith(buyer.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, wallet))
+	deliver := func(height int64, msg weave.Msg, perm weave.Permission) weave.DeliverResult {
+		ctx := weave.WithHeight(context.Background(), height)
+		ctx = authenticator().SetPermissions(ctx, perm)
+		res, err := r.Deliver(ctx, db, helpers.MockTx(msg))
+		require.NoError(t, err)
+		return res
+	}
+	tag := deposit.Tag(exchange.Address(), "104882")
+
+	msg := NewCreateMsg(buyer, exchange, arbiter,
+		mustCombineCoins(x.NewCoin(10, 0, "FOO")), 1000, "order")
+	msg.DestTag = "104882"
+	id := deliver(10, msg, buyer).Data
+	res := deliver(20, &ReleaseEscrowMsg{EscrowId: id,
+		Amount: mustCombineCoins(x.NewCoin(4, 0, "FOO"))}, arbiter)
+	assert.Equal(t, "104882", deposit.Find(res.Tags, exchange.Address()))
+	res = deliver(20, &ReleaseEscrowMsg{EscrowId: id}, arbiter)
+	assert.Contains(t, res.Tags, tag)
+
+	// only the share of the recipient is tagged, the ledger
+	// has both
+	msg.Shares = []*Share{
+		{Address: exchange.Address(), Bps: 9750},
+		{Address: platform.Address(), Bps: 250},
+	}
+	id = deliver(30, msg, buyer).Data
+	res = deliver(40, &ReleaseEscrowMsg{EscrowId: id}, arbiter)
+	assert.Equal(t, []common.KVPair{
+		ledger.Tag(ledger.KindIn, exchange.Address(), x.NewCoin(9, 750000000, "FOO")),
+		ledger.Tag(ledger.KindIn, platform.Address(), x.NewCoin(0, 250000000, "FOO")),
+		tag,
+	}, res.Tags)
+
+	// without a tag nothing is tagged
+	msg.Shares, msg.DestTag = nil, ""
+	id = deliver(50, msg, buyer).Data
+	res = deliver(60, &ReleaseEscrowMsg{EscrowId: id}, arbiter)
+	assert.Empty(t, deposit.Find(res.Tags, exchange.Address()))
+}
+
+func TestSplitCoin(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	_, c := helpers.MakeKey()
+	thirds := []*Share{
+		{Address: a.Address(), Bps: 3334},
+		{Address: b.Address(), Bps: 3333},
+		{Address: c.Address(), Bps: 3333},
+	}
+
+	parts := splitCoin(x.NewCoin(0, 10, "FOO"), thirds)
+	assert.Equal(t, []x.Coin{
+		x.NewCoin(0, 4, "FOO"), x.NewCoin(0, 3, "FOO"), x.NewCoin(0, 3, "FOO"),
+	}, parts)
+
+	parts = splitCoin(x.NewCoin(100, 0, "FOO"), thirds)
+	assert.Equal(t, []x.Coin{
+		x.NewCoin(33, 340000000, "FOO"), x.NewCoin(33, 330000000, "FOO"),
+		x.NewCoin(33, 330000000, "FOO"),
+	}, parts)
+
+	// the parts keep the issuer
+	issued := x.Coin{Whole: 1, Ticker: "FOO", Issuer: "chain"}
+	for _, part := range splitCoin(issued, thirds) {
+		assert.True(t, part.SameType(issued))
+	}
+}
+
+func TestValidateShares(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+
+	cases := map[string]struct {
+		shares []*Share
+		valid  bool
+	}{
+		"none":      {nil, true},
+		"one":       {[]*Share{{Address: a.Address(), Bps: 10000}}, true},
+		"split":     {[]*Share{{Address: a.Address(), Bps: 9000}, {Address: b.Address(), Bps: 1000}}, true},
+		"short":     {[]*Share{{Address: a.Address(), Bps: 9000}}, false},
+		"zero":      {[]*Share{{Address: a.Address(), Bps: 10000}, {Address: b.Address()}}, false},
+		"duplicate": {[]*Share{{Address: a.Address(), Bps: 5000}, {Address: a.Address(), Bps: 5000}}, false},
+		"no addr":   {[]*Share{{Bps: 10000}}, false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateShares(tc.shares)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package escrow
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/tracing"
+)
+
+const (
+	// BucketNameEscalations is where we store when disputes
+	// go to the backup arbiter
+	BucketNameEscalations = "escsla"
+
+	disputeEscrowCost int64 = 50
+)
+
+var _ orm.CloneableData = (*Escalation)(nil)
+
+// Validate ensures the dispute has a reason and a height
+func (d *Dispute) Validate() error {
+	if err := validateReason(d.Reason); err != nil {
+		return err
+	}
+	if d.Height <= 0 || d.Escalated < 0 {
+		return ErrInvalidDispute("height")
+	}
+	return nil
+}
+
+// Validate ensures the escalation is due at some height
+func (e *Escalation) Validate() error {
+	if e.Due <= 0 {
+		return ErrInvalidDispute("due")
+	}
+	return nil
+}
+
+// Copy makes a new escalation with the same value
+func (e *Escalation) Copy() orm.CloneableData {
+	return &Escalation{Due: e.Due}
+}
+
+// AsEscalation safely extracts an Escalation value from the object
+func AsEscalation(obj orm.Object) *Escalation {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Escalation)
+}
+
+// validateSLA checks the backup arbiter of an escrow. It only
+// makes sense with the blocks the arbiter has for a dispute,
+// and the other way round.
+func validateSLA(e *Escrow) error {
+	switch {
+	case e.SlaBlocks < 0:
+		return ErrInvalidDispute("sla blocks")
+	case (e.BackupArbiter == nil) != (e.SlaBlocks == 0):
+		return ErrInvalidDispute("backup arbiter without sla blocks")
+	case e.BackupArbiter != nil && weave.Permission(e.BackupArbiter).Equals(e.Arbiter):
+		return ErrInvalidDispute("backup is the arbiter")
+	}
+	return nil
+}
+
+// EscalationBucket holds when the disputes of escrows go to the
+// backup arbiter, keyed by the escrow id. An escalation may
+// outlive its dispute until it is due, the Ticker removes it then.
+type EscalationBucket struct {
+	orm.Bucket
+	due orm.Index
+}
+
+// NewEscalationBucket initializes an EscalationBucket with default name
+func NewEscalationBucket() EscalationBucket {
+	bucket := orm.NewBucket(BucketNameEscalations,
+		orm.NewSimpleObj(nil, new(Escalation))).
+		WithIndex(IndexDue, idxEscalationDue, false)
+	return EscalationBucket{
+		Bucket: bucket,
+		// must match the name orm.Bucket.WithIndex uses
+		due: orm.NewIndex(BucketNameEscalations+"_"+IndexDue, idxEscalationDue, false, nil),
+	}
+}
+
+func idxEscalationDue(obj orm.Object) ([]byte, error) {
+	e := AsEscalation(obj)
+	if e == nil {
+		return nil, errors.ErrInternal("Can only take index of Escalation")
+	}
+	return timeoutKey(e.Due), nil
+}
+
+// Schedule sets the dispute of the escrow with the id to go to
+// the backup arbiter after the given height
+func (b EscalationBucket) Schedule(db weave.KVStore, id []byte, due int64) error {
+	return b.Save(db, orm.NewSimpleObj(id, &Escalation{Due: due}))
+}
+
+// DueBefore returns up to limit escalations due before the
+// given height, the ones due first come first
+func (b EscalationBucket) DueBefore(db weave.ReadOnlyKVStore, height int64,
+	limit int) ([]orm.Object, error) {
+	return dueBefore(db, b.Bucket, b.due, height, limit)
+}
+
+//---- dispute
+
+// DisputeEscrowHandler lets the sender or the recipient call
+// on the arbiter
+type DisputeEscrowHandler struct {
+	auth        x.Authenticator
+	bucket      Store
+	escalations EscalationBucket
+	history     HistoryBucket
+}
+
+var _ weave.Handler = DisputeEscrowHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h DisputeEscrowHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += disputeEscrowCost
+	return res, nil
+}
+
+// Deliver records the dispute and, for an escrow with a backup
+// arbiter, when it escalates
+func (h DisputeEscrowHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	escrow := AsEscrow(obj)
+
+	height, _ := weave.GetHeight(ctx)
+	escrow.Dispute = &Dispute{Reason: msg.Reason, Height: height}
+	err = h.bucket.Save(db, obj)
+	if err != nil {
+		return res, err
+	}
+	if escrow.SlaBlocks > 0 {
+		err = h.escalations.Schedule(db, obj.Key(), height+escrow.SlaBlocks)
+		if err != nil {
+			return res, err
+		}
+	}
+	err = h.history.AppendNote(ctx, db, h.auth, obj.Key(), EventDispute, nil, msg.Reason)
+	if err != nil {
+		return res, err
+	}
+	res.Tags = append(res.Tags, EventTag(EventDispute, obj.Key()))
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h DisputeEscrowHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*DisputeEscrowMsg, orm.Object, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*DisputeEscrowMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := h.bucket.Get(db, msg.EscrowId)
+	if err != nil {
+		return nil, nil, err
+	}
+	escrow := AsEscrow(obj)
+	if escrow == nil {
+		return nil, nil, ErrNoSuchEscrow(msg.EscrowId)
+	}
+	if err := checkOpen(obj.Key(), escrow); err != nil {
+		return nil, nil, err
+	}
+	// an escrow waiting for bids has no one to call on yet
+	if escrow.Arbiter == nil {
+		return nil, nil, ErrInvalidDispute("no arbiter")
+	}
+	if escrow.Dispute != nil {
+		return nil, nil, ErrInvalidDispute("already disputed")
+	}
+
+	sender := weave.Permission(escrow.Sender).Address()
+	recipient := weave.Permission(escrow.Recipient).Address()
+	if !h.auth.HasAddress(ctx, sender) && !h.auth.HasAddress(ctx, recipient) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+
+	height, _ := weave.GetHeight(ctx)
+	if escrow.Timeout < height {
+		return nil, nil, ErrEscrowExpired(escrow.Timeout)
+	}
+	return msg, obj, nil
+}
+
+//---- escalate on missed sla
+
+// escalate hands the dispute of the escrow to its backup
+// arbiter, who has no backup itself
+func (t Ticker) escalate(ctx weave.Context, db weave.KVStore, obj orm.Object) error {
+	escrow := AsEscrow(obj)
+	height, _ := weave.GetHeight(ctx)
+	escrow.Arbiter = escrow.BackupArbiter
+	escrow.BackupArbiter = nil
+	escrow.SlaBlocks = 0
+	escrow.Dispute.Escalated = height
+	err := t.bucket.Save(db, obj)
+	if err != nil {
+		return err
+	}
+	// no one signs for the ticker
+	return t.history.Append(ctx, db, x.ChainAuth(), obj.Key(), EventEscalate, nil)
+}
@@ -0,0 +1,161 @@
+package escrow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// TestEscalation hands a dispute the arbiter sits on to the
+// backup arbiter
+func TestEscalation(t *testing.T) {
+	var helpers x.TestHelpers
+	_, sender := helpers.MakeKey()
+	_, arbiter := helpers.MakeKey()
+	_, panel := helpers.MakeKey()
+	_, rcpt := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), control, NewBucket())
+	ticker := NewTicker(control)
+
+	db := store.MemStore()
+	wallet, err := cash.WalletWith(sender.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, wallet))
+	deliver := func(height int64, msg weave.Msg, perm weave.Permission) ([]byte, error) {
+		ctx := weave.WithHeight(context.Background(), height)
+		ctx = authenticator().SetPermissions(ctx, perm)
+		tx := helpers.MockTx(msg)
+		_, err := r.Check(ctx, db, tx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := r.Deliver(ctx, db, tx)
+		return res.Data, err
+	}
+	tick := func(height int64) {
+		_, err := ticker.Tick(weave.WithHeight(context.Background(), height), db)
+		require.NoError(t, err)
+	}
+	load := func(id []byte) *Escrow {
+		obj, err := NewBucket().Get(db, id)
+		require.NoError(t, err)
+		return AsEscrow(obj)
+	}
+	create := func(backup weave.Permission, sla int64) []byte {
+		msg := NewCreateMsg(sender, rcpt, arbiter,
+			mustCombineCoins(x.NewCoin(20, 0, "FOO")), 1000, "goods")
+		msg.BackupArbiter = backup
+		msg.SlaBlocks = sla
+		id, err := deliver(10, msg, sender)
+		require.NoError(t, err)
+		return id
+	}
+	dispute := func(height int64, id []byte, perm weave.Permission) error {
+		_, err := deliver(height, &DisputeEscrowMsg{EscrowId: id, Reason: "not delivered"}, perm)
+		return err
+	}
+
+	slow := create(panel, 50)
+	// only the sender and the recipient dispute, and only once
+	err = dispute(20, slow, arbiter)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	require.NoError(t, dispute(20, slow, rcpt))
+	err = dispute(30, slow, sender)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+	assert.Equal(t, &Dispute{Reason: "not delivered", Height: 20}, load(slow).Dispute)
+
+	// the arbiter has until 70
+	tick(70)
+	assert.Equal(t, arbiter, weave.Permission(load(slow).Arbiter))
+	tick(71)
+	escrow := load(slow)
+	assert.Equal(t, panel, weave.Permission(escrow.Arbiter))
+	assert.Nil(t, escrow.BackupArbiter)
+	assert.Equal(t, int64(0), escrow.SlaBlocks)
+	assert.Equal(t, &Dispute{Reason: "not delivered", Height: 20, Escalated: 71}, escrow.Dispute)
+	history, err := NewHistoryBucket().History(db, slow)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, EventDispute, history[1].Event)
+	assert.Equal(t, "not delivered", history[1].Note)
+	assert.Equal(t, EventEscalate, history[2].Event)
+	assert.Nil(t, history[2].Actor)
+
+	// the panel decides now
+	_, err = deliver(80, &ReleaseEscrowMsg{EscrowId: slow}, arbiter)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = deliver(80, &ReleaseEscrowMsg{EscrowId: slow}, panel)
+	require.NoError(t, err)
+	assert.Nil(t, load(slow))
+
+	// a release settles the dispute in time
+	quick := create(panel, 50)
+	require.NoError(t, dispute(100, quick, sender))
+	_, err = deliver(120, &ReleaseEscrowMsg{EscrowId: quick,
+		Amount: mustCombineCoins(x.NewCoin(5, 0, "FOO"))}, arbiter)
+	require.NoError(t, err)
+	assert.Nil(t, load(quick).Dispute)
+	tick(200)
+	assert.Equal(t, arbiter, weave.Permission(load(quick).Arbiter))
+	due, err := NewEscalationBucket().DueBefore(db, 1000, 10)
+	require.NoError(t, err)
+	assert.Len(t, due, 0)
+
+	// without a backup the dispute never escalates
+	plain := create(nil, 0)
+	require.NoError(t, dispute(300, plain, rcpt))
+	due, err = NewEscalationBucket().DueBefore(db, 1000, 10)
+	require.NoError(t, err)
+	assert.Len(t, due, 0)
+}
+
+func TestSLATerms(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	_, c := helpers.MakeKey()
+	plus := mustCombineCoins(x.NewCoin(5, 0, "FOO"))
+
+	cases := []struct {
+		backup weave.Permission
+		sla    int64
+		valid  bool
+	}{
+		{nil, 0, true},
+		{c, 10, true},
+		{c, 0, false},
+		{nil, 10, false},
+		{c, -1, false},
+		// the backup must be someone else
+		{b, 10, false},
+	}
+	for i, tc := range cases {
+		msg := NewCreateMsg(a, b, b, plus, 100, "")
+		msg.BackupArbiter = tc.backup
+		msg.SlaBlocks = tc.sla
+		err := msg.Validate()
+		if tc.valid {
+			assert.NoError(t, err, "case %d", i)
+		} else {
+			assert.True(t, IsInvalidMetadataErr(err), "case %d: %+v", i, err)
+		}
+	}
+
+	assert.Error(t, (&DisputeEscrowMsg{EscrowId: []byte("12345678")}).Validate())
+	assert.NoError(t, (&DisputeEscrowMsg{EscrowId: []byte("12345678"), Reason: "late"}).Validate())
+}
@@ -0,0 +1,224 @@
+package escrow
+
+import (
+	"regexp"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/tracing"
+	"github.com/iov-one/bcp-demo/x/rbac"
+)
+
+const (
+	// BucketNameTemplates is where we store the escrow templates
+	BucketNameTemplates = "esctmpl"
+
+	setTemplateCost int64 = 50
+
+	// blocksPerDay assumes 5 second blocks, it only sets the
+	// timeouts of the default templates
+	blocksPerDay int64 = 17280
+)
+
+// Template ids are short names, eg. "otc-trade"
+var isTemplateID = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`).MatchString
+
+// DefaultTemplates are the presets shipped in the genesis
+// of new chains. They leave the bounty unset, as it depends
+// on the tokens of the chain.
+func DefaultTemplates() map[string]*EscrowTemplate {
+	return map[string]*EscrowTemplate{
+		// the client pays once the milestone is delivered,
+		// the arbiter decides a dispute
+		"freelance-milestone": {
+			Description:      "Payment for a milestone, released by the client or the arbiter",
+			TimeoutIn:        30 * blocksPerDay,
+			SenderCanRelease: true,
+		},
+		// the landlord returns the deposit at the end of the
+		// lease, or the arbiter releases what the damage costs
+		"rental-deposit": {
+			Description: "Rental deposit, returned by the landlord at the end of the lease",
+			TimeoutIn:   365 * blocksPerDay,
+		},
+		// the buyer releases once the other side of the
+		// trade has arrived, or gets the coins back soon
+		"otc-trade": {
+			Description:      "One side of an over the counter trade, settled within a day",
+			TimeoutIn:        blocksPerDay,
+			SenderCanRelease: true,
+		},
+	}
+}
+
+var _ orm.CloneableData = (*EscrowTemplate)(nil)
+
+// Validate ensures the template sets a timeout and valid coins
+func (t *EscrowTemplate) Validate() error {
+	if t.TimeoutIn <= 0 {
+		return ErrInvalidTemplate("timeout")
+	}
+	if len(t.Description) > maxMemoSize {
+		return ErrInvalidTemplate("description too long")
+	}
+	if t.Bounty != nil {
+		if err := validateAmount(x.Coins{t.Bounty}); err != nil {
+			return err
+		}
+	}
+	for i, c := range t.MaxAmount {
+		if c == nil || !c.IsPositive() {
+			return ErrInvalidTemplate("max amount")
+		}
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		for _, other := range t.MaxAmount[:i] {
+			if c.SameType(*other) {
+				return ErrInvalidTemplate("duplicate ticker")
+			}
+		}
+	}
+	return nil
+}
+
+// Copy makes a new template with the same values
+func (t *EscrowTemplate) Copy() orm.CloneableData {
+	return &EscrowTemplate{
+		Description:      t.Description,
+		TimeoutIn:        t.TimeoutIn,
+		SenderCanRelease: t.SenderCanRelease,
+		Bounty:           t.Bounty,
+		MaxAmount:        x.Coins(t.MaxAmount).Clone(),
+	}
+}
+
+// Allows returns an error if the template limits the amount
+// and it is over the limit of any ticker
+func (t *EscrowTemplate) Allows(amount x.Coins) error {
+	if len(t.MaxAmount) == 0 {
+		return nil
+	}
+	for _, c := range amount {
+		limit := findTicker(t.MaxAmount, c.Ticker)
+		if limit == nil || !limit.IsGTE(*c) {
+			return ErrInvalidTemplate("over max amount of " + c.Ticker)
+		}
+	}
+	return nil
+}
+
+// CreateMsg adapts a CreateFromTemplateMsg to the CreateEscrowMsg
+// the handler works on. The timeout counts from height.
+func (t *EscrowTemplate) CreateMsg(m *CreateFromTemplateMsg, height int64) *CreateEscrowMsg {
+	msg := NewCreateMsg(m.Sender, m.Recipient, m.Arbiter,
+		m.Amount, height+t.TimeoutIn, m.Memo)
+	msg.SenderCanRelease = t.SenderCanRelease
+	msg.Bounty = t.Bounty
+	return msg
+}
+
+// AsEscrowTemplate safely extracts an EscrowTemplate value from the object
+func AsEscrowTemplate(obj orm.Object) *EscrowTemplate {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*EscrowTemplate)
+}
+
+// TemplateBucket holds the escrow templates, keyed by their id
+type TemplateBucket struct {
+	orm.Bucket
+}
+
+// NewTemplateBucket initializes a TemplateBucket with default name
+func NewTemplateBucket() TemplateBucket {
+	return TemplateBucket{
+		Bucket: orm.NewBucket(BucketNameTemplates,
+			orm.NewSimpleObj(nil, new(EscrowTemplate))),
+	}
+}
+
+// Template returns the template with the id, or an error
+// if there is none
+func (b TemplateBucket) Template(db weave.ReadOnlyKVStore, id string) (*EscrowTemplate, error) {
+	obj, err := b.Get(db, []byte(id))
+	if err != nil {
+		return nil, err
+	}
+	t := AsEscrowTemplate(obj)
+	if t == nil {
+		return nil, ErrInvalidTemplate("no template " + id)
+	}
+	return t, nil
+}
+
+//---- set template
+
+// SetTemplateHandler lets admins maintain the templates
+type SetTemplateHandler struct {
+	auth      rbac.Authenticator
+	templates TemplateBucket
+}
+
+var _ weave.Handler = SetTemplateHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h SetTemplateHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += setTemplateCost
+	return res, nil
+}
+
+// Deliver replaces the template, or removes it if the message
+// has none. Escrows created from it keep their terms.
+func (h SetTemplateHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	key := []byte(msg.TemplateId)
+	if msg.Template == nil {
+		return res, h.templates.Delete(db, key)
+	}
+	return res, h.templates.Save(db, orm.NewSimpleObj(key, msg.Template))
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h SetTemplateHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*SetTemplateMsg, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*SetTemplateMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	err = h.auth.RequireRole(ctx, db, rbac.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
@@ -0,0 +1,120 @@
+package escrow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/rbac"
+)
+
+func TestDefaultTemplates(t *testing.T) {
+	templates := DefaultTemplates()
+	assert.Len(t, templates, 3)
+	for id, tmpl := range templates {
+		assert.True(t, isTemplateID(id), id)
+		assert.NoError(t, tmpl.Validate(), id)
+	}
+
+	opts, err := BuildTemplatesGenesis(templates)
+	require.NoError(t, err)
+	db := store.MemStore()
+	require.NoError(t, Initializer{}.FromGenesis(opts, db))
+	tmpl, err := NewTemplateBucket().Template(db, "otc-trade")
+	require.NoError(t, err)
+	assert.Equal(t, templates["otc-trade"], tmpl)
+
+	bad := map[string]*EscrowTemplate{"Not An Id": {TimeoutIn: 5}}
+	opts, err = BuildTemplatesGenesis(bad)
+	require.NoError(t, err)
+	assert.Error(t, Initializer{}.FromGenesis(opts, store.MemStore()))
+}
+
+// TestCreateFromTemplate creates escrows with the terms of a
+// template, which admins can change
+func TestCreateFromTemplate(t *testing.T) {
+	var helpers x.TestHelpers
+	_, sender := helpers.MakeKey()
+	_, arbiter := helpers.MakeKey()
+	_, rcpt := helpers.MakeKey()
+	_, admin := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), namecoin.NewWalletController(bank), NewBucket())
+
+	db := store.MemStore()
+	wallet, err := cash.WalletWith(sender.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, wallet))
+	require.NoError(t, rbac.NewBucket().Assign(db, admin.Address(), rbac.RoleAdmin))
+	deliver := func(msg weave.Msg, perm weave.Permission) ([]byte, error) {
+		ctx := weave.WithHeight(context.Background(), 100)
+		ctx = authenticator().SetPermissions(ctx, perm)
+		tx := helpers.MockTx(msg)
+		_, err := r.Check(ctx, db, tx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := r.Deliver(ctx, db, tx)
+		return res.Data, err
+	}
+	create := func(id string, amount int64) ([]byte, error) {
+		return deliver(&CreateFromTemplateMsg{
+			TemplateId: id,
+			Arbiter:    arbiter,
+			Recipient:  rcpt,
+			Amount:     mustCombineCoins(x.NewCoin(amount, 0, "FOO")),
+			Memo:       "milestone 1",
+		}, sender)
+	}
+
+	_, err = create("trade", 5)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+
+	tmpl := &EscrowTemplate{
+		TimeoutIn:        50,
+		SenderCanRelease: true,
+		Bounty:           &x.Coin{Whole: 1, Ticker: "FOO"},
+		MaxAmount:        []*x.Coin{{Whole: 10, Ticker: "FOO"}},
+	}
+	// only admins maintain the templates
+	_, err = deliver(&SetTemplateMsg{TemplateId: "trade", Template: tmpl}, sender)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = deliver(&SetTemplateMsg{TemplateId: "trade", Template: &EscrowTemplate{}}, admin)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+	_, err = deliver(&SetTemplateMsg{TemplateId: "trade", Template: tmpl}, admin)
+	require.NoError(t, err)
+
+	id, err := create("trade", 5)
+	require.NoError(t, err)
+	obj, err := NewBucket().Get(db, id)
+	require.NoError(t, err)
+	escrow := AsEscrow(obj)
+	require.NotNil(t, escrow)
+	assert.Equal(t, int64(150), escrow.Timeout)
+	assert.Equal(t, sender, weave.Permission(escrow.Sender))
+	assert.True(t, escrow.SenderCanRelease)
+	assert.Equal(t, tmpl.Bounty, escrow.Bounty)
+	assert.Equal(t, "milestone 1", escrow.Memo)
+
+	// over the max amount
+	_, err = create("trade", 20)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+
+	// removed templates can't be used anymore
+	_, err = deliver(&SetTemplateMsg{TemplateId: "trade"}, admin)
+	require.NoError(t, err)
+	_, err = create("trade", 5)
+	assert.True(t, IsInvalidMetadataErr(err), "%+v", err)
+}
@@ -0,0 +1,318 @@
+package escrow
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/tracing"
+	"github.com/iov-one/bcp-demo/x/modaccount"
+	"github.com/iov-one/bcp-demo/x/ownership"
+)
+
+const (
+	// RoleSender, RoleArbiter and RoleRecipient are the parties
+	// of an escrow that can be offered to someone else
+	RoleSender    = "sender"
+	RoleArbiter   = "arbiter"
+	RoleRecipient = "recipient"
+
+	// offerKind names escrows in the keys of their offers
+	offerKind = "escrow"
+)
+
+// partyTypes are the permissions a new party may have, as
+// "<extension>/<type>": the keys of x/sigs and x/keys and the
+// preimages of x/hashlock. All others can't sign for it.
+var partyTypes = map[string]bool{
+	"sigs/ed25519":   true,
+	"keys/secp256k1": true,
+	"keys/multisig":  true,
+	"hash/sha256":    true,
+}
+
+// party returns the field of the escrow holding the role,
+// nil if there is no such party
+func party(escrow *Escrow, role string) *[]byte {
+	switch role {
+	case RoleSender:
+		return &escrow.Sender
+	case RoleArbiter:
+		return &escrow.Arbiter
+	case RoleRecipient:
+		return &escrow.Recipient
+	}
+	return nil
+}
+
+// validateRole makes sure the role is a party of an escrow
+func validateRole(role string) error {
+	if party(&Escrow{}, role) == nil {
+		return ErrInvalidRole(role)
+	}
+	return nil
+}
+
+// partyChange is a role the message gives to someone else
+type partyChange struct {
+	role string
+	to   []byte
+}
+
+// partyChanges lists the roles set in the message
+func partyChanges(msg *UpdateEscrowPartiesMsg) []partyChange {
+	all := []partyChange{
+		{RoleSender, msg.Sender},
+		{RoleRecipient, msg.Recipient},
+		{RoleArbiter, msg.Arbiter},
+	}
+	var changes []partyChange
+	for _, c := range all {
+		if c.to != nil {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// validateNewParty makes sure a permission is well formed and
+// of a type that can sign for the role it is offered
+func validateNewParty(perm weave.Permission) error {
+	ext, typ, _, err := perm.Parse()
+	if err != nil {
+		return err
+	}
+	if !partyTypes[ext+"/"+typ] {
+		return ErrUnsupportedParty(perm)
+	}
+	return nil
+}
+
+// checkNewParty makes sure a new party of the escrow is neither
+// the escrow itself nor any other module account, which nobody
+// can sign for
+func checkNewParty(db weave.ReadOnlyKVStore, accounts modaccount.Bucket,
+	id []byte, perm weave.Permission) error {
+
+	addr := perm.Address()
+	if addr.Equals(NewCondition(id).Address()) {
+		return ErrEscrowParty(id)
+	}
+	module, err := accounts.Module(db, addr)
+	if err != nil {
+		return err
+	}
+	if module != "" {
+		return modaccount.ErrModuleAccount(module)
+	}
+	return nil
+}
+
+// offerKey is where the offer of the role on the escrow is stored
+func offerKey(id []byte, role string) []byte {
+	return ownership.Key(offerKind, id, role)
+}
+
+// loadUnexpired loads an escrow the parties may still act on
+func loadUnexpired(ctx weave.Context, db weave.KVStore, bucket Store,
+	id []byte) (orm.Object, error) {
+
+	obj, err := bucket.Get(db, id)
+	if err != nil {
+		return nil, err
+	}
+	escrow := AsEscrow(obj)
+	if escrow == nil {
+		return nil, ErrNoSuchEscrow(id)
+	}
+	if err := checkOpen(id, escrow); err != nil {
+		return nil, err
+	}
+	height, _ := weave.GetHeight(ctx)
+	if height > escrow.Timeout {
+		return nil, ErrEscrowExpired(escrow.Timeout)
+	}
+	return obj, nil
+}
+
+//---- offer
+
+// OfferEscrowPartyHandler lets a party offer its role to
+// someone else
+type OfferEscrowPartyHandler struct {
+	auth     x.Authenticator
+	bucket   Store
+	params   ParamsBucket
+	offers   ownership.Bucket
+	accounts modaccount.Bucket
+	history  HistoryBucket
+}
+
+var _ weave.Handler = OfferEscrowPartyHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h OfferEscrowPartyHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += updateEscrowCost
+	return res, nil
+}
+
+// Deliver stores the offer, the escrow is unchanged until
+// it is accepted within the offer window
+func (h OfferEscrowPartyHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	params, err := h.params.Load(db)
+	if err != nil {
+		return res, err
+	}
+
+	height, _ := weave.GetHeight(ctx)
+	holder := *party(AsEscrow(obj), msg.Role)
+	tag, err := h.offers.Offer(ctx, db, offerKey(obj.Key(), msg.Role), holder, msg.To,
+		params.OfferExpires(height))
+	if err != nil {
+		return res, err
+	}
+	err = h.history.AppendNote(ctx, db, h.auth, obj.Key(), EventOffer, nil, msg.Role)
+	if err != nil {
+		return res, err
+	}
+	res.Tags = append(res.Tags, tag)
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h OfferEscrowPartyHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*OfferEscrowPartyMsg, orm.Object, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*OfferEscrowPartyMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+	obj, err := loadUnexpired(ctx, db, h.bucket, msg.EscrowId)
+	if err != nil {
+		return nil, nil, err
+	}
+	holder := *party(AsEscrow(obj), msg.Role)
+	err = ownership.Authorize(ctx, h.auth, holder)
+	if err != nil {
+		return nil, nil, err
+	}
+	// an offer to no one withdraws it
+	if msg.To != nil {
+		err = checkNewParty(db, h.accounts, obj.Key(), msg.To)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return msg, obj, nil
+}
+
+//---- accept
+
+// AcceptEscrowPartyHandler hands a role of an escrow to
+// the signer it was offered to
+type AcceptEscrowPartyHandler struct {
+	auth    x.Authenticator
+	bucket  Store
+	offers  ownership.Bucket
+	history HistoryBucket
+}
+
+var _ weave.Handler = AcceptEscrowPartyHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h AcceptEscrowPartyHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += updateEscrowCost
+	return res, nil
+}
+
+// Deliver replaces the party and removes the offer
+func (h AcceptEscrowPartyHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	field := party(AsEscrow(obj), msg.Role)
+	to, tag, err := h.offers.Accept(ctx, db, h.auth, offerKey(obj.Key(), msg.Role), *field)
+	if err != nil {
+		return res, err
+	}
+	*field = to
+	err = h.bucket.Save(db, obj)
+	if err != nil {
+		return res, err
+	}
+	err = h.history.AppendNote(ctx, db, h.auth, obj.Key(), EventUpdate, nil, msg.Role)
+	if err != nil {
+		return res, err
+	}
+	res.Tags = append(res.Tags, tag)
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h AcceptEscrowPartyHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*AcceptEscrowPartyMsg, orm.Object, error) {
+
+	defer tracing.Begin(db, "validate").End()
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*AcceptEscrowPartyMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+	obj, err := loadUnexpired(ctx, db, h.bucket, msg.EscrowId)
+	if err != nil {
+		return nil, nil, err
+	}
+	holder := *party(AsEscrow(obj), msg.Role)
+	_, err = h.offers.Offered(ctx, db, h.auth, offerKey(obj.Key(), msg.Role), holder)
+	if err != nil {
+		return nil, nil, err
+	}
+	return msg, obj, nil
+}
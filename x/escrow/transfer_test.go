@@ -0,0 +1,120 @@
+package escrow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/modaccount"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/ownership"
+)
+
+// TestOfferParty hands the arbiter of an escrow over in two steps
+func TestOfferParty(t *testing.T) {
+	var helpers x.TestHelpers
+	_, sender := helpers.MakeKey()
+	_, arbiter := helpers.MakeKey()
+	_, rcpt := helpers.MakeKey()
+	_, successor := helpers.MakeKey()
+	_, typo := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	r := app.NewRouter()
+	RegisterRoutes(r, authenticator(), namecoin.NewWalletController(bank), NewBucket())
+
+	db := store.MemStore()
+	wallet, err := cash.WalletWith(sender.Address(), &x.Coin{Whole: 100, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, wallet))
+	require.NoError(t, NewParamsBucket().Store(db, &Params{OfferWindow: 5}))
+	deliver := func(height int64, msg weave.Msg, perm weave.Permission) error {
+		ctx := weave.WithHeight(context.Background(), height)
+		ctx = authenticator().SetPermissions(ctx, perm)
+		tx := helpers.MockTx(msg)
+		_, err := r.Check(ctx, db, tx)
+		if err != nil {
+			return err
+		}
+		_, err = r.Deliver(ctx, db, tx)
+		return err
+	}
+	res, err := r.Deliver(authenticator().SetPermissions(
+		weave.WithHeight(context.Background(), 10), sender),
+		db, helpers.MockTx(NewCreateMsg(sender, rcpt, arbiter,
+			mustCombineCoins(x.NewCoin(10, 0, "FOO")), 100, "handover")))
+	require.NoError(t, err)
+	id := res.Data
+	arbiterOf := func() weave.Permission {
+		obj, err := NewBucket().Get(db, id)
+		require.NoError(t, err)
+		return AsEscrow(obj).Arbiter
+	}
+
+	// only the arbiter can offer its role
+	offer := &OfferEscrowPartyMsg{EscrowId: id, Role: RoleArbiter, To: typo}
+	assert.True(t, errors.IsUnauthorizedErr(deliver(20, offer, sender)))
+	require.NoError(t, deliver(20, offer, arbiter))
+	assert.Equal(t, arbiter, arbiterOf())
+
+	// the mistake is fixed by a new offer
+	offer.To = successor
+	require.NoError(t, deliver(21, offer, arbiter))
+	accept := &AcceptEscrowPartyMsg{EscrowId: id, Role: RoleArbiter}
+	assert.True(t, errors.IsUnauthorizedErr(deliver(22, accept, typo)))
+	require.NoError(t, deliver(22, accept, successor))
+	assert.Equal(t, successor, arbiterOf())
+	assert.True(t, ownership.IsInvalidOfferErr(deliver(23, accept, successor)))
+
+	// an update is an offer as well, and replaces open ones
+	offer = &OfferEscrowPartyMsg{EscrowId: id, Role: RoleRecipient, To: typo}
+	require.NoError(t, deliver(30, offer, rcpt))
+	require.NoError(t, deliver(31, &UpdateEscrowPartiesMsg{EscrowId: id, Recipient: successor}, rcpt))
+	accept = &AcceptEscrowPartyMsg{EscrowId: id, Role: RoleRecipient}
+	assert.True(t, errors.IsUnauthorizedErr(deliver(32, accept, typo)))
+
+	// which can't be accepted after the offer window
+	assert.True(t, ownership.IsInvalidOfferErr(deliver(37, accept, successor)))
+	obj, err := NewBucket().Get(db, id)
+	require.NoError(t, err)
+	assert.Equal(t, rcpt, weave.Permission(AsEscrow(obj).Recipient))
+
+	entries, err := NewHistoryBucket().History(db, id)
+	require.NoError(t, err)
+	require.Len(t, entries, 6)
+	assert.Equal(t, EventUpdate, entries[3].Event)
+	assert.Equal(t, RoleArbiter, entries[3].Note)
+	assert.Equal(t, successor.Address(), weave.Address(entries[3].Actor))
+
+	bad := &OfferEscrowPartyMsg{EscrowId: id, Role: "observer", To: typo}
+	assert.True(t, IsInvalidMetadataErr(bad.Validate()))
+}
+
+func TestCheckNewParty(t *testing.T) {
+	var helpers x.TestHelpers
+	_, key := helpers.MakeKey()
+	id := []byte{0, 0, 0, 0, 0, 0, 0, 1}
+	other := []byte{0, 0, 0, 0, 0, 0, 0, 2}
+
+	db := store.MemStore()
+	accounts := modaccount.NewBucket()
+	_, err := accounts.Open(db, Account, other)
+	require.NoError(t, err)
+
+	assert.NoError(t, checkNewParty(db, accounts, id, key))
+	err = checkNewParty(db, accounts, id, NewCondition(id).Permission())
+	assert.True(t, IsInvalidPartyErr(err), "%+v", err)
+	err = checkNewParty(db, accounts, id, NewCondition(other).Permission())
+	assert.True(t, modaccount.IsModuleAccountErr(err), "%+v", err)
+	err = checkNewParty(db, accounts, id, modaccount.Permission(modaccount.FeeCollector))
+	assert.True(t, modaccount.IsModuleAccountErr(err), "%+v", err)
+}
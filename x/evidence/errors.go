@@ -0,0 +1,25 @@
+package evidence
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// evidence takes 1190-1200
+const (
+	CodeInvalidEvidence = 1190
+)
+
+var (
+	errInvalidEvidence = fmt.Errorf("Invalid evidence")
+)
+
+func ErrInvalidEvidence(reason string) error {
+	return errors.WithLog(reason, errInvalidEvidence, CodeInvalidEvidence)
+}
+func IsInvalidEvidenceErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidEvidence)
+}
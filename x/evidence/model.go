@@ -0,0 +1,128 @@
+/*
+Package evidence records the validators that tendermint reports
+for signing two blocks at the same height, so operators can audit
+equivocations with the "/evidence" query.
+
+The Recorder stores the evidence of every block, each misbehavior
+once, and passes new ones to a Penalizer. There is no staking yet,
+so the app only records and logs them. Once bonds exist, a
+Penalizer can slash them without changing how evidence is read.
+*/
+package evidence
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+)
+
+const (
+	// BucketName is where we store the evidence
+	BucketName = "evidence"
+	// IndexValidator lists the misbehavior of a pub key
+	IndexValidator = "validator"
+)
+
+func init() {
+	keyspace.Buckets("evidence", BucketName)
+}
+
+var _ orm.CloneableData = (*Misbehavior)(nil)
+
+// Validate ensures the misbehavior names a validator and
+// was reported after it happened
+func (m *Misbehavior) Validate() error {
+	if len(m.PubKey) == 0 {
+		return ErrInvalidEvidence("missing pub key")
+	}
+	if m.Height <= 0 {
+		return ErrInvalidEvidence("height")
+	}
+	if m.Reported < m.Height {
+		return ErrInvalidEvidence("reported before height")
+	}
+	return nil
+}
+
+// Copy makes a new misbehavior with the same values
+func (m *Misbehavior) Copy() orm.CloneableData {
+	return &Misbehavior{
+		PubKey:   m.PubKey,
+		Height:   m.Height,
+		Reported: m.Reported,
+	}
+}
+
+// AsMisbehavior safely extracts a Misbehavior value from the object
+func AsMisbehavior(obj orm.Object) *Misbehavior {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Misbehavior)
+}
+
+// Key returns the key of the misbehavior: the pub key followed
+// by the height big endian, so the ones of a validator are
+// sorted by height
+func Key(pubKey []byte, height int64) []byte {
+	bz := make([]byte, len(pubKey)+8)
+	copy(bz, pubKey)
+	binary.BigEndian.PutUint64(bz[len(pubKey):], uint64(height))
+	return bz
+}
+
+//--- Bucket
+
+// Bucket is a type-safe wrapper around orm.Bucket
+type Bucket struct {
+	orm.Bucket
+}
+
+// NewBucket initializes a Bucket with default name
+func NewBucket() Bucket {
+	return Bucket{
+		Bucket: orm.NewBucket(BucketName,
+			orm.NewSimpleObj(nil, new(Misbehavior))).
+			WithIndex(IndexValidator, idxValidator, false),
+	}
+}
+
+func idxValidator(obj orm.Object) ([]byte, error) {
+	if obj == nil {
+		return nil, errors.New("Cannot take index of nil")
+	}
+	m, ok := obj.Value().(*Misbehavior)
+	if !ok {
+		return nil, errors.New("Can only take index of Misbehavior")
+	}
+	return m.PubKey, nil
+}
+
+// Add stores the misbehavior, unless it was already reported.
+// It returns whether it is new.
+func (b Bucket) Add(db weave.KVStore, m *Misbehavior) (bool, error) {
+	key := Key(m.PubKey, m.Height)
+	obj, err := b.Get(db, key)
+	if err != nil || obj != nil {
+		return false, err
+	}
+	err = b.Save(db, orm.NewSimpleObj(key, m))
+	return err == nil, err
+}
+
+// ByValidator returns all misbehavior of the pub key
+func (b Bucket) ByValidator(db weave.ReadOnlyKVStore, pubKey []byte) ([]*Misbehavior, error) {
+	objs, err := b.GetIndexed(db, IndexValidator, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*Misbehavior, len(objs))
+	for i, obj := range objs {
+		res[i] = AsMisbehavior(obj)
+	}
+	return res, nil
+}
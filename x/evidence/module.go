@@ -0,0 +1,30 @@
+package evidence
+
+import (
+	"github.com/confio/weave"
+
+	"github.com/iov-one/bcp-demo/module"
+)
+
+// Module serves the misbehavior of validators the app records
+type Module struct{}
+
+var (
+	_ module.Module  = Module{}
+	_ module.Querier = Module{}
+)
+
+// Name fulfils module.Module
+func (Module) Name() string {
+	return "evidence"
+}
+
+// Version fulfils module.Module
+func (Module) Version() uint32 {
+	return 1
+}
+
+// RegisterQuery fulfils module.Querier
+func (Module) RegisterQuery(qr weave.QueryRouter) {
+	RegisterQuery(qr)
+}
@@ -0,0 +1,71 @@
+package evidence
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave"
+)
+
+// Penalizer punishes a validator for new misbehavior,
+// eg. by slashing its bond
+type Penalizer interface {
+	Penalize(ctx weave.Context, db weave.KVStore, m *Misbehavior) error
+}
+
+// Recorder stores the evidence tendermint sends with a block
+type Recorder struct {
+	bucket  Bucket
+	penalty Penalizer
+}
+
+// NewRecorder creates a Recorder passing new misbehavior
+// to penalty, which may be nil to only record it
+func NewRecorder(penalty Penalizer) Recorder {
+	return Recorder{bucket: NewBucket(), penalty: penalty}
+}
+
+// Record stores the evidence of the block at the height of ctx.
+// Misbehavior that was reported before is ignored, so it is
+// never penalized twice, and invalid evidence is only logged.
+func (r Recorder) Record(ctx weave.Context, db weave.KVStore,
+	evidence []abci.Evidence) error {
+
+	height, _ := weave.GetHeight(ctx)
+	for _, ev := range evidence {
+		m := &Misbehavior{
+			PubKey:   ev.PubKey,
+			Height:   ev.Height,
+			Reported: height,
+		}
+		// a bad report must not halt the chain
+		err := m.Validate()
+		if err != nil {
+			weave.GetLogger(ctx).Error("Invalid evidence", "err", err)
+			continue
+		}
+		added, err := r.bucket.Add(db, m)
+		if err != nil {
+			return err
+		}
+		if !added {
+			continue
+		}
+		weave.GetLogger(ctx).Info("Validator misbehavior",
+			"pub_key", fmt.Sprintf("%X", m.PubKey), "height", m.Height)
+		if r.penalty != nil {
+			err = r.penalty.Penalize(ctx, db, m)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RegisterQuery will register the evidence as "/evidence",
+// along with "/evidence/validator" for the pub key as data
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("evidence", qr)
+}
@@ -0,0 +1,64 @@
+package evidence
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+)
+
+// countPenalty records what it was asked to penalize
+type countPenalty struct {
+	penalized []*Misbehavior
+}
+
+func (c *countPenalty) Penalize(ctx weave.Context, db weave.KVStore, m *Misbehavior) error {
+	c.penalized = append(c.penalized, m)
+	return nil
+}
+
+func TestRecorder(t *testing.T) {
+	alice, bob := []byte("alice"), []byte("bob")
+	penalty := new(countPenalty)
+	r := NewRecorder(penalty)
+	db := store.MemStore()
+	ctx := weave.WithHeight(context.Background(), 10)
+
+	err := r.Record(ctx, db, []abci.Evidence{
+		{PubKey: alice, Height: 8},
+		{PubKey: bob, Height: 9},
+		// invalid evidence is skipped
+		{PubKey: bob, Height: 11},
+		{Height: 9},
+	})
+	require.NoError(t, err)
+	// the same misbehavior again, and a new one
+	ctx = weave.WithHeight(context.Background(), 12)
+	err = r.Record(ctx, db, []abci.Evidence{
+		{PubKey: alice, Height: 8},
+		{PubKey: alice, Height: 3},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []*Misbehavior{
+		{PubKey: alice, Height: 8, Reported: 10},
+		{PubKey: bob, Height: 9, Reported: 10},
+		{PubKey: alice, Height: 3, Reported: 12},
+	}, penalty.penalized)
+
+	bucket := NewBucket()
+	found, err := bucket.ByValidator(db, alice)
+	require.NoError(t, err)
+	assert.Equal(t, []*Misbehavior{
+		{PubKey: alice, Height: 3, Reported: 12},
+		{PubKey: alice, Height: 8, Reported: 10},
+	}, found)
+	found, err = bucket.ByValidator(db, []byte("carl"))
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
@@ -0,0 +1,61 @@
+package faucet
+
+import (
+	"encoding/hex"
+	"net/http"
+)
+
+// Captcha is the hook of Endpoint to check a request comes from
+// a person, eg. by verifying its token with a captcha service
+type Captcha interface {
+	Verify(r *http.Request) error
+}
+
+// Sender submits the msg in a tx, eg. by broadcasting it to
+// a node of the gateway
+type Sender func(msg *TapMsg) error
+
+// Endpoint is the http.Handler of a gateway for the faucet. It
+// takes a POST with the hex address of the recipient as form
+// value "address", and sends a TapMsg for it once the captcha
+// passes. The chain still limits the taps of every address.
+type Endpoint struct {
+	captcha Captcha
+	send    Sender
+}
+
+var _ http.Handler = Endpoint{}
+
+// NewEndpoint returns an Endpoint that checks every request
+// with captcha and submits the msg with send
+func NewEndpoint(captcha Captcha, send Sender) Endpoint {
+	return Endpoint{captcha: captcha, send: send}
+}
+
+// ServeHTTP answers 202 once the msg is sent. The tx may still
+// fail in a block, eg. if the address tapped too recently.
+func (e Endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST only", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := e.captcha.Verify(r); err != nil {
+		http.Error(w, "captcha: "+err.Error(), http.StatusForbidden)
+		return
+	}
+	addr, err := hex.DecodeString(r.FormValue("address"))
+	if err != nil {
+		http.Error(w, "address: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	msg := &TapMsg{Recipient: addr}
+	if err := msg.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := e.send(msg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,44 @@
+package faucet
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// faucet takes 1210-1220
+const (
+	CodeInvalidConfig = 1210
+	CodeRateLimited   = 1211
+	CodeClosed        = 1212
+)
+
+var (
+	errInvalidConfig = fmt.Errorf("Invalid faucet config")
+	errRateLimited   = fmt.Errorf("Tapped the faucet too recently")
+	errClosed        = fmt.Errorf("No faucet on this chain")
+)
+
+func ErrInvalidConfig(reason string) error {
+	return errors.WithLog(reason, errInvalidConfig, CodeInvalidConfig)
+}
+func IsInvalidConfigErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidConfig)
+}
+
+func ErrRateLimited(next int64) error {
+	msg := fmt.Sprintf("wait until height %d", next)
+	return errors.WithLog(msg, errRateLimited, CodeRateLimited)
+}
+func IsRateLimitedErr(err error) bool {
+	return errors.HasErrorCode(err, CodeRateLimited)
+}
+
+func ErrClosed() error {
+	return errors.WithCode(errClosed, CodeClosed)
+}
+func IsClosedErr(err error) bool {
+	return errors.HasErrorCode(err, CodeClosed)
+}
@@ -0,0 +1,98 @@
+package faucet
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+const tapCost int64 = 10
+
+// RegisterRoutes will instantiate and register
+// all handlers in this package
+func RegisterRoutes(r weave.Registry, control namecoin.Controller) {
+	r.Handle(pathTapMsg, TapHandler{NewConfigBucket(), NewTapBucket(), control})
+}
+
+// RegisterQuery will register the config as "/faucet",
+// queried by "config", and the last taps as "/faucet/taps"
+func RegisterQuery(qr weave.QueryRouter) {
+	NewConfigBucket().Register("faucet", qr)
+	NewTapBucket().Register("faucet/taps", qr)
+}
+
+// TapHandler pays the recipient from the faucet
+type TapHandler struct {
+	config ConfigBucket
+	taps   TapBucket
+	cash   namecoin.Controller
+}
+
+var _ weave.Handler = TapHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h TapHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += tapCost
+	return res, nil
+}
+
+// Deliver pays the amount and records the tap
+func (h TapHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, config, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	err = h.cash.WithContext(ctx).MoveCoins(db, Account, msg.Recipient, *config.Amount)
+	if err != nil {
+		return res, err
+	}
+	height, _ := weave.GetHeight(ctx)
+	return res, h.taps.Record(db, msg.Recipient, height)
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h TapHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*TapMsg, *Config, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*TapMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+	config, err := h.config.Load(db)
+	if err != nil {
+		return nil, nil, err
+	}
+	if config == nil {
+		return nil, nil, ErrClosed()
+	}
+	next, err := h.taps.Next(db, msg.Recipient, config.Window)
+	if err != nil {
+		return nil, nil, err
+	}
+	height, _ := weave.GetHeight(ctx)
+	if height < next {
+		return nil, nil, ErrRateLimited(next)
+	}
+	return msg, config, nil
+}
@@ -0,0 +1,130 @@
+package faucet
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestTap(t *testing.T) {
+	var helpers x.TestHelpers
+	_, alice := helpers.MakeKey()
+	_, bob := helpers.MakeKey()
+
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, control)
+	db := store.MemStore()
+
+	deliver := func(height int64, recipient weave.Address) error {
+		ctx := weave.WithHeight(context.Background(), height)
+		tx := helpers.MockTx(&TapMsg{Recipient: recipient})
+		_, err := r.Check(ctx, db, tx)
+		if err != nil {
+			return err
+		}
+		_, err = r.Deliver(ctx, db, tx)
+		return err
+	}
+	balance := func(addr weave.Address) x.Coins {
+		coins, err := control.Balance(db, addr)
+		require.NoError(t, err)
+		return coins
+	}
+
+	// closed without a config
+	err := deliver(1, alice.Address())
+	assert.True(t, IsClosedErr(err), "%+v", err)
+
+	amount := x.NewCoin(5, 0, "FOO")
+	opts, err := BuildGenesis(Genesis{Amount: &amount, Window: 10})
+	require.NoError(t, err)
+	require.NoError(t, Initializer{}.FromGenesis(opts, db))
+	wallet, err := cash.WalletWith(Account, &x.Coin{Whole: 17, Ticker: "FOO"})
+	require.NoError(t, err)
+	require.NoError(t, bank.Save(db, wallet))
+
+	require.NoError(t, deliver(1, alice.Address()))
+	assert.Equal(t, x.Coins{&amount}, balance(alice.Address()))
+	// once per window and address
+	err = deliver(10, alice.Address())
+	assert.True(t, IsRateLimitedErr(err), "%+v", err)
+	require.NoError(t, deliver(10, bob.Address()))
+	require.NoError(t, deliver(11, alice.Address()))
+	assert.Equal(t, x.Coins{&x.Coin{Whole: 10, Ticker: "FOO"}}, balance(alice.Address()))
+
+	// until the faucet runs dry
+	err = deliver(20, bob.Address())
+	assert.Error(t, err)
+	assert.Equal(t, x.Coins{&x.Coin{Whole: 2, Ticker: "FOO"}}, balance(Account))
+
+	err = deliver(30, []byte("short"))
+	assert.Error(t, err)
+}
+
+// captchaFunc is a Captcha from a function
+type captchaFunc func(r *http.Request) error
+
+func (f captchaFunc) Verify(r *http.Request) error {
+	return f(r)
+}
+
+func TestEndpoint(t *testing.T) {
+	var helpers x.TestHelpers
+	_, alice := helpers.MakeKey()
+
+	captcha := captchaFunc(func(r *http.Request) error {
+		if r.FormValue("captcha") != "solved" {
+			return fmt.Errorf("not solved")
+		}
+		return nil
+	})
+	var sent []*TapMsg
+	send := func(msg *TapMsg) error {
+		sent = append(sent, msg)
+		return nil
+	}
+	e := NewEndpoint(captcha, send)
+
+	cases := map[string]struct {
+		method  string
+		address string
+		captcha string
+		status  int
+	}{
+		"ok":          {"POST", hex.EncodeToString(alice.Address()), "solved", http.StatusAccepted},
+		"get":         {"GET", hex.EncodeToString(alice.Address()), "solved", http.StatusMethodNotAllowed},
+		"no captcha":  {"POST", hex.EncodeToString(alice.Address()), "", http.StatusForbidden},
+		"not hex":     {"POST", "alice", "solved", http.StatusBadRequest},
+		"not address": {"POST", "abcd", "solved", http.StatusBadRequest},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			form := url.Values{"address": {tc.address}, "captcha": {tc.captcha}}
+			req := httptest.NewRequest(tc.method, "/faucet", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, tc.status, rec.Code, rec.Body.String())
+		})
+	}
+	require.Len(t, sent, 1)
+	assert.Equal(t, []byte(alice.Address()), sent[0].Recipient)
+}
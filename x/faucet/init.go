@@ -0,0 +1,43 @@
+package faucet
+
+import (
+	"encoding/json"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+)
+
+const optFaucet = "faucet"
+
+// Genesis is the format of the "faucet" genesis option. Fund
+// the faucet with a wallet for Account.
+type Genesis struct {
+	Amount *x.Coin `json:"amount"`
+	Window int64   `json:"window"`
+}
+
+// Initializer fulfils the InitStater interface to load data from
+// the genesis file
+type Initializer struct{}
+
+var _ weave.Initializer = Initializer{}
+
+// FromGenesis will store the config, if any
+func (Initializer) FromGenesis(opts weave.Options, db weave.KVStore) error {
+	var gen Genesis
+	err := opts.ReadOptions(optFaucet, &gen)
+	if err != nil || gen.Amount == nil {
+		return err
+	}
+	config := &Config{Amount: gen.Amount, Window: gen.Window}
+	return NewConfigBucket().Store(db, config)
+}
+
+// BuildGenesis will create Options with the given config
+func BuildGenesis(gen Genesis) (weave.Options, error) {
+	bz, err := json.MarshalIndent(gen, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return weave.Options{optFaucet: bz}, nil
+}
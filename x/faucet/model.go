@@ -0,0 +1,125 @@
+/*
+Package faucet hands out small amounts of a token on testnets, so
+demo users can get coins to create escrows with.
+
+The faucet is a module account funded in genesis. Anyone can send
+a TapMsg for a recipient, who need not sign it, and the recipient
+gets the configured amount unless it tapped less than the window
+ago, counted in blocks. The chain only limits taps per address:
+a gateway that sends TapMsgs for its users should put a captcha in
+front of it, see Endpoint.
+*/
+package faucet
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+	"github.com/iov-one/bcp-demo/x/modaccount"
+)
+
+const (
+	// BucketName is where we store the config
+	BucketName = "faucet"
+	// TapBucketName is where we store the last tap of every address
+	TapBucketName = "ftap"
+
+	configKey = "config"
+)
+
+func init() {
+	keyspace.Buckets("faucet", BucketName, TapBucketName)
+}
+
+// Account is the address the faucet pays from
+var Account = modaccount.Address(modaccount.Faucet)
+
+var _ orm.CloneableData = (*Config)(nil)
+
+// Validate ensures the amount is positive and there is a window
+func (c *Config) Validate() error {
+	if c.Amount == nil || !c.Amount.IsPositive() {
+		return ErrInvalidConfig("amount must be positive")
+	}
+	if c.Window <= 0 {
+		return ErrInvalidConfig("window must be positive")
+	}
+	return c.Amount.Validate()
+}
+
+// Copy makes a new config with the same values
+func (c *Config) Copy() orm.CloneableData {
+	return &Config{Amount: c.Amount, Window: c.Window}
+}
+
+// ConfigBucket stores the single Config of the module
+type ConfigBucket struct {
+	orm.Bucket
+}
+
+// NewConfigBucket initializes a ConfigBucket with default name
+func NewConfigBucket() ConfigBucket {
+	return ConfigBucket{
+		Bucket: orm.NewBucket(BucketName,
+			orm.NewSimpleObj(nil, new(Config))),
+	}
+}
+
+// Load returns the stored config, nil if none is set
+func (b ConfigBucket) Load(db weave.ReadOnlyKVStore) (*Config, error) {
+	obj, err := b.Get(db, []byte(configKey))
+	if err != nil || obj == nil || obj.Value() == nil {
+		return nil, err
+	}
+	return obj.Value().(*Config), nil
+}
+
+// Store saves the config, replacing the old one
+func (b ConfigBucket) Store(db weave.KVStore, config *Config) error {
+	return b.Save(db, orm.NewSimpleObj([]byte(configKey), config))
+}
+
+var _ orm.CloneableData = (*Tap)(nil)
+
+// Validate ensures the height is set
+func (t *Tap) Validate() error {
+	if t.Height <= 0 {
+		return ErrInvalidConfig("tap height")
+	}
+	return nil
+}
+
+// Copy makes a new tap with the same height
+func (t *Tap) Copy() orm.CloneableData {
+	return &Tap{Height: t.Height}
+}
+
+// TapBucket stores the last tap by recipient address
+type TapBucket struct {
+	orm.Bucket
+}
+
+// NewTapBucket initializes a TapBucket with default name
+func NewTapBucket() TapBucket {
+	return TapBucket{
+		Bucket: orm.NewBucket(TapBucketName,
+			orm.NewSimpleObj(nil, new(Tap))),
+	}
+}
+
+// Next returns the first height the address may tap at
+func (b TapBucket) Next(db weave.ReadOnlyKVStore, addr weave.Address,
+	window int64) (int64, error) {
+
+	obj, err := b.Get(db, addr)
+	if err != nil || obj == nil || obj.Value() == nil {
+		return 0, err
+	}
+	return obj.Value().(*Tap).Height + window, nil
+}
+
+// Record stores a tap of the address at the height
+func (b TapBucket) Record(db weave.KVStore, addr weave.Address, height int64) error {
+	return b.Save(db, orm.NewSimpleObj(addr, &Tap{Height: height}))
+}
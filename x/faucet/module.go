@@ -0,0 +1,45 @@
+package faucet
+
+import (
+	"github.com/confio/weave"
+
+	"github.com/iov-one/bcp-demo/module"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// Module adds the faucet, paying out with Control
+type Module struct {
+	Control namecoin.Controller
+}
+
+var (
+	_ module.Module  = Module{}
+	_ module.Router  = Module{}
+	_ module.Querier = Module{}
+	_ module.Genesis = Module{}
+)
+
+// Name fulfils module.Module
+func (Module) Name() string {
+	return "faucet"
+}
+
+// Version fulfils module.Module
+func (Module) Version() uint32 {
+	return 1
+}
+
+// RegisterRoutes fulfils module.Router
+func (m Module) RegisterRoutes(r weave.Registry) {
+	RegisterRoutes(r, m.Control)
+}
+
+// RegisterQuery fulfils module.Querier
+func (Module) RegisterQuery(qr weave.QueryRouter) {
+	RegisterQuery(qr)
+}
+
+// Initializer fulfils module.Genesis
+func (Module) Initializer() weave.Initializer {
+	return Initializer{}
+}
@@ -0,0 +1,19 @@
+package faucet
+
+import (
+	"github.com/confio/weave"
+)
+
+const pathTapMsg = "faucet/tap"
+
+var _ weave.Msg = (*TapMsg)(nil)
+
+// Path fulfills weave.Msg interface to allow routing
+func (TapMsg) Path() string {
+	return pathTapMsg
+}
+
+// Validate makes sure there is a recipient
+func (m *TapMsg) Validate() error {
+	return weave.Address(m.Recipient).Validate()
+}
@@ -0,0 +1,34 @@
+package features
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// features takes 1150-1160
+const (
+	CodeFeatureDisabled = 1150
+	CodeInvalidFeature  = 1151
+)
+
+var (
+	errFeatureDisabled = fmt.Errorf("Feature disabled")
+	errInvalidFeature  = fmt.Errorf("Invalid feature")
+)
+
+func ErrFeatureDisabled(path string) error {
+	return errors.WithLog(path, errFeatureDisabled, CodeFeatureDisabled)
+}
+func IsFeatureDisabledErr(err error) bool {
+	return errors.HasErrorCode(err, CodeFeatureDisabled)
+}
+
+func ErrInvalidFeature(reason string) error {
+	return errors.WithLog(reason, errInvalidFeature, CodeInvalidFeature)
+}
+func IsInvalidFeatureErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidFeature)
+}
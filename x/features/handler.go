@@ -0,0 +1,138 @@
+package features
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+
+	"github.com/iov-one/bcp-demo/x/rbac"
+)
+
+const setFeatureCost int64 = 10
+
+// RegisterRoutes will instantiate and register
+// all handlers in this package
+func RegisterRoutes(r weave.Registry, auth rbac.Authenticator) {
+	r.Handle(pathSetFeatureMsg, SetFeatureHandler{auth, NewBucket()})
+}
+
+// RegisterQuery will register the features as "/features",
+// queried by "features"
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("features", qr)
+}
+
+// Router rejects messages of disabled paths, and passes
+// all others on to the wrapped handler, usually the router
+// of the app
+type Router struct {
+	next   weave.Handler
+	bucket Bucket
+}
+
+var _ weave.Handler = Router{}
+
+// NewRouter wraps the handler
+func NewRouter(next weave.Handler) Router {
+	return Router{next: next, bucket: NewBucket()}
+}
+
+// Check rejects disabled messages, or passes them on
+func (r Router) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+
+	if err := r.enabled(db, tx); err != nil {
+		return weave.CheckResult{}, err
+	}
+	return r.next.Check(ctx, db, tx)
+}
+
+// Deliver rejects disabled messages, or passes them on
+func (r Router) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+
+	if err := r.enabled(db, tx); err != nil {
+		return weave.DeliverResult{}, err
+	}
+	return r.next.Deliver(ctx, db, tx)
+}
+
+func (r Router) enabled(db weave.ReadOnlyKVStore, tx weave.Tx) error {
+	msg, err := tx.GetMsg()
+	if err != nil {
+		return err
+	}
+	if msg == nil {
+		return errors.ErrDecoding()
+	}
+	features, err := r.bucket.Load(db)
+	if err != nil {
+		return err
+	}
+	if features.IsDisabled(msg.Path()) {
+		return ErrFeatureDisabled(msg.Path())
+	}
+	return nil
+}
+
+// SetFeatureHandler lets admins turn paths on and off
+type SetFeatureHandler struct {
+	auth   rbac.Authenticator
+	bucket Bucket
+}
+
+var _ weave.Handler = SetFeatureHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h SetFeatureHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += setFeatureCost
+	return res, nil
+}
+
+// Deliver turns the path on or off
+func (h SetFeatureHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	features, err := h.bucket.Load(db)
+	if err != nil {
+		return res, err
+	}
+	features.Set(msg.MsgPath, msg.Disabled)
+	return res, h.bucket.Store(db, features)
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h SetFeatureHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*SetFeatureMsg, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*SetFeatureMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	err = h.auth.RequireRole(ctx, db, rbac.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
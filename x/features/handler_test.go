@@ -0,0 +1,174 @@
+package features
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/rbac"
+)
+
+// okHandler accepts every tx
+type okHandler struct{}
+
+func (okHandler) Check(ctx weave.Context, db weave.KVStore, tx weave.Tx) (weave.CheckResult, error) {
+	return weave.CheckResult{}, nil
+}
+
+func (okHandler) Deliver(ctx weave.Context, db weave.KVStore, tx weave.Tx) (weave.DeliverResult, error) {
+	return weave.DeliverResult{Data: []byte("ok")}, nil
+}
+
+func TestFeatures(t *testing.T) {
+	f := new(Features)
+	f.Set("escrow/update", true)
+	f.Set("cash/send", true)
+	f.Set("oracle/set_price", true)
+	f.Set("cash/send", true)
+	assert.Equal(t, []string{"cash/send", "escrow/update", "oracle/set_price"}, f.Disabled)
+	assert.NoError(t, f.Validate())
+	assert.True(t, f.IsDisabled("escrow/update"))
+	assert.False(t, f.IsDisabled("escrow/create"))
+
+	f.Set("escrow/update", false)
+	f.Set("escrow/create", false)
+	assert.Equal(t, []string{"cash/send", "oracle/set_price"}, f.Disabled)
+	assert.False(t, f.IsDisabled("escrow/update"))
+
+	cases := []*Features{
+		{Disabled: []string{"b", "a"}},
+		{Disabled: []string{"a", "a"}},
+		{Disabled: []string{"no spaces"}},
+		{Disabled: []string{""}},
+		{Disabled: []string{pathSetFeatureMsg}},
+	}
+	for i, tc := range cases {
+		err := tc.Validate()
+		assert.True(t, IsInvalidFeatureErr(err), "%d: %+v", i, err)
+	}
+}
+
+func TestExperimental(t *testing.T) {
+	RegisterExperimental("test/experiment")
+	assert.True(t, IsExperimental("test/experiment"))
+	assert.False(t, IsExperimental("escrow/create"))
+
+	// off until enabled
+	f := new(Features)
+	assert.True(t, f.IsDisabled("test/experiment"))
+	f.Set("test/experiment", false)
+	assert.Equal(t, []string{"test/experiment"}, f.Enabled)
+	assert.Empty(t, f.Disabled)
+	assert.False(t, f.IsDisabled("test/experiment"))
+	f.Set("test/experiment", true)
+	assert.Empty(t, f.Enabled)
+	assert.True(t, f.IsDisabled("test/experiment"))
+
+	assert.True(t, IsInvalidFeatureErr((&Features{Enabled: []string{"b", "a"}}).Validate()))
+
+	// genesis only enables experimental paths
+	db := store.MemStore()
+	gen, err := BuildGenesis(Genesis{Enabled: []string{"test/experiment"}})
+	require.NoError(t, err)
+	require.NoError(t, Initializer{}.FromGenesis(gen, db))
+	f, err = NewBucket().Load(db)
+	require.NoError(t, err)
+	assert.False(t, f.IsDisabled("test/experiment"))
+	gen, err = BuildGenesis(Genesis{Enabled: []string{"escrow/create"}})
+	require.NoError(t, err)
+	assert.Error(t, Initializer{}.FromGenesis(gen, store.MemStore()))
+}
+
+func TestSetFeature(t *testing.T) {
+	var helpers x.TestHelpers
+	_, admin := helpers.MakeKey()
+	_, other := helpers.MakeKey()
+
+	auth := helpers.CtxAuth("auth")
+	r := app.NewRouter()
+	RegisterRoutes(r, rbac.NewAuthenticator(auth))
+
+	cases := []struct {
+		perm  weave.Permission
+		msg   *SetFeatureMsg
+		check func(error) bool
+	}{
+		0: {admin, &SetFeatureMsg{MsgPath: "escrow/update", Disabled: true}, nil},
+		1: {other, &SetFeatureMsg{MsgPath: "escrow/update", Disabled: true}, errors.IsUnauthorizedErr},
+		2: {admin, &SetFeatureMsg{MsgPath: "escrow update", Disabled: true}, IsInvalidFeatureErr},
+		3: {admin, &SetFeatureMsg{MsgPath: pathSetFeatureMsg, Disabled: true}, IsInvalidFeatureErr},
+		// turning on what is on is fine
+		4: {admin, &SetFeatureMsg{MsgPath: "cash/send"}, nil},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			require.NoError(t, rbac.NewBucket().Assign(db, admin.Address(), rbac.RoleAdmin))
+
+			ctx := auth.SetPermissions(context.Background(), tc.perm)
+			tx := helpers.MockTx(tc.msg)
+			_, err := r.Check(ctx, db, tx)
+			if tc.check != nil {
+				assert.True(t, tc.check(err), "%+v", err)
+				_, err = r.Deliver(ctx, db, tx)
+				assert.True(t, tc.check(err), "%+v", err)
+				return
+			}
+			require.NoError(t, err)
+			_, err = r.Deliver(ctx, db, tx)
+			require.NoError(t, err)
+
+			features, err := NewBucket().Load(db)
+			require.NoError(t, err)
+			assert.Equal(t, tc.msg.Disabled, features.IsDisabled(tc.msg.MsgPath))
+		})
+	}
+}
+
+func TestRouter(t *testing.T) {
+	var helpers x.TestHelpers
+	_, admin := helpers.MakeKey()
+	auth := helpers.CtxAuth("auth")
+	ctx := auth.SetPermissions(context.Background(), admin)
+
+	inner := app.NewRouter()
+	inner.Handle("cash/send", okHandler{})
+	RegisterRoutes(inner, rbac.NewAuthenticator(auth))
+	r := NewRouter(inner)
+
+	db := store.MemStore()
+	require.NoError(t, rbac.NewBucket().Assign(db, admin.Address(), rbac.RoleAdmin))
+	opts, err := BuildGenesis(Genesis{Disabled: []string{"cash/send"}})
+	require.NoError(t, err)
+	require.NoError(t, Initializer{}.FromGenesis(opts, db))
+
+	send := helpers.MockTx(&cash.SendMsg{})
+	_, err = r.Check(ctx, db, send)
+	assert.True(t, IsFeatureDisabledErr(err), "%+v", err)
+	_, err = r.Deliver(ctx, db, send)
+	assert.True(t, IsFeatureDisabledErr(err), "%+v", err)
+
+	// the admin turns it on
+	_, err = r.Deliver(ctx, db, helpers.MockTx(&SetFeatureMsg{MsgPath: "cash/send"}))
+	require.NoError(t, err)
+	_, err = r.Check(ctx, db, send)
+	assert.NoError(t, err)
+	res, err := r.Deliver(ctx, db, send)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ok"), []byte(res.Data))
+
+	// unknown paths are still unknown
+	_, err = r.Deliver(ctx, db, helpers.MockTx(&rbac.AssignRoleMsg{}))
+	assert.True(t, app.IsNoSuchPathErr(err), "%+v", err)
+}
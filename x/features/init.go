@@ -0,0 +1,51 @@
+package features
+
+import (
+	"encoding/json"
+
+	"github.com/confio/weave"
+)
+
+const optFeatures = "features"
+
+// Genesis is the format of the "features" genesis option
+type Genesis struct {
+	Disabled []string `json:"disabled"`
+	// Enabled turns on experimental paths
+	Enabled []string `json:"enabled,omitempty"`
+}
+
+// Initializer fulfils the InitStater interface to load data from
+// the genesis file
+type Initializer struct{}
+
+var _ weave.Initializer = Initializer{}
+
+// FromGenesis will store the disabled and enabled paths, if any
+func (Initializer) FromGenesis(opts weave.Options, db weave.KVStore) error {
+	var gen Genesis
+	err := opts.ReadOptions(optFeatures, &gen)
+	if err != nil || len(gen.Disabled)+len(gen.Enabled) == 0 {
+		return err
+	}
+	features := new(Features)
+	for _, path := range gen.Disabled {
+		features.Set(path, true)
+	}
+	for _, path := range gen.Enabled {
+		if !IsExperimental(path) {
+			return ErrInvalidFeature(path + " is not experimental")
+		}
+		features.Set(path, false)
+	}
+	return NewBucket().Store(db, features)
+}
+
+// BuildGenesis will create Options with the given disabled paths
+func BuildGenesis(gen Genesis) (weave.Options, error) {
+	bz, err := json.MarshalIndent(gen, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return weave.Options{optFeatures: bz}, nil
+}
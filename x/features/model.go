@@ -0,0 +1,162 @@
+/*
+Package features lets genesis and the admins turn whole message
+paths off, eg. to ship a chain with "escrow/update" disabled until
+it is audited.
+
+Router wraps the router of the app and rejects all messages of a
+disabled path with ErrFeatureDisabled, in CheckTx and DeliverTx.
+Admins turn paths on and off with SetFeatureMsg, which itself can
+never be disabled.
+
+Paths a module registers as experimental work the other way round:
+they are off until genesis or an admin turns them on.
+*/
+package features
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+)
+
+const (
+	// BucketName is where we store the features
+	BucketName = "features"
+
+	featuresKey = "features"
+)
+
+func init() {
+	keyspace.Buckets("features", BucketName)
+}
+
+// isPath matches the paths the router accepts
+var isPath = regexp.MustCompile(`^[a-zA-Z0-9_/]+$`).MatchString
+
+// experimental are the paths that are off unless enabled
+var experimental = make(map[string]bool)
+
+// RegisterExperimental marks message paths as experimental, so
+// they are rejected until they are turned on. Modules call it
+// from init.
+func RegisterExperimental(paths ...string) {
+	for _, path := range paths {
+		experimental[path] = true
+	}
+}
+
+// IsExperimental returns true if the path must be turned on
+// before it is used
+func IsExperimental(path string) bool {
+	return experimental[path]
+}
+
+var _ orm.CloneableData = (*Features)(nil)
+
+// Validate ensures all paths are valid, sorted and unique,
+// and the path to turn them on again is not disabled
+func (f *Features) Validate() error {
+	for _, paths := range [][]string{f.Disabled, f.Enabled} {
+		for i, path := range paths {
+			if err := validatePath(path); err != nil {
+				return err
+			}
+			if i > 0 && paths[i-1] >= path {
+				return ErrInvalidFeature("paths must be sorted and unique")
+			}
+		}
+	}
+	return nil
+}
+
+// Copy makes a new list with the same values
+func (f *Features) Copy() orm.CloneableData {
+	return &Features{
+		Disabled: append([]string(nil), f.Disabled...),
+		Enabled:  append([]string(nil), f.Enabled...),
+	}
+}
+
+// IsDisabled returns true if messages of the path are rejected
+func (f *Features) IsDisabled(path string) bool {
+	if IsExperimental(path) {
+		return !contains(f.Enabled, path)
+	}
+	return contains(f.Disabled, path)
+}
+
+// Set turns the path off if disabled, otherwise on,
+// keeping the lists sorted
+func (f *Features) Set(path string, disabled bool) {
+	if IsExperimental(path) {
+		f.Enabled = toggle(f.Enabled, path, !disabled)
+	} else {
+		f.Disabled = toggle(f.Disabled, path, disabled)
+	}
+}
+
+func contains(paths []string, path string) bool {
+	i := sort.SearchStrings(paths, path)
+	return i < len(paths) && paths[i] == path
+}
+
+// toggle adds the path to the sorted list if in, otherwise
+// removes it
+func toggle(paths []string, path string, in bool) []string {
+	i := sort.SearchStrings(paths, path)
+	found := i < len(paths) && paths[i] == path
+	switch {
+	case in && !found:
+		paths = append(paths, "")
+		copy(paths[i+1:], paths[i:])
+		paths[i] = path
+	case !in && found:
+		paths = append(paths[:i], paths[i+1:]...)
+	}
+	return paths
+}
+
+func validatePath(path string) error {
+	if !isPath(path) {
+		return ErrInvalidFeature(path)
+	}
+	if path == pathSetFeatureMsg {
+		return ErrInvalidFeature("cannot disable " + path)
+	}
+	return nil
+}
+
+// Bucket stores the single Features of the chain
+type Bucket struct {
+	orm.Bucket
+}
+
+// NewBucket initializes a Bucket with default name
+func NewBucket() Bucket {
+	return Bucket{
+		Bucket: orm.NewBucket(BucketName,
+			orm.NewSimpleObj(nil, new(Features))),
+	}
+}
+
+// Load returns the stored features, all enabled if
+// none were set
+func (b Bucket) Load(db weave.ReadOnlyKVStore) (*Features, error) {
+	obj, err := b.Get(db, []byte(featuresKey))
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil || obj.Value() == nil {
+		return new(Features), nil
+	}
+	return obj.Value().(*Features), nil
+}
+
+// Store saves the features, replacing the old ones
+func (b Bucket) Store(db weave.KVStore, features *Features) error {
+	return b.Save(db, orm.NewSimpleObj([]byte(featuresKey), features))
+}
@@ -0,0 +1,46 @@
+package features
+
+import (
+	"github.com/confio/weave"
+
+	"github.com/iov-one/bcp-demo/module"
+	"github.com/iov-one/bcp-demo/x/rbac"
+)
+
+// Module adds switching message paths on and off, by the
+// roles of Auth
+type Module struct {
+	Auth rbac.Authenticator
+}
+
+var (
+	_ module.Module  = Module{}
+	_ module.Router  = Module{}
+	_ module.Querier = Module{}
+	_ module.Genesis = Module{}
+)
+
+// Name fulfils module.Module
+func (Module) Name() string {
+	return "features"
+}
+
+// Version fulfils module.Module
+func (Module) Version() uint32 {
+	return 2
+}
+
+// RegisterRoutes fulfils module.Router
+func (m Module) RegisterRoutes(r weave.Registry) {
+	RegisterRoutes(r, m.Auth)
+}
+
+// RegisterQuery fulfils module.Querier
+func (Module) RegisterQuery(qr weave.QueryRouter) {
+	RegisterQuery(qr)
+}
+
+// Initializer fulfils module.Genesis
+func (Module) Initializer() weave.Initializer {
+	return Initializer{}
+}
@@ -0,0 +1,19 @@
+package features
+
+import (
+	"github.com/confio/weave"
+)
+
+const pathSetFeatureMsg = "features/set"
+
+var _ weave.Msg = (*SetFeatureMsg)(nil)
+
+// Path fulfills weave.Msg interface to allow routing
+func (SetFeatureMsg) Path() string {
+	return pathSetFeatureMsg
+}
+
+// Validate makes sure that this is sensible
+func (m *SetFeatureMsg) Validate() error {
+	return validatePath(m.MsgPath)
+}
@@ -0,0 +1,25 @@
+package feepool
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// feepool takes 1180-1190
+const (
+	CodeInvalidConversion = 1180
+)
+
+var (
+	errInvalidConversion = fmt.Errorf("Invalid fee conversion")
+)
+
+func ErrInvalidConversion(reason string) error {
+	return errors.WithLog(reason, errInvalidConversion, CodeInvalidConversion)
+}
+func IsInvalidConversionErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidConversion)
+}
@@ -0,0 +1,258 @@
+package feepool
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/oracle"
+	"github.com/iov-one/bcp-demo/x/rbac"
+)
+
+const setConversionCost int64 = 10
+
+// RegisterRoutes will instantiate and register
+// all handlers in this package
+func RegisterRoutes(r weave.Registry, auth rbac.Authenticator) {
+	r.Handle(pathSetConversionMsg, SetConversionHandler{auth, NewConfigBucket()})
+}
+
+// RegisterQuery will register the config as "/feepool",
+// queried by "config"
+func RegisterQuery(qr weave.QueryRouter) {
+	NewConfigBucket().Register("feepool", qr)
+}
+
+// Decorator takes the fee of a tx. Fees in the fee token, or
+// of any token if there is no config, are passed on to the
+// wrapped cash.FeeDecorator. Fees in an accepted token go to
+// the pool, which pays their value to the collector instead.
+type Decorator struct {
+	fees      namecoin.FeeDecorator
+	auth      x.Authenticator
+	control   namecoin.Controller
+	minFee    x.Coin
+	collector weave.Address
+	config    ConfigBucket
+	prices    oracle.PriceBucket
+}
+
+var _ weave.Decorator = Decorator{}
+
+// NewDecorator returns a Decorator with the given minimum
+// fee, in the fee token, sending all fees to collector
+func NewDecorator(auth x.Authenticator, min x.Coin, collector weave.Address) Decorator {
+	return Decorator{
+		fees:      namecoin.NewFeeDecorator(auth, min).WithCollector(collector),
+		auth:      auth,
+		control:   namecoin.NewController(),
+		minFee:    min,
+		collector: collector,
+		config:    NewConfigBucket(),
+		prices:    oracle.NewPriceBucket(),
+	}
+}
+
+// Check converts the fee before calling down the stack
+func (d Decorator) Check(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	finfo, err := d.foreignFee(ctx, store, tx)
+	if err != nil {
+		return res, err
+	}
+	if finfo == nil {
+		return d.fees.Check(ctx, store, tx, next)
+	}
+	err = d.convert(ctx, store, finfo)
+	if err != nil {
+		return res, err
+	}
+	return next.Check(ctx, store, tx)
+}
+
+// Deliver converts the fee before calling down the stack
+func (d Decorator) Deliver(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	finfo, err := d.foreignFee(ctx, store, tx)
+	if err != nil {
+		return res, err
+	}
+	if finfo == nil {
+		return d.fees.Deliver(ctx, store, tx, next)
+	}
+	err = d.convert(ctx, store, finfo)
+	if err != nil {
+		return res, err
+	}
+	return next.Deliver(ctx, store, tx)
+}
+
+// foreignFee returns the fee info if the fee is not in the fee
+// token, nil if the cash.FeeDecorator takes it. It fails if the
+// token is not accepted.
+func (d Decorator) foreignFee(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (*cash.FeeInfo, error) {
+
+	ftx, ok := tx.(cash.FeeTx)
+	if !ok {
+		return nil, nil
+	}
+	fee := ftx.GetFees().GetFees()
+	if x.IsEmpty(fee) {
+		return nil, nil
+	}
+	config, err := d.config.Load(store)
+	if err != nil || config == nil || fee.Ticker == config.FeeToken {
+		return nil, err
+	}
+	if config.Conversion(fee.Ticker) == nil {
+		return nil, x.ErrInvalidCurrency("fee", fee.Ticker)
+	}
+	payer := x.MainSigner(ctx, d.auth).Address()
+	finfo := ftx.GetFees().DefaultPayer(payer)
+	return finfo, finfo.Validate()
+}
+
+// convert moves the fee to the pool, and its value in the fee
+// token from the pool to the collector
+func (d Decorator) convert(ctx weave.Context, store weave.KVStore,
+	finfo *cash.FeeInfo) error {
+
+	if !d.auth.HasAddress(ctx, finfo.Payer) {
+		return errors.ErrUnauthorized()
+	}
+	config, err := d.config.Load(store)
+	if err != nil {
+		return err
+	}
+	fee := *finfo.Fees
+	value, err := feeValue(store, config, d.prices, fee)
+	if err != nil {
+		return err
+	}
+	if !value.IsPositive() || (d.minFee.Ticker == value.Ticker && !value.IsGTE(d.minFee)) {
+		return cash.ErrInsufficientFees(fee)
+	}
+	return d.control.WithContext(ctx).MoveCoinsBatch(store, []namecoin.Transfer{
+		{Src: finfo.Payer, Dest: Pool, Amount: fee},
+		{Src: Pool, Dest: d.collector, Amount: value},
+	})
+}
+
+// Value returns what fee is worth in the fee token, as the
+// Decorator converts it. Fees in the fee token, or in any token
+// if there is no config, are worth their amount.
+func Value(db weave.ReadOnlyKVStore, fee x.Coin) (x.Coin, error) {
+	config, err := NewConfigBucket().Load(db)
+	if err != nil {
+		return x.Coin{}, err
+	}
+	return feeValue(db, config, oracle.NewPriceBucket(), fee)
+}
+
+// feeValue converts fee at the rate of the config, or the oracle
+// price without one
+func feeValue(db weave.ReadOnlyKVStore, config *Config, prices oracle.PriceBucket,
+	fee x.Coin) (x.Coin, error) {
+
+	if config == nil || fee.Ticker == config.FeeToken {
+		return fee, nil
+	}
+	conv := config.Conversion(fee.Ticker)
+	if conv == nil {
+		return x.Coin{}, x.ErrInvalidCurrency("fee", fee.Ticker)
+	}
+	rate := conv.Rate
+	if rate == nil {
+		price, err := prices.Price(db, fee.Ticker, config.FeeToken)
+		if err != nil {
+			return x.Coin{}, err
+		}
+		rate = price.Rate
+	}
+	return convert(fee, *rate)
+}
+
+// SetConversionHandler lets admins change the accepted tokens
+type SetConversionHandler struct {
+	auth   rbac.Authenticator
+	bucket ConfigBucket
+}
+
+var _ weave.Handler = SetConversionHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h SetConversionHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += setConversionCost
+	return res, nil
+}
+
+// Deliver accepts or removes the token
+func (h SetConversionHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, config, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	if msg.Remove {
+		config.Remove(msg.Ticker)
+	} else {
+		config.Set(msg.Ticker, msg.Rate)
+	}
+	return res, h.bucket.Store(db, config)
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h SetConversionHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*SetConversionMsg, *Config, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*SetConversionMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+	err = h.auth.RequireRole(ctx, db, rbac.RoleAdmin)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// conversions need a fee token, set in genesis
+	config, err := h.bucket.Load(db)
+	if err != nil {
+		return nil, nil, err
+	}
+	if config == nil {
+		return nil, nil, ErrInvalidConversion("no fee token")
+	}
+	if msg.Ticker == config.FeeToken {
+		return nil, nil, ErrInvalidConversion("cannot convert the fee token")
+	}
+	if msg.Rate != nil && msg.Rate.Ticker != config.FeeToken {
+		return nil, nil, ErrInvalidConversion("rate not in fee token")
+	}
+	return msg, config, nil
+}
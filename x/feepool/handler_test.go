@@ -0,0 +1,223 @@
+package feepool
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/oracle"
+	"github.com/iov-one/bcp-demo/x/rbac"
+)
+
+// okHandler accepts every tx
+type okHandler struct{}
+
+func (okHandler) Check(ctx weave.Context, db weave.KVStore, tx weave.Tx) (weave.CheckResult, error) {
+	return weave.CheckResult{}, nil
+}
+
+func (okHandler) Deliver(ctx weave.Context, db weave.KVStore, tx weave.Tx) (weave.DeliverResult, error) {
+	return weave.DeliverResult{Data: []byte("ok")}, nil
+}
+
+// feeTx adds a fee to the tx
+type feeTx struct {
+	weave.Tx
+	fee *x.Coin
+}
+
+var _ cash.FeeTx = feeTx{}
+
+func (f feeTx) GetFees() *cash.FeeInfo {
+	if f.fee == nil {
+		return nil
+	}
+	return &cash.FeeInfo{Fees: f.fee}
+}
+
+func TestConvert(t *testing.T) {
+	cases := []struct {
+		fee, rate, value x.Coin
+	}{
+		0: {x.NewCoin(3, 0, "FOO"), x.NewCoin(2, 0, "IOV"), x.NewCoin(6, 0, "IOV")},
+		1: {x.NewCoin(0, 500000000, "FOO"), x.NewCoin(0, 10, "IOV"), x.NewCoin(0, 5, "IOV")},
+		// rounds down to the smallest unit
+		2: {x.NewCoin(0, 1, "FOO"), x.NewCoin(0, 500000000, "IOV"), x.NewCoin(0, 0, "IOV")},
+		3: {x.NewCoin(0, 3, "FOO"), x.NewCoin(1, 500000000, "IOV"), x.NewCoin(0, 4, "IOV")},
+	}
+	for i, tc := range cases {
+		value, err := convert(tc.fee, tc.rate)
+		require.NoError(t, err, "%d", i)
+		assert.Equal(t, tc.value, value, "%d", i)
+	}
+}
+
+func TestDecorator(t *testing.T) {
+	var helpers x.TestHelpers
+	_, payer := helpers.MakeKey()
+	_, collector := helpers.MakeKey()
+	auth := helpers.CtxAuth("auth")
+	ctx := auth.SetPermissions(context.Background(), payer)
+
+	fee := func(whole int64, ticker string) *x.Coin {
+		c := x.NewCoin(whole, 0, ticker)
+		return &c
+	}
+	rate := x.NewCoin(0, 500000000, "IOV")
+	gen := Genesis{
+		FeeToken: "IOV",
+		Tokens:   []*Conversion{{Ticker: "FOO", Rate: &rate}, {Ticker: "BAR"}},
+	}
+
+	cases := []struct {
+		config  bool
+		pool    int64
+		fee     *x.Coin
+		payer   x.Coins
+		dest    x.Coins
+		poolEnd x.Coins
+		check   func(error) bool
+	}{
+		// the fee token goes straight to the collector
+		0: {true, 10, fee(2, "IOV"), x.Coins{fee(10, "BAR"), fee(10, "BAZ"), fee(10, "FOO"), fee(8, "IOV")},
+			x.Coins{fee(2, "IOV")}, x.Coins{fee(10, "IOV")}, nil},
+		// at the fixed rate
+		1: {true, 10, fee(4, "FOO"), x.Coins{fee(10, "BAR"), fee(10, "BAZ"), fee(6, "FOO"), fee(10, "IOV")},
+			x.Coins{fee(2, "IOV")}, x.Coins{fee(4, "FOO"), fee(8, "IOV")}, nil},
+		// at the oracle price
+		2: {true, 10, fee(2, "BAR"), x.Coins{fee(8, "BAR"), fee(10, "BAZ"), fee(10, "FOO"), fee(10, "IOV")},
+			x.Coins{fee(6, "IOV")}, x.Coins{fee(2, "BAR"), fee(4, "IOV")}, nil},
+		// below the minimum fee
+		3: {true, 10, fee(1, "FOO"), nil, nil, nil, cash.IsInsufficientFeesErr},
+		// not accepted
+		4: {true, 10, fee(1, "BAZ"), nil, nil, nil, x.IsInvalidCurrencyErr},
+		// the pool cannot pay
+		5: {true, 1, fee(4, "FOO"), nil, nil, nil, cash.IsInsufficientFundsErr},
+		// without config, only the inner decorator checks the fee
+		6: {false, 10, fee(2, "FOO"), nil, nil, nil, x.IsInvalidCurrencyErr},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			bank := namecoin.NewWalletBucket()
+			for _, acct := range []struct {
+				addr  weave.Address
+				coins x.Coins
+			}{
+				{payer.Address(), x.Coins{fee(10, "IOV"), fee(10, "FOO"), fee(10, "BAR"), fee(10, "BAZ")}},
+				{Pool, x.Coins{fee(tc.pool, "IOV")}},
+			} {
+				wallet, err := namecoin.WalletWith(acct.addr, "", acct.coins...)
+				require.NoError(t, err)
+				require.NoError(t, bank.Save(db, wallet))
+			}
+			if tc.config {
+				opts, err := BuildGenesis(gen)
+				require.NoError(t, err)
+				require.NoError(t, Initializer{}.FromGenesis(opts, db))
+			}
+			require.NoError(t, oracle.NewPriceBucket().Set(db, "BAR", x.NewCoin(3, 0, "IOV"), 1))
+
+			min := x.NewCoin(1, 0, "IOV")
+			stack := helpers.Wrap(NewDecorator(auth, min, collector.Address()), okHandler{})
+			tx := feeTx{helpers.MockTx(&cash.SendMsg{}), tc.fee}
+
+			// check takes the fee as well, on a copy of the state
+			_, err := stack.Check(ctx, db.CacheWrap(), tx)
+			if tc.check != nil {
+				assert.True(t, tc.check(err), "%+v", err)
+				return
+			}
+			require.NoError(t, err)
+			_, err = stack.Deliver(ctx, db, tx)
+			require.NoError(t, err)
+
+			control := namecoin.NewWalletController(bank)
+			for _, acct := range []struct {
+				addr  weave.Address
+				coins x.Coins
+			}{
+				{payer.Address(), tc.payer},
+				{collector.Address(), tc.dest},
+				{Pool, tc.poolEnd},
+			} {
+				coins, err := control.Balance(db, acct.addr)
+				require.NoError(t, err)
+				assert.True(t, acct.coins.Equals(coins), "%s: %s", acct.addr, coins)
+			}
+		})
+	}
+}
+
+func TestSetConversion(t *testing.T) {
+	var helpers x.TestHelpers
+	_, admin := helpers.MakeKey()
+	_, other := helpers.MakeKey()
+
+	auth := helpers.CtxAuth("auth")
+	r := app.NewRouter()
+	RegisterRoutes(r, rbac.NewAuthenticator(auth))
+
+	rate := x.NewCoin(2, 0, "IOV")
+	wrong := x.NewCoin(2, 0, "BAR")
+	cases := []struct {
+		config bool
+		perm   weave.Permission
+		msg    *SetConversionMsg
+		tokens []*Conversion
+		check  func(error) bool
+	}{
+		0: {true, admin, &SetConversionMsg{Ticker: "FOO", Rate: &rate},
+			[]*Conversion{{Ticker: "BAR"}, {Ticker: "FOO", Rate: &rate}}, nil},
+		// without a rate, the oracle price is used
+		1: {true, admin, &SetConversionMsg{Ticker: "BAR"},
+			[]*Conversion{{Ticker: "BAR"}}, nil},
+		2: {true, admin, &SetConversionMsg{Ticker: "BAR", Remove: true}, nil, nil},
+		3: {true, other, &SetConversionMsg{Ticker: "FOO", Rate: &rate}, nil, errors.IsUnauthorizedErr},
+		4: {true, admin, &SetConversionMsg{Ticker: "IOV", Rate: &rate}, nil, IsInvalidConversionErr},
+		5: {true, admin, &SetConversionMsg{Ticker: "FOO", Rate: &wrong}, nil, IsInvalidConversionErr},
+		6: {true, admin, &SetConversionMsg{Ticker: "FOO", Rate: &rate, Remove: true}, nil, IsInvalidConversionErr},
+		7: {false, admin, &SetConversionMsg{Ticker: "FOO", Rate: &rate}, nil, IsInvalidConversionErr},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			require.NoError(t, rbac.NewBucket().Assign(db, admin.Address(), rbac.RoleAdmin))
+			if tc.config {
+				opts, err := BuildGenesis(Genesis{FeeToken: "IOV", Tokens: []*Conversion{{Ticker: "BAR"}}})
+				require.NoError(t, err)
+				require.NoError(t, Initializer{}.FromGenesis(opts, db))
+			}
+
+			ctx := auth.SetPermissions(context.Background(), tc.perm)
+			tx := helpers.MockTx(tc.msg)
+			_, err := r.Check(ctx, db, tx)
+			if tc.check != nil {
+				assert.True(t, tc.check(err), "%+v", err)
+				_, err = r.Deliver(ctx, db, tx)
+				assert.True(t, tc.check(err), "%+v", err)
+				return
+			}
+			require.NoError(t, err)
+			_, err = r.Deliver(ctx, db, tx)
+			require.NoError(t, err)
+
+			config, err := NewConfigBucket().Load(db)
+			require.NoError(t, err)
+			assert.Equal(t, tc.tokens, config.Tokens)
+		})
+	}
+}
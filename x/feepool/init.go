@@ -0,0 +1,41 @@
+package feepool
+
+import (
+	"encoding/json"
+
+	"github.com/confio/weave"
+)
+
+const optFeePool = "feepool"
+
+// Genesis is the format of the "feepool" genesis option
+type Genesis struct {
+	FeeToken string        `json:"fee_token"`
+	Tokens   []*Conversion `json:"tokens"`
+}
+
+// Initializer fulfils the InitStater interface to load data from
+// the genesis file
+type Initializer struct{}
+
+var _ weave.Initializer = Initializer{}
+
+// FromGenesis will store the config, if any
+func (Initializer) FromGenesis(opts weave.Options, db weave.KVStore) error {
+	var gen Genesis
+	err := opts.ReadOptions(optFeePool, &gen)
+	if err != nil || gen.FeeToken == "" {
+		return err
+	}
+	config := &Config{FeeToken: gen.FeeToken, Tokens: gen.Tokens}
+	return NewConfigBucket().Store(db, config)
+}
+
+// BuildGenesis will create Options with the given config
+func BuildGenesis(gen Genesis) (weave.Options, error) {
+	bz, err := json.MarshalIndent(gen, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return weave.Options{optFeePool: bz}, nil
+}
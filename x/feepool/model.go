@@ -0,0 +1,159 @@
+/*
+Package feepool lets users pay tx fees in other tokens than the
+fee token of the chain, eg. when they only hold escrowed assets.
+
+The config lists the accepted tokens, each with a rate set by the
+admins or, without one, the oracle price in the fee token. The
+Decorator takes such a fee into the conversion pool, a module
+account, and pays the value in the fee token from the pool to
+the collector. The pool must be funded with the fee token in
+genesis, a tx fails if the pool cannot pay its fee.
+*/
+package feepool
+
+import (
+	"math/big"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+	"github.com/iov-one/bcp-demo/x/modaccount"
+)
+
+const (
+	// BucketName is where we store the config
+	BucketName = "feepool"
+
+	configKey = "config"
+
+	// fracUnit is the number of fractional units in one whole coin
+	fracUnit = 1000000000
+)
+
+func init() {
+	keyspace.Buckets("feepool", BucketName)
+}
+
+// Pool is the address of the conversion pool
+var Pool = modaccount.Address(modaccount.ConversionPool)
+
+var _ orm.CloneableData = (*Config)(nil)
+
+// Validate ensures every token is listed once, with a rate
+// in the fee token if any
+func (c *Config) Validate() error {
+	if !x.IsCC(c.FeeToken) {
+		return x.ErrInvalidCurrency(c.FeeToken)
+	}
+	for i, conv := range c.Tokens {
+		if conv == nil || !x.IsCC(conv.Ticker) {
+			return ErrInvalidConversion("ticker")
+		}
+		if conv.Ticker == c.FeeToken {
+			return ErrInvalidConversion("cannot convert the fee token")
+		}
+		if c.indexOf(conv.Ticker) < i {
+			return ErrInvalidConversion("duplicate " + conv.Ticker)
+		}
+		if err := validateRate(conv.Rate); err != nil {
+			return err
+		}
+		if conv.Rate != nil && conv.Rate.Ticker != c.FeeToken {
+			return ErrInvalidConversion("rate not in fee token")
+		}
+	}
+	return nil
+}
+
+// Copy makes a new config with the same values
+func (c *Config) Copy() orm.CloneableData {
+	return &Config{
+		FeeToken: c.FeeToken,
+		Tokens:   append([]*Conversion(nil), c.Tokens...),
+	}
+}
+
+// Conversion returns how fees in the ticker are converted,
+// nil if they are not accepted
+func (c *Config) Conversion(ticker string) *Conversion {
+	i := c.indexOf(ticker)
+	if i < 0 {
+		return nil
+	}
+	return c.Tokens[i]
+}
+
+// Set accepts fees in the ticker at the rate, or the oracle
+// price if rate is nil, replacing any previous conversion
+func (c *Config) Set(ticker string, rate *x.Coin) {
+	conv := &Conversion{Ticker: ticker, Rate: rate}
+	if i := c.indexOf(ticker); i >= 0 {
+		c.Tokens[i] = conv
+		return
+	}
+	c.Tokens = append(c.Tokens, conv)
+}
+
+// Remove no longer accepts fees in the ticker
+func (c *Config) Remove(ticker string) {
+	if i := c.indexOf(ticker); i >= 0 {
+		c.Tokens = append(c.Tokens[:i], c.Tokens[i+1:]...)
+	}
+}
+
+func (c *Config) indexOf(ticker string) int {
+	for i, conv := range c.Tokens {
+		if conv != nil && conv.Ticker == ticker {
+			return i
+		}
+	}
+	return -1
+}
+
+// convert returns the value of fee at the rate of one whole
+// coin, rounded down to the smallest unit
+func convert(fee, rate x.Coin) (x.Coin, error) {
+	val := new(big.Int).Mul(units(fee), units(rate))
+	val.Quo(val, big.NewInt(fracUnit))
+	if !val.IsInt64() {
+		return x.Coin{}, ErrInvalidConversion("fee too large")
+	}
+	n := val.Int64()
+	res := x.NewCoin(n/fracUnit, n%fracUnit, rate.Ticker)
+	return res, res.Validate()
+}
+
+// units returns the value of the coin in fractional units
+func units(c x.Coin) *big.Int {
+	n := new(big.Int).Mul(big.NewInt(c.Whole), big.NewInt(fracUnit))
+	return n.Add(n, big.NewInt(c.Fractional))
+}
+
+// ConfigBucket stores the single Config of the module
+type ConfigBucket struct {
+	orm.Bucket
+}
+
+// NewConfigBucket initializes a ConfigBucket with default name
+func NewConfigBucket() ConfigBucket {
+	return ConfigBucket{
+		Bucket: orm.NewBucket(BucketName,
+			orm.NewSimpleObj(nil, new(Config))),
+	}
+}
+
+// Load returns the stored config, nil if none is set
+func (b ConfigBucket) Load(db weave.ReadOnlyKVStore) (*Config, error) {
+	obj, err := b.Get(db, []byte(configKey))
+	if err != nil || obj == nil || obj.Value() == nil {
+		return nil, err
+	}
+	return obj.Value().(*Config), nil
+}
+
+// Store saves the config, replacing the old one
+func (b ConfigBucket) Store(db weave.KVStore, config *Config) error {
+	return b.Save(db, orm.NewSimpleObj([]byte(configKey), config))
+}
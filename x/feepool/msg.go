@@ -0,0 +1,40 @@
+package feepool
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+)
+
+const pathSetConversionMsg = "feepool/set"
+
+var _ weave.Msg = (*SetConversionMsg)(nil)
+
+// Path fulfills weave.Msg interface to allow routing
+func (SetConversionMsg) Path() string {
+	return pathSetConversionMsg
+}
+
+// Validate makes sure the rate, if any, is positive. The
+// currency of the rate is checked against the config.
+func (m *SetConversionMsg) Validate() error {
+	if !x.IsCC(m.Ticker) {
+		return x.ErrInvalidCurrency(m.Ticker)
+	}
+	if m.Remove {
+		if m.Rate != nil {
+			return ErrInvalidConversion("rate set on remove")
+		}
+		return nil
+	}
+	return validateRate(m.Rate)
+}
+
+func validateRate(rate *x.Coin) error {
+	if rate == nil {
+		return nil
+	}
+	if !rate.IsPositive() {
+		return ErrInvalidConversion("rate must be positive")
+	}
+	return rate.Validate()
+}
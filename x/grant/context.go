@@ -0,0 +1,45 @@
+package grant
+
+import (
+	"context"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+)
+
+type contextKey int // local to the grant module
+
+const (
+	contextKeyGranters contextKey = iota
+)
+
+// withGranters is a private method, as only the Decorator
+// may add granters after checking the grants
+func withGranters(ctx weave.Context, granters []weave.Permission) weave.Context {
+	return context.WithValue(ctx, contextKeyGranters, granters)
+}
+
+// Authenticate implements x.Authenticator and provides
+// the permissions that were granted to the signers of the
+// current message
+type Authenticate struct{}
+
+var _ x.Authenticator = Authenticate{}
+
+// GetPermissions returns the granters the signers may act for.
+// May be nil
+func (a Authenticate) GetPermissions(ctx weave.Context) []weave.Permission {
+	val, _ := ctx.Value(contextKeyGranters).([]weave.Permission)
+	return val
+}
+
+// HasAddress returns true if the signers were granted the
+// right to act as this address in the current Context.
+func (a Authenticate) HasAddress(ctx weave.Context, addr weave.Address) bool {
+	for _, perm := range a.GetPermissions(ctx) {
+		if perm.Address().Equals(addr) {
+			return true
+		}
+	}
+	return false
+}
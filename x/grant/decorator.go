@@ -0,0 +1,75 @@
+package grant
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+)
+
+// Decorator adds the permissions granted to the signers
+// for the current message to the context
+type Decorator struct {
+	auth   x.Authenticator
+	bucket Bucket
+}
+
+var _ weave.Decorator = Decorator{}
+
+// NewDecorator looks up the grants of the signers
+// returned by auth
+func NewDecorator(auth x.Authenticator) Decorator {
+	return Decorator{
+		auth:   auth,
+		bucket: NewBucket(),
+	}
+}
+
+// Check adds the granters before calling down the stack
+func (d Decorator) Check(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	ctx, err := d.withGrants(ctx, store, tx)
+	if err != nil {
+		return res, err
+	}
+	return next.Check(ctx, store, tx)
+}
+
+// Deliver adds the granters before calling down the stack
+func (d Decorator) Deliver(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	ctx, err := d.withGrants(ctx, store, tx)
+	if err != nil {
+		return res, err
+	}
+	return next.Deliver(ctx, store, tx)
+}
+
+// withGrants collects the granters of all active grants the
+// signers hold for the path of the message
+func (d Decorator) withGrants(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.Context, error) {
+
+	msg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	height, _ := weave.GetHeight(ctx)
+
+	var granters []weave.Permission
+	for _, perm := range d.auth.GetPermissions(ctx) {
+		grants, err := d.bucket.Active(store, perm.Address(), msg.Path(), height)
+		if err != nil {
+			return nil, err
+		}
+		for _, grant := range grants {
+			granters = append(granters, grant.Granter)
+		}
+	}
+	if len(granters) == 0 {
+		return ctx, nil
+	}
+	return withGranters(ctx, granters), nil
+}
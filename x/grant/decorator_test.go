@@ -0,0 +1,105 @@
+package grant
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+func TestDecorator(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	_, c := helpers.MakeKey()
+	_, d := helpers.MakeKey()
+
+	auth := helpers.CtxAuth("auth")
+	h := new(GrantCheckHandler)
+	stack := helpers.Wrap(NewDecorator(auth), h)
+
+	db := store.MemStore()
+	bucket := NewBucket()
+	send := new(cash.SendMsg).Path()
+	require.NoError(t, bucket.Save(db, NewGrant(a, c.Address(), send, 100)))
+	require.NoError(t, bucket.Save(db, NewGrant(b, c.Address(), send, 50)))
+	require.NoError(t, bucket.Save(db, NewGrant(b, d.Address(), "escrow/release", 100)))
+
+	cases := []struct {
+		height int64
+		perms  []weave.Permission
+		expect []weave.Permission
+	}{
+		0: {10, []weave.Permission{c}, []weave.Permission{a, b}},
+		// last valid block
+		1: {50, []weave.Permission{c}, []weave.Permission{a, b}},
+		2: {51, []weave.Permission{c}, []weave.Permission{a}},
+		3: {101, []weave.Permission{c}, nil},
+		// only granted for another path
+		4: {10, []weave.Permission{d}, nil},
+		5: {10, nil, nil},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			ctx := weave.WithHeight(context.Background(), tc.height)
+			ctx = auth.SetPermissions(ctx, tc.perms...)
+			tx := helpers.MockTx(new(cash.SendMsg))
+
+			_, err := stack.Check(ctx, db, tx)
+			require.NoError(t, err)
+			assert.Equal(t, len(tc.expect), len(h.Perms))
+			for _, p := range tc.expect {
+				assert.Contains(t, h.Perms, p)
+			}
+
+			_, err = stack.Deliver(ctx, db, tx)
+			require.NoError(t, err)
+			assert.Equal(t, len(tc.expect), len(h.Perms))
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+
+	auth := Authenticate{}
+	ctx := context.Background()
+	assert.Nil(t, auth.GetPermissions(ctx))
+	assert.False(t, auth.HasAddress(ctx, a.Address()))
+
+	ctx = withGranters(ctx, []weave.Permission{a})
+	assert.Equal(t, []weave.Permission{a}, auth.GetPermissions(ctx))
+	assert.True(t, auth.HasAddress(ctx, a.Address()))
+	assert.False(t, auth.HasAddress(ctx, b.Address()))
+}
+
+//---------------- helpers --------
+
+// GrantCheckHandler stores the granted permissions on each call
+type GrantCheckHandler struct {
+	Perms []weave.Permission
+}
+
+var _ weave.Handler = (*GrantCheckHandler)(nil)
+
+func (s *GrantCheckHandler) Check(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (res weave.CheckResult, err error) {
+	s.Perms = Authenticate{}.GetPermissions(ctx)
+	return
+}
+
+func (s *GrantCheckHandler) Deliver(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (res weave.DeliverResult, err error) {
+	s.Perms = Authenticate{}.GetPermissions(ctx)
+	return
+}
@@ -0,0 +1,43 @@
+package grant
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// grant takes 1070-1080
+const (
+	CodeInvalidGrant = 1070
+	CodeNoGrant      = 1071
+)
+
+var (
+	errInvalidPath    = fmt.Errorf("Invalid message path")
+	errInvalidExpires = fmt.Errorf("Invalid expiration height")
+	errSelfGrant      = fmt.Errorf("Cannot grant to yourself")
+	errNoGrant        = fmt.Errorf("No such grant")
+)
+
+func ErrInvalidPath(path string) error {
+	return errors.WithLog(path, errInvalidPath, CodeInvalidGrant)
+}
+func ErrInvalidExpires(height int64) error {
+	msg := fmt.Sprintf("%d", height)
+	return errors.WithLog(msg, errInvalidExpires, CodeInvalidGrant)
+}
+func ErrSelfGrant() error {
+	return errors.WithCode(errSelfGrant, CodeInvalidGrant)
+}
+func IsInvalidGrantErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidGrant)
+}
+
+func ErrNoGrant(path string) error {
+	return errors.WithLog(path, errNoGrant, CodeNoGrant)
+}
+func IsNoGrantErr(err error) bool {
+	return errors.HasErrorCode(err, CodeNoGrant)
+}
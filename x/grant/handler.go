@@ -0,0 +1,167 @@
+package grant
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x"
+)
+
+const (
+	createGrantCost int64 = 50
+	revokeGrantCost int64 = 0
+)
+
+// RegisterRoutes will instantiate and register
+// all handlers in this package
+func RegisterRoutes(r weave.Registry, auth x.Authenticator) {
+	bucket := NewBucket()
+	r.Handle(pathCreateGrantMsg, CreateGrantHandler{auth, bucket})
+	r.Handle(pathRevokeGrantMsg, RevokeGrantHandler{auth, bucket})
+}
+
+// RegisterQuery will register the grants as "/grants",
+// and "/grants/grantee" to query by grantee and path
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("grants", qr)
+}
+
+// CreateGrantHandler stores a grant from the main signer,
+// replacing any previous grant to the grantee for the path
+type CreateGrantHandler struct {
+	auth   x.Authenticator
+	bucket Bucket
+}
+
+var _ weave.Handler = CreateGrantHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h CreateGrantHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += createGrantCost
+	return res, nil
+}
+
+// Deliver saves the grant
+func (h CreateGrantHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, granter, err := h.validate(ctx, tx)
+	if err != nil {
+		return res, err
+	}
+
+	obj := NewGrant(granter, msg.Grantee, msg.MsgPath, msg.Expires)
+	err = h.bucket.Save(db, obj)
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h CreateGrantHandler) validate(ctx weave.Context,
+	tx weave.Tx) (*CreateGrantMsg, weave.Permission, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*CreateGrantMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	height, _ := weave.GetHeight(ctx)
+	if msg.Expires <= height {
+		return nil, nil, ErrInvalidExpires(msg.Expires)
+	}
+
+	// the grant is always given by the main signer
+	granter := x.MainSigner(ctx, h.auth)
+	if granter == nil {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	if granter.Address().Equals(msg.Grantee) {
+		return nil, nil, ErrSelfGrant()
+	}
+	return msg, granter, nil
+}
+
+// RevokeGrantHandler lets the granter remove a grant
+type RevokeGrantHandler struct {
+	auth   x.Authenticator
+	bucket Bucket
+}
+
+var _ weave.Handler = RevokeGrantHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h RevokeGrantHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += revokeGrantCost
+	return res, nil
+}
+
+// Deliver removes the grant
+func (h RevokeGrantHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	key, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	err = h.bucket.Delete(db, key)
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver,
+// returning the key of the grant to remove
+func (h RevokeGrantHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) ([]byte, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*RevokeGrantMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	granter := x.MainSigner(ctx, h.auth)
+	if granter == nil {
+		return nil, errors.ErrUnauthorized()
+	}
+
+	key := GrantKey(granter.Address(), msg.Grantee, msg.MsgPath)
+	obj, err := h.bucket.Get(db, key)
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, ErrNoGrant(msg.MsgPath)
+	}
+	return key, nil
+}
@@ -0,0 +1,93 @@
+package grant
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+)
+
+func TestGrantHandlers(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+	_, c := helpers.MakeKey()
+
+	auth := helpers.CtxAuth("auth")
+	r := app.NewRouter()
+	RegisterRoutes(r, auth)
+
+	release := "escrow/release"
+	cases := []struct {
+		perms []weave.Permission
+		msg   weave.Msg
+		check func(error) bool
+		// key of the grant we expect after the message
+		granter weave.Address
+		grantee weave.Address
+		expires int64
+	}{
+		0: {[]weave.Permission{a}, &CreateGrantMsg{Grantee: c.Address(), MsgPath: release, Expires: 200},
+			nil, a.Address(), c.Address(), 200},
+		// extend the existing grant
+		1: {[]weave.Permission{a}, &CreateGrantMsg{Grantee: b.Address(), MsgPath: release, Expires: 300},
+			nil, a.Address(), b.Address(), 300},
+		2: {[]weave.Permission{a}, &RevokeGrantMsg{Grantee: b.Address(), MsgPath: release},
+			nil, a.Address(), b.Address(), 0},
+		// the main signer is the granter
+		3: {[]weave.Permission{c, a}, &CreateGrantMsg{Grantee: b.Address(), MsgPath: release, Expires: 200},
+			nil, c.Address(), b.Address(), 200},
+		4: {[]weave.Permission{b}, &RevokeGrantMsg{Grantee: b.Address(), MsgPath: release},
+			IsNoGrantErr, a.Address(), b.Address(), 100},
+		5: {[]weave.Permission{a}, &RevokeGrantMsg{Grantee: b.Address(), MsgPath: "escrow/return"},
+			IsNoGrantErr, a.Address(), b.Address(), 100},
+		// invalid grants
+		6: {[]weave.Permission{a}, &CreateGrantMsg{Grantee: a.Address(), MsgPath: release, Expires: 200},
+			IsInvalidGrantErr, a.Address(), a.Address(), 0},
+		7: {[]weave.Permission{a}, &CreateGrantMsg{Grantee: c.Address(), MsgPath: release, Expires: 50},
+			IsInvalidGrantErr, a.Address(), c.Address(), 0},
+		8: {[]weave.Permission{a}, &CreateGrantMsg{Grantee: c.Address(), MsgPath: "escrow", Expires: 200},
+			IsInvalidGrantErr, a.Address(), c.Address(), 0},
+		9: {nil, &CreateGrantMsg{Grantee: c.Address(), MsgPath: release, Expires: 200},
+			errors.IsUnauthorizedErr, a.Address(), c.Address(), 0},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			bucket := NewBucket()
+			require.NoError(t, bucket.Save(db, NewGrant(a, b.Address(), release, 100)))
+
+			ctx := weave.WithHeight(context.Background(), 77)
+			ctx = auth.SetPermissions(ctx, tc.perms...)
+			tx := helpers.MockTx(tc.msg)
+			_, err := r.Check(ctx, db, tx)
+			if tc.check != nil {
+				assert.True(t, tc.check(err), "%+v", err)
+				_, err = r.Deliver(ctx, db, tx)
+				assert.True(t, tc.check(err), "%+v", err)
+			} else {
+				require.NoError(t, err)
+				_, err = r.Deliver(ctx, db, tx)
+				require.NoError(t, err)
+			}
+
+			obj, err := bucket.Get(db, GrantKey(tc.granter, tc.grantee, release))
+			require.NoError(t, err)
+			if tc.expires == 0 {
+				assert.Nil(t, obj)
+				return
+			}
+			require.NotNil(t, obj)
+			assert.Equal(t, tc.expires, AsGrant(obj).Expires)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Package grant lets an address delegate the right to act in its
+name for one message path, until a given block height. This is
+an on-chain power of attorney: the sender of an escrow can let
+an assistant release it for a week, without sharing any keys.
+
+The Decorator looks up the grants of the signers for the path of
+the current message, and Authenticate exposes the granters as
+permissions, so handlers need no changes to honour them.
+*/
+package grant
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+)
+
+const (
+	// BucketName is where we store the grants
+	BucketName = "grants"
+	// IndexGrantee is the index of grants by grantee and path
+	IndexGrantee = "grantee"
+)
+
+func init() {
+	keyspace.Buckets("grant", BucketName)
+}
+
+// IsPath matches the message paths a grant may be given for,
+// like "escrow/release"
+var IsPath = regexp.MustCompile(`^[a-z0-9_]{1,32}/[a-z0-9_]{1,32}$`).MatchString
+
+var _ orm.CloneableData = (*Grant)(nil)
+
+// Validate ensures the grant is valid
+func (g *Grant) Validate() error {
+	if err := weave.Permission(g.Granter).Validate(); err != nil {
+		return err
+	}
+	if err := weave.Address(g.Grantee).Validate(); err != nil {
+		return err
+	}
+	if !IsPath(g.Path) {
+		return ErrInvalidPath(g.Path)
+	}
+	if g.Expires <= 0 {
+		return ErrInvalidExpires(g.Expires)
+	}
+	return nil
+}
+
+// Copy makes a new grant with the same values
+func (g *Grant) Copy() orm.CloneableData {
+	return &Grant{
+		Granter: g.Granter,
+		Grantee: g.Grantee,
+		Path:    g.Path,
+		Expires: g.Expires,
+	}
+}
+
+// IsActive returns true if the grant may be used at this height
+func (g *Grant) IsActive(height int64) bool {
+	return height <= g.Expires
+}
+
+// GrantKey is the primary key of the grant from granter
+// to grantee for the path
+func GrantKey(granter, grantee weave.Address, path string) []byte {
+	key := make([]byte, 0, len(granter)+len(grantee)+len(path))
+	key = append(key, granter...)
+	return append(key, granteeKey(grantee, path)...)
+}
+
+// granteeKey is used in the index, so we can find all grants
+// for a signer and message path at once
+func granteeKey(grantee weave.Address, path string) []byte {
+	key := make([]byte, 0, len(grantee)+len(path))
+	key = append(key, grantee...)
+	return append(key, path...)
+}
+
+// NewGrant creates a grant orm.Object
+func NewGrant(granter weave.Permission, grantee weave.Address,
+	path string, expires int64) orm.Object {
+
+	grant := &Grant{
+		Granter: granter,
+		Grantee: grantee,
+		Path:    path,
+		Expires: expires,
+	}
+	key := GrantKey(granter.Address(), grantee, path)
+	return orm.NewSimpleObj(key, grant)
+}
+
+// AsGrant safely extracts a Grant value from the object
+func AsGrant(obj orm.Object) *Grant {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Grant)
+}
+
+// Bucket is a type-safe wrapper around orm.Bucket
+type Bucket struct {
+	orm.Bucket
+}
+
+// NewBucket initializes a Bucket with default name
+func NewBucket() Bucket {
+	return Bucket{
+		Bucket: orm.NewBucket(BucketName,
+			orm.NewSimpleObj(nil, new(Grant))).
+			WithIndex(IndexGrantee, idxGrantee, false),
+	}
+}
+
+// Active returns all grants the grantee holds for the path,
+// that have not expired at the given height
+func (b Bucket) Active(db weave.KVStore, grantee weave.Address,
+	path string, height int64) ([]*Grant, error) {
+
+	objs, err := b.GetIndexed(db, IndexGrantee, granteeKey(grantee, path))
+	if err != nil {
+		return nil, err
+	}
+	var grants []*Grant
+	for _, obj := range objs {
+		grant := AsGrant(obj)
+		if grant != nil && grant.IsActive(height) {
+			grants = append(grants, grant)
+		}
+	}
+	return grants, nil
+}
+
+func idxGrantee(obj orm.Object) ([]byte, error) {
+	grant := AsGrant(obj)
+	if grant == nil {
+		return nil, errors.New("Can only take index of Grant")
+	}
+	return granteeKey(grant.Grantee, grant.Path), nil
+}
@@ -0,0 +1,40 @@
+package grant
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/module"
+)
+
+// Module adds granting messages to other signers, authenticated
+// by Auth
+type Module struct {
+	Auth x.Authenticator
+}
+
+var (
+	_ module.Module  = Module{}
+	_ module.Router  = Module{}
+	_ module.Querier = Module{}
+)
+
+// Name fulfils module.Module
+func (Module) Name() string {
+	return "grant"
+}
+
+// Version fulfils module.Module
+func (Module) Version() uint32 {
+	return 1
+}
+
+// RegisterRoutes fulfils module.Router
+func (m Module) RegisterRoutes(r weave.Registry) {
+	RegisterRoutes(r, m.Auth)
+}
+
+// RegisterQuery fulfils module.Querier
+func (Module) RegisterQuery(qr weave.QueryRouter) {
+	RegisterQuery(qr)
+}
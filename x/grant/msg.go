@@ -0,0 +1,46 @@
+package grant
+
+import (
+	"github.com/confio/weave"
+)
+
+const (
+	pathCreateGrantMsg = "grant/create"
+	pathRevokeGrantMsg = "grant/revoke"
+)
+
+var _ weave.Msg = (*CreateGrantMsg)(nil)
+var _ weave.Msg = (*RevokeGrantMsg)(nil)
+
+// Path fulfills weave.Msg interface to allow routing
+func (CreateGrantMsg) Path() string {
+	return pathCreateGrantMsg
+}
+
+// Validate makes sure that this is sensible
+func (m *CreateGrantMsg) Validate() error {
+	if m.Expires <= 0 {
+		return ErrInvalidExpires(m.Expires)
+	}
+	return validateGrantee(m.Grantee, m.MsgPath)
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (RevokeGrantMsg) Path() string {
+	return pathRevokeGrantMsg
+}
+
+// Validate makes sure that this is sensible
+func (m *RevokeGrantMsg) Validate() error {
+	return validateGrantee(m.Grantee, m.MsgPath)
+}
+
+func validateGrantee(grantee weave.Address, path string) error {
+	if err := grantee.Validate(); err != nil {
+		return err
+	}
+	if !IsPath(path) {
+		return ErrInvalidPath(path)
+	}
+	return nil
+}
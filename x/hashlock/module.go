@@ -0,0 +1,21 @@
+package hashlock
+
+import (
+	"github.com/iov-one/bcp-demo/module"
+)
+
+// Module declares the schema of preimage permissions, the app
+// authenticates them
+type Module struct{}
+
+var _ module.Module = Module{}
+
+// Name fulfils module.Module
+func (Module) Name() string {
+	return "hashlock"
+}
+
+// Version fulfils module.Module
+func (Module) Version() uint32 {
+	return 1
+}
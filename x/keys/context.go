@@ -0,0 +1,44 @@
+package keys
+
+import (
+	"context"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+)
+
+type contextKey int // local to the keys module
+
+const (
+	contextKeySigners contextKey = iota
+)
+
+// withSigners is a private method, as only this module
+// can add a signer
+func withSigners(ctx weave.Context, signers []weave.Permission) weave.Context {
+	return context.WithValue(ctx, contextKeySigners, signers)
+}
+
+// Authenticate implements x.Authenticator and provides
+// authentication based on secp256k1 and multisig keys.
+type Authenticate struct{}
+
+var _ x.Authenticator = Authenticate{}
+
+// GetPermissions returns which keys have signed the current Context.
+// May be nil
+func (a Authenticate) GetPermissions(ctx weave.Context) []weave.Permission {
+	val, _ := ctx.Value(contextKeySigners).([]weave.Permission)
+	return val
+}
+
+// HasAddress returns true if the given address
+// had signed in the current Context.
+func (a Authenticate) HasAddress(ctx weave.Context, addr weave.Address) bool {
+	for _, perm := range a.GetPermissions(ctx) {
+		if perm.Address().Equals(addr) {
+			return true
+		}
+	}
+	return false
+}
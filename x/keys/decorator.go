@@ -0,0 +1,95 @@
+package keys
+
+import (
+	"github.com/confio/weave"
+)
+
+// SignedTx is a tx that carries signatures of this package
+type SignedTx interface {
+	weave.Tx
+	// GetSignBytes returns the bytes to sign, without
+	// any signatures
+	GetSignBytes() ([]byte, error)
+	GetKeySignatures() []*StdSignature
+}
+
+// Decorator verifies the signatures of this package
+// and adds the signers to the context
+type Decorator struct {
+	bucket UserBucket
+}
+
+var _ weave.Decorator = Decorator{}
+
+// NewDecorator returns a default keys decorator
+func NewDecorator() Decorator {
+	return Decorator{bucket: NewUserBucket()}
+}
+
+// Check verifies signatures before calling down the stack
+func (d Decorator) Check(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	ctx, err := d.withSigners(ctx, store, tx)
+	if err != nil {
+		return res, err
+	}
+	return next.Check(ctx, store, tx)
+}
+
+// Deliver verifies signatures before calling down the stack
+func (d Decorator) Deliver(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	ctx, err := d.withSigners(ctx, store, tx)
+	if err != nil {
+		return res, err
+	}
+	return next.Deliver(ctx, store, tx)
+}
+
+func (d Decorator) withSigners(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.Context, error) {
+
+	stx, ok := tx.(SignedTx)
+	if !ok || len(stx.GetKeySignatures()) == 0 {
+		return ctx, nil
+	}
+	bz, err := stx.GetSignBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	chainID := weave.GetChainID(ctx)
+	signers := make([]weave.Permission, 0, len(stx.GetKeySignatures()))
+	for _, sig := range stx.GetKeySignatures() {
+		signer, err := d.bucket.VerifySignature(store, sig, bz, chainID)
+		if err != nil {
+			return nil, err
+		}
+		signers = append(signers, signer)
+	}
+	return withSigners(ctx, signers), nil
+}
+
+// SignTx creates the signature of key over the tx, to be
+// added to its key signatures
+func SignTx(key *PrivateKey, tx SignedTx, chainID string,
+	seq int64) (*StdSignature, error) {
+
+	bz, err := tx.GetSignBytes()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := key.Sign(BuildSignBytes(bz, chainID, seq))
+	if err != nil {
+		return nil, err
+	}
+	return &StdSignature{
+		Pubkey:    key.PublicKey(),
+		Signature: sig,
+		Sequence:  seq,
+	}, nil
+}
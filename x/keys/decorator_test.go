@@ -0,0 +1,179 @@
+package keys
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+)
+
+func TestDecorator(t *testing.T) {
+	var helpers x.TestHelpers
+
+	a, err := GenPrivKeySecp256k1()
+	require.NoError(t, err)
+	b, err := GenPrivKeySecp256k1()
+	require.NoError(t, err)
+	c, err := GenPrivKeySecp256k1()
+	require.NoError(t, err)
+	chainID := "test-chain"
+
+	multi := &PublicKey{Multi: &MultiKey{
+		Threshold: 2,
+		Keys:      []*PublicKey{a.PublicKey(), b.PublicKey(), c.PublicKey()},
+	}}
+	// signMulti signs with the keys at the given positions
+	signMulti := func(tx SignedTx, seq int64, signers ...int) *StdSignature {
+		bz, err := tx.GetSignBytes()
+		require.NoError(t, err)
+		sigs := make([]*Signature, 3)
+		for i := range sigs {
+			sigs[i] = &Signature{}
+		}
+		for _, i := range signers {
+			key := []*PrivateKey{a, b, c}[i]
+			sigs[i], err = key.Sign(BuildSignBytes(bz, chainID, seq))
+			require.NoError(t, err)
+		}
+		return &StdSignature{
+			Pubkey:    multi,
+			Signature: &Signature{Multi: &MultiSignature{Sigs: sigs}},
+			Sequence:  seq,
+		}
+	}
+	sign := func(key *PrivateKey, tx SignedTx, seq int64) *StdSignature {
+		sig, err := SignTx(key, tx, chainID, seq)
+		require.NoError(t, err)
+		return sig
+	}
+
+	tx := &keysTx{Tx: helpers.MockTx(helpers.MockMsg([]byte("data")))}
+	other := &keysTx{Tx: helpers.MockTx(helpers.MockMsg([]byte("other")))}
+
+	cases := []struct {
+		sigs  []*StdSignature
+		check func(error) bool
+		perms []weave.Permission
+	}{
+		0: {nil, nil, nil},
+		1: {[]*StdSignature{sign(a, tx, 0)}, nil,
+			[]weave.Permission{a.PublicKey().Permission()}},
+		2: {[]*StdSignature{sign(a, tx, 0), sign(b, tx, 0)}, nil,
+			[]weave.Permission{a.PublicKey().Permission(), b.PublicKey().Permission()}},
+		// replay or skipped sequence
+		3: {[]*StdSignature{sign(a, tx, 1)}, IsInvalidSequenceErr, nil},
+		// signed another tx
+		4: {[]*StdSignature{sign(a, other, 0)}, IsInvalidSignatureErr, nil},
+		5: {[]*StdSignature{signMulti(tx, 0, 0, 2)}, nil,
+			[]weave.Permission{multi.Permission()}},
+		6: {[]*StdSignature{signMulti(tx, 0, 0, 1, 2)}, nil,
+			[]weave.Permission{multi.Permission()}},
+		// below threshold
+		7: {[]*StdSignature{signMulti(tx, 0, 1)}, IsInvalidSignatureErr, nil},
+		8: {[]*StdSignature{{Signature: sign(a, tx, 0).Signature}}, IsInvalidPubKeyErr, nil},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			h := new(KeyCheckHandler)
+			stack := helpers.Wrap(NewDecorator(), h)
+			db := store.MemStore()
+			ctx := weave.WithChainID(context.Background(), chainID)
+			tx.sigs = tc.sigs
+
+			_, err := stack.Check(ctx, db, tx)
+			if tc.check != nil {
+				assert.True(t, tc.check(err), "%+v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.perms, h.Perms)
+
+			// the sequence was incremented, so it cannot be replayed
+			_, err = stack.Deliver(ctx, db, tx)
+			if len(tc.sigs) > 0 {
+				assert.True(t, IsInvalidSequenceErr(err), "%+v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMultiKeyValidate(t *testing.T) {
+	a, err := GenPrivKeySecp256k1()
+	require.NoError(t, err)
+	pub := a.PublicKey()
+	multi := &PublicKey{Multi: &MultiKey{Threshold: 1, Keys: []*PublicKey{pub}}}
+
+	cases := []struct {
+		key   *PublicKey
+		valid bool
+	}{
+		0: {pub, true},
+		1: {multi, true},
+		2: {&PublicKey{}, false},
+		3: {&PublicKey{Secp256K1: pub.Secp256K1, Multi: multi.Multi}, false},
+		4: {&PublicKey{Multi: &MultiKey{Threshold: 2, Keys: []*PublicKey{pub}}}, false},
+		5: {&PublicKey{Multi: &MultiKey{Threshold: 0, Keys: []*PublicKey{pub}}}, false},
+		6: {&PublicKey{Multi: &MultiKey{Threshold: 1, Keys: []*PublicKey{multi}}}, false},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			err := tc.key.Validate()
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, IsInvalidPubKeyErr(err), "%+v", err)
+			}
+		})
+	}
+}
+
+//---------------- helpers --------
+
+// keysTx adds key signatures to a mock tx
+type keysTx struct {
+	weave.Tx
+	sigs []*StdSignature
+}
+
+var _ SignedTx = (*keysTx)(nil)
+
+func (t *keysTx) GetSignBytes() ([]byte, error) {
+	msg, err := t.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	return msg.Marshal()
+}
+
+func (t *keysTx) GetKeySignatures() []*StdSignature {
+	return t.sigs
+}
+
+// KeyCheckHandler stores the seen permissions on each call
+type KeyCheckHandler struct {
+	Perms []weave.Permission
+}
+
+var _ weave.Handler = (*KeyCheckHandler)(nil)
+
+func (s *KeyCheckHandler) Check(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (res weave.CheckResult, err error) {
+	s.Perms = Authenticate{}.GetPermissions(ctx)
+	return
+}
+
+func (s *KeyCheckHandler) Deliver(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (res weave.DeliverResult, err error) {
+	s.Perms = Authenticate{}.GetPermissions(ctx)
+	return
+}
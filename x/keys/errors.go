@@ -0,0 +1,44 @@
+package keys
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// keys takes 1090-1100
+const (
+	CodeInvalidPubKey    = 1090
+	CodeInvalidSignature = 1091
+	CodeInvalidSequence  = 1092
+)
+
+var (
+	errInvalidPubKey    = fmt.Errorf("Invalid public key")
+	errInvalidSignature = fmt.Errorf("Invalid signature")
+	errInvalidSequence  = fmt.Errorf("Invalid sequence number")
+)
+
+func ErrInvalidPubKey(reason string) error {
+	return errors.WithLog(reason, errInvalidPubKey, CodeInvalidPubKey)
+}
+func IsInvalidPubKeyErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidPubKey)
+}
+
+func ErrInvalidSignature() error {
+	return errors.WithCode(errInvalidSignature, CodeInvalidSignature)
+}
+func IsInvalidSignatureErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidSignature)
+}
+
+func ErrInvalidSequence(seq int64) error {
+	msg := fmt.Sprintf("%d", seq)
+	return errors.WithLog(msg, errInvalidSequence, CodeInvalidSequence)
+}
+func IsInvalidSequenceErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidSequence)
+}
@@ -0,0 +1,109 @@
+/*
+Package keys adds secp256k1 and multisig public keys next to the
+ed25519 keys of x/sigs, so users can sign with Bitcoin and
+Ethereum style keys and hardware wallets.
+
+A tx carries these signatures in a separate list, the Decorator
+verifies them and Authenticate returns the permissions of the
+signers. A MultiKey is satisfied by signatures of a threshold of
+its keys and has a permission of its own, so it can own coins or
+be the party of an escrow.
+*/
+package keys
+
+import (
+	"crypto/sha256"
+
+	"github.com/confio/weave"
+)
+
+const (
+	// PermExt is the extension of all permissions of this package
+	PermExt = "keys"
+
+	permSecp256k1 = "secp256k1"
+	permMultisig  = "multisig"
+
+	maxMultiKeys = 16
+)
+
+// Validate ensures exactly one key type is set and well formed
+func (k *PublicKey) Validate() error {
+	switch {
+	case k.Secp256K1 != nil && k.Multi != nil:
+		return ErrInvalidPubKey("more than one key")
+	case k.Secp256K1 != nil:
+		_, err := parsePubKey(k.Secp256K1)
+		return err
+	case k.Multi != nil:
+		return k.Multi.Validate()
+	}
+	return ErrInvalidPubKey("missing key")
+}
+
+// Validate ensures the threshold can be reached with the
+// keys, which must all be secp256k1
+func (m *MultiKey) Validate() error {
+	if len(m.Keys) > maxMultiKeys {
+		return ErrInvalidPubKey("too many keys")
+	}
+	if m.Threshold == 0 || int(m.Threshold) > len(m.Keys) {
+		return ErrInvalidPubKey("threshold")
+	}
+	for _, key := range m.Keys {
+		if key == nil || key.Multi != nil {
+			return ErrInvalidPubKey("nested multisig")
+		}
+		if err := key.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Permission returns the permission of this key. A MultiKey
+// is identified by the hash of all its keys and the threshold.
+func (k *PublicKey) Permission() weave.Permission {
+	if k.Multi != nil {
+		bz, err := k.Multi.Marshal()
+		if err != nil {
+			panic(err)
+		}
+		hash := sha256.Sum256(bz)
+		return weave.NewPermission(PermExt, permMultisig, hash[:])
+	}
+	return weave.NewPermission(PermExt, permSecp256k1, k.Secp256K1)
+}
+
+// Address returns the address of the Permission
+func (k *PublicKey) Address() weave.Address {
+	return k.Permission().Address()
+}
+
+// Verify returns true if sig is a valid signature of msg by
+// this key. The key must be valid.
+func (k *PublicKey) Verify(msg []byte, sig *Signature) bool {
+	if sig == nil {
+		return false
+	}
+	if k.Multi == nil {
+		return verifySecp256k1(k.Secp256K1, msg, sig.Secp256K1)
+	}
+
+	multi := sig.GetMulti()
+	if multi == nil || len(multi.Sigs) != len(k.Multi.Keys) {
+		return false
+	}
+	var signed uint32
+	for i, s := range multi.Sigs {
+		// this key did not sign
+		if s == nil || (s.Secp256K1 == nil && s.Multi == nil) {
+			continue
+		}
+		if !k.Multi.Keys[i].Verify(msg, s) {
+			return false
+		}
+		signed++
+	}
+	return signed >= k.Multi.Threshold
+}
@@ -0,0 +1,31 @@
+package keys
+
+import (
+	"github.com/confio/weave"
+
+	"github.com/iov-one/bcp-demo/module"
+)
+
+// Module serves the secp256k1 and multisig keys, the app
+// authenticates them
+type Module struct{}
+
+var (
+	_ module.Module  = Module{}
+	_ module.Querier = Module{}
+)
+
+// Name fulfils module.Module
+func (Module) Name() string {
+	return "keys"
+}
+
+// Version fulfils module.Module
+func (Module) Version() uint32 {
+	return 1
+}
+
+// RegisterQuery fulfils module.Querier
+func (Module) RegisterQuery(qr weave.QueryRouter) {
+	RegisterQuery(qr)
+}
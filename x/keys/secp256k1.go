@@ -0,0 +1,120 @@
+package keys
+
+import (
+	"math/big"
+
+	"github.com/btcsuite/btcd/btcec"
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// PrivKeySize is the length of a secp256k1 private key
+	PrivKeySize = 32
+	// PubKeySize is the length of a compressed secp256k1 public key
+	PubKeySize = 33
+	// SignatureSize is the length of a secp256k1 signature, r || s
+	SignatureSize = 64
+)
+
+var (
+	curveN = btcec.S256().N
+	// halfN is the largest s we accept, to avoid malleable signatures
+	halfN = new(big.Int).Rsh(curveN, 1)
+)
+
+// PrivateKey is a secp256k1 key, as used by Bitcoin and Ethereum
+type PrivateKey struct {
+	key *btcec.PrivateKey
+}
+
+// GenPrivKeySecp256k1 creates a new random key
+func GenPrivKeySecp256k1() (*PrivateKey, error) {
+	key, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{key: key}, nil
+}
+
+// NewPrivateKey imports the 32 byte secret of an existing key,
+// such as an Ethereum private key
+func NewPrivateKey(secret []byte) (*PrivateKey, error) {
+	if len(secret) != PrivKeySize {
+		return nil, ErrInvalidPubKey("private key size")
+	}
+	d := new(big.Int).SetBytes(secret)
+	if d.Sign() == 0 || d.Cmp(curveN) >= 0 {
+		return nil, ErrInvalidPubKey("private key out of range")
+	}
+	key, _ := btcec.PrivKeyFromBytes(btcec.S256(), secret)
+	return &PrivateKey{key: key}, nil
+}
+
+// Bytes returns the 32 byte secret
+func (k *PrivateKey) Bytes() []byte {
+	return padded(k.key.D)
+}
+
+// PublicKey returns the compressed public key
+func (k *PrivateKey) PublicKey() *PublicKey {
+	return &PublicKey{Secp256K1: k.key.PubKey().SerializeCompressed()}
+}
+
+// Sign creates a deterministic (RFC 6979), low-s ECDSA
+// signature over the keccak256 of msg
+func (k *PrivateKey) Sign(msg []byte) (*Signature, error) {
+	sig, err := k.key.Sign(hash(msg))
+	if err != nil {
+		return nil, err
+	}
+	bz := append(padded(sig.R), padded(sig.S)...)
+	return &Signature{Secp256K1: bz}, nil
+}
+
+// verifySecp256k1 checks a low-s ECDSA signature over the
+// keccak256 of msg
+func verifySecp256k1(pubKey, msg, sig []byte) bool {
+	if len(sig) != SignatureSize {
+		return false
+	}
+	pub, err := parsePubKey(pubKey)
+	if err != nil {
+		return false
+	}
+	s := &btcec.Signature{
+		R: new(big.Int).SetBytes(sig[:32]),
+		S: new(big.Int).SetBytes(sig[32:]),
+	}
+	if s.R.Sign() == 0 || s.R.Cmp(curveN) >= 0 ||
+		s.S.Sign() == 0 || s.S.Cmp(halfN) > 0 {
+		return false
+	}
+	return s.Verify(hash(msg), pub)
+}
+
+// parsePubKey only accepts the 33 byte compressed form
+func parsePubKey(pubKey []byte) (*btcec.PublicKey, error) {
+	if len(pubKey) != PubKeySize {
+		return nil, ErrInvalidPubKey("public key size")
+	}
+	pub, err := btcec.ParsePubKey(pubKey, btcec.S256())
+	if err != nil {
+		return nil, ErrInvalidPubKey(err.Error())
+	}
+	return pub, nil
+}
+
+// hash is the keccak256 used by Ethereum
+func hash(msg []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(msg)
+	return h.Sum(nil)
+}
+
+// padded returns i as 32 bytes big endian
+func padded(i *big.Int) []byte {
+	bz := i.Bytes()
+	out := make([]byte, 32)
+	copy(out[32-len(bz):], bz)
+	return out
+}
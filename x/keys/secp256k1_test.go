@@ -0,0 +1,109 @@
+package keys
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicKeyDerivation(t *testing.T) {
+	cases := []struct {
+		secret int64
+		pubkey string
+	}{
+		// these are G, 2G and 3G
+		0: {1, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+		1: {2, "02c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5"},
+		2: {3, "02f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9"},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			key, err := NewPrivateKey(padded(big.NewInt(tc.secret)))
+			require.NoError(t, err)
+			pub := key.PublicKey()
+			assert.Equal(t, tc.pubkey, hex.EncodeToString(pub.Secp256K1))
+			assert.NoError(t, pub.Validate())
+		})
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	key, err := GenPrivKeySecp256k1()
+	require.NoError(t, err)
+	other, err := GenPrivKeySecp256k1()
+	require.NoError(t, err)
+
+	msg := []byte("pay bob")
+	sig, err := key.Sign(msg)
+	require.NoError(t, err)
+	pub := key.PublicKey()
+
+	assert.True(t, pub.Verify(msg, sig))
+	assert.False(t, pub.Verify([]byte("pay eve"), sig))
+	assert.False(t, other.PublicKey().Verify(msg, sig))
+	assert.False(t, pub.Verify(msg, nil))
+	assert.False(t, pub.Verify(msg, &Signature{Secp256K1: sig.Secp256K1[:63]}))
+
+	// the same signature with a high s is malleable
+	s := new(big.Int).SetBytes(sig.Secp256K1[32:])
+	high := append(append([]byte{}, sig.Secp256K1[:32]...), padded(s.Sub(curveN, s))...)
+	assert.False(t, pub.Verify(msg, &Signature{Secp256K1: high}))
+
+	// import the secret again
+	imported, err := NewPrivateKey(key.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, pub, imported.PublicKey())
+}
+
+func TestInvalidKeys(t *testing.T) {
+	_, err := NewPrivateKey(make([]byte, PrivKeySize))
+	assert.True(t, IsInvalidPubKeyErr(err))
+	_, err = NewPrivateKey(padded(curveN))
+	assert.True(t, IsInvalidPubKeyErr(err))
+	_, err = NewPrivateKey([]byte{1, 2, 3})
+	assert.True(t, IsInvalidPubKeyErr(err))
+
+	// x = 5 is not on the curve
+	bad := append([]byte{2}, padded(big.NewInt(5))...)
+	assert.True(t, IsInvalidPubKeyErr((&PublicKey{Secp256K1: bad}).Validate()))
+
+	// only the compressed form is accepted
+	key, err := NewPrivateKey(padded(big.NewInt(1)))
+	require.NoError(t, err)
+	long := key.key.PubKey().SerializeUncompressed()
+	assert.True(t, IsInvalidPubKeyErr((&PublicKey{Secp256K1: long}).Validate()))
+}
+
+// TestEthereumVector checks against the example of
+// web3.eth.accounts.sign in the web3.js documentation
+func TestEthereumVector(t *testing.T) {
+	secret, _ := hex.DecodeString("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	key, err := NewPrivateKey(secret)
+	require.NoError(t, err)
+
+	// the address is the last 20 bytes of the hash of x || y
+	uncompressed := key.key.PubKey().SerializeUncompressed()
+	addr := hash(uncompressed[1:])[12:]
+	assert.Equal(t, "2c7536e3605d9c16a7a3d7b1898e529396a65c23", hex.EncodeToString(addr))
+
+	msg := []byte("\x19Ethereum Signed Message:\n9Some data")
+	assert.Equal(t, "1da44b586eb0729ff70a73c326926f6ed5a25f5b056e7f47fbc6e58d86871655",
+		hex.EncodeToString(hash(msg)))
+
+	sig, err := key.Sign(msg)
+	require.NoError(t, err)
+	assert.Equal(t, "b91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd"+
+		"6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a029",
+		hex.EncodeToString(sig.Secp256K1))
+	assert.True(t, key.PublicKey().Verify(msg, sig))
+
+	// signing is deterministic
+	again, err := key.Sign(msg)
+	require.NoError(t, err)
+	assert.Equal(t, sig, again)
+}
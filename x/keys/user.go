@@ -0,0 +1,112 @@
+package keys
+
+import (
+	"encoding/binary"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+)
+
+// BucketName is where we store the sequence of each key
+const BucketName = "keys"
+
+func init() {
+	keyspace.Buckets("keys", BucketName)
+}
+
+// SignCodeV1 prefixes the sign bytes, so they never match
+// those of x/sigs
+var SignCodeV1 = []byte{0, 0xCA, 0xFE, 1}
+
+var _ orm.CloneableData = (*UserData)(nil)
+
+// Validate ensures the sequence is not negative
+func (u *UserData) Validate() error {
+	if u.Sequence < 0 {
+		return ErrInvalidSequence(u.Sequence)
+	}
+	return nil
+}
+
+// Copy makes a new UserData with the same sequence
+func (u *UserData) Copy() orm.CloneableData {
+	return &UserData{Sequence: u.Sequence}
+}
+
+// UserBucket stores the UserData under the address of the key
+type UserBucket struct {
+	orm.Bucket
+}
+
+// NewUserBucket initializes a UserBucket with default name
+func NewUserBucket() UserBucket {
+	return UserBucket{
+		Bucket: orm.NewBucket(BucketName,
+			orm.NewSimpleObj(nil, new(UserData))),
+	}
+}
+
+// Sequence returns the next sequence the key must sign with
+func (b UserBucket) Sequence(db weave.ReadOnlyKVStore, addr weave.Address) (int64, error) {
+	obj, err := b.Get(db, addr)
+	if err != nil || obj == nil || obj.Value() == nil {
+		return 0, err
+	}
+	return obj.Value().(*UserData).Sequence, nil
+}
+
+// VerifySignature checks the signature over the sign bytes of
+// the tx and increments the sequence of the key. It returns
+// the permission of the signer.
+func (b UserBucket) VerifySignature(db weave.KVStore, sig *StdSignature,
+	bz []byte, chainID string) (weave.Permission, error) {
+
+	pubkey := sig.GetPubkey()
+	if pubkey == nil {
+		return nil, ErrInvalidPubKey("missing key")
+	}
+	if err := pubkey.Validate(); err != nil {
+		return nil, err
+	}
+	addr := pubkey.Address()
+	seq, err := b.Sequence(db, addr)
+	if err != nil {
+		return nil, err
+	}
+	if sig.Sequence != seq {
+		return nil, ErrInvalidSequence(sig.Sequence)
+	}
+
+	signBytes := BuildSignBytes(bz, chainID, seq)
+	if !pubkey.Verify(signBytes, sig.Signature) {
+		return nil, ErrInvalidSignature()
+	}
+
+	user := &UserData{Sequence: seq + 1}
+	err = b.Save(db, orm.NewSimpleObj(addr, user))
+	if err != nil {
+		return nil, err
+	}
+	return pubkey.Permission(), nil
+}
+
+// BuildSignBytes combines the tx bytes with the chain id and
+// sequence, so a signature is only valid once on one chain
+func BuildSignBytes(bz []byte, chainID string, seq int64) []byte {
+	nonce := make([]byte, 8)
+	binary.BigEndian.PutUint64(nonce, uint64(seq))
+
+	signBytes := make([]byte, 0, len(SignCodeV1)+len(chainID)+len(nonce)+len(bz))
+	signBytes = append(signBytes, SignCodeV1...)
+	signBytes = append(signBytes, chainID...)
+	signBytes = append(signBytes, nonce...)
+	return append(signBytes, bz...)
+}
+
+// RegisterQuery will register the sequences as "/keys",
+// queried by the address of the key
+func RegisterQuery(qr weave.QueryRouter) {
+	NewUserBucket().Register("keys", qr)
+}
@@ -0,0 +1,79 @@
+package ledger
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/outbox"
+	"github.com/iov-one/bcp-demo/x/txindex"
+)
+
+// FeeDecorator records the fee of every delivered tx in the
+// outbox, as the tag of KindFee of the payer. It goes right
+// below the decorator taking the fees and above the savepoint
+// of Deliver, as a tx pays its fee even if its message fails.
+type FeeDecorator struct {
+	auth   x.Authenticator
+	events outbox.Bucket
+}
+
+var _ weave.Decorator = FeeDecorator{}
+
+// NewFeeDecorator finds the default payer with auth, like the
+// fee decorator does
+func NewFeeDecorator(auth x.Authenticator) FeeDecorator {
+	return FeeDecorator{auth: auth, events: outbox.NewBucket()}
+}
+
+// Check just calls down the stack
+func (d FeeDecorator) Check(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	return next.Check(ctx, db, tx)
+}
+
+// Deliver records the fee, which is paid by now, before
+// calling down the stack
+func (d FeeDecorator) Deliver(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	if err := d.record(ctx, db, tx); err != nil {
+		return res, err
+	}
+	return next.Deliver(ctx, db, tx)
+}
+
+// record appends the event of the fee of tx, if it has one
+func (d FeeDecorator) record(ctx weave.Context, db weave.KVStore, tx weave.Tx) error {
+	ftx, ok := tx.(cash.FeeTx)
+	if !ok {
+		return nil
+	}
+	finfo := ftx.GetFees()
+	if finfo == nil || x.IsEmpty(finfo.Fees) {
+		return nil
+	}
+	payer := weave.Address(finfo.Payer)
+	if len(payer) == 0 {
+		signer := x.MainSigner(ctx, d.auth)
+		if signer == nil {
+			return nil
+		}
+		payer = signer.Address()
+	}
+
+	tag := Tag(KindFee, payer, *finfo.Fees)
+	height, _ := weave.GetHeight(ctx)
+	header, _ := weave.GetHeader(ctx)
+	e := &outbox.Event{Height: height, Time: header.Time, Key: string(tag.Key), Value: tag.Value}
+	if mtx, ok := tx.(txindex.MarshaledTx); ok {
+		bz, err := mtx.Marshal()
+		if err != nil {
+			return err
+		}
+		e.TxHash = txindex.Hash(bz)
+	}
+	return d.events.Append(db, e)
+}
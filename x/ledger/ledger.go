@@ -0,0 +1,125 @@
+/*
+Package ledger tags the coins that enter and leave an account, so
+bookkeeping can follow them from the events of the outbox without
+reading the state.
+
+The escrow handlers tag what every escrow pays out as "in" of the
+payee and what a new escrow takes from its sender as "out", and the
+FeeDecorator records the fee of every tx as "fee" of its payer. The
+key of a tag is "ledger.<kind>.<ADDRESS>", with the hex address, its
+value is the amount, eg. "12.500000000 IOV". Parse reads them back.
+*/
+package ledger
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+	"github.com/tendermint/tmlibs/common"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+const (
+	// TagPrefix starts the key of every ledger tag
+	TagPrefix = "ledger."
+
+	// KindIn is a payment to the account
+	KindIn = "in"
+	// KindOut is a payment from the account
+	KindOut = "out"
+	// KindFee is a fee the account paid
+	KindFee = "fee"
+
+	fractionalDigits = 9
+)
+
+// Entry is what one tag says
+type Entry struct {
+	Kind    string
+	Address weave.Address
+	Amount  x.Coin
+}
+
+// Tag is the tag of the amount moving in or out of addr
+func Tag(kind string, addr weave.Address, amount x.Coin) common.KVPair {
+	return common.KVPair{
+		Key:   []byte(TagPrefix + kind + "." + addr.String()),
+		Value: []byte(FormatAmount(amount)),
+	}
+}
+
+// TransferTags tags every positive transfer, for the payee with
+// KindIn, for the payer with any other kind
+func TransferTags(kind string, transfers []namecoin.Transfer) []common.KVPair {
+	var res []common.KVPair
+	for _, t := range transfers {
+		if !t.Amount.IsPositive() {
+			continue
+		}
+		addr := t.Src
+		if kind == KindIn {
+			addr = t.Dest
+		}
+		res = append(res, Tag(kind, addr, t.Amount))
+	}
+	return res
+}
+
+// Parse reads the entry of a ledger tag
+func Parse(key string, value []byte) (Entry, error) {
+	var e Entry
+	if !strings.HasPrefix(key, TagPrefix) {
+		return e, fmt.Errorf("not a ledger tag: %q", key)
+	}
+	parts := strings.SplitN(key[len(TagPrefix):], ".", 2)
+	if len(parts) != 2 {
+		return e, fmt.Errorf("invalid ledger tag: %q", key)
+	}
+	switch parts[0] {
+	case KindIn, KindOut, KindFee:
+		e.Kind = parts[0]
+	default:
+		return e, fmt.Errorf("unknown ledger kind: %q", parts[0])
+	}
+	addr, err := hex.DecodeString(parts[1])
+	if err != nil || len(addr) != weave.AddressLength {
+		return e, fmt.Errorf("invalid ledger address: %q", parts[1])
+	}
+	e.Address = addr
+	e.Amount, err = ParseAmount(string(value))
+	return e, err
+}
+
+// FormatAmount writes the coin with all fractional digits and
+// its ticker, eg. "12.500000000 IOV"
+func FormatAmount(c x.Coin) string {
+	return fmt.Sprintf("%d.%09d %s", c.Whole, c.Fractional, c.Ticker)
+}
+
+// ParseAmount reads a coin written by FormatAmount
+func ParseAmount(s string) (x.Coin, error) {
+	var c x.Coin
+	parts := strings.Split(s, " ")
+	if len(parts) != 2 {
+		return c, fmt.Errorf("invalid amount: %q", s)
+	}
+	num := strings.Split(parts[0], ".")
+	if len(num) != 2 || len(num[1]) != fractionalDigits {
+		return c, fmt.Errorf("invalid amount: %q", s)
+	}
+	whole, err := strconv.ParseInt(num[0], 10, 64)
+	if err != nil {
+		return c, err
+	}
+	frac, err := strconv.ParseInt(num[1], 10, 64)
+	if err != nil {
+		return c, err
+	}
+	c = x.Coin{Whole: whole, Fractional: frac, Ticker: parts[1]}
+	return c, c.Validate()
+}
@@ -0,0 +1,132 @@
+package ledger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+	"github.com/tendermint/tmlibs/common"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/outbox"
+	"github.com/iov-one/bcp-demo/x/txindex"
+)
+
+func TestParse(t *testing.T) {
+	addr := weave.NewAddress([]byte("alice"))
+	amount := x.NewCoin(12, 500000000, "IOV")
+
+	tag := Tag(KindIn, addr, amount)
+	assert.Equal(t, "ledger.in."+addr.String(), string(tag.Key))
+	assert.Equal(t, "12.500000000 IOV", string(tag.Value))
+	e, err := Parse(string(tag.Key), tag.Value)
+	require.NoError(t, err)
+	assert.Equal(t, Entry{Kind: KindIn, Address: addr, Amount: amount}, e)
+
+	cases := []struct {
+		key, value string
+	}{
+		{"deposit." + addr.String(), "12.500000000 IOV"},
+		{"ledger.gift." + addr.String(), "12.500000000 IOV"},
+		{"ledger.in.0102", "12.500000000 IOV"},
+		{"ledger.in", "12.500000000 IOV"},
+		{"ledger.in." + addr.String(), "12.5 IOV"},
+		{"ledger.in." + addr.String(), "12.500000000"},
+		{"ledger.in." + addr.String(), "12.500000000 iov"},
+	}
+	for i, tc := range cases {
+		_, err := Parse(tc.key, []byte(tc.value))
+		assert.Error(t, err, "case %d", i)
+	}
+}
+
+func TestTransferTags(t *testing.T) {
+	a := weave.NewAddress([]byte("a"))
+	b := weave.NewAddress([]byte("b"))
+	foo := x.NewCoin(5, 0, "FOO")
+	transfers := []namecoin.Transfer{
+		{Src: a, Dest: b, Amount: foo},
+		{Src: a, Dest: b, Amount: x.NewCoin(0, 0, "FOO")},
+	}
+	assert.Equal(t, []common.KVPair{Tag(KindIn, b, foo)}, TransferTags(KindIn, transfers))
+	assert.Equal(t, []common.KVPair{Tag(KindOut, a, foo)}, TransferTags(KindOut, transfers))
+	assert.Empty(t, TransferTags(KindIn, nil))
+}
+
+func TestFeeDecorator(t *testing.T) {
+	var helpers x.TestHelpers
+	_, signer := helpers.MakeKey()
+	payer := weave.NewAddress([]byte("payer"))
+	fee := x.NewCoin(0, 50000, "IOV")
+
+	cases := []struct {
+		tx       weave.Tx
+		expected []*outbox.Event
+	}{
+		0: {feeTx{helpers.MockTx(&cash.SendMsg{}), &cash.FeeInfo{Fees: &fee}}, []*outbox.Event{{
+			Height: 4, Time: 1500000000, Key: "ledger.fee." + signer.Address().String(),
+			Value: []byte("0.000050000 IOV"), TxHash: txindex.Hash([]byte("tx")),
+		}}},
+		1: {feeTx{helpers.MockTx(&cash.SendMsg{}), &cash.FeeInfo{Payer: payer, Fees: &fee}}, []*outbox.Event{{
+			Height: 4, Time: 1500000000, Key: "ledger.fee." + payer.String(),
+			Value: []byte("0.000050000 IOV"), TxHash: txindex.Hash([]byte("tx")),
+		}}},
+		// no fee, nothing to record
+		2: {feeTx{helpers.MockTx(&cash.SendMsg{}), &cash.FeeInfo{}}, nil},
+		3: {helpers.MockTx(&cash.SendMsg{}), nil},
+	}
+
+	for i, tc := range cases {
+		db := store.MemStore()
+		ctx := weave.WithHeight(context.Background(), 4)
+		ctx = weave.WithHeader(ctx, abci.Header{Height: 4, Time: 1500000000})
+		ctx = helpers.CtxAuth("auth").SetPermissions(ctx, signer)
+		// even failing messages paid their fee
+		stack := helpers.Wrap(NewFeeDecorator(helpers.CtxAuth("auth")),
+			helpers.ErrorHandler(cash.ErrInsufficientFees(fee)))
+
+		_, err := stack.Check(ctx, db, tc.tx)
+		assert.Error(t, err)
+		objs, err := outbox.NewBucket().After(db, 0, 10)
+		require.NoError(t, err)
+		assert.Empty(t, objs, "case %d", i)
+
+		_, err = stack.Deliver(ctx, db, tc.tx)
+		assert.Error(t, err)
+		objs, err = outbox.NewBucket().After(db, 0, 10)
+		require.NoError(t, err)
+		var events []*outbox.Event
+		for _, obj := range objs {
+			events = append(events, outbox.AsEvent(obj))
+		}
+		assert.Equal(t, tc.expected, events, "case %d", i)
+	}
+}
+
+//---------------- helpers --------
+
+// feeTx carries a fee and encodes as "tx"
+type feeTx struct {
+	weave.Tx
+	fees *cash.FeeInfo
+}
+
+var (
+	_ cash.FeeTx          = feeTx{}
+	_ txindex.MarshaledTx = feeTx{}
+)
+
+func (f feeTx) GetFees() *cash.FeeInfo {
+	return f.fees
+}
+
+func (f feeTx) Marshal() ([]byte, error) {
+	return []byte("tx"), nil
+}
@@ -0,0 +1,125 @@
+package limits
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+)
+
+// MaxTxBytes is the largest tx the decoder accepts, on any
+// chain. The params can only set a lower limit.
+const MaxTxBytes = 1 << 20
+
+// SizedTx knows its encoded size, as all protobuf txs do
+type SizedTx interface {
+	Size() int
+}
+
+// CheckEncoded is called by the tx decoder. It rejects txs
+// above MaxTxBytes before they are decoded, and bytes that
+// decoding drops, such as unknown fields, which the Decorator
+// would not count against the limit of the params.
+func CheckEncoded(bz []byte, tx SizedTx) error {
+	if len(bz) > MaxTxBytes {
+		return ErrTxTooLarge(len(bz))
+	}
+	if tx != nil && tx.Size() != len(bz) {
+		return ErrTxPadded(len(bz) - tx.Size())
+	}
+	return nil
+}
+
+// BatchMsg holds several messages that are executed together
+type BatchMsg interface {
+	MsgList() ([]weave.Msg, error)
+}
+
+// memoMsg is any message with a memo
+type memoMsg interface {
+	GetMemo() string
+}
+
+// coinsMsg is any message with an amount of several coins
+type coinsMsg interface {
+	GetAmount() []*x.Coin
+}
+
+// Decorator rejects txs that exceed the limits in the params
+type Decorator struct {
+	bucket ParamsBucket
+}
+
+var _ weave.Decorator = Decorator{}
+
+// NewDecorator returns a decorator using the default bucket
+func NewDecorator() Decorator {
+	return Decorator{bucket: NewParamsBucket()}
+}
+
+// Check verifies the tx before calling down the stack
+func (d Decorator) Check(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	if err := d.validate(store, tx); err != nil {
+		return res, err
+	}
+	return next.Check(ctx, store, tx)
+}
+
+// Deliver verifies the tx before calling down the stack
+func (d Decorator) Deliver(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	if err := d.validate(store, tx); err != nil {
+		return res, err
+	}
+	return next.Deliver(ctx, store, tx)
+}
+
+func (d Decorator) validate(store weave.KVStore, tx weave.Tx) error {
+	params, err := d.bucket.Load(store)
+	if err != nil {
+		return err
+	}
+	if stx, ok := tx.(SizedTx); ok {
+		if err := params.CheckTxSize(stx.Size()); err != nil {
+			return err
+		}
+	}
+	msg, err := tx.GetMsg()
+	if err != nil {
+		return err
+	}
+	return checkMsg(params, msg)
+}
+
+// checkMsg checks the fields of the message, and all
+// messages in it if it is a batch
+func checkMsg(params *Params, msg weave.Msg) error {
+	if m, ok := msg.(memoMsg); ok {
+		if err := params.CheckMemo(m.GetMemo()); err != nil {
+			return err
+		}
+	}
+	if m, ok := msg.(coinsMsg); ok {
+		if err := params.CheckCoins(len(m.GetAmount())); err != nil {
+			return err
+		}
+	}
+	if m, ok := msg.(BatchMsg); ok {
+		msgs, err := m.MsgList()
+		if err != nil {
+			return err
+		}
+		if err := params.CheckBatch(len(msgs)); err != nil {
+			return err
+		}
+		for _, sub := range msgs {
+			if err := checkMsg(params, sub); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
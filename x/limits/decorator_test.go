@@ -0,0 +1,150 @@
+package limits
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/escrow"
+)
+
+func TestDecorator(t *testing.T) {
+	var helpers x.TestHelpers
+
+	params := &Params{
+		MaxTxBytes:    1000,
+		MaxMemoLength: 10,
+		MaxCoins:      2,
+		MaxBatchMsgs:  2,
+	}
+	coins := func(n int) []*x.Coin {
+		res := make([]*x.Coin, n)
+		for i := range res {
+			res[i] = &x.Coin{Whole: 1, Ticker: fmt.Sprintf("FO%c", 'A'+i)}
+		}
+		return res
+	}
+	create := func(memo string, n int) weave.Msg {
+		return &escrow.CreateEscrowMsg{Memo: memo, Amount: coins(n)}
+	}
+
+	cases := []struct {
+		params *Params
+		tx     weave.Tx
+		check  func(error) bool
+	}{
+		0: {params, sizedTx{helpers.MockTx(create("hello", 2)), 1000}, nil},
+		1: {params, sizedTx{helpers.MockTx(create("hello", 2)), 1001}, IsLimitExceededErr},
+		2: {params, helpers.MockTx(create(strings.Repeat("a", 11), 1)), IsLimitExceededErr},
+		3: {params, helpers.MockTx(create("hello", 3)), IsLimitExceededErr},
+		4: {params, helpers.MockTx(&cash.SendMsg{Memo: strings.Repeat("a", 11)}), IsLimitExceededErr},
+		// batches are checked as a whole and each message
+		5: {params, helpers.MockTx(batchMsg{create("hello", 1), create("", 2)}), nil},
+		6: {params, helpers.MockTx(batchMsg{create("", 1), create("", 1), create("", 1)}),
+			IsLimitExceededErr},
+		7: {params, helpers.MockTx(batchMsg{create("", 1), create("", 3)}), IsLimitExceededErr},
+		// no params means no limits
+		8: {nil, sizedTx{helpers.MockTx(create(strings.Repeat("a", 11), 3)), 5000}, nil},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			if tc.params != nil {
+				require.NoError(t, NewParamsBucket().Store(db, tc.params))
+			}
+			stack := helpers.Wrap(NewDecorator(), helpers.CountingHandler())
+
+			_, err := stack.Check(context.Background(), db, tc.tx)
+			if tc.check != nil {
+				assert.True(t, tc.check(err), "%+v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+			_, err = stack.Deliver(context.Background(), db, tc.tx)
+			if tc.check != nil {
+				assert.True(t, tc.check(err), "%+v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckEncoded(t *testing.T) {
+	var helpers x.TestHelpers
+	tx := helpers.MockTx(&cash.SendMsg{})
+
+	cases := []struct {
+		bz    []byte
+		tx    SizedTx
+		check func(error) bool
+	}{
+		0: {make([]byte, 100), nil, nil},
+		1: {make([]byte, MaxTxBytes+1), nil, IsLimitExceededErr},
+		2: {make([]byte, 100), sizedTx{tx, 100}, nil},
+		// unknown fields were dropped on decode
+		3: {make([]byte, 100), sizedTx{tx, 90}, IsLimitExceededErr},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			err := CheckEncoded(tc.bz, tc.tx)
+			if tc.check != nil {
+				assert.True(t, tc.check(err), "%+v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParamsValidate(t *testing.T) {
+	assert.NoError(t, (&Params{}).Validate())
+	assert.NoError(t, (&Params{MaxTxBytes: 1, MaxMemoLength: 2}).Validate())
+	assert.True(t, IsInvalidParamsErr((&Params{MaxCoins: -1}).Validate()))
+}
+
+//---------------- helpers --------
+
+// sizedTx reports a fixed encoded size
+type sizedTx struct {
+	weave.Tx
+	size int
+}
+
+var _ SizedTx = sizedTx{}
+
+func (s sizedTx) Size() int {
+	return s.size
+}
+
+// batchMsg wraps several messages
+type batchMsg []weave.Msg
+
+var _ BatchMsg = batchMsg{}
+
+func (b batchMsg) Marshal() ([]byte, error) {
+	return nil, nil
+}
+
+func (b batchMsg) Unmarshal([]byte) error {
+	return nil
+}
+
+func (b batchMsg) Path() string {
+	return "test/batch"
+}
+
+func (b batchMsg) MsgList() ([]weave.Msg, error) {
+	return b, nil
+}
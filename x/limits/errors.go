@@ -0,0 +1,55 @@
+package limits
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// limits takes 1270-1280
+const (
+	CodeLimitExceeded = 1270
+	CodeInvalidParams = 1271
+)
+
+var (
+	errTxTooLarge     = fmt.Errorf("Tx too large")
+	errTxPadded       = fmt.Errorf("Tx has undecoded bytes")
+	errMemoTooLong    = fmt.Errorf("Memo too long")
+	errTooManyCoins   = fmt.Errorf("Too many coins")
+	errBatchTooLarge  = fmt.Errorf("Too many messages in batch")
+	errNegativeLimits = fmt.Errorf("Limits must not be negative")
+)
+
+func ErrTxTooLarge(size int) error {
+	msg := fmt.Sprintf("%d bytes", size)
+	return errors.WithLog(msg, errTxTooLarge, CodeLimitExceeded)
+}
+func ErrTxPadded(extra int) error {
+	msg := fmt.Sprintf("%d bytes", extra)
+	return errors.WithLog(msg, errTxPadded, CodeLimitExceeded)
+}
+func ErrMemoTooLong(length int) error {
+	msg := fmt.Sprintf("%d characters", length)
+	return errors.WithLog(msg, errMemoTooLong, CodeLimitExceeded)
+}
+func ErrTooManyCoins(count int) error {
+	msg := fmt.Sprintf("%d", count)
+	return errors.WithLog(msg, errTooManyCoins, CodeLimitExceeded)
+}
+func ErrBatchTooLarge(count int) error {
+	msg := fmt.Sprintf("%d", count)
+	return errors.WithLog(msg, errBatchTooLarge, CodeLimitExceeded)
+}
+func IsLimitExceededErr(err error) bool {
+	return errors.HasErrorCode(err, CodeLimitExceeded)
+}
+
+func ErrNegativeLimits() error {
+	return errors.WithCode(errNegativeLimits, CodeInvalidParams)
+}
+func IsInvalidParamsErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidParams)
+}
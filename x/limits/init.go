@@ -0,0 +1,34 @@
+package limits
+
+import (
+	"encoding/json"
+
+	"github.com/confio/weave"
+)
+
+const optLimits = "tx_limits"
+
+// Initializer fulfils the InitStater interface to load data from
+// the genesis file
+type Initializer struct{}
+
+var _ weave.Initializer = Initializer{}
+
+// FromGenesis will store the limits, if set
+func (Initializer) FromGenesis(opts weave.Options, db weave.KVStore) error {
+	var params *Params
+	err := opts.ReadOptions(optLimits, &params)
+	if err != nil || params == nil {
+		return err
+	}
+	return NewParamsBucket().Store(db, params)
+}
+
+// BuildGenesis will create Options with the given limits
+func BuildGenesis(params *Params) (weave.Options, error) {
+	bz, err := json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return weave.Options{optLimits: bz}, nil
+}
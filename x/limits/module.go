@@ -0,0 +1,30 @@
+package limits
+
+import (
+	"github.com/confio/weave"
+
+	"github.com/iov-one/bcp-demo/module"
+)
+
+// Module sets the limits of txs from the genesis file
+type Module struct{}
+
+var (
+	_ module.Module  = Module{}
+	_ module.Genesis = Module{}
+)
+
+// Name fulfils module.Module
+func (Module) Name() string {
+	return "limits"
+}
+
+// Version fulfils module.Module
+func (Module) Version() uint32 {
+	return 1
+}
+
+// Initializer fulfils module.Genesis
+func (Module) Initializer() weave.Initializer {
+	return Initializer{}
+}
@@ -0,0 +1,113 @@
+/*
+Package limits rejects txs that are too large, before they
+reach the signature checks and handlers.
+
+The limits are chain parameters, set in genesis: the encoded
+size of the tx, the length of any memo, the coins in any amount
+and the messages in a batch. The tx decoder of the app calls
+CheckEncoded, so no tx above MaxTxBytes is decoded at all and
+the size checked by the Decorator is the size that was sent.
+*/
+package limits
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+)
+
+const (
+	// BucketName is where we store the limits
+	BucketName = "limits"
+
+	paramsKey = "params"
+)
+
+func init() {
+	keyspace.Buckets("limits", BucketName)
+}
+
+var _ orm.CloneableData = (*Params)(nil)
+
+// Validate ensures no limit is negative
+func (p *Params) Validate() error {
+	if p.MaxTxBytes < 0 || p.MaxMemoLength < 0 ||
+		p.MaxCoins < 0 || p.MaxBatchMsgs < 0 {
+		return ErrNegativeLimits()
+	}
+	return nil
+}
+
+// Copy makes a new set with the same values
+func (p *Params) Copy() orm.CloneableData {
+	return &Params{
+		MaxTxBytes:    p.MaxTxBytes,
+		MaxMemoLength: p.MaxMemoLength,
+		MaxCoins:      p.MaxCoins,
+		MaxBatchMsgs:  p.MaxBatchMsgs,
+	}
+}
+
+// CheckTxSize returns an error if size exceeds the limit
+func (p *Params) CheckTxSize(size int) error {
+	if p.GetMaxTxBytes() > 0 && size > int(p.MaxTxBytes) {
+		return ErrTxTooLarge(size)
+	}
+	return nil
+}
+
+// CheckMemo returns an error if the memo is too long
+func (p *Params) CheckMemo(memo string) error {
+	if p.GetMaxMemoLength() > 0 && len(memo) > int(p.MaxMemoLength) {
+		return ErrMemoTooLong(len(memo))
+	}
+	return nil
+}
+
+// CheckCoins returns an error if there are too many coins
+func (p *Params) CheckCoins(count int) error {
+	if p.GetMaxCoins() > 0 && count > int(p.MaxCoins) {
+		return ErrTooManyCoins(count)
+	}
+	return nil
+}
+
+// CheckBatch returns an error if there are too many messages
+func (p *Params) CheckBatch(count int) error {
+	if p.GetMaxBatchMsgs() > 0 && count > int(p.MaxBatchMsgs) {
+		return ErrBatchTooLarge(count)
+	}
+	return nil
+}
+
+// ParamsBucket stores the chain wide limits
+type ParamsBucket struct {
+	orm.Bucket
+}
+
+// NewParamsBucket initializes a ParamsBucket with default name
+func NewParamsBucket() ParamsBucket {
+	return ParamsBucket{
+		Bucket: orm.NewBucket(BucketName,
+			orm.NewSimpleObj(nil, new(Params))),
+	}
+}
+
+// Load returns the stored params, or no limits if
+// none were set in genesis
+func (b ParamsBucket) Load(db weave.ReadOnlyKVStore) (*Params, error) {
+	obj, err := b.Get(db, []byte(paramsKey))
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil || obj.Value() == nil {
+		return new(Params), nil
+	}
+	return obj.Value().(*Params), nil
+}
+
+// Store saves the params, replacing the old ones
+func (b ParamsBucket) Store(db weave.KVStore, params *Params) error {
+	return b.Save(db, orm.NewSimpleObj([]byte(paramsKey), params))
+}
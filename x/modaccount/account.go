@@ -0,0 +1,148 @@
+/*
+Package modaccount registers the addresses that are owned by
+modules rather than keys, like the fee collector or the
+account of every escrow.
+
+Module accounts are derived from a permission that no
+authenticator ever grants, so they cannot sign. They are also
+recorded in state, so the Decorator can refuse any SendMsg
+to or from them: coins only move in and out through the
+module that owns the account.
+*/
+package modaccount
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+)
+
+const (
+	// BucketName is where we store the derived accounts
+	BucketName = "modacct"
+
+	// FeeCollector receives all tx fees
+	FeeCollector = "fees"
+	// CommunityPool holds the coins of the community
+	CommunityPool = "community"
+	// ConversionPool pays the fees taken in other tokens,
+	// see x/feepool
+	ConversionPool = "conversion"
+	// ShieldedPool holds the coins of all shielded balances,
+	// see x/confidential
+	ShieldedPool = "shielded"
+	// Faucet dispenses coins on testnets, see x/faucet
+	Faucet = "faucet"
+)
+
+func init() {
+	keyspace.Buckets("modaccount", BucketName)
+}
+
+// Fixed lists the module accounts that exist on every chain
+var Fixed = []string{FeeCollector, CommunityPool, ConversionPool, ShieldedPool, Faucet}
+
+// Permission returns the permission of a fixed module account
+func Permission(name string) weave.Permission {
+	return weave.NewPermission("module", "account", []byte(name))
+}
+
+// Address returns the address of a fixed module account
+func Address(name string) weave.Address {
+	return Permission(name).Address()
+}
+
+// fixedByAddress maps all fixed account addresses to their names
+var fixedByAddress = func() map[string]string {
+	res := make(map[string]string, len(Fixed))
+	for _, name := range Fixed {
+		res[string(Address(name))] = name
+	}
+	return res
+}()
+
+// Derived describes the accounts a module creates for each of
+// its objects, such as one account per escrow. The permission
+// is "<module>/<kind>/<key>".
+type Derived struct {
+	module string
+	kind   string
+}
+
+// NewDerived creates the account type of a module
+func NewDerived(module, kind string) Derived {
+	return Derived{module: module, kind: kind}
+}
+
+// Module returns the name of the owning module
+func (d Derived) Module() string {
+	return d.module
+}
+
+// Permission returns the permission of the account for key
+func (d Derived) Permission(key []byte) weave.Permission {
+	return weave.NewPermission(d.module, d.kind, key)
+}
+
+// Address returns the address of the account for key
+func (d Derived) Address(key []byte) weave.Address {
+	return d.Permission(key).Address()
+}
+
+//--- Account
+
+var _ orm.CloneableData = (*Account)(nil)
+
+// Validate ensures the account is valid
+func (a *Account) Validate() error {
+	if a.Module == "" {
+		return ErrModuleAccount(a.Module)
+	}
+	return weave.Permission(a.Permission).Validate()
+}
+
+// Copy makes a new account with the same values
+func (a *Account) Copy() orm.CloneableData {
+	return &Account{
+		Module:     a.Module,
+		Permission: a.Permission,
+	}
+}
+
+//--- Bucket
+
+// Bucket records all derived module accounts by address
+type Bucket struct {
+	orm.Bucket
+}
+
+// NewBucket initializes a Bucket with default name
+func NewBucket() Bucket {
+	return Bucket{
+		Bucket: orm.NewBucket(BucketName,
+			orm.NewSimpleObj(nil, new(Account))),
+	}
+}
+
+// Open registers the account of d for key. The record is kept
+// when the object is gone, so nobody sends to a dead address.
+func (b Bucket) Open(db weave.KVStore, d Derived, key []byte) (weave.Address, error) {
+	perm := d.Permission(key)
+	addr := perm.Address()
+	acct := &Account{Module: d.Module(), Permission: perm}
+	return addr, b.Save(db, orm.NewSimpleObj(addr, acct))
+}
+
+// Module returns the name of the module owning the address,
+// or "" if it is a normal account
+func (b Bucket) Module(db weave.ReadOnlyKVStore, addr weave.Address) (string, error) {
+	if name, ok := fixedByAddress[string(addr)]; ok {
+		return name, nil
+	}
+	obj, err := b.Get(db, addr)
+	if err != nil || obj == nil || obj.Value() == nil {
+		return "", err
+	}
+	return obj.Value().(*Account).Module, nil
+}
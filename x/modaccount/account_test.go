@@ -0,0 +1,58 @@
+package modaccount
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+)
+
+func TestDerived(t *testing.T) {
+	d := NewDerived("escrow", "seq")
+	key := []byte{0, 0, 0, 0, 0, 0, 0, 1}
+
+	perm := d.Permission(key)
+	assert.Equal(t, weave.NewPermission("escrow", "seq", key), perm)
+	assert.Equal(t, perm.Address(), d.Address(key))
+	assert.NoError(t, perm.Validate())
+	assert.Equal(t, "escrow", d.Module())
+
+	// fixed accounts are unique
+	assert.NotEqual(t, Address(FeeCollector), Address(CommunityPool))
+}
+
+func TestBucketModule(t *testing.T) {
+	var helpers x.TestHelpers
+	_, user := helpers.MakeKey()
+
+	db := store.MemStore()
+	bucket := NewBucket()
+	d := NewDerived("escrow", "seq")
+	addr, err := bucket.Open(db, d, []byte("foo"))
+	require.NoError(t, err)
+	assert.Equal(t, d.Address([]byte("foo")), addr)
+
+	cases := []struct {
+		addr   weave.Address
+		module string
+	}{
+		0: {addr, "escrow"},
+		1: {d.Address([]byte("bar")), ""},
+		2: {Address(FeeCollector), FeeCollector},
+		3: {Address(CommunityPool), CommunityPool},
+		4: {user.Address(), ""},
+		5: {Address(ConversionPool), ConversionPool},
+	}
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			module, err := bucket.Module(db, tc.addr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.module, module)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package modaccount
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x/cash"
+)
+
+// Decorator rejects any SendMsg that moves coins to or from
+// a module account
+type Decorator struct {
+	bucket Bucket
+}
+
+var _ weave.Decorator = Decorator{}
+
+// NewDecorator returns a decorator using the default bucket
+func NewDecorator() Decorator {
+	return Decorator{bucket: NewBucket()}
+}
+
+// Check verifies the message before calling down the stack
+func (d Decorator) Check(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	if err := d.validate(store, tx); err != nil {
+		return res, err
+	}
+	return next.Check(ctx, store, tx)
+}
+
+// Deliver verifies the message before calling down the stack
+func (d Decorator) Deliver(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	if err := d.validate(store, tx); err != nil {
+		return res, err
+	}
+	return next.Deliver(ctx, store, tx)
+}
+
+func (d Decorator) validate(store weave.KVStore, tx weave.Tx) error {
+	msg, err := tx.GetMsg()
+	if err != nil {
+		return err
+	}
+	send, ok := msg.(*cash.SendMsg)
+	if !ok {
+		return nil
+	}
+	for _, addr := range [][]byte{send.Src, send.Dest} {
+		module, err := d.bucket.Module(store, addr)
+		if err != nil {
+			return err
+		}
+		if module != "" {
+			return ErrModuleAccount(module)
+		}
+	}
+	return nil
+}
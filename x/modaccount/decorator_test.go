@@ -0,0 +1,61 @@
+package modaccount
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+func TestDecorator(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+
+	db := store.MemStore()
+	escrow, err := NewBucket().Open(db, NewDerived("escrow", "seq"), []byte{1})
+	require.NoError(t, err)
+
+	send := func(src, dest weave.Address) weave.Tx {
+		msg := &cash.SendMsg{
+			Src:    src,
+			Dest:   dest,
+			Amount: &x.Coin{Whole: 10, Ticker: "FOO"},
+		}
+		return helpers.MockTx(msg)
+	}
+
+	cases := []struct {
+		tx      weave.Tx
+		blocked bool
+	}{
+		0: {send(a.Address(), b.Address()), false},
+		1: {send(a.Address(), escrow), true},
+		2: {send(escrow, b.Address()), true},
+		3: {send(a.Address(), Address(FeeCollector)), true},
+		// other messages pass
+		4: {helpers.MockTx(helpers.MockMsg([]byte{1, 2, 3})), false},
+	}
+
+	stack := helpers.Wrap(NewDecorator(), helpers.CountingHandler())
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			_, err := stack.Check(context.Background(), db, tc.tx)
+			_, err2 := stack.Deliver(context.Background(), db, tc.tx)
+			if tc.blocked {
+				assert.True(t, IsModuleAccountErr(err))
+				assert.True(t, IsModuleAccountErr(err2))
+			} else {
+				assert.NoError(t, err)
+				assert.NoError(t, err2)
+			}
+		})
+	}
+}
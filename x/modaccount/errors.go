@@ -0,0 +1,25 @@
+package modaccount
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// modaccount takes 1040-1050
+const (
+	CodeModuleAccount = 1040
+)
+
+var (
+	errModuleAccount = fmt.Errorf("Module account cannot be used here")
+)
+
+func ErrModuleAccount(module string) error {
+	return errors.WithLog(module, errModuleAccount, CodeModuleAccount)
+}
+func IsModuleAccountErr(err error) bool {
+	return errors.HasErrorCode(err, CodeModuleAccount)
+}
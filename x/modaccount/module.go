@@ -0,0 +1,21 @@
+package modaccount
+
+import (
+	"github.com/iov-one/bcp-demo/module"
+)
+
+// Module declares the schema of module accounts, the app
+// guards them with the decorator
+type Module struct{}
+
+var _ module.Module = Module{}
+
+// Name fulfils module.Module
+func (Module) Name() string {
+	return "modaccount"
+}
+
+// Version fulfils module.Module
+func (Module) Version() uint32 {
+	return 4
+}
@@ -0,0 +1,57 @@
+package namecoin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/confio/weave/x"
+)
+
+// fracDigits is the number of decimals a x.Coin can hold
+const fracDigits = 9
+
+// FormatAmount renders the value of a coin as a decimal
+// number, eg. "12.005", without the ticker
+func FormatAmount(coin x.Coin) string {
+	whole, frac := coin.Whole, coin.Fractional
+	sign := ""
+	if whole < 0 || frac < 0 {
+		sign, whole, frac = "-", -whole, -frac
+	}
+	res := sign + strconv.FormatInt(whole, 10)
+	if frac == 0 {
+		return res
+	}
+	digits := fmt.Sprintf("%0*d", fracDigits, frac)
+	return res + "." + strings.TrimRight(digits, "0")
+}
+
+// ParseAmount converts a decimal string like "12.005" into
+// a coin of the given ticker
+func ParseAmount(amount, ticker string) (*x.Coin, error) {
+	whole, frac := amount, ""
+	if i := strings.Index(amount, "."); i >= 0 {
+		whole, frac = amount[:i], amount[i+1:]
+	}
+	if len(frac) > fracDigits {
+		return nil, ErrInvalidAmount(amount)
+	}
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || strings.HasPrefix(whole, "-") {
+		return nil, ErrInvalidAmount(amount)
+	}
+	var f int64
+	if frac != "" {
+		frac += strings.Repeat("0", fracDigits-len(frac))
+		f, err = strconv.ParseInt(frac, 10, 64)
+		if err != nil || strings.HasPrefix(frac, "-") {
+			return nil, ErrInvalidAmount(amount)
+		}
+	}
+	coin := x.NewCoin(w, f, ticker)
+	if err := coin.Validate(); err != nil {
+		return nil, err
+	}
+	return &coin, nil
+}
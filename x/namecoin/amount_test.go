@@ -0,0 +1,59 @@
+package namecoin
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave/x"
+)
+
+func TestParseAmount(t *testing.T) {
+	cases := []struct {
+		amount   string
+		isError  bool
+		expected x.Coin
+	}{
+		0: {"12", false, x.NewCoin(12, 0, "IOV")},
+		1: {"12.5", false, x.NewCoin(12, 500000000, "IOV")},
+		2: {"0.000000001", false, x.NewCoin(0, 1, "IOV")},
+		3: {"1.0000000001", true, x.Coin{}},
+		4: {"-5", true, x.Coin{}},
+		5: {"3.-5", true, x.Coin{}},
+		6: {"abc", true, x.Coin{}},
+		7: {"", true, x.Coin{}},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			coin, err := ParseAmount(tc.amount, "IOV")
+			if tc.isError {
+				assert.True(t, IsInvalidAmountErr(err), "%+v", err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, *coin)
+		})
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	cases := []struct {
+		coin     x.Coin
+		expected string
+	}{
+		0: {x.NewCoin(12, 0, "IOV"), "12"},
+		1: {x.NewCoin(12, 500000000, "IOV"), "12.5"},
+		2: {x.NewCoin(0, 1, "IOV"), "0.000000001"},
+		3: {x.NewCoin(-3, -50000000, "IOV"), "-3.05"},
+		4: {x.NewCoin(0, -7000, "IOV"), "-0.000007"},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			assert.Equal(t, tc.expected, FormatAmount(tc.coin))
+		})
+	}
+}
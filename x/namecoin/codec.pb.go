@@ -9,9 +9,15 @@
 
 	It has these top-level messages:
 		Wallet
+		WalletMetadata
 		Token
 		NewTokenMsg
 		SetWalletNameMsg
+		UpdateWalletMetadataMsg
+		NameSale
+		SellNameMsg
+		BuyNameMsg
+		CancelNameSaleMsg
 */
 package namecoin
 
@@ -37,6 +43,8 @@ const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 type Wallet struct {
 	Coins []*x.Coin `protobuf:"bytes,1,rep,name=coins" json:"coins,omitempty"`
 	Name  string    `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// metadata is set by the owner, see UpdateWalletMetadataMsg
+	Metadata *WalletMetadata `protobuf:"bytes,3,opt,name=metadata" json:"metadata,omitempty"`
 }
 
 func (m *Wallet) Reset()                    { *m = Wallet{} }
@@ -58,6 +66,51 @@ func (m *Wallet) GetName() string {
 	return ""
 }
 
+func (m *Wallet) GetMetadata() *WalletMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// WalletMetadata describes the owner of a wallet to others,
+// all fields are optional
+type WalletMetadata struct {
+	// avatar_hash is the sha256 of the avatar image
+	AvatarHash []byte `protobuf:"bytes,1,opt,name=avatar_hash,json=avatarHash,proto3" json:"avatar_hash,omitempty"`
+	// contact_uri is eg. a mailto: or https: link
+	ContactUri string `protobuf:"bytes,2,opt,name=contact_uri,json=contactUri,proto3" json:"contact_uri,omitempty"`
+	// default_arbiter is the weave.Permission the owner
+	// prefers as arbiter of their escrows
+	DefaultArbiter []byte `protobuf:"bytes,3,opt,name=default_arbiter,json=defaultArbiter,proto3" json:"default_arbiter,omitempty"`
+}
+
+func (m *WalletMetadata) Reset()                    { *m = WalletMetadata{} }
+func (m *WalletMetadata) String() string            { return proto.CompactTextString(m) }
+func (*WalletMetadata) ProtoMessage()               {}
+func (*WalletMetadata) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{1} }
+
+func (m *WalletMetadata) GetAvatarHash() []byte {
+	if m != nil {
+		return m.AvatarHash
+	}
+	return nil
+}
+
+func (m *WalletMetadata) GetContactUri() string {
+	if m != nil {
+		return m.ContactUri
+	}
+	return ""
+}
+
+func (m *WalletMetadata) GetDefaultArbiter() []byte {
+	if m != nil {
+		return m.DefaultArbiter
+	}
+	return nil
+}
+
 // Token contains information about a registered currency
 type Token struct {
 	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
@@ -67,7 +120,7 @@ type Token struct {
 func (m *Token) Reset()                    { *m = Token{} }
 func (m *Token) String() string            { return proto.CompactTextString(m) }
 func (*Token) ProtoMessage()               {}
-func (*Token) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{1} }
+func (*Token) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{2} }
 
 func (m *Token) GetName() string {
 	if m != nil {
@@ -95,7 +148,7 @@ type NewTokenMsg struct {
 func (m *NewTokenMsg) Reset()                    { *m = NewTokenMsg{} }
 func (m *NewTokenMsg) String() string            { return proto.CompactTextString(m) }
 func (*NewTokenMsg) ProtoMessage()               {}
-func (*NewTokenMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{2} }
+func (*NewTokenMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{3} }
 
 func (m *NewTokenMsg) GetTicker() string {
 	if m != nil {
@@ -128,7 +181,7 @@ type SetWalletNameMsg struct {
 func (m *SetWalletNameMsg) Reset()                    { *m = SetWalletNameMsg{} }
 func (m *SetWalletNameMsg) String() string            { return proto.CompactTextString(m) }
 func (*SetWalletNameMsg) ProtoMessage()               {}
-func (*SetWalletNameMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{3} }
+func (*SetWalletNameMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{4} }
 
 func (m *SetWalletNameMsg) GetAddress() []byte {
 	if m != nil {
@@ -144,11 +197,175 @@ func (m *SetWalletNameMsg) GetName() string {
 	return ""
 }
 
+// UpdateWalletMetadataMsg replaces the metadata of a wallet,
+// empty metadata removes it
+type UpdateWalletMetadataMsg struct {
+	Address  []byte          `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Metadata *WalletMetadata `protobuf:"bytes,2,opt,name=metadata" json:"metadata,omitempty"`
+}
+
+func (m *UpdateWalletMetadataMsg) Reset()                    { *m = UpdateWalletMetadataMsg{} }
+func (m *UpdateWalletMetadataMsg) String() string            { return proto.CompactTextString(m) }
+func (*UpdateWalletMetadataMsg) ProtoMessage()               {}
+func (*UpdateWalletMetadataMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{5} }
+
+func (m *UpdateWalletMetadataMsg) GetAddress() []byte {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *UpdateWalletMetadataMsg) GetMetadata() *WalletMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// NameSale offers the name of a wallet for a price. Until the
+// timeout, paying the price moves the name to the buyer and
+// the coins to the seller in one step.
+type NameSale struct {
+	// seller is the address of the wallet holding the name
+	Seller []byte  `protobuf:"bytes,1,opt,name=seller,proto3" json:"seller,omitempty"`
+	Price  *x.Coin `protobuf:"bytes,2,opt,name=price" json:"price,omitempty"`
+	// buyer, if set, is the only address that may buy
+	Buyer []byte `protobuf:"bytes,3,opt,name=buyer,proto3" json:"buyer,omitempty"`
+	// block height after which the sale can only be canceled
+	Timeout int64 `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+}
+
+func (m *NameSale) Reset()                    { *m = NameSale{} }
+func (m *NameSale) String() string            { return proto.CompactTextString(m) }
+func (*NameSale) ProtoMessage()               {}
+func (*NameSale) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{6} }
+
+func (m *NameSale) GetSeller() []byte {
+	if m != nil {
+		return m.Seller
+	}
+	return nil
+}
+
+func (m *NameSale) GetPrice() *x.Coin {
+	if m != nil {
+		return m.Price
+	}
+	return nil
+}
+
+func (m *NameSale) GetBuyer() []byte {
+	if m != nil {
+		return m.Buyer
+	}
+	return nil
+}
+
+func (m *NameSale) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+// SellNameMsg puts the name of a wallet up for sale.
+// The name stays with the wallet until it is bought.
+type SellNameMsg struct {
+	Name  string  `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Price *x.Coin `protobuf:"bytes,2,opt,name=price" json:"price,omitempty"`
+	// buyer optionally reserves the sale for one address
+	Buyer   []byte `protobuf:"bytes,3,opt,name=buyer,proto3" json:"buyer,omitempty"`
+	Timeout int64  `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+}
+
+func (m *SellNameMsg) Reset()                    { *m = SellNameMsg{} }
+func (m *SellNameMsg) String() string            { return proto.CompactTextString(m) }
+func (*SellNameMsg) ProtoMessage()               {}
+func (*SellNameMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{7} }
+
+func (m *SellNameMsg) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SellNameMsg) GetPrice() *x.Coin {
+	if m != nil {
+		return m.Price
+	}
+	return nil
+}
+
+func (m *SellNameMsg) GetBuyer() []byte {
+	if m != nil {
+		return m.Buyer
+	}
+	return nil
+}
+
+func (m *SellNameMsg) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+// BuyNameMsg pays the price of a sale from the buyer
+// wallet, which must not have a name yet, and gets the name
+type BuyNameMsg struct {
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Buyer []byte `protobuf:"bytes,2,opt,name=buyer,proto3" json:"buyer,omitempty"`
+}
+
+func (m *BuyNameMsg) Reset()                    { *m = BuyNameMsg{} }
+func (m *BuyNameMsg) String() string            { return proto.CompactTextString(m) }
+func (*BuyNameMsg) ProtoMessage()               {}
+func (*BuyNameMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{8} }
+
+func (m *BuyNameMsg) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *BuyNameMsg) GetBuyer() []byte {
+	if m != nil {
+		return m.Buyer
+	}
+	return nil
+}
+
+// CancelNameSaleMsg ends a sale after its timeout
+type CancelNameSaleMsg struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (m *CancelNameSaleMsg) Reset()                    { *m = CancelNameSaleMsg{} }
+func (m *CancelNameSaleMsg) String() string            { return proto.CompactTextString(m) }
+func (*CancelNameSaleMsg) ProtoMessage()               {}
+func (*CancelNameSaleMsg) Descriptor() ([]byte, []int) { return fileDescriptorCodec, []int{9} }
+
+func (m *CancelNameSaleMsg) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Wallet)(nil), "namecoin.Wallet")
+	proto.RegisterType((*WalletMetadata)(nil), "namecoin.WalletMetadata")
 	proto.RegisterType((*Token)(nil), "namecoin.Token")
 	proto.RegisterType((*NewTokenMsg)(nil), "namecoin.NewTokenMsg")
 	proto.RegisterType((*SetWalletNameMsg)(nil), "namecoin.SetWalletNameMsg")
+	proto.RegisterType((*UpdateWalletMetadataMsg)(nil), "namecoin.UpdateWalletMetadataMsg")
+	proto.RegisterType((*NameSale)(nil), "namecoin.NameSale")
+	proto.RegisterType((*SellNameMsg)(nil), "namecoin.SellNameMsg")
+	proto.RegisterType((*BuyNameMsg)(nil), "namecoin.BuyNameMsg")
+	proto.RegisterType((*CancelNameSaleMsg)(nil), "namecoin.CancelNameSaleMsg")
 }
 func (m *Wallet) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -183,6 +400,52 @@ func (m *Wallet) MarshalTo(dAtA []byte) (int, error) {
 		i = encodeVarintCodec(dAtA, i, uint64(len(m.Name)))
 		i += copy(dAtA[i:], m.Name)
 	}
+	if m.Metadata != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Metadata.Size()))
+		n1, err := m.Metadata.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	return i, nil
+}
+
+func (m *WalletMetadata) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *WalletMetadata) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.AvatarHash) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.AvatarHash)))
+		i += copy(dAtA[i:], m.AvatarHash)
+	}
+	if len(m.ContactUri) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.ContactUri)))
+		i += copy(dAtA[i:], m.ContactUri)
+	}
+	if len(m.DefaultArbiter) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.DefaultArbiter)))
+		i += copy(dAtA[i:], m.DefaultArbiter)
+	}
 	return i, nil
 }
 
@@ -280,76 +543,356 @@ func (m *SetWalletNameMsg) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
-func encodeVarintCodec(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *UpdateWalletMetadataMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
+	return dAtA[:n], nil
 }
-func (m *Wallet) Size() (n int) {
+
+func (m *UpdateWalletMetadataMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	if len(m.Coins) > 0 {
-		for _, e := range m.Coins {
-			l = e.Size()
-			n += 1 + l + sovCodec(uint64(l))
+	if len(m.Address) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Address)))
+		i += copy(dAtA[i:], m.Address)
+	}
+	if m.Metadata != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Metadata.Size()))
+		n2, err := m.Metadata.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
 		}
+		i += n2
 	}
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
+	return i, nil
+}
+
+func (m *NameSale) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *Token) Size() (n int) {
+func (m *NameSale) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
+	if len(m.Seller) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Seller)))
+		i += copy(dAtA[i:], m.Seller)
 	}
-	if m.SigFigs != 0 {
-		n += 1 + sovCodec(uint64(m.SigFigs))
+	if m.Price != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Price.Size()))
+		n3, err := m.Price.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
 	}
-	return n
+	if len(m.Buyer) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Buyer)))
+		i += copy(dAtA[i:], m.Buyer)
+	}
+	if m.Timeout != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Timeout))
+	}
+	return i, nil
 }
 
-func (m *NewTokenMsg) Size() (n int) {
+func (m *SellNameMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SellNameMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Ticker)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
+	if len(m.Name) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
 	}
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
+	if m.Price != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Price.Size()))
+		n4, err := m.Price.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
 	}
-	if m.SigFigs != 0 {
-		n += 1 + sovCodec(uint64(m.SigFigs))
+	if len(m.Buyer) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Buyer)))
+		i += copy(dAtA[i:], m.Buyer)
 	}
-	return n
+	if m.Timeout != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(m.Timeout))
+	}
+	return i, nil
 }
 
-func (m *SetWalletNameMsg) Size() (n int) {
+func (m *BuyNameMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BuyNameMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Address)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
+	if len(m.Name) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
 	}
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovCodec(uint64(l))
+	if len(m.Buyer) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Buyer)))
+		i += copy(dAtA[i:], m.Buyer)
 	}
-	return n
+	return i, nil
 }
 
-func sovCodec(x uint64) (n int) {
+func (m *CancelNameSaleMsg) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *CancelNameSaleMsg) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Name) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintCodec(dAtA, i, uint64(len(m.Name)))
+		i += copy(dAtA[i:], m.Name)
+	}
+	return i, nil
+}
+
+func encodeVarintCodec(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *Wallet) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Coins) > 0 {
+		for _, e := range m.Coins {
+			l = e.Size()
+			n += 1 + l + sovCodec(uint64(l))
+		}
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Metadata != nil {
+		l = m.Metadata.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *WalletMetadata) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.AvatarHash)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.ContactUri)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.DefaultArbiter)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *Token) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.SigFigs != 0 {
+		n += 1 + sovCodec(uint64(m.SigFigs))
+	}
+	return n
+}
+
+func (m *NewTokenMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Ticker)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.SigFigs != 0 {
+		n += 1 + sovCodec(uint64(m.SigFigs))
+	}
+	return n
+}
+
+func (m *SetWalletNameMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Address)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *UpdateWalletMetadataMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Address)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Metadata != nil {
+		l = m.Metadata.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *NameSale) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Seller)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Price != nil {
+		l = m.Price.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Buyer)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Timeout != 0 {
+		n += 1 + sovCodec(uint64(m.Timeout))
+	}
+	return n
+}
+
+func (m *SellNameMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Price != nil {
+		l = m.Price.Size()
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Buyer)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	if m.Timeout != 0 {
+		n += 1 + sovCodec(uint64(m.Timeout))
+	}
+	return n
+}
+
+func (m *BuyNameMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	l = len(m.Buyer)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func (m *CancelNameSaleMsg) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovCodec(uint64(l))
+	}
+	return n
+}
+
+func sovCodec(x uint64) (n int) {
 	for {
 		n++
 		x >>= 7
@@ -357,12 +900,631 @@ func sovCodec(x uint64) (n int) {
 			break
 		}
 	}
-	return n
-}
-func sozCodec(x uint64) (n int) {
-	return sovCodec(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+	return n
+}
+func sozCodec(x uint64) (n int) {
+	return sovCodec(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (m *Wallet) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Wallet: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Wallet: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Coins", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Coins = append(m.Coins, &x.Coin{})
+			if err := m.Coins[len(m.Coins)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Metadata == nil {
+				m.Metadata = &WalletMetadata{}
+			}
+			if err := m.Metadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *WalletMetadata) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: WalletMetadata: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: WalletMetadata: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AvatarHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AvatarHash = append(m.AvatarHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.AvatarHash == nil {
+				m.AvatarHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContactUri", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ContactUri = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DefaultArbiter", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DefaultArbiter = append(m.DefaultArbiter[:0], dAtA[iNdEx:postIndex]...)
+			if m.DefaultArbiter == nil {
+				m.DefaultArbiter = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Token) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Token: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Token: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SigFigs", wireType)
+			}
+			m.SigFigs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SigFigs |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *NewTokenMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: NewTokenMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: NewTokenMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ticker", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Ticker = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SigFigs", wireType)
+			}
+			m.SigFigs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SigFigs |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SetWalletNameMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SetWalletNameMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SetWalletNameMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Address = append(m.Address[:0], dAtA[iNdEx:postIndex]...)
+			if m.Address == nil {
+				m.Address = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
 }
-func (m *Wallet) Unmarshal(dAtA []byte) error {
+func (m *UpdateWalletMetadataMsg) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -385,17 +1547,17 @@ func (m *Wallet) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Wallet: wiretype end group for non-group")
+			return fmt.Errorf("proto: UpdateWalletMetadataMsg: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Wallet: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: UpdateWalletMetadataMsg: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Coins", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -405,28 +1567,28 @@ func (m *Wallet) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthCodec
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Coins = append(m.Coins, &x.Coin{})
-			if err := m.Coins[len(m.Coins)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Address = append(m.Address[:0], dAtA[iNdEx:postIndex]...)
+			if m.Address == nil {
+				m.Address = []byte{}
 			}
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Metadata", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -436,20 +1598,24 @@ func (m *Wallet) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthCodec
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			if m.Metadata == nil {
+				m.Metadata = &WalletMetadata{}
+			}
+			if err := m.Metadata.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -472,7 +1638,7 @@ func (m *Wallet) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Token) Unmarshal(dAtA []byte) error {
+func (m *NameSale) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -495,17 +1661,48 @@ func (m *Token) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Token: wiretype end group for non-group")
+			return fmt.Errorf("proto: NameSale: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Token: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: NameSale: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seller", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Seller = append(m.Seller[:0], dAtA[iNdEx:postIndex]...)
+			if m.Seller == nil {
+				m.Seller = []byte{}
+			}
+			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Price", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -515,26 +1712,61 @@ func (m *Token) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthCodec
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			if m.Price == nil {
+				m.Price = &x.Coin{}
+			}
+			if err := m.Price.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Buyer", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Buyer = append(m.Buyer[:0], dAtA[iNdEx:postIndex]...)
+			if m.Buyer == nil {
+				m.Buyer = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SigFigs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
 			}
-			m.SigFigs = 0
+			m.Timeout = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -544,7 +1776,7 @@ func (m *Token) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.SigFigs |= (int32(b) & 0x7F) << shift
+				m.Timeout |= (int64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -570,7 +1802,7 @@ func (m *Token) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *NewTokenMsg) Unmarshal(dAtA []byte) error {
+func (m *SellNameMsg) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -593,15 +1825,15 @@ func (m *NewTokenMsg) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: NewTokenMsg: wiretype end group for non-group")
+			return fmt.Errorf("proto: SellNameMsg: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: NewTokenMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: SellNameMsg: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Ticker", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -626,13 +1858,13 @@ func (m *NewTokenMsg) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Ticker = string(dAtA[iNdEx:postIndex])
+			m.Name = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Price", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -642,26 +1874,61 @@ func (m *NewTokenMsg) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthCodec
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Name = string(dAtA[iNdEx:postIndex])
+			if m.Price == nil {
+				m.Price = &x.Coin{}
+			}
+			if err := m.Price.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Buyer", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Buyer = append(m.Buyer[:0], dAtA[iNdEx:postIndex]...)
+			if m.Buyer == nil {
+				m.Buyer = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field SigFigs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
 			}
-			m.SigFigs = 0
+			m.Timeout = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowCodec
@@ -671,7 +1938,7 @@ func (m *NewTokenMsg) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.SigFigs |= (int32(b) & 0x7F) << shift
+				m.Timeout |= (int64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -697,7 +1964,7 @@ func (m *NewTokenMsg) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *SetWalletNameMsg) Unmarshal(dAtA []byte) error {
+func (m *BuyNameMsg) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -720,15 +1987,44 @@ func (m *SetWalletNameMsg) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: SetWalletNameMsg: wiretype end group for non-group")
+			return fmt.Errorf("proto: BuyNameMsg: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: SetWalletNameMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: BuyNameMsg: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCodec
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCodec
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Buyer", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -752,12 +2048,62 @@ func (m *SetWalletNameMsg) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Address = append(m.Address[:0], dAtA[iNdEx:postIndex]...)
-			if m.Address == nil {
-				m.Address = []byte{}
+			m.Buyer = append(m.Buyer[:0], dAtA[iNdEx:postIndex]...)
+			if m.Buyer == nil {
+				m.Buyer = []byte{}
 			}
 			iNdEx = postIndex
-		case 2:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCodec(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthCodec
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *CancelNameSaleMsg) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCodec
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: CancelNameSaleMsg: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: CancelNameSaleMsg: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
 			}
@@ -915,21 +2261,35 @@ var (
 func init() { proto.RegisterFile("x/namecoin/codec.proto", fileDescriptorCodec) }
 
 var fileDescriptorCodec = []byte{
-	// 256 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0xab, 0xd0, 0xcf, 0x4b,
-	0xcc, 0x4d, 0x4d, 0xce, 0xcf, 0xcc, 0xd3, 0x4f, 0xce, 0x4f, 0x49, 0x4d, 0xd6, 0x2b, 0x28, 0xca,
-	0x2f, 0xc9, 0x17, 0xe2, 0x80, 0x89, 0x4a, 0xa9, 0xa6, 0x67, 0x96, 0x64, 0x94, 0x26, 0xe9, 0x25,
-	0xe7, 0xe7, 0xea, 0x27, 0xe7, 0xe7, 0xa5, 0x65, 0xe6, 0xeb, 0x97, 0xa7, 0x26, 0x96, 0xa5, 0xea,
-	0x57, 0x20, 0x6b, 0x50, 0xb2, 0xe6, 0x62, 0x0b, 0x4f, 0xcc, 0xc9, 0x49, 0x2d, 0x11, 0x92, 0xe5,
-	0x62, 0x05, 0x69, 0x2c, 0x96, 0x60, 0x54, 0x60, 0xd6, 0xe0, 0x36, 0x62, 0xd7, 0xab, 0xd0, 0x73,
-	0xce, 0xcf, 0xcc, 0x0b, 0x82, 0x88, 0x0a, 0x09, 0x71, 0xb1, 0x80, 0xcc, 0x96, 0x60, 0x52, 0x60,
-	0xd4, 0xe0, 0x0c, 0x02, 0xb3, 0x95, 0xcc, 0xb8, 0x58, 0x43, 0xf2, 0xb3, 0x53, 0xf3, 0xb0, 0x49,
-	0x0a, 0x49, 0x72, 0x71, 0x14, 0x67, 0xa6, 0xc7, 0xa7, 0x65, 0xa6, 0x17, 0x4b, 0x30, 0x2b, 0x30,
-	0x6a, 0xb0, 0x06, 0xb1, 0x17, 0x67, 0xa6, 0xbb, 0x65, 0xa6, 0x17, 0x2b, 0x85, 0x70, 0x71, 0xfb,
-	0xa5, 0x96, 0x83, 0xb5, 0xfa, 0x16, 0xa7, 0x0b, 0x89, 0x71, 0xb1, 0x95, 0x64, 0x26, 0x67, 0xa7,
-	0x16, 0x49, 0x30, 0x82, 0xf5, 0x43, 0x79, 0xa4, 0x9a, 0xea, 0xc0, 0x25, 0x10, 0x9c, 0x5a, 0x02,
-	0xf1, 0x8d, 0x5f, 0x62, 0x6e, 0x2a, 0xc8, 0x68, 0x09, 0x2e, 0xf6, 0xc4, 0x94, 0x94, 0xa2, 0xd4,
-	0xe2, 0x62, 0xb0, 0xd9, 0x3c, 0x41, 0x30, 0x2e, 0x36, 0xc3, 0x9d, 0x04, 0x4e, 0x3c, 0x92, 0x63,
-	0xbc, 0xf0, 0x48, 0x8e, 0xf1, 0xc1, 0x23, 0x39, 0xc6, 0x09, 0x8f, 0xe5, 0x18, 0x92, 0xd8, 0xc0,
-	0xa1, 0x64, 0x0c, 0x08, 0x00, 0x00, 0xff, 0xff, 0xc9, 0x5f, 0xb5, 0x0c, 0x70, 0x01, 0x00, 0x00,
+	// 468 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x53, 0xcd, 0x6e, 0xd3, 0x4c,
+	0x14, 0xfd, 0x26, 0x69, 0x7e, 0x7a, 0x1d, 0xf5, 0x2b, 0x16, 0x2a, 0x06, 0x89, 0x60, 0x59, 0x42,
+	0xf5, 0xca, 0x96, 0x0a, 0xea, 0x1a, 0x5a, 0x09, 0xb1, 0x69, 0x17, 0x4e, 0x2b, 0x96, 0xd1, 0xcd,
+	0xf8, 0xc6, 0x19, 0xd5, 0xf6, 0xa4, 0x9e, 0x71, 0x9b, 0x8a, 0x97, 0xe0, 0xb1, 0x58, 0xf2, 0x08,
+	0x28, 0xbc, 0x08, 0xb2, 0x3d, 0x0e, 0xa9, 0x54, 0x2a, 0x90, 0xd8, 0xf9, 0x9c, 0x99, 0x73, 0xe6,
+	0xcc, 0xb9, 0x63, 0x38, 0x58, 0x85, 0x39, 0x66, 0xc4, 0xa5, 0xc8, 0x43, 0x2e, 0x63, 0xe2, 0xc1,
+	0xb2, 0x90, 0x5a, 0xda, 0xc3, 0x96, 0x7d, 0xf1, 0x3a, 0x11, 0x7a, 0x51, 0xce, 0x02, 0x2e, 0xb3,
+	0x90, 0xcb, 0x7c, 0x2e, 0x64, 0x78, 0x4b, 0x78, 0x43, 0xe1, 0x6a, 0x5b, 0xe0, 0x5d, 0x43, 0xff,
+	0x13, 0xa6, 0x29, 0x69, 0xfb, 0x25, 0xf4, 0x2a, 0xa1, 0x72, 0x98, 0xdb, 0xf5, 0xad, 0xa3, 0x41,
+	0xb0, 0x0a, 0x4e, 0xa5, 0xc8, 0xa3, 0x86, 0xb5, 0x6d, 0xd8, 0xa9, 0xbc, 0x9d, 0x8e, 0xcb, 0xfc,
+	0xdd, 0xa8, 0xfe, 0xb6, 0xdf, 0xc2, 0x30, 0x23, 0x8d, 0x31, 0x6a, 0x74, 0xba, 0x2e, 0xf3, 0xad,
+	0x23, 0x27, 0x68, 0x03, 0x04, 0x8d, 0xed, 0x99, 0x59, 0x8f, 0x36, 0x3b, 0xbd, 0xcf, 0xb0, 0x77,
+	0x7f, 0xcd, 0x7e, 0x05, 0x16, 0xde, 0xa0, 0xc6, 0x62, 0xba, 0x40, 0xb5, 0x70, 0x98, 0xcb, 0xfc,
+	0x51, 0x04, 0x0d, 0xf5, 0x11, 0xd5, 0xa2, 0xda, 0xc0, 0x65, 0xae, 0x91, 0xeb, 0x69, 0x59, 0x08,
+	0x93, 0x01, 0x0c, 0x75, 0x59, 0x08, 0xfb, 0x10, 0xfe, 0x8f, 0x69, 0x8e, 0x65, 0xaa, 0xa7, 0x58,
+	0xcc, 0x84, 0xa6, 0xa2, 0x0e, 0x34, 0x8a, 0xf6, 0x0c, 0xfd, 0xbe, 0x61, 0xbd, 0x63, 0xe8, 0x5d,
+	0xc8, 0x2b, 0xca, 0x1f, 0xbc, 0xcf, 0x73, 0x18, 0x2a, 0x91, 0x4c, 0xe7, 0x22, 0x51, 0xb5, 0xbc,
+	0x17, 0x0d, 0x94, 0x48, 0x3e, 0x88, 0x44, 0x79, 0x17, 0x60, 0x9d, 0xd3, 0x6d, 0x2d, 0x3d, 0x53,
+	0x89, 0x7d, 0x00, 0x7d, 0x2d, 0xf8, 0x15, 0x15, 0x75, 0xd8, 0xdd, 0xc8, 0xa0, 0xbf, 0x75, 0x7d,
+	0x07, 0xfb, 0x13, 0xd2, 0x4d, 0x1b, 0xe7, 0x98, 0x51, 0x65, 0xed, 0xc0, 0x00, 0xe3, 0xb8, 0x20,
+	0xa5, 0x4c, 0x11, 0x2d, 0x7c, 0xc8, 0xdc, 0x13, 0xf0, 0xec, 0x72, 0x19, 0xa3, 0xa6, 0xfb, 0x95,
+	0x3e, 0x6e, 0xb4, 0x3d, 0xb7, 0xce, 0x1f, 0xcf, 0xed, 0x1a, 0x86, 0x55, 0xc6, 0x09, 0xa6, 0x54,
+	0xdd, 0x5f, 0x51, 0x9a, 0x9a, 0xfb, 0x8f, 0x22, 0x83, 0xaa, 0x47, 0xb4, 0x2c, 0x04, 0x27, 0x63,
+	0xfb, 0xeb, 0x11, 0xd5, 0xac, 0xfd, 0x14, 0x7a, 0xb3, 0xf2, 0x6e, 0x33, 0x9c, 0x06, 0x54, 0x41,
+	0xb5, 0xc8, 0x48, 0x96, 0xda, 0xd9, 0x71, 0x99, 0xdf, 0x8d, 0x5a, 0xe8, 0x2d, 0xc1, 0x9a, 0x50,
+	0x9a, 0xb6, 0xd5, 0xb4, 0x05, 0xb0, 0xad, 0x76, 0xff, 0xf1, 0x89, 0xc7, 0x00, 0x27, 0xe5, 0xdd,
+	0x63, 0x07, 0x6e, 0x1c, 0x3b, 0x5b, 0x8e, 0xde, 0x21, 0x3c, 0x39, 0xc5, 0x9c, 0x53, 0xda, 0x56,
+	0xf4, 0x1b, 0xf9, 0xc9, 0xfe, 0xd7, 0xf5, 0x98, 0x7d, 0x5b, 0x8f, 0xd9, 0xf7, 0xf5, 0x98, 0x7d,
+	0xf9, 0x31, 0xfe, 0x6f, 0xd6, 0xaf, 0xff, 0xc4, 0x37, 0x3f, 0x07, 0x00, 0x31, 0xcf, 0x92, 0x39,
+	0xd4, 0x03, 0x00, 0x00,
 }
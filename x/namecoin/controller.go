@@ -1,10 +1,254 @@
 package namecoin
 
-import "github.com/confio/weave/x/cash"
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
 
-// NewController uses the default implementation for now.
+	"github.com/iov-one/bcp-demo/tracing"
+)
+
+// Controller extends cash.Controller with balance queries
+// and holds, as well as batched transfers.
+//
+// A hold keeps coins in the wallet, but they can not be moved
+// until the hold is released. This lets a module reserve funds
+// without moving them to an account of its own.
+type Controller interface {
+	BatchController
+	// Balance returns all coins in the wallet, including held ones
+	Balance(db weave.ReadOnlyKVStore, addr weave.Address) (x.Coins, error)
+	// Spendable returns the balance minus all holds
+	Spendable(db weave.ReadOnlyKVStore, addr weave.Address) (x.Coins, error)
+	// Hold reserves amount of the spendable coins
+	Hold(db weave.KVStore, addr weave.Address, amount x.Coin) error
+	// ReleaseHold makes held coins spendable again
+	ReleaseHold(db weave.KVStore, addr weave.Address, amount x.Coin) error
+	// WithContext returns the controller passing ctx to the
+	// MoveHooks, handlers bind it to the tx they deliver
+	WithContext(ctx weave.Context) Controller
+}
+
+// NewController uses the default implementation on the
+// namecoin wallets.
 //
 // TODO: better enforce token presence and sigfigs
-func NewController() cash.Controller {
-	return cash.NewController(NewWalletBucket())
+func NewController() Controller {
+	return NewWalletController(NewWalletBucket())
+}
+
+// NewWalletController creates a Controller for any wallet bucket
+func NewWalletController(bucket cash.WalletBucket) Controller {
+	return walletController{
+		BaseController: cash.NewController(bucket),
+		wallets:        bucket,
+		holds:          NewHoldBucket(),
+	}
+}
+
+type walletController struct {
+	cash.BaseController
+	wallets cash.WalletBucket
+	holds   HoldBucket
+	ctx     weave.Context
+}
+
+var _ Controller = walletController{}
+
+// WithContext returns the controller passing ctx to the hooks
+func (c walletController) WithContext(ctx weave.Context) Controller {
+	c.ctx = ctx
+	return c
+}
+
+// Balance returns all coins in the wallet
+func (c walletController) Balance(db weave.ReadOnlyKVStore, addr weave.Address) (x.Coins, error) {
+	obj, err := c.wallets.Get(db, addr)
+	if err != nil || obj == nil {
+		return nil, err
+	}
+	return cash.AsCoins(obj).Clone(), nil
+}
+
+// Spendable returns the balance minus all holds
+func (c walletController) Spendable(db weave.ReadOnlyKVStore, addr weave.Address) (x.Coins, error) {
+	balance, err := c.Balance(db, addr)
+	if err != nil {
+		return nil, err
+	}
+	held, err := c.holds.Held(db, addr)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range held {
+		if !balance.Contains(*h) {
+			// the wallet shrunk below the hold
+			return nil, nil
+		}
+		balance, err = balance.Subtract(*h)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return balance, nil
+}
+
+// Hold reserves amount, which must be spendable
+func (c walletController) Hold(db weave.KVStore, addr weave.Address, amount x.Coin) error {
+	if !amount.IsPositive() {
+		return ErrInvalidAmount("non-positive hold")
+	}
+	if err := c.checkSpendable(db, addr, amount); err != nil {
+		return err
+	}
+	return c.holds.Add(db, addr, amount)
+}
+
+// ReleaseHold makes held coins spendable again
+func (c walletController) ReleaseHold(db weave.KVStore, addr weave.Address, amount x.Coin) error {
+	if !amount.IsPositive() {
+		return ErrInvalidAmount("non-positive hold")
+	}
+	return c.holds.Subtract(db, addr, amount)
+}
+
+// MoveCoins moves the given amount from src to dest, as long
+// as it is not on hold, and calls all registered MoveHooks
+func (c walletController) MoveCoins(db weave.KVStore, src weave.Address,
+	dest weave.Address, amount x.Coin) error {
+
+	defer tracing.Begin(db, "MoveCoins").End()
+	held, err := c.holds.Held(db, src)
+	if err != nil {
+		return err
+	}
+	// without holds, leave all checks to the base controller
+	if len(held) > 0 && amount.IsPositive() {
+		if err := c.checkSpendable(db, src, amount); err != nil {
+			return err
+		}
+	}
+	err = c.BaseController.MoveCoins(db, src, dest, amount)
+	if err != nil {
+		return err
+	}
+	return callMoveHooks(c.ctx, db, src, dest, amount)
+}
+
+// IssueCoins creates the amount in the wallet of dest, and
+// calls all registered MoveHooks with a nil src
+func (c walletController) IssueCoins(db weave.KVStore, dest weave.Address,
+	amount x.Coin) error {
+
+	err := c.BaseController.IssueCoins(db, dest, amount)
+	if err != nil {
+		return err
+	}
+	return callMoveHooks(c.ctx, db, nil, dest, amount)
+}
+
+// MoveCoinsBatch applies all transfers or none of them
+func (c walletController) MoveCoinsBatch(db weave.KVStore, transfers []Transfer) error {
+	defer tracing.Begin(db, "MoveCoinsBatch").End()
+	return moveCoinsBatch(c, db, transfers)
+}
+
+func (c walletController) checkSpendable(db weave.ReadOnlyKVStore, addr weave.Address, amount x.Coin) error {
+	spendable, err := c.Spendable(db, addr)
+	if err != nil {
+		return err
+	}
+	if !spendable.Contains(amount) {
+		return cash.ErrInsufficientFunds()
+	}
+	return nil
+}
+
+// Transfer is one movement of coins in a batch
+type Transfer struct {
+	Src    weave.Address
+	Dest   weave.Address
+	Amount x.Coin
+}
+
+// NewTransfers moves all coins from src to dest
+func NewTransfers(src, dest weave.Address, coins x.Coins) []Transfer {
+	res := make([]Transfer, len(coins))
+	for i, c := range coins {
+		res[i] = Transfer{Src: src, Dest: dest, Amount: *c}
+	}
+	return res
+}
+
+// Validate makes sure the transfer can be applied
+func (t Transfer) Validate() error {
+	if err := t.Src.Validate(); err != nil {
+		return err
+	}
+	if err := t.Dest.Validate(); err != nil {
+		return err
+	}
+	if !t.Amount.IsPositive() {
+		return ErrInvalidAmount("non-positive transfer")
+	}
+	return t.Amount.Validate()
+}
+
+// BatchController is a cash.Controller that can also apply
+// a list of transfers all-or-nothing
+type BatchController interface {
+	cash.Controller
+	MoveCoinsBatch(db weave.KVStore, transfers []Transfer) error
+}
+
+// Batch adds MoveCoinsBatch to any cash.Controller.
+// If it already is a BatchController, it is returned as is.
+func Batch(ctrl cash.Controller) BatchController {
+	if b, ok := ctrl.(BatchController); ok {
+		return b
+	}
+	return batchController{ctrl}
+}
+
+type batchController struct {
+	cash.Controller
+}
+
+// MoveCoinsBatch applies all transfers or none of them
+func (c batchController) MoveCoinsBatch(db weave.KVStore, transfers []Transfer) error {
+	return moveCoinsBatch(c, db, transfers)
+}
+
+// moveCoinsBatch validates all transfers before moving any coins,
+// then applies them in order. If the store can be cache
+// wrapped, nothing is written unless all transfers succeed,
+// otherwise we rely on the rollback of the failed tx.
+func moveCoinsBatch(ctrl cash.Controller, db weave.KVStore, transfers []Transfer) error {
+	for _, t := range transfers {
+		if err := t.Validate(); err != nil {
+			return err
+		}
+	}
+
+	cacheable, ok := db.(weave.CacheableKVStore)
+	if !ok {
+		return applyTransfers(ctrl, db, transfers)
+	}
+	cache := cacheable.CacheWrap()
+	if err := applyTransfers(ctrl, cache, transfers); err != nil {
+		cache.Discard()
+		return err
+	}
+	cache.Write()
+	return nil
+}
+
+func applyTransfers(ctrl cash.Controller, db weave.KVStore, transfers []Transfer) error {
+	for _, t := range transfers {
+		err := ctrl.MoveCoins(db, t.Src, t.Dest, t.Amount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,179 @@
+package namecoin
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+func TestMoveCoinsBatch(t *testing.T) {
+	a := weave.NewAddress([]byte("alice"))
+	b := weave.NewAddress([]byte("bob"))
+	c := weave.NewAddress([]byte("carl"))
+
+	iov := func(n int64) x.Coin { return x.NewCoin(n, 0, "IOV") }
+	cases := []struct {
+		transfers []Transfer
+		isError   bool
+		// expected balances of a, b and c in IOV
+		a, b, c int64
+	}{
+		0: {nil, false, 10, 0, 0},
+		1: {[]Transfer{{a, b, iov(4)}, {a, c, iov(6)}}, false, 0, 4, 6},
+		// multi-hop, b forwards what it got
+		2: {[]Transfer{{a, b, iov(4)}, {b, c, iov(3)}}, false, 6, 1, 3},
+		// second one overdraws, first is rolled back
+		3: {[]Transfer{{a, b, iov(4)}, {a, c, iov(7)}}, true, 10, 0, 0},
+		// invalid transfers are rejected before anything moves
+		4: {[]Transfer{{a, b, iov(4)}, {a, c, iov(0)}}, true, 10, 0, 0},
+		5: {[]Transfer{{a, b, iov(4)}, {nil, c, iov(1)}}, true, 10, 0, 0},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			ctrl := NewController()
+			require.NoError(t, ctrl.IssueCoins(db, a, iov(10)))
+
+			err := ctrl.MoveCoinsBatch(db, tc.transfers)
+			if tc.isError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			bucket := NewWalletBucket()
+			for _, bal := range []struct {
+				addr     weave.Address
+				expected int64
+			}{{a, tc.a}, {b, tc.b}, {c, tc.c}} {
+				obj, err := bucket.Get(db, bal.addr)
+				require.NoError(t, err)
+				var got int64
+				if obj != nil {
+					for _, c := range cash.AsCoins(obj) {
+						got += c.Whole
+					}
+				}
+				assert.Equal(t, bal.expected, got)
+			}
+		})
+	}
+}
+
+func TestBatch(t *testing.T) {
+	base := cash.NewController(NewWalletBucket())
+	batch := Batch(base)
+	assert.Equal(t, batch, Batch(batch))
+
+	coins := x.Coins{&x.Coin{Whole: 1, Ticker: "IOV"}, &x.Coin{Whole: 2, Ticker: "ETH"}}
+	src, dest := weave.NewAddress([]byte{1}), weave.NewAddress([]byte{2})
+	transfers := NewTransfers(src, dest, coins)
+	require.Equal(t, 2, len(transfers))
+	assert.Equal(t, dest, transfers[1].Dest)
+	assert.Equal(t, "ETH", transfers[1].Amount.Ticker)
+}
+
+func TestHolds(t *testing.T) {
+	a := weave.NewAddress([]byte("alice"))
+	b := weave.NewAddress([]byte("bob"))
+	iov := func(n int64) x.Coin { return x.NewCoin(n, 0, "IOV") }
+
+	db := store.MemStore()
+	ctrl := NewController()
+	require.NoError(t, ctrl.IssueCoins(db, a, iov(10)))
+
+	// empty wallets have nothing
+	bal, err := ctrl.Balance(db, b)
+	require.NoError(t, err)
+	assert.Empty(t, bal)
+
+	require.NoError(t, ctrl.Hold(db, a, iov(6)))
+	assert.Error(t, ctrl.Hold(db, a, iov(5)))
+	assert.True(t, IsInvalidAmountErr(ctrl.Hold(db, a, iov(0))))
+
+	bal, err = ctrl.Balance(db, a)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), bal[0].Whole)
+	spend, err := ctrl.Spendable(db, a)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), spend[0].Whole)
+
+	// held coins can not move
+	err = ctrl.MoveCoins(db, a, b, iov(5))
+	assert.True(t, cash.IsInsufficientFundsErr(err), "%+v", err)
+	require.NoError(t, ctrl.MoveCoins(db, a, b, iov(4)))
+
+	// release only what is held
+	assert.True(t, IsInvalidAmountErr(ctrl.ReleaseHold(db, a, iov(7))))
+	require.NoError(t, ctrl.ReleaseHold(db, a, iov(6)))
+	require.NoError(t, ctrl.MoveCoins(db, a, b, iov(6)))
+
+	held, err := NewHoldBucket().Held(db, a)
+	require.NoError(t, err)
+	assert.Empty(t, held)
+	bal, err = ctrl.Balance(db, b)
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), bal[0].Whole)
+}
+
+func TestMoveHooks(t *testing.T) {
+	a := weave.NewAddress([]byte("alice"))
+	b := weave.NewAddress([]byte("bob"))
+	iov := func(n int64) x.Coin { return x.NewCoin(n, 0, "IOV") }
+
+	defer func(hooks []MoveHook) { moveHooks = hooks }(moveHooks)
+	var moved, issued, height int64
+	RegisterMoveHook(func(ctx weave.Context, db weave.KVStore, src, dest weave.Address, amount x.Coin) error {
+		if src == nil {
+			issued += amount.Whole
+		} else {
+			moved += amount.Whole
+		}
+		height, _ = weave.GetHeight(ctx)
+		return nil
+	})
+	RegisterMoveHook(func(ctx weave.Context, db weave.KVStore, src, dest weave.Address, amount x.Coin) error {
+		if amount.Whole > 5 {
+			return ErrInvalidAmount("too much")
+		}
+		return nil
+	})
+
+	db := store.MemStore()
+	// without a context, eg. at genesis
+	require.NoError(t, NewController().IssueCoins(db, a, iov(4)))
+	assert.Equal(t, int64(4), issued)
+	assert.Equal(t, int64(0), height)
+
+	ctrl := NewController().WithContext(weave.WithHeight(context.Background(), 8))
+	require.NoError(t, ctrl.IssueCoins(db, a, iov(5)))
+	require.NoError(t, ctrl.IssueCoins(db, a, iov(1)))
+	assert.Equal(t, int64(10), issued)
+	// a hook can abort the issuance
+	err := ctrl.IssueCoins(db, a, iov(6))
+	assert.True(t, IsInvalidAmountErr(err), "%+v", err)
+
+	require.NoError(t, ctrl.MoveCoins(db, a, b, iov(3)))
+	assert.Equal(t, int64(3), moved)
+	assert.Equal(t, int64(8), height)
+	// failed moves are not reported
+	err = ctrl.MoveCoins(db, b, a, iov(4))
+	assert.True(t, cash.IsInsufficientFundsErr(err), "%+v", err)
+	assert.Equal(t, int64(3), moved)
+
+	// a hook can abort a batch
+	err = ctrl.MoveCoinsBatch(db, []Transfer{{a, b, iov(1)}, {a, b, iov(6)}})
+	assert.True(t, IsInvalidAmountErr(err), "%+v", err)
+	bal, err := ctrl.Balance(db, b)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), bal[0].Whole)
+}
@@ -8,5 +8,16 @@ as a flexible number of significant figures.
 
 Wallets also have a name associated with them, which must be unique
 and can be used to locate the wallet (secondary index).
+The owner can sell the name with a SellNameMsg. Until the timeout
+of the sale, a buyer without a name pays the price and gets the
+name in one BuyNameMsg. After it, anyone may cancel the sale.
+With an UpdateWalletMetadataMsg the owner can add an avatar,
+a contact URI and a default arbiter for escrows to the wallet.
+
+The Controller can put coins on hold. They stay in the wallet,
+but can not be moved until the hold is released. Other modules
+can follow all coin movements and new coins with RegisterMoveHook,
+handlers bind the controller to their tx with WithContext so the
+hooks get its context.
 */
 package namecoin
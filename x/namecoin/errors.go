@@ -7,12 +7,15 @@ import (
 )
 
 // ABCI Response Codes
-// bov takes 1000-1100
+// bov takes 1000-1300
 // namecoin takes 1000-1010
 const (
 	CodeInvalidToken  = 1000
 	CodeInvalidIndex  = 1001
 	CodeInvalidWallet = 1002
+	CodeInvalidAmount = 1003
+	CodeInvalidSale   = 1004
+	CodeInvalidMeta   = 1005
 
 	CodeInvalidObject = 1100 // TODO: move into weave
 )
@@ -25,6 +28,13 @@ var (
 	errInvalidWalletName = fmt.Errorf("Invalid name for a wallet")
 	errChangeWalletName  = fmt.Errorf("Wallet already has a name")
 	errNoSuchWallet      = fmt.Errorf("No wallet exists with this address")
+	errInvalidAmount     = fmt.Errorf("Invalid amount")
+	errNoSuchSale        = fmt.Errorf("Name is not for sale")
+	errDuplicateSale     = fmt.Errorf("Name is already for sale")
+	errSaleExpired       = fmt.Errorf("Sale already expired")
+	errSaleNotExpired    = fmt.Errorf("Sale not yet expired")
+	errInvalidTimeout    = fmt.Errorf("Invalid timeout")
+	errInvalidMetadata   = fmt.Errorf("Invalid wallet metadata")
 
 	errInvalidObject = fmt.Errorf("Wrong object type for this bucket")
 )
@@ -68,3 +78,39 @@ func ErrNoSuchWallet(addr []byte) error {
 func IsInvalidWallet(err error) bool {
 	return errors.HasErrorCode(err, CodeInvalidWallet)
 }
+
+func ErrInvalidAmount(amount string) error {
+	return errors.WithLog(amount, errInvalidAmount, CodeInvalidAmount)
+}
+func IsInvalidAmountErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidAmount)
+}
+
+func ErrNoSuchSale(name string) error {
+	return errors.WithLog(name, errNoSuchSale, CodeInvalidSale)
+}
+func ErrDuplicateSale(name string) error {
+	return errors.WithLog(name, errDuplicateSale, CodeInvalidSale)
+}
+func ErrSaleExpired(timeout int64) error {
+	msg := fmt.Sprintf("%d", timeout)
+	return errors.WithLog(msg, errSaleExpired, CodeInvalidSale)
+}
+func ErrSaleNotExpired(timeout int64) error {
+	msg := fmt.Sprintf("%d", timeout)
+	return errors.WithLog(msg, errSaleNotExpired, CodeInvalidSale)
+}
+func ErrInvalidTimeout(timeout int64) error {
+	msg := fmt.Sprintf("%d", timeout)
+	return errors.WithLog(msg, errInvalidTimeout, CodeInvalidSale)
+}
+func IsInvalidSaleErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidSale)
+}
+
+func ErrInvalidMetadata(reason string) error {
+	return errors.WithLog(reason, errInvalidMetadata, CodeInvalidMeta)
+}
+func IsInvalidMetadataErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidMeta)
+}
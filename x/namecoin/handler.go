@@ -3,31 +3,87 @@ package namecoin
 import (
 	"github.com/confio/weave"
 	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
 	"github.com/confio/weave/x"
 	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/deposit"
+	"github.com/iov-one/bcp-demo/x/rbac"
 )
 
 // NewFeeDecorator customizes cash/FeeDecorator to use our
-// WalletBucket
+// WalletBucket, bound to every tx for the MoveHooks
 func NewFeeDecorator(auth x.Authenticator,
-	min x.Coin) weave.Decorator {
-	return cash.NewFeeDecorator(auth, NewController(), min)
+	min x.Coin) FeeDecorator {
+	return FeeDecorator{auth: auth, min: min}
+}
+
+// FeeDecorator is a cash.FeeDecorator on a controller bound to
+// the tx it takes the fee of
+type FeeDecorator struct {
+	auth      x.Authenticator
+	min       x.Coin
+	collector weave.Address
+}
+
+var _ weave.Decorator = FeeDecorator{}
+
+// WithCollector sends the fees to addr, see cash.FeeDecorator
+func (d FeeDecorator) WithCollector(addr weave.Address) FeeDecorator {
+	d.collector = addr
+	return d
+}
+
+// Check takes the fee, see cash.FeeDecorator
+func (d FeeDecorator) Check(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+	return d.fees(ctx).Check(ctx, db, tx, next)
+}
+
+// Deliver takes the fee, see cash.FeeDecorator
+func (d FeeDecorator) Deliver(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+	return d.fees(ctx).Deliver(ctx, db, tx, next)
+}
+
+func (d FeeDecorator) fees(ctx weave.Context) cash.FeeDecorator {
+	fees := cash.NewFeeDecorator(d.auth, NewController().WithContext(ctx), d.min)
+	if d.collector != nil {
+		fees = fees.WithCollector(d.collector)
+	}
+	return fees
 }
 
 // NewSendHandler customizes cash/SendHandler to use our
-// WalletBucket
+// WalletBucket, bound to every tx for the MoveHooks, and to
+// tag the payments with their memo as destination tag
 func NewSendHandler(auth x.Authenticator) weave.Handler {
-	return cash.NewSendHandler(auth, NewController())
+	return deposit.NewSendHandler(sendHandler{auth: auth})
 }
 
-// NewTokenHandler creates a handler that allows issuer to
-// create new token types. If issuer is nil, anyone can create
-// new tokens.
-// TODO: check that permissioning???
-func NewTokenHandler(auth x.Authenticator, issuer weave.Address) weave.Handler {
+type sendHandler struct {
+	auth x.Authenticator
+}
+
+var _ weave.Handler = sendHandler{}
+
+func (h sendHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	ctrl := NewController().WithContext(ctx)
+	return cash.NewSendHandler(h.auth, ctrl).Check(ctx, db, tx)
+}
+
+func (h sendHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	ctrl := NewController().WithContext(ctx)
+	return cash.NewSendHandler(h.auth, ctrl).Deliver(ctx, db, tx)
+}
+
+// NewTokenHandler creates a handler that allows signers with
+// the issuer role to create new token types.
+func NewTokenHandler(auth rbac.Authenticator) weave.Handler {
 	return TokenHandler{
 		auth:   auth,
-		issuer: issuer,
 		bucket: NewTokenBucket(),
 	}
 }
@@ -43,25 +99,29 @@ func NewSetNameHandler(auth x.Authenticator, bucket NamedBucket) weave.Handler {
 
 // RegisterRoutes will instantiate and register
 // all handlers in this package
-func RegisterRoutes(r weave.Registry, auth x.Authenticator, issuer weave.Address) {
+func RegisterRoutes(r weave.Registry, auth rbac.Authenticator) {
 	pathSend := cash.SendMsg{}.Path()
 	r.Handle(pathSend, NewSendHandler(auth))
-	r.Handle(pathNewTokenMsg, NewTokenHandler(auth, issuer))
+	r.Handle(pathNewTokenMsg, NewTokenHandler(auth))
 	r.Handle(pathSetNameMsg, NewSetNameHandler(auth, NewWalletBucket()))
+	r.Handle(pathUpdateMetadataMsg, NewUpdateMetadataHandler(auth))
+	r.Handle(pathSellNameMsg, NewSellNameHandler(auth))
+	r.Handle(pathBuyNameMsg, NewBuyNameHandler(auth, NewController()))
+	r.Handle(pathCancelSaleMsg, NewCancelSaleHandler())
 }
 
-// RegisterQuery will register wallets as "/wallets"
-// and tokens as "/tokens"
+// RegisterQuery will register wallets as "/wallets",
+// tokens as "/tokens" and names for sale as "/sales"
 func RegisterQuery(qr weave.QueryRouter) {
 	NewWalletBucket().Register("wallets", qr)
 	NewTokenBucket().Register("tokens", qr)
+	NewSaleBucket().Register("sales", qr)
 }
 
 // TokenHandler will handle creating new tokens
 type TokenHandler struct {
-	auth   x.Authenticator
+	auth   rbac.Authenticator
 	bucket TickerBucket
-	issuer weave.Address
 }
 
 var _ weave.Handler = TokenHandler{}
@@ -115,9 +175,10 @@ func (h TokenHandler) validate(ctx weave.Context, db weave.KVStore,
 		return nil, err
 	}
 
-	// make sure we have permission if the issuer is set
-	if h.issuer != nil && !h.auth.HasAddress(ctx, h.issuer) {
-		return nil, errors.ErrUnauthorized()
+	// only issuers may create tokens
+	err = h.auth.RequireRole(ctx, db, rbac.RoleIssuer)
+	if err != nil {
+		return nil, err
 	}
 
 	// make sure no token there yet
@@ -208,3 +269,399 @@ func (h SetNameHandler) validate(ctx weave.Context, db weave.KVStore,
 
 	return msg, nil
 }
+
+// NewUpdateMetadataHandler creates a handler that lets the
+// owner describe the wallet
+func NewUpdateMetadataHandler(auth x.Authenticator) weave.Handler {
+	return UpdateMetadataHandler{
+		auth:   auth,
+		bucket: NewWalletBucket(),
+	}
+}
+
+// UpdateMetadataHandler replaces the metadata of a wallet
+type UpdateMetadataHandler struct {
+	auth   x.Authenticator
+	bucket WalletBucket
+}
+
+var _ weave.Handler = UpdateMetadataHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h UpdateMetadataHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += updateMetadataCost
+	return res, nil
+}
+
+// Deliver stores the metadata on the wallet
+func (h UpdateMetadataHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	wallet := AsWallet(obj)
+	wallet.Metadata = msg.Metadata
+	if msg.Metadata.IsEmpty() {
+		wallet.Metadata = nil
+	}
+	err = h.bucket.Save(db, obj)
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h UpdateMetadataHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*UpdateWalletMetadataMsg, orm.Object, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*UpdateWalletMetadataMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// only wallet owner can describe it
+	if !h.auth.HasAddress(ctx, msg.Address) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	obj, err := h.bucket.Get(db, msg.Address)
+	if err != nil {
+		return nil, nil, err
+	}
+	if obj == nil {
+		return nil, nil, ErrNoSuchWallet(msg.Address)
+	}
+	return msg, obj, nil
+}
+
+// NewSellNameHandler creates a handler that lets the owner of
+// a named wallet offer the name for sale
+func NewSellNameHandler(auth x.Authenticator) weave.Handler {
+	return SellNameHandler{
+		auth:    auth,
+		wallets: NewWalletBucket(),
+		sales:   NewSaleBucket(),
+	}
+}
+
+// SellNameHandler puts a name up for sale
+type SellNameHandler struct {
+	auth    x.Authenticator
+	wallets WalletBucket
+	sales   SaleBucket
+}
+
+var _ weave.Handler = SellNameHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h SellNameHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += sellNameCost
+	return res, nil
+}
+
+// Deliver stores the sale, the name stays with the seller
+// until it is bought
+func (h SellNameHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, seller, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	sale := NewNameSale(msg.Name, seller, *msg.Price, msg.Buyer, msg.Timeout)
+	err = h.sales.Save(db, sale)
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver.
+// It returns the address of the seller.
+func (h SellNameHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*SellNameMsg, weave.Address, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*SellNameMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	height, _ := weave.GetHeight(ctx)
+	if msg.Timeout <= height {
+		return nil, nil, ErrInvalidTimeout(msg.Timeout)
+	}
+
+	// only the owner of the name can sell it
+	obj, err := h.wallets.GetByName(db, msg.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if obj == nil {
+		return nil, nil, ErrNoSuchWallet([]byte(msg.Name))
+	}
+	seller := weave.Address(obj.Key())
+	if !h.auth.HasAddress(ctx, seller) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	if seller.Equals(msg.Buyer) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+
+	sale, err := h.sales.Get(db, msg.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sale != nil {
+		return nil, nil, ErrDuplicateSale(msg.Name)
+	}
+	return msg, seller, nil
+}
+
+// NewBuyNameHandler creates a handler that pays for a name
+// and moves it to the buyer
+func NewBuyNameHandler(auth x.Authenticator, control Controller) weave.Handler {
+	return BuyNameHandler{
+		auth:    auth,
+		wallets: NewWalletBucket(),
+		sales:   NewSaleBucket(),
+		cash:    control,
+	}
+}
+
+// BuyNameHandler completes a sale
+type BuyNameHandler struct {
+	auth    x.Authenticator
+	wallets WalletBucket
+	sales   SaleBucket
+	cash    Controller
+}
+
+var _ weave.Handler = BuyNameHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h BuyNameHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += buyNameCost
+	return res, nil
+}
+
+// Deliver pays the seller and moves the name to the buyer
+func (h BuyNameHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, sale, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	err = h.cash.WithContext(ctx).MoveCoins(db, msg.Buyer, sale.Seller, *sale.Price)
+	if err != nil {
+		return res, err
+	}
+
+	// clear the seller first, the name index is unique
+	obj, err := h.wallets.Get(db, sale.Seller)
+	if err != nil {
+		return res, err
+	}
+	AsWallet(obj).Name = ""
+	err = h.wallets.Save(db, obj)
+	if err != nil {
+		return res, err
+	}
+	obj, err = h.wallets.Get(db, msg.Buyer)
+	if err != nil {
+		return res, err
+	}
+	err = AsNamed(obj).SetName(msg.Name)
+	if err != nil {
+		return res, err
+	}
+	err = h.wallets.Save(db, obj)
+	if err != nil {
+		return res, err
+	}
+
+	err = h.sales.Delete(db, []byte(msg.Name))
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h BuyNameHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*BuyNameMsg, *NameSale, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*BuyNameMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := h.sales.Get(db, msg.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+	sale := AsNameSale(obj)
+	if sale == nil {
+		return nil, nil, ErrNoSuchSale(msg.Name)
+	}
+	height, _ := weave.GetHeight(ctx)
+	if height > sale.Timeout {
+		return nil, nil, ErrSaleExpired(sale.Timeout)
+	}
+
+	// the buyer pays, and must be the reserved one if set
+	if !h.auth.HasAddress(ctx, msg.Buyer) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	if sale.Buyer != nil && !weave.Address(sale.Buyer).Equals(msg.Buyer) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+
+	// a wallet has only one name
+	wallet, err := h.wallets.Get(db, msg.Buyer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if wallet == nil {
+		return nil, nil, ErrNoSuchWallet(msg.Buyer)
+	}
+	if AsWallet(wallet).Name != "" {
+		return nil, nil, ErrChangeWalletName()
+	}
+	spendable, err := h.cash.Spendable(db, msg.Buyer)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !spendable.Contains(*sale.Price) {
+		return nil, nil, cash.ErrInsufficientFunds()
+	}
+	return msg, sale, nil
+}
+
+// NewCancelSaleHandler creates a handler that removes
+// expired sales
+func NewCancelSaleHandler() weave.Handler {
+	return CancelSaleHandler{
+		sales: NewSaleBucket(),
+	}
+}
+
+// CancelSaleHandler lets anyone end a sale after its timeout
+type CancelSaleHandler struct {
+	sales SaleBucket
+}
+
+var _ weave.Handler = CancelSaleHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h CancelSaleHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += cancelSaleCost
+	return res, nil
+}
+
+// Deliver removes the sale, the seller keeps the name
+func (h CancelSaleHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	err = h.sales.Delete(db, []byte(msg.Name))
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h CancelSaleHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*CancelNameSaleMsg, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*CancelNameSaleMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := h.sales.Get(db, msg.Name)
+	if err != nil {
+		return nil, err
+	}
+	sale := AsNameSale(obj)
+	if sale == nil {
+		return nil, ErrNoSuchSale(msg.Name)
+	}
+
+	// the offer holds until the timeout
+	height, _ := weave.GetHeight(ctx)
+	if height <= sale.Timeout {
+		return nil, ErrSaleNotExpired(sale.Timeout)
+	}
+	return msg, nil
+}
@@ -1,6 +1,7 @@
 package namecoin
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/confio/weave/store"
 	"github.com/confio/weave/x"
 	"github.com/confio/weave/x/cash"
+	"github.com/iov-one/bcp-demo/x/rbac"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -118,6 +120,8 @@ func TestNewTokenHandler(t *testing.T) {
 	msg := BuildTokenMsg(ticker, "my good token", 6)
 	added := NewToken(ticker, "my good token", 6)
 
+	issuer := []weave.Permission{perm2}
+
 	// TODO: add queries to verify
 	cases := []struct {
 		signers       []weave.Permission
@@ -131,23 +135,23 @@ func TestNewTokenHandler(t *testing.T) {
 		expected orm.Object
 	}{
 		// wrong message type
-		0: {nil, nil, nil, new(cash.SendMsg),
+		0: {issuer, addr2, nil, new(cash.SendMsg),
 			errors.IsUnknownTxTypeErr, errors.IsUnknownTxTypeErr, "", nil},
 		// wrong currency values
-		1: {nil, nil, nil, BuildTokenMsg("YO", "digga", 7),
+		1: {issuer, addr2, nil, BuildTokenMsg("YO", "digga", 7),
 			x.IsInvalidCurrencyErr, x.IsInvalidCurrencyErr, "", nil},
-		2: {nil, nil, nil, BuildTokenMsg("GOOD", "ill3glz!", 7),
+		2: {issuer, addr2, nil, BuildTokenMsg("GOOD", "ill3glz!", 7),
 			IsInvalidToken, IsInvalidToken, "", nil},
-		3: {nil, nil, nil, BuildTokenMsg("GOOD", "my good token", 17),
+		3: {issuer, addr2, nil, BuildTokenMsg("GOOD", "my good token", 17),
 			IsInvalidToken, IsInvalidToken, "", nil},
 		// valid message, done!
-		4: {nil, nil, nil, msg,
+		4: {issuer, addr2, nil, msg,
 			noErr, noErr, ticker, added},
 		// try to overwrite
-		5: {nil, nil, []orm.Object{NewToken(ticker, "i was here first", 4)}, msg,
+		5: {issuer, addr2, []orm.Object{NewToken(ticker, "i was here first", 4)}, msg,
 			IsInvalidToken, IsInvalidToken, "", nil},
 		// different name is fine
-		6: {nil, nil, []orm.Object{NewToken("OTHR", "i was here first", 4)}, msg,
+		6: {issuer, addr2, []orm.Object{NewToken("OTHR", "i was here first", 4)}, msg,
 			noErr, noErr, ticker, added},
 		// not enough permissions
 		7: {nil, addr, nil, msg,
@@ -161,11 +165,13 @@ func TestNewTokenHandler(t *testing.T) {
 
 	for i, tc := range cases {
 		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
-			auth := helpers.Authenticate(tc.signers...)
+			auth := rbac.NewAuthenticator(helpers.Authenticate(tc.signers...))
 			// use default controller/bucket from namecoin
-			h := NewTokenHandler(auth, tc.issuer)
+			h := NewTokenHandler(auth)
 
 			db := store.MemStore()
+			err := rbac.NewBucket().Assign(db, tc.issuer, rbac.RoleIssuer)
+			require.NoError(t, err)
 			bucket := NewTokenBucket()
 			for _, wallet := range tc.initState {
 				err := bucket.Save(db, wallet)
@@ -175,7 +181,7 @@ func TestNewTokenHandler(t *testing.T) {
 			tx := helpers.MockTx(tc.msg)
 
 			// note that this counts on checkDB *not* creating it
-			_, err := h.Check(nil, db, tx)
+			_, err = h.Check(nil, db, tx)
 			assert.True(t, tc.expectCheck(err), "%+v", err)
 			_, err = h.Deliver(nil, db, tx)
 			assert.True(t, tc.expectDeliver(err), "%+v", err)
@@ -276,3 +282,153 @@ func TestSetNameHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateMetadataHandler(t *testing.T) {
+	var helpers x.TestHelpers
+
+	_, perm := helpers.MakeKey()
+	_, perm2 := helpers.MakeKey()
+	_, arbiter := helpers.MakeKey()
+	addr := perm.Address()
+
+	coin := x.NewCoin(100, 0, "FOO")
+	user := mo(WalletWith(addr, "carl", &coin))
+
+	meta := &WalletMetadata{
+		AvatarHash:     make([]byte, 32),
+		ContactUri:     "mailto:carl@example.com",
+		DefaultArbiter: arbiter,
+	}
+	described := mo(WalletWith(addr, "carl", &coin))
+	AsWallet(described).Metadata = meta
+
+	cases := []struct {
+		signer        weave.Permission
+		initState     []orm.Object
+		msg           weave.Msg
+		expectCheck   checkErr
+		expectDeliver checkErr
+		expected      orm.Object
+	}{
+		// wrong message type
+		0: {perm, nil, new(cash.SendMsg),
+			errors.IsUnknownTxTypeErr, errors.IsUnknownTxTypeErr, nil},
+		// invalid metadata
+		1: {perm, []orm.Object{user},
+			&UpdateWalletMetadataMsg{addr, &WalletMetadata{AvatarHash: []byte{1, 2}}},
+			IsInvalidMetadataErr, IsInvalidMetadataErr, nil},
+		2: {perm, []orm.Object{user},
+			&UpdateWalletMetadataMsg{addr, &WalletMetadata{ContactUri: "carl"}},
+			IsInvalidMetadataErr, IsInvalidMetadataErr, nil},
+		// only the owner may describe the wallet
+		3: {perm2, []orm.Object{user}, &UpdateWalletMetadataMsg{addr, meta},
+			errors.IsUnauthorizedErr, errors.IsUnauthorizedErr, nil},
+		// no wallet to describe
+		4: {perm, nil, &UpdateWalletMetadataMsg{addr, meta},
+			IsInvalidWallet, IsInvalidWallet, nil},
+		// set it
+		5: {perm, []orm.Object{user}, &UpdateWalletMetadataMsg{addr, meta},
+			noErr, noErr, described},
+		// and clear it again
+		6: {perm, []orm.Object{described},
+			&UpdateWalletMetadataMsg{addr, &WalletMetadata{}},
+			noErr, noErr, user},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			auth := helpers.Authenticate(tc.signer)
+			h := NewUpdateMetadataHandler(auth)
+
+			bucket := NewWalletBucket()
+			db := store.MemStore()
+			for _, wallet := range tc.initState {
+				err := bucket.Save(db, wallet)
+				require.NoError(t, err)
+			}
+
+			tx := helpers.MockTx(tc.msg)
+			_, err := h.Check(nil, db, tx)
+			assert.True(t, tc.expectCheck(err), "%+v", err)
+			_, err = h.Deliver(nil, db, tx)
+			assert.True(t, tc.expectDeliver(err), "%+v", err)
+
+			if tc.expected != nil {
+				res, err := bucket.Get(db, addr)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestNameSale(t *testing.T) {
+	var helpers x.TestHelpers
+
+	_, seller := helpers.MakeKey()
+	_, buyer := helpers.MakeKey()
+	_, other := helpers.MakeKey()
+	price := x.NewCoin(10, 0, "FOO")
+
+	db := store.MemStore()
+	wallets := NewWalletBucket()
+	coin := x.NewCoin(100, 0, "FOO")
+	require.NoError(t, wallets.Save(db, mo(WalletWith(seller.Address(), "carl", &coin))))
+	require.NoError(t, wallets.Save(db, mo(WalletWith(buyer.Address(), "", &coin))))
+	require.NoError(t, wallets.Save(db, mo(WalletWith(other.Address(), "otto", &coin))))
+
+	control := NewController()
+	run := func(h weave.Handler, height int64, msg weave.Msg) error {
+		ctx := weave.WithHeight(context.Background(), height)
+		tx := helpers.MockTx(msg)
+		if _, err := h.Check(ctx, db, tx); err != nil {
+			return err
+		}
+		_, err := h.Deliver(ctx, db, tx)
+		return err
+	}
+	sell := func(signer weave.Permission, msg *SellNameMsg) error {
+		return run(NewSellNameHandler(helpers.Authenticate(signer)), 10, msg)
+	}
+	buy := func(signer weave.Permission, height int64, msg *BuyNameMsg) error {
+		return run(NewBuyNameHandler(helpers.Authenticate(signer), control), height, msg)
+	}
+	cancel := func(height int64, name string) error {
+		return run(NewCancelSaleHandler(), height, &CancelNameSaleMsg{Name: name})
+	}
+	nameOf := func(perm weave.Permission) string {
+		obj, err := wallets.Get(db, perm.Address())
+		require.NoError(t, err)
+		return AsWallet(obj).Name
+	}
+
+	offer := &SellNameMsg{Name: "carl", Price: &price, Timeout: 100}
+	// only the owner sells, in the future
+	assert.True(t, errors.IsUnauthorizedErr(sell(buyer, offer)))
+	assert.True(t, IsInvalidSaleErr(sell(seller, &SellNameMsg{Name: "carl", Price: &price, Timeout: 5})))
+	assert.True(t, IsInvalidWallet(sell(seller, &SellNameMsg{Name: "nobody", Price: &price, Timeout: 100})))
+	require.NoError(t, sell(seller, offer))
+	assert.True(t, IsInvalidSaleErr(sell(seller, offer)))
+
+	// a named wallet can not buy, nor after the timeout
+	assert.True(t, IsInvalidWallet(buy(other, 20, &BuyNameMsg{Name: "carl", Buyer: other.Address()})))
+	assert.True(t, IsInvalidSaleErr(buy(buyer, 101, &BuyNameMsg{Name: "carl", Buyer: buyer.Address()})))
+	assert.True(t, errors.IsUnauthorizedErr(buy(other, 20, &BuyNameMsg{Name: "carl", Buyer: buyer.Address()})))
+	assert.True(t, IsInvalidSaleErr(cancel(100, "carl")))
+
+	require.NoError(t, buy(buyer, 20, &BuyNameMsg{Name: "carl", Buyer: buyer.Address()}))
+	assert.Equal(t, "", nameOf(seller))
+	assert.Equal(t, "carl", nameOf(buyer))
+	balance, err := control.Balance(db, seller.Address())
+	require.NoError(t, err)
+	assert.Equal(t, x.Coins{&x.Coin{Whole: 110, Ticker: "FOO"}}, balance)
+	assert.True(t, IsInvalidSaleErr(buy(buyer, 20, &BuyNameMsg{Name: "carl", Buyer: buyer.Address()})))
+
+	// reserved sales only go to the buyer, and can be canceled after the timeout
+	reserved := &SellNameMsg{Name: "carl", Price: &price, Buyer: seller.Address(), Timeout: 50}
+	require.NoError(t, sell(buyer, reserved))
+	assert.True(t, errors.IsUnauthorizedErr(buy(other, 20, &BuyNameMsg{Name: "carl", Buyer: other.Address()})))
+	require.NoError(t, cancel(51, "carl"))
+	assert.True(t, IsInvalidSaleErr(cancel(51, "carl")))
+	assert.Equal(t, "carl", nameOf(buyer))
+}
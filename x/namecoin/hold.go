@@ -0,0 +1,71 @@
+package namecoin
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+// BucketNameHold is where we store the coins on hold
+const BucketNameHold = "hold"
+
+// HoldBucket stores, per address, the coins that stay in the
+// wallet but can not be spent until the hold is released.
+type HoldBucket struct {
+	orm.Bucket
+}
+
+// NewHoldBucket initializes a HoldBucket with default name
+func NewHoldBucket() HoldBucket {
+	return HoldBucket{
+		Bucket: orm.NewBucket(BucketNameHold,
+			orm.NewSimpleObj(nil, new(cash.Set))),
+	}
+}
+
+// Held returns all coins on hold for the address
+func (b HoldBucket) Held(db weave.ReadOnlyKVStore, addr weave.Address) (x.Coins, error) {
+	obj, err := b.Get(db, addr)
+	if err != nil || obj == nil || obj.Value() == nil {
+		return nil, err
+	}
+	return cash.AsCoins(obj), nil
+}
+
+// Add puts amount on hold, on top of what is already held
+func (b HoldBucket) Add(db weave.KVStore, addr weave.Address, amount x.Coin) error {
+	held, err := b.Held(db, addr)
+	if err != nil {
+		return err
+	}
+	held, err = held.Clone().Add(amount)
+	if err != nil {
+		return err
+	}
+	return b.store(db, addr, held)
+}
+
+// Subtract releases amount from the hold. It fails if less
+// than that is held.
+func (b HoldBucket) Subtract(db weave.KVStore, addr weave.Address, amount x.Coin) error {
+	held, err := b.Held(db, addr)
+	if err != nil {
+		return err
+	}
+	if !held.Contains(amount) {
+		return ErrInvalidAmount("more than held")
+	}
+	held, err = held.Clone().Subtract(amount)
+	if err != nil {
+		return err
+	}
+	return b.store(db, addr, held)
+}
+
+func (b HoldBucket) store(db weave.KVStore, addr weave.Address, held x.Coins) error {
+	if held.IsEmpty() {
+		return b.Delete(db, addr)
+	}
+	return b.Save(db, orm.NewSimpleObj(addr, &cash.Set{Coins: held}))
+}
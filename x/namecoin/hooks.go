@@ -0,0 +1,39 @@
+package namecoin
+
+import (
+	"context"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+)
+
+// MoveHook is called after coins moved from src to dest, so other
+// modules can follow all transfers (eg. to track the value locked
+// in escrows) without wrapping the controller. New coins are
+// reported with a nil src. Returning an error aborts the transfer,
+// which rolls back the tx.
+//
+// cash.Controller has no context, so ctx is the one passed to
+// Controller.WithContext. It is empty for a controller that was
+// not bound to a tx, eg. when minting the genesis coins.
+type MoveHook func(ctx weave.Context, db weave.KVStore, src, dest weave.Address, amount x.Coin) error
+
+var moveHooks []MoveHook
+
+// RegisterMoveHook adds a hook to all controllers of this package.
+// Call it on startup, before the app handles any tx.
+func RegisterMoveHook(hook MoveHook) {
+	moveHooks = append(moveHooks, hook)
+}
+
+func callMoveHooks(ctx weave.Context, db weave.KVStore, src, dest weave.Address, amount x.Coin) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for _, hook := range moveHooks {
+		if err := hook(ctx, db, src, dest, amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
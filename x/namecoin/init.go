@@ -2,6 +2,7 @@ package namecoin
 
 import (
 	"encoding/json"
+	"fmt"
 
 	"github.com/confio/weave"
 	"github.com/confio/weave/errors"
@@ -120,3 +121,39 @@ func BuildGenesis(wallets []GenesisAccount,
 
 	return opts, nil
 }
+
+// RewriteGenesis moves every wallet in opts to the address
+// returned by fn, along with its name and coins. fn also gets
+// a description of the wallet for reporting. It fails if two
+// wallets end up at the same address.
+func RewriteGenesis(opts weave.Options,
+	fn func(where string, addr weave.Address) (weave.Address, error)) error {
+
+	var accts []GenesisAccount
+	err := opts.ReadOptions(optWallet, &accts)
+	if err != nil || accts == nil {
+		return err
+	}
+	seen := make(map[string]bool, len(accts))
+	for i, acct := range accts {
+		where := fmt.Sprintf("wallet %s", acct.Address)
+		if name := acct.GetName(); name != "" {
+			where = fmt.Sprintf("wallet %s (%s)", acct.Address, name)
+		}
+		addr, err := fn(where, acct.Address)
+		if err != nil {
+			return err
+		}
+		if seen[string(addr)] {
+			return fmt.Errorf("two wallets at %s", addr)
+		}
+		seen[string(addr)] = true
+		accts[i].Address = addr
+	}
+	bz, err := json.MarshalIndent(accts, "", "  ")
+	if err != nil {
+		return err
+	}
+	opts[optWallet] = bz
+	return nil
+}
@@ -0,0 +1,38 @@
+package namecoin
+
+import (
+	"crypto/sha256"
+	"net/url"
+
+	"github.com/confio/weave"
+)
+
+const maxContactLength = 256
+
+// Validate ensures all fields that are set are well formed
+func (m *WalletMetadata) Validate() error {
+	if len(m.AvatarHash) != 0 && len(m.AvatarHash) != sha256.Size {
+		return ErrInvalidMetadata("avatar hash")
+	}
+	if m.ContactUri != "" {
+		if len(m.ContactUri) > maxContactLength {
+			return ErrInvalidMetadata("contact uri too long")
+		}
+		u, err := url.Parse(m.ContactUri)
+		if err != nil || u.Scheme == "" {
+			return ErrInvalidMetadata("contact uri")
+		}
+	}
+	if len(m.DefaultArbiter) != 0 {
+		if err := weave.Permission(m.DefaultArbiter).Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsEmpty returns true if no field is set
+func (m *WalletMetadata) IsEmpty() bool {
+	return m == nil || (len(m.AvatarHash) == 0 && m.ContactUri == "" &&
+		len(m.DefaultArbiter) == 0)
+}
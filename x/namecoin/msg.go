@@ -12,10 +12,18 @@ import (
 var _ weave.Msg = (*NewTokenMsg)(nil)
 
 const (
-	pathNewTokenMsg       = "namecoin/ticker"
-	pathSetNameMsg        = "namecoin/set_name"
-	setNameCost     int64 = 50
-	newTokenCost    int64 = 100
+	pathNewTokenMsg             = "namecoin/ticker"
+	pathSetNameMsg              = "namecoin/set_name"
+	pathUpdateMetadataMsg       = "namecoin/update_metadata"
+	pathSellNameMsg             = "namecoin/sell_name"
+	pathBuyNameMsg              = "namecoin/buy_name"
+	pathCancelSaleMsg           = "namecoin/cancel_sale"
+	setNameCost           int64 = 50
+	updateMetadataCost    int64 = 50
+	newTokenCost          int64 = 100
+	sellNameCost          int64 = 50
+	buyNameCost           int64 = 50
+	cancelSaleCost        int64 = 0
 
 	minSigFigs = 0
 	maxSigFigs = 9
@@ -80,3 +88,66 @@ func BuildSetNameMsg(addr weave.Address, name string) *SetWalletNameMsg {
 		Name:    name,
 	}
 }
+
+// Path returns the routing path for this message
+func (UpdateWalletMetadataMsg) Path() string {
+	return pathUpdateMetadataMsg
+}
+
+// Validate makes sure that this is sensible
+func (m *UpdateWalletMetadataMsg) Validate() error {
+	if len(m.Address) != weave.AddressLength {
+		return errors.ErrUnrecognizedAddress(m.Address)
+	}
+	if m.Metadata != nil {
+		return m.Metadata.Validate()
+	}
+	return nil
+}
+
+// Path returns the routing path for this message
+func (SellNameMsg) Path() string {
+	return pathSellNameMsg
+}
+
+// Validate makes sure that this is sensible
+func (m *SellNameMsg) Validate() error {
+	if !IsWalletName(m.Name) {
+		return ErrInvalidWalletName(m.Name)
+	}
+	if m.Buyer != nil {
+		if err := weave.Address(m.Buyer).Validate(); err != nil {
+			return err
+		}
+	}
+	if m.Timeout <= 0 {
+		return ErrInvalidTimeout(m.Timeout)
+	}
+	return validatePrice(m.Price)
+}
+
+// Path returns the routing path for this message
+func (BuyNameMsg) Path() string {
+	return pathBuyNameMsg
+}
+
+// Validate makes sure that this is sensible
+func (m *BuyNameMsg) Validate() error {
+	if !IsWalletName(m.Name) {
+		return ErrInvalidWalletName(m.Name)
+	}
+	return weave.Address(m.Buyer).Validate()
+}
+
+// Path returns the routing path for this message
+func (CancelNameSaleMsg) Path() string {
+	return pathCancelSaleMsg
+}
+
+// Validate makes sure that this is sensible
+func (m *CancelNameSaleMsg) Validate() error {
+	if !IsWalletName(m.Name) {
+		return ErrInvalidWalletName(m.Name)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package namecoin
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+)
+
+const (
+	// BucketNameSale is where we store the names for sale
+	BucketNameSale = "sale"
+)
+
+//--- NameSale
+
+var _ orm.CloneableData = (*NameSale)(nil)
+
+// Validate ensures the sale is valid
+func (s *NameSale) Validate() error {
+	if err := weave.Address(s.Seller).Validate(); err != nil {
+		return err
+	}
+	if s.Buyer != nil {
+		if err := weave.Address(s.Buyer).Validate(); err != nil {
+			return err
+		}
+	}
+	if s.Timeout <= 0 {
+		return ErrInvalidTimeout(s.Timeout)
+	}
+	return validatePrice(s.Price)
+}
+
+// Copy makes a new sale with the same values
+func (s *NameSale) Copy() orm.CloneableData {
+	return &NameSale{
+		Seller:  s.Seller,
+		Price:   s.Price,
+		Buyer:   s.Buyer,
+		Timeout: s.Timeout,
+	}
+}
+
+// AsNameSale safely extracts a NameSale value from the object
+func AsNameSale(obj orm.Object) *NameSale {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*NameSale)
+}
+
+// NewNameSale creates a sale object, using the name as key
+func NewNameSale(name string, seller weave.Address, price x.Coin,
+	buyer weave.Address, timeout int64) orm.Object {
+
+	value := &NameSale{
+		Seller:  seller,
+		Price:   &price,
+		Buyer:   buyer,
+		Timeout: timeout,
+	}
+	return orm.NewSimpleObj([]byte(name), value)
+}
+
+//--- SaleBucket
+
+// SaleBucket is a type-safe wrapper around orm.Bucket
+type SaleBucket struct {
+	orm.Bucket
+}
+
+// NewSaleBucket initializes a SaleBucket with default name
+func NewSaleBucket() SaleBucket {
+	return SaleBucket{
+		Bucket: orm.NewBucket(BucketNameSale,
+			orm.NewSimpleObj(nil, new(NameSale))),
+	}
+}
+
+// Get returns the sale of the name, or nil
+func (b SaleBucket) Get(db weave.ReadOnlyKVStore, name string) (orm.Object, error) {
+	return b.Bucket.Get(db, []byte(name))
+}
+
+// Save enforces the proper type
+func (b SaleBucket) Save(db weave.KVStore, obj orm.Object) error {
+	if _, ok := obj.Value().(*NameSale); !ok {
+		return ErrInvalidObject(obj.Value())
+	}
+	return b.Bucket.Save(db, obj)
+}
+
+// validatePrice makes sure the price is a positive coin
+func validatePrice(price *x.Coin) error {
+	if price == nil || !price.IsPositive() {
+		return ErrInvalidAmount("non-positive price")
+	}
+	return price.Validate()
+}
@@ -5,6 +5,9 @@ import (
 	"github.com/confio/weave/orm"
 	"github.com/confio/weave/x"
 	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+	"github.com/iov-one/bcp-demo/query"
 )
 
 const (
@@ -14,6 +17,11 @@ const (
 	IndexName = "name"
 )
 
+func init() {
+	keyspace.Buckets("namecoin",
+		BucketNameWallet, BucketNameToken, BucketNameHold, BucketNameSale)
+}
+
 //--- Wallet
 
 var _ orm.CloneableData = (*Wallet)(nil)
@@ -32,14 +40,20 @@ func (w *Wallet) Validate() error {
 	if name != "" && !IsWalletName(name) {
 		return ErrInvalidWalletName(name)
 	}
+	if w.Metadata != nil {
+		if err := w.Metadata.Validate(); err != nil {
+			return err
+		}
+	}
 	return cash.XCoins(w).Validate()
 }
 
 // Copy makes a new set with the same coins
 func (w *Wallet) Copy() orm.CloneableData {
 	return &Wallet{
-		Name:  w.Name,
-		Coins: cash.XCoins(w).Clone(),
+		Name:     w.Name,
+		Coins:    cash.XCoins(w).Clone(),
+		Metadata: w.Metadata,
 	}
 }
 
@@ -135,12 +149,24 @@ func (b WalletBucket) GetByName(db weave.KVStore, name string) (orm.Object, erro
 	return objs[0], nil
 }
 
-// Save enforces the proper type
+// Save enforces the proper type, and counts new wallets
+// for the paged query
 func (b WalletBucket) Save(db weave.KVStore, obj orm.Object) error {
 	if _, ok := obj.Value().(*Wallet); !ok {
 		return ErrInvalidObject(obj.Value())
 	}
-	return b.Bucket.Save(db, obj)
+	return query.Save(db, b.Bucket, obj)
+}
+
+// Delete removes the wallet and its count
+func (b WalletBucket) Delete(db weave.KVStore, key []byte) error {
+	return query.Delete(db, b.Bucket, key)
+}
+
+// NameIndex returns the unique index by name, to page over it
+func (b WalletBucket) NameIndex() orm.Index {
+	// must match the name orm.Bucket.WithIndex uses
+	return orm.NewIndex(BucketNameWallet+"_"+IndexName, nameIndex, true, b.Bucket.DBKey)
 }
 
 // simple indexer for Wallet name
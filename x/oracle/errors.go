@@ -0,0 +1,34 @@
+package oracle
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// oracle takes 1050-1060
+const (
+	CodeInvalidPrice = 1050
+	CodeNoPrice      = 1051
+)
+
+var (
+	errInvalidPrice = fmt.Errorf("Invalid price")
+	errNoPrice      = fmt.Errorf("No price for this pair")
+)
+
+func ErrInvalidPrice(reason string) error {
+	return errors.WithLog(reason, errInvalidPrice, CodeInvalidPrice)
+}
+func IsInvalidPriceErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidPrice)
+}
+
+func ErrNoPrice(ticker, currency string) error {
+	return errors.WithLog(pairKey(ticker, currency), errNoPrice, CodeNoPrice)
+}
+func IsNoPriceErr(err error) bool {
+	return errors.HasErrorCode(err, CodeNoPrice)
+}
@@ -0,0 +1,95 @@
+package oracle
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+
+	"github.com/iov-one/bcp-demo/x/rbac"
+)
+
+const setPriceCost int64 = 10
+
+// RegisterRoutes will instantiate and register
+// all handlers in this package
+func RegisterRoutes(r weave.Registry, auth rbac.Authenticator) {
+	r.Handle(pathSetPriceMsg, SetPriceHandler{auth, NewPriceBucket(), NewConfigBucket()})
+}
+
+// RegisterQuery will register the prices as "/prices",
+// queried by "<ticker>/<currency>"
+func RegisterQuery(qr weave.QueryRouter) {
+	NewPriceBucket().Register("prices", qr)
+}
+
+// SetPriceHandler lets the feeders update prices
+type SetPriceHandler struct {
+	auth   rbac.Authenticator
+	bucket PriceBucket
+	config ConfigBucket
+}
+
+var _ weave.Handler = SetPriceHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h SetPriceHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += setPriceCost
+	return res, nil
+}
+
+// Deliver stores the new price
+func (h SetPriceHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	height, _ := weave.GetHeight(ctx)
+	err = h.bucket.Set(db, msg.Ticker, *msg.Rate, height)
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h SetPriceHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*SetPriceMsg, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*SetPriceMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	// only feeders may set prices, either listed in the
+	// genesis or with the oracle-feeder role
+	config, err := h.config.Load(db)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range config.Feeders {
+		if h.auth.HasAddress(ctx, f) {
+			return msg, nil
+		}
+	}
+	err = h.auth.RequireRole(ctx, db, rbac.RoleOracleFeeder)
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
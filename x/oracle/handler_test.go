@@ -0,0 +1,76 @@
+package oracle
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/x/rbac"
+)
+
+func TestSetPrice(t *testing.T) {
+	var helpers x.TestHelpers
+	_, feeder := helpers.MakeKey()
+	_, other := helpers.MakeKey()
+	_, role := helpers.MakeKey()
+
+	auth := helpers.CtxAuth("auth")
+	r := app.NewRouter()
+	RegisterRoutes(r, rbac.NewAuthenticator(auth))
+
+	usd := x.NewCoin(0, 250000000, "USD")
+	cases := []struct {
+		perm    weave.Permission
+		msg     *SetPriceMsg
+		isError bool
+	}{
+		0: {feeder, &SetPriceMsg{Ticker: "IOV", Rate: &usd}, false},
+		// not a feeder
+		1: {other, &SetPriceMsg{Ticker: "IOV", Rate: &usd}, true},
+		// invalid messages
+		2: {feeder, &SetPriceMsg{Ticker: "iov", Rate: &usd}, true},
+		3: {feeder, &SetPriceMsg{Ticker: "IOV"}, true},
+		4: {feeder, &SetPriceMsg{Ticker: "USD", Rate: &usd}, true},
+		5: {feeder, &SetPriceMsg{Ticker: "IOV", Rate: &x.Coin{Whole: -1, Ticker: "USD"}}, true},
+		// feeder by role
+		6: {role, &SetPriceMsg{Ticker: "IOV", Rate: &usd}, false},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			config := &Config{Feeders: [][]byte{feeder.Address()}}
+			require.NoError(t, NewConfigBucket().Store(db, config))
+			require.NoError(t, rbac.NewBucket().Assign(db, role.Address(), rbac.RoleOracleFeeder))
+
+			ctx := auth.SetPermissions(weave.WithHeight(context.Background(), 77), tc.perm)
+			tx := helpers.MockTx(tc.msg)
+			_, err := r.Check(ctx, db, tx)
+			if tc.isError {
+				require.Error(t, err)
+				_, err = r.Deliver(ctx, db, tx)
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			_, err = r.Deliver(ctx, db, tx)
+			require.NoError(t, err)
+
+			price, err := NewPriceBucket().Price(db, tc.msg.Ticker, tc.msg.Rate.Ticker)
+			require.NoError(t, err)
+			assert.Equal(t, tc.msg.Rate, price.Rate)
+			assert.Equal(t, int64(77), price.Height)
+
+			_, err = NewPriceBucket().Price(db, tc.msg.Ticker, "EUR")
+			assert.True(t, IsNoPriceErr(err))
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package oracle
+
+import (
+	"encoding/json"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+)
+
+const optOracle = "oracle"
+
+// GenesisPrice is one initial price
+type GenesisPrice struct {
+	Ticker string `json:"ticker"`
+	Rate   x.Coin `json:"rate"`
+}
+
+// Genesis is the format of the "oracle" genesis option
+type Genesis struct {
+	Feeders []weave.Address `json:"feeders"`
+	Prices  []GenesisPrice  `json:"prices"`
+}
+
+// Initializer fulfils the InitStater interface to load data from
+// the genesis file
+type Initializer struct{}
+
+var _ weave.Initializer = Initializer{}
+
+// FromGenesis will store the feeders and initial prices
+func (Initializer) FromGenesis(opts weave.Options, db weave.KVStore) error {
+	var gen Genesis
+	err := opts.ReadOptions(optOracle, &gen)
+	if err != nil {
+		return err
+	}
+
+	config := new(Config)
+	for _, f := range gen.Feeders {
+		config.Feeders = append(config.Feeders, f)
+	}
+	err = NewConfigBucket().Store(db, config)
+	if err != nil {
+		return err
+	}
+
+	bucket := NewPriceBucket()
+	for _, p := range gen.Prices {
+		err := bucket.Set(db, p.Ticker, p.Rate, 0)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BuildGenesis will create Options with the given feeders and prices
+func BuildGenesis(gen Genesis) (weave.Options, error) {
+	bz, err := json.MarshalIndent(gen, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return weave.Options{optOracle: bz}, nil
+}
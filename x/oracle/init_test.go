@@ -0,0 +1,45 @@
+package oracle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+)
+
+func TestInitState(t *testing.T) {
+	var helpers x.TestHelpers
+	_, feeder := helpers.MakeKey()
+	_, other := helpers.MakeKey()
+
+	opts, err := BuildGenesis(Genesis{
+		Feeders: []weave.Address{feeder.Address()},
+		Prices: []GenesisPrice{
+			{Ticker: "IOV", Rate: x.NewCoin(0, 250000000, "USD")},
+			{Ticker: "ETH", Rate: x.NewCoin(500, 0, "USD")},
+		},
+	})
+	require.NoError(t, err)
+
+	db := store.MemStore()
+	require.NoError(t, Initializer{}.FromGenesis(opts, db))
+
+	config, err := NewConfigBucket().Load(db)
+	require.NoError(t, err)
+	assert.True(t, config.IsFeeder(feeder.Address()))
+	assert.False(t, config.IsFeeder(other.Address()))
+
+	price, err := NewPriceBucket().Price(db, "ETH", "USD")
+	require.NoError(t, err)
+	assert.Equal(t, int64(500), price.Rate.Whole)
+
+	// empty genesis is fine, invalid prices are not
+	require.NoError(t, Initializer{}.FromGenesis(weave.Options{}, store.MemStore()))
+	bad, err := BuildGenesis(Genesis{Prices: []GenesisPrice{{Ticker: "IOV", Rate: x.NewCoin(-1, 0, "USD")}}})
+	require.NoError(t, err)
+	assert.Error(t, Initializer{}.FromGenesis(bad, store.MemStore()))
+}
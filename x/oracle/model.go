@@ -0,0 +1,151 @@
+/*
+Package oracle keeps token prices, set by trusted feeders,
+so other modules can value coins in a reference currency.
+
+Prices are stored per pair, "<ticker>/<currency>", and only
+the latest one is kept.
+*/
+package oracle
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+)
+
+const (
+	// BucketNamePrice is where we store the prices
+	BucketNamePrice = "price"
+	// BucketNameConfig is where we store the feeders
+	BucketNameConfig = "oracfg"
+
+	configKey = "config"
+)
+
+func init() {
+	keyspace.Buckets("oracle", BucketNamePrice, BucketNameConfig)
+}
+
+func pairKey(ticker, currency string) string {
+	return ticker + "/" + currency
+}
+
+//--- Price
+
+var _ orm.CloneableData = (*Price)(nil)
+
+// Validate ensures the price is valid
+func (p *Price) Validate() error {
+	if p.Rate == nil || !p.Rate.IsPositive() {
+		return ErrInvalidPrice("rate must be positive")
+	}
+	return p.Rate.Validate()
+}
+
+// Copy makes a new price with the same values
+func (p *Price) Copy() orm.CloneableData {
+	var rate *x.Coin
+	if p.Rate != nil {
+		rate = p.Rate.Clone()
+	}
+	return &Price{
+		Rate:   rate,
+		Height: p.Height,
+	}
+}
+
+// PriceBucket is a type-safe wrapper around orm.Bucket
+type PriceBucket struct {
+	orm.Bucket
+}
+
+// NewPriceBucket initializes a PriceBucket with default name
+func NewPriceBucket() PriceBucket {
+	return PriceBucket{
+		Bucket: orm.NewBucket(BucketNamePrice,
+			orm.NewSimpleObj(nil, new(Price))),
+	}
+}
+
+// Price returns the latest price of one unit of ticker,
+// in the currency. It fails if there is none.
+func (b PriceBucket) Price(db weave.ReadOnlyKVStore, ticker, currency string) (*Price, error) {
+	obj, err := b.Get(db, []byte(pairKey(ticker, currency)))
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil || obj.Value() == nil {
+		return nil, ErrNoPrice(ticker, currency)
+	}
+	return obj.Value().(*Price), nil
+}
+
+// Set stores the price of one unit of ticker
+func (b PriceBucket) Set(db weave.KVStore, ticker string, rate x.Coin, height int64) error {
+	if !x.IsCC(ticker) {
+		return x.ErrInvalidCurrency(ticker)
+	}
+	price := &Price{Rate: &rate, Height: height}
+	return b.Save(db, orm.NewSimpleObj([]byte(pairKey(ticker, rate.Ticker)), price))
+}
+
+//--- Config
+
+var _ orm.CloneableData = (*Config)(nil)
+
+// Validate ensures all feeders are addresses
+func (c *Config) Validate() error {
+	for _, f := range c.Feeders {
+		if err := weave.Address(f).Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Copy makes a new config with the same values
+func (c *Config) Copy() orm.CloneableData {
+	return &Config{Feeders: c.Feeders}
+}
+
+// IsFeeder returns true if addr may set prices
+func (c *Config) IsFeeder(addr weave.Address) bool {
+	for _, f := range c.Feeders {
+		if addr.Equals(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfigBucket stores the single Config of the module
+type ConfigBucket struct {
+	orm.Bucket
+}
+
+// NewConfigBucket initializes a ConfigBucket with default name
+func NewConfigBucket() ConfigBucket {
+	return ConfigBucket{
+		Bucket: orm.NewBucket(BucketNameConfig,
+			orm.NewSimpleObj(nil, new(Config))),
+	}
+}
+
+// Load returns the stored config, empty if none is set
+func (b ConfigBucket) Load(db weave.ReadOnlyKVStore) (*Config, error) {
+	obj, err := b.Get(db, []byte(configKey))
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil || obj.Value() == nil {
+		return new(Config), nil
+	}
+	return obj.Value().(*Config), nil
+}
+
+// Store saves the config, replacing the old one
+func (b ConfigBucket) Store(db weave.KVStore, config *Config) error {
+	return b.Save(db, orm.NewSimpleObj([]byte(configKey), config))
+}
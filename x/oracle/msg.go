@@ -0,0 +1,29 @@
+package oracle
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+)
+
+const pathSetPriceMsg = "oracle/set_price"
+
+var _ weave.Msg = (*SetPriceMsg)(nil)
+
+// Path fulfills weave.Msg interface to allow routing
+func (SetPriceMsg) Path() string {
+	return pathSetPriceMsg
+}
+
+// Validate makes sure that this is sensible
+func (m *SetPriceMsg) Validate() error {
+	if !x.IsCC(m.Ticker) {
+		return x.ErrInvalidCurrency(m.Ticker)
+	}
+	if m.Rate == nil || !m.Rate.IsPositive() {
+		return ErrInvalidPrice("rate must be positive")
+	}
+	if m.Rate.Ticker == m.Ticker {
+		return ErrInvalidPrice("cannot price a token in itself")
+	}
+	return m.Rate.Validate()
+}
@@ -0,0 +1,130 @@
+package outbox
+
+import (
+	"strings"
+
+	"github.com/confio/weave"
+
+	"github.com/iov-one/bcp-demo/budget"
+	"github.com/iov-one/bcp-demo/x/txindex"
+)
+
+// Retention is how many blocks the events are kept, about a
+// day of 5 second blocks. A relay that is down for longer
+// misses the older ones.
+const Retention = 20000
+
+const (
+	// maxPrunePerBlock bounds the work of the Ticker
+	maxPrunePerBlock = 100
+	// pruneTickCost is taken of the budget of the block for
+	// every event the Ticker deletes
+	pruneTickCost int64 = 10
+)
+
+// Decorator appends the tags of every delivered tx with one
+// of its prefixes to the outbox. Failed txs add none.
+type Decorator struct {
+	bucket   Bucket
+	prefixes []string
+}
+
+var _ weave.Decorator = Decorator{}
+
+// NewDecorator keeps the tags with keys starting with one
+// of the prefixes, eg. "escrow."
+func NewDecorator(prefixes ...string) Decorator {
+	return Decorator{bucket: NewBucket(), prefixes: prefixes}
+}
+
+// Check just calls down the stack, only delivered txs
+// have events
+func (d Decorator) Check(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	return next.Check(ctx, db, tx)
+}
+
+// Deliver calls down the stack and appends the events
+func (d Decorator) Deliver(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	res, err := next.Deliver(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	height, _ := weave.GetHeight(ctx)
+	header, _ := weave.GetHeader(ctx)
+	var hash []byte
+	for _, tag := range res.Tags {
+		if !d.matches(string(tag.Key)) {
+			continue
+		}
+		if hash == nil {
+			hash = txHash(tx)
+		}
+		e := &Event{Height: height, Time: header.Time, Key: string(tag.Key),
+			Value: tag.Value, TxHash: hash}
+		if err := d.bucket.Append(db, e); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// matches is true if key has one of the prefixes
+func (d Decorator) matches(key string) bool {
+	for _, prefix := range d.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// txHash is the hash of the tx, nil if it can't be encoded
+func txHash(tx weave.Tx) []byte {
+	mtx, ok := tx.(txindex.MarshaledTx)
+	if !ok {
+		return nil
+	}
+	bz, err := mtx.Marshal()
+	if err != nil {
+		return nil
+	}
+	return txindex.Hash(bz)
+}
+
+// Ticker prunes the events older than Retention blocks
+type Ticker struct {
+	bucket Bucket
+}
+
+var _ weave.Ticker = Ticker{}
+
+// NewTicker creates a Ticker of the default bucket
+func NewTicker() Ticker {
+	return Ticker{bucket: NewBucket()}
+}
+
+// Tick deletes up to maxPrunePerBlock of the oldest events,
+// as long as they are older than Retention blocks and the
+// budget of the block lasts
+func (t Ticker) Tick(ctx weave.Context, db weave.KVStore) (weave.TickResult, error) {
+	var res weave.TickResult
+	height, _ := weave.GetHeight(ctx)
+	oldest, err := t.bucket.After(db, 0, maxPrunePerBlock)
+	if err != nil {
+		return res, err
+	}
+	for _, obj := range oldest {
+		if AsEvent(obj).Height > height-Retention || !budget.Take(ctx, pruneTickCost) {
+			break
+		}
+		err = t.bucket.Delete(db, obj.Key())
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,25 @@
+package outbox
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// outbox takes 1240-1250
+const (
+	CodeInvalidEvent = 1240
+)
+
+var (
+	errInvalidEvent = fmt.Errorf("Invalid event")
+)
+
+func ErrInvalidEvent(reason string) error {
+	return errors.WithLog(reason, errInvalidEvent, CodeInvalidEvent)
+}
+func IsInvalidEventErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidEvent)
+}
@@ -0,0 +1,139 @@
+/*
+Package outbox keeps the events of the delivered txs in the state,
+so the webhooks and streams that notify clients of escrow settlements
+never miss one.
+
+The Decorator appends the tags of every successful tx with one of
+its prefixes, eg. "escrow.", to the outbox. The events are committed
+with the block, so they survive a restart of the node, and are pruned
+by the Ticker after Retention blocks.
+
+A Relay on the node reads the new events after every commit and
+pushes them, eg. to a Webhook, in order. It writes the sequence of
+the last delivered event to a cursor file, and starts after it when
+the node restarts. Every event is delivered at least once: a crash
+between a push and the write of the cursor pushes it again, so
+receivers drop the sequences they have seen.
+*/
+package outbox
+
+import (
+	"encoding/binary"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+)
+
+const (
+	// BucketName is where we store the events
+	BucketName = "outbox"
+	// SequenceName numbers the events
+	SequenceName = "id"
+)
+
+func init() {
+	keyspace.Buckets("outbox", BucketName)
+}
+
+var _ orm.CloneableData = (*Event)(nil)
+
+// Validate ensures the event has a height and a key
+func (e *Event) Validate() error {
+	if e.Height <= 0 {
+		return ErrInvalidEvent("height")
+	}
+	if e.Key == "" {
+		return ErrInvalidEvent("missing key")
+	}
+	return nil
+}
+
+// Copy makes a new event with the same values
+func (e *Event) Copy() orm.CloneableData {
+	return &Event{
+		Height: e.Height,
+		Time:   e.Time,
+		Key:    e.Key,
+		Value:  e.Value,
+		TxHash: e.TxHash,
+	}
+}
+
+// AsEvent safely extracts an Event value from the object
+func AsEvent(obj orm.Object) *Event {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Event)
+}
+
+// Key returns the key of the event with the sequence, big
+// endian so the events are sorted
+func Key(seq int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(seq))
+	return bz
+}
+
+// Seq returns the sequence of the event key
+func Seq(key []byte) int64 {
+	if len(key) != 8 {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(key))
+}
+
+//--- Bucket
+
+// Bucket is a type-safe wrapper around orm.Bucket
+type Bucket struct {
+	orm.Bucket
+	seq orm.Sequence
+}
+
+// NewBucket initializes a Bucket with default name
+func NewBucket() Bucket {
+	bucket := orm.NewBucket(BucketName, orm.NewSimpleObj(nil, new(Event)))
+	return Bucket{
+		Bucket: bucket,
+		seq:    bucket.Sequence(SequenceName),
+	}
+}
+
+// Append stores the event under the next sequence
+func (b Bucket) Append(db weave.KVStore, e *Event) error {
+	return b.Save(db, orm.NewSimpleObj(b.seq.NextVal(db), e))
+}
+
+// After returns up to limit events after the sequence, in order,
+// with their keys
+func (b Bucket) After(db weave.ReadOnlyKVStore, seq int64, limit int) ([]orm.Object, error) {
+	prefix := b.DBKey(nil)
+	itr := db.Iterator(b.DBKey(Key(seq+1)), prefixEnd(prefix))
+	defer itr.Close()
+
+	var res []orm.Object
+	for ; itr.Valid() && len(res) < limit; itr.Next() {
+		var e Event
+		if err := e.Unmarshal(itr.Value()); err != nil {
+			return nil, err
+		}
+		res = append(res, orm.NewSimpleObj(itr.Key()[len(prefix):], &e))
+	}
+	return res, nil
+}
+
+// prefixEnd returns the first key after all keys with prefix,
+// which ends in the ":" of the bucket
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	end[len(end)-1]++
+	return end
+}
+
+// RegisterQuery will register the events as "/outbox"
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("outbox", qr)
+}
@@ -0,0 +1,37 @@
+package outbox
+
+import (
+	"github.com/confio/weave"
+
+	"github.com/iov-one/bcp-demo/module"
+)
+
+// Module keeps the events for the relay, the app adds the
+// Decorator to its chain
+type Module struct{}
+
+var (
+	_ module.Module  = Module{}
+	_ module.Querier = Module{}
+	_ module.Ticking = Module{}
+)
+
+// Name fulfils module.Module
+func (Module) Name() string {
+	return "outbox"
+}
+
+// Version fulfils module.Module
+func (Module) Version() uint32 {
+	return 2
+}
+
+// RegisterQuery fulfils module.Querier
+func (Module) RegisterQuery(qr weave.QueryRouter) {
+	RegisterQuery(qr)
+}
+
+// Ticker fulfils module.Ticking
+func (Module) Ticker() weave.Ticker {
+	return NewTicker()
+}
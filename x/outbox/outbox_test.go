@@ -0,0 +1,185 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+	"github.com/tendermint/tmlibs/common"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/txindex"
+)
+
+func TestDecorator(t *testing.T) {
+	var helpers x.TestHelpers
+
+	tx := marshaledTx{helpers.MockTx(&cash.SendMsg{}), []byte("tx")}
+	hash := txindex.Hash([]byte("tx"))
+	tags := []common.KVPair{
+		{Key: []byte("escrow.release"), Value: []byte("0001")},
+		{Key: []byte("signer"), Value: []byte("alice")},
+		{Key: []byte("escrow.return"), Value: []byte("0002")},
+	}
+
+	cases := []struct {
+		tx       weave.Tx
+		err      error
+		expected []*Event
+	}{
+		0: {tx, nil, []*Event{
+			{Height: 7, Time: 1500000000, Key: "escrow.release", Value: []byte("0001"), TxHash: hash},
+			{Height: 7, Time: 1500000000, Key: "escrow.return", Value: []byte("0002"), TxHash: hash},
+		}},
+		// failed txs have no events
+		1: {tx, errors.ErrUnauthorized(), nil},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			ctx := weave.WithHeight(context.Background(), 7)
+			ctx = weave.WithHeader(ctx, abci.Header{Height: 7, Time: 1500000000})
+			res := weave.DeliverResult{Tags: tags}
+			stack := helpers.Wrap(NewDecorator("escrow."), resultHandler{res, tc.err})
+
+			_, err := stack.Check(ctx, db, tc.tx)
+			require.NoError(t, err)
+			_, err = stack.Deliver(ctx, db, tc.tx)
+			assert.Equal(t, tc.err, err)
+
+			objs, err := NewBucket().After(db, 0, 10)
+			require.NoError(t, err)
+			var events []*Event
+			for j, obj := range objs {
+				assert.Equal(t, int64(j+1), Seq(obj.Key()))
+				events = append(events, AsEvent(obj))
+			}
+			assert.Equal(t, tc.expected, events)
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	db := store.MemStore()
+	bucket := NewBucket()
+	for h := int64(1); h <= 5; h++ {
+		require.NoError(t, bucket.Append(db, &Event{Height: h, Key: "escrow.create"}))
+	}
+	// the events of other buckets are not read
+	db.Set([]byte("outboy:x"), []byte("x"))
+
+	cases := []struct {
+		seq      int64
+		limit    int
+		expected []int64
+	}{
+		0: {0, 10, []int64{1, 2, 3, 4, 5}},
+		1: {2, 10, []int64{3, 4, 5}},
+		2: {0, 2, []int64{1, 2}},
+		3: {5, 10, nil},
+	}
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			objs, err := bucket.After(db, tc.seq, tc.limit)
+			require.NoError(t, err)
+			var seqs []int64
+			for _, obj := range objs {
+				seqs = append(seqs, Seq(obj.Key()))
+				assert.Equal(t, Seq(obj.Key()), AsEvent(obj).Height)
+			}
+			assert.Equal(t, tc.expected, seqs)
+		})
+	}
+
+	err := bucket.Append(db, &Event{Key: "escrow.create"})
+	assert.True(t, IsInvalidEventErr(err))
+}
+
+func TestTicker(t *testing.T) {
+	db := store.MemStore()
+	bucket := NewBucket()
+	for i := 0; i < maxPrunePerBlock+10; i++ {
+		require.NoError(t, bucket.Append(db, &Event{Height: 10, Key: "escrow.create"}))
+	}
+	require.NoError(t, bucket.Append(db, &Event{Height: 20, Key: "escrow.release"}))
+
+	tick := func(height int64) []int64 {
+		ctx := weave.WithHeight(context.Background(), height)
+		_, err := NewTicker().Tick(ctx, db)
+		require.NoError(t, err)
+		objs, err := bucket.After(db, 0, 1000)
+		require.NoError(t, err)
+		var seqs []int64
+		for _, obj := range objs {
+			seqs = append(seqs, Seq(obj.Key()))
+		}
+		return seqs
+	}
+
+	// nothing is old enough
+	assert.Len(t, tick(Retention+9), maxPrunePerBlock+11)
+	// at most maxPrunePerBlock go at once
+	assert.Equal(t, []int64{101, 102, 103, 104, 105, 106, 107, 108, 109, 110, 111},
+		tick(Retention+10))
+	assert.Equal(t, []int64{111}, tick(Retention+11))
+	assert.Nil(t, tick(Retention+20))
+}
+
+func TestQuery(t *testing.T) {
+	db := store.MemStore()
+	e := &Event{Height: 3, Key: "escrow.create", Value: []byte("0001")}
+	require.NoError(t, NewBucket().Append(db, e))
+
+	qr := weave.NewQueryRouter()
+	RegisterQuery(qr)
+	h := qr.Handler("/outbox")
+	require.NotNil(t, h)
+
+	res, err := h.Query(db, weave.KeyQueryMod, Key(1))
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res))
+	var loaded Event
+	require.NoError(t, loaded.Unmarshal(res[0].Value))
+	assert.Equal(t, *e, loaded)
+}
+
+//---------------- helpers --------
+
+// marshaledTx returns fixed bytes as its encoding
+type marshaledTx struct {
+	weave.Tx
+	bz []byte
+}
+
+var _ txindex.MarshaledTx = marshaledTx{}
+
+func (m marshaledTx) Marshal() ([]byte, error) {
+	return m.bz, nil
+}
+
+// resultHandler always returns the same result
+type resultHandler struct {
+	res weave.DeliverResult
+	err error
+}
+
+var _ weave.Handler = resultHandler{}
+
+func (h resultHandler) Check(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	return weave.CheckResult{}, nil
+}
+
+func (h resultHandler) Deliver(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	return h.res, h.err
+}
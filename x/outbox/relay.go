@@ -0,0 +1,238 @@
+package outbox
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/confio/weave"
+	"github.com/tendermint/tmlibs/log"
+)
+
+const (
+	// maxPending is how many events the relay reads ahead of
+	// the pushes, the others wait in the state
+	maxPending = 1000
+	// pushBatch is how many events are pushed at once
+	pushBatch = 50
+	// the backoff between failed pushes
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+)
+
+// Delivery is an event as it is pushed. Seq numbers the events
+// without gaps, unless some were pruned before the push.
+type Delivery struct {
+	Seq    int64  `json:"seq"`
+	Height int64  `json:"height"`
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	// TxHash is hex encoded
+	TxHash string `json:"tx_hash"`
+	// Time of the block in unix seconds
+	Time int64 `json:"time"`
+}
+
+// Pusher delivers events to their receivers, in order. An
+// error pushes them again after a while.
+type Pusher interface {
+	Push(events []Delivery) error
+}
+
+// cursor is the content of the cursor file
+type cursor struct {
+	Delivered int64 `json:"delivered"`
+}
+
+// Relay pushes the events of the committed blocks in the
+// background. Call Commit after every commit of the app, as
+// the views are refreshed, so the state is only read on the
+// consensus connection.
+type Relay struct {
+	bucket Bucket
+	push   Pusher
+	path   string
+	logger log.Logger
+	wait   func(d time.Duration, stop <-chan struct{}) bool
+
+	mtx     sync.Mutex
+	read    int64
+	pending []Delivery
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewRelay pushes the events after the one in the cursor file,
+// all of them if there is no such file yet
+func NewRelay(push Pusher, cursorFile string, logger log.Logger) (*Relay, error) {
+	return newRelay(push, cursorFile, logger, sleep)
+}
+
+// newRelay waits between failed pushes with wait
+func newRelay(push Pusher, cursorFile string, logger log.Logger,
+	wait func(time.Duration, <-chan struct{}) bool) (*Relay, error) {
+
+	delivered, err := readCursor(cursorFile)
+	if err != nil {
+		return nil, err
+	}
+	r := &Relay{
+		bucket: NewBucket(),
+		push:   push,
+		path:   cursorFile,
+		logger: logger,
+		wait:   wait,
+		read:   delivered,
+		wake:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go r.run()
+	return r, nil
+}
+
+// readCursor returns the last delivered sequence, 0 if
+// the file does not exist
+func readCursor(path string) (int64, error) {
+	bz, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	var c cursor
+	err = json.Unmarshal(bz, &c)
+	return c.Delivered, err
+}
+
+// writeCursor replaces the file at once, so a crash leaves
+// the old or the new cursor
+func writeCursor(path string, delivered int64) error {
+	bz, err := json.Marshal(cursor{Delivered: delivered})
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, bz, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Commit reads the events that are new since the last call.
+// While the pushes lag behind, the events wait in the state.
+func (r *Relay) Commit(db weave.ReadOnlyKVStore) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	room := maxPending - len(r.pending)
+	if room <= 0 {
+		return
+	}
+	objs, err := r.bucket.After(db, r.read, room)
+	if err != nil {
+		r.logger.Error("Cannot read outbox", "err", err)
+		return
+	}
+	for _, obj := range objs {
+		seq := Seq(obj.Key())
+		if seq != r.read+1 {
+			r.logger.Error("Events pruned before they were pushed",
+				"from", r.read+1, "to", seq-1)
+		}
+		e := AsEvent(obj)
+		r.pending = append(r.pending, Delivery{
+			Seq:    seq,
+			Height: e.Height,
+			Key:    e.Key,
+			Value:  string(e.Value),
+			TxHash: hex.EncodeToString(e.TxHash),
+			Time:   e.Time,
+		})
+		r.read = seq
+	}
+	if len(objs) > 0 {
+		select {
+		case r.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Close stops pushing, the events not pushed yet are pushed
+// after the next start
+func (r *Relay) Close() error {
+	r.once.Do(func() { close(r.stop) })
+	<-r.done
+	return nil
+}
+
+// run pushes the pending events until the relay is closed
+func (r *Relay) run() {
+	defer close(r.done)
+	backoff := minBackoff
+	for {
+		batch := r.next()
+		if len(batch) == 0 {
+			select {
+			case <-r.wake:
+				continue
+			case <-r.stop:
+				return
+			}
+		}
+		if err := r.push.Push(batch); err != nil {
+			r.logger.Error("Cannot push events", "from", batch[0].Seq,
+				"retry_in", backoff, "err", err)
+			if !r.wait(backoff, r.stop) {
+				return
+			}
+			if backoff *= 2; backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+		backoff = minBackoff
+		last := batch[len(batch)-1].Seq
+		if err := writeCursor(r.path, last); err != nil {
+			r.logger.Error("Cannot write outbox cursor", "seq", last, "err", err)
+		}
+		r.delivered(len(batch))
+	}
+}
+
+// next returns the events to push next
+func (r *Relay) next() []Delivery {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	n := len(r.pending)
+	if n > pushBatch {
+		n = pushBatch
+	}
+	return append([]Delivery{}, r.pending[:n]...)
+}
+
+// delivered drops the first n pending events
+func (r *Relay) delivered(n int) {
+	r.mtx.Lock()
+	r.pending = r.pending[n:]
+	r.mtx.Unlock()
+}
+
+// sleep waits for d, it is false if stop closed before
+func sleep(d time.Duration, stop <-chan struct{}) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-stop:
+		return false
+	}
+}
@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tmlibs/log"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+)
+
+func TestRelay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outbox")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cursor.json")
+
+	db := store.MemStore()
+	appendEvents(t, db, 3)
+
+	// the first push fails, it is retried after the backoff
+	push := &fakePusher{fail: 1, pushed: make(chan []Delivery, 10)}
+	var waits []time.Duration
+	wait := func(d time.Duration, stop <-chan struct{}) bool {
+		waits = append(waits, d)
+		return true
+	}
+	relay, err := newRelay(push, path, log.NewNopLogger(), wait)
+	require.NoError(t, err)
+	relay.Commit(db)
+	assert.Equal(t, []int64{1, 2, 3}, seqs(receive(t, push.pushed)))
+	require.NoError(t, relay.Close())
+	assert.Equal(t, []time.Duration{minBackoff}, waits)
+	assert.Equal(t, int64(3), readCursorFile(t, path))
+
+	// new events are pushed in batches
+	appendEvents(t, db, pushBatch+1)
+	relay, err = newRelay(push, path, log.NewNopLogger(), wait)
+	require.NoError(t, err)
+	relay.Commit(db)
+	first := receive(t, push.pushed)
+	require.Len(t, first, pushBatch)
+	assert.Equal(t, int64(4), first[0].Seq)
+	assert.Equal(t, []int64{pushBatch + 4}, seqs(receive(t, push.pushed)))
+	require.NoError(t, relay.Close())
+	assert.Equal(t, int64(pushBatch+4), readCursorFile(t, path))
+
+	// a restart starts after the cursor
+	relay, err = newRelay(push, path, log.NewNopLogger(), wait)
+	require.NoError(t, err)
+	relay.Commit(db)
+	appendEvents(t, db, 1)
+	relay.Commit(db)
+	assert.Equal(t, []int64{pushBatch + 5}, seqs(receive(t, push.pushed)))
+	require.NoError(t, relay.Close())
+}
+
+func TestWebhook(t *testing.T) {
+	var got webhookBody
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	events := []Delivery{{Seq: 1, Height: 2, Key: "escrow.create", Value: "0001", TxHash: "aa"}}
+	hook := NewWebhook(srv.URL)
+	require.NoError(t, hook.Push(events))
+	assert.Equal(t, events, got.Events)
+
+	status = http.StatusServiceUnavailable
+	assert.Error(t, hook.Push(events))
+}
+
+//---------------- helpers --------
+
+// fakePusher sends the pushed events on a channel, failing
+// the first fail pushes
+type fakePusher struct {
+	mtx    sync.Mutex
+	fail   int
+	pushed chan []Delivery
+}
+
+func (p *fakePusher) Push(events []Delivery) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.fail > 0 {
+		p.fail--
+		return fmt.Errorf("down")
+	}
+	p.pushed <- events
+	return nil
+}
+
+// appendEvents adds n events to the outbox
+func appendEvents(t *testing.T, db weave.KVStore, n int) {
+	for i := 0; i < n; i++ {
+		require.NoError(t, NewBucket().Append(db, &Event{Height: 1, Key: "escrow.create"}))
+	}
+}
+
+// receive waits for the next push
+func receive(t *testing.T, pushed <-chan []Delivery) []Delivery {
+	select {
+	case events := <-pushed:
+		return events
+	case <-time.After(5 * time.Second):
+		t.Fatal("nothing pushed")
+		return nil
+	}
+}
+
+func seqs(events []Delivery) []int64 {
+	var res []int64
+	for _, e := range events {
+		res = append(res, e.Seq)
+	}
+	return res
+}
+
+func readCursorFile(t *testing.T, path string) int64 {
+	delivered, err := readCursor(path)
+	require.NoError(t, err)
+	return delivered
+}
@@ -0,0 +1,45 @@
+package outbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Webhook posts the events as json to a url, as
+// {"events": [...]}. Any answer but 2xx is a failure.
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+var _ Pusher = Webhook{}
+
+// NewWebhook posts to url
+func NewWebhook(url string) Webhook {
+	return Webhook{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// webhookBody is what a Webhook posts
+type webhookBody struct {
+	Events []Delivery `json:"events"`
+}
+
+// Push posts the events
+func (w Webhook) Push(events []Delivery) error {
+	body, err := json.Marshal(webhookBody{Events: events})
+	if err != nil {
+		return err
+	}
+	res, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook answered %s", res.Status)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package ownership
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// ownership takes 1220-1230
+const (
+	CodeInvalidOffer = 1220
+)
+
+var (
+	errNoOffer    = fmt.Errorf("No offer for this role")
+	errStaleOffer = fmt.Errorf("Offer made by a former holder")
+	errExpired    = fmt.Errorf("Offer expired")
+)
+
+func ErrNoOffer(key []byte) error {
+	return errors.WithLog(string(key), errNoOffer, CodeInvalidOffer)
+}
+func ErrStaleOffer(key []byte) error {
+	return errors.WithLog(string(key), errStaleOffer, CodeInvalidOffer)
+}
+func ErrOfferExpired(expires int64) error {
+	msg := fmt.Sprintf("%d", expires)
+	return errors.WithLog(msg, errExpired, CodeInvalidOffer)
+}
+func IsInvalidOfferErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidOffer)
+}
@@ -0,0 +1,173 @@
+/*
+Package ownership moves a role on an object, eg. the arbiter of an
+escrow or the owner of a name, from one permission to another.
+
+Only the current holder may give a role away. A handler can change
+it at once after Authorize, or in two steps: the holder offers it
+with Offer, and the new holder takes it with Accept. That way a
+mistyped address can't lose the role, as nobody would accept it.
+The next offer replaces the last one, an offer to nobody cancels
+it. An offer goes stale once the role changes hands in any other
+way, and expires after the height the handler gave it.
+
+Offers are stored under Key, in a bucket that needs no handlers
+of its own, and tagged as "ownership.<event>" with the key as
+value, so clients can follow them.
+*/
+package ownership
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+	"github.com/tendermint/tmlibs/common"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+)
+
+const (
+	// BucketName is where we store the pending offers
+	BucketName = "offers"
+
+	tagPrefix = "ownership."
+
+	// EventOffer is tagged when a role is offered
+	EventOffer = "offer"
+	// EventCancel is tagged when an offer is withdrawn
+	EventCancel = "cancel"
+	// EventAccept is tagged when an offer is accepted
+	EventAccept = "accept"
+)
+
+func init() {
+	keyspace.Buckets("ownership", BucketName)
+}
+
+var _ orm.CloneableData = (*Offer)(nil)
+
+// Validate ensures both sides of the offer are permissions
+func (o *Offer) Validate() error {
+	if err := weave.Permission(o.From).Validate(); err != nil {
+		return err
+	}
+	if o.Expires < 0 {
+		return ErrOfferExpired(o.Expires)
+	}
+	return weave.Permission(o.To).Validate()
+}
+
+// Copy makes a new offer with the same values
+func (o *Offer) Copy() orm.CloneableData {
+	return &Offer{
+		From:    o.From,
+		To:      o.To,
+		Height:  o.Height,
+		Expires: o.Expires,
+	}
+}
+
+// AsOffer safely extracts an Offer value from the object
+func AsOffer(obj orm.Object) *Offer {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Offer)
+}
+
+// Key is where the offer of a role on an object is stored, eg.
+// "escrow/0000000000000001/arbiter". A prefix query on the kind
+// and id lists all offers for the object.
+func Key(kind string, id []byte, role string) []byte {
+	return []byte(kind + "/" + strings.ToUpper(hex.EncodeToString(id)) + "/" + role)
+}
+
+// Tag describes an event of the offer under key
+func Tag(event string, key []byte) common.KVPair {
+	return common.KVPair{Key: []byte(tagPrefix + event), Value: key}
+}
+
+// Authorize returns an error unless the holder of a role
+// signed the tx. An empty role can't be given away.
+func Authorize(ctx weave.Context, auth x.Authenticator, holder weave.Permission) error {
+	if len(holder) == 0 || !auth.HasAddress(ctx, holder.Address()) {
+		return errors.ErrUnauthorized()
+	}
+	return nil
+}
+
+// Bucket holds the pending offers by their Key
+type Bucket struct {
+	orm.Bucket
+}
+
+// NewBucket initializes a Bucket with default name
+func NewBucket() Bucket {
+	return Bucket{
+		Bucket: orm.NewBucket(BucketName,
+			orm.NewSimpleObj(nil, new(Offer))),
+	}
+}
+
+// RegisterQuery will register this bucket as "/offers"
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("offers", qr)
+}
+
+// Offer offers the role under key to the permission to, on
+// behalf of its holder, or cancels the offer if to is empty.
+// It may be accepted until the height expires, 0 means forever.
+// The caller must Authorize the holder first.
+func (b Bucket) Offer(ctx weave.Context, db weave.KVStore, key []byte,
+	holder, to weave.Permission, expires int64) (common.KVPair, error) {
+
+	if len(to) == 0 {
+		return Tag(EventCancel, key), b.Delete(db, key)
+	}
+	height, _ := weave.GetHeight(ctx)
+	offer := &Offer{From: holder, To: to, Height: height, Expires: expires}
+	return Tag(EventOffer, key), b.Save(db, orm.NewSimpleObj(key, offer))
+}
+
+// Offered returns who accepts the role under key, if one of the
+// signers was offered it by its holder. It changes nothing, so
+// Check can call it.
+func (b Bucket) Offered(ctx weave.Context, db weave.ReadOnlyKVStore,
+	auth x.Authenticator, key []byte, holder weave.Permission) (weave.Permission, error) {
+
+	obj, err := b.Get(db, key)
+	if err != nil {
+		return nil, err
+	}
+	offer := AsOffer(obj)
+	height, _ := weave.GetHeight(ctx)
+	switch {
+	case offer == nil:
+		return nil, ErrNoOffer(key)
+	case !bytes.Equal(offer.From, holder):
+		return nil, ErrStaleOffer(key)
+	case offer.Expires > 0 && height > offer.Expires:
+		return nil, ErrOfferExpired(offer.Expires)
+	}
+	to := weave.Permission(offer.To)
+	if !auth.HasAddress(ctx, to.Address()) {
+		return nil, errors.ErrUnauthorized()
+	}
+	return to, nil
+}
+
+// Accept removes the offer under key, as in Offered, and returns
+// the new holder for the caller to store
+func (b Bucket) Accept(ctx weave.Context, db weave.KVStore, auth x.Authenticator,
+	key []byte, holder weave.Permission) (weave.Permission, common.KVPair, error) {
+
+	to, err := b.Offered(ctx, db, auth, key, holder)
+	if err != nil {
+		return nil, common.KVPair{}, err
+	}
+	return to, Tag(EventAccept, key), b.Delete(db, key)
+}
@@ -0,0 +1,76 @@
+package ownership
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+)
+
+func TestOfferAccept(t *testing.T) {
+	var helpers x.TestHelpers
+	_, alice := helpers.MakeKey()
+	_, bob := helpers.MakeKey()
+	_, carol := helpers.MakeKey()
+	auth := helpers.CtxAuth("ownership")
+	signed := func(perm weave.Permission) weave.Context {
+		ctx := weave.WithHeight(context.Background(), 5)
+		return auth.SetPermissions(ctx, perm)
+	}
+
+	db := store.MemStore()
+	b := NewBucket()
+	key := Key("thing", []byte{0, 1}, "owner")
+	assert.Equal(t, "thing/0001/owner", string(key))
+
+	assert.NoError(t, Authorize(signed(alice), auth, alice))
+	assert.True(t, errors.IsUnauthorizedErr(Authorize(signed(bob), auth, alice)))
+	assert.True(t, errors.IsUnauthorizedErr(Authorize(signed(bob), auth, nil)))
+
+	_, err := b.Offered(signed(bob), db, auth, key, alice)
+	assert.True(t, IsInvalidOfferErr(err), "%+v", err)
+
+	// the last offer counts
+	tag, err := b.Offer(signed(alice), db, key, alice, carol, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "ownership.offer", string(tag.Key))
+	_, err = b.Offer(signed(alice), db, key, alice, bob, 0)
+	require.NoError(t, err)
+	_, err = b.Offered(signed(carol), db, auth, key, alice)
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+
+	// it is stale once the role moved on
+	_, err = b.Offered(signed(bob), db, auth, key, carol)
+	assert.True(t, IsInvalidOfferErr(err), "%+v", err)
+
+	to, tag, err := b.Accept(signed(bob), db, auth, key, alice)
+	require.NoError(t, err)
+	assert.Equal(t, bob, to)
+	assert.Equal(t, Tag(EventAccept, key), tag)
+	_, _, err = b.Accept(signed(bob), db, auth, key, alice)
+	assert.True(t, IsInvalidOfferErr(err), "%+v", err)
+
+	// an offer to no one withdraws it
+	_, err = b.Offer(signed(bob), db, key, bob, alice, 0)
+	require.NoError(t, err)
+	tag, err = b.Offer(signed(bob), db, key, bob, nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "ownership.cancel", string(tag.Key))
+	_, err = b.Offered(signed(alice), db, auth, key, bob)
+	assert.True(t, IsInvalidOfferErr(err), "%+v", err)
+
+	// it can't be accepted after it expires
+	_, err = b.Offer(signed(bob), db, key, bob, alice, 10)
+	require.NoError(t, err)
+	_, err = b.Offered(signed(alice), db, auth, key, bob)
+	assert.NoError(t, err)
+	late := auth.SetPermissions(weave.WithHeight(context.Background(), 11), alice)
+	_, _, err = b.Accept(late, db, auth, key, bob)
+	assert.True(t, IsInvalidOfferErr(err), "%+v", err)
+}
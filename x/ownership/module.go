@@ -0,0 +1,31 @@
+package ownership
+
+import (
+	"github.com/confio/weave"
+
+	"github.com/iov-one/bcp-demo/module"
+)
+
+// Module serves the offers of roles, the modules holding the
+// roles handle them
+type Module struct{}
+
+var (
+	_ module.Module  = Module{}
+	_ module.Querier = Module{}
+)
+
+// Name fulfils module.Module
+func (Module) Name() string {
+	return "ownership"
+}
+
+// Version fulfils module.Module
+func (Module) Version() uint32 {
+	return 2
+}
+
+// RegisterQuery fulfils module.Querier
+func (Module) RegisterQuery(qr weave.QueryRouter) {
+	RegisterQuery(qr)
+}
@@ -0,0 +1,101 @@
+/*
+Package priority ranks txs in the mempool by the fee they offer
+per byte, so validators fill blocks with the best paying txs first.
+
+The priority is returned as GasPayment of the CheckResult. It does
+not depend on the gas of the handler, many messages like escrow
+releases cost no gas at all, but every tx takes space in a block.
+Fees in other tokens count with their value in the fee token, as
+the feepool converts them.
+*/
+package priority
+
+import (
+	"math"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/feepool"
+)
+
+// fractional units per whole coin
+const fracUnit = 1000000000
+
+// SizedTx knows its encoded size, as all protobuf txs do
+type SizedTx interface {
+	Size() int
+}
+
+// Decorator sets the fee density as priority on CheckTx, and
+// rejects txs paying less than the minimum price per byte
+type Decorator struct {
+	minPrice int64
+}
+
+var _ weave.Decorator = Decorator{}
+
+// NewDecorator rejects txs offering less than minPrice
+// fractional units of fee per byte, 0 accepts all
+func NewDecorator(minPrice int64) Decorator {
+	return Decorator{minPrice: minPrice}
+}
+
+// Check verifies the fee before calling down the stack,
+// and replaces the GasPayment with the price per byte
+func (d Decorator) Check(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	price := Price(store, tx)
+	if price < d.minPrice {
+		return res, ErrFeeTooLow(price, d.minPrice)
+	}
+	res, err := next.Check(ctx, store, tx)
+	if err != nil {
+		return res, err
+	}
+	res.GasPayment = price
+	return res, nil
+}
+
+// Deliver just calls down the stack. The minimum price is a
+// local setting of the node, it must not affect consensus.
+func (d Decorator) Deliver(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	return next.Deliver(ctx, store, tx)
+}
+
+// Price returns the fee of the tx, in fractional units of the
+// fee token per byte of the tx. Txs without fee, or with a fee
+// the feepool does not accept, have price 0.
+func Price(db weave.ReadOnlyKVStore, tx weave.Tx) int64 {
+	ftx, ok := tx.(cash.FeeTx)
+	if !ok {
+		return 0
+	}
+	finfo := ftx.GetFees()
+	if finfo == nil || finfo.Fees == nil || !finfo.Fees.IsPositive() {
+		return 0
+	}
+	size := int64(1)
+	if stx, ok := tx.(SizedTx); ok && stx.Size() > 1 {
+		size = int64(stx.Size())
+	}
+	fee, err := feepool.Value(db, *finfo.Fees)
+	if err != nil {
+		return 0
+	}
+	return fractional(fee) / size
+}
+
+// fractional returns the amount of the coin in fractional
+// units, or math.MaxInt64 if it doesn't fit
+func fractional(c x.Coin) int64 {
+	if c.Whole > (math.MaxInt64-c.Fractional)/fracUnit {
+		return math.MaxInt64
+	}
+	return c.Whole*fracUnit + c.Fractional
+}
@@ -0,0 +1,108 @@
+package priority
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/escrow"
+	"github.com/iov-one/bcp-demo/x/feepool"
+)
+
+func TestDecorator(t *testing.T) {
+	var helpers x.TestHelpers
+
+	release := helpers.MockTx(&escrow.ReleaseEscrowMsg{EscrowId: []byte("foo")})
+	send := helpers.MockTx(&cash.SendMsg{Memo: "hello"})
+	fee := func(whole, frac int64) *x.Coin {
+		return &x.Coin{Whole: whole, Fractional: frac, Ticker: "IOV"}
+	}
+	// ETH is worth two IOV, BAR is not accepted
+	config := &feepool.Config{
+		FeeToken: "IOV",
+		Tokens: []*feepool.Conversion{
+			{Ticker: "ETH", Rate: &x.Coin{Whole: 2, Ticker: "IOV"}},
+		},
+	}
+	other := func(frac int64, ticker string) *x.Coin {
+		return &x.Coin{Fractional: frac, Ticker: ticker}
+	}
+
+	cases := []struct {
+		minPrice int64
+		tx       weave.Tx
+		priority int64
+		check    func(error) bool
+	}{
+		// escrow releases use no gas, but still pay for their size
+		0: {0, feeTx{release, fee(0, 5000), 100}, 50, nil},
+		1: {0, feeTx{send, fee(0, 5000), 250}, 20, nil},
+		2: {0, feeTx{release, fee(1, 0), 200}, 5000000, nil},
+		// no fee, no priority
+		3: {0, feeTx{release, nil, 100}, 0, nil},
+		4: {0, release, 0, nil},
+		5: {10, feeTx{release, fee(0, 1000), 100}, 10, nil},
+		6: {10, feeTx{release, fee(0, 999), 100}, 0, IsFeeTooLowErr},
+		7: {10, release, 0, IsFeeTooLowErr},
+		// huge fees don't overflow
+		8: {0, feeTx{send, fee(1000000000000, 0), 1}, math.MaxInt64, nil},
+		// other tokens count with their value in the fee token
+		9:  {0, feeTx{release, other(5000, "ETH"), 100}, 100, nil},
+		10: {100, feeTx{release, other(5000, "ETH"), 100}, 100, nil},
+		11: {0, feeTx{release, other(5000, "BAR"), 100}, 0, nil},
+		12: {10, feeTx{release, other(5000, "BAR"), 100}, 0, IsFeeTooLowErr},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			require.NoError(t, feepool.NewConfigBucket().Store(db, config))
+			stack := helpers.Wrap(NewDecorator(tc.minPrice), helpers.CountingHandler())
+
+			res, err := stack.Check(context.Background(), db, tc.tx)
+			if tc.check != nil {
+				assert.True(t, tc.check(err), "%+v", err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, int64(0), res.GasAllocated)
+				assert.Equal(t, tc.priority, res.GasPayment)
+			}
+
+			// the local minimum never rejects a tx in a block
+			_, err = stack.Deliver(context.Background(), db, tc.tx)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+//---------------- helpers --------
+
+// feeTx adds a fee and a fixed encoded size
+type feeTx struct {
+	weave.Tx
+	fee  *x.Coin
+	size int
+}
+
+var _ cash.FeeTx = feeTx{}
+var _ SizedTx = feeTx{}
+
+func (f feeTx) GetFees() *cash.FeeInfo {
+	if f.fee == nil {
+		return nil
+	}
+	return &cash.FeeInfo{Fees: f.fee}
+}
+
+func (f feeTx) Size() int {
+	return f.size
+}
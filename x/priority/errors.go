@@ -0,0 +1,26 @@
+package priority
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// priority takes 1110-1120
+const (
+	CodeFeeTooLow = 1110
+)
+
+var (
+	errFeeTooLow = fmt.Errorf("Fee too low")
+)
+
+func ErrFeeTooLow(price, min int64) error {
+	msg := fmt.Sprintf("%d per byte, need %d", price, min)
+	return errors.WithLog(msg, errFeeTooLow, CodeFeeTooLow)
+}
+func IsFeeTooLowErr(err error) bool {
+	return errors.HasErrorCode(err, CodeFeeTooLow)
+}
@@ -0,0 +1,53 @@
+package rbac
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x"
+)
+
+// Authenticator wraps an x.Authenticator, to also check
+// the roles held by the signers of a tx
+type Authenticator struct {
+	x.Authenticator
+	bucket Bucket
+}
+
+var _ x.Authenticator = Authenticator{}
+
+// NewAuthenticator checks roles of the permissions
+// returned by auth
+func NewAuthenticator(auth x.Authenticator) Authenticator {
+	return Authenticator{
+		Authenticator: auth,
+		bucket:        NewBucket(),
+	}
+}
+
+// HasRole returns true if any signer holds the role
+func (a Authenticator) HasRole(ctx weave.Context, db weave.ReadOnlyKVStore,
+	role string) (bool, error) {
+
+	for _, perm := range a.GetPermissions(ctx) {
+		ok, err := a.bucket.HasRole(db, perm.Address(), role)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// RequireRole returns ErrUnauthorized unless a signer
+// holds the role
+func (a Authenticator) RequireRole(ctx weave.Context, db weave.ReadOnlyKVStore,
+	role string) error {
+
+	ok, err := a.HasRole(ctx, db, role)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.ErrUnauthorized()
+	}
+	return nil
+}
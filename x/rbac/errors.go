@@ -0,0 +1,38 @@
+package rbac
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// rbac takes 1060-1070
+const (
+	CodeInvalidRole = 1060
+	CodeNoRole      = 1061
+)
+
+var (
+	errInvalidRole = fmt.Errorf("Invalid role")
+	errLastAdmin   = fmt.Errorf("Cannot revoke own admin role")
+	errNoRole      = fmt.Errorf("Address does not have this role")
+)
+
+func ErrInvalidRole(role string) error {
+	return errors.WithLog(role, errInvalidRole, CodeInvalidRole)
+}
+func ErrLastAdmin() error {
+	return errors.WithCode(errLastAdmin, CodeInvalidRole)
+}
+func IsInvalidRoleErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidRole)
+}
+
+func ErrNoRole(role string) error {
+	return errors.WithLog(role, errNoRole, CodeNoRole)
+}
+func IsNoRoleErr(err error) bool {
+	return errors.HasErrorCode(err, CodeNoRole)
+}
@@ -0,0 +1,161 @@
+package rbac
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+)
+
+const (
+	assignRoleCost int64 = 50
+	revokeRoleCost int64 = 0
+)
+
+// RegisterRoutes will instantiate and register
+// all handlers in this package
+func RegisterRoutes(r weave.Registry, auth Authenticator) {
+	bucket := NewBucket()
+	r.Handle(pathAssignRoleMsg, AssignRoleHandler{auth, bucket})
+	r.Handle(pathRevokeRoleMsg, RevokeRoleHandler{auth, bucket})
+}
+
+// RegisterQuery will register the roles as "/roles",
+// queried by address
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("roles", qr)
+}
+
+// AssignRoleHandler lets admins give roles to an address
+type AssignRoleHandler struct {
+	auth   Authenticator
+	bucket Bucket
+}
+
+var _ weave.Handler = AssignRoleHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h AssignRoleHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += assignRoleCost
+	return res, nil
+}
+
+// Deliver adds the role to the address
+func (h AssignRoleHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	err = h.bucket.Assign(db, msg.Address, msg.Role)
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h AssignRoleHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*AssignRoleMsg, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*AssignRoleMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	// only admins may assign roles
+	err = h.auth.RequireRole(ctx, db, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// RevokeRoleHandler lets admins take roles from an address
+type RevokeRoleHandler struct {
+	auth   Authenticator
+	bucket Bucket
+}
+
+var _ weave.Handler = RevokeRoleHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h RevokeRoleHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += revokeRoleCost
+	return res, nil
+}
+
+// Deliver removes the role from the address
+func (h RevokeRoleHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	err = h.bucket.Revoke(db, msg.Address, msg.Role)
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h RevokeRoleHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*RevokeRoleMsg, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*RevokeRoleMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	// only admins may revoke roles
+	err = h.auth.RequireRole(ctx, db, RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	// an admin cannot step down on its own, so there
+	// is always one admin left
+	if msg.Role == RoleAdmin && h.auth.HasAddress(ctx, msg.Address) {
+		return nil, ErrLastAdmin()
+	}
+
+	ok, err = h.bucket.HasRole(db, msg.Address, msg.Role)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrNoRole(msg.Role)
+	}
+	return msg, nil
+}
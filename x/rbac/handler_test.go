@@ -0,0 +1,114 @@
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+)
+
+func TestRoleHandlers(t *testing.T) {
+	var helpers x.TestHelpers
+	_, admin := helpers.MakeKey()
+	_, other := helpers.MakeKey()
+	_, issuer := helpers.MakeKey()
+
+	auth := helpers.CtxAuth("auth")
+	r := app.NewRouter()
+	RegisterRoutes(r, NewAuthenticator(auth))
+
+	cases := []struct {
+		perm    weave.Permission
+		msg     weave.Msg
+		check   func(error) bool
+		holder  weave.Address
+		role    string
+		holding bool
+	}{
+		0: {admin, &AssignRoleMsg{Address: other.Address(), Role: RoleIssuer},
+			nil, other.Address(), RoleIssuer, true},
+		// assigning twice is a no-op
+		1: {admin, &AssignRoleMsg{Address: issuer.Address(), Role: RoleIssuer},
+			nil, issuer.Address(), RoleIssuer, true},
+		2: {admin, &RevokeRoleMsg{Address: issuer.Address(), Role: RoleIssuer},
+			nil, issuer.Address(), RoleIssuer, false},
+		// admins can make admins, and revoke them
+		3: {admin, &AssignRoleMsg{Address: other.Address(), Role: RoleAdmin},
+			nil, other.Address(), RoleAdmin, true},
+		4: {admin, &RevokeRoleMsg{Address: admin.Address(), Role: RoleAdmin},
+			IsInvalidRoleErr, admin.Address(), RoleAdmin, true},
+		5: {admin, &RevokeRoleMsg{Address: other.Address(), Role: RoleIssuer},
+			IsNoRoleErr, other.Address(), RoleIssuer, false},
+		// only admins
+		6: {issuer, &AssignRoleMsg{Address: other.Address(), Role: RoleIssuer},
+			errors.IsUnauthorizedErr, other.Address(), RoleIssuer, false},
+		7: {issuer, &RevokeRoleMsg{Address: issuer.Address(), Role: RoleIssuer},
+			errors.IsUnauthorizedErr, issuer.Address(), RoleIssuer, true},
+		// invalid messages
+		8: {admin, &AssignRoleMsg{Address: other.Address(), Role: "Boss"},
+			IsInvalidRoleErr, other.Address(), RoleIssuer, false},
+		9: {admin, &AssignRoleMsg{Address: []byte{1, 2, 3}, Role: RoleIssuer},
+			errors.IsUnrecognizedAddressErr, other.Address(), RoleIssuer, false},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			bucket := NewBucket()
+			require.NoError(t, bucket.Assign(db, admin.Address(), RoleAdmin))
+			require.NoError(t, bucket.Assign(db, issuer.Address(), RoleIssuer))
+
+			ctx := auth.SetPermissions(context.Background(), tc.perm)
+			tx := helpers.MockTx(tc.msg)
+			_, err := r.Check(ctx, db, tx)
+			if tc.check != nil {
+				assert.True(t, tc.check(err), "%+v", err)
+				_, err = r.Deliver(ctx, db, tx)
+				assert.True(t, tc.check(err), "%+v", err)
+			} else {
+				require.NoError(t, err)
+				_, err = r.Deliver(ctx, db, tx)
+				require.NoError(t, err)
+			}
+
+			has, err := bucket.HasRole(db, tc.holder, tc.role)
+			require.NoError(t, err)
+			assert.Equal(t, tc.holding, has)
+		})
+	}
+}
+
+func TestAuthenticator(t *testing.T) {
+	var helpers x.TestHelpers
+	_, a := helpers.MakeKey()
+	_, b := helpers.MakeKey()
+
+	db := store.MemStore()
+	require.NoError(t, NewBucket().Assign(db, b.Address(), RoleOracleFeeder))
+
+	auth := NewAuthenticator(helpers.CtxAuth("auth"))
+	ctx := helpers.CtxAuth("auth").SetPermissions(context.Background(), a)
+	assert.True(t, auth.HasAddress(ctx, a.Address()))
+	assert.True(t, errors.IsUnauthorizedErr(auth.RequireRole(ctx, db, RoleOracleFeeder)))
+
+	// any signer with the role is enough
+	ctx = helpers.CtxAuth("auth").SetPermissions(context.Background(), a, b)
+	assert.NoError(t, auth.RequireRole(ctx, db, RoleOracleFeeder))
+	ok, err := auth.HasRole(ctx, db, RoleAdmin)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// the last role removes the entry
+	require.NoError(t, NewBucket().Revoke(db, b.Address(), RoleOracleFeeder))
+	obj, err := NewBucket().Get(db, b.Address())
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+}
@@ -0,0 +1,51 @@
+package rbac
+
+import (
+	"encoding/json"
+
+	"github.com/confio/weave"
+)
+
+const optRoles = "roles"
+
+// GenesisRoles lists the roles of one address in the
+// genesis file
+type GenesisRoles struct {
+	Address weave.Address `json:"address"`
+	Roles   []string      `json:"roles"`
+}
+
+// Initializer fulfils the InitStater interface to load data from
+// the genesis file
+type Initializer struct{}
+
+var _ weave.Initializer = Initializer{}
+
+// FromGenesis will assign the initial roles
+func (Initializer) FromGenesis(opts weave.Options, db weave.KVStore) error {
+	gens := []GenesisRoles{}
+	err := opts.ReadOptions(optRoles, &gens)
+	if err != nil {
+		return err
+	}
+
+	bucket := NewBucket()
+	for _, gen := range gens {
+		for _, role := range gen.Roles {
+			err := bucket.Assign(db, gen.Address, role)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// BuildGenesis will create Options with the given roles
+func BuildGenesis(gens []GenesisRoles) (weave.Options, error) {
+	bz, err := json.MarshalIndent(gens, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return weave.Options{optRoles: bz}, nil
+}
@@ -0,0 +1,39 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+)
+
+func TestInitState(t *testing.T) {
+	var helpers x.TestHelpers
+	_, admin := helpers.MakeKey()
+	_, other := helpers.MakeKey()
+
+	opts, err := BuildGenesis([]GenesisRoles{
+		{Address: admin.Address(), Roles: []string{RoleIssuer, RoleAdmin}},
+	})
+	require.NoError(t, err)
+
+	db := store.MemStore()
+	require.NoError(t, Initializer{}.FromGenesis(opts, db))
+
+	roles, err := NewBucket().Roles(db, admin.Address())
+	require.NoError(t, err)
+	assert.Equal(t, []string{RoleAdmin, RoleIssuer}, roles.Roles)
+	roles, err = NewBucket().Roles(db, other.Address())
+	require.NoError(t, err)
+	assert.Empty(t, roles.Roles)
+
+	// empty genesis is fine, invalid roles are not
+	require.NoError(t, Initializer{}.FromGenesis(weave.Options{}, store.MemStore()))
+	bad, err := BuildGenesis([]GenesisRoles{{Address: other.Address(), Roles: []string{"x"}}})
+	require.NoError(t, err)
+	assert.Error(t, Initializer{}.FromGenesis(bad, store.MemStore()))
+}
@@ -0,0 +1,157 @@
+/*
+Package rbac assigns roles to addresses, so handlers can check
+what the signers of a tx may do, rather than comparing them to
+a fixed address.
+
+Roles are plain names. Holders of the admin role assign and
+revoke all roles, including admin. The first admins are set in
+the genesis file.
+*/
+package rbac
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+)
+
+const (
+	// BucketName is where we store the roles of each address
+	BucketName = "roles"
+
+	// RoleAdmin may assign and revoke roles
+	RoleAdmin = "admin"
+	// RoleIssuer may create new tokens
+	RoleIssuer = "issuer"
+	// RoleCompliance may freeze and force-resolve
+	RoleCompliance = "compliance"
+	// RoleOracleFeeder may set prices
+	RoleOracleFeeder = "oracle-feeder"
+	// RoleArbiter may bid to arbitrate escrows
+	RoleArbiter = "arbiter"
+)
+
+func init() {
+	keyspace.Buckets("rbac", BucketName)
+}
+
+// IsRole limits role names to lowercase ASCII and dashes
+var IsRole = regexp.MustCompile(`^[a-z][a-z0-9-]{1,31}$`).MatchString
+
+var _ orm.CloneableData = (*Roles)(nil)
+
+// Validate ensures all roles are valid, and listed once
+func (r *Roles) Validate() error {
+	for i, role := range r.Roles {
+		if !IsRole(role) {
+			return ErrInvalidRole(role)
+		}
+		if i > 0 && r.Roles[i-1] >= role {
+			return ErrInvalidRole(role)
+		}
+	}
+	return nil
+}
+
+// Copy makes a new set with the same roles
+func (r *Roles) Copy() orm.CloneableData {
+	roles := make([]string, len(r.Roles))
+	copy(roles, r.Roles)
+	return &Roles{Roles: roles}
+}
+
+// Has returns true if the role is in the set
+func (r *Roles) Has(role string) bool {
+	i := sort.SearchStrings(r.GetRoles(), role)
+	return i < len(r.GetRoles()) && r.Roles[i] == role
+}
+
+// add inserts the role, keeping them sorted
+func (r *Roles) add(role string) {
+	if r.Has(role) {
+		return
+	}
+	r.Roles = append(r.Roles, role)
+	sort.Strings(r.Roles)
+}
+
+// remove takes the role out, returns false if it was not there
+func (r *Roles) remove(role string) bool {
+	if !r.Has(role) {
+		return false
+	}
+	i := sort.SearchStrings(r.Roles, role)
+	r.Roles = append(r.Roles[:i], r.Roles[i+1:]...)
+	return true
+}
+
+// Bucket is a type-safe wrapper around orm.Bucket,
+// storing the Roles under the address
+type Bucket struct {
+	orm.Bucket
+}
+
+// NewBucket initializes a Bucket with default name
+func NewBucket() Bucket {
+	return Bucket{
+		Bucket: orm.NewBucket(BucketName,
+			orm.NewSimpleObj(nil, new(Roles))),
+	}
+}
+
+// Roles returns the roles of the address, empty if it has none
+func (b Bucket) Roles(db weave.ReadOnlyKVStore, addr weave.Address) (*Roles, error) {
+	obj, err := b.Get(db, addr)
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil || obj.Value() == nil {
+		return new(Roles), nil
+	}
+	return obj.Value().(*Roles), nil
+}
+
+// HasRole returns true if the address holds the role
+func (b Bucket) HasRole(db weave.ReadOnlyKVStore, addr weave.Address, role string) (bool, error) {
+	roles, err := b.Roles(db, addr)
+	if err != nil {
+		return false, err
+	}
+	return roles.Has(role), nil
+}
+
+// Assign gives the role to the address, if it
+// doesn't hold it yet
+func (b Bucket) Assign(db weave.KVStore, addr weave.Address, role string) error {
+	if err := addr.Validate(); err != nil {
+		return err
+	}
+	if !IsRole(role) {
+		return ErrInvalidRole(role)
+	}
+	roles, err := b.Roles(db, addr)
+	if err != nil {
+		return err
+	}
+	roles.add(role)
+	return b.Save(db, orm.NewSimpleObj(addr, roles))
+}
+
+// Revoke takes the role from the address
+func (b Bucket) Revoke(db weave.KVStore, addr weave.Address, role string) error {
+	roles, err := b.Roles(db, addr)
+	if err != nil {
+		return err
+	}
+	if !roles.remove(role) {
+		return ErrNoRole(role)
+	}
+	if len(roles.Roles) == 0 {
+		return b.Delete(db, addr)
+	}
+	return b.Save(db, orm.NewSimpleObj(addr, roles))
+}
@@ -0,0 +1,43 @@
+package rbac
+
+import (
+	"github.com/confio/weave"
+)
+
+const (
+	pathAssignRoleMsg = "rbac/assign"
+	pathRevokeRoleMsg = "rbac/revoke"
+)
+
+var _ weave.Msg = (*AssignRoleMsg)(nil)
+var _ weave.Msg = (*RevokeRoleMsg)(nil)
+
+// Path fulfills weave.Msg interface to allow routing
+func (AssignRoleMsg) Path() string {
+	return pathAssignRoleMsg
+}
+
+// Validate makes sure that this is sensible
+func (m *AssignRoleMsg) Validate() error {
+	return validateRole(m.Address, m.Role)
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (RevokeRoleMsg) Path() string {
+	return pathRevokeRoleMsg
+}
+
+// Validate makes sure that this is sensible
+func (m *RevokeRoleMsg) Validate() error {
+	return validateRole(m.Address, m.Role)
+}
+
+func validateRole(addr weave.Address, role string) error {
+	if err := addr.Validate(); err != nil {
+		return err
+	}
+	if !IsRole(role) {
+		return ErrInvalidRole(role)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"context"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+)
+
+type contextKey int // local to the scheduler module
+
+const (
+	contextKeyOwner contextKey = iota
+)
+
+// withOwner is a private method, as only the Ticker runs
+// the message of a job as its owner
+func withOwner(ctx weave.Context, owner weave.Permission) weave.Context {
+	return context.WithValue(ctx, contextKeyOwner, owner)
+}
+
+// Authenticate implements x.Authenticator and grants the
+// permission of the owner to the message of a job
+type Authenticate struct{}
+
+var _ x.Authenticator = Authenticate{}
+
+// GetPermissions returns the owner of the job that runs in
+// the current Context. May be nil
+func (a Authenticate) GetPermissions(ctx weave.Context) []weave.Permission {
+	// (val, ok) form to return nil instead of panic if unset
+	val, _ := ctx.Value(contextKeyOwner).(weave.Permission)
+	if val == nil {
+		return nil
+	}
+	return []weave.Permission{val}
+}
+
+// HasAddress returns true if the given address is the owner
+// of the job that runs in the current Context
+func (a Authenticate) HasAddress(ctx weave.Context, addr weave.Address) bool {
+	val, _ := ctx.Value(contextKeyOwner).(weave.Permission)
+	return val != nil && val.Address().Equals(addr)
+}
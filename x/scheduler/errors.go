@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// scheduler takes 1260-1270
+const (
+	CodeInvalidJob = 1260
+	CodeNoSuchJob  = 1261
+	CodeOutOfGas   = 1262
+)
+
+var (
+	errInvalidJob = fmt.Errorf("Invalid job")
+	errNoSuchJob  = fmt.Errorf("No job with this id")
+	errOutOfGas   = fmt.Errorf("Job out of gas")
+)
+
+func ErrInvalidJob(reason string) error {
+	return errors.WithLog(reason, errInvalidJob, CodeInvalidJob)
+}
+func IsInvalidJobErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidJob)
+}
+
+func ErrNoSuchJob(id []byte) error {
+	return errors.WithLog(fmt.Sprintf("%X", id), errNoSuchJob, CodeNoSuchJob)
+}
+func IsNoSuchJobErr(err error) bool {
+	return errors.HasErrorCode(err, CodeNoSuchJob)
+}
+
+func ErrOutOfGas(used, limit int64) error {
+	msg := fmt.Sprintf("%d of %d", used, limit)
+	return errors.WithLog(msg, errOutOfGas, CodeOutOfGas)
+}
+func IsOutOfGasErr(err error) bool {
+	return errors.HasErrorCode(err, CodeOutOfGas)
+}
@@ -0,0 +1,32 @@
+package scheduler
+
+import (
+	"github.com/tendermint/tmlibs/common"
+)
+
+// Events of the jobs, with Key="scheduler.<event>" and Value the
+// id of the job. No tx carries a retry or a quarantine, so the
+// Ticker appends them to the outbox itself. A re-drive is added
+// as a tag to the DeliverResult.
+const (
+	// TagPrefix starts the key of every scheduler event
+	TagPrefix = "scheduler."
+
+	// EventRetry is recorded when a job failed and runs again
+	// later, with the id it runs under then
+	EventRetry = "retry"
+	// EventQuarantine is recorded when a job failed too often
+	// and is kept with the failed jobs
+	EventQuarantine = "quarantine"
+	// EventRedrive is recorded when an admin puts a failed job
+	// back in the queue, with the id it runs under then
+	EventRedrive = "redrive"
+)
+
+// EventTag is the tag of the event of the job id
+func EventTag(event string, id []byte) common.KVPair {
+	return common.KVPair{
+		Key:   []byte(TagPrefix + event),
+		Value: id,
+	}
+}
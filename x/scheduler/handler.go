@@ -0,0 +1,99 @@
+package scheduler
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+
+	"github.com/iov-one/bcp-demo/x/rbac"
+)
+
+const redriveJobCost int64 = 50
+
+// RegisterRoutes will instantiate and register
+// all handlers in this package
+func RegisterRoutes(r weave.Registry, auth rbac.Authenticator) {
+	r.Handle(pathRedriveJobMsg, RedriveJobHandler{auth, NewBucket(), NewFailedBucket()})
+}
+
+// RedriveJobHandler lets admins retry a failed job, once
+// whatever made it fail is fixed
+type RedriveJobHandler struct {
+	auth   rbac.Authenticator
+	bucket Bucket
+	failed FailedBucket
+}
+
+var _ weave.Handler = RedriveJobHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h RedriveJobHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += redriveJobCost
+	return res, nil
+}
+
+// Deliver moves the job back to the queue for the next block,
+// and returns the id it runs under
+func (h RedriveJobHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	height, _ := weave.GetHeight(ctx)
+	job := AsFailedJob(obj).Job
+	job.Height = height + 1
+	job.Attempts = 0
+	id, err := h.bucket.Schedule(db, job)
+	if err != nil {
+		return res, err
+	}
+	err = h.failed.Delete(db, obj.Key())
+	if err != nil {
+		return res, err
+	}
+	res.Data = id
+	res.Tags = append(res.Tags, EventTag(EventRedrive, id))
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h RedriveJobHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (orm.Object, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*RedriveJobMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	err = h.auth.RequireRole(ctx, db, rbac.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := h.failed.Get(db, msg.JobId)
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil {
+		return nil, ErrNoSuchJob(msg.JobId)
+	}
+	return obj, nil
+}
@@ -0,0 +1,265 @@
+/*
+Package scheduler runs messages the modules queue for a later
+block, like returning an escrow once it timed out.
+
+A module creates a Job with NewJob and saves it with
+Bucket.Schedule, usually in the Deliver of a handler. At the
+start of the block at that height the Ticker unpacks the message
+and routes it to its handler, as if a tx carried it. The message
+runs with the permission of the owner of the job, if any, so only
+modules may schedule jobs, never a tx directly.
+
+Jobs are stored under their height and a sequence, so they run
+in the order they are due and, for one height, were scheduled.
+The Ticker runs the messages with up to maxGasPerBlock gas per
+block, as their Check reports, and no more than the budget of the
+block (see package budget) allows. It leaves the rest due for the
+next block. A job may not take more than the gas it was given.
+
+Anything the message of a failed job wrote is discarded. If its
+Check fails the message no longer applies, eg. the escrow to
+return was closed, and the job is dropped. If it fails to deliver
+or takes too much gas, it runs again retryBlocks later, then twice
+that, up to MaxAttempts in all. Then it is kept with the failed
+jobs, so it doesn't hold up the others, until an admin re-drives
+it with a RedriveJobMsg. Retries and quarantines are appended to
+the outbox as events.
+
+The pending jobs are served as "/jobs", and as "/jobs/module"
+by the module that scheduled them, the failed ones likewise as
+"/jobs/failed" and "/jobs/failed/module".
+*/
+package scheduler
+
+import (
+	"encoding/binary"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+	"github.com/iov-one/bcp-demo/x/anymsg"
+)
+
+const (
+	// BucketName is where we store the jobs
+	BucketName = "sched"
+	// BucketNameFailed is where we keep the jobs that failed
+	// too often
+	BucketNameFailed = "schedq"
+	// SequenceName numbers the jobs
+	SequenceName = "id"
+	// IndexModule finds the jobs of a module
+	IndexModule = "module"
+
+	// MaxJobGas is the most gas a job may be given
+	MaxJobGas int64 = 1000
+	// MaxAttempts is how often a job may fail to deliver
+	// before it is kept with the failed jobs
+	MaxAttempts = 3
+
+	maxGasPerBlock  int64 = 10000
+	maxJobsPerBlock       = 100
+	retryBlocks     int64 = 10
+)
+
+func init() {
+	keyspace.Buckets("scheduler", BucketName, BucketNameFailed)
+}
+
+var (
+	_ orm.CloneableData = (*Job)(nil)
+	_ orm.CloneableData = (*FailedJob)(nil)
+)
+
+// NewJob packs the message of a job the module runs at the
+// given height with up to gas. The message must be registered
+// with x/anymsg.
+func NewJob(module string, height int64, msg anymsg.Msg, gas int64) (*Job, error) {
+	packed, err := anymsg.Pack(msg)
+	if err != nil {
+		return nil, err
+	}
+	job := &Job{Height: height, Module: module, Msg: packed, Gas: gas}
+	return job, job.Validate()
+}
+
+// Validate ensures the job has a height, a module, a message
+// and gas for it
+func (j *Job) Validate() error {
+	switch {
+	case j.Height <= 0:
+		return ErrInvalidJob("height")
+	case j.Module == "":
+		return ErrInvalidJob("missing module")
+	case j.Msg == nil || j.Msg.TypeUrl == "":
+		return ErrInvalidJob("missing msg")
+	case j.Gas <= 0 || j.Gas > MaxJobGas:
+		return ErrInvalidJob("gas")
+	case j.Attempts < 0 || j.Attempts > MaxAttempts:
+		return ErrInvalidJob("attempts")
+	}
+	if j.Owner != nil {
+		return weave.Permission(j.Owner).Validate()
+	}
+	return nil
+}
+
+// Copy makes a new job with the same values
+func (j *Job) Copy() orm.CloneableData {
+	var msg *anymsg.Any
+	if j.Msg != nil {
+		msg = &anymsg.Any{TypeUrl: j.Msg.TypeUrl, Value: j.Msg.Value}
+	}
+	return &Job{
+		Height:   j.Height,
+		Module:   j.Module,
+		Owner:    j.Owner,
+		Msg:      msg,
+		Gas:      j.Gas,
+		Attempts: j.Attempts,
+	}
+}
+
+// AsJob safely extracts a Job value from the object
+func AsJob(obj orm.Object) *Job {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Job)
+}
+
+// Validate ensures the failed job is valid and has an error
+// and a height
+func (f *FailedJob) Validate() error {
+	if f.Job == nil {
+		return ErrInvalidJob("missing job")
+	}
+	if f.Error == "" || f.Height <= 0 {
+		return ErrInvalidJob("missing failure")
+	}
+	return f.Job.Validate()
+}
+
+// Copy makes a new failed job with the same values
+func (f *FailedJob) Copy() orm.CloneableData {
+	var job *Job
+	if f.Job != nil {
+		job = f.Job.Copy().(*Job)
+	}
+	return &FailedJob{Job: job, Error: f.Error, Height: f.Height}
+}
+
+// AsFailedJob safely extracts a FailedJob value from the object
+func AsFailedJob(obj orm.Object) *FailedJob {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*FailedJob)
+}
+
+// Key returns the key of a job due at height with the sequence,
+// big endian so the jobs are sorted by height, then sequence
+func Key(height, seq int64) []byte {
+	bz := make([]byte, 16)
+	binary.BigEndian.PutUint64(bz, uint64(height))
+	binary.BigEndian.PutUint64(bz[8:], uint64(seq))
+	return bz
+}
+
+//--- Bucket
+
+// Bucket is a type-safe wrapper around orm.Bucket
+type Bucket struct {
+	orm.Bucket
+	seq orm.Sequence
+}
+
+// NewBucket initializes a Bucket with default name
+func NewBucket() Bucket {
+	bucket := orm.NewBucket(BucketName, orm.NewSimpleObj(nil, new(Job))).
+		WithIndex(IndexModule, idxModule, false)
+	return Bucket{
+		Bucket: bucket,
+		seq:    bucket.Sequence(SequenceName),
+	}
+}
+
+func idxModule(obj orm.Object) ([]byte, error) {
+	job := AsJob(obj)
+	if job == nil {
+		return nil, errors.ErrInternal("Can only take index of Job")
+	}
+	return []byte(job.Module), nil
+}
+
+// Schedule stores the job after the ones due at the same
+// height and returns its id
+func (b Bucket) Schedule(db weave.KVStore, job *Job) ([]byte, error) {
+	if err := job.Validate(); err != nil {
+		return nil, err
+	}
+	id := Key(job.Height, b.seq.NextInt(db))
+	return id, b.Save(db, orm.NewSimpleObj(id, job))
+}
+
+// Cancel removes the job with the id before it runs
+func (b Bucket) Cancel(db weave.KVStore, id []byte) error {
+	obj, err := b.Get(db, id)
+	if err != nil {
+		return err
+	}
+	if obj == nil {
+		return ErrNoSuchJob(id)
+	}
+	return b.Delete(db, id)
+}
+
+// Due returns up to limit jobs due at the height or before,
+// in the order they run, with their keys
+func (b Bucket) Due(db weave.ReadOnlyKVStore, height int64, limit int) ([]orm.Object, error) {
+	if height <= 0 {
+		return nil, nil
+	}
+	prefix := b.DBKey(nil)
+	itr := db.Iterator(prefix, b.DBKey(Key(height+1, 0)))
+	defer itr.Close()
+
+	var res []orm.Object
+	for ; itr.Valid() && len(res) < limit; itr.Next() {
+		var job Job
+		if err := job.Unmarshal(itr.Value()); err != nil {
+			return nil, err
+		}
+		res = append(res, orm.NewSimpleObj(itr.Key()[len(prefix):], &job))
+	}
+	return res, nil
+}
+
+// FailedBucket keeps the jobs that failed too often by id
+type FailedBucket struct {
+	orm.Bucket
+}
+
+// NewFailedBucket initializes a FailedBucket with default name
+func NewFailedBucket() FailedBucket {
+	bucket := orm.NewBucket(BucketNameFailed, orm.NewSimpleObj(nil, new(FailedJob))).
+		WithIndex(IndexModule, idxFailedModule, false)
+	return FailedBucket{Bucket: bucket}
+}
+
+func idxFailedModule(obj orm.Object) ([]byte, error) {
+	f := AsFailedJob(obj)
+	if f == nil || f.Job == nil {
+		return nil, errors.ErrInternal("Can only take index of FailedJob")
+	}
+	return []byte(f.Job.Module), nil
+}
+
+// RegisterQuery will register the pending jobs as "/jobs"
+// and the failed ones as "/jobs/failed"
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("jobs", qr)
+	NewFailedBucket().Register("jobs/failed", qr)
+}
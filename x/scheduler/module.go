@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"github.com/confio/weave"
+
+	"github.com/iov-one/bcp-demo/module"
+	"github.com/iov-one/bcp-demo/x/rbac"
+)
+
+// Module runs the jobs other modules schedule, the app adds
+// Authenticate to its authenticator so they run as their owner.
+// The admins of Auth re-drive the failed jobs.
+type Module struct {
+	Auth rbac.Authenticator
+}
+
+var (
+	_ module.Module      = Module{}
+	_ module.Router      = Module{}
+	_ module.Querier     = Module{}
+	_ module.Dispatching = Module{}
+)
+
+// Name fulfils module.Module
+func (Module) Name() string {
+	return "scheduler"
+}
+
+// Version fulfils module.Module
+func (Module) Version() uint32 {
+	return 2
+}
+
+// RegisterRoutes fulfils module.Router
+func (m Module) RegisterRoutes(r weave.Registry) {
+	RegisterRoutes(r, m.Auth)
+}
+
+// RegisterQuery fulfils module.Querier
+func (Module) RegisterQuery(qr weave.QueryRouter) {
+	RegisterQuery(qr)
+}
+
+// Dispatcher fulfils module.Dispatching
+func (Module) Dispatcher(h weave.Handler) weave.Ticker {
+	return NewTicker(h)
+}
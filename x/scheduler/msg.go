@@ -0,0 +1,22 @@
+package scheduler
+
+import (
+	"github.com/confio/weave"
+)
+
+const pathRedriveJobMsg = "scheduler/redrive"
+
+var _ weave.Msg = (*RedriveJobMsg)(nil)
+
+// Path fulfills weave.Msg interface to allow routing
+func (RedriveJobMsg) Path() string {
+	return pathRedriveJobMsg
+}
+
+// Validate makes sure the id is the one of a job
+func (m *RedriveJobMsg) Validate() error {
+	if len(m.JobId) != 16 {
+		return ErrInvalidJob("job id")
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/budget"
+	"github.com/iov-one/bcp-demo/x/anymsg"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+	"github.com/iov-one/bcp-demo/x/outbox"
+	"github.com/iov-one/bcp-demo/x/rbac"
+)
+
+func init() {
+	anymsg.Register(&cash.SendMsg{})
+}
+
+// recorder takes the amount of a send as gas, rejects the ones
+// with memo "stale", and writes its memo before it fails the ones
+// with memo "fail" while broken or panics on the ones with "panic"
+type recorder struct {
+	ran    []string
+	owners [][]weave.Permission
+	broken bool
+}
+
+func (r *recorder) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	msg, _ := tx.GetMsg()
+	send := msg.(*cash.SendMsg)
+	if send.Memo == "stale" {
+		return weave.CheckResult{}, errors.ErrUnauthorized()
+	}
+	return weave.CheckResult{GasAllocated: send.Amount.Whole}, nil
+}
+
+func (r *recorder) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	msg, _ := tx.GetMsg()
+	memo := msg.(*cash.SendMsg).Memo
+	db.Set([]byte(memo), []byte{1})
+	r.ran = append(r.ran, memo)
+	r.owners = append(r.owners, Authenticate{}.GetPermissions(ctx))
+	if memo == "panic" {
+		panic(memo)
+	}
+	if memo == "fail" && r.broken {
+		return weave.DeliverResult{}, errors.ErrInternal(memo)
+	}
+	return weave.DeliverResult{}, nil
+}
+
+func send(memo string, gas int64) *cash.SendMsg {
+	return &cash.SendMsg{Memo: memo, Amount: &x.Coin{Whole: gas, Ticker: "FOO"}}
+}
+
+func TestScheduler(t *testing.T) {
+	var helpers x.TestHelpers
+	_, owner := helpers.MakeKey()
+
+	db := store.MemStore()
+	bucket := NewBucket()
+	h := new(recorder)
+	ticker := NewTicker(h)
+	schedule := func(height int64, memo string, gas, limit int64) []byte {
+		job, err := NewJob("test", height, send(memo, gas), limit)
+		require.NoError(t, err)
+		id, err := bucket.Schedule(db, job)
+		require.NoError(t, err)
+		return id
+	}
+	tick := func(height int64) []string {
+		h.ran = nil
+		_, err := ticker.Tick(weave.WithHeight(context.Background(), height), db)
+		require.NoError(t, err)
+		return h.ran
+	}
+
+	// by height, then in the order they were scheduled
+	schedule(20, "late", 10, 10)
+	schedule(10, "first", 10, 10)
+	schedule(10, "second", 10, 10)
+	canceled := schedule(10, "canceled", 10, 10)
+	require.NoError(t, bucket.Cancel(db, canceled))
+	assert.True(t, IsNoSuchJobErr(bucket.Cancel(db, canceled)))
+	assert.Empty(t, tick(9))
+	assert.Equal(t, []string{"first", "second"}, tick(15))
+	assert.Equal(t, []string{"late"}, tick(20))
+	assert.Empty(t, tick(21))
+
+	// a job that no longer applies is dropped
+	schedule(30, "stale", 10, 10)
+	schedule(30, "done", 10, 10)
+	assert.Equal(t, []string{"done"}, tick(30))
+	assert.NotNil(t, db.Get([]byte("done")))
+	due, err := bucket.Due(db, 1000, 10)
+	require.NoError(t, err)
+	assert.Len(t, due, 0)
+
+	// the jobs that don't fit in a block run in the next one
+	for i := 0; i < 12; i++ {
+		schedule(40, "full", MaxJobGas, MaxJobGas)
+	}
+	assert.Len(t, tick(40), int(maxGasPerBlock/MaxJobGas))
+	assert.Len(t, tick(41), 12-int(maxGasPerBlock/MaxJobGas))
+
+	// and those the budget of the block has no gas left for
+	for i := 0; i < 5; i++ {
+		schedule(45, "budget", MaxJobGas, MaxJobGas)
+	}
+	h.ran = nil
+	ctx := budget.With(weave.WithHeight(context.Background(), 45), 3*MaxJobGas+1)
+	_, err = ticker.Tick(ctx, db)
+	require.NoError(t, err)
+	assert.Len(t, h.ran, 3)
+	left, _ := budget.Left(ctx)
+	assert.Equal(t, int64(1), left)
+	assert.Len(t, tick(46), 2)
+
+	// the message runs with the permission of the owner
+	job, err := NewJob("test", 50, send("owned", 1), 1)
+	require.NoError(t, err)
+	job.Owner = owner
+	_, err = bucket.Schedule(db, job)
+	require.NoError(t, err)
+	h.owners = nil
+	assert.Equal(t, []string{"owned"}, tick(50))
+	assert.Equal(t, [][]weave.Permission{{owner}}, h.owners)
+	assert.False(t, Authenticate{}.HasAddress(context.Background(), owner.Address()))
+
+	// a job that panics fails alone, and is retried
+	schedule(60, "panic", 1, 1)
+	schedule(60, "after", 1, 1)
+	assert.Equal(t, []string{"panic", "after"}, tick(60))
+	assert.Nil(t, db.Get([]byte("panic")))
+	assert.NotNil(t, db.Get([]byte("after")))
+	due, err = bucket.Due(db, 1000, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, int32(1), AsJob(due[0]).Attempts)
+}
+
+// TestRetries retries a job that fails to deliver, then keeps
+// it aside until an admin re-drives it
+func TestRetries(t *testing.T) {
+	var helpers x.TestHelpers
+	_, admin := helpers.MakeKey()
+	_, other := helpers.MakeKey()
+
+	db := store.MemStore()
+	bucket := NewBucket()
+	failed := NewFailedBucket()
+	events := outbox.NewBucket()
+	h := &recorder{broken: true}
+	ticker := NewTicker(h)
+	auth := helpers.CtxAuth("admin")
+	r := app.NewRouter()
+	RegisterRoutes(r, rbac.NewAuthenticator(auth))
+	require.NoError(t, rbac.NewBucket().Assign(db, admin.Address(), rbac.RoleAdmin))
+
+	tick := func(height int64) []string {
+		h.ran = nil
+		_, err := ticker.Tick(weave.WithHeight(context.Background(), height), db)
+		require.NoError(t, err)
+		return h.ran
+	}
+	pending := func() []*Job {
+		objs, err := bucket.Due(db, 1000, 10)
+		require.NoError(t, err)
+		var res []*Job
+		for _, obj := range objs {
+			res = append(res, AsJob(obj))
+		}
+		return res
+	}
+	recorded := func() []string {
+		objs, err := events.After(db, 0, 10)
+		require.NoError(t, err)
+		var res []string
+		for _, obj := range objs {
+			res = append(res, outbox.AsEvent(obj).Key)
+		}
+		return res
+	}
+
+	job, err := NewJob("test", 10, send("fail", 10), 10)
+	require.NoError(t, err)
+	id, err := bucket.Schedule(db, job)
+	require.NoError(t, err)
+	greedy, err := NewJob("test", 10, send("greedy", 20), 10)
+	require.NoError(t, err)
+	_, err = bucket.Schedule(db, greedy)
+	require.NoError(t, err)
+
+	// both run again 10 blocks later, then 20, and nothing they
+	// wrote is kept
+	assert.Equal(t, []string{"fail"}, tick(10))
+	assert.Nil(t, db.Get([]byte("fail")))
+	jobs := pending()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, int64(20), jobs[0].Height)
+	assert.Equal(t, int32(1), jobs[0].Attempts)
+	assert.Equal(t, []string{"fail"}, tick(20))
+	assert.Empty(t, tick(39))
+	assert.Equal(t, []string{"fail"}, tick(40))
+	assert.Empty(t, pending())
+
+	// then they are kept aside, by their last id
+	objs, err := failed.Query(db, weave.PrefixQueryMod, nil)
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+	obj, err := failed.Get(db, Key(40, 5))
+	require.NoError(t, err)
+	require.NotNil(t, obj)
+	quarantined := AsFailedJob(obj)
+	assert.Equal(t, int64(40), quarantined.Height)
+	assert.Equal(t, int32(MaxAttempts), quarantined.Job.Attempts)
+	assert.Equal(t, "fail", quarantined.Error)
+	assert.Equal(t, []string{"scheduler.retry", "scheduler.retry", "scheduler.retry",
+		"scheduler.retry", "scheduler.quarantine", "scheduler.quarantine"}, recorded())
+
+	// only admins re-drive, and only failed jobs
+	redrive := func(signer weave.Permission, id []byte) ([]byte, error) {
+		ctx := weave.WithHeight(context.Background(), 50)
+		ctx = auth.SetPermissions(ctx, signer)
+		tx := helpers.MockTx(&RedriveJobMsg{JobId: id})
+		if _, err := r.Check(ctx, db, tx); err != nil {
+			return nil, err
+		}
+		res, err := r.Deliver(ctx, db, tx)
+		return res.Data, err
+	}
+	_, err = redrive(other, Key(40, 5))
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+	_, err = redrive(admin, id)
+	assert.True(t, IsNoSuchJobErr(err), "%+v", err)
+	h.broken = false
+	next, err := redrive(admin, Key(40, 5))
+	require.NoError(t, err)
+	assert.Equal(t, int64(51), AsJob(mustGet(t, bucket.Bucket, db, next)).Height)
+	assert.Nil(t, mustGet(t, failed.Bucket, db, Key(40, 5)))
+	assert.Equal(t, []string{"fail"}, tick(51))
+	assert.NotNil(t, db.Get([]byte("fail")))
+}
+
+func mustGet(t *testing.T, b orm.Bucket, db weave.ReadOnlyKVStore, id []byte) orm.Object {
+	obj, err := b.Get(db, id)
+	require.NoError(t, err)
+	return obj
+}
+
+func TestJobValidate(t *testing.T) {
+	valid := func() *Job {
+		job, err := NewJob("test", 10, send("ok", 1), 10)
+		require.NoError(t, err)
+		return job
+	}
+	cases := []func(*Job){
+		func(j *Job) { j.Height = 0 },
+		func(j *Job) { j.Module = "" },
+		func(j *Job) { j.Msg = nil },
+		func(j *Job) { j.Gas = 0 },
+		func(j *Job) { j.Gas = MaxJobGas + 1 },
+		func(j *Job) { j.Owner = []byte("foo") },
+		func(j *Job) { j.Attempts = MaxAttempts + 1 },
+	}
+	for i, mutate := range cases {
+		job := valid()
+		mutate(job)
+		_, err := NewBucket().Schedule(store.MemStore(), job)
+		assert.Error(t, err, "case %d", i)
+	}
+
+	// only registered messages
+	_, err := NewJob("test", 10, &namecoin.SetWalletNameMsg{}, 10)
+	assert.True(t, anymsg.IsUnknownTypeErr(err), "%+v", err)
+}
+
+func TestQuery(t *testing.T) {
+	db := store.MemStore()
+	qr := weave.NewQueryRouter()
+	RegisterQuery(qr)
+	for _, module := range []string{"escrow", "trade", "escrow"} {
+		job, err := NewJob(module, 10, send(module, 1), 1)
+		require.NoError(t, err)
+		_, err = NewBucket().Schedule(db, job)
+		require.NoError(t, err)
+	}
+
+	models, err := qr.Handler("/jobs").Query(db, weave.PrefixQueryMod, nil)
+	require.NoError(t, err)
+	assert.Len(t, models, 3)
+	models, err = qr.Handler("/jobs/module").Query(db, weave.KeyQueryMod, []byte("escrow"))
+	require.NoError(t, err)
+	assert.Len(t, models, 2)
+}
@@ -0,0 +1,198 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/iov-one/bcp-demo/budget"
+	"github.com/iov-one/bcp-demo/x/anymsg"
+	"github.com/iov-one/bcp-demo/x/outbox"
+)
+
+// Ticker runs the jobs that are due through the handler,
+// usually the router of the app behind x/features
+type Ticker struct {
+	bucket  Bucket
+	failed  FailedBucket
+	events  outbox.Bucket
+	handler weave.Handler
+}
+
+var _ weave.Ticker = Ticker{}
+
+// NewTicker creates a Ticker routing the messages to h
+func NewTicker(h weave.Handler) Ticker {
+	return Ticker{
+		bucket:  NewBucket(),
+		failed:  NewFailedBucket(),
+		events:  outbox.NewBucket(),
+		handler: h,
+	}
+}
+
+// Tick runs up to maxJobsPerBlock jobs that are due, the ones
+// due first come first, as long as they fit in maxGasPerBlock
+// and the budget of the block. A job takes all the gas it was
+// given of the budget before it runs.
+// Every job is removed once it ran. A job that no longer applies
+// is only logged, one that failed to deliver, or panicked, is
+// retried.
+func (t Ticker) Tick(ctx weave.Context, db weave.KVStore) (weave.TickResult, error) {
+	var res weave.TickResult
+	height, _ := weave.GetHeight(ctx)
+	due, err := t.bucket.Due(db, height, maxJobsPerBlock)
+	if err != nil {
+		return res, err
+	}
+	var used int64
+	for _, obj := range due {
+		job := AsJob(obj)
+		// the rest runs in the next block
+		if used+job.Gas > maxGasPerBlock || !budget.Take(ctx, job.Gas) {
+			break
+		}
+		err := t.bucket.Delete(db, obj.Key())
+		if err != nil {
+			return res, err
+		}
+		gas, diff, applies, err := t.run(ctx, db, job)
+		used += gas
+		switch {
+		case err == nil:
+			res.Diff = append(res.Diff, diff...)
+		case !applies:
+			weave.GetLogger(ctx).Info("Scheduled job dropped", "id", fmt.Sprintf("%X", obj.Key()),
+				"module", job.Module, "type", job.Msg.TypeUrl, "err", err)
+		default:
+			weave.GetLogger(ctx).Info("Scheduled job failed", "id", fmt.Sprintf("%X", obj.Key()),
+				"module", job.Module, "type", job.Msg.TypeUrl, "attempt", job.Attempts+1, "err", err)
+			err = t.fail(ctx, db, obj.Key(), job, err)
+			if err != nil {
+				return res, err
+			}
+		}
+	}
+	return res, nil
+}
+
+// run checks and delivers the message of the job as its owner.
+// It returns the gas the message took and if its Check passed,
+// and what it wrote is discarded if it fails.
+func (t Ticker) run(ctx weave.Context, db weave.KVStore,
+	job *Job) (int64, []abci.Validator, bool, error) {
+
+	msg, err := job.Msg.Unpack()
+	if err != nil {
+		return 0, nil, false, err
+	}
+	tx := jobTx{msg: msg, packed: job.Msg}
+	if job.Owner != nil {
+		ctx = withOwner(ctx, job.Owner)
+	}
+
+	var gas int64
+	err = isolate(db, false, func(db weave.KVStore) error {
+		res, err := t.handler.Check(ctx, db, tx)
+		gas = res.GasAllocated
+		return err
+	})
+	if err != nil {
+		return gas, nil, false, err
+	}
+	if gas > job.Gas {
+		return job.Gas, nil, true, ErrOutOfGas(gas, job.Gas)
+	}
+
+	var diff []abci.Validator
+	err = isolate(db, true, func(db weave.KVStore) error {
+		res, err := t.handler.Deliver(ctx, db, tx)
+		diff = res.Diff
+		return err
+	})
+	return gas, diff, true, err
+}
+
+// fail counts a failed attempt of the job with the id. It runs
+// again after retryBlocks times the attempts so far, and after
+// MaxAttempts it is kept with the failed jobs under the id.
+func (t Ticker) fail(ctx weave.Context, db weave.KVStore, id []byte,
+	job *Job, cause error) error {
+
+	height, _ := weave.GetHeight(ctx)
+	job.Attempts++
+	if job.Attempts < MaxAttempts {
+		job.Height = height + retryBlocks*int64(job.Attempts)
+		next, err := t.bucket.Schedule(db, job)
+		if err != nil {
+			return err
+		}
+		return t.record(ctx, db, EventRetry, next)
+	}
+	failed := &FailedJob{Job: job, Error: cause.Error(), Height: height}
+	err := t.failed.Save(db, orm.NewSimpleObj(id, failed))
+	if err != nil {
+		return err
+	}
+	return t.record(ctx, db, EventQuarantine, id)
+}
+
+// record appends the event of the job to the outbox
+func (t Ticker) record(ctx weave.Context, db weave.KVStore, event string, id []byte) error {
+	height, _ := weave.GetHeight(ctx)
+	header, _ := weave.GetHeader(ctx)
+	tag := EventTag(event, id)
+	return t.events.Append(db, &outbox.Event{Height: height, Time: header.Time,
+		Key: string(tag.Key), Value: tag.Value})
+}
+
+// isolate runs fn on a cache of db, which is written if fn
+// succeeds and write is set, and discarded otherwise. A panic
+// in fn fails it like an error, as no tx recovers it here.
+func isolate(db weave.KVStore, write bool, fn func(weave.KVStore) error) error {
+	cstore, ok := db.(weave.CacheableKVStore)
+	if !ok {
+		return recovered(fn, db)
+	}
+	cache := cstore.CacheWrap()
+	err := recovered(fn, cache)
+	if err == nil && write {
+		cache.Write()
+	} else {
+		cache.Discard()
+	}
+	return err
+}
+
+// recovered calls fn, turning a panic into an error
+func recovered(fn func(weave.KVStore) error, db weave.KVStore) (err error) {
+	defer errors.Recover(&err)
+	return fn(db)
+}
+
+// jobTx carries the message of a job to its handler
+type jobTx struct {
+	msg    weave.Msg
+	packed *anymsg.Any
+}
+
+var _ weave.Tx = jobTx{}
+
+// GetMsg fulfils weave.Tx
+func (t jobTx) GetMsg() (weave.Msg, error) {
+	return t.msg, nil
+}
+
+// Marshal fulfils weave.Persistent, a job is stored as an Any
+func (t jobTx) Marshal() ([]byte, error) {
+	return t.packed.Marshal()
+}
+
+// Unmarshal fulfils weave.Persistent, but jobs are never decoded
+// from a tx
+func (t jobTx) Unmarshal([]byte) error {
+	return ErrInvalidJob("not a tx")
+}
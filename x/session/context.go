@@ -0,0 +1,44 @@
+package session
+
+import (
+	"context"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+)
+
+type contextKey int // local to the session module
+
+const (
+	contextKeyAccounts contextKey = iota
+)
+
+// withAccounts is a private method, as only the Decorator
+// may add accounts after checking the sessions
+func withAccounts(ctx weave.Context, accounts []weave.Permission) weave.Context {
+	return context.WithValue(ctx, contextKeyAccounts, accounts)
+}
+
+// Authenticate implements x.Authenticator and provides
+// the accounts the session keys of the tx act for
+type Authenticate struct{}
+
+var _ x.Authenticator = Authenticate{}
+
+// GetPermissions returns the accounts of the session keys.
+// May be nil
+func (a Authenticate) GetPermissions(ctx weave.Context) []weave.Permission {
+	val, _ := ctx.Value(contextKeyAccounts).([]weave.Permission)
+	return val
+}
+
+// HasAddress returns true if a session key signed for this
+// account in the current Context.
+func (a Authenticate) HasAddress(ctx weave.Context, addr weave.Address) bool {
+	for _, perm := range a.GetPermissions(ctx) {
+		if perm.Address().Equals(addr) {
+			return true
+		}
+	}
+	return false
+}
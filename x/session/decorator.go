@@ -0,0 +1,158 @@
+package session
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+)
+
+// Balancer returns the coins held by an address,
+// as namecoin.Controller does
+type Balancer interface {
+	Balance(db weave.ReadOnlyKVStore, addr weave.Address) (x.Coins, error)
+}
+
+// Decorator lets session keys act for their account,
+// within the limits of their session
+type Decorator struct {
+	auth    x.Authenticator
+	balance Balancer
+	bucket  Bucket
+}
+
+var _ weave.Decorator = Decorator{}
+
+// NewDecorator looks up the sessions of the signers returned
+// by auth, and measures what they spend with balance
+func NewDecorator(auth x.Authenticator, balance Balancer) Decorator {
+	return Decorator{
+		auth:    auth,
+		balance: balance,
+		bucket:  NewBucket(),
+	}
+}
+
+// Check verifies the sessions before calling down the stack
+func (d Decorator) Check(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	sessions, err := d.sessions(ctx, store, tx)
+	if err != nil {
+		return res, err
+	}
+	if len(sessions) == 0 {
+		return next.Check(ctx, store, tx)
+	}
+	return next.Check(withAccounts(ctx, accounts(sessions)), store, tx)
+}
+
+// Deliver verifies the sessions before calling down the stack,
+// and charges what left the accounts to their spend limit
+func (d Decorator) Deliver(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	sessions, err := d.sessions(ctx, store, tx)
+	if err != nil {
+		return res, err
+	}
+	if len(sessions) == 0 {
+		return next.Deliver(ctx, store, tx)
+	}
+
+	before := make([]x.Coins, len(sessions))
+	for i, obj := range sessions {
+		before[i], err = d.balance.Balance(store, account(obj).Address())
+		if err != nil {
+			return res, err
+		}
+	}
+
+	res, err = next.Deliver(withAccounts(ctx, accounts(sessions)), store, tx)
+	if err != nil {
+		return res, err
+	}
+
+	for i, obj := range sessions {
+		err = d.charge(store, obj, before[i])
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// sessions returns the sessions of all signers that are session
+// keys allowed to sign this message for their account. The
+// others, expired or for other paths, are skipped: the key only
+// signs for itself then.
+func (d Decorator) sessions(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) ([]orm.Object, error) {
+
+	msg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	height, _ := weave.GetHeight(ctx)
+
+	var sessions []orm.Object
+	for _, perm := range d.auth.GetPermissions(ctx) {
+		obj, err := d.bucket.Get(store, perm.Address())
+		if err != nil {
+			return nil, err
+		}
+		session := AsSession(obj)
+		if session == nil || session.Expired(height) || !session.Allows(msg.Path()) {
+			continue
+		}
+		sessions = append(sessions, obj)
+	}
+	return sessions, nil
+}
+
+// charge subtracts all coins that left the account since before
+// from the spend limit of the session
+func (d Decorator) charge(store weave.KVStore, obj orm.Object, before x.Coins) error {
+	after, err := d.balance.Balance(store, account(obj).Address())
+	if err != nil {
+		return err
+	}
+	diff := before.Clone()
+	for _, c := range after {
+		diff, err = diff.Subtract(*c)
+		if err != nil {
+			return err
+		}
+	}
+
+	session := AsSession(obj)
+	limit := x.Coins(session.SpendLimit)
+	for _, c := range diff {
+		// coins received are not credited to the limit
+		if !c.IsPositive() {
+			continue
+		}
+		if !limit.Contains(*c) {
+			return ErrSpendLimit(c.Ticker)
+		}
+		limit, err = limit.Subtract(*c)
+		if err != nil {
+			return err
+		}
+	}
+	session.SpendLimit = limit
+	return d.bucket.Save(store, obj)
+}
+
+func account(obj orm.Object) weave.Permission {
+	return weave.Permission(AsSession(obj).Account)
+}
+
+func accounts(sessions []orm.Object) []weave.Permission {
+	perms := make([]weave.Permission, len(sessions))
+	for i, obj := range sessions {
+		perms[i] = account(obj)
+	}
+	return perms
+}
@@ -0,0 +1,118 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestDecorator(t *testing.T) {
+	var helpers x.TestHelpers
+	_, account := helpers.MakeKey()
+	_, key := helpers.MakeKey()
+	_, other := helpers.MakeKey()
+
+	auth := helpers.CtxAuth("auth")
+	ctrl := namecoin.NewController()
+	chain := x.ChainAuth(auth, Authenticate{})
+	r := app.NewRouter()
+	r.Handle(cash.SendMsg{}.Path(), namecoin.NewSendHandler(chain))
+	r.Handle(namecoin.SetWalletNameMsg{}.Path(),
+		namecoin.NewSetNameHandler(chain, namecoin.NewWalletBucket()))
+	stack := helpers.Wrap(NewDecorator(auth, ctrl), r)
+
+	sendFrom := func(src weave.Permission, whole int64) weave.Msg {
+		return &cash.SendMsg{
+			Src:    src.Address(),
+			Dest:   other.Address(),
+			Amount: &x.Coin{Whole: whole, Ticker: "FOO"},
+		}
+	}
+	sendMsg := func(whole int64) weave.Msg {
+		return sendFrom(account, whole)
+	}
+	limit := func(whole int64) x.Coins {
+		if whole == 0 {
+			return nil
+		}
+		return x.Coins{{Whole: whole, Ticker: "FOO"}}
+	}
+
+	cases := []struct {
+		height  int64
+		signer  weave.Permission
+		msg     weave.Msg
+		check   func(error) bool
+		deliver func(error) bool
+		// remaining spend limit of the session
+		limit x.Coins
+	}{
+		0: {10, key, sendMsg(20), nil, nil, limit(10)},
+		// whole limit spent
+		1: {10, key, sendMsg(30), nil, nil, limit(0)},
+		2: {10, key, sendMsg(40), nil, IsSessionDeniedErr, limit(30)},
+		// last valid block
+		3: {100, key, sendMsg(20), nil, nil, limit(10)},
+		// an expired key, or one for other paths, only signs for itself
+		4: {101, key, sendMsg(20), errors.IsUnauthorizedErr, errors.IsUnauthorizedErr, limit(30)},
+		5: {10, key, namecoin.BuildSetNameMsg(account.Address(), "alice"),
+			errors.IsUnauthorizedErr, errors.IsUnauthorizedErr, limit(30)},
+		8: {101, key, sendFrom(key, 20), nil, nil, limit(30)},
+		9: {10, key, namecoin.BuildSetNameMsg(key.Address(), "bobby"), nil, nil, limit(30)},
+		// the account itself has no limit
+		6: {10, account, sendMsg(40), nil, nil, limit(30)},
+		// other is no session key for the account
+		7: {10, other, sendMsg(20), errors.IsUnauthorizedErr, errors.IsUnauthorizedErr, limit(30)},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			wallet, err := namecoin.WalletWith(account.Address(), "",
+				&x.Coin{Whole: 100, Ticker: "FOO"})
+			require.NoError(t, err)
+			require.NoError(t, namecoin.NewWalletBucket().Save(db, wallet))
+			own, err := namecoin.WalletWith(key.Address(), "",
+				&x.Coin{Whole: 100, Ticker: "FOO"})
+			require.NoError(t, err)
+			require.NoError(t, namecoin.NewWalletBucket().Save(db, own))
+			bucket := NewBucket()
+			session := NewSession(key.Address(), account,
+				[]string{"cash/send"}, limit(30), 100)
+			require.NoError(t, bucket.Save(db, session))
+
+			ctx := weave.WithHeight(context.Background(), tc.height)
+			ctx = auth.SetPermissions(ctx, tc.signer)
+			tx := helpers.MockTx(tc.msg)
+
+			_, err = stack.Check(ctx, db, tx)
+			if tc.check != nil {
+				assert.True(t, tc.check(err), "%+v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+			_, err = stack.Deliver(ctx, db, tx)
+			if tc.deliver != nil {
+				assert.True(t, tc.deliver(err), "%+v", err)
+				return
+			}
+			require.NoError(t, err)
+
+			obj, err := bucket.Get(db, key.Address())
+			require.NoError(t, err)
+			assert.Equal(t, tc.limit, x.Coins(AsSession(obj).SpendLimit))
+		})
+	}
+}
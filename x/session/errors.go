@@ -0,0 +1,52 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// session takes 1080-1090
+const (
+	CodeInvalidSession = 1080
+	CodeSessionDenied  = 1081
+	CodeNoSession      = 1082
+)
+
+var (
+	errInvalidPath    = fmt.Errorf("Invalid message path")
+	errInvalidExpires = fmt.Errorf("Invalid expiration height")
+	errKeyInUse       = fmt.Errorf("Session key bound to another account")
+	errSpendLimit     = fmt.Errorf("Session spend limit exceeded")
+	errNoSession      = fmt.Errorf("No such session")
+)
+
+func ErrInvalidPath(path string) error {
+	return errors.WithLog(path, errInvalidPath, CodeInvalidSession)
+}
+func ErrInvalidExpires(height int64) error {
+	msg := fmt.Sprintf("%d", height)
+	return errors.WithLog(msg, errInvalidExpires, CodeInvalidSession)
+}
+func ErrKeyInUse() error {
+	return errors.WithCode(errKeyInUse, CodeInvalidSession)
+}
+func IsInvalidSessionErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidSession)
+}
+
+func ErrSpendLimit(ticker string) error {
+	return errors.WithLog(ticker, errSpendLimit, CodeSessionDenied)
+}
+func IsSessionDeniedErr(err error) bool {
+	return errors.HasErrorCode(err, CodeSessionDenied)
+}
+
+func ErrNoSession() error {
+	return errors.WithCode(errNoSession, CodeNoSession)
+}
+func IsNoSessionErr(err error) bool {
+	return errors.HasErrorCode(err, CodeNoSession)
+}
@@ -0,0 +1,193 @@
+package session
+
+import (
+	"bytes"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x"
+)
+
+const (
+	createSessionCost int64 = 50
+	revokeSessionCost int64 = 0
+)
+
+// RegisterRoutes will instantiate and register
+// all handlers in this package
+func RegisterRoutes(r weave.Registry, auth x.Authenticator) {
+	bucket := NewBucket()
+	r.Handle(pathCreateSessionMsg, CreateSessionHandler{auth, bucket})
+	r.Handle(pathRevokeSessionMsg, RevokeSessionHandler{auth, bucket})
+}
+
+// RegisterQuery will register the sessions as "/sessions",
+// and "/sessions/account" to list the keys of an account
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("sessions", qr)
+}
+
+// CreateSessionHandler binds a session key to the account,
+// both sign
+type CreateSessionHandler struct {
+	auth   x.Authenticator
+	bucket Bucket
+}
+
+var _ weave.Handler = CreateSessionHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h CreateSessionHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += createSessionCost
+	return res, nil
+}
+
+// Deliver stores the session
+func (h CreateSessionHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, account, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	obj := NewSession(msg.Key, account, msg.Paths, msg.SpendLimit, msg.Expires)
+	err = h.bucket.Save(db, obj)
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h CreateSessionHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*CreateSessionMsg, weave.Permission, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*CreateSessionMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// sessions are short-lived
+	height, _ := weave.GetHeight(ctx)
+	if msg.Expires <= height || msg.Expires > height+MaxLifetime {
+		return nil, nil, ErrInvalidExpires(msg.Expires)
+	}
+
+	// the key must agree to serve the account, else anyone could
+	// bind the address of someone else and take over its txs
+	if !h.auth.HasAddress(ctx, msg.Key) {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	account := accountFor(h.auth.GetPermissions(ctx), msg.Key)
+	if account == nil {
+		return nil, nil, ErrKeyInUse()
+	}
+
+	// a key can only serve one account until its session expired
+	obj, err := h.bucket.Get(db, msg.Key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if session := AsSession(obj); session != nil && !session.Expired(height) &&
+		!bytes.Equal(session.Account, account) {
+		return nil, nil, ErrKeyInUse()
+	}
+	return msg, account, nil
+}
+
+// accountFor returns the first signer that is not the key,
+// nil if there is none
+func accountFor(signers []weave.Permission, key weave.Address) weave.Permission {
+	for _, perm := range signers {
+		if !perm.Address().Equals(key) {
+			return perm
+		}
+	}
+	return nil
+}
+
+// RevokeSessionHandler removes a session key
+type RevokeSessionHandler struct {
+	auth   x.Authenticator
+	bucket Bucket
+}
+
+var _ weave.Handler = RevokeSessionHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h RevokeSessionHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	res.GasAllocated += revokeSessionCost
+	return res, nil
+}
+
+// Deliver removes the session
+func (h RevokeSessionHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	err = h.bucket.Delete(db, msg.Key)
+	return res, err
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h RevokeSessionHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*RevokeSessionMsg, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*RevokeSessionMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := h.bucket.Get(db, msg.Key)
+	if err != nil {
+		return nil, err
+	}
+	session := AsSession(obj)
+	if session == nil {
+		return nil, ErrNoSession()
+	}
+
+	// the account may end the session, and the key may
+	// leave it
+	if !h.auth.HasAddress(ctx, account(obj).Address()) &&
+		!h.auth.HasAddress(ctx, msg.Key) {
+		return nil, errors.ErrUnauthorized()
+	}
+	return msg, nil
+}
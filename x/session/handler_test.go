@@ -0,0 +1,112 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+)
+
+func TestSessionHandlers(t *testing.T) {
+	var helpers x.TestHelpers
+	_, account := helpers.MakeKey()
+	_, key := helpers.MakeKey()
+	_, other := helpers.MakeKey()
+
+	auth := helpers.CtxAuth("auth")
+	r := app.NewRouter()
+	RegisterRoutes(r, auth)
+
+	paths := []string{"escrow/release", "escrow/return"}
+	limit := x.Coins{{Whole: 10, Ticker: "FOO"}}
+	create := func(key weave.Address, paths []string, expires int64) *CreateSessionMsg {
+		return &CreateSessionMsg{Key: key, Paths: paths, SpendLimit: limit, Expires: expires}
+	}
+
+	both := []weave.Permission{account, key}
+	cases := []struct {
+		signers []weave.Permission
+		height  int64
+		msg     weave.Msg
+		check   func(error) bool
+		// expected account of the session key, nil if none
+		owner weave.Permission
+	}{
+		0: {both, 100, create(key.Address(), paths, 200), nil, account},
+		1: {nil, 100, &RevokeSessionMsg{Key: other.Address()}, nil, nil},
+		// the key may leave the session, no one else
+		2: {[]weave.Permission{other}, 100, &RevokeSessionMsg{Key: other.Address()}, nil, nil},
+		3: {[]weave.Permission{key}, 100, &RevokeSessionMsg{Key: other.Address()},
+			errors.IsUnauthorizedErr, account},
+		4: {nil, 100, &RevokeSessionMsg{Key: key.Address()}, IsNoSessionErr, nil},
+		// the key is taken until it expires
+		5: {[]weave.Permission{key, other}, 100, create(other.Address(), paths, 200),
+			IsInvalidSessionErr, account},
+		6: {[]weave.Permission{key, other}, 151, create(other.Address(), paths, 200), nil, key},
+		7: {nil, 100, create(account.Address(), paths, 200), IsInvalidSessionErr, nil},
+		// the key must agree
+		8: {[]weave.Permission{account}, 100, create(key.Address(), paths, 200),
+			errors.IsUnauthorizedErr, nil},
+		9: {[]weave.Permission{}, 100, create(key.Address(), paths, 200),
+			errors.IsUnauthorizedErr, nil},
+		// not short-lived
+		10: {both, 100, create(key.Address(), paths, 100+MaxLifetime+1), IsInvalidSessionErr, nil},
+		11: {both, 100, create(key.Address(), paths, 100), IsInvalidSessionErr, nil},
+		// session keys cannot manage sessions
+		12: {both, 100, create(key.Address(), []string{"session/create"}, 200), IsInvalidSessionErr, nil},
+		13: {both, 100, create(key.Address(), nil, 200), IsInvalidSessionErr, nil},
+		14: {both, 100, create(key.Address(), []string{"escrow/release", "escrow/release"}, 200),
+			IsInvalidSessionErr, nil},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			bucket := NewBucket()
+			session := NewSession(other.Address(), account, paths, limit, 150)
+			require.NoError(t, bucket.Save(db, session))
+
+			ctx := weave.WithHeight(context.Background(), tc.height)
+			signers := tc.signers
+			if signers == nil {
+				signers = []weave.Permission{account}
+			}
+			ctx = auth.SetPermissions(ctx, signers...)
+			tx := helpers.MockTx(tc.msg)
+			_, err := r.Check(ctx, db, tx)
+			if tc.check != nil {
+				assert.True(t, tc.check(err), "%+v", err)
+				_, err = r.Deliver(ctx, db, tx)
+				assert.True(t, tc.check(err), "%+v", err)
+			} else {
+				require.NoError(t, err)
+				_, err = r.Deliver(ctx, db, tx)
+				require.NoError(t, err)
+			}
+
+			var target weave.Address
+			switch msg := tc.msg.(type) {
+			case *CreateSessionMsg:
+				target = msg.Key
+			case *RevokeSessionMsg:
+				target = msg.Key
+			}
+			obj, err := bucket.Get(db, target)
+			require.NoError(t, err)
+			if tc.owner == nil {
+				assert.Nil(t, obj)
+				return
+			}
+			require.NotNil(t, obj)
+			assert.Equal(t, []byte(tc.owner), AsSession(obj).Account)
+		})
+	}
+}
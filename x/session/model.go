@@ -0,0 +1,160 @@
+/*
+Package session lets an account register short-lived signing
+keys, so a browser wallet doesn't need the main key for every
+operation.
+
+The key signs the CreateSessionMsg along with the account, so no
+one can bind the address of someone else. Either of them may
+revoke the session.
+
+A session key may only sign the message paths listed in its
+session, until it expires, and may move at most its spend limit
+out of the account. The Decorator checks all of this and then
+adds the account to the permissions of the tx, see Authenticate.
+For other paths, and once the session expired, the key only signs
+for itself, and may be bound to another account.
+*/
+package session
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+)
+
+const (
+	// BucketName is where we store the sessions
+	BucketName = "sessions"
+	// IndexAccount is the index of sessions by account
+	IndexAccount = "account"
+
+	// MaxLifetime is the most blocks a session may be valid,
+	// about a week with 5 second blocks
+	MaxLifetime = 120000
+
+	maxPaths = 16
+)
+
+func init() {
+	keyspace.Buckets("session", BucketName)
+}
+
+// IsPath matches the message paths a session key may sign,
+// like "escrow/release"
+var IsPath = regexp.MustCompile(`^[a-z0-9_]{1,32}/[a-z0-9_]{1,32}$`).MatchString
+
+var _ orm.CloneableData = (*Session)(nil)
+
+// Validate ensures the session is valid
+func (s *Session) Validate() error {
+	if err := weave.Permission(s.Account).Validate(); err != nil {
+		return err
+	}
+	if s.Expires <= 0 {
+		return ErrInvalidExpires(s.Expires)
+	}
+	return validateLimits(s.Paths, s.SpendLimit)
+}
+
+// Copy makes a new session with the same values
+func (s *Session) Copy() orm.CloneableData {
+	paths := make([]string, len(s.Paths))
+	copy(paths, s.Paths)
+	return &Session{
+		Account:    s.Account,
+		Paths:      paths,
+		SpendLimit: x.Coins(s.SpendLimit).Clone(),
+		Expires:    s.Expires,
+	}
+}
+
+// Expired returns true once the session no longer serves
+// at height
+func (s *Session) Expired(height int64) bool {
+	return height > s.Expires
+}
+
+// Allows returns true if the session key may sign the path
+func (s *Session) Allows(path string) bool {
+	for _, p := range s.Paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSession creates a session orm.Object for the key
+func NewSession(key weave.Address, account weave.Permission,
+	paths []string, limit x.Coins, expires int64) orm.Object {
+
+	session := &Session{
+		Account:    account,
+		Paths:      paths,
+		SpendLimit: limit,
+		Expires:    expires,
+	}
+	return orm.NewSimpleObj(key, session)
+}
+
+// AsSession safely extracts a Session value from the object
+func AsSession(obj orm.Object) *Session {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Session)
+}
+
+// Bucket is a type-safe wrapper around orm.Bucket
+type Bucket struct {
+	orm.Bucket
+}
+
+// NewBucket initializes a Bucket with default name
+func NewBucket() Bucket {
+	return Bucket{
+		Bucket: orm.NewBucket(BucketName,
+			orm.NewSimpleObj(nil, new(Session))).
+			WithIndex(IndexAccount, idxAccount, false),
+	}
+}
+
+func idxAccount(obj orm.Object) ([]byte, error) {
+	session := AsSession(obj)
+	if session == nil {
+		return nil, errors.New("Can only take index of Session")
+	}
+	return weave.Permission(session.Account).Address(), nil
+}
+
+// validateLimits is shared by the session and the message
+// creating it
+func validateLimits(paths []string, limit x.Coins) error {
+	if len(paths) == 0 || len(paths) > maxPaths {
+		return ErrInvalidPath(strings.Join(paths, ","))
+	}
+	for i, path := range paths {
+		// a session key must not manage sessions itself
+		if !IsPath(path) || strings.HasPrefix(path, "session/") {
+			return ErrInvalidPath(path)
+		}
+		for _, p := range paths[:i] {
+			if p == path {
+				return ErrInvalidPath(path)
+			}
+		}
+	}
+	for _, c := range limit {
+		if !c.IsPositive() {
+			return cash.ErrInvalidAmount("Non-positive spend limit")
+		}
+	}
+	return limit.Validate()
+}
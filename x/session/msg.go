@@ -0,0 +1,39 @@
+package session
+
+import (
+	"github.com/confio/weave"
+)
+
+const (
+	pathCreateSessionMsg = "session/create"
+	pathRevokeSessionMsg = "session/revoke"
+)
+
+var _ weave.Msg = (*CreateSessionMsg)(nil)
+var _ weave.Msg = (*RevokeSessionMsg)(nil)
+
+// Path fulfills weave.Msg interface to allow routing
+func (CreateSessionMsg) Path() string {
+	return pathCreateSessionMsg
+}
+
+// Validate makes sure that this is sensible
+func (m *CreateSessionMsg) Validate() error {
+	if err := weave.Address(m.Key).Validate(); err != nil {
+		return err
+	}
+	if m.Expires <= 0 {
+		return ErrInvalidExpires(m.Expires)
+	}
+	return validateLimits(m.Paths, m.SpendLimit)
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (RevokeSessionMsg) Path() string {
+	return pathRevokeSessionMsg
+}
+
+// Validate makes sure that this is sensible
+func (m *RevokeSessionMsg) Validate() error {
+	return weave.Address(m.Key).Validate()
+}
@@ -0,0 +1,44 @@
+package trade
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// trade takes 1170-1180
+const (
+	CodeNoOrder      = 1170
+	CodeInvalidOrder = 1171
+	CodeOrderExpired = 1172
+)
+
+var (
+	errNoOrder      = fmt.Errorf("No order with this ID")
+	errInvalidOrder = fmt.Errorf("Invalid order")
+	errOrderExpired = fmt.Errorf("Order already expired")
+)
+
+func ErrNoOrder(id []byte) error {
+	return errors.WithLog(fmt.Sprintf("%X", id), errNoOrder, CodeNoOrder)
+}
+func IsNoOrderErr(err error) bool {
+	return errors.HasErrorCode(err, CodeNoOrder)
+}
+
+func ErrInvalidOrder(reason string) error {
+	return errors.WithLog(reason, errInvalidOrder, CodeInvalidOrder)
+}
+func IsInvalidOrderErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidOrder)
+}
+
+func ErrOrderExpired(timeout int64) error {
+	msg := fmt.Sprintf("%d", timeout)
+	return errors.WithLog(msg, errOrderExpired, CodeOrderExpired)
+}
+func IsOrderExpiredErr(err error) bool {
+	return errors.HasErrorCode(err, CodeOrderExpired)
+}
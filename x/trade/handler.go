@@ -0,0 +1,365 @@
+package trade
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/budget"
+	"github.com/iov-one/bcp-demo/x/modaccount"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+const (
+	createOrderCost int64 = 100
+	fillOrderCost   int64 = 0
+	cancelOrderCost int64 = 0
+
+	// maxExpirePerBlock limits the orders the Ticker closes at
+	// once, the rest are closed in the following blocks
+	maxExpirePerBlock = 100
+	// closeOrderTickCost is taken of the budget of the block
+	// for every order the Ticker closes
+	closeOrderTickCost int64 = 50
+)
+
+// RegisterRoutes will instantiate and register
+// all handlers in this package
+func RegisterRoutes(r weave.Registry, auth x.Authenticator,
+	control namecoin.Controller) {
+
+	bucket := NewBucket()
+	r.Handle(pathCreateOrderMsg, CreateOrderHandler{auth, bucket,
+		modaccount.NewBucket(), control})
+	r.Handle(pathFillOrderMsg, FillOrderHandler{auth, bucket, control})
+	r.Handle(pathCancelOrderMsg, CancelOrderHandler{auth, bucket, control})
+}
+
+// RegisterQuery will register the orders as "/orders", along
+// with "/orders/maker" and "/orders/pair"
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("orders", qr)
+}
+
+//---- create
+
+// CreateOrderHandler moves the offer of the maker to a new order
+type CreateOrderHandler struct {
+	auth     x.Authenticator
+	bucket   Bucket
+	accounts modaccount.Bucket
+	cash     namecoin.Controller
+}
+
+var _ weave.Handler = CreateOrderHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h CreateOrderHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += createOrderCost
+	return res, nil
+}
+
+// Deliver stores the order and moves the offer to its account.
+// It returns the id of the order as data.
+func (h CreateOrderHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, maker, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	order := &Order{
+		Maker:   maker,
+		Offer:   msg.Offer,
+		Rate:    msg.Rate,
+		Timeout: msg.Timeout,
+	}
+	obj, err := h.bucket.Create(db, order)
+	if err != nil {
+		return res, err
+	}
+	dest, err := h.accounts.Open(db, Account, obj.Key())
+	if err != nil {
+		return res, err
+	}
+	err = h.cash.WithContext(ctx).MoveCoins(db, maker, dest, *msg.Offer)
+	if err != nil {
+		return res, err
+	}
+
+	res.Data = obj.Key()
+	return res, nil
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h CreateOrderHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*CreateOrderMsg, weave.Address, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, err
+	}
+	msg, ok := rmsg.(*CreateOrderMsg)
+	if !ok {
+		return nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	height, _ := weave.GetHeight(ctx)
+	if msg.Timeout <= height {
+		return nil, nil, ErrOrderExpired(msg.Timeout)
+	}
+
+	maker := x.MainSigner(ctx, h.auth)
+	if maker == nil {
+		return nil, nil, errors.ErrUnauthorized()
+	}
+	return msg, maker.Address(), nil
+}
+
+//---- fill
+
+// FillOrderHandler swaps coins between an order and a taker
+type FillOrderHandler struct {
+	auth   x.Authenticator
+	bucket Bucket
+	cash   namecoin.Controller
+}
+
+var _ weave.Handler = FillOrderHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h FillOrderHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, _, _, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += fillOrderCost
+	return res, nil
+}
+
+// Deliver pays the maker the cost of the amount at the rate of
+// the order, and the taker the amount from the order. The order
+// is closed once all of it is sold.
+func (h FillOrderHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	msg, obj, taker, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	order := AsOrder(obj)
+
+	cost, err := order.Cost(*msg.Amount)
+	if err != nil {
+		return res, err
+	}
+	err = h.cash.WithContext(ctx).MoveCoinsBatch(db, []namecoin.Transfer{
+		{Src: taker, Dest: order.Maker, Amount: cost},
+		{Src: Account.Address(obj.Key()), Dest: taker, Amount: *msg.Amount},
+	})
+	if err != nil {
+		return res, err
+	}
+
+	left, err := order.Offer.Add(msg.Amount.Negative())
+	if err != nil {
+		return res, err
+	}
+	if !left.IsPositive() {
+		return res, h.bucket.Delete(db, obj.Key())
+	}
+	// this updates the object, as we have a pointer
+	order.Offer = &left
+	res.Data = obj.Key()
+	return res, h.bucket.Save(db, obj)
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h FillOrderHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (*FillOrderMsg, orm.Object, weave.Address, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	msg, ok := rmsg.(*FillOrderMsg)
+	if !ok {
+		return nil, nil, nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	obj, err := loadOrder(db, h.bucket, msg.OrderId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	order := AsOrder(obj)
+	height, _ := weave.GetHeight(ctx)
+	if order.Timeout < height {
+		return nil, nil, nil, ErrOrderExpired(order.Timeout)
+	}
+	if !msg.Amount.SameType(*order.Offer) {
+		return nil, nil, nil, ErrInvalidOrder("amount not of offered token")
+	}
+	if !order.Offer.IsGTE(*msg.Amount) {
+		return nil, nil, nil, ErrInvalidOrder("amount exceeds offer")
+	}
+
+	taker := x.MainSigner(ctx, h.auth)
+	if taker == nil {
+		return nil, nil, nil, errors.ErrUnauthorized()
+	}
+	return msg, obj, taker.Address(), nil
+}
+
+//---- cancel
+
+// CancelOrderHandler lets the maker close an order
+type CancelOrderHandler struct {
+	auth   x.Authenticator
+	bucket Bucket
+	cash   namecoin.Controller
+}
+
+var _ weave.Handler = CancelOrderHandler{}
+
+// Check just verifies it is properly formed and returns
+// the cost of executing it
+func (h CancelOrderHandler) Check(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	var res weave.CheckResult
+	_, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+
+	// return cost
+	res.GasAllocated += cancelOrderCost
+	return res, nil
+}
+
+// Deliver returns what is left to the maker and deletes the order
+func (h CancelOrderHandler) Deliver(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	var res weave.DeliverResult
+	obj, err := h.validate(ctx, db, tx)
+	if err != nil {
+		return res, err
+	}
+	return res, closeOrder(db, h.bucket, h.cash.WithContext(ctx), obj)
+}
+
+// validate does all common pre-processing between Check and Deliver
+func (h CancelOrderHandler) validate(ctx weave.Context, db weave.KVStore,
+	tx weave.Tx) (orm.Object, error) {
+
+	rmsg, err := tx.GetMsg()
+	if err != nil {
+		return nil, err
+	}
+	msg, ok := rmsg.(*CancelOrderMsg)
+	if !ok {
+		return nil, errors.ErrUnknownTxType(rmsg)
+	}
+
+	err = msg.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := loadOrder(db, h.bucket, msg.OrderId)
+	if err != nil {
+		return nil, err
+	}
+	if !h.auth.HasAddress(ctx, AsOrder(obj).Maker) {
+		return nil, errors.ErrUnauthorized()
+	}
+	return obj, nil
+}
+
+//---- expiry
+
+// Ticker closes the orders that timed out before the current
+// block, returning what is left to their makers
+type Ticker struct {
+	bucket Bucket
+	cash   namecoin.Controller
+}
+
+var _ weave.Ticker = Ticker{}
+
+// NewTicker creates a Ticker moving coins with control
+func NewTicker(control namecoin.Controller) Ticker {
+	return Ticker{bucket: NewBucket(), cash: control}
+}
+
+// Tick closes up to maxExpirePerBlock orders, the ones that
+// expired first come first, as long as the budget of the
+// block lasts
+func (t Ticker) Tick(ctx weave.Context, db weave.KVStore) (weave.TickResult, error) {
+	var res weave.TickResult
+	height, _ := weave.GetHeight(ctx)
+	expired, err := t.bucket.ExpiringBefore(db, height, maxExpirePerBlock)
+	if err != nil {
+		return res, err
+	}
+	for _, obj := range expired {
+		if !budget.Take(ctx, closeOrderTickCost) {
+			break
+		}
+		err = closeOrder(db, t.bucket, t.cash.WithContext(ctx), obj)
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// loadOrder returns the order with the id, or an error
+// if there is none
+func loadOrder(db weave.KVStore, bucket Bucket, id []byte) (orm.Object, error) {
+	obj, err := bucket.Get(db, id)
+	if err != nil {
+		return nil, err
+	}
+	if AsOrder(obj) == nil {
+		return nil, ErrNoOrder(id)
+	}
+	return obj, nil
+}
+
+// closeOrder returns the offer to the maker and deletes the order
+func closeOrder(db weave.KVStore, bucket Bucket, cash namecoin.Controller,
+	obj orm.Object) error {
+
+	order := AsOrder(obj)
+	err := cash.MoveCoins(db, Account.Address(obj.Key()), order.Maker, *order.Offer)
+	if err != nil {
+		return err
+	}
+	return bucket.Delete(db, obj.Key())
+}
@@ -0,0 +1,154 @@
+package trade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/app"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+func TestOrders(t *testing.T) {
+	var helpers x.TestHelpers
+	_, maker := helpers.MakeKey()
+	_, taker := helpers.MakeKey()
+
+	auth := helpers.CtxAuth("auth")
+	bank := cash.NewBucket()
+	control := namecoin.NewWalletController(bank)
+	r := app.NewRouter()
+	RegisterRoutes(r, auth, control)
+
+	db := store.MemStore()
+	for _, acct := range []struct {
+		perm weave.Permission
+		coin *x.Coin
+	}{
+		{maker, &x.Coin{Whole: 100, Ticker: "FOO"}},
+		{taker, &x.Coin{Whole: 100, Ticker: "BAR"}},
+	} {
+		wallet, err := cash.WalletWith(acct.perm.Address(), acct.coin)
+		require.NoError(t, err)
+		require.NoError(t, bank.Save(db, wallet))
+	}
+	deliver := func(height int64, signer weave.Permission, msg weave.Msg) ([]byte, error) {
+		ctx := auth.SetPermissions(weave.WithHeight(context.Background(), height), signer)
+		tx := helpers.MockTx(msg)
+		_, err := r.Check(ctx, db, tx)
+		if err != nil {
+			return nil, err
+		}
+		res, err := r.Deliver(ctx, db, tx)
+		return res.Data, err
+	}
+	balance := func(addr weave.Address) x.Coins {
+		coins, err := control.Balance(db, addr)
+		require.NoError(t, err)
+		return coins
+	}
+	coins := func(cs ...x.Coin) x.Coins {
+		res := make(x.Coins, len(cs))
+		for i := range cs {
+			res[i] = &cs[i]
+		}
+		return res
+	}
+	foo := func(whole, frac int64) *x.Coin {
+		c := x.NewCoin(whole, frac, "FOO")
+		return &c
+	}
+
+	// 1 FOO for 1.5 BAR
+	rate := x.NewCoin(1, 500000000, "BAR")
+	create := &CreateOrderMsg{Offer: foo(40, 0), Rate: &rate, Timeout: 20}
+	_, err := deliver(20, maker, create)
+	assert.True(t, IsOrderExpiredErr(err), "%+v", err)
+	same := &CreateOrderMsg{Offer: foo(40, 0), Rate: foo(2, 0), Timeout: 20}
+	_, err = deliver(10, maker, same)
+	assert.True(t, IsInvalidOrderErr(err), "%+v", err)
+	id, err := deliver(10, maker, create)
+	require.NoError(t, err)
+	assert.Equal(t, coins(x.NewCoin(60, 0, "FOO")), balance(maker.Address()))
+	assert.Equal(t, coins(x.NewCoin(40, 0, "FOO")), balance(Account.Address(id)))
+
+	// partial fills, rounded up for the maker
+	_, err = deliver(11, taker, &FillOrderMsg{OrderId: id, Amount: foo(10, 1)})
+	require.NoError(t, err)
+	assert.Equal(t, coins(x.NewCoin(84, 999999998, "BAR"), x.NewCoin(10, 1, "FOO")), balance(taker.Address()))
+	_, err = deliver(11, taker, &FillOrderMsg{OrderId: id, Amount: foo(30, 0)})
+	assert.True(t, IsInvalidOrderErr(err), "%+v", err)
+	_, err = deliver(11, taker, &FillOrderMsg{OrderId: id, Amount: &rate})
+	assert.True(t, IsInvalidOrderErr(err), "%+v", err)
+	// only the maker cancels
+	_, err = deliver(11, taker, &CancelOrderMsg{OrderId: id})
+	assert.True(t, errors.IsUnauthorizedErr(err), "%+v", err)
+
+	// the rest closes the order
+	_, err = deliver(12, taker, &FillOrderMsg{OrderId: id, Amount: foo(29, 999999999)})
+	require.NoError(t, err)
+	// both fills rounded up by one unit
+	assert.Equal(t, coins(x.NewCoin(39, 999999999, "BAR"), x.NewCoin(40, 0, "FOO")), balance(taker.Address()))
+	assert.Equal(t, coins(x.NewCoin(60, 1, "BAR"), x.NewCoin(60, 0, "FOO")), balance(maker.Address()))
+	obj, err := NewBucket().Get(db, id)
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+	_, err = deliver(12, taker, &FillOrderMsg{OrderId: id, Amount: foo(1, 0)})
+	assert.True(t, IsNoOrderErr(err), "%+v", err)
+
+	// cancel returns the rest
+	id, err = deliver(12, maker, create)
+	require.NoError(t, err)
+	_, err = deliver(13, maker, &CancelOrderMsg{OrderId: id})
+	require.NoError(t, err)
+	assert.Equal(t, coins(x.NewCoin(60, 1, "BAR"), x.NewCoin(60, 0, "FOO")), balance(maker.Address()))
+
+	// the ticker returns expired orders
+	id, err = deliver(13, maker, create)
+	require.NoError(t, err)
+	later, err := deliver(13, maker, &CreateOrderMsg{Offer: foo(10, 0), Rate: &rate, Timeout: 30})
+	require.NoError(t, err)
+	_, err = deliver(21, taker, &FillOrderMsg{OrderId: id, Amount: foo(1, 0)})
+	assert.True(t, IsOrderExpiredErr(err), "%+v", err)
+	ticker := NewTicker(control)
+	_, err = ticker.Tick(weave.WithHeight(context.Background(), 20), db)
+	require.NoError(t, err)
+	obj, err = NewBucket().Get(db, id)
+	require.NoError(t, err)
+	assert.NotNil(t, obj)
+	_, err = ticker.Tick(weave.WithHeight(context.Background(), 21), db)
+	require.NoError(t, err)
+	obj, err = NewBucket().Get(db, id)
+	require.NoError(t, err)
+	assert.Nil(t, obj)
+	obj, err = NewBucket().Get(db, later)
+	require.NoError(t, err)
+	assert.NotNil(t, obj)
+	assert.Equal(t, coins(x.NewCoin(60, 1, "BAR"), x.NewCoin(50, 0, "FOO")), balance(maker.Address()))
+}
+
+func TestOrderCost(t *testing.T) {
+	cases := []struct {
+		rate, amount, cost x.Coin
+	}{
+		0: {x.NewCoin(2, 0, "BAR"), x.NewCoin(3, 0, "FOO"), x.NewCoin(6, 0, "BAR")},
+		1: {x.NewCoin(0, 500000000, "BAR"), x.NewCoin(3, 0, "FOO"), x.NewCoin(1, 500000000, "BAR")},
+		// rounds up to the smallest unit
+		2: {x.NewCoin(0, 1, "BAR"), x.NewCoin(0, 1, "FOO"), x.NewCoin(0, 1, "BAR")},
+		3: {x.NewCoin(1, 500000000, "BAR"), x.NewCoin(0, 3, "FOO"), x.NewCoin(0, 5, "BAR")},
+	}
+	for i, tc := range cases {
+		order := &Order{Rate: &tc.rate}
+		cost, err := order.Cost(tc.amount)
+		require.NoError(t, err, "%d", i)
+		assert.Equal(t, tc.cost, cost, "%d", i)
+	}
+}
@@ -0,0 +1,216 @@
+/*
+Package trade swaps two tokens of this chain without a third
+party. A maker offers coins at a fixed rate in another token,
+and the coins are held by the account of the order. Takers buy
+all or part of what is left, paying the maker in the same tx.
+
+Orders that reach their timeout are closed by the Ticker at the
+start of the next block, which returns what is left to the maker.
+*/
+package trade
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+	"github.com/iov-one/bcp-demo/x/modaccount"
+)
+
+const (
+	// BucketName is where we store the orders
+	BucketName = "order"
+	// SequenceName is an auto-increment ID counter for orders
+	SequenceName = "id"
+	// IndexMaker is the index of orders by maker
+	IndexMaker = "maker"
+	// IndexPair is the index of orders by "<offer>/<rate>" ticker
+	IndexPair = "pair"
+	// IndexTimeout is the index of orders by timeout height
+	IndexTimeout = "timeout"
+
+	// fracUnit is the number of fractional units in one whole coin
+	fracUnit = 1000000000
+)
+
+func init() {
+	keyspace.Buckets("trade", BucketName)
+}
+
+// Account is the module account holding the offer of each
+// order, derived from the order id
+var Account = modaccount.NewDerived("trade", "order")
+
+var _ orm.CloneableData = (*Order)(nil)
+
+// Validate ensures the order is valid
+func (o *Order) Validate() error {
+	if err := weave.Address(o.Maker).Validate(); err != nil {
+		return err
+	}
+	if o.Timeout <= 0 {
+		return ErrInvalidOrder("timeout")
+	}
+	return validateTerms(o.Offer, o.Rate)
+}
+
+// Copy makes a new order with the same values
+func (o *Order) Copy() orm.CloneableData {
+	return &Order{
+		Maker:   o.Maker,
+		Offer:   o.Offer,
+		Rate:    o.Rate,
+		Timeout: o.Timeout,
+	}
+}
+
+// Cost returns what buying amount of the offer costs at the
+// rate of the order, rounded up to the smallest unit
+func (o *Order) Cost(amount x.Coin) (x.Coin, error) {
+	cost := new(big.Int).Mul(units(amount), units(*o.Rate))
+	cost.Add(cost, big.NewInt(fracUnit-1))
+	cost.Quo(cost, big.NewInt(fracUnit))
+	if !cost.IsInt64() {
+		return x.Coin{}, ErrInvalidOrder("cost too large")
+	}
+	n := cost.Int64()
+	res := x.NewCoin(n/fracUnit, n%fracUnit, o.Rate.Ticker)
+	res.Issuer = o.Rate.Issuer
+	return res, res.Validate()
+}
+
+// units returns the value of the coin in fractional units
+func units(c x.Coin) *big.Int {
+	n := new(big.Int).Mul(big.NewInt(c.Whole), big.NewInt(fracUnit))
+	return n.Add(n, big.NewInt(c.Fractional))
+}
+
+// AsOrder safely extracts an Order value from the object
+func AsOrder(obj orm.Object) *Order {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Order)
+}
+
+//--- Bucket
+
+// Bucket is a type-safe wrapper around orm.Bucket
+type Bucket struct {
+	orm.Bucket
+	idSeq orm.Sequence
+	// timeout mirrors the index of the bucket, so we can
+	// scan it directly
+	timeout orm.Index
+}
+
+// NewBucket initializes a Bucket with default name
+func NewBucket() Bucket {
+	bucket := orm.NewBucket(BucketName,
+		orm.NewSimpleObj(nil, new(Order))).
+		WithIndex(IndexMaker, idxMaker, false).
+		WithIndex(IndexPair, idxPair, false).
+		WithIndex(IndexTimeout, idxTimeout, false)
+
+	return Bucket{
+		Bucket: bucket,
+		idSeq:  bucket.Sequence(SequenceName),
+		// must match the name orm.Bucket.WithIndex uses
+		timeout: orm.NewIndex(BucketName+"_"+IndexTimeout,
+			idxTimeout, false, bucket.DBKey),
+	}
+}
+
+func getOrder(obj orm.Object) (*Order, error) {
+	if obj == nil {
+		return nil, errors.New("Cannot take index of nil")
+	}
+	order, ok := obj.Value().(*Order)
+	if !ok {
+		return nil, errors.New("Can only take index of Order")
+	}
+	return order, nil
+}
+
+func idxMaker(obj orm.Object) ([]byte, error) {
+	order, err := getOrder(obj)
+	if err != nil {
+		return nil, err
+	}
+	return order.Maker, nil
+}
+
+func idxPair(obj orm.Object) ([]byte, error) {
+	order, err := getOrder(obj)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(order.Offer.Ticker + "/" + order.Rate.Ticker), nil
+}
+
+func idxTimeout(obj orm.Object) ([]byte, error) {
+	order, err := getOrder(obj)
+	if err != nil {
+		return nil, err
+	}
+	return timeoutKey(order.Timeout), nil
+}
+
+// timeoutKey encodes the height big endian, so the index
+// is sorted by height
+func timeoutKey(height int64) []byte {
+	bz := make([]byte, 8)
+	binary.BigEndian.PutUint64(bz, uint64(height))
+	return bz
+}
+
+// Create will calculate the next sequence number and then
+// store the order there
+func (b Bucket) Create(db weave.KVStore, order *Order) (orm.Object, error) {
+	key := b.idSeq.NextVal(db)
+	obj := orm.NewSimpleObj(key, order)
+	err := b.Save(db, obj)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// ExpiringBefore returns up to limit orders with a timeout lower
+// than the given height, the ones expiring first come first
+func (b Bucket) ExpiringBefore(db weave.ReadOnlyKVStore, height int64,
+	limit int) ([]orm.Object, error) {
+
+	if height <= 0 {
+		return nil, nil
+	}
+	start := b.timeout.IndexKey(nil)
+	end := b.timeout.IndexKey(timeoutKey(height))
+	itr := db.Iterator(start, end)
+	defer itr.Close()
+
+	var res []orm.Object
+	for ; itr.Valid() && len(res) < limit; itr.Next() {
+		var refs orm.MultiRef
+		err := refs.Unmarshal(itr.Value())
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range refs.GetRefs() {
+			obj, err := b.Get(db, ref)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, obj)
+			if len(res) == limit {
+				break
+			}
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,48 @@
+package trade
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/module"
+	"github.com/iov-one/bcp-demo/x/namecoin"
+)
+
+// Module adds the order book. Auth authenticates the makers
+// and takers, Control moves their coins.
+type Module struct {
+	Auth    x.Authenticator
+	Control namecoin.Controller
+}
+
+var (
+	_ module.Module  = Module{}
+	_ module.Router  = Module{}
+	_ module.Querier = Module{}
+	_ module.Ticking = Module{}
+)
+
+// Name fulfils module.Module
+func (Module) Name() string {
+	return "trade"
+}
+
+// Version fulfils module.Module
+func (Module) Version() uint32 {
+	return 1
+}
+
+// RegisterRoutes fulfils module.Router
+func (m Module) RegisterRoutes(r weave.Registry) {
+	RegisterRoutes(r, m.Auth, m.Control)
+}
+
+// RegisterQuery fulfils module.Querier
+func (Module) RegisterQuery(qr weave.QueryRouter) {
+	RegisterQuery(qr)
+}
+
+// Ticker fulfils module.Ticking
+func (m Module) Ticker() weave.Ticker {
+	return NewTicker(m.Control)
+}
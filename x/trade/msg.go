@@ -0,0 +1,83 @@
+package trade
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/x"
+)
+
+const (
+	pathCreateOrderMsg = "trade/create"
+	pathFillOrderMsg   = "trade/fill"
+	pathCancelOrderMsg = "trade/cancel"
+)
+
+var _ weave.Msg = (*CreateOrderMsg)(nil)
+var _ weave.Msg = (*FillOrderMsg)(nil)
+var _ weave.Msg = (*CancelOrderMsg)(nil)
+
+// Path fulfills weave.Msg interface to allow routing
+func (CreateOrderMsg) Path() string {
+	return pathCreateOrderMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (FillOrderMsg) Path() string {
+	return pathFillOrderMsg
+}
+
+// Path fulfills weave.Msg interface to allow routing
+func (CancelOrderMsg) Path() string {
+	return pathCancelOrderMsg
+}
+
+// Validate makes sure the offer and rate are positive coins
+// of two different tokens
+func (m *CreateOrderMsg) Validate() error {
+	if m.Timeout <= 0 {
+		return ErrInvalidOrder("timeout")
+	}
+	return validateTerms(m.Offer, m.Rate)
+}
+
+// Validate makes sure the amount is positive
+func (m *FillOrderMsg) Validate() error {
+	err := validateOrderID(m.OrderId)
+	if err != nil {
+		return err
+	}
+	if m.Amount == nil || !m.Amount.IsPositive() {
+		return ErrInvalidOrder("amount must be positive")
+	}
+	return m.Amount.Validate()
+}
+
+// Validate only checks the id
+func (m *CancelOrderMsg) Validate() error {
+	return validateOrderID(m.OrderId)
+}
+
+func validateTerms(offer, rate *x.Coin) error {
+	if offer == nil || !offer.IsPositive() {
+		return ErrInvalidOrder("offer must be positive")
+	}
+	if err := offer.Validate(); err != nil {
+		return err
+	}
+	if rate == nil || !rate.IsPositive() {
+		return ErrInvalidOrder("rate must be positive")
+	}
+	if err := rate.Validate(); err != nil {
+		return err
+	}
+	if offer.SameType(*rate) {
+		return ErrInvalidOrder("cannot trade a token for itself")
+	}
+	return nil
+}
+
+func validateOrderID(id []byte) error {
+	if len(id) != 8 {
+		return ErrInvalidOrder("id")
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package travelrule
+
+import (
+	"fmt"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+	"github.com/tendermint/tmlibs/common"
+
+	"github.com/iov-one/bcp-demo/x/txindex"
+)
+
+// TagKey is the key of the tag with the hash of the envelope
+// of a transfer, hex encoded
+const TagKey = "travelrule"
+
+// EnvelopeTx is a tx that may carry the hash of an envelope
+type EnvelopeTx interface {
+	GetTravelRuleHash() []byte
+}
+
+// AmountFunc returns the coins a message transfers, nil if it
+// is not a transfer. It is called before the message is delivered.
+type AmountFunc func(db weave.ReadOnlyKVStore, msg weave.Msg) (x.Coins, error)
+
+// Decorator rejects the transfers that reach a threshold without
+// the hash of an envelope, and records every envelope on delivery
+type Decorator struct {
+	amount  AmountFunc
+	params  ParamsBucket
+	records Bucket
+}
+
+var _ weave.Decorator = Decorator{}
+
+// NewDecorator finds what the messages transfer with amount
+func NewDecorator(amount AmountFunc) Decorator {
+	return Decorator{
+		amount:  amount,
+		params:  NewParamsBucket(),
+		records: NewBucket(),
+	}
+}
+
+// Check verifies the tx before calling down the stack
+func (d Decorator) Check(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	var res weave.CheckResult
+	if _, _, err := d.validate(db, tx); err != nil {
+		return res, err
+	}
+	return next.Check(ctx, db, tx)
+}
+
+// Deliver verifies the tx, and records its envelope once
+// the message succeeded
+func (d Decorator) Deliver(ctx weave.Context, db weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	var res weave.DeliverResult
+	hash, amount, err := d.validate(db, tx)
+	if err != nil {
+		return res, err
+	}
+	res, err = next.Deliver(ctx, db, tx)
+	if err != nil || hash == nil {
+		return res, err
+	}
+
+	msg, err := tx.GetMsg()
+	if err != nil {
+		return res, err
+	}
+	height, _ := weave.GetHeight(ctx)
+	record := &Record{Height: height, Path: msg.Path(), Amount: amount}
+	if mtx, ok := tx.(txindex.MarshaledTx); ok {
+		bz, err := mtx.Marshal()
+		if err != nil {
+			return res, err
+		}
+		record.TxHash = txindex.Hash(bz)
+	}
+	err = d.records.Save(db, orm.NewSimpleObj(hash, record))
+	if err != nil {
+		return res, err
+	}
+	res.Tags = append(res.Tags, common.KVPair{
+		Key:   []byte(TagKey),
+		Value: []byte(fmt.Sprintf("%X", hash)),
+	})
+	return res, nil
+}
+
+// validate returns the hash of the envelope and what the
+// message transfers, the hash is nil if the tx has none
+func (d Decorator) validate(db weave.KVStore, tx weave.Tx) ([]byte, x.Coins, error) {
+	var hash []byte
+	if etx, ok := tx.(EnvelopeTx); ok {
+		hash = etx.GetTravelRuleHash()
+	}
+	if len(hash) == 0 {
+		hash = nil
+	} else if len(hash) != HashSize {
+		return nil, nil, ErrInvalidEnvelope("hash size")
+	}
+	if hash != nil {
+		obj, err := d.records.Get(db, hash)
+		if err != nil {
+			return nil, nil, err
+		}
+		if obj != nil {
+			return nil, nil, ErrInvalidEnvelope("already used")
+		}
+	}
+
+	msg, err := tx.GetMsg()
+	if err != nil || msg == nil {
+		// the tx fails below
+		return hash, nil, nil
+	}
+	amount, err := d.amount(db, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if hash != nil {
+		return hash, amount, nil
+	}
+	params, err := d.params.Load(db)
+	if err != nil {
+		return nil, nil, err
+	}
+	t, err := params.Reached(amount)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t != nil {
+		return nil, nil, ErrEnvelopeRequired(*t)
+	}
+	return nil, amount, nil
+}
@@ -0,0 +1,73 @@
+package travelrule
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"io"
+
+	"golang.org/x/crypto/nacl/box"
+)
+
+const (
+	// KeySize is the length of a curve25519 key of a VASP
+	KeySize = 32
+	// HashSize is the length of the hash of an envelope
+	HashSize  = sha256.Size
+	nonceSize = 24
+)
+
+// GenerateKey returns a new key pair for a VASP to receive
+// envelopes, it publishes the public key to its peers
+func GenerateKey() (public, private *[KeySize]byte, err error) {
+	return box.GenerateKey(rand.Reader)
+}
+
+// Seal encrypts the payload to the key of the beneficiary VASP
+// with a nacl box from a new key, the envelope is
+// ephemeral public key || nonce || box
+func Seal(vaspKey []byte, p *Payload) ([]byte, error) {
+	var peer [KeySize]byte
+	if len(vaspKey) != KeySize {
+		return nil, ErrInvalidEnvelope("key size")
+	}
+	copy(peer[:], vaspKey)
+
+	pub, priv, err := box.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	var nonce [nonceSize]byte
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return nil, err
+	}
+	msg, err := p.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	out := append(pub[:], nonce[:]...)
+	return box.Seal(out, msg, &nonce, &peer, priv), nil
+}
+
+// Open decrypts an envelope with the private key of the VASP
+func Open(private *[KeySize]byte, envelope []byte) (*Payload, error) {
+	if len(envelope) < KeySize+nonceSize+box.Overhead {
+		return nil, ErrInvalidEnvelope("size")
+	}
+	var peer [KeySize]byte
+	var nonce [nonceSize]byte
+	copy(peer[:], envelope)
+	copy(nonce[:], envelope[KeySize:])
+	msg, ok := box.Open(nil, envelope[KeySize+nonceSize:], &nonce, &peer, private)
+	if !ok {
+		return nil, ErrInvalidEnvelope("cannot open")
+	}
+	var p Payload
+	err := p.Unmarshal(msg)
+	return &p, err
+}
+
+// Hash is the travel_rule_hash of a tx carrying the envelope
+func Hash(envelope []byte) []byte {
+	h := sha256.Sum256(envelope)
+	return h[:]
+}
@@ -0,0 +1,46 @@
+package travelrule
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/x"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// travelrule takes 1250-1260
+const (
+	CodeEnvelopeRequired = 1250
+	CodeInvalidEnvelope  = 1251
+	CodeInvalidParams    = 1252
+)
+
+var (
+	errEnvelopeRequired = fmt.Errorf("Travel rule envelope required")
+	errInvalidEnvelope  = fmt.Errorf("Invalid travel rule envelope")
+	errInvalidParams    = fmt.Errorf("Invalid travel rule params")
+)
+
+func ErrEnvelopeRequired(threshold x.Coin) error {
+	msg := fmt.Sprintf("%d.%09d %s or more", threshold.Whole, threshold.Fractional,
+		threshold.Ticker)
+	return errors.WithLog(msg, errEnvelopeRequired, CodeEnvelopeRequired)
+}
+func IsEnvelopeRequiredErr(err error) bool {
+	return errors.HasErrorCode(err, CodeEnvelopeRequired)
+}
+
+func ErrInvalidEnvelope(reason string) error {
+	return errors.WithLog(reason, errInvalidEnvelope, CodeInvalidEnvelope)
+}
+func IsInvalidEnvelopeErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidEnvelope)
+}
+
+func ErrInvalidParams(reason string) error {
+	return errors.WithLog(reason, errInvalidParams, CodeInvalidParams)
+}
+func IsInvalidParamsErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidParams)
+}
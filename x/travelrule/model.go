@@ -0,0 +1,160 @@
+/*
+Package travelrule makes large transfers carry the information on
+their originator and beneficiary that the travel rule asks VASPs
+to exchange, without putting it on chain.
+
+The originating VASP seals a Payload to the key of the beneficiary
+VASP, relays the sealed envelope off chain, eg. through the gateway,
+and sets its Hash as travel_rule_hash of the tx. The Decorator
+rejects every send or escrow settlement that moves at least one of
+the thresholds in the Params without such a hash, and stores a
+Record of the transfer under the hash. The beneficiary VASP finds
+the hash in the "travelrule" tag of the tx, fetches the envelope
+and opens it with its private key.
+*/
+package travelrule
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+)
+
+const (
+	// BucketName is where we store the records
+	BucketName = "trvl"
+	// BucketNameParams is where we store the thresholds
+	BucketNameParams = "trvlprm"
+
+	paramsKey = "params"
+)
+
+func init() {
+	keyspace.Buckets("travelrule", BucketName, BucketNameParams)
+}
+
+var _ orm.CloneableData = (*Params)(nil)
+
+// Validate ensures every threshold is positive, with one
+// per ticker
+func (p *Params) Validate() error {
+	seen := make(map[string]bool, len(p.Thresholds))
+	for _, c := range p.Thresholds {
+		if c == nil || !c.IsPositive() {
+			return ErrInvalidParams("threshold must be positive")
+		}
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		if seen[c.ID()] {
+			return ErrInvalidParams("duplicate ticker")
+		}
+		seen[c.ID()] = true
+	}
+	return nil
+}
+
+// Copy makes a new set with the same values
+func (p *Params) Copy() orm.CloneableData {
+	return &Params{Thresholds: x.Coins(p.Thresholds).Clone()}
+}
+
+// Reached returns the first threshold the amount reaches,
+// nil if it stays below all of them. The coins of a ticker
+// are added up, in whatever order they come.
+func (p *Params) Reached(amount x.Coins) (*x.Coin, error) {
+	total, err := x.Coins(nil).Combine(amount)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range p.Thresholds {
+		if total.Contains(*t) {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+// ParamsBucket stores the thresholds
+type ParamsBucket struct {
+	orm.Bucket
+}
+
+// NewParamsBucket initializes a ParamsBucket with default name
+func NewParamsBucket() ParamsBucket {
+	return ParamsBucket{
+		Bucket: orm.NewBucket(BucketNameParams,
+			orm.NewSimpleObj(nil, new(Params))),
+	}
+}
+
+// Load returns the stored params, or no thresholds if
+// none were set in genesis
+func (b ParamsBucket) Load(db weave.ReadOnlyKVStore) (*Params, error) {
+	obj, err := b.Get(db, []byte(paramsKey))
+	if err != nil {
+		return nil, err
+	}
+	if obj == nil || obj.Value() == nil {
+		return new(Params), nil
+	}
+	return obj.Value().(*Params), nil
+}
+
+// Store saves the params, replacing the old ones
+func (b ParamsBucket) Store(db weave.KVStore, params *Params) error {
+	return b.Save(db, orm.NewSimpleObj([]byte(paramsKey), params))
+}
+
+//--- Records
+
+var _ orm.CloneableData = (*Record)(nil)
+
+// Validate ensures the record has a height and a path
+func (r *Record) Validate() error {
+	if r.Height <= 0 {
+		return ErrInvalidEnvelope("height")
+	}
+	if r.Path == "" {
+		return ErrInvalidEnvelope("missing path")
+	}
+	return nil
+}
+
+// Copy makes a new record with the same values
+func (r *Record) Copy() orm.CloneableData {
+	return &Record{
+		Height: r.Height,
+		TxHash: r.TxHash,
+		Path:   r.Path,
+		Amount: x.Coins(r.Amount).Clone(),
+	}
+}
+
+// AsRecord safely extracts a Record value from the object
+func AsRecord(obj orm.Object) *Record {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*Record)
+}
+
+// Bucket stores the records by the hash of their envelope
+type Bucket struct {
+	orm.Bucket
+}
+
+// NewBucket initializes a Bucket with default name
+func NewBucket() Bucket {
+	return Bucket{
+		Bucket: orm.NewBucket(BucketName,
+			orm.NewSimpleObj(nil, new(Record))),
+	}
+}
+
+// RegisterQuery will register the records as "/travelrule"
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("travelrule", qr)
+}
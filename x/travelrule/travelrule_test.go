@@ -0,0 +1,188 @@
+package travelrule
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/x/txindex"
+)
+
+func TestDecorator(t *testing.T) {
+	var helpers x.TestHelpers
+
+	params := &Params{Thresholds: []*x.Coin{
+		{Whole: 1000, Ticker: "IOV"},
+		{Whole: 10, Ticker: "ETH"},
+	}}
+	send := func(c x.Coin) weave.Msg {
+		return &cash.SendMsg{Amount: &c}
+	}
+	small := send(x.Coin{Whole: 999, Fractional: 999999999, Ticker: "IOV"})
+	large := send(x.Coin{Whole: 10, Ticker: "ETH"})
+	hash := Hash([]byte("envelope"))
+	used := Hash([]byte("used"))
+
+	cases := []struct {
+		params *Params
+		tx     weave.Tx
+		check  func(error) bool
+		record bool
+	}{
+		0: {params, helpers.MockTx(small), nil, false},
+		1: {params, helpers.MockTx(large), IsEnvelopeRequiredErr, false},
+		2: {params, envelopeTx{helpers.MockTx(large), hash}, nil, true},
+		// small transfers may carry one too
+		3: {params, envelopeTx{helpers.MockTx(small), hash}, nil, true},
+		4: {params, envelopeTx{helpers.MockTx(large), hash[:20]}, IsInvalidEnvelopeErr, false},
+		5: {params, envelopeTx{helpers.MockTx(large), used}, IsInvalidEnvelopeErr, false},
+		// other messages transfer nothing
+		6: {params, helpers.MockTx(helpers.MockMsg([]byte("x"))), nil, false},
+		// no params means no thresholds
+		7: {nil, helpers.MockTx(large), nil, false},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			ctx := weave.WithHeight(context.Background(), 5)
+			if tc.params != nil {
+				require.NoError(t, NewParamsBucket().Store(db, tc.params))
+			}
+			records := NewBucket()
+			err := records.Save(db, orm.NewSimpleObj(used, &Record{Height: 1, Path: "cash/send"}))
+			require.NoError(t, err)
+			stack := helpers.Wrap(NewDecorator(sendAmount), helpers.CountingHandler())
+
+			_, err = stack.Check(ctx, db, tc.tx)
+			if tc.check != nil {
+				assert.True(t, tc.check(err), "%+v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+			res, err := stack.Deliver(ctx, db, tc.tx)
+			if tc.check != nil {
+				assert.True(t, tc.check(err), "%+v", err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			obj, err := records.Get(db, hash)
+			require.NoError(t, err)
+			if !tc.record {
+				assert.Nil(t, obj)
+				assert.Empty(t, res.Tags)
+				return
+			}
+			msg, _ := tc.tx.GetMsg()
+			expected := &Record{
+				Height: 5,
+				Path:   "cash/send",
+				Amount: []*x.Coin{msg.(*cash.SendMsg).Amount},
+				TxHash: txindex.Hash([]byte("tx")),
+			}
+			assert.Equal(t, expected, AsRecord(obj))
+			require.Len(t, res.Tags, 1)
+			assert.Equal(t, TagKey, string(res.Tags[0].Key))
+			assert.Equal(t, fmt.Sprintf("%X", hash), string(res.Tags[0].Value))
+
+			// the envelope is spent
+			_, err = stack.Check(ctx, db, tc.tx)
+			assert.True(t, IsInvalidEnvelopeErr(err), "%+v", err)
+		})
+	}
+}
+
+func TestParams(t *testing.T) {
+	params := &Params{Thresholds: []*x.Coin{
+		{Whole: 1000, Ticker: "IOV"},
+		{Whole: 10, Ticker: "ETH"},
+	}}
+	assert.NoError(t, params.Validate())
+	assert.NoError(t, (&Params{}).Validate())
+	dup := &Params{Thresholds: []*x.Coin{{Whole: 1, Ticker: "IOV"}, {Whole: 2, Ticker: "IOV"}}}
+	assert.True(t, IsInvalidParamsErr(dup.Validate()))
+	zero := &Params{Thresholds: []*x.Coin{{Ticker: "IOV"}}}
+	assert.True(t, IsInvalidParamsErr(zero.Validate()))
+
+	eth := x.Coin{Whole: 12, Ticker: "ETH"}
+	iov := x.Coin{Whole: 5, Ticker: "IOV"}
+	reached := func(amount ...*x.Coin) *x.Coin {
+		t.Helper()
+		res, err := params.Reached(amount)
+		require.NoError(t, err)
+		return res
+	}
+	assert.Nil(t, reached())
+	assert.Nil(t, reached(&iov))
+	assert.Equal(t, params.Thresholds[1], reached(&iov, &eth))
+	// coins of the same ticker add up
+	assert.Equal(t, params.Thresholds[0], reached(&iov, &eth, &x.Coin{Whole: 995, Ticker: "IOV"}))
+}
+
+func TestSeal(t *testing.T) {
+	public, private, err := GenerateKey()
+	require.NoError(t, err)
+	payload := &Payload{
+		Originator:      &Person{Name: "Alice", Account: "alice@vasp-a"},
+		Beneficiary:     &Person{Name: "Bob", Account: "bob@vasp-b"},
+		OriginatorVasp:  "vasp-a",
+		BeneficiaryVasp: "vasp-b",
+	}
+
+	envelope, err := Seal(public[:], payload)
+	require.NoError(t, err)
+	opened, err := Open(private, envelope)
+	require.NoError(t, err)
+	assert.Equal(t, payload, opened)
+	assert.Len(t, Hash(envelope), HashSize)
+
+	// only the beneficiary VASP can open it
+	_, other, err := GenerateKey()
+	require.NoError(t, err)
+	_, err = Open(other, envelope)
+	assert.True(t, IsInvalidEnvelopeErr(err), "%+v", err)
+	_, err = Open(private, envelope[:10])
+	assert.True(t, IsInvalidEnvelopeErr(err), "%+v", err)
+	_, err = Seal(public[:20], payload)
+	assert.True(t, IsInvalidEnvelopeErr(err), "%+v", err)
+}
+
+//---------------- helpers --------
+
+// envelopeTx carries the hash of an envelope and encodes
+// as "tx"
+type envelopeTx struct {
+	weave.Tx
+	hash []byte
+}
+
+var (
+	_ EnvelopeTx          = envelopeTx{}
+	_ txindex.MarshaledTx = envelopeTx{}
+)
+
+func (e envelopeTx) GetTravelRuleHash() []byte {
+	return e.hash
+}
+
+func (e envelopeTx) Marshal() ([]byte, error) {
+	return []byte("tx"), nil
+}
+
+// sendAmount is the AmountFunc of sends
+func sendAmount(db weave.ReadOnlyKVStore, msg weave.Msg) (x.Coins, error) {
+	if send, ok := msg.(*cash.SendMsg); ok {
+		return x.Coins{send.Amount}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,150 @@
+package txindex
+
+import (
+	"encoding/json"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+)
+
+// codeInternal is returned by the app for errors without
+// an abci code
+const codeInternal uint32 = 1
+
+// MarshaledTx can return the bytes it was decoded from,
+// as all protobuf txs can
+type MarshaledTx interface {
+	Marshal() ([]byte, error)
+}
+
+// coder is an error with an abci code, like all errors
+// created with weave/errors
+type coder interface {
+	ABCICode() uint32
+}
+
+// Decorator stores the result of every delivered tx, whether
+// it failed or not, and counts the failures by path and code.
+// It must be above any savepoint, so the result of a failed tx
+// isn't rolled back with it.
+type Decorator struct {
+	bucket Bucket
+	errors ErrorBucket
+}
+
+var _ weave.Decorator = Decorator{}
+
+// NewDecorator returns a decorator using the default bucket
+func NewDecorator() Decorator {
+	return Decorator{bucket: NewBucket(), errors: NewErrorBucket()}
+}
+
+// Check just calls down the stack, only delivered txs
+// are indexed
+func (d Decorator) Check(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	return next.Check(ctx, store, tx)
+}
+
+// Deliver calls down the stack and records the result
+func (d Decorator) Deliver(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	gas := checkGas(ctx, store, tx, next)
+	res, err := next.Deliver(ctx, store, tx)
+
+	height, _ := weave.GetHeight(ctx)
+	if err != nil {
+		if cerr := d.errors.Add(store, msgPath(tx), errorCode(err), height); cerr != nil {
+			return res, cerr
+		}
+	}
+
+	mtx, ok := tx.(MarshaledTx)
+	if !ok {
+		return res, err
+	}
+	bz, merr := mtx.Marshal()
+	if merr != nil {
+		return res, merr
+	}
+	result := newResult(height, tx, res, err)
+	result.GasAllocated = gas
+	if serr := d.bucket.Save(store, orm.NewSimpleObj(Hash(bz), result)); serr != nil {
+		return res, serr
+	}
+	return res, err
+}
+
+// newResult summarizes the outcome of delivering tx
+func newResult(height int64, tx weave.Tx, res weave.DeliverResult, err error) *TxResult {
+	result := &TxResult{
+		Height:  height,
+		GasUsed: res.GasUsed,
+		Path:    msgPath(tx),
+		Msg:     msgJSON(tx),
+	}
+	if err != nil {
+		result.Code = errorCode(err)
+		result.Log = err.Error()
+		return result
+	}
+	for _, t := range res.Tags {
+		result.Tags = append(result.Tags, &Tag{Key: t.Key, Value: t.Value})
+	}
+	return result
+}
+
+// checkGas returns the gas the Check of the stack allocates
+// for tx, as Deliver does not report it. Like the scheduler
+// does for jobs, it checks on a copy of the store that is
+// discarded, so it costs a second run of the tx.
+func checkGas(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) int64 {
+
+	checker, ok := next.(weave.Checker)
+	if !ok {
+		return 0
+	}
+	cstore, ok := store.(weave.CacheableKVStore)
+	if !ok {
+		return 0
+	}
+	cache := cstore.CacheWrap()
+	defer cache.Discard()
+	res, _ := checker.Check(ctx, cache, tx)
+	return res.GasAllocated
+}
+
+// msgPath is the path of the message of tx, empty if it
+// has none
+func msgPath(tx weave.Tx) string {
+	msg, err := tx.GetMsg()
+	if err != nil {
+		return ""
+	}
+	return msg.Path()
+}
+
+// msgJSON is the message of tx as json, empty if it has
+// none or it cannot be encoded
+func msgJSON(tx weave.Tx) string {
+	msg, err := tx.GetMsg()
+	if err != nil || msg == nil {
+		return ""
+	}
+	bz, err := json.Marshal(msg)
+	if err != nil {
+		return ""
+	}
+	return string(bz)
+}
+
+// errorCode is the abci code the app returns for err
+func errorCode(err error) uint32 {
+	if c, ok := err.(coder); ok {
+		return c.ABCICode()
+	}
+	return codeInternal
+}
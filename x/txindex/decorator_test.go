@@ -0,0 +1,197 @@
+package txindex
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tmlibs/common"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/errors"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+)
+
+func TestHash(t *testing.T) {
+	cases := []struct {
+		tx   []byte
+		hash string
+	}{
+		0: {nil, "C81B94933420221A7AC004A90242D8B1D3E5070D"},
+		1: {[]byte("abc=abc"), "C82C2FE55DB02B266B18CCFA3EAE9668E2B1EE49"},
+		// two byte length prefix
+		2: {bytes.Repeat([]byte("x"), 300), "5F2E038F33BC5292F6CE959A4890FD5AA990DF69"},
+	}
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			assert.Equal(t, tc.hash, strings.ToUpper(hex.EncodeToString(Hash(tc.tx))))
+		})
+	}
+
+	assert.Equal(t, []byte{0}, uvarint(0))
+	assert.Equal(t, []byte{1, 7}, uvarint(7))
+	assert.Equal(t, []byte{2, 1, 0x2c}, uvarint(300))
+}
+
+func TestDecorator(t *testing.T) {
+	var helpers x.TestHelpers
+
+	msg := &cash.SendMsg{Memo: "hello"}
+	msgJSON := `{"memo":"hello"}`
+	tags := []common.KVPair{{Key: []byte("escrow.return"), Value: []byte("0001")}}
+	failed := errors.ErrUnauthorized()
+
+	cases := []struct {
+		tx       weave.Tx
+		res      weave.DeliverResult
+		err      error
+		expected *TxResult
+	}{
+		0: {marshaledTx{helpers.MockTx(msg), []byte("one")},
+			weave.DeliverResult{Tags: tags}, nil,
+			&TxResult{Height: 7, Path: msg.Path(), Msg: msgJSON, GasAllocated: checkCost,
+				Tags: []*Tag{{Key: tags[0].Key, Value: tags[0].Value}}}},
+		// failed txs are recorded too, without tags
+		1: {marshaledTx{helpers.MockTx(msg), []byte("two")},
+			weave.DeliverResult{Tags: tags}, failed,
+			&TxResult{Height: 7, Path: msg.Path(), Msg: msgJSON, GasAllocated: checkCost,
+				Code: failed.(coder).ABCICode(), Log: failed.Error()}},
+		2: {marshaledTx{helpers.MockTx(msg), []byte("three")},
+			weave.DeliverResult{}, fmt.Errorf("boom"),
+			&TxResult{Height: 7, Path: msg.Path(), Msg: msgJSON, GasAllocated: checkCost,
+				Code: codeInternal, Log: "boom"}},
+	}
+
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			db := store.MemStore()
+			ctx := weave.WithHeight(context.Background(), 7)
+			stack := helpers.Wrap(NewDecorator(), resultHandler{tc.res, tc.err})
+
+			_, err := stack.Check(ctx, db, tc.tx)
+			require.NoError(t, err)
+			db.Delete([]byte("checked"))
+			_, err = stack.Deliver(ctx, db, tc.tx)
+			assert.Equal(t, tc.err, err)
+			// the check for the gas is discarded
+			assert.False(t, db.Has([]byte("checked")))
+
+			bz, _ := tc.tx.(marshaledTx).Marshal()
+			res, err := NewBucket().Result(db, Hash(bz))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestQuery(t *testing.T) {
+	db := store.MemStore()
+	hash := Hash([]byte("tx"))
+	result := &TxResult{Height: 12, Path: "escrow/release"}
+	require.NoError(t, NewBucket().Save(db, orm.NewSimpleObj(hash, result)))
+
+	qr := weave.NewQueryRouter()
+	RegisterQuery(qr)
+	h := qr.Handler("/txs")
+	require.NotNil(t, h)
+
+	res, err := h.Query(db, weave.KeyQueryMod, hash)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res))
+	var loaded TxResult
+	require.NoError(t, loaded.Unmarshal(res[0].Value))
+	assert.Equal(t, *result, loaded)
+
+	// unknown hashes return nothing
+	unknown, _ := hex.DecodeString("00112233445566778899AABBCCDDEEFF00112233")
+	res, err = h.Query(db, weave.KeyQueryMod, unknown)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(res))
+}
+
+//---------------- helpers --------
+
+// marshaledTx returns fixed bytes as its encoding
+type marshaledTx struct {
+	weave.Tx
+	bz []byte
+}
+
+var _ MarshaledTx = marshaledTx{}
+
+func (m marshaledTx) Marshal() ([]byte, error) {
+	return m.bz, nil
+}
+
+// resultHandler always returns the same result, and
+// allocates checkCost gas
+type resultHandler struct {
+	res weave.DeliverResult
+	err error
+}
+
+const checkCost = 120
+
+var _ weave.Handler = resultHandler{}
+
+func (h resultHandler) Check(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.CheckResult, error) {
+	// must not be kept
+	store.Set([]byte("checked"), []byte{1})
+	return weave.CheckResult{GasAllocated: checkCost}, nil
+}
+
+func (h resultHandler) Deliver(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) (weave.DeliverResult, error) {
+	return h.res, h.err
+}
+
+func TestErrorCounters(t *testing.T) {
+	var helpers x.TestHelpers
+	db := store.MemStore()
+	send := helpers.MockTx(&cash.SendMsg{Memo: "hello"})
+	failed := errors.ErrUnauthorized()
+
+	deliver := func(height int64, tx weave.Tx, err error) {
+		ctx := weave.WithHeight(context.Background(), height)
+		stack := helpers.Wrap(NewDecorator(), resultHandler{weave.DeliverResult{}, err})
+		_, derr := stack.Deliver(ctx, db, tx)
+		assert.Equal(t, err, derr)
+	}
+	deliver(3, send, failed)
+	deliver(4, send, nil)
+	deliver(5, send, failed)
+	deliver(6, send, fmt.Errorf("boom"))
+
+	qr := weave.NewQueryRouter()
+	RegisterQuery(qr)
+	h := qr.Handler(QueryErrors)
+	require.NotNil(t, h)
+	res, err := h.Query(db, weave.PrefixQueryMod, []byte("cash/"))
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+
+	var counts []*ErrorCount
+	for _, m := range res {
+		var c ErrorCount
+		require.NoError(t, c.Unmarshal(m.Value))
+		counts = append(counts, &c)
+	}
+	code := failed.(coder).ABCICode()
+	assert.Equal(t, &ErrorCount{Path: "cash/send", Code: codeInternal, Count: 1, LastHeight: 6}, counts[0])
+	assert.Equal(t, &ErrorCount{Path: "cash/send", Code: code, Count: 2, LastHeight: 5}, counts[1])
+
+	var out bytes.Buffer
+	require.NoError(t, WriteMetrics(&out, counts))
+	assert.Contains(t, out.String(), "# TYPE bov_failed_txs_total counter\n")
+	assert.Contains(t, out.String(),
+		fmt.Sprintf("bov_failed_txs_total{path=\"cash/send\",code=\"%d\"} 2\n", code))
+}
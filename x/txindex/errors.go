@@ -0,0 +1,35 @@
+package txindex
+
+import (
+	"fmt"
+
+	"github.com/confio/weave/errors"
+)
+
+// ABCI Response Codes
+// bov takes 1000-1300
+// txindex takes 1130-1140
+const (
+	CodeInvalidResult = 1130
+)
+
+var (
+	errInvalidHeight = fmt.Errorf("Invalid height")
+	errMissingHash   = fmt.Errorf("Missing tx hash")
+	errInvalidCount  = fmt.Errorf("Invalid error count")
+)
+
+func ErrInvalidHeight(height int64) error {
+	msg := fmt.Sprintf("%d", height)
+	return errors.WithLog(msg, errInvalidHeight, CodeInvalidResult)
+}
+func ErrMissingHash() error {
+	return errors.WithCode(errMissingHash, CodeInvalidResult)
+}
+func ErrInvalidCount(count int64) error {
+	msg := fmt.Sprintf("%d", count)
+	return errors.WithLog(msg, errInvalidCount, CodeInvalidResult)
+}
+func IsInvalidResultErr(err error) bool {
+	return errors.HasErrorCode(err, CodeInvalidResult)
+}
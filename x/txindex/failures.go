@@ -0,0 +1,108 @@
+package txindex
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+)
+
+const (
+	// BucketNameErrors is where we count the failed txs
+	BucketNameErrors = "txerrs"
+	// QueryErrors lists the failures by path and code,
+	// eg. "/health/errors" with a prefix query of "escrow/"
+	QueryErrors = "/health/errors"
+
+	// metricFailedTxs is the name WriteMetrics exports the
+	// counters under
+	metricFailedTxs = "bov_failed_txs_total"
+)
+
+var _ orm.CloneableData = (*ErrorCount)(nil)
+
+// Validate ensures the counter counted something
+func (e *ErrorCount) Validate() error {
+	if e.Count <= 0 {
+		return ErrInvalidCount(e.Count)
+	}
+	if e.LastHeight < 0 {
+		return ErrInvalidHeight(e.LastHeight)
+	}
+	return nil
+}
+
+// Copy makes a new counter with the same values
+func (e *ErrorCount) Copy() orm.CloneableData {
+	return &ErrorCount{
+		Path:       e.Path,
+		Code:       e.Code,
+		Count:      e.Count,
+		LastHeight: e.LastHeight,
+	}
+}
+
+// AsErrorCount safely extracts an ErrorCount value from the object
+func AsErrorCount(obj orm.Object) *ErrorCount {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*ErrorCount)
+}
+
+// errorKey is the path and the code, so a prefix query
+// lists all failures of a module or message
+func errorKey(path string, code uint32) []byte {
+	return []byte(path + ":" + strconv.FormatUint(uint64(code), 10))
+}
+
+// ErrorBucket counts the failed txs by path and code
+type ErrorBucket struct {
+	orm.Bucket
+}
+
+// NewErrorBucket initializes an ErrorBucket with default name
+func NewErrorBucket() ErrorBucket {
+	return ErrorBucket{
+		Bucket: orm.NewBucket(BucketNameErrors,
+			orm.NewSimpleObj(nil, new(ErrorCount))),
+	}
+}
+
+// Add counts one more failure of the path with the code
+func (b ErrorBucket) Add(db weave.KVStore, path string, code uint32, height int64) error {
+	key := errorKey(path, code)
+	obj, err := b.Get(db, key)
+	if err != nil {
+		return err
+	}
+	count := AsErrorCount(obj)
+	if count == nil {
+		count = &ErrorCount{Path: path, Code: code}
+		obj = orm.NewSimpleObj(key, count)
+	}
+	count.Count++
+	count.LastHeight = height
+	return b.Save(db, obj)
+}
+
+// WriteMetrics writes the counters in the text format of
+// prometheus, for a gateway to serve what it read from
+// QueryErrors to its scraper
+func WriteMetrics(w io.Writer, counts []*ErrorCount) error {
+	_, err := fmt.Fprintf(w, "# HELP %s Delivered txs that failed, by message path and code.\n"+
+		"# TYPE %s counter\n", metricFailedTxs, metricFailedTxs)
+	if err != nil {
+		return err
+	}
+	for _, c := range counts {
+		_, err := fmt.Fprintf(w, "%s{path=%q,code=\"%d\"} %d\n",
+			metricFailedTxs, c.Path, c.Code, c.Count)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
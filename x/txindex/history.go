@@ -0,0 +1,196 @@
+package txindex
+
+import (
+	"bytes"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"github.com/confio/weave/x"
+
+	"github.com/iov-one/bcp-demo/query"
+	"github.com/iov-one/bcp-demo/x/deposit"
+)
+
+const (
+	// BucketNameHistory is where we store the history of
+	// all addresses
+	BucketNameHistory = "acctx"
+	// QueryAccount is the path of the AccountQuery
+	QueryAccount = "/txs/account"
+
+	historySeq = "seq"
+)
+
+// PartiesFunc returns the addresses a tx concerns besides its
+// signers, like the recipient of a payment or the arbiter of
+// an escrow. It is called before the tx is delivered.
+type PartiesFunc func(db weave.ReadOnlyKVStore, tx weave.Tx) ([]weave.Address, error)
+
+var _ orm.CloneableData = (*AccountTx)(nil)
+
+// Validate ensures the entry is complete
+func (a *AccountTx) Validate() error {
+	if len(a.Hash) == 0 {
+		return ErrMissingHash()
+	}
+	if a.Height < 0 {
+		return ErrInvalidHeight(a.Height)
+	}
+	return nil
+}
+
+// Copy makes a new entry with the same values
+func (a *AccountTx) Copy() orm.CloneableData {
+	return &AccountTx{
+		Hash:    a.Hash,
+		Height:  a.Height,
+		DestTag: a.DestTag,
+	}
+}
+
+// HistoryBucket lists the txs of every address. Keys are the
+// address followed by a sequence, so a prefix scan on the
+// address returns its txs in order.
+type HistoryBucket struct {
+	orm.Bucket
+	seq orm.Sequence
+}
+
+// NewHistoryBucket initializes a HistoryBucket with default name
+func NewHistoryBucket() HistoryBucket {
+	bucket := orm.NewBucket(BucketNameHistory,
+		orm.NewSimpleObj(nil, new(AccountTx)))
+	return HistoryBucket{
+		Bucket: bucket,
+		seq:    bucket.Sequence(historySeq),
+	}
+}
+
+// Append adds the tx to the history of addr, with the
+// destination tag of a payment to addr if there was one
+func (b HistoryBucket) Append(db weave.KVStore, addr weave.Address,
+	hash []byte, height int64, destTag string) error {
+
+	entry := &AccountTx{Hash: hash, Height: height, DestTag: destTag}
+	key := append(append([]byte{}, addr...), b.seq.NextVal(db)...)
+	err := b.Save(db, orm.NewSimpleObj(key, entry))
+	if err != nil {
+		return err
+	}
+	// the txs of each address are counted for the paged query
+	query.NewCounter(b.DBKey(addr)).Add(db, 1)
+	return nil
+}
+
+// HistoryDecorator adds every delivered tx to the history of
+// its signers and the parties returned by the PartiesFunc,
+// with the destination tags of the payments they received.
+// It must be below the signature checks and above any savepoint.
+type HistoryDecorator struct {
+	auth    x.Authenticator
+	parties PartiesFunc
+	bucket  HistoryBucket
+}
+
+var _ weave.Decorator = HistoryDecorator{}
+
+// NewHistoryDecorator records the signers returned by auth
+// and the parties of every tx
+func NewHistoryDecorator(auth x.Authenticator, parties PartiesFunc) HistoryDecorator {
+	return HistoryDecorator{
+		auth:    auth,
+		parties: parties,
+		bucket:  NewHistoryBucket(),
+	}
+}
+
+// Check just calls down the stack, only delivered txs
+// are recorded
+func (d HistoryDecorator) Check(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Checker) (weave.CheckResult, error) {
+
+	return next.Check(ctx, store, tx)
+}
+
+// Deliver finds all addresses of the tx before calling down
+// the stack, as the tx may remove the state it refers to
+func (d HistoryDecorator) Deliver(ctx weave.Context, store weave.KVStore, tx weave.Tx,
+	next weave.Deliverer) (weave.DeliverResult, error) {
+
+	mtx, ok := tx.(MarshaledTx)
+	if !ok {
+		return next.Deliver(ctx, store, tx)
+	}
+	var res weave.DeliverResult
+	addrs, err := d.addresses(ctx, store, tx)
+	if err != nil {
+		return res, err
+	}
+	bz, err := mtx.Marshal()
+	if err != nil {
+		return res, err
+	}
+
+	res, err = next.Deliver(ctx, store, tx)
+
+	hash := Hash(bz)
+	height, _ := weave.GetHeight(ctx)
+	for _, addr := range addrs {
+		destTag := deposit.Find(res.Tags, addr)
+		if serr := d.bucket.Append(store, addr, hash, height, destTag); serr != nil {
+			return res, serr
+		}
+	}
+	return res, err
+}
+
+// addresses returns the signers and parties, without duplicates
+func (d HistoryDecorator) addresses(ctx weave.Context, store weave.KVStore,
+	tx weave.Tx) ([]weave.Address, error) {
+
+	var all []weave.Address
+	for _, perm := range d.auth.GetPermissions(ctx) {
+		all = append(all, perm.Address())
+	}
+	if d.parties != nil {
+		parties, err := d.parties(store, tx)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, parties...)
+	}
+
+	var addrs []weave.Address
+	for _, addr := range all {
+		if len(addr) == 0 || contains(addrs, addr) {
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+func contains(addrs []weave.Address, addr weave.Address) bool {
+	for _, a := range addrs {
+		if bytes.Equal(a, addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// AccountQuery returns the history of one address, in pages as
+// described in package query.
+//
+// The data is the address for the first page, and the key of
+// the last result for the following ones.
+type AccountQuery struct {
+	query.PrefixQuery
+}
+
+var _ weave.QueryHandler = AccountQuery{}
+
+// NewAccountQuery creates a query handler for the bucket
+func NewAccountQuery(bucket HistoryBucket) AccountQuery {
+	return AccountQuery{query.NewPrefixQuery(bucket.Bucket, weave.AddressLength, 0)}
+}
@@ -0,0 +1,149 @@
+package txindex
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tmlibs/common"
+
+	"github.com/confio/weave"
+	"github.com/confio/weave/store"
+	"github.com/confio/weave/x"
+	"github.com/confio/weave/x/cash"
+
+	"github.com/iov-one/bcp-demo/query"
+	"github.com/iov-one/bcp-demo/x/deposit"
+)
+
+func TestHistory(t *testing.T) {
+	var helpers x.TestHelpers
+
+	_, alice := helpers.MakeKey()
+	_, bob := helpers.MakeKey()
+	_, carl := helpers.MakeKey()
+
+	// the recipient of a send is a party
+	parties := func(db weave.ReadOnlyKVStore, tx weave.Tx) ([]weave.Address, error) {
+		msg, err := tx.GetMsg()
+		if err != nil {
+			return nil, err
+		}
+		return []weave.Address{msg.(*cash.SendMsg).Dest}, nil
+	}
+	auth := helpers.CtxAuth("auth")
+	stack := helpers.Wrap(NewHistoryDecorator(auth, parties), resultHandler{})
+	failing := helpers.Wrap(NewHistoryDecorator(auth, parties),
+		resultHandler{err: fmt.Errorf("boom")})
+
+	send := func(dest weave.Address, bz string) weave.Tx {
+		return marshaledTx{helpers.MockTx(&cash.SendMsg{Dest: dest}), []byte(bz)}
+	}
+	db := store.MemStore()
+	run := func(h weave.Handler, height int64, signer weave.Permission, tx weave.Tx) {
+		ctx := weave.WithHeight(context.Background(), height)
+		ctx = auth.SetPermissions(ctx, signer)
+		h.Deliver(ctx, db, tx)
+	}
+	run(stack, 1, alice, send(bob.Address(), "one"))
+	// sending to yourself is listed once
+	run(stack, 2, bob, send(bob.Address(), "two"))
+	// failed txs are listed too
+	run(failing, 3, alice, send(carl.Address(), "three"))
+
+	qr := weave.NewQueryRouter()
+	RegisterQuery(qr)
+	h := qr.Handler(QueryAccount)
+	require.NotNil(t, h)
+
+	cases := []struct {
+		addr   weave.Address
+		hashes [][]byte
+	}{
+		0: {alice.Address(), [][]byte{Hash([]byte("one")), Hash([]byte("three"))}},
+		1: {bob.Address(), [][]byte{Hash([]byte("one")), Hash([]byte("two"))}},
+		2: {carl.Address(), [][]byte{Hash([]byte("three"))}},
+	}
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			var hashes [][]byte
+			fetch := func(cursor []byte) ([]weave.Model, error) {
+				if cursor == nil {
+					cursor = tc.addr
+				}
+				return h.Query(db, "limit=1", cursor)
+			}
+			err := query.Stream(fetch, func(m weave.Model) error {
+				var entry AccountTx
+				if err := entry.Unmarshal(m.Value); err != nil {
+					return err
+				}
+				hashes = append(hashes, entry.Hash)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tc.hashes, hashes)
+		})
+	}
+
+	// newest first, with the total
+	page, err := h.Query(db, "reverse=true&include_total=true", alice.Address())
+	require.NoError(t, err)
+	page, total, ok := query.SplitTotal(page)
+	require.True(t, ok)
+	assert.Equal(t, int64(2), total)
+	require.Equal(t, 2, len(page))
+	var entry AccountTx
+	require.NoError(t, entry.Unmarshal(page[0].Value))
+	assert.Equal(t, int64(3), entry.Height)
+
+	// bad input
+	_, err = h.Query(db, "", []byte("foo"))
+	assert.True(t, query.IsInvalidCursorErr(err))
+}
+
+func TestHistoryDestTag(t *testing.T) {
+	var helpers x.TestHelpers
+
+	_, alice := helpers.MakeKey()
+	_, exchange := helpers.MakeKey()
+
+	parties := func(db weave.ReadOnlyKVStore, tx weave.Tx) ([]weave.Address, error) {
+		return []weave.Address{exchange.Address()}, nil
+	}
+	auth := helpers.CtxAuth("auth")
+	res := weave.DeliverResult{Tags: []common.KVPair{deposit.Tag(exchange.Address(), "104882")}}
+	stack := helpers.Wrap(NewHistoryDecorator(auth, parties), resultHandler{res: res})
+
+	db := store.MemStore()
+	ctx := weave.WithHeight(context.Background(), 5)
+	ctx = auth.SetPermissions(ctx, alice)
+	tx := marshaledTx{helpers.MockTx(&cash.SendMsg{}), []byte("tx")}
+	_, err := stack.Deliver(ctx, db, tx)
+	require.NoError(t, err)
+
+	qr := weave.NewQueryRouter()
+	RegisterQuery(qr)
+	h := qr.Handler(QueryAccount)
+
+	// only the payee has the tag
+	cases := []struct {
+		addr    weave.Address
+		destTag string
+	}{
+		0: {alice.Address(), ""},
+		1: {exchange.Address(), "104882"},
+	}
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("case-%d", i), func(t *testing.T) {
+			page, err := h.Query(db, "", tc.addr)
+			require.NoError(t, err)
+			require.Equal(t, 1, len(page))
+			var entry AccountTx
+			require.NoError(t, entry.Unmarshal(page[0].Value))
+			assert.Equal(t, tc.destTag, entry.DestTag)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Package txindex records the result of every delivered tx under
+its hash, so a gateway can look up a tx with the "/txs" query
+of the app, without enabling the tx_index of tendermint.
+The HistoryDecorator also lists the txs of every address
+involved, so wallets can show the history of an account.
+
+The hash is the one tendermint uses, the ripemd160 of the
+go-wire encoded tx bytes. Txs that cannot be decoded never
+reach the Decorator and are not indexed.
+*/
+package txindex
+
+import (
+	"github.com/confio/weave"
+	"github.com/confio/weave/orm"
+	"golang.org/x/crypto/ripemd160"
+
+	"github.com/iov-one/bcp-demo/keyspace"
+)
+
+const (
+	// BucketName is where we store the tx results
+	BucketName = "txs"
+)
+
+func init() {
+	keyspace.Buckets("txindex", BucketName, BucketNameHistory, BucketNameErrors)
+}
+
+var _ orm.CloneableData = (*TxResult)(nil)
+
+// Validate ensures the result is complete
+func (r *TxResult) Validate() error {
+	if r.Height < 0 {
+		return ErrInvalidHeight(r.Height)
+	}
+	return nil
+}
+
+// Copy makes a new result with the same values
+func (r *TxResult) Copy() orm.CloneableData {
+	var tags []*Tag
+	for _, t := range r.Tags {
+		tags = append(tags, &Tag{Key: t.Key, Value: t.Value})
+	}
+	return &TxResult{
+		Height:       r.Height,
+		Code:         r.Code,
+		Log:          r.Log,
+		GasUsed:      r.GasUsed,
+		Tags:         tags,
+		Path:         r.Path,
+		GasAllocated: r.GasAllocated,
+		Msg:          r.Msg,
+	}
+}
+
+// AsTxResult safely extracts a TxResult value from the object
+func AsTxResult(obj orm.Object) *TxResult {
+	if obj == nil || obj.Value() == nil {
+		return nil
+	}
+	return obj.Value().(*TxResult)
+}
+
+// Hash returns the hash tendermint uses to identify the tx
+func Hash(tx []byte) []byte {
+	hasher := ripemd160.New()
+	hasher.Write(uvarint(uint64(len(tx))))
+	hasher.Write(tx)
+	return hasher.Sum(nil)
+}
+
+// uvarint encodes n as go-wire does: the number of bytes,
+// followed by n in big endian without leading zeros
+func uvarint(n uint64) []byte {
+	var bz []byte
+	for ; n > 0; n >>= 8 {
+		bz = append([]byte{byte(n)}, bz...)
+	}
+	return append([]byte{byte(len(bz))}, bz...)
+}
+
+// Bucket is a type-safe wrapper around orm.Bucket
+type Bucket struct {
+	orm.Bucket
+}
+
+// NewBucket initializes a Bucket with default name
+func NewBucket() Bucket {
+	return Bucket{
+		Bucket: orm.NewBucket(BucketName,
+			orm.NewSimpleObj(nil, new(TxResult))),
+	}
+}
+
+// Result returns the result of the tx with the hash, or nil
+// if it is not known
+func (b Bucket) Result(db weave.ReadOnlyKVStore, hash []byte) (*TxResult, error) {
+	obj, err := b.Get(db, hash)
+	if err != nil {
+		return nil, err
+	}
+	return AsTxResult(obj), nil
+}
+
+// RegisterQuery will register the results as "/txs",
+// to look up a tx by its hash, the history of every
+// address as "/txs/account" and the failure counters
+// as "/health/errors"
+func RegisterQuery(qr weave.QueryRouter) {
+	NewBucket().Register("txs", qr)
+	qr.Register(QueryAccount, NewAccountQuery(NewHistoryBucket()))
+	qr.Register(QueryErrors, NewErrorBucket())
+}